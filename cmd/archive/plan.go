@@ -1,22 +1,31 @@
 package archive
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"os"
 
 	"github.com/spf13/cobra"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 
 	"github.com/yaacov/kubectl-mtv/pkg/cmd/archive/plan"
+	deleteplan "github.com/yaacov/kubectl-mtv/pkg/cmd/delete/plan"
 	"github.com/yaacov/kubectl-mtv/pkg/util/client"
 	"github.com/yaacov/kubectl-mtv/pkg/util/completion"
 	"github.com/yaacov/kubectl-mtv/pkg/util/flags"
+	"github.com/yaacov/kubectl-mtv/pkg/util/prompt"
+	"github.com/yaacov/kubectl-mtv/pkg/util/upload"
 )
 
 // NewPlanCmd creates the plan archiving command
 func NewPlanCmd(kubeConfigFlags *genericclioptions.ConfigFlags) *cobra.Command {
 	var all bool
 	var planNames []string
+	var olderThan, statusFilter string
+	var yes bool
+	var exportDir string
+	var uploadURL string
 
 	cmd := &cobra.Command{
 		Use:   "plan",
@@ -25,7 +34,26 @@ func NewPlanCmd(kubeConfigFlags *genericclioptions.ConfigFlags) *cobra.Command {
 
 Archiving a plan marks it as completed and stops any ongoing operations.
 Archived plans are retained for historical reference but cannot be started.
-Use 'unarchive' to restore a plan if needed.`,
+Use 'unarchive' to restore a plan if needed.
+
+The plans are listed with their status, VM and migration counts, and archived only
+after confirmation; --yes, or the KUBECTL_MTV_ASSUME_YES=true environment variable,
+skips the question.
+
+Use --older-than and --status to archive finished plans in bulk: plans whose last
+migration completed longer ago than a duration, with a given status, or both. The
+matching plans are listed for review and only archived when --yes is given. Running
+and never started plans are never selected.
+
+Use --export DIR to save the final migration report, plan YAML, and VM status
+snapshot to DIR/<plan-name>/ before archiving, preserving the evidence after the
+cluster objects are eventually pruned. The plan is not archived if the export fails.
+
+Use --upload s3://bucket/path to upload the exported artifacts to S3 or S3-compatible
+storage (s3://bucket/path/<plan-name>/). Credentials and region are read from the
+standard AWS environment variables and shared config files; set AWS_ENDPOINT_URL_S3
+(or AWS_ENDPOINT_URL) for S3-compatible endpoints. Without --export, the artifacts are
+staged in a temporary directory that is removed after the upload.`,
 		Example: `  # Archive a completed plan
   kubectl-mtv archive plan --name my-migration
 
@@ -33,7 +61,20 @@ Use 'unarchive' to restore a plan if needed.`,
   kubectl-mtv archive plans --name plan1,plan2,plan3
 
   # Archive all plans in the namespace
-  kubectl-mtv archive plans --all`,
+  kubectl-mtv archive plans --all
+
+  # Preview the plans that completed more than 30 days ago, then archive them
+  kubectl-mtv archive plans --older-than 30d
+  kubectl-mtv archive plans --older-than 30d --yes
+
+  # Archive all succeeded plans
+  kubectl-mtv archive plans --status Succeeded --yes
+
+  # Export migration artifacts before archiving
+  kubectl-mtv archive plan --name my-migration --export ./migration-evidence
+
+  # Export migration artifacts to S3 before archiving
+  kubectl-mtv archive plan --name my-migration --upload s3://mtv-evidence/cluster-a`,
 		Args:         cobra.MaximumNArgs(1),
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -41,17 +82,32 @@ Use 'unarchive' to restore a plan if needed.`,
 				return err
 			}
 
-			// Validate mutual exclusivity of --name and --all
+			// Validate mutual exclusivity of --name, --all and the bulk selectors
+			selecting := olderThan != "" || statusFilter != ""
 			if all && len(planNames) > 0 {
 				return errors.New("cannot use --name with --all")
 			}
-			if !all && len(planNames) == 0 {
-				return errors.New("must specify --name or --all")
+			if selecting && (all || len(planNames) > 0) {
+				return errors.New("cannot use --older-than or --status with --name or --all")
+			}
+			if !all && !selecting && len(planNames) == 0 {
+				return errors.New("must specify --name, --all, --older-than or --status")
 			}
 
 			// Resolve the appropriate namespace based on context and flags
 			namespace := client.ResolveNamespace(kubeConfigFlags)
 
+			if selecting {
+				opts, err := plan.NewSelectOptions(olderThan, statusFilter, false)
+				if err != nil {
+					return err
+				}
+				planNames, err = plan.PreviewSelection(cmd.Context(), kubeConfigFlags, namespace, opts, yes, "archive")
+				if err != nil || len(planNames) == 0 {
+					return err
+				}
+			}
+
 			if all {
 				// Get all plan names from the namespace
 				var err error
@@ -65,8 +121,45 @@ Use 'unarchive' to restore a plan if needed.`,
 				}
 			}
 
+			// Show the plans and ask before archiving; a bulk selection was already
+			// listed and confirmed with --yes
+			if !selecting {
+				if err := confirmArchive(cmd.Context(), kubeConfigFlags, namespace, planNames, yes); err != nil {
+					return err
+				}
+			}
+
+			// Prepare the uploader before touching any plan so bad credentials fail early
+			dir := exportDir
+			var uploader *upload.S3Uploader
+			if uploadURL != "" {
+				var err error
+				uploader, err = upload.NewS3Uploader(uploadURL)
+				if err != nil {
+					return err
+				}
+				if dir == "" {
+					dir, err = os.MkdirTemp("", "kubectl-mtv-export-")
+					if err != nil {
+						return fmt.Errorf("failed to create temporary export directory: %v", err)
+					}
+					defer os.RemoveAll(dir)
+				}
+			}
+
 			// Loop over each plan name and archive it
 			for _, name := range planNames {
+				if dir != "" {
+					if err := plan.Export(cmd.Context(), kubeConfigFlags, name, namespace, dir); err != nil {
+						return fmt.Errorf("failed to export plan '%s': %v", name, err)
+					}
+				}
+				if uploader != nil {
+					if err := plan.UploadExport(cmd.Context(), uploader, dir, name); err != nil {
+						return fmt.Errorf("failed to upload plan '%s': %v", name, err)
+					}
+				}
+
 				err := plan.Archive(cmd.Context(), kubeConfigFlags, name, namespace, true)
 				if err != nil {
 					return err
@@ -80,8 +173,33 @@ Use 'unarchive' to restore a plan if needed.`,
 	cmd.Flags().StringSliceVar(&planNames, "names", nil, "Alias for --name")
 	_ = cmd.Flags().MarkHidden("names")
 	cmd.Flags().BoolVar(&all, "all", false, "Archive all migration plans in the namespace")
+	cmd.Flags().StringVar(&olderThan, "older-than", "", "Select the unarchived plans whose last migration completed longer ago than this duration (e.g. 30d, 12h)")
+	cmd.Flags().StringVar(&statusFilter, "status", "", "Select the unarchived plans whose last migration ended with this status (Succeeded, Failed, Canceled)")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Archive without asking for confirmation; with --older-than or --status, archive the selected plans instead of only listing them")
+	cmd.Flags().StringVar(&exportDir, "export", "", "Directory to save the migration report, plan YAML, and VM status snapshot to before archiving")
+	cmd.Flags().StringVar(&uploadURL, "upload", "", "Upload the exported artifacts to object storage before archiving (s3://bucket/path)")
 
 	_ = cmd.RegisterFlagCompletionFunc("name", completion.PlanNameCompletion(kubeConfigFlags))
 
 	return cmd
 }
+
+// confirmArchive lists the plans that will be archived and asks the user to confirm
+func confirmArchive(ctx context.Context, kubeConfigFlags *genericclioptions.ConfigFlags, namespace string, names []string, yes bool) error {
+	c, err := client.GetDynamicClient(kubeConfigFlags)
+	if err != nil {
+		return fmt.Errorf("failed to get client: %v", err)
+	}
+	summary, err := deleteplan.Summarize(ctx, c, namespace, names)
+	if err != nil {
+		return err
+	}
+
+	// Archiving keeps the owned mappings, so they are not shown
+	confirm := prompt.NewOptions(yes)
+	title := fmt.Sprintf("The following %d plan(s) in namespace '%s' will be archived:", len(names), namespace)
+	if err := prompt.Table(confirm, title, deleteplan.SummaryColumns[:4], summary); err != nil {
+		return err
+	}
+	return prompt.Confirm(confirm, fmt.Sprintf("Archive these %d plan(s)?", len(names)), fmt.Sprintf("archive %d plan(s)", len(names)))
+}
@@ -14,12 +14,15 @@ import (
 	"github.com/yaacov/kubectl-mtv/pkg/util/client"
 	"github.com/yaacov/kubectl-mtv/pkg/util/completion"
 	"github.com/yaacov/kubectl-mtv/pkg/util/flags"
+	"github.com/yaacov/kubectl-mtv/pkg/util/prompt"
 )
 
 // NewPlanCmd creates the plan cancellation command
 func NewPlanCmd(kubeConfigFlags *genericclioptions.ConfigFlags) *cobra.Command {
 	var vmNamesOrFile string
 	var name string
+	var selector plan.Selector
+	var yes bool
 
 	cmd := &cobra.Command{
 		Use:   "plan",
@@ -28,12 +31,32 @@ func NewPlanCmd(kubeConfigFlags *genericclioptions.ConfigFlags) *cobra.Command {
 
 This command allows you to stop the migration of selected VMs while allowing
 other VMs in the plan to continue. VMs to cancel can be specified as a
-comma-separated list or read from a file.`,
+comma-separated list or read from a file.
+
+VMs can also be selected by their state in the running migration, which is
+useful for large plans: --all-failed, --all-running, or --query with a TSL
+filter over the migration VM status. The filter can use the VM status fields
+(name, phase, error.reasons, ...) and a derived "state" field with the values
+Pending, Running, Failed, Succeeded or Canceled. Selectors are combined with
+--vms.
+
+The VMs to cancel are listed with their migration state, and the migration is
+patched only after confirmation; --yes, or the KUBECTL_MTV_ASSUME_YES=true
+environment variable, skips the question.`,
 		Example: `  # Cancel specific VMs in a plan
   kubectl-mtv cancel plan --name my-migration --vms "vm1,vm2"
 
   # Cancel VMs from a file
-  kubectl-mtv cancel plan --name my-migration --vms @failed-vms.yaml`,
+  kubectl-mtv cancel plan --name my-migration --vms @failed-vms.yaml
+
+  # Cancel all VMs that failed in the running migration, without asking
+  kubectl-mtv cancel plan --name my-migration --all-failed --yes
+
+  # Cancel all VMs still running
+  kubectl-mtv cancel plan --name my-migration --all-running
+
+  # Cancel VMs selected by a query over their migration status
+  kubectl-mtv cancel plan --name my-migration --query "where state = 'Running' and phase = 'CopyDisks'"`,
 		Args:         cobra.MaximumNArgs(1),
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -47,9 +70,16 @@ comma-separated list or read from a file.`,
 			// Resolve the appropriate namespace based on context and flags
 			namespace := client.ResolveNamespace(kubeConfigFlags)
 
+			if vmNamesOrFile == "" && selector.IsEmpty() {
+				return fmt.Errorf("specify VMs to cancel with --vms, --all-failed, --all-running or --query")
+			}
+
 			var vmNames []string
 
-			if strings.HasPrefix(vmNamesOrFile, "@") {
+			switch {
+			case vmNamesOrFile == "":
+				// Only selectors are used
+			case strings.HasPrefix(vmNamesOrFile, "@"):
 				// It's a file
 				filePath := vmNamesOrFile[1:]
 				content, err := os.ReadFile(filePath)
@@ -65,7 +95,7 @@ comma-separated list or read from a file.`,
 					}
 				}
 				vmNames = namesArray
-			} else {
+			default:
 				// It's a comma-separated list
 				vmNameSlice := strings.Split(vmNamesOrFile, ",")
 				for _, vmName := range vmNameSlice {
@@ -73,22 +103,24 @@ comma-separated list or read from a file.`,
 				}
 			}
 
-			if len(vmNames) == 0 {
+			if len(vmNames) == 0 && selector.IsEmpty() {
 				return fmt.Errorf("no VM names specified to cancel")
 			}
 
-			return plan.Cancel(kubeConfigFlags, name, namespace, vmNames)
+			return plan.Cancel(kubeConfigFlags, name, namespace, vmNames, selector, prompt.NewOptions(yes))
 		},
 	}
 
 	cmd.Flags().StringVarP(&name, "name", "M", "", "Plan name")
 	cmd.Flags().StringVar(&vmNamesOrFile, "vms", "", "List of VM names to cancel (comma-separated) or path to file containing VM names (prefix with @)")
+	cmd.Flags().BoolVar(&selector.AllFailed, "all-failed", false, "Cancel all VMs that failed in the running migration")
+	cmd.Flags().BoolVar(&selector.AllRunning, "all-running", false, "Cancel all VMs that are still running in the running migration")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Cancel the VMs without asking for confirmation")
+	cmd.Flags().StringVarP(&selector.Query, "query", "q", "", "Cancel VMs matching a TSL query over their migration status (e.g. \"where state = 'Failed'\")")
 
 	flags.MarkRequiredForMCP(cmd, "name")
-	if err := cmd.MarkFlagRequired("vms"); err != nil {
-		fmt.Printf("Warning: error marking 'vms' flag as required: %v\n", err)
-	}
 
+	cmd.ValidArgsFunction = completion.NameArgCompletion(completion.PlanNameCompletion(kubeConfigFlags))
 	_ = cmd.RegisterFlagCompletionFunc("name", completion.PlanNameCompletion(kubeConfigFlags))
 
 	return cmd
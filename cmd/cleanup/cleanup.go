@@ -0,0 +1,23 @@
+package cleanup
+
+import (
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/yaacov/kubectl-mtv/cmd/get"
+)
+
+// NewCleanupCmd creates the cleanup command with all its subcommands
+func NewCleanupCmd(kubeConfigFlags *genericclioptions.ConfigFlags, globalConfig get.GlobalConfigGetter) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "cleanup",
+		Short:        "Find leftovers of failed migrations",
+		Long:         `Find resources left behind on the source providers by failed or canceled migrations`,
+		SilenceUsage: true,
+	}
+
+	snapshotsCmd := NewSnapshotsCmd(kubeConfigFlags, globalConfig)
+	snapshotsCmd.Aliases = []string{"snapshot"}
+	cmd.AddCommand(snapshotsCmd)
+	return cmd
+}
@@ -0,0 +1,65 @@
+package cleanup
+
+import (
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/yaacov/kubectl-mtv/cmd/get"
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/cleanup/snapshots"
+	"github.com/yaacov/kubectl-mtv/pkg/util/client"
+	"github.com/yaacov/kubectl-mtv/pkg/util/completion"
+)
+
+// NewSnapshotsCmd creates the cleanup snapshots command
+func NewSnapshotsCmd(kubeConfigFlags *genericclioptions.ConfigFlags, globalConfig get.GlobalConfigGetter) *cobra.Command {
+	opts := snapshots.Options{}
+
+	cmd := &cobra.Command{
+		Use:   "snapshots",
+		Short: "List warm migration snapshots left on source VMs",
+		Long: `List the warm migration snapshots left on source VMs by failed or canceled migrations.
+
+Warm migrations take a snapshot of each source VM for every precopy and remove it
+when the precopy completes. When a migration fails or is canceled mid-way, the last
+snapshot may stay on the source VM, growing its delta disks and blocking storage
+operations until it is removed.
+
+The plans are selected by --plan, or by --provider for every plan migrating from
+that source provider. Plans with a running migration are skipped.
+  - vSphere: the VM's current snapshot is reported when a precopy of the plan took it
+  - oVirt: snapshots described as "Forklift Operator warm migration precopy" are reported
+
+Snapshots are never removed by this command: neither Forklift nor its inventory
+offer an API to delete source snapshots. Use -o script to print a removal script
+(govc for vSphere, engine REST calls for oVirt) to review and run by hand.`,
+		Example: `  # List leftover snapshots of a plan
+  kubectl-mtv cleanup snapshots --plan my-warm-plan
+
+  # Check every plan migrating from a provider
+  kubectl-mtv cleanup snapshots --provider vsphere-prod
+
+  # Write a removal script to review and run
+  kubectl-mtv cleanup snapshots --plan my-warm-plan -o script > remove-snapshots.sh`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg := globalConfig.GetKubeConfigFlags()
+			opts.Namespace = client.ResolveNamespace(cfg)
+			opts.InventoryURL = globalConfig.GetInventoryURL()
+			opts.InsecureSkipTLS = globalConfig.GetInventoryInsecureSkipTLS()
+			return snapshots.Print(cmd.Context(), cfg, opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.Plan, "plan", "", "Plan whose source VMs are checked")
+	cmd.Flags().StringVar(&opts.Provider, "provider", "", "Check the plans migrating from this source provider")
+	cmd.Flags().StringVarP(&opts.Output, "output", "o", "table", "Output format (table, json, yaml, script)")
+
+	_ = cmd.RegisterFlagCompletionFunc("plan", completion.PlanNameCompletion(kubeConfigFlags))
+	_ = cmd.RegisterFlagCompletionFunc("provider", completion.ProviderNameCompletion(kubeConfigFlags))
+	_ = cmd.RegisterFlagCompletionFunc("output", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"table", "json", "yaml", "script"}, cobra.ShellCompDirectiveNoFileComp
+	})
+
+	return cmd
+}
@@ -15,7 +15,7 @@ func NewCreateCmd(kubeConfigFlags *genericclioptions.ConfigFlags, globalConfig G
 	cmd := &cobra.Command{
 		Use:          "create",
 		Short:        "Create resources",
-		Long:         `Create various MTV resources like providers, plans, mappings, and VDDK images`,
+		Long:         `Create various MTV resources like providers, plans, mappings, waves, and VDDK images`,
 		SilenceUsage: true,
 	}
 
@@ -39,6 +39,10 @@ func NewCreateCmd(kubeConfigFlags *genericclioptions.ConfigFlags, globalConfig G
 	hookCmd.Aliases = []string{"hooks"}
 	cmd.AddCommand(hookCmd)
 
+	waveCmd := NewWaveCmd(kubeConfigFlags)
+	waveCmd.Aliases = []string{"waves"}
+	cmd.AddCommand(waveCmd)
+
 	cmd.AddCommand(NewVddkCmd(globalConfig, kubeConfigFlags))
 
 	return cmd
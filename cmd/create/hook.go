@@ -11,6 +11,7 @@ import (
 
 	"github.com/yaacov/kubectl-mtv/pkg/cmd/create/hook"
 	"github.com/yaacov/kubectl-mtv/pkg/util/client"
+	"github.com/yaacov/kubectl-mtv/pkg/util/flags"
 )
 
 // NewHookCmd creates the hook creation command
@@ -19,7 +20,7 @@ func NewHookCmd(kubeConfigFlags *genericclioptions.ConfigFlags) *cobra.Command {
 	var serviceAccount string
 	var playbook string
 	var deadline int64
-	var dryRun bool
+	dryRunMode := flags.NewDryRunFlag()
 	var outputFormat string
 	var aapJobTemplateID int
 	var aapURL, aapTokenSecret string
@@ -58,6 +59,9 @@ Examples:
 		Args:         cobra.NoArgs,
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			// Client dry run prints the resources, server dry run sends them with dryRun=All
+			dryRun := dryRunMode.IsClient()
+
 			if deadline < 0 {
 				return fmt.Errorf("deadline must be a positive number")
 			}
@@ -106,10 +110,10 @@ Examples:
 				hookSpec.Deadline = deadline
 			}
 
-			if !dryRun && outputFormat != "" {
+			if !dryRunMode.Enabled() && outputFormat != "" {
 				return fmt.Errorf("--output flag can only be used with --dry-run")
 			}
-			if dryRun && outputFormat != "" && outputFormat != "json" && outputFormat != "yaml" {
+			if dryRunMode.Enabled() && outputFormat != "" && outputFormat != "json" && outputFormat != "yaml" {
 				return fmt.Errorf("invalid output format for dry-run: %s. Valid formats are: json, yaml", outputFormat)
 			}
 			resolvedFormat := outputFormat
@@ -139,7 +143,7 @@ Examples:
 	cmd.Flags().StringVar(&serviceAccount, "service-account", "", "Service account to use for the hook (optional)")
 	cmd.Flags().StringVar(&playbook, "playbook", "", "Ansible playbook content, or use @filename to read from file (optional)")
 	cmd.Flags().Int64Var(&deadline, "deadline", 0, "Hook deadline in seconds (optional)")
-	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Output Hook CR to stdout instead of creating it")
+	flags.AddDryRunFlag(cmd, dryRunMode, "Print Hook CR instead of creating (client), or submit with server-side dry run to validate without persisting (server)")
 	cmd.Flags().StringVarP(&outputFormat, "output", "o", "", "Output format for dry-run (json, yaml). Defaults to yaml when --dry-run is used")
 	cmd.Flags().IntVar(&aapJobTemplateID, "aap-job-template-id", 0, "AAP job template ID (mutually exclusive with --image and --playbook)")
 	cmd.Flags().StringVar(&aapURL, "aap-url", "", "Per-hook AAP base URL (overrides controller default)")
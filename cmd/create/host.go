@@ -12,19 +12,22 @@ import (
 	"github.com/yaacov/kubectl-mtv/pkg/cmd/create/host"
 	"github.com/yaacov/kubectl-mtv/pkg/util/client"
 	"github.com/yaacov/kubectl-mtv/pkg/util/completion"
+	"github.com/yaacov/kubectl-mtv/pkg/util/flags"
 )
 
 // NewHostCmd creates the host creation command
 func NewHostCmd(kubeConfigFlags *genericclioptions.ConfigFlags, globalConfig GlobalConfigGetter) *cobra.Command {
 	var hostIDs []string
+	var hostQuery string
 	var provider string
 	var username, password string
 	var existingSecret string
 	var ipAddress string
 	var networkAdapterName string
+	var vSwitch string
 	var hostInsecureSkipTLS bool
 	var cacert string
-	var dryRun bool
+	dryRunMode := flags.NewDryRunFlag()
 	var outputFormat string
 
 	// HostSpec fields
@@ -39,7 +42,12 @@ By creating host resources, Forklift can utilize ESXi host interfaces directly f
 
 Only vSphere providers support host creation. The --host-id flag requires inventory host IDs
 (e.g. "host-8"), NOT display names or IP addresses. Use 'kubectl-mtv get inventory host
---provider <name>' to list available host IDs.
+--provider <name>' to list available host IDs. Alternatively, --host-query selects several hosts
+at once with a TSL query over the host inventory fields (e.g. cluster, name, inMaintenance).
+
+The disk transfer IP address is given with --ip-address, or discovered per host from the inventory:
+--network-adapter takes the IP of the named network adapter (port group), and --vswitch takes the
+IP of the VMkernel adapter connected to the named standard vSwitch.
 
 Examples:
   # First, discover available host IDs from the provider inventory
@@ -61,10 +69,16 @@ Examples:
   kubectl-mtv create host --host-id host-8 --provider my-vsphere-provider --username user --password pass --network-adapter "Management Network"
 
   # Create multiple hosts (all use same IP resolution method)
-  kubectl-mtv create host --host-id host-8,host-12,host-15 --provider my-vsphere-provider --existing-secret my-secret --network-adapter "Management Network"`,
+  kubectl-mtv create host --host-id host-8,host-12,host-15 --provider my-vsphere-provider --existing-secret my-secret --network-adapter "Management Network"
+
+  # Create hosts for every ESXi host of a cluster, using the IP of each host on vSwitch1
+  kubectl-mtv create host --host-query "where cluster = 'domain-c8' and inMaintenance = false" --provider my-vsphere-provider --existing-secret my-secret --vswitch vSwitch1`,
 		Args:         cobra.NoArgs,
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			// Client dry run prints the resources, server dry run sends them with dryRun=All
+			dryRun := dryRunMode.IsClient()
+
 			// Validate input parameters
 			if provider == "" {
 				return fmt.Errorf("provider is required")
@@ -91,11 +105,24 @@ Examples:
 				return fmt.Errorf("cannot use both --existing-secret and --username/--password")
 			}
 
-			if ipAddress == "" && networkAdapterName == "" {
-				return fmt.Errorf("either --ip-address OR --network-adapter must be provided")
+			if len(hostIDs) == 0 && hostQuery == "" {
+				return fmt.Errorf("either --host-id OR --host-query must be provided")
+			}
+			if len(hostIDs) > 0 && hostQuery != "" {
+				return fmt.Errorf("cannot use both --host-id and --host-query")
+			}
+
+			ipSources := 0
+			for _, source := range []string{ipAddress, networkAdapterName, vSwitch} {
+				if source != "" {
+					ipSources++
+				}
+			}
+			if ipSources == 0 {
+				return fmt.Errorf("one of --ip-address, --network-adapter or --vswitch must be provided")
 			}
-			if ipAddress != "" && networkAdapterName != "" {
-				return fmt.Errorf("cannot use both --ip-address and --network-adapter")
+			if ipSources > 1 {
+				return fmt.Errorf("only one of --ip-address, --network-adapter and --vswitch can be used")
 			}
 
 			if strings.HasPrefix(cacert, "@") {
@@ -107,10 +134,10 @@ Examples:
 				cacert = string(fileContent)
 			}
 
-			if !dryRun && outputFormat != "" {
+			if !dryRunMode.Enabled() && outputFormat != "" {
 				return fmt.Errorf("--output flag can only be used with --dry-run")
 			}
-			if dryRun && outputFormat != "" && outputFormat != "json" && outputFormat != "yaml" {
+			if dryRunMode.Enabled() && outputFormat != "" && outputFormat != "json" && outputFormat != "yaml" {
 				return fmt.Errorf("invalid output format for dry-run: %s. Valid formats are: json, yaml", outputFormat)
 			}
 			resolvedFormat := outputFormat
@@ -120,6 +147,7 @@ Examples:
 
 			opts := host.CreateHostOptions{
 				HostIDs:                  hostIDs,
+				HostQuery:                hostQuery,
 				Namespace:                namespace,
 				Provider:                 provider,
 				ConfigFlags:              kubeConfigFlags,
@@ -130,6 +158,7 @@ Examples:
 				ExistingSecret:           existingSecret,
 				IPAddress:                ipAddress,
 				NetworkAdapterName:       networkAdapterName,
+				VSwitch:                  vSwitch,
 				HostInsecureSkipTLS:      hostInsecureSkipTLS,
 				CACert:                   cacert,
 				HostSpec:                 hostSpec,
@@ -144,20 +173,19 @@ Examples:
 	cmd.Flags().StringSliceVar(&hostIDs, "host-id", nil, "Inventory host ID(s) to create (comma-separated, e.g. \"host-8,host-12\"); use 'get inventory host' to list IDs")
 	cmd.Flags().StringSliceVar(&hostIDs, "host-ids", nil, "Alias for --host-id")
 	_ = cmd.Flags().MarkHidden("host-ids")
+	cmd.Flags().StringVar(&hostQuery, "host-query", "", "TSL query selecting the inventory hosts to create (e.g. \"where cluster = 'domain-c8'\"), mutually exclusive with --host-id")
 	cmd.Flags().StringVarP(&provider, "provider", "p", "", "Provider name (must be a vSphere provider)")
 	cmd.Flags().StringVarP(&username, "username", "u", "", "Username for host authentication (required if --existing-secret not provided)")
 	cmd.Flags().StringVar(&password, "password", "", "Password for host authentication (required if --existing-secret not provided)")
 	cmd.Flags().StringVar(&existingSecret, "existing-secret", "", "Name of existing secret to use for host authentication")
-	cmd.Flags().StringVar(&ipAddress, "ip-address", "", "IP address for disk transfer (required - mutually exclusive with --network-adapter)")
-	cmd.Flags().StringVar(&networkAdapterName, "network-adapter", "", "Network adapter name to get IP address from inventory (required - mutually exclusive with --ip-address)")
+	cmd.Flags().StringVar(&ipAddress, "ip-address", "", "IP address for disk transfer (mutually exclusive with --network-adapter and --vswitch)")
+	cmd.Flags().StringVar(&networkAdapterName, "network-adapter", "", "Network adapter (port group) name to get each host's IP address from inventory (mutually exclusive with --ip-address and --vswitch)")
+	cmd.Flags().StringVar(&vSwitch, "vswitch", "", "Standard vSwitch name to get each host's VMkernel IP address from inventory (mutually exclusive with --ip-address and --network-adapter)")
 	cmd.Flags().BoolVar(&hostInsecureSkipTLS, "host-insecure-skip-tls", false, "Skip TLS verification when connecting to the host (only used when creating new secret)")
 	cmd.Flags().StringVar(&cacert, "cacert", "", "CA certificate for host authentication - provide certificate content directly or use @filename to load from file (only used when creating new secret)")
-	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Output Host CR(s) to stdout instead of creating them")
+	flags.AddDryRunFlag(cmd, dryRunMode, "Print Host CR(s) instead of creating (client), or submit with server-side dry run to validate without persisting (server)")
 	cmd.Flags().StringVarP(&outputFormat, "output", "o", "", "Output format for dry-run (json, yaml). Defaults to yaml when --dry-run is used")
 
-	if err := cmd.MarkFlagRequired("host-id"); err != nil {
-		panic(err)
-	}
 	if err := cmd.MarkFlagRequired("provider"); err != nil {
 		panic(err)
 	}
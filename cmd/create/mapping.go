@@ -36,7 +36,9 @@ create specific mapping types.`,
 func newNetworkMappingCmd(kubeConfigFlags *genericclioptions.ConfigFlags, globalConfig GlobalConfigGetter) *cobra.Command {
 	var name, sourceProvider, targetProvider string
 	var networkPairs string
-	var dryRun bool
+	var fromPlan, vms, defaultTargetNetwork string
+	var yes bool
+	dryRunMode := flags.NewDryRunFlag()
 	var outputFormat string
 
 	cmd := &cobra.Command{
@@ -51,7 +53,13 @@ Pair formats:
   - source:target-namespace/target-network - Map to specific NAD
   - source:target-network - Map to NAD in same namespace
   - source:default - Map to pod networking
-  - source:ignored - Skip this network`,
+  - source:ignored - Skip this network
+
+Inferring a mapping:
+  Instead of listing pairs, use --from-plan or --vms to inspect a set of VMs in the
+  source inventory and propose a mapping for the networks they use. The first network
+  is mapped to --default-target-network (pod networking unless overridden) and the
+  others are ignored. The proposed mapping is printed for review; add --yes to create it.`,
 		Example: `  # Create a network mapping to pod networking
   kubectl-mtv create mapping network --name my-net-map \
     --source vsphere-prod \
@@ -62,10 +70,24 @@ Pair formats:
   kubectl-mtv create mapping network --name my-net-map \
     --source vsphere-prod \
     --target host \
-    --network-pairs "VM Network:openshift-cnv/br-external,Management:default"`,
+    --network-pairs "VM Network:openshift-cnv/br-external,Management:default"
+
+  # Propose a network mapping for the VMs of an existing plan
+  kubectl-mtv create mapping network --name wave2-net --from-plan wave2
+
+  # Create a mapping for the networks of the VMs matching a query
+  kubectl-mtv create mapping network --name prod-net \
+    --source vsphere-prod \
+    --target host \
+    --vms "where name ~= 'prod-.*'" \
+    --default-target-network openshift-cnv/br-external \
+    --yes`,
 		Args:         cobra.MaximumNArgs(1),
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			// Client dry run prints the resources, server dry run sends them with dryRun=All
+			dryRun := dryRunMode.IsClient()
+
 			if err := flags.ResolveNameArg(&name, args); err != nil {
 				return err
 			}
@@ -80,10 +102,37 @@ Pair formats:
 			inventoryURL := globalConfig.GetInventoryURL()
 			inventoryInsecureSkipTLS := globalConfig.GetInventoryInsecureSkipTLS()
 
-			if !dryRun && outputFormat != "" {
+			if fromPlan != "" || vms != "" {
+				if networkPairs != "" {
+					return fmt.Errorf("--network-pairs cannot be used with --from-plan or --vms, the pairs are inferred from the VMs")
+				}
+				format, err := inferOutputFormat(yes, dryRunMode.Enabled(), outputFormat)
+				if err != nil {
+					return err
+				}
+				return mapping.InferNetwork(cmd.Context(), mapping.InferOptions{
+					ConfigFlags:              kubeConfigFlags,
+					Name:                     name,
+					Namespace:                namespace,
+					SourceProvider:           sourceProvider,
+					TargetProvider:           targetProvider,
+					InventoryURL:             inventoryURL,
+					InventoryInsecureSkipTLS: inventoryInsecureSkipTLS,
+					FromPlan:                 fromPlan,
+					VMs:                      vms,
+					DefaultTargetNetwork:     defaultTargetNetwork,
+					Yes:                      yes,
+					OutputFormat:             format,
+				})
+			}
+			if yes || cmd.Flags().Changed("default-target-network") {
+				return fmt.Errorf("--yes and --default-target-network require --from-plan or --vms")
+			}
+
+			if !dryRunMode.Enabled() && outputFormat != "" {
 				return fmt.Errorf("--output flag can only be used with --dry-run")
 			}
-			if dryRun && outputFormat != "" && outputFormat != "json" && outputFormat != "yaml" {
+			if dryRunMode.Enabled() && outputFormat != "" && outputFormat != "json" && outputFormat != "yaml" {
 				return fmt.Errorf("invalid output format for dry-run: %s. Valid formats are: json, yaml", outputFormat)
 			}
 			if dryRun && outputFormat == "" {
@@ -98,11 +147,16 @@ Pair formats:
 	cmd.Flags().StringVarP(&sourceProvider, "source", "S", "", "Source provider name")
 	cmd.Flags().StringVarP(&targetProvider, "target", "T", "", "Target provider name")
 	cmd.Flags().StringVar(&networkPairs, "network-pairs", "", "Network mapping pairs in format 'source:target-namespace/target-network', 'source:target-network', 'source:default', or 'source:ignored' (comma-separated)")
-	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Output mapping CR to stdout instead of creating it")
+	cmd.Flags().StringVar(&fromPlan, "from-plan", "", "Infer the mapping from the networks used by the VMs of this plan (also sets --source and --target)")
+	cmd.Flags().StringVar(&vms, "vms", "", "Infer the mapping from the networks used by these VMs: names (comma-separated) or a query string (prefix with 'where ')")
+	cmd.Flags().StringVar(&defaultTargetNetwork, "default-target-network", "default", "Target of the inferred mapping: 'default' for pod networking, 'namespace/name' or 'name' for a NAD")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Create the inferred mapping instead of printing it for review")
+	flags.AddDryRunFlag(cmd, dryRunMode, "Print mapping CR instead of creating (client), or submit with server-side dry run to validate without persisting (server)")
 	cmd.Flags().StringVarP(&outputFormat, "output", "o", "", "Output format for dry-run (json, yaml). Defaults to yaml when --dry-run is used")
 
 	_ = cmd.RegisterFlagCompletionFunc("source", completion.ProviderNameCompletion(kubeConfigFlags))
 	_ = cmd.RegisterFlagCompletionFunc("target", completion.ProviderNameCompletion(kubeConfigFlags))
+	_ = cmd.RegisterFlagCompletionFunc("from-plan", completion.PlanNameCompletion(kubeConfigFlags))
 
 	flags.MarkRequiredForMCP(cmd, "name")
 
@@ -125,7 +179,9 @@ func newStorageMappingCmd(kubeConfigFlags *genericclioptions.ConfigFlags, global
 	var offloadStorageUsername, offloadStoragePassword, offloadStorageEndpoint string
 	var offloadCACert string
 	var offloadInsecureSkipTLS bool
-	var dryRun bool
+	var fromPlan, vms, defaultTargetStorageClass string
+	var yes bool
+	dryRunMode := flags.NewDryRunFlag()
 	var outputFormat string
 
 	cmd := &cobra.Command{
@@ -135,7 +191,14 @@ func newStorageMappingCmd(kubeConfigFlags *genericclioptions.ConfigFlags, global
 
 Storage mappings translate source datastores/storage domains to target Kubernetes
 storage classes. Advanced options include volume mode, access mode, and offload
-plugin configuration for optimized data transfer.`,
+plugin configuration for optimized data transfer.
+
+Inferring a mapping:
+  Instead of listing pairs, use --from-plan or --vms to inspect a set of VMs in the
+  source inventory and propose a mapping for the datastores/storage domains they use.
+  All of them are mapped to --default-target-storage-class (the cluster default
+  StorageClass unless overridden). The proposed mapping is printed for review; add
+  --yes to create it.`,
 		Example: `  # Create a simple storage mapping
   kubectl-mtv create mapping storage --name my-storage-map \
     --source vsphere-prod \
@@ -153,10 +216,24 @@ plugin configuration for optimized data transfer.`,
   kubectl-mtv create mapping storage --name my-storage-map \
     --source vsphere-prod \
     --target host \
-    --storage-pairs "datastore1:ocs-storagecluster-ceph-rbd;offloadPlugin=vsphere;offloadVendor=ontap"`,
+    --storage-pairs "datastore1:ocs-storagecluster-ceph-rbd;offloadPlugin=vsphere;offloadVendor=ontap"
+
+  # Propose a storage mapping for the VMs of an existing plan
+  kubectl-mtv create mapping storage --name wave2-storage --from-plan wave2
+
+  # Create a mapping for the datastores of the VMs matching a query
+  kubectl-mtv create mapping storage --name prod-storage \
+    --source vsphere-prod \
+    --target host \
+    --vms "where name ~= 'prod-.*'" \
+    --default-target-storage-class ocs-storagecluster-ceph-rbd \
+    --yes`,
 		Args:         cobra.MaximumNArgs(1),
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			// Client dry run prints the resources, server dry run sends them with dryRun=All
+			dryRun := dryRunMode.IsClient()
+
 			if err := flags.ResolveNameArg(&name, args); err != nil {
 				return err
 			}
@@ -171,10 +248,44 @@ plugin configuration for optimized data transfer.`,
 			inventoryURL := globalConfig.GetInventoryURL()
 			inventoryInsecureSkipTLS := globalConfig.GetInventoryInsecureSkipTLS()
 
-			if !dryRun && outputFormat != "" {
+			if fromPlan != "" || vms != "" {
+				if storagePairs != "" {
+					return fmt.Errorf("--storage-pairs cannot be used with --from-plan or --vms, the pairs are inferred from the VMs")
+				}
+				for _, f := range []string{"default-offload-plugin", "default-offload-secret", "default-offload-vendor", "default-offload-migration-hosts", "offload-vsphere-username", "offload-storage-username"} {
+					if cmd.Flags().Changed(f) {
+						return fmt.Errorf("--%s cannot be used with --from-plan or --vms, edit the created mapping to add offload settings", f)
+					}
+				}
+				format, err := inferOutputFormat(yes, dryRunMode.Enabled(), outputFormat)
+				if err != nil {
+					return err
+				}
+				return mapping.InferStorage(cmd.Context(), mapping.InferOptions{
+					ConfigFlags:               kubeConfigFlags,
+					Name:                      name,
+					Namespace:                 namespace,
+					SourceProvider:            sourceProvider,
+					TargetProvider:            targetProvider,
+					InventoryURL:              inventoryURL,
+					InventoryInsecureSkipTLS:  inventoryInsecureSkipTLS,
+					FromPlan:                  fromPlan,
+					VMs:                       vms,
+					DefaultTargetStorageClass: defaultTargetStorageClass,
+					DefaultVolumeMode:         defaultVolumeMode,
+					DefaultAccessMode:         defaultAccessMode,
+					Yes:                       yes,
+					OutputFormat:              format,
+				})
+			}
+			if yes || defaultTargetStorageClass != "" {
+				return fmt.Errorf("--yes and --default-target-storage-class require --from-plan or --vms")
+			}
+
+			if !dryRunMode.Enabled() && outputFormat != "" {
 				return fmt.Errorf("--output flag can only be used with --dry-run")
 			}
-			if dryRun && outputFormat != "" && outputFormat != "json" && outputFormat != "yaml" {
+			if dryRunMode.Enabled() && outputFormat != "" && outputFormat != "json" && outputFormat != "yaml" {
 				return fmt.Errorf("invalid output format for dry-run: %s. Valid formats are: json, yaml", outputFormat)
 			}
 			if dryRun && outputFormat == "" {
@@ -215,6 +326,10 @@ plugin configuration for optimized data transfer.`,
 	cmd.Flags().StringVarP(&sourceProvider, "source", "S", "", "Source provider name")
 	cmd.Flags().StringVarP(&targetProvider, "target", "T", "", "Target provider name")
 	cmd.Flags().StringVar(&storagePairs, "storage-pairs", "", "Storage mapping pairs in format 'source:storage-class[;volumeMode=Block|Filesystem][;accessMode=ReadWriteOnce|ReadWriteMany|ReadOnlyMany][;offloadPlugin=vsphere][;offloadSecret=secret-name][;offloadVendor=vantara|ontap|...]' (comma-separated pairs, semicolon-separated parameters)")
+	cmd.Flags().StringVar(&fromPlan, "from-plan", "", "Infer the mapping from the storage used by the VMs of this plan (also sets --source and --target)")
+	cmd.Flags().StringVar(&vms, "vms", "", "Infer the mapping from the storage used by these VMs: names (comma-separated) or a query string (prefix with 'where ')")
+	cmd.Flags().StringVar(&defaultTargetStorageClass, "default-target-storage-class", "", "Target storage class of the inferred mapping (defaults to the cluster default StorageClass)")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Create the inferred mapping instead of printing it for review")
 	cmd.Flags().StringVar(&defaultVolumeMode, "default-volume-mode", "", "Default volume mode for all storage pairs (Filesystem|Block)")
 	cmd.Flags().StringVar(&defaultAccessMode, "default-access-mode", "", "Default access mode for all storage pairs (ReadWriteOnce|ReadWriteMany|ReadOnlyMany)")
 	cmd.Flags().StringVar(&defaultOffloadPlugin, "default-offload-plugin", "", "Default offload plugin type for all storage pairs (vsphere)")
@@ -231,11 +346,12 @@ plugin configuration for optimized data transfer.`,
 	cmd.Flags().StringVar(&offloadStorageEndpoint, "offload-storage-endpoint", "", "Storage array management endpoint URL for offload secret")
 	cmd.Flags().StringVar(&offloadCACert, "offload-cacert", "", "CA certificate for offload secret (use @filename to load from file)")
 	cmd.Flags().BoolVar(&offloadInsecureSkipTLS, "offload-insecure-skip-tls", false, "Skip TLS verification for offload connections")
-	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Output mapping CR to stdout instead of creating it")
+	flags.AddDryRunFlag(cmd, dryRunMode, "Print mapping CR instead of creating (client), or submit with server-side dry run to validate without persisting (server)")
 	cmd.Flags().StringVarP(&outputFormat, "output", "o", "", "Output format for dry-run (json, yaml). Defaults to yaml when --dry-run is used")
 
 	_ = cmd.RegisterFlagCompletionFunc("source", completion.ProviderNameCompletion(kubeConfigFlags))
 	_ = cmd.RegisterFlagCompletionFunc("target", completion.ProviderNameCompletion(kubeConfigFlags))
+	_ = cmd.RegisterFlagCompletionFunc("from-plan", completion.PlanNameCompletion(kubeConfigFlags))
 
 	if err := cmd.RegisterFlagCompletionFunc("default-volume-mode", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		return []string{"Filesystem", "Block"}, cobra.ShellCompDirectiveNoFileComp
@@ -268,3 +384,21 @@ plugin configuration for optimized data transfer.`,
 
 	return cmd
 }
+
+// inferOutputFormat returns the output format of an inferred mapping. Without --yes the proposed
+// mapping is printed, in yaml unless --output is set.
+func inferOutputFormat(yes, dryRun bool, outputFormat string) (string, error) {
+	if yes && dryRun {
+		return "", fmt.Errorf("--yes and --dry-run cannot be used together")
+	}
+	if yes && outputFormat != "" {
+		return "", fmt.Errorf("--output cannot be used with --yes")
+	}
+	if outputFormat != "" && outputFormat != "json" && outputFormat != "yaml" {
+		return "", fmt.Errorf("invalid output format: %s. Valid formats are: json, yaml", outputFormat)
+	}
+	if !yes && outputFormat == "" {
+		outputFormat = "yaml"
+	}
+	return outputFormat, nil
+}
@@ -1,14 +1,18 @@
 package create
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	forkliftv1beta1 "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
 	planv1beta1 "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"github.com/yaacov/karl-interpreter/pkg/karl"
 	"gopkg.in/yaml.v3"
 	corev1 "k8s.io/api/core/v1"
@@ -16,9 +20,12 @@ import (
 
 	"github.com/yaacov/kubectl-mtv/pkg/cmd/create/plan"
 	"github.com/yaacov/kubectl-mtv/pkg/cmd/get/inventory"
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/get/plan/targetlabels"
+	startplan "github.com/yaacov/kubectl-mtv/pkg/cmd/start/plan"
 	"github.com/yaacov/kubectl-mtv/pkg/util/client"
 	"github.com/yaacov/kubectl-mtv/pkg/util/completion"
 	"github.com/yaacov/kubectl-mtv/pkg/util/flags"
+	"github.com/yaacov/kubectl-mtv/pkg/util/wait"
 )
 
 // parseKeyValuePairs parses a slice of strings containing comma-separated key=value pairs
@@ -73,11 +80,18 @@ func NewPlanCmd(kubeConfigFlags *genericclioptions.ConfigFlags, globalConfig Glo
 	var enableNestedVirtualization string // "true", "false", or "auto" for nil (auto-detect)
 	migrationTypeFlag := flags.NewMigrationTypeFlag()
 	var targetLabels []string
+	var labelTargetVMs []string
+	var maxConcurrentVMs int
 	var targetNodeSelector []string
 	var useCompatibilityMode bool
 	var targetAffinity string
 	var targetPowerState string
 
+	// Live migration (OpenShift to OpenShift) flags
+	var live bool
+	var destinationNodeSelector []string
+	var storageClassMap string
+
 	// Conversion temporary storage flags (providers requiring guest conversion)
 	var customizationScripts string
 
@@ -90,7 +104,16 @@ func NewPlanCmd(kubeConfigFlags *genericclioptions.ConfigFlags, globalConfig Glo
 	var tagMappingDisabled bool
 	var tagMappingLabelTags []string
 
-	var dryRun bool
+	// Cloning flags
+	var fromPlan string
+	var splitByProvider bool
+
+	// Template flags
+	var templateRef string
+	var templateValues []string
+
+	dryRunMode := flags.NewDryRunFlag()
+	var waitOpts waitFlags
 	var outputFormat string
 
 	cmd := &cobra.Command{
@@ -102,11 +125,49 @@ Only --name, --source, and --vms are required. All other flags are optional
 and have sensible defaults — only set them when you need to override the
 default behavior (see "Optional Fields" below).
 
+Cloning:
+  --from-plan copies the spec of an existing plan (name or namespace/name) into
+  a new plan, for retries after a failure or phased rollouts across namespaces.
+  --source and --vms are not needed. The clone starts without status and
+  unarchived, and the mappings of the plan are copied as <name>-network and
+  <name>-storage. Only --vms, --target-namespace, --description and the mapping
+  flags (--network-mapping, --storage-mapping, --network-pairs, --storage-pairs)
+  override copied values; change other fields with 'kubectl-mtv patch plan'.
+
+Templates:
+  --template applies a reusable plan template, e.g. a team migration policy.
+  A template is YAML with parameters and create plan flag values, which may use
+  the parameters and the built-in {{ .name }} and {{ .namespace }} of the plan:
+    parameters:
+      - name: source
+        required: true
+    flags:
+      source: "{{ .source }}"
+      migration-type: warm
+  --set key=value fills a parameter. Flags given on the command line override
+  the template. A template is a file path, or a name looked up as <name>.yaml
+  in the plan-templates directory of the kubectl-mtv config directory (e.g.
+  ~/.config/kubectl-mtv/plan-templates), then as a key of the mtv-plan-templates
+  ConfigMap in the plan namespace.
+
 VMs can be specified as:
   - Comma-separated names: --vms "vm1,vm2,vm3"
   - TSL query: --vms "where name ~= 'prod-.*' and cpuCount <= 8"
   - YAML/JSON file: --vms @vms.yaml
 
+Multiple providers:
+  A plan has a single source provider. VM files written by 'kubectl-mtv get
+  inventory vms --output planvms' record the provider of each VM, and VMs of
+  other providers than --source are rejected by name. --split-by-provider
+  instead creates one plan per provider, named <name>-<provider>, each with
+  auto-generated mappings (--source is then only used for VMs that record no
+  provider).
+
+Waiting:
+  --wait blocks until Forklift validated the plan and it is Ready to start,
+  printing failed validations to stderr. The command fails when validations
+  keep failing (e.g. a VM that is not found) or --wait-timeout expires.
+
 Providers:
   --source is the name of the source provider resource (e.g. "vsphere-prod").
   --target is the name of the target provider resource (e.g. "host", "ocp-target").
@@ -147,6 +208,14 @@ Query Language (TSL):
     --vms "where len(disks) > 1"
   Run 'kubectl-mtv help tsl' for the full syntax reference and field list.
 
+Live Migration (OpenShift to OpenShift):
+  --live moves running VMs between two OpenShift clusters (same as
+  --migration-type live). Both --source and --target must be OpenShift
+  providers of different clusters, and guest conversion flags do not apply.
+    --destination-node-selector  - Nodes of the destination cluster to run VMs on
+    --storage-class-map          - "src-sc:dst-sc" pairs translating source storage
+                                   classes to destination classes for the CSI clones
+
 Affinity Syntax (KARL):
   The --target-affinity and --convertor-affinity flags use KARL syntax:
     --target-affinity "REQUIRE pods(app=database) on node"
@@ -202,10 +271,57 @@ Affinity Syntax (KARL):
     --source vsphere-prod \
     --vms "quick-vm" \
     --run-preflight-inspection false \
-    --preserve-static-ips false`,
+    --preserve-static-ips false
+
+  # Ensure migrated VMs are labeled (verified after migration)
+  kubectl-mtv create plan --name labeled \
+    --source vsphere-prod \
+    --vms "web-server" \
+    --label-target-vms "migrated-by=mtv,wave=7"
+
+  # Migrate at most 5 VMs of a large plan at a time
+  kubectl-mtv create plan --name big-batch \
+    --source vsphere-prod \
+    --vms "where name ~= '^app-.*'" \
+    --max-concurrent-vms 5
+
+  # Live migrate running VMs to another OpenShift cluster
+  kubectl-mtv create plan --name cross-cluster \
+    --source ocp-east \
+    --target ocp-west \
+    --vms "web-server,db-server" \
+    --live \
+    --destination-node-selector "node-role.kubernetes.io/worker=" \
+    --storage-class-map "ocs-storagecluster-ceph-rbd:gp3-csi"
+
+  # Create one plan per provider from a VM list combining several providers
+  kubectl-mtv get inventory vms --provider vsphere-east --output planvms > vms.yaml
+  kubectl-mtv get inventory vms --provider vsphere-west --output planvms >> vms.yaml
+  kubectl-mtv create plan --name datacenter-move --vms @vms.yaml --split-by-provider
+
+  # Clone a failed plan to retry only the VMs that failed
+  kubectl-mtv create plan wave1-retry --from-plan wave1 --vms "db-server"
+
+  # Clone a plan into another namespace, targeting a different VM namespace
+  kubectl-mtv create plan wave2 --namespace team-b \
+    --from-plan team-a/wave1 \
+    --target-namespace team-b-vms
+
+  # Create a plan from the team's gold-warm template
+  kubectl-mtv create plan --name wave3 --template gold-warm \
+    --set source=vsphere-prod --set query="where name ~= 'web-.*'"
+
+  # Create a plan and block until it passed validation
+  kubectl-mtv create plan --name my-migration \
+    --source vsphere-prod \
+    --vms "web-server,db-server" \
+    --wait`,
 		Args:         cobra.MaximumNArgs(1),
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			// Client dry run prints the resources, server dry run sends them with dryRun=All
+			dryRun := dryRunMode.IsClient()
+
 			if err := flags.ResolveNameArg(&name, args); err != nil {
 				return err
 			}
@@ -216,10 +332,48 @@ Affinity Syntax (KARL):
 			// Resolve the appropriate namespace based on context and flags
 			namespace := client.ResolveNamespace(kubeConfigFlags)
 
+			// A template sets the flags not given on the command line
+			if templateRef != "" {
+				if fromPlan != "" {
+					return fmt.Errorf("cannot use --template with --from-plan")
+				}
+				if err := applyPlanTemplate(cmd, kubeConfigFlags, templateRef, templateValues, name, namespace); err != nil {
+					return err
+				}
+			} else if len(templateValues) > 0 {
+				return fmt.Errorf("--set requires --template")
+			}
+
 			// Get inventory URL and insecure skip TLS from global config (auto-discovers if needed)
 			inventoryURL := globalConfig.GetInventoryURL()
 			inventoryInsecureSkipTLS := globalConfig.GetInventoryInsecureSkipTLS()
 
+			// Cloning copies the spec of an existing plan, including its providers
+			var cloneSource *plan.CloneSource
+			if fromPlan != "" {
+				if err := validateCloneFlags(cmd); err != nil {
+					return err
+				}
+				var err error
+				cloneSource, err = plan.GetCloneSource(cmd.Context(), kubeConfigFlags, fromPlan, namespace)
+				if err != nil {
+					return err
+				}
+				sourceProvider = cloneSource.SourceProvider()
+			} else if vmNamesQuaryOrFile == "" || (sourceProvider == "" && !splitByProvider) {
+				return fmt.Errorf("--source and --vms are required unless --from-plan is given")
+			}
+
+			// Split plans take their source providers from the VM list
+			if splitByProvider {
+				if networkMapping != "" || storageMapping != "" {
+					return fmt.Errorf("cannot use --network-mapping or --storage-mapping with --split-by-provider, mappings belong to a single source provider")
+				}
+				if sourceProvider == "" && strings.HasPrefix(vmNamesQuaryOrFile, "where ") {
+					return fmt.Errorf("--source is required when --vms is a query")
+				}
+			}
+
 			// Validate that existing mapping flags and mapping pair flags are not used together
 			if networkMapping != "" && networkPairs != "" {
 				return fmt.Errorf("cannot use both --network-mapping and --network-pairs flags")
@@ -228,6 +382,53 @@ Affinity Syntax (KARL):
 				return fmt.Errorf("cannot use both --storage-mapping and --storage-pairs flags")
 			}
 
+			if !dryRunMode.Enabled() && outputFormat != "" {
+				return fmt.Errorf("--output flag can only be used with --dry-run")
+			}
+			if dryRunMode.Enabled() && outputFormat != "" && outputFormat != "json" && outputFormat != "yaml" {
+				return fmt.Errorf("invalid output format for dry-run: %s. Valid formats are: json, yaml", outputFormat)
+			}
+			resolvedFormat := outputFormat
+			if dryRun && resolvedFormat == "" {
+				resolvedFormat = "yaml"
+			}
+			wait, err := waitOpts.enabled(cmd, dryRunMode.Enabled())
+			if err != nil {
+				return err
+			}
+
+			// Handle live migration shorthands (OpenShift to OpenShift)
+			if live {
+				if migrationTypeFlag.GetValue() != "" && migrationTypeFlag.GetValue() != "live" {
+					return fmt.Errorf("cannot use --live with --migration-type %s", migrationTypeFlag.GetValue())
+				}
+				_ = migrationTypeFlag.Set("live")
+			}
+			if migrationTypeFlag.GetValue() != "live" {
+				if len(destinationNodeSelector) > 0 {
+					return fmt.Errorf("--destination-node-selector requires --live, use --target-node-selector for other migration types")
+				}
+				if storageClassMap != "" {
+					return fmt.Errorf("--storage-class-map requires --live, use --storage-pairs for other migration types")
+				}
+			}
+			if len(destinationNodeSelector) > 0 {
+				if len(targetNodeSelector) > 0 {
+					return fmt.Errorf("cannot use both --destination-node-selector and --target-node-selector flags")
+				}
+				targetNodeSelector = destinationNodeSelector
+			}
+			if storageClassMap != "" {
+				if storageMapping != "" || storagePairs != "" {
+					return fmt.Errorf("cannot use --storage-class-map with --storage-mapping or --storage-pairs")
+				}
+				pairs, err := plan.ParseStorageClassMap(storageClassMap)
+				if err != nil {
+					return err
+				}
+				storagePairs = pairs
+			}
+
 			// Validate that conversion-only migrations don't use storage mappings
 			if migrationTypeFlag.GetValue() == "conversion" {
 				if storageMapping != "" {
@@ -238,63 +439,51 @@ Affinity Syntax (KARL):
 				}
 			}
 
-			var vmList []planv1beta1.VM
-
-			if strings.HasPrefix(vmNamesQuaryOrFile, "where ") {
-				// It's a query string - fetch VMs from inventory
-				query := vmNamesQuaryOrFile // The full string including "where "
-
-				// Parse source provider to extract name and namespace
-				sourceProviderName := sourceProvider
-				sourceProviderNamespace := namespace
-				if strings.Contains(sourceProvider, "/") {
-					parts := strings.SplitN(sourceProvider, "/", 2)
-					sourceProviderNamespace = strings.TrimSpace(parts[0])
-					sourceProviderName = strings.TrimSpace(parts[1])
-				}
-
-				fmt.Printf("Fetching VMs from provider '%s' using query: %s\n", sourceProviderName, query)
-
-				var err error
-				vmList, err = inventory.FetchVMsByQueryWithInsecure(cmd.Context(), kubeConfigFlags, sourceProviderName, sourceProviderNamespace, inventoryURL, query, inventoryInsecureSkipTLS)
-				if err != nil {
-					return fmt.Errorf("failed to fetch VMs using query: %v", err)
-				}
+			vmEntries, err := parseVMList(cmd.Context(), kubeConfigFlags, vmNamesQuaryOrFile, sourceProvider, namespace, inventoryURL, inventoryInsecureSkipTLS)
+			if err != nil {
+				return err
+			}
 
-				if len(vmList) == 0 {
-					return fmt.Errorf("no VMs found matching the query")
+			// A plan has a single source provider, VMs listed from other providers are rejected
+			if !splitByProvider {
+				if err := plan.CheckVMProviders(vmEntries, sourceProvider, namespace); err != nil {
+					return err
 				}
+			}
 
-				fmt.Printf("Found %d VM(s) matching the query\n", len(vmList))
-			} else if strings.HasPrefix(vmNamesQuaryOrFile, "@") {
-				// It's a file
-				filePath := vmNamesQuaryOrFile[1:]
-				content, err := os.ReadFile(filePath)
-				if err != nil {
-					return fmt.Errorf("failed to read file %s: %v", filePath, err)
+			// A clone keeps the settings of the plan, with only the supported overrides
+			if cloneSource != nil {
+				opts := plan.CreatePlanOptions{
+					Name:                     name,
+					Namespace:                namespace,
+					NetworkMapping:           networkMapping,
+					StorageMapping:           storageMapping,
+					NetworkPairs:             networkPairs,
+					StoragePairs:             storagePairs,
+					ConfigFlags:              kubeConfigFlags,
+					InventoryURL:             inventoryURL,
+					InventoryInsecureSkipTLS: inventoryInsecureSkipTLS,
+					PlanSpec: forkliftv1beta1.PlanSpec{
+						VMs:             inventory.PlanVMsOf(vmEntries),
+						TargetNamespace: planSpec.TargetNamespace,
+						Description:     planSpec.Description,
+					},
+					DryRun:       dryRun,
+					OutputFormat: resolvedFormat,
 				}
-
-				// Attempt to unmarshal as YAML first, then try JSON
-				err = yaml.Unmarshal(content, &vmList)
-				if err != nil {
-					err = json.Unmarshal(content, &vmList)
-					if err != nil {
-						return fmt.Errorf("failed to unmarshal file %s as YAML or JSON: %v", filePath, err)
-					}
+				cloneSource.Apply(&opts)
+				if err := plan.Create(cmd.Context(), opts); err != nil {
+					return err
 				}
-			} else {
-				// It's a comma-separated list
-				vmNameSlice := strings.Split(vmNamesQuaryOrFile, ",")
-				for _, vmName := range vmNameSlice {
-					newVM := planv1beta1.VM{}
-					newVM.Name = strings.TrimSpace(vmName)
-					vmList = append(vmList, newVM)
+				if wait {
+					return waitForPlans(cmd, kubeConfigFlags, namespace, []string{name}, waitOpts)
 				}
+				return nil
 			}
 
 			// Add hooks to all VMs if specified
 			if preHook != "" || postHook != "" {
-				for i := range vmList {
+				for i := range vmEntries {
 					var hooks []planv1beta1.HookRef
 
 					// Add pre-hook if specified
@@ -326,7 +515,7 @@ Affinity Syntax (KARL):
 					}
 
 					// Add hooks to the VM (append to existing hooks if any)
-					vmList[i].Hooks = append(vmList[i].Hooks, hooks...)
+					vmEntries[i].Hooks = append(vmEntries[i].Hooks, hooks...)
 				}
 			}
 
@@ -401,6 +590,32 @@ Affinity Syntax (KARL):
 				planSpec.TargetLabels = labels
 			}
 
+			// Handle enforced target VM labels (added to targetLabels and verified after migration)
+			var annotations map[string]string
+			if len(labelTargetVMs) > 0 {
+				enforced, err := targetlabels.Parse(labelTargetVMs)
+				if err != nil {
+					return err
+				}
+				merged, err := targetlabels.Merge(planSpec.TargetLabels, enforced)
+				if err != nil {
+					return err
+				}
+				planSpec.TargetLabels = merged
+				annotations = map[string]string{targetlabels.EnforcedLabelsAnnotation: targetlabels.Format(enforced)}
+			}
+
+			// Handle per-plan VM concurrency (enforced by start plan, which migrates in batches)
+			if cmd.Flags().Changed("max-concurrent-vms") {
+				if err := startplan.ValidateMaxConcurrentVMs(cmd.Context(), kubeConfigFlags, maxConcurrentVMs); err != nil {
+					return err
+				}
+				if annotations == nil {
+					annotations = map[string]string{}
+				}
+				annotations[startplan.MaxConcurrentVMsAnnotation] = strconv.Itoa(maxConcurrentVMs)
+			}
+
 			// Handle target node selector (convert from key=value slice to map)
 			if len(targetNodeSelector) > 0 {
 				nodeSelector, err := parseKeyValuePairs(targetNodeSelector, "target node selector")
@@ -490,18 +705,7 @@ Affinity Syntax (KARL):
 			planSpec.UseCompatibilityMode = useCompatibilityMode
 
 			// Set VMs in the PlanSpec
-			planSpec.VMs = vmList
-
-			if !dryRun && outputFormat != "" {
-				return fmt.Errorf("--output flag can only be used with --dry-run")
-			}
-			if dryRun && outputFormat != "" && outputFormat != "json" && outputFormat != "yaml" {
-				return fmt.Errorf("invalid output format for dry-run: %s. Valid formats are: json, yaml", outputFormat)
-			}
-			resolvedFormat := outputFormat
-			if dryRun && resolvedFormat == "" {
-				resolvedFormat = "yaml"
-			}
+			planSpec.VMs = inventory.PlanVMsOf(vmEntries)
 
 			opts := plan.CreatePlanOptions{
 				Name:                         name,
@@ -516,6 +720,7 @@ Affinity Syntax (KARL):
 				DefaultTargetNetwork:         defaultTargetNetwork,
 				DefaultTargetStorageClass:    defaultTargetStorageClass,
 				PlanSpec:                     planSpec,
+				Annotations:                  annotations,
 				NetworkPairs:                 networkPairs,
 				StoragePairs:                 storagePairs,
 				DefaultVolumeMode:            defaultVolumeMode,
@@ -537,13 +742,33 @@ Affinity Syntax (KARL):
 				OutputFormat:           resolvedFormat,
 			}
 
-			err := plan.Create(cmd.Context(), opts)
-			return err
+			if splitByProvider {
+				groups, err := plan.SplitByProvider(vmEntries, sourceProvider, namespace)
+				if err != nil {
+					return err
+				}
+				created, err := plan.CreatePerProvider(cmd.Context(), opts, groups)
+				if err != nil {
+					return err
+				}
+				if wait {
+					return waitForPlans(cmd, kubeConfigFlags, namespace, created, waitOpts)
+				}
+				return nil
+			}
+
+			if err := plan.Create(cmd.Context(), opts); err != nil {
+				return err
+			}
+			if wait {
+				return waitForPlans(cmd, kubeConfigFlags, namespace, []string{name}, waitOpts)
+			}
+			return nil
 		},
 	}
 
 	cmd.Flags().StringVarP(&name, "name", "M", "", "Plan name")
-	cmd.Flags().StringVarP(&sourceProvider, "source", "S", "", "Source provider name (supports namespace/name pattern, defaults to plan namespace)")
+	cmd.Flags().StringVarP(&sourceProvider, "source", "S", "", "Source provider name (supports namespace/name pattern, defaults to plan namespace). Required unless --from-plan is given")
 	cmd.Flags().StringVarP(&targetProvider, "target", "t", "", "Target provider name (auto-detects first OpenShift provider when omitted)")
 	cmd.Flags().StringVar(&networkMapping, "network-mapping", "", "Network mapping name (auto-generated when omitted)")
 	cmd.Flags().StringVar(&storageMapping, "storage-mapping", "", "Storage mapping name (auto-generated when omitted)")
@@ -569,9 +794,11 @@ Affinity Syntax (KARL):
 	cmd.Flags().BoolVar(&offloadInsecureSkipTLS, "offload-insecure-skip-tls", false, "Skip TLS verification for offload connections")
 
 	flags.MarkRequiredForMCP(cmd, "name")
-	_ = cmd.MarkFlagRequired("source")
-	cmd.Flags().StringVar(&vmNamesQuaryOrFile, "vms", "", "List of VM names (comma-separated), path to YAML/JSON file (prefix with @), or query string (prefix with 'where ')")
-	_ = cmd.MarkFlagRequired("vms")
+	cmd.Flags().StringVar(&vmNamesQuaryOrFile, "vms", "", "List of VM names (comma-separated), path to YAML/JSON file (prefix with @), or query string (prefix with 'where '). Required unless --from-plan is given")
+	cmd.Flags().BoolVar(&splitByProvider, "split-by-provider", false, "Create one plan per source provider when the VM list (from 'get inventory vms --output planvms') spans several providers")
+	cmd.Flags().StringVar(&templateRef, "template", "", "Plan template to apply: a file path, or a name in the local plan-templates directory or the mtv-plan-templates ConfigMap")
+	cmd.Flags().StringArrayVar(&templateValues, "set", nil, "Template parameter as key=value (can be repeated)")
+	cmd.Flags().StringVar(&fromPlan, "from-plan", "", "Clone the spec of this plan (name or namespace/name); mappings are copied and status is not")
 	cmd.Flags().StringVar(&preHook, "pre-hook", "", "Pre-migration hook to add to all VMs in the plan")
 	cmd.Flags().StringVar(&postHook, "post-hook", "", "Post-migration hook to add to all VMs in the plan")
 
@@ -597,11 +824,18 @@ Affinity Syntax (KARL):
 	cmd.Flags().StringVar(&defaultTargetStorageClass, "default-target-storage-class", "", "Default target storage class for auto-generated mapping")
 	flags.ExplicitBoolVar(cmd.Flags(), &useCompatibilityMode, "use-compatibility-mode", true, "Use compatibility devices (SATA bus, E1000E NIC) when skipGuestConversion is true (true/false)")
 	cmd.Flags().StringSliceVarP(&targetLabels, "target-labels", "L", nil, "Target labels to be added to the VM (e.g., key1=value1,key2=value2)")
+	cmd.Flags().IntVar(&maxConcurrentVMs, "max-concurrent-vms", 0, "Maximum VMs of this plan migrating at the same time; start plan migrates larger plans in batches (must not exceed controller_max_vm_inflight)")
+	cmd.Flags().StringSliceVar(&labelTargetVMs, "label-target-vms", nil, "Labels the migrated VirtualMachines must carry, added to target labels and verified after migration (e.g., migrated-by=mtv,wave=7)")
 	cmd.Flags().StringSliceVar(&targetNodeSelector, "target-node-selector", nil, "Target node selector to constrain VM scheduling (e.g., key1=value1,key2=value2)")
 	cmd.Flags().BoolVar(&planSpec.Warm, "warm", false, "Enable warm migration (use --migration-type=warm instead)")
 	cmd.Flags().StringVar(&targetAffinity, "target-affinity", "", "Target affinity to constrain VM scheduling using KARL syntax (e.g. 'REQUIRE pods(app=database) on node')")
 	cmd.Flags().StringVar(&targetPowerState, "target-power-state", "", "Target power state for VMs after migration: 'on', 'off', or 'auto' (default: match source VM power state)")
 
+	// Live migration flags (OpenShift to OpenShift providers)
+	cmd.Flags().BoolVar(&live, "live", false, "Live migrate running VMs between OpenShift clusters (same as --migration-type live)")
+	cmd.Flags().StringSliceVar(&destinationNodeSelector, "destination-node-selector", nil, "Node selector on the destination cluster for live migrated VMs (e.g., key1=value1,key2=value2)")
+	cmd.Flags().StringVar(&storageClassMap, "storage-class-map", "", "Translate source to destination storage classes for live migration CSI clones. Format: 'source-sc:target-sc' (comma-separated)")
+
 	// Convertor-related flags (only apply to providers requiring guest conversion)
 	cmd.Flags().StringSliceVar(&convertorLabels, "convertor-labels", nil, "Labels to be added to virt-v2v convertor pods (e.g., key1=value1,key2=value2)")
 	cmd.Flags().StringSliceVar(&convertorNodeSelector, "convertor-node-selector", nil, "Node selector to constrain convertor pod scheduling (e.g., key1=value1,key2=value2)")
@@ -615,7 +849,8 @@ Affinity Syntax (KARL):
 	cmd.Flags().BoolVar(&planSpec.SkipZoneNodeSelector, "skip-zone-node-selector", false, "Skip adding zone-based node selector to migrated VMs (EC2 only)")
 	cmd.Flags().StringVar(&customizationScripts, "customization-scripts", "", "ConfigMap containing customization scripts for guest conversion. Supports 'namespace/name' or 'name'")
 	cmd.Flags().StringVar(&planSpec.VirtV2vImage, "virt-v2v-image", "", "Override global virt-v2v container image for this plan")
-	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Output Plan CR(s) to stdout instead of creating them")
+	flags.AddDryRunFlag(cmd, dryRunMode, "Print Plan CR(s) instead of creating (client), or submit with server-side dry run to validate without persisting (server)")
+	waitOpts.add(cmd, "the plan passed validation and is Ready; fails when validations keep failing")
 	cmd.Flags().StringVarP(&outputFormat, "output", "o", "", "Output format for dry-run (json, yaml). Defaults to yaml when --dry-run is used")
 	cmd.Flags().StringVar(&enableNestedVirtualization, "enable-nested-virtualization", "auto", "Enable nested virtualization on target VMs (true/false/auto)")
 	cmd.Flags().BoolVar(&planSpec.XfsCompatibility, "xfs-compatibility", false, "Use XFS-compatible virt-v2v image for this plan")
@@ -626,6 +861,7 @@ Affinity Syntax (KARL):
 
 	_ = cmd.RegisterFlagCompletionFunc("source", completion.ProviderNameCompletion(kubeConfigFlags))
 	_ = cmd.RegisterFlagCompletionFunc("target", completion.ProviderNameCompletionByType(kubeConfigFlags, "openshift"))
+	_ = cmd.RegisterFlagCompletionFunc("from-plan", completion.PlanNameCompletion(kubeConfigFlags))
 	_ = cmd.RegisterFlagCompletionFunc("network-mapping", completion.MappingNameCompletion(kubeConfigFlags, "network"))
 	_ = cmd.RegisterFlagCompletionFunc("storage-mapping", completion.MappingNameCompletion(kubeConfigFlags, "storage"))
 
@@ -692,3 +928,127 @@ Affinity Syntax (KARL):
 
 	return cmd
 }
+
+// parseVMList reads the --vms value: a query (prefix with 'where '), a YAML/JSON file (prefix
+// with @), or comma-separated VM names. An empty value returns no VMs.
+func parseVMList(ctx context.Context, kubeConfigFlags *genericclioptions.ConfigFlags, vmNamesQuaryOrFile, sourceProvider, namespace, inventoryURL string, inventoryInsecureSkipTLS bool) ([]inventory.PlanVM, error) {
+	var vmList []inventory.PlanVM
+
+	if vmNamesQuaryOrFile == "" {
+		return nil, nil
+	}
+
+	if strings.HasPrefix(vmNamesQuaryOrFile, "where ") {
+		// It's a query string - fetch VMs from inventory
+		query := vmNamesQuaryOrFile // The full string including "where "
+
+		// Parse source provider to extract name and namespace
+		sourceProviderName := sourceProvider
+		sourceProviderNamespace := namespace
+		if strings.Contains(sourceProvider, "/") {
+			parts := strings.SplitN(sourceProvider, "/", 2)
+			sourceProviderNamespace = strings.TrimSpace(parts[0])
+			sourceProviderName = strings.TrimSpace(parts[1])
+		}
+
+		fmt.Printf("Fetching VMs from provider '%s' using query: %s\n", sourceProviderName, query)
+
+		vms, err := inventory.FetchVMsByQueryWithInsecure(ctx, kubeConfigFlags, sourceProviderName, sourceProviderNamespace, inventoryURL, query, inventoryInsecureSkipTLS)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch VMs using query: %v", err)
+		}
+		for _, vm := range vms {
+			vmList = append(vmList, inventory.PlanVM{VM: vm, Provider: sourceProviderNamespace + "/" + sourceProviderName})
+		}
+
+		if len(vmList) == 0 {
+			return nil, fmt.Errorf("no VMs found matching the query")
+		}
+
+		fmt.Printf("Found %d VM(s) matching the query\n", len(vmList))
+	} else if strings.HasPrefix(vmNamesQuaryOrFile, "@") {
+		// It's a file
+		filePath := vmNamesQuaryOrFile[1:]
+		content, err := os.ReadFile(filePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read file %s: %v", filePath, err)
+		}
+
+		// Attempt to unmarshal as YAML first, then try JSON
+		err = yaml.Unmarshal(content, &vmList)
+		if err != nil {
+			err = json.Unmarshal(content, &vmList)
+			if err != nil {
+				return nil, fmt.Errorf("failed to unmarshal file %s as YAML or JSON: %v", filePath, err)
+			}
+		}
+	} else {
+		// It's a comma-separated list
+		vmNameSlice := strings.Split(vmNamesQuaryOrFile, ",")
+		for _, vmName := range vmNameSlice {
+			newVM := inventory.PlanVM{}
+			newVM.Name = strings.TrimSpace(vmName)
+			vmList = append(vmList, newVM)
+		}
+	}
+
+	return vmList, nil
+}
+
+// applyPlanTemplate loads a plan template, renders it with the --set values and sets the
+// create plan flags it defines that were not given on the command line
+func applyPlanTemplate(cmd *cobra.Command, kubeConfigFlags *genericclioptions.ConfigFlags, ref string, values []string, name, namespace string) error {
+	set, err := plan.ParseSetValues(values)
+	if err != nil {
+		return err
+	}
+	tmpl, err := plan.LoadTemplate(cmd.Context(), kubeConfigFlags, ref, namespace)
+	if err != nil {
+		return err
+	}
+	rendered, err := tmpl.Render(map[string]string{"name": name, "namespace": namespace}, set)
+	if err != nil {
+		return err
+	}
+	return plan.ApplyTemplateFlags(cmd.Flags(), rendered)
+}
+
+// cloneFlags are the create plan flags that can be combined with --from-plan
+var cloneFlags = map[string]bool{
+	"name":             true,
+	"from-plan":        true,
+	"vms":              true,
+	"target-namespace": true,
+	"description":      true,
+	"network-mapping":  true,
+	"storage-mapping":  true,
+	"network-pairs":    true,
+	"storage-pairs":    true,
+	"dry-run":          true,
+	"output":           true,
+	"wait":             true,
+	"wait-timeout":     true,
+}
+
+// validateCloneFlags rejects flags that a clone copies from the source plan
+func validateCloneFlags(cmd *cobra.Command) error {
+	var rejected []string
+	cmd.LocalFlags().Visit(func(f *pflag.Flag) {
+		if !cloneFlags[f.Name] {
+			rejected = append(rejected, "--"+f.Name)
+		}
+	})
+	if len(rejected) > 0 {
+		return fmt.Errorf("%s cannot be used with --from-plan, clone the plan first and then change it with 'kubectl-mtv patch plan'", strings.Join(rejected, ", "))
+	}
+	return nil
+}
+
+// waitForPlans waits for each created plan to become Ready, all within the wait timeout
+func waitForPlans(cmd *cobra.Command, kubeConfigFlags *genericclioptions.ConfigFlags, namespace string, names []string, w waitFlags) error {
+	return wait.ForEach(names, w.timeout, cmd.ErrOrStderr(), "plans", "are not Ready", func(name string, timeout time.Duration) error {
+		opts := w.options(cmd)
+		opts.Timeout = timeout
+		return plan.Wait(cmd.Context(), kubeConfigFlags, name, namespace, opts)
+	})
+}
@@ -3,15 +3,17 @@ package create
 import (
 	"fmt"
 	"os"
-	"strings"
 
 	"github.com/spf13/cobra"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 
 	"github.com/yaacov/kubectl-mtv/pkg/cmd/create/provider"
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/create/provider/ova"
 	"github.com/yaacov/kubectl-mtv/pkg/cmd/create/provider/providerutil"
+	"github.com/yaacov/kubectl-mtv/pkg/util/bugreport"
 	"github.com/yaacov/kubectl-mtv/pkg/util/client"
 	"github.com/yaacov/kubectl-mtv/pkg/util/flags"
+	"github.com/yaacov/kubectl-mtv/pkg/util/secretref"
 )
 
 // NewProviderCmd creates the provider creation command
@@ -21,6 +23,7 @@ func NewProviderCmd(kubeConfigFlags *genericclioptions.ConfigFlags) *cobra.Comma
 
 	// Add Provider credential flags
 	var url, username, password, cacert, token string
+	var cacertSource providerutil.CACertSource
 	var insecureSkipTLS bool
 	var vddkInitImage string
 	sdkEndpointType := flags.NewSdkEndpointTypeFlag()
@@ -38,6 +41,9 @@ func NewProviderCmd(kubeConfigFlags *genericclioptions.ConfigFlags) *cobra.Comma
 	var ec2TargetAccessKeyID, ec2TargetSecretKey string
 	var autoTargetCredentials bool
 
+	// OVA specific flags
+	var ovaFile, ovaUploadTo, ovaStorageClass, ovaPVCSize, ovaServerImage string
+
 	// HyperV specific flags
 	var smbUrl, smbUser, smbPassword string
 
@@ -45,8 +51,11 @@ func NewProviderCmd(kubeConfigFlags *genericclioptions.ConfigFlags) *cobra.Comma
 	var azureTenantID, azureSubscriptionID, azureClientID, azureClientSecret string
 	var azureResourceGroup, azureTargetRegion, azureSnapshotSku, azureSnapshotResourceGroup string
 
-	var dryRun bool
+	var fromEnv bool
+	var kubeconfigContext string
+	dryRunMode := flags.NewDryRunFlag()
 	var outputFormat string
+	var waitOpts waitFlags
 
 	// Check if MTV_VDDK_INIT_IMAGE environment variable is set
 	if envVddkInitImage := os.Getenv("MTV_VDDK_INIT_IMAGE"); envVddkInitImage != "" {
@@ -62,13 +71,44 @@ Providers represent source or target environments for VM migrations. Supported t
   - vsphere: VMware vSphere/vCenter (requires VDDK init image for migration)
   - ovirt: Red Hat Virtualization (oVirt/RHV)
   - openstack: OpenStack cloud platform
-  - ova: OVA files from NFS share
+  - ova: OVA files from an NFS share, HTTP(S) server or S3 bucket
   - openshift: Target OpenShift cluster (usually named 'host')
   - ec2: Amazon EC2 instances
   - hyperv: Microsoft Hyper-V
   - azure: Microsoft Azure VMs
 
-Credentials can be provided directly via flags or through an existing Kubernetes secret.`,
+Credentials can be provided directly via flags or through an existing Kubernetes secret.
+Credential flags (username, password, token, SMB, EC2 target and Azure client flags) can
+also reference an external secret manager, resolved when the command runs:
+  --password vault:secret/vsphere#password   HashiCorp Vault KV (VAULT_ADDR, VAULT_TOKEN)
+  --password aws-sm:vsphere-creds#password   AWS Secrets Manager (AWS credentials and region)
+Prefix a literal value with "plain:" if it starts with one of these schemes.
+
+OVA providers can also be created from a local file with --ova-file. The file is uploaded
+to a PVC served by a small web server in the provider namespace (--ova-upload-to cluster,
+the default) or to an S3 bucket (--ova-upload-to s3://bucket/prefix, using the local AWS
+credentials), and the provider URL is set to the uploaded copy.
+
+For endpoints with self-signed or private CA certificates, use --cacert-file to trust a CA
+bundle, or --fetch-cacert to fetch the certificate the server presents, check its SHA-256
+fingerprint and pin it as the provider CA, instead of --provider-insecure-skip-tls.
+--fetch-cacert asks for confirmation; in scripts pass the expected fingerprint with
+--cacert-fingerprint.
+
+With --from-env, connection settings not given as flags are read from the configuration
+of the provider's client tools, keeping credentials out of the command line and shell history:
+  - vsphere: GOVC_URL, GOVC_USERNAME, GOVC_PASSWORD, GOVC_INSECURE, GOVC_TLS_CA_CERTS (govc)
+  - ovirt: OVIRT_URL, OVIRT_USERNAME, OVIRT_PASSWORD, OVIRT_CAFILE, or ~/.ovirtshellrc
+  - openstack: OS_AUTH_URL, OS_USERNAME, OS_PASSWORD, OS_PROJECT_NAME, OS_USER_DOMAIN_NAME,
+    OS_REGION_NAME, OS_CACERT (openrc), or the cloud selected by OS_CLOUD in clouds.yaml
+
+For a remote OpenShift target, --kubeconfig-context reads the API server URL, bearer
+token and CA certificate of a context in your kubeconfig (e.g. one added by oc login)
+and stores them in the provider secret. The context must authenticate with a token.
+
+Use --wait to block until the provider is Ready and its inventory is loaded. The command
+fails when the provider keeps reporting a critical condition, such as a failed connection
+test, or when --wait-timeout expires.`,
 		Example: `  # Create a vSphere provider
   kubectl-mtv create provider --name vsphere-prod \
     --type vsphere \
@@ -77,6 +117,13 @@ Credentials can be provided directly via flags or through an existing Kubernetes
     --password 'secret' \
     --vddk-init-image quay.io/kubev2v/vddk:latest
 
+  # Create a vSphere provider from the GOVC_* variables used with govc
+  kubectl-mtv create provider --name vsphere-prod --type vsphere --from-env \
+    --vddk-init-image quay.io/kubev2v/vddk:latest
+
+  # Create an OpenStack provider from a cloud in clouds.yaml
+  OS_CLOUD=prod kubectl-mtv create provider --name openstack-prod --type openstack --from-env
+
   # Create an oVirt provider
   kubectl-mtv create provider --name ovirt-prod \
     --type ovirt \
@@ -93,6 +140,16 @@ Credentials can be provided directly via flags or through an existing Kubernetes
     --url https://api.cluster.example.com:6443 \
     --provider-token 'eyJhbGciOiJSUzI1NiIsInR5...'
 
+  # Create a remote OpenShift target provider from a kubeconfig context
+  kubectl-mtv create provider --name remote-cluster --type openshift --kubeconfig-context remote-admin
+
+  # Read the vSphere password from Vault (KV path#field) instead of the command line
+  kubectl-mtv create provider --name vsphere-vault \
+    --type vsphere \
+    --url https://vcenter.example.com/sdk \
+    --username administrator@vsphere.local \
+    --password vault:secret/vsphere#password
+
   # Create an OpenStack provider
   kubectl-mtv create provider --name openstack-prod \
     --type openstack \
@@ -102,6 +159,25 @@ Credentials can be provided directly via flags or through an existing Kubernetes
     --provider-domain-name Default \
     --provider-project-name admin
 
+  # Create an OVA provider from an HTTPS server
+  kubectl-mtv create provider --name web-ova \
+    --type ova \
+    --url https://images.example.com/ova/
+
+  # Upload a local OVA to the cluster and create a provider serving it
+  kubectl-mtv create provider --name appliance \
+    --type ova \
+    --ova-file ./appliance.ova \
+    --ova-storage-class standard
+
+  # Create an EC2 provider
+  kubectl-mtv create provider --name my-ec2 \
+    --type ec2 \
+    --ec2-region us-east-1 \
+    --access-key-id "$AWS_ACCESS_KEY_ID" \
+    --secret-access-key "$AWS_SECRET_ACCESS_KEY" \
+    --auto-target-credentials
+
   # Create a HyperV provider
   kubectl-mtv create provider --name my-hyperv \
     --type hyperv \
@@ -117,40 +193,137 @@ Credentials can be provided directly via flags or through an existing Kubernetes
     --azure-subscription-id "$AZURE_SUBSCRIPTION_ID" \
     --azure-client-id "$AZURE_CLIENT_ID" \
     --azure-client-secret "$AZURE_CLIENT_SECRET" \
-    --azure-resource-group "my-resource-group"`,
+    --azure-resource-group "my-resource-group"
+
+  # Create a vSphere provider with a self-signed certificate, pinned after confirming its fingerprint
+  kubectl-mtv create provider --name vsphere-lab \
+    --type vsphere \
+    --url https://vcenter.lab.example.com/sdk \
+    --username admin@vsphere.local \
+    --password 'secret' \
+    --fetch-cacert
+
+  # Create a provider trusting a CA bundle file
+  kubectl-mtv create provider --name ovirt-prod \
+    --type ovirt \
+    --url https://rhv-manager.example.com/ovirt-engine/api \
+    --username admin@internal \
+    --password 'secret' \
+    --cacert-file ./corp-ca.pem
+
+  # Create a provider and block until its inventory is loaded
+  kubectl-mtv create provider --name vsphere-prod \
+    --type vsphere \
+    --url https://vcenter.example.com/sdk \
+    --username admin@vsphere.local \
+    --password 'secret' \
+    --wait --wait-timeout 15m`,
 		Args:         cobra.MaximumNArgs(1),
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			// Client dry run prints the resources, server dry run sends them with dryRun=All
+			dryRun := dryRunMode.IsClient()
+
 			if err := flags.ResolveNameArg(&name, args); err != nil {
 				return err
 			}
 			if name == "" {
 				return fmt.Errorf("--name is required")
 			}
+			if ovaFile != "" && providerType.GetValue() != "ova" {
+				return fmt.Errorf("--ova-file can only be used with --type ova")
+			}
 
 			// Resolve the appropriate namespace based on context and flags
 			namespace := client.ResolveNamespace(kubeConfigFlags)
 
-			// Check if cacert starts with @ and load from file if so
-			if strings.HasPrefix(cacert, "@") {
-				filePath := cacert[1:]
-				fileContent, err := os.ReadFile(filePath)
+			// Fill in the connection settings not given as flags from the client tool configuration
+			if fromEnv {
+				if secret != "" {
+					return fmt.Errorf("--from-env cannot be used with --secret")
+				}
+				detected, err := providerutil.DetectCredentials(providerType.GetValue(), providerutil.CurrentEnvironment())
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(os.Stderr, "Using connection settings from %s\n", detected.Source)
+				setIfEmpty(&url, detected.URL)
+				setIfEmpty(&username, detected.Username)
+				setIfEmpty(&password, detected.Password)
+				setIfEmpty(&domainName, detected.DomainName)
+				setIfEmpty(&projectName, detected.ProjectName)
+				setIfEmpty(&regionName, detected.RegionName)
+				if !cmd.Flags().Changed("provider-insecure-skip-tls") {
+					insecureSkipTLS = detected.InsecureSkipTLS
+				}
+				if detected.CACertFile != "" && !insecureSkipTLS && cacert == "" && cacertSource.File == "" && !cacertSource.Fetch {
+					cacertSource.File = detected.CACertFile
+				}
+			}
+
+			// Connect a remote OpenShift target with the URL, token and CA of a kubeconfig context
+			if kubeconfigContext != "" {
+				if providerType.GetValue() != "openshift" {
+					return fmt.Errorf("--kubeconfig-context can only be used with --type openshift")
+				}
+				if secret != "" || fromEnv {
+					return fmt.Errorf("--kubeconfig-context cannot be used with --secret or --from-env")
+				}
+				rawConfig, err := kubeConfigFlags.ToRawKubeConfigLoader().RawConfig()
+				if err != nil {
+					return fmt.Errorf("failed to load kubeconfig: %v", err)
+				}
+				detected, err := providerutil.KubeconfigContextCredentials(rawConfig, kubeconfigContext)
 				if err != nil {
 					return err
 				}
-				cacert = string(fileContent)
+				fmt.Fprintf(os.Stderr, "Using API server %s and token of %s\n", detected.URL, detected.Source)
+				setIfEmpty(&url, detected.URL)
+				setIfEmpty(&token, detected.Token)
+				if !cmd.Flags().Changed("provider-insecure-skip-tls") {
+					insecureSkipTLS = detected.InsecureSkipTLS
+				}
+				if !insecureSkipTLS && cacert == "" && cacertSource.File == "" && !cacertSource.Fetch {
+					cacert = detected.CACert
+					cacertSource.File = detected.CACertFile
+				}
+			}
+
+			// Resolve credentials referenced in external secret managers (vault:, aws-sm:)
+			if err := secretref.ResolveAll(cmd.Context(), &username, &password, &token,
+				&ec2TargetAccessKeyID, &ec2TargetSecretKey, &smbUser, &smbPassword,
+				&azureClientID, &azureClientSecret); err != nil {
+				return err
 			}
 
-			if !dryRun && outputFormat != "" {
+			// Load the CA certificate from a file, or fetch and pin the server certificate
+			if insecureSkipTLS && (cacertSource.File != "" || cacertSource.Fetch) {
+				return fmt.Errorf("--cacert-file and --fetch-cacert cannot be used with --provider-insecure-skip-tls")
+			}
+			cacertSource.CACert = cacert
+			var err error
+			cacert, err = providerutil.ResolveCACert(cmd.Context(), cacertSource, url, bugreport.Interactive(), os.Stdin, os.Stderr)
+			if err != nil {
+				return err
+			}
+
+			if !dryRunMode.Enabled() && outputFormat != "" {
 				return fmt.Errorf("--output flag can only be used with --dry-run")
 			}
-			if dryRun && outputFormat != "" && outputFormat != "json" && outputFormat != "yaml" {
+			if dryRunMode.Enabled() && outputFormat != "" && outputFormat != "json" && outputFormat != "yaml" {
 				return fmt.Errorf("invalid output format for dry-run: %s. Valid formats are: json, yaml", outputFormat)
 			}
 			resolvedFormat := outputFormat
 			if dryRun && resolvedFormat == "" {
 				resolvedFormat = "yaml"
 			}
+			wait, err := waitOpts.enabled(cmd, dryRunMode.Enabled())
+			if err != nil {
+				return err
+			}
+			if dryRunMode.IsServer() && ovaFile != "" {
+				return fmt.Errorf("--ova-file cannot be used with --dry-run=server")
+			}
 
 			options := providerutil.ProviderOptions{
 				Name:                       name,
@@ -177,6 +350,11 @@ Credentials can be provided directly via flags or through an existing Kubernetes
 				EC2TargetAccessKeyID:       ec2TargetAccessKeyID,
 				EC2TargetSecretKey:         ec2TargetSecretKey,
 				AutoTargetCredentials:      autoTargetCredentials,
+				OVAFile:                    ovaFile,
+				OVAUploadTo:                ovaUploadTo,
+				OVAStorageClass:            ovaStorageClass,
+				OVAPVCSize:                 ovaPVCSize,
+				OVAServerImage:             ovaServerImage,
 				SMBUrl:                     smbUrl,
 				SMBUser:                    smbUser,
 				SMBPassword:                smbPassword,
@@ -192,7 +370,13 @@ Credentials can be provided directly via flags or through an existing Kubernetes
 				OutputFormat:               resolvedFormat,
 			}
 
-			return provider.Create(kubeConfigFlags, providerType.GetValue(), options)
+			if err := provider.Create(kubeConfigFlags, providerType.GetValue(), options); err != nil {
+				return err
+			}
+			if wait {
+				return provider.Wait(cmd.Context(), kubeConfigFlags, name, namespace, waitOpts.options(cmd))
+			}
+			return nil
 		},
 	}
 
@@ -205,6 +389,9 @@ Credentials can be provided directly via flags or through an existing Kubernetes
 	cmd.Flags().StringVarP(&username, "username", "u", "", "Provider credentials username")
 	cmd.Flags().StringVarP(&password, "password", "p", "", "Provider credentials password")
 	cmd.Flags().StringVar(&cacert, "cacert", "", "Provider CA certificate (use @filename to load from file)")
+	cmd.Flags().StringVar(&cacertSource.File, "cacert-file", "", "File with the provider CA certificate bundle (PEM)")
+	cmd.Flags().BoolVar(&cacertSource.Fetch, "fetch-cacert", false, "Fetch the certificate presented by the provider URL, show its fingerprint and pin it as the provider CA once confirmed")
+	cmd.Flags().StringVar(&cacertSource.Fingerprint, "cacert-fingerprint", "", "Expected SHA-256 fingerprint of the certificate fetched by --fetch-cacert, confirming it without a prompt")
 	cmd.Flags().BoolVar(&insecureSkipTLS, "provider-insecure-skip-tls", false, "Skip TLS verification when connecting to the provider")
 
 	// OpenShift specific flags
@@ -226,12 +413,24 @@ Credentials can be provided directly via flags or through an existing Kubernetes
 
 	// EC2 specific flags
 	cmd.Flags().StringVar(&ec2Region, "ec2-region", "", "AWS region where source EC2 instances are located")
+	cmd.Flags().StringVar(&username, "access-key-id", "", "AWS access key ID (alias for --username)")
+	cmd.Flags().StringVar(&password, "secret-access-key", "", "AWS secret access key (alias for --password)")
+	// The aliases share the variables of --username and --password, so only one of each pair may be set
+	cmd.MarkFlagsMutuallyExclusive("username", "access-key-id")
+	cmd.MarkFlagsMutuallyExclusive("password", "secret-access-key")
 	cmd.Flags().StringVar(&ec2TargetRegion, "target-region", "", "Target region for migrations (defaults to provider region)")
 	cmd.Flags().StringVar(&ec2TargetAZ, "target-az", "", "Target availability zone for migrations (auto-detected from worker nodes if omitted)")
 	cmd.Flags().StringVar(&ec2TargetAccessKeyID, "target-access-key-id", "", "Target AWS account access key ID (for cross-account migrations)")
 	cmd.Flags().StringVar(&ec2TargetSecretKey, "target-secret-access-key", "", "Target AWS account secret access key (for cross-account migrations)")
 	cmd.Flags().BoolVar(&autoTargetCredentials, "auto-target-credentials", false, "Automatically fetch target AWS credentials from cluster and target-az from worker nodes")
 
+	// OVA specific flags
+	cmd.Flags().StringVar(&ovaFile, "ova-file", "", "Local OVA file to upload; the provider URL is set to the uploaded copy")
+	cmd.Flags().StringVar(&ovaUploadTo, "ova-upload-to", ova.UploadToCluster, "Where --ova-file is uploaded: 'cluster' (PVC served over HTTP) or an s3://bucket/prefix URL")
+	cmd.Flags().StringVar(&ovaStorageClass, "ova-storage-class", "", "Storage class of the OVA upload PVC (cluster default when empty)")
+	cmd.Flags().StringVar(&ovaPVCSize, "ova-pvc-size", "", "Size of the OVA upload PVC, e.g. 50Gi (derived from the file size when empty)")
+	cmd.Flags().StringVar(&ovaServerImage, "ova-server-image", ova.DefaultServerImage, "Web server image serving the OVA upload PVC (must accept HTTP PUT)")
+
 	// HyperV specific flags
 	cmd.Flags().StringVar(&smbUrl, "smb-url", "", "SMB share URL for HyperV (e.g., //server/share)")
 	cmd.Flags().StringVar(&smbUser, "smb-user", "", "SMB username (defaults to HyperV username)")
@@ -247,8 +446,11 @@ Credentials can be provided directly via flags or through an existing Kubernetes
 	cmd.Flags().StringVar(&azureSnapshotSku, "azure-snapshot-sku", "", "Snapshot SKU (Standard_LRS, Standard_ZRS, Premium_LRS; default: Standard_ZRS)")
 	cmd.Flags().StringVar(&azureSnapshotResourceGroup, "azure-snapshot-resource-group", "", "Resource group for snapshots (defaults to source resource group)")
 
-	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Output Provider CR(s) to stdout instead of creating them")
+	cmd.Flags().BoolVar(&fromEnv, "from-env", false, "Read connection settings not given as flags from GOVC_* variables (vsphere), OVIRT_* variables or ~/.ovirtshellrc (ovirt), or OS_* variables or clouds.yaml (openstack)")
+	cmd.Flags().StringVar(&kubeconfigContext, "kubeconfig-context", "", "Kubeconfig context of a remote OpenShift cluster; its API server URL, token and CA fill --url, --provider-token and --cacert")
+	flags.AddDryRunFlag(cmd, dryRunMode, "Print Provider CR(s) instead of creating (client), or submit with server-side dry run to validate without persisting (server)")
 	cmd.Flags().StringVarP(&outputFormat, "output", "o", "", "Output format for dry-run (json, yaml). Defaults to yaml when --dry-run is used")
+	waitOpts.add(cmd, "the provider is Ready and its inventory is loaded")
 
 	// Add completion for provider type flag
 	if err := cmd.RegisterFlagCompletionFunc("type", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -257,6 +459,21 @@ Credentials can be provided directly via flags or through an existing Kubernetes
 		panic(err)
 	}
 
+	// Add completion for kubeconfig-context flag
+	if err := cmd.RegisterFlagCompletionFunc("kubeconfig-context", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		rawConfig, err := kubeConfigFlags.ToRawKubeConfigLoader().RawConfig()
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+		contexts := make([]string, 0, len(rawConfig.Contexts))
+		for name := range rawConfig.Contexts {
+			contexts = append(contexts, name)
+		}
+		return contexts, cobra.ShellCompDirectiveNoFileComp
+	}); err != nil {
+		panic(err)
+	}
+
 	// Add completion for sdk-endpoint flag
 	if err := cmd.RegisterFlagCompletionFunc("sdk-endpoint", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		return sdkEndpointType.GetValidValues(), cobra.ShellCompDirectiveNoFileComp
@@ -278,3 +495,10 @@ Credentials can be provided directly via flags or through an existing Kubernetes
 
 	return cmd
 }
+
+// setIfEmpty sets a flag value that was not given on the command line
+func setIfEmpty(value *string, detected string) {
+	if *value == "" {
+		*value = detected
+	}
+}
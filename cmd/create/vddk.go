@@ -2,17 +2,20 @@ package create
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/spf13/cobra"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 
 	"github.com/yaacov/kubectl-mtv/pkg/cmd/create/vddk"
+	"github.com/yaacov/kubectl-mtv/pkg/util/client"
 )
 
 // NewVddkCmd creates the VDDK image creation command
 func NewVddkCmd(globalConfig GlobalConfigGetter, kubeConfigFlags *genericclioptions.ConfigFlags) *cobra.Command {
-	var vddkTarGz, vddkTag, vddkBuildDir, vddkRuntime, vddkPlatform, vddkDockerfile string
+	var vddkTarGz, vddkTag, vddkBuildDir, vddkRuntime, vddkPlatform, vddkDockerfile, pushSecret string
 	var vddkPush, setControllerImage, vddkPushInsecureSkipTLS bool
+	var waitOpts waitFlags
 
 	cmd := &cobra.Command{
 		Use:   "vddk-image",
@@ -23,7 +26,15 @@ VDDK is required for migrating VMs from vSphere. This command builds a container
 image from the VMware VDDK SDK and pushes it to your container registry.
 
 You must download the VDDK SDK from VMware (requires VMware account):
-https://developer.vmware.com/web/sdk/8.0/vddk`,
+https://developer.vmware.com/web/sdk/8.0/vddk
+
+The build and push always complete before the command returns, with the output of
+the container runtime streamed as they run. A pushed image is verified by reading its
+manifest back from the registry. Use --push-secret to push with the credentials of a
+docker-registry secret, such as the pull secret the cluster uses for the image; without
+it the runtime uses its own 'login' credentials. With
+--set-controller-image, use --wait to also block until the ForkliftController
+operator applied the new image, so providers created next use it.`,
 		Example: `  # Build VDDK image using podman
   kubectl-mtv create vddk-image \
     --tar VMware-vix-disklib-8.0.1-21562716.x86_64.tar.gz \
@@ -42,12 +53,27 @@ https://developer.vmware.com/web/sdk/8.0/vddk`,
     --push \
     --set-controller-image
 
+  # Build, push, configure, and block until the operator applied the image
+  kubectl-mtv create vddk-image \
+    --tar VMware-vix-disklib-8.0.1-21562716.x86_64.tar.gz \
+    --tag quay.io/myorg/vddk:8.0.1 \
+    --push \
+    --set-controller-image \
+    --wait
+
   # Use specific container runtime
   kubectl-mtv create vddk-image \
     --tar VMware-vix-disklib-8.0.1-21562716.x86_64.tar.gz \
     --tag quay.io/myorg/vddk:8.0.1 \
     --runtime docker
 
+  # Push with the credentials of a docker-registry secret
+  kubectl-mtv create vddk-image \
+    --tar VMware-vix-disklib-8.0.1-21562716.x86_64.tar.gz \
+    --tag registry.example.com/mtv/vddk:8.0.1 \
+    --push \
+    --push-secret registry-creds
+
   # Push to insecure registry (self-signed certificate)
   kubectl-mtv create vddk-image \
     --tar VMware-vix-disklib-8.0.1-21562716.x86_64.tar.gz \
@@ -59,12 +85,30 @@ https://developer.vmware.com/web/sdk/8.0/vddk`,
 			if setControllerImage && !vddkPush {
 				return fmt.Errorf("--set-controller-image requires --push to be set")
 			}
+			if pushSecret != "" && !vddkPush {
+				return fmt.Errorf("--push-secret requires --push to be set")
+			}
+			wait, err := waitOpts.enabled(cmd, false)
+			if err != nil {
+				return err
+			}
+			if wait && !setControllerImage {
+				return fmt.Errorf("--wait requires --set-controller-image, the image build itself always completes before the command returns")
+			}
 
 			verbosity := 0
 			if globalConfig != nil {
 				verbosity = globalConfig.GetVerbosity()
 			}
-			err := vddk.BuildImage(vddkTarGz, vddkTag, vddkBuildDir, vddkRuntime, vddkPlatform, vddkDockerfile, verbosity, vddkPush, vddkPushInsecureSkipTLS)
+			var pushAuth *vddk.RegistryAuth
+			if pushSecret != "" {
+				namespace := client.ResolveNamespace(kubeConfigFlags)
+				if pushAuth, err = vddk.LoadPushSecret(cmd.Context(), kubeConfigFlags, namespace, pushSecret); err != nil {
+					return err
+				}
+			}
+
+			err = vddk.BuildImage(vddkTarGz, vddkTag, vddkBuildDir, vddkRuntime, vddkPlatform, vddkDockerfile, verbosity, vddkPush, vddkPushInsecureSkipTLS, pushAuth)
 			if err != nil {
 				fmt.Printf("Error building VDDK image: %v\n", err)
 				fmt.Printf("You can use the '--help' flag for more information on usage.\n")
@@ -73,10 +117,14 @@ https://developer.vmware.com/web/sdk/8.0/vddk`,
 
 			// Configure ForkliftController if requested
 			if setControllerImage {
+				since := time.Now()
 				if err := vddk.SetControllerVddkImage(kubeConfigFlags, vddkTag, verbosity); err != nil {
 					fmt.Printf("Error configuring ForkliftController: %v\n", err)
 					return nil
 				}
+				if wait {
+					return vddk.WaitControllerReconciled(cmd.Context(), kubeConfigFlags, since, waitOpts.options(cmd))
+				}
 			}
 
 			return nil
@@ -91,7 +139,9 @@ https://developer.vmware.com/web/sdk/8.0/vddk`,
 	cmd.Flags().StringVar(&vddkDockerfile, "dockerfile", "", "Path to custom Dockerfile (optional, uses default if not set)")
 	cmd.Flags().BoolVar(&vddkPush, "push", false, "Push image after build (optional)")
 	cmd.Flags().BoolVar(&vddkPushInsecureSkipTLS, "push-insecure-skip-tls", false, "Skip TLS verification when pushing to the registry (podman only, docker requires daemon config)")
+	cmd.Flags().StringVar(&pushSecret, "push-secret", "", "Secret of type kubernetes.io/dockerconfigjson with the registry credentials to push with (requires --push)")
 	cmd.Flags().BoolVar(&setControllerImage, "set-controller-image", false, "Configure the pushed image as global vddk_image in ForkliftController (requires --push)")
+	waitOpts.add(cmd, "the ForkliftController applied the image set by --set-controller-image")
 
 	// Add autocomplete for runtime flag
 	if err := cmd.RegisterFlagCompletionFunc("runtime", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
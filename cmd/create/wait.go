@@ -0,0 +1,45 @@
+package create
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/help"
+	"github.com/yaacov/kubectl-mtv/pkg/util/wait"
+)
+
+// waitFlags holds the --wait and --wait-timeout flags of a create command
+type waitFlags struct {
+	wait    bool
+	timeout time.Duration
+}
+
+// add registers the flags, until describes what --wait blocks for
+func (w *waitFlags) add(cmd *cobra.Command, until string) {
+	cmd.Flags().BoolVar(&w.wait, "wait", false, "Block until "+until)
+	cmd.Flags().DurationVar(&w.timeout, "wait-timeout", wait.DefaultTimeout, "Maximum time to wait with --wait, e.g. 30m (0 waits without limit; implies --wait)")
+
+	help.MarkMCPHidden(cmd, "wait", "wait-timeout")
+}
+
+// enabled validates the flags and reports whether the command should wait. Setting
+// --wait-timeout implies --wait.
+func (w *waitFlags) enabled(cmd *cobra.Command, dryRun bool) (bool, error) {
+	if cmd.Flags().Changed("wait-timeout") {
+		if w.timeout < 0 {
+			return false, fmt.Errorf("--wait-timeout must not be negative, got %s", w.timeout)
+		}
+		w.wait = true
+	}
+	if w.wait && dryRun {
+		return false, fmt.Errorf("--wait cannot be used with --dry-run")
+	}
+	return w.wait, nil
+}
+
+// options returns the wait options, progress is printed to stderr
+func (w *waitFlags) options(cmd *cobra.Command) wait.Options {
+	return wait.Options{Timeout: w.timeout, Settle: wait.DefaultSettle, Out: cmd.ErrOrStderr()}
+}
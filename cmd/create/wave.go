@@ -0,0 +1,129 @@
+package create
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/create/wave"
+	"github.com/yaacov/kubectl-mtv/pkg/util/client"
+	"github.com/yaacov/kubectl-mtv/pkg/util/completion"
+	"github.com/yaacov/kubectl-mtv/pkg/util/flags"
+	wavepkg "github.com/yaacov/kubectl-mtv/pkg/util/wave"
+)
+
+// NewWaveCmd creates the wave creation command
+func NewWaveCmd(kubeConfigFlags *genericclioptions.ConfigFlags) *cobra.Command {
+	var name string
+	var plans []string
+	var mode string
+	var concurrency int
+	var startAtStr string
+	var description string
+	dryRunMode := flags.NewDryRunFlag()
+	var outputFormat string
+
+	cmd := &cobra.Command{
+		Use:   "wave",
+		Short: "Create a migration wave",
+		Long: `Create a migration wave that groups migration plans so they can be started together.
+
+Waves are stored as ConfigMaps labeled kubectl-mtv/wave=true in the plans' namespace.
+Plans in a sequential wave are started one after another, in the order given.
+Plans in a parallel wave are started together, limited by --concurrency.
+
+A wave can have a scheduled start time; 'start wave' waits until that time
+before starting the first plan.`,
+		Example: `  # Create a wave that migrates three plans one after another
+  kubectl-mtv create wave --name wave-1 --plans db-plan,app-plan,web-plan
+
+  # Create a parallel wave running at most two plans at a time
+  kubectl-mtv create wave --name wave-2 --plans plan1,plan2,plan3,plan4 --mode parallel --concurrency 2
+
+  # Create a wave scheduled for a maintenance window
+  kubectl-mtv create wave --name weekend --plans plan1,plan2 --start-at 2026-12-31T22:00:00Z
+
+  # Output the wave ConfigMap without creating it
+  kubectl-mtv create wave --name wave-1 --plans plan1,plan2 --dry-run`,
+		Args:         cobra.MaximumNArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Client dry run prints the resources, server dry run sends them with dryRun=All
+			dryRun := dryRunMode.IsClient()
+
+			if err := flags.ResolveNameArg(&name, args); err != nil {
+				return err
+			}
+			if name == "" {
+				return fmt.Errorf("--name is required")
+			}
+
+			if mode != wavepkg.ModeSequential && mode != wavepkg.ModeParallel {
+				return fmt.Errorf("invalid --mode '%s': must be %s or %s", mode, wavepkg.ModeSequential, wavepkg.ModeParallel)
+			}
+			if cmd.Flag("concurrency").Changed && mode != wavepkg.ModeParallel {
+				return fmt.Errorf("--concurrency can only be used with --mode %s", wavepkg.ModeParallel)
+			}
+
+			var startAt *time.Time
+			if startAtStr != "" {
+				t, err := time.Parse(time.RFC3339, startAtStr)
+				if err != nil {
+					return fmt.Errorf("failed to parse start time: %v", err)
+				}
+				startAt = &t
+			}
+
+			if !dryRunMode.Enabled() && outputFormat != "" {
+				return fmt.Errorf("--output flag can only be used with --dry-run")
+			}
+			if dryRunMode.Enabled() && outputFormat != "" && outputFormat != "json" && outputFormat != "yaml" {
+				return fmt.Errorf("invalid output format for dry-run: %s. Valid formats are: json, yaml", outputFormat)
+			}
+			if dryRun && outputFormat == "" {
+				outputFormat = "yaml"
+			}
+
+			opts := wave.CreateWaveOptions{
+				Wave: wavepkg.Wave{
+					Name:        name,
+					Namespace:   client.ResolveNamespace(kubeConfigFlags),
+					Plans:       plans,
+					Mode:        mode,
+					Concurrency: concurrency,
+					StartAt:     startAt,
+					Description: description,
+				},
+				ConfigFlags:  kubeConfigFlags,
+				DryRun:       dryRun,
+				OutputFormat: outputFormat,
+			}
+
+			return wave.Create(cmd.Context(), opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&name, "name", "M", "", "Wave name")
+	cmd.Flags().StringSliceVar(&plans, "plans", nil, "Plan names in start order (comma-separated, e.g. \"plan1,plan2\")")
+	cmd.Flags().StringVar(&mode, "mode", wavepkg.ModeSequential, "Execution mode: sequential or parallel")
+	cmd.Flags().IntVar(&concurrency, "concurrency", 0, "Maximum number of plans running at the same time in parallel mode (default: all)")
+	cmd.Flags().StringVar(&startAtStr, "start-at", "", "Scheduled start time in ISO8601 format (e.g., 2026-12-31T22:00:00Z)")
+	cmd.Flags().StringVar(&description, "description", "", "Wave description")
+	flags.AddDryRunFlag(cmd, dryRunMode, "Print the wave ConfigMap instead of creating (client), or submit with server-side dry run to validate without persisting (server)")
+	cmd.Flags().StringVarP(&outputFormat, "output", "o", "", "Output format for dry-run (json, yaml). Defaults to yaml when --dry-run is used")
+
+	if err := cmd.MarkFlagRequired("plans"); err != nil {
+		panic(err)
+	}
+
+	flags.MarkRequiredForMCP(cmd, "name")
+
+	_ = cmd.RegisterFlagCompletionFunc("plans", completion.PlanNameCompletion(kubeConfigFlags))
+	_ = cmd.RegisterFlagCompletionFunc("mode", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{wavepkg.ModeSequential, wavepkg.ModeParallel}, cobra.ShellCompDirectiveNoFileComp
+	})
+
+	return cmd
+}
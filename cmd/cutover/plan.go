@@ -3,6 +3,7 @@ package cutover
 import (
 	"errors"
 	"fmt"
+	"os"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -17,8 +18,11 @@ import (
 // NewPlanCmd creates the plan cutover command
 func NewPlanCmd(kubeConfigFlags *genericclioptions.ConfigFlags) *cobra.Command {
 	var cutoverTimeStr string
+	var inStr, atStr, timezone string
 	var all bool
 	var planNames []string
+	var trigger plan.PrecopyTrigger
+	var waitTimeout time.Duration
 
 	cmd := &cobra.Command{
 		Use:   "plan",
@@ -27,13 +31,51 @@ func NewPlanCmd(kubeConfigFlags *genericclioptions.ConfigFlags) *cobra.Command {
 
 Cutover stops the source VMs and performs the final sync to complete the migration.
 Use this to manually trigger cutover for warm migrations, or to reschedule
-a cutover time. If no cutover time is specified, it defaults to immediately.`,
+a cutover time. If no cutover time is specified, it defaults to immediately.
+
+The cutover time can be given relative to now with --in (e.g. "2h", "1h30m", "1d"),
+or as a local time with --at (e.g. "22:30", "tomorrow 02:00", "2026-12-31 23:00").
+A time of day that has already passed today means tomorrow.
+
+Local times use the local timezone, the --timezone flag, or a timezone written
+after the time (e.g. "2026-12-31 23:00 Europe/Berlin"). The resolved time is
+echoed back in that timezone and in UTC. A warning is printed when the time is
+in the past (cutover starts immediately), or falls in a daylight saving time
+change: a skipped time is moved forward, and a repeated time uses the earlier
+occurrence.
+
+Instead of guessing a time, --when-precopy-under and --after-precopies watch the
+running migration and set the cutover to now once every VM still being migrated
+meets the condition: its last precopy took less than the given duration, or it
+finished the given number of precopies (either one when both are set). The
+Migration status does not record the size of the precopy deltas; the duration
+of a precopy grows with the data changed since the previous one and approximates
+how long the final copy at cutover will take. The command blocks until then, or
+until --wait-timeout expires.`,
 		Example: `  # Trigger immediate cutover
   kubectl-mtv cutover plan --name my-warm-migration
 
   # Schedule cutover for a specific time
   kubectl-mtv cutover plan --name my-warm-migration --cutover 2026-12-31T23:00:00Z
 
+  # Cutover in two hours
+  kubectl-mtv cutover plan --name my-warm-migration --in 2h
+
+  # Cutover at 22:30 local time (tomorrow if 22:30 has passed)
+  kubectl-mtv cutover plan --name my-warm-migration --at 22:30
+
+  # Cutover tomorrow at 02:00 local time
+  kubectl-mtv cutover plan --name my-warm-migration --at "tomorrow 02:00"
+
+  # Cutover at 22:30 in the timezone of the source datacenter
+  kubectl-mtv cutover plan --name my-warm-migration --at 22:30 --timezone America/New_York
+
+  # Cutover once the last precopy of every VM took less than 5 minutes
+  kubectl-mtv cutover plan --name my-warm-migration --when-precopy-under 5m
+
+  # Cutover after a quick precopy, or after 6 precopies at the latest
+  kubectl-mtv cutover plan --name my-warm-migration --when-precopy-under 5m --after-precopies 6
+
   # Cutover all warm migration plans
   kubectl-mtv cutover plans --all
 
@@ -54,22 +96,43 @@ a cutover time. If no cutover time is specified, it defaults to immediately.`,
 				return errors.New("must specify --name or --all")
 			}
 
+			conditional := trigger.Under > 0 || trigger.After > 0
+			if trigger.Under < 0 || trigger.After < 0 {
+				return errors.New("--when-precopy-under and --after-precopies must be positive")
+			}
+			if conditional && (all || len(planNames) > 1) {
+				return errors.New("--when-precopy-under and --after-precopies work on a single plan")
+			}
+			if conditional && (cutoverTimeStr != "" || inStr != "" || atStr != "") {
+				return errors.New("--when-precopy-under and --after-precopies cannot be used with --cutover, --in or --at")
+			}
+			if waitTimeout != 0 && !conditional {
+				return errors.New("--wait-timeout needs --when-precopy-under or --after-precopies")
+			}
+
 			// Resolve the appropriate namespace based on context and flags
 			namespace := client.ResolveNamespace(kubeConfigFlags)
 
-			var cutoverTime *time.Time
-			if cutoverTimeStr != "" {
-				// Parse the provided cutover time
-				t, err := time.Parse(time.RFC3339, cutoverTimeStr)
+			if conditional {
+				return plan.WaitForPrecopy(cmd.Context(), kubeConfigFlags, planNames[0], namespace, trigger, waitTimeout, cmd.OutOrStdout())
+			}
+
+			now := time.Now()
+			if timezone != "" {
+				loc, err := flags.LoadTimezone(timezone)
 				if err != nil {
-					return fmt.Errorf("failed to parse cutover time: %v", err)
+					return err
 				}
-				cutoverTime = &t
+				now = now.In(loc)
+			}
+
+			cutoverTime, err := resolveCutoverTime(cutoverTimeStr, inStr, atStr, now)
+			if err != nil {
+				return err
 			}
 
 			if all {
 				// Get all plan names from the namespace
-				var err error
 				planNames, err = client.GetAllPlanNames(cmd.Context(), kubeConfigFlags, namespace)
 				if err != nil {
 					return fmt.Errorf("failed to get all plan names: %v", err)
@@ -94,10 +157,64 @@ a cutover time. If no cutover time is specified, it defaults to immediately.`,
 	cmd.Flags().StringSliceVarP(&planNames, "name", "M", nil, "Plan name(s) to cutover (comma-separated, e.g. \"plan1,plan2\")")
 	cmd.Flags().StringSliceVar(&planNames, "names", nil, "Alias for --name")
 	_ = cmd.Flags().MarkHidden("names")
-	cmd.Flags().StringVarP(&cutoverTimeStr, "cutover", "c", "", "Cutover time in ISO8601 format (e.g., 2023-12-31T15:30:00Z, '$(date --iso-8601=sec)'). If not specified, defaults to current time.")
+	cmd.Flags().StringVarP(&cutoverTimeStr, "cutover", "c", "", "Cutover time in ISO8601 format (e.g., 2023-12-31T15:30:00Z, '$(date --iso-8601=sec)'), also accepts the --in and --at forms. If not specified, defaults to current time.")
+	cmd.Flags().StringVar(&inStr, "in", "", "Cutover after a duration from now (e.g., 2h, 90m, 1h30m, 1d)")
+	cmd.Flags().StringVar(&atStr, "at", "", "Cutover at a local time (e.g., 22:30, \"tomorrow 02:00\", \"2026-12-31 23:00\")")
+	cmd.Flags().StringVar(&timezone, "timezone", "", "Timezone of local --at and --cutover times, an IANA name (e.g., America/New_York, Europe/Berlin) or UTC (default: local timezone)")
 	cmd.Flags().BoolVar(&all, "all", false, "Set cutover time for all migration plans in the namespace")
+	cmd.Flags().DurationVar(&trigger.Under, "when-precopy-under", 0, "Wait until the last precopy of every VM took less than this duration (e.g., 5m), then cutover")
+	cmd.Flags().IntVar(&trigger.After, "after-precopies", 0, "Wait until every VM finished this many precopies, then cutover")
+	cmd.Flags().DurationVar(&waitTimeout, "wait-timeout", 0, "Maximum time to wait with --when-precopy-under or --after-precopies, e.g. 12h (default no limit)")
+	cmd.MarkFlagsMutuallyExclusive("cutover", "in", "at")
 
 	_ = cmd.RegisterFlagCompletionFunc("name", completion.PlanNameCompletion(kubeConfigFlags))
 
 	return cmd
 }
+
+// resolveCutoverTime returns the cutover time from --cutover, --in or --at, or nil when none
+// is set. The resolved time is echoed in the timezone of now and in UTC, with warnings for
+// times in the past and local times in a daylight saving time change.
+func resolveCutoverTime(cutoverStr, inStr, atStr string, now time.Time) (*time.Time, error) {
+	var t time.Time
+	var warning string
+	switch {
+	case inStr != "":
+		d, err := flags.ParseRelativeDuration(inStr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse --in: %v", err)
+		}
+		t = now.Add(d)
+	case atStr != "":
+		var err error
+		if t, warning, err = flags.ParseTimeValueWithWarning(atStr, now); err != nil {
+			return nil, fmt.Errorf("failed to parse --at: %v", err)
+		}
+	case cutoverStr != "":
+		var err error
+		if t, warning, err = flags.ParseTimeValueWithWarning(cutoverStr, now); err != nil {
+			return nil, fmt.Errorf("failed to parse cutover time: %v", err)
+		}
+	default:
+		return nil, nil
+	}
+
+	// RFC3339 times carry only an offset, show them in the timezone of now
+	if zone := t.Location().String(); zone == "" || zone == "UTC" {
+		t = t.In(now.Location())
+	}
+
+	if warning != "" {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+	}
+	delay := t.Sub(now).Round(time.Second)
+	if delay < 0 {
+		fmt.Fprintf(os.Stderr, "Warning: cutover time is %s in the past, cutover starts immediately\n", -delay)
+	}
+	fmt.Printf("Cutover time resolved to %s (%s)", t.Format(flags.TimeWithZoneLayout), t.UTC().Format(time.RFC3339))
+	if delay >= 0 {
+		fmt.Printf(", in %s", delay)
+	}
+	fmt.Println()
+	return &t, nil
+}
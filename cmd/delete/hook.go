@@ -3,19 +3,24 @@ package delete
 import (
 	"errors"
 	"fmt"
+	"os"
 
 	"github.com/spf13/cobra"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/delete/bulk"
 	"github.com/yaacov/kubectl-mtv/pkg/cmd/delete/hook"
+	"github.com/yaacov/kubectl-mtv/pkg/util/bugreport"
 	"github.com/yaacov/kubectl-mtv/pkg/util/client"
 	"github.com/yaacov/kubectl-mtv/pkg/util/completion"
 	"github.com/yaacov/kubectl-mtv/pkg/util/flags"
+	"github.com/yaacov/kubectl-mtv/pkg/util/progress"
 )
 
 // NewHookCmd creates the delete hook command
 func NewHookCmd(kubeConfigFlags *genericclioptions.ConfigFlags) *cobra.Command {
 	var all bool
+	var yes bool
 	var hookNames []string
 
 	cmd := &cobra.Command{
@@ -52,13 +57,33 @@ func NewHookCmd(kubeConfigFlags *genericclioptions.ConfigFlags) *cobra.Command {
 				}
 			}
 
+			// Show the hooks and ask before deleting anything; a dry run deletes nothing
+			opts := bulk.Options{
+				Kind:        "migration hook",
+				Kinds:       "migration hooks",
+				Namespace:   namespace,
+				Columns:     bulk.NameColumns,
+				Yes:         yes || client.DryRunEnabled(),
+				Interactive: bugreport.Interactive(),
+				In:          os.Stdin,
+				Out:         os.Stdout,
+			}
+			if err := bulk.Confirm(bulk.NameItems(hookNames), opts); err != nil {
+				return err
+			}
+
 			// Loop over each hook name and delete it
+			tracker := progress.Start("delete hook", "")
+			tracker.Stage("delete", "deleting migration hooks", len(hookNames))
 			for _, name := range hookNames {
 				err := hook.Delete(kubeConfigFlags, name, namespace)
 				if err != nil {
+					tracker.Done(err)
 					return err
 				}
+				tracker.Step(name)
 			}
+			tracker.Done(nil)
 			return nil
 		},
 	}
@@ -67,8 +92,11 @@ func NewHookCmd(kubeConfigFlags *genericclioptions.ConfigFlags) *cobra.Command {
 	cmd.Flags().StringSliceVarP(&hookNames, "name", "M", nil, "Hook name(s) to delete (comma-separated, e.g. \"hook1,hook2\")")
 	cmd.Flags().StringSliceVar(&hookNames, "names", nil, "Alias for --name")
 	_ = cmd.Flags().MarkHidden("names")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Delete the hooks without asking for confirmation")
 
 	_ = cmd.RegisterFlagCompletionFunc("name", completion.HookResourceNameCompletion(kubeConfigFlags))
 
+	flags.AddDryRunFlag(cmd, flags.NewDryRunFlag(), "Show what would be deleted without deleting it (client), or submit the delete with server-side dry run to validate it without persisting (server)")
+
 	return cmd
 }
@@ -3,19 +3,24 @@ package delete
 import (
 	"errors"
 	"fmt"
+	"os"
 
 	"github.com/spf13/cobra"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/delete/bulk"
 	"github.com/yaacov/kubectl-mtv/pkg/cmd/delete/host"
+	"github.com/yaacov/kubectl-mtv/pkg/util/bugreport"
 	"github.com/yaacov/kubectl-mtv/pkg/util/client"
 	"github.com/yaacov/kubectl-mtv/pkg/util/completion"
 	"github.com/yaacov/kubectl-mtv/pkg/util/flags"
+	"github.com/yaacov/kubectl-mtv/pkg/util/progress"
 )
 
 // NewHostCmd creates the delete host command
 func NewHostCmd(kubeConfigFlags *genericclioptions.ConfigFlags) *cobra.Command {
 	var all bool
+	var yes bool
 	var hostNames []string
 
 	cmd := &cobra.Command{
@@ -52,13 +57,33 @@ func NewHostCmd(kubeConfigFlags *genericclioptions.ConfigFlags) *cobra.Command {
 				}
 			}
 
+			// Show the hosts and ask before deleting anything; a dry run deletes nothing
+			opts := bulk.Options{
+				Kind:        "migration host",
+				Kinds:       "migration hosts",
+				Namespace:   namespace,
+				Columns:     bulk.NameColumns,
+				Yes:         yes || client.DryRunEnabled(),
+				Interactive: bugreport.Interactive(),
+				In:          os.Stdin,
+				Out:         os.Stdout,
+			}
+			if err := bulk.Confirm(bulk.NameItems(hostNames), opts); err != nil {
+				return err
+			}
+
 			// Loop over each host name and delete it
+			tracker := progress.Start("delete host", "")
+			tracker.Stage("delete", "deleting migration hosts", len(hostNames))
 			for _, name := range hostNames {
 				err := host.Delete(kubeConfigFlags, name, namespace)
 				if err != nil {
+					tracker.Done(err)
 					return err
 				}
+				tracker.Step(name)
 			}
+			tracker.Done(nil)
 			return nil
 		},
 	}
@@ -67,8 +92,11 @@ func NewHostCmd(kubeConfigFlags *genericclioptions.ConfigFlags) *cobra.Command {
 	cmd.Flags().StringSliceVarP(&hostNames, "name", "M", nil, "Host name(s) to delete (comma-separated, e.g. \"host1,host2\")")
 	cmd.Flags().StringSliceVar(&hostNames, "names", nil, "Alias for --name")
 	_ = cmd.Flags().MarkHidden("names")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Delete the hosts without asking for confirmation")
 
 	_ = cmd.RegisterFlagCompletionFunc("name", completion.HostResourceNameCompletion(kubeConfigFlags))
 
+	flags.AddDryRunFlag(cmd, flags.NewDryRunFlag(), "Show what would be deleted without deleting it (client), or submit the delete with server-side dry run to validate it without persisting (server)")
+
 	return cmd
 }
@@ -1,13 +1,17 @@
 package delete
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"os"
 
 	"github.com/spf13/cobra"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/delete/bulk"
 	"github.com/yaacov/kubectl-mtv/pkg/cmd/delete/mapping"
+	"github.com/yaacov/kubectl-mtv/pkg/util/bugreport"
 	"github.com/yaacov/kubectl-mtv/pkg/util/client"
 	"github.com/yaacov/kubectl-mtv/pkg/util/completion"
 	"github.com/yaacov/kubectl-mtv/pkg/util/flags"
@@ -39,6 +43,8 @@ Mappings define how source resources translate to target resources. Use
 // newDeleteNetworkMappingCmd creates the delete network mapping subcommand
 func newDeleteNetworkMappingCmd(kubeConfigFlags *genericclioptions.ConfigFlags) *cobra.Command {
 	var all bool
+	var yes bool
+	var concurrency int
 	var mappingNames []string
 
 	cmd := &cobra.Command{
@@ -46,16 +52,24 @@ func newDeleteNetworkMappingCmd(kubeConfigFlags *genericclioptions.ConfigFlags)
 		Short: "Delete one or more network mappings",
 		Long: `Delete one or more network mappings.
 
-Ensure no migration plans reference the mapping before deletion.`,
+Ensure no migration plans reference the mapping before deletion.
+
+The mappings are listed first with the plans that still use them, and deleted
+only after confirmation (or with --yes, or with KUBECTL_MTV_ASSUME_YES=true).
+They are then deleted concurrently; the result of each mapping is shown and the
+command fails if any mapping could not be deleted.`,
 		Example: `  # Delete a network mapping
   kubectl-mtv delete mapping network --name my-net-map
 
   # Delete multiple network mappings
-  kubectl-mtv delete mappings network --name map1,map2,map3
+  kubectl-mtv delete mappings network map1 map2 map3
+
+  # Delete all network mappings without asking
+  kubectl-mtv delete mappings network --all --yes
 
   # Delete all network mappings
   kubectl-mtv delete mappings network --all`,
-		Args:         cobra.MaximumNArgs(1),
+		Args:         cobra.ArbitraryArgs,
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if err := flags.ResolveNamesArg(&mappingNames, args); err != nil {
@@ -86,32 +100,32 @@ Ensure no migration plans reference the mapping before deletion.`,
 				}
 			}
 
-			// Loop over each mapping name and delete it
-			for _, name := range mappingNames {
-				err := mapping.Delete(kubeConfigFlags, name, namespace, "network")
-				if err != nil {
-					return err
-				}
-			}
-			return nil
+			return deleteMappings(cmd.Context(), kubeConfigFlags, "network", mappingNames, namespace, yes, concurrency)
 		},
 	}
 
 	cmd.Flags().BoolVar(&all, "all", false, "Delete all network mappings in the namespace")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Delete the network mappings without asking for confirmation")
+	cmd.Flags().IntVar(&concurrency, "concurrency", bulk.DefaultConcurrency, "Number of network mappings deleted at the same time")
 	cmd.Flags().StringSliceVarP(&mappingNames, "name", "M", nil, "Network mapping name(s) to delete (comma-separated, e.g. \"map1,map2\")")
 	cmd.Flags().StringSliceVar(&mappingNames, "names", nil, "Alias for --name")
 	_ = cmd.Flags().MarkHidden("names")
 
+	cmd.ValidArgsFunction = completion.NameArgCompletion(completion.MappingNameCompletion(kubeConfigFlags, "network"))
 	_ = cmd.RegisterFlagCompletionFunc("name", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		return completion.MappingNameCompletion(kubeConfigFlags, "network")(cmd, args, toComplete)
 	})
 
+	flags.AddDryRunFlag(cmd, flags.NewDryRunFlag(), "Show what would be deleted without deleting it (client), or submit the delete with server-side dry run to validate it without persisting (server)")
+
 	return cmd
 }
 
 // newDeleteStorageMappingCmd creates the delete storage mapping subcommand
 func newDeleteStorageMappingCmd(kubeConfigFlags *genericclioptions.ConfigFlags) *cobra.Command {
 	var all bool
+	var yes bool
+	var concurrency int
 	var mappingNames []string
 
 	cmd := &cobra.Command{
@@ -119,16 +133,24 @@ func newDeleteStorageMappingCmd(kubeConfigFlags *genericclioptions.ConfigFlags)
 		Short: "Delete one or more storage mappings",
 		Long: `Delete one or more storage mappings.
 
-Ensure no migration plans reference the mapping before deletion.`,
+Ensure no migration plans reference the mapping before deletion.
+
+The mappings are listed first with the plans that still use them, and deleted
+only after confirmation (or with --yes, or with KUBECTL_MTV_ASSUME_YES=true).
+They are then deleted concurrently; the result of each mapping is shown and the
+command fails if any mapping could not be deleted.`,
 		Example: `  # Delete a storage mapping
   kubectl-mtv delete mapping storage --name my-storage-map
 
   # Delete multiple storage mappings
-  kubectl-mtv delete mappings storage --name map1,map2,map3
+  kubectl-mtv delete mappings storage map1 map2 map3
+
+  # Delete all storage mappings without asking
+  kubectl-mtv delete mappings storage --all --yes
 
   # Delete all storage mappings
   kubectl-mtv delete mappings storage --all`,
-		Args:         cobra.MaximumNArgs(1),
+		Args:         cobra.ArbitraryArgs,
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if err := flags.ResolveNamesArg(&mappingNames, args); err != nil {
@@ -159,25 +181,59 @@ Ensure no migration plans reference the mapping before deletion.`,
 				}
 			}
 
-			// Loop over each mapping name and delete it
-			for _, name := range mappingNames {
-				err := mapping.Delete(kubeConfigFlags, name, namespace, "storage")
-				if err != nil {
-					return err
-				}
-			}
-			return nil
+			return deleteMappings(cmd.Context(), kubeConfigFlags, "storage", mappingNames, namespace, yes, concurrency)
 		},
 	}
 
 	cmd.Flags().BoolVar(&all, "all", false, "Delete all storage mappings in the namespace")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Delete the storage mappings without asking for confirmation")
+	cmd.Flags().IntVar(&concurrency, "concurrency", bulk.DefaultConcurrency, "Number of storage mappings deleted at the same time")
 	cmd.Flags().StringSliceVarP(&mappingNames, "name", "M", nil, "Storage mapping name(s) to delete (comma-separated, e.g. \"map1,map2\")")
 	cmd.Flags().StringSliceVar(&mappingNames, "names", nil, "Alias for --name")
 	_ = cmd.Flags().MarkHidden("names")
 
+	cmd.ValidArgsFunction = completion.NameArgCompletion(completion.MappingNameCompletion(kubeConfigFlags, "storage"))
 	_ = cmd.RegisterFlagCompletionFunc("name", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		return completion.MappingNameCompletion(kubeConfigFlags, "storage")(cmd, args, toComplete)
 	})
 
+	flags.AddDryRunFlag(cmd, flags.NewDryRunFlag(), "Show what would be deleted without deleting it (client), or submit the delete with server-side dry run to validate it without persisting (server)")
+
 	return cmd
 }
+
+// deleteMappings deletes network or storage mappings; the mappings are summarized and
+// confirmed first, then deleted concurrently
+func deleteMappings(ctx context.Context, kubeConfigFlags *genericclioptions.ConfigFlags, mappingType string, names []string, namespace string, yes bool, concurrency int) error {
+	opts := bulk.Options{
+		Kind:        mappingType + " mapping",
+		Kinds:       mappingType + " mappings",
+		Namespace:   namespace,
+		Columns:     mapping.SummaryColumns,
+		Yes:         yes,
+		Interactive: bugreport.Interactive(),
+		In:          os.Stdin,
+		Out:         os.Stdout,
+		Concurrency: concurrency,
+	}
+	// A dry run deletes nothing, so there is nothing to confirm
+	if client.DryRunEnabled() {
+		opts.Yes = true
+	}
+
+	c, err := client.GetDynamicClient(kubeConfigFlags)
+	if err != nil {
+		return fmt.Errorf("failed to get client: %v", err)
+	}
+	summary, err := mapping.Summarize(ctx, c, namespace, mappingType, names)
+	if err != nil {
+		return err
+	}
+	if err := bulk.Confirm(summary, opts); err != nil {
+		return err
+	}
+
+	return bulk.Run(ctx, names, func(ctx context.Context, name string) error {
+		return mapping.Delete(kubeConfigFlags, name, namespace, mappingType)
+	}, opts)
+}
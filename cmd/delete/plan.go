@@ -1,13 +1,17 @@
 package delete
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"os"
 
 	"github.com/spf13/cobra"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/delete/bulk"
 	"github.com/yaacov/kubectl-mtv/pkg/cmd/delete/plan"
+	"github.com/yaacov/kubectl-mtv/pkg/util/bugreport"
 	"github.com/yaacov/kubectl-mtv/pkg/util/client"
 	"github.com/yaacov/kubectl-mtv/pkg/util/completion"
 	"github.com/yaacov/kubectl-mtv/pkg/util/flags"
@@ -18,6 +22,8 @@ func NewPlanCmd(kubeConfigFlags *genericclioptions.ConfigFlags) *cobra.Command {
 	var all bool
 	var skipArchive bool
 	var cleanAll bool
+	var yes bool
+	var concurrency int
 	var planNames []string
 
 	cmd := &cobra.Command{
@@ -27,10 +33,19 @@ func NewPlanCmd(kubeConfigFlags *genericclioptions.ConfigFlags) *cobra.Command {
 
 By default, plans are archived before deletion to preserve history. Use
 --skip-archive to delete immediately without archiving. Use --clean-all
-to also clean up any target VMs created from failed migrations.`,
+to also clean up any target VMs created from failed migrations.
+
+The plans are listed first with their status, VM, migration and owned mapping
+counts, and deleted only after confirmation (or with --yes, or with the
+KUBECTL_MTV_ASSUME_YES=true environment variable). They are then deleted
+concurrently; the result of each plan is shown and the command fails if any plan
+could not be deleted.`,
 		Example: `  # Delete a plan (archives first)
   kubectl-mtv delete plan --name my-migration
 
+  # Delete a plan without asking for confirmation
+  kubectl-mtv delete plan --name my-migration --yes
+
   # Delete immediately without archiving
   kubectl-mtv delete plan --name my-migration --skip-archive
 
@@ -38,11 +53,14 @@ to also clean up any target VMs created from failed migrations.`,
   kubectl-mtv delete plan --name my-migration --clean-all
 
   # Delete multiple plans
-  kubectl-mtv delete plans --name plan1,plan2,plan3
+  kubectl-mtv delete plans plan1 plan2 plan3
+
+  # Delete multiple plans without asking, 8 at a time
+  kubectl-mtv delete plans --name plan1,plan2,plan3 --yes --concurrency 8
 
   # Delete all plans in namespace
   kubectl-mtv delete plans --all`,
-		Args:         cobra.MaximumNArgs(1),
+		Args:         cobra.ArbitraryArgs,
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if err := flags.ResolveNamesArg(&planNames, args); err != nil {
@@ -73,14 +91,38 @@ to also clean up any target VMs created from failed migrations.`,
 				}
 			}
 
-			// Loop over each plan name and delete it
-			for _, name := range planNames {
-				err := plan.Delete(cmd.Context(), kubeConfigFlags, name, namespace, skipArchive, cleanAll)
-				if err != nil {
-					return err
-				}
+			opts := bulk.Options{
+				Kind:        "plan",
+				Kinds:       "plans",
+				Namespace:   namespace,
+				Columns:     plan.SummaryColumns,
+				Yes:         yes,
+				Interactive: bugreport.Interactive(),
+				In:          os.Stdin,
+				Out:         os.Stdout,
+				Concurrency: concurrency,
+			}
+			// A dry run deletes nothing, so there is nothing to confirm
+			if client.DryRunEnabled() {
+				opts.Yes = true
+			}
+
+			// Show what the delete removes and ask before deleting anything
+			c, err := client.GetDynamicClient(kubeConfigFlags)
+			if err != nil {
+				return fmt.Errorf("failed to get client: %v", err)
+			}
+			summary, err := plan.Summarize(cmd.Context(), c, namespace, planNames)
+			if err != nil {
+				return err
 			}
-			return nil
+			if err := bulk.Confirm(summary, opts); err != nil {
+				return err
+			}
+
+			return bulk.Run(cmd.Context(), planNames, func(ctx context.Context, name string) error {
+				return plan.Delete(ctx, kubeConfigFlags, name, namespace, skipArchive, cleanAll)
+			}, opts)
 		},
 	}
 
@@ -90,8 +132,13 @@ to also clean up any target VMs created from failed migrations.`,
 	_ = cmd.Flags().MarkHidden("names")
 	cmd.Flags().BoolVar(&skipArchive, "skip-archive", false, "Skip archiving and delete the plan immediately")
 	cmd.Flags().BoolVar(&cleanAll, "clean-all", false, "Archive, delete VMs on failed migration, then delete")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Delete the plans without asking for confirmation")
+	cmd.Flags().IntVar(&concurrency, "concurrency", bulk.DefaultConcurrency, "Number of plans deleted at the same time")
 
+	cmd.ValidArgsFunction = completion.NameArgCompletion(completion.PlanNameCompletion(kubeConfigFlags))
 	_ = cmd.RegisterFlagCompletionFunc("name", completion.PlanNameCompletion(kubeConfigFlags))
 
+	flags.AddDryRunFlag(cmd, flags.NewDryRunFlag(), "Show what would be deleted without deleting it (client), or submit the delete with server-side dry run to validate it without persisting (server)")
+
 	return cmd
 }
@@ -3,20 +3,27 @@ package delete
 import (
 	"errors"
 	"fmt"
+	"os"
 
 	"github.com/spf13/cobra"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/delete/bulk"
 	"github.com/yaacov/kubectl-mtv/pkg/cmd/delete/provider"
+	"github.com/yaacov/kubectl-mtv/pkg/util/bugreport"
 	"github.com/yaacov/kubectl-mtv/pkg/util/client"
 	"github.com/yaacov/kubectl-mtv/pkg/util/completion"
 	"github.com/yaacov/kubectl-mtv/pkg/util/flags"
+	"github.com/yaacov/kubectl-mtv/pkg/util/progress"
 )
 
 // NewProviderCmd creates the provider deletion command
 func NewProviderCmd(kubeConfigFlags *genericclioptions.ConfigFlags) *cobra.Command {
 	var all bool
 	var providerNames []string
+	var cleanupPlans bool
+	var force bool
+	var yes bool
 
 	cmd := &cobra.Command{
 		Use:   "provider",
@@ -24,7 +31,17 @@ func NewProviderCmd(kubeConfigFlags *genericclioptions.ConfigFlags) *cobra.Comma
 		Long: `Delete one or more MTV providers.
 
 Deleting a provider removes its connection to the source or target environment.
-Ensure no migration plans reference the provider before deletion.`,
+
+Plans, network and storage mappings, and hosts that reference the provider would
+be left broken, so a referenced provider is not deleted: the references are listed
+instead. Use --cleanup-plans to delete them together with the provider (plans are
+archived first), or --force to delete only the provider and leave them broken.
+Both show the references and ask for confirmation (without a terminal, pass --yes).
+With --cleanup-plans --force, the provider is deleted even if some references
+could not be deleted.
+
+The providers are listed and deleted only after confirmation; --yes, or the
+KUBECTL_MTV_ASSUME_YES=true environment variable, skips the question.`,
 		Example: `  # Delete a provider
   kubectl-mtv delete provider --name vsphere-prod
 
@@ -32,7 +49,13 @@ Ensure no migration plans reference the provider before deletion.`,
   kubectl-mtv delete providers --name provider1,provider2
 
   # Delete all providers in namespace
-  kubectl-mtv delete providers --all`,
+  kubectl-mtv delete providers --all
+
+  # Delete a provider with the plans, mappings and hosts that reference it
+  kubectl-mtv delete provider --name vsphere-prod --cleanup-plans
+
+  # Delete a referenced provider, leaving its references broken, without asking
+  kubectl-mtv delete provider --name vsphere-prod --force --yes`,
 		Args:         cobra.MaximumNArgs(1),
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -64,13 +87,40 @@ Ensure no migration plans reference the provider before deletion.`,
 				}
 			}
 
+			opts := provider.DeleteOptions{
+				CleanupReferences: cleanupPlans,
+				Force:             force,
+				Confirm: bulk.Options{
+					Yes:         yes,
+					Interactive: bugreport.Interactive(),
+					In:          os.Stdin,
+					Out:         os.Stdout,
+				},
+			}
+			// A dry run deletes nothing, so there is nothing to confirm
+			if client.DryRunEnabled() {
+				opts.Confirm.Yes = true
+			}
+
+			// Show the providers and ask before deleting anything
+			opts.Confirm.Kind, opts.Confirm.Kinds, opts.Confirm.Namespace = "provider", "providers", namespace
+			opts.Confirm.Columns = bulk.NameColumns
+			if err := bulk.Confirm(bulk.NameItems(providerNames), opts.Confirm); err != nil {
+				return err
+			}
+
 			// Loop over each provider name and delete it
+			tracker := progress.Start("delete provider", "")
+			tracker.Stage("delete", "deleting providers", len(providerNames))
 			for _, name := range providerNames {
-				err := provider.Delete(kubeConfigFlags, name, namespace)
+				err := provider.DeleteWithReferences(cmd.Context(), kubeConfigFlags, name, namespace, opts)
 				if err != nil {
+					tracker.Done(err)
 					return err
 				}
+				tracker.Step(name)
 			}
+			tracker.Done(nil)
 			return nil
 		},
 	}
@@ -79,8 +129,14 @@ Ensure no migration plans reference the provider before deletion.`,
 	cmd.Flags().StringSliceVarP(&providerNames, "name", "M", nil, "Provider name(s) to delete (comma-separated, e.g. \"prov1,prov2\")")
 	cmd.Flags().StringSliceVar(&providerNames, "names", nil, "Alias for --name")
 	_ = cmd.Flags().MarkHidden("names")
+	cmd.Flags().BoolVar(&cleanupPlans, "cleanup-plans", false, "Also delete the plans, mappings and hosts that reference the provider")
+	cmd.Flags().BoolVar(&force, "force", false, "Delete the provider even if plans, mappings or hosts reference it")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Delete the providers, and their references, without asking for confirmation")
 
+	cmd.ValidArgsFunction = completion.NameArgCompletion(completion.ProviderNameCompletion(kubeConfigFlags))
 	_ = cmd.RegisterFlagCompletionFunc("name", completion.ProviderNameCompletion(kubeConfigFlags))
 
+	flags.AddDryRunFlag(cmd, flags.NewDryRunFlag(), "Show what would be deleted without deleting it (client), or submit the delete with server-side dry run to validate it without persisting (server)")
+
 	return cmd
 }
@@ -63,6 +63,7 @@ Shows the source and target network pairs, provider references, and status.`,
 	flags.MarkRequiredForMCP(cmd, "name")
 	cmd.Flags().VarP(outputFormatFlag, "output", "o", flags.OutputFormatHelp)
 
+	cmd.ValidArgsFunction = completion.NameArgCompletion(completion.MappingNameCompletion(globalConfig.GetKubeConfigFlags(), "network"))
 	_ = cmd.RegisterFlagCompletionFunc("name", completion.MappingNameCompletion(globalConfig.GetKubeConfigFlags(), "network"))
 	_ = cmd.RegisterFlagCompletionFunc("output", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		return outputFormatFlag.GetValidValues(), cobra.ShellCompDirectiveNoFileComp
@@ -101,6 +102,7 @@ Shows the source and target storage pairs, volume modes, access modes, and statu
 	flags.MarkRequiredForMCP(cmd, "name")
 	cmd.Flags().VarP(outputFormatFlag, "output", "o", flags.OutputFormatHelp)
 
+	cmd.ValidArgsFunction = completion.NameArgCompletion(completion.MappingNameCompletion(globalConfig.GetKubeConfigFlags(), "storage"))
 	_ = cmd.RegisterFlagCompletionFunc("name", completion.MappingNameCompletion(globalConfig.GetKubeConfigFlags(), "storage"))
 	_ = cmd.RegisterFlagCompletionFunc("output", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		return outputFormatFlag.GetValidValues(), cobra.ShellCompDirectiveNoFileComp
@@ -11,6 +11,7 @@ import (
 	vm "github.com/yaacov/kubectl-mtv/pkg/cmd/describe/vm"
 	"github.com/yaacov/kubectl-mtv/pkg/util/client"
 	"github.com/yaacov/kubectl-mtv/pkg/util/completion"
+	"github.com/yaacov/kubectl-mtv/pkg/util/conditions"
 	"github.com/yaacov/kubectl-mtv/pkg/util/flags"
 )
 
@@ -20,9 +21,15 @@ func NewPlanCmd(kubeConfigFlags *genericclioptions.ConfigFlags, globalConfig get
 	var withVMs bool
 	var vmName string
 	var watch bool
+	var history bool
+	var historyStore string
+	var conditionsHistory bool
 	var withDiagnostics bool
 	var logLines int
 	var showLines int
+	var selector string
+	var brief bool
+	var networkPreview bool
 	outputFormatFlag := flags.NewOutputFormatTypeFlag()
 
 	cmd := &cobra.Command{
@@ -32,7 +39,27 @@ func NewPlanCmd(kubeConfigFlags *genericclioptions.ConfigFlags, globalConfig get
 
 Shows plan configuration, status, conditions, and optionally the list of VMs.
 Use --vm to see detailed status of a specific VM in the plan.
-Use --diagnostics to include pod logs, events, and configuration context.`,
+Use --diagnostics to include pod logs, events, and configuration context.
+
+Use --selector to describe every plan matching a label selector, one after another, and
+--brief to condense each plan to a short section of key fields (status, providers, target,
+mappings, VM counts and blocking conditions), e.g. to review a whole wave at once.
+
+Forklift updates conditions in place, so earlier states are lost. Use --history to keep a
+client-side journal of condition changes and show it as a condition history timeline;
+combine it with --watch to catch flapping conditions. The journal is kept in the local
+cache directory, or with --history-store annotation on the plan itself.
+
+Use --conditions-history to show a timeline of the plan without keeping a journal: the phases
+it went through (Created, Ready, Executing, Succeeded/Failed/Canceled) and how long each took,
+other conditions and the events of the plan and its latest migration in time order, and the
+duration of each migration step of each VM. Earlier runs of a plan are not included.
+
+Use --network-preview before starting a plan with static IP preservation to see each source
+NIC (MAC, guest IPs, network) of the plan VMs and the target network it is mapped to, with
+the conflicts found: unmapped networks, static IPs on the pod network, IPs used twice in the
+plan or already in use in the target namespace, and NICs without guest IP information.
+The preview supports vSphere source providers.`,
 		Example: `  # Describe a plan
   kubectl-mtv describe plan --name my-migration
 
@@ -49,18 +76,31 @@ Use --diagnostics to include pod logs, events, and configuration context.`,
   kubectl-mtv describe plan --name my-migration --diagnostics
 
   # Show more log lines in diagnostics
-  kubectl-mtv describe plan --name my-migration --diagnostics --show-log-lines 20`,
+  kubectl-mtv describe plan --name my-migration --diagnostics --show-log-lines 20
+
+  # Watch a plan and record its condition history
+  kubectl-mtv describe plan --name my-migration --watch --history
+
+  # Show the phase timeline and per-VM step durations of a plan
+  kubectl-mtv describe plan --name my-migration --conditions-history
+
+  # Review all the plans of a wave in one condensed summary
+  kubectl-mtv describe plan -l wave=7 --brief
+
+  # Preview the source NICs, target networks and IP conflicts before starting
+  kubectl-mtv describe plan --name my-migration --network-preview`,
 		Args:         cobra.MaximumNArgs(1),
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if err := flags.ResolveNameArg(&name, args); err != nil {
 				return err
 			}
-			if name == "" {
-				return fmt.Errorf("--name is required")
+			if err := flags.ValidateSelector(selector, name); err != nil {
+				return err
+			}
+			if name == "" && selector == "" {
+				return fmt.Errorf("--name or --selector is required")
 			}
-
-			// Get the global configuration
 
 			// Validate mutual exclusivity
 			if withVMs && vmName != "" {
@@ -80,27 +120,75 @@ Use --diagnostics to include pod logs, events, and configuration context.`,
 			// Resolve the appropriate namespace based on context and flags
 			namespace := client.ResolveNamespace(globalConfig.GetKubeConfigFlags())
 
+			// Several plans, or a condensed summary
+			if selector != "" || brief {
+				if vmName != "" || withDiagnostics || history || conditionsHistory || networkPreview {
+					return fmt.Errorf("--vm, --diagnostics, --history, --conditions-history and --network-preview describe a single plan and cannot be combined with --selector or --brief")
+				}
+				if brief && withVMs {
+					return fmt.Errorf("--brief and --with-vms flags are mutually exclusive")
+				}
+				if selector != "" && globalConfig.GetAllNamespaces() {
+					namespace = ""
+				}
+				return plan.DescribeSelected(globalConfig.GetKubeConfigFlags(), plan.SelectOptions{
+					Name:      name,
+					Selector:  selector,
+					Namespace: namespace,
+					Brief:     brief,
+					WithVMs:   withVMs,
+				}, globalConfig.GetUseUTC(), outputFormat, watch)
+			}
+
+			// Network and static IP preview of the plan VMs
+			if networkPreview {
+				if vmName != "" || withVMs || withDiagnostics || history || conditionsHistory || watch {
+					return fmt.Errorf("--network-preview cannot be combined with --vm, --with-vms, --diagnostics, --history, --conditions-history or --watch")
+				}
+				return plan.DescribeNetworkPreview(globalConfig.GetKubeConfigFlags(), name, namespace,
+					globalConfig.GetInventoryURL(), globalConfig.GetInventoryInsecureSkipTLS(), outputFormat)
+			}
+
 			// If --vm flag is provided, switch to VM description behavior
 			if vmName != "" {
+				if conditionsHistory {
+					return fmt.Errorf("--conditions-history and --vm flags are mutually exclusive")
+				}
 				return vm.DescribeVM(globalConfig.GetKubeConfigFlags(), name, namespace, vmName, watch, globalConfig.GetUseUTC(), outputFormat)
 			}
 
+			if history && vmName != "" {
+				return fmt.Errorf("--history and --vm flags are mutually exclusive")
+			}
+			if !history {
+				historyStore = ""
+			}
+
 			// Default behavior: describe plan
-			return plan.Describe(globalConfig.GetKubeConfigFlags(), name, namespace, withVMs, withDiagnostics, logLines, showLines, globalConfig.GetUseUTC(), outputFormat)
+			return plan.Describe(globalConfig.GetKubeConfigFlags(), name, namespace, withVMs, withDiagnostics, logLines, showLines, globalConfig.GetUseUTC(), outputFormat, watch, historyStore, conditionsHistory)
 		},
 	}
 
 	cmd.Flags().StringVarP(&name, "name", "M", "", "Plan name")
-	flags.MarkRequiredForMCP(cmd, "name")
+	cmd.Flags().StringVarP(&selector, "selector", "l", "", flags.SelectorHelp+"; describes every matching plan")
+	cmd.Flags().BoolVar(&brief, "brief", false, "Show a condensed summary with one section of key fields per plan")
 	cmd.Flags().BoolVar(&withVMs, "with-vms", false, "Include list of VMs in the plan specification")
 	cmd.Flags().StringVar(&vmName, "vm", "", "VM name to describe (switches to VM description mode)")
-	cmd.Flags().BoolVarP(&watch, "watch", "w", false, "Watch VM status with live updates (only when --vm is used)")
+	cmd.Flags().BoolVarP(&watch, "watch", "w", false, "Watch the plan, or the VM status with --vm, with live updates")
+	cmd.Flags().BoolVar(&history, "history", false, "Record condition changes and show the condition history")
+	cmd.Flags().BoolVar(&conditionsHistory, "conditions-history", false, "Show a timeline of the plan phases, conditions and events with phase and per-VM step durations")
+	cmd.Flags().StringVar(&historyStore, "history-store", conditions.StoreLocal, "Where the condition history is kept: local (cache directory) or annotation (on the plan)")
+	cmd.Flags().BoolVar(&networkPreview, "network-preview", false, "Preview the source NICs, their target networks and static IP conflicts of the plan VMs")
 	cmd.Flags().BoolVarP(&withDiagnostics, "diagnostics", "D", false, "Include diagnostics (pod logs, events, configuration context)")
 	cmd.Flags().IntVar(&logLines, "scan-log-lines", 500, "Number of log lines to scan for diagnostics (max 10000)")
 	cmd.Flags().IntVar(&showLines, "show-log-lines", 10, "Number of log lines to display in diagnostics output (max 500)")
 	cmd.Flags().VarP(outputFormatFlag, "output", "o", flags.OutputFormatHelp)
 
+	cmd.ValidArgsFunction = completion.NameArgCompletion(completion.PlanNameCompletion(kubeConfigFlags))
 	_ = cmd.RegisterFlagCompletionFunc("name", completion.PlanNameCompletion(kubeConfigFlags))
+	_ = cmd.RegisterFlagCompletionFunc("history-store", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{conditions.StoreLocal, conditions.StoreAnnotation}, cobra.ShellCompDirectiveNoFileComp
+	})
 	_ = cmd.RegisterFlagCompletionFunc("output", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		return outputFormatFlag.GetValidValues(), cobra.ShellCompDirectiveNoFileComp
 	})
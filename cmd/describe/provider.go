@@ -8,14 +8,19 @@ import (
 
 	"github.com/yaacov/kubectl-mtv/cmd/get"
 	"github.com/yaacov/kubectl-mtv/pkg/cmd/describe/provider"
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/help"
 	"github.com/yaacov/kubectl-mtv/pkg/util/client"
 	"github.com/yaacov/kubectl-mtv/pkg/util/completion"
+	"github.com/yaacov/kubectl-mtv/pkg/util/conditions"
 	"github.com/yaacov/kubectl-mtv/pkg/util/flags"
 )
 
 // NewProviderCmd creates the provider description command
 func NewProviderCmd(kubeConfigFlags *genericclioptions.ConfigFlags, globalConfig get.GlobalConfigGetter) *cobra.Command {
 	var name string
+	var watch, history bool
+	var showCredentials, revealCredentials bool
+	var historyStore string
 	outputFormatFlag := flags.NewOutputFormatTypeFlag()
 
 	cmd := &cobra.Command{
@@ -24,12 +29,29 @@ func NewProviderCmd(kubeConfigFlags *genericclioptions.ConfigFlags, globalConfig
 		Long: `Display detailed information about a migration provider.
 
 Shows provider configuration, type, URL, connection status, conditions,
-secret reference, and provider-specific settings (VDDK, SDK endpoint, etc.).`,
+secret reference, and provider-specific settings (VDDK, SDK endpoint, etc.).
+
+Forklift updates conditions in place, so earlier states are lost. Use --history to keep a
+client-side journal of condition changes and show it as a condition history timeline;
+combine it with --watch to catch flapping conditions. The journal is kept in the local
+cache directory, or with --history-store annotation on the provider itself.
+
+Use --show-credentials to inspect the provider secret: identifying fields such as the URL,
+username and insecureSkipVerify are shown, CA certificates by fingerprint, and passwords,
+tokens and unknown fields are masked. Reading the secret requires permission to get
+secrets in its namespace. --reveal-credentials shows every field in clear text, for
+break-glass debugging only.`,
 		Example: `  # Describe a provider
   kubectl-mtv describe provider --name vsphere-prod
 
   # Describe a provider in JSON format
-  kubectl-mtv describe provider --name vsphere-prod --output json`,
+  kubectl-mtv describe provider --name vsphere-prod --output json
+
+  # Inspect the provider credentials with secrets masked
+  kubectl-mtv describe provider --name vsphere-prod --show-credentials
+
+  # Watch a provider and record its condition history
+  kubectl-mtv describe provider --name vsphere-prod --watch --history`,
 		Args:         cobra.MaximumNArgs(1),
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -40,16 +62,42 @@ secret reference, and provider-specific settings (VDDK, SDK endpoint, etc.).`,
 				return fmt.Errorf("--name is required")
 			}
 
+			outputFormat := outputFormatFlag.GetValue()
+			if watch && outputFormat != "table" {
+				return fmt.Errorf("--watch and --output %s are mutually exclusive; --watch only works with table output", outputFormat)
+			}
+			if !history {
+				historyStore = ""
+			}
+			credentials := provider.CredentialsHidden
+			switch {
+			case revealCredentials:
+				credentials = provider.CredentialsReveal
+			case showCredentials:
+				credentials = provider.CredentialsMasked
+			}
+
 			namespace := client.ResolveNamespace(globalConfig.GetKubeConfigFlags())
-			return provider.Describe(cmd.Context(), globalConfig.GetKubeConfigFlags(), name, namespace, globalConfig.GetUseUTC(), outputFormatFlag.GetValue())
+			return provider.Describe(cmd.Context(), globalConfig.GetKubeConfigFlags(), name, namespace, globalConfig.GetUseUTC(), outputFormat, watch, historyStore, credentials)
 		},
 	}
 
 	cmd.Flags().StringVarP(&name, "name", "M", "", "Provider name")
 	flags.MarkRequiredForMCP(cmd, "name")
 	cmd.Flags().VarP(outputFormatFlag, "output", "o", flags.OutputFormatHelp)
+	cmd.Flags().BoolVarP(&watch, "watch", "w", false, "Watch the provider with live updates")
+	cmd.Flags().BoolVar(&history, "history", false, "Record condition changes and show the condition history")
+	cmd.Flags().StringVar(&historyStore, "history-store", conditions.StoreLocal, "Where the condition history is kept: local (cache directory) or annotation (on the provider)")
+	cmd.Flags().BoolVar(&showCredentials, "show-credentials", false, "Show the provider secret fields, with passwords and tokens masked")
+	cmd.Flags().BoolVar(&revealCredentials, "reveal-credentials", false, "Show the provider secret fields in clear text (break-glass debugging)")
+	help.MarkMCPHidden(cmd, "watch")
+	help.MarkMCPHidden(cmd, "reveal-credentials")
 
+	cmd.ValidArgsFunction = completion.NameArgCompletion(completion.ProviderNameCompletion(kubeConfigFlags))
 	_ = cmd.RegisterFlagCompletionFunc("name", completion.ProviderNameCompletion(kubeConfigFlags))
+	_ = cmd.RegisterFlagCompletionFunc("history-store", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{conditions.StoreLocal, conditions.StoreAnnotation}, cobra.ShellCompDirectiveNoFileComp
+	})
 	_ = cmd.RegisterFlagCompletionFunc("output", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		return outputFormatFlag.GetValidValues(), cobra.ShellCompDirectiveNoFileComp
 	})
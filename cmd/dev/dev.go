@@ -0,0 +1,20 @@
+package dev
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// NewDevCmd creates the dev command with all its subcommands
+func NewDevCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "dev",
+		Short: "Tools for developing and testing migration pipelines",
+		Long: `Tools for developing and testing migration pipelines without access to the source
+providers, for example in CI.`,
+		SilenceUsage: true,
+	}
+
+	fakeInventoryCmd := NewFakeInventoryCmd()
+	cmd.AddCommand(fakeInventoryCmd)
+	return cmd
+}
@@ -0,0 +1,55 @@
+package dev
+
+import (
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/dev/fakeinventory"
+)
+
+// NewFakeInventoryCmd creates the dev fake-inventory command
+func NewFakeInventoryCmd() *cobra.Command {
+	var from string
+	var listen string
+
+	cmd := &cobra.Command{
+		Use:   "fake-inventory",
+		Short: "Serve recorded inventory snapshots over the inventory API",
+		Long: `Serve inventory snapshots saved by 'inventory snapshot' over the same API as the
+Forklift inventory service, so inventory queries, assessments and plan creation can run
+in CI without access to vCenter or other source providers.
+
+--from is a directory of snapshot files (.json, .yaml, .yml) or a single snapshot file.
+Point kubectl-mtv at the server with --inventory-url (or MTV_INVENTORY_URL).
+
+The provider resources must still exist in the cluster and be Ready. A request for a
+provider is answered from the snapshot with the same UID or name; when there is no such
+snapshot and only one snapshot has the provider type, that snapshot is used, so snapshots
+recorded in one cluster serve providers created in another. Collections that were not
+recorded are served empty.`,
+		Example: `  # Record the inventory of a provider once
+  kubectl-mtv inventory snapshot --provider vsphere-prod -f testdata/inventory/vsphere-prod.json
+
+  # Serve the recorded snapshots
+  kubectl-mtv dev fake-inventory --from testdata/inventory --listen :9090
+
+  # Run inventory queries against the recorded inventory
+  kubectl-mtv get inventory vm --provider vsphere-prod --inventory-url http://localhost:9090`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, stop := signal.NotifyContext(cmd.Context(), syscall.SIGINT, syscall.SIGTERM)
+			defer stop()
+
+			return fakeinventory.Serve(ctx, from, listen)
+		},
+	}
+
+	cmd.Flags().StringVar(&from, "from", "", "Directory of inventory snapshot files, or a single snapshot file")
+	_ = cmd.MarkFlagRequired("from")
+	cmd.Flags().StringVar(&listen, "listen", ":9090", "Address to listen on")
+
+	return cmd
+}
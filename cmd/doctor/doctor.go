@@ -0,0 +1,72 @@
+package doctor
+
+import (
+	"context"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	pkgdoctor "github.com/yaacov/kubectl-mtv/pkg/cmd/doctor"
+	"github.com/yaacov/kubectl-mtv/pkg/util/client"
+	"github.com/yaacov/kubectl-mtv/pkg/util/config"
+	"github.com/yaacov/kubectl-mtv/pkg/util/flags"
+	"github.com/yaacov/kubectl-mtv/pkg/version"
+)
+
+// NewDoctorCmd creates the doctor command
+func NewDoctorCmd(kubeConfigFlags *genericclioptions.ConfigFlags, globalConfig config.GlobalConfigGetter) *cobra.Command {
+	outputFormatFlag := flags.NewOutputFormatTypeFlag()
+
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check that kubectl-mtv can work with the cluster and print what to fix",
+		Long: `Check everything kubectl-mtv needs to work and print a prioritized list of fixes.
+
+Run it first when nothing seems to work. It checks:
+- Cluster connection of the current kubeconfig context
+- MTV operator installation and version
+- Version skew between kubectl-mtv and the MTV operator
+- Forklift API (CRDs) served by the cluster
+- Inventory service reachability
+- Your permissions for common operations in the namespace (list and create
+  providers, mappings, plans and migrations, create secrets)
+- kubectl or oc on PATH, used by the MCP kubectl tools, and kubectl-mtv on PATH
+
+Checks that depend on a failed one are skipped. Fixes are listed critical
+first; within a severity, the fix unblocking the most other checks comes first.
+Use 'kubectl-mtv health' for the health of a working installation.`,
+		Example: `  # Check the environment
+  kubectl-mtv doctor
+
+  # Check permissions in a specific namespace
+  kubectl-mtv doctor --namespace migrations
+
+  # Machine-readable report
+  kubectl-mtv doctor --output json`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := context.WithTimeout(cmd.Context(), 60*time.Second)
+			defer cancel()
+
+			report := pkgdoctor.Run(ctx, kubeConfigFlags, pkgdoctor.Options{
+				Namespace:                client.ResolveNamespace(kubeConfigFlags),
+				InventoryURL:             globalConfig.GetInventoryURL(),
+				InventoryInsecureSkipTLS: globalConfig.GetInventoryInsecureSkipTLS(),
+				ClientVersion:            version.ClientVersion,
+			})
+			return pkgdoctor.Print(report, outputFormatFlag.GetValue())
+		},
+	}
+
+	cmd.Flags().VarP(outputFormatFlag, "output", "o", flags.OutputFormatHelp)
+
+	if err := cmd.RegisterFlagCompletionFunc("output", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return outputFormatFlag.GetValidValues(), cobra.ShellCompDirectiveNoFileComp
+	}); err != nil {
+		panic(err)
+	}
+
+	return cmd
+}
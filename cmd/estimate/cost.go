@@ -0,0 +1,69 @@
+package estimate
+
+import (
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/yaacov/kubectl-mtv/cmd/get"
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/estimate/cost"
+	"github.com/yaacov/kubectl-mtv/pkg/util/client"
+	"github.com/yaacov/kubectl-mtv/pkg/util/completion"
+)
+
+// NewCostCmd creates the estimate cost command
+func NewCostCmd(kubeConfigFlags *genericclioptions.ConfigFlags, globalConfig get.GlobalConfigGetter) *cobra.Command {
+	opts := cost.Options{}
+	var pricingFile string
+
+	cmd := &cobra.Command{
+		Use:   "cost",
+		Short: "Project the monthly cost of a plan's VMs on the target",
+		Long: `Project the monthly cost of the VMs of a plan on the target cluster.
+
+The vCPUs, memory and disk size of each VM are read from the source provider inventory
+and multiplied by the prices of a pricing file, in YAML or JSON:
+
+  currency: USD              # shown with the costs
+  hoursPerMonth: 730         # converts hourly prices to monthly costs (default 730)
+  cpuPerHour: 0.0316         # price of one vCPU per hour
+  memoryGiBPerHour: 0.0042   # price of one GiB of memory per hour
+  storageGiBPerMonth: 0.08   # price of one GiB of disk per month
+  vmPerMonth: 0              # fixed price per VM, e.g. a subscription
+
+The estimate assumes the VMs keep their source size and run all month. VMs not
+found in the inventory, or without a known size, are listed with a note and
+priced only for what is known.`,
+		Example: `  # Estimate the monthly cost of a plan
+  kubectl-mtv estimate cost --plan my-plan --pricing pricing.yaml
+
+  # Paste the estimate into a ticket
+  kubectl-mtv estimate cost --plan my-plan --pricing pricing.yaml -o markdown`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			pricing, err := cost.LoadPricing(pricingFile)
+			if err != nil {
+				return err
+			}
+			cfg := globalConfig.GetKubeConfigFlags()
+			opts.Pricing = pricing
+			opts.Namespace = client.ResolveNamespace(cfg)
+			opts.InventoryURL = globalConfig.GetInventoryURL()
+			opts.InsecureSkipTLS = globalConfig.GetInventoryInsecureSkipTLS()
+			return cost.Print(cmd.Context(), cfg, opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.Plan, "plan", "", "Plan whose VMs are priced")
+	cmd.Flags().StringVar(&pricingFile, "pricing", "", "Pricing file with the prices of the target (YAML or JSON)")
+	cmd.Flags().StringVarP(&opts.Output, "output", "o", "table", "Output format (table, markdown, json, yaml)")
+	_ = cmd.MarkFlagRequired("plan")
+	_ = cmd.MarkFlagRequired("pricing")
+
+	_ = cmd.RegisterFlagCompletionFunc("plan", completion.PlanNameCompletion(kubeConfigFlags))
+	_ = cmd.RegisterFlagCompletionFunc("output", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"table", "markdown", "json", "yaml"}, cobra.ShellCompDirectiveNoFileComp
+	})
+
+	return cmd
+}
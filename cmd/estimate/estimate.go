@@ -0,0 +1,21 @@
+package estimate
+
+import (
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/yaacov/kubectl-mtv/cmd/get"
+)
+
+// NewEstimateCmd creates the estimate command with all its subcommands
+func NewEstimateCmd(kubeConfigFlags *genericclioptions.ConfigFlags, globalConfig get.GlobalConfigGetter) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "estimate",
+		Short:        "Estimate the cost of migration plans",
+		Long:         `Estimate what the VMs of a migration plan will cost on the target cluster`,
+		SilenceUsage: true,
+	}
+
+	cmd.AddCommand(NewCostCmd(kubeConfigFlags, globalConfig))
+	return cmd
+}
@@ -0,0 +1,94 @@
+package events
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/yaacov/kubectl-mtv/cmd/get"
+	pkgevents "github.com/yaacov/kubectl-mtv/pkg/cmd/events"
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/help"
+	"github.com/yaacov/kubectl-mtv/pkg/util/client"
+	"github.com/yaacov/kubectl-mtv/pkg/util/completion"
+)
+
+// NewEventsCmd creates the events command
+func NewEventsCmd(kubeConfigFlags *genericclioptions.ConfigFlags, globalConfig get.GlobalConfigGetter) *cobra.Command {
+	var planName string
+	var vmName string
+	var types []string
+	var outputFormat string
+	var watch bool
+
+	cmd := &cobra.Command{
+		Use:   "events",
+		Short: "Show a timeline of the events of migrations",
+		Long: `Show the Kubernetes events related to MTV resources as one chronological timeline.
+
+Events are gathered from:
+  - MTV resources: plans, migrations, providers, mappings, hosts and hooks
+  - Objects created for a plan in its target namespace: migration and virt-v2v
+    conversion pods, PVCs and DataVolumes
+
+Each event is shown with the plan and VM its object belongs to. With --plan only the
+events of that plan are shown. With --vm only the events of the pods, PVCs and
+DataVolumes of that VM are shown, together with plan and migration events naming it.
+
+Kubernetes keeps events for a limited time (one hour by default), so older events
+may be gone.`,
+		Example: `  # Timeline of all migration events in the current namespace
+  kubectl-mtv events
+
+  # Events of one plan
+  kubectl-mtv events --plan my-migration
+
+  # Events of one VM of a plan, refreshed live
+  kubectl-mtv events --plan my-migration --vm web-01 --watch
+
+  # Only warnings, in all namespaces
+  kubectl-mtv events -A --types Warning
+
+  # Machine-readable timeline
+  kubectl-mtv events --plan my-migration --output json`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			for _, t := range types {
+				if t != pkgevents.TypeNormal && t != pkgevents.TypeWarning {
+					return fmt.Errorf("invalid event type '%s': use %s or %s", t, pkgevents.TypeNormal, pkgevents.TypeWarning)
+				}
+			}
+			if planName != "" && globalConfig.GetAllNamespaces() {
+				return fmt.Errorf("--plan cannot be used with --all-namespaces")
+			}
+
+			cfg := globalConfig.GetKubeConfigFlags()
+			opts := pkgevents.Options{
+				ConfigFlags: cfg,
+				Namespace:   client.ResolveNamespaceWithAllFlag(cfg, globalConfig.GetAllNamespaces()),
+				Plan:        planName,
+				VM:          vmName,
+				Types:       types,
+			}
+			return pkgevents.Print(cmd.Context(), opts, outputFormat, watch)
+		},
+	}
+
+	cmd.Flags().StringVarP(&planName, "plan", "p", "", "Show only the events of this plan")
+	cmd.Flags().StringVar(&vmName, "vm", "", "Show only the events of this VM, by name or ID")
+	cmd.Flags().StringSliceVar(&types, "types", nil, "Show only events of these types (Normal, Warning)")
+	cmd.Flags().StringVarP(&outputFormat, "output", "o", "table", "Output format (table, json, yaml)")
+	cmd.Flags().BoolVarP(&watch, "watch", "w", false, "Watch for new events")
+	help.MarkMCPHidden(cmd, "watch")
+
+	_ = cmd.RegisterFlagCompletionFunc("plan", completion.PlanNameCompletion(kubeConfigFlags))
+	_ = cmd.RegisterFlagCompletionFunc("types", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{pkgevents.TypeNormal, pkgevents.TypeWarning}, cobra.ShellCompDirectiveNoFileComp
+	})
+	_ = cmd.RegisterFlagCompletionFunc("output", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"table", "json", "yaml"}, cobra.ShellCompDirectiveNoFileComp
+	})
+
+	return cmd
+}
@@ -15,9 +15,10 @@ import (
 
 // NewConversionCmd creates the get conversion command
 func NewConversionCmd(kubeConfigFlags *genericclioptions.ConfigFlags, globalConfig GlobalConfigGetter) *cobra.Command {
-	outputFormatFlag := flags.NewOutputFormatTypeFlag()
+	outputFormatFlag := flags.NewOutputFormatTypeFlag().WithTemplates()
 	var watch bool
 	var query string
+	var selector string
 	var convName string
 
 	cmd := &cobra.Command{
@@ -42,6 +43,9 @@ inspection, in-place, and remote virt-v2v operations.`,
 			if err := flags.ResolveNameArg(&convName, args); err != nil {
 				return err
 			}
+			if err := flags.ValidateSelector(selector, convName); err != nil {
+				return err
+			}
 
 			ctx := cmd.Context()
 			if !watch {
@@ -61,13 +65,14 @@ inspection, in-place, and remote virt-v2v operations.`,
 			}
 			logOutputFormat(outputFormatFlag.GetValue())
 
-			return conversion.List(ctx, kubeConfigFlags, namespace, watch, outputFormatFlag.GetValue(), convName, globalConfig.GetUseUTC(), query)
+			return conversion.List(ctx, kubeConfigFlags, namespace, watch, outputFormatFlag.GetValue(), convName, globalConfig.GetUseUTC(), query, selector)
 		},
 	}
 
 	cmd.Flags().StringVarP(&convName, "name", "M", "", "Conversion name")
-	cmd.Flags().VarP(outputFormatFlag, "output", "o", flags.OutputFormatHelp)
+	cmd.Flags().VarP(outputFormatFlag, "output", "o", flags.OutputFormatTemplateHelp)
 	cmd.Flags().StringVarP(&query, "query", "q", "", flags.QueryHelp)
+	cmd.Flags().StringVarP(&selector, "selector", "l", "", flags.SelectorHelp)
 	cmd.Flags().BoolVarP(&watch, "watch", "w", false, "Watch for changes")
 	help.MarkMCPHidden(cmd, "watch")
 
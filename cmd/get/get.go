@@ -75,6 +75,11 @@ func NewGetCmd(kubeConfigFlags *genericclioptions.ConfigFlags, globalConfig Glob
 	conversionCmd.Aliases = []string{"conversions"}
 	cmd.AddCommand(conversionCmd)
 
+	// Add wave subcommand with plural alias
+	waveCmd := NewWaveCmd(kubeConfigFlags, globalConfig)
+	waveCmd.Aliases = []string{"waves"}
+	cmd.AddCommand(waveCmd)
+
 	// Add inventory subcommand
 	cmd.AddCommand(NewInventoryCmd(kubeConfigFlags, globalConfig))
 
@@ -16,9 +16,10 @@ import (
 
 // NewHookCmd creates the get hook command
 func NewHookCmd(kubeConfigFlags *genericclioptions.ConfigFlags, globalConfig GlobalConfigGetter) *cobra.Command {
-	outputFormatFlag := flags.NewOutputFormatTypeFlag()
+	outputFormatFlag := flags.NewOutputFormatTypeFlag().WithTemplates()
 	var watch bool
 	var query string
+	var selector string
 
 	var hookName string
 	cmd := &cobra.Command{
@@ -43,6 +44,9 @@ the migration process, such as installing drivers or configuring the target VM.`
 			if err := flags.ResolveNameArg(&hookName, args); err != nil {
 				return err
 			}
+			if err := flags.ValidateSelector(selector, hookName); err != nil {
+				return err
+			}
 
 			ctx := cmd.Context()
 			if !watch {
@@ -64,13 +68,14 @@ the migration process, such as installing drivers or configuring the target VM.`
 			}
 			logOutputFormat(outputFormatFlag.GetValue())
 
-			return hook.List(ctx, kubeConfigFlags, namespace, watch, outputFormatFlag.GetValue(), hookName, globalConfig.GetUseUTC(), query)
+			return hook.List(ctx, kubeConfigFlags, namespace, watch, outputFormatFlag.GetValue(), hookName, globalConfig.GetUseUTC(), query, selector)
 		},
 	}
 
 	cmd.Flags().StringVarP(&hookName, "name", "M", "", "Hook name")
-	cmd.Flags().VarP(outputFormatFlag, "output", "o", flags.OutputFormatHelp)
+	cmd.Flags().VarP(outputFormatFlag, "output", "o", flags.OutputFormatTemplateHelp)
 	cmd.Flags().StringVarP(&query, "query", "q", "", flags.QueryHelp)
+	cmd.Flags().StringVarP(&selector, "selector", "l", "", flags.SelectorHelp)
 	cmd.Flags().BoolVarP(&watch, "watch", "w", false, "Watch for changes")
 	help.MarkMCPHidden(cmd, "watch")
 
@@ -16,9 +16,10 @@ import (
 
 // NewHostCmd creates the get host command
 func NewHostCmd(kubeConfigFlags *genericclioptions.ConfigFlags, globalConfig GlobalConfigGetter) *cobra.Command {
-	outputFormatFlag := flags.NewOutputFormatTypeFlag()
+	outputFormatFlag := flags.NewOutputFormatTypeFlag().WithTemplates()
 	var watch bool
 	var query string
+	var selector string
 
 	var hostName string
 	cmd := &cobra.Command{
@@ -42,6 +43,9 @@ for oVirt migrations. They store host-specific credentials and configuration.`,
 			if err := flags.ResolveNameArg(&hostName, args); err != nil {
 				return err
 			}
+			if err := flags.ValidateSelector(selector, hostName); err != nil {
+				return err
+			}
 
 			ctx := cmd.Context()
 			if !watch {
@@ -61,13 +65,14 @@ for oVirt migrations. They store host-specific credentials and configuration.`,
 			}
 			logOutputFormat(outputFormatFlag.GetValue())
 
-			return host.List(ctx, globalConfig.GetKubeConfigFlags(), namespace, watch, outputFormatFlag.GetValue(), hostName, globalConfig.GetUseUTC(), query)
+			return host.List(ctx, globalConfig.GetKubeConfigFlags(), namespace, watch, outputFormatFlag.GetValue(), hostName, globalConfig.GetUseUTC(), query, selector)
 		},
 	}
 
 	cmd.Flags().StringVarP(&hostName, "name", "M", "", "Host name")
-	cmd.Flags().VarP(outputFormatFlag, "output", "o", flags.OutputFormatHelp)
+	cmd.Flags().VarP(outputFormatFlag, "output", "o", flags.OutputFormatTemplateHelp)
 	cmd.Flags().StringVarP(&query, "query", "q", "", flags.QueryHelp)
+	cmd.Flags().StringVarP(&selector, "selector", "l", "", flags.SelectorHelp)
 	cmd.Flags().BoolVarP(&watch, "watch", "w", false, "Watch for changes")
 	help.MarkMCPHidden(cmd, "watch")
 
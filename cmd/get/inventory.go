@@ -23,7 +23,8 @@ Available resource types vary by provider:
   - oVirt: diskprofile, nicprofile
   - OpenStack: instance, image, flavor, project, volume, volumetype, snapshot, subnet
   - OpenShift: namespace, pvc, datavolume
-  - EC2: ec2instance, ec2volume, ec2volumetype, ec2network
+  - EC2: ec2-instance, ec2-volume, ec2-volume-type, ec2-network (subnets), ec2-snapshot,
+         ec2-image, ec2-security-group
   - Azure: vm, network, storage`,
 		SilenceUsage: true,
 	}
@@ -144,13 +145,21 @@ Available resource types vary by provider:
 	cmd.AddCommand(ec2VolumeTypeCmd)
 
 	ec2NetworkCmd := NewInventoryEC2NetworkCmd(kubeConfigFlags, globalConfig)
-	ec2NetworkCmd.Aliases = []string{"ec2-networks"}
+	ec2NetworkCmd.Aliases = []string{"ec2-networks", "ec2-subnet", "ec2-subnets"}
 	cmd.AddCommand(ec2NetworkCmd)
 
 	ec2SnapshotCmd := NewInventoryEC2SnapshotCmd(kubeConfigFlags, globalConfig)
 	ec2SnapshotCmd.Aliases = []string{"ec2-snapshots"}
 	cmd.AddCommand(ec2SnapshotCmd)
 
+	ec2ImageCmd := NewInventoryEC2ImageCmd(kubeConfigFlags, globalConfig)
+	ec2ImageCmd.Aliases = []string{"ec2-images", "ec2-ami", "ec2-amis"}
+	cmd.AddCommand(ec2ImageCmd)
+
+	ec2SecurityGroupCmd := NewInventoryEC2SecurityGroupCmd(kubeConfigFlags, globalConfig)
+	ec2SecurityGroupCmd.Aliases = []string{"ec2-security-groups", "ec2-sg"}
+	cmd.AddCommand(ec2SecurityGroupCmd)
+
 	// Add AAP resources
 	jobTemplateCmd := NewInventoryJobTemplateCmd(kubeConfigFlags, globalConfig)
 	jobTemplateCmd.Aliases = []string{"job-templates", "jobtemplates"}
@@ -13,7 +13,7 @@ import (
 
 // NewInventoryJobTemplateCmd creates the get inventory job-template command
 func NewInventoryJobTemplateCmd(kubeConfigFlags *genericclioptions.ConfigFlags, globalConfig GlobalConfigGetter) *cobra.Command {
-	outputFormatFlag := flags.NewOutputFormatTypeFlag()
+	outputFormatFlag := flags.NewOutputFormatTypeFlag().WithTemplates()
 	var query string
 
 	cmd := &cobra.Command{
@@ -48,7 +48,7 @@ Examples:
 		},
 	}
 
-	cmd.Flags().VarP(outputFormatFlag, "output", "o", flags.OutputFormatHelp)
+	cmd.Flags().VarP(outputFormatFlag, "output", "o", flags.OutputFormatTemplateHelp)
 	cmd.Flags().StringVarP(&query, "query", "q", "", flags.QueryHelp)
 
 	if err := cmd.RegisterFlagCompletionFunc("output", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
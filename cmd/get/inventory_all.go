@@ -2,6 +2,7 @@ package get
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -12,14 +13,16 @@ import (
 	"github.com/yaacov/kubectl-mtv/pkg/util/client"
 	"github.com/yaacov/kubectl-mtv/pkg/util/completion"
 	"github.com/yaacov/kubectl-mtv/pkg/util/flags"
+	"github.com/yaacov/kubectl-mtv/pkg/util/output"
 )
 
 // NewInventoryNetworkCmd creates the get inventory network command
 func NewInventoryNetworkCmd(kubeConfigFlags *genericclioptions.ConfigFlags, globalConfig GlobalConfigGetter) *cobra.Command {
-	outputFormatFlag := flags.NewOutputFormatTypeFlag()
+	outputFormatFlag := flags.NewOutputFormatTypeFlag().WithTemplates()
 	var query string
 	var watch bool
 	var provider string
+	var inUseBy string
 
 	cmd := &cobra.Command{
 		Use:   "network",
@@ -27,7 +30,12 @@ func NewInventoryNetworkCmd(kubeConfigFlags *genericclioptions.ConfigFlags, glob
 		Long: `Get networks from a provider's inventory.
 
 Queries the MTV inventory service to list networks available in the source provider.
-Use --query to filter results using TSL query syntax.`,
+Use --query to filter results using TSL query syntax.
+
+Use --in-use-by to show how many VMs have a NIC on each network, or --in-use-by=names
+to also list them, so you know which networks actually need a mapping before creating
+one. The vmCount, vmNames and vmNamesHuman fields can be used in queries and are added
+to JSON and YAML output. Supported for vSphere, oVirt, OpenStack, OVA and Hyper-V providers.`,
 		Example: `  # Filter networks by name
   kubectl-mtv get inventory networks --provider vsphere-prod --query "where name ~= 'VM Network.*'"
 
@@ -35,7 +43,13 @@ Use --query to filter results using TSL query syntax.`,
   kubectl-mtv get inventory networks --provider vsphere-prod
 
   # Output as JSON
-  kubectl-mtv get inventory networks --provider vsphere-prod --output json`,
+  kubectl-mtv get inventory networks --provider vsphere-prod --output json
+
+  # Show how many VMs use each network, and which ones
+  kubectl-mtv get inventory networks --provider vsphere-prod --in-use-by=names
+
+  # List only the networks in use, which need a network mapping
+  kubectl-mtv get inventory networks --provider vsphere-prod --in-use-by --query "where vmCount > 0"`,
 		Args:         cobra.NoArgs,
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -55,36 +69,48 @@ Use --query to filter results using TSL query syntax.`,
 			inventoryURL := globalConfig.GetInventoryURL()
 			inventoryInsecureSkipTLS := globalConfig.GetInventoryInsecureSkipTLS()
 
-			return inventory.ListNetworksWithInsecure(ctx, globalConfig.GetKubeConfigFlags(), provider, namespace, inventoryURL, outputFormatFlag.GetValue(), query, watch, inventoryInsecureSkipTLS)
+			return inventory.ListNetworksWithInsecure(ctx, globalConfig.GetKubeConfigFlags(), provider, namespace, inventoryURL, outputFormatFlag.GetValue(), query, inUseBy, watch, inventoryInsecureSkipTLS)
 		},
 	}
 
 	cmd.Flags().StringVarP(&provider, "provider", "p", "", "Provider name")
 	_ = cmd.MarkFlagRequired("provider")
-	cmd.Flags().VarP(outputFormatFlag, "output", "o", flags.OutputFormatHelp)
+	cmd.Flags().VarP(outputFormatFlag, "output", "o", flags.OutputFormatTemplateHelp)
 	cmd.Flags().StringVarP(&query, "query", "q", "", flags.QueryHelp)
 	cmd.Flags().BoolVarP(&watch, "watch", "w", false, "Watch for changes")
+	cmd.Flags().StringVar(&inUseBy, "in-use-by", "", "Show the VMs using each network: count, or names to also list them (--in-use-by alone is count)")
+	cmd.Flags().Lookup("in-use-by").NoOptDefVal = inventory.InUseByCount
 	help.MarkMCPHidden(cmd, "watch")
 
 	// Add completion for provider and output format flags
 	if err := cmd.RegisterFlagCompletionFunc("provider", completion.ProviderNameCompletion(kubeConfigFlags)); err != nil {
 		panic(err)
 	}
+	if err := cmd.RegisterFlagCompletionFunc("query", completion.QueryCompletion(kubeConfigFlags, help.SchemaResourceNetwork)); err != nil {
+		panic(err)
+	}
 	if err := cmd.RegisterFlagCompletionFunc("output", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		return outputFormatFlag.GetValidValues(), cobra.ShellCompDirectiveNoFileComp
 	}); err != nil {
 		panic(err)
 	}
+	if err := cmd.RegisterFlagCompletionFunc("in-use-by", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{inventory.InUseByCount, inventory.InUseByNames}, cobra.ShellCompDirectiveNoFileComp
+	}); err != nil {
+		panic(err)
+	}
 
 	return cmd
 }
 
 // NewInventoryStorageCmd creates the get inventory storage command
 func NewInventoryStorageCmd(kubeConfigFlags *genericclioptions.ConfigFlags, globalConfig GlobalConfigGetter) *cobra.Command {
-	outputFormatFlag := flags.NewOutputFormatTypeFlag()
+	outputFormatFlag := flags.NewOutputFormatTypeFlag().WithTemplates()
 	var query string
 	var watch bool
 	var provider string
+	var capacity bool
+	var minFreePercent float64
 
 	cmd := &cobra.Command{
 		Use:   "storage",
@@ -92,7 +118,12 @@ func NewInventoryStorageCmd(kubeConfigFlags *genericclioptions.ConfigFlags, glob
 		Long: `Get storage resources from a provider's inventory.
 
 Queries the MTV inventory service to list storage domains (oVirt), datastores (vSphere),
-or storage classes (OpenShift) available in the source provider.`,
+or storage classes (OpenShift) available in the source provider.
+
+Use --capacity to show the capacity, used and free space of vSphere datastores or
+oVirt storage domains, with a total row, and to mark the ones with less free space
+than --min-free-percent as LOW. This helps to pick target storage with enough
+headroom before creating plans.`,
 		Example: `  # Filter storage by name pattern
   kubectl-mtv get inventory storages --provider ovirt-prod --query "where name ~= 'data.*'"
 
@@ -100,7 +131,13 @@ or storage classes (OpenShift) available in the source provider.`,
   kubectl-mtv get inventory storages --provider vsphere-prod
 
   # Output as YAML
-  kubectl-mtv get inventory storages --provider vsphere-prod --output yaml`,
+  kubectl-mtv get inventory storages --provider vsphere-prod --output yaml
+
+  # Show capacity and headroom, marking datastores with less than 30% free space
+  kubectl-mtv get inventory storages --provider vsphere-prod --capacity --min-free-percent 30
+
+  # List only datastores with low headroom
+  kubectl-mtv get inventory storages --provider vsphere-prod --capacity --query "where headroom = 'LOW'"`,
 		Args:         cobra.NoArgs,
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -120,15 +157,20 @@ or storage classes (OpenShift) available in the source provider.`,
 			inventoryURL := globalConfig.GetInventoryURL()
 			inventoryInsecureSkipTLS := globalConfig.GetInventoryInsecureSkipTLS()
 
+			if capacity {
+				return inventory.ListStorageCapacityWithInsecure(ctx, globalConfig.GetKubeConfigFlags(), provider, namespace, inventoryURL, outputFormatFlag.GetValue(), query, minFreePercent, watch, inventoryInsecureSkipTLS)
+			}
 			return inventory.ListStorageWithInsecure(ctx, globalConfig.GetKubeConfigFlags(), provider, namespace, inventoryURL, outputFormatFlag.GetValue(), query, watch, inventoryInsecureSkipTLS)
 		},
 	}
 
 	cmd.Flags().StringVarP(&provider, "provider", "p", "", "Provider name")
 	_ = cmd.MarkFlagRequired("provider")
-	cmd.Flags().VarP(outputFormatFlag, "output", "o", flags.OutputFormatHelp)
+	cmd.Flags().VarP(outputFormatFlag, "output", "o", flags.OutputFormatTemplateHelp)
 	cmd.Flags().StringVarP(&query, "query", "q", "", flags.QueryHelp)
 	cmd.Flags().BoolVarP(&watch, "watch", "w", false, "Watch for changes")
+	cmd.Flags().BoolVar(&capacity, "capacity", false, "Show capacity, used and free space with a total row (vSphere and oVirt)")
+	cmd.Flags().Float64Var(&minFreePercent, "min-free-percent", inventory.DefaultMinFreePercent, "Free space percentage below which storage is marked LOW (with --capacity)")
 	help.MarkMCPHidden(cmd, "watch")
 
 	// Add completion for provider and output format flags
@@ -150,6 +192,11 @@ func NewInventoryVMCmd(kubeConfigFlags *genericclioptions.ConfigFlags, globalCon
 	var query string
 	var watch bool
 	var provider string
+	var concernsOnly bool
+	var raw bool
+	var overridesFile string
+	var changedSinceStr string
+	var fieldsStr string
 
 	cmd := &cobra.Command{
 		Use:   "vm",
@@ -160,11 +207,54 @@ Queries the MTV inventory service to list VMs available for migration.
 Use --query to filter results using TSL query syntax.
 
 Output format 'planvms' generates YAML suitable for use with 'create plan --vms @file'.
+Use --merge-overrides to merge a file of per-VM plan settings keyed by VM name (target
+names, instance types, LUKS secrets, ...) into the planvms output, so the assessment
+query and the manual tweaks stay separate, re-runnable inputs:
+
+  web-01:
+    targetName: web-prod-01
+    instanceType: u1.large
+  db-01:
+    luks:
+      name: db-01-luks
+
+Use --concerns-only for migration assessment: only VMs with concerns are listed, most
+severe first, with CRITICAL/WARNING/INFO counts and the top concern per VM, followed by
+a summary of concerns grouped by severity. The topConcern and concernLabels fields are
+also available to --query in this mode.
+
+Use --changed-since to list only the VMs that changed in the source environment since
+the last assessment, e.g. --changed-since 24h. The change time is the update time the
+provider reports (OpenStack, OpenShift, EC2, Azure). vSphere, oVirt, OVA and Hyper-V
+only report an inventory revision, so kubectl-mtv records the revision of each VM when
+listing them with --changed-since and a VM counts as changed when its revision differs
+from the one seen before; the first such listing records the baseline. The change time is shown in the CHANGED
+column and the lastChanged field.
+
+Power states and sizes are normalized across providers: the POWER column and the
+powerStateHuman field use On, Off, Suspended, Paused, Starting, Stopping and Error
+whether the provider reports poweredOn, up, ACTIVE or running, and memoryGiB and
+diskGiB hold sizes in GiB. Use --raw to show the power state reported by the provider;
+it is always available as powerStateRaw.
+
+Use --fields to keep only some fields of each VM, e.g. --fields name,id,powerStateHuman,concerns.
+The fields become the table columns and the only fields of the json, yaml and template
+output, which keeps large inventories readable. Nested fields use dots, e.g.
+Placement.AvailabilityZone. The fields are selected after --query, so the query can use
+any field. When only base fields (id, name, path, revision, selfLink) are requested
+without --query, the VM details are not fetched from the inventory at all.
+
+Use --output json-stream (or ndjson) for very large inventories: each VM is printed as a
+compact JSON object on its own line as soon as it is read from the inventory, instead
+of one array once every VM was read, so tens of thousands of VMs can be piped into jq
+in constant memory. --query filters and limits the stream on the way. Listings that
+need every VM first (ORDER BY, --concerns-only, --changed-since, EC2 providers) print
+the same lines after reading the whole inventory.
 
 Query Language (TSL):
   Use --query "where ..." to filter inventory results with TSL query syntax:
     --query "where name ~= 'prod-.*'"
-    --query "where powerState = 'poweredOn' and memoryMB > 4096"
+    --query "where powerStateHuman = 'On' and memoryGiB > 4"
     --query "where len(disks) > 1 and cpuCount <= 8"
     --query "where any(concerns[*].category = 'Critical')"
     --query "where name like '%web%' order by memoryMB desc limit 10"
@@ -185,18 +275,60 @@ Query Language (TSL):
   # Find VMs with critical migration concerns
   kubectl-mtv get inventory vms --provider vsphere-prod --query "where any(concerns[*].category = 'Critical')"
 
+  # Triage: list only VMs with concerns, with a CRITICAL/WARNING/INFO breakdown
+  kubectl-mtv get inventory vms --provider vsphere-prod --concerns-only
+
   # Filter VMs by name, CPU, and memory
   kubectl-mtv get inventory vms --provider vsphere-prod --query "where name ~= 'web-.*' and memoryMB > 4096"
 
   # List all VMs from a provider
   kubectl-mtv get inventory vms --provider vsphere-prod
 
+  # Show the power states as reported by the provider
+  kubectl-mtv get inventory vms --provider openstack-prod --raw
+
+  # Only the names, IDs, power states and concerns of the VMs
+  kubectl-mtv get inventory vms --provider vsphere-prod --fields name,id,powerStateHuman,concerns -o json
+
+  # Stream a large inventory into jq, one VM per line
+  kubectl-mtv get inventory vms --provider vsphere-prod -o json-stream --fields name,cpuCount | jq -r 'select(.cpuCount > 8) | .name'
+
+  # VMs that changed in the last day
+  kubectl-mtv get inventory vms --provider vsphere-prod --changed-since 24h
+
   # Export VMs for plan creation
   kubectl-mtv get inventory vms --provider vsphere-prod --query "where name ~= 'prod-.*'" --output planvms > vms.yaml
-  kubectl-mtv create plan --name my-migration --vms @vms.yaml`,
+  kubectl-mtv create plan --name my-migration --vms @vms.yaml
+
+  # Export VMs for plan creation with per-VM target names, instance types and LUKS secrets
+  kubectl-mtv get inventory vms --provider vsphere-prod --query "where name ~= 'prod-.*'" --output planvms --merge-overrides overrides.yaml > vms.yaml`,
 		Args:         cobra.NoArgs,
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			var overrides inventory.PlanVMOverrides
+			if overridesFile != "" {
+				if outputFormatFlag.GetValue() != "planvms" {
+					return fmt.Errorf("--merge-overrides requires --output planvms")
+				}
+				var err error
+				if overrides, err = inventory.LoadPlanVMOverrides(overridesFile); err != nil {
+					return err
+				}
+			}
+
+			fields := output.ParseFields(fieldsStr)
+			if len(fields) > 0 && outputFormatFlag.GetValue() == "planvms" {
+				return fmt.Errorf("--fields cannot be used with --output planvms")
+			}
+
+			var changedSince time.Time
+			if changedSinceStr != "" {
+				var err error
+				if changedSince, err = inventory.ParseChangedSince(changedSinceStr, time.Now()); err != nil {
+					return err
+				}
+			}
+
 			ctx := cmd.Context()
 			if !watch {
 				var cancel context.CancelFunc
@@ -210,16 +342,31 @@ Query Language (TSL):
 			logOutputFormat(outputFormatFlag.GetValue())
 
 			// Get inventory URL and insecure skip TLS from global config (auto-discovers if needed)
-			inventoryURL := globalConfig.GetInventoryURL()
-			inventoryInsecureSkipTLS := globalConfig.GetInventoryInsecureSkipTLS()
-
-			return inventory.ListVMsWithInsecure(ctx, globalConfig.GetKubeConfigFlags(), provider, namespace, inventoryURL, outputFormatFlag.GetValue(), query, watch, inventoryInsecureSkipTLS)
+			return inventory.ListVMsWithInsecure(ctx, globalConfig.GetKubeConfigFlags(), inventory.ListVMsOptions{
+				ProviderName:    provider,
+				Namespace:       namespace,
+				InventoryURL:    globalConfig.GetInventoryURL(),
+				InsecureSkipTLS: globalConfig.GetInventoryInsecureSkipTLS(),
+				OutputFormat:    outputFormatFlag.GetValue(),
+				Query:           query,
+				Watch:           watch,
+				ConcernsOnly:    concernsOnly,
+				Raw:             raw,
+				Overrides:       overrides,
+				ChangedSince:    changedSince,
+				Fields:          fields,
+			})
 		},
 	}
 
 	cmd.Flags().StringVarP(&provider, "provider", "p", "", "Provider name")
 	_ = cmd.MarkFlagRequired("provider")
-	cmd.Flags().VarP(outputFormatFlag, "output", "o", "Output format (table, json, yaml, markdown, planvms)")
+	cmd.Flags().VarP(outputFormatFlag, "output", "o", "Output format (table, json, yaml, markdown, planvms, json-stream, jsonpath=TEMPLATE, go-template=TEMPLATE)")
+	cmd.Flags().BoolVar(&concernsOnly, "concerns-only", false, "List only VMs with migration concerns, ordered by severity, with a summary grouped by concern")
+	cmd.Flags().BoolVar(&raw, "raw", false, "Show power states as reported by the provider (e.g. poweredOn, ACTIVE) instead of the canonical On/Off vocabulary")
+	cmd.Flags().StringVar(&changedSinceStr, "changed-since", "", "List only VMs changed since "+inventory.ChangedSinceHelp)
+	cmd.Flags().StringVar(&overridesFile, "merge-overrides", "", "YAML/JSON file of per-VM plan settings keyed by VM name (e.g. targetName, instanceType, luks) to merge into the planvms output")
+	cmd.Flags().StringVar(&fieldsStr, "fields", "", "Comma-separated VM fields to show, e.g. name,id,powerStateHuman,concerns (table columns and json/yaml/template fields)")
 	cmd.Flags().StringVarP(&query, "query", "q", "", flags.QueryHelp)
 	cmd.Flags().BoolVarP(&watch, "watch", "w", false, "Watch for changes")
 	help.MarkMCPHidden(cmd, "watch")
@@ -228,6 +375,9 @@ Query Language (TSL):
 	if err := cmd.RegisterFlagCompletionFunc("provider", completion.ProviderNameCompletion(kubeConfigFlags)); err != nil {
 		panic(err)
 	}
+	if err := cmd.RegisterFlagCompletionFunc("query", completion.QueryCompletion(kubeConfigFlags, help.SchemaResourceVM)); err != nil {
+		panic(err)
+	}
 	// Custom completion for inventory VM output format that includes planvms
 	if err := cmd.RegisterFlagCompletionFunc("output", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		return outputFormatFlag.GetValidValues(), cobra.ShellCompDirectiveNoFileComp
@@ -28,7 +28,7 @@ type ec2CommandConfig struct {
 
 // newEC2InventoryCmd creates a new EC2 inventory command with the given configuration
 func newEC2InventoryCmd(kubeConfigFlags *genericclioptions.ConfigFlags, globalConfig GlobalConfigGetter, cfg ec2CommandConfig) *cobra.Command {
-	outputFormatFlag := flags.NewOutputFormatTypeFlag()
+	outputFormatFlag := flags.NewOutputFormatTypeFlag().WithTemplates()
 	var query string
 	var watch bool
 	var provider string
@@ -61,7 +61,7 @@ func newEC2InventoryCmd(kubeConfigFlags *genericclioptions.ConfigFlags, globalCo
 	}
 	cmd.Flags().StringVarP(&provider, "provider", "p", "", "Provider name")
 	_ = cmd.MarkFlagRequired("provider")
-	cmd.Flags().VarP(outputFormatFlag, "output", "o", flags.OutputFormatHelp)
+	cmd.Flags().VarP(outputFormatFlag, "output", "o", flags.OutputFormatTemplateHelp)
 	cmd.Flags().StringVarP(&query, "query", "q", "", flags.QueryHelp)
 	cmd.Flags().BoolVarP(&watch, "watch", "w", false, "Watch for changes")
 	help.MarkMCPHidden(cmd, "watch")
@@ -134,3 +134,29 @@ func NewInventoryEC2SnapshotCmd(kubeConfigFlags *genericclioptions.ConfigFlags,
 		listFunc:   inventory.ListEC2SnapshotsWithInsecure,
 	})
 }
+
+// NewInventoryEC2ImageCmd creates the get inventory image command for EC2 AMIs
+func NewInventoryEC2ImageCmd(kubeConfigFlags *genericclioptions.ConfigFlags, globalConfig GlobalConfigGetter) *cobra.Command {
+	return newEC2InventoryCmd(kubeConfigFlags, globalConfig, ec2CommandConfig{
+		use:   "ec2-image",
+		short: "Get EC2 AMIs used by instances from a provider",
+		long: `Get the EC2 AMIs (images) used by instances in an AWS provider's inventory.
+
+The list is derived from the instance inventory and shows each AMI with the instances launched from it.`,
+		logMessage: "Getting EC2 images from provider",
+		listFunc:   inventory.ListEC2ImagesWithInsecure,
+	})
+}
+
+// NewInventoryEC2SecurityGroupCmd creates the get inventory security-group command for EC2
+func NewInventoryEC2SecurityGroupCmd(kubeConfigFlags *genericclioptions.ConfigFlags, globalConfig GlobalConfigGetter) *cobra.Command {
+	return newEC2InventoryCmd(kubeConfigFlags, globalConfig, ec2CommandConfig{
+		use:   "ec2-security-group",
+		short: "Get EC2 security groups used by instances from a provider",
+		long: `Get the EC2 security groups attached to instances in an AWS provider's inventory.
+
+The list is derived from the instance inventory and shows each security group with the instances it is attached to.`,
+		logMessage: "Getting EC2 security groups from provider",
+		listFunc:   inventory.ListEC2SecurityGroupsWithInsecure,
+	})
+}
@@ -16,10 +16,11 @@ import (
 
 // NewInventoryHostCmd creates the get inventory host command
 func NewInventoryHostCmd(kubeConfigFlags *genericclioptions.ConfigFlags, globalConfig GlobalConfigGetter) *cobra.Command {
-	outputFormatFlag := flags.NewOutputFormatTypeFlag()
+	outputFormatFlag := flags.NewOutputFormatTypeFlag().WithTemplates()
 	var query string
 	var watch bool
 	var provider string
+	var withVMs bool
 
 	cmd := &cobra.Command{
 		Use:   "host",
@@ -27,7 +28,13 @@ func NewInventoryHostCmd(kubeConfigFlags *genericclioptions.ConfigFlags, globalC
 		Long: `Get hypervisor hosts from a provider's inventory.
 
 Lists ESXi hosts (vSphere) or hypervisor hosts (oVirt) from the source provider.
-Host information is useful for planning migrations and understanding the source environment.`,
+Host information is useful for planning migrations and understanding the source environment.
+
+Use --with-vms to list the VMs running on each host under it, largest memory first, with
+per-host VM counts, allocated vCPUs and memory, and the ratio of allocated vCPUs to host
+cores. This helps plan the order in which hosts are evacuated. In JSON and YAML output
+each host has a "vms" list; the totals (vmCount, poweredOnCount, allocatedCpus,
+allocatedMemoryGiB, cpuRatio) can be used in --query.`,
 		Example: `  # Filter hosts by cluster
   kubectl-mtv get inventory hosts --provider vsphere-prod --query "where cluster = 'production'"
 
@@ -35,7 +42,10 @@ Host information is useful for planning migrations and understanding the source
   kubectl-mtv get inventory hosts --provider vsphere-prod
 
   # Output as JSON
-  kubectl-mtv get inventory hosts --provider vsphere-prod --output json`,
+  kubectl-mtv get inventory hosts --provider vsphere-prod --output json
+
+  # Show the VMs of each host, busiest hosts first
+  kubectl-mtv get inventory hosts --provider vsphere-prod --with-vms --query "order by allocatedMemoryGiB desc"`,
 		Args:         cobra.NoArgs,
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -55,13 +65,14 @@ Host information is useful for planning migrations and understanding the source
 			inventoryURL := globalConfig.GetInventoryURL()
 			inventoryInsecureSkipTLS := globalConfig.GetInventoryInsecureSkipTLS()
 
-			return inventory.ListHostsWithInsecure(ctx, globalConfig.GetKubeConfigFlags(), provider, namespace, inventoryURL, outputFormatFlag.GetValue(), query, watch, inventoryInsecureSkipTLS)
+			return inventory.ListHostsWithInsecure(ctx, globalConfig.GetKubeConfigFlags(), provider, namespace, inventoryURL, outputFormatFlag.GetValue(), query, withVMs, watch, inventoryInsecureSkipTLS)
 		},
 	}
 	cmd.Flags().StringVarP(&provider, "provider", "p", "", "Provider name")
 	_ = cmd.MarkFlagRequired("provider")
-	cmd.Flags().VarP(outputFormatFlag, "output", "o", flags.OutputFormatHelp)
+	cmd.Flags().VarP(outputFormatFlag, "output", "o", flags.OutputFormatTemplateHelp)
 	cmd.Flags().StringVarP(&query, "query", "q", "", flags.QueryHelp)
+	cmd.Flags().BoolVar(&withVMs, "with-vms", false, "Nest the VMs of each host under it with per-host VM counts and allocated capacity")
 	cmd.Flags().BoolVarP(&watch, "watch", "w", false, "Watch for changes")
 	help.MarkMCPHidden(cmd, "watch")
 
@@ -80,7 +91,7 @@ Host information is useful for planning migrations and understanding the source
 
 // NewInventoryDataCenterCmd creates the get inventory datacenter command
 func NewInventoryDataCenterCmd(kubeConfigFlags *genericclioptions.ConfigFlags, globalConfig GlobalConfigGetter) *cobra.Command {
-	outputFormatFlag := flags.NewOutputFormatTypeFlag()
+	outputFormatFlag := flags.NewOutputFormatTypeFlag().WithTemplates()
 	var query string
 	var watch bool
 	var provider string
@@ -124,7 +135,7 @@ organizational units that contain clusters, hosts, and VMs.`,
 	}
 	cmd.Flags().StringVarP(&provider, "provider", "p", "", "Provider name")
 	_ = cmd.MarkFlagRequired("provider")
-	cmd.Flags().VarP(outputFormatFlag, "output", "o", flags.OutputFormatHelp)
+	cmd.Flags().VarP(outputFormatFlag, "output", "o", flags.OutputFormatTemplateHelp)
 	cmd.Flags().StringVarP(&query, "query", "q", "", flags.QueryHelp)
 	cmd.Flags().BoolVarP(&watch, "watch", "w", false, "Watch for changes")
 	help.MarkMCPHidden(cmd, "watch")
@@ -144,7 +155,7 @@ organizational units that contain clusters, hosts, and VMs.`,
 
 // NewInventoryClusterCmd creates the get inventory cluster command
 func NewInventoryClusterCmd(kubeConfigFlags *genericclioptions.ConfigFlags, globalConfig GlobalConfigGetter) *cobra.Command {
-	outputFormatFlag := flags.NewOutputFormatTypeFlag()
+	outputFormatFlag := flags.NewOutputFormatTypeFlag().WithTemplates()
 	var query string
 	var watch bool
 	var provider string
@@ -188,7 +199,7 @@ together and define resource pools for VMs.`,
 	}
 	cmd.Flags().StringVarP(&provider, "provider", "p", "", "Provider name")
 	_ = cmd.MarkFlagRequired("provider")
-	cmd.Flags().VarP(outputFormatFlag, "output", "o", flags.OutputFormatHelp)
+	cmd.Flags().VarP(outputFormatFlag, "output", "o", flags.OutputFormatTemplateHelp)
 	cmd.Flags().StringVarP(&query, "query", "q", "", flags.QueryHelp)
 	cmd.Flags().BoolVarP(&watch, "watch", "w", false, "Watch for changes")
 	help.MarkMCPHidden(cmd, "watch")
@@ -208,7 +219,7 @@ together and define resource pools for VMs.`,
 
 // NewInventoryDiskCmd creates the get inventory disk command
 func NewInventoryDiskCmd(kubeConfigFlags *genericclioptions.ConfigFlags, globalConfig GlobalConfigGetter) *cobra.Command {
-	outputFormatFlag := flags.NewOutputFormatTypeFlag()
+	outputFormatFlag := flags.NewOutputFormatTypeFlag().WithTemplates()
 	var query string
 	var watch bool
 	var provider string
@@ -252,7 +263,7 @@ size, storage location, and attachment to VMs.`,
 	}
 	cmd.Flags().StringVarP(&provider, "provider", "p", "", "Provider name")
 	_ = cmd.MarkFlagRequired("provider")
-	cmd.Flags().VarP(outputFormatFlag, "output", "o", flags.OutputFormatHelp)
+	cmd.Flags().VarP(outputFormatFlag, "output", "o", flags.OutputFormatTemplateHelp)
 	cmd.Flags().StringVarP(&query, "query", "q", "", flags.QueryHelp)
 	cmd.Flags().BoolVarP(&watch, "watch", "w", false, "Watch for changes")
 	help.MarkMCPHidden(cmd, "watch")
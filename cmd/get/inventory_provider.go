@@ -16,7 +16,7 @@ import (
 
 // NewInventoryProviderCmd creates the get inventory provider command
 func NewInventoryProviderCmd(kubeConfigFlags *genericclioptions.ConfigFlags, globalConfig GlobalConfigGetter) *cobra.Command {
-	outputFormatFlag := flags.NewOutputFormatTypeFlag()
+	outputFormatFlag := flags.NewOutputFormatTypeFlag().WithTemplates()
 	var query string
 	var watch bool
 	var providerName string
@@ -56,7 +56,7 @@ func NewInventoryProviderCmd(kubeConfigFlags *genericclioptions.ConfigFlags, glo
 		},
 	}
 	cmd.Flags().StringVarP(&providerName, "name", "M", "", "Provider name")
-	cmd.Flags().VarP(outputFormatFlag, "output", "o", flags.OutputFormatHelp)
+	cmd.Flags().VarP(outputFormatFlag, "output", "o", flags.OutputFormatTemplateHelp)
 	cmd.Flags().StringVarP(&query, "query", "q", "", flags.QueryHelp)
 	cmd.Flags().BoolVarP(&watch, "watch", "w", false, "Watch for changes")
 	help.MarkMCPHidden(cmd, "watch")
@@ -16,15 +16,32 @@ import (
 
 // NewInventoryDatastoreCmd creates the get inventory datastore command
 func NewInventoryDatastoreCmd(kubeConfigFlags *genericclioptions.ConfigFlags, globalConfig GlobalConfigGetter) *cobra.Command {
-	outputFormatFlag := flags.NewOutputFormatTypeFlag()
+	outputFormatFlag := flags.NewOutputFormatTypeFlag().WithTemplates()
 	var query string
 	var watch bool
 	var provider string
+	var withVMs bool
+	var largest int
 
 	cmd := &cobra.Command{
-		Use:          "datastore",
-		Short:        "Get datastores from a provider",
-		Long:         `Get datastores from a vSphere provider's inventory.`,
+		Use:   "datastore",
+		Short: "Get datastores from a provider",
+		Long: `Get datastores from a vSphere provider's inventory.
+
+Use --vms to list under each datastore the VMs with disks on it, largest first, with
+the size of their disks on the datastore, and per datastore the VM count and the disk
+size of all its VMs. --largest N keeps the N largest VMs of each datastore (and implies
+--vms), with the share of the datastore's VM disks they hold, to spot the storage
+backends that would be saturated by migrating their largest VMs together, and to spread
+the large VMs of a datastore across plans.`,
+		Example: `  # List datastores
+  kubectl-mtv get inventory datastores --provider vsphere-prod
+
+  # List the VMs on each datastore
+  kubectl-mtv get inventory datastores --provider vsphere-prod --vms
+
+  # Show the 3 largest VMs of each datastore, busiest datastores first
+  kubectl-mtv get inventory datastores --provider vsphere-prod --largest 3 --query "order by vmDiskBytes desc"`,
 		Args:         cobra.NoArgs,
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -44,12 +61,15 @@ func NewInventoryDatastoreCmd(kubeConfigFlags *genericclioptions.ConfigFlags, gl
 			inventoryURL := globalConfig.GetInventoryURL()
 			inventoryInsecureSkipTLS := globalConfig.GetInventoryInsecureSkipTLS()
 
-			return inventory.ListDatastoresWithInsecure(ctx, globalConfig.GetKubeConfigFlags(), provider, namespace, inventoryURL, outputFormatFlag.GetValue(), query, watch, inventoryInsecureSkipTLS)
+			withVMs = withVMs || largest > 0
+			return inventory.ListDatastoresWithInsecure(ctx, globalConfig.GetKubeConfigFlags(), provider, namespace, inventoryURL, outputFormatFlag.GetValue(), query, withVMs, largest, watch, inventoryInsecureSkipTLS)
 		},
 	}
 	cmd.Flags().StringVarP(&provider, "provider", "p", "", "Provider name")
+	cmd.Flags().BoolVar(&withVMs, "vms", false, "List the VMs with disks on each datastore, largest first")
+	cmd.Flags().IntVar(&largest, "largest", 0, "Keep the N largest VMs of each datastore (implies --vms)")
 	_ = cmd.MarkFlagRequired("provider")
-	cmd.Flags().VarP(outputFormatFlag, "output", "o", flags.OutputFormatHelp)
+	cmd.Flags().VarP(outputFormatFlag, "output", "o", flags.OutputFormatTemplateHelp)
 	cmd.Flags().StringVarP(&query, "query", "q", "", flags.QueryHelp)
 	cmd.Flags().BoolVarP(&watch, "watch", "w", false, "Watch for changes")
 	help.MarkMCPHidden(cmd, "watch")
@@ -69,7 +89,7 @@ func NewInventoryDatastoreCmd(kubeConfigFlags *genericclioptions.ConfigFlags, gl
 
 // NewInventoryResourcePoolCmd creates the get inventory resource-pool command
 func NewInventoryResourcePoolCmd(kubeConfigFlags *genericclioptions.ConfigFlags, globalConfig GlobalConfigGetter) *cobra.Command {
-	outputFormatFlag := flags.NewOutputFormatTypeFlag()
+	outputFormatFlag := flags.NewOutputFormatTypeFlag().WithTemplates()
 	var query string
 	var watch bool
 	var provider string
@@ -102,7 +122,7 @@ func NewInventoryResourcePoolCmd(kubeConfigFlags *genericclioptions.ConfigFlags,
 	}
 	cmd.Flags().StringVarP(&provider, "provider", "p", "", "Provider name")
 	_ = cmd.MarkFlagRequired("provider")
-	cmd.Flags().VarP(outputFormatFlag, "output", "o", flags.OutputFormatHelp)
+	cmd.Flags().VarP(outputFormatFlag, "output", "o", flags.OutputFormatTemplateHelp)
 	cmd.Flags().StringVarP(&query, "query", "q", "", flags.QueryHelp)
 	cmd.Flags().BoolVarP(&watch, "watch", "w", false, "Watch for changes")
 	help.MarkMCPHidden(cmd, "watch")
@@ -122,7 +142,7 @@ func NewInventoryResourcePoolCmd(kubeConfigFlags *genericclioptions.ConfigFlags,
 
 // NewInventoryFolderCmd creates the get inventory folder command
 func NewInventoryFolderCmd(kubeConfigFlags *genericclioptions.ConfigFlags, globalConfig GlobalConfigGetter) *cobra.Command {
-	outputFormatFlag := flags.NewOutputFormatTypeFlag()
+	outputFormatFlag := flags.NewOutputFormatTypeFlag().WithTemplates()
 	var query string
 	var watch bool
 	var provider string
@@ -155,7 +175,7 @@ func NewInventoryFolderCmd(kubeConfigFlags *genericclioptions.ConfigFlags, globa
 	}
 	cmd.Flags().StringVarP(&provider, "provider", "p", "", "Provider name")
 	_ = cmd.MarkFlagRequired("provider")
-	cmd.Flags().VarP(outputFormatFlag, "output", "o", flags.OutputFormatHelp)
+	cmd.Flags().VarP(outputFormatFlag, "output", "o", flags.OutputFormatTemplateHelp)
 	cmd.Flags().StringVarP(&query, "query", "q", "", flags.QueryHelp)
 	cmd.Flags().BoolVarP(&watch, "watch", "w", false, "Watch for changes")
 	help.MarkMCPHidden(cmd, "watch")
@@ -15,9 +15,10 @@ import (
 
 // NewMappingCmd creates the get mapping command with subcommands
 func NewMappingCmd(globalConfig GlobalConfigGetter) *cobra.Command {
-	outputFormatFlag := flags.NewOutputFormatTypeFlag()
+	outputFormatFlag := flags.NewOutputFormatTypeFlag().WithTemplates()
 	var watchFlag bool
 	var query string
+	var selector string
 	var mappingName string
 
 	cmd := &cobra.Command{
@@ -31,6 +32,9 @@ mapping type.`,
 		Example: `  # List all mappings (both network and storage)
   kubectl-mtv get mappings
 
+  # List the mappings of an application
+  kubectl-mtv get mappings -l app=payments
+
   # Get a specific mapping by name (searches both types)
   kubectl-mtv get mapping --name my-mapping --output yaml
 
@@ -48,6 +52,9 @@ mapping type.`,
 			if err := flags.ResolveNameArg(&mappingName, args); err != nil {
 				return err
 			}
+			if err := flags.ValidateSelector(selector, mappingName); err != nil {
+				return err
+			}
 
 			ctx := cmd.Context()
 			if !watchFlag {
@@ -66,13 +73,14 @@ mapping type.`,
 			}
 			logOutputFormat(outputFormatFlag.GetValue())
 
-			return mapping.List(ctx, globalConfig.GetKubeConfigFlags(), "all", namespace, watchFlag, outputFormatFlag.GetValue(), mappingName, globalConfig.GetUseUTC(), query)
+			return mapping.List(ctx, globalConfig.GetKubeConfigFlags(), "all", namespace, watchFlag, outputFormatFlag.GetValue(), mappingName, globalConfig.GetUseUTC(), query, selector)
 		},
 	}
 
 	cmd.Flags().StringVarP(&mappingName, "name", "M", "", "Mapping name")
-	cmd.Flags().VarP(outputFormatFlag, "output", "o", flags.OutputFormatHelp)
+	cmd.Flags().VarP(outputFormatFlag, "output", "o", flags.OutputFormatTemplateHelp)
 	cmd.Flags().StringVarP(&query, "query", "q", "", flags.QueryHelp)
+	cmd.Flags().StringVarP(&selector, "selector", "l", "", flags.SelectorHelp)
 	cmd.Flags().BoolVarP(&watchFlag, "watch", "w", false, "Watch for changes")
 	help.MarkMCPHidden(cmd, "watch")
 
@@ -91,9 +99,10 @@ mapping type.`,
 
 // newGetNetworkMappingCmd creates the get network mapping subcommand
 func newGetNetworkMappingCmd(globalConfig GlobalConfigGetter) *cobra.Command {
-	outputFormatFlag := flags.NewOutputFormatTypeFlag()
+	outputFormatFlag := flags.NewOutputFormatTypeFlag().WithTemplates()
 	var watch bool
 	var query string
+	var selector string
 	var mappingName string
 
 	cmd := &cobra.Command{
@@ -117,6 +126,9 @@ definitions (NADs) or pod networking.`,
 			if err := flags.ResolveNameArg(&mappingName, args); err != nil {
 				return err
 			}
+			if err := flags.ValidateSelector(selector, mappingName); err != nil {
+				return err
+			}
 
 			ctx := cmd.Context()
 			if !watch {
@@ -135,17 +147,19 @@ definitions (NADs) or pod networking.`,
 			}
 			logOutputFormat(outputFormatFlag.GetValue())
 
-			return mapping.List(ctx, globalConfig.GetKubeConfigFlags(), "network", namespace, watch, outputFormatFlag.GetValue(), mappingName, globalConfig.GetUseUTC(), query)
+			return mapping.List(ctx, globalConfig.GetKubeConfigFlags(), "network", namespace, watch, outputFormatFlag.GetValue(), mappingName, globalConfig.GetUseUTC(), query, selector)
 		},
 	}
 
 	cmd.Flags().StringVarP(&mappingName, "name", "M", "", "Mapping name")
-	cmd.Flags().VarP(outputFormatFlag, "output", "o", flags.OutputFormatHelp)
+	cmd.Flags().VarP(outputFormatFlag, "output", "o", flags.OutputFormatTemplateHelp)
 	cmd.Flags().StringVarP(&query, "query", "q", "", flags.QueryHelp)
+	cmd.Flags().StringVarP(&selector, "selector", "l", "", flags.SelectorHelp)
 	cmd.Flags().BoolVarP(&watch, "watch", "w", false, "Watch for changes")
 	help.MarkMCPHidden(cmd, "watch")
 
-	// Add completion for name and output format flags
+	// Add completion for the name argument, name and output format flags
+	cmd.ValidArgsFunction = completion.NameArgCompletion(completion.MappingNameCompletion(globalConfig.GetKubeConfigFlags(), "network"))
 	if err := cmd.RegisterFlagCompletionFunc("name", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		return completion.MappingNameCompletion(globalConfig.GetKubeConfigFlags(), "network")(cmd, args, toComplete)
 	}); err != nil {
@@ -162,9 +176,10 @@ definitions (NADs) or pod networking.`,
 
 // newGetStorageMappingCmd creates the get storage mapping subcommand
 func newGetStorageMappingCmd(globalConfig GlobalConfigGetter) *cobra.Command {
-	outputFormatFlag := flags.NewOutputFormatTypeFlag()
+	outputFormatFlag := flags.NewOutputFormatTypeFlag().WithTemplates()
 	var watch bool
 	var query string
+	var selector string
 	var mappingName string
 
 	cmd := &cobra.Command{
@@ -188,6 +203,9 @@ storage classes with optional volume mode and access mode settings.`,
 			if err := flags.ResolveNameArg(&mappingName, args); err != nil {
 				return err
 			}
+			if err := flags.ValidateSelector(selector, mappingName); err != nil {
+				return err
+			}
 
 			ctx := cmd.Context()
 			if !watch {
@@ -206,17 +224,19 @@ storage classes with optional volume mode and access mode settings.`,
 			}
 			logOutputFormat(outputFormatFlag.GetValue())
 
-			return mapping.List(ctx, globalConfig.GetKubeConfigFlags(), "storage", namespace, watch, outputFormatFlag.GetValue(), mappingName, globalConfig.GetUseUTC(), query)
+			return mapping.List(ctx, globalConfig.GetKubeConfigFlags(), "storage", namespace, watch, outputFormatFlag.GetValue(), mappingName, globalConfig.GetUseUTC(), query, selector)
 		},
 	}
 
 	cmd.Flags().StringVarP(&mappingName, "name", "M", "", "Mapping name")
-	cmd.Flags().VarP(outputFormatFlag, "output", "o", flags.OutputFormatHelp)
+	cmd.Flags().VarP(outputFormatFlag, "output", "o", flags.OutputFormatTemplateHelp)
 	cmd.Flags().StringVarP(&query, "query", "q", "", flags.QueryHelp)
+	cmd.Flags().StringVarP(&selector, "selector", "l", "", flags.SelectorHelp)
 	cmd.Flags().BoolVarP(&watch, "watch", "w", false, "Watch for changes")
 	help.MarkMCPHidden(cmd, "watch")
 
-	// Add completion for name and output format flags
+	// Add completion for the name argument, name and output format flags
+	cmd.ValidArgsFunction = completion.NameArgCompletion(completion.MappingNameCompletion(globalConfig.GetKubeConfigFlags(), "storage"))
 	if err := cmd.RegisterFlagCompletionFunc("name", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		return completion.MappingNameCompletion(globalConfig.GetKubeConfigFlags(), "storage")(cmd, args, toComplete)
 	}); err != nil {
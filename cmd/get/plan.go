@@ -13,16 +13,20 @@ import (
 	"github.com/yaacov/kubectl-mtv/pkg/util/client"
 	"github.com/yaacov/kubectl-mtv/pkg/util/completion"
 	"github.com/yaacov/kubectl-mtv/pkg/util/flags"
+	"github.com/yaacov/kubectl-mtv/pkg/util/output"
 )
 
 // NewPlanCmd creates the get plan command
 func NewPlanCmd(kubeConfigFlags *genericclioptions.ConfigFlags, globalConfig GlobalConfigGetter) *cobra.Command {
-	outputFormatFlag := flags.NewOutputFormatTypeFlag()
+	outputFormatFlag := flags.NewOutputFormatTypeFlag().WithTemplates()
 	var watch bool
 	var vms bool
 	var disk bool
 	var vmsTable bool
+	var diskMap bool
+	var summary bool
 	var query string
+	var selector string
 
 	var planName string
 	cmd := &cobra.Command{
@@ -35,14 +39,28 @@ Use --vms to see the migration status of individual VMs within a plan.
 Use --disk to see the disk transfer status with individual disk details.
 Use both --vms and --disk together to see VMs with their disk details.
 Use --vms-table to see all VMs across plans in a flat table with source/target inventory details.
+Use --disk-map to map each source disk (datastore, path, size) of the migrated VMs to the
+PVC, DataVolume and storage class created for it, e.g. for storage reconciliation after cutover.
 Use --query with --vms-table to filter, sort, or select columns using TSL syntax.
-Use --query without --vms-table to filter the plans list using TSL syntax.`,
+Use --query without --vms-table to filter the plans list using TSL syntax.
+Use --query with --disk-map to filter the disk map, e.g. by vm, datastore or storageClass.
+Use --output markdown with a plan NAME for a document to paste into change tickets and wikis,
+with the plan settings, its status and the status of each VM (only the VMs with --vms).
+Use --summary for a fleet overview of all the plans: plans by status, VMs migrated, failed
+and pending, data transferred, and the most common VM failure reasons. It respects
+--all-namespaces and --selector.`,
 		Example: `  # List all plans in current namespace
   kubectl-mtv get plans
 
   # List plans across all namespaces
   kubectl-mtv get plans --all-namespaces
 
+  # List the plans labeled for a wave and team
+  kubectl-mtv get plans -l wave=3,team=payments
+
+  # Fleet overview of the plans of a wave across all namespaces
+  kubectl-mtv get plans --summary -A -l wave=3
+
   # Get a specific plan in JSON format
   kubectl-mtv get plan --name my-migration --output json
 
@@ -55,6 +73,9 @@ Use --query without --vms-table to filter the plans list using TSL syntax.`,
   # Get disk transfer status within a plan
   kubectl-mtv get plan --name my-migration --disk
 
+  # Plan settings and per-VM status as markdown for a change ticket
+  kubectl-mtv get plan --name my-migration --output markdown
+
   # Get both VM and disk transfer status
   kubectl-mtv get plan --name my-migration --vms --disk
 
@@ -68,13 +89,22 @@ Use --query without --vms-table to filter the plans list using TSL syntax.`,
   kubectl-mtv get plans --vms-table --query "where planStatus = 'Failed'"
 
   # Export VMs table as JSON
-  kubectl-mtv get plans --vms-table --output json`,
+  kubectl-mtv get plans --vms-table --output json
+
+  # Map source disks to the created PVCs and DataVolumes
+  kubectl-mtv get plan --name my-migration --disk-map
+
+  # Export the disk map of one VM as JSON
+  kubectl-mtv get plan --name my-migration --disk-map --query "where vm = 'web-01'" --output json`,
 		Args:         cobra.MaximumNArgs(1),
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if err := flags.ResolveNameArg(&planName, args); err != nil {
 				return err
 			}
+			if err := flags.ValidateSelector(selector, planName); err != nil {
+				return err
+			}
 
 			ctx := cmd.Context()
 			if !watch {
@@ -87,6 +117,17 @@ Use --query without --vms-table to filter the plans list using TSL syntax.`,
 			allNamespaces := globalConfig.GetAllNamespaces()
 			namespace := client.ResolveNamespaceWithAllFlag(kubeConfigFlags, allNamespaces)
 
+			// If --summary flag is used, aggregate the status of all the plans
+			if summary {
+				if planName != "" || vms || disk || vmsTable || diskMap || query != "" {
+					return fmt.Errorf("--summary cannot be used with a plan NAME, --vms, --disk, --vms-table, --disk-map or --query")
+				}
+				logNamespaceOperation("Getting plans summary", namespace, allNamespaces)
+				logOutputFormat(outputFormatFlag.GetValue())
+
+				return plan.PrintSummary(ctx, kubeConfigFlags, namespace, selector, watch, outputFormatFlag.GetValue())
+			}
+
 			// If --vms-table flag is used, show flat VM table with inventory details
 			if vmsTable {
 				logNamespaceOperation("Getting VMs table", namespace, allNamespaces)
@@ -95,7 +136,24 @@ Use --query without --vms-table to filter the plans list using TSL syntax.`,
 				inventoryURL := globalConfig.GetInventoryURL()
 				inventoryInsecureSkipTLS := globalConfig.GetInventoryInsecureSkipTLS()
 
-				return plan.ListVMsTable(ctx, kubeConfigFlags, planName, namespace, inventoryURL, inventoryInsecureSkipTLS, outputFormatFlag.GetValue(), query, watch)
+				return plan.ListVMsTable(ctx, kubeConfigFlags, planName, namespace, inventoryURL, inventoryInsecureSkipTLS, outputFormatFlag.GetValue(), query, selector, watch)
+			}
+
+			// If --disk-map flag is used, map source disks to the created PVCs
+			if diskMap {
+				if planName == "" {
+					return fmt.Errorf("plan NAME is required when using --disk-map flag")
+				}
+				logNamespaceOperation("Getting plan disk map", namespace, allNamespaces)
+				logOutputFormat(outputFormatFlag.GetValue())
+
+				return plan.ListDiskMap(ctx, kubeConfigFlags, planName, namespace, outputFormatFlag.GetValue(), query, watch)
+			}
+
+			// A single plan, or its VMs, as a markdown document for change tickets and wikis
+			if planName != "" && !disk && output.NormalizeFormat(outputFormatFlag.GetValue()) == "markdown" {
+				logNamespaceOperation("Getting plan markdown document", namespace, allNamespaces)
+				return plan.ListMarkdown(ctx, kubeConfigFlags, planName, namespace, vms, globalConfig.GetUseUTC(), watch)
 			}
 
 			// If both --vms and --disk flags are used, show combined view
@@ -144,20 +202,24 @@ Use --query without --vms-table to filter the plans list using TSL syntax.`,
 			}
 			logOutputFormat(outputFormatFlag.GetValue())
 
-			return plan.List(ctx, kubeConfigFlags, namespace, watch, outputFormatFlag.GetValue(), planName, globalConfig.GetUseUTC(), query)
+			return plan.List(ctx, kubeConfigFlags, namespace, watch, outputFormatFlag.GetValue(), planName, globalConfig.GetUseUTC(), query, selector)
 		},
 	}
 
 	cmd.Flags().StringVarP(&planName, "name", "M", "", "Plan name")
-	cmd.Flags().VarP(outputFormatFlag, "output", "o", flags.OutputFormatHelp)
+	cmd.Flags().VarP(outputFormatFlag, "output", "o", flags.OutputFormatTemplateHelp)
 	cmd.Flags().BoolVarP(&watch, "watch", "w", false, "Watch for changes")
 	cmd.Flags().BoolVar(&vms, "vms", false, "Get VMs status in the migration plan (requires plan NAME)")
 	cmd.Flags().BoolVar(&disk, "disk", false, "Get disk transfer status in the migration plan (requires plan NAME)")
 	cmd.Flags().BoolVar(&vmsTable, "vms-table", false, "Show all VMs across plans in a flat table with source/target inventory details")
+	cmd.Flags().BoolVar(&diskMap, "disk-map", false, "Map the source disks of the migrated VMs to the created PVCs, DataVolumes and storage classes (requires plan NAME)")
+	cmd.Flags().BoolVar(&summary, "summary", false, "Show a fleet overview of the plans: plans by status, VMs migrated and pending, data transferred and failure reasons")
 	cmd.Flags().StringVarP(&query, "query", "q", "", flags.QueryHelp)
+	cmd.Flags().StringVarP(&selector, "selector", "l", "", flags.SelectorHelp)
 	help.MarkMCPHidden(cmd, "watch", "vms-table")
 
-	// Add completion for name and output format flags
+	// Add completion for the name argument, name and output format flags
+	cmd.ValidArgsFunction = completion.NameArgCompletion(completion.PlanNameCompletion(kubeConfigFlags))
 	if err := cmd.RegisterFlagCompletionFunc("name", completion.PlanNameCompletion(kubeConfigFlags)); err != nil {
 		panic(err)
 	}
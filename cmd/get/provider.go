@@ -16,9 +16,10 @@ import (
 
 // NewProviderCmd creates the get provider command
 func NewProviderCmd(kubeConfigFlags *genericclioptions.ConfigFlags, globalConfig GlobalConfigGetter) *cobra.Command {
-	outputFormatFlag := flags.NewOutputFormatTypeFlag()
+	outputFormatFlag := flags.NewWideOutputFormatTypeFlag().WithTemplates()
 	var watch bool
 	var query string
+	var selector string
 
 	var providerName string
 	cmd := &cobra.Command{
@@ -34,6 +35,12 @@ environments for VM migrations. Lists all providers or retrieves details for a s
   # List providers across all namespaces
   kubectl-mtv get providers --all-namespaces
 
+  # List the providers of a site
+  kubectl-mtv get providers -l site=dc1
+
+  # Audit provider hosts, secrets, VDDK images and TLS verification
+  kubectl-mtv get providers --output wide
+
   # Get provider details in YAML format
   kubectl-mtv get provider --name vsphere-prod --output yaml
 
@@ -45,6 +52,9 @@ environments for VM migrations. Lists all providers or retrieves details for a s
 			if err := flags.ResolveNameArg(&providerName, args); err != nil {
 				return err
 			}
+			if err := flags.ValidateSelector(selector, providerName); err != nil {
+				return err
+			}
 
 			ctx := cmd.Context()
 			if !watch {
@@ -69,17 +79,19 @@ environments for VM migrations. Lists all providers or retrieves details for a s
 			}
 			logOutputFormat(outputFormatFlag.GetValue())
 
-			return provider.List(ctx, kubeConfigFlags, namespace, inventoryURL, watch, outputFormatFlag.GetValue(), providerName, inventoryInsecureSkipTLS, query)
+			return provider.List(ctx, kubeConfigFlags, namespace, inventoryURL, watch, outputFormatFlag.GetValue(), providerName, inventoryInsecureSkipTLS, query, selector)
 		},
 	}
 
 	cmd.Flags().StringVarP(&providerName, "name", "M", "", "Provider name")
-	cmd.Flags().VarP(outputFormatFlag, "output", "o", flags.OutputFormatHelp)
+	cmd.Flags().VarP(outputFormatFlag, "output", "o", flags.OutputFormatWideTemplateHelp)
 	cmd.Flags().StringVarP(&query, "query", "q", "", flags.QueryHelp)
+	cmd.Flags().StringVarP(&selector, "selector", "l", "", flags.SelectorHelp)
 	cmd.Flags().BoolVarP(&watch, "watch", "w", false, "Watch for changes")
 	help.MarkMCPHidden(cmd, "watch")
 
-	// Add completion for name and output format flags
+	// Add completion for the name argument, name and output format flags
+	cmd.ValidArgsFunction = completion.NameArgCompletion(completion.ProviderNameCompletion(kubeConfigFlags))
 	if err := cmd.RegisterFlagCompletionFunc("name", completion.ProviderNameCompletion(kubeConfigFlags)); err != nil {
 		panic(err)
 	}
@@ -0,0 +1,92 @@
+package get
+
+import (
+	"context"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/get/wave"
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/help"
+	"github.com/yaacov/kubectl-mtv/pkg/util/client"
+	"github.com/yaacov/kubectl-mtv/pkg/util/completion"
+	"github.com/yaacov/kubectl-mtv/pkg/util/flags"
+)
+
+// NewWaveCmd creates the get wave command
+func NewWaveCmd(kubeConfigFlags *genericclioptions.ConfigFlags, globalConfig GlobalConfigGetter) *cobra.Command {
+	outputFormatFlag := flags.NewOutputFormatTypeFlag().WithTemplates()
+	var watch bool
+	var query string
+
+	var waveName string
+	cmd := &cobra.Command{
+		Use:   "wave",
+		Short: "Get migration waves",
+		Long: `Get migration waves and their aggregated progress.
+
+A wave groups migration plans that are started together with 'start wave'.
+The listing shows how many plans and VMs of each wave have succeeded, how many
+plans are running or failed, and the overall wave status. Getting a single wave
+also shows the progress of each of its plans.`,
+		Example: `  # List all waves
+  kubectl-mtv get waves
+
+  # Show the progress of a wave and each of its plans
+  kubectl-mtv get wave --name wave-1
+
+  # Watch a running wave
+  kubectl-mtv get wave --name wave-1 --watch
+
+  # Get a wave in JSON format
+  kubectl-mtv get wave --name wave-1 --output json`,
+		Args:         cobra.MaximumNArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := flags.ResolveNameArg(&waveName, args); err != nil {
+				return err
+			}
+
+			ctx := cmd.Context()
+			if !watch {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, 30*time.Second)
+				defer cancel()
+			}
+
+			// Get namespace from global configuration
+			kubeConfigFlags := globalConfig.GetKubeConfigFlags()
+			allNamespaces := globalConfig.GetAllNamespaces()
+			namespace := client.ResolveNamespaceWithAllFlag(kubeConfigFlags, allNamespaces)
+
+			// Log the operation being performed
+			if waveName != "" {
+				logNamespaceOperation("Getting wave", namespace, allNamespaces)
+			} else {
+				logNamespaceOperation("Getting waves", namespace, allNamespaces)
+			}
+			logOutputFormat(outputFormatFlag.GetValue())
+
+			return wave.List(ctx, kubeConfigFlags, namespace, watch, outputFormatFlag.GetValue(), waveName, globalConfig.GetUseUTC(), query)
+		},
+	}
+
+	cmd.Flags().StringVarP(&waveName, "name", "M", "", "Wave name")
+	cmd.Flags().VarP(outputFormatFlag, "output", "o", flags.OutputFormatTemplateHelp)
+	cmd.Flags().StringVarP(&query, "query", "q", "", flags.QueryHelp)
+	cmd.Flags().BoolVarP(&watch, "watch", "w", false, "Watch for changes")
+	help.MarkMCPHidden(cmd, "watch")
+
+	// Add completion for name and output format flags
+	if err := cmd.RegisterFlagCompletionFunc("name", completion.WaveNameCompletion(kubeConfigFlags)); err != nil {
+		panic(err)
+	}
+	if err := cmd.RegisterFlagCompletionFunc("output", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return outputFormatFlag.GetValidValues(), cobra.ShellCompDirectiveNoFileComp
+	}); err != nil {
+		panic(err)
+	}
+
+	return cmd
+}
@@ -0,0 +1,58 @@
+package graph
+
+import (
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/yaacov/kubectl-mtv/cmd/get"
+	pkggraph "github.com/yaacov/kubectl-mtv/pkg/cmd/graph"
+	"github.com/yaacov/kubectl-mtv/pkg/util/client"
+	"github.com/yaacov/kubectl-mtv/pkg/util/completion"
+)
+
+// NewGraphCmd creates the graph command
+func NewGraphCmd(kubeConfigFlags *genericclioptions.ConfigFlags, globalConfig get.GlobalConfigGetter) *cobra.Command {
+	var planName string
+	var outputFormat string
+
+	cmd := &cobra.Command{
+		Use:   "graph",
+		Short: "Draw the resources of a plan and their references",
+		Long: `Print the relationships between a plan and the resources it references or created
+as diagram source:
+  - the source and destination providers of the plan
+  - its network and storage mappings, and the providers each mapping was created for
+  - the hooks of its VMs
+  - its migrations
+  - the VirtualMachines created in the target namespace, linked to their migration
+
+References to resources that do not exist are drawn dashed in red, and a mapping created
+for other providers than the plan's shows up as an extra provider, which makes miswired
+references easy to spot.
+
+The dot output renders with Graphviz; the mermaid output renders in markdown on GitHub,
+GitLab and most wikis.`,
+		Example: `  # Render a plan as an SVG with Graphviz
+  kubectl-mtv graph --plan my-migration | dot -Tsvg > my-migration.svg
+
+  # Mermaid flowchart for a wiki page
+  kubectl-mtv graph --plan my-migration -o mermaid`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg := globalConfig.GetKubeConfigFlags()
+			return pkggraph.Print(cmd.Context(), cfg, planName, client.ResolveNamespace(cfg), outputFormat)
+		},
+	}
+
+	cmd.Flags().StringVarP(&planName, "plan", "p", "", "Plan to draw")
+	cmd.Flags().StringVarP(&outputFormat, "output", "o", "dot", "Output format (dot, mermaid, json)")
+	_ = cmd.MarkFlagRequired("plan")
+
+	_ = cmd.RegisterFlagCompletionFunc("plan", completion.PlanNameCompletion(kubeConfigFlags))
+	_ = cmd.RegisterFlagCompletionFunc("output", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"dot", "mermaid", "json"}, cobra.ShellCompDirectiveNoFileComp
+	})
+
+	return cmd
+}
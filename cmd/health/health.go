@@ -9,6 +9,7 @@ import (
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 
 	pkghealth "github.com/yaacov/kubectl-mtv/pkg/cmd/health"
+	"github.com/yaacov/kubectl-mtv/pkg/util/client"
 	"github.com/yaacov/kubectl-mtv/pkg/util/flags"
 )
 
@@ -16,12 +17,15 @@ import (
 type GlobalConfigGetter interface {
 	GetAllNamespaces() bool
 	GetVerbosity() int
+	GetInventoryURL() string
+	GetInventoryInsecureSkipTLS() bool
 }
 
 // NewHealthCmd creates the health command
 func NewHealthCmd(kubeConfigFlags *genericclioptions.ConfigFlags, globalConfig GlobalConfigGetter) *cobra.Command {
 	var skipLogs bool
 	var logLines int
+	var deep bool
 	outputFormatFlag := flags.NewOutputFormatTypeFlag()
 
 	cmd := &cobra.Command{
@@ -37,6 +41,14 @@ This command checks:
 - Migration plan status and issues
 - Pod logs for errors and warnings (can be skipped with --skip-logs)
 
+Deep mode (--deep) adds slower diagnostics, each reported with a severity:
+- forklift-controller deployment readiness
+- Inventory route reachability
+- Forklift CRD served versions
+- CDI (Containerized Data Importer) availability
+- Admission webhook CA certificates (missing, invalid, expiring)
+- Per-provider inventory connectivity
+
 Namespace behavior:
   Forklift OPERATOR components (controller, pods, logs) are always checked in
   the auto-detected operator namespace (typically openshift-mtv), regardless
@@ -67,17 +79,17 @@ Examples:
   kubectl mtv health --skip-logs
 
   # Check health with more log lines analyzed
-  kubectl mtv health --log-lines 200`,
+  kubectl mtv health --log-lines 200
+
+  # Run deep diagnostics and emit a machine-readable report
+  kubectl mtv health --deep --output json`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// Create context with timeout
 			ctx, cancel := context.WithTimeout(cmd.Context(), 60*time.Second)
 			defer cancel()
 
-			// Get namespace from flag or use default
-			namespace := ""
-			if kubeConfigFlags.Namespace != nil && *kubeConfigFlags.Namespace != "" {
-				namespace = *kubeConfigFlags.Namespace
-			}
+			// Get the namespace chosen for MTV resources, or auto-detect the operator namespace
+			namespace, _ := client.MTVNamespace(kubeConfigFlags)
 
 			// Build health check options
 			opts := pkghealth.HealthCheckOptions{
@@ -86,6 +98,10 @@ Examples:
 				CheckLogs:     !skipLogs,
 				LogLines:      logLines,
 				Verbose:       globalConfig.GetVerbosity() > 0,
+
+				Deep:                     deep,
+				InventoryURL:             globalConfig.GetInventoryURL(),
+				InventoryInsecureSkipTLS: globalConfig.GetInventoryInsecureSkipTLS(),
 			}
 
 			// Run health check
@@ -103,6 +119,7 @@ Examples:
 	cmd.Flags().VarP(outputFormatFlag, "output", "o", flags.OutputFormatHelp)
 	cmd.Flags().BoolVar(&skipLogs, "skip-logs", false, "Skip pod log analysis (faster but less thorough)")
 	cmd.Flags().IntVar(&logLines, "log-lines", 100, "Number of log lines to analyze per pod")
+	cmd.Flags().BoolVar(&deep, "deep", false, "Run deep diagnostics (deployment readiness, inventory route, CRD versions, CDI, webhook certificates, provider connectivity)")
 
 	// Add completion for output format flag
 	if err := cmd.RegisterFlagCompletionFunc("output", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -0,0 +1,69 @@
+package inventory
+
+import (
+	"context"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/yaacov/kubectl-mtv/cmd/get"
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/inventory/snapshot"
+	"github.com/yaacov/kubectl-mtv/pkg/util/client"
+	"github.com/yaacov/kubectl-mtv/pkg/util/completion"
+)
+
+// NewDiffCmd creates the inventory diff command
+func NewDiffCmd(kubeConfigFlags *genericclioptions.ConfigFlags, globalConfig get.GlobalConfigGetter) *cobra.Command {
+	var provider string
+	var outputFormat string
+
+	cmd := &cobra.Command{
+		Use:   "diff OLD [NEW]",
+		Short: "Compare inventory snapshots",
+		Long: `Compare two inventory snapshots saved by 'inventory snapshot', or a snapshot with the
+live inventory of its provider when only one file is given.
+
+Objects are matched by ID and reported as:
+  - Added, Removed: the object exists in only one snapshot
+  - Renamed: the object's name changed
+  - Changed: CPU, memory, power state, guest OS, folder, networks or NICs of a VM,
+    the capacity of a datastore or disk, the VLAN of a network, and so on
+  - DiskAdded, DiskRemoved, DiskResized: the disks of a VM`,
+		Example: `  # Changes between two snapshots
+  kubectl-mtv inventory diff inventory-2026-01-05.json inventory-2026-02-05.json
+
+  # Changes since a snapshot, compared with the live inventory
+  kubectl-mtv inventory diff inventory-2026-01-05.json
+
+  # Machine-readable changes
+  kubectl-mtv inventory diff old.json new.json -o json`,
+		Args:         cobra.RangeArgs(1, 2),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := context.WithTimeout(cmd.Context(), 280*time.Second)
+			defer cancel()
+
+			newFile := ""
+			if len(args) == 2 {
+				newFile = args[1]
+			}
+			cfg := globalConfig.GetKubeConfigFlags()
+			namespace := ""
+			if provider != "" {
+				namespace = client.ResolveNamespace(cfg)
+			}
+			return snapshot.PrintDiff(ctx, cfg, args[0], newFile, provider, namespace, globalConfig.GetInventoryURL(), globalConfig.GetInventoryInsecureSkipTLS(), outputFormat)
+		},
+	}
+
+	cmd.Flags().StringVarP(&provider, "provider", "p", "", "Provider to compare with when only one file is given (default: the snapshot's provider)")
+	cmd.Flags().StringVarP(&outputFormat, "output", "o", "table", "Output format (table, markdown, json, yaml)")
+
+	_ = cmd.RegisterFlagCompletionFunc("provider", completion.ProviderNameCompletion(kubeConfigFlags))
+	_ = cmd.RegisterFlagCompletionFunc("output", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"table", "markdown", "json", "yaml"}, cobra.ShellCompDirectiveNoFileComp
+	})
+
+	return cmd
+}
@@ -0,0 +1,28 @@
+package inventory
+
+import (
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/yaacov/kubectl-mtv/cmd/get"
+)
+
+// NewInventoryCmd creates the inventory command with all its subcommands
+func NewInventoryCmd(kubeConfigFlags *genericclioptions.ConfigFlags, globalConfig get.GlobalConfigGetter) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "inventory",
+		Short: "Save and compare provider inventories",
+		Long: `Save a provider's inventory to a local file and compare saved inventories, to track
+how the source estate drifts during long-running migration projects.
+
+To query the live inventory, use 'get inventory'.`,
+		SilenceUsage: true,
+	}
+
+	snapshotCmd := NewSnapshotCmd(kubeConfigFlags, globalConfig)
+	cmd.AddCommand(snapshotCmd)
+
+	diffCmd := NewDiffCmd(kubeConfigFlags, globalConfig)
+	cmd.AddCommand(diffCmd)
+	return cmd
+}
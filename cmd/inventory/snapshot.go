@@ -0,0 +1,53 @@
+package inventory
+
+import (
+	"context"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/yaacov/kubectl-mtv/cmd/get"
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/inventory/snapshot"
+	"github.com/yaacov/kubectl-mtv/pkg/util/client"
+	"github.com/yaacov/kubectl-mtv/pkg/util/completion"
+)
+
+// NewSnapshotCmd creates the inventory snapshot command
+func NewSnapshotCmd(kubeConfigFlags *genericclioptions.ConfigFlags, globalConfig get.GlobalConfigGetter) *cobra.Command {
+	var provider string
+	var file string
+
+	cmd := &cobra.Command{
+		Use:   "snapshot",
+		Short: "Save a provider's inventory to a file",
+		Long: `Save the full inventory of a provider to a local file, to compare later with 'inventory diff'.
+
+The snapshot holds the VMs, networks and storage of the provider, and depending on the
+provider type its hosts, clusters, disks, volumes and flavors, with all their details.
+Files ending in .yaml or .yml are written as YAML, others as JSON.`,
+		Example: `  # Save the inventory of a provider
+  kubectl-mtv inventory snapshot --provider vsphere-prod --file inventory-2026-01-05.json
+
+  # Save as YAML
+  kubectl-mtv inventory snapshot --provider vsphere-prod -f inventory.yaml`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := context.WithTimeout(cmd.Context(), 280*time.Second)
+			defer cancel()
+
+			cfg := globalConfig.GetKubeConfigFlags()
+			namespace := client.ResolveNamespace(cfg)
+			return snapshot.Write(ctx, cfg, provider, namespace, globalConfig.GetInventoryURL(), globalConfig.GetInventoryInsecureSkipTLS(), file)
+		},
+	}
+
+	cmd.Flags().StringVarP(&provider, "provider", "p", "", "Provider name")
+	_ = cmd.MarkFlagRequired("provider")
+	cmd.Flags().StringVarP(&file, "file", "f", "", "Write the snapshot to a file instead of stdout")
+
+	_ = cmd.RegisterFlagCompletionFunc("provider", completion.ProviderNameCompletion(kubeConfigFlags))
+
+	return cmd
+}
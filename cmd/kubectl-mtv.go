@@ -5,6 +5,9 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"runtime/debug"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
@@ -13,21 +16,39 @@ import (
 
 	"github.com/yaacov/kubectl-mtv/cmd/archive"
 	"github.com/yaacov/kubectl-mtv/cmd/cancel"
+	"github.com/yaacov/kubectl-mtv/cmd/cleanup"
 	"github.com/yaacov/kubectl-mtv/cmd/create"
 	"github.com/yaacov/kubectl-mtv/cmd/cutover"
 	"github.com/yaacov/kubectl-mtv/cmd/delete"
 	"github.com/yaacov/kubectl-mtv/cmd/describe"
+	"github.com/yaacov/kubectl-mtv/cmd/dev"
+	"github.com/yaacov/kubectl-mtv/cmd/doctor"
+	"github.com/yaacov/kubectl-mtv/cmd/estimate"
+	"github.com/yaacov/kubectl-mtv/cmd/events"
 	"github.com/yaacov/kubectl-mtv/cmd/get"
+	"github.com/yaacov/kubectl-mtv/cmd/graph"
 	"github.com/yaacov/kubectl-mtv/cmd/health"
 	"github.com/yaacov/kubectl-mtv/cmd/help"
+	"github.com/yaacov/kubectl-mtv/cmd/inventory"
 	"github.com/yaacov/kubectl-mtv/cmd/mcpserver"
 	"github.com/yaacov/kubectl-mtv/cmd/patch"
+	"github.com/yaacov/kubectl-mtv/cmd/query"
+	"github.com/yaacov/kubectl-mtv/cmd/report"
+	"github.com/yaacov/kubectl-mtv/cmd/seal"
 	"github.com/yaacov/kubectl-mtv/cmd/settings"
 	"github.com/yaacov/kubectl-mtv/cmd/start"
+	"github.com/yaacov/kubectl-mtv/cmd/test"
+	"github.com/yaacov/kubectl-mtv/cmd/top"
 	"github.com/yaacov/kubectl-mtv/cmd/unarchive"
 	"github.com/yaacov/kubectl-mtv/cmd/version"
+	"github.com/yaacov/kubectl-mtv/pkg/util/bugreport"
 	"github.com/yaacov/kubectl-mtv/pkg/util/client"
+	"github.com/yaacov/kubectl-mtv/pkg/util/config"
+	"github.com/yaacov/kubectl-mtv/pkg/util/flags"
 	"github.com/yaacov/kubectl-mtv/pkg/util/output"
+	"github.com/yaacov/kubectl-mtv/pkg/util/progress"
+	"github.com/yaacov/kubectl-mtv/pkg/util/telemetry"
+	"github.com/yaacov/kubectl-mtv/pkg/util/watch"
 	pkgversion "github.com/yaacov/kubectl-mtv/pkg/version"
 )
 
@@ -36,7 +57,15 @@ type GlobalConfig struct {
 	Verbosity                int
 	AllNamespaces            bool
 	UseUTC                   bool
+	TimeFormat               string
+	SortBy                   string
+	SortDescending           bool
+	ShowIDs                  bool
+	IDsOnly                  bool
 	NoColor                  bool
+	Record                   bool
+	Progress                 string
+	WatchTimeout             time.Duration
 	InventoryURL             string
 	InventoryInsecureSkipTLS bool
 	KubeConfigFlags          *genericclioptions.ConfigFlags
@@ -78,11 +107,8 @@ func (g *GlobalConfig) GetInventoryURL() string {
 	g.inventoryURLResolved = true
 
 	// Attempt auto-discovery from OpenShift routes
-	// Note: This uses the default namespace from kubeconfig
-	namespace := ""
-	if g.KubeConfigFlags.Namespace != nil && *g.KubeConfigFlags.Namespace != "" {
-		namespace = *g.KubeConfigFlags.Namespace
-	}
+	// Note: This uses the namespace chosen for MTV resources, or the operator namespace
+	namespace, _ := client.MTVNamespace(g.KubeConfigFlags)
 
 	// Use context.Background() for discovery as we don't have a command context here
 	discoveredURL := client.DiscoverInventoryURL(context.Background(), g.KubeConfigFlags, namespace)
@@ -127,7 +153,68 @@ func GetGlobalConfig() *GlobalConfig {
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 func Execute() error {
-	return rootCmd.Execute()
+	start := time.Now()
+
+	// Offer a bug report for panics, then exit the way an unrecovered panic would
+	defer func() {
+		if r := recover(); r != nil {
+			stack := debug.Stack()
+			fmt.Fprintf(os.Stderr, "panic: %v\n\n%s", r, stack)
+			offerBugReport(fmt.Sprintf("panic: %v", r), stack)
+			os.Exit(2)
+		}
+	}()
+
+	cmd, err := rootCmd.ExecuteC()
+
+	// Count the command when the user opted in to telemetry (off by default)
+	if cmd != nil {
+		commandPath := strings.TrimSpace(strings.TrimPrefix(cmd.CommandPath(), rootCmd.Name()))
+		telemetry.RecordCommand(commandPath, err, time.Since(start))
+	}
+
+	// Server side failures are likely bugs, usage and validation errors are not
+	if bugreport.IsUnexpected(err) {
+		offerBugReport(err.Error(), nil)
+	}
+	return err
+}
+
+// newBreadcrumb returns the breadcrumb recorded by the command on the resources it changes.
+// Flag values are hashed, never recorded, they may hold credentials.
+func newBreadcrumb(cmd *cobra.Command) *client.Breadcrumb {
+	flags := map[string]string{}
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		if f.Name != "record" {
+			flags[f.Name] = f.Value.String()
+		}
+	})
+	commandPath := strings.TrimSpace(strings.TrimPrefix(cmd.CommandPath(), rootCmd.Name()))
+	return client.NewBreadcrumb(commandPath, client.KubeconfigUser(kubeConfigFlags), clientVersion, flags)
+}
+
+// applyLocalSettings applies the defaults saved for the kubeconfig context with 'settings set --local'
+func applyLocalSettings(cmd *cobra.Command) {
+	file, err := config.LoadDefaults()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: ignoring local settings: %v\n", err)
+		return
+	}
+	if len(file.Contexts) == 0 {
+		return
+	}
+	config.ApplyDefaults(cmd.Flags(), file.Values(config.CurrentContext(kubeConfigFlags)))
+}
+
+// offerBugReport offers to write a pre-filled bug report for the current command line
+func offerBugReport(failure string, stack []byte) {
+	args := os.Args[1:]
+	commandPath := ""
+	if cmd, _, err := rootCmd.Find(args); err == nil && cmd != nil {
+		commandPath = strings.TrimSpace(strings.TrimPrefix(cmd.CommandPath(), rootCmd.Name()))
+	}
+	report := bugreport.New(commandPath, args, failure, stack)
+	bugreport.Offer(report, bugreport.Mode(), bugreport.Interactive(), os.Stdin, os.Stderr)
 }
 
 func init() {
@@ -146,19 +233,79 @@ func init() {
 		Short: "Migration Toolkit for Virtualization CLI",
 		Long: `Migration Toolkit for Virtualization (MTV) CLI.
 Migrate virtual machines from VMware vSphere, oVirt (RHV), OpenStack, and OVA to KubeVirt on OpenShift/Kubernetes.`,
-		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
 			// Initialize klog with the verbosity level
 			klog.InitFlags(nil)
 			if err := flag.Set("v", fmt.Sprintf("%d", globalConfig.Verbosity)); err != nil {
 				klog.Warningf("Failed to set klog verbosity: %v", err)
 			}
 
-			// Disable ANSI color output when requested
-			output.SetColorEnabled(!globalConfig.NoColor)
+			// Fill the flags not given on the command line from the local settings of the kubeconfig context
+			applyLocalSettings(cmd)
+
+			// Disable ANSI color output and status icons when requested or when stdout is not a terminal
+			output.SetColorEnabled(!globalConfig.NoColor && output.IsTerminal(os.Stdout))
+
+			// Apply timestamp settings to every printed time
+			output.SetUTC(globalConfig.UseUTC)
+			if err := output.SetTimeFormat(globalConfig.TimeFormat); err != nil {
+				return err
+			}
+
+			// Order every printed table by the requested column or field
+			output.SetSortBy(globalConfig.SortBy, globalConfig.SortDescending)
+
+			// Show IDs next to names, or only IDs, in every printed table
+			if globalConfig.ShowIDs && globalConfig.IDsOnly {
+				return fmt.Errorf("--show-ids and --ids-only cannot be used together")
+			}
+			output.SetIDMode(globalConfig.ShowIDs, globalConfig.IDsOnly)
+
+			// Write structured progress events of long operations to stderr, when requested
+			if err := progress.SetMode(globalConfig.Progress); err != nil {
+				return err
+			}
+
+			// Stop every --watch after the requested time
+			if globalConfig.WatchTimeout < 0 {
+				return fmt.Errorf("invalid --watch-timeout %s: must not be negative", globalConfig.WatchTimeout)
+			}
+			watch.SetTimeout(globalConfig.WatchTimeout)
+
+			// Record which command changed the Forklift resources, when requested
+			if globalConfig.Record {
+				client.SetBreadcrumb(newBreadcrumb(cmd))
+			}
+
+			// Apply the --dry-run mode of write commands to every request sent to the cluster
+			client.SetDryRun(flags.DryRunMode(cmd))
+			diff := flags.Diff(cmd)
+			if diff && !client.DryRunEnabled() {
+				return fmt.Errorf("--diff can only be used with --dry-run")
+			}
+			// --show-diff of patch commands prints the diff of a dry run, or of each patch
+			// before it is applied
+			showDiff := flags.ShowDiff(cmd)
+			client.SetDryRunDiff(diff || showDiff && client.DryRunEnabled())
+			if showDiff && !client.DryRunEnabled() {
+				client.SetShowDiff(cmd.OutOrStdout())
+			}
 
 			// Log global configuration if verbosity is enabled
 			logDebugf("Global configuration - Verbosity: %d, All Namespaces: %t, NoColor: %t",
 				globalConfig.Verbosity, globalConfig.AllNamespaces, globalConfig.NoColor)
+			return nil
+		},
+		PersistentPostRunE: func(cmd *cobra.Command, args []string) error {
+			// Print the resources a dry run of a write command would have created or changed
+			if flags.Diff(cmd) || flags.ShowDiff(cmd) && client.DryRunEnabled() {
+				return client.PrintDryRunDiff(cmd.OutOrStdout())
+			}
+			format := ""
+			if f := cmd.Flags().Lookup("output"); f != nil {
+				format = f.Value.String()
+			}
+			return client.PrintDryRunObjects(cmd.OutOrStdout(), format)
 		},
 	}
 
@@ -167,10 +314,19 @@ Migrate virtual machines from VMware vSphere, oVirt (RHV), OpenStack, and OVA to
 	// Add global flags
 	rootCmd.PersistentFlags().IntVarP(&globalConfig.Verbosity, "verbose", "v", 0, "verbose output level (0=silent, 1=info, 2=debug, 3=trace)")
 	rootCmd.PersistentFlags().BoolVarP(&globalConfig.AllNamespaces, "all-namespaces", "A", false, "list resources across all namespaces")
-	rootCmd.PersistentFlags().BoolVar(&globalConfig.UseUTC, "use-utc", false, "format timestamps in UTC instead of local timezone")
+	rootCmd.PersistentFlags().BoolVar(&globalConfig.UseUTC, "utc", false, "format timestamps in UTC instead of local timezone")
+	rootCmd.PersistentFlags().BoolVar(&globalConfig.UseUTC, "use-utc", false, "alias for --utc")
+	rootCmd.PersistentFlags().StringVar(&globalConfig.TimeFormat, "time-format", os.Getenv("MTV_TIME_FORMAT"), "timestamp format: "+output.TimeFormatHelp)
+	rootCmd.PersistentFlags().StringVar(&globalConfig.SortBy, "sort-by", "", "sort table output by a column title or a JSON field path (e.g. memoryMB, .spec.targetNamespace)")
+	rootCmd.PersistentFlags().BoolVar(&globalConfig.SortDescending, "desc", false, "sort table output in descending order (with --sort-by)")
+	rootCmd.PersistentFlags().BoolVar(&globalConfig.ShowIDs, "show-ids", false, "add an ID column to tables that only show names")
+	rootCmd.PersistentFlags().BoolVar(&globalConfig.IDsOnly, "ids-only", false, "print only the IDs of the table rows, one per line")
+	rootCmd.PersistentFlags().StringVar(&globalConfig.Progress, "progress", progress.ModeNone, "progress events of long operations: none or json (JSON lines on stderr)")
+	rootCmd.PersistentFlags().DurationVar(&globalConfig.WatchTimeout, "watch-timeout", 0, "stop --watch after this duration, e.g. 30m (0 watches until you quit)")
 	rootCmd.PersistentFlags().StringVarP(&globalConfig.InventoryURL, "inventory-url", "i", os.Getenv("MTV_INVENTORY_URL"), "Base URL for the inventory service")
 	rootCmd.PersistentFlags().BoolVar(&globalConfig.InventoryInsecureSkipTLS, "inventory-insecure-skip-tls", os.Getenv("MTV_INVENTORY_INSECURE_SKIP_TLS") == "true", "Skip TLS verification for inventory service connections")
-	rootCmd.PersistentFlags().BoolVar(&globalConfig.NoColor, "no-color", os.Getenv("NO_COLOR") != "", "Disable colored output (also respects NO_COLOR env var)")
+	rootCmd.PersistentFlags().BoolVar(&globalConfig.NoColor, "no-color", os.Getenv("NO_COLOR") != "", "Disable colored output and status icons (also respects NO_COLOR env var; disabled automatically when stdout is not a terminal)")
+	rootCmd.PersistentFlags().BoolVar(&globalConfig.Record, "record", os.Getenv("MTV_RECORD") == "true", "Annotate changed MTV resources with the command, user, version and a hash of the flags (kubectl-mtv/last-action, also MTV_RECORD=true)")
 
 	// Mark global flags that should appear in AI/MCP tool descriptions.
 	// These are surfaced via the "llm-relevant" pflag annotation, which the help
@@ -190,6 +346,34 @@ Migrate virtual machines from VMware vSphere, oVirt (RHV), OpenStack, and OVA to
 	rootCmd.AddCommand(cutover.NewCutoverCmd(kubeConfigFlags))
 	rootCmd.AddCommand(archive.NewArchiveCmd(kubeConfigFlags))
 	rootCmd.AddCommand(unarchive.NewUnArchiveCmd(kubeConfigFlags))
+	rootCmd.AddCommand(seal.NewSealCmd(kubeConfigFlags))
+
+	// Report command - shareable post-migration reports
+	rootCmd.AddCommand(report.NewReportCmd(kubeConfigFlags, globalConfig))
+
+	// Cleanup command - leftovers of failed migrations on the source providers
+	rootCmd.AddCommand(cleanup.NewCleanupCmd(kubeConfigFlags, globalConfig))
+
+	// Estimate command - projected cost of plans on the target cluster
+	rootCmd.AddCommand(estimate.NewEstimateCmd(kubeConfigFlags, globalConfig))
+
+	// Inventory command - offline inventory snapshots and diffs
+	rootCmd.AddCommand(inventory.NewInventoryCmd(kubeConfigFlags, globalConfig))
+
+	// Query command - compose TSL queries field by field
+	rootCmd.AddCommand(query.NewQueryCmd(kubeConfigFlags))
+
+	// Events command - timeline of the Kubernetes events of migrations
+	rootCmd.AddCommand(events.NewEventsCmd(kubeConfigFlags, globalConfig))
+
+	// Graph command - diagram of the resources of a plan and their references
+	rootCmd.AddCommand(graph.NewGraphCmd(kubeConfigFlags, globalConfig))
+
+	// Test command - run hooks once before attaching them to plans
+	rootCmd.AddCommand(test.NewTestCmd(kubeConfigFlags, globalConfig))
+
+	// Top command - live resource usage of running migrations
+	rootCmd.AddCommand(top.NewTopCmd(kubeConfigFlags, globalConfig))
 
 	// Version command - directly using package function
 	rootCmd.AddCommand(version.NewVersionCmd(clientVersion, kubeConfigFlags, globalConfig))
@@ -197,9 +381,15 @@ Migrate virtual machines from VMware vSphere, oVirt (RHV), OpenStack, and OVA to
 	// Health command - check MTV system health
 	rootCmd.AddCommand(health.NewHealthCmd(kubeConfigFlags, globalConfig))
 
+	// Doctor command - first-run environment checks with a prioritized fix list
+	rootCmd.AddCommand(doctor.NewDoctorCmd(kubeConfigFlags, globalConfig))
+
 	// Settings command - view ForkliftController settings
 	rootCmd.AddCommand(settings.NewSettingsCmd(kubeConfigFlags, globalConfig))
 
+	// Dev command - fake inventory server and other tools for testing pipelines
+	rootCmd.AddCommand(dev.NewDevCmd())
+
 	// MCP Server command - start the Model Context Protocol server
 	rootCmd.AddCommand(mcpserver.NewMCPServerCmd())
 
@@ -8,6 +8,7 @@ import (
 	"net/url"
 	"os"
 	"os/signal"
+	"strconv"
 	"strings"
 	"syscall"
 	"time"
@@ -21,6 +22,10 @@ import (
 	"k8s.io/klog/v2"
 )
 
+// sessionIdleTimeout closes HTTP sessions that went idle, so the per-session state of
+// clients that never end their session (e.g. the selected kubeconfig context) is released
+const sessionIdleTimeout = 30 * time.Minute
+
 var (
 	httpMode         bool
 	port             string
@@ -34,6 +39,16 @@ var (
 	kubeCACert       string
 	maxResponseChars int
 	readOnly         bool
+	profile          string
+	toolNames        []string
+	forceDryRun      bool
+	allowedContexts  []string
+	allowedWindows   []string
+	cacheTTL         time.Duration
+	auditLogPath     string
+	auditMaxSize     int
+	auditMaxBackups  int
+	writeRateLimit   int
 )
 
 // NewMCPServerCmd creates the mcp-server command
@@ -52,8 +67,29 @@ Modes:
 
 Read-Only Mode:
   --read-only: Disables all write operations (mtv_write tool not registered)
+
+Audit and Rate Limits:
+  --audit-log:        Record every tool call (tool, masked arguments, caller, duration,
+                      exit code) as JSON lines in a file rotated at --audit-max-size,
+                      or "-" for stdout (HTTP mode only)
+  --write-rate-limit: Max mtv_write calls per minute per session (0=unlimited)
                Only read operations will be available to AI assistants
 
+Tool Profiles:
+  --profile:       Tools to register (also MTV_MCP_PROFILE):
+                     read-only  mtv_read, mtv_help, mtv_inventory_summary
+                     debug      read-only tools and mtv_troubleshoot (pod logs, events)
+                     full       all tools, including mtv_write (default)
+  --tools:         Register exactly these tools instead of a profile (comma-separated, also MTV_MCP_TOOLS)
+  --force-dry-run: Run every mtv_write command with --dry-run and refuse commands without it
+                   (also MTV_MCP_FORCE_DRY_RUN=true); a safe endpoint for untrusted agents
+
+Read Cache:
+  --cache-ttl: Cache inventory reads (get inventory ...) for this long, e.g. 30s or 2m (default: 0, disabled)
+               Cached entries are per cluster target and are dropped after any write operation.
+               Agents can bypass the cache with "no_cache": true and inspect it with the
+               "cache_stats" read command.
+
 Security:
   --cert-file:   Path to TLS certificate file (enables TLS when both cert and key provided)
   --key-file:    Path to TLS private key file (enables TLS when both cert and key provided)
@@ -66,6 +102,20 @@ Kubernetes Authentication:
 
   These flags set default credentials for all requests. They work in both stdio and HTTP modes.
 
+Per-Session Cluster Selection:
+  --kubeconfig, --context:  Kubeconfig file and default context (global flags)
+  --allowed-contexts:       Contexts that sessions may select (default: any context in the kubeconfig)
+
+  Each MCP session may select its own kubeconfig context, so a single server can
+  serve agents working against different clusters:
+  - Initialize request: "_meta": {"kubectl-mtv/context": "<context>"}
+  - Tool input:         "context": "<context>" (remembered for the rest of the session)
+  - HTTP header:        X-Kubernetes-Context: <context>
+
+  The special context "in-cluster" uses the server pod's service account.
+  A selected context replaces the --server/--token defaults, and sessions can
+  never pass their own kubeconfig path, so credentials stay isolated per session.
+
 HTTP Mode Authentication (HTTP Headers):
   In HTTP mode, the following HTTP headers are supported for per-request authentication:
 
@@ -75,6 +125,9 @@ HTTP Mode Authentication (HTTP Headers):
   X-Kubernetes-Server: <url>
     Kubernetes API server URL. Passed to kubectl via --server flag.
 
+  X-Kubernetes-Context: <context>
+    Kubeconfig context for this request. Passed to kubectl via --context flag.
+
   Precedence: HTTP headers (per-request) > CLI flags (--server/--token) > kubeconfig (implicit).
 
   Each HTTP POST carries its own headers, so token rotation works seamlessly.
@@ -99,6 +152,11 @@ Manual Claude config: Add to claude_desktop_config.json:
 			// Set max response size (helps small LLMs stay within context window)
 			util.SetMaxResponseChars(maxResponseChars)
 
+			if cacheTTL < 0 {
+				return fmt.Errorf("invalid --cache-ttl value %s: must not be negative", cacheTTL)
+			}
+			util.SetReadCacheTTL(cacheTTL)
+
 			// Set default Kubernetes credentials from CLI flags
 			// These serve as fallback when HTTP headers don't provide credentials
 			util.SetDefaultKubeServer(kubeServer)
@@ -112,6 +170,40 @@ Manual Claude config: Add to claude_desktop_config.json:
 				util.SetDefaultVerbosity(v)
 			}
 
+			// Kubeconfig and default context come from the inherited global flags;
+			// sessions may only select contexts from this kubeconfig.
+			if v, err := cobraCmd.Flags().GetString("kubeconfig"); err == nil {
+				util.SetDefaultKubeconfig(v)
+			}
+			if v, err := cobraCmd.Flags().GetString("context"); err == nil {
+				util.SetDefaultKubeContext(v)
+			}
+			util.SetAllowedKubeContexts(allowedContexts)
+			util.SetForceDryRun(forceDryRun)
+
+			enabledTools, err := tools.EnabledTools(profile, toolNames, readOnly)
+			if err != nil {
+				return err
+			}
+			klog.V(1).Infof("Registering MCP tools: %s", strings.Join(tools.ToolList(enabledTools), ", "))
+			if forceDryRun {
+				klog.V(1).Info("Forcing --dry-run on all write operations")
+			}
+			if err := util.SetAllowedWindows(allowedWindows); err != nil {
+				return fmt.Errorf("invalid --allowed-windows value: %v", err)
+			}
+
+			if writeRateLimit < 0 {
+				return fmt.Errorf("invalid --write-rate-limit value %d: must not be negative", writeRateLimit)
+			}
+			util.SetWriteRateLimit(writeRateLimit)
+
+			closeAudit, err := setupAuditLog()
+			if err != nil {
+				return err
+			}
+			defer closeAudit()
+
 			// Create a context that listens for interrupt signals
 			ctx, cancel := context.WithCancel(context.Background())
 			defer cancel()
@@ -131,13 +223,13 @@ Manual Claude config: Add to claude_desktop_config.json:
 				}
 
 				innerHandler := mcp.NewStreamableHTTPHandler(func(req *http.Request) *mcp.Server {
-					server, err := createMCPServerWithRegistry(registry, readOnly)
+					server, err := createMCPServerWithRegistry(registry, enabledTools)
 					if err != nil {
 						klog.Errorf("Failed to create server: %v", err)
 						return nil
 					}
 					return server
-				}, &mcp.StreamableHTTPOptions{})
+				}, &mcp.StreamableHTTPOptions{SessionTimeout: sessionIdleTimeout})
 
 				handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 					if origin := r.Header.Get("Origin"); origin != "" {
@@ -203,7 +295,7 @@ Manual Claude config: Add to claude_desktop_config.json:
 			}
 
 			// Stdio mode - default behavior
-			server, err := createMCPServer(readOnly)
+			server, err := createMCPServer(enabledTools)
 			if err != nil {
 				return fmt.Errorf("failed to create server: %w", err)
 			}
@@ -243,19 +335,85 @@ Manual Claude config: Add to claude_desktop_config.json:
 	mcpCmd.Flags().StringVar(&kubeCACert, "certificate-authority", "", "Path to a CA certificate file for Kubernetes API TLS verification")
 	mcpCmd.Flags().IntVar(&maxResponseChars, "max-response-chars", 0, "Max characters for text output (0=unlimited). Helps small LLMs by truncating long responses")
 	mcpCmd.Flags().BoolVar(&readOnly, "read-only", false, "Run in read-only mode (disables write operations)")
+	mcpCmd.Flags().StringVar(&profile, "profile", envOrDefault("MTV_MCP_PROFILE", tools.ProfileFull), "Tools to register: "+strings.Join(tools.Profiles(), ", "))
+	mcpCmd.Flags().StringSliceVar(&toolNames, "tools", splitEnvList("MTV_MCP_TOOLS"), "Register exactly these tools instead of a profile (comma-separated, e.g. mtv_read,mtv_help)")
+	mcpCmd.Flags().BoolVar(&forceDryRun, "force-dry-run", os.Getenv("MTV_MCP_FORCE_DRY_RUN") == "true", "Run every write operation with --dry-run, refusing commands without it")
+	mcpCmd.Flags().DurationVar(&cacheTTL, "cache-ttl", 0, "Cache inventory read results for this duration, e.g. 30s (0=disabled)")
+	mcpCmd.Flags().StringArrayVar(&allowedWindows, "allowed-windows", nil, "Windows in which mtv_write may start or cut over plans, e.g. \"Mon-Fri 22:00-05:00 Europe/Berlin\" (repeatable; plans can set their own with the kubectl-mtv/allowed-windows annotation)")
+	mcpCmd.Flags().StringSliceVar(&allowedContexts, "allowed-contexts", nil, "Kubeconfig contexts that sessions may select (comma-separated, default: any; use \"in-cluster\" for the service account)")
+	mcpCmd.Flags().StringVar(&auditLogPath, "audit-log", os.Getenv("MTV_MCP_AUDIT_LOG"), "Record every tool call as JSON lines in this file, or \"-\" for stdout (HTTP mode only)")
+	mcpCmd.Flags().IntVar(&auditMaxSize, "audit-max-size", util.DefaultAuditMaxSizeMB, "Rotate the audit log file when it grows over this size in megabytes")
+	mcpCmd.Flags().IntVar(&auditMaxBackups, "audit-max-backups", util.DefaultAuditMaxBackups, "Number of rotated audit log files to keep")
+	mcpCmd.Flags().IntVar(&writeRateLimit, "write-rate-limit", envIntOrDefault("MTV_MCP_WRITE_RATE_LIMIT", 0), "Max mtv_write calls per minute per session (0=unlimited)")
 
 	return mcpCmd
 }
 
+// envOrDefault returns the value of an environment variable, or def when it is unset
+func envOrDefault(name, def string) string {
+	if v := os.Getenv(name); v != "" {
+		return v
+	}
+	return def
+}
+
+// envIntOrDefault returns the integer value of an environment variable, or def when it is unset or invalid
+func envIntOrDefault(name string, def int) int {
+	v, err := strconv.Atoi(strings.TrimSpace(os.Getenv(name)))
+	if err != nil {
+		return def
+	}
+	return v
+}
+
+// setupAuditLog opens the audit log selected by --audit-log and returns a function closing it
+func setupAuditLog() (func(), error) {
+	switch auditLogPath {
+	case "":
+		util.SetAuditLog(nil)
+		return func() {}, nil
+	case util.AuditStdout:
+		// In stdio mode stdout carries the MCP protocol
+		if !httpMode {
+			return nil, fmt.Errorf("--audit-log - (stdout) requires --http: in stdio mode stdout carries the MCP protocol")
+		}
+		util.SetAuditLog(util.NewAuditLog(os.Stdout))
+		return func() {}, nil
+	}
+
+	if auditMaxSize < 1 {
+		return nil, fmt.Errorf("invalid --audit-max-size value %d: must be at least 1", auditMaxSize)
+	}
+	if auditMaxBackups < 0 {
+		return nil, fmt.Errorf("invalid --audit-max-backups value %d: must not be negative", auditMaxBackups)
+	}
+	file, err := util.OpenRotatingFile(auditLogPath, int64(auditMaxSize)*1024*1024, auditMaxBackups)
+	if err != nil {
+		return nil, err
+	}
+	util.SetAuditLog(util.NewAuditLog(file))
+	klog.V(1).Infof("Recording tool calls in %s", auditLogPath)
+	return func() { file.Close() }, nil
+}
+
+// splitEnvList returns the comma-separated values of an environment variable
+func splitEnvList(name string) []string {
+	v := strings.TrimSpace(os.Getenv(name))
+	if v == "" {
+		return nil
+	}
+	return strings.Split(v, ",")
+}
+
 // createMCPServer discovers commands and creates the MCP server.
 // Used by stdio mode where a single server instance is sufficient.
-func createMCPServer(readOnlyMode bool) (*mcp.Server, error) {
+func createMCPServer(enabledTools map[string]bool) (*mcp.Server, error) {
 	ctx := context.Background()
 	registry, err := discovery.NewRegistry(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to discover commands: %w", err)
 	}
-	return createMCPServerWithRegistry(registry, readOnlyMode)
+	return createMCPServerWithRegistry(registry, enabledTools)
 }
 
 // createMCPServerWithRegistry builds an MCP server from a pre-built registry.
@@ -265,18 +423,35 @@ func createMCPServer(readOnlyMode bool) (*mcp.Server, error) {
 // In HTTP mode, the SDK populates req.Extra.Header on every POST with that
 // request's HTTP headers, giving each tool call fresh auth credentials.
 // In stdio mode, there are no HTTP headers and we fall back to CLI defaults.
-func createMCPServerWithRegistry(registry *discovery.Registry, readOnlyMode bool) (*mcp.Server, error) {
+//
+// Only the enabled tools (see tools.EnabledTools) are registered.
+func createMCPServerWithRegistry(registry *discovery.Registry, enabledTools map[string]bool) (*mcp.Server, error) {
 	server := mcp.NewServer(&mcp.Implementation{
 		Name:    "kubectl-mtv",
 		Version: version.ClientVersion,
 	}, &mcp.ServerOptions{
-		Instructions: registry.GenerateServerInstructions(),
+		Instructions:       registry.GenerateServerInstructions(),
+		InitializedHandler: tools.HandleSessionInitialized,
 	})
+	server.AddReceivingMiddleware(tools.AuditMiddleware)
+
+	// Inventory field schemas are static read-only metadata, published with every profile
+	tools.AddInventorySchemaResources(server)
 
-	tools.AddToolWithCoercion(server, tools.GetMTVReadTool(registry), tools.HandleMTVRead(registry))
-	mcp.AddTool(server, tools.GetMTVHelpTool(), tools.HandleMTVHelp)
+	if enabledTools[tools.ToolRead] {
+		tools.AddToolWithCoercion(server, tools.GetMTVReadTool(registry), tools.HandleMTVRead(registry))
+	}
+	if enabledTools[tools.ToolHelp] {
+		mcp.AddTool(server, tools.GetMTVHelpTool(), tools.HandleMTVHelp)
+	}
+	if enabledTools[tools.ToolInventorySummary] {
+		tools.AddToolWithCoercion(server, tools.GetMTVInventorySummaryTool(), tools.HandleMTVInventorySummary)
+	}
+	if enabledTools[tools.ToolTroubleshoot] {
+		tools.AddToolWithCoercion(server, tools.GetMTVTroubleshootTool(), tools.HandleMTVTroubleshoot)
+	}
 
-	if !readOnlyMode {
+	if enabledTools[tools.ToolWrite] {
 		tools.AddToolWithCoercion(server, tools.GetMTVWriteTool(registry), tools.HandleMTVWrite(registry))
 	} else {
 		klog.V(1).Info("Running in read-only mode - write operations disabled")
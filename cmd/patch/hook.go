@@ -90,5 +90,8 @@ Examples:
 
 	_ = cmd.RegisterFlagCompletionFunc("name", completion.HookResourceNameCompletion(kubeConfigFlags))
 
+	flags.AddDryRunFlag(cmd, flags.NewDryRunFlag(), "Print the patched resource instead of changing it (client), or submit the patch with server-side dry run to validate it without persisting (server)")
+	flags.AddShowDiffFlag(cmd)
+
 	return cmd
 }
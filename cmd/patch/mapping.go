@@ -36,16 +36,28 @@ func NewMappingCmd(kubeConfigFlags *genericclioptions.ConfigFlags, globalConfig
 func newPatchNetworkMappingCmd(kubeConfigFlags *genericclioptions.ConfigFlags, globalConfig GlobalConfigGetter) *cobra.Command {
 	var name string
 	var addPairs, updatePairs, removePairs string
+	var addPair, updatePair, removePair []string
 
 	cmd := &cobra.Command{
 		Use:   "network",
 		Short: "Patch a network mapping",
-		Long:  `Patch a network mapping by adding, updating, or removing network pairs`,
+		Long: `Patch a network mapping by adding, updating, or removing network pairs.
+
+Pairs are merged into the existing map. Use --add-pair, --update-pair and --remove-pair
+(repeatable, 'source=destination') for single pairs, or the comma-separated --add-pairs,
+--update-pairs and --remove-pairs lists. Sources are resolved in the source provider
+inventory, and destination network attachment definitions must exist on the target
+provider. Updated sources must already be in the mapping; removing a source that is not
+mapped only prints a warning.`,
 		Example: `  # Add network pairs to a mapping
   kubectl-mtv patch mapping network --name my-net-map --add-pairs "VM Network:default"
 
   # Update network pairs
-  kubectl-mtv patch mapping network --name my-net-map --update-pairs "VM Network:migration-net"`,
+  kubectl-mtv patch mapping network --name my-net-map --update-pairs "VM Network:migration-net"
+
+  # Add, move and remove single pairs
+  kubectl-mtv patch mapping network --name my-net-map --add-pair "DMZ=dmz-ns/dmz-net" \
+    --update-pair "VM Network=prod-ns/prod-net" --remove-pair "Legacy Network"`,
 		Args:         cobra.MaximumNArgs(1),
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -56,13 +68,23 @@ func newPatchNetworkMappingCmd(kubeConfigFlags *genericclioptions.ConfigFlags, g
 				return fmt.Errorf("--name is required")
 			}
 
+			var err error
+			if addPairs, err = mapping.MergePairFlags(addPairs, addPair); err != nil {
+				return fmt.Errorf("--add-pair: %v", err)
+			}
+			if updatePairs, err = mapping.MergePairFlags(updatePairs, updatePair); err != nil {
+				return fmt.Errorf("--update-pair: %v", err)
+			}
+			removePairs = mapping.MergeSourceFlags(removePairs, removePair)
+
 			// Resolve the appropriate namespace based on context and flags
 			namespace := client.ResolveNamespace(kubeConfigFlags)
 
-			// Get inventory URL from global config (auto-discovers if needed)
+			// Get inventory URL and insecure skip TLS from global config (auto-discovers if needed)
 			inventoryURL := globalConfig.GetInventoryURL()
+			inventoryInsecureSkipTLS := globalConfig.GetInventoryInsecureSkipTLS()
 
-			return mapping.PatchNetwork(kubeConfigFlags, name, namespace, addPairs, updatePairs, removePairs, inventoryURL)
+			return mapping.PatchNetworkWithInsecure(kubeConfigFlags, name, namespace, addPairs, updatePairs, removePairs, inventoryURL, inventoryInsecureSkipTLS)
 		},
 	}
 
@@ -71,9 +93,15 @@ func newPatchNetworkMappingCmd(kubeConfigFlags *genericclioptions.ConfigFlags, g
 	cmd.Flags().StringVar(&addPairs, "add-pairs", "", "Network pairs to add in format 'source:target-namespace/target-network', 'source:target-network', 'source:default', or 'source:ignored' (comma-separated)")
 	cmd.Flags().StringVar(&updatePairs, "update-pairs", "", "Network pairs to update in format 'source:target-namespace/target-network', 'source:target-network', 'source:default', or 'source:ignored' (comma-separated)")
 	cmd.Flags().StringVar(&removePairs, "remove-pairs", "", "Source network names to remove from mapping (comma-separated)")
+	cmd.Flags().StringArrayVar(&addPair, "add-pair", nil, "Network pair to add as 'source=target-namespace/target-network', 'source=target-network', 'source=default' or 'source=ignored' (can be specified multiple times)")
+	cmd.Flags().StringArrayVar(&updatePair, "update-pair", nil, "Network pair to update as 'source=new-destination', the source must be in the mapping (can be specified multiple times)")
+	cmd.Flags().StringArrayVar(&removePair, "remove-pair", nil, "Source network to remove from the mapping (can be specified multiple times)")
 
 	_ = cmd.RegisterFlagCompletionFunc("name", completion.MappingNameCompletion(kubeConfigFlags, "network"))
 
+	flags.AddDryRunFlag(cmd, flags.NewDryRunFlag(), "Print the patched resource instead of changing it (client), or submit the patch with server-side dry run to validate it without persisting (server)")
+	flags.AddShowDiffFlag(cmd)
+
 	return cmd
 }
 
@@ -81,6 +109,7 @@ func newPatchNetworkMappingCmd(kubeConfigFlags *genericclioptions.ConfigFlags, g
 func newPatchStorageMappingCmd(kubeConfigFlags *genericclioptions.ConfigFlags, globalConfig GlobalConfigGetter) *cobra.Command {
 	var name string
 	var addPairs, updatePairs, removePairs string
+	var addPair, updatePair, removePair []string
 	var defaultVolumeMode string
 	var defaultAccessMode string
 	var defaultOffloadPlugin string
@@ -91,12 +120,23 @@ func newPatchStorageMappingCmd(kubeConfigFlags *genericclioptions.ConfigFlags, g
 	cmd := &cobra.Command{
 		Use:   "storage",
 		Short: "Patch a storage mapping",
-		Long:  `Patch a storage mapping by adding, updating, or removing storage pairs`,
+		Long: `Patch a storage mapping by adding, updating, or removing storage pairs.
+
+Pairs are merged into the existing map. Use --add-pair, --update-pair and --remove-pair
+(repeatable, 'source=destination') for single pairs, or the comma-separated --add-pairs,
+--update-pairs and --remove-pairs lists. Sources are resolved in the source provider
+inventory, and destination storage classes must exist on the target provider. Updated
+sources must already be in the mapping; removing a source that is not mapped only prints
+a warning.`,
 		Example: `  # Add storage pairs to a mapping
   kubectl-mtv patch mapping storage --name my-storage-map --add-pairs "datastore1:standard"
 
   # Update storage pairs
-  kubectl-mtv patch mapping storage --name my-storage-map --update-pairs "datastore1:premium"`,
+  kubectl-mtv patch mapping storage --name my-storage-map --update-pairs "datastore1:premium"
+
+  # Add, move and remove single pairs
+  kubectl-mtv patch mapping storage --name my-storage-map --add-pair "datastore3=standard" \
+    --update-pair "datastore1=premium;volumeMode=Block" --remove-pair datastore2`,
 		Args:         cobra.MaximumNArgs(1),
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -107,6 +147,15 @@ func newPatchStorageMappingCmd(kubeConfigFlags *genericclioptions.ConfigFlags, g
 				return fmt.Errorf("--name is required")
 			}
 
+			var err error
+			if addPairs, err = mapping.MergePairFlags(addPairs, addPair); err != nil {
+				return fmt.Errorf("--add-pair: %v", err)
+			}
+			if updatePairs, err = mapping.MergePairFlags(updatePairs, updatePair); err != nil {
+				return fmt.Errorf("--update-pair: %v", err)
+			}
+			removePairs = mapping.MergeSourceFlags(removePairs, removePair)
+
 			// Resolve the appropriate namespace based on context and flags
 			namespace := client.ResolveNamespace(kubeConfigFlags)
 
@@ -125,6 +174,9 @@ func newPatchStorageMappingCmd(kubeConfigFlags *genericclioptions.ConfigFlags, g
 	cmd.Flags().StringVar(&addPairs, "add-pairs", "", "Storage pairs to add in format 'source:storage-class[;volumeMode=Block|Filesystem][;accessMode=ReadWriteOnce|ReadWriteMany|ReadOnlyMany][;offloadPlugin=vsphere][;offloadSecret=secret-name][;offloadVendor=vantara|ontap|...]' (comma-separated pairs, semicolon-separated parameters)")
 	cmd.Flags().StringVar(&updatePairs, "update-pairs", "", "Storage pairs to update in format 'source:storage-class[;volumeMode=Block|Filesystem][;accessMode=ReadWriteOnce|ReadWriteMany|ReadOnlyMany][;offloadPlugin=vsphere][;offloadSecret=secret-name][;offloadVendor=vantara|ontap|...]' (comma-separated pairs, semicolon-separated parameters)")
 	cmd.Flags().StringVar(&removePairs, "remove-pairs", "", "Source storage names to remove from mapping (comma-separated)")
+	cmd.Flags().StringArrayVar(&addPair, "add-pair", nil, "Storage pair to add as 'source=storage-class[;volumeMode=...][;accessMode=...]' (can be specified multiple times)")
+	cmd.Flags().StringArrayVar(&updatePair, "update-pair", nil, "Storage pair to update as 'source=new-storage-class[;...]', the source must be in the mapping (can be specified multiple times)")
+	cmd.Flags().StringArrayVar(&removePair, "remove-pair", nil, "Source storage to remove from the mapping (can be specified multiple times)")
 	cmd.Flags().StringVar(&defaultVolumeMode, "default-volume-mode", "", "Default volume mode for new/updated storage pairs (Filesystem|Block)")
 	cmd.Flags().StringVar(&defaultAccessMode, "default-access-mode", "", "Default access mode for new/updated storage pairs (ReadWriteOnce|ReadWriteMany|ReadOnlyMany)")
 	cmd.Flags().StringVar(&defaultOffloadPlugin, "default-offload-plugin", "", "Default offload plugin type for new/updated storage pairs (vsphere)")
@@ -162,5 +214,8 @@ func newPatchStorageMappingCmd(kubeConfigFlags *genericclioptions.ConfigFlags, g
 
 	_ = cmd.RegisterFlagCompletionFunc("name", completion.MappingNameCompletion(kubeConfigFlags, "storage"))
 
+	flags.AddDryRunFlag(cmd, flags.NewDryRunFlag(), "Print the patched resource instead of changing it (client), or submit the patch with server-side dry run to validate it without persisting (server)")
+	flags.AddShowDiffFlag(cmd)
+
 	return cmd
 }
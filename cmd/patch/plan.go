@@ -44,6 +44,7 @@ func NewPlanCmd(kubeConfigFlags *genericclioptions.ConfigFlags) *cobra.Command {
 	// Tag mapping flags (vSphere only)
 	var tagMappingDisabled bool
 	var tagMappingLabelTags []string
+	var dependsOn []string
 
 	// Change tracking for new bool flags
 	var skipZoneNodeSelectorChanged bool
@@ -113,6 +114,9 @@ Affinity Syntax (KARL):
   # Enable raw disk copy mode (skip guest conversion)
   kubectl-mtv patch plan --plan-name my-migration --skip-guest-conversion true
 
+  # Start this plan only after the database plan succeeded
+  kubectl-mtv patch plan --plan-name app-tier --depends-on db-tier
+
   # Configure convertor pod scheduling
   kubectl-mtv patch plan --plan-name my-migration --convertor-node-selector node-role=worker`,
 		Args:         cobra.NoArgs,
@@ -196,6 +200,7 @@ Affinity Syntax (KARL):
 				ServiceAccount:                 serviceAccount,
 				TagMappingDisabled:             tagMappingDisabled,
 				TagMappingLabelTags:            tagMappingLabelTags,
+				DependsOn:                      dependsOn,
 
 				// Flag change tracking
 				UseCompatibilityModeChanged:           useCompatibilityModeChanged,
@@ -217,6 +222,7 @@ Affinity Syntax (KARL):
 				ServiceAccountChanged:                 serviceAccountChanged,
 				TagMappingDisabledChanged:             tagMappingDisabledChanged,
 				TagMappingLabelTagsChanged:            tagMappingLabelTagsChanged,
+				DependsOnChanged:                      cmd.Flags().Changed("depends-on"),
 			})
 		},
 	}
@@ -266,6 +272,7 @@ Affinity Syntax (KARL):
 	cmd.Flags().StringVar(&serviceAccount, "service-account", "", "ServiceAccount for migration pods in the target namespace (overrides global setting)")
 	flags.ExplicitBoolVar(cmd.Flags(), &tagMappingDisabled, "tag-mapping-disabled", false, "Disable vSphere tag-to-label conversion entirely (vSphere only) (true/false)")
 	cmd.Flags().StringSliceVar(&tagMappingLabelTags, "tag-mapping-label-tags", nil, "Only convert these vSphere tag categories to labels (comma-separated, vSphere only)")
+	cmd.Flags().StringSliceVar(&dependsOn, "depends-on", nil, "Plans that must succeed before this plan is started (comma-separated; empty to clear), used by 'start plan --sequential'")
 
 	// Add completion for migration type flag
 	if err := cmd.RegisterFlagCompletionFunc("migration-type", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -301,6 +308,10 @@ Affinity Syntax (KARL):
 	}
 
 	_ = cmd.RegisterFlagCompletionFunc("plan-name", completion.PlanNameCompletion(kubeConfigFlags))
+	_ = cmd.RegisterFlagCompletionFunc("depends-on", completion.PlanNameCompletion(kubeConfigFlags))
+
+	flags.AddDryRunFlag(cmd, flags.NewDryRunFlag(), "Print the patched resource instead of changing it (client), or submit the patch with server-side dry run to validate it without persisting (server)")
+	flags.AddShowDiffFlag(cmd)
 
 	return cmd
 }
@@ -447,5 +458,8 @@ func NewPlanVMCmd(kubeConfigFlags *genericclioptions.ConfigFlags) *cobra.Command
 	_ = cmd.RegisterFlagCompletionFunc("plan-name", completion.PlanNameCompletion(kubeConfigFlags))
 	_ = cmd.RegisterFlagCompletionFunc("vm-name", completion.PlanVMNameCompletion(kubeConfigFlags))
 
+	flags.AddDryRunFlag(cmd, flags.NewDryRunFlag(), "Print the patched resource instead of changing it (client), or submit the patch with server-side dry run to validate it without persisting (server)")
+	flags.AddShowDiffFlag(cmd)
+
 	return cmd
 }
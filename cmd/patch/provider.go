@@ -3,21 +3,25 @@ package patch
 import (
 	"fmt"
 	"os"
-	"strings"
 
 	"github.com/spf13/cobra"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/create/provider/providerutil"
 	"github.com/yaacov/kubectl-mtv/pkg/cmd/patch/provider"
+	"github.com/yaacov/kubectl-mtv/pkg/util/bugreport"
 	"github.com/yaacov/kubectl-mtv/pkg/util/client"
 	"github.com/yaacov/kubectl-mtv/pkg/util/completion"
 	"github.com/yaacov/kubectl-mtv/pkg/util/flags"
+	"github.com/yaacov/kubectl-mtv/pkg/util/secretref"
 )
 
 // NewProviderCmd creates the patch provider command
 func NewProviderCmd(kubeConfigFlags *genericclioptions.ConfigFlags) *cobra.Command {
 	esxiCloneMethod := flags.NewEsxiCloneMethodFlag()
 
+	var cacertSource providerutil.CACertSource
+
 	opts := provider.PatchProviderOptions{
 		ConfigFlags: kubeConfigFlags,
 	}
@@ -28,9 +32,29 @@ func NewProviderCmd(kubeConfigFlags *genericclioptions.ConfigFlags) *cobra.Comma
 	}
 
 	cmd := &cobra.Command{
-		Use:          "provider",
-		Short:        "Patch an existing provider",
-		Long:         `Patch an existing provider by updating URL, credentials, or VDDK settings. Type and SDK endpoint cannot be changed.`,
+		Use:   "provider",
+		Short: "Patch an existing provider",
+		Long: `Patch an existing provider by updating URL, credentials, or VDDK settings. Type and SDK endpoint cannot be changed.
+
+Use --rotate-credentials together with new credentials to update the provider secret,
+trigger reconciliation, and wait for the provider to return to Ready. Validation
+failures reported by the provider conditions are printed if the new credentials are rejected.
+
+Use --cacert-file to replace the provider CA bundle from a PEM file, or --fetch-cacert to
+fetch the certificate presented by the provider URL, review its SHA-256 fingerprint and pin
+it as the provider CA. Pass --cacert-fingerprint to confirm the fetched certificate without
+a prompt.`,
+		Example: `  # Update the vSphere provider URL
+  kubectl-mtv patch provider --name my-vsphere --url https://vcenter.example.com/sdk
+
+  # Rotate vSphere credentials and wait for the provider to become Ready
+  kubectl-mtv patch provider --name my-vsphere --rotate-credentials --username admin@vsphere.local --password 'new-secret'
+
+  # Rotate an OpenShift provider token with a custom wait timeout
+  kubectl-mtv patch provider --name my-ocp --rotate-credentials --provider-token "$TOKEN" --wait-timeout 10m
+
+  # Pin the certificate presented by the provider instead of skipping TLS verification
+  kubectl-mtv patch provider --name my-vsphere --fetch-cacert --provider-insecure-skip-tls=false`,
 		Args:         cobra.MaximumNArgs(1),
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -44,18 +68,34 @@ func NewProviderCmd(kubeConfigFlags *genericclioptions.ConfigFlags) *cobra.Comma
 			// Resolve the appropriate namespace based on context and flags
 			opts.Namespace = client.ResolveNamespace(kubeConfigFlags)
 
-			// Check if cacert starts with @ and load from file if so
-			if strings.HasPrefix(opts.CACert, "@") {
-				filePath := opts.CACert[1:]
-				fileContent, err := os.ReadFile(filePath)
+			// Resolve credentials referenced in external secret managers (vault:, aws-sm:)
+			if err := secretref.ResolveAll(cmd.Context(), &opts.Username, &opts.Password, &opts.Token,
+				&opts.EC2TargetAccessKeyID, &opts.EC2TargetSecretKey, &opts.SMBUser, &opts.SMBPassword,
+				&opts.AzureClientID, &opts.AzureClientSecret); err != nil {
+				return err
+			}
+
+			// Resolve the CA certificate from --cacert, --cacert-file or the fetched server certificate
+			opts.InsecureSkipTLSChanged = cmd.Flag("provider-insecure-skip-tls").Changed
+			if opts.InsecureSkipTLSChanged && opts.InsecureSkipTLS && (cacertSource.File != "" || cacertSource.Fetch) {
+				return fmt.Errorf("--cacert-file and --fetch-cacert cannot be used with --provider-insecure-skip-tls")
+			}
+			providerURL := opts.URL
+			if cacertSource.Fetch && providerURL == "" {
+				var err error
+				providerURL, err = provider.ProviderURL(cmd.Context(), kubeConfigFlags, opts.Name, opts.Namespace)
 				if err != nil {
-					return fmt.Errorf("failed to read CA certificate file '%s': %v", filePath, err)
+					return err
 				}
-				opts.CACert = string(fileContent)
+			}
+			cacertSource.CACert = opts.CACert
+			var err error
+			opts.CACert, err = providerutil.ResolveCACert(cmd.Context(), cacertSource, providerURL, bugreport.Interactive(), os.Stdin, os.Stderr)
+			if err != nil {
+				return err
 			}
 
 			// Set flag change tracking
-			opts.InsecureSkipTLSChanged = cmd.Flag("provider-insecure-skip-tls").Changed
 			opts.UseVddkAioOptimizationChanged = cmd.Flag("use-vddk-aio-optimization").Changed
 			opts.EsxiCloneMethod = esxiCloneMethod.GetValue()
 
@@ -72,8 +112,15 @@ func NewProviderCmd(kubeConfigFlags *genericclioptions.ConfigFlags) *cobra.Comma
 	cmd.Flags().StringVarP(&opts.Username, "username", "u", "", "Provider credentials username")
 	cmd.Flags().StringVarP(&opts.Password, "password", "p", "", "Provider credentials password")
 	cmd.Flags().StringVar(&opts.CACert, "cacert", "", "Provider CA certificate (use @filename to load from file)")
+	cmd.Flags().StringVar(&cacertSource.File, "cacert-file", "", "File with the provider CA certificate bundle (PEM)")
+	cmd.Flags().BoolVar(&cacertSource.Fetch, "fetch-cacert", false, "Fetch the certificate presented by the provider URL, show its fingerprint and pin it as the provider CA once confirmed")
+	cmd.Flags().StringVar(&cacertSource.Fingerprint, "cacert-fingerprint", "", "Expected SHA-256 fingerprint of the certificate fetched by --fetch-cacert, confirming it without a prompt")
 	flags.ExplicitBoolVar(cmd.Flags(), &opts.InsecureSkipTLS, "provider-insecure-skip-tls", false, "Skip TLS verification when connecting to the provider (true/false)")
 
+	// Credential rotation flags
+	cmd.Flags().BoolVar(&opts.RotateCredentials, "rotate-credentials", false, "Update the provider secret with new credentials and wait for the provider to become Ready")
+	cmd.Flags().DurationVar(&opts.WaitTimeout, "wait-timeout", provider.DefaultRotateWaitTimeout, "Maximum time to wait for the provider to become Ready after --rotate-credentials")
+
 	// OpenShift specific flags
 	cmd.Flags().StringVarP(&opts.Token, "provider-token", "T", "", "Provider authentication token")
 
@@ -97,6 +144,11 @@ func NewProviderCmd(kubeConfigFlags *genericclioptions.ConfigFlags) *cobra.Comma
 
 	// EC2 specific flags
 	cmd.Flags().StringVar(&opts.EC2Region, "ec2-region", "", "AWS region where source EC2 instances are located")
+	cmd.Flags().StringVar(&opts.Username, "access-key-id", "", "AWS access key ID (alias for --username)")
+	cmd.Flags().StringVar(&opts.Password, "secret-access-key", "", "AWS secret access key (alias for --password)")
+	// The aliases share the variables of --username and --password, so only one of each pair may be set
+	cmd.MarkFlagsMutuallyExclusive("username", "access-key-id")
+	cmd.MarkFlagsMutuallyExclusive("password", "secret-access-key")
 	cmd.Flags().StringVar(&opts.EC2TargetRegion, "target-region", "", "Target region for migrations (defaults to provider region)")
 	cmd.Flags().StringVar(&opts.EC2TargetAZ, "target-az", "", "Target availability zone for migrations (auto-detected from worker nodes if omitted)")
 	cmd.Flags().StringVar(&opts.EC2TargetAccessKeyID, "target-access-key-id", "", "Target AWS account access key ID (for cross-account migrations)")
@@ -118,5 +170,8 @@ func NewProviderCmd(kubeConfigFlags *genericclioptions.ConfigFlags) *cobra.Comma
 		return esxiCloneMethod.GetValidValues(), cobra.ShellCompDirectiveNoFileComp
 	})
 
+	flags.AddDryRunFlag(cmd, flags.NewDryRunFlag(), "Print the patched resource instead of changing it (client), or submit the patch with server-side dry run to validate it without persisting (server)")
+	flags.AddShowDiffFlag(cmd)
+
 	return cmd
 }
@@ -0,0 +1,87 @@
+package query
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/help"
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/query/build"
+	"github.com/yaacov/kubectl-mtv/pkg/util/client"
+	"github.com/yaacov/kubectl-mtv/pkg/util/completion"
+)
+
+// NewBuildCmd creates the interactive query build command
+func NewBuildCmd(kubeConfigFlags *genericclioptions.ConfigFlags) *cobra.Command {
+	var provider string
+	var providerType string
+	var resource string
+
+	cmd := &cobra.Command{
+		Use:   "build",
+		Short: "Build a TSL query field by field",
+		Long: `Build a TSL query field by field.
+
+The command asks for the conditions of the query one at a time: a field, an
+operator and a value, then how to combine it with the next condition. Fields are
+checked against the inventory schema of the provider type (see 'help tsl'), so a
+misspelled field is asked again with suggestions; enter ? to list the fields.
+The ordering field and the limit are asked last.
+
+The questions are written to stderr and the final query to stdout, so the query
+can be captured in a variable and passed to --query.`,
+		Example: `  # Build a VM query for the type of a provider
+  kubectl-mtv query build --provider vsphere-prod
+
+  # Build a network query for a provider type, without a cluster
+  kubectl-mtv query build --type ovirt --resource network
+
+  # Use the built query
+  q=$(kubectl-mtv query build --provider vsphere-prod)
+  kubectl-mtv get inventory vms --provider vsphere-prod --query "$q"`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if (provider == "") == (providerType == "") {
+				return errors.New("specify exactly one of --provider or --type")
+			}
+			if resource != help.SchemaResourceVM && resource != help.SchemaResourceNetwork {
+				return fmt.Errorf("invalid --resource '%s': must be %s or %s", resource, help.SchemaResourceVM, help.SchemaResourceNetwork)
+			}
+
+			namespace := client.ResolveNamespace(kubeConfigFlags)
+			schema, err := build.ResolveSchema(cmd.Context(), kubeConfigFlags, providerType, provider, namespace)
+			if err != nil {
+				return err
+			}
+
+			q, err := build.Build(build.Options{Schema: schema, Resource: resource, In: os.Stdin, Out: os.Stderr})
+			if err != nil {
+				return err
+			}
+			fmt.Println(q)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&provider, "provider", "p", "", "Provider whose type selects the query fields")
+	cmd.Flags().StringVar(&providerType, "type", "", "Provider type whose query fields are used (vsphere, ovirt, openstack, ec2)")
+	cmd.Flags().StringVar(&resource, "resource", help.SchemaResourceVM, "Queried inventory resource: vm or network")
+
+	_ = cmd.RegisterFlagCompletionFunc("provider", completion.ProviderNameCompletion(kubeConfigFlags))
+	_ = cmd.RegisterFlagCompletionFunc("type", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		var types []string
+		for _, s := range help.InventorySchemas() {
+			types = append(types, s.Provider)
+		}
+		return types, cobra.ShellCompDirectiveNoFileComp
+	})
+	_ = cmd.RegisterFlagCompletionFunc("resource", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{help.SchemaResourceVM, help.SchemaResourceNetwork}, cobra.ShellCompDirectiveNoFileComp
+	})
+
+	return cmd
+}
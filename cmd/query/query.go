@@ -0,0 +1,19 @@
+package query
+
+import (
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+// NewQueryCmd creates the query command with all its subcommands
+func NewQueryCmd(kubeConfigFlags *genericclioptions.ConfigFlags) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "query",
+		Short:        "Compose TSL queries for --query flags",
+		Long:         `Compose TSL queries for the --query flags of the inventory commands`,
+		SilenceUsage: true,
+	}
+
+	cmd.AddCommand(NewBuildCmd(kubeConfigFlags))
+	return cmd
+}
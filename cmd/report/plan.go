@@ -0,0 +1,87 @@
+package report
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/yaacov/kubectl-mtv/cmd/get"
+	plan "github.com/yaacov/kubectl-mtv/pkg/cmd/report/plan"
+	"github.com/yaacov/kubectl-mtv/pkg/util/client"
+	"github.com/yaacov/kubectl-mtv/pkg/util/completion"
+	"github.com/yaacov/kubectl-mtv/pkg/util/flags"
+	"github.com/yaacov/kubectl-mtv/pkg/util/upload"
+)
+
+// NewPlanCmd creates the plan report command
+func NewPlanCmd(kubeConfigFlags *genericclioptions.ConfigFlags, globalConfig get.GlobalConfigGetter) *cobra.Command {
+	var name string
+	var format string
+	var file string
+	var uploadURL string
+
+	cmd := &cobra.Command{
+		Use:   "plan",
+		Short: "Generate a post-migration report for a plan",
+		Long: `Generate a migration report for a plan, suitable for handing to stakeholders.
+
+The report combines the plan specification, the result, start and completion time
+and duration of each VM, the data transferred and the size of the DataVolumes created
+for its disks, and the warnings and failures reported by the plan and its VMs. It is
+based on the plan's latest migration.
+
+Formats: markdown (default), html (a standalone page), and json.
+
+Use --upload s3://bucket/path to upload the report to S3 or S3-compatible storage as
+s3://bucket/path/<plan-name>-report.<md|html|json>. Credentials and region are read from
+the standard AWS environment variables and shared config files; set AWS_ENDPOINT_URL_S3
+(or AWS_ENDPOINT_URL) for S3-compatible endpoints. An uploaded report is not printed.`,
+		Example: `  # Print a markdown report
+  kubectl-mtv report plan --name my-migration
+
+  # Save an HTML report to share
+  kubectl-mtv report plan --name my-migration --format html --file my-migration.html
+
+  # Machine-readable report
+  kubectl-mtv report plan --name my-migration --format json
+
+  # Upload an HTML report to shared storage
+  kubectl-mtv report plan --name my-migration --format html --upload s3://mtv-evidence/reports`,
+		Args:         cobra.MaximumNArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := flags.ResolveNameArg(&name, args); err != nil {
+				return err
+			}
+			if name == "" {
+				return fmt.Errorf("--name is required")
+			}
+
+			var uploader *upload.S3Uploader
+			if uploadURL != "" {
+				var err error
+				if uploader, err = upload.NewS3Uploader(uploadURL); err != nil {
+					return err
+				}
+			}
+
+			cfg := globalConfig.GetKubeConfigFlags()
+			namespace := client.ResolveNamespace(cfg)
+			return plan.Write(cmd.Context(), cfg, name, namespace, format, file, uploader, globalConfig.GetUseUTC())
+		},
+	}
+
+	cmd.Flags().StringVarP(&name, "name", "M", "", "Plan name")
+	flags.MarkRequiredForMCP(cmd, "name")
+	cmd.Flags().StringVar(&format, "format", "markdown", "Report format (markdown, html, json)")
+	cmd.Flags().StringVarP(&file, "file", "f", "", "Write the report to a file instead of stdout")
+	cmd.Flags().StringVar(&uploadURL, "upload", "", "Upload the report to object storage (s3://bucket/path)")
+
+	_ = cmd.RegisterFlagCompletionFunc("name", completion.PlanNameCompletion(kubeConfigFlags))
+	_ = cmd.RegisterFlagCompletionFunc("format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"markdown", "html", "json"}, cobra.ShellCompDirectiveNoFileComp
+	})
+
+	return cmd
+}
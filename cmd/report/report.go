@@ -0,0 +1,27 @@
+package report
+
+import (
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/yaacov/kubectl-mtv/cmd/get"
+)
+
+// NewReportCmd creates the report command with all its subcommands
+func NewReportCmd(kubeConfigFlags *genericclioptions.ConfigFlags, globalConfig get.GlobalConfigGetter) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "report",
+		Short:        "Generate reports",
+		Long:         `Generate shareable reports about MTV resources`,
+		SilenceUsage: true,
+	}
+
+	planCmd := NewPlanCmd(kubeConfigFlags, globalConfig)
+	planCmd.Aliases = []string{"plans"}
+	cmd.AddCommand(planCmd)
+
+	retrospectiveCmd := NewRetrospectiveCmd(kubeConfigFlags, globalConfig)
+	retrospectiveCmd.Aliases = []string{"retro"}
+	cmd.AddCommand(retrospectiveCmd)
+	return cmd
+}
@@ -0,0 +1,82 @@
+package report
+
+import (
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/yaacov/kubectl-mtv/cmd/get"
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/report/retrospective"
+	"github.com/yaacov/kubectl-mtv/pkg/util/client"
+	"github.com/yaacov/kubectl-mtv/pkg/util/flags"
+	"github.com/yaacov/kubectl-mtv/pkg/util/upload"
+)
+
+// NewRetrospectiveCmd creates the retrospective report command
+func NewRetrospectiveCmd(kubeConfigFlags *genericclioptions.ConfigFlags, globalConfig get.GlobalConfigGetter) *cobra.Command {
+	var since string
+	var outputFormat string
+	var uploadURL string
+
+	cmd := &cobra.Command{
+		Use:   "retrospective",
+		Short: "Aggregate completed migrations into statistics for planning the next waves",
+		Long: `Aggregate the migrations completed in a time window into statistics, to tune the next waves.
+
+The retrospective reads the Migration resources already in the cluster and reports:
+  - the median and maximum duration of succeeded VMs by disk size
+    (< 50 GiB, 50-200 GiB, 200 GiB-1 TiB, >= 1 TiB)
+  - the failure causes of failed VMs, most frequent first
+  - the disk transfer throughput percentiles (p50, p90) per target storage class,
+    taken from the plan's storage map
+
+Only VMs that finished (succeeded, failed or canceled) are counted. Migrations whose
+plan or storage map were deleted are reported under the "unknown" storage class.
+
+Use --upload s3://bucket/path to upload the retrospective to S3 or S3-compatible storage
+as s3://bucket/path/retrospective-<timestamp>.<txt|md|json> instead of printing it, using
+the standard AWS environment variables and shared config files for credentials.`,
+		Example: `  # Statistics of the migrations completed in the last 30 days
+  kubectl-mtv report retrospective --since 30d
+
+  # Last week, across all namespaces
+  kubectl-mtv report retrospective --since 7d -A
+
+  # Markdown to paste into a wave review
+  kubectl-mtv report retrospective --since 30d -o markdown
+
+  # Machine-readable statistics
+  kubectl-mtv report retrospective -o json | jq '.throughput'
+
+  # Upload the wave review to shared storage
+  kubectl-mtv report retrospective --since 30d -o markdown --upload s3://mtv-evidence/retrospectives`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			window, err := flags.ParseRelativeDuration(since)
+			if err != nil {
+				return err
+			}
+
+			var uploader *upload.S3Uploader
+			if uploadURL != "" {
+				if uploader, err = upload.NewS3Uploader(uploadURL); err != nil {
+					return err
+				}
+			}
+
+			cfg := globalConfig.GetKubeConfigFlags()
+			namespace := client.ResolveNamespaceWithAllFlag(cfg, globalConfig.GetAllNamespaces())
+			return retrospective.Print(cmd.Context(), cfg, namespace, window, outputFormat, uploader, globalConfig.GetUseUTC())
+		},
+	}
+
+	cmd.Flags().StringVar(&since, "since", "30d", "Include migrations completed within this duration (e.g. 7d, 30d, 12h)")
+	cmd.Flags().StringVarP(&outputFormat, "output", "o", "table", "Output format (table, markdown, json)")
+	cmd.Flags().StringVar(&uploadURL, "upload", "", "Upload the retrospective to object storage (s3://bucket/path) instead of printing it")
+
+	_ = cmd.RegisterFlagCompletionFunc("output", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"table", "markdown", "json"}, cobra.ShellCompDirectiveNoFileComp
+	})
+
+	return cmd
+}
@@ -0,0 +1,73 @@
+package seal
+
+import (
+	"errors"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/seal/plan"
+	"github.com/yaacov/kubectl-mtv/pkg/util/client"
+	"github.com/yaacov/kubectl-mtv/pkg/util/completion"
+	"github.com/yaacov/kubectl-mtv/pkg/util/flags"
+)
+
+// NewPlanCmd creates the plan seal command
+func NewPlanCmd(kubeConfigFlags *genericclioptions.ConfigFlags) *cobra.Command {
+	var planNames []string
+	var unseal bool
+
+	cmd := &cobra.Command{
+		Use:   "plan",
+		Short: "Seal the approved spec of one or more migration plans",
+		Long: `Seal the approved spec of one or more migration plans.
+
+Sealing stores a checksum of the current plan spec in the plan annotations.
+'start plan' compares the spec with the checksum and warns when the plan was
+edited after it was sealed, or refuses to start it with --strict. This catches
+last-minute changes that were not reviewed.
+
+Seal the plan again to approve later changes, or use --unseal to remove the seal.`,
+		Example: `  # Seal a reviewed plan
+  kubectl-mtv seal plan --name my-migration
+
+  # Seal multiple plans
+  kubectl-mtv seal plans --name plan1,plan2,plan3
+
+  # Refuse to start the plan if it changed after it was sealed
+  kubectl-mtv start plan --name my-migration --strict
+
+  # Remove the seal
+  kubectl-mtv seal plan --name my-migration --unseal`,
+		Args:         cobra.MaximumNArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := flags.ResolveNamesArg(&planNames, args); err != nil {
+				return err
+			}
+			if len(planNames) == 0 {
+				return errors.New("must specify --name")
+			}
+
+			// Resolve the appropriate namespace based on context and flags
+			namespace := client.ResolveNamespace(kubeConfigFlags)
+
+			for _, name := range planNames {
+				if err := plan.Seal(cmd.Context(), kubeConfigFlags, name, namespace, unseal, cmd.OutOrStdout()); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringSliceVarP(&planNames, "name", "M", nil, "Plan name(s) to seal (comma-separated, e.g. \"plan1,plan2\")")
+	cmd.Flags().StringSliceVar(&planNames, "names", nil, "Alias for --name")
+	_ = cmd.Flags().MarkHidden("names")
+	cmd.Flags().BoolVar(&unseal, "unseal", false, "Remove the seal instead of sealing the current spec")
+
+	cmd.ValidArgsFunction = completion.NameArgCompletion(completion.PlanNameCompletion(kubeConfigFlags))
+	_ = cmd.RegisterFlagCompletionFunc("name", completion.PlanNameCompletion(kubeConfigFlags))
+
+	return cmd
+}
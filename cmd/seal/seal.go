@@ -0,0 +1,22 @@
+package seal
+
+import (
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+// NewSealCmd creates the seal command with all its subcommands
+func NewSealCmd(kubeConfigFlags *genericclioptions.ConfigFlags) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "seal",
+		Short:        "Seal resources",
+		Long:         `Seal various MTV resources`,
+		SilenceUsage: true,
+	}
+
+	// Add plan subcommand with plural alias
+	planCmd := NewPlanCmd(kubeConfigFlags)
+	planCmd.Aliases = []string{"plans"}
+	cmd.AddCommand(planCmd)
+	return cmd
+}
@@ -0,0 +1,106 @@
+package settings
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/yaacov/kubectl-mtv/pkg/util/client"
+	"github.com/yaacov/kubectl-mtv/pkg/util/config"
+)
+
+// NewDefaultNamespaceCmd creates the 'settings default-namespace' subcommand.
+func NewDefaultNamespaceCmd(kubeConfigFlags *genericclioptions.ConfigFlags) *cobra.Command {
+	var unset bool
+
+	cmd := &cobra.Command{
+		Use:   "default-namespace [NAMESPACE]",
+		Short: "Set the default namespace for MTV resources",
+		Long: `Set the namespace used for MTV resources when --namespace is not given, instead of
+the namespace of the current kubeconfig context.
+
+Like telemetry, this is a local setting stored in the kubectl-mtv user config
+directory, next to the local settings of each kubeconfig context; it does not
+change the ForkliftController.
+
+The namespace of a command is chosen in this order:
+  1. the --namespace flag
+  2. the MTV_NAMESPACE environment variable
+  3. the namespace local setting of the kubeconfig context (settings set --local)
+  4. this setting
+  5. the namespace of the current kubeconfig context
+  6. "default"
+
+Run with -v=1 to log which one a command used.
+
+Examples:
+  # Show the default namespace and the namespace commands use now
+  kubectl mtv settings default-namespace
+
+  # Keep MTV resources in openshift-mtv regardless of the kubeconfig context
+  kubectl mtv settings default-namespace openshift-mtv
+
+  # Go back to the kubeconfig context namespace
+  kubectl mtv settings default-namespace --unset`,
+		Args:         cobra.MaximumNArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			switch {
+			case unset && len(args) > 0:
+				return fmt.Errorf("--unset does not take a namespace")
+			case unset:
+				if err := saveDefaultNamespace(""); err != nil {
+					return err
+				}
+				fmt.Println("Default namespace removed, commands use the kubeconfig context namespace.")
+				return nil
+			case len(args) > 0:
+				if err := saveDefaultNamespace(args[0]); err != nil {
+					return err
+				}
+				fmt.Printf("Default namespace set to '%s'.\n", args[0])
+				if namespace, source := client.ResolveNamespaceWithSource(kubeConfigFlags); source != client.NamespaceSourceSetting {
+					fmt.Printf("Note: commands use '%s' from the %s, which takes precedence.\n", namespace, source)
+				}
+				return nil
+			default:
+				return printDefaultNamespace(kubeConfigFlags)
+			}
+		},
+	}
+
+	cmd.Flags().BoolVar(&unset, "unset", false, "Remove the default namespace")
+
+	return cmd
+}
+
+// saveDefaultNamespace saves the default namespace in the settings file, an empty namespace
+// removes it
+func saveDefaultNamespace(namespace string) error {
+	file, err := config.LoadDefaults()
+	if err != nil {
+		return err
+	}
+	file.DefaultNamespace = namespace
+	return file.Save()
+}
+
+// printDefaultNamespace prints the saved default namespace and the namespace commands resolve
+func printDefaultNamespace(kubeConfigFlags *genericclioptions.ConfigFlags) error {
+	file, err := config.LoadDefaults()
+	if err != nil {
+		return err
+	}
+	saved := file.DefaultNamespace
+	if saved == "" {
+		saved = "(none)"
+	}
+	namespace, source := client.ResolveNamespaceWithSource(kubeConfigFlags)
+	path, _ := config.DefaultsPath()
+
+	fmt.Printf("Default namespace: %s\n", saved)
+	fmt.Printf("In use:            %s (from the %s)\n", namespace, source)
+	fmt.Printf("Settings:          %s\n", path)
+	return nil
+}
@@ -0,0 +1,85 @@
+package settings
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/settings"
+	"github.com/yaacov/kubectl-mtv/pkg/util/config"
+	"github.com/yaacov/kubectl-mtv/pkg/util/output"
+)
+
+// printLocalSettings prints the command defaults saved for the current kubeconfig context
+func printLocalSettings(kubeConfigFlags *genericclioptions.ConfigFlags, name, format string) error {
+	kubeContext, localSettings, err := settings.GetLocalSettings(kubeConfigFlags, name)
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case "json":
+		data, err := json.MarshalIndent(localSettings, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	case "yaml":
+		data, err := yaml.Marshal(localSettings)
+		if err != nil {
+			return fmt.Errorf("failed to marshal YAML: %w", err)
+		}
+		fmt.Print(string(data))
+		return nil
+	}
+
+	// A single setting prints just its value
+	if name != "" && format == "table" {
+		if len(localSettings) > 0 && localSettings[0].IsSet {
+			fmt.Println(localSettings[0].Value)
+		}
+		return nil
+	}
+
+	items := make([]map[string]interface{}, 0, len(localSettings))
+	for _, ls := range localSettings {
+		value := ls.Value
+		if !ls.IsSet {
+			value = "(not set)"
+		}
+		items = append(items, map[string]interface{}{
+			"setting":     ls.Name,
+			"value":       value,
+			"description": ls.Description,
+		})
+	}
+	printer := output.NewTablePrinter().
+		WithColumns(
+			output.Column{Title: "SETTING", Key: "setting"},
+			output.Column{Title: "VALUE", Key: "value"},
+			output.Column{Title: "DESCRIPTION", Key: "description"},
+		).
+		AddItems(items)
+	if format == "markdown" {
+		return printer.PrintMarkdown()
+	}
+	path, _ := config.DefaultsPath()
+	fmt.Printf("Context:  %s\nSettings: %s\n\n", kubeContext, path)
+	return printer.Print()
+}
+
+// localSettingCompletion completes the names of the local settings
+func localSettingCompletion(toComplete string) ([]string, cobra.ShellCompDirective) {
+	var completions []string
+	for _, name := range config.DefaultNames() {
+		if strings.HasPrefix(name, toComplete) {
+			completions = append(completions, name)
+		}
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
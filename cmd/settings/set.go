@@ -17,6 +17,7 @@ import (
 func NewSetCmd(kubeConfigFlags *genericclioptions.ConfigFlags, globalConfig GlobalConfigGetter) *cobra.Command {
 	var settingNames []string
 	var settingValues []string
+	var local bool
 
 	cmd := &cobra.Command{
 		Use:   "set",
@@ -29,6 +30,9 @@ to see all available settings and their current values.
 Multiple --setting/--value pairs can be specified to update several settings in a
 single Kubernetes patch operation, avoiding multiple reconciliation cycles.
 
+With --local, the settings are command defaults saved for the current kubeconfig
+context in the local settings file instead (see 'kubectl mtv settings get --local').
+
 Value types are automatically validated:
   - Boolean settings accept: true, false, yes, no, 1, 0
   - Integer settings accept: numeric values
@@ -52,10 +56,25 @@ Examples:
                            --setting mcp_server_lightspeed_set_mcp_gate --value true
 
   # Set a value starting with -- (use -- to stop flag parsing)
-  kubectl mtv settings set --setting virt_v2v_extra_args --value --machine-readable`,
+  kubectl mtv settings set --setting virt_v2v_extra_args --value --machine-readable
+
+  # Default to openshift-mtv and JSON output whenever the current kubeconfig context is used
+  kubectl mtv settings set --local --setting namespace --value openshift-mtv \
+                           --setting output --value json`,
 		Args:         cobra.NoArgs,
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if local {
+				kubeContext, err := settings.SetLocalSettings(kubeConfigFlags, settingNames, settingValues)
+				if err != nil {
+					return err
+				}
+				for i, name := range settingNames {
+					fmt.Printf("Local setting '%s' set to '%s' for context '%s'\n", name, settingValues[i], kubeContext)
+				}
+				return nil
+			}
+
 			if len(settingNames) != len(settingValues) {
 				return fmt.Errorf("number of --setting flags (%d) must match number of --value flags (%d)", len(settingNames), len(settingValues))
 			}
@@ -83,6 +102,7 @@ Examples:
 
 	cmd.Flags().StringArrayVar(&settingNames, "setting", nil, "Setting name (can be specified multiple times)")
 	cmd.Flags().StringArrayVar(&settingValues, "value", nil, "Setting value (can be specified multiple times)")
+	cmd.Flags().BoolVar(&local, "local", false, "Save command defaults for the current kubeconfig context instead of ForkliftController settings")
 
 	_ = cmd.MarkFlagRequired("setting")
 	_ = cmd.MarkFlagRequired("value")
@@ -95,6 +115,9 @@ Examples:
 
 // setSettingCompletion provides completion for the --setting flag.
 func setSettingCompletion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if local, _ := cmd.Flags().GetBool("local"); local {
+		return localSettingCompletion(toComplete)
+	}
 	var completions []string
 	for name := range settings.SupportedSettings {
 		if strings.HasPrefix(name, toComplete) {
@@ -113,6 +136,15 @@ func setValueCompletion(cmd *cobra.Command, args []string, toComplete string) ([
 	}
 	// Use the last --setting value for context-sensitive completion
 	lastSettingName := settingNameList[len(settingNameList)-1]
+	if local, _ := cmd.Flags().GetBool("local"); local {
+		switch lastSettingName {
+		case "output":
+			return []string{"table", "wide", "json", "yaml", "markdown"}, cobra.ShellCompDirectiveNoFileComp
+		case "utc":
+			return []string{"true", "false"}, cobra.ShellCompDirectiveNoFileComp
+		}
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
 	def := settings.GetSettingDefinition(lastSettingName)
 	if def == nil {
 		return nil, cobra.ShellCompDirectiveNoFileComp
@@ -59,7 +59,16 @@ Examples:
   # Set a value
   kubectl mtv settings set --setting vddk_image --value quay.io/myorg/vddk:8.0
   kubectl mtv settings set --setting controller_max_vm_inflight --value 30
-  kubectl mtv settings set --setting feature_ocp_live_migration --value true`,
+  kubectl mtv settings set --setting feature_ocp_live_migration --value true
+
+  # Opt in to anonymous usage telemetry (local setting, off by default)
+  kubectl mtv settings telemetry on
+
+  # Keep MTV resources in openshift-mtv regardless of the kubeconfig context (local setting)
+  kubectl mtv settings default-namespace openshift-mtv
+
+  # Save command defaults for the current kubeconfig context (local setting)
+  kubectl mtv settings set --local --setting inventory-url --value https://inventory.example.com`,
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// Default action: show all settings
@@ -96,6 +105,8 @@ Examples:
 	cmd.AddCommand(newGetCmd(kubeConfigFlags, globalConfig))
 	cmd.AddCommand(NewSetCmd(kubeConfigFlags, globalConfig))
 	cmd.AddCommand(NewUnsetCmd(kubeConfigFlags, globalConfig))
+	cmd.AddCommand(NewTelemetryCmd())
+	cmd.AddCommand(NewDefaultNamespaceCmd(kubeConfigFlags))
 
 	return cmd
 }
@@ -105,6 +116,7 @@ func newGetCmd(kubeConfigFlags *genericclioptions.ConfigFlags, globalConfig Glob
 	outputFormatFlag := flags.NewOutputFormatTypeFlag()
 	var allSettings bool
 	var settingName string
+	var local bool
 
 	cmd := &cobra.Command{
 		Use:   "get",
@@ -117,6 +129,10 @@ If a setting name is provided, only that setting's value is shown.
 Use --all to see all available ForkliftController settings, including advanced
 options for controller, inventory, API, validation, and other components.
 
+Use --local to see the command defaults saved for the current kubeconfig context
+instead. They fill in flags not given on the command line, before any command runs;
+command line flags and MTV_* environment variables take precedence.
+
 Examples:
   # Get common settings
   kubectl mtv settings get
@@ -127,10 +143,17 @@ Examples:
   # Get a specific setting
   kubectl mtv settings get --setting vddk_image
   kubectl mtv settings get --setting controller_max_vm_inflight
-  kubectl mtv settings get --setting controller_container_limits_cpu`,
+  kubectl mtv settings get --setting controller_container_limits_cpu
+
+  # Get the command defaults of the current kubeconfig context
+  kubectl mtv settings get --local`,
 		Args:         cobra.NoArgs,
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if local {
+				return printLocalSettings(kubeConfigFlags, settingName, outputFormatFlag.GetValue())
+			}
+
 			ctx, cancel := context.WithTimeout(cmd.Context(), 30*time.Second)
 			defer cancel()
 
@@ -170,6 +193,7 @@ Examples:
 
 	// Add --all flag
 	cmd.Flags().BoolVar(&allSettings, "all", false, "Show all ForkliftController settings (not just common ones)")
+	cmd.Flags().BoolVar(&local, "local", false, "Show the command defaults of the current kubeconfig context instead of ForkliftController settings")
 
 	_ = cmd.RegisterFlagCompletionFunc("setting", getSettingCompletion)
 
@@ -178,6 +202,9 @@ Examples:
 
 // getSettingCompletion provides completion for the --setting flag in 'settings get'.
 func getSettingCompletion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if local, _ := cmd.Flags().GetBool("local"); local {
+		return localSettingCompletion(toComplete)
+	}
 	var completions []string
 	for name := range settings.SupportedSettings {
 		if strings.HasPrefix(name, toComplete) {
@@ -0,0 +1,129 @@
+package settings
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/yaacov/kubectl-mtv/pkg/util/telemetry"
+)
+
+// NewTelemetryCmd creates the 'settings telemetry' subcommand.
+func NewTelemetryCmd() *cobra.Command {
+	var endpoint string
+
+	cmd := &cobra.Command{
+		Use:       "telemetry [on|off|status]",
+		Short:     "Opt in to or out of anonymous usage telemetry",
+		ValidArgs: []string{"on", "off", "status"},
+		Long: `Opt in to or out of anonymous usage telemetry. Telemetry is off by default.
+
+Unlike the other settings, this is a local setting stored in the kubectl-mtv user
+config directory; it does not change the ForkliftController.
+
+When enabled, kubectl-mtv keeps aggregated counters only:
+  - command counts by command path and outcome (e.g. "get plan", success/error)
+  - command durations, bucketed (e.g. "1s-10s")
+  - migration outcomes observed by 'start wave' (succeeded/failed/canceled), with bucketed durations
+
+Arguments, flag values, resource names, namespaces, URLs and cluster details are
+never collected. Counters are sent at most once a day to the configured endpoint,
+together with a random client ID, the kubectl-mtv version, OS and architecture.
+Without an endpoint the counters stay on this machine; use 'status' to see them.
+
+Set KUBECTL_MTV_TELEMETRY=off or DO_NOT_TRACK=1 to disable telemetry for a session
+regardless of this setting.
+
+Examples:
+  # Show the current telemetry setting and pending counters
+  kubectl mtv settings telemetry
+
+  # Opt in and report to an endpoint
+  kubectl mtv settings telemetry on --endpoint https://telemetry.example.com/kubectl-mtv
+
+  # Opt out and delete locally collected counters
+  kubectl mtv settings telemetry off`,
+		Args:         cobra.MatchAll(cobra.MaximumNArgs(1), cobra.OnlyValidArgs),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			action := "status"
+			if len(args) > 0 {
+				action = args[0]
+			}
+			if endpoint != "" && action != "on" {
+				return fmt.Errorf("--endpoint can only be used with 'on'")
+			}
+
+			switch action {
+			case "on":
+				cfg, err := telemetry.Enable(endpoint)
+				if err != nil {
+					return err
+				}
+				fmt.Println("Telemetry enabled. Thank you for helping improve kubectl-mtv.")
+				if cfg.Endpoint == "" {
+					fmt.Println("No endpoint is configured, counters are only kept locally.")
+				}
+				return nil
+			case "off":
+				if err := telemetry.Disable(); err != nil {
+					return err
+				}
+				fmt.Println("Telemetry disabled, locally collected counters were removed.")
+				return nil
+			default:
+				return printTelemetryStatus()
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&endpoint, "endpoint", "", "URL that receives the aggregated telemetry reports (with 'on')")
+
+	return cmd
+}
+
+// printTelemetryStatus prints the telemetry setting and the counters not yet reported
+func printTelemetryStatus() error {
+	cfg, err := telemetry.LoadConfig()
+	if err != nil {
+		return err
+	}
+	_, active := telemetry.Active()
+
+	state := "off"
+	switch {
+	case active:
+		state = "on"
+	case cfg.Enabled:
+		state = fmt.Sprintf("off (disabled by %s or %s)", telemetry.EnvTelemetry, telemetry.EnvDoNotTrack)
+	}
+	endpoint := cfg.Endpoint
+	if env := os.Getenv(telemetry.EnvEndpoint); env != "" {
+		endpoint = env
+	}
+	if endpoint == "" {
+		endpoint = "(none, counters are kept locally)"
+	}
+	path, _ := telemetry.ConfigPath()
+
+	fmt.Printf("Telemetry: %s\n", state)
+	fmt.Printf("Endpoint:  %s\n", endpoint)
+	fmt.Printf("Settings:  %s\n", path)
+
+	if !cfg.Enabled {
+		return nil
+	}
+	keys, counts, err := telemetry.PendingCounts()
+	if err != nil {
+		return err
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	fmt.Println("\nPending counters:")
+	for _, key := range keys {
+		fmt.Printf("  %-60s %d\n", key, counts[key])
+	}
+	return nil
+}
@@ -16,6 +16,7 @@ import (
 // NewUnsetCmd creates the 'settings unset' subcommand.
 func NewUnsetCmd(kubeConfigFlags *genericclioptions.ConfigFlags, globalConfig GlobalConfigGetter) *cobra.Command {
 	var settingName string
+	var local bool
 
 	cmd := &cobra.Command{
 		Use:   "unset",
@@ -25,6 +26,9 @@ func NewUnsetCmd(kubeConfigFlags *genericclioptions.ConfigFlags, globalConfig Gl
 This removes the setting from the ForkliftController spec, causing the controller
 to use its default value instead.
 
+With --local, the command default saved for the current kubeconfig context is
+removed from the local settings file instead.
+
 Examples:
   # Remove the VDDK image setting (revert to default)
   kubectl mtv settings unset --setting vddk_image
@@ -33,10 +37,26 @@ Examples:
   kubectl mtv settings unset --setting virt_v2v_extra_args
 
   # Revert max concurrent VMs to default (20)
-  kubectl mtv settings unset --setting controller_max_vm_inflight`,
+  kubectl mtv settings unset --setting controller_max_vm_inflight
+
+  # Stop defaulting to JSON output for the current kubeconfig context
+  kubectl mtv settings unset --local --setting output`,
 		Args:         cobra.NoArgs,
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if local {
+				kubeContext, removed, err := settings.UnsetLocalSetting(kubeConfigFlags, settingName)
+				if err != nil {
+					return err
+				}
+				if removed {
+					fmt.Printf("Local setting '%s' removed for context '%s'\n", settingName, kubeContext)
+				} else {
+					fmt.Printf("Local setting '%s' is not set for context '%s'\n", settingName, kubeContext)
+				}
+				return nil
+			}
+
 			ctx, cancel := context.WithTimeout(cmd.Context(), 30*time.Second)
 			defer cancel()
 
@@ -61,6 +81,7 @@ Examples:
 	}
 
 	cmd.Flags().StringVar(&settingName, "setting", "", "Setting name")
+	cmd.Flags().BoolVar(&local, "local", false, "Remove a command default of the current kubeconfig context instead of a ForkliftController setting")
 	if err := cmd.MarkFlagRequired("setting"); err != nil {
 		_ = err
 	}
@@ -72,6 +93,9 @@ Examples:
 
 // unsetSettingCompletion provides completion for the --setting flag.
 func unsetSettingCompletion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if local, _ := cmd.Flags().GetBool("local"); local {
+		return localSettingCompletion(toComplete)
+	}
 	var completions []string
 	for name := range settings.SupportedSettings {
 		if strings.HasPrefix(name, toComplete) {
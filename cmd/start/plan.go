@@ -1,18 +1,24 @@
 package start
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"time"
 
 	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 
 	"github.com/yaacov/kubectl-mtv/cmd/get"
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/get/plan/targetlabels"
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/help"
+	seal "github.com/yaacov/kubectl-mtv/pkg/cmd/seal/plan"
 	"github.com/yaacov/kubectl-mtv/pkg/cmd/start/plan"
 	"github.com/yaacov/kubectl-mtv/pkg/util/client"
 	"github.com/yaacov/kubectl-mtv/pkg/util/completion"
 	"github.com/yaacov/kubectl-mtv/pkg/util/flags"
+	waitutil "github.com/yaacov/kubectl-mtv/pkg/util/wait"
 )
 
 // NewPlanCmd creates the plan start command
@@ -22,6 +28,14 @@ func NewPlanCmd(kubeConfigFlags *genericclioptions.ConfigFlags, globalConfig get
 	var dryRun bool
 	var outputFormat string
 	var planNames []string
+	var labelTargetVMs []string
+	var maxConcurrentVMs int
+	var wait bool
+	var waitFor string
+	var waitTimeout time.Duration
+	var strict bool
+	var sequential bool
+	var onFailure string
 
 	cmd := &cobra.Command{
 		Use:   "plan",
@@ -35,7 +49,26 @@ to 1 hour from the start time.
 The plan must be in a 'Ready' state to be started.
 
 Use --dry-run to output the Migration CR(s) to stdout instead of creating
-them in Kubernetes. This is useful for debugging, validation, and inspection.`,
+them in Kubernetes. This is useful for debugging, validation, and inspection.
+
+Use --wait to block until the migration finishes, printing progress to stderr.
+The command exits with a non-zero code when the migration fails or is canceled.
+Use --wait-for to block until another phase: Succeeded, Failed, Canceled,
+Running, or a VM pipeline step such as DiskTransfer or Cutover (reached when
+every VM still migrating started the step). --wait-timeout bounds the wait.
+
+Use --sequential to start the plans one after another: each plan is started only
+after the previous one succeeded. Plans are reordered so that plans listed in the
+kubectl-mtv/depends-on annotation of a plan (set with 'patch plan --depends-on')
+run first. With --on-failure abort (default) no further plans are started after a
+failure; with --on-failure continue only the plans depending on the failed plan
+are skipped. Plans that already succeeded are skipped and running plans are waited
+for, so running the same command again resumes an interrupted sequence.
+Without --sequential, a plan whose dependencies have not succeeded is not started.
+
+Plans sealed with 'seal plan' are checked before they are started: a warning is
+printed when the spec changed since it was sealed, and --strict refuses to start
+the plan instead.`,
 		Example: `  # Start a migration plan
   kubectl-mtv start plan --name my-migration
 
@@ -51,6 +84,27 @@ them in Kubernetes. This is useful for debugging, validation, and inspection.`,
   # Start warm migration with cutover in 2 hours (Linux)
   kubectl-mtv start plan --name my-migration --cutover "$(date -d '+2 hours' --iso-8601=sec)"
 
+  # Start and ensure migrated VMs carry labels (verified after migration)
+  kubectl-mtv start plan --name my-migration --label-target-vms "migrated-by=mtv,wave=7"
+
+  # Migrate at most 5 VMs at a time, in batches (waits until all batches finish)
+  kubectl-mtv start plan --name big-migration --max-concurrent-vms 5
+
+  # Start and block until the migration finishes, failing on a failed migration
+  kubectl-mtv start plan --name my-migration --wait --wait-timeout 4h
+
+  # Start a warm migration and block until the disk transfer started
+  kubectl-mtv start plan --name my-migration --wait-for DiskTransfer
+
+  # Start plans one after another, each after the previous one succeeded
+  kubectl-mtv start plans db-tier app-tier web-tier --sequential
+
+  # Keep going with independent plans when one fails
+  kubectl-mtv start plans --name db-tier,app-tier,reports --sequential --on-failure continue
+
+  # Refuse to start the plan if its spec changed after it was sealed
+  kubectl-mtv start plan --name my-migration --strict
+
   # Dry-run: output Migration CR to stdout (YAML format)
   kubectl-mtv start plan --name my-migration --dry-run
 
@@ -59,7 +113,7 @@ them in Kubernetes. This is useful for debugging, validation, and inspection.`,
 
   # Dry-run: output all Migration CRs in namespace
   kubectl-mtv start plans --all --dry-run`,
-		Args:         cobra.MaximumNArgs(1),
+		Args:         cobra.ArbitraryArgs,
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if err := flags.ResolveNamesArg(&planNames, args); err != nil {
@@ -118,12 +172,102 @@ them in Kubernetes. This is useful for debugging, validation, and inspection.`,
 				outputFormat = "yaml"
 			}
 
-			// Loop over each plan name and start it (dry-run is handled inside plan.Start)
+			var enforcedLabels map[string]string
+			if len(labelTargetVMs) > 0 {
+				if dryRun {
+					return fmt.Errorf("--label-target-vms cannot be used with --dry-run")
+				}
+				var err error
+				enforcedLabels, err = targetlabels.Parse(labelTargetVMs)
+				if err != nil {
+					return err
+				}
+			}
+
+			if cmd.Flags().Changed("wait-for") {
+				var err error
+				if waitFor, err = plan.ValidateWaitFor(waitFor); err != nil {
+					return err
+				}
+				wait = true
+			}
+			if cmd.Flags().Changed("wait-timeout") {
+				if waitTimeout <= 0 {
+					return fmt.Errorf("--wait-timeout must be positive, got %s", waitTimeout)
+				}
+				wait = true
+			}
+			if wait && dryRun {
+				return fmt.Errorf("--wait cannot be used with --dry-run")
+			}
+
+			if cmd.Flags().Changed("on-failure") && !sequential {
+				return fmt.Errorf("--on-failure requires --sequential")
+			}
+			if sequential {
+				if dryRun {
+					return fmt.Errorf("--sequential cannot be used with --dry-run")
+				}
+				if cmd.Flags().Changed("wait-for") {
+					return fmt.Errorf("--sequential waits for each plan to succeed and cannot be used with --wait-for")
+				}
+				var err error
+				if onFailure, err = plan.ValidateOnFailure(onFailure); err != nil {
+					return err
+				}
+			}
+
+			if cmd.Flags().Changed("max-concurrent-vms") {
+				if err := plan.ValidateMaxConcurrentVMs(cmd.Context(), cfg, maxConcurrentVMs); err != nil {
+					return err
+				}
+			}
+
+			// startPlan checks and starts one plan (dry-run is handled inside plan.Start)
+			startPlan := func(ctx context.Context, name string) error {
+				if err := seal.Verify(ctx, cfg, name, namespace, strict, cmd.ErrOrStderr()); err != nil {
+					return err
+				}
+				if len(enforcedLabels) > 0 {
+					if err := labelPlanTargetVMs(ctx, cfg, namespace, name, enforcedLabels); err != nil {
+						return err
+					}
+				}
+				limit, err := planVMLimit(ctx, cfg, namespace, name, maxConcurrentVMs)
+				if err != nil {
+					return err
+				}
+				if limit > 0 {
+					return plan.StartBatched(ctx, cfg, name, namespace, limit, cutoverTime, globalConfig.GetUseUTC(), dryRun, outputFormat)
+				}
+				return plan.Start(cfg, name, namespace, cutoverTime, globalConfig.GetUseUTC(), dryRun, outputFormat)
+			}
+
+			if sequential {
+				return plan.RunSequence(cmd.Context(), cfg, namespace, planNames, startPlan, plan.SequenceOptions{
+					OnFailure:   onFailure,
+					WaitTimeout: waitTimeout,
+					Out:         cmd.ErrOrStderr(),
+				})
+			}
+
+			// Loop over each plan name and start it
 			for _, name := range planNames {
-				if err := plan.Start(cfg, name, namespace, cutoverTime, globalConfig.GetUseUTC(), dryRun, outputFormat); err != nil {
+				if !dryRun {
+					if err := plan.CheckDependencies(cmd.Context(), cfg, name, namespace); err != nil {
+						return err
+					}
+				}
+				if err := startPlan(cmd.Context(), name); err != nil {
 					return fmt.Errorf("failed to start plan %q: %w", name, err)
 				}
 			}
+
+			if wait {
+				return waitutil.ForEach(planNames, waitTimeout, cmd.ErrOrStderr(), "plans", "did not reach "+waitPhaseName(waitFor), func(name string, timeout time.Duration) error {
+					return plan.Wait(cmd.Context(), cfg, name, namespace, plan.WaitOptions{For: waitFor, Timeout: timeout, Out: cmd.ErrOrStderr()})
+				})
+			}
 			return nil
 		},
 	}
@@ -134,9 +278,26 @@ them in Kubernetes. This is useful for debugging, validation, and inspection.`,
 	cmd.Flags().StringVarP(&cutoverTimeStr, "cutover", "c", "", "Cutover time in ISO8601 format (e.g., 2023-12-31T15:30:00Z, '$(date -d \"+1 hour\" --iso-8601=sec)' ). If not provided, defaults to 1 hour from now.")
 	cmd.Flags().BoolVar(&all, "all", false, "Start all migration plans in the namespace")
 	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Output Migration CR(s) to stdout instead of creating them")
+	cmd.Flags().IntVar(&maxConcurrentVMs, "max-concurrent-vms", 0, "Maximum VMs migrating at the same time; larger plans are migrated in batches and the command waits for them (overrides the plan setting)")
+	cmd.Flags().StringSliceVar(&labelTargetVMs, "label-target-vms", nil, "Labels the migrated VirtualMachines must carry, added to the plan target labels and verified after migration (e.g., migrated-by=mtv,wave=7)")
+	cmd.Flags().BoolVar(&wait, "wait", false, "Block until the migration finishes; exits non-zero when it fails or is canceled")
+	cmd.Flags().StringVar(&waitFor, "wait-for", "", "Block until the migration reaches a phase: Completed, Succeeded, Failed, Canceled, Running, or a pipeline step such as DiskTransfer (implies --wait)")
+	cmd.Flags().DurationVar(&waitTimeout, "wait-timeout", 0, "Maximum time to wait with --wait or --wait-for, e.g. 4h (default no limit)")
+	cmd.Flags().BoolVar(&sequential, "sequential", false, "Start the plans one after another, each after the previous one and its dependencies succeeded; running the command again resumes the sequence")
+	cmd.Flags().StringVar(&onFailure, "on-failure", plan.FailureAbort, "What --sequential does when a plan fails: abort (start no further plans) or continue (skip only the plans depending on it)")
+	cmd.Flags().BoolVar(&strict, "strict", false, "Refuse to start sealed plans whose spec changed since they were sealed, instead of warning")
 	cmd.Flags().StringVarP(&outputFormat, "output", "o", "", "Output format for dry-run (json, yaml). Defaults to yaml when --dry-run is used")
 
+	help.MarkMCPHidden(cmd, "wait", "wait-for", "wait-timeout")
+
+	cmd.ValidArgsFunction = completion.NameArgCompletion(completion.PlanNameCompletion(kubeConfigFlags))
 	_ = cmd.RegisterFlagCompletionFunc("name", completion.PlanNameCompletion(kubeConfigFlags))
+	_ = cmd.RegisterFlagCompletionFunc("on-failure", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{plan.FailureAbort, plan.FailureContinue}, cobra.ShellCompDirectiveNoFileComp
+	})
+	_ = cmd.RegisterFlagCompletionFunc("wait-for", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{plan.WaitForCompleted, "Succeeded", "Failed", "Canceled", "Running", "DiskTransfer", "Cutover", "ImageConversion"}, cobra.ShellCompDirectiveNoFileComp
+	})
 
 	// Add completion for output format flag
 	if err := cmd.RegisterFlagCompletionFunc("output", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -148,3 +309,41 @@ them in Kubernetes. This is useful for debugging, validation, and inspection.`,
 
 	return cmd
 }
+
+// labelPlanTargetVMs adds the enforced target VM labels to a plan before it is started
+func labelPlanTargetVMs(ctx context.Context, cfg *genericclioptions.ConfigFlags, namespace, name string, labels map[string]string) error {
+	c, err := client.GetDynamicClient(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to get client: %v", err)
+	}
+	p, err := c.Resource(client.PlansGVR).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get plan '%s': %v", name, err)
+	}
+	return targetlabels.ApplyToPlan(ctx, c, p, labels)
+}
+
+// planVMLimit returns the VM concurrency to enforce for a plan: the flag value when set,
+// otherwise the value recorded on the plan by create plan
+func planVMLimit(ctx context.Context, cfg *genericclioptions.ConfigFlags, namespace, name string, flagValue int) (int, error) {
+	if flagValue > 0 {
+		return flagValue, nil
+	}
+	c, err := client.GetDynamicClient(cfg)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get client: %v", err)
+	}
+	p, err := c.Resource(client.PlansGVR).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get plan '%s': %v", name, err)
+	}
+	return plan.MaxConcurrentVMs(p), nil
+}
+
+// waitPhaseName returns the phase waited for, --wait waits for completion
+func waitPhaseName(phase string) string {
+	if phase == "" {
+		return plan.WaitForCompleted
+	}
+	return phase
+}
@@ -20,5 +20,10 @@ func NewStartCmd(kubeConfigFlags *genericclioptions.ConfigFlags, globalConfig ge
 	planCmd := NewPlanCmd(kubeConfigFlags, globalConfig)
 	planCmd.Aliases = []string{"plans"}
 	cmd.AddCommand(planCmd)
+
+	// Add wave subcommand with plural alias
+	waveCmd := NewWaveCmd(kubeConfigFlags, globalConfig)
+	waveCmd.Aliases = []string{"waves"}
+	cmd.AddCommand(waveCmd)
 	return cmd
 }
@@ -0,0 +1,98 @@
+package start
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/yaacov/kubectl-mtv/cmd/get"
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/start/wave"
+	"github.com/yaacov/kubectl-mtv/pkg/util/client"
+	"github.com/yaacov/kubectl-mtv/pkg/util/completion"
+	"github.com/yaacov/kubectl-mtv/pkg/util/flags"
+)
+
+// NewWaveCmd creates the wave start command
+func NewWaveCmd(kubeConfigFlags *genericclioptions.ConfigFlags, globalConfig get.GlobalConfigGetter) *cobra.Command {
+	var name string
+	var concurrency int
+	var now bool
+	var continueOnError bool
+	var cutoverTimeStr string
+
+	cmd := &cobra.Command{
+		Use:   "wave",
+		Short: "Start a migration wave",
+		Long: `Start the migration plans of a wave and wait for them to finish.
+
+Sequential waves start each plan after the previous one has finished. Parallel
+waves start plans together, with at most --concurrency plans running at a time.
+Plans that have already succeeded are skipped, so a failed wave can be started again.
+
+If the wave has a scheduled start time in the future, the command waits until
+then; use --now to start immediately. By default no further plans are started
+after a plan fails; use --continue-on-error to run the remaining plans.
+
+Use 'get wave' from another terminal to follow the aggregated progress.`,
+		Example: `  # Start a wave and wait for all of its plans
+  kubectl-mtv start wave --name wave-1
+
+  # Start a scheduled wave right away
+  kubectl-mtv start wave --name weekend --now
+
+  # Run a parallel wave with at most three plans at a time
+  kubectl-mtv start wave --name wave-2 --concurrency 3
+
+  # Keep going when a plan fails
+  kubectl-mtv start wave --name wave-1 --continue-on-error`,
+		Args:         cobra.MaximumNArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := flags.ResolveNameArg(&name, args); err != nil {
+				return err
+			}
+			if name == "" {
+				return fmt.Errorf("--name is required")
+			}
+			if concurrency < 0 {
+				return fmt.Errorf("--concurrency must not be negative")
+			}
+
+			var cutoverTime *time.Time
+			if cutoverTimeStr != "" {
+				t, err := time.Parse(time.RFC3339, cutoverTimeStr)
+				if err != nil {
+					return fmt.Errorf("failed to parse cutover time: %v", err)
+				}
+				cutoverTime = &t
+			}
+
+			cfg := globalConfig.GetKubeConfigFlags()
+			opts := wave.StartWaveOptions{
+				Name:            name,
+				Namespace:       client.ResolveNamespace(cfg),
+				ConfigFlags:     cfg,
+				Concurrency:     concurrency,
+				IgnoreSchedule:  now,
+				ContinueOnError: continueOnError,
+				CutoverTime:     cutoverTime,
+				UseUTC:          globalConfig.GetUseUTC(),
+			}
+
+			return wave.Start(cmd.Context(), opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&name, "name", "M", "", "Wave name")
+	cmd.Flags().IntVar(&concurrency, "concurrency", 0, "Override the maximum number of plans running at the same time (parallel waves only)")
+	cmd.Flags().BoolVar(&now, "now", false, "Start immediately, ignoring the wave's scheduled start time")
+	cmd.Flags().BoolVar(&continueOnError, "continue-on-error", false, "Keep starting plans after a plan fails")
+	cmd.Flags().StringVarP(&cutoverTimeStr, "cutover", "c", "", "Cutover time for warm plans in ISO8601 format (e.g., 2026-12-31T15:30:00Z). If not provided, defaults to 1 hour after each plan starts.")
+	flags.MarkRequiredForMCP(cmd, "name")
+
+	_ = cmd.RegisterFlagCompletionFunc("name", completion.WaveNameCompletion(kubeConfigFlags))
+
+	return cmd
+}
@@ -0,0 +1,84 @@
+package test
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/yaacov/kubectl-mtv/cmd/get"
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/test/hook"
+	"github.com/yaacov/kubectl-mtv/pkg/util/client"
+	"github.com/yaacov/kubectl-mtv/pkg/util/completion"
+	"github.com/yaacov/kubectl-mtv/pkg/util/flags"
+)
+
+// NewHookCmd creates the test hook command
+func NewHookCmd(kubeConfigFlags *genericclioptions.ConfigFlags, globalConfig get.GlobalConfigGetter) *cobra.Command {
+	opts := hook.Options{}
+
+	cmd := &cobra.Command{
+		Use:   "hook [NAME]",
+		Short: "Run a hook once in a throwaway pod",
+		Long: `Run the playbook of a local hook once, outside of any migration, to validate it
+before it is attached to a plan.
+
+The hook image runs in a pod like the one Forklift creates for a hook step, with
+the playbook, a workload.yml and a plan.yml mounted at /tmp/hook. The pod logs are
+streamed while it runs and the command fails when the hook exits with an error.
+
+The workload payload describes a sample vSphere VM unless --workload gives a file
+or --provider and --vm read the payload of a real VM from the inventory. The plan
+payload is a minimal plan for the sample VM unless --plan names an existing plan.
+
+The pod and its config map are deleted when the run ends; use --keep to inspect
+them. AAP hooks run in Ansible Automation Platform and cannot be tested here.`,
+		Example: `  # Run a hook with the sample payloads
+  kubectl-mtv test hook my-pre-hook
+
+  # Run a hook with the workload of a real VM
+  kubectl-mtv test hook my-pre-hook --provider vsphere-prod --vm vm-123
+
+  # Run a hook with a custom workload and an existing plan, keeping the pod
+  kubectl-mtv test hook my-pre-hook --workload workload.yml --plan my-plan --keep`,
+		Args:         cobra.MaximumNArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := flags.ResolveNameArg(&opts.Name, args); err != nil {
+				return err
+			}
+			if opts.Name == "" {
+				return fmt.Errorf("--name is required")
+			}
+			if (opts.Provider == "") != (opts.VM == "") {
+				return fmt.Errorf("--provider and --vm must be used together")
+			}
+			if opts.Provider != "" && opts.WorkloadFile != "" {
+				return fmt.Errorf("--workload cannot be used with --provider and --vm")
+			}
+
+			cfg := globalConfig.GetKubeConfigFlags()
+			opts.Namespace = client.ResolveNamespace(cfg)
+			opts.InventoryURL = globalConfig.GetInventoryURL()
+			opts.InventoryInsecureSkipTLS = globalConfig.GetInventoryInsecureSkipTLS()
+			opts.Out = cmd.OutOrStdout()
+			return hook.Test(cmd.Context(), cfg, opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.Name, "name", "M", "", "Hook name")
+	flags.MarkRequiredForMCP(cmd, "name")
+	cmd.Flags().StringVar(&opts.WorkloadFile, "workload", "", "File with the workload.yml payload (default: a sample vSphere VM)")
+	cmd.Flags().StringVarP(&opts.Provider, "provider", "p", "", "Source provider to read the workload payload of --vm from")
+	cmd.Flags().StringVar(&opts.VM, "vm", "", "ID of the VM whose inventory workload is used as payload")
+	cmd.Flags().StringVar(&opts.Plan, "plan", "", "Existing plan used as the plan.yml payload (default: a sample plan)")
+	cmd.Flags().DurationVar(&opts.Timeout, "timeout", 0, fmt.Sprintf("Maximum time to wait for the hook (default: the hook deadline plus 1m, or %s)", hook.DefaultTimeout))
+	cmd.Flags().BoolVar(&opts.Keep, "keep", false, "Keep the pod and config map after the run")
+
+	cmd.ValidArgsFunction = completion.NameArgCompletion(completion.HookResourceNameCompletion(kubeConfigFlags))
+	_ = cmd.RegisterFlagCompletionFunc("name", completion.HookResourceNameCompletion(kubeConfigFlags))
+	_ = cmd.RegisterFlagCompletionFunc("provider", completion.ProviderNameCompletion(kubeConfigFlags))
+	_ = cmd.RegisterFlagCompletionFunc("plan", completion.PlanNameCompletion(kubeConfigFlags))
+
+	return cmd
+}
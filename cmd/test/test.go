@@ -0,0 +1,21 @@
+package test
+
+import (
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/yaacov/kubectl-mtv/cmd/get"
+)
+
+// NewTestCmd creates the test command with all its subcommands
+func NewTestCmd(kubeConfigFlags *genericclioptions.ConfigFlags, globalConfig get.GlobalConfigGetter) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "test",
+		Short:        "Try out resources before using them in a migration",
+		Long:         `Run resources such as hooks on their own, to validate them before they are attached to migration plans`,
+		SilenceUsage: true,
+	}
+
+	cmd.AddCommand(NewHookCmd(kubeConfigFlags, globalConfig))
+	return cmd
+}
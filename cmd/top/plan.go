@@ -0,0 +1,84 @@
+package top
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/yaacov/kubectl-mtv/cmd/get"
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/help"
+	plan "github.com/yaacov/kubectl-mtv/pkg/cmd/top/plan"
+	"github.com/yaacov/kubectl-mtv/pkg/util/client"
+	"github.com/yaacov/kubectl-mtv/pkg/util/completion"
+	"github.com/yaacov/kubectl-mtv/pkg/util/flags"
+)
+
+// NewPlanCmd creates the top plan command
+func NewPlanCmd(kubeConfigFlags *genericclioptions.ConfigFlags, globalConfig get.GlobalConfigGetter) *cobra.Command {
+	var name string
+	var outputFormat string
+	var watch bool
+	var sampleInterval time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "plan",
+		Short: "Show resource usage of running migration plans",
+		Long: `Show the resources consumed by the migration infrastructure of each running plan:
+
+  - IMPORTERS:   running disk transfer pods (CDI importers and volume populators)
+  - CONVERSIONS: running virt-v2v guest conversion pods
+  - CPU-REQ, MEM-REQ: CPU and memory requested by those pods
+  - PROVISIONED: size of the PVCs created for the migrated disks
+  - COPIED:      data copied so far, estimated from the DataVolume progress
+  - THROUGHPUT:  copy rate estimated from the change of COPIED between two samples
+
+Without --name, all plans with a running migration are shown. Throughput needs two
+samples: the first collection is repeated after --sample-interval, and with --watch
+each refresh is compared with the previous one.`,
+		Example: `  # Resource usage of the running migrations in the current namespace
+  kubectl-mtv top plan
+
+  # Live view of one plan
+  kubectl-mtv top plan --name my-migration --watch
+
+  # All namespaces, without waiting for a throughput sample
+  kubectl-mtv top plan -A --sample-interval 0
+
+  # Machine-readable usage
+  kubectl-mtv top plan --output json`,
+		Args:         cobra.MaximumNArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := flags.ResolveNameArg(&name, args); err != nil {
+				return err
+			}
+			if sampleInterval < 0 {
+				return fmt.Errorf("--sample-interval must not be negative")
+			}
+
+			cfg := globalConfig.GetKubeConfigFlags()
+			if name != "" && globalConfig.GetAllNamespaces() {
+				return fmt.Errorf("--name cannot be used with --all-namespaces")
+			}
+			namespace := client.ResolveNamespaceWithAllFlag(cfg, globalConfig.GetAllNamespaces())
+
+			opts := plan.Options{ConfigFlags: cfg, Name: name, Namespace: namespace}
+			return plan.Print(cmd.Context(), opts, outputFormat, watch, sampleInterval)
+		},
+	}
+
+	cmd.Flags().StringVarP(&name, "name", "M", "", "Plan name (default: all plans with a running migration)")
+	cmd.Flags().StringVarP(&outputFormat, "output", "o", "table", "Output format (table, json, yaml)")
+	cmd.Flags().BoolVarP(&watch, "watch", "w", false, "Watch for changes")
+	cmd.Flags().DurationVar(&sampleInterval, "sample-interval", 5*time.Second, "Time between the two samples used to estimate throughput (0 = no estimate without --watch)")
+	help.MarkMCPHidden(cmd, "watch")
+
+	_ = cmd.RegisterFlagCompletionFunc("name", completion.PlanNameCompletion(kubeConfigFlags))
+	_ = cmd.RegisterFlagCompletionFunc("output", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"table", "json", "yaml"}, cobra.ShellCompDirectiveNoFileComp
+	})
+
+	return cmd
+}
@@ -0,0 +1,23 @@
+package top
+
+import (
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/yaacov/kubectl-mtv/cmd/get"
+)
+
+// NewTopCmd creates the top command with all its subcommands
+func NewTopCmd(kubeConfigFlags *genericclioptions.ConfigFlags, globalConfig get.GlobalConfigGetter) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "top",
+		Short:        "Show resource usage of running migrations",
+		Long:         `Show the resource usage of the migration infrastructure of running migrations`,
+		SilenceUsage: true,
+	}
+
+	planCmd := NewPlanCmd(kubeConfigFlags, globalConfig)
+	planCmd.Aliases = []string{"plans"}
+	cmd.AddCommand(planCmd)
+	return cmd
+}
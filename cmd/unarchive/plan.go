@@ -17,6 +17,8 @@ import (
 func NewPlanCmd(kubeConfigFlags *genericclioptions.ConfigFlags) *cobra.Command {
 	var all bool
 	var planNames []string
+	var olderThan, statusFilter string
+	var yes bool
 
 	cmd := &cobra.Command{
 		Use:   "plan",
@@ -24,7 +26,11 @@ func NewPlanCmd(kubeConfigFlags *genericclioptions.ConfigFlags) *cobra.Command {
 		Long: `Unarchive one or more migration plans.
 
 Unarchiving restores a previously archived plan, allowing it to be started again.
-This is useful if you need to retry a migration or make changes to an archived plan.`,
+This is useful if you need to retry a migration or make changes to an archived plan.
+
+Use --older-than and --status to select archived plans in bulk by the age and status
+of their last migration. The matching plans are listed for review and only
+unarchived when --yes is given.`,
 		Example: `  # Unarchive a plan
   kubectl-mtv unarchive plan --name my-migration
 
@@ -32,7 +38,10 @@ This is useful if you need to retry a migration or make changes to an archived p
   kubectl-mtv unarchive plans --name plan1,plan2,plan3
 
   # Unarchive all archived plans in the namespace
-  kubectl-mtv unarchive plans --all`,
+  kubectl-mtv unarchive plans --all
+
+  # Unarchive the archived plans that failed, to retry them
+  kubectl-mtv unarchive plans --status Failed --yes`,
 		Args:         cobra.MaximumNArgs(1),
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -40,17 +49,35 @@ This is useful if you need to retry a migration or make changes to an archived p
 				return err
 			}
 
-			// Validate mutual exclusivity of --name and --all
+			// Validate mutual exclusivity of --name, --all and the bulk selectors
+			selecting := olderThan != "" || statusFilter != ""
 			if all && len(planNames) > 0 {
 				return errors.New("cannot use --name with --all")
 			}
-			if !all && len(planNames) == 0 {
-				return errors.New("must specify --name or --all")
+			if selecting && (all || len(planNames) > 0) {
+				return errors.New("cannot use --older-than or --status with --name or --all")
+			}
+			if !all && !selecting && len(planNames) == 0 {
+				return errors.New("must specify --name, --all, --older-than or --status")
+			}
+			if yes && !selecting {
+				return errors.New("--yes requires --older-than or --status")
 			}
 
 			// Resolve the appropriate namespace based on context and flags
 			namespace := client.ResolveNamespace(kubeConfigFlags)
 
+			if selecting {
+				opts, err := plan.NewSelectOptions(olderThan, statusFilter, true)
+				if err != nil {
+					return err
+				}
+				planNames, err = plan.PreviewSelection(cmd.Context(), kubeConfigFlags, namespace, opts, yes, "unarchive")
+				if err != nil || len(planNames) == 0 {
+					return err
+				}
+			}
+
 			if all {
 				// Get all plan names from the namespace
 				var err error
@@ -79,6 +106,9 @@ This is useful if you need to retry a migration or make changes to an archived p
 	cmd.Flags().StringSliceVar(&planNames, "names", nil, "Alias for --name")
 	_ = cmd.Flags().MarkHidden("names")
 	cmd.Flags().BoolVar(&all, "all", false, "Unarchive all migration plans in the namespace")
+	cmd.Flags().StringVar(&olderThan, "older-than", "", "Select the archived plans whose last migration completed longer ago than this duration (e.g. 30d, 12h)")
+	cmd.Flags().StringVar(&statusFilter, "status", "", "Select the archived plans whose last migration ended with this status (Succeeded, Failed, Canceled)")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Unarchive the plans selected by --older-than or --status instead of only listing them")
 
 	_ = cmd.RegisterFlagCompletionFunc("name", completion.PlanNameCompletion(kubeConfigFlags))
 
@@ -16,6 +16,7 @@ import (
 // NewVersionCmd creates the version command
 func NewVersionCmd(clientVersion string, kubeConfigFlags *genericclioptions.ConfigFlags, globalConfig get.GlobalConfigGetter) *cobra.Command {
 	var clientOnly bool
+	var checkCompatibility bool
 	outputFormatFlag := flags.NewOutputFormatTypeFlag()
 
 	cmd := &cobra.Command{
@@ -24,8 +25,22 @@ func NewVersionCmd(clientVersion string, kubeConfigFlags *genericclioptions.Conf
 		Long: `Print the version information for kubectl-mtv and MTV Operator.
 
 Use --client to print only the client version without connecting to the cluster.
-This is useful for CI/CD pipelines, MCP servers, or when the cluster is unavailable.`,
+This is useful for CI/CD pipelines, MCP servers, or when the cluster is unavailable.
+
+Use --check-compatibility to also fetch the version of the inventory service and check the
+kubectl-mtv, MTV operator, inventory and Forklift API versions against the compatibility
+matrix built into kubectl-mtv. Known-broken or untested combinations are reported as
+warnings and errors.`,
+		Example: `  # Print the client and cluster versions
+  kubectl-mtv version
+
+  # Check that this kubectl-mtv works with the installed MTV operator
+  kubectl-mtv version --check-compatibility`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if clientOnly && checkCompatibility {
+				return fmt.Errorf("--check-compatibility cannot be used with --client, it needs the cluster versions")
+			}
+
 			// If --client flag is set, skip cluster connectivity and return only client version
 			if clientOnly {
 				clientInfo := version.Info{
@@ -45,6 +60,11 @@ This is useful for CI/CD pipelines, MCP servers, or when the cluster is unavaila
 
 			// Get version information (globalConfig handles inventory URL and insecure flag)
 			versionInfo := version.GetVersionInfo(ctx, clientVersion, kubeConfigFlags, globalConfig)
+			if checkCompatibility {
+				if err := version.CheckCompatibility(ctx, &versionInfo, kubeConfigFlags); err != nil {
+					return err
+				}
+			}
 
 			// Format and output the version information
 			output, err := versionInfo.FormatOutput(outputFormatFlag.GetValue())
@@ -58,6 +78,7 @@ This is useful for CI/CD pipelines, MCP servers, or when the cluster is unavaila
 	}
 
 	cmd.Flags().BoolVar(&clientOnly, "client", false, "Print only the client version (skip cluster connectivity)")
+	cmd.Flags().BoolVar(&checkCompatibility, "check-compatibility", false, "Check the client, operator, inventory and API versions against the built-in compatibility matrix")
 	cmd.Flags().VarP(outputFormatFlag, "output", "o", flags.OutputFormatHelp)
 
 	// Add completion for output format flag
@@ -54,6 +54,6 @@ func Archive(ctx context.Context, configFlags *genericclioptions.ConfigFlags, pl
 		action = "unarchived"
 	}
 
-	fmt.Printf("Plan '%s' %s\n", planName, action)
+	fmt.Printf("Plan '%s' %s%s\n", planName, action, client.DryRunSuffix())
 	return nil
 }
@@ -0,0 +1,246 @@
+package plan
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
+
+	describeplan "github.com/yaacov/kubectl-mtv/pkg/cmd/describe/plan"
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/get/plan/status"
+	"github.com/yaacov/kubectl-mtv/pkg/util/client"
+	"github.com/yaacov/kubectl-mtv/pkg/util/describe"
+	"github.com/yaacov/kubectl-mtv/pkg/util/fields"
+	"github.com/yaacov/kubectl-mtv/pkg/util/upload"
+)
+
+// Exported artifact file names
+const (
+	ExportReportFile    = "report.md"
+	ExportPlanFile      = "plan.yaml"
+	ExportMigrationFile = "migration.yaml"
+	ExportVMsFile       = "vms.yaml"
+)
+
+// VMStatusSnapshot is the exported status of a single VM in the final migration
+type VMStatusSnapshot struct {
+	Name      string             `json:"name"`
+	ID        string             `json:"id,omitempty"`
+	Phase     string             `json:"phase,omitempty"`
+	Status    string             `json:"status,omitempty"`
+	Started   string             `json:"started,omitempty"`
+	Completed string             `json:"completed,omitempty"`
+	Error     string             `json:"error,omitempty"`
+	Pipeline  []PipelineSnapshot `json:"pipeline,omitempty"`
+}
+
+// PipelineSnapshot is the exported status of a single VM pipeline step
+type PipelineSnapshot struct {
+	Name     string `json:"name"`
+	Phase    string `json:"phase,omitempty"`
+	Progress string `json:"progress,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// VMStatusExport is the content of the exported VM status file
+type VMStatusExport struct {
+	Plan       string             `json:"plan"`
+	Namespace  string             `json:"namespace"`
+	Migration  string             `json:"migration,omitempty"`
+	ExportedAt string             `json:"exportedAt"`
+	VMs        []VMStatusSnapshot `json:"vms"`
+}
+
+// validateExportDir rejects export targets that are not local directories
+func validateExportDir(dir string) error {
+	if strings.TrimSpace(dir) == "" {
+		return fmt.Errorf("export directory must not be empty")
+	}
+	if strings.Contains(dir, "://") {
+		return fmt.Errorf("unsupported export target '%s': --export takes a local directory, "+
+			"use --upload for object storage", dir)
+	}
+	return nil
+}
+
+// Export saves the migration report, plan YAML and VM status snapshot of a plan to dir/<plan-name>
+func Export(ctx context.Context, configFlags *genericclioptions.ConfigFlags, planName, namespace, dir string) error {
+	if err := validateExportDir(dir); err != nil {
+		return err
+	}
+
+	c, err := client.GetDynamicClient(configFlags)
+	if err != nil {
+		return fmt.Errorf("failed to get client: %v", err)
+	}
+
+	plan, err := c.Resource(client.PlansGVR).Namespace(namespace).Get(ctx, planName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get plan '%s': %v", planName, err)
+	}
+
+	runningMigration, latestMigration, err := status.GetRunningMigration(c, namespace, plan, client.MigrationsGVR)
+	if err != nil {
+		klog.V(2).Infof("Failed to get migrations for plan '%s': %v", planName, err)
+	}
+	migration := runningMigration
+	if migration == nil {
+		migration = latestMigration
+	}
+
+	planDir := filepath.Join(dir, planName)
+	if err := os.MkdirAll(planDir, 0755); err != nil {
+		return fmt.Errorf("failed to create export directory '%s': %v", planDir, err)
+	}
+
+	// Migration report, the same content as 'describe plan --with-vms' in markdown
+	desc, err := describeplan.BuildDescription(configFlags, planName, namespace, true, false, 0, 0, true, "", false)
+	if err != nil {
+		return fmt.Errorf("failed to build migration report: %v", err)
+	}
+	report, err := describe.Format(desc, "markdown")
+	if err != nil {
+		return fmt.Errorf("failed to format migration report: %v", err)
+	}
+	if err := writeExportFile(planDir, ExportReportFile, []byte(report)); err != nil {
+		return err
+	}
+
+	// Plan and migration resources
+	if err := writeExportObject(planDir, ExportPlanFile, plan); err != nil {
+		return err
+	}
+	if migration != nil {
+		if err := writeExportObject(planDir, ExportMigrationFile, migration); err != nil {
+			return err
+		}
+	}
+
+	// VM status snapshot
+	snapshot := VMStatusExport{
+		Plan:       planName,
+		Namespace:  namespace,
+		ExportedAt: time.Now().UTC().Format(time.RFC3339),
+		VMs:        []VMStatusSnapshot{},
+	}
+	if migration != nil {
+		snapshot.Migration = migration.GetName()
+		vms, _, _ := unstructured.NestedSlice(migration.Object, "status", "vms")
+		snapshot.VMs = buildVMSnapshots(vms)
+	}
+	data, err := yaml.Marshal(snapshot)
+	if err != nil {
+		return fmt.Errorf("failed to encode VM status snapshot: %v", err)
+	}
+	if err := writeExportFile(planDir, ExportVMsFile, data); err != nil {
+		return err
+	}
+
+	fmt.Printf("Plan '%s' exported to %s\n", planName, planDir)
+	return nil
+}
+
+// UploadExport uploads the exported artifacts of a plan from dir/<plan-name> to object storage
+func UploadExport(ctx context.Context, uploader *upload.S3Uploader, dir, planName string) error {
+	uploaded, err := uploader.UploadDir(ctx, filepath.Join(dir, planName), planName)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Plan '%s' artifacts uploaded to %s (%d files)\n", planName, uploader.Location.String()+"/"+planName, len(uploaded))
+	return nil
+}
+
+// writeExportObject writes a cluster object as YAML without server-managed noise
+func writeExportObject(dir, name string, obj *unstructured.Unstructured) error {
+	clean := obj.DeepCopy()
+	unstructured.RemoveNestedField(clean.Object, "metadata", "managedFields")
+
+	data, err := yaml.Marshal(clean.Object)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %v", name, err)
+	}
+	return writeExportFile(dir, name, data)
+}
+
+// writeExportFile writes a single artifact file
+func writeExportFile(dir, name string, data []byte) error {
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write '%s': %v", path, err)
+	}
+	return nil
+}
+
+// buildVMSnapshots converts migration status.vms entries into VM status snapshots
+func buildVMSnapshots(vms []interface{}) []VMStatusSnapshot {
+	snapshots := make([]VMStatusSnapshot, 0, len(vms))
+	for _, v := range vms {
+		vm, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		snapshot := VMStatusSnapshot{
+			Name:      fields.String(vm, "name"),
+			ID:        fields.String(vm, "id"),
+			Phase:     fields.String(vm, "phase"),
+			Status:    vmConditionStatus(vm),
+			Started:   fields.String(vm, "started"),
+			Completed: fields.String(vm, "completed"),
+			Error:     fields.ErrorReasons(vm),
+		}
+
+		pipeline, _, _ := unstructured.NestedSlice(vm, "pipeline")
+		for _, p := range pipeline {
+			step, ok := p.(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			progress := ""
+			completed, hasCompleted, _ := unstructured.NestedInt64(step, "progress", "completed")
+			total, hasTotal, _ := unstructured.NestedInt64(step, "progress", "total")
+			if hasCompleted && hasTotal {
+				progress = fmt.Sprintf("%d/%d", completed, total)
+				if unit := fields.String(step, "annotations", "unit"); unit != "" {
+					progress += " " + unit
+				}
+			}
+
+			snapshot.Pipeline = append(snapshot.Pipeline, PipelineSnapshot{
+				Name:     fields.String(step, "name"),
+				Phase:    fields.String(step, "phase"),
+				Progress: progress,
+				Error:    fields.ErrorReasons(step),
+			})
+		}
+
+		snapshots = append(snapshots, snapshot)
+	}
+	return snapshots
+}
+
+// vmConditionStatus returns Succeeded, Failed or Canceled from the VM conditions, if set
+func vmConditionStatus(vm map[string]interface{}) string {
+	conditions, _, _ := unstructured.NestedSlice(vm, "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok || fields.String(condition, "status") != "True" {
+			continue
+		}
+		switch condType := fields.String(condition, "type"); condType {
+		case status.StatusSucceeded, status.StatusFailed, status.StatusCanceled:
+			return condType
+		}
+	}
+	return ""
+}
@@ -0,0 +1,59 @@
+package plan
+
+import "testing"
+
+func TestValidateExportDir(t *testing.T) {
+	if err := validateExportDir("./evidence"); err != nil {
+		t.Errorf("unexpected error for local dir: %v", err)
+	}
+	if err := validateExportDir("s3://bucket/evidence"); err == nil {
+		t.Error("expected object storage URL to be rejected")
+	}
+	if err := validateExportDir("  "); err == nil {
+		t.Error("expected empty dir to be rejected")
+	}
+}
+
+func TestBuildVMSnapshots(t *testing.T) {
+	vms := []interface{}{
+		map[string]interface{}{
+			"name":      "web-1",
+			"id":        "vm-101",
+			"phase":     "Completed",
+			"started":   "2026-01-01T10:00:00Z",
+			"completed": "2026-01-01T10:30:00Z",
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Failed", "status": "True"},
+			},
+			"error": map[string]interface{}{
+				"phase":   "DiskTransfer",
+				"reasons": []interface{}{"import failed", "timeout"},
+			},
+			"pipeline": []interface{}{
+				map[string]interface{}{
+					"name":        "DiskTransfer",
+					"phase":       "Completed",
+					"progress":    map[string]interface{}{"completed": int64(512), "total": int64(1024)},
+					"annotations": map[string]interface{}{"unit": "MB"},
+				},
+			},
+		},
+		"not-a-vm",
+	}
+
+	snapshots := buildVMSnapshots(vms)
+	if len(snapshots) != 1 {
+		t.Fatalf("snapshots = %d, want 1", len(snapshots))
+	}
+
+	vm := snapshots[0]
+	if vm.Name != "web-1" || vm.ID != "vm-101" || vm.Status != "Failed" {
+		t.Errorf("unexpected snapshot: %+v", vm)
+	}
+	if vm.Error != "import failed; timeout" {
+		t.Errorf("Error = %q", vm.Error)
+	}
+	if len(vm.Pipeline) != 1 || vm.Pipeline[0].Progress != "512/1024 MB" {
+		t.Errorf("Pipeline = %+v", vm.Pipeline)
+	}
+}
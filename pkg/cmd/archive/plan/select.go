@@ -0,0 +1,177 @@
+package plan
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/get/plan/status"
+	"github.com/yaacov/kubectl-mtv/pkg/util/client"
+	"github.com/yaacov/kubectl-mtv/pkg/util/flags"
+	"github.com/yaacov/kubectl-mtv/pkg/util/output"
+)
+
+// SelectableStatuses are the plan statuses accepted by --status: plans whose last migration finished
+var SelectableStatuses = []string{status.StatusSucceeded, status.StatusFailed, status.StatusCanceled}
+
+// SelectOptions selects plans to archive or unarchive in bulk
+type SelectOptions struct {
+	// OlderThan selects plans whose last migration completed at least this long ago
+	OlderThan time.Duration
+	// Status selects plans with this status, one of SelectableStatuses
+	Status string
+	// Archived is the current archived state of the selected plans: false when archiving,
+	// true when unarchiving
+	Archived bool
+}
+
+// Candidate is a plan selected for bulk archiving or unarchiving
+type Candidate struct {
+	Name      string
+	Status    string
+	Completed time.Time
+}
+
+// NewSelectOptions builds the selection from the --older-than and --status flag values
+func NewSelectOptions(olderThan, statusValue string, archived bool) (SelectOptions, error) {
+	opts := SelectOptions{Archived: archived}
+	if olderThan != "" {
+		d, err := flags.ParseRelativeDuration(olderThan)
+		if err != nil {
+			return opts, fmt.Errorf("invalid --older-than: %v", err)
+		}
+		opts.OlderThan = d
+	}
+	if statusValue != "" {
+		s, err := NormalizeStatus(statusValue)
+		if err != nil {
+			return opts, err
+		}
+		opts.Status = s
+	}
+	return opts, nil
+}
+
+// NormalizeStatus returns the canonical spelling of a --status value
+func NormalizeStatus(value string) (string, error) {
+	for _, s := range SelectableStatuses {
+		if strings.EqualFold(value, s) {
+			return s, nil
+		}
+	}
+	return "", fmt.Errorf("invalid status '%s': must be one of %s", value, strings.Join(SelectableStatuses, ", "))
+}
+
+// SelectPlans returns the plans of the namespace matching opts, ordered by name. Only plans
+// whose last migration completed are selected, so running and never started plans are kept.
+func SelectPlans(ctx context.Context, c dynamic.Interface, namespace string, opts SelectOptions, now time.Time) ([]Candidate, error) {
+	list, err := c.Resource(client.PlansGVR).Namespace(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list plans: %v", err)
+	}
+
+	var candidates []Candidate
+	for i := range list.Items {
+		plan := &list.Items[i]
+		archived, _, _ := unstructured.NestedBool(plan.Object, "spec", "archived")
+		if archived != opts.Archived {
+			continue
+		}
+
+		completedStr, _, _ := unstructured.NestedString(plan.Object, "status", "migration", "completed")
+		completed, err := time.Parse(time.RFC3339, completedStr)
+		if err != nil {
+			continue
+		}
+		planStatus, _ := status.GetPlanStatus(plan)
+		if !isSelectableStatus(planStatus) {
+			continue
+		}
+
+		if opts.Status != "" && planStatus != opts.Status {
+			continue
+		}
+		if opts.OlderThan > 0 && now.Sub(completed) < opts.OlderThan {
+			continue
+		}
+		candidates = append(candidates, Candidate{Name: plan.GetName(), Status: planStatus, Completed: completed})
+	}
+
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].Name < candidates[j].Name })
+	return candidates, nil
+}
+
+func isSelectableStatus(s string) bool {
+	for _, selectable := range SelectableStatuses {
+		if s == selectable {
+			return true
+		}
+	}
+	return false
+}
+
+// PrintCandidates prints the selected plans with their status and the age of their last migration
+func PrintCandidates(candidates []Candidate, now time.Time) error {
+	items := make([]map[string]interface{}, 0, len(candidates))
+	for _, c := range candidates {
+		items = append(items, map[string]interface{}{
+			"name":      c.Name,
+			"status":    c.Status,
+			"completed": output.FormatTimestamp(c.Completed, false),
+			"age":       duration.HumanDuration(now.Sub(c.Completed)),
+		})
+	}
+
+	return output.NewTablePrinter().
+		WithColumns(
+			output.Column{Title: "NAME", Key: "name", ColorFunc: output.Bold},
+			output.Column{Title: "STATUS", Key: "status", ColorFunc: output.ColorizeStatus},
+			output.Column{Title: "COMPLETED", Key: "completed"},
+			output.Column{Title: "AGE", Key: "age"},
+		).
+		AddItems(items).
+		Print()
+}
+
+// PreviewSelection selects the plans matching opts and lists them. Unless confirmed, it only
+// asks to re-run with --yes and returns no names, so nothing is changed. action names the
+// operation in messages, e.g. "archive".
+func PreviewSelection(ctx context.Context, configFlags *genericclioptions.ConfigFlags, namespace string, opts SelectOptions, confirmed bool, action string) ([]string, error) {
+	c, err := client.GetDynamicClient(configFlags)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get client: %v", err)
+	}
+
+	now := time.Now()
+	candidates, err := SelectPlans(ctx, c, namespace, opts, now)
+	if err != nil {
+		return nil, err
+	}
+	if len(candidates) == 0 {
+		fmt.Printf("No plans to %s in namespace %s\n", action, namespace)
+		return nil, nil
+	}
+
+	if err := PrintCandidates(candidates, now); err != nil {
+		return nil, err
+	}
+	if !confirmed {
+		fmt.Printf("\n%d plan(s) would be %sd. Re-run with --yes to %s them.\n", len(candidates), action, action)
+		return nil, nil
+	}
+	fmt.Println()
+
+	names := make([]string, 0, len(candidates))
+	for _, c := range candidates {
+		names = append(names, c.Name)
+	}
+	return names, nil
+}
@@ -0,0 +1,83 @@
+package plan
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/yaacov/kubectl-mtv/pkg/util/client"
+)
+
+func finishedPlan(name, planStatus, completed string, archived bool) *unstructured.Unstructured {
+	p := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "forklift.konveyor.io/v1beta1",
+		"kind":       "Plan",
+		"spec":       map[string]interface{}{"archived": archived},
+		"status": map[string]interface{}{
+			"conditions": []interface{}{map[string]interface{}{"type": planStatus, "status": "True"}},
+			"migration":  map[string]interface{}{"completed": completed},
+		},
+	}}
+	p.SetName(name)
+	p.SetNamespace("demo")
+	return p
+}
+
+func TestSelectPlans(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	c := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		client.PlansGVR: "PlanList",
+	},
+		finishedPlan("old-ok", "Succeeded", "2026-08-01T10:00:00Z", false),
+		finishedPlan("old-failed", "Failed", "2026-08-02T10:00:00Z", false),
+		finishedPlan("recent-ok", "Succeeded", "2026-10-10T10:00:00Z", false),
+		finishedPlan("old-archived", "Succeeded", "2026-07-01T10:00:00Z", true),
+		finishedPlan("running", "Running", "", false),
+	)
+
+	tests := []struct {
+		name      string
+		olderThan string
+		status    string
+		archived  bool
+		want      []string
+	}{
+		{"older than", "30d", "", false, []string{"old-failed", "old-ok"}},
+		{"status", "", "succeeded", false, []string{"old-ok", "recent-ok"}},
+		{"both", "30d", "Succeeded", false, []string{"old-ok"}},
+		{"archived", "30d", "", true, []string{"old-archived"}},
+	}
+	for _, tt := range tests {
+		opts, err := NewSelectOptions(tt.olderThan, tt.status, tt.archived)
+		if err != nil {
+			t.Fatalf("%s: NewSelectOptions() unexpected error: %v", tt.name, err)
+		}
+		candidates, err := SelectPlans(context.Background(), c, "demo", opts, now)
+		if err != nil {
+			t.Fatalf("%s: SelectPlans() unexpected error: %v", tt.name, err)
+		}
+		var got []string
+		for _, c := range candidates {
+			got = append(got, c.Name)
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: selected %v, want %v", tt.name, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%s: selected %v, want %v", tt.name, got, tt.want)
+				break
+			}
+		}
+	}
+
+	if _, err := NewSelectOptions("", "Running", false); err == nil {
+		t.Error("NewSelectOptions() accepted --status Running, want an error")
+	}
+}
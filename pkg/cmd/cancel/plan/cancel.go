@@ -13,10 +13,14 @@ import (
 	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
 	"github.com/yaacov/kubectl-mtv/pkg/cmd/get/plan/status"
 	"github.com/yaacov/kubectl-mtv/pkg/util/client"
+	"github.com/yaacov/kubectl-mtv/pkg/util/output"
+	"github.com/yaacov/kubectl-mtv/pkg/util/prompt"
 )
 
-// Cancel cancels specific VMs in a running migration
-func Cancel(configFlags *genericclioptions.ConfigFlags, planName string, namespace string, vmNames []string) error {
+// Cancel cancels specific VMs in a running migration. VMs matched by the selector are
+// resolved from the migration status and added to vmNames. The VMs are listed with their
+// migration state and canceled only after confirmation.
+func Cancel(configFlags *genericclioptions.ConfigFlags, planName string, namespace string, vmNames []string, selector Selector, confirm prompt.Options) error {
 	c, err := client.GetDynamicClient(configFlags)
 	if err != nil {
 		return fmt.Errorf("failed to get client: %v", err)
@@ -54,6 +58,27 @@ func Cancel(configFlags *genericclioptions.ConfigFlags, planName string, namespa
 		vmNameToIDMap[vmName] = vmID
 	}
 
+	// Find the running migration for this plan
+	runningMigration, _, err := status.GetRunningMigration(c, namespace, planObj, client.MigrationsGVR)
+	if err != nil {
+		return err
+	}
+	if runningMigration == nil {
+		return fmt.Errorf("no running migration found for plan '%s'", planName)
+	}
+
+	// Resolve the selectors to concrete VM names
+	if !selector.IsEmpty() {
+		selected, err := SelectVMs(runningMigration, selector)
+		if err != nil {
+			return err
+		}
+		if len(selected) == 0 && len(vmNames) == 0 {
+			return fmt.Errorf("no VMs in the running migration of plan '%s' match the selection", planName)
+		}
+		vmNames = appendUnique(vmNames, selected)
+	}
+
 	// Check if requested VM names exist in the plan
 	var invalidVMs []string
 	var validVMs []string
@@ -69,13 +94,14 @@ func Cancel(configFlags *genericclioptions.ConfigFlags, planName string, namespa
 		return fmt.Errorf("the following VMs were not found in plan '%s': %v", planName, invalidVMs)
 	}
 
-	// Find the running migration for this plan
-	runningMigration, _, err := status.GetRunningMigration(c, namespace, planObj, client.MigrationsGVR)
-	if err != nil {
+	// Show the VMs to cancel and ask before patching the migration
+	title := fmt.Sprintf("The following %d VM(s) of plan '%s' will be canceled (migration '%s'):", len(validVMs), planName, runningMigration.GetName())
+	if err := prompt.Table(confirm, title, CancelColumns, summarizeVMs(runningMigration, validVMs)); err != nil {
 		return err
 	}
-	if runningMigration == nil {
-		return fmt.Errorf("no running migration found for plan '%s'", planName)
+	question := fmt.Sprintf("Cancel the migration of these %d VM(s)?", len(validVMs))
+	if err := prompt.Confirm(confirm, question, fmt.Sprintf("cancel %d VM(s) of plan '%s'", len(validVMs), planName)); err != nil {
+		return err
 	}
 
 	// Prepare the VM references to cancel
@@ -140,6 +166,52 @@ func Cancel(configFlags *genericclioptions.ConfigFlags, planName string, namespa
 	return nil
 }
 
+// CancelColumns are the columns of the VMs shown before they are canceled
+var CancelColumns = []output.Column{
+	{Title: "NAME", Key: "name"},
+	{Title: "STATE", Key: "state", ColorFunc: output.ColorizeStatus},
+	{Title: "PHASE", Key: "phase"},
+}
+
+// summarizeVMs returns a row for each VM to cancel with its state in the migration
+func summarizeVMs(migration *unstructured.Unstructured, names []string) []map[string]interface{} {
+	statuses := map[string]map[string]interface{}{}
+	vms, _, _ := unstructured.NestedSlice(migration.Object, "status", "vms")
+	for _, v := range vms {
+		if vm, ok := v.(map[string]interface{}); ok {
+			if name, _ := vm["name"].(string); name != "" {
+				statuses[name] = vm
+			}
+		}
+	}
+
+	items := make([]map[string]interface{}, 0, len(names))
+	for _, name := range names {
+		item := map[string]interface{}{"name": name, "state": StatusPending, "phase": ""}
+		if vm, ok := statuses[name]; ok {
+			item["state"] = vmState(vm)
+			item["phase"], _ = vm["phase"].(string)
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// appendUnique appends the names not already in names
+func appendUnique(names, more []string) []string {
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		seen[name] = true
+	}
+	for _, name := range more {
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
 // mergeCancelVMs merges two slices of ref.Ref, avoiding duplicates based on VM ID
 func mergeCancelVMs(existing, new []ref.Ref) []interface{} {
 	// Create a map to track unique VMs by ID
@@ -0,0 +1,104 @@
+package plan
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/get/plan/status"
+	querypkg "github.com/yaacov/kubectl-mtv/pkg/util/query"
+)
+
+// StatusPending is the state of a VM the migration has not started yet
+const StatusPending = "Pending"
+
+// Selector selects VMs of the running migration by their migration status
+type Selector struct {
+	// AllFailed selects VMs that failed or report an error
+	AllFailed bool
+	// AllRunning selects VMs that started and have not completed
+	AllRunning bool
+	// Query is a TSL filter over the VM status fields of the migration, with a derived
+	// "state" field: Pending, Running, Failed, Succeeded or Canceled
+	Query string
+}
+
+// IsEmpty reports whether no selector is set
+func (s Selector) IsEmpty() bool {
+	return !s.AllFailed && !s.AllRunning && s.Query == ""
+}
+
+// vmState derives the migration state of a VM from its status
+func vmState(vm map[string]interface{}) string {
+	switch {
+	case status.VMHasCondition(vm, status.StatusCanceled):
+		return status.StatusCanceled
+	case status.VMHasCondition(vm, status.StatusFailed):
+		return status.StatusFailed
+	case status.VMHasCondition(vm, status.StatusSucceeded):
+		return status.StatusSucceeded
+	}
+	if _, hasError := vm["error"]; hasError {
+		return status.StatusFailed
+	}
+	phase, _ := vm["phase"].(string)
+	if started, _ := vm["started"].(string); started != "" && phase != status.StatusCompleted {
+		return status.StatusRunning
+	}
+	return StatusPending
+}
+
+// SelectVMs returns the names of the migration VMs matching any of the selectors, in
+// migration order. Canceled VMs are never selected.
+func SelectVMs(migration *unstructured.Unstructured, selector Selector) ([]string, error) {
+	vms, _, err := unstructured.NestedSlice(migration.Object, "status", "vms")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get VMs from migration: %v", err)
+	}
+
+	items := make([]map[string]interface{}, 0, len(vms))
+	for _, v := range vms {
+		vm, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		item := make(map[string]interface{}, len(vm)+1)
+		for key, value := range vm {
+			item[key] = value
+		}
+		item["state"] = vmState(vm)
+		items = append(items, item)
+	}
+
+	matched := map[string]bool{}
+	if selector.Query != "" {
+		queryOpts, err := querypkg.ParseQueryString(selector.Query)
+		if err != nil {
+			return nil, fmt.Errorf("invalid query: %v", err)
+		}
+		filtered, err := querypkg.ApplyQuery(items, queryOpts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to apply query: %v", err)
+		}
+		for _, item := range filtered {
+			if name, _ := item["name"].(string); name != "" {
+				matched[name] = true
+			}
+		}
+	}
+
+	var names []string
+	for _, item := range items {
+		name, _ := item["name"].(string)
+		state := item["state"]
+		if name == "" || state == status.StatusCanceled {
+			continue
+		}
+		if matched[name] ||
+			(selector.AllFailed && state == status.StatusFailed) ||
+			(selector.AllRunning && state == status.StatusRunning) {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
@@ -0,0 +1,79 @@
+package plan
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func condition(condType string) []interface{} {
+	return []interface{}{map[string]interface{}{"type": condType, "status": "True"}}
+}
+
+func testMigration() *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"status": map[string]interface{}{
+			"vms": []interface{}{
+				map[string]interface{}{"name": "pending"},
+				map[string]interface{}{"name": "copying", "started": "2026-01-01T00:00:00Z", "phase": "CopyDisks"},
+				map[string]interface{}{"name": "failed", "started": "2026-01-01T00:00:00Z", "conditions": condition("Failed")},
+				map[string]interface{}{"name": "errored", "started": "2026-01-01T00:00:00Z", "error": map[string]interface{}{"phase": "CopyDisks"}},
+				map[string]interface{}{"name": "done", "started": "2026-01-01T00:00:00Z", "phase": "Completed", "conditions": condition("Succeeded")},
+				map[string]interface{}{"name": "canceled", "started": "2026-01-01T00:00:00Z", "conditions": condition("Canceled")},
+			},
+		},
+	}}
+}
+
+func TestSelectVMs(t *testing.T) {
+	tests := []struct {
+		name     string
+		selector Selector
+		want     []string
+	}{
+		{"all failed", Selector{AllFailed: true}, []string{"failed", "errored"}},
+		{"all running", Selector{AllRunning: true}, []string{"copying"}},
+		{"failed and running", Selector{AllFailed: true, AllRunning: true}, []string{"copying", "failed", "errored"}},
+		{"query on state", Selector{Query: "where state = 'Pending'"}, []string{"pending"}},
+		{"query without where", Selector{Query: "phase = 'CopyDisks'"}, []string{"copying"}},
+		{"query never selects canceled", Selector{Query: "where state = 'Canceled'"}, nil},
+		{"query combined with flag", Selector{AllFailed: true, Query: "where name = 'pending'"}, []string{"pending", "failed", "errored"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SelectVMs(testMigration(), tt.selector)
+			if err != nil {
+				t.Fatalf("SelectVMs() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SelectVMs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSelectVMsInvalidQuery(t *testing.T) {
+	if _, err := SelectVMs(testMigration(), Selector{Query: "where where"}); err == nil {
+		t.Error("expected an error for an invalid query")
+	}
+}
+
+func TestAppendUnique(t *testing.T) {
+	got := appendUnique([]string{"a", "b"}, []string{"b", "c", "c"})
+	if want := []string{"a", "b", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("appendUnique() = %v, want %v", got, want)
+	}
+}
+
+func TestSummarizeVMs(t *testing.T) {
+	got := summarizeVMs(testMigration(), []string{"copying", "failed", "not-started"})
+	want := []map[string]interface{}{
+		{"name": "copying", "state": "Running", "phase": "CopyDisks"},
+		{"name": "failed", "state": "Failed", "phase": ""},
+		{"name": "not-started", "state": StatusPending, "phase": ""},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("summarizeVMs() = %v, want %v", got, want)
+	}
+}
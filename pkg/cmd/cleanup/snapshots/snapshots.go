@@ -0,0 +1,350 @@
+package snapshots
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/klog/v2"
+
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/get/inventory"
+	planstatus "github.com/yaacov/kubectl-mtv/pkg/cmd/get/plan/status"
+	"github.com/yaacov/kubectl-mtv/pkg/util/client"
+	"github.com/yaacov/kubectl-mtv/pkg/util/output"
+)
+
+// PrecopyDescription is the description Forklift gives the snapshots taken for warm migration precopies
+const PrecopyDescription = "Forklift Operator warm migration precopy"
+
+// Snapshot is a warm migration snapshot left on a source VM
+type Snapshot struct {
+	Plan         string `json:"plan"`
+	Migration    string `json:"migration,omitempty"`
+	Provider     string `json:"provider"`
+	ProviderType string `json:"providerType"`
+	VM           string `json:"vm"`
+	VMID         string `json:"vmID"`
+	Path         string `json:"path,omitempty"`
+	Snapshot     string `json:"snapshot"`
+}
+
+// Options selects the plans whose source VMs are checked
+type Options struct {
+	Namespace       string
+	Plan            string
+	Provider        string
+	InventoryURL    string
+	InsecureSkipTLS bool
+	Output          string
+}
+
+var snapshotColumns = []output.Column{
+	{Title: "PLAN", Key: "plan"},
+	{Title: "PROVIDER", Key: "provider"},
+	{Title: "VM", Key: "vm"},
+	{Title: "VM ID", Key: "vmID"},
+	{Title: "SNAPSHOT", Key: "snapshot"},
+	{Title: "MIGRATION", Key: "migration"},
+}
+
+// Print lists the leftover warm migration snapshots of the selected plans as table, json,
+// yaml or a removal script. Nothing is removed: neither Forklift nor its inventory offer
+// an API to delete source snapshots, so the script is meant to be reviewed and run by hand.
+func Print(ctx context.Context, configFlags *genericclioptions.ConfigFlags, opts Options) error {
+	found, err := Find(ctx, configFlags, opts)
+	if err != nil {
+		return err
+	}
+
+	emptyMsg := "No leftover warm migration snapshots found"
+	switch strings.ToLower(opts.Output) {
+	case "json":
+		return output.PrintJSONWithEmpty(snapshotItems(found), emptyMsg)
+	case "yaml":
+		return output.PrintYAMLWithEmpty(snapshotItems(found), emptyMsg)
+	case "script":
+		return WriteScript(os.Stdout, found)
+	case "", "table":
+		return output.PrintTableWithQuery(snapshotItems(found), snapshotColumns, nil, emptyMsg)
+	default:
+		return fmt.Errorf("unsupported output format: %s. Supported formats: table, json, yaml, script", opts.Output)
+	}
+}
+
+// Find returns the leftover warm migration snapshots of the plans selected by name or
+// source provider. Plans with a running migration are skipped, their snapshots are in use.
+func Find(ctx context.Context, configFlags *genericclioptions.ConfigFlags, opts Options) ([]Snapshot, error) {
+	if opts.Plan == "" && opts.Provider == "" {
+		return nil, fmt.Errorf("either --plan or --provider is required")
+	}
+
+	c, err := client.GetDynamicClient(configFlags)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get client: %v", err)
+	}
+
+	var plans []unstructured.Unstructured
+	if opts.Plan != "" {
+		plan, err := c.Resource(client.PlansGVR).Namespace(opts.Namespace).Get(ctx, opts.Plan, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get plan '%s': %v", opts.Plan, err)
+		}
+		plans = append(plans, *plan)
+	} else {
+		list, err := c.Resource(client.PlansGVR).Namespace(opts.Namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list plans: %v", err)
+		}
+		plans = list.Items
+	}
+
+	migrations, err := c.Resource(client.MigrationsGVR).Namespace(opts.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list migrations: %v", err)
+	}
+
+	// VMs are fetched once per source provider, several plans often share one
+	providerVMs := map[string][]map[string]interface{}{}
+	providerTypes := map[string]string{}
+
+	result := []Snapshot{}
+	for i := range plans {
+		plan := &plans[i]
+		providerName, _, _ := unstructured.NestedString(plan.Object, "spec", "provider", "source", "name")
+		if opts.Provider != "" && providerName != opts.Provider {
+			continue
+		}
+		if providerName == "" {
+			klog.V(1).Infof("Plan '%s' has no source provider, skipping", plan.GetName())
+			continue
+		}
+
+		running, _, err := planstatus.GetRunningMigration(c, plan.GetNamespace(), plan, client.MigrationsGVR)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get migrations of plan '%s': %v", plan.GetName(), err)
+		}
+		if running != nil {
+			fmt.Fprintf(os.Stderr, "Warning: plan '%s' has a running migration '%s', its snapshots are skipped\n", plan.GetName(), running.GetName())
+			continue
+		}
+
+		planMigrations := []unstructured.Unstructured{}
+		for _, m := range migrations.Items {
+			if name, _, _ := unstructured.NestedString(m.Object, "spec", "plan", "name"); name == plan.GetName() {
+				planMigrations = append(planMigrations, m)
+			}
+		}
+		precopies := PrecopySnapshots(planMigrations)
+		planVMs := planVMIDs(plan)
+
+		providerNamespace, _, _ := unstructured.NestedString(plan.Object, "spec", "provider", "source", "namespace")
+		if providerNamespace == "" {
+			providerNamespace = plan.GetNamespace()
+		}
+		key := providerNamespace + "/" + providerName
+		vms, ok := providerVMs[key]
+		if !ok {
+			providerTypes[key], vms, err = fetchVMs(ctx, configFlags, providerName, providerNamespace, opts)
+			if err != nil {
+				return nil, err
+			}
+			providerVMs[key] = vms
+		}
+
+		for _, vm := range vms {
+			id, _ := vm["id"].(string)
+			if !planVMs[id] {
+				continue
+			}
+			result = append(result, LeftoverSnapshots(providerTypes[key], vm, precopies[id], plan.GetName(), providerName)...)
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Plan != result[j].Plan {
+			return result[i].Plan < result[j].Plan
+		}
+		return result[i].VM < result[j].VM
+	})
+	return result, nil
+}
+
+// fetchVMs returns the provider type and the inventory VMs of a source provider
+func fetchVMs(ctx context.Context, configFlags *genericclioptions.ConfigFlags, name, namespace string, opts Options) (string, []map[string]interface{}, error) {
+	provider, err := inventory.GetProviderByName(ctx, configFlags, name, namespace)
+	if err != nil {
+		return "", nil, err
+	}
+	providerClient := inventory.NewProviderClientWithInsecure(configFlags, provider, opts.InventoryURL, opts.InsecureSkipTLS)
+	providerType, err := providerClient.GetProviderType()
+	if err != nil {
+		return "", nil, err
+	}
+	if providerType != "vsphere" && providerType != "ovirt" {
+		return "", nil, fmt.Errorf("provider '%s' is of type '%s', warm migration snapshots are only created on vsphere and ovirt providers", name, providerType)
+	}
+
+	data, err := providerClient.GetVMs(ctx, 4)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to get VMs of provider '%s': %v", name, err)
+	}
+	list, ok := data.([]interface{})
+	if !ok {
+		return "", nil, fmt.Errorf("unexpected inventory response for VMs of provider '%s'", name)
+	}
+	vms := make([]map[string]interface{}, 0, len(list))
+	for _, v := range list {
+		if vm, ok := v.(map[string]interface{}); ok {
+			vms = append(vms, vm)
+		}
+	}
+	return providerType, vms, nil
+}
+
+// planVMIDs returns the IDs of the VMs of a plan
+func planVMIDs(plan *unstructured.Unstructured) map[string]bool {
+	ids := map[string]bool{}
+	vms, _, _ := unstructured.NestedSlice(plan.Object, "spec", "vms")
+	for _, v := range vms {
+		if vm, ok := v.(map[string]interface{}); ok {
+			if id, _ := vm["id"].(string); id != "" {
+				ids[id] = true
+			}
+		}
+	}
+	return ids
+}
+
+// PrecopySnapshots returns the snapshots taken by the warm migration precopies of the
+// migrations, by VM ID and snapshot ID, with the name of the migration that took them
+func PrecopySnapshots(migrations []unstructured.Unstructured) map[string]map[string]string {
+	result := map[string]map[string]string{}
+	for _, m := range migrations {
+		vms, _, _ := unstructured.NestedSlice(m.Object, "status", "vms")
+		for _, v := range vms {
+			vm, ok := v.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			id, _ := vm["id"].(string)
+			precopies, _, _ := unstructured.NestedSlice(vm, "warm", "precopies")
+			for _, p := range precopies {
+				precopy, ok := p.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				snapshot, _ := precopy["snapshot"].(string)
+				if snapshot == "" {
+					continue
+				}
+				if result[id] == nil {
+					result[id] = map[string]string{}
+				}
+				result[id][snapshot] = m.GetName()
+			}
+		}
+	}
+	return result
+}
+
+// LeftoverSnapshots returns the warm migration snapshots still present on an inventory VM.
+// vSphere VMs only expose their current snapshot, which is leftover when a precopy took it.
+// oVirt VMs list their snapshots, which are recognized by the precopy description.
+func LeftoverSnapshots(providerType string, vm map[string]interface{}, precopies map[string]string, plan, provider string) []Snapshot {
+	name, _ := vm["name"].(string)
+	id, _ := vm["id"].(string)
+	path, _ := vm["path"].(string)
+	snapshot := func(snapshotID string) Snapshot {
+		return Snapshot{
+			Plan:         plan,
+			Migration:    precopies[snapshotID],
+			Provider:     provider,
+			ProviderType: providerType,
+			VM:           name,
+			VMID:         id,
+			Path:         path,
+			Snapshot:     snapshotID,
+		}
+	}
+
+	result := []Snapshot{}
+	switch providerType {
+	case "vsphere":
+		current, _, _ := unstructured.NestedString(vm, "snapshot", "id")
+		if _, ok := precopies[current]; ok && current != "" {
+			result = append(result, snapshot(current))
+		}
+	case "ovirt":
+		list, _, _ := unstructured.NestedSlice(vm, "snapshots")
+		for _, s := range list {
+			item, ok := s.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			snapshotID, _ := item["id"].(string)
+			description, _ := item["description"].(string)
+			if _, ok := precopies[snapshotID]; ok || description == PrecopyDescription {
+				result = append(result, snapshot(snapshotID))
+			}
+		}
+	}
+	return result
+}
+
+// WriteScript writes a shell script removing the snapshots: govc commands for vSphere,
+// and REST calls against the oVirt engine API for oVirt
+func WriteScript(w io.Writer, snapshots []Snapshot) error {
+	var b strings.Builder
+	b.WriteString("#!/bin/sh\n")
+	b.WriteString("# Leftover warm migration snapshots, review before running.\n")
+	b.WriteString("# vSphere commands need govc with GOVC_URL, GOVC_USERNAME and GOVC_PASSWORD set.\n")
+	b.WriteString("# oVirt commands need OVIRT_URL (https://engine/ovirt-engine/api) and OVIRT_AUTH (user@domain:password) set.\n")
+	b.WriteString("set -e\n")
+	if len(snapshots) == 0 {
+		b.WriteString("# No leftover warm migration snapshots found\n")
+	}
+	for _, s := range snapshots {
+		fmt.Fprintf(&b, "\n# plan %s, VM %s (%s)\n", s.Plan, s.VM, s.VMID)
+		switch s.ProviderType {
+		case "vsphere":
+			vm := s.Path
+			if vm == "" {
+				vm = s.VM
+			}
+			fmt.Fprintf(&b, "govc snapshot.remove -vm %s %s\n", shellQuote(vm), shellQuote(s.Snapshot))
+		case "ovirt":
+			fmt.Fprintf(&b, "curl -sf -u \"$OVIRT_AUTH\" -X DELETE \"$OVIRT_URL\"/vms/%s/snapshots/%s\n", shellQuote(s.VMID), shellQuote(s.Snapshot))
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// snapshotItems converts the snapshots to output items
+func snapshotItems(snapshots []Snapshot) []map[string]interface{} {
+	items := make([]map[string]interface{}, 0, len(snapshots))
+	for _, s := range snapshots {
+		items = append(items, map[string]interface{}{
+			"plan":         s.Plan,
+			"migration":    s.Migration,
+			"provider":     s.Provider,
+			"providerType": s.ProviderType,
+			"vm":           s.VM,
+			"vmID":         s.VMID,
+			"path":         s.Path,
+			"snapshot":     s.Snapshot,
+		})
+	}
+	return items
+}
+
+// shellQuote quotes a value for a POSIX shell
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
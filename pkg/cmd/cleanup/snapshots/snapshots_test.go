@@ -0,0 +1,87 @@
+package snapshots
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestPrecopySnapshots(t *testing.T) {
+	migration := unstructured.Unstructured{Object: map[string]interface{}{
+		"status": map[string]interface{}{
+			"vms": []interface{}{
+				map[string]interface{}{
+					"id": "vm-1",
+					"warm": map[string]interface{}{
+						"precopies": []interface{}{
+							map[string]interface{}{"snapshot": "snapshot-10"},
+							map[string]interface{}{"snapshot": "snapshot-11"},
+						},
+					},
+				},
+				map[string]interface{}{"id": "vm-2"},
+			},
+		},
+	}}
+	migration.SetName("warm-plan-x1")
+
+	got := PrecopySnapshots([]unstructured.Unstructured{migration})
+	if len(got) != 1 || len(got["vm-1"]) != 2 || got["vm-1"]["snapshot-11"] != "warm-plan-x1" {
+		t.Errorf("PrecopySnapshots() = %v", got)
+	}
+}
+
+func TestLeftoverSnapshots(t *testing.T) {
+	precopies := map[string]string{"snapshot-11": "warm-plan-x1"}
+
+	vsphere := map[string]interface{}{
+		"id":       "vm-1",
+		"name":     "web-01",
+		"path":     "/dc/vm/web-01",
+		"snapshot": map[string]interface{}{"kind": "VirtualMachineSnapshot", "id": "snapshot-11"},
+	}
+	got := LeftoverSnapshots("vsphere", vsphere, precopies, "warm-plan", "vsphere-prod")
+	if len(got) != 1 || got[0].Snapshot != "snapshot-11" || got[0].Migration != "warm-plan-x1" || got[0].Path != "/dc/vm/web-01" {
+		t.Errorf("vsphere leftovers = %+v", got)
+	}
+
+	// A snapshot the user took after the migration is not reported
+	vsphere["snapshot"] = map[string]interface{}{"id": "snapshot-20"}
+	if got := LeftoverSnapshots("vsphere", vsphere, precopies, "warm-plan", "vsphere-prod"); len(got) != 0 {
+		t.Errorf("unexpected vsphere leftovers = %+v", got)
+	}
+
+	ovirt := map[string]interface{}{
+		"id":   "a1b2",
+		"name": "db-01",
+		"snapshots": []interface{}{
+			map[string]interface{}{"id": "s-active", "description": "Active VM"},
+			map[string]interface{}{"id": "s-precopy", "description": PrecopyDescription},
+		},
+	}
+	got = LeftoverSnapshots("ovirt", ovirt, nil, "warm-plan", "ovirt-prod")
+	if len(got) != 1 || got[0].Snapshot != "s-precopy" || got[0].VM != "db-01" {
+		t.Errorf("ovirt leftovers = %+v", got)
+	}
+}
+
+func TestWriteScript(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteScript(&buf, []Snapshot{
+		{Plan: "warm-plan", VM: "web-01", VMID: "vm-1", Path: "/dc/vm/web 01", ProviderType: "vsphere", Snapshot: "snapshot-11"},
+		{Plan: "warm-plan", VM: "db-01", VMID: "a1b2", ProviderType: "ovirt", Snapshot: "s-precopy"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"govc snapshot.remove -vm '/dc/vm/web 01' 'snapshot-11'",
+		`"$OVIRT_URL"/vms/'a1b2'/snapshots/'s-precopy'`,
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("script missing %q:\n%s", want, buf.String())
+		}
+	}
+}
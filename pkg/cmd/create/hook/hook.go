@@ -68,7 +68,7 @@ func Create(opts CreateHookOptions) error {
 		return fmt.Errorf("failed to create hook %s: %v", opts.Name, err)
 	}
 
-	fmt.Printf("hook/%s created\n", createdHook.GetName())
+	fmt.Printf("hook/%s created%s\n", createdHook.GetName(), client.DryRunSuffix())
 	klog.V(2).Infof("Created hook '%s' in namespace '%s'", opts.Name, opts.Namespace)
 
 	return nil
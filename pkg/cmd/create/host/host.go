@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/sha256"
 	"fmt"
+	"slices"
 	"strings"
 
 	corev1 "k8s.io/api/core/v1"
@@ -18,6 +19,7 @@ import (
 	"github.com/yaacov/kubectl-mtv/pkg/cmd/get/inventory"
 	"github.com/yaacov/kubectl-mtv/pkg/util/client"
 	"github.com/yaacov/kubectl-mtv/pkg/util/output"
+	"github.com/yaacov/kubectl-mtv/pkg/util/query"
 )
 
 // CreateHostOptions encapsulates the parameters for creating migration hosts.
@@ -25,6 +27,7 @@ import (
 // and TLS settings for host connections.
 type CreateHostOptions struct {
 	HostIDs                  []string
+	HostQuery                string
 	Namespace                string
 	Provider                 string
 	ConfigFlags              *genericclioptions.ConfigFlags
@@ -35,6 +38,7 @@ type CreateHostOptions struct {
 	ExistingSecret           string
 	IPAddress                string
 	NetworkAdapterName       string
+	VSwitch                  string
 	HostInsecureSkipTLS      bool
 	CACert                   string
 	HostSpec                 forkliftv1beta1.HostSpec
@@ -61,8 +65,15 @@ func Create(ctx context.Context, opts CreateHostOptions) error {
 		return fmt.Errorf("failed to get provider hosts: %v", err)
 	}
 
-	// Ensure all requested host IDs exist in the provider's inventory
-	if err := validateHostIDs(opts.HostIDs, availableHosts); err != nil {
+	// Select the hosts with the TSL query, or ensure all requested host IDs exist in the provider's inventory
+	hostIDs := opts.HostIDs
+	if opts.HostQuery != "" {
+		hostIDs, err = selectHostIDs(opts.HostQuery, availableHosts)
+		if err != nil {
+			return err
+		}
+		klog.V(2).Infof("Host query selected %d hosts: %s", len(hostIDs), strings.Join(hostIDs, ", "))
+	} else if err := validateHostIDs(hostIDs, availableHosts); err != nil {
 		return err
 	}
 
@@ -94,7 +105,7 @@ func Create(ctx context.Context, opts CreateHostOptions) error {
 	} else {
 		// Create new secret with provided credentials
 		// Use first host ID for secret naming when creating multiple hosts
-		firstHostID := opts.HostIDs[0]
+		firstHostID := hostIDs[0]
 		firstHostResourceName := firstHostID + "-" + generateHash(firstHostID)
 		if opts.DryRun {
 			sec := buildHostSecretObject(opts.Namespace, firstHostResourceName, opts.Username, opts.Password, opts.HostInsecureSkipTLS, opts.CACert, true)
@@ -118,9 +129,9 @@ func Create(ctx context.Context, opts CreateHostOptions) error {
 	}
 
 	// Create each host resource with proper ownership and secret references
-	for _, hostID := range opts.HostIDs {
-		// Resolve IP address from direct input or network adapter lookup
-		hostIP, err := resolveHostIPAddress(opts.IPAddress, opts.NetworkAdapterName, hostID, availableHosts)
+	for _, hostID := range hostIDs {
+		// Resolve IP address from direct input, network adapter or vSwitch lookup
+		hostIP, err := resolveHostIPAddress(opts.IPAddress, opts.NetworkAdapterName, opts.VSwitch, hostID, availableHosts)
 		if err != nil {
 			return fmt.Errorf("failed to resolve IP address for host %s: %v", hostID, err)
 		}
@@ -150,7 +161,7 @@ func Create(ctx context.Context, opts CreateHostOptions) error {
 		}
 
 		// Inform user about the created resource
-		fmt.Printf("host/%s created\n", hostObj.Name)
+		fmt.Printf("host/%s created%s\n", hostObj.Name, client.DryRunSuffix())
 
 		klog.V(2).Infof("Created host '%s' in namespace '%s'", hostID, opts.Namespace)
 	}
@@ -250,25 +261,62 @@ func validateHostIDs(hostIDs []string, availableHosts []map[string]interface{})
 	return nil
 }
 
+// selectHostIDs returns the IDs of the inventory hosts matching a TSL query,
+// e.g. "where cluster = 'prod' and inMaintenance = false".
+func selectHostIDs(hostQuery string, availableHosts []map[string]interface{}) ([]string, error) {
+	queryOpts, err := query.ParseQueryString(hostQuery)
+	if err != nil {
+		return nil, fmt.Errorf("invalid host query: %v", err)
+	}
+
+	matched, err := query.ApplyQuery(availableHosts, queryOpts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply host query: %v", err)
+	}
+
+	hostIDs := make([]string, 0, len(matched))
+	for _, host := range matched {
+		if id, ok := host["id"].(string); ok {
+			hostIDs = append(hostIDs, id)
+		}
+	}
+	if len(hostIDs) == 0 {
+		return nil, fmt.Errorf("no hosts in provider inventory match the query: %s", hostQuery)
+	}
+
+	return hostIDs, nil
+}
+
 // resolveHostIPAddress determines the IP address to use for host communication.
-// It supports either direct IP specification or lookup from a named network adapter
-// in the host's inventory data.
-func resolveHostIPAddress(directIP, networkAdapterName, hostID string, availableHosts []map[string]interface{}) (string, error) {
+// It supports either direct IP specification, lookup from a named network adapter
+// (port group) or lookup of the VMkernel adapter on a named vSwitch in the host's inventory data.
+func resolveHostIPAddress(directIP, networkAdapterName, vSwitch, hostID string, availableHosts []map[string]interface{}) (string, error) {
 	if directIP != "" {
 		return directIP, nil
 	}
 
+	var host map[string]interface{}
+	for _, h := range availableHosts {
+		if id, ok := h["id"].(string); ok && id == hostID {
+			host = h
+			break
+		}
+	}
+	if host == nil {
+		return "", fmt.Errorf("host '%s' not found in provider inventory", hostID)
+	}
+
+	if vSwitch != "" {
+		return resolveVSwitchIPAddress(vSwitch, hostID, host)
+	}
+
 	// Search through host inventory to find the specified network adapter
-	for _, host := range availableHosts {
-		if id, ok := host["id"].(string); ok && id == hostID {
-			if networkAdapters, ok := host["networkAdapters"].([]interface{}); ok {
-				for _, adapter := range networkAdapters {
-					if adapterMap, ok := adapter.(map[string]interface{}); ok {
-						if adapterName, ok := adapterMap["name"].(string); ok && adapterName == networkAdapterName {
-							if ipAddress, ok := adapterMap["ipAddress"].(string); ok {
-								return ipAddress, nil
-							}
-						}
+	if networkAdapters, ok := host["networkAdapters"].([]interface{}); ok {
+		for _, adapter := range networkAdapters {
+			if adapterMap, ok := adapter.(map[string]interface{}); ok {
+				if adapterName, ok := adapterMap["name"].(string); ok && adapterName == networkAdapterName {
+					if ipAddress, ok := adapterMap["ipAddress"].(string); ok {
+						return ipAddress, nil
 					}
 				}
 			}
@@ -278,6 +326,61 @@ func resolveHostIPAddress(directIP, networkAdapterName, hostID string, available
 	return "", fmt.Errorf("network adapter '%s' not found for host '%s' or no IP address available", networkAdapterName, hostID)
 }
 
+// resolveVSwitchIPAddress finds the IP address of the VMkernel adapter connected to a port group
+// of the named standard vSwitch, using the host's networking inventory (switches, port groups and vNICs).
+// Several VMkernel adapters with different IPs on the vSwitch are ambiguous and return an error.
+func resolveVSwitchIPAddress(vSwitch, hostID string, host map[string]interface{}) (string, error) {
+	networking, _ := host["networking"].(map[string]interface{})
+
+	// Find the key of the named vSwitch, port groups reference their switch by key
+	switchKey := ""
+	switches, _ := networking["switches"].([]interface{})
+	for _, item := range switches {
+		if sw, ok := item.(map[string]interface{}); ok {
+			if name, _ := sw["name"].(string); name == vSwitch {
+				switchKey, _ = sw["key"].(string)
+				break
+			}
+		}
+	}
+	if switchKey == "" {
+		return "", fmt.Errorf("vSwitch '%s' not found for host '%s'", vSwitch, hostID)
+	}
+
+	portGroups := make(map[string]bool)
+	groups, _ := networking["portGroups"].([]interface{})
+	for _, item := range groups {
+		if pg, ok := item.(map[string]interface{}); ok {
+			if key, _ := pg["vSwitch"].(string); key == switchKey {
+				if name, ok := pg["name"].(string); ok {
+					portGroups[name] = true
+				}
+			}
+		}
+	}
+
+	var addresses []string
+	vnics, _ := networking["vNICs"].([]interface{})
+	for _, item := range vnics {
+		if vnic, ok := item.(map[string]interface{}); ok {
+			portGroup, _ := vnic["portGroup"].(string)
+			ipAddress, _ := vnic["ipAddress"].(string)
+			if portGroups[portGroup] && ipAddress != "" && !slices.Contains(addresses, ipAddress) {
+				addresses = append(addresses, ipAddress)
+			}
+		}
+	}
+
+	switch len(addresses) {
+	case 0:
+		return "", fmt.Errorf("no VMkernel adapter with an IP address on vSwitch '%s' for host '%s'", vSwitch, hostID)
+	case 1:
+		return addresses[0], nil
+	default:
+		return "", fmt.Errorf("vSwitch '%s' has several VMkernel IP addresses on host '%s' (%s), use --network-adapter to select the port group", vSwitch, hostID, strings.Join(addresses, ", "))
+	}
+}
+
 // buildSingleHost constructs a Host resource with provider ownership and secret reference without persisting it.
 // The provider parameter is the already-validated provider object fetched once by the caller.
 func buildSingleHost(ctx context.Context, configFlags *genericclioptions.ConfigFlags, namespace, hostID string, provider *unstructured.Unstructured, ipAddress string, secret *corev1.ObjectReference, availableHosts []map[string]interface{}) (*forkliftv1beta1.Host, error) {
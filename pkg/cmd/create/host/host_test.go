@@ -12,3 +12,73 @@ func TestBuildHostSecretObject_UsesStandardCACertKey(t *testing.T) {
 		t.Fatal("unexpected legacy cacert key in host secret")
 	}
 }
+
+func testHosts() []map[string]interface{} {
+	networking := func(vmk1IP string) map[string]interface{} {
+		return map[string]interface{}{
+			"switches": []interface{}{
+				map[string]interface{}{"key": "key-vim.host.VirtualSwitch-vSwitch0", "name": "vSwitch0"},
+				map[string]interface{}{"key": "key-vim.host.VirtualSwitch-vSwitch1", "name": "vSwitch1"},
+			},
+			"portGroups": []interface{}{
+				map[string]interface{}{"name": "Management Network", "vSwitch": "key-vim.host.VirtualSwitch-vSwitch0"},
+				map[string]interface{}{"name": "Migration", "vSwitch": "key-vim.host.VirtualSwitch-vSwitch1"},
+			},
+			"vNICs": []interface{}{
+				map[string]interface{}{"key": "vmk0", "portGroup": "Management Network", "ipAddress": "10.0.0.8"},
+				map[string]interface{}{"key": "vmk1", "portGroup": "Migration", "ipAddress": vmk1IP},
+			},
+		}
+	}
+	return []map[string]interface{}{
+		{"id": "host-8", "name": "esx-8", "cluster": "domain-c8", "inMaintenance": false, "networking": networking("192.168.10.8")},
+		{"id": "host-12", "name": "esx-12", "cluster": "domain-c8", "inMaintenance": true, "networking": networking("192.168.10.12")},
+		{"id": "host-15", "name": "esx-15", "cluster": "domain-c15", "inMaintenance": false, "networking": networking("")},
+	}
+}
+
+func TestSelectHostIDs(t *testing.T) {
+	ids, err := selectHostIDs("where cluster = 'domain-c8' and inMaintenance = false", testHosts())
+	if err != nil {
+		t.Fatalf("selectHostIDs() unexpected error: %v", err)
+	}
+	if len(ids) != 1 || ids[0] != "host-8" {
+		t.Errorf("selectHostIDs() = %v, want [host-8]", ids)
+	}
+
+	if _, err := selectHostIDs("where cluster = 'domain-c99'", testHosts()); err == nil {
+		t.Error("selectHostIDs() with no matching hosts should return an error")
+	}
+}
+
+func TestResolveHostIPAddress_VSwitch(t *testing.T) {
+	hosts := testHosts()
+
+	tests := []struct {
+		name    string
+		vSwitch string
+		hostID  string
+		want    string
+		wantErr bool
+	}{
+		{"management vSwitch", "vSwitch0", "host-8", "10.0.0.8", false},
+		{"migration vSwitch", "vSwitch1", "host-12", "192.168.10.12", false},
+		{"vmkernel adapter without IP", "vSwitch1", "host-15", "", true},
+		{"unknown vSwitch", "vSwitch9", "host-8", "", true},
+		{"unknown host", "vSwitch0", "host-99", "", true},
+	}
+	for _, tt := range tests {
+		got, err := resolveHostIPAddress("", "", tt.vSwitch, tt.hostID, hosts)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("%s: resolveHostIPAddress() = %q, %v, want %q (error %v)", tt.name, got, err, tt.want, tt.wantErr)
+		}
+	}
+
+	// Two VMkernel adapters with different IPs on the same vSwitch are ambiguous
+	networking := hosts[0]["networking"].(map[string]interface{})
+	networking["vNICs"] = append(networking["vNICs"].([]interface{}),
+		map[string]interface{}{"key": "vmk2", "portGroup": "Migration", "ipAddress": "192.168.10.9"})
+	if _, err := resolveHostIPAddress("", "", "vSwitch1", "host-8", hosts); err == nil {
+		t.Error("resolveHostIPAddress() with several IPs on the vSwitch should return an error")
+	}
+}
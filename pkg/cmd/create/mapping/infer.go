@@ -0,0 +1,243 @@
+package mapping
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/create/plan/network"
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/create/plan/storage"
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/get/inventory"
+	"github.com/yaacov/kubectl-mtv/pkg/util/client"
+)
+
+// InferOptions holds options for proposing a mapping from the networks or storages used by a set of VMs
+type InferOptions struct {
+	ConfigFlags              *genericclioptions.ConfigFlags
+	Name                     string
+	Namespace                string
+	SourceProvider           string
+	TargetProvider           string
+	InventoryURL             string
+	InventoryInsecureSkipTLS bool
+	// FromPlan takes the VMs, providers and target namespace from an existing plan
+	FromPlan string
+	// VMs is a comma-separated list of VM names or a "where ..." inventory query
+	VMs string
+	// DefaultTargetNetwork is the target of the inferred network pairs ("default" is pod networking)
+	DefaultTargetNetwork string
+	// DefaultTargetStorageClass is the target of the inferred storage pairs, empty for the cluster default
+	DefaultTargetStorageClass string
+	DefaultVolumeMode         string
+	DefaultAccessMode         string
+	// Yes creates the proposed mapping, otherwise it is only printed
+	Yes          bool
+	OutputFormat string
+}
+
+// inferredVMs are the VMs and providers a mapping is inferred from
+type inferredVMs struct {
+	sourceProvider          string
+	sourceProviderNamespace string
+	targetProvider          string
+	targetProviderNamespace string
+	targetNamespace         string
+	names                   []string
+}
+
+// InferNetwork proposes a network mapping for the networks used by the selected VMs
+func InferNetwork(ctx context.Context, opts InferOptions) error {
+	vms, err := resolveInferredVMs(ctx, opts)
+	if err != nil {
+		return err
+	}
+	printInferHeader("network", opts, vms)
+
+	name, err := network.CreateNetworkMap(ctx, network.NetworkMapperOptions{
+		Name:                     opts.Name,
+		MapName:                  opts.Name,
+		Namespace:                opts.Namespace,
+		TargetNamespace:          vms.targetNamespace,
+		SourceProvider:           vms.sourceProvider,
+		SourceProviderNamespace:  vms.sourceProviderNamespace,
+		TargetProvider:           vms.targetProvider,
+		TargetProviderNamespace:  vms.targetProviderNamespace,
+		ConfigFlags:              opts.ConfigFlags,
+		InventoryURL:             opts.InventoryURL,
+		InventoryInsecureSkipTLS: opts.InventoryInsecureSkipTLS,
+		PlanVMNames:              vms.names,
+		DefaultTargetNetwork:     opts.DefaultTargetNetwork,
+		DryRun:                   !opts.Yes,
+		OutputFormat:             opts.OutputFormat,
+	})
+	if err != nil {
+		return err
+	}
+	printInferResult("network", name, opts)
+	return nil
+}
+
+// InferStorage proposes a storage mapping for the datastores/storage domains used by the selected VMs
+func InferStorage(ctx context.Context, opts InferOptions) error {
+	vms, err := resolveInferredVMs(ctx, opts)
+	if err != nil {
+		return err
+	}
+	if opts.DefaultVolumeMode != "" {
+		if err := validateVolumeMode(opts.DefaultVolumeMode); err != nil {
+			return fmt.Errorf("invalid --default-volume-mode: %v", err)
+		}
+	}
+	if opts.DefaultAccessMode != "" {
+		if err := validateAccessMode(opts.DefaultAccessMode); err != nil {
+			return fmt.Errorf("invalid --default-access-mode: %v", err)
+		}
+	}
+	printInferHeader("storage", opts, vms)
+
+	name, _, err := storage.CreateStorageMap(ctx, storage.StorageMapperOptions{
+		Name:                      opts.Name,
+		MapName:                   opts.Name,
+		Namespace:                 opts.Namespace,
+		SourceProvider:            vms.sourceProvider,
+		SourceProviderNamespace:   vms.sourceProviderNamespace,
+		TargetProvider:            vms.targetProvider,
+		TargetProviderNamespace:   vms.targetProviderNamespace,
+		ConfigFlags:               opts.ConfigFlags,
+		InventoryURL:              opts.InventoryURL,
+		InventoryInsecureSkipTLS:  opts.InventoryInsecureSkipTLS,
+		PlanVMNames:               vms.names,
+		DefaultTargetStorageClass: opts.DefaultTargetStorageClass,
+		DefaultVolumeMode:         opts.DefaultVolumeMode,
+		DefaultAccessMode:         opts.DefaultAccessMode,
+		DryRun:                    !opts.Yes,
+		OutputFormat:              opts.OutputFormat,
+	})
+	if err != nil {
+		return err
+	}
+	printInferResult("storage", name, opts)
+	return nil
+}
+
+// resolveInferredVMs returns the VMs and providers selected by --from-plan or --vms.
+// Explicit --source and --target values take precedence over the plan providers.
+func resolveInferredVMs(ctx context.Context, opts InferOptions) (*inferredVMs, error) {
+	vms := &inferredVMs{targetNamespace: opts.Namespace}
+	sourceRef, targetRef := opts.SourceProvider, opts.TargetProvider
+
+	if opts.FromPlan != "" {
+		c, err := client.GetDynamicClient(opts.ConfigFlags)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get client: %v", err)
+		}
+		plan, err := c.Resource(client.PlansGVR).Namespace(opts.Namespace).Get(ctx, opts.FromPlan, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get plan '%s': %v", opts.FromPlan, err)
+		}
+		planSource, planTarget, targetNamespace, names := planInferSource(plan)
+		if sourceRef == "" {
+			sourceRef = planSource
+		}
+		if targetRef == "" {
+			targetRef = planTarget
+		}
+		if targetNamespace != "" {
+			vms.targetNamespace = targetNamespace
+		}
+		vms.names = names
+	}
+
+	if sourceRef == "" {
+		return nil, fmt.Errorf("source provider is required, use --source or --from-plan")
+	}
+	if targetRef == "" {
+		return nil, fmt.Errorf("target provider is required, use --target or --from-plan")
+	}
+	vms.sourceProvider, vms.sourceProviderNamespace = parseProviderReference(sourceRef, opts.Namespace)
+	vms.targetProvider, vms.targetProviderNamespace = parseProviderReference(targetRef, opts.Namespace)
+
+	if opts.VMs != "" {
+		names, err := selectVMNames(ctx, opts, vms.sourceProvider, vms.sourceProviderNamespace)
+		if err != nil {
+			return nil, err
+		}
+		vms.names = names
+	}
+
+	if len(vms.names) == 0 {
+		return nil, fmt.Errorf("no VMs selected, use --from-plan or --vms to choose the VMs to inspect")
+	}
+	return vms, nil
+}
+
+// planInferSource returns the providers ("namespace/name"), target namespace and VM names of a plan
+func planInferSource(plan *unstructured.Unstructured) (source, target, targetNamespace string, names []string) {
+	providerRef := func(role string) string {
+		name, _, _ := unstructured.NestedString(plan.Object, "spec", "provider", role, "name")
+		namespace, _, _ := unstructured.NestedString(plan.Object, "spec", "provider", role, "namespace")
+		if name == "" || namespace == "" {
+			return name
+		}
+		return namespace + "/" + name
+	}
+	targetNamespace, _, _ = unstructured.NestedString(plan.Object, "spec", "targetNamespace")
+
+	planVMs, _, _ := unstructured.NestedSlice(plan.Object, "spec", "vms")
+	for _, v := range planVMs {
+		vm, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if name, _, _ := unstructured.NestedString(vm, "name"); name != "" {
+			names = append(names, name)
+		}
+	}
+	return providerRef("source"), providerRef("destination"), targetNamespace, names
+}
+
+// selectVMNames returns the VM names of a comma-separated list or of a "where ..." inventory query
+func selectVMNames(ctx context.Context, opts InferOptions, sourceProvider, sourceProviderNamespace string) ([]string, error) {
+	if !strings.HasPrefix(opts.VMs, "where ") {
+		names := []string{}
+		for _, name := range strings.Split(opts.VMs, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				names = append(names, name)
+			}
+		}
+		return names, nil
+	}
+
+	vmList, err := inventory.FetchVMsByQueryWithInsecure(ctx, opts.ConfigFlags, sourceProvider, sourceProviderNamespace, opts.InventoryURL, opts.VMs, opts.InventoryInsecureSkipTLS)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch VMs using query: %v", err)
+	}
+	names := []string{}
+	for _, vm := range vmList {
+		names = append(names, vm.Name)
+	}
+	return names, nil
+}
+
+// printInferHeader describes on stderr what the proposed mapping is based on
+func printInferHeader(kind string, opts InferOptions, vms *inferredVMs) {
+	source := fmt.Sprintf("%d VM(s)", len(vms.names))
+	if opts.FromPlan != "" && opts.VMs == "" {
+		source = fmt.Sprintf("the %d VM(s) of plan '%s'", len(vms.names), opts.FromPlan)
+	}
+	fmt.Fprintf(os.Stderr, "Inferring %s mapping '%s' from %s of provider '%s'\n", kind, opts.Name, source, vms.sourceProvider)
+}
+
+// printInferResult tells how to apply a proposed mapping, or confirms its creation
+func printInferResult(kind, name string, opts InferOptions) {
+	if opts.Yes {
+		fmt.Printf("%smap/%s created%s\n", kind, name, client.DryRunSuffix())
+		return
+	}
+	fmt.Fprintf(os.Stderr, "The %s mapping above was not created. Review it, then re-run with --yes to create it.\n", kind)
+}
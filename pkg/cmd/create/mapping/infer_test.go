@@ -0,0 +1,64 @@
+package mapping
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestPlanInferSource(t *testing.T) {
+	plan := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"targetNamespace": "apps",
+			"provider": map[string]interface{}{
+				"source":      map[string]interface{}{"name": "vsphere", "namespace": "mtv"},
+				"destination": map[string]interface{}{"name": "host"},
+			},
+			"vms": []interface{}{
+				map[string]interface{}{"name": "web", "id": "vm-1"},
+				map[string]interface{}{"id": "vm-2"},
+				map[string]interface{}{"name": "db"},
+			},
+		},
+	}}
+
+	source, target, targetNamespace, names := planInferSource(plan)
+	if source != "mtv/vsphere" || target != "host" || targetNamespace != "apps" {
+		t.Errorf("planInferSource() = %q, %q, %q", source, target, targetNamespace)
+	}
+	if len(names) != 2 || names[0] != "web" || names[1] != "db" {
+		t.Errorf("planInferSource() names = %v, want [web db]", names)
+	}
+}
+
+func TestResolveInferredVMs_Names(t *testing.T) {
+	vms, err := resolveInferredVMs(context.Background(), InferOptions{
+		Namespace:      "mtv",
+		SourceProvider: "other/vsphere",
+		TargetProvider: "host",
+		VMs:            " web , db,",
+	})
+	if err != nil {
+		t.Fatalf("resolveInferredVMs() unexpected error: %v", err)
+	}
+	if vms.sourceProvider != "vsphere" || vms.sourceProviderNamespace != "other" || vms.targetProviderNamespace != "mtv" {
+		t.Errorf("unexpected providers: %+v", vms)
+	}
+	if len(vms.names) != 2 || vms.names[0] != "web" || vms.targetNamespace != "mtv" {
+		t.Errorf("unexpected VMs: %+v", vms)
+	}
+}
+
+func TestResolveInferredVMs_Errors(t *testing.T) {
+	tests := map[string]InferOptions{
+		"no source": {Namespace: "mtv", TargetProvider: "host", VMs: "web"},
+		"no target": {Namespace: "mtv", SourceProvider: "vsphere", VMs: "web"},
+		"no VMs":    {Namespace: "mtv", SourceProvider: "vsphere", TargetProvider: "host", VMs: ","},
+	}
+	for name, opts := range tests {
+		if _, err := resolveInferredVMs(context.Background(), opts); err == nil {
+			t.Errorf("%s: resolveInferredVMs() expected error", name)
+		}
+	}
+}
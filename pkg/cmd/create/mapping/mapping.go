@@ -72,6 +72,11 @@ func ParseNetworkPairs(pairStr, defaultNamespace string, configFlags *genericcli
 	return parseNetworkPairs(context.TODO(), pairStr, defaultNamespace, configFlags, sourceProvider, inventoryURL)
 }
 
+// ParseNetworkPairsWithInsecure parses network pairs like ParseNetworkPairs, with optional insecure TLS skip verification
+func ParseNetworkPairsWithInsecure(pairStr, defaultNamespace string, configFlags *genericclioptions.ConfigFlags, sourceProvider, inventoryURL string, insecureSkipTLS bool) ([]forkliftv1beta1.NetworkPair, error) {
+	return parseNetworkPairsWithInsecure(context.TODO(), pairStr, defaultNamespace, configFlags, sourceProvider, inventoryURL, insecureSkipTLS)
+}
+
 // ParseStoragePairsWithOptions parses storage pairs with additional options for VolumeMode, AccessMode, and OffloadPlugin (exported for patch functionality)
 func ParseStoragePairsWithOptions(opts StorageParseOptions) ([]forkliftv1beta1.StoragePair, error) {
 	return parseStoragePairsWithOptions(context.TODO(), opts.PairStr, opts.DefaultNamespace, opts.ConfigFlags, opts.SourceProvider, opts.InventoryURL, opts.DefaultVolumeMode, opts.DefaultAccessMode, opts.DefaultOffloadPlugin, opts.DefaultOffloadSecret, opts.DefaultOffloadVendor, opts.DefaultOffloadMigrationHosts, opts.InventoryInsecureSkipTLS)
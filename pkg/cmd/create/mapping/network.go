@@ -238,7 +238,7 @@ func createNetworkMappingWithInsecure(configFlags *genericclioptions.ConfigFlags
 		return fmt.Errorf("failed to create network mapping: %v", err)
 	}
 
-	fmt.Printf("networkmap/%s created\n", name)
+	fmt.Printf("networkmap/%s created%s\n", name, client.DryRunSuffix())
 	return nil
 }
 
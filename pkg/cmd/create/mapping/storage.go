@@ -316,7 +316,7 @@ func createStorageMappingWithOptions(ctx context.Context, opts StorageCreateOpti
 		return fmt.Errorf("failed to create storage mapping: %v", err)
 	}
 
-	fmt.Printf("storagemap/%s created\n", opts.Name)
+	fmt.Printf("storagemap/%s created%s\n", opts.Name, client.DryRunSuffix())
 	return nil
 }
 
@@ -0,0 +1,174 @@
+package plan
+
+import (
+	"context"
+	"fmt"
+
+	forkliftv1beta1 "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/yaacov/kubectl-mtv/pkg/util/client"
+	"github.com/yaacov/kubectl-mtv/pkg/util/conditions"
+	"github.com/yaacov/kubectl-mtv/pkg/util/flags"
+	"github.com/yaacov/kubectl-mtv/pkg/util/output"
+)
+
+// ClonedFromAnnotation records the namespace/name of the plan a plan was cloned from
+const ClonedFromAnnotation = "kubectl-mtv/cloned-from"
+
+// cloneSkipAnnotations are annotations describing the source plan itself, not its settings
+var cloneSkipAnnotations = map[string]bool{
+	ClonedFromAnnotation:                               true,
+	conditions.HistoryAnnotation:                       true,
+	"kubectl.kubernetes.io/last-applied-configuration": true,
+}
+
+// CloneSource is the plan a new plan copies its spec from
+type CloneSource struct {
+	Name        string
+	Namespace   string
+	Spec        forkliftv1beta1.PlanSpec
+	Annotations map[string]string
+}
+
+// GetCloneSource reads the plan to clone. ref is a plan name or namespace/name.
+func GetCloneSource(ctx context.Context, configFlags *genericclioptions.ConfigFlags, ref, namespace string) (*CloneSource, error) {
+	sourceNamespace, sourceName, err := flags.ParseResourceRef(ref, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --from-plan value: %v", err)
+	}
+
+	c, err := client.GetDynamicClient(configFlags)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get client: %v", err)
+	}
+	obj, err := c.Resource(client.PlansGVR).Namespace(sourceNamespace).Get(ctx, sourceName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get plan '%s' in namespace '%s': %v", sourceName, sourceNamespace, err)
+	}
+	return cloneSourceFromObject(obj)
+}
+
+// cloneSourceFromObject converts a plan object into a clone source. Only the spec is read,
+// the status of the plan is not copied.
+func cloneSourceFromObject(obj *unstructured.Unstructured) (*CloneSource, error) {
+	specMap, found, err := unstructured.NestedMap(obj.Object, "spec")
+	if err != nil || !found {
+		return nil, fmt.Errorf("plan '%s' has no spec", obj.GetName())
+	}
+	var spec forkliftv1beta1.PlanSpec
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(specMap, &spec); err != nil {
+		return nil, fmt.Errorf("failed to read plan '%s': %v", obj.GetName(), err)
+	}
+	return &CloneSource{
+		Name:        obj.GetName(),
+		Namespace:   obj.GetNamespace(),
+		Spec:        spec,
+		Annotations: obj.GetAnnotations(),
+	}, nil
+}
+
+// Apply fills the create options from the source plan. Options already set (VMs, target
+// namespace, description, mappings and mapping pairs) override the copied values. The copy
+// starts unarchived, and mappings that are not overridden are copied under new names so the
+// clone does not depend on mappings owned by the source plan.
+func (s *CloneSource) Apply(opts *CreatePlanOptions) {
+	spec := *s.Spec.DeepCopy()
+	spec.Archived = false
+
+	opts.SourceProvider = s.providerRef(spec.Provider.Source)
+	opts.TargetProvider = s.providerRef(spec.Provider.Destination)
+
+	if opts.PlanSpec.VMs != nil {
+		spec.VMs = opts.PlanSpec.VMs
+	}
+	if opts.PlanSpec.TargetNamespace != "" {
+		spec.TargetNamespace = opts.PlanSpec.TargetNamespace
+	}
+	if opts.PlanSpec.Description != "" {
+		spec.Description = opts.PlanSpec.Description
+	}
+
+	if opts.NetworkMapping == "" && opts.NetworkPairs == "" && spec.Map.Network.Name != "" {
+		ref := s.mapRef(spec.Map.Network)
+		opts.CloneNetworkMap = &ref
+	}
+	if opts.StorageMapping == "" && opts.StoragePairs == "" && spec.Map.Storage.Name != "" &&
+		spec.Type != forkliftv1beta1.MigrationOnlyConversion {
+		ref := s.mapRef(spec.Map.Storage)
+		opts.CloneStorageMap = &ref
+	}
+	opts.PlanSpec = spec
+
+	annotations := map[string]string{}
+	for key, value := range s.Annotations {
+		if !cloneSkipAnnotations[key] {
+			annotations[key] = value
+		}
+	}
+	for key, value := range opts.Annotations {
+		annotations[key] = value
+	}
+	annotations[ClonedFromAnnotation] = s.Namespace + "/" + s.Name
+	opts.Annotations = annotations
+}
+
+// SourceProvider returns the namespace/name of the source provider of the plan
+func (s *CloneSource) SourceProvider() string {
+	return s.providerRef(s.Spec.Provider.Source)
+}
+
+// providerRef returns the namespace/name reference of a provider of the source plan
+func (s *CloneSource) providerRef(ref corev1.ObjectReference) string {
+	namespace := ref.Namespace
+	if namespace == "" {
+		namespace = s.Namespace
+	}
+	return namespace + "/" + ref.Name
+}
+
+// mapRef returns a mapping reference of the source plan with its namespace resolved
+func (s *CloneSource) mapRef(ref corev1.ObjectReference) corev1.ObjectReference {
+	if ref.Namespace == "" {
+		ref.Namespace = s.Namespace
+	}
+	return ref
+}
+
+// copyMap creates a copy of a mapping of the cloned plan under a new name. Only the spec
+// is copied. In dry-run the copy is printed instead.
+func copyMap(ctx context.Context, c dynamic.Interface, gvr schema.GroupVersionResource, kind string, source corev1.ObjectReference, name, namespace string, dryRun bool, outputFormat string) error {
+	sourceMap, err := c.Resource(gvr).Namespace(source.Namespace).Get(ctx, source.Name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get %s '%s' in namespace '%s': %v", kind, source.Name, source.Namespace, err)
+	}
+	spec, found, err := unstructured.NestedMap(sourceMap.Object, "spec")
+	if err != nil || !found {
+		return fmt.Errorf("%s '%s' has no spec", kind, source.Name)
+	}
+
+	mapCopy := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": forkliftv1beta1.SchemeGroupVersion.String(),
+		"kind":       kind,
+		"metadata": map[string]interface{}{
+			"name":      name,
+			"namespace": namespace,
+		},
+		"spec": spec,
+	}}
+
+	if dryRun {
+		return output.OutputResource(mapCopy.Object, outputFormat)
+	}
+	if _, err := c.Resource(gvr).Namespace(namespace).Create(ctx, mapCopy, metav1.CreateOptions{}); err != nil {
+		return fmt.Errorf("failed to create %s '%s': %v", kind, name, err)
+	}
+	fmt.Printf("Copied %s '%s/%s' to '%s'\n", kind, source.Namespace, source.Name, name)
+	return nil
+}
@@ -0,0 +1,103 @@
+package plan
+
+import (
+	"testing"
+
+	planv1beta1 "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/yaacov/kubectl-mtv/pkg/util/conditions"
+)
+
+func testSourcePlan() *unstructured.Unstructured {
+	p := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "forklift.konveyor.io/v1beta1",
+		"kind":       "Plan",
+		"metadata": map[string]interface{}{
+			"name":      "wave1",
+			"namespace": "team-a",
+			"annotations": map[string]interface{}{
+				"kubectl-mtv/max-concurrent-vms": "5",
+				conditions.HistoryAnnotation:     "{}",
+			},
+		},
+		"spec": map[string]interface{}{
+			"archived":        true,
+			"targetNamespace": "team-a-vms",
+			"provider": map[string]interface{}{
+				"source":      map[string]interface{}{"name": "vsphere"},
+				"destination": map[string]interface{}{"name": "host", "namespace": "openshift-mtv"},
+			},
+			"map": map[string]interface{}{
+				"network": map[string]interface{}{"name": "wave1-network"},
+				"storage": map[string]interface{}{"name": "wave1-storage", "namespace": "team-a"},
+			},
+			"vms": []interface{}{
+				map[string]interface{}{"id": "vm-1", "name": "web"},
+				map[string]interface{}{"id": "vm-2", "name": "db"},
+			},
+		},
+		"status": map[string]interface{}{"conditions": []interface{}{map[string]interface{}{"type": "Failed", "status": "True"}}},
+	}}
+	return p
+}
+
+func TestCloneSourceApply(t *testing.T) {
+	source, err := cloneSourceFromObject(testSourcePlan())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	opts := CreatePlanOptions{Name: "wave2", Namespace: "team-b"}
+	source.Apply(&opts)
+
+	if opts.SourceProvider != "team-a/vsphere" || opts.TargetProvider != "openshift-mtv/host" {
+		t.Errorf("providers = %s, %s", opts.SourceProvider, opts.TargetProvider)
+	}
+	if opts.PlanSpec.Archived {
+		t.Error("clone should not be archived")
+	}
+	if len(opts.PlanSpec.VMs) != 2 || opts.PlanSpec.TargetNamespace != "team-a-vms" {
+		t.Errorf("spec = %+v", opts.PlanSpec)
+	}
+	if opts.CloneNetworkMap == nil || opts.CloneNetworkMap.Namespace != "team-a" || opts.CloneNetworkMap.Name != "wave1-network" {
+		t.Errorf("network map = %+v", opts.CloneNetworkMap)
+	}
+	if opts.CloneStorageMap == nil || opts.CloneStorageMap.Name != "wave1-storage" {
+		t.Errorf("storage map = %+v", opts.CloneStorageMap)
+	}
+	if opts.Annotations[ClonedFromAnnotation] != "team-a/wave1" || opts.Annotations["kubectl-mtv/max-concurrent-vms"] != "5" {
+		t.Errorf("annotations = %v", opts.Annotations)
+	}
+	if _, ok := opts.Annotations[conditions.HistoryAnnotation]; ok {
+		t.Errorf("condition history copied: %v", opts.Annotations)
+	}
+}
+
+func TestCloneSourceApply_Overrides(t *testing.T) {
+	source, err := cloneSourceFromObject(testSourcePlan())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	opts := CreatePlanOptions{
+		Name:           "wave1-retry",
+		Namespace:      "team-a",
+		NetworkMapping: "shared-network",
+		StoragePairs:   "ds1:standard",
+	}
+	opts.PlanSpec.VMs = []planv1beta1.VM{{}}
+	opts.PlanSpec.VMs[0].Name = "db"
+	opts.PlanSpec.TargetNamespace = "retry-vms"
+	source.Apply(&opts)
+
+	if len(opts.PlanSpec.VMs) != 1 || opts.PlanSpec.VMs[0].Name != "db" {
+		t.Errorf("VMs = %+v", opts.PlanSpec.VMs)
+	}
+	if opts.PlanSpec.TargetNamespace != "retry-vms" {
+		t.Errorf("target namespace = %s", opts.PlanSpec.TargetNamespace)
+	}
+	if opts.CloneNetworkMap != nil || opts.CloneStorageMap != nil {
+		t.Errorf("overridden mappings copied: %+v, %+v", opts.CloneNetworkMap, opts.CloneStorageMap)
+	}
+}
@@ -0,0 +1,127 @@
+package plan
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	forkliftv1beta1 "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/klog/v2"
+
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/get/inventory"
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/settings"
+)
+
+// liveMigrationType is the plan type of KubeVirt-to-KubeVirt live migration
+const liveMigrationType = "live"
+
+// liveMigrationFeature is the ForkliftController setting that enables OpenShift live migration
+const liveMigrationFeature = "feature_ocp_live_migration"
+
+// ParseStorageClassMap converts a --storage-class-map value ("src-sc:dst-sc,...") to storage
+// pairs. Live migration copies disks with CSI clones, so only storage class names are accepted.
+func ParseStorageClassMap(value string) (string, error) {
+	pairs := []string{}
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.Split(entry, ":")
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" || strings.TrimSpace(parts[1]) == "" || strings.Contains(entry, ";") {
+			return "", fmt.Errorf("invalid storage class mapping '%s': expected 'source-storage-class:target-storage-class'", entry)
+		}
+		pairs = append(pairs, strings.TrimSpace(parts[0])+":"+strings.TrimSpace(parts[1]))
+	}
+	if len(pairs) == 0 {
+		return "", fmt.Errorf("--storage-class-map must contain at least one 'source-storage-class:target-storage-class' pair")
+	}
+	return strings.Join(pairs, ","), nil
+}
+
+// checkLiveMigrationSpec rejects plan options that do not apply to live migration
+func checkLiveMigrationSpec(spec forkliftv1beta1.PlanSpec) error {
+	if spec.Warm {
+		return fmt.Errorf("live migration cannot be combined with --warm")
+	}
+	if string(spec.TargetPowerState) == "off" {
+		return fmt.Errorf("live migration keeps VMs running, --target-power-state off is not supported")
+	}
+
+	// Live migration moves the running VM as is, there is no virt-v2v guest conversion
+	unsupported := []string{}
+	if len(spec.ConvertorLabels) > 0 {
+		unsupported = append(unsupported, "--convertor-labels")
+	}
+	if len(spec.ConvertorNodeSelector) > 0 {
+		unsupported = append(unsupported, "--convertor-node-selector")
+	}
+	if spec.ConvertorAffinity != nil {
+		unsupported = append(unsupported, "--convertor-affinity")
+	}
+	if spec.ConversionTempStorageClass != "" || spec.ConversionTempStorageSize != "" {
+		unsupported = append(unsupported, "--conversion-temp-storage-class/--conversion-temp-storage-size")
+	}
+	if spec.CustomizationScripts != nil {
+		unsupported = append(unsupported, "--customization-scripts")
+	}
+	if spec.TransferNetwork != nil {
+		unsupported = append(unsupported, "--transfer-network")
+	}
+	if len(unsupported) > 0 {
+		return fmt.Errorf("live migration does not use guest conversion, remove: %s", strings.Join(unsupported, ", "))
+	}
+	return nil
+}
+
+// checkLiveMigrationProviders checks that both providers are OpenShift providers of different clusters
+func checkLiveMigrationProviders(source, target *unstructured.Unstructured) error {
+	for _, p := range []*unstructured.Unstructured{source, target} {
+		providerType, _, _ := unstructured.NestedString(p.Object, "spec", "type")
+		if providerType != "openshift" {
+			return fmt.Errorf("live migration requires OpenShift source and target providers, provider '%s' is of type '%s'", p.GetName(), providerType)
+		}
+	}
+
+	if source.GetName() == target.GetName() && source.GetNamespace() == target.GetNamespace() {
+		return fmt.Errorf("live migration moves VMs between clusters, source and target provider must differ (both are '%s')", source.GetName())
+	}
+	sourceURL, _, _ := unstructured.NestedString(source.Object, "spec", "url")
+	targetURL, _, _ := unstructured.NestedString(target.Object, "spec", "url")
+	if sourceURL == targetURL {
+		return fmt.Errorf("live migration moves VMs between clusters, providers '%s' and '%s' point to the same cluster", source.GetName(), target.GetName())
+	}
+	return nil
+}
+
+// validateLiveMigration checks a live migration plan before any resource is created
+func validateLiveMigration(ctx context.Context, opts *CreatePlanOptions) error {
+	if err := checkLiveMigrationSpec(opts.PlanSpec); err != nil {
+		return err
+	}
+
+	source, err := inventory.GetProviderByName(ctx, opts.ConfigFlags, opts.SourceProvider, opts.SourceProviderNamespace)
+	if err != nil {
+		return fmt.Errorf("failed to get source provider: %v", err)
+	}
+	target, err := inventory.GetProviderByName(ctx, opts.ConfigFlags, opts.TargetProvider, opts.TargetProviderNamespace)
+	if err != nil {
+		return fmt.Errorf("failed to get target provider: %v", err)
+	}
+	if err := checkLiveMigrationProviders(source, target); err != nil {
+		return err
+	}
+
+	// The feature gate is checked best effort, reading the controller settings may not be allowed
+	values, err := settings.GetSettings(ctx, settings.GetSettingsOptions{ConfigFlags: opts.ConfigFlags, SettingName: liveMigrationFeature})
+	if err != nil || len(values) == 0 {
+		klog.V(1).Infof("Could not read %s, skipping feature check: %v", liveMigrationFeature, err)
+		return nil
+	}
+	if enabled, ok := values[0].Value.(bool); ok && values[0].IsSet && !enabled {
+		fmt.Fprintf(os.Stderr, "Warning: live migration is disabled on this cluster (%s=false), the plan will not start until it is enabled:\n  kubectl mtv settings set --setting %s --value true\n", liveMigrationFeature, liveMigrationFeature)
+	}
+	return nil
+}
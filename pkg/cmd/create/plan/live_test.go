@@ -0,0 +1,70 @@
+package plan
+
+import (
+	"testing"
+
+	forkliftv1beta1 "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func testProvider(name, providerType, url string) *unstructured.Unstructured {
+	p := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{"type": providerType, "url": url},
+	}}
+	p.SetName(name)
+	p.SetNamespace("mtv")
+	return p
+}
+
+func TestParseStorageClassMap(t *testing.T) {
+	got, err := ParseStorageClassMap(" ceph-rbd : gp3-csi ,nfs:odf ")
+	if err != nil {
+		t.Fatalf("ParseStorageClassMap() unexpected error: %v", err)
+	}
+	if got != "ceph-rbd:gp3-csi,nfs:odf" {
+		t.Errorf("ParseStorageClassMap() = %q", got)
+	}
+
+	for _, value := range []string{"", "ceph-rbd", "ceph-rbd:", "a:b:c", "ceph-rbd:gp3;volumeMode=Block"} {
+		if _, err := ParseStorageClassMap(value); err == nil {
+			t.Errorf("ParseStorageClassMap(%q) expected error", value)
+		}
+	}
+}
+
+func TestCheckLiveMigrationSpec(t *testing.T) {
+	if err := checkLiveMigrationSpec(forkliftv1beta1.PlanSpec{TargetNodeSelector: map[string]string{"zone": "a"}}); err != nil {
+		t.Errorf("checkLiveMigrationSpec() unexpected error: %v", err)
+	}
+
+	invalid := []forkliftv1beta1.PlanSpec{
+		{Warm: true},
+		{TargetPowerState: "off"},
+		{ConvertorLabels: map[string]string{"a": "b"}},
+		{ConversionTempStorageClass: "fast"},
+	}
+	for _, spec := range invalid {
+		if err := checkLiveMigrationSpec(spec); err == nil {
+			t.Errorf("checkLiveMigrationSpec(%+v) expected error", spec)
+		}
+	}
+}
+
+func TestCheckLiveMigrationProviders(t *testing.T) {
+	source := testProvider("east", "openshift", "https://api.east:6443")
+	target := testProvider("west", "openshift", "https://api.west:6443")
+	if err := checkLiveMigrationProviders(source, target); err != nil {
+		t.Errorf("checkLiveMigrationProviders() unexpected error: %v", err)
+	}
+
+	tests := map[string][2]*unstructured.Unstructured{
+		"vsphere source": {testProvider("vc", "vsphere", "https://vc"), target},
+		"same provider":  {target, target},
+		"same cluster":   {testProvider("host", "openshift", ""), testProvider("local", "openshift", "")},
+	}
+	for name, providers := range tests {
+		if err := checkLiveMigrationProviders(providers[0], providers[1]); err == nil {
+			t.Errorf("%s: checkLiveMigrationProviders() expected error", name)
+		}
+	}
+}
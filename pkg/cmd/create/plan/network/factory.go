@@ -53,6 +53,7 @@ type NetworkMapperInterface interface {
 // NetworkMapperOptions contains common options for network mapping
 type NetworkMapperOptions struct {
 	Name                     string
+	MapName                  string // Overrides the default "<Name>-network-map" map name
 	Namespace                string
 	TargetNamespace          string // Where VMs will be created (plan.spec.targetNamespace)
 	SourceProvider           string
@@ -322,6 +323,9 @@ func buildNetworkMapObject(opts NetworkMapperOptions, networkPairs []forkliftv1b
 	}
 
 	networkMapName := opts.Name + "-network-map"
+	if opts.MapName != "" {
+		networkMapName = opts.MapName
+	}
 
 	networkMap := &forkliftv1beta1.NetworkMap{
 		ObjectMeta: metav1.ObjectMeta{
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"os"
 	"strings"
 
 	corev1 "k8s.io/api/core/v1"
@@ -25,8 +26,10 @@ import (
 	"github.com/yaacov/kubectl-mtv/pkg/cmd/create/plan/storage"
 	"github.com/yaacov/kubectl-mtv/pkg/cmd/create/provider/defaultprovider"
 	"github.com/yaacov/kubectl-mtv/pkg/cmd/get/inventory"
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/get/plan/guestconversion"
 	"github.com/yaacov/kubectl-mtv/pkg/util/client"
 	"github.com/yaacov/kubectl-mtv/pkg/util/output"
+	"github.com/yaacov/kubectl-mtv/pkg/util/progress"
 )
 
 // CreatePlanOptions encapsulates the parameters for the Create function.
@@ -44,6 +47,7 @@ type CreatePlanOptions struct {
 	DefaultTargetNetwork      string
 	DefaultTargetStorageClass string
 	PlanSpec                  forkliftv1beta1.PlanSpec
+	Annotations               map[string]string
 	ConfigFlags               *genericclioptions.ConfigFlags
 	NetworkPairs              string
 	StoragePairs              string
@@ -66,6 +70,10 @@ type CreatePlanOptions struct {
 	OffloadCACert          string
 	OffloadInsecureSkipTLS bool
 
+	// Mappings of a cloned plan, copied under new names when no mapping is given
+	CloneNetworkMap *corev1.ObjectReference
+	CloneStorageMap *corev1.ObjectReference
+
 	DryRun       bool
 	OutputFormat string
 }
@@ -86,6 +94,13 @@ func parseProviderName(providerName, defaultNamespace string) (name, namespace s
 
 // Create creates a new migration plan
 func Create(ctx context.Context, opts CreatePlanOptions) error {
+	tracker := progress.Start("create plan", opts.Name)
+	err := create(ctx, opts, tracker)
+	tracker.Done(err)
+	return err
+}
+
+func create(ctx context.Context, opts CreatePlanOptions, tracker *progress.Tracker) error {
 	c, err := client.GetDynamicClient(opts.ConfigFlags)
 	if err != nil {
 		return fmt.Errorf("failed to get client: %v", err)
@@ -121,8 +136,15 @@ func Create(ctx context.Context, opts CreatePlanOptions) error {
 	opts.TargetProvider = targetProviderName
 	opts.TargetProviderNamespace = targetProviderNamespace
 
+	// Live migration is only possible between OpenShift clusters
+	if opts.PlanSpec.Type == liveMigrationType {
+		if err := validateLiveMigration(ctx, &opts); err != nil {
+			return err
+		}
+	}
+
 	// Validate that VMs exist in the source provider
-	err = validateVMs(ctx, opts.ConfigFlags, &opts)
+	err = validateVMs(ctx, opts.ConfigFlags, &opts, tracker)
 	if err != nil {
 		return fmt.Errorf("VM validation failed: %v", err)
 	}
@@ -145,9 +167,22 @@ func Create(ctx context.Context, opts CreatePlanOptions) error {
 		fmt.Printf("No target namespace specified, using plan namespace: %s\n", opts.PlanSpec.TargetNamespace)
 	}
 
+	tracker.Stage("mappings", "creating network and storage mappings", 0)
+
 	// If network map is not provided, create a default network map
 	if opts.NetworkMapping == "" {
-		if opts.NetworkPairs != "" {
+		if opts.CloneNetworkMap != nil {
+			// Copy the network map of the cloned plan
+			networkMapName := fmt.Sprintf("%s-network", opts.Name)
+			err := copyMap(ctx, c, client.NetworkMapGVR, "NetworkMap", *opts.CloneNetworkMap, networkMapName, opts.Namespace, opts.DryRun, opts.OutputFormat)
+			if err != nil {
+				return fmt.Errorf("failed to copy network map: %v", err)
+			}
+			opts.NetworkMapping = networkMapName
+			if !opts.DryRun {
+				createdNetworkMap = true
+			}
+		} else if opts.NetworkPairs != "" {
 			// Create network mapping from pairs
 			networkMapName := fmt.Sprintf("%s-network", opts.Name)
 			// For mapping creation, we need to pass the full provider references with namespaces
@@ -199,7 +234,24 @@ func Create(ctx context.Context, opts CreatePlanOptions) error {
 	// If storage map is not provided, create a default storage map
 	// Skip storage mapping for conversion-only migrations
 	if opts.StorageMapping == "" && opts.PlanSpec.Type != forkliftv1beta1.MigrationOnlyConversion {
-		if opts.StoragePairs != "" {
+		if opts.CloneStorageMap != nil {
+			// Copy the storage map of the cloned plan
+			storageMapName := fmt.Sprintf("%s-storage", opts.Name)
+			err := copyMap(ctx, c, client.StorageMapGVR, "StorageMap", *opts.CloneStorageMap, storageMapName, opts.Namespace, opts.DryRun, opts.OutputFormat)
+			if err != nil {
+				// Clean up the network map if we created it
+				if createdNetworkMap {
+					if delErr := deleteMap(opts.ConfigFlags, client.NetworkMapGVR, opts.NetworkMapping, opts.Namespace); delErr != nil {
+						fmt.Printf("Warning: failed to delete network map: %v\n", delErr)
+					}
+				}
+				return fmt.Errorf("failed to copy storage map: %v", err)
+			}
+			opts.StorageMapping = storageMapName
+			if !opts.DryRun {
+				createdStorageMap = true
+			}
+		} else if opts.StoragePairs != "" {
 			// Create storage mapping from pairs
 			storageMapName := fmt.Sprintf("%s-storage", opts.Name)
 			// For mapping creation, we need to pass the full provider references with namespaces
@@ -303,8 +355,9 @@ func Create(ctx context.Context, opts CreatePlanOptions) error {
 	// Create a new Plan object using the PlanSpec
 	planObj := &forkliftv1beta1.Plan{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      opts.Name,
-			Namespace: opts.Namespace,
+			Name:        opts.Name,
+			Namespace:   opts.Namespace,
+			Annotations: opts.Annotations,
 		},
 		Spec: opts.PlanSpec,
 	}
@@ -351,6 +404,8 @@ func Create(ctx context.Context, opts CreatePlanOptions) error {
 		return output.OutputResource(planObj, opts.OutputFormat)
 	}
 
+	tracker.Stage("plan", "creating plan", 0)
+
 	// Convert Plan object to Unstructured
 	unstructuredPlan, err := runtime.DefaultUnstructuredConverter.ToUnstructured(planObj)
 	if err != nil {
@@ -396,6 +451,12 @@ func Create(ctx context.Context, opts CreatePlanOptions) error {
 		return fmt.Errorf("failed to create plan: %v", err)
 	}
 
+	// A server dry run does not persist the plan, so there is nothing to patch or own the maps
+	if client.DryRunEnabled() {
+		fmt.Printf("plan/%s created%s\n", opts.Name, client.DryRunSuffix())
+		return nil
+	}
+
 	// For fields with +kubebuilder:default:=true, the API server auto-sets them
 	// to true on creation. When the user explicitly sets them to false, we need
 	// a post-create patch to override the kubebuilder default.
@@ -433,14 +494,16 @@ func Create(ctx context.Context, opts CreatePlanOptions) error {
 		}
 	}
 
-	fmt.Printf("plan/%s created\n", opts.Name)
+	fmt.Printf("plan/%s created%s\n", opts.Name, client.DryRunSuffix())
 	return nil
 }
 
 // validateVMs validates that all VMs in the VMList exist in the source provider,
 // sets their IDs based on the names, and removes any that don't exist.
 // Returns an error if no valid VMs remain.
-func validateVMs(ctx context.Context, configFlags *genericclioptions.ConfigFlags, opts *CreatePlanOptions) error {
+func validateVMs(ctx context.Context, configFlags *genericclioptions.ConfigFlags, opts *CreatePlanOptions, tracker *progress.Tracker) error {
+	tracker.Stage("inventory", "fetching source VMs inventory", 0)
+
 	// Fetch source provider using the parsed namespace
 	sourceProvider, err := inventory.GetProviderByName(ctx, configFlags, opts.SourceProvider, opts.SourceProviderNamespace)
 	if err != nil {
@@ -468,6 +531,8 @@ func validateVMs(ctx context.Context, configFlags *genericclioptions.ConfigFlags
 	vmNameToIDMap := make(map[string]string)
 	vmIDToNameMap := make(map[string]string)
 	vmIDToNamespaceMap := make(map[string]string)
+	vmIDToDevicesMap := make(map[string][]inventory.PassthroughDevice)
+	vmIDToGuestRiskMap := make(map[string]string)
 
 	for _, item := range sourceVMsArray {
 		vm, ok := item.(map[string]interface{})
@@ -494,14 +559,18 @@ func validateVMs(ctx context.Context, configFlags *genericclioptions.ConfigFlags
 		vmNameToIDMap[vmName] = vmID
 		vmIDToNameMap[vmID] = vmName
 		vmIDToNamespaceMap[vmID] = vmNamespace
+		vmIDToDevicesMap[vmID] = inventory.DetectPassthroughDevices(vm)
+		vmIDToGuestRiskMap[vmID] = inventory.GuestConversionRisk(vm)
 	}
 
 	// Process VMs: first those with IDs, then those with only names
 	var validVMs []plan.VM
+	tracker.Stage("resolve-vms", "resolving plan VMs", len(opts.PlanSpec.VMs))
 
 	// First process VMs that already have IDs
 	for _, planVM := range opts.PlanSpec.VMs {
 		if planVM.ID != "" {
+			tracker.Step(planVM.ID)
 			// Check if VM with this ID exists in inventory
 			if vmName, exists := vmIDToNameMap[planVM.ID]; exists {
 				// If name is empty or different, update it
@@ -518,6 +587,7 @@ func validateVMs(ctx context.Context, configFlags *genericclioptions.ConfigFlags
 	// Then process VMs that only have names (and need IDs)
 	for _, planVM := range opts.PlanSpec.VMs {
 		if planVM.ID == "" && planVM.Name != "" {
+			tracker.Step(planVM.Name)
 			vmID, exists := vmNameToIDMap[planVM.Name]
 			if exists {
 				planVM.ID = vmID
@@ -544,6 +614,26 @@ func validateVMs(ctx context.Context, configFlags *genericclioptions.ConfigFlags
 		}
 	}
 
+	// Report host-bound devices that will not be transferred to the target VMs
+	for _, planVM := range validVMs {
+		devices := vmIDToDevicesMap[planVM.ID]
+		if len(devices) == 0 {
+			continue
+		}
+		if inventory.CountBlockingDevices(devices) > 0 {
+			fmt.Printf("Warning: VM '%s' has passthrough devices that will not be migrated (%s), the target VM will need equivalent host devices configured\n", planVM.Name, inventory.FormatPassthroughDevices(devices))
+		} else {
+			fmt.Printf("Info: VM '%s' has devices that will not be migrated (%s)\n", planVM.Name, inventory.FormatPassthroughDevices(devices))
+		}
+	}
+
+	// Report guests that depend on the guest conversion the plan skips, and record the decision
+	if opts.PlanSpec.SkipGuestConversion {
+		if err := recordSkipGuestConversion(opts, validVMs, vmIDToGuestRiskMap); err != nil {
+			return err
+		}
+	}
+
 	// Update the VM list
 	opts.PlanSpec.VMs = validVMs
 
@@ -555,6 +645,35 @@ func validateVMs(ctx context.Context, configFlags *genericclioptions.ConfigFlags
 	return nil
 }
 
+// recordSkipGuestConversion warns about VMs whose guests need guest conversion (Windows, older
+// Linux) when the plan skips it, and annotates the plan with the at-risk VMs.
+func recordSkipGuestConversion(opts *CreatePlanOptions, vms []plan.VM, guestRisks map[string]string) error {
+	decision := guestconversion.Decision{AtRisk: map[string]string{}}
+	for _, planVM := range vms {
+		if risk := guestRisks[planVM.ID]; risk != "" {
+			decision.AtRisk[planVM.Name] = risk
+		}
+	}
+
+	if len(decision.AtRisk) > 0 {
+		consequence := guestconversion.Consequence(opts.PlanSpec.UseCompatibilityMode)
+		fmt.Fprintf(os.Stderr, "Warning: guest conversion is skipped (--skip-guest-conversion) for %d VM(s) that need it:\n", len(decision.AtRisk))
+		for _, name := range decision.AtRiskVMs() {
+			fmt.Fprintf(os.Stderr, "  %s: %s, %s\n", name, decision.AtRisk[name], consequence)
+		}
+	}
+
+	value, err := guestconversion.Format(decision)
+	if err != nil {
+		return err
+	}
+	if opts.Annotations == nil {
+		opts.Annotations = map[string]string{}
+	}
+	opts.Annotations[guestconversion.SkipDecisionAnnotation] = value
+	return nil
+}
+
 // setMapOwnership sets the plan as the owner of the map
 func setMapOwnership(configFlags *genericclioptions.ConfigFlags, plan *unstructured.Unstructured, mapGVR schema.GroupVersionResource, mapName, namespace string) error {
 	c, err := client.GetDynamicClient(configFlags)
@@ -0,0 +1,126 @@
+package plan
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	planv1beta1 "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/get/inventory"
+)
+
+// ProviderVMs is the VMs of one source provider, for creating one plan per provider
+type ProviderVMs struct {
+	// Provider is the source provider as namespace/name
+	Provider string
+	VMs      []planv1beta1.VM
+}
+
+// Name returns the provider name without its namespace
+func (p ProviderVMs) Name() string {
+	name, _ := parseProviderName(p.Provider, "")
+	return name
+}
+
+// normalizeProvider returns a provider reference as namespace/name
+func normalizeProvider(provider, namespace string) string {
+	name, ns := parseProviderName(provider, namespace)
+	return ns + "/" + name
+}
+
+// CheckVMProviders verifies that every VM recording a provider (as written by
+// 'get inventory vms --output planvms') belongs to the source provider. Forklift plans
+// have a single source provider, so VMs of other providers are reported by name.
+func CheckVMProviders(entries []inventory.PlanVM, sourceProvider, namespace string) error {
+	source := normalizeProvider(sourceProvider, namespace)
+
+	mismatched := map[string][]string{}
+	for _, entry := range entries {
+		if entry.Provider == "" {
+			continue
+		}
+		if provider := normalizeProvider(entry.Provider, namespace); provider != source {
+			mismatched[provider] = append(mismatched[provider], entry.Name)
+		}
+	}
+	if len(mismatched) == 0 {
+		return nil
+	}
+
+	providers := make([]string, 0, len(mismatched))
+	for provider := range mismatched {
+		providers = append(providers, provider)
+	}
+	sort.Strings(providers)
+
+	var details []string
+	for _, provider := range providers {
+		details = append(details, fmt.Sprintf("%s (%s)", provider, strings.Join(mismatched[provider], ", ")))
+	}
+	return fmt.Errorf("a plan has a single source provider, but some VMs do not belong to '%s': %s. "+
+		"Remove them from the VM list, or use --split-by-provider to create one plan per provider",
+		source, strings.Join(details, "; "))
+}
+
+// SplitByProvider groups VMs by their provider, in the order providers first appear.
+// VMs without a recorded provider belong to defaultProvider.
+func SplitByProvider(entries []inventory.PlanVM, defaultProvider, namespace string) ([]ProviderVMs, error) {
+	var groups []ProviderVMs
+	index := map[string]int{}
+	var unassigned []string
+
+	for _, entry := range entries {
+		provider := entry.Provider
+		if provider == "" {
+			provider = defaultProvider
+		}
+		if provider == "" {
+			unassigned = append(unassigned, entry.Name)
+			continue
+		}
+		provider = normalizeProvider(provider, namespace)
+
+		i, ok := index[provider]
+		if !ok {
+			i = len(groups)
+			index[provider] = i
+			groups = append(groups, ProviderVMs{Provider: provider})
+		}
+		groups[i].VMs = append(groups[i].VMs, entry.VM)
+	}
+
+	if len(unassigned) > 0 {
+		return nil, fmt.Errorf("VMs without a provider: %s. Set --source, or list the VMs with 'get inventory vms --output planvms'",
+			strings.Join(unassigned, ", "))
+	}
+	return groups, nil
+}
+
+// CreatePerProvider creates one plan for each provider group, named <name>-<provider>.
+// With a single group the plan keeps its name. It returns the names of the created plans.
+func CreatePerProvider(ctx context.Context, opts CreatePlanOptions, groups []ProviderVMs) ([]string, error) {
+	if len(groups) > 1 && !opts.DryRun {
+		fmt.Printf("VMs belong to %d source providers, creating one plan per provider\n", len(groups))
+	}
+
+	var created []string
+	for _, group := range groups {
+		groupOpts := opts
+		groupOpts.SourceProvider = group.Provider
+		groupOpts.PlanSpec.VMs = group.VMs
+		if len(groups) > 1 {
+			groupOpts.Name = opts.Name + "-" + group.Name()
+		}
+
+		if err := Create(ctx, groupOpts); err != nil {
+			if len(created) > 0 {
+				return created, fmt.Errorf("failed to create plan '%s' (already created: %s): %v", groupOpts.Name, strings.Join(created, ", "), err)
+			}
+			return nil, fmt.Errorf("failed to create plan '%s': %v", groupOpts.Name, err)
+		}
+		created = append(created, groupOpts.Name)
+	}
+	return created, nil
+}
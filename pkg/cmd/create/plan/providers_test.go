@@ -0,0 +1,87 @@
+package plan
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/get/inventory"
+)
+
+func planVM(name, provider string) inventory.PlanVM {
+	vm := inventory.PlanVM{Provider: provider}
+	vm.Name = name
+	return vm
+}
+
+func TestCheckVMProviders(t *testing.T) {
+	entries := []inventory.PlanVM{
+		planVM("web", "demo/vsphere"),
+		planVM("db", ""),
+		planVM("legacy", "demo/ovirt"),
+		planVM("app", "other/vsphere"),
+	}
+
+	if err := CheckVMProviders(entries[:2], "vsphere", "demo"); err != nil {
+		t.Errorf("expected VMs of the source provider to pass, got %v", err)
+	}
+
+	err := CheckVMProviders(entries, "demo/vsphere", "demo")
+	if err == nil {
+		t.Fatal("expected an error for VMs of other providers")
+	}
+	for _, want := range []string{"demo/ovirt (legacy)", "other/vsphere (app)", "--split-by-provider"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %q", err, want)
+		}
+	}
+}
+
+func TestSplitByProvider(t *testing.T) {
+	entries := []inventory.PlanVM{
+		planVM("web", "demo/vsphere"),
+		planVM("legacy", "ovirt"),
+		planVM("db", ""),
+		planVM("app", "demo/vsphere"),
+	}
+
+	groups, err := SplitByProvider(entries, "vsphere", "demo")
+	if err != nil {
+		t.Fatalf("SplitByProvider() error = %v", err)
+	}
+	if len(groups) != 2 {
+		t.Fatalf("expected 2 groups, got %d", len(groups))
+	}
+	if groups[0].Provider != "demo/vsphere" || len(groups[0].VMs) != 3 || groups[0].Name() != "vsphere" {
+		t.Errorf("unexpected first group %+v", groups[0])
+	}
+	if groups[1].Provider != "demo/ovirt" || len(groups[1].VMs) != 1 || groups[1].VMs[0].Name != "legacy" {
+		t.Errorf("unexpected second group %+v", groups[1])
+	}
+
+	if _, err := SplitByProvider(entries, "", "demo"); err == nil || !strings.Contains(err.Error(), "db") {
+		t.Errorf("expected an error naming the VM without a provider, got %v", err)
+	}
+}
+
+func TestPlanVMYAMLRoundTrip(t *testing.T) {
+	entry := planVM("web", "demo/vsphere")
+	entry.ID = "vm-1"
+
+	data, err := yaml.Marshal([]inventory.PlanVM{entry})
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if !strings.Contains(string(data), "provider: demo/vsphere") {
+		t.Errorf("expected a provider key in:\n%s", data)
+	}
+
+	var parsed []inventory.PlanVM
+	if err := yaml.Unmarshal(data, &parsed); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if len(parsed) != 1 || parsed[0].Name != "web" || parsed[0].ID != "vm-1" || parsed[0].Provider != "demo/vsphere" {
+		t.Errorf("unexpected round trip result %+v", parsed)
+	}
+}
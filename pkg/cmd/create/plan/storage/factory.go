@@ -54,6 +54,7 @@ type StorageMapperInterface interface {
 // StorageMapperOptions contains common options for storage mapping
 type StorageMapperOptions struct {
 	Name                      string
+	MapName                   string // Overrides the default "<Name>-storage-map" map name
 	Namespace                 string
 	SourceProvider            string
 	SourceProviderNamespace   string
@@ -86,6 +87,14 @@ type StorageMapperOptions struct {
 	OffloadInsecureSkipTLS bool
 }
 
+// mapName returns the storage map name, defaulting to "<Name>-storage-map"
+func (opts StorageMapperOptions) mapName() string {
+	if opts.MapName != "" {
+		return opts.MapName
+	}
+	return opts.Name + "-storage-map"
+}
+
 // CreateStorageMap creates a storage map using the new fetcher-based architecture.
 // It returns the storage map name, the name of any offload secret that was created
 // (empty if none), and an error. The caller is responsible for cleaning up the
@@ -166,7 +175,7 @@ func CreateStorageMap(ctx context.Context, opts StorageMapperOptions) (storageMa
 		return "", "", err
 	}
 
-	storageMapName = opts.Name + "-storage-map"
+	storageMapName = opts.mapName()
 	if opts.DryRun && offload.NeedsSecret(secretOpts) {
 		sec, err := offload.BuildSecret(opts.Namespace, storageMapName, secretOpts, true)
 		if err != nil {
@@ -241,7 +250,7 @@ func buildStorageMapObject(opts StorageMapperOptions, storagePairs []forkliftv1b
 		klog.V(4).Infof("DEBUG: No storage pairs found, StorageMap will have an empty map")
 	}
 
-	storageMapName := opts.Name + "-storage-map"
+	storageMapName := opts.mapName()
 
 	storageMap := &forkliftv1beta1.StorageMap{
 		ObjectMeta: metav1.ObjectMeta{
@@ -0,0 +1,257 @@
+package plan
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/klog/v2"
+
+	"github.com/yaacov/kubectl-mtv/pkg/util/client"
+)
+
+// TemplateConfigMap is the ConfigMap holding the shared plan templates of a namespace, one
+// data key per template name
+const TemplateConfigMap = "mtv-plan-templates"
+
+// templateOnlyFlags are create plan flags a template cannot set: they name the plan or the
+// template itself
+var templateOnlyFlags = map[string]bool{
+	"name":     true,
+	"template": true,
+	"set":      true,
+}
+
+// TemplateParameter is a placeholder of a plan template, given with --set name=value
+type TemplateParameter struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description,omitempty"`
+	Default     string `yaml:"default,omitempty"`
+	Required    bool   `yaml:"required,omitempty"`
+}
+
+// Template is a reusable set of create plan flags. Flag values are Go templates that can
+// use the parameters and the built-in name and namespace of the plan, e.g.
+//
+//	description: Gold tier warm migration
+//	parameters:
+//	  - name: source
+//	    required: true
+//	  - name: query
+//	    default: "where powerState = 'poweredOn'"
+//	flags:
+//	  source: "{{ .source }}"
+//	  vms: "{{ .query }}"
+//	  migration-type: warm
+//	  target-namespace: "{{ .namespace }}-vms"
+type Template struct {
+	Description string                 `yaml:"description,omitempty"`
+	Parameters  []TemplateParameter    `yaml:"parameters,omitempty"`
+	Flags       map[string]interface{} `yaml:"flags"`
+
+	// Source tells where the template was loaded from
+	Source string `yaml:"-"`
+}
+
+// TemplateDir returns the local plan template directory in the kubectl-mtv user config directory
+func TemplateDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find user config directory: %v", err)
+	}
+	return filepath.Join(dir, "kubectl-mtv", "plan-templates"), nil
+}
+
+// isTemplatePath tells template files given by path from template names
+func isTemplatePath(ref string) bool {
+	return strings.ContainsRune(ref, os.PathSeparator) || strings.HasSuffix(ref, ".yaml") || strings.HasSuffix(ref, ".yml")
+}
+
+// LoadTemplate finds a plan template: ref is a file path, or a name looked up as <name>.yaml
+// in the local template directory and then in the TemplateConfigMap of the namespace
+func LoadTemplate(ctx context.Context, configFlags *genericclioptions.ConfigFlags, ref, namespace string) (*Template, error) {
+	if isTemplatePath(ref) {
+		data, err := os.ReadFile(ref)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read plan template: %v", err)
+		}
+		return ParseTemplate(data, ref)
+	}
+
+	if dir, err := TemplateDir(); err == nil {
+		path := filepath.Join(dir, ref+".yaml")
+		data, err := os.ReadFile(path)
+		if err == nil {
+			return ParseTemplate(data, path)
+		}
+		if !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to read plan template: %v", err)
+		}
+		klog.V(2).Infof("Plan template '%s' not found in %s", ref, dir)
+	}
+
+	c, err := client.GetDynamicClient(configFlags)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get client: %v", err)
+	}
+	cm, err := c.Resource(client.ConfigMapsGVR).Namespace(namespace).Get(ctx, TemplateConfigMap, metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to get ConfigMap '%s': %v", TemplateConfigMap, err)
+	}
+	if err == nil {
+		if data, found, _ := unstructured.NestedString(cm.Object, "data", ref); found {
+			return ParseTemplate([]byte(data), fmt.Sprintf("configmap %s/%s", namespace, TemplateConfigMap))
+		}
+	}
+	return nil, fmt.Errorf("plan template '%s' not found in the local template directory or in ConfigMap '%s' of namespace '%s'", ref, TemplateConfigMap, namespace)
+}
+
+// ParseTemplate parses and validates a plan template read from source
+func ParseTemplate(data []byte, source string) (*Template, error) {
+	t := &Template{}
+	if err := yaml.Unmarshal(data, t); err != nil {
+		return nil, fmt.Errorf("failed to parse plan template '%s': %v", source, err)
+	}
+	t.Source = source
+
+	if len(t.Flags) == 0 {
+		return nil, fmt.Errorf("plan template '%s' sets no flags", source)
+	}
+	for name := range t.Flags {
+		if templateOnlyFlags[name] {
+			return nil, fmt.Errorf("plan template '%s' cannot set --%s", source, name)
+		}
+	}
+	seen := map[string]bool{}
+	for _, p := range t.Parameters {
+		if p.Name == "" {
+			return nil, fmt.Errorf("plan template '%s' has a parameter without a name", source)
+		}
+		if seen[p.Name] {
+			return nil, fmt.Errorf("plan template '%s' declares parameter '%s' twice", source, p.Name)
+		}
+		seen[p.Name] = true
+	}
+	return t, nil
+}
+
+// ParseSetValues parses --set key=value flags
+func ParseSetValues(values []string) (map[string]string, error) {
+	result := make(map[string]string, len(values))
+	for _, v := range values {
+		parts := strings.SplitN(v, "=", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			return nil, fmt.Errorf("invalid --set value '%s': expected key=value", v)
+		}
+		result[strings.TrimSpace(parts[0])] = parts[1]
+	}
+	return result, nil
+}
+
+// flagValue converts a template flag value to a flag string; lists become comma-separated
+func flagValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case []interface{}:
+		parts := make([]string, 0, len(v))
+		for _, item := range v {
+			parts = append(parts, fmt.Sprint(item))
+		}
+		return strings.Join(parts, ",")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// Render returns the flag values of the template with its placeholders filled from the
+// built-in values (name and namespace of the plan), the parameter defaults and set, in
+// increasing precedence. Unknown or missing parameters are errors.
+func (t *Template) Render(builtins, set map[string]string) (map[string]string, error) {
+	values := make(map[string]string, len(builtins)+len(t.Parameters))
+	for k, v := range builtins {
+		values[k] = v
+	}
+
+	declared := map[string]bool{}
+	var missing []string
+	for _, p := range t.Parameters {
+		declared[p.Name] = true
+		v, ok := set[p.Name]
+		switch {
+		case ok:
+			values[p.Name] = v
+		case p.Default != "":
+			values[p.Name] = p.Default
+		case p.Required:
+			missing = append(missing, p.Name)
+		default:
+			values[p.Name] = ""
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("plan template '%s' requires --set for: %s", t.Source, strings.Join(missing, ", "))
+	}
+
+	var unknown []string
+	for k := range set {
+		if !declared[k] {
+			unknown = append(unknown, k)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("plan template '%s' has no parameter %s", t.Source, strings.Join(unknown, ", "))
+	}
+
+	rendered := make(map[string]string, len(t.Flags))
+	for name, raw := range t.Flags {
+		tmpl, err := template.New(name).Option("missingkey=error").Parse(flagValue(raw))
+		if err != nil {
+			return nil, fmt.Errorf("invalid value of --%s in plan template '%s': %v", name, t.Source, err)
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, values); err != nil {
+			return nil, fmt.Errorf("failed to render --%s of plan template '%s': %v", name, t.Source, err)
+		}
+		rendered[name] = buf.String()
+	}
+	return rendered, nil
+}
+
+// ApplyTemplateFlags sets the rendered template flags the user did not set on the command
+// line, so explicit flags override the template. The flags are marked as set, as if given
+// on the command line.
+func ApplyTemplateFlags(fs *pflag.FlagSet, rendered map[string]string) error {
+	names := make([]string, 0, len(rendered))
+	for name := range rendered {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		flag := fs.Lookup(name)
+		if flag == nil {
+			return fmt.Errorf("plan template sets unknown flag --%s", name)
+		}
+		if flag.Changed {
+			klog.V(2).Infof("Flag --%s given on the command line overrides the plan template", name)
+			continue
+		}
+		if err := fs.Set(name, rendered[name]); err != nil {
+			return fmt.Errorf("invalid value of --%s in plan template: %v", name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package plan
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+const goldWarm = `
+description: Gold tier warm migration
+parameters:
+  - name: source
+    required: true
+  - name: query
+    default: "where powerState = 'poweredOn'"
+  - name: tier
+flags:
+  source: "{{ .source }}"
+  vms: "{{ .query }}"
+  migration-type: warm
+  target-namespace: "{{ .namespace }}-vms"
+  target-labels:
+    - tier=gold{{ .tier }}
+    - plan={{ .name }}
+  max-concurrent-vms: 4
+`
+
+func TestTemplateRender(t *testing.T) {
+	tmpl, err := ParseTemplate([]byte(goldWarm), "gold-warm.yaml")
+	if err != nil {
+		t.Fatalf("ParseTemplate() error = %v", err)
+	}
+
+	rendered, err := tmpl.Render(map[string]string{"name": "wave3", "namespace": "team-a"}, map[string]string{"source": "vsphere-prod"})
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	want := map[string]string{
+		"source":             "vsphere-prod",
+		"vms":                "where powerState = 'poweredOn'",
+		"migration-type":     "warm",
+		"target-namespace":   "team-a-vms",
+		"target-labels":      "tier=gold,plan=wave3",
+		"max-concurrent-vms": "4",
+	}
+	for k, v := range want {
+		if rendered[k] != v {
+			t.Errorf("rendered[%s] = %q, want %q", k, rendered[k], v)
+		}
+	}
+
+	if _, err := tmpl.Render(nil, nil); err == nil || !strings.Contains(err.Error(), "source") {
+		t.Errorf("Render() without a required parameter error = %v, want it named", err)
+	}
+	if _, err := tmpl.Render(nil, map[string]string{"source": "a", "typo": "b"}); err == nil || !strings.Contains(err.Error(), "typo") {
+		t.Errorf("Render() with an unknown parameter error = %v, want it named", err)
+	}
+}
+
+func TestParseTemplateRejects(t *testing.T) {
+	tests := map[string]string{
+		"no flags":      "description: empty\n",
+		"plan name":     "flags:\n  name: fixed\n",
+		"duplicate":     "parameters:\n  - name: a\n  - name: a\nflags:\n  source: x\n",
+		"unnamed param": "parameters:\n  - default: a\nflags:\n  source: x\n",
+	}
+	for name, data := range tests {
+		if _, err := ParseTemplate([]byte(data), name); err == nil {
+			t.Errorf("ParseTemplate(%s) succeeded, want an error", name)
+		}
+	}
+}
+
+func TestApplyTemplateFlags(t *testing.T) {
+	fs := pflag.NewFlagSet("plan", pflag.ContinueOnError)
+	source := fs.String("source", "", "")
+	warm := fs.Bool("warm", false, "")
+	_ = fs.Int("max-concurrent-vms", 0, "")
+	if err := fs.Parse([]string{"--source", "from-cli"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ApplyTemplateFlags(fs, map[string]string{"source": "from-template", "warm": "true"}); err != nil {
+		t.Fatalf("ApplyTemplateFlags() error = %v", err)
+	}
+	if *source != "from-cli" {
+		t.Errorf("source = %q, want the command line value to override the template", *source)
+	}
+	if !*warm || !fs.Changed("warm") {
+		t.Errorf("warm = %v (changed %v), want true and marked as set", *warm, fs.Changed("warm"))
+	}
+
+	if err := ApplyTemplateFlags(fs, map[string]string{"no-such-flag": "x"}); err == nil {
+		t.Error("ApplyTemplateFlags() with an unknown flag succeeded, want an error")
+	}
+	if err := ApplyTemplateFlags(fs, map[string]string{"max-concurrent-vms": "many"}); err == nil {
+		t.Error("ApplyTemplateFlags() with an invalid value succeeded, want an error")
+	}
+}
+
+func TestParseSetValues(t *testing.T) {
+	got, err := ParseSetValues([]string{"source=vsphere", "query=where a = b"})
+	if err != nil || got["source"] != "vsphere" || got["query"] != "where a = b" {
+		t.Errorf("ParseSetValues() = %v, %v", got, err)
+	}
+	if _, err := ParseSetValues([]string{"novalue"}); err == nil {
+		t.Error("ParseSetValues(novalue) succeeded, want an error")
+	}
+}
@@ -0,0 +1,37 @@
+package plan
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/yaacov/kubectl-mtv/pkg/util/client"
+	"github.com/yaacov/kubectl-mtv/pkg/util/wait"
+)
+
+// Wait blocks until the plan passed validation and is Ready to start, returning an error
+// listing the failed validations when they persist, or when the timeout expires.
+func Wait(ctx context.Context, configFlags *genericclioptions.ConfigFlags, name, namespace string, opts wait.Options) error {
+	c, err := client.GetDynamicClient(configFlags)
+	if err != nil {
+		return fmt.Errorf("failed to get client: %v", err)
+	}
+
+	fmt.Fprintf(opts.Out, "Waiting for plan '%s' to be validated...\n", name)
+	ri := c.Resource(client.PlansGVR).Namespace(namespace)
+	if _, err := wait.For(ctx, ri, "plan", name, "pass validation", ReadyCheck, opts); err != nil {
+		return err
+	}
+	fmt.Fprintf(opts.Out, "plan/%s is Ready\n", name)
+	return nil
+}
+
+// ReadyCheck is done when the plan is Ready; its problems are the failed validations
+func ReadyCheck(obj *unstructured.Unstructured) (bool, []string) {
+	if wait.ConditionTrue(obj, "Ready") {
+		return true, nil
+	}
+	return false, wait.BlockingConditions(obj)
+}
@@ -52,6 +52,11 @@ func createSecret(configFlags *genericclioptions.ConfigFlags, namespace, provide
 }
 
 func setSecretOwnership(configFlags *genericclioptions.ConfigFlags, provider *forkliftv1beta1.Provider, secret *corev1.Secret) error {
+	// A dry-run secret is not persisted, there is nothing to set the owner of
+	if client.DryRunEnabled() {
+		return nil
+	}
+
 	k8sClient, err := client.GetKubernetesClientset(configFlags)
 	if err != nil {
 		return fmt.Errorf("failed to create kubernetes client: %v", err)
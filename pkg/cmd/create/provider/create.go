@@ -14,6 +14,7 @@ import (
 	"github.com/yaacov/kubectl-mtv/pkg/cmd/create/provider/ova"
 	"github.com/yaacov/kubectl-mtv/pkg/cmd/create/provider/providerutil"
 	"github.com/yaacov/kubectl-mtv/pkg/cmd/create/provider/vsphere"
+	"github.com/yaacov/kubectl-mtv/pkg/util/client"
 	"github.com/yaacov/kubectl-mtv/pkg/util/flags"
 	"github.com/yaacov/kubectl-mtv/pkg/util/output"
 
@@ -70,7 +71,7 @@ func Create(configFlags *genericclioptions.ConfigFlags, providerType string, opt
 	}
 
 	// Display the creation results to the user
-	fmt.Printf("provider/%s created\n", providerResource.Name)
+	fmt.Printf("provider/%s created%s\n", providerResource.Name, client.DryRunSuffix())
 
 	if secretResource != nil {
 		fmt.Printf("Created secret '%s' for provider authentication\n", secretResource.Name)
@@ -79,6 +79,11 @@ func createSecret(configFlags *genericclioptions.ConfigFlags, namespace, provide
 
 // setSecretOwnership sets the provider as the owner of the secret
 func setSecretOwnership(configFlags *genericclioptions.ConfigFlags, provider *forkliftv1beta1.Provider, secret *corev1.Secret) error {
+	// A dry-run secret is not persisted, there is nothing to set the owner of
+	if client.DryRunEnabled() {
+		return nil
+	}
+
 	// Get the Kubernetes client using configFlags
 	k8sClient, err := client.GetKubernetesClientset(configFlags)
 	if err != nil {
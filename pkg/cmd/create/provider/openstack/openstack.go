@@ -65,6 +65,11 @@ func cleanupCreatedResources(configFlags *genericclioptions.ConfigFlags, namespa
 
 // setSecretOwnership sets the provider as the owner of the secret
 func setSecretOwnership(configFlags *genericclioptions.ConfigFlags, provider *forkliftv1beta1.Provider, secret *corev1.Secret) error {
+	// A dry-run secret is not persisted, there is nothing to set the owner of
+	if client.DryRunEnabled() {
+		return nil
+	}
+
 	k8sClient, err := client.GetKubernetesClientset(configFlags)
 	if err != nil {
 		return fmt.Errorf("failed to get kubernetes client: %v", err)
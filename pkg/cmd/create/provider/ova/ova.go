@@ -3,6 +3,7 @@ package ova
 import (
 	"context"
 	"fmt"
+	"net/url"
 	"strings"
 
 	corev1 "k8s.io/api/core/v1"
@@ -15,6 +16,14 @@ import (
 
 	"github.com/yaacov/kubectl-mtv/pkg/cmd/create/provider/providerutil"
 	"github.com/yaacov/kubectl-mtv/pkg/util/client"
+	"github.com/yaacov/kubectl-mtv/pkg/util/upload"
+)
+
+// URL kinds served by OVA providers
+const (
+	urlKindNFS  = "nfs"
+	urlKindHTTP = "http"
+	urlKindS3   = "s3"
 )
 
 // validateProviderOptions validates the options for creating an OVA provider
@@ -26,26 +35,43 @@ func validateProviderOptions(options providerutil.ProviderOptions) error {
 		return fmt.Errorf("provider namespace is required")
 	}
 	if options.URL == "" {
-		return fmt.Errorf("provider URL is required")
+		return fmt.Errorf("provider URL is required, or use --ova-file to upload a local OVA file")
 	}
 
-	// Validate that OVA URLs are in NFS format (server:path)
-	// OVA providers only support NFS URLs, not file://, http://, or https://
-	if !isValidNFSURL(options.URL) {
-		return fmt.Errorf("OVA provider URL must be in NFS format (server:path), e.g., 'nfs.example.com:/path/to/ova-files' or '192.168.1.100:/exports/vm-images'")
+	if ovaURLKind(options.URL) == "" {
+		return fmt.Errorf("OVA provider URL must be an NFS share (server:path), e.g., 'nfs.example.com:/path/to/ova-files', an HTTP(S) URL, e.g., 'https://images.example.com/ova/', or an S3 URL, e.g., 's3://bucket/ova/'")
 	}
 
 	return nil
 }
 
+// ovaURLKind returns the kind of an OVA provider URL, or "" when the URL is not supported
+func ovaURLKind(rawURL string) string {
+	switch {
+	case strings.HasPrefix(rawURL, "http://") || strings.HasPrefix(rawURL, "https://"):
+		parsed, err := url.Parse(rawURL)
+		if err != nil || parsed.Host == "" {
+			return ""
+		}
+		return urlKindHTTP
+	case strings.HasPrefix(rawURL, "s3://"):
+		if _, err := upload.ParseS3URL(rawURL); err != nil {
+			return ""
+		}
+		return urlKindS3
+	case isValidNFSURL(rawURL):
+		return urlKindNFS
+	}
+	return ""
+}
+
 // isValidNFSURL checks if the URL is in valid NFS format (server:path)
 func isValidNFSURL(url string) bool {
 	// NFS URLs should not have protocol prefixes and should contain a colon
 	// Examples: "nfs.example.com:/path" or "192.168.1.100:/exports/vms"
 
 	// Reject URLs with protocol prefixes
-	if strings.HasPrefix(url, "http://") || strings.HasPrefix(url, "https://") ||
-		strings.HasPrefix(url, "file://") || strings.HasPrefix(url, "nfs://") {
+	if strings.Contains(url, "://") {
 		return false
 	}
 
@@ -114,6 +140,16 @@ func createTypedProvider(configFlags *genericclioptions.ConfigFlags, namespace s
 
 // CreateProvider implements the ProviderCreator interface for OVA
 func CreateProvider(configFlags *genericclioptions.ConfigFlags, options providerutil.ProviderOptions) (*forkliftv1beta1.Provider, *corev1.Secret, error) {
+	// A local OVA file is uploaded first and the provider points at the uploaded copy
+	var ovaFile *ovaUpload
+	if options.OVAFile != "" {
+		var err error
+		if ovaFile, err = planUpload(options); err != nil {
+			return nil, nil, err
+		}
+		options.URL = ovaFile.URL
+	}
+
 	// Validate required fields
 	if err := validateProviderOptions(options); err != nil {
 		return nil, nil, err
@@ -137,7 +173,7 @@ func CreateProvider(configFlags *genericclioptions.ConfigFlags, options provider
 
 	if options.DryRun {
 		if options.Secret == "" {
-			createdSecret = buildSecret(options.Namespace, options.Name, options.URL)
+			createdSecret = buildSecret(options.Namespace, options.Name, options)
 			provider.Spec.Secret = corev1.ObjectReference{
 				Name:      createdSecret.Name,
 				Namespace: createdSecret.Namespace,
@@ -151,8 +187,14 @@ func CreateProvider(configFlags *genericclioptions.ConfigFlags, options provider
 		return provider, createdSecret, nil
 	}
 
+	if ovaFile != nil {
+		if err := ovaFile.run(context.Background(), configFlags, options); err != nil {
+			return nil, nil, fmt.Errorf("failed to upload OVA file: %v", err)
+		}
+	}
+
 	if options.Secret == "" {
-		createdSecret, err = createSecret(configFlags, options.Namespace, options.Name, options.URL)
+		createdSecret, err = createSecret(configFlags, options.Namespace, options.Name, options)
 		if err != nil {
 			return nil, nil, fmt.Errorf("failed to create OVA secret: %v", err)
 		}
@@ -179,6 +221,17 @@ func CreateProvider(configFlags *genericclioptions.ConfigFlags, options provider
 		return nil, nil, fmt.Errorf("failed to create OVA provider: %v", err)
 	}
 
+	// Deleting the provider removes the upload server and its PVC
+	if ovaFile != nil && ovaFile.Target == UploadToCluster {
+		k8sClient, err := client.GetKubernetesClientset(configFlags)
+		if err != nil {
+			return nil, createdSecret, fmt.Errorf("provider created but failed to create kubernetes client: %v", err)
+		}
+		if err := setServerOwnership(context.Background(), k8sClient, createdProvider); err != nil {
+			return nil, createdSecret, fmt.Errorf("provider created but %v", err)
+		}
+	}
+
 	// Set the secret ownership to the provider if we created the secret
 	if createdSecret != nil {
 		if err := setSecretOwnership(configFlags, createdProvider, createdSecret); err != nil {
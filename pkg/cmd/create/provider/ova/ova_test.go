@@ -0,0 +1,190 @@
+package ova
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	forkliftv1beta1 "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/create/provider/providerutil"
+)
+
+func TestOVAURLKind(t *testing.T) {
+	tests := map[string]string{
+		"nfs.example.com:/exports/ova":   urlKindNFS,
+		"192.168.1.100:/vm-images":       urlKindNFS,
+		"https://images.example.com/ova": urlKindHTTP,
+		"http://10.0.0.5:8080/web.ova":   urlKindHTTP,
+		"s3://images/ova/":               urlKindS3,
+		"file:///srv/ova":                "",
+		"nfs://server/path":              "",
+		"https://":                       "",
+		"s3:///no-bucket":                "",
+		"just-a-path":                    "",
+	}
+	for url, want := range tests {
+		if got := ovaURLKind(url); got != want {
+			t.Errorf("ovaURLKind(%q) = %q, want %q", url, got, want)
+		}
+	}
+}
+
+func TestBuildSecret_HTTPAndS3Credentials(t *testing.T) {
+	secret := buildSecret("mtv", "web", providerutil.ProviderOptions{
+		URL:             "https://images.example.com/ova/",
+		Username:        "reader",
+		Password:        "pass",
+		CACert:          "cert-data",
+		InsecureSkipTLS: true,
+	})
+	if string(secret.Data["user"]) != "reader" || string(secret.Data["password"]) != "pass" {
+		t.Errorf("http secret data = %v", secret.Data)
+	}
+	if string(secret.Data["ca.crt"]) != "cert-data" || string(secret.Data["insecureSkipVerify"]) != "true" {
+		t.Errorf("http secret TLS data = %v", secret.Data)
+	}
+
+	secret = buildSecret("mtv", "bucket", providerutil.ProviderOptions{
+		URL:        "s3://images/ova/",
+		Username:   "AKIA",
+		Password:   "secret",
+		RegionName: "eu-west-1",
+	})
+	if string(secret.Data["accessKeyId"]) != "AKIA" || string(secret.Data["secretAccessKey"]) != "secret" || string(secret.Data["region"]) != "eu-west-1" {
+		t.Errorf("s3 secret data = %v", secret.Data)
+	}
+
+	secret = buildSecret("mtv", "share", providerutil.ProviderOptions{URL: "nfs.example.com:/ova", Username: "ignored"})
+	if len(secret.Data) != 1 || string(secret.Data["url"]) != "nfs.example.com:/ova" {
+		t.Errorf("nfs secret data = %v", secret.Data)
+	}
+}
+
+func writeOVA(t *testing.T, name string) string {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(file, []byte("ova"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+func TestPlanUpload(t *testing.T) {
+	file := writeOVA(t, "web server.ova")
+	options := providerutil.ProviderOptions{Name: "appliance", Namespace: "mtv", OVAFile: file}
+
+	u, err := planUpload(options)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if u.URL != "http://appliance-ova-server.mtv.svc:8080/web%20server.ova" || u.Target != UploadToCluster {
+		t.Errorf("cluster upload = %+v", u)
+	}
+
+	options.OVAUploadTo = "s3://images/ova/"
+	if u, err = planUpload(options); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if u.URL != "s3://images/ova/web server.ova" || u.S3Key != "ova/web server.ova" {
+		t.Errorf("s3 upload = %+v", u)
+	}
+
+	for name, bad := range map[string]providerutil.ProviderOptions{
+		"url and file": {Name: "a", Namespace: "mtv", OVAFile: file, URL: "nfs:/x"},
+		"not an ova":   {Name: "a", Namespace: "mtv", OVAFile: writeOVA(t, "disk.vmdk")},
+		"missing":      {Name: "a", Namespace: "mtv", OVAFile: "/does/not/exist.ova"},
+		"bad target":   {Name: "a", Namespace: "mtv", OVAFile: file, OVAUploadTo: "https://example.com"},
+	} {
+		if _, err := planUpload(bad); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestPVCSize(t *testing.T) {
+	q, err := pvcSize("", 10<<30)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// 10Gi + 10% + 1Gi
+	if q.String() != "12Gi" {
+		t.Errorf("pvcSize = %s, want 12Gi", q.String())
+	}
+	if q, _ = pvcSize("50Gi", 10<<30); q.String() != "50Gi" {
+		t.Errorf("pvcSize = %s, want 50Gi", q.String())
+	}
+	if _, err := pvcSize("lots", 1); err == nil {
+		t.Error("expected an invalid size error")
+	}
+}
+
+func TestEnsureServer_CreatesAndReusesObjects(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	ctx := context.Background()
+	size := resource.MustParse("5Gi")
+
+	for i := 0; i < 2; i++ {
+		if err := ensureServer(ctx, clientset, "mtv", "appliance", DefaultServerImage, "fast", size); err != nil {
+			t.Fatalf("ensureServer #%d: %v", i+1, err)
+		}
+	}
+
+	pvc, err := clientset.CoreV1().PersistentVolumeClaims("mtv").Get(ctx, "appliance-ova-server", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("PVC not created: %v", err)
+	}
+	if *pvc.Spec.StorageClassName != "fast" || pvc.Spec.Resources.Requests.Storage().String() != "5Gi" {
+		t.Errorf("PVC spec = %+v", pvc.Spec)
+	}
+	config, err := clientset.CoreV1().ConfigMaps("mtv").Get(ctx, "appliance-ova-server", metav1.GetOptions{})
+	if err != nil || !strings.Contains(config.Data["default.conf"], "dav_methods PUT") {
+		t.Errorf("ConfigMap = %v, %v", config, err)
+	}
+	deployment, err := clientset.AppsV1().Deployments("mtv").Get(ctx, "appliance-ova-server", metav1.GetOptions{})
+	if err != nil || deployment.Spec.Template.Spec.Containers[0].Image != DefaultServerImage {
+		t.Errorf("Deployment = %v, %v", deployment, err)
+	}
+	if _, err := clientset.CoreV1().Services("mtv").Get(ctx, "appliance-ova-server", metav1.GetOptions{}); err != nil {
+		t.Errorf("Service not created: %v", err)
+	}
+
+	provider := &forkliftv1beta1.Provider{}
+	provider.APIVersion = forkliftv1beta1.SchemeGroupVersion.String()
+	provider.Kind = "Provider"
+	provider.Name = "appliance"
+	provider.Namespace = "mtv"
+	provider.UID = "provider-uid"
+	if err := setServerOwnership(ctx, clientset, provider); err != nil {
+		t.Fatalf("setServerOwnership: %v", err)
+	}
+	pvc, _ = clientset.CoreV1().PersistentVolumeClaims("mtv").Get(ctx, "appliance-ova-server", metav1.GetOptions{})
+	if len(pvc.OwnerReferences) != 1 || pvc.OwnerReferences[0].UID != "provider-uid" {
+		t.Errorf("PVC owners = %v", pvc.OwnerReferences)
+	}
+}
+
+func TestCreateProvider_DryRunWithOVAFile(t *testing.T) {
+	file := writeOVA(t, "appliance.ova")
+	provider, secret, err := CreateProvider(nil, providerutil.ProviderOptions{
+		Name:      "appliance",
+		Namespace: "mtv",
+		OVAFile:   file,
+		DryRun:    true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if provider.Spec.URL != "http://appliance-ova-server.mtv.svc:8080/appliance.ova" {
+		t.Errorf("provider URL = %q", provider.Spec.URL)
+	}
+	if string(secret.Data["url"]) != provider.Spec.URL {
+		t.Errorf("secret url = %q", secret.Data["url"])
+	}
+}
@@ -9,13 +9,39 @@ import (
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 
 	forkliftv1beta1 "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/create/provider/providerutil"
 	"github.com/yaacov/kubectl-mtv/pkg/util/client"
 )
 
 // buildSecret returns an OVA provider Secret without submitting it to the API.
-func buildSecret(namespace, providerName, url string) *corev1.Secret {
+// HTTP(S) URLs may carry basic auth credentials and TLS settings, S3 URLs access keys.
+func buildSecret(namespace, providerName string, options providerutil.ProviderOptions) *corev1.Secret {
 	secretData := map[string][]byte{
-		"url": []byte(url),
+		"url": []byte(options.URL),
+	}
+
+	switch ovaURLKind(options.URL) {
+	case urlKindHTTP:
+		if options.Username != "" {
+			secretData["user"] = []byte(options.Username)
+			secretData["password"] = []byte(options.Password)
+		}
+	case urlKindS3:
+		if options.Username != "" {
+			secretData["accessKeyId"] = []byte(options.Username)
+			secretData["secretAccessKey"] = []byte(options.Password)
+		}
+		if options.RegionName != "" {
+			secretData["region"] = []byte(options.RegionName)
+		}
+	}
+	if ovaURLKind(options.URL) != urlKindNFS {
+		if options.CACert != "" {
+			secretData["ca.crt"] = []byte(options.CACert)
+		}
+		if options.InsecureSkipTLS {
+			secretData["insecureSkipVerify"] = []byte("true")
+		}
 	}
 	return &corev1.Secret{
 		TypeMeta: metav1.TypeMeta{
@@ -37,13 +63,13 @@ func buildSecret(namespace, providerName, url string) *corev1.Secret {
 
 // createSecret creates an OVA secret reusing the same object shape as buildSecret.
 // It swaps the deterministic Name for a GenerateName so the API server assigns a unique suffix.
-func createSecret(configFlags *genericclioptions.ConfigFlags, namespace, providerName, url string) (*corev1.Secret, error) {
+func createSecret(configFlags *genericclioptions.ConfigFlags, namespace, providerName string, options providerutil.ProviderOptions) (*corev1.Secret, error) {
 	k8sClient, err := client.GetKubernetesClientset(configFlags)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create kubernetes client: %v", err)
 	}
 
-	secret := buildSecret(namespace, providerName, url)
+	secret := buildSecret(namespace, providerName, options)
 	secret.Name = ""
 	secret.GenerateName = fmt.Sprintf("%s-ova-", providerName)
 
@@ -52,6 +78,11 @@ func createSecret(configFlags *genericclioptions.ConfigFlags, namespace, provide
 
 // setSecretOwnership sets the provider as the owner of the secret
 func setSecretOwnership(configFlags *genericclioptions.ConfigFlags, provider *forkliftv1beta1.Provider, secret *corev1.Secret) error {
+	// A dry-run secret is not persisted, there is nothing to set the owner of
+	if client.DryRunEnabled() {
+		return nil
+	}
+
 	// Get the Kubernetes client using configFlags
 	k8sClient, err := client.GetKubernetesClientset(configFlags)
 	if err != nil {
@@ -0,0 +1,344 @@
+package ova
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	forkliftv1beta1 "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/create/provider/providerutil"
+	"github.com/yaacov/kubectl-mtv/pkg/util/client"
+	"github.com/yaacov/kubectl-mtv/pkg/util/upload"
+)
+
+const (
+	// UploadToCluster uploads the OVA to a PVC served over HTTP inside the cluster
+	UploadToCluster = "cluster"
+	// DefaultServerImage is the web server serving the upload PVC; it must support HTTP PUT (nginx dav module)
+	DefaultServerImage = "docker.io/nginxinc/nginx-unprivileged:stable-alpine"
+
+	serverPort         = 8080
+	serverDataPath     = "/data"
+	serverReadyTimeout = 5 * time.Minute
+	serverPollInterval = 2 * time.Second
+)
+
+// serverConfig lets nginx serve the PVC and accept PUT uploads of any size. Upload bodies are
+// buffered on the PVC so large OVAs do not fill the container filesystem.
+const serverConfig = `server {
+    listen 8080;
+    root /data;
+    client_max_body_size 0;
+    client_body_temp_path /data/.upload;
+    location / {
+        dav_methods PUT;
+        create_full_put_path on;
+        autoindex on;
+    }
+}
+`
+
+// ovaUpload describes where a local OVA file is uploaded and the provider URL serving it
+type ovaUpload struct {
+	File     string
+	FileName string
+	Size     int64
+	// Target is UploadToCluster or an s3:// URL
+	Target string
+	// S3Key is the object key of s3 uploads
+	S3Key string
+	// URL is the provider URL of the uploaded file
+	URL string
+}
+
+// serverName returns the name of the PVC, deployment and service of the provider upload server
+func serverName(providerName string) string {
+	return providerName + "-ova-server"
+}
+
+// planUpload resolves the upload target and provider URL of options.OVAFile without uploading
+func planUpload(options providerutil.ProviderOptions) (*ovaUpload, error) {
+	if options.URL != "" {
+		return nil, fmt.Errorf("--url and --ova-file are mutually exclusive, the provider URL is set to the uploaded file")
+	}
+	if !strings.EqualFold(filepath.Ext(options.OVAFile), ".ova") {
+		return nil, fmt.Errorf("--ova-file '%s' is not an .ova file", options.OVAFile)
+	}
+	info, err := os.Stat(options.OVAFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read OVA file: %v", err)
+	}
+	if !info.Mode().IsRegular() {
+		return nil, fmt.Errorf("--ova-file '%s' is not a regular file", options.OVAFile)
+	}
+
+	u := &ovaUpload{
+		File:     options.OVAFile,
+		FileName: filepath.Base(options.OVAFile),
+		Size:     info.Size(),
+		Target:   options.OVAUploadTo,
+	}
+	if u.Target == "" {
+		u.Target = UploadToCluster
+	}
+
+	switch {
+	case u.Target == UploadToCluster:
+		u.URL = fmt.Sprintf("http://%s.%s.svc:%d/%s", serverName(options.Name), options.Namespace, serverPort, url.PathEscape(u.FileName))
+	case strings.HasPrefix(u.Target, "s3://"):
+		location, err := upload.ParseS3URL(u.Target)
+		if err != nil {
+			return nil, err
+		}
+		u.S3Key = path.Join(location.Prefix, u.FileName)
+		u.URL = "s3://" + location.Bucket + "/" + u.S3Key
+	default:
+		return nil, fmt.Errorf("invalid --ova-upload-to '%s': use '%s' or an s3://bucket/prefix URL", u.Target, UploadToCluster)
+	}
+	return u, nil
+}
+
+// run uploads the OVA file to its target
+func (u *ovaUpload) run(ctx context.Context, configFlags *genericclioptions.ConfigFlags, options providerutil.ProviderOptions) error {
+	fmt.Fprintf(os.Stderr, "Uploading %s (%d bytes) to %s\n", u.File, u.Size, u.URL)
+	if u.Target != UploadToCluster {
+		uploader, err := upload.NewS3Uploader(u.Target)
+		if err != nil {
+			return err
+		}
+		return uploader.UploadLargeFile(ctx, u.File, u.S3Key)
+	}
+
+	clientset, err := client.GetKubernetesClientset(configFlags)
+	if err != nil {
+		return fmt.Errorf("failed to create kubernetes client: %v", err)
+	}
+
+	size, err := pvcSize(options.OVAPVCSize, u.Size)
+	if err != nil {
+		return err
+	}
+	image := options.OVAServerImage
+	if image == "" {
+		image = DefaultServerImage
+	}
+	if err := ensureServer(ctx, clientset, options.Namespace, options.Name, image, options.OVAStorageClass, size); err != nil {
+		return err
+	}
+	if err := waitForServer(ctx, clientset, options.Namespace, serverName(options.Name)); err != nil {
+		return err
+	}
+	return u.putToServer(ctx, clientset, options.Namespace, serverName(options.Name))
+}
+
+// pvcSize returns the requested PVC size, or the file size with 10% and 1Gi of headroom
+// rounded up to whole Gi
+func pvcSize(requested string, fileSize int64) (resource.Quantity, error) {
+	if requested != "" {
+		q, err := resource.ParseQuantity(requested)
+		if err != nil {
+			return resource.Quantity{}, fmt.Errorf("invalid --ova-pvc-size '%s': %v", requested, err)
+		}
+		return q, nil
+	}
+	const gi = int64(1 << 30)
+	bytes := fileSize + fileSize/10 + gi
+	return *resource.NewQuantity((bytes+gi-1)/gi*gi, resource.BinarySI), nil
+}
+
+// buildServerObjects returns the PVC, nginx configuration, deployment and service of the
+// upload server of a provider
+func buildServerObjects(namespace, providerName, image, storageClass string, size resource.Quantity) (*corev1.PersistentVolumeClaim, *corev1.ConfigMap, *appsv1.Deployment, *corev1.Service) {
+	name := serverName(providerName)
+	labels := map[string]string{
+		"app":                    name,
+		"createdForProviderType": "ova",
+		"createdForResourceType": "providers",
+	}
+	meta := func() metav1.ObjectMeta {
+		return metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: labels}
+	}
+
+	pvc := &corev1.PersistentVolumeClaim{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "PersistentVolumeClaim"},
+		ObjectMeta: meta(),
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			Resources: corev1.VolumeResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: size},
+			},
+		},
+	}
+	if storageClass != "" {
+		pvc.Spec.StorageClassName = &storageClass
+	}
+
+	configMap := &corev1.ConfigMap{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+		ObjectMeta: meta(),
+		Data:       map[string]string{"default.conf": serverConfig},
+	}
+
+	replicas := int32(1)
+	deployment := &appsv1.Deployment{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+		ObjectMeta: meta(),
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": name}},
+			// The PVC is ReadWriteOnce, the old pod must release it first
+			Strategy: appsv1.DeploymentStrategy{Type: appsv1.RecreateDeploymentStrategyType},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Name:  "server",
+						Image: image,
+						Ports: []corev1.ContainerPort{{Name: "http", ContainerPort: serverPort}},
+						ReadinessProbe: &corev1.Probe{
+							ProbeHandler: corev1.ProbeHandler{
+								TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromInt32(serverPort)},
+							},
+						},
+						VolumeMounts: []corev1.VolumeMount{
+							{Name: "data", MountPath: serverDataPath},
+							{Name: "config", MountPath: "/etc/nginx/conf.d"},
+						},
+					}},
+					Volumes: []corev1.Volume{
+						{Name: "data", VolumeSource: corev1.VolumeSource{
+							PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: name},
+						}},
+						{Name: "config", VolumeSource: corev1.VolumeSource{
+							ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: name}},
+						}},
+					},
+				},
+			},
+		},
+	}
+
+	service := &corev1.Service{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Service"},
+		ObjectMeta: meta(),
+		Spec: corev1.ServiceSpec{
+			Selector: map[string]string{"app": name},
+			Ports: []corev1.ServicePort{{
+				Name:       "http",
+				Port:       serverPort,
+				TargetPort: intstr.FromString("http"),
+			}},
+		},
+	}
+
+	return pvc, configMap, deployment, service
+}
+
+// ensureServer creates the upload server of a provider; existing objects are reused so more
+// OVA files can be uploaded to the same server
+func ensureServer(ctx context.Context, clientset kubernetes.Interface, namespace, providerName, image, storageClass string, size resource.Quantity) error {
+	pvc, configMap, deployment, service := buildServerObjects(namespace, providerName, image, storageClass, size)
+
+	if _, err := clientset.CoreV1().PersistentVolumeClaims(namespace).Create(ctx, pvc, metav1.CreateOptions{}); err != nil && !errors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create OVA server PVC '%s': %v", pvc.Name, err)
+	}
+	if _, err := clientset.CoreV1().ConfigMaps(namespace).Create(ctx, configMap, metav1.CreateOptions{}); err != nil && !errors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create OVA server ConfigMap '%s': %v", configMap.Name, err)
+	}
+	if _, err := clientset.AppsV1().Deployments(namespace).Create(ctx, deployment, metav1.CreateOptions{}); err != nil && !errors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create OVA server Deployment '%s': %v", deployment.Name, err)
+	}
+	if _, err := clientset.CoreV1().Services(namespace).Create(ctx, service, metav1.CreateOptions{}); err != nil && !errors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create OVA server Service '%s': %v", service.Name, err)
+	}
+	return nil
+}
+
+// waitForServer waits until the upload server deployment has a ready pod
+func waitForServer(ctx context.Context, clientset kubernetes.Interface, namespace, name string) error {
+	ctx, cancel := context.WithTimeout(ctx, serverReadyTimeout)
+	defer cancel()
+
+	ticker := time.NewTicker(serverPollInterval)
+	defer ticker.Stop()
+	for {
+		deployment, err := clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err == nil && deployment.Status.ReadyReplicas > 0 {
+			return nil
+		}
+		if err != nil {
+			klog.V(2).Infof("Waiting for OVA server '%s': %v", name, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("OVA server '%s' in namespace '%s' is not ready after %s, check its pod and PVC", name, namespace, serverReadyTimeout)
+		case <-ticker.C:
+		}
+	}
+}
+
+// putToServer streams the file to the upload server through the API server service proxy,
+// so no route or port-forward is needed
+func (u *ovaUpload) putToServer(ctx context.Context, clientset kubernetes.Interface, namespace, name string) error {
+	file, err := os.Open(u.File)
+	if err != nil {
+		return fmt.Errorf("failed to open '%s': %v", u.File, err)
+	}
+	defer file.Close()
+
+	result := clientset.CoreV1().RESTClient().Put().
+		Namespace(namespace).
+		Resource("services").
+		Name(name+":http").
+		SubResource("proxy").
+		Suffix(url.PathEscape(u.FileName)).
+		SetHeader("Content-Type", "application/octet-stream").
+		MaxRetries(0).
+		Body(file).
+		Do(ctx)
+	if err := result.Error(); err != nil {
+		return fmt.Errorf("failed to upload '%s' to OVA server '%s': %v", u.FileName, name, err)
+	}
+	return nil
+}
+
+// setServerOwnership makes the provider own its upload server, so deleting the provider
+// removes the server and the uploaded files
+func setServerOwnership(ctx context.Context, clientset kubernetes.Interface, provider *forkliftv1beta1.Provider) error {
+	name := serverName(provider.Name)
+	namespace := provider.Namespace
+	patch := []byte(fmt.Sprintf(`{"metadata":{"ownerReferences":[{"apiVersion":%q,"kind":%q,"name":%q,"uid":%q}]}}`,
+		provider.APIVersion, provider.Kind, provider.Name, provider.UID))
+
+	if _, err := clientset.CoreV1().PersistentVolumeClaims(namespace).Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		return fmt.Errorf("failed to set owner of OVA server PVC '%s': %v", name, err)
+	}
+	if _, err := clientset.CoreV1().ConfigMaps(namespace).Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		return fmt.Errorf("failed to set owner of OVA server ConfigMap '%s': %v", name, err)
+	}
+	if _, err := clientset.AppsV1().Deployments(namespace).Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		return fmt.Errorf("failed to set owner of OVA server Deployment '%s': %v", name, err)
+	}
+	if _, err := clientset.CoreV1().Services(namespace).Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		return fmt.Errorf("failed to set owner of OVA server Service '%s': %v", name, err)
+	}
+	return nil
+}
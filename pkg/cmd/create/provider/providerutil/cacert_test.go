@@ -0,0 +1,85 @@
+package providerutil
+
+import (
+	"bytes"
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFetchAndConfirmServerCertificates(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	defer server.Close()
+
+	certs, err := FetchServerCertificates(context.Background(), server.URL+"/sdk")
+	if err != nil {
+		t.Fatalf("FetchServerCertificates() unexpected error: %v", err)
+	}
+	fp := Fingerprint(server.Certificate())
+	if got := Fingerprint(certs[0]); got != fp {
+		t.Fatalf("fetched fingerprint = %s, want %s", got, fp)
+	}
+
+	var out bytes.Buffer
+	bundle, err := ConfirmServerCertificates(certs, strings.ToLower(strings.ReplaceAll(fp, ":", "")), false, nil, &out)
+	if err != nil {
+		t.Fatalf("ConfirmServerCertificates() with a matching fingerprint unexpected error: %v", err)
+	}
+	if block, _ := pem.Decode([]byte(bundle)); block == nil || !bytes.Equal(block.Bytes, server.Certificate().Raw) {
+		t.Errorf("pinned bundle does not hold the server certificate:\n%s", bundle)
+	}
+	if !strings.Contains(out.String(), "SHA256 "+fp) {
+		t.Errorf("certificate details missing the fingerprint:\n%s", out.String())
+	}
+
+	if _, err := ConfirmServerCertificates(certs, "AA:BB", false, nil, &out); err == nil || !strings.Contains(err.Error(), "does not match") {
+		t.Errorf("ConfirmServerCertificates() with a wrong fingerprint error = %v, want a mismatch", err)
+	}
+	if _, err := ConfirmServerCertificates(certs, "", false, nil, &out); err == nil || !strings.Contains(err.Error(), "--cacert-fingerprint") {
+		t.Errorf("ConfirmServerCertificates() without a terminal error = %v, want a hint to pass the fingerprint", err)
+	}
+	if _, err := ConfirmServerCertificates(certs, "", true, strings.NewReader("n\n"), &out); err == nil {
+		t.Error("ConfirmServerCertificates() declined by the user, want an error")
+	}
+	if _, err := ConfirmServerCertificates(certs, "", true, strings.NewReader("y\n"), &out); err != nil {
+		t.Errorf("ConfirmServerCertificates() accepted by the user unexpected error: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(path, []byte(bundle), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := ResolveCACert(context.Background(), CACertSource{File: path}, "", false, nil, &out)
+	if err != nil || loaded != bundle {
+		t.Errorf("ResolveCACert() from --cacert-file = %q, %v, want the file content", loaded, err)
+	}
+}
+
+func TestResolveCACertErrors(t *testing.T) {
+	notPEM := filepath.Join(t.TempDir(), "ca.txt")
+	if err := os.WriteFile(notPEM, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		source CACertSource
+		want   string
+	}{
+		{"two sources", CACertSource{CACert: "pem", Fetch: true}, "cannot be used together"},
+		{"fingerprint without fetch", CACertSource{Fingerprint: "AA"}, "requires --fetch-cacert"},
+		{"file without certificates", CACertSource{File: notPEM}, "no PEM certificate"},
+		{"fetch without URL", CACertSource{Fetch: true}, "requires the provider URL"},
+	}
+	for _, tt := range tests {
+		_, err := ResolveCACert(context.Background(), tt.source, "", false, nil, &bytes.Buffer{})
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: ResolveCACert() error = %v, want it to mention %q", tt.name, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,255 @@
+package providerutil
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/yaacov/kubectl-mtv/pkg/util/upload"
+)
+
+// FromEnvProviderTypes are the provider types supported by DetectCredentials
+var FromEnvProviderTypes = []string{"vsphere", "ovirt", "openstack"}
+
+// EnvCredentials are the provider connection settings found in the configuration of the
+// standard client tools of a provider type
+type EnvCredentials struct {
+	URL             string
+	Username        string
+	Password        string
+	Token           string
+	InsecureSkipTLS bool
+	// CACertFile is the path of the CA bundle configured for the client
+	CACertFile string
+	// CACert is a PEM CA bundle configured inline
+	CACert string
+	// OpenStack specific settings
+	DomainName  string
+	ProjectName string
+	RegionName  string
+	// Source describes where the settings were found
+	Source string
+}
+
+// Environment gives access to the environment variables and files the settings are read from
+type Environment struct {
+	Getenv  func(string) string
+	HomeDir string
+	WorkDir string
+}
+
+// CurrentEnvironment returns the environment of the running process
+func CurrentEnvironment() Environment {
+	home, _ := os.UserHomeDir()
+	wd, _ := os.Getwd()
+	return Environment{Getenv: os.Getenv, HomeDir: home, WorkDir: wd}
+}
+
+// DetectCredentials reads the connection settings of a provider type from its client tools:
+//   - vsphere: the GOVC_URL, GOVC_USERNAME, GOVC_PASSWORD, GOVC_INSECURE and GOVC_TLS_CA_CERTS
+//     variables of govc
+//   - ovirt: the OVIRT_URL, OVIRT_USERNAME, OVIRT_PASSWORD and OVIRT_CAFILE variables, or the
+//     [ovirt-shell] section of ~/.ovirtshellrc
+//   - openstack: the OS_* variables of an openrc file, or the cloud selected by OS_CLOUD in
+//     clouds.yaml
+func DetectCredentials(providerType string, env Environment) (*EnvCredentials, error) {
+	switch providerType {
+	case "vsphere":
+		return detectGovc(env)
+	case "ovirt":
+		return detectOvirt(env)
+	case "openstack":
+		return detectOpenStack(env)
+	default:
+		return nil, fmt.Errorf("--from-env supports provider types %s, not '%s'", strings.Join(FromEnvProviderTypes, ", "), providerType)
+	}
+}
+
+// detectGovc reads the govc environment variables
+func detectGovc(env Environment) (*EnvCredentials, error) {
+	rawURL := env.Getenv("GOVC_URL")
+	if rawURL == "" {
+		return nil, fmt.Errorf("GOVC_URL is not set")
+	}
+
+	// govc accepts a bare host name and credentials in the URL
+	if !strings.Contains(rawURL, "://") {
+		rawURL = "https://" + rawURL
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid GOVC_URL: %v", err)
+	}
+	creds := &EnvCredentials{
+		Username:   env.Getenv("GOVC_USERNAME"),
+		Password:   env.Getenv("GOVC_PASSWORD"),
+		CACertFile: env.Getenv("GOVC_TLS_CA_CERTS"),
+		Source:     "GOVC_* environment variables",
+	}
+	if u.User != nil {
+		if creds.Username == "" {
+			creds.Username = u.User.Username()
+		}
+		if password, ok := u.User.Password(); ok && creds.Password == "" {
+			creds.Password = password
+		}
+		u.User = nil
+	}
+	if u.Path == "" || u.Path == "/" {
+		u.Path = "/sdk"
+	}
+	creds.URL = u.String()
+	creds.InsecureSkipTLS = parseEnvBool(env.Getenv("GOVC_INSECURE"))
+	return creds, nil
+}
+
+// detectOvirt reads the OVIRT_* environment variables, or ~/.ovirtshellrc
+func detectOvirt(env Environment) (*EnvCredentials, error) {
+	if u := env.Getenv("OVIRT_URL"); u != "" {
+		return &EnvCredentials{
+			URL:             u,
+			Username:        env.Getenv("OVIRT_USERNAME"),
+			Password:        env.Getenv("OVIRT_PASSWORD"),
+			CACertFile:      env.Getenv("OVIRT_CAFILE"),
+			InsecureSkipTLS: parseEnvBool(env.Getenv("OVIRT_INSECURE")),
+			Source:          "OVIRT_* environment variables",
+		}, nil
+	}
+
+	path := filepath.Join(env.HomeDir, ".ovirtshellrc")
+	section, err := upload.ReadINISection(path, "ovirt-shell")
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("OVIRT_URL is not set and %s does not exist", path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if section["url"] == "" {
+		return nil, fmt.Errorf("%s has no url in its [ovirt-shell] section", path)
+	}
+	return &EnvCredentials{
+		URL:             section["url"],
+		Username:        section["username"],
+		Password:        section["password"],
+		CACertFile:      section["ca_file"],
+		InsecureSkipTLS: parseEnvBool(section["insecure"]),
+		Source:          path,
+	}, nil
+}
+
+// detectOpenStack reads the OS_* environment variables, or clouds.yaml
+func detectOpenStack(env Environment) (*EnvCredentials, error) {
+	if u := env.Getenv("OS_AUTH_URL"); u != "" {
+		return &EnvCredentials{
+			URL:             u,
+			Username:        env.Getenv("OS_USERNAME"),
+			Password:        env.Getenv("OS_PASSWORD"),
+			DomainName:      firstNonEmpty(env.Getenv("OS_USER_DOMAIN_NAME"), env.Getenv("OS_PROJECT_DOMAIN_NAME"), env.Getenv("OS_DOMAIN_NAME")),
+			ProjectName:     firstNonEmpty(env.Getenv("OS_PROJECT_NAME"), env.Getenv("OS_TENANT_NAME")),
+			RegionName:      env.Getenv("OS_REGION_NAME"),
+			CACertFile:      env.Getenv("OS_CACERT"),
+			InsecureSkipTLS: parseEnvBool(env.Getenv("OS_INSECURE")),
+			Source:          "OS_* environment variables",
+		}, nil
+	}
+
+	path, err := findCloudsYAML(env)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %v", path, err)
+	}
+	var config struct {
+		Clouds map[string]struct {
+			Auth struct {
+				AuthURL           string `json:"auth_url"`
+				Username          string `json:"username"`
+				Password          string `json:"password"`
+				ProjectName       string `json:"project_name"`
+				UserDomainName    string `json:"user_domain_name"`
+				ProjectDomainName string `json:"project_domain_name"`
+				DomainName        string `json:"domain_name"`
+			} `json:"auth"`
+			RegionName string `json:"region_name"`
+			CACert     string `json:"cacert"`
+			Verify     *bool  `json:"verify"`
+		} `json:"clouds"`
+	}
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %v", path, err)
+	}
+
+	names := make([]string, 0, len(config.Clouds))
+	for name := range config.Clouds {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	name := env.Getenv("OS_CLOUD")
+	switch {
+	case name == "" && len(names) == 1:
+		name = names[0]
+	case name == "":
+		return nil, fmt.Errorf("%s has clouds %s: select one with OS_CLOUD", path, strings.Join(names, ", "))
+	}
+	cloud, ok := config.Clouds[name]
+	if !ok {
+		return nil, fmt.Errorf("cloud '%s' not found in %s (clouds: %s)", name, path, strings.Join(names, ", "))
+	}
+	if cloud.Auth.AuthURL == "" {
+		return nil, fmt.Errorf("cloud '%s' in %s has no auth_url", name, path)
+	}
+	return &EnvCredentials{
+		URL:             cloud.Auth.AuthURL,
+		Username:        cloud.Auth.Username,
+		Password:        cloud.Auth.Password,
+		DomainName:      firstNonEmpty(cloud.Auth.UserDomainName, cloud.Auth.ProjectDomainName, cloud.Auth.DomainName),
+		ProjectName:     cloud.Auth.ProjectName,
+		RegionName:      cloud.RegionName,
+		CACertFile:      cloud.CACert,
+		InsecureSkipTLS: cloud.Verify != nil && !*cloud.Verify,
+		Source:          fmt.Sprintf("cloud '%s' in %s", name, path),
+	}, nil
+}
+
+// findCloudsYAML returns the clouds.yaml file the OpenStack client would use
+func findCloudsYAML(env Environment) (string, error) {
+	if path := env.Getenv("OS_CLIENT_CONFIG_FILE"); path != "" {
+		return path, nil
+	}
+	candidates := []string{
+		filepath.Join(env.WorkDir, "clouds.yaml"),
+		filepath.Join(env.HomeDir, ".config", "openstack", "clouds.yaml"),
+		"/etc/openstack/clouds.yaml",
+	}
+	for _, path := range candidates {
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("OS_AUTH_URL is not set and no clouds.yaml was found in %s", strings.Join(candidates, ", "))
+}
+
+// parseEnvBool reads boolean settings such as "1", "true" or "True"
+func parseEnvBool(value string) bool {
+	b, err := strconv.ParseBool(strings.TrimSpace(value))
+	return err == nil && b
+}
+
+// firstNonEmpty returns the first non-empty value
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,132 @@
+package providerutil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func testEnvironment(t *testing.T, vars map[string]string) Environment {
+	return Environment{
+		Getenv:  func(name string) string { return vars[name] },
+		HomeDir: t.TempDir(),
+		WorkDir: t.TempDir(),
+	}
+}
+
+func TestDetectGovc(t *testing.T) {
+	env := testEnvironment(t, map[string]string{
+		"GOVC_URL":      "admin@vsphere.local:s3cret@vcenter.example.com",
+		"GOVC_INSECURE": "1",
+	})
+	creds, err := DetectCredentials("vsphere", env)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if creds.URL != "https://vcenter.example.com/sdk" {
+		t.Errorf("URL = %q, want the credentials removed and /sdk added", creds.URL)
+	}
+	if creds.Username != "admin@vsphere.local" || creds.Password != "s3cret" || !creds.InsecureSkipTLS {
+		t.Errorf("credentials = %+v", creds)
+	}
+
+	// Explicit variables win over the URL user information
+	env = testEnvironment(t, map[string]string{
+		"GOVC_URL":      "https://user@vcenter.example.com/sdk",
+		"GOVC_USERNAME": "admin",
+		"GOVC_PASSWORD": "pw",
+	})
+	creds, err = DetectCredentials("vsphere", env)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if creds.Username != "admin" || creds.Password != "pw" || creds.InsecureSkipTLS {
+		t.Errorf("credentials = %+v", creds)
+	}
+
+	if _, err := DetectCredentials("vsphere", testEnvironment(t, nil)); err == nil {
+		t.Error("a missing GOVC_URL should fail")
+	}
+}
+
+func TestDetectOvirtShellrc(t *testing.T) {
+	env := testEnvironment(t, nil)
+	rc := `[cli]
+autoconnect = True
+
+[ovirt-shell]
+username = admin@internal
+password = s3cret
+url = https://engine.example.com/ovirt-engine/api
+insecure = False
+ca_file = /etc/pki/ovirt-engine/ca.pem
+`
+	if err := os.WriteFile(filepath.Join(env.HomeDir, ".ovirtshellrc"), []byte(rc), 0600); err != nil {
+		t.Fatal(err)
+	}
+	creds, err := DetectCredentials("ovirt", env)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if creds.URL != "https://engine.example.com/ovirt-engine/api" || creds.Username != "admin@internal" ||
+		creds.Password != "s3cret" || creds.CACertFile != "/etc/pki/ovirt-engine/ca.pem" || creds.InsecureSkipTLS {
+		t.Errorf("credentials = %+v", creds)
+	}
+}
+
+func TestDetectOpenStack(t *testing.T) {
+	env := testEnvironment(t, map[string]string{"OS_CLOUD": "prod"})
+	clouds := `clouds:
+  lab:
+    auth:
+      auth_url: https://lab.example.com:5000/v3
+  prod:
+    auth:
+      auth_url: https://keystone.example.com:5000/v3
+      username: admin
+      password: s3cret
+      project_name: migrations
+      user_domain_name: Default
+    region_name: RegionOne
+    verify: false
+`
+	dir := filepath.Join(env.HomeDir, ".config", "openstack")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "clouds.yaml"), []byte(clouds), 0600); err != nil {
+		t.Fatal(err)
+	}
+	creds, err := DetectCredentials("openstack", env)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if creds.URL != "https://keystone.example.com:5000/v3" || creds.Username != "admin" || creds.ProjectName != "migrations" ||
+		creds.DomainName != "Default" || creds.RegionName != "RegionOne" || !creds.InsecureSkipTLS {
+		t.Errorf("credentials = %+v", creds)
+	}
+
+	// Several clouds need OS_CLOUD
+	env.Getenv = func(string) string { return "" }
+	if _, err := DetectCredentials("openstack", env); err == nil {
+		t.Error("several clouds without OS_CLOUD should fail")
+	}
+
+	// openrc variables come first
+	env.Getenv = func(name string) string {
+		return map[string]string{"OS_AUTH_URL": "https://rc.example.com:5000/v3", "OS_TENANT_NAME": "demo"}[name]
+	}
+	creds, err = DetectCredentials("openstack", env)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if creds.URL != "https://rc.example.com:5000/v3" || creds.ProjectName != "demo" {
+		t.Errorf("credentials = %+v", creds)
+	}
+}
+
+func TestDetectCredentialsUnsupportedType(t *testing.T) {
+	if _, err := DetectCredentials("ec2", testEnvironment(t, nil)); err == nil {
+		t.Error("ec2 should not be supported")
+	}
+}
@@ -0,0 +1,60 @@
+package providerutil
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// KubeconfigContextCredentials reads the API server URL, bearer token and CA of a kubeconfig
+// context, the connection settings of a remote OpenShift provider. The context must
+// authenticate with a token (e.g. after oc login); client certificates and exec plugins
+// cannot be stored in a provider secret.
+func KubeconfigContextCredentials(config clientcmdapi.Config, contextName string) (*EnvCredentials, error) {
+	kubeContext, ok := config.Contexts[contextName]
+	if !ok {
+		names := make([]string, 0, len(config.Contexts))
+		for name := range config.Contexts {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("kubeconfig context '%s' not found, available contexts: %s", contextName, strings.Join(names, ", "))
+	}
+
+	cluster, ok := config.Clusters[kubeContext.Cluster]
+	if !ok || cluster.Server == "" {
+		return nil, fmt.Errorf("kubeconfig context '%s' refers to cluster '%s' that has no server", contextName, kubeContext.Cluster)
+	}
+	authInfo, ok := config.AuthInfos[kubeContext.AuthInfo]
+	if !ok {
+		return nil, fmt.Errorf("kubeconfig context '%s' refers to user '%s' that is not defined", contextName, kubeContext.AuthInfo)
+	}
+
+	token := authInfo.Token
+	if token == "" && authInfo.TokenFile != "" {
+		data, err := os.ReadFile(authInfo.TokenFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read token file of kubeconfig context '%s': %v", contextName, err)
+		}
+		token = strings.TrimSpace(string(data))
+	}
+	if token == "" {
+		return nil, fmt.Errorf("kubeconfig context '%s' has no bearer token, log in with 'oc login --token' or pass --provider-token", contextName)
+	}
+
+	creds := &EnvCredentials{
+		URL:             cluster.Server,
+		Token:           token,
+		InsecureSkipTLS: cluster.InsecureSkipTLSVerify,
+		CACertFile:      cluster.CertificateAuthority,
+		Source:          fmt.Sprintf("kubeconfig context '%s'", contextName),
+	}
+	if len(cluster.CertificateAuthorityData) > 0 {
+		creds.CACert = string(cluster.CertificateAuthorityData)
+		creds.CACertFile = ""
+	}
+	return creds, nil
+}
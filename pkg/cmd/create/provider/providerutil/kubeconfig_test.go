@@ -0,0 +1,48 @@
+package providerutil
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+func testKubeconfig(auth *clientcmdapi.AuthInfo) clientcmdapi.Config {
+	return clientcmdapi.Config{
+		Clusters: map[string]*clientcmdapi.Cluster{
+			"remote": {Server: "https://api.remote.example.com:6443", CertificateAuthorityData: []byte("PEM")},
+		},
+		AuthInfos: map[string]*clientcmdapi.AuthInfo{"admin": auth},
+		Contexts: map[string]*clientcmdapi.Context{
+			"remote-admin": {Cluster: "remote", AuthInfo: "admin"},
+		},
+	}
+}
+
+func TestKubeconfigContextCredentials(t *testing.T) {
+	creds, err := KubeconfigContextCredentials(testKubeconfig(&clientcmdapi.AuthInfo{Token: "sha256~abc"}), "remote-admin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if creds.URL != "https://api.remote.example.com:6443" || creds.Token != "sha256~abc" || creds.CACert != "PEM" || creds.InsecureSkipTLS {
+		t.Errorf("credentials = %+v", creds)
+	}
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte("from-file\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	creds, err = KubeconfigContextCredentials(testKubeconfig(&clientcmdapi.AuthInfo{TokenFile: tokenFile}), "remote-admin")
+	if err != nil || creds.Token != "from-file" {
+		t.Errorf("token file credentials = %+v, %v", creds, err)
+	}
+
+	if _, err := KubeconfigContextCredentials(testKubeconfig(&clientcmdapi.AuthInfo{ClientCertificate: "cert.pem"}), "remote-admin"); err == nil || !strings.Contains(err.Error(), "no bearer token") {
+		t.Errorf("client certificate context error = %v, want no bearer token", err)
+	}
+	if _, err := KubeconfigContextCredentials(testKubeconfig(&clientcmdapi.AuthInfo{Token: "t"}), "other"); err == nil || !strings.Contains(err.Error(), "remote-admin") {
+		t.Errorf("unknown context error = %v, want the available contexts", err)
+	}
+}
@@ -27,6 +27,12 @@ type ProviderOptions struct {
 	SMBUrl      string
 	SMBUser     string
 	SMBPassword string
+	// OVA specific options
+	OVAFile         string // Local OVA file uploaded before the provider is created
+	OVAUploadTo     string // Upload target: "cluster" (PVC served over HTTP) or an s3://bucket/prefix URL
+	OVAStorageClass string // Storage class of the upload PVC
+	OVAPVCSize      string // Size of the upload PVC, derived from the file size when empty
+	OVAServerImage  string // Web server image serving the upload PVC
 	// EC2 specific options
 	EC2Region             string
 	EC2TargetRegion       string
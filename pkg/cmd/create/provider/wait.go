@@ -0,0 +1,38 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/yaacov/kubectl-mtv/pkg/util/client"
+	"github.com/yaacov/kubectl-mtv/pkg/util/wait"
+)
+
+// Wait blocks until the provider is Ready and its inventory is loaded, returning an error when
+// the provider keeps reporting critical conditions (e.g. a failed connection test) or the
+// timeout expires.
+func Wait(ctx context.Context, configFlags *genericclioptions.ConfigFlags, name, namespace string, opts wait.Options) error {
+	c, err := client.GetDynamicClient(configFlags)
+	if err != nil {
+		return fmt.Errorf("failed to get client: %v", err)
+	}
+
+	fmt.Fprintf(opts.Out, "Waiting for provider '%s' to become Ready...\n", name)
+	ri := c.Resource(client.ProvidersGVR).Namespace(namespace)
+	if _, err := wait.For(ctx, ri, "provider", name, "become Ready", ReadyCheck, opts); err != nil {
+		return err
+	}
+	fmt.Fprintf(opts.Out, "provider/%s is Ready, inventory loaded\n", name)
+	return nil
+}
+
+// ReadyCheck is done when the provider is Ready and its inventory was created
+func ReadyCheck(obj *unstructured.Unstructured) (bool, []string) {
+	if wait.ConditionTrue(obj, "Ready") && wait.ConditionTrue(obj, "InventoryCreated") {
+		return true, nil
+	}
+	return false, wait.BlockingConditions(obj)
+}
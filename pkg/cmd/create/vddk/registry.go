@@ -0,0 +1,373 @@
+package vddk
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/yaacov/kubectl-mtv/pkg/util/client"
+)
+
+// RegistryAuth holds registry credentials in the format of a docker config.json
+type RegistryAuth struct {
+	// Source tells where the credentials were read from, for messages
+	Source string                        `json:"-"`
+	Auths  map[string]registryCredential `json:"auths"`
+}
+
+type registryCredential struct {
+	Auth          string `json:"auth,omitempty"`
+	Username      string `json:"username,omitempty"`
+	Password      string `json:"password,omitempty"`
+	IdentityToken string `json:"identitytoken,omitempty"`
+}
+
+// LoadPushSecret reads registry credentials from a Kubernetes secret of type
+// kubernetes.io/dockerconfigjson or kubernetes.io/dockercfg, such as a pull secret.
+func LoadPushSecret(ctx context.Context, configFlags *genericclioptions.ConfigFlags, namespace, name string) (*RegistryAuth, error) {
+	c, err := client.GetDynamicClient(configFlags)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get client: %v", err)
+	}
+
+	secret, err := c.Resource(client.SecretsGVR).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get push secret '%s': %v", name, err)
+	}
+
+	data, _, _ := unstructured.NestedStringMap(secret.Object, "data")
+	source := fmt.Sprintf("secret %s/%s", namespace, name)
+	if encoded, ok := data[corev1.DockerConfigJsonKey]; ok {
+		raw, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s of push secret '%s': %v", corev1.DockerConfigJsonKey, name, err)
+		}
+		return parseRegistryAuth(raw, false, source)
+	}
+	if encoded, ok := data[corev1.DockerConfigKey]; ok {
+		raw, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s of push secret '%s': %v", corev1.DockerConfigKey, name, err)
+		}
+		return parseRegistryAuth(raw, true, source)
+	}
+	return nil, fmt.Errorf("push secret '%s' has no %s or %s key, create it with 'kubectl create secret docker-registry'",
+		name, corev1.DockerConfigJsonKey, corev1.DockerConfigKey)
+}
+
+// parseRegistryAuth parses a docker config.json, or with legacy a .dockercfg that holds
+// the auths map directly
+func parseRegistryAuth(data []byte, legacy bool, source string) (*RegistryAuth, error) {
+	auth := &RegistryAuth{Source: source}
+	var err error
+	if legacy {
+		err = json.Unmarshal(data, &auth.Auths)
+	} else {
+		err = json.Unmarshal(data, auth)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse registry credentials of %s: %v", source, err)
+	}
+	if len(auth.Auths) == 0 {
+		return nil, fmt.Errorf("%s has no registry credentials", source)
+	}
+	return auth, nil
+}
+
+// loadLocalAuth reads the credentials podman and docker saved with 'login', so pushes
+// done with them can be verified. Credential helpers are not supported.
+func loadLocalAuth() *RegistryAuth {
+	var files []string
+	if f := os.Getenv("REGISTRY_AUTH_FILE"); f != "" {
+		files = append(files, f)
+	}
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		files = append(files, filepath.Join(dir, "containers", "auth.json"))
+	}
+	if dir := os.Getenv("DOCKER_CONFIG"); dir != "" {
+		files = append(files, filepath.Join(dir, "config.json"))
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		files = append(files, filepath.Join(home, ".config", "containers", "auth.json"), filepath.Join(home, ".docker", "config.json"))
+	}
+
+	merged := &RegistryAuth{Source: "local registry logins", Auths: map[string]registryCredential{}}
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			continue
+		}
+		auth, err := parseRegistryAuth(data, false, f)
+		if err != nil {
+			continue
+		}
+		for registry, cred := range auth.Auths {
+			if _, ok := merged.Auths[registry]; !ok {
+				merged.Auths[registry] = cred
+			}
+		}
+	}
+	if len(merged.Auths) == 0 {
+		return nil
+	}
+	return merged
+}
+
+// writeAuthFile writes the credentials as config.json in dir, the layout docker --config
+// expects; podman --authfile reads the same file
+func (a *RegistryAuth) writeAuthFile(dir string) (string, error) {
+	data, err := json.Marshal(map[string]interface{}{"auths": a.Auths})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode registry credentials: %w", err)
+	}
+	path := filepath.Join(dir, "config.json")
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return "", fmt.Errorf("failed to write registry credentials: %w", err)
+	}
+	return path, nil
+}
+
+// credentials returns the user and password for a registry host. Keys of the auths map
+// may carry a scheme and a repository path, e.g. https://quay.io/myorg.
+func (a *RegistryAuth) credentials(ref imageRef) (string, string, bool) {
+	if a == nil {
+		return "", "", false
+	}
+
+	best, bestLen := registryCredential{}, -1
+	for key, cred := range a.Auths {
+		key = strings.TrimPrefix(strings.TrimPrefix(key, "https://"), "http://")
+		key = strings.TrimSuffix(key, "/v1/")
+		host, path, _ := strings.Cut(strings.TrimSuffix(key, "/"), "/")
+		if !ref.matchesHost(host) {
+			continue
+		}
+		if path != "" && path != ref.Repository && !strings.HasPrefix(ref.Repository, path+"/") {
+			continue
+		}
+		if len(path) > bestLen {
+			best, bestLen = cred, len(path)
+		}
+	}
+	if bestLen < 0 {
+		return "", "", false
+	}
+
+	if best.Username != "" || best.Password != "" {
+		return best.Username, best.Password, true
+	}
+	if best.IdentityToken != "" {
+		return "<token>", best.IdentityToken, true
+	}
+	decoded, err := base64.StdEncoding.DecodeString(best.Auth)
+	if err != nil {
+		return "", "", false
+	}
+	user, pass, ok := strings.Cut(string(decoded), ":")
+	return user, pass, ok
+}
+
+// imageRef is an image tag split into the registry, repository and tag or digest
+type imageRef struct {
+	Registry   string
+	Repository string
+	Reference  string
+}
+
+// dockerHub is the registry of image names without a registry host
+const dockerHub = "registry-1.docker.io"
+
+// parseImageRef splits an image tag such as quay.io/myorg/vddk:8.0.1
+func parseImageRef(tag string) (imageRef, error) {
+	ref := imageRef{Registry: dockerHub, Reference: "latest"}
+
+	name := tag
+	if i := strings.Index(name, "@"); i >= 0 {
+		name, ref.Reference = name[:i], name[i+1:]
+	} else if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		name, ref.Reference = name[:i], name[i+1:]
+	}
+
+	if first, rest, found := strings.Cut(name, "/"); found && (strings.ContainsAny(first, ".:") || first == "localhost") {
+		ref.Registry, name = first, rest
+	}
+	if ref.Registry == dockerHub && !strings.Contains(name, "/") {
+		name = "library/" + name
+	}
+	ref.Repository = name
+
+	if ref.Repository == "" || ref.Reference == "" {
+		return imageRef{}, fmt.Errorf("invalid image tag '%s'", tag)
+	}
+	return ref, nil
+}
+
+// matchesHost reports whether a host of a credentials key is the registry of the image
+func (r imageRef) matchesHost(host string) bool {
+	if r.Registry == dockerHub {
+		return host == "docker.io" || host == "index.docker.io" || host == dockerHub
+	}
+	return host == r.Registry
+}
+
+// manifestAccept lists the manifest types a pushed image may have
+var manifestAccept = strings.Join([]string{
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+}, ", ")
+
+// errVerifyUnauthorized means the registry refused to show the manifest with the known credentials
+var errVerifyUnauthorized = errors.New("the registry requires credentials to read the image")
+
+// verifyManifest checks with the registry API that the manifest of a pushed image exists
+func verifyManifest(ctx context.Context, tag string, auth *RegistryAuth, insecureSkipTLS bool) error {
+	ref, err := parseImageRef(tag)
+	if err != nil {
+		return err
+	}
+
+	httpClient := &http.Client{Timeout: 30 * time.Second}
+	if insecureSkipTLS {
+		httpClient.Transport = &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}} // #nosec G402 -- requested with --push-insecure-skip-tls
+	}
+
+	manifestURL := fmt.Sprintf("https://%s/v2/%s/manifests/%s", ref.Registry, ref.Repository, ref.Reference)
+	resp, err := headManifest(ctx, httpClient, manifestURL, "")
+	if err != nil && insecureSkipTLS {
+		// Insecure registries often serve plain HTTP
+		manifestURL = "http" + strings.TrimPrefix(manifestURL, "https")
+		resp, err = headManifest(ctx, httpClient, manifestURL, "")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to reach registry %s: %w", ref.Registry, err)
+	}
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		authorization, err := authorize(ctx, httpClient, resp.Header.Get("WWW-Authenticate"), ref, auth)
+		if err != nil {
+			return err
+		}
+		if resp, err = headManifest(ctx, httpClient, manifestURL, authorization); err != nil {
+			return fmt.Errorf("failed to reach registry %s: %w", ref.Registry, err)
+		}
+	}
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode == http.StatusNotFound:
+		return fmt.Errorf("image manifest %s:%s not found in registry %s", ref.Repository, ref.Reference, ref.Registry)
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return errVerifyUnauthorized
+	default:
+		return fmt.Errorf("registry %s answered %s for image manifest %s:%s", ref.Registry, resp.Status, ref.Repository, ref.Reference)
+	}
+}
+
+func headManifest(ctx context.Context, httpClient *http.Client, manifestURL, authorization string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, manifestURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", manifestAccept)
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	return resp, nil
+}
+
+// authorize answers a registry challenge: basic credentials, or a bearer token from the
+// token service of the challenge, fetched with the credentials when known
+func authorize(ctx context.Context, httpClient *http.Client, challenge string, ref imageRef, auth *RegistryAuth) (string, error) {
+	user, pass, hasCreds := auth.credentials(ref)
+
+	scheme, params := parseChallenge(challenge)
+	switch scheme {
+	case "basic":
+		if !hasCreds {
+			return "", errVerifyUnauthorized
+		}
+		return "Basic " + base64.StdEncoding.EncodeToString([]byte(user+":"+pass)), nil
+	case "bearer":
+		tokenURL, err := url.Parse(params["realm"])
+		if err != nil || params["realm"] == "" {
+			return "", fmt.Errorf("registry %s sent an invalid token realm", ref.Registry)
+		}
+		query := tokenURL.Query()
+		if service := params["service"]; service != "" {
+			query.Set("service", service)
+		}
+		query.Set("scope", fmt.Sprintf("repository:%s:pull", ref.Repository))
+		tokenURL.RawQuery = query.Encode()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, tokenURL.String(), nil)
+		if err != nil {
+			return "", err
+		}
+		if hasCreds {
+			req.SetBasicAuth(user, pass)
+		}
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			return "", fmt.Errorf("failed to get a registry token: %w", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+			return "", errVerifyUnauthorized
+		}
+		if resp.StatusCode != http.StatusOK {
+			return "", fmt.Errorf("registry token service answered %s", resp.Status)
+		}
+
+		var token struct {
+			Token       string `json:"token"`
+			AccessToken string `json:"access_token"`
+		}
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+		if err := json.Unmarshal(body, &token); err != nil {
+			return "", fmt.Errorf("failed to parse registry token: %w", err)
+		}
+		if token.Token == "" {
+			token.Token = token.AccessToken
+		}
+		return "Bearer " + token.Token, nil
+	default:
+		return "", errVerifyUnauthorized
+	}
+}
+
+// parseChallenge parses a WWW-Authenticate header such as
+// Bearer realm="https://auth.example.com/token",service="registry.example.com"
+func parseChallenge(challenge string) (string, map[string]string) {
+	scheme, rest, _ := strings.Cut(strings.TrimSpace(challenge), " ")
+	params := map[string]string{}
+	for _, part := range strings.Split(rest, ",") {
+		key, value, found := strings.Cut(strings.TrimSpace(part), "=")
+		if found {
+			params[strings.ToLower(key)] = strings.Trim(value, `"`)
+		}
+	}
+	return strings.ToLower(scheme), params
+}
@@ -0,0 +1,153 @@
+package vddk
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseImageRef(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want imageRef
+	}{
+		{"quay.io/myorg/vddk:8.0.1", imageRef{"quay.io", "myorg/vddk", "8.0.1"}},
+		{"registry.local:5000/vddk", imageRef{"registry.local:5000", "vddk", "latest"}},
+		{"localhost/vddk:1", imageRef{"localhost", "vddk", "1"}},
+		{"vddk:8", imageRef{dockerHub, "library/vddk", "8"}},
+		{"myorg/vddk@sha256:abc", imageRef{dockerHub, "myorg/vddk", "sha256:abc"}},
+	}
+	for _, tt := range tests {
+		got, err := parseImageRef(tt.tag)
+		if err != nil || got != tt.want {
+			t.Errorf("parseImageRef(%q) = %+v, %v, want %+v", tt.tag, got, err, tt.want)
+		}
+	}
+}
+
+func TestRegistryAuthCredentials(t *testing.T) {
+	basic := base64.StdEncoding.EncodeToString([]byte("org-user:org-pass"))
+	auth, err := parseRegistryAuth([]byte(`{"auths":{
+		"https://quay.io":{"username":"user","password":"pass"},
+		"quay.io/myorg":{"auth":"`+basic+`"},
+		"https://index.docker.io/v1/":{"username":"hub","password":"hub-pass"}}}`), false, "test")
+	if err != nil {
+		t.Fatalf("parseRegistryAuth() unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		tag, user, pass string
+	}{
+		{"quay.io/other/vddk:1", "user", "pass"},
+		{"quay.io/myorg/vddk:1", "org-user", "org-pass"},
+		{"myorg/vddk:1", "hub", "hub-pass"},
+	}
+	for _, tt := range tests {
+		ref, _ := parseImageRef(tt.tag)
+		user, pass, ok := auth.credentials(ref)
+		if !ok || user != tt.user || pass != tt.pass {
+			t.Errorf("credentials(%s) = %s, %s, %v, want %s, %s", tt.tag, user, pass, ok, tt.user, tt.pass)
+		}
+	}
+
+	ref, _ := parseImageRef("ghcr.io/myorg/vddk:1")
+	if _, _, ok := auth.credentials(ref); ok {
+		t.Errorf("credentials(ghcr.io) found, want none")
+	}
+
+	legacy, err := parseRegistryAuth([]byte(`{"quay.io":{"auth":"`+basic+`"}}`), true, "legacy")
+	if err != nil || len(legacy.Auths) != 1 {
+		t.Errorf("parseRegistryAuth(legacy) = %+v, %v", legacy, err)
+	}
+}
+
+func TestPushCommandArgs(t *testing.T) {
+	auth := &RegistryAuth{Source: "test", Auths: map[string]registryCredential{"quay.io": {Username: "u", Password: "p"}}}
+
+	args, cleanup, err := pushCommandArgs("podman", auth)
+	if err != nil {
+		t.Fatalf("pushCommandArgs() unexpected error: %v", err)
+	}
+	if len(args) != 3 || args[1] != "--authfile" {
+		t.Fatalf("podman args = %v, want push --authfile FILE", args)
+	}
+	data, err := os.ReadFile(args[2])
+	if err != nil || !strings.Contains(string(data), `"auths"`) || strings.Contains(string(data), "Source") {
+		t.Errorf("auth file = %s, %v, want only the auths", data, err)
+	}
+	cleanup()
+	if _, err := os.Stat(filepath.Dir(args[2])); !os.IsNotExist(err) {
+		t.Errorf("auth dir not removed: %v", err)
+	}
+
+	args, cleanup, _ = pushCommandArgs("docker", auth)
+	defer cleanup()
+	if len(args) != 3 || args[0] != "--config" || args[2] != "push" {
+		t.Errorf("docker args = %v, want --config DIR push", args)
+	}
+
+	if args, _, _ := pushCommandArgs("podman", nil); len(args) != 1 {
+		t.Errorf("args without credentials = %v, want push", args)
+	}
+}
+
+// newTestRegistry serves the manifest of myorg/vddk:8.0.1 to clients with a token issued
+// for user:pass
+func newTestRegistry(t *testing.T) *httptest.Server {
+	t.Helper()
+	var srv *httptest.Server
+	srv = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/token":
+			user, pass, ok := r.BasicAuth()
+			if !ok || user != "user" || pass != "pass" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			if r.URL.Query().Get("scope") != "repository:myorg/vddk:pull" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			fmt.Fprint(w, `{"token":"secret-token"}`)
+		case strings.HasPrefix(r.URL.Path, "/v2/"):
+			if r.Header.Get("Authorization") != "Bearer secret-token" {
+				w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="test"`, srv.URL))
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			if r.URL.Path != "/v2/myorg/vddk/manifests/8.0.1" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	return srv
+}
+
+func TestVerifyManifest(t *testing.T) {
+	srv := newTestRegistry(t)
+	defer srv.Close()
+	host := strings.TrimPrefix(srv.URL, "https://")
+	auth := &RegistryAuth{Auths: map[string]registryCredential{host: {Username: "user", Password: "pass"}}}
+	ctx := context.Background()
+
+	if err := verifyManifest(ctx, host+"/myorg/vddk:8.0.1", auth, true); err != nil {
+		t.Errorf("verifyManifest() unexpected error: %v", err)
+	}
+	if err := verifyManifest(ctx, host+"/myorg/vddk:9.0.0", auth, true); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("verifyManifest(missing tag) error = %v, want not found", err)
+	}
+	if err := verifyManifest(ctx, host+"/myorg/vddk:8.0.1", nil, true); !errors.Is(err, errVerifyUnauthorized) {
+		t.Errorf("verifyManifest(no credentials) error = %v, want unauthorized", err)
+	}
+}
@@ -2,6 +2,7 @@ package vddk
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -13,6 +14,7 @@ import (
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 
 	"github.com/yaacov/kubectl-mtv/pkg/util/client"
+	"github.com/yaacov/kubectl-mtv/pkg/util/progress"
 )
 
 // detectContainerRuntime checks for available container runtime (podman or docker).
@@ -69,8 +71,17 @@ RUN mkdir -p /opt
 ENTRYPOINT ["cp", "-r", "/vmware-vix-disklib-distrib", "/opt"]
 `
 
-// BuildImage builds (and optionally pushes) a VDDK image for MTV.
-func BuildImage(tarGzPath, tag, buildDir, runtimePreference, platform, dockerfilePath string, verbosity int, push, pushInsecureSkipTLS bool) error {
+// BuildImage builds (and optionally pushes) a VDDK image for MTV. Pushes use the
+// credentials of pushAuth when set, and are verified by reading the image manifest
+// back from the registry.
+func BuildImage(tarGzPath, tag, buildDir, runtimePreference, platform, dockerfilePath string, verbosity int, push, pushInsecureSkipTLS bool, pushAuth *RegistryAuth) error {
+	tracker := progress.Start("build vddk image", tag)
+	err := buildImage(tracker, tarGzPath, tag, buildDir, runtimePreference, platform, dockerfilePath, verbosity, push, pushInsecureSkipTLS, pushAuth)
+	tracker.Done(err)
+	return err
+}
+
+func buildImage(tracker *progress.Tracker, tarGzPath, tag, buildDir, runtimePreference, platform, dockerfilePath string, verbosity int, push, pushInsecureSkipTLS bool, pushAuth *RegistryAuth) error {
 	// Select container runtime based on preference
 	runtime, err := selectContainerRuntime(runtimePreference)
 	if err != nil {
@@ -91,6 +102,7 @@ func BuildImage(tarGzPath, tag, buildDir, runtimePreference, platform, dockerfil
 
 	// Unpack tar.gz
 	fmt.Println("Extracting VDDK tar.gz...")
+	tracker.Stage("extract", "extracting VDDK tar.gz", 0)
 	if err := extractTarGz(tarGzPath, buildDir, verbosity); err != nil {
 		return fmt.Errorf("failed to extract tar.gz: %w", err)
 	}
@@ -139,6 +151,7 @@ func BuildImage(tarGzPath, tag, buildDir, runtimePreference, platform, dockerfil
 
 	// Build image
 	fmt.Printf("Building image with %s...\n", runtime)
+	tracker.Stage("build", "building image with "+runtime, 0)
 	// Construct build command with platform
 	buildArgs := []string{"build"}
 	if platform != "" {
@@ -163,9 +176,14 @@ func BuildImage(tarGzPath, tag, buildDir, runtimePreference, platform, dockerfil
 	// Optionally push
 	if push {
 		fmt.Printf("Pushing image with %s...\n", runtime)
+		tracker.Stage("push", "pushing image "+tag, 0)
 
-		// Construct push command with optional TLS skip
-		pushArgs := []string{"push"}
+		// Construct push command with optional credentials and TLS skip
+		pushArgs, cleanup, err := pushCommandArgs(runtime, pushAuth)
+		if err != nil {
+			return err
+		}
+		defer cleanup()
 		if pushInsecureSkipTLS {
 			if runtime == "podman" {
 				pushArgs = append(pushArgs, "--tls-verify=false")
@@ -190,12 +208,54 @@ func BuildImage(tarGzPath, tag, buildDir, runtimePreference, platform, dockerfil
 		if err := pushCmd.Run(); err != nil {
 			return fmt.Errorf("%s push failed: %w", runtime, err)
 		}
+
+		// Read the manifest back, a push can report success without the image being served
+		fmt.Printf("Verifying image %s in the registry...\n", tag)
+		tracker.Stage("verify", "verifying image "+tag, 0)
+		verifyAuth := pushAuth
+		if verifyAuth == nil {
+			verifyAuth = loadLocalAuth()
+		}
+		if err := verifyManifest(context.Background(), tag, verifyAuth, pushInsecureSkipTLS); err != nil {
+			if !errors.Is(err, errVerifyUnauthorized) {
+				return fmt.Errorf("pushed image could not be verified: %w", err)
+			}
+			fmt.Printf("Warning: could not verify the pushed image: %v. Use --push-secret to give kubectl-mtv the registry credentials.\n", err)
+		} else {
+			fmt.Println("Image manifest found in the registry.")
+		}
 	}
 
 	fmt.Println("VDDK image build complete.")
 	return nil
 }
 
+// pushCommandArgs returns the push arguments of the runtime, with the credentials of
+// pushAuth written to a temporary auth file, and a cleanup removing that file
+func pushCommandArgs(runtime string, pushAuth *RegistryAuth) ([]string, func(), error) {
+	if pushAuth == nil {
+		return []string{"push"}, func() {}, nil
+	}
+
+	dir, err := os.MkdirTemp("", "vddk-auth-*")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create auth dir: %w", err)
+	}
+	cleanup := func() { os.RemoveAll(dir) }
+	authFile, err := pushAuth.writeAuthFile(dir)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+
+	fmt.Printf("Pushing with the credentials of %s\n", pushAuth.Source)
+	if runtime == "docker" {
+		// docker reads config.json from the directory given to its global --config flag
+		return []string{"--config", dir, "push"}, cleanup, nil
+	}
+	return []string{"push", "--authfile", authFile}, cleanup, nil
+}
+
 func extractTarGz(tarGzPath, destDir string, verbosity int) error {
 	// Ensure destination directory exists
 	if err := os.MkdirAll(destDir, 0755); err != nil {
@@ -0,0 +1,65 @@
+package vddk
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/yaacov/kubectl-mtv/pkg/util/client"
+	"github.com/yaacov/kubectl-mtv/pkg/util/wait"
+)
+
+// WaitControllerReconciled blocks until the ForkliftController finished a reconcile that started
+// after since, i.e. the operator rolled out the VDDK image set at that time. It fails when the
+// operator keeps reporting a failed reconcile or the timeout expires.
+func WaitControllerReconciled(ctx context.Context, configFlags *genericclioptions.ConfigFlags, since time.Time, opts wait.Options) error {
+	operatorNamespace := client.GetMTVOperatorNamespace(ctx, configFlags)
+
+	dynamicClient, err := client.GetDynamicClient(configFlags)
+	if err != nil {
+		return fmt.Errorf("failed to get Kubernetes client: %w", err)
+	}
+	ri := dynamicClient.Resource(client.ForkliftControllersGVR).Namespace(operatorNamespace)
+
+	controllerList, err := ri.List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list ForkliftController resources: %w", err)
+	}
+	if len(controllerList.Items) == 0 {
+		return fmt.Errorf("no ForkliftController found in namespace %s", operatorNamespace)
+	}
+	controllerName := controllerList.Items[0].GetName()
+
+	fmt.Fprintf(opts.Out, "Waiting for ForkliftController '%s' to apply the VDDK image...\n", controllerName)
+	if _, err := wait.For(ctx, ri, "ForkliftController", controllerName, "reconcile", ReconciledCheck(since), opts); err != nil {
+		return err
+	}
+	fmt.Fprintf(opts.Out, "ForkliftController '%s' applied the VDDK image.\n", controllerName)
+	return nil
+}
+
+// ReconciledCheck is done when the operator reports a successful reconcile that started after
+// since. The Running condition of the operator changes to reason Successful at the end of each
+// reconcile; its problems are the message of a true Failure condition.
+func ReconciledCheck(since time.Time) wait.Check {
+	// Condition times have a resolution of one second
+	since = since.Truncate(time.Second)
+
+	return func(obj *unstructured.Unstructured) (bool, []string) {
+		if failure := wait.Condition(obj, "Failure"); failure != nil && failure["status"] == "True" {
+			return false, []string{fmt.Sprintf("Failure: %v", failure["message"])}
+		}
+
+		running := wait.Condition(obj, "Running")
+		if running == nil || running["status"] != "True" || running["reason"] != "Successful" {
+			return false, nil
+		}
+		transition, _ := running["lastTransitionTime"].(string)
+		t, err := time.Parse(time.RFC3339, transition)
+		return err == nil && !t.Before(since), nil
+	}
+}
@@ -0,0 +1,47 @@
+package vddk
+
+import (
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func testController(conditions ...interface{}) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"status": map[string]interface{}{"conditions": conditions},
+	}}
+}
+
+func TestReconciledCheck(t *testing.T) {
+	since := time.Date(2026, 3, 1, 10, 0, 0, 500, time.UTC)
+	running := func(reason, transition string) interface{} {
+		return map[string]interface{}{"type": "Running", "status": "True", "reason": reason, "lastTransitionTime": transition}
+	}
+
+	tests := []struct {
+		name       string
+		controller *unstructured.Unstructured
+		done       bool
+		problems   int
+	}{
+		{"earlier reconcile", testController(running("Successful", "2026-03-01T09:59:59Z")), false, 0},
+		{"reconcile running", testController(running("Running", "2026-03-01T10:00:02Z")), false, 0},
+		{"reconciled in the same second", testController(running("Successful", "2026-03-01T10:00:00Z")), true, 0},
+		{"reconciled later", testController(running("Successful", "2026-03-01T10:01:00Z")), true, 0},
+		{"failed", testController(
+			running("Running", "2026-03-01T10:00:02Z"),
+			map[string]interface{}{"type": "Failure", "status": "True", "message": "task failed"},
+		), false, 1},
+	}
+
+	check := ReconciledCheck(since)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			done, problems := check(tt.controller)
+			if done != tt.done || len(problems) != tt.problems {
+				t.Errorf("check() = %v, %v, want done %v with %d problems", done, problems, tt.done, tt.problems)
+			}
+		})
+	}
+}
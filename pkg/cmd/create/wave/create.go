@@ -0,0 +1,54 @@
+package wave
+
+import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/klog/v2"
+
+	"github.com/yaacov/kubectl-mtv/pkg/util/client"
+	"github.com/yaacov/kubectl-mtv/pkg/util/output"
+	wavepkg "github.com/yaacov/kubectl-mtv/pkg/util/wave"
+)
+
+// CreateWaveOptions encapsulates the parameters for creating a migration wave.
+type CreateWaveOptions struct {
+	Wave         wavepkg.Wave
+	ConfigFlags  *genericclioptions.ConfigFlags
+	DryRun       bool
+	OutputFormat string
+}
+
+// Create creates a new migration wave.
+func Create(ctx context.Context, opts CreateWaveOptions) error {
+	w := opts.Wave
+	if err := w.Validate(); err != nil {
+		return fmt.Errorf("invalid wave specification: %v", err)
+	}
+
+	if opts.DryRun {
+		return output.OutputResource(w.ToConfigMap().Object, opts.OutputFormat)
+	}
+
+	c, err := client.GetDynamicClient(opts.ConfigFlags)
+	if err != nil {
+		return fmt.Errorf("failed to get client: %v", err)
+	}
+
+	// Every plan in the wave must exist in the wave namespace
+	for _, planName := range w.Plans {
+		if _, err := c.Resource(client.PlansGVR).Namespace(w.Namespace).Get(ctx, planName, metav1.GetOptions{}); err != nil {
+			return fmt.Errorf("failed to get plan '%s': %v", planName, err)
+		}
+	}
+
+	if err := wavepkg.Create(ctx, c, &w); err != nil {
+		return err
+	}
+
+	fmt.Printf("wave/%s created%s\n", w.Name, client.DryRunSuffix())
+	klog.V(2).Infof("Created wave '%s' with %d plan(s) in namespace '%s'", w.Name, len(w.Plans), w.Namespace)
+	return nil
+}
@@ -0,0 +1,123 @@
+package plan
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	planstatus "github.com/yaacov/kubectl-mtv/pkg/cmd/get/plan/status"
+	"github.com/yaacov/kubectl-mtv/pkg/util/client"
+	"github.com/yaacov/kubectl-mtv/pkg/util/wait"
+)
+
+// PrecopyTrigger is the condition of a conditional cutover, met when every VM still being
+// migrated meets one of the set limits. The Migration status does not record the size of
+// the precopy deltas, so their size is measured by how long the last precopy took.
+type PrecopyTrigger struct {
+	// Under is met when the last finished precopy took less than this
+	Under time.Duration
+	// After is met when this many precopies finished successfully
+	After int
+}
+
+// Check tells whether the trigger is met by a Migration, and describes the precopies
+func (t PrecopyTrigger) Check(migration *unstructured.Unstructured) (bool, string) {
+	vms, _, _ := unstructured.NestedSlice(migration.Object, "status", "vms")
+
+	pending, met := 0, 0
+	longest := time.Duration(0)
+	fewest := -1
+	for _, v := range vms {
+		vm, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if completed, _, _ := unstructured.NestedString(vm, "completed"); completed != "" {
+			continue
+		}
+		stats, warm := planstatus.GetPrecopyStats(vm)
+		if !warm {
+			continue
+		}
+
+		pending++
+		if stats.LastCompleted != "" && stats.LastDuration > longest {
+			longest = stats.LastDuration
+		}
+		if fewest < 0 || stats.Successes < fewest {
+			fewest = stats.Successes
+		}
+		if (t.Under > 0 && stats.LastCompleted != "" && stats.LastDuration < t.Under) || (t.After > 0 && stats.Successes >= t.After) {
+			met++
+		}
+	}
+
+	if pending == 0 {
+		return false, "waiting for the first precopy"
+	}
+	status := fmt.Sprintf("%d/%d VMs ready for cutover, longest last precopy %s, fewest precopies %d", met, pending, longest, fewest)
+	return met == pending, status
+}
+
+// WaitForPrecopy watches the running migration of a warm plan until the trigger is met,
+// printing the precopy status as it changes, and then sets the cutover time to now
+func WaitForPrecopy(ctx context.Context, configFlags *genericclioptions.ConfigFlags, planName, namespace string, trigger PrecopyTrigger, timeout time.Duration, out io.Writer) error {
+	c, err := client.GetDynamicClient(configFlags)
+	if err != nil {
+		return fmt.Errorf("failed to get client: %v", err)
+	}
+
+	planObj, err := c.Resource(client.PlansGVR).Namespace(namespace).Get(ctx, planName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get plan '%s': %v", planName, err)
+	}
+	if !planstatus.IsWarmMigration(planObj) {
+		return fmt.Errorf("plan '%s' is not configured for warm migration", planName)
+	}
+	runningMigration, _, err := planstatus.GetRunningMigration(c, namespace, planObj, client.MigrationsGVR)
+	if err != nil {
+		return err
+	}
+	if runningMigration == nil {
+		return fmt.Errorf("no running migration found for plan '%s'", planName)
+	}
+
+	fmt.Fprintf(out, "Waiting for the precopies of plan '%s' to meet %s...\n", planName, trigger)
+	lastStatus := ""
+	check := func(obj *unstructured.Unstructured) (bool, []string) {
+		for _, condType := range []string{"Succeeded", "Failed", "Canceled"} {
+			if wait.ConditionTrue(obj, condType) {
+				return false, []string{fmt.Sprintf("migration %s before the cutover condition was met", condType)}
+			}
+		}
+		met, status := trigger.Check(obj)
+		if status != lastStatus {
+			fmt.Fprintf(out, "  %s %s\n", time.Now().Format("15:04:05"), status)
+			lastStatus = status
+		}
+		return met, nil
+	}
+
+	ri := c.Resource(client.MigrationsGVR).Namespace(namespace)
+	if _, err := wait.For(ctx, ri, "migration", runningMigration.GetName(), "meet the cutover condition", check, wait.Options{Timeout: timeout, Out: out}); err != nil {
+		return err
+	}
+	return Cutover(configFlags, planName, namespace, nil)
+}
+
+// String describes the limits of the trigger
+func (t PrecopyTrigger) String() string {
+	switch {
+	case t.Under > 0 && t.After > 0:
+		return fmt.Sprintf("a precopy under %s or %d precopies", t.Under, t.After)
+	case t.Under > 0:
+		return fmt.Sprintf("a precopy under %s", t.Under)
+	default:
+		return fmt.Sprintf("%d precopies", t.After)
+	}
+}
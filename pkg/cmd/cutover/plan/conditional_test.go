@@ -0,0 +1,61 @@
+package plan
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// warmVM returns a migration status VM with finished precopies of the given minutes
+func warmVM(name string, minutes ...int) map[string]interface{} {
+	start := time.Date(2026, 10, 16, 8, 0, 0, 0, time.UTC)
+	precopies := []interface{}{}
+	for _, m := range minutes {
+		precopies = append(precopies, map[string]interface{}{
+			"start": start.Format(time.RFC3339),
+			"end":   start.Add(time.Duration(m) * time.Minute).Format(time.RFC3339),
+		})
+		start = start.Add(time.Hour)
+	}
+	return map[string]interface{}{
+		"name": name,
+		"warm": map[string]interface{}{"successes": int64(len(minutes)), "precopies": precopies},
+	}
+}
+
+func TestPrecopyTriggerCheck(t *testing.T) {
+	migration := func(vms ...interface{}) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{"status": map[string]interface{}{"vms": vms}}}
+	}
+	done := warmVM("done", 60)
+	done["completed"] = "2026-10-16T12:00:00Z"
+
+	tests := []struct {
+		name       string
+		trigger    PrecopyTrigger
+		migration  *unstructured.Unstructured
+		want       bool
+		wantStatus string
+	}{
+		{"no precopies yet", PrecopyTrigger{Under: 5 * time.Minute}, migration(), false, "waiting for the first precopy"},
+		{"all under", PrecopyTrigger{Under: 5 * time.Minute}, migration(warmVM("web", 30, 4), warmVM("db", 20, 3)), true, "2/2 VMs"},
+		{"one over", PrecopyTrigger{Under: 5 * time.Minute}, migration(warmVM("web", 30, 4), warmVM("db", 20, 12)), false, "longest last precopy 12m0s"},
+		{"completed VMs are skipped", PrecopyTrigger{Under: 5 * time.Minute}, migration(warmVM("web", 4), done), true, "1/1 VMs"},
+		{"after precopies", PrecopyTrigger{After: 3}, migration(warmVM("web", 30, 20, 10), warmVM("db", 30, 20, 10)), true, "fewest precopies 3"},
+		{"either limit", PrecopyTrigger{Under: 5 * time.Minute, After: 3}, migration(warmVM("web", 30, 4), warmVM("db", 30, 20, 10)), true, "2/2 VMs"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, status := tt.trigger.Check(tt.migration)
+			if got != tt.want || !strings.Contains(status, tt.wantStatus) {
+				t.Errorf("Check() = %v, %q, want %v and %q", got, status, tt.want, tt.wantStatus)
+			}
+		})
+	}
+
+	if s := (PrecopyTrigger{Under: 5 * time.Minute, After: 6}).String(); s != "a precopy under 5m0s or 6 precopies" {
+		t.Errorf("String() = %q", s)
+	}
+}
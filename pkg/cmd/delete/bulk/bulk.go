@@ -0,0 +1,183 @@
+// Package bulk deletes several resources at once: it shows what will be removed, asks for
+// confirmation, then deletes the resources concurrently and reports the result of each.
+package bulk
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/yaacov/kubectl-mtv/pkg/util/output"
+	"github.com/yaacov/kubectl-mtv/pkg/util/progress"
+	"github.com/yaacov/kubectl-mtv/pkg/util/prompt"
+)
+
+// DefaultConcurrency is the number of resources deleted at the same time
+const DefaultConcurrency = 4
+
+// Results of a deleted resource
+const (
+	ResultDeleted = "Deleted"
+	ResultFailed  = "Failed"
+	ResultSkipped = "Skipped"
+)
+
+// Options configures a bulk delete
+type Options struct {
+	// Kind and Kinds name the resources in messages, e.g. "plan" and "plans"
+	Kind  string
+	Kinds string
+	// Namespace is shown in the summary
+	Namespace string
+	// Columns are the summary columns; items are keyed by the column keys, "name" included
+	Columns []output.Column
+	// Yes skips the confirmation
+	Yes bool
+	// Interactive tells whether the user can be asked on In
+	Interactive bool
+	In          io.Reader
+	// Out receives the summary, the question and the results
+	Out io.Writer
+	// Concurrency is the number of resources deleted at the same time
+	Concurrency int
+}
+
+// Result is the outcome of deleting one resource
+type Result struct {
+	Name   string
+	Result string
+	Error  string
+	err    error
+}
+
+// Confirm prints the resources that will be deleted and asks the user to confirm. Without a
+// terminal the deletion is refused unless Yes is set, so scripts must opt in explicitly.
+func Confirm(items []map[string]interface{}, opts Options) error {
+	kinds := opts.Kinds
+	if len(items) == 1 {
+		kinds = opts.Kind
+	}
+	title := fmt.Sprintf("The following %d %s in namespace '%s' will be deleted:", len(items), kinds, opts.Namespace)
+	if err := prompt.Table(opts.Prompt(), title, opts.Columns, items); err != nil {
+		return err
+	}
+	return prompt.Confirm(opts.Prompt(), fmt.Sprintf("Delete these %d %s?", len(items), kinds), fmt.Sprintf("delete %d %s", len(items), kinds))
+}
+
+// Prompt returns the confirmation settings of the options
+func (o Options) Prompt() prompt.Options {
+	return prompt.Options{Yes: o.Yes, Interactive: o.Interactive, In: o.In, Out: o.Out}
+}
+
+// NameItems returns summary items with only the names, for resources summarized by name
+func NameItems(names []string) []map[string]interface{} {
+	items := make([]map[string]interface{}, 0, len(names))
+	for _, name := range names {
+		items = append(items, map[string]interface{}{"name": name})
+	}
+	return items
+}
+
+// NameColumns are the summary columns of NameItems
+var NameColumns = []output.Column{{Title: "NAME", Key: "name"}}
+
+// Run deletes the resources concurrently with del, prints the result of each one and
+// returns an error when any of them could not be deleted
+func Run(ctx context.Context, names []string, del func(ctx context.Context, name string) error, opts Options) error {
+	results := run(ctx, names, del, opts)
+
+	failed := 0
+	items := make([]map[string]interface{}, 0, len(results))
+	for _, r := range results {
+		if r.Result != ResultDeleted {
+			failed++
+		}
+		items = append(items, map[string]interface{}{"name": r.Name, "result": r.Result, "error": r.Error})
+	}
+
+	if len(names) > 1 {
+		fmt.Fprintln(opts.Out)
+		err := output.NewTablePrinter().WithWriter(opts.Out).WithColumns(
+			output.Column{Title: "NAME", Key: "name"},
+			output.Column{Title: "RESULT", Key: "result", ColorFunc: colorizeResult},
+			output.Column{Title: "ERROR", Key: "error"},
+		).AddItems(items).Print()
+		if err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case failed == 0:
+		return nil
+	case len(names) == 1:
+		return results[0].err
+	default:
+		return fmt.Errorf("%d of %d %s were not deleted", failed, len(names), opts.Kinds)
+	}
+}
+
+// run deletes the resources with a pool of workers, keeping the results in the order of names
+func run(ctx context.Context, names []string, del func(ctx context.Context, name string) error, opts Options) []Result {
+	workers := opts.Concurrency
+	if workers < 1 {
+		workers = DefaultConcurrency
+	}
+	if workers > len(names) {
+		workers = len(names)
+	}
+
+	tracker := progress.Start("delete "+opts.Kind, "")
+	tracker.Stage("delete", "deleting "+opts.Kinds, len(names))
+
+	results := make([]Result, len(names))
+	indexes := make(chan int)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				result := Result{Name: names[i], Result: ResultDeleted}
+				if err := ctx.Err(); err != nil {
+					result.Result, result.Error, result.err = ResultSkipped, err.Error(), err
+				} else if err := del(ctx, names[i]); err != nil {
+					result.Result, result.Error, result.err = ResultFailed, err.Error(), err
+				}
+				results[i] = result
+
+				mu.Lock()
+				tracker.Step(names[i])
+				mu.Unlock()
+			}
+		}()
+	}
+	for i := range names {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	var err error
+	for _, r := range results {
+		if r.Result != ResultDeleted {
+			err = fmt.Errorf("%s '%s': %s", opts.Kind, r.Name, r.Error)
+			break
+		}
+	}
+	tracker.Done(err)
+	return results
+}
+
+func colorizeResult(value string) string {
+	switch value {
+	case ResultDeleted:
+		return output.Green(value)
+	case ResultFailed:
+		return output.Red(value)
+	default:
+		return output.Yellow(value)
+	}
+}
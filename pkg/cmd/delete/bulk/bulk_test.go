@@ -0,0 +1,98 @@
+package bulk
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/yaacov/kubectl-mtv/pkg/util/output"
+)
+
+func testOptions(out *bytes.Buffer) Options {
+	return Options{
+		Kind:        "plan",
+		Kinds:       "plans",
+		Namespace:   "demo",
+		Columns:     []output.Column{{Title: "NAME", Key: "name"}, {Title: "VMS", Key: "vms"}},
+		Out:         out,
+		Concurrency: 2,
+	}
+}
+
+func TestConfirm(t *testing.T) {
+	items := []map[string]interface{}{{"name": "wave-1", "vms": 10}, {"name": "wave-2", "vms": 3}}
+	tests := []struct {
+		name        string
+		yes         bool
+		interactive bool
+		answer      string
+		wantErr     string
+	}{
+		{"yes flag", true, false, "", ""},
+		{"no terminal", false, false, "", "run again with --yes"},
+		{"confirmed", false, true, "y\n", ""},
+		{"declined", false, true, "\n", "canceled"},
+	}
+	for _, tt := range tests {
+		var out bytes.Buffer
+		opts := testOptions(&out)
+		opts.Yes, opts.Interactive, opts.In = tt.yes, tt.interactive, strings.NewReader(tt.answer)
+
+		err := Confirm(items, opts)
+		if tt.wantErr == "" && err != nil || tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+			t.Errorf("%s: Confirm() error = %v, want %q", tt.name, err, tt.wantErr)
+		}
+		if !strings.Contains(out.String(), "wave-1") || !strings.Contains(out.String(), "2 plans in namespace 'demo'") {
+			t.Errorf("%s: summary missing the plans:\n%s", tt.name, out.String())
+		}
+	}
+}
+
+func TestRunConcurrentWithFailures(t *testing.T) {
+	var out bytes.Buffer
+	var running, maxRunning int32
+	del := func(ctx context.Context, name string) error {
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			m := atomic.LoadInt32(&maxRunning)
+			if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		if name == "b" {
+			return errors.New("plan is running")
+		}
+		return nil
+	}
+
+	err := Run(context.Background(), []string{"a", "b", "c", "d"}, del, testOptions(&out))
+	if err == nil || err.Error() != "1 of 4 plans were not deleted" {
+		t.Errorf("Run() error = %v, want 1 of 4 plans not deleted", err)
+	}
+	if maxRunning != 2 {
+		t.Errorf("deleted %d plans at the same time, want the concurrency of 2", maxRunning)
+	}
+	for _, want := range []string{"Deleted", "Failed", "plan is running"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("results missing %q:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestRunSingleKeepsError(t *testing.T) {
+	var out bytes.Buffer
+	want := errors.New("not found")
+	err := Run(context.Background(), []string{"a"}, func(context.Context, string) error { return want }, testOptions(&out))
+	if !errors.Is(err, want) {
+		t.Errorf("Run() error = %v, want the delete error itself", err)
+	}
+	if out.Len() != 0 {
+		t.Errorf("a single delete printed a results table:\n%s", out.String())
+	}
+}
@@ -22,6 +22,6 @@ func Delete(configFlags *genericclioptions.ConfigFlags, name, namespace string)
 		return fmt.Errorf("failed to delete host: %v", err)
 	}
 
-	fmt.Printf("Host '%s' deleted from namespace '%s'\n", name, namespace)
+	fmt.Printf("Host '%s' deleted from namespace '%s'%s\n", name, namespace, client.DryRunSuffix())
 	return nil
 }
@@ -45,7 +45,7 @@ func deleteMapping(configFlags *genericclioptions.ConfigFlags, name, namespace s
 		return fmt.Errorf("failed to delete %s mapping: %v", mappingType, err)
 	}
 
-	fmt.Printf("%s mapping '%s' deleted from namespace '%s'\n",
-		fmt.Sprintf("%s%s", string(mappingType[0]-32), mappingType[1:]), name, namespace)
+	fmt.Printf("%s mapping '%s' deleted from namespace '%s'%s\n",
+		fmt.Sprintf("%s%s", string(mappingType[0]-32), mappingType[1:]), name, namespace, client.DryRunSuffix())
 	return nil
 }
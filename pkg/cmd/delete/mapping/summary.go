@@ -0,0 +1,67 @@
+package mapping
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/yaacov/kubectl-mtv/pkg/util/client"
+	"github.com/yaacov/kubectl-mtv/pkg/util/output"
+)
+
+// SummaryColumns are the columns of the mappings shown before a bulk delete
+var SummaryColumns = []output.Column{
+	{Title: "NAME", Key: "name"},
+	{Title: "ENTRIES", Key: "entries"},
+	{Title: "PLANS", Key: "plans"},
+	{Title: "USED BY", Key: "usedBy"},
+}
+
+// Summarize returns a summary row for each network or storage mapping with its number of
+// entries and the plans that still reference it
+func Summarize(ctx context.Context, c dynamic.Interface, namespace, mappingType string, names []string) ([]map[string]interface{}, error) {
+	gvr := client.NetworkMapGVR
+	if mappingType == "storage" {
+		gvr = client.StorageMapGVR
+	}
+	mappings, err := c.Resource(gvr).Namespace(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s mappings: %v", mappingType, err)
+	}
+	byName := make(map[string]*unstructured.Unstructured, len(mappings.Items))
+	for i := range mappings.Items {
+		byName[mappings.Items[i].GetName()] = &mappings.Items[i]
+	}
+
+	usedBy := map[string][]string{}
+	if plans, err := c.Resource(client.PlansGVR).Namespace(namespace).List(ctx, metav1.ListOptions{}); err == nil {
+		for _, p := range plans.Items {
+			if name, _, _ := unstructured.NestedString(p.Object, "spec", "map", mappingType, "name"); name != "" {
+				usedBy[name] = append(usedBy[name], p.GetName())
+			}
+		}
+	}
+
+	rows := make([]map[string]interface{}, 0, len(names))
+	for _, name := range names {
+		mapping, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("%s mapping '%s' not found in namespace '%s'", mappingType, name, namespace)
+		}
+		entries, _, _ := unstructured.NestedSlice(mapping.Object, "spec", "map")
+		plans := usedBy[name]
+		sort.Strings(plans)
+		rows = append(rows, map[string]interface{}{
+			"name":    name,
+			"entries": len(entries),
+			"plans":   len(plans),
+			"usedBy":  strings.Join(plans, ","),
+		})
+	}
+	return rows, nil
+}
@@ -0,0 +1,39 @@
+package mapping
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/yaacov/kubectl-mtv/pkg/internal/testutil"
+	"github.com/yaacov/kubectl-mtv/pkg/util/client"
+)
+
+func TestSummarize(t *testing.T) {
+	usedBy := func(plan, mapping string) *unstructured.Unstructured {
+		return testutil.Plan(plan, "demo", testutil.WithField(map[string]interface{}{"name": mapping}, "spec", "map", "network"))
+	}
+	c := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		client.PlansGVR:      "PlanList",
+		client.NetworkMapGVR: "NetworkMapList",
+	},
+		testutil.NetworkMap("shared", "demo", testutil.WithField([]interface{}{map[string]interface{}{}, map[string]interface{}{}}, "spec", "map")),
+		testutil.NetworkMap("unused", "demo"),
+		usedBy("wave-2", "shared"), usedBy("wave-1", "shared"),
+	)
+
+	rows, err := Summarize(context.Background(), c, "demo", "network", []string{"shared", "unused"})
+	if err != nil {
+		t.Fatalf("Summarize() unexpected error: %v", err)
+	}
+	if rows[0]["entries"] != 2 || rows[0]["plans"] != 2 || rows[0]["usedBy"] != "wave-1,wave-2" {
+		t.Errorf("shared summary = %v, want 2 entries used by wave-1,wave-2", rows[0])
+	}
+	if rows[1]["plans"] != 0 || rows[1]["usedBy"] != "" {
+		t.Errorf("unused summary = %v, want no plans", rows[1])
+	}
+}
@@ -34,7 +34,7 @@ func Delete(ctx context.Context, configFlags *genericclioptions.ConfigFlags, nam
 			return fmt.Errorf("failed to patch plan: %v", err)
 		}
 
-		fmt.Printf("Plan '%s' patched with deleteVmOnFailMigration=true\n", name)
+		fmt.Printf("Plan '%s' patched with deleteVmOnFailMigration=true%s\n", name, client.DryRunSuffix())
 	}
 
 	// Archive the plan if not skipped
@@ -47,13 +47,14 @@ func Delete(ctx context.Context, configFlags *genericclioptions.ConfigFlags, nam
 			return fmt.Errorf("failed to archive plan: %v", err)
 		}
 
-		// Wait for the Archived condition to be true
-		fmt.Printf("Waiting for plan '%s' to be archived...\n", name)
-		err = waitForArchivedCondition(ctx, c, name, namespace, 60)
-		if err != nil {
-			return err
+		// Wait for the Archived condition to be true; a dry-run archive is never reconciled
+		if !client.DryRunEnabled() {
+			fmt.Printf("Waiting for plan '%s' to be archived...\n", name)
+			err = waitForArchivedCondition(ctx, c, name, namespace, 60)
+			if err != nil {
+				return err
+			}
 		}
-
 	}
 
 	// Delete the plan
@@ -62,7 +63,7 @@ func Delete(ctx context.Context, configFlags *genericclioptions.ConfigFlags, nam
 		return fmt.Errorf("failed to delete plan: %v", err)
 	}
 
-	fmt.Printf("Plan '%s' deleted from namespace '%s'\n", name, namespace)
+	fmt.Printf("Plan '%s' deleted from namespace '%s'%s\n", name, namespace, client.DryRunSuffix())
 	return nil
 }
 
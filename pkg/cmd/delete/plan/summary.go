@@ -0,0 +1,78 @@
+package plan
+
+import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	planstatus "github.com/yaacov/kubectl-mtv/pkg/cmd/get/plan/status"
+	"github.com/yaacov/kubectl-mtv/pkg/util/client"
+	"github.com/yaacov/kubectl-mtv/pkg/util/output"
+)
+
+// SummaryColumns are the columns of the plans shown before a bulk delete
+var SummaryColumns = []output.Column{
+	{Title: "NAME", Key: "name"},
+	{Title: "STATUS", Key: "status", ColorFunc: output.ColorizeStatus},
+	{Title: "VMS", Key: "vms"},
+	{Title: "MIGRATIONS", Key: "migrations"},
+	{Title: "OWNED MAPPINGS", Key: "ownedMappings"},
+}
+
+// Summarize returns a summary row for each plan with the resources that depend on it: its
+// VMs, its migrations, and the mappings it owns, which are removed together with the plan
+func Summarize(ctx context.Context, c dynamic.Interface, namespace string, names []string) ([]map[string]interface{}, error) {
+	plans, err := c.Resource(client.PlansGVR).Namespace(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list plans: %v", err)
+	}
+	byName := make(map[string]*unstructured.Unstructured, len(plans.Items))
+	for i := range plans.Items {
+		byName[plans.Items[i].GetName()] = &plans.Items[i]
+	}
+
+	migrations := map[string]int{}
+	if list, err := c.Resource(client.MigrationsGVR).Namespace(namespace).List(ctx, metav1.ListOptions{}); err == nil {
+		for _, m := range list.Items {
+			planName, _, _ := unstructured.NestedString(m.Object, "spec", "plan", "name")
+			migrations[planName]++
+		}
+	}
+
+	owned := map[string]int{}
+	for _, gvr := range []schema.GroupVersionResource{client.NetworkMapGVR, client.StorageMapGVR} {
+		list, err := c.Resource(gvr).Namespace(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			continue
+		}
+		for _, m := range list.Items {
+			for _, ref := range m.GetOwnerReferences() {
+				if ref.Kind == "Plan" {
+					owned[string(ref.UID)]++
+				}
+			}
+		}
+	}
+
+	rows := make([]map[string]interface{}, 0, len(names))
+	for _, name := range names {
+		plan, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("plan '%s' not found in namespace '%s'", name, namespace)
+		}
+		status, _ := planstatus.GetPlanStatus(plan)
+		vms, _, _ := unstructured.NestedSlice(plan.Object, "spec", "vms")
+		rows = append(rows, map[string]interface{}{
+			"name":          name,
+			"status":        status,
+			"vms":           len(vms),
+			"migrations":    migrations[name],
+			"ownedMappings": owned[string(plan.GetUID())],
+		})
+	}
+	return rows, nil
+}
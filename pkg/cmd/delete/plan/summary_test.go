@@ -0,0 +1,51 @@
+package plan
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/yaacov/kubectl-mtv/pkg/internal/testutil"
+	"github.com/yaacov/kubectl-mtv/pkg/util/client"
+)
+
+func TestSummarize(t *testing.T) {
+	wave1 := testutil.Plan("wave-1", "demo", testutil.WithUID("uid-1"), testutil.WithVMs(testutil.VM("", "web"), testutil.VM("", "db")))
+	wave2 := testutil.Plan("wave-2", "demo")
+	netMap := testutil.NetworkMap("wave-1-net", "demo")
+	netMap.SetOwnerReferences([]metav1.OwnerReference{{Kind: "Plan", Name: "wave-1", UID: "uid-1"}})
+	migration := func(name string) *unstructured.Unstructured {
+		return testutil.Migration(name, "demo", testutil.WithPlanRef("wave-1", "demo"))
+	}
+
+	c := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		client.PlansGVR:      "PlanList",
+		client.MigrationsGVR: "MigrationList",
+		client.NetworkMapGVR: "NetworkMapList",
+		client.StorageMapGVR: "StorageMapList",
+	}, wave1, wave2, netMap, migration("wave-1-a"), migration("wave-1-b"))
+
+	rows, err := Summarize(context.Background(), c, "demo", []string{"wave-1", "wave-2"})
+	if err != nil {
+		t.Fatalf("Summarize() unexpected error: %v", err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("Summarize() = %d rows, want 2", len(rows))
+	}
+	if rows[0]["vms"] != 2 || rows[0]["migrations"] != 2 || rows[0]["ownedMappings"] != 1 {
+		t.Errorf("wave-1 summary = %v, want 2 VMs, 2 migrations, 1 owned mapping", rows[0])
+	}
+	if rows[1]["vms"] != 0 || rows[1]["migrations"] != 0 || rows[1]["ownedMappings"] != 0 {
+		t.Errorf("wave-2 summary = %v, want no dependents", rows[1])
+	}
+
+	if _, err := Summarize(context.Background(), c, "demo", []string{"wave-9"}); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("Summarize(missing plan) error = %v, want not found", err)
+	}
+}
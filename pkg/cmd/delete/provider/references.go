@@ -0,0 +1,192 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/klog/v2"
+
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/delete/bulk"
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/delete/host"
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/delete/mapping"
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/delete/plan"
+	"github.com/yaacov/kubectl-mtv/pkg/util/client"
+	"github.com/yaacov/kubectl-mtv/pkg/util/output"
+	"github.com/yaacov/kubectl-mtv/pkg/util/prompt"
+)
+
+// Reference is a resource that refers to a provider
+type Reference struct {
+	Kind      string
+	Namespace string
+	Name      string
+}
+
+// String returns the reference as kind/namespace/name
+func (r Reference) String() string {
+	return strings.ToLower(r.Kind) + "/" + r.Namespace + "/" + r.Name
+}
+
+// ReferenceColumns are the columns of the references shown before deleting a provider
+var ReferenceColumns = []output.Column{
+	{Title: "KIND", Key: "kind"},
+	{Title: "NAMESPACE", Key: "namespace"},
+	{Title: "NAME", Key: "name"},
+}
+
+// referenceKinds are the resources that refer to providers, in deletion order: plans
+// before the mappings they use. Paths are the provider references of each resource.
+var referenceKinds = []struct {
+	kind  string
+	gvr   schema.GroupVersionResource
+	paths [][]string
+}{
+	{"Plan", client.PlansGVR, [][]string{{"spec", "provider", "source"}, {"spec", "provider", "destination"}}},
+	{"NetworkMap", client.NetworkMapGVR, [][]string{{"spec", "provider", "source"}, {"spec", "provider", "destination"}}},
+	{"StorageMap", client.StorageMapGVR, [][]string{{"spec", "provider", "source"}, {"spec", "provider", "destination"}}},
+	{"Host", client.HostsGVR, [][]string{{"spec", "provider"}}},
+}
+
+// DeleteOptions configures deleting a provider that other resources refer to
+type DeleteOptions struct {
+	// CleanupReferences deletes the plans, mappings and hosts that refer to the provider first
+	CleanupReferences bool
+	// Force deletes the provider even when it is referenced, leaving broken references, or
+	// when some references could not be cleaned up
+	Force bool
+	// Confirm holds the confirmation settings (Yes, Interactive, In and Out)
+	Confirm bulk.Options
+}
+
+// FindReferences returns the plans, mappings and hosts in any namespace the user can list
+// that refer to the provider
+func FindReferences(ctx context.Context, c dynamic.Interface, name, namespace string) ([]Reference, error) {
+	var refs []Reference
+	for _, rk := range referenceKinds {
+		list, err := client.ListAllNamespaces(ctx, c, rk.gvr, metav1.ListOptions{})
+		if err != nil {
+			klog.V(2).Infof("Cannot list %s in all namespaces, listing namespace %s: %v", rk.gvr.Resource, namespace, err)
+			list, err = c.Resource(rk.gvr).Namespace(namespace).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, fmt.Errorf("failed to list %s: %v", rk.gvr.Resource, err)
+			}
+		}
+
+		for _, item := range list.Items {
+			if refersTo(&item, name, namespace, rk.paths) {
+				refs = append(refs, Reference{Kind: rk.kind, Namespace: item.GetNamespace(), Name: item.GetName()})
+			}
+		}
+	}
+	return refs, nil
+}
+
+// refersTo tells whether one of the provider references of obj names the provider; a
+// reference without a namespace is in the namespace of obj
+func refersTo(obj *unstructured.Unstructured, name, namespace string, paths [][]string) bool {
+	for _, path := range paths {
+		refName, _, _ := unstructured.NestedString(obj.Object, append(path, "name")...)
+		refNamespace, _, _ := unstructured.NestedString(obj.Object, append(path, "namespace")...)
+		if refNamespace == "" {
+			refNamespace = obj.GetNamespace()
+		}
+		if refName == name && refNamespace == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// DeleteWithReferences deletes a provider after checking which resources refer to it. A
+// referenced provider is only deleted with CleanupReferences, which deletes the references
+// first, or Force, and after confirmation.
+func DeleteWithReferences(ctx context.Context, configFlags *genericclioptions.ConfigFlags, name, namespace string, opts DeleteOptions) error {
+	c, err := client.GetDynamicClient(configFlags)
+	if err != nil {
+		return fmt.Errorf("failed to get client: %v", err)
+	}
+
+	refs, err := FindReferences(ctx, c, name, namespace)
+	if err != nil {
+		return fmt.Errorf("failed to find resources referencing provider '%s': %v", name, err)
+	}
+	if len(refs) == 0 {
+		return Delete(configFlags, name, namespace)
+	}
+
+	if !opts.CleanupReferences && !opts.Force {
+		return fmt.Errorf("provider '%s' is referenced by %s: delete them first, add --cleanup-plans to delete them with the provider, or --force to leave them with a broken reference",
+			name, describeReferences(refs))
+	}
+
+	question := fmt.Sprintf("Delete provider '%s' and leave these %d resources with a broken reference?", name, len(refs))
+	if opts.CleanupReferences {
+		question = fmt.Sprintf("Delete provider '%s' and these %d resources?", name, len(refs))
+	}
+	if err := confirmReferences(name, refs, question, opts.Confirm); err != nil {
+		return err
+	}
+
+	if opts.CleanupReferences {
+		for _, ref := range refs {
+			if err := deleteReference(ctx, configFlags, ref); err != nil {
+				if !opts.Force {
+					return fmt.Errorf("provider '%s' not deleted: %v", name, err)
+				}
+				fmt.Fprintf(opts.Confirm.Out, "Warning: %v\n", err)
+			}
+		}
+	}
+
+	return Delete(configFlags, name, namespace)
+}
+
+// describeReferences lists references as "N resources (kind/namespace/name, ...)"
+func describeReferences(refs []Reference) string {
+	names := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		names = append(names, ref.String())
+	}
+	noun := "resources"
+	if len(refs) == 1 {
+		noun = "resource"
+	}
+	return fmt.Sprintf("%d %s (%s)", len(refs), noun, strings.Join(names, ", "))
+}
+
+// confirmReferences prints the references of a provider and asks the question. Without a
+// terminal it is refused unless Yes is set, so scripts must opt in explicitly.
+func confirmReferences(name string, refs []Reference, question string, opts bulk.Options) error {
+	items := make([]map[string]interface{}, 0, len(refs))
+	for _, ref := range refs {
+		items = append(items, map[string]interface{}{"kind": ref.Kind, "namespace": ref.Namespace, "name": ref.Name})
+	}
+
+	confirm := opts.Prompt()
+	if err := prompt.Table(confirm, fmt.Sprintf("Provider '%s' is referenced by:", name), ReferenceColumns, items); err != nil {
+		return err
+	}
+	return prompt.Confirm(confirm, question, fmt.Sprintf("delete referenced provider '%s'", name))
+}
+
+// deleteReference deletes a resource that refers to a provider; plans are archived first
+func deleteReference(ctx context.Context, configFlags *genericclioptions.ConfigFlags, ref Reference) error {
+	switch ref.Kind {
+	case "Plan":
+		return plan.Delete(ctx, configFlags, ref.Name, ref.Namespace, false, false)
+	case "NetworkMap":
+		return mapping.Delete(configFlags, ref.Name, ref.Namespace, "network")
+	case "StorageMap":
+		return mapping.Delete(configFlags, ref.Name, ref.Namespace, "storage")
+	case "Host":
+		return host.Delete(configFlags, ref.Name, ref.Namespace)
+	default:
+		return fmt.Errorf("cannot delete %s", ref)
+	}
+}
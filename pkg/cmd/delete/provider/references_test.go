@@ -0,0 +1,111 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/delete/bulk"
+	"github.com/yaacov/kubectl-mtv/pkg/util/client"
+)
+
+func referencing(kind, namespace, name string, spec map[string]interface{}) runtime.Object {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "forklift.konveyor.io/v1beta1",
+		"kind":       kind,
+		"metadata":   map[string]interface{}{"name": name, "namespace": namespace},
+		"spec":       spec,
+	}}
+}
+
+func providerPair(source, sourceNamespace, destination string) map[string]interface{} {
+	src := map[string]interface{}{"name": source}
+	if sourceNamespace != "" {
+		src["namespace"] = sourceNamespace
+	}
+	return map[string]interface{}{"provider": map[string]interface{}{
+		"source":      src,
+		"destination": map[string]interface{}{"name": destination},
+	}}
+}
+
+func TestFindReferences(t *testing.T) {
+	c := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		client.PlansGVR:      "PlanList",
+		client.NetworkMapGVR: "NetworkMapList",
+		client.StorageMapGVR: "StorageMapList",
+		client.HostsGVR:      "HostList",
+	},
+		referencing("Plan", "mtv", "uses-source", providerPair("vsphere", "", "host")),
+		referencing("Plan", "mtv", "other", providerPair("ovirt", "", "host")),
+		referencing("Plan", "team", "cross-namespace", providerPair("vsphere", "mtv", "host")),
+		referencing("Plan", "team", "same-name", providerPair("vsphere", "", "host")),
+		referencing("NetworkMap", "mtv", "net", providerPair("vsphere", "", "host")),
+		referencing("StorageMap", "mtv", "storage", providerPair("ovirt", "", "host")),
+		referencing("Host", "mtv", "esxi", map[string]interface{}{"provider": map[string]interface{}{"name": "vsphere", "namespace": "mtv"}}),
+	)
+
+	refs, err := FindReferences(context.Background(), c, "vsphere", "mtv")
+	if err != nil {
+		t.Fatalf("FindReferences() error = %v", err)
+	}
+
+	var got []string
+	for _, ref := range refs {
+		got = append(got, ref.String())
+	}
+	want := "plan/mtv/uses-source,plan/team/cross-namespace,networkmap/mtv/net,host/mtv/esxi"
+	if strings.Join(got, ",") != want {
+		t.Errorf("FindReferences() = %s, want %s", strings.Join(got, ","), want)
+	}
+
+	refs, err = FindReferences(context.Background(), c, "host", "mtv")
+	if err != nil {
+		t.Fatalf("FindReferences() error = %v", err)
+	}
+	if len(refs) != 4 {
+		t.Errorf("FindReferences(host) = %v, want the 4 plans and mappings in mtv using it as destination", refs)
+	}
+}
+
+func TestConfirmReferences(t *testing.T) {
+	refs := []Reference{{Kind: "Plan", Namespace: "mtv", Name: "migrate-web"}}
+
+	tests := []struct {
+		name        string
+		opts        bulk.Options
+		wantErr     string
+		wantPrompts bool
+	}{
+		{name: "yes skips the question", opts: bulk.Options{Yes: true}},
+		{name: "no terminal requires yes", opts: bulk.Options{}, wantErr: "--yes"},
+		{name: "answer yes", opts: bulk.Options{Interactive: true, In: strings.NewReader("y\n")}, wantPrompts: true},
+		{name: "answer no", opts: bulk.Options{Interactive: true, In: strings.NewReader("\n")}, wantErr: "canceled", wantPrompts: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			tt.opts.Out = &out
+			err := confirmReferences("vsphere", refs, "Delete?", tt.opts)
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("confirmReferences() error = %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("confirmReferences() error = %v, want %q", err, tt.wantErr)
+			}
+			if !strings.Contains(out.String(), "migrate-web") {
+				t.Errorf("output %q does not list the references", out.String())
+			}
+			if strings.Contains(out.String(), "[y/N]") != tt.wantPrompts {
+				t.Errorf("output %q, prompted = %v", out.String(), !tt.wantPrompts)
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package plan
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/get/plan/status"
+	"github.com/yaacov/kubectl-mtv/pkg/util/client"
+	"github.com/yaacov/kubectl-mtv/pkg/util/describe"
+	"github.com/yaacov/kubectl-mtv/pkg/util/output"
+	"github.com/yaacov/kubectl-mtv/pkg/util/wait"
+	"github.com/yaacov/kubectl-mtv/pkg/util/watch"
+)
+
+// SelectOptions selects the plans described together and how they are shown
+type SelectOptions struct {
+	// Name describes a single plan; Selector describes every plan matching the label selector
+	Name     string
+	Selector string
+	// Namespace is empty for all namespaces
+	Namespace string
+	// Brief shows one condensed section per plan instead of the full descriptions
+	Brief   bool
+	WithVMs bool
+}
+
+// DescribeSelected describes several plans at once, either in full one after another or,
+// with Brief, as a single summary with one section of key fields per plan
+func DescribeSelected(configFlags *genericclioptions.ConfigFlags, opts SelectOptions, useUTC bool, outputFormat string, watchMode bool) error {
+	return watch.WrapWithWatch(watchMode, outputFormat, func() error {
+		c, err := client.GetDynamicClient(configFlags)
+		if err != nil {
+			return fmt.Errorf("failed to get client: %v", err)
+		}
+
+		plans, err := selectPlans(context.TODO(), c, opts)
+		if err != nil {
+			return err
+		}
+		if len(plans) == 0 {
+			fmt.Printf("No plans found matching selector '%s'\n", opts.Selector)
+			return nil
+		}
+
+		if opts.Brief {
+			return describe.Print(BuildBriefDescription(c, plans, opts.Selector, useUTC), outputFormat)
+		}
+
+		descs := make([]*describe.Description, 0, len(plans))
+		for _, p := range plans {
+			desc, err := BuildDescription(configFlags, p.GetName(), p.GetNamespace(), opts.WithVMs, false, 0, 0, useUTC, "", false)
+			if err != nil {
+				return err
+			}
+			descs = append(descs, desc)
+		}
+		return describe.PrintAll(descs, outputFormat)
+	}, watch.DefaultInterval)
+}
+
+// selectPlans returns the named plan or the plans matching the selector, ordered by namespace and name
+func selectPlans(ctx context.Context, c dynamic.Interface, opts SelectOptions) ([]unstructured.Unstructured, error) {
+	if opts.Name != "" {
+		p, err := c.Resource(client.PlansGVR).Namespace(opts.Namespace).Get(ctx, opts.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get plan: %v", err)
+		}
+		return []unstructured.Unstructured{*p}, nil
+	}
+
+	list, err := c.Resource(client.PlansGVR).Namespace(opts.Namespace).List(ctx, metav1.ListOptions{LabelSelector: opts.Selector})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list plans: %v", err)
+	}
+	plans := list.Items
+	sort.SliceStable(plans, func(i, j int) bool {
+		if plans[i].GetNamespace() != plans[j].GetNamespace() {
+			return plans[i].GetNamespace() < plans[j].GetNamespace()
+		}
+		return plans[i].GetName() < plans[j].GetName()
+	})
+	return plans, nil
+}
+
+// BuildBriefDescription builds a condensed description of several plans: one section per
+// plan with its status, providers, target, mappings, VM counts and blocking conditions
+func BuildBriefDescription(c dynamic.Interface, plans []unstructured.Unstructured, selector string, useUTC bool) *describe.Description {
+	b := describe.NewBuilder("MIGRATION PLANS")
+	if selector != "" {
+		b.Field("Selector", selector)
+	}
+	b.Field("Plans", fmt.Sprintf("%d", len(plans)))
+
+	for i := range plans {
+		buildBriefSection(b, c, &plans[i], useUTC)
+	}
+	return b.Build()
+}
+
+func buildBriefSection(b *describe.Builder, c dynamic.Interface, plan *unstructured.Unstructured, useUTC bool) {
+	// A failed migrations lookup only leaves the migration fields empty
+	details, _ := status.GetPlanDetails(c, plan.GetNamespace(), plan, client.MigrationsGVR)
+
+	source, _, _ := unstructured.NestedString(plan.Object, "spec", "provider", "source", "name")
+	target, _, _ := unstructured.NestedString(plan.Object, "spec", "provider", "destination", "name")
+	targetNamespace, _, _ := unstructured.NestedString(plan.Object, "spec", "targetNamespace")
+	networkMapping, _, _ := unstructured.NestedString(plan.Object, "spec", "map", "network", "name")
+	storageMapping, _, _ := unstructured.NestedString(plan.Object, "spec", "map", "storage", "name")
+	vms, _, _ := unstructured.NestedSlice(plan.Object, "spec", "vms")
+
+	b.Section(plan.GetName())
+	b.Field("Namespace", plan.GetNamespace())
+	if labels := formatLabels(plan.GetLabels()); labels != "" {
+		b.Field("Labels", labels)
+	}
+	b.FieldC("Status", details.Status, output.ColorizeStatus)
+	b.FieldC("Ready", fmt.Sprintf("%t", details.IsReady), output.ColorizeBooleanString)
+	b.Field("Providers", fmt.Sprintf("%s -> %s", stringOrDefault(source, "-"), stringOrDefault(target, "-")))
+	b.Field("Target Namespace", stringOrDefault(targetNamespace, "-"))
+	b.Field("Migration Type", status.GetMigrationType(plan))
+	b.Field("Mappings", fmt.Sprintf("network: %s, storage: %s", stringOrDefault(networkMapping, "-"), stringOrDefault(storageMapping, "-")))
+
+	vmCount := fmt.Sprintf("%d", len(vms))
+	if stats := details.VMStats; stats.Total > 0 {
+		vmCount = fmt.Sprintf("%d (succeeded %d, failed %d, canceled %d)", len(vms), stats.Succeeded, stats.Failed, stats.Canceled)
+	}
+	b.Field("VMs", vmCount)
+
+	if migration := details.LatestMigration; migration != nil {
+		b.Field("Last Migration", output.FormatTimestamp(migration.GetCreationTimestamp().Time, useUTC))
+	}
+	for _, problem := range wait.BlockingConditions(plan) {
+		b.FieldC("Problem", problem, output.Red)
+	}
+}
+
+// formatLabels returns the labels as sorted key=value pairs
+func formatLabels(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for k, v := range labels {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
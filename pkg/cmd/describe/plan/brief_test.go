@@ -0,0 +1,79 @@
+package plan
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/yaacov/kubectl-mtv/pkg/util/client"
+	"github.com/yaacov/kubectl-mtv/pkg/util/describe"
+)
+
+func testPlan(name, wave string, conditions ...interface{}) *unstructured.Unstructured {
+	p := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "forklift.konveyor.io/v1beta1",
+		"kind":       "Plan",
+		"spec": map[string]interface{}{
+			"targetNamespace": "apps",
+			"provider": map[string]interface{}{
+				"source":      map[string]interface{}{"name": "vsphere"},
+				"destination": map[string]interface{}{"name": "host"},
+			},
+			"map": map[string]interface{}{
+				"network": map[string]interface{}{"name": name + "-net"},
+				"storage": map[string]interface{}{"name": name + "-storage"},
+			},
+			"vms": []interface{}{map[string]interface{}{"name": "web"}, map[string]interface{}{"name": "db"}},
+		},
+		"status": map[string]interface{}{"conditions": conditions},
+	}}
+	p.SetName(name)
+	p.SetNamespace("demo")
+	p.SetLabels(map[string]string{"wave": wave})
+	return p
+}
+
+func TestBriefDescription(t *testing.T) {
+	c := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		client.PlansGVR:      "PlanList",
+		client.MigrationsGVR: "MigrationList",
+	},
+		testPlan("wave7-b", "7", map[string]interface{}{"type": "Ready", "status": "True", "category": "Required"}),
+		testPlan("wave7-a", "7", map[string]interface{}{"type": "VMNotFound", "status": "True", "category": "Critical", "message": "VM db not found"}),
+		testPlan("wave8", "8"),
+	)
+
+	plans, err := selectPlans(context.Background(), c, SelectOptions{Selector: "wave=7", Namespace: "demo"})
+	if err != nil {
+		t.Fatalf("selectPlans() unexpected error: %v", err)
+	}
+	if len(plans) != 2 || plans[0].GetName() != "wave7-a" || plans[1].GetName() != "wave7-b" {
+		t.Fatalf("selectPlans() = %d plans, want wave7-a and wave7-b in order", len(plans))
+	}
+
+	out, err := describe.Format(BuildBriefDescription(c, plans, "wave=7", false), "markdown")
+	if err != nil {
+		t.Fatalf("Format() unexpected error: %v", err)
+	}
+	for _, want := range []string{
+		"- **Plans:** 2",
+		"## wave7-a",
+		"## wave7-b",
+		"- **Providers:** vsphere -> host",
+		"- **Mappings:** network: wave7-a-net, storage: wave7-a-storage",
+		"- **VMs:** 2",
+		"- **Problem:** VMNotFound: VM db not found",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("brief description missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "wave8") {
+		t.Errorf("brief description includes a plan outside the selector:\n%s", out)
+	}
+}
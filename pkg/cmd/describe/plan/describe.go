@@ -9,29 +9,61 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
-	"k8s.io/client-go/dynamic"
 
 	"github.com/yaacov/kubectl-mtv/pkg/cmd/describe/plan/diagnostics"
 	planutil "github.com/yaacov/kubectl-mtv/pkg/cmd/get/plan"
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/get/plan/guestconversion"
 	"github.com/yaacov/kubectl-mtv/pkg/cmd/get/plan/status"
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/get/plan/targetlabels"
 	"github.com/yaacov/kubectl-mtv/pkg/util/client"
+	"github.com/yaacov/kubectl-mtv/pkg/util/conditions"
 	"github.com/yaacov/kubectl-mtv/pkg/util/describe"
 	"github.com/yaacov/kubectl-mtv/pkg/util/output"
+	"github.com/yaacov/kubectl-mtv/pkg/util/watch"
 )
 
-// Describe describes a migration plan.
-func Describe(configFlags *genericclioptions.ConfigFlags, name, namespace string, withVMs bool, withDiagnostics bool, logLines, showLines int, useUTC bool, outputFormat string) error {
+// Describe describes a migration plan. In watch mode the description is refreshed, and with a
+// history store each refresh adds condition changes to the condition history.
+func Describe(configFlags *genericclioptions.ConfigFlags, name, namespace string, withVMs bool, withDiagnostics bool, logLines, showLines int, useUTC bool, outputFormat string, watchMode bool, historyStore string, withTimeline bool) error {
+	return watch.WrapWithWatch(watchMode, outputFormat, func() error {
+		desc, err := BuildDescription(configFlags, name, namespace, withVMs, withDiagnostics, logLines, showLines, useUTC, historyStore, withTimeline)
+		if err != nil {
+			return err
+		}
+
+		return describe.Print(desc, outputFormat)
+	}, watch.DefaultInterval)
+}
+
+// BuildDescription builds the description of a migration plan without printing it.
+// A non-empty historyStore (conditions.StoreLocal or conditions.StoreAnnotation) records the
+// plan conditions and adds the condition history. withTimeline adds the conditions history
+// timeline built from the conditions, the latest migration and events, with VM phase durations.
+func BuildDescription(configFlags *genericclioptions.ConfigFlags, name, namespace string, withVMs bool, withDiagnostics bool, logLines, showLines int, useUTC bool, historyStore string, withTimeline bool) (*describe.Description, error) {
 	c, err := client.GetDynamicClient(configFlags)
 	if err != nil {
-		return fmt.Errorf("failed to get client: %v", err)
+		return nil, fmt.Errorf("failed to get client: %v", err)
+	}
+
+	var history conditions.Store
+	if historyStore != "" {
+		if history, err = conditions.NewStore(historyStore, c, client.PlansGVR); err != nil {
+			return nil, err
+		}
 	}
 
 	plan, err := c.Resource(client.PlansGVR).Namespace(namespace).Get(context.TODO(), name, metav1.GetOptions{})
 	if err != nil {
-		return fmt.Errorf("failed to get plan: %v", err)
+		return nil, fmt.Errorf("failed to get plan: %v", err)
 	}
 
-	planDetails, _ := status.GetPlanDetails(c, namespace, plan, client.MigrationsGVR)
+	data := fetchPlanData(context.Background(), configFlags, c, plan, fetchOptions{
+		withDiagnostics: withDiagnostics,
+		logLines:        logLines,
+		showLines:       showLines,
+		withTimeline:    withTimeline,
+	})
+	planDetails := data.details
 
 	b := describe.NewBuilder("MIGRATION PLAN")
 
@@ -66,11 +98,28 @@ func Describe(configFlags *genericclioptions.ConfigFlags, name, namespace string
 	}
 
 	// Mapping details
-	buildMappingDetailsSectionImpl(b, c, namespace, "NETWORK MAPPING DETAILS", networkMapping, true)
-	buildMappingDetailsSectionImpl(b, c, namespace, "STORAGE MAPPING DETAILS", storageMapping, false)
+	buildMappingDetailsSection(b, "NETWORK MAPPING DETAILS", data.networkMap)
+	buildMappingDetailsSection(b, "STORAGE MAPPING DETAILS", data.storageMap)
 
 	// Conditions
 	buildConditionsSection(b, plan)
+	if history != nil {
+		conditions.AddHistorySection(context.TODO(), b, history, plan, useUTC)
+	}
+	if withTimeline {
+		migration := planDetails.RunningMigration
+		if migration == nil {
+			migration = planDetails.LatestMigration
+		}
+		now := time.Now()
+		buildTimelineSection(b, BuildTimeline(plan, migration, data.events, now), data.eventsErr, useUTC, now)
+	}
+
+	// Enforced target VM labels
+	buildTargetLabelsSection(b, data)
+
+	// Skipped guest conversion
+	buildGuestConversionSection(b, plan)
 
 	// VMs
 	if withVMs {
@@ -83,28 +132,15 @@ func Describe(configFlags *genericclioptions.ConfigFlags, name, namespace string
 
 	// Diagnostics
 	if withDiagnostics {
-		migration := planDetails.RunningMigration
-		if migration == nil {
-			migration = planDetails.LatestMigration
-		}
-		targetNS, _, _ := unstructured.NestedString(plan.Object, "spec", "targetNamespace")
-		if targetNS == "" {
-			targetNS = namespace
-		}
-
-		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
-		defer cancel()
-
-		report, err := diagnostics.GatherDiagnostics(ctx, configFlags, c, plan, migration, targetNS, logLines, showLines)
-		if err != nil {
+		if data.diagnosticsErr != nil {
 			b.Section("DIAGNOSTICS")
-			b.FieldC("Error", err.Error(), output.Red)
-		} else if report != nil {
-			diagnostics.Render(b, report)
+			b.FieldC("Error", data.diagnosticsErr.Error(), output.Red)
+		} else if data.diagnostics != nil {
+			diagnostics.Render(b, data.diagnostics)
 		}
 	}
 
-	return describe.Print(b.Build(), outputFormat)
+	return b.Build(), nil
 }
 
 func buildSpecSection(b *describe.Builder, plan *unstructured.Unstructured) {
@@ -117,6 +153,7 @@ func buildSpecSection(b *describe.Builder, plan *unstructured.Unstructured) {
 	preserveStaticIPs, _, _ := unstructured.NestedBool(plan.Object, "spec", "preserveStaticIPs")
 	enableNestedVirt, enableNestedVirtExists, _ := unstructured.NestedBool(plan.Object, "spec", "enableNestedVirtualization")
 	xfsCompatibility, _, _ := unstructured.NestedBool(plan.Object, "spec", "xfsCompatibility")
+	skipGuestConversion, _, _ := unstructured.NestedBool(plan.Object, "spec", "skipGuestConversion")
 
 	migrationType := status.GetMigrationType(plan)
 
@@ -132,6 +169,9 @@ func buildSpecSection(b *describe.Builder, plan *unstructured.Unstructured) {
 	if transferNetwork != "" {
 		b.Field("Transfer Network", transferNetwork)
 	}
+	if skipGuestConversion {
+		b.FieldC("Guest Conversion", "skipped (raw disk copy)", output.Yellow)
+	}
 	b.EndSubSection()
 
 	b.SubSection("Advanced Settings")
@@ -192,14 +232,8 @@ func buildMigrationSection(b *describe.Builder, title string, migration *unstruc
 	}
 }
 
-func buildMappingDetailsSectionImpl(b *describe.Builder, c dynamic.Interface, namespace, title, mappingName string, isNetwork bool) {
-	var gvr = client.StorageMapGVR
-	if isNetwork {
-		gvr = client.NetworkMapGVR
-	}
-
-	m, err := c.Resource(gvr).Namespace(namespace).Get(context.TODO(), mappingName, metav1.GetOptions{})
-	if err != nil {
+func buildMappingDetailsSection(b *describe.Builder, title string, m *unstructured.Unstructured) {
+	if m == nil {
 		return
 	}
 
@@ -267,6 +301,79 @@ func buildConditionsSection(b *describe.Builder, plan *unstructured.Unstructured
 	b.Table(headers, rows)
 }
 
+func buildTargetLabelsSection(b *describe.Builder, data *planData) {
+	if len(data.labels) == 0 {
+		return
+	}
+
+	b.Section("TARGET VM LABELS")
+	b.Field("Expected", targetlabels.Format(data.labels))
+
+	checks, err := data.labelChecks, data.labelsErr
+	if err != nil {
+		b.Field("Verification", fmt.Sprintf("failed: %v", err))
+		return
+	}
+	if len(checks) == 0 {
+		b.Field("Verification", "no migrated VMs yet")
+		return
+	}
+
+	headers := []describe.TableColumn{
+		{Display: "VM", Key: "vm"},
+		{Display: "NAMESPACE", Key: "namespace"},
+		{Display: "STATUS", Key: "status"},
+	}
+	rows := make([]map[string]string, 0, len(checks))
+	for _, check := range checks {
+		vmStatus := "labeled"
+		switch {
+		case !check.Found:
+			vmStatus = "not found"
+		case len(check.Missing) > 0:
+			vmStatus = "missing " + strings.Join(check.Missing, ",")
+		}
+		rows = append(rows, map[string]string{
+			"vm":        check.Name,
+			"namespace": check.Namespace,
+			"status":    vmStatus,
+		})
+	}
+	b.Table(headers, rows)
+}
+
+func buildGuestConversionSection(b *describe.Builder, plan *unstructured.Unstructured) {
+	skip, _, _ := unstructured.NestedBool(plan.Object, "spec", "skipGuestConversion")
+	if !skip {
+		return
+	}
+	compatibilityMode, _, _ := unstructured.NestedBool(plan.Object, "spec", "useCompatibilityMode")
+
+	b.Section("SKIPPED GUEST CONVERSION")
+	b.FieldC("Compatibility Mode", fmt.Sprintf("%t", compatibilityMode), output.ColorizeBooleanString)
+
+	decision, ok := guestconversion.Get(plan)
+	if !ok {
+		b.FieldC("Guest Check", "not checked (guest conversion was not skipped by create plan)", output.Yellow)
+		return
+	}
+	if len(decision.AtRisk) == 0 {
+		b.FieldC("Guest Check", "no Windows or older Linux guests found", output.Green)
+		return
+	}
+
+	b.FieldC("Guest Check", fmt.Sprintf("%d VM(s) need guest conversion", len(decision.AtRisk)), output.Red)
+	b.Field("Impact", guestconversion.Consequence(compatibilityMode))
+	rows := make([]map[string]string, 0, len(decision.AtRisk))
+	for _, name := range decision.AtRiskVMs() {
+		rows = append(rows, map[string]string{"vm": name, "guest": decision.AtRisk[name]})
+	}
+	b.Table([]describe.TableColumn{
+		{Display: "VM", Key: "vm"},
+		{Display: "GUEST", Key: "guest"},
+	}, rows)
+}
+
 func buildVMsSection(b *describe.Builder, plan *unstructured.Unstructured, migration *unstructured.Unstructured, useUTC bool) {
 	specVMs, exists, err := unstructured.NestedSlice(plan.Object, "spec", "vms")
 	if err != nil || !exists || len(specVMs) == 0 {
@@ -410,6 +517,17 @@ func addVMMigrationStatus(b *describe.Builder, vmStatus map[string]interface{},
 		b.Field("Migration Completed", planutil.FormatTime(completed, useUTC))
 	}
 
+	if precopy, ok := status.GetPrecopyStats(vmStatus); ok {
+		b.Field("Precopies", fmt.Sprintf("%d (succeeded %d, failed %d)", precopy.Iterations, precopy.Successes, precopy.Failures))
+		if precopy.LastCompleted != "" {
+			b.Field("Last Precopy", fmt.Sprintf("%s (%s, %s)", planutil.FormatTime(precopy.LastCompleted, useUTC),
+				planutil.FormatPrecopyDuration(precopy.LastDuration), planutil.FormatPrecopyDeltas(precopy.LastDeltas)))
+		}
+		if precopy.NextPrecopyAt != "" {
+			b.Field("Next Precopy", planutil.FormatTime(precopy.NextPrecopyAt, useUTC))
+		}
+	}
+
 	// Summarise pipeline progress as a compact line per phase
 	pipeline, exists, _ := unstructured.NestedSlice(vmStatus, "pipeline")
 	if !exists || len(pipeline) == 0 {
@@ -0,0 +1,126 @@
+package plan
+
+import (
+	"context"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/klog/v2"
+
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/describe/plan/diagnostics"
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/get/plan/status"
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/get/plan/targetlabels"
+	"github.com/yaacov/kubectl-mtv/pkg/util/client"
+)
+
+// diagnosticsTimeout bounds the gathering of diagnostics
+const diagnosticsTimeout = 2 * time.Minute
+
+// planData holds the resources related to a plan that its description shows
+type planData struct {
+	details    status.PlanDetails
+	networkMap *unstructured.Unstructured
+	storageMap *unstructured.Unstructured
+
+	// labels are the enforced target VM labels, verified on the migrated VMs
+	labels      map[string]string
+	labelChecks []targetlabels.VMCheck
+	labelsErr   error
+
+	diagnostics    *diagnostics.DiagnosticsReport
+	diagnosticsErr error
+
+	// events of the plan and its latest migration, for the conditions history
+	events    []corev1.Event
+	eventsErr error
+}
+
+// fetchOptions selects the optional data fetched for a plan description
+type fetchOptions struct {
+	withDiagnostics bool
+	logLines        int
+	showLines       int
+	withTimeline    bool
+}
+
+// fetchPlanData fetches the migrations, mappings, target VM label checks, events and diagnostics
+// of a plan concurrently. The mappings are fetched alongside the migrations; the label checks,
+// events and diagnostics need the latest migration and start as soon as it is known. Every section is
+// optional, a failed fetch leaves its section out or shows the error instead of failing the description.
+func fetchPlanData(ctx context.Context, configFlags *genericclioptions.ConfigFlags, c dynamic.Interface, plan *unstructured.Unstructured, opts fetchOptions) *planData {
+	data := &planData{labels: targetlabels.Enforced(plan)}
+	namespace := plan.GetNamespace()
+
+	g, ctx := errgroup.WithContext(ctx)
+
+	g.Go(func() error {
+		data.details, _ = status.GetPlanDetails(c, namespace, plan, client.MigrationsGVR)
+
+		migration := data.details.RunningMigration
+		if migration == nil {
+			migration = data.details.LatestMigration
+		}
+
+		// Label checks and diagnostics depend on the migration, they run in parallel with each other
+		dg, dctx := errgroup.WithContext(ctx)
+		if len(data.labels) > 0 {
+			dg.Go(func() error {
+				data.labelChecks, data.labelsErr = targetlabels.Verify(dctx, c, plan, data.details.LatestMigration, data.labels, false)
+				return nil
+			})
+		}
+		if opts.withTimeline {
+			dg.Go(func() error {
+				data.events, data.eventsErr = fetchTimelineEvents(dctx, configFlags, plan, migration)
+				return nil
+			})
+		}
+		if opts.withDiagnostics {
+			dg.Go(func() error {
+				targetNS, _, _ := unstructured.NestedString(plan.Object, "spec", "targetNamespace")
+				if targetNS == "" {
+					targetNS = namespace
+				}
+				tctx, cancel := context.WithTimeout(dctx, diagnosticsTimeout)
+				defer cancel()
+				data.diagnostics, data.diagnosticsErr = diagnostics.GatherDiagnostics(tctx, configFlags, c, plan, migration, targetNS, opts.logLines, opts.showLines)
+				return nil
+			})
+		}
+		return dg.Wait()
+	})
+
+	networkMapping, _, _ := unstructured.NestedString(plan.Object, "spec", "map", "network", "name")
+	storageMapping, _, _ := unstructured.NestedString(plan.Object, "spec", "map", "storage", "name")
+	g.Go(func() error {
+		data.networkMap = getMapping(ctx, c, client.NetworkMapGVR, namespace, networkMapping)
+		return nil
+	})
+	g.Go(func() error {
+		data.storageMap = getMapping(ctx, c, client.StorageMapGVR, namespace, storageMapping)
+		return nil
+	})
+
+	// The goroutines record their errors in data, Wait only synchronizes
+	_ = g.Wait()
+	return data
+}
+
+// getMapping returns a mapping of the plan, or nil when it is not set or cannot be read
+func getMapping(ctx context.Context, c dynamic.Interface, gvr schema.GroupVersionResource, namespace, name string) *unstructured.Unstructured {
+	if name == "" {
+		return nil
+	}
+	m, err := c.Resource(gvr).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		klog.V(2).Infof("Failed to get %s '%s': %v", gvr.Resource, name, err)
+		return nil
+	}
+	return m
+}
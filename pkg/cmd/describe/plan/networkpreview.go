@@ -0,0 +1,458 @@
+package plan
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/klog/v2"
+
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/get/inventory"
+	"github.com/yaacov/kubectl-mtv/pkg/util/client"
+	"github.com/yaacov/kubectl-mtv/pkg/util/describe"
+	"github.com/yaacov/kubectl-mtv/pkg/util/output"
+)
+
+// Severities of network preview issues
+const (
+	PreviewCritical = "Critical"
+	PreviewWarning  = "Warning"
+)
+
+// NICPreview is a source NIC of a plan VM and the target network it is mapped to
+type NICPreview struct {
+	VM            string
+	NIC           int
+	MAC           string
+	IPs           []string
+	Static        bool
+	SourceNetwork string
+	Target        string
+}
+
+// PreviewIssue is a problem found by the network preview
+type PreviewIssue struct {
+	VM       string
+	NIC      int
+	Severity string
+	Message  string
+}
+
+// previewInput holds the inventory and mapping data joined by the network preview
+type previewInput struct {
+	// planVMs are the plan VMs, by ID and name
+	planVMs []planVMRef
+	// sourceVMs are the source inventory VMs by ID
+	sourceVMs map[string]map[string]interface{}
+	// networkNames are the source network names by ID
+	networkNames map[string]string
+	// mapEntries are the spec.map entries of the network map
+	mapEntries []interface{}
+	// targetIPs are the IPs in use in the target namespace, by the VM using them
+	targetIPs map[string]string
+	// preserveStaticIPs is the plan setting
+	preserveStaticIPs bool
+}
+
+// planVMRef is a VM reference of the plan spec
+type planVMRef struct {
+	ID   string
+	Name string
+}
+
+// DescribeNetworkPreview prints a per-VM preview of the source NICs of a plan and the target
+// networks they are mapped to, with the conflicts that would break static IP preservation
+func DescribeNetworkPreview(configFlags *genericclioptions.ConfigFlags, name, namespace, inventoryURL string, insecureSkipTLS bool, outputFormat string) error {
+	desc, err := BuildNetworkPreview(context.Background(), configFlags, name, namespace, inventoryURL, insecureSkipTLS)
+	if err != nil {
+		return err
+	}
+	return describe.Print(desc, outputFormat)
+}
+
+// BuildNetworkPreview joins the plan VMs with the source inventory VMs and networks, the
+// network map and the IPs in use in the target namespace
+func BuildNetworkPreview(ctx context.Context, configFlags *genericclioptions.ConfigFlags, name, namespace, inventoryURL string, insecureSkipTLS bool) (*describe.Description, error) {
+	c, err := client.GetDynamicClient(configFlags)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get client: %v", err)
+	}
+	plan, err := c.Resource(client.PlansGVR).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get plan: %v", err)
+	}
+
+	spec := func(fields ...string) string {
+		value, _, _ := unstructured.NestedString(plan.Object, append([]string{"spec"}, fields...)...)
+		return value
+	}
+	withDefault := func(value, fallback string) string {
+		if value == "" {
+			return fallback
+		}
+		return value
+	}
+	sourceName := spec("provider", "source", "name")
+	sourceNamespace := withDefault(spec("provider", "source", "namespace"), plan.GetNamespace())
+	targetName := spec("provider", "destination", "name")
+	targetProviderNamespace := withDefault(spec("provider", "destination", "namespace"), plan.GetNamespace())
+	targetNamespace := withDefault(spec("targetNamespace"), plan.GetNamespace())
+	mapName := spec("map", "network", "name")
+	mapNamespace := withDefault(spec("map", "network", "namespace"), plan.GetNamespace())
+
+	input := previewInput{targetIPs: map[string]string{}}
+	input.preserveStaticIPs, _, _ = unstructured.NestedBool(plan.Object, "spec", "preserveStaticIPs")
+	vms, _, _ := unstructured.NestedSlice(plan.Object, "spec", "vms")
+	for _, v := range vms {
+		if vm, ok := v.(map[string]interface{}); ok {
+			id, _ := vm["id"].(string)
+			vmName, _ := vm["name"].(string)
+			input.planVMs = append(input.planVMs, planVMRef{ID: id, Name: vmName})
+		}
+	}
+
+	// Source inventory: VMs with their NICs and guest networks, and network names
+	source, err := inventory.GetProviderByName(ctx, configFlags, sourceName, sourceNamespace)
+	if err != nil {
+		return nil, err
+	}
+	sourceClient := inventory.NewProviderClientWithInsecure(configFlags, source, inventoryURL, insecureSkipTLS)
+	providerType, err := sourceClient.GetProviderType()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get provider type: %v", err)
+	}
+	if providerType != "vsphere" {
+		return nil, fmt.Errorf("the network preview supports vSphere source providers, provider '%s' is of type %s", sourceName, providerType)
+	}
+	sourceVMs, err := inventoryObjects(ctx, sourceClient, "vms")
+	if err != nil {
+		return nil, err
+	}
+	input.sourceVMs = map[string]map[string]interface{}{}
+	for _, vm := range sourceVMs {
+		if id, _ := vm["id"].(string); id != "" {
+			input.sourceVMs[id] = vm
+		}
+	}
+	networks, err := inventoryObjects(ctx, sourceClient, "networks")
+	if err != nil {
+		return nil, err
+	}
+	input.networkNames = map[string]string{}
+	for _, network := range networks {
+		id, _ := network["id"].(string)
+		networkName, _ := network["name"].(string)
+		input.networkNames[id] = networkName
+	}
+
+	// Network map
+	if mapName != "" {
+		networkMap, err := c.Resource(client.NetworkMapGVR).Namespace(mapNamespace).Get(ctx, mapName, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get network map '%s': %v", mapName, err)
+		}
+		input.mapEntries, _, _ = unstructured.NestedSlice(networkMap.Object, "spec", "map")
+	}
+
+	// IPs of the VMs already running in the target namespace
+	targetChecked := "yes"
+	if target, err := inventory.GetProviderByName(ctx, configFlags, targetName, targetProviderNamespace); err != nil {
+		targetChecked = fmt.Sprintf("no (%v)", err)
+	} else {
+		targetClient := inventory.NewProviderClientWithInsecure(configFlags, target, inventoryURL, insecureSkipTLS)
+		targetVMs, err := inventoryObjects(ctx, targetClient, "vms")
+		if err != nil {
+			klog.V(1).Infof("Failed to read the target VMs: %v", err)
+			targetChecked = fmt.Sprintf("no (%v)", err)
+		}
+		for _, vm := range targetVMs {
+			if ns, _ := vm["namespace"].(string); ns != targetNamespace {
+				continue
+			}
+			vmName, _ := vm["name"].(string)
+			for _, ip := range targetVMIPs(vm) {
+				input.targetIPs[ip] = targetNamespace + "/" + vmName
+			}
+		}
+	}
+
+	nics, issues := previewNetworks(input)
+
+	b := describe.NewBuilder("NETWORK PREVIEW")
+	b.Field("Plan", plan.GetName())
+	b.FieldC("Preserve Static IPs", fmt.Sprintf("%t", input.preserveStaticIPs), output.ColorizeBooleanString)
+	b.Field("Source Provider", sourceName)
+	b.Field("Network Map", valueOrNone(mapName))
+	b.Field("Target Namespace", targetNamespace)
+	b.Field("Target IPs Checked", targetChecked)
+
+	b.Section("NICS")
+	rows := make([]map[string]string, 0, len(nics))
+	for _, nic := range nics {
+		ips := strings.Join(nic.IPs, ", ")
+		if nic.Static {
+			ips += " (static)"
+		}
+		rows = append(rows, map[string]string{
+			"vm":      nic.VM,
+			"nic":     fmt.Sprintf("%d", nic.NIC),
+			"mac":     nic.MAC,
+			"ips":     valueOrNone(ips),
+			"network": nic.SourceNetwork,
+			"target":  nic.Target,
+		})
+	}
+	b.Table([]describe.TableColumn{
+		{Display: "VM", Key: "vm"},
+		{Display: "NIC", Key: "nic"},
+		{Display: "MAC", Key: "mac"},
+		{Display: "IPS", Key: "ips"},
+		{Display: "SOURCE NETWORK", Key: "network"},
+		{Display: "TARGET NETWORK", Key: "target"},
+	}, rows)
+
+	b.Section("ISSUES")
+	if len(issues) == 0 {
+		b.FieldC("Result", "no conflicts found", output.Green)
+	} else {
+		issueRows := make([]map[string]string, 0, len(issues))
+		for _, issue := range issues {
+			issueRows = append(issueRows, map[string]string{
+				"vm":       issue.VM,
+				"nic":      fmt.Sprintf("%d", issue.NIC),
+				"severity": issue.Severity,
+				"message":  issue.Message,
+			})
+		}
+		b.Table([]describe.TableColumn{
+			{Display: "VM", Key: "vm"},
+			{Display: "NIC", Key: "nic"},
+			{Display: "SEVERITY", Key: "severity", ColorFunc: colorizeSeverity},
+			{Display: "ISSUE", Key: "message"},
+		}, issueRows)
+	}
+
+	return b.Build(), nil
+}
+
+// previewNetworks lists the NICs of the plan VMs with their target networks and the issues
+// that would break static IP preservation: unmapped networks, static IPs on the pod network,
+// IPs used twice in the plan or already in use in the target namespace, and NICs without
+// guest IP information.
+func previewNetworks(input previewInput) ([]NICPreview, []PreviewIssue) {
+	nics := []NICPreview{}
+	issues := []PreviewIssue{}
+	ipOwners := map[string]string{}
+
+	for _, ref := range input.planVMs {
+		vm := input.sourceVMs[ref.ID]
+		vmName := ref.Name
+		if vm == nil {
+			for _, candidate := range input.sourceVMs {
+				if candidate["name"] == ref.Name {
+					vm = candidate
+				}
+			}
+		}
+		if vm == nil {
+			issues = append(issues, PreviewIssue{VM: vmName, Severity: PreviewCritical, Message: "VM not found in the source inventory"})
+			continue
+		}
+		if vmName == "" {
+			vmName, _ = vm["name"].(string)
+		}
+
+		guestIPs := guestNetworksByMAC(vm)
+		vmNICs, _ := vm["nics"].([]interface{})
+		for i, n := range vmNICs {
+			nic, ok := n.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			mac, _ := nic["mac"].(string)
+			networkID, _, _ := unstructured.NestedString(nic, "network", "id")
+			preview := NICPreview{
+				VM:            vmName,
+				NIC:           i,
+				MAC:           mac,
+				SourceNetwork: valueOrNone(input.networkNames[networkID]),
+			}
+			if preview.SourceNetwork == "-" && networkID != "" {
+				preview.SourceNetwork = networkID
+			}
+
+			guest := guestIPs[strings.ToLower(mac)]
+			for _, g := range guest {
+				preview.IPs = append(preview.IPs, g.address)
+				preview.Static = preview.Static || g.static
+			}
+
+			target, mapped := mapTarget(input.mapEntries, networkID, input.networkNames[networkID])
+			preview.Target = target
+			nics = append(nics, preview)
+
+			issue := func(severity, message string) {
+				issues = append(issues, PreviewIssue{VM: vmName, NIC: i, Severity: severity, Message: message})
+			}
+			if !mapped {
+				issue(PreviewCritical, fmt.Sprintf("network '%s' is not in the network map", preview.SourceNetwork))
+				continue
+			}
+			if target == "ignored" {
+				continue
+			}
+			if len(guest) == 0 && input.preserveStaticIPs {
+				issue(PreviewWarning, "no guest IP information, is VMware Tools running?")
+			}
+			if !preview.Static {
+				continue
+			}
+			if target == "pod" {
+				issue(PreviewWarning, "static IP on the pod network is preserved only in namespaces with a primary User-Defined Network")
+			}
+			for _, g := range guest {
+				if !g.static {
+					continue
+				}
+				ip := strings.SplitN(g.address, "/", 2)[0]
+				if owner, ok := ipOwners[ip]; ok && owner != vmName {
+					issue(PreviewCritical, fmt.Sprintf("IP %s is also used by plan VM %s", ip, owner))
+				}
+				ipOwners[ip] = vmName
+				if owner, ok := input.targetIPs[ip]; ok {
+					issue(PreviewCritical, fmt.Sprintf("IP %s is in use by target VM %s", ip, owner))
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		return issues[i].Severity == PreviewCritical && issues[j].Severity != PreviewCritical
+	})
+	return nics, issues
+}
+
+// guestAddress is an IP reported by the guest tools
+type guestAddress struct {
+	address string
+	static  bool
+}
+
+// guestNetworksByMAC returns the guest IPs of a vSphere VM by lower-case MAC. IPs with the
+// "manual" origin are static.
+func guestNetworksByMAC(vm map[string]interface{}) map[string][]guestAddress {
+	result := map[string][]guestAddress{}
+	guestNetworks, _ := vm["guestNetworks"].([]interface{})
+	for _, g := range guestNetworks {
+		network, ok := g.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		mac, _ := network["mac"].(string)
+		ip, _ := network["ip"].(string)
+		if ip == "" {
+			continue
+		}
+		if prefix, ok := network["prefix"].(float64); ok && prefix > 0 {
+			ip = fmt.Sprintf("%s/%d", ip, int(prefix))
+		}
+		origin, _ := network["origin"].(string)
+		key := strings.ToLower(mac)
+		result[key] = append(result[key], guestAddress{address: ip, static: strings.EqualFold(origin, "manual")})
+	}
+	return result
+}
+
+// mapTarget returns the target network of a source network in the network map entries, and
+// whether the network is mapped at all
+func mapTarget(entries []interface{}, networkID, networkName string) (string, bool) {
+	for _, e := range entries {
+		entry, ok := e.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		id, _, _ := unstructured.NestedString(entry, "source", "id")
+		name, _, _ := unstructured.NestedString(entry, "source", "name")
+		if (id == "" || id != networkID) && (name == "" || name != networkName) {
+			continue
+		}
+		destType, _, _ := unstructured.NestedString(entry, "destination", "type")
+		destName, _, _ := unstructured.NestedString(entry, "destination", "name")
+		destNamespace, _, _ := unstructured.NestedString(entry, "destination", "namespace")
+		switch destType {
+		case "multus":
+			if destNamespace != "" {
+				destName = destNamespace + "/" + destName
+			}
+			return "multus " + destName, true
+		case "":
+			return "pod", true
+		default:
+			return destType, true
+		}
+	}
+	return "-", false
+}
+
+// targetVMIPs returns the IPs of a KubeVirt VM in the target provider inventory
+func targetVMIPs(vm map[string]interface{}) []string {
+	ips := []string{}
+	for _, prefix := range []string{"object", "instance"} {
+		interfaces, _, _ := unstructured.NestedSlice(vm, prefix, "status", "interfaces")
+		for _, i := range interfaces {
+			iface, ok := i.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if ip, ok := iface["ipAddress"].(string); ok && ip != "" {
+				ips = append(ips, ip)
+			}
+			addresses, _ := iface["ipAddresses"].([]interface{})
+			for _, a := range addresses {
+				if ip, ok := a.(string); ok && ip != "" {
+					ips = append(ips, ip)
+				}
+			}
+		}
+	}
+	return ips
+}
+
+// inventoryObjects returns the objects of an inventory collection
+func inventoryObjects(ctx context.Context, providerClient *inventory.ProviderClient, collection string) ([]map[string]interface{}, error) {
+	data, err := providerClient.GetResourceCollection(ctx, collection, 4)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s from inventory: %v", collection, err)
+	}
+	items, ok := data.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected data format: expected array for %s inventory", collection)
+	}
+	objects := make([]map[string]interface{}, 0, len(items))
+	for _, item := range items {
+		if object, ok := item.(map[string]interface{}); ok {
+			objects = append(objects, object)
+		}
+	}
+	return objects, nil
+}
+
+// colorizeSeverity colors preview issue severities
+func colorizeSeverity(severity string) string {
+	if severity == PreviewCritical {
+		return output.Red(severity)
+	}
+	return output.Yellow(severity)
+}
+
+// valueOrNone returns "-" for empty values
+func valueOrNone(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
@@ -0,0 +1,83 @@
+package plan
+
+import (
+	"strings"
+	"testing"
+)
+
+func previewVM(id, name string, nics []interface{}, guestNetworks []interface{}) map[string]interface{} {
+	return map[string]interface{}{"id": id, "name": name, "nics": nics, "guestNetworks": guestNetworks}
+}
+
+func previewNIC(mac, network string) map[string]interface{} {
+	return map[string]interface{}{"mac": mac, "network": map[string]interface{}{"kind": "Network", "id": network}}
+}
+
+func previewGuest(mac, ip, origin string) map[string]interface{} {
+	return map[string]interface{}{"mac": mac, "ip": ip, "origin": origin, "prefix": float64(24)}
+}
+
+func TestPreviewNetworks(t *testing.T) {
+	input := previewInput{
+		preserveStaticIPs: true,
+		planVMs:           []planVMRef{{ID: "vm-1"}, {ID: "vm-2"}, {ID: "vm-3", Name: "gone"}},
+		sourceVMs: map[string]map[string]interface{}{
+			"vm-1": previewVM("vm-1", "web",
+				[]interface{}{previewNIC("00:50:56:AA:00:01", "net-1"), previewNIC("00:50:56:aa:00:02", "net-2")},
+				[]interface{}{previewGuest("00:50:56:aa:00:01", "10.0.0.5", "manual"), previewGuest("00:50:56:aa:00:02", "192.168.1.5", "dhcp")}),
+			"vm-2": previewVM("vm-2", "db",
+				[]interface{}{previewNIC("00:50:56:aa:00:03", "net-1"), previewNIC("00:50:56:aa:00:04", "net-3"), previewNIC("00:50:56:aa:00:05", "net-4")},
+				[]interface{}{previewGuest("00:50:56:aa:00:03", "10.0.0.5", "manual"), previewGuest("00:50:56:aa:00:04", "10.0.1.9", "manual")}),
+		},
+		networkNames: map[string]string{"net-1": "VM Network", "net-2": "DHCP", "net-3": "Backup", "net-4": "Lab"},
+		mapEntries: []interface{}{
+			map[string]interface{}{
+				"source":      map[string]interface{}{"id": "net-1"},
+				"destination": map[string]interface{}{"type": "multus", "name": "vlan10", "namespace": "prod"},
+			},
+			map[string]interface{}{
+				"source":      map[string]interface{}{"name": "DHCP"},
+				"destination": map[string]interface{}{"type": "pod"},
+			},
+			map[string]interface{}{
+				"source":      map[string]interface{}{"id": "net-3"},
+				"destination": map[string]interface{}{"type": "pod"},
+			},
+		},
+		targetIPs: map[string]string{"10.0.1.9": "prod/legacy"},
+	}
+
+	nics, issues := previewNetworks(input)
+	if len(nics) != 5 {
+		t.Fatalf("previewNetworks() returned %d NICs, want 5", len(nics))
+	}
+	if nics[0].VM != "web" || !nics[0].Static || nics[0].IPs[0] != "10.0.0.5/24" || nics[0].Target != "multus prod/vlan10" || nics[0].SourceNetwork != "VM Network" {
+		t.Errorf("web nic 0 = %+v", nics[0])
+	}
+	if nics[1].Static || nics[1].Target != "pod" {
+		t.Errorf("web nic 1 = %+v", nics[1])
+	}
+
+	messages := []string{}
+	for _, issue := range issues {
+		messages = append(messages, issue.Severity+" "+issue.VM+": "+issue.Message)
+	}
+	got := strings.Join(messages, "\n")
+	for _, want := range []string{
+		"Critical gone: VM not found in the source inventory",
+		"Critical db: IP 10.0.0.5 is also used by plan VM web",
+		"Critical db: IP 10.0.1.9 is in use by target VM prod/legacy",
+		"Critical db: network 'Lab' is not in the network map",
+		"Warning db: static IP on the pod network is preserved only in namespaces with a primary User-Defined Network",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("issues missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "web: static IP") {
+		t.Errorf("DHCP NIC on the pod network reported:\n%s", got)
+	}
+	if issues[len(issues)-1].Severity != PreviewWarning {
+		t.Errorf("critical issues are not listed first:\n%s", got)
+	}
+}
@@ -0,0 +1,349 @@
+package plan
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/yaacov/kubectl-mtv/pkg/util/client"
+	"github.com/yaacov/kubectl-mtv/pkg/util/describe"
+	"github.com/yaacov/kubectl-mtv/pkg/util/output"
+)
+
+// Kinds of timeline entries
+const (
+	TimelinePhase     = "Phase"
+	TimelineCondition = "Condition"
+	TimelineEvent     = "Event"
+)
+
+// planPhases are the conditions that mark the phases of a plan; the other conditions are
+// shown as transitions without a duration
+var planPhases = map[string]bool{
+	"Ready":     true,
+	"Executing": true,
+	"Succeeded": true,
+	"Failed":    true,
+	"Canceled":  true,
+}
+
+// terminalPhases end the life of a plan run; they have no duration
+var terminalPhases = map[string]bool{
+	"Succeeded": true,
+	"Failed":    true,
+	"Canceled":  true,
+}
+
+// TimelineEntry is a phase the plan entered, a condition that became true, or an event
+type TimelineEntry struct {
+	Time    time.Time
+	Kind    string
+	Name    string
+	Message string
+	// Warning marks warning events and error conditions
+	Warning bool
+	// Duration is the time spent in a phase until the next one, or until now when Ongoing
+	Duration time.Duration
+	Ongoing  bool
+}
+
+// StepTiming is the start and end of a VM migration step
+type StepTiming struct {
+	Name      string
+	Phase     string
+	Started   time.Time
+	Completed time.Time
+}
+
+// VMTimeline is the timing of the migration of a VM and of its pipeline steps
+type VMTimeline struct {
+	StepTiming
+	Steps []StepTiming
+}
+
+// Timeline is the history of a plan built from its conditions, its latest migration and
+// the events of both
+type Timeline struct {
+	Entries []TimelineEntry
+	VMs     []VMTimeline
+	// Started and Completed bound the latest migration run
+	Started   time.Time
+	Completed time.Time
+}
+
+// parseTime parses an RFC 3339 timestamp; invalid or empty values are the zero time
+func parseTime(value string) time.Time {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// BuildTimeline builds the timeline of a plan. The latest migration supplies the Executing
+// phase (Forklift removes the Executing condition when the run ends) and the VM step
+// timings; migration may be nil. Phase durations of a plan still running end at now.
+func BuildTimeline(plan, migration *unstructured.Unstructured, events []corev1.Event, now time.Time) Timeline {
+	var tl Timeline
+	phases := map[string]TimelineEntry{
+		"Created": {Time: plan.GetCreationTimestamp().Time, Kind: TimelinePhase, Name: "Created"},
+	}
+
+	conditions, _, _ := unstructured.NestedSlice(plan.Object, "status", "conditions")
+	for _, c := range conditions {
+		condMap, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		condType, _ := condMap["type"].(string)
+		condStatus, _ := condMap["status"].(string)
+		category, _ := condMap["category"].(string)
+		message, _ := condMap["message"].(string)
+		transition, _ := condMap["lastTransitionTime"].(string)
+		at := parseTime(transition)
+		if condStatus != "True" || at.IsZero() {
+			continue
+		}
+
+		entry := TimelineEntry{Time: at, Kind: TimelineCondition, Name: condType, Message: message,
+			Warning: category == "Critical" || category == "Error" || condType == "Failed"}
+		if planPhases[condType] {
+			entry.Kind = TimelinePhase
+			phases[condType] = entry
+			continue
+		}
+		tl.Entries = append(tl.Entries, entry)
+	}
+
+	if migration != nil {
+		tl.Started = parseTime(stringField(migration.Object, "status", "started"))
+		tl.Completed = parseTime(stringField(migration.Object, "status", "completed"))
+		if !tl.Started.IsZero() {
+			phases["Executing"] = TimelineEntry{Time: tl.Started, Kind: TimelinePhase, Name: "Executing",
+				Message: fmt.Sprintf("migration %s started", migration.GetName())}
+		}
+		tl.VMs = buildVMTimelines(migration)
+	}
+
+	// Phases in time order, each lasting until the next one
+	ordered := make([]TimelineEntry, 0, len(phases))
+	for _, p := range phases {
+		if !p.Time.IsZero() {
+			ordered = append(ordered, p)
+		}
+	}
+	sort.SliceStable(ordered, func(i, j int) bool { return ordered[i].Time.Before(ordered[j].Time) })
+	for i := range ordered {
+		switch {
+		case terminalPhases[ordered[i].Name]:
+		case i+1 < len(ordered):
+			ordered[i].Duration = ordered[i+1].Time.Sub(ordered[i].Time)
+		default:
+			ordered[i].Duration = now.Sub(ordered[i].Time)
+			ordered[i].Ongoing = true
+		}
+	}
+	tl.Entries = append(tl.Entries, ordered...)
+
+	for _, ev := range events {
+		at := ev.LastTimestamp.Time
+		if at.IsZero() {
+			at = ev.EventTime.Time
+		}
+		tl.Entries = append(tl.Entries, TimelineEntry{
+			Time:    at,
+			Kind:    TimelineEvent,
+			Name:    ev.Reason,
+			Message: fmt.Sprintf("%s/%s: %s", ev.InvolvedObject.Kind, ev.InvolvedObject.Name, ev.Message),
+			Warning: ev.Type == corev1.EventTypeWarning,
+		})
+	}
+
+	sort.SliceStable(tl.Entries, func(i, j int) bool { return tl.Entries[i].Time.Before(tl.Entries[j].Time) })
+	return tl
+}
+
+// buildVMTimelines returns the timing of each VM of a migration and of its pipeline steps
+func buildVMTimelines(migration *unstructured.Unstructured) []VMTimeline {
+	vms, _, _ := unstructured.NestedSlice(migration.Object, "status", "vms")
+	result := make([]VMTimeline, 0, len(vms))
+	for _, v := range vms {
+		vm, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name := stringField(vm, "name")
+		if name == "" {
+			name = stringField(vm, "id")
+		}
+		vmTimeline := VMTimeline{StepTiming: StepTiming{
+			Name:      name,
+			Phase:     stringField(vm, "phase"),
+			Started:   parseTime(stringField(vm, "started")),
+			Completed: parseTime(stringField(vm, "completed")),
+		}}
+
+		pipeline, _, _ := unstructured.NestedSlice(vm, "pipeline")
+		for _, p := range pipeline {
+			step, ok := p.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			vmTimeline.Steps = append(vmTimeline.Steps, StepTiming{
+				Name:      stringField(step, "name"),
+				Phase:     stringField(step, "phase"),
+				Started:   parseTime(stringField(step, "started")),
+				Completed: parseTime(stringField(step, "completed")),
+			})
+		}
+		result = append(result, vmTimeline)
+	}
+	return result
+}
+
+// stringField returns a nested string field, or "" when it is missing
+func stringField(obj map[string]interface{}, fields ...string) string {
+	value, _, _ := unstructured.NestedString(obj, fields...)
+	return value
+}
+
+// fetchTimelineEvents returns the events of the plan and of its latest migration
+func fetchTimelineEvents(ctx context.Context, configFlags *genericclioptions.ConfigFlags, plan, migration *unstructured.Unstructured) ([]corev1.Event, error) {
+	clientset, err := client.GetKubernetesClientset(configFlags)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get kubernetes client: %v", err)
+	}
+
+	objects := []*unstructured.Unstructured{plan}
+	if migration != nil {
+		objects = append(objects, migration)
+	}
+
+	var events []corev1.Event
+	for _, obj := range objects {
+		list, err := clientset.CoreV1().Events(obj.GetNamespace()).List(ctx, metav1.ListOptions{
+			FieldSelector: fmt.Sprintf("involvedObject.uid=%s", obj.GetUID()),
+		})
+		if err != nil {
+			return events, fmt.Errorf("failed to list events of %s: %v", obj.GetName(), err)
+		}
+		events = append(events, list.Items...)
+	}
+	return events, nil
+}
+
+// formatElapsed formats a duration as 45s, 12m30s or 3h5m
+func formatElapsed(d time.Duration) string {
+	switch {
+	case d < 0:
+		return "-"
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm%ds", int(d.Minutes()), int(d.Seconds())%60)
+	default:
+		return fmt.Sprintf("%dh%dm", int(d.Hours()), int(d.Minutes())%60)
+	}
+}
+
+// stepDuration formats the duration of a step; a started step that has not completed runs until now
+func stepDuration(step StepTiming, now time.Time) string {
+	switch {
+	case step.Started.IsZero():
+		return "-"
+	case step.Completed.IsZero():
+		return formatElapsed(now.Sub(step.Started)) + " (running)"
+	default:
+		return formatElapsed(step.Completed.Sub(step.Started))
+	}
+}
+
+// buildTimelineSection adds the CONDITIONS HISTORY timeline and the VM PHASE DURATIONS to the
+// description
+func buildTimelineSection(b *describe.Builder, tl Timeline, eventsErr error, useUTC bool, now time.Time) {
+	b.Section("CONDITIONS HISTORY")
+	if !tl.Started.IsZero() {
+		run := StepTiming{Started: tl.Started, Completed: tl.Completed}
+		b.Field("Migration Duration", stepDuration(run, now))
+	}
+	if eventsErr != nil {
+		b.FieldC("Events", eventsErr.Error(), output.Yellow)
+	}
+
+	rows := make([]map[string]string, 0, len(tl.Entries))
+	for _, e := range tl.Entries {
+		duration := ""
+		if e.Kind == TimelinePhase && !terminalPhases[e.Name] {
+			duration = formatElapsed(e.Duration)
+			if e.Ongoing {
+				duration += " (ongoing)"
+			}
+		}
+		name := e.Name
+		if e.Warning {
+			name = output.Red(name)
+		} else if e.Kind == TimelinePhase {
+			name = output.ColorizeStatus(name)
+		}
+		rows = append(rows, map[string]string{
+			"time":     output.FormatTimestamp(e.Time, useUTC),
+			"kind":     e.Kind,
+			"name":     name,
+			"duration": duration,
+			"message":  e.Message,
+		})
+	}
+	b.Table([]describe.TableColumn{
+		{Display: "TIME", Key: "time"},
+		{Display: "KIND", Key: "kind"},
+		{Display: "NAME", Key: "name"},
+		{Display: "DURATION", Key: "duration"},
+		{Display: "MESSAGE", Key: "message"},
+	}, rows)
+
+	if len(tl.VMs) == 0 {
+		return
+	}
+
+	b.Section("VM PHASE DURATIONS")
+	vmRows := make([]map[string]string, 0, len(tl.VMs))
+	for _, vm := range tl.VMs {
+		started := "-"
+		if !vm.Started.IsZero() {
+			started = output.FormatTimestamp(vm.Started, useUTC)
+		}
+		vmRows = append(vmRows, map[string]string{
+			"vm":       vm.Name,
+			"step":     "(total)",
+			"phase":    vm.Phase,
+			"started":  started,
+			"duration": stepDuration(vm.StepTiming, now),
+		})
+		for _, step := range vm.Steps {
+			stepStarted := "-"
+			if !step.Started.IsZero() {
+				stepStarted = output.FormatTimestamp(step.Started, useUTC)
+			}
+			vmRows = append(vmRows, map[string]string{
+				"vm":       "",
+				"step":     step.Name,
+				"phase":    step.Phase,
+				"started":  stepStarted,
+				"duration": stepDuration(step, now),
+			})
+		}
+	}
+	b.Table([]describe.TableColumn{
+		{Display: "VM", Key: "vm"},
+		{Display: "STEP", Key: "step"},
+		{Display: "PHASE", Key: "phase", ColorFunc: output.ColorizeStatus},
+		{Display: "STARTED", Key: "started"},
+		{Display: "DURATION", Key: "duration"},
+	}, vmRows)
+}
@@ -0,0 +1,111 @@
+package plan
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var timelineStart = time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+
+func at(minutes int) string {
+	return timelineStart.Add(time.Duration(minutes) * time.Minute).Format(time.RFC3339)
+}
+
+func timelinePlan(conditions ...interface{}) *unstructured.Unstructured {
+	plan := &unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "web", "namespace": "mtv"},
+		"status":   map[string]interface{}{"conditions": conditions},
+	}}
+	plan.SetCreationTimestamp(metav1.NewTime(timelineStart))
+	return plan
+}
+
+func condition(condType, status, category string, minutes int) interface{} {
+	return map[string]interface{}{
+		"type": condType, "status": status, "category": category, "lastTransitionTime": at(minutes),
+	}
+}
+
+func TestBuildTimeline(t *testing.T) {
+	plan := timelinePlan(
+		condition("Ready", "True", "Required", 2),
+		condition("Succeeded", "True", "Advisory", 50),
+		condition("VMWarmPowerStateUnknown", "True", "Critical", 12),
+		condition("Executing", "False", "Advisory", 30),
+	)
+	migration := &unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "web-run"},
+		"status": map[string]interface{}{
+			"started":   at(10),
+			"completed": at(50),
+			"vms": []interface{}{map[string]interface{}{
+				"name": "vm-1", "phase": "Completed", "started": at(10), "completed": at(45),
+				"pipeline": []interface{}{
+					map[string]interface{}{"name": "DiskTransfer", "phase": "Completed", "started": at(11), "completed": at(31)},
+					map[string]interface{}{"name": "ImageConversion", "phase": "Running", "started": at(31)},
+				},
+			}},
+		},
+	}}
+	events := []corev1.Event{{
+		Reason:         "Retry",
+		Type:           corev1.EventTypeWarning,
+		InvolvedObject: corev1.ObjectReference{Kind: "Migration", Name: "web-run"},
+		LastTimestamp:  metav1.NewTime(timelineStart.Add(20 * time.Minute)),
+	}}
+	now := timelineStart.Add(60 * time.Minute)
+
+	tl := BuildTimeline(plan, migration, events, now)
+
+	want := []struct {
+		name     string
+		kind     string
+		duration time.Duration
+		warning  bool
+	}{
+		{"Created", TimelinePhase, 2 * time.Minute, false},
+		{"Ready", TimelinePhase, 8 * time.Minute, false},
+		{"Executing", TimelinePhase, 40 * time.Minute, false},
+		{"VMWarmPowerStateUnknown", TimelineCondition, 0, true},
+		{"Retry", TimelineEvent, 0, true},
+		{"Succeeded", TimelinePhase, 0, false},
+	}
+	if len(tl.Entries) != len(want) {
+		t.Fatalf("Entries = %+v, want %d entries", tl.Entries, len(want))
+	}
+	for i, w := range want {
+		e := tl.Entries[i]
+		if e.Name != w.name || e.Kind != w.kind || e.Duration != w.duration || e.Warning != w.warning || e.Ongoing {
+			t.Errorf("Entries[%d] = %+v, want %s %s lasting %v (warning %v)", i, e, w.kind, w.name, w.duration, w.warning)
+		}
+	}
+
+	if len(tl.VMs) != 1 || len(tl.VMs[0].Steps) != 2 {
+		t.Fatalf("VMs = %+v, want one VM with two steps", tl.VMs)
+	}
+	if got := stepDuration(tl.VMs[0].StepTiming, now); got != "35m0s" {
+		t.Errorf("VM duration = %s, want 35m0s", got)
+	}
+	if got := stepDuration(tl.VMs[0].Steps[1], now); got != "29m0s (running)" {
+		t.Errorf("running step duration = %s, want 29m0s (running)", got)
+	}
+}
+
+func TestBuildTimelineOngoing(t *testing.T) {
+	plan := timelinePlan(condition("Ready", "True", "Required", 5))
+	now := timelineStart.Add(2 * time.Hour)
+
+	tl := BuildTimeline(plan, nil, nil, now)
+
+	last := tl.Entries[len(tl.Entries)-1]
+	if last.Name != "Ready" || !last.Ongoing || last.Duration != 115*time.Minute {
+		t.Errorf("last entry = %+v, want Ready ongoing for 1h55m", last)
+	}
+	if got := formatElapsed(last.Duration); got != "1h55m" {
+		t.Errorf("formatElapsed() = %s, want 1h55m", got)
+	}
+}
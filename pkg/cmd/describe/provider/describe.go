@@ -7,20 +7,40 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/dynamic"
 
 	"github.com/yaacov/kubectl-mtv/pkg/cmd/create/provider/providerutil"
 	"github.com/yaacov/kubectl-mtv/pkg/util/client"
+	"github.com/yaacov/kubectl-mtv/pkg/util/conditions"
 	"github.com/yaacov/kubectl-mtv/pkg/util/describe"
 	"github.com/yaacov/kubectl-mtv/pkg/util/output"
+	"github.com/yaacov/kubectl-mtv/pkg/util/watch"
 )
 
-// Describe displays detailed information about a migration provider.
-func Describe(ctx context.Context, configFlags *genericclioptions.ConfigFlags, name, namespace string, useUTC bool, outputFormat string) error {
+// Describe displays detailed information about a migration provider. In watch mode the
+// description is refreshed, and with a history store (conditions.StoreLocal or
+// conditions.StoreAnnotation) each refresh adds condition changes to the condition history.
+// credentials selects how the provider secret is shown (CredentialsHidden, CredentialsMasked
+// or CredentialsReveal).
+func Describe(ctx context.Context, configFlags *genericclioptions.ConfigFlags, name, namespace string, useUTC bool, outputFormat string, watchMode bool, historyStore, credentials string) error {
 	c, err := client.GetDynamicClient(configFlags)
 	if err != nil {
 		return fmt.Errorf("failed to get client: %v", err)
 	}
 
+	var history conditions.Store
+	if historyStore != "" {
+		if history, err = conditions.NewStore(historyStore, c, client.ProvidersGVR); err != nil {
+			return err
+		}
+	}
+
+	return watch.WrapWithWatch(watchMode, outputFormat, func() error {
+		return describeProvider(ctx, configFlags, c, name, namespace, useUTC, outputFormat, history, credentials)
+	}, watch.DefaultInterval)
+}
+
+func describeProvider(ctx context.Context, configFlags *genericclioptions.ConfigFlags, c dynamic.Interface, name, namespace string, useUTC bool, outputFormat string, history conditions.Store, credentials string) error {
 	provider, err := c.Resource(client.ProvidersGVR).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
 		return fmt.Errorf("failed to get provider: %v", err)
@@ -54,21 +74,30 @@ func Describe(ctx context.Context, configFlags *genericclioptions.ConfigFlags, n
 	buildSettingsSection(b, provider, providerType)
 
 	// Secret reference
-	if secretMap, found, _ := unstructured.NestedMap(provider.Object, "spec", "secret"); found {
-		if sname, ok := secretMap["name"].(string); ok {
-			b.Section("SECRET")
-			b.Field("Name", sname)
-			if sns, ok := secretMap["namespace"].(string); ok && sns != "" {
-				b.Field("Namespace", sns)
-			}
+	secretName, _, _ := unstructured.NestedString(provider.Object, "spec", "secret", "name")
+	secretNamespace, _, _ := unstructured.NestedString(provider.Object, "spec", "secret", "namespace")
+	if secretName != "" {
+		b.Section("SECRET")
+		b.Field("Name", secretName)
+		if secretNamespace != "" {
+			b.Field("Namespace", secretNamespace)
 		}
 	}
+	if credentials != CredentialsHidden {
+		if secretNamespace == "" {
+			secretNamespace = provider.GetNamespace()
+		}
+		addCredentialsSection(ctx, b, configFlags, secretName, secretNamespace, credentials)
+	}
 
 	// Status conditions detail
 	if conditions, found, _ := unstructured.NestedSlice(provider.Object, "status", "conditions"); found && len(conditions) > 0 {
 		b.Section("CONDITIONS")
 		addConditionsTable(b, conditions, useUTC)
 	}
+	if history != nil {
+		conditions.AddHistorySection(ctx, b, history, provider, useUTC)
+	}
 
 	if gen, found, _ := unstructured.NestedInt64(provider.Object, "status", "observedGeneration"); found {
 		b.Field("Observed Generation", fmt.Sprintf("%d", gen))
@@ -86,8 +115,12 @@ func Describe(ctx context.Context, configFlags *genericclioptions.ConfigFlags, n
 		}
 	}
 
-	// Annotations & labels
-	addAnnotationsAndLabels(b, provider)
+	// Annotations & labels; the condition history annotation is shown as a timeline above
+	var skipAnnotations []string
+	if history != nil {
+		skipAnnotations = append(skipAnnotations, conditions.HistoryAnnotation)
+	}
+	addAnnotationsAndLabels(b, provider, skipAnnotations...)
 
 	return describe.Print(b.Build(), outputFormat)
 }
@@ -186,8 +219,12 @@ func addConditionsTable(b *describe.Builder, conditions []interface{}, useUTC bo
 	b.Table(headers, rows)
 }
 
-func addAnnotationsAndLabels(b *describe.Builder, obj *unstructured.Unstructured) {
-	if annotations := obj.GetAnnotations(); len(annotations) > 0 {
+func addAnnotationsAndLabels(b *describe.Builder, obj *unstructured.Unstructured, skipAnnotations ...string) {
+	annotations := obj.GetAnnotations()
+	for _, key := range skipAnnotations {
+		delete(annotations, key)
+	}
+	if len(annotations) > 0 {
 		b.Section("ANNOTATIONS")
 		for key, value := range annotations {
 			b.Field(key, value)
@@ -0,0 +1,231 @@
+package fakeinventory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/inventory/snapshot"
+)
+
+// countFields are the inventory count fields of the provider list, by collection
+var countFields = map[string]string{
+	"vms":            "vmCount",
+	"hosts":          "hostCount",
+	"clusters":       "clusterCount",
+	"networks":       "networkCount",
+	"datastores":     "datastoreCount",
+	"storageclasses": "storageClassCount",
+}
+
+// LoadSnapshots reads the inventory snapshots in a directory, or a single snapshot file.
+// Every .json, .yaml and .yml file in the directory must be a snapshot.
+func LoadSnapshots(path string) ([]*snapshot.Snapshot, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshots: %v", err)
+	}
+
+	files := []string{path}
+	if info.IsDir() {
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read snapshots: %v", err)
+		}
+		files = nil
+		for _, entry := range entries {
+			switch strings.ToLower(filepath.Ext(entry.Name())) {
+			case ".json", ".yaml", ".yml":
+				if !entry.IsDir() {
+					files = append(files, filepath.Join(path, entry.Name()))
+				}
+			}
+		}
+	}
+
+	snapshots := make([]*snapshot.Snapshot, 0, len(files))
+	for _, file := range files {
+		s, err := snapshot.Load(file)
+		if err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, s)
+	}
+	if len(snapshots) == 0 {
+		return nil, fmt.Errorf("no inventory snapshots found in '%s'", path)
+	}
+	return snapshots, nil
+}
+
+// Server serves inventory snapshots over the provider inventory API of the Forklift inventory
+// service: /providers, /providers/<type>/<uid>, /providers/<type>/<uid>/<collection> and
+// /providers/<type>/<uid>/<collection>/<id>.
+type Server struct {
+	snapshots []*snapshot.Snapshot
+}
+
+// NewServer returns a server for the given snapshots
+func NewServer(snapshots []*snapshot.Snapshot) *Server {
+	return &Server{snapshots: snapshots}
+}
+
+// ServeHTTP implements http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	klog.V(2).Infof("%s %s", r.Method, r.URL.RequestURI())
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if parts[0] != "providers" || len(parts) > 5 {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch len(parts) {
+	case 1:
+		writeJSON(w, s.providerList())
+		return
+	case 2:
+		entries := []interface{}{}
+		for _, snap := range s.snapshots {
+			if snap.ProviderType == parts[1] {
+				entries = append(entries, providerEntry(snap))
+			}
+		}
+		writeJSON(w, entries)
+		return
+	}
+
+	snap := s.findSnapshot(parts[1], parts[2])
+	if snap == nil {
+		http.Error(w, fmt.Sprintf("provider %s/%s not found in the snapshots", parts[1], parts[2]), http.StatusNotFound)
+		return
+	}
+	if len(parts) == 3 {
+		writeJSON(w, providerEntry(snap))
+		return
+	}
+
+	objects, ok := snap.Resources[parts[3]]
+	if !ok {
+		// Collections that were not recorded are served empty, like a provider without them
+		klog.V(1).Infof("Collection '%s' is not in the snapshot of provider '%s', serving it empty", parts[3], snap.Provider)
+		objects = []map[string]interface{}{}
+	}
+	if len(parts) == 4 {
+		writeJSON(w, objects)
+		return
+	}
+
+	for _, object := range objects {
+		if object["id"] == parts[4] || object["name"] == parts[4] {
+			writeJSON(w, object)
+			return
+		}
+	}
+	http.Error(w, fmt.Sprintf("%s '%s' not found in the snapshot of provider '%s'", parts[3], parts[4], snap.Provider), http.StatusNotFound)
+}
+
+// findSnapshot returns the snapshot of a provider by UID or name. When the ID matches no
+// snapshot and only one snapshot has the provider type, that one is used, so a snapshot
+// recorded from one cluster serves a provider created with a new UID in another.
+func (s *Server) findSnapshot(providerType, id string) *snapshot.Snapshot {
+	var candidates []*snapshot.Snapshot
+	for _, snap := range s.snapshots {
+		if snap.ProviderType != providerType {
+			continue
+		}
+		if snap.ProviderUID == id || snap.Provider == id {
+			return snap
+		}
+		candidates = append(candidates, snap)
+	}
+	if len(candidates) == 1 {
+		return candidates[0]
+	}
+	return nil
+}
+
+// providerList returns the providers by type, like /providers of the inventory service
+func (s *Server) providerList() map[string][]interface{} {
+	list := map[string][]interface{}{}
+	for _, snap := range s.snapshots {
+		list[snap.ProviderType] = append(list[snap.ProviderType], providerEntry(snap))
+	}
+	return list
+}
+
+// providerEntry describes the provider of a snapshot with its inventory counts
+func providerEntry(snap *snapshot.Snapshot) map[string]interface{} {
+	uid := snap.ProviderUID
+	if uid == "" {
+		uid = snap.Provider
+	}
+	entry := map[string]interface{}{
+		"uid":       uid,
+		"name":      snap.Provider,
+		"namespace": snap.Namespace,
+		"type":      snap.ProviderType,
+	}
+	for collection, field := range countFields {
+		if objects, ok := snap.Resources[collection]; ok {
+			entry[field] = len(objects)
+		}
+	}
+	return entry
+}
+
+// writeJSON writes a JSON response
+func writeJSON(w http.ResponseWriter, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(value); err != nil {
+		klog.V(1).Infof("Failed to write response: %v", err)
+	}
+}
+
+// Serve loads the snapshots at path and serves them on addr until ctx is done
+func Serve(ctx context.Context, path, addr string) error {
+	snapshots, err := LoadSnapshots(path)
+	if err != nil {
+		return err
+	}
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %v", addr, err)
+	}
+
+	names := make([]string, 0, len(snapshots))
+	for _, snap := range snapshots {
+		names = append(names, fmt.Sprintf("%s (%s)", snap.Provider, snap.ProviderType))
+	}
+	sort.Strings(names)
+	fmt.Fprintf(os.Stderr, "Serving the inventory of %s on http://%s\n", strings.Join(names, ", "), listener.Addr())
+	fmt.Fprintf(os.Stderr, "Use it with: kubectl mtv --inventory-url http://%s ...\n", listener.Addr())
+
+	server := &http.Server{Handler: NewServer(snapshots), ReadHeaderTimeout: 10 * time.Second}
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- server.Serve(listener)
+	}()
+
+	select {
+	case err := <-errChan:
+		return err
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return server.Shutdown(shutdownCtx)
+	}
+}
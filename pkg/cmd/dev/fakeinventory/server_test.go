@@ -0,0 +1,122 @@
+package fakeinventory
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/inventory/snapshot"
+)
+
+func testSnapshots() []*snapshot.Snapshot {
+	return []*snapshot.Snapshot{
+		{
+			Kind:         snapshot.Kind,
+			Provider:     "vsphere-prod",
+			Namespace:    "mtv",
+			ProviderType: "vsphere",
+			ProviderUID:  "uid-1",
+			Resources: map[string][]map[string]interface{}{
+				"vms":      {{"id": "vm-1", "name": "web-01"}, {"id": "vm-2", "name": "db-01"}},
+				"networks": {{"id": "net-1", "name": "VM Network"}},
+			},
+		},
+		{
+			Kind:         snapshot.Kind,
+			Provider:     "rhv",
+			Namespace:    "mtv",
+			ProviderType: "ovirt",
+			Resources:    map[string][]map[string]interface{}{"vms": {{"id": "vm-a", "name": "app"}}},
+		},
+	}
+}
+
+func get(t *testing.T, server *httptest.Server, path string, status int) interface{} {
+	t.Helper()
+	resp, err := http.Get(server.URL + path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != status {
+		t.Fatalf("GET %s: status %d, want %d", path, resp.StatusCode, status)
+	}
+	if status != http.StatusOK {
+		return nil
+	}
+	var body interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("GET %s: %v", path, err)
+	}
+	return body
+}
+
+func TestServer(t *testing.T) {
+	server := httptest.NewServer(NewServer(testSnapshots()))
+	defer server.Close()
+
+	providers := get(t, server, "/providers?detail=1", http.StatusOK).(map[string]interface{})
+	vsphere := providers["vsphere"].([]interface{})[0].(map[string]interface{})
+	if vsphere["uid"] != "uid-1" || vsphere["vmCount"] != float64(2) || vsphere["networkCount"] != float64(1) {
+		t.Errorf("vsphere provider entry = %v", vsphere)
+	}
+
+	vms := get(t, server, "/providers/vsphere/uid-1/vms?detail=4", http.StatusOK).([]interface{})
+	if len(vms) != 2 {
+		t.Errorf("got %d VMs, want 2", len(vms))
+	}
+	vm := get(t, server, "/providers/vsphere/uid-1/vms/vm-2?detail=4", http.StatusOK).(map[string]interface{})
+	if vm["name"] != "db-01" {
+		t.Errorf("VM = %v, want db-01", vm)
+	}
+
+	// A provider created with a new UID is served from the only snapshot of its type
+	vms = get(t, server, "/providers/ovirt/4f2c-new-uid/vms", http.StatusOK).([]interface{})
+	if len(vms) != 1 {
+		t.Errorf("got %d oVirt VMs, want 1", len(vms))
+	}
+
+	// Collections that were not recorded are empty
+	if folders := get(t, server, "/providers/vsphere/uid-1/folders", http.StatusOK).([]interface{}); len(folders) != 0 {
+		t.Errorf("folders = %v, want none", folders)
+	}
+
+	get(t, server, "/providers/vsphere/uid-1/vms/vm-9", http.StatusNotFound)
+	get(t, server, "/providers/openstack/uid-2/vms", http.StatusNotFound)
+	get(t, server, "/other", http.StatusNotFound)
+}
+
+func TestLoadSnapshots(t *testing.T) {
+	dir := t.TempDir()
+	for i, snap := range testSnapshots() {
+		file := filepath.Join(dir, []string{"vsphere.json", "ovirt.yaml"}[i])
+		data, err := snapshot.Encode(snap, file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(file, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("fixtures"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	snapshots, err := LoadSnapshots(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snapshots) != 2 {
+		t.Errorf("loaded %d snapshots, want 2", len(snapshots))
+	}
+
+	if _, err := LoadSnapshots(filepath.Join(dir, "README.md")); err == nil {
+		t.Error("a file that is not a snapshot should be rejected")
+	}
+	if _, err := LoadSnapshots(t.TempDir()); err == nil {
+		t.Error("a directory without snapshots should be rejected")
+	}
+}
@@ -0,0 +1,221 @@
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/health"
+	"github.com/yaacov/kubectl-mtv/pkg/util/client"
+	"github.com/yaacov/kubectl-mtv/pkg/version"
+)
+
+// requiredResources lists the Forklift API resources kubectl-mtv works with
+var requiredResources = []string{"providers", "plans", "migrations", "networkmaps", "storagemaps", "hosts", "hooks"}
+
+// permission is an operation whose RBAC is verified in the user's namespace
+type permission struct {
+	verb     string
+	gvr      schema.GroupVersionResource
+	severity health.IssueSeverity
+	// purpose tells which kubectl-mtv commands need the permission
+	purpose string
+}
+
+// permissions lists the common operations checked by checkRBAC. Listing is critical,
+// nothing works without it; creating is only needed to set up and run migrations.
+var permissions = []permission{
+	{"list", client.ProvidersGVR, health.SeverityCritical, "get provider, inventory commands"},
+	{"list", client.PlansGVR, health.SeverityCritical, "get plan, describe plan"},
+	{"create", client.ProvidersGVR, health.SeverityWarning, "create provider"},
+	{"create", client.SecretsGVR, health.SeverityWarning, "create provider (credentials are stored in secrets)"},
+	{"create", client.NetworkMapGVR, health.SeverityWarning, "create mapping network, create plan"},
+	{"create", client.StorageMapGVR, health.SeverityWarning, "create mapping storage, create plan"},
+	{"create", client.PlansGVR, health.SeverityWarning, "create plan"},
+	{"create", client.MigrationsGVR, health.SeverityWarning, "start plan"},
+}
+
+// lookPath finds a binary on PATH, replaced in tests
+var lookPath = exec.LookPath
+
+// checkCluster verifies that the API server of the current context answers.
+// Returns true when the cluster is reachable.
+func checkCluster(configFlags *genericclioptions.ConfigFlags, report *Report) bool {
+	clientset, err := client.GetKubernetesClientset(configFlags)
+	if err != nil {
+		report.fail(health.SeverityCritical, "Cluster", "api-server", fmt.Sprintf("Failed to create cluster client: %v", err),
+			"Check the kubeconfig file and the current context (kubectl config current-context)")
+		return false
+	}
+	version, err := clientset.Discovery().ServerVersion()
+	if err != nil {
+		report.fail(health.SeverityCritical, "Cluster", "api-server", fmt.Sprintf("API server is not reachable: %v", err),
+			"Log in to the cluster (oc login) or check the kubeconfig server address and network access")
+		return false
+	}
+	report.pass("Cluster", "api-server", fmt.Sprintf("Kubernetes %s", version.GitVersion))
+	return true
+}
+
+// checkOperator verifies that the MTV operator is installed and returns its namespace and
+// version, the version is empty when it is unknown
+func checkOperator(ctx context.Context, configFlags *genericclioptions.ConfigFlags, report *Report) (string, string) {
+	operator := health.CheckOperatorHealth(ctx, configFlags)
+	switch {
+	case operator.Installed:
+		message := fmt.Sprintf("Installed in %s", operator.Namespace)
+		operatorVersion := ""
+		if operator.Version != "" && operator.Version != "unknown" {
+			operatorVersion = operator.Version
+			message = fmt.Sprintf("%s installed in %s", operator.Version, operator.Namespace)
+		}
+		report.pass("Operator", "mtv-operator", message)
+		return operator.Namespace, operatorVersion
+	case operator.Error != "":
+		// Users without cluster-wide read access cannot see CRDs, the Forklift API check still tells if MTV works
+		report.fail(health.SeverityInfo, "Operator", "mtv-operator", fmt.Sprintf("Cannot detect the operator: %s", operator.Error),
+			"Ask a cluster administrator for read access to customresourcedefinitions to see the operator version")
+	default:
+		report.fail(health.SeverityCritical, "Operator", "mtv-operator", "MTV operator is not installed",
+			"Install the Migration Toolkit for Virtualization operator from OperatorHub")
+	}
+	return client.OpenShiftMTVNamespace, ""
+}
+
+// checkVersionSkew verifies that the MTV operator version is in the range this kubectl-mtv
+// release supports. An older operator lacks API fields kubectl-mtv sets; a newer one may
+// have features kubectl-mtv does not know yet.
+func checkVersionSkew(report *Report, clientVersion, operatorVersion string) {
+	if operatorVersion == "" {
+		report.skip("Version", "version-skew", "Skipped because the MTV operator version is unknown")
+		return
+	}
+	if clientVersion == "" {
+		clientVersion = "unknown"
+	}
+
+	skew, err := version.OperatorSkew(operatorVersion)
+	switch {
+	case err != nil:
+		report.skip("Version", "version-skew", fmt.Sprintf("Skipped because the MTV operator version %q cannot be parsed", operatorVersion))
+	case skew < 0:
+		report.fail(health.SeverityWarning, "Version", "version-skew",
+			fmt.Sprintf("MTV operator %s is older than %s, the oldest release kubectl-mtv %s supports; some commands and flags will fail", operatorVersion, version.MinOperatorVersion, clientVersion),
+			"Upgrade the MTV operator, or use a kubectl-mtv release made for this operator version")
+	case skew > 0:
+		report.fail(health.SeverityInfo, "Version", "version-skew",
+			fmt.Sprintf("MTV operator %s is newer than %s, the newest release kubectl-mtv %s was tested with", operatorVersion, version.TestedOperatorVersion, clientVersion),
+			"Upgrade kubectl-mtv (kubectl krew upgrade mtv, or download the latest release)")
+	default:
+		report.pass("Version", "version-skew", fmt.Sprintf("kubectl-mtv %s supports MTV operator %s", clientVersion, operatorVersion))
+	}
+}
+
+// checkCRDs verifies through API discovery, which any user may read, that the Forklift
+// API group serves every resource kubectl-mtv uses. Returns true when the API is available.
+func checkCRDs(configFlags *genericclioptions.ConfigFlags, report *Report) bool {
+	version, err := client.ForkliftVersion(configFlags)
+	if err != nil {
+		version = client.Version
+	}
+	groupVersion := client.Group + "/" + version
+	clientset, err := client.GetKubernetesClientset(configFlags)
+	if err != nil {
+		report.fail(health.SeverityCritical, "CRD", groupVersion, fmt.Sprintf("Failed to create cluster client: %v", err),
+			"Check the kubeconfig file and the current context")
+		return false
+	}
+	resources, err := clientset.Discovery().ServerResourcesForGroupVersion(groupVersion)
+	if err != nil {
+		report.fail(health.SeverityCritical, "CRD", groupVersion, fmt.Sprintf("Forklift API is not served: %v", err),
+			"Install the MTV operator, or upgrade it to a version serving "+groupVersion)
+		return false
+	}
+
+	served := map[string]bool{}
+	for _, r := range resources.APIResources {
+		served[r.Name] = true
+	}
+	missing := MissingResources(served)
+	if len(missing) > 0 {
+		report.fail(health.SeverityCritical, "CRD", groupVersion, fmt.Sprintf("Missing resources: %s", strings.Join(missing, ", ")),
+			"Reinstall or upgrade the MTV operator")
+		return true
+	}
+	report.pass("CRD", groupVersion, fmt.Sprintf("%d resources served", len(resources.APIResources)))
+	return true
+}
+
+// MissingResources returns the required Forklift resources absent from the served set
+func MissingResources(served map[string]bool) []string {
+	missing := []string{}
+	for _, name := range requiredResources {
+		if !served[name] {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
+
+// checkInventory verifies that the inventory service answers
+func checkInventory(ctx context.Context, configFlags *genericclioptions.ConfigFlags, report *Report, operatorNamespace, inventoryURL string, insecureSkipTLS bool) {
+	if inventoryURL == "" {
+		inventoryURL = client.DiscoverInventoryURL(ctx, configFlags, operatorNamespace)
+	}
+	if inventoryURL == "" {
+		report.fail(health.SeverityWarning, "Inventory", "inventory-service", "No inventory URL provided and no forklift-inventory route found",
+			"Pass --inventory-url or set MTV_INVENTORY_URL, e.g. to a port-forward of the forklift-inventory service")
+		return
+	}
+
+	if _, err := client.FetchProvidersWithDetailAndInsecure(ctx, configFlags, inventoryURL, 0, insecureSkipTLS); err != nil {
+		fix := "Check the forklift-inventory route and service, and that the inventory pod is running"
+		if strings.Contains(err.Error(), "x509") || strings.Contains(err.Error(), "certificate") {
+			fix = "Trust the cluster ingress CA, or pass --inventory-insecure-skip-tls for testing"
+		}
+		report.fail(health.SeverityCritical, "Inventory", "inventory-service", fmt.Sprintf("%s is not reachable: %v", inventoryURL, err), fix)
+		return
+	}
+	report.pass("Inventory", "inventory-service", fmt.Sprintf("%s is reachable", inventoryURL))
+}
+
+// checkRBAC verifies the user may perform the common kubectl-mtv operations in the namespace
+func checkRBAC(ctx context.Context, configFlags *genericclioptions.ConfigFlags, report *Report, namespace string) {
+	for _, p := range permissions {
+		name := p.verb + " " + p.gvr.Resource
+		if client.CanAccessResource(ctx, configFlags, namespace, p.gvr, p.verb) {
+			report.pass("RBAC", name, fmt.Sprintf("Allowed in %s", namespace))
+			continue
+		}
+		report.fail(p.severity, "RBAC", name, fmt.Sprintf("Not allowed in %s, needed by: %s", namespace, p.purpose),
+			fmt.Sprintf("Ask a cluster administrator to grant '%s' on %s in namespace %s, or use a namespace you own (--namespace)", p.verb, p.gvr.Resource, namespace))
+	}
+}
+
+// checkTools verifies the local binaries: kubectl or oc, used by the MCP kubectl tools and
+// to run kubectl-mtv as a plugin, and kubectl-mtv itself on PATH for 'kubectl mtv'
+func checkTools(report *Report) {
+	found := []string{}
+	for _, name := range []string{"kubectl", "oc"} {
+		if path, err := lookPath(name); err == nil {
+			found = append(found, path)
+		}
+	}
+	if len(found) == 0 {
+		report.fail(health.SeverityWarning, "Tools", "kubectl", "Neither kubectl nor oc was found on PATH",
+			"Install kubectl or oc, the MCP kubectl tools and 'kubectl mtv' need one of them")
+	} else {
+		report.pass("Tools", "kubectl", "Found "+strings.Join(found, ", "))
+	}
+
+	if path, err := lookPath("kubectl-mtv"); err == nil {
+		report.pass("Tools", "kubectl-mtv", "Found "+path)
+	} else {
+		report.fail(health.SeverityInfo, "Tools", "kubectl-mtv", "kubectl-mtv is not on PATH, 'kubectl mtv' cannot find the plugin",
+			"Copy the kubectl-mtv binary to a directory on PATH")
+	}
+}
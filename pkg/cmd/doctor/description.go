@@ -0,0 +1,75 @@
+package doctor
+
+import (
+	"fmt"
+
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/health"
+	"github.com/yaacov/kubectl-mtv/pkg/util/describe"
+	"github.com/yaacov/kubectl-mtv/pkg/util/output"
+)
+
+// ToDescription converts a Report into a describe.Description
+// that can be rendered in any supported format (table, json, yaml, markdown).
+func (r *Report) ToDescription() *describe.Description {
+	b := describe.NewBuilder("MTV DOCTOR")
+
+	failed := 0
+	for _, c := range r.Checks {
+		if c.Status == health.DeepCheckFail {
+			failed++
+		}
+	}
+	b.Section(fmt.Sprintf("CHECKS (%d total, %d failed)", len(r.Checks), failed))
+	b.Field("Namespace", r.Namespace)
+
+	headers := []describe.TableColumn{
+		{Display: "COMPONENT", Key: "component"},
+		{Display: "CHECK", Key: "name"},
+		{Display: "STATUS", Key: "status", ColorFunc: colorizeStatus},
+		{Display: "SEVERITY", Key: "severity"},
+		{Display: "MESSAGE", Key: "message"},
+	}
+	rows := make([]map[string]string, 0, len(r.Checks))
+	for _, c := range r.Checks {
+		rows = append(rows, map[string]string{
+			"component": c.Component,
+			"name":      c.Name,
+			"status":    c.Status,
+			"severity":  string(c.Severity),
+			"message":   c.Message,
+		})
+	}
+	b.Table(headers, rows)
+
+	b.Section("FIXES")
+	if len(r.Fixes) == 0 {
+		b.FieldC("", "Everything looks good, no fixes needed", output.Green)
+		return b.Build()
+	}
+	for _, f := range r.Fixes {
+		var cfn func(string) string
+		switch f.Severity {
+		case health.SeverityCritical:
+			cfn = output.Red
+		case health.SeverityWarning:
+			cfn = output.Yellow
+		default:
+			cfn = output.Blue
+		}
+		b.FieldC(fmt.Sprintf("%d", f.Priority), fmt.Sprintf("[%s] %s: %s -- %s", f.Severity, f.Check, f.Problem, f.Action), cfn)
+	}
+
+	return b.Build()
+}
+
+// colorizeStatus colors a check status value.
+func colorizeStatus(status string) string {
+	switch status {
+	case health.DeepCheckPass:
+		return output.Green(status)
+	case health.DeepCheckFail:
+		return output.Red(status)
+	default:
+		return output.Yellow(status)
+	}
+}
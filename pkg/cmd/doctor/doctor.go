@@ -0,0 +1,145 @@
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/health"
+	"github.com/yaacov/kubectl-mtv/pkg/util/describe"
+)
+
+// Check is the result of a single doctor check
+type Check struct {
+	Component string               `json:"component" yaml:"component"`
+	Name      string               `json:"name" yaml:"name"`
+	Status    string               `json:"status" yaml:"status"`
+	Severity  health.IssueSeverity `json:"severity,omitempty" yaml:"severity,omitempty"`
+	Message   string               `json:"message" yaml:"message"`
+	Fix       string               `json:"fix,omitempty" yaml:"fix,omitempty"`
+}
+
+// Fix is a step of the prioritized fix list, built from the failed checks
+type Fix struct {
+	Priority int                  `json:"priority" yaml:"priority"`
+	Severity health.IssueSeverity `json:"severity" yaml:"severity"`
+	Check    string               `json:"check" yaml:"check"`
+	Problem  string               `json:"problem" yaml:"problem"`
+	Action   string               `json:"action" yaml:"action"`
+}
+
+// Report contains the doctor check results and the fixes to apply, most important first
+type Report struct {
+	Timestamp time.Time `json:"timestamp" yaml:"timestamp"`
+	Namespace string    `json:"namespace" yaml:"namespace"`
+	Checks    []Check   `json:"checks" yaml:"checks"`
+	Fixes     []Fix     `json:"fixes" yaml:"fixes"`
+}
+
+// Options contains the options of the doctor checks
+type Options struct {
+	// Namespace is where the user's MTV resources live, used for the RBAC checks
+	Namespace                string
+	InventoryURL             string
+	InventoryInsecureSkipTLS bool
+	// ClientVersion is the kubectl-mtv version compared with the operator version
+	ClientVersion string
+}
+
+// add records a check result
+func (r *Report) add(check Check) {
+	r.Checks = append(r.Checks, check)
+}
+
+// pass records a passed check
+func (r *Report) pass(component, name, message string) {
+	r.add(Check{Component: component, Name: name, Status: health.DeepCheckPass, Message: message})
+}
+
+// fail records a failed check and the fix that resolves it
+func (r *Report) fail(severity health.IssueSeverity, component, name, message, fix string) {
+	r.add(Check{Component: component, Name: name, Status: health.DeepCheckFail, Severity: severity, Message: message, Fix: fix})
+}
+
+// skip records a check that could not run
+func (r *Report) skip(component, name, message string) {
+	r.add(Check{Component: component, Name: name, Status: health.DeepCheckSkipped, Message: message})
+}
+
+// Run checks, from the most basic up, everything kubectl-mtv needs to work: the cluster
+// connection, the MTV operator and its version skew with kubectl-mtv, its CRDs, the inventory
+// service, the user's permissions and the local kubectl or oc binary. Checks that depend on a failed one are skipped.
+func Run(ctx context.Context, configFlags *genericclioptions.ConfigFlags, opts Options) *Report {
+	report := &Report{
+		Timestamp: time.Now(),
+		Namespace: opts.Namespace,
+		Checks:    []Check{},
+	}
+
+	if checkCluster(configFlags, report) {
+		operatorNamespace, operatorVersion := checkOperator(ctx, configFlags, report)
+		checkVersionSkew(report, opts.ClientVersion, operatorVersion)
+		if checkCRDs(configFlags, report) {
+			checkInventory(ctx, configFlags, report, operatorNamespace, opts.InventoryURL, opts.InventoryInsecureSkipTLS)
+			checkRBAC(ctx, configFlags, report, opts.Namespace)
+		} else {
+			report.skip("Inventory", "inventory-service", "Skipped because the Forklift API is not available")
+			report.skip("RBAC", "permissions", "Skipped because the Forklift API is not available")
+		}
+	} else {
+		for _, component := range []string{"Operator", "Version", "CRD", "Inventory", "RBAC"} {
+			report.skip(component, "cluster", "Skipped because the cluster is not reachable")
+		}
+	}
+	checkTools(report)
+
+	report.Fixes = FixList(report.Checks)
+	return report
+}
+
+// severityRank orders severities from the most to the least urgent
+var severityRank = map[health.IssueSeverity]int{
+	health.SeverityCritical: 0,
+	health.SeverityWarning:  1,
+	health.SeverityInfo:     2,
+}
+
+// FixList returns the fixes of the failed checks, critical first. Checks of the same
+// severity keep their order, which runs from the cluster connection up to local tools,
+// so the fix unblocking the most other checks comes first.
+func FixList(checks []Check) []Fix {
+	failed := []Check{}
+	for _, c := range checks {
+		if c.Status == health.DeepCheckFail {
+			failed = append(failed, c)
+		}
+	}
+	sort.SliceStable(failed, func(i, j int) bool {
+		return severityRank[failed[i].Severity] < severityRank[failed[j].Severity]
+	})
+
+	fixes := make([]Fix, 0, len(failed))
+	for i, c := range failed {
+		fixes = append(fixes, Fix{
+			Priority: i + 1,
+			Severity: c.Severity,
+			Check:    c.Component + "/" + c.Name,
+			Problem:  c.Message,
+			Action:   c.Fix,
+		})
+	}
+	return fixes
+}
+
+// Print formats and prints the doctor report
+func Print(report *Report, outputFormat string) error {
+	out, err := describe.Format(report.ToDescription(), outputFormat)
+	if err != nil {
+		return fmt.Errorf("failed to format report: %v", err)
+	}
+	fmt.Print(out)
+	return nil
+}
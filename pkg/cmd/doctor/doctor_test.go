@@ -0,0 +1,103 @@
+package doctor
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/health"
+)
+
+func TestFixList(t *testing.T) {
+	report := &Report{}
+	report.pass("Cluster", "api-server", "Kubernetes v1.31.0")
+	report.fail(health.SeverityInfo, "Tools", "kubectl-mtv", "not on PATH", "copy the binary")
+	report.fail(health.SeverityWarning, "RBAC", "create plans", "not allowed", "ask for access")
+	report.fail(health.SeverityCritical, "Inventory", "inventory-service", "not reachable", "check the route")
+	report.skip("RBAC", "permissions", "skipped")
+	report.fail(health.SeverityCritical, "RBAC", "list plans", "not allowed", "ask for access")
+
+	fixes := FixList(report.Checks)
+	want := []string{"Inventory/inventory-service", "RBAC/list plans", "RBAC/create plans", "Tools/kubectl-mtv"}
+	if len(fixes) != len(want) {
+		t.Fatalf("FixList() returned %d fixes, want %d: %+v", len(fixes), len(want), fixes)
+	}
+	for i, f := range fixes {
+		if f.Check != want[i] || f.Priority != i+1 {
+			t.Errorf("fix %d = %d %s, want %d %s", i, f.Priority, f.Check, i+1, want[i])
+		}
+	}
+	if fixes[0].Action != "check the route" || fixes[0].Problem != "not reachable" {
+		t.Errorf("fix = %+v", fixes[0])
+	}
+}
+
+func TestMissingResources(t *testing.T) {
+	served := map[string]bool{}
+	for _, r := range requiredResources {
+		served[r] = true
+	}
+	if missing := MissingResources(served); len(missing) != 0 {
+		t.Errorf("MissingResources() = %v, want none", missing)
+	}
+
+	delete(served, "hooks")
+	delete(served, "plans")
+	if missing := MissingResources(served); strings.Join(missing, ",") != "plans,hooks" {
+		t.Errorf("MissingResources() = %v, want [plans hooks]", missing)
+	}
+}
+
+func TestCheckTools(t *testing.T) {
+	original := lookPath
+	defer func() { lookPath = original }()
+
+	lookPath = func(name string) (string, error) {
+		if name == "oc" {
+			return "/usr/bin/oc", nil
+		}
+		return "", fmt.Errorf("%s not found", name)
+	}
+	report := &Report{}
+	checkTools(report)
+	if len(report.Checks) != 2 {
+		t.Fatalf("checkTools() recorded %d checks, want 2", len(report.Checks))
+	}
+	if c := report.Checks[0]; c.Status != health.DeepCheckPass || !strings.Contains(c.Message, "/usr/bin/oc") {
+		t.Errorf("kubectl check = %+v, want pass with oc", c)
+	}
+	if c := report.Checks[1]; c.Status != health.DeepCheckFail || c.Severity != health.SeverityInfo {
+		t.Errorf("kubectl-mtv check = %+v, want info failure", c)
+	}
+
+	lookPath = func(name string) (string, error) { return "", fmt.Errorf("%s not found", name) }
+	report = &Report{}
+	checkTools(report)
+	if c := report.Checks[0]; c.Status != health.DeepCheckFail || c.Severity != health.SeverityWarning {
+		t.Errorf("kubectl check = %+v, want warning failure", c)
+	}
+}
+
+func TestCheckVersionSkew(t *testing.T) {
+	tests := []struct {
+		operatorVersion string
+		status          string
+		severity        health.IssueSeverity
+	}{
+		{"mtv-operator.v2.9.3", health.DeepCheckPass, ""},
+		{"mtv-operator.v2.6.0", health.DeepCheckFail, health.SeverityWarning},
+		{"mtv-operator.v2.99.0", health.DeepCheckFail, health.SeverityInfo},
+		{"", health.DeepCheckSkipped, ""},
+		{"mtv-operator", health.DeepCheckSkipped, ""},
+	}
+	for _, tt := range tests {
+		report := &Report{}
+		checkVersionSkew(report, "v0.7.0", tt.operatorVersion)
+		if len(report.Checks) != 1 {
+			t.Fatalf("checkVersionSkew(%q) recorded %d checks, want 1", tt.operatorVersion, len(report.Checks))
+		}
+		if c := report.Checks[0]; c.Status != tt.status || c.Severity != tt.severity {
+			t.Errorf("checkVersionSkew(%q) = %s %s, want %s %s", tt.operatorVersion, c.Status, c.Severity, tt.status, tt.severity)
+		}
+	}
+}
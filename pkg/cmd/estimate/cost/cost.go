@@ -0,0 +1,377 @@
+package cost
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/get/inventory"
+	"github.com/yaacov/kubectl-mtv/pkg/util/client"
+	"github.com/yaacov/kubectl-mtv/pkg/util/output"
+	"github.com/yaacov/kubectl-mtv/pkg/util/vocab"
+)
+
+// Options selects the plan and the price table
+type Options struct {
+	Plan            string
+	Namespace       string
+	Pricing         *Pricing
+	InventoryURL    string
+	InsecureSkipTLS bool
+	Output          string
+}
+
+// Estimate is the projected monthly cost of the VMs of a plan on the target cluster
+type Estimate struct {
+	Plan          string   `json:"plan"`
+	Namespace     string   `json:"namespace"`
+	Provider      string   `json:"provider"`
+	Currency      string   `json:"currency,omitempty"`
+	HoursPerMonth float64  `json:"hoursPerMonth"`
+	VMs           []VMCost `json:"vms"`
+	Total         Cost     `json:"total"`
+}
+
+// Cost is the size of a VM, or of all the VMs, and its monthly cost
+type Cost struct {
+	CPUs       int64   `json:"cpus"`
+	MemoryGiB  float64 `json:"memoryGiB"`
+	StorageGiB float64 `json:"storageGiB"`
+	Compute    float64 `json:"computePerMonth"`
+	Storage    float64 `json:"storagePerMonth"`
+	Monthly    float64 `json:"totalPerMonth"`
+}
+
+// VMCost is the monthly cost of one VM
+type VMCost struct {
+	Name string `json:"name"`
+	ID   string `json:"id,omitempty"`
+	Cost
+	// Note explains missing sizes, e.g. VMs not found in the inventory
+	Note string `json:"note,omitempty"`
+}
+
+// Print estimates the cost of a plan and prints it
+func Print(ctx context.Context, configFlags *genericclioptions.ConfigFlags, opts Options) error {
+	estimate, err := Compute(ctx, configFlags, opts)
+	if err != nil {
+		return err
+	}
+
+	switch strings.ToLower(opts.Output) {
+	case "json":
+		return output.PrintJSONWithEmpty(estimate, "")
+	case "yaml":
+		return output.PrintYAMLWithEmpty(estimate, "")
+	case "", "table":
+		return printTable(estimate, false)
+	case "markdown":
+		return printTable(estimate, true)
+	default:
+		return fmt.Errorf("unsupported output format: %s. Supported formats: table, markdown, json, yaml", opts.Output)
+	}
+}
+
+// Compute reads the plan and the sizes of its VMs from the source inventory and prices them
+func Compute(ctx context.Context, configFlags *genericclioptions.ConfigFlags, opts Options) (*Estimate, error) {
+	c, err := client.GetDynamicClient(configFlags)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get client: %v", err)
+	}
+	plan, err := c.Resource(client.PlansGVR).Namespace(opts.Namespace).Get(ctx, opts.Plan, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get plan '%s': %v", opts.Plan, err)
+	}
+
+	providerName, _, _ := unstructured.NestedString(plan.Object, "spec", "provider", "source", "name")
+	providerNamespace, _, _ := unstructured.NestedString(plan.Object, "spec", "provider", "source", "namespace")
+	if providerNamespace == "" {
+		providerNamespace = plan.GetNamespace()
+	}
+	if providerName == "" {
+		return nil, fmt.Errorf("plan '%s' has no source provider", opts.Plan)
+	}
+
+	provider, err := inventory.GetProviderByName(ctx, configFlags, providerName, providerNamespace)
+	if err != nil {
+		return nil, err
+	}
+	providerClient := inventory.NewProviderClientWithInsecure(configFlags, provider, opts.InventoryURL, opts.InsecureSkipTLS)
+	providerType, err := providerClient.GetProviderType()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get provider type: %v", err)
+	}
+	vms, err := fetchCollection(ctx, providerClient, "vms", providerType)
+	if err != nil {
+		return nil, err
+	}
+
+	sizer := newSizer(providerType)
+	if providerType == "openstack" {
+		if sizer.flavors, err = fetchCollection(ctx, providerClient, "flavors", providerType); err != nil {
+			return nil, err
+		}
+		if sizer.volumes, err = fetchCollection(ctx, providerClient, "volumes", providerType); err != nil {
+			return nil, err
+		}
+	}
+
+	estimate := &Estimate{
+		Plan:          plan.GetName(),
+		Namespace:     plan.GetNamespace(),
+		Provider:      providerName,
+		Currency:      opts.Pricing.Currency,
+		HoursPerMonth: opts.Pricing.HoursPerMonth,
+		VMs:           []VMCost{},
+	}
+	estimate.VMs, estimate.Total = priceVMs(planVMs(plan), vms, sizer, opts.Pricing)
+	return estimate, nil
+}
+
+// planVM is a VM reference in the plan spec
+type planVM struct {
+	ID   string
+	Name string
+}
+
+// planVMs returns the VMs of a plan
+func planVMs(plan *unstructured.Unstructured) []planVM {
+	result := []planVM{}
+	vms, _, _ := unstructured.NestedSlice(plan.Object, "spec", "vms")
+	for _, v := range vms {
+		vm, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		id, _ := vm["id"].(string)
+		name, _ := vm["name"].(string)
+		result = append(result, planVM{ID: id, Name: name})
+	}
+	return result
+}
+
+// fetchCollection returns the objects of an inventory collection
+func fetchCollection(ctx context.Context, providerClient *inventory.ProviderClient, collection, providerType string) ([]map[string]interface{}, error) {
+	data, err := providerClient.GetResourceCollection(ctx, collection, 4)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s from inventory: %v", collection, err)
+	}
+	if providerType == "ec2" {
+		data = inventory.ExtractEC2Objects(data)
+	}
+	items, ok := data.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected data format: expected array for %s inventory", collection)
+	}
+	objects := make([]map[string]interface{}, 0, len(items))
+	for _, item := range items {
+		if object, ok := item.(map[string]interface{}); ok {
+			objects = append(objects, object)
+		}
+	}
+	return objects, nil
+}
+
+// priceVMs prices the plan VMs found in the inventory VMs and returns the per-VM costs and
+// their total. VMs missing from the inventory, or with unknown sizes, get a note.
+func priceVMs(refs []planVM, vms []map[string]interface{}, sizer *sizer, pricing *Pricing) ([]VMCost, Cost) {
+	byID := map[string]map[string]interface{}{}
+	byName := map[string]map[string]interface{}{}
+	for _, vm := range vms {
+		if id, _ := vm["id"].(string); id != "" {
+			byID[id] = vm
+		}
+		if name, _ := vm["name"].(string); name != "" {
+			byName[name] = vm
+		}
+	}
+
+	costs := make([]VMCost, 0, len(refs))
+	var total Cost
+	for _, ref := range refs {
+		vm := byID[ref.ID]
+		if vm == nil {
+			vm = byName[ref.Name]
+		}
+		item := VMCost{Name: ref.Name, ID: ref.ID}
+		if vm == nil {
+			item.Note = "not found in inventory"
+			costs = append(costs, item)
+			continue
+		}
+		if item.Name == "" {
+			item.Name, _ = vm["name"].(string)
+		}
+
+		item.CPUs, item.MemoryGiB, item.StorageGiB = sizer.size(vm)
+		switch {
+		case item.CPUs == 0 || item.MemoryGiB == 0:
+			item.Note = "CPU or memory size unknown"
+		case item.StorageGiB == 0:
+			item.Note = "disk size unknown"
+		}
+		item.Compute = roundCents((float64(item.CPUs)*pricing.CPUPerHour+item.MemoryGiB*pricing.MemoryGiBPerHour)*pricing.HoursPerMonth + pricing.VMPerMonth)
+		item.Storage = roundCents(item.StorageGiB * pricing.StorageGiBPerMonth)
+		item.Monthly = roundCents(item.Compute + item.Storage)
+
+		total.CPUs += item.CPUs
+		total.MemoryGiB += item.MemoryGiB
+		total.StorageGiB += item.StorageGiB
+		total.Compute += item.Compute
+		total.Storage += item.Storage
+		total.Monthly += item.Monthly
+		costs = append(costs, item)
+	}
+	total.MemoryGiB = math.Round(total.MemoryGiB*10) / 10
+	total.StorageGiB = math.Round(total.StorageGiB*10) / 10
+	total.Compute = roundCents(total.Compute)
+	total.Storage = roundCents(total.Storage)
+	total.Monthly = roundCents(total.Monthly)
+	return costs, total
+}
+
+// sizer reads the CPUs, memory and disk size of inventory VMs
+type sizer struct {
+	providerType string
+	// flavors and volumes size OpenStack VMs
+	flavors []map[string]interface{}
+	volumes []map[string]interface{}
+}
+
+func newSizer(providerType string) *sizer {
+	return &sizer{providerType: providerType}
+}
+
+// size returns the vCPUs, memory GiB and disk GiB of an inventory VM
+func (s *sizer) size(vm map[string]interface{}) (int64, float64, float64) {
+	inventory.AugmentVM(vm)
+
+	cpus := int64(number(vm["cpuCount"]))
+	if cpus == 0 {
+		// oVirt reports the CPU topology
+		sockets, cores, threads := number(vm["cpuSockets"]), number(vm["cpuCores"]), number(vm["cpuThreads"])
+		if threads == 0 {
+			threads = 1
+		}
+		cpus = int64(sockets * cores * threads)
+	}
+	memory := number(vm["memoryGiB"])
+	if memory == 0 {
+		// oVirt reports the memory in bytes
+		memory = vocab.ToGiB(number(vm["memory"]), vocab.Bytes)
+	}
+	storage := number(vm["diskGiB"])
+
+	if s.providerType == "openstack" {
+		flavorID, _, _ := unstructured.NestedString(vm, "flavor", "id")
+		for _, flavor := range s.flavors {
+			if flavor["id"] == flavorID {
+				cpus = int64(number(flavor["vcpus"]))
+				memory = vocab.ToGiB(number(flavor["ram"]), vocab.MiB)
+				if storage == 0 {
+					storage = number(flavor["disk"])
+				}
+			}
+		}
+		storage += s.openstackVolumesGiB(vm)
+	}
+	return cpus, memory, storage
+}
+
+// openstackVolumesGiB returns the size of the volumes attached to an OpenStack VM
+func (s *sizer) openstackVolumesGiB(vm map[string]interface{}) float64 {
+	attached, _ := vm["attachedVolumes"].([]interface{})
+	ids := map[string]bool{}
+	for _, a := range attached {
+		if volume, ok := a.(map[string]interface{}); ok {
+			if id, _ := volume["ID"].(string); id != "" {
+				ids[id] = true
+			}
+			if id, _ := volume["id"].(string); id != "" {
+				ids[id] = true
+			}
+		}
+	}
+	var total float64
+	for _, volume := range s.volumes {
+		if id, _ := volume["id"].(string); ids[id] {
+			total += number(volume["size"])
+		}
+	}
+	return total
+}
+
+// number reads an inventory number
+func number(value interface{}) float64 {
+	switch v := value.(type) {
+	case float64:
+		return v
+	case int64:
+		return float64(v)
+	case int:
+		return float64(v)
+	}
+	return 0
+}
+
+// roundCents rounds a price to two decimals
+func roundCents(value float64) float64 {
+	return math.Round(value*100) / 100
+}
+
+// printTable prints the per-VM costs with a total row, as a table or markdown
+func printTable(estimate *Estimate, markdown bool) error {
+	currency := estimate.Currency
+	if currency != "" {
+		currency = " (" + currency + ")"
+	}
+	columns := []output.Column{
+		{Title: "VM", Key: "name"},
+		{Title: "CPUS", Key: "cpus"},
+		{Title: "MEMORY GIB", Key: "memoryGiB"},
+		{Title: "STORAGE GIB", Key: "storageGiB"},
+		{Title: "COMPUTE/MONTH" + currency, Key: "compute"},
+		{Title: "STORAGE/MONTH" + currency, Key: "storage"},
+		{Title: "TOTAL/MONTH" + currency, Key: "total"},
+		{Title: "NOTE", Key: "note"},
+	}
+
+	rows := make([]map[string]interface{}, 0, len(estimate.VMs)+1)
+	for _, vm := range estimate.VMs {
+		rows = append(rows, costRow(vm.Name, vm.Cost, vm.Note))
+	}
+	if len(rows) > 0 {
+		rows = append(rows, costRow("TOTAL", estimate.Total, ""))
+	}
+
+	emptyMsg := fmt.Sprintf("Plan '%s' has no VMs", estimate.Plan)
+	if markdown {
+		return output.PrintMarkdownWithQuery(rows, columns, nil, emptyMsg)
+	}
+	if err := output.PrintTableWithQuery(rows, columns, nil, emptyMsg); err != nil {
+		return err
+	}
+	if len(rows) > 0 {
+		fmt.Printf("\nMonthly cost of plan '%s' at %g hours per month: %.2f%s\n", estimate.Plan, estimate.HoursPerMonth, estimate.Total.Monthly, currency)
+	}
+	return nil
+}
+
+// costRow returns a table row for a cost
+func costRow(name string, cost Cost, note string) map[string]interface{} {
+	return map[string]interface{}{
+		"name":       name,
+		"cpus":       cost.CPUs,
+		"memoryGiB":  fmt.Sprintf("%.1f", cost.MemoryGiB),
+		"storageGiB": fmt.Sprintf("%.1f", cost.StorageGiB),
+		"compute":    fmt.Sprintf("%.2f", cost.Compute),
+		"storage":    fmt.Sprintf("%.2f", cost.Storage),
+		"total":      fmt.Sprintf("%.2f", cost.Monthly),
+		"note":       note,
+	}
+}
@@ -0,0 +1,103 @@
+package cost
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadPricing(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	pricing, err := LoadPricing(write("ok.yaml", "currency: EUR\ncpuPerHour: 0.03\nmemoryGiBPerHour: 0.004\nstorageGiBPerMonth: 0.1\n"))
+	if err != nil {
+		t.Fatalf("LoadPricing() error = %v", err)
+	}
+	if pricing.Currency != "EUR" || pricing.HoursPerMonth != DefaultHoursPerMonth || pricing.CPUPerHour != 0.03 {
+		t.Errorf("LoadPricing() = %+v", pricing)
+	}
+
+	for name, content := range map[string]string{
+		"negative.yaml": "cpuPerHour: -1\n",
+		"empty.yaml":    "currency: USD\n",
+		"unknown.yaml":  "cpuPerHour: 1\ngpuPerHour: 2\n",
+		"hours.yaml":    "cpuPerHour: 1\nhoursPerMonth: 1000\n",
+	} {
+		if _, err := LoadPricing(write(name, content)); err == nil {
+			t.Errorf("LoadPricing(%s) expected an error", name)
+		}
+	}
+}
+
+func TestPriceVMs(t *testing.T) {
+	pricing := &Pricing{HoursPerMonth: 100, CPUPerHour: 0.01, MemoryGiBPerHour: 0.001, StorageGiBPerMonth: 0.1, VMPerMonth: 2}
+	vms := []map[string]interface{}{
+		{
+			"id": "vm-1", "name": "web", "cpuCount": float64(2), "memoryMB": float64(4096),
+			"disks": []interface{}{map[string]interface{}{"capacity": float64(50 * 1024 * 1024 * 1024)}},
+		},
+		{
+			"id": "vm-2", "name": "db", "cpuSockets": float64(2), "cpuCores": float64(2),
+			"memory": float64(8 * 1024 * 1024 * 1024),
+		},
+	}
+	refs := []planVM{{ID: "vm-1"}, {Name: "db"}, {ID: "vm-3", Name: "gone"}}
+
+	costs, total := priceVMs(refs, vms, newSizer("vsphere"), pricing)
+	if len(costs) != 3 {
+		t.Fatalf("priceVMs() returned %d costs", len(costs))
+	}
+
+	// web: (2*0.01 + 4*0.001) * 100 + 2 = 4.4 compute, 50 * 0.1 = 5 storage
+	web := costs[0]
+	if web.Name != "web" || web.CPUs != 2 || web.MemoryGiB != 4 || web.StorageGiB != 50 {
+		t.Errorf("web size = %+v", web)
+	}
+	if web.Compute != 4.4 || web.Storage != 5 || web.Monthly != 9.4 || web.Note != "" {
+		t.Errorf("web cost = %+v", web)
+	}
+
+	// db: oVirt topology and memory in bytes, without disks
+	db := costs[1]
+	if db.CPUs != 4 || db.MemoryGiB != 8 || db.Compute != 6.8 || db.Note != "disk size unknown" {
+		t.Errorf("db cost = %+v", db)
+	}
+
+	if costs[2].Note != "not found in inventory" || costs[2].Monthly != 0 {
+		t.Errorf("gone cost = %+v", costs[2])
+	}
+	if total.CPUs != 6 || total.MemoryGiB != 12 || total.Monthly != 16.2 {
+		t.Errorf("total = %+v", total)
+	}
+}
+
+func TestOpenStackSize(t *testing.T) {
+	s := newSizer("openstack")
+	s.flavors = []map[string]interface{}{{"id": "f-1", "vcpus": float64(4), "ram": float64(16384), "disk": float64(20)}}
+	s.volumes = []map[string]interface{}{{"id": "v-1", "size": float64(100)}, {"id": "v-2", "size": float64(7)}}
+	vm := map[string]interface{}{
+		"id":              "vm-1",
+		"flavor":          map[string]interface{}{"id": "f-1", "name": "m1.large"},
+		"attachedVolumes": []interface{}{map[string]interface{}{"ID": "v-1"}},
+	}
+
+	cpus, memory, storage := s.size(vm)
+	if cpus != 4 || memory != 16 || storage != 120 {
+		t.Errorf("size() = %d, %g, %g", cpus, memory, storage)
+	}
+}
+
+func TestCostRow(t *testing.T) {
+	row := costRow("TOTAL", Cost{CPUs: 2, MemoryGiB: 4, Monthly: 12.5}, "")
+	if row["total"] != "12.50" || !strings.HasPrefix(row["memoryGiB"].(string), "4.0") {
+		t.Errorf("costRow() = %v", row)
+	}
+}
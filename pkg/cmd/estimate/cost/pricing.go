@@ -0,0 +1,70 @@
+package cost
+
+import (
+	"fmt"
+	"os"
+
+	"sigs.k8s.io/yaml"
+)
+
+// DefaultHoursPerMonth is the number of hours in an average month (365 * 24 / 12)
+const DefaultHoursPerMonth = 730
+
+// Pricing is the price table of a target cluster, read from a YAML or JSON file
+type Pricing struct {
+	// Currency is shown with the costs, e.g. USD
+	Currency string `json:"currency,omitempty"`
+	// HoursPerMonth converts hourly prices to monthly costs (default 730)
+	HoursPerMonth float64 `json:"hoursPerMonth,omitempty"`
+	// CPUPerHour is the price of one vCPU per hour
+	CPUPerHour float64 `json:"cpuPerHour"`
+	// MemoryGiBPerHour is the price of one GiB of memory per hour
+	MemoryGiBPerHour float64 `json:"memoryGiBPerHour"`
+	// StorageGiBPerMonth is the price of one GiB of disk per month
+	StorageGiBPerMonth float64 `json:"storageGiBPerMonth"`
+	// VMPerMonth is a fixed monthly price per VM, e.g. a subscription or license
+	VMPerMonth float64 `json:"vmPerMonth,omitempty"`
+}
+
+// LoadPricing reads a price table and checks its prices
+func LoadPricing(file string) (*Pricing, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pricing file '%s': %v", file, err)
+	}
+	pricing := &Pricing{}
+	if err := yaml.UnmarshalStrict(data, pricing); err != nil {
+		return nil, fmt.Errorf("failed to parse pricing file '%s': %v", file, err)
+	}
+	if err := pricing.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid pricing file '%s': %v", file, err)
+	}
+	return pricing, nil
+}
+
+// Validate checks the prices and fills in the defaults
+func (p *Pricing) Validate() error {
+	if p.HoursPerMonth == 0 {
+		p.HoursPerMonth = DefaultHoursPerMonth
+	}
+	if p.HoursPerMonth < 0 || p.HoursPerMonth > 744 {
+		return fmt.Errorf("hoursPerMonth must be between 0 and 744, got %g", p.HoursPerMonth)
+	}
+	prices := map[string]float64{
+		"cpuPerHour":         p.CPUPerHour,
+		"memoryGiBPerHour":   p.MemoryGiBPerHour,
+		"storageGiBPerMonth": p.StorageGiBPerMonth,
+		"vmPerMonth":         p.VMPerMonth,
+	}
+	set := false
+	for name, price := range prices {
+		if price < 0 {
+			return fmt.Errorf("%s must not be negative", name)
+		}
+		set = set || price > 0
+	}
+	if !set {
+		return fmt.Errorf("no prices set: use cpuPerHour, memoryGiBPerHour, storageGiBPerMonth or vmPerMonth")
+	}
+	return nil
+}
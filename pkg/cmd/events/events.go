@@ -0,0 +1,362 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	"github.com/yaacov/kubectl-mtv/pkg/util/client"
+	"github.com/yaacov/kubectl-mtv/pkg/util/fields"
+)
+
+// dataVolumesGVR is used to find the DataVolumes created for the migrated disks
+var dataVolumesGVR = schema.GroupVersionResource{
+	Group:    "cdi.kubevirt.io",
+	Version:  "v1beta1",
+	Resource: "datavolumes",
+}
+
+// forkliftGroup is the API group of the MTV resources
+const forkliftGroup = "forklift.konveyor.io"
+
+// Event types reported by Kubernetes
+const (
+	TypeNormal  = "Normal"
+	TypeWarning = "Warning"
+)
+
+// Entry is a Kubernetes event normalized into the timeline
+type Entry struct {
+	Time      time.Time `json:"time"`
+	Type      string    `json:"type"`
+	Reason    string    `json:"reason"`
+	Namespace string    `json:"namespace"`
+	Kind      string    `json:"kind"`
+	Name      string    `json:"name"`
+	// Plan and VM are the plan and VM the involved object belongs to, when known
+	Plan    string `json:"plan,omitempty"`
+	VM      string `json:"vm,omitempty"`
+	Message string `json:"message"`
+	Count   int32  `json:"count,omitempty"`
+}
+
+// Options selects the events to show
+type Options struct {
+	ConfigFlags *genericclioptions.ConfigFlags
+	// Namespace of the plans; empty for all namespaces
+	Namespace string
+	// Plan limits the events to one plan and the objects created for it
+	Plan string
+	// VM limits the events to one VM, by name or ID
+	VM string
+	// Types limits the events to these types (Normal, Warning); empty for all
+	Types []string
+}
+
+// owner is the plan and VM an involved object belongs to
+type owner struct {
+	plan string
+	vm   string
+	// mentions is set for plan-wide objects, whose events are kept for a VM only when they name it
+	mentions []string
+}
+
+// related maps "namespace/kind/name" of the involved objects to their owner
+type related map[string]owner
+
+func objectKey(namespace, kind, name string) string {
+	return namespace + "/" + kind + "/" + name
+}
+
+// Collect returns the timeline of the events related to the selected MTV resources
+func Collect(ctx context.Context, opts Options) ([]Entry, error) {
+	c, err := client.GetDynamicClient(opts.ConfigFlags)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get client: %v", err)
+	}
+	clientset, err := client.GetKubernetesClientset(opts.ConfigFlags)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get kubernetes client: %v", err)
+	}
+	return collect(ctx, c, clientset, opts)
+}
+
+func collect(ctx context.Context, c dynamic.Interface, clientset kubernetes.Interface, opts Options) ([]Entry, error) {
+	var plans []unstructured.Unstructured
+	if opts.Plan != "" {
+		plan, err := c.Resource(client.PlansGVR).Namespace(opts.Namespace).Get(ctx, opts.Plan, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get plan '%s': %v", opts.Plan, err)
+		}
+		plans = append(plans, *plan)
+	} else {
+		list, err := c.Resource(client.PlansGVR).Namespace(opts.Namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list plans: %v", err)
+		}
+		plans = list.Items
+	}
+
+	objects := related{}
+	// namespaces are the namespaces whose events are read
+	namespaces := map[string]bool{}
+	if opts.Namespace != "" {
+		namespaces[opts.Namespace] = true
+	}
+	found := false
+	for i := range plans {
+		plan := &plans[i]
+		vms := planVMs(plan)
+		vmID := ""
+		if opts.VM != "" {
+			vmID = matchVM(vms, opts.VM)
+			if vmID == "" {
+				continue
+			}
+		}
+		found = true
+		targetNS := fields.TargetNamespace(plan)
+		namespaces[plan.GetNamespace()] = true
+		namespaces[targetNS] = true
+		addPlanObjects(ctx, c, clientset, objects, plan, vms, vmID, targetNS)
+	}
+	if opts.VM != "" && !found {
+		if opts.Plan != "" {
+			return nil, fmt.Errorf("VM '%s' is not in plan '%s'", opts.VM, opts.Plan)
+		}
+		return nil, fmt.Errorf("VM '%s' is not in any plan", opts.VM)
+	}
+
+	// Without a plan or VM, events of every MTV resource are shown, e.g. of providers and mappings
+	allMTV := opts.Plan == "" && opts.VM == ""
+
+	names := make([]string, 0, len(namespaces))
+	for ns := range namespaces {
+		names = append(names, ns)
+	}
+	sort.Strings(names)
+	if opts.Namespace == "" && allMTV {
+		// All namespaces: read the events once, cluster wide
+		names = []string{""}
+	}
+
+	var entries []Entry
+	seen := map[string]bool{}
+	for _, ns := range names {
+		list, err := clientset.CoreV1().Events(ns).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list events: %v", err)
+		}
+		for i := range list.Items {
+			ev := &list.Items[i]
+			if seen[string(ev.UID)+ev.Name] {
+				continue
+			}
+			seen[string(ev.UID)+ev.Name] = true
+			if entry, ok := normalize(ev, objects, allMTV, opts.VM); ok && matchType(entry.Type, opts.Types) {
+				entries = append(entries, entry)
+			}
+		}
+	}
+
+	sortEntries(entries)
+	return entries, nil
+}
+
+// addPlanObjects records the plan, its migrations and providers, and the pods, PVCs and
+// DataVolumes created for it in the target namespace. With a VM ID only the objects of
+// that VM are recorded; events of plan-wide objects are kept when they name the VM.
+func addPlanObjects(ctx context.Context, c dynamic.Interface, clientset kubernetes.Interface, objects related, plan *unstructured.Unstructured, vms map[string]string, vmID, targetNS string) {
+	planName := plan.GetName()
+	planNS := plan.GetNamespace()
+
+	var mentions []string
+	if vmID != "" {
+		mentions = []string{vmID}
+		if name := vms[vmID]; name != "" {
+			mentions = append(mentions, name)
+		}
+	}
+	planWide := owner{plan: planName, vm: vms[vmID], mentions: mentions}
+
+	objects[objectKey(planNS, "Plan", planName)] = planWide
+	if list, err := c.Resource(client.MigrationsGVR).Namespace(planNS).List(ctx, metav1.ListOptions{}); err == nil {
+		for _, migration := range list.Items {
+			if name, _, _ := unstructured.NestedString(migration.Object, "spec", "plan", "name"); name == planName {
+				objects[objectKey(planNS, "Migration", migration.GetName())] = planWide
+			}
+		}
+	} else {
+		klog.V(1).Infof("Failed to list migrations in '%s': %v", planNS, err)
+	}
+	for _, side := range []string{"source", "destination"} {
+		name, _, _ := unstructured.NestedString(plan.Object, "spec", "provider", side, "name")
+		if name == "" {
+			continue
+		}
+		ns, _, _ := unstructured.NestedString(plan.Object, "spec", "provider", side, "namespace")
+		if ns == "" {
+			ns = planNS
+		}
+		// Providers are shared by plans, a provider of several plans is not shown with a plan
+		key := objectKey(ns, "Provider", name)
+		if shared, ok := objects[key]; ok && shared.plan != planName {
+			objects[key] = owner{mentions: mentions}
+		} else {
+			objects[key] = planWide
+		}
+	}
+
+	selector := fmt.Sprintf("plan=%s", plan.GetUID())
+	if vmID != "" {
+		selector += fmt.Sprintf(",vmID=%s", vmID)
+	}
+	listOpts := metav1.ListOptions{LabelSelector: selector}
+	addObject := func(kind, name string, labels map[string]string) {
+		objects[objectKey(targetNS, kind, name)] = owner{plan: planName, vm: vms[labels["vmID"]]}
+	}
+
+	if list, err := clientset.CoreV1().Pods(targetNS).List(ctx, listOpts); err == nil {
+		for _, pod := range list.Items {
+			addObject("Pod", pod.Name, pod.Labels)
+		}
+	} else {
+		klog.V(1).Infof("Failed to list migration pods in '%s': %v", targetNS, err)
+	}
+	if list, err := clientset.CoreV1().PersistentVolumeClaims(targetNS).List(ctx, listOpts); err == nil {
+		for _, pvc := range list.Items {
+			addObject("PersistentVolumeClaim", pvc.Name, pvc.Labels)
+		}
+	} else {
+		klog.V(1).Infof("Failed to list PVCs in '%s': %v", targetNS, err)
+	}
+	if list, err := c.Resource(dataVolumesGVR).Namespace(targetNS).List(ctx, listOpts); err == nil {
+		for _, dv := range list.Items {
+			addObject("DataVolume", dv.GetName(), dv.GetLabels())
+		}
+	} else {
+		klog.V(1).Infof("Failed to list DataVolumes in '%s': %v", targetNS, err)
+	}
+}
+
+// normalize converts a Kubernetes event into a timeline entry. It returns false for events
+// of objects that are not related to the selected resources. With allMTV set, events of any
+// MTV resource are kept even when no plan is related to them.
+func normalize(ev *corev1.Event, objects related, allMTV bool, vm string) (Entry, bool) {
+	involved := ev.InvolvedObject
+	namespace := involved.Namespace
+	if namespace == "" {
+		namespace = ev.Namespace
+	}
+
+	o, ok := objects[objectKey(namespace, involved.Kind, involved.Name)]
+	if !ok && !(allMTV && strings.HasPrefix(involved.APIVersion, forkliftGroup+"/")) {
+		return Entry{}, false
+	}
+	if vm != "" && len(o.mentions) > 0 && !mentionsAny(ev.Message, o.mentions) {
+		return Entry{}, false
+	}
+
+	return Entry{
+		Time:      eventTime(ev),
+		Type:      ev.Type,
+		Reason:    ev.Reason,
+		Namespace: namespace,
+		Kind:      involved.Kind,
+		Name:      involved.Name,
+		Plan:      o.plan,
+		VM:        o.vm,
+		Message:   strings.TrimSpace(ev.Message),
+		Count:     ev.Count,
+	}, true
+}
+
+// sortEntries orders the entries chronologically
+func sortEntries(entries []Entry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		if !entries[i].Time.Equal(entries[j].Time) {
+			return entries[i].Time.Before(entries[j].Time)
+		}
+		if entries[i].Kind != entries[j].Kind {
+			return entries[i].Kind < entries[j].Kind
+		}
+		return entries[i].Name < entries[j].Name
+	})
+}
+
+// eventTime returns the last time an event was seen, falling back to older timestamps
+func eventTime(ev *corev1.Event) time.Time {
+	switch {
+	case !ev.LastTimestamp.IsZero():
+		return ev.LastTimestamp.Time
+	case !ev.EventTime.IsZero():
+		return ev.EventTime.Time
+	case !ev.FirstTimestamp.IsZero():
+		return ev.FirstTimestamp.Time
+	default:
+		return ev.CreationTimestamp.Time
+	}
+}
+
+// planVMs maps the IDs of the plan VMs to their names
+func planVMs(plan *unstructured.Unstructured) map[string]string {
+	vms := map[string]string{}
+	list, _, _ := unstructured.NestedSlice(plan.Object, "spec", "vms")
+	for _, v := range list {
+		vm, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		id, _, _ := unstructured.NestedString(vm, "id")
+		name, _, _ := unstructured.NestedString(vm, "name")
+		if id != "" {
+			vms[id] = name
+		}
+	}
+	return vms
+}
+
+// matchVM returns the ID of the plan VM with the given name or ID, "" when not in the plan
+func matchVM(vms map[string]string, vm string) string {
+	if _, ok := vms[vm]; ok {
+		return vm
+	}
+	for id, name := range vms {
+		if name == vm {
+			return id
+		}
+	}
+	return ""
+}
+
+func mentionsAny(message string, words []string) bool {
+	for _, w := range words {
+		if strings.Contains(message, w) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchType(eventType string, types []string) bool {
+	if len(types) == 0 {
+		return true
+	}
+	for _, t := range types {
+		if strings.EqualFold(t, eventType) {
+			return true
+		}
+	}
+	return false
+}
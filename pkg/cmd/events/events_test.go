@@ -0,0 +1,156 @@
+package events
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/yaacov/kubectl-mtv/pkg/internal/testutil"
+	"github.com/yaacov/kubectl-mtv/pkg/util/client"
+)
+
+var base = time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+
+func testEvent(name, namespace, apiVersion, kind, object, eventType, reason, message string, offset time.Duration) *corev1.Event {
+	return &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: name, Namespace: namespace, UID: types.UID(name)},
+		InvolvedObject: corev1.ObjectReference{APIVersion: apiVersion, Kind: kind, Name: object, Namespace: namespace},
+		Type:           eventType,
+		Reason:         reason,
+		Message:        message,
+		LastTimestamp:  metav1.NewTime(base.Add(offset)),
+	}
+}
+
+func newClients() (*dynamicfake.FakeDynamicClient, *fake.Clientset) {
+	scheme := runtime.NewScheme()
+	listKinds := map[schema.GroupVersionResource]string{
+		client.PlansGVR:      "PlanList",
+		client.MigrationsGVR: "MigrationList",
+		dataVolumesGVR:       "DataVolumeList",
+	}
+	c := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(scheme, listKinds,
+		testutil.Plan("wave-1", "mtv",
+			testutil.WithUID("plan-uid"),
+			testutil.WithTargetNamespace("apps"),
+			testutil.WithProviders("vcenter", "host"),
+			testutil.WithVMs(testutil.VM("vm-1", "web"), testutil.VM("vm-2", "db")),
+		),
+		testutil.Migration("wave-1-abc", "mtv", testutil.WithPlanRef("wave-1", "mtv")),
+		testutil.DataVolume("web-disk-0", "apps", testutil.WithLabels(map[string]string{"plan": "plan-uid", "vmID": "vm-1"})),
+		testutil.DataVolume("db-disk-0", "apps", testutil.WithLabels(map[string]string{"plan": "plan-uid", "vmID": "vm-2"})),
+	)
+
+	clientset := fake.NewSimpleClientset(
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "wave-1-web-v2v", Namespace: "apps",
+			Labels: map[string]string{"plan": "plan-uid", "vmID": "vm-1"}}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "unrelated", Namespace: "apps"}},
+		testEvent("e1", "mtv", "forklift.konveyor.io/v1beta1", "Plan", "wave-1", TypeNormal, "Started", "Migration started for VMs web, db", 0),
+		testEvent("e2", "apps", "cdi.kubevirt.io/v1beta1", "DataVolume", "web-disk-0", TypeNormal, "ImportSucceeded", "Import done", 3*time.Minute),
+		testEvent("e3", "apps", "v1", "Pod", "wave-1-web-v2v", TypeWarning, "BackOff", "Back-off restarting container", 2*time.Minute),
+		testEvent("e4", "apps", "cdi.kubevirt.io/v1beta1", "DataVolume", "db-disk-0", TypeWarning, "ImportFailed", "Unable to connect", time.Minute),
+		testEvent("e5", "apps", "v1", "Pod", "unrelated", TypeWarning, "BackOff", "Back-off", time.Minute),
+		testEvent("e6", "mtv", "forklift.konveyor.io/v1beta1", "Provider", "other", TypeWarning, "ConnectionFailed", "Connection refused", 4*time.Minute),
+		testEvent("e7", "mtv", "forklift.konveyor.io/v1beta1", "Migration", "wave-1-abc", TypeNormal, "Succeeded", "Migration of db succeeded", 5*time.Minute),
+	)
+	return c, clientset
+}
+
+func reasons(entries []Entry) []string {
+	var result []string
+	for _, e := range entries {
+		result = append(result, e.Reason)
+	}
+	return result
+}
+
+func assertReasons(t *testing.T, entries []Entry, want ...string) {
+	t.Helper()
+	got := reasons(entries)
+	if len(got) != len(want) {
+		t.Fatalf("reasons = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("reasons = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestCollectNamespace(t *testing.T) {
+	c, clientset := newClients()
+	entries, err := collect(context.Background(), c, clientset, Options{Namespace: "mtv"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Events of unrelated pods are left out, events of any MTV resource are kept
+	assertReasons(t, entries, "Started", "ImportFailed", "BackOff", "ImportSucceeded", "ConnectionFailed", "Succeeded")
+
+	byReason := map[string]Entry{}
+	for _, e := range entries {
+		byReason[e.Reason] = e
+	}
+	if e := byReason["BackOff"]; e.Plan != "wave-1" || e.VM != "web" || e.Kind != "Pod" {
+		t.Errorf("pod event = %+v, want plan wave-1 and VM web", e)
+	}
+	if e := byReason["ImportFailed"]; e.VM != "db" {
+		t.Errorf("DataVolume event VM = %q, want db", e.VM)
+	}
+	if e := byReason["ConnectionFailed"]; e.Plan != "" {
+		t.Errorf("unrelated provider event plan = %q, want none", e.Plan)
+	}
+}
+
+func TestCollectPlan(t *testing.T) {
+	c, clientset := newClients()
+	entries, err := collect(context.Background(), c, clientset, Options{Namespace: "mtv", Plan: "wave-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertReasons(t, entries, "Started", "ImportFailed", "BackOff", "ImportSucceeded", "Succeeded")
+
+	entries, err = collect(context.Background(), c, clientset, Options{Namespace: "mtv", Plan: "wave-1", Types: []string{"warning"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertReasons(t, entries, "ImportFailed", "BackOff")
+}
+
+func TestCollectVM(t *testing.T) {
+	c, clientset := newClients()
+	entries, err := collect(context.Background(), c, clientset, Options{Namespace: "mtv", VM: "web"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Plan-wide events are kept when they name the VM
+	assertReasons(t, entries, "Started", "BackOff", "ImportSucceeded")
+
+	entries, err = collect(context.Background(), c, clientset, Options{Namespace: "mtv", Plan: "wave-1", VM: "vm-2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertReasons(t, entries, "Started", "ImportFailed", "Succeeded")
+
+	if _, err := collect(context.Background(), c, clientset, Options{Namespace: "mtv", Plan: "wave-1", VM: "mail"}); err == nil {
+		t.Error("expected an error for a VM that is not in the plan")
+	}
+}
+
+func TestEventTime(t *testing.T) {
+	ev := &corev1.Event{EventTime: metav1.NewMicroTime(base)}
+	if got := eventTime(ev); !got.Equal(base) {
+		t.Errorf("eventTime = %v, want %v", got, base)
+	}
+	ev.LastTimestamp = metav1.NewTime(base.Add(time.Hour))
+	if got := eventTime(ev); !got.Equal(base.Add(time.Hour)) {
+		t.Errorf("eventTime = %v, want the last timestamp", got)
+	}
+}
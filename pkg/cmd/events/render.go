@@ -0,0 +1,104 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/yaacov/kubectl-mtv/pkg/util/output"
+	"github.com/yaacov/kubectl-mtv/pkg/util/watch"
+)
+
+// Print shows the event timeline of the selected resources, refreshed in watch mode
+func Print(ctx context.Context, opts Options, outputFormat string, watchMode bool) error {
+	outputFormat = strings.ToLower(outputFormat)
+	if outputFormat != "table" && outputFormat != "json" && outputFormat != "yaml" {
+		return fmt.Errorf("unsupported output format: %s. Supported formats: table, json, yaml", outputFormat)
+	}
+
+	return watch.WrapWithWatch(watchMode, outputFormat, func() error {
+		entries, err := Collect(ctx, opts)
+		if err != nil {
+			return err
+		}
+		return printEntries(entries, outputFormat, opts)
+	}, watch.DefaultInterval)
+}
+
+func printEntries(entries []Entry, outputFormat string, opts Options) error {
+	emptyMessage := "No events found"
+	switch {
+	case opts.Plan != "":
+		emptyMessage += " for plan " + opts.Plan
+	case opts.Namespace != "":
+		emptyMessage += " in namespace " + opts.Namespace
+	}
+
+	items := make([]map[string]interface{}, 0, len(entries))
+	for _, e := range entries {
+		items = append(items, entryItem(e))
+	}
+
+	switch outputFormat {
+	case "json":
+		return output.PrintJSONWithEmpty(items, emptyMessage)
+	case "yaml":
+		return output.PrintYAMLWithEmpty(items, emptyMessage)
+	}
+
+	for _, item := range items {
+		item["object"] = fmt.Sprintf("%s/%s", item["kind"], item["name"])
+	}
+	columns := []output.Column{
+		{Title: "TIME", Key: "time", ColorFunc: output.FormatTimeCell},
+		{Title: "TYPE", Key: "type", ColorFunc: colorizeType},
+		{Title: "PLAN", Key: "plan"},
+		{Title: "VM", Key: "vm"},
+		{Title: "OBJECT", Key: "object"},
+		{Title: "REASON", Key: "reason", ColorFunc: colorizeReason},
+		{Title: "MESSAGE", Key: "message"},
+	}
+	if opts.Namespace == "" {
+		columns = append([]output.Column{{Title: "NAMESPACE", Key: "namespace"}}, columns...)
+	}
+	return output.PrintTableWithQuery(items, columns, nil, emptyMessage)
+}
+
+func entryItem(e Entry) map[string]interface{} {
+	item := map[string]interface{}{
+		"time":      e.Time.UTC().Format(time.RFC3339),
+		"type":      e.Type,
+		"reason":    e.Reason,
+		"namespace": e.Namespace,
+		"kind":      e.Kind,
+		"name":      e.Name,
+		"plan":      e.Plan,
+		"vm":        e.VM,
+		"message":   e.Message,
+	}
+	if e.Count > 1 {
+		item["count"] = e.Count
+	}
+	return item
+}
+
+func colorizeType(value string) string {
+	if value == TypeWarning {
+		return output.Yellow(value)
+	}
+	return value
+}
+
+// colorizeReason highlights the reasons of failures
+func colorizeReason(value string) string {
+	lower := strings.ToLower(value)
+	switch {
+	case strings.Contains(lower, "fail"), strings.Contains(lower, "error"), strings.Contains(lower, "backoff"):
+		return output.Red(value)
+	case strings.Contains(lower, "succeed"), strings.Contains(lower, "complete"):
+		return output.Green(value)
+	default:
+		return value
+	}
+}
@@ -3,7 +3,6 @@ package conversion
 import (
 	"context"
 	"fmt"
-	"strings"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -57,14 +56,14 @@ func createConversionItem(conv unstructured.Unstructured, useUTC bool) map[strin
 }
 
 // ListConversions lists conversion resources without watch functionality
-func ListConversions(ctx context.Context, configFlags *genericclioptions.ConfigFlags, namespace, outputFormat string, convName string, useUTC bool, query string) error {
+func ListConversions(ctx context.Context, configFlags *genericclioptions.ConfigFlags, namespace, outputFormat string, convName string, useUTC bool, query string, selector string) error {
 	dynamicClient, err := client.GetDynamicClient(configFlags)
 	if err != nil {
 		return fmt.Errorf("failed to get client: %v", err)
 	}
 
-	outputFormat = strings.ToLower(outputFormat)
-	if outputFormat != "table" && outputFormat != "json" && outputFormat != "yaml" && outputFormat != "markdown" {
+	outputFormat = output.NormalizeFormat(outputFormat)
+	if outputFormat != "table" && outputFormat != "json" && outputFormat != "yaml" && outputFormat != "markdown" && !output.IsTemplateFormat(outputFormat) {
 		return fmt.Errorf("unsupported output format: %s. Supported formats: table, json, yaml, markdown", outputFormat)
 	}
 
@@ -73,7 +72,7 @@ func ListConversions(ctx context.Context, configFlags *genericclioptions.ConfigF
 	if convName != "" {
 		allItems, err = getSpecificConversion(ctx, dynamicClient, namespace, convName, useUTC)
 	} else {
-		allItems, err = getAllConversions(ctx, dynamicClient, namespace, useUTC)
+		allItems, err = getAllConversions(ctx, dynamicClient, namespace, selector, useUTC)
 	}
 	if err != nil {
 		return err
@@ -90,6 +89,10 @@ func ListConversions(ctx context.Context, configFlags *genericclioptions.ConfigF
 		}
 	}
 
+	if output.IsTemplateFormat(outputFormat) {
+		return output.PrintTemplate(allItems, outputFormat)
+	}
+
 	switch outputFormat {
 	case "json":
 		return output.PrintJSONWithEmpty(allItems, "No conversions found.")
@@ -100,8 +103,8 @@ func ListConversions(ctx context.Context, configFlags *genericclioptions.ConfigF
 	}
 }
 
-func getAllConversions(ctx context.Context, dynamicClient dynamic.Interface, namespace string, useUTC bool) ([]map[string]interface{}, error) {
-	list, err := dynamicClient.Resource(client.ConversionsGVR).Namespace(namespace).List(ctx, metav1.ListOptions{})
+func getAllConversions(ctx context.Context, dynamicClient dynamic.Interface, namespace string, selector string, useUTC bool) ([]map[string]interface{}, error) {
+	list, err := dynamicClient.Resource(client.ConversionsGVR).Namespace(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list conversions: %v", err)
 	}
@@ -157,8 +160,8 @@ func printConversionOutput(items []map[string]interface{}, outputFormat string)
 }
 
 // List lists conversions with optional watch mode
-func List(ctx context.Context, configFlags *genericclioptions.ConfigFlags, namespace string, watchMode bool, outputFormat string, convName string, useUTC bool, query string) error {
+func List(ctx context.Context, configFlags *genericclioptions.ConfigFlags, namespace string, watchMode bool, outputFormat string, convName string, useUTC bool, query string, selector string) error {
 	return watch.WrapWithWatch(watchMode, outputFormat, func() error {
-		return ListConversions(ctx, configFlags, namespace, outputFormat, convName, useUTC, query)
+		return ListConversions(ctx, configFlags, namespace, outputFormat, convName, useUTC, query, selector)
 	}, watch.DefaultInterval)
 }
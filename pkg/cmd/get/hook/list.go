@@ -4,7 +4,6 @@ import (
 	"context"
 	"encoding/base64"
 	"fmt"
-	"strings"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -94,15 +93,15 @@ func createHookItem(hook unstructured.Unstructured, useUTC bool) map[string]inte
 }
 
 // ListHooks lists hooks without watch functionality
-func ListHooks(ctx context.Context, configFlags *genericclioptions.ConfigFlags, namespace, outputFormat string, hookName string, useUTC bool, query string) error {
+func ListHooks(ctx context.Context, configFlags *genericclioptions.ConfigFlags, namespace, outputFormat string, hookName string, useUTC bool, query string, selector string) error {
 	dynamicClient, err := client.GetDynamicClient(configFlags)
 	if err != nil {
 		return fmt.Errorf("failed to get client: %v", err)
 	}
 
 	// Format validation
-	outputFormat = strings.ToLower(outputFormat)
-	if outputFormat != "table" && outputFormat != "json" && outputFormat != "yaml" && outputFormat != "markdown" {
+	outputFormat = output.NormalizeFormat(outputFormat)
+	if outputFormat != "table" && outputFormat != "json" && outputFormat != "yaml" && outputFormat != "markdown" && !output.IsTemplateFormat(outputFormat) {
 		return fmt.Errorf("unsupported output format: %s. Supported formats: table, json, yaml, markdown", outputFormat)
 	}
 
@@ -113,7 +112,7 @@ func ListHooks(ctx context.Context, configFlags *genericclioptions.ConfigFlags,
 		allItems, err = getSpecificHook(ctx, dynamicClient, namespace, hookName, useUTC)
 	} else {
 		// Get all hooks
-		allItems, err = getAllHooks(ctx, dynamicClient, namespace, useUTC)
+		allItems, err = getAllHooks(ctx, dynamicClient, namespace, selector, useUTC)
 	}
 
 	// Handle error if no items found
@@ -134,6 +133,10 @@ func ListHooks(ctx context.Context, configFlags *genericclioptions.ConfigFlags,
 	}
 
 	// Handle output based on format
+	if output.IsTemplateFormat(outputFormat) {
+		return output.PrintTemplate(allItems, outputFormat)
+	}
+
 	switch outputFormat {
 	case "json":
 		return output.PrintJSONWithEmpty(allItems, "No hooks found.")
@@ -145,8 +148,8 @@ func ListHooks(ctx context.Context, configFlags *genericclioptions.ConfigFlags,
 }
 
 // getAllHooks retrieves all hooks from the given namespace
-func getAllHooks(ctx context.Context, dynamicClient dynamic.Interface, namespace string, useUTC bool) ([]map[string]interface{}, error) {
-	hooks, err := dynamicClient.Resource(client.HooksGVR).Namespace(namespace).List(ctx, metav1.ListOptions{})
+func getAllHooks(ctx context.Context, dynamicClient dynamic.Interface, namespace string, selector string, useUTC bool) ([]map[string]interface{}, error) {
+	hooks, err := dynamicClient.Resource(client.HooksGVR).Namespace(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list hooks: %v", err)
 	}
@@ -272,8 +275,8 @@ func GetHookPlaybookContent(hook unstructured.Unstructured) (string, error) {
 }
 
 // List lists hooks with optional watch mode
-func List(ctx context.Context, configFlags *genericclioptions.ConfigFlags, namespace string, watchMode bool, outputFormat string, hookName string, useUTC bool, query string) error {
+func List(ctx context.Context, configFlags *genericclioptions.ConfigFlags, namespace string, watchMode bool, outputFormat string, hookName string, useUTC bool, query string, selector string) error {
 	return watch.WrapWithWatch(watchMode, outputFormat, func() error {
-		return ListHooks(ctx, configFlags, namespace, outputFormat, hookName, useUTC, query)
+		return ListHooks(ctx, configFlags, namespace, outputFormat, hookName, useUTC, query, selector)
 	}, watch.DefaultInterval)
 }
@@ -102,15 +102,15 @@ func createHostItem(host unstructured.Unstructured, useUTC bool) map[string]inte
 }
 
 // ListHosts lists hosts without watch functionality
-func ListHosts(ctx context.Context, configFlags *genericclioptions.ConfigFlags, namespace, outputFormat string, hostName string, useUTC bool, query string) error {
+func ListHosts(ctx context.Context, configFlags *genericclioptions.ConfigFlags, namespace, outputFormat string, hostName string, useUTC bool, query string, selector string) error {
 	dynamicClient, err := client.GetDynamicClient(configFlags)
 	if err != nil {
 		return fmt.Errorf("failed to get client: %v", err)
 	}
 
 	// Format validation
-	outputFormat = strings.ToLower(outputFormat)
-	if outputFormat != "table" && outputFormat != "json" && outputFormat != "yaml" && outputFormat != "markdown" {
+	outputFormat = output.NormalizeFormat(outputFormat)
+	if outputFormat != "table" && outputFormat != "json" && outputFormat != "yaml" && outputFormat != "markdown" && !output.IsTemplateFormat(outputFormat) {
 		return fmt.Errorf("unsupported output format: %s. Supported formats: table, json, yaml, markdown", outputFormat)
 	}
 
@@ -121,7 +121,7 @@ func ListHosts(ctx context.Context, configFlags *genericclioptions.ConfigFlags,
 		allItems, err = getSpecificHost(ctx, dynamicClient, namespace, hostName, useUTC)
 	} else {
 		// Get all hosts
-		allItems, err = getAllHosts(ctx, dynamicClient, namespace, useUTC)
+		allItems, err = getAllHosts(ctx, dynamicClient, namespace, selector, useUTC)
 	}
 
 	// Handle error if no items found
@@ -142,6 +142,10 @@ func ListHosts(ctx context.Context, configFlags *genericclioptions.ConfigFlags,
 	}
 
 	// Handle output based on format
+	if output.IsTemplateFormat(outputFormat) {
+		return output.PrintTemplate(allItems, outputFormat)
+	}
+
 	switch outputFormat {
 	case "json":
 		return output.PrintJSONWithEmpty(allItems, "No hosts found.")
@@ -153,8 +157,8 @@ func ListHosts(ctx context.Context, configFlags *genericclioptions.ConfigFlags,
 }
 
 // getAllHosts retrieves all hosts from the given namespace
-func getAllHosts(ctx context.Context, dynamicClient dynamic.Interface, namespace string, useUTC bool) ([]map[string]interface{}, error) {
-	hosts, err := dynamicClient.Resource(client.HostsGVR).Namespace(namespace).List(ctx, metav1.ListOptions{})
+func getAllHosts(ctx context.Context, dynamicClient dynamic.Interface, namespace string, selector string, useUTC bool) ([]map[string]interface{}, error) {
+	hosts, err := dynamicClient.Resource(client.HostsGVR).Namespace(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list hosts: %v", err)
 	}
@@ -243,8 +247,8 @@ func printHostOutput(items []map[string]interface{}, outputFormat string) error
 }
 
 // List lists hosts with optional watch mode
-func List(ctx context.Context, configFlags *genericclioptions.ConfigFlags, namespace string, watchMode bool, outputFormat string, hostName string, useUTC bool, query string) error {
+func List(ctx context.Context, configFlags *genericclioptions.ConfigFlags, namespace string, watchMode bool, outputFormat string, hostName string, useUTC bool, query string, selector string) error {
 	return watch.WrapWithWatch(watchMode, outputFormat, func() error {
-		return ListHosts(ctx, configFlags, namespace, outputFormat, hostName, useUTC, query)
+		return ListHosts(ctx, configFlags, namespace, outputFormat, hostName, useUTC, query, selector)
 	}, watch.DefaultInterval)
 }
@@ -3,7 +3,6 @@ package inventory
 import (
 	"context"
 	"fmt"
-	"strings"
 
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 
@@ -52,8 +51,8 @@ func ListJobTemplates(ctx context.Context, configFlags *genericclioptions.Config
 		return fmt.Errorf("error applying query: %v", err)
 	}
 
-	outputFormat = strings.ToLower(outputFormat)
-	if outputFormat != "table" && outputFormat != "json" && outputFormat != "yaml" && outputFormat != "markdown" {
+	outputFormat = output.NormalizeFormat(outputFormat)
+	if outputFormat != "table" && outputFormat != "json" && outputFormat != "yaml" && outputFormat != "markdown" && !output.IsTemplateFormat(outputFormat) {
 		return fmt.Errorf("unsupported output format: %s. Supported formats: table, json, yaml, markdown", outputFormat)
 	}
 
@@ -63,6 +62,10 @@ func ListJobTemplates(ctx context.Context, configFlags *genericclioptions.Config
 	}
 
 	emptyMessage := "No AAP job templates found (is AAP configured on ForkliftController?)"
+	if output.IsTemplateFormat(outputFormat) {
+		return output.PrintTemplate(items, outputFormat)
+	}
+
 	switch outputFormat {
 	case "json":
 		return output.PrintJSONWithEmpty(items, emptyMessage)
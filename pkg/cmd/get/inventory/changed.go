@@ -0,0 +1,237 @@
+package inventory
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/klog/v2"
+
+	"github.com/yaacov/kubectl-mtv/pkg/util/flags"
+)
+
+// ChangedSinceHelp describes the values accepted by ParseChangedSince
+const ChangedSinceHelp = "a duration before now (e.g. 24h, 7d) or a time (e.g. 2026-03-01T08:00:00Z, \"2026-03-01 08:00\")"
+
+// changeTimeFields are the inventory fields holding the last update time of a VM, by provider
+// type, most recent first. Providers not listed only report a revision counter.
+var changeTimeFields = map[string][][]string{
+	"openstack": {{"updated"}, {"created"}},
+	"openshift": {{"object", "metadata", "creationTimestamp"}},
+	"ec2":       {{"LaunchTime"}},
+	"azure":     {{"object", "properties", "timeCreated"}},
+}
+
+// ParseChangedSince parses a --changed-since value: a duration before now, e.g. "24h" or
+// "7d", or a point in time such as "2026-03-01 08:00". The time must not be in the future.
+func ParseChangedSince(value string, now time.Time) (time.Time, error) {
+	if d, err := flags.ParseRelativeDuration(value); err == nil {
+		return now.Add(-d), nil
+	}
+	t, err := flags.ParseTimeValue(value, now)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --changed-since '%s': use %s", value, ChangedSinceHelp)
+	}
+	if t.After(now) {
+		return time.Time{}, fmt.Errorf("invalid --changed-since '%s': the time is in the future", value)
+	}
+	return t, nil
+}
+
+// RevisionJournal records when the inventory revision of each object was first seen, so
+// providers that report a revision counter but no update time can be filtered by change time
+type RevisionJournal struct {
+	// Baseline is when the journal was created; revisions seen then have no known change time
+	Baseline time.Time                 `json:"baseline"`
+	Objects  map[string]RevisionRecord `json:"objects"`
+}
+
+// RevisionRecord is the last seen revision of an object and when it was first seen
+type RevisionRecord struct {
+	Revision int64 `json:"revision"`
+	// Since is zero for revisions recorded in the baseline
+	Since time.Time `json:"since,omitempty"`
+}
+
+// RevisionJournalDir returns the revision journal directory in the user cache directory
+func RevisionJournalDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find user cache directory: %v", err)
+	}
+	return filepath.Join(dir, "kubectl-mtv", "inventory-revisions"), nil
+}
+
+// revisionJournalPath returns the journal file of the VMs of a provider
+func revisionJournalPath(namespace, providerName string) (string, error) {
+	dir, err := RevisionJournalDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, fmt.Sprintf("%s_%s_vms.json", namespace, providerName)), nil
+}
+
+// LoadRevisionJournal reads a revision journal; a missing file is an empty journal
+func LoadRevisionJournal(path string) (*RevisionJournal, error) {
+	journal := &RevisionJournal{Objects: map[string]RevisionRecord{}}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return journal, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read revision journal: %v", err)
+	}
+	if err := json.Unmarshal(data, journal); err != nil {
+		return nil, fmt.Errorf("failed to parse revision journal %s: %v", path, err)
+	}
+	if journal.Objects == nil {
+		journal.Objects = map[string]RevisionRecord{}
+	}
+	return journal, nil
+}
+
+// Save writes a revision journal
+func (j *RevisionJournal) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create revision journal directory: %v", err)
+	}
+	data, err := json.MarshalIndent(j, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode revision journal: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write revision journal: %v", err)
+	}
+	return nil
+}
+
+// Observe records the current revisions of the objects. A new object or a new revision
+// is recorded as changed at now, except in the first observation, which is the baseline.
+// Objects no longer in the inventory are forgotten. It reports whether the journal changed.
+func (j *RevisionJournal) Observe(objects []map[string]interface{}, now time.Time) bool {
+	baseline := j.Baseline.IsZero()
+	if baseline {
+		j.Baseline = now
+	}
+
+	changed := baseline
+	seen := map[string]bool{}
+	for _, object := range objects {
+		id, _ := object["id"].(string)
+		revision, ok := object["revision"]
+		if id == "" || !ok {
+			continue
+		}
+		seen[id] = true
+		current := int64(inventoryNumber(revision))
+		if record, ok := j.Objects[id]; ok && record.Revision == current {
+			continue
+		}
+		record := RevisionRecord{Revision: current}
+		if !baseline {
+			record.Since = now
+		}
+		j.Objects[id] = record
+		changed = true
+	}
+	for id := range j.Objects {
+		if !seen[id] {
+			delete(j.Objects, id)
+			changed = true
+		}
+	}
+	return changed
+}
+
+// ChangeTime returns the last change time of an inventory VM: the update time reported by
+// the provider, or when its current revision was first seen. It returns false when unknown.
+func ChangeTime(vm map[string]interface{}, providerType string, journal *RevisionJournal) (time.Time, bool) {
+	// KubeVirt VMs are changed by field managers long after they are created
+	if providerType == "openshift" {
+		if t, ok := latestManagedFieldsTime(vm); ok {
+			return t, true
+		}
+	}
+	for _, path := range changeTimeFields[providerType] {
+		value, found, _ := unstructured.NestedFieldNoCopy(vm, path...)
+		s, ok := value.(string)
+		if !found || !ok || s == "" {
+			continue
+		}
+		if t, err := time.Parse(time.RFC3339, s); err == nil {
+			return t, true
+		}
+	}
+
+	if journal == nil {
+		return time.Time{}, false
+	}
+	id, _ := vm["id"].(string)
+	record, ok := journal.Objects[id]
+	if !ok || record.Since.IsZero() {
+		return time.Time{}, false
+	}
+	return record.Since, true
+}
+
+// latestManagedFieldsTime returns the last time a field manager updated a KubeVirt VM
+func latestManagedFieldsTime(vm map[string]interface{}) (time.Time, bool) {
+	entries, _, _ := unstructured.NestedSlice(vm, "object", "metadata", "managedFields")
+	var latest time.Time
+	for _, e := range entries {
+		entry, ok := e.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		s, _ := entry["time"].(string)
+		if t, err := time.Parse(time.RFC3339, s); err == nil && t.After(latest) {
+			latest = t
+		}
+	}
+	return latest, !latest.IsZero()
+}
+
+// hasChangeTimes reports whether a provider type reports update times of its VMs
+func hasChangeTimes(providerType string) bool {
+	_, ok := changeTimeFields[providerType]
+	return ok
+}
+
+// recordRevisions updates the revision journal of the VMs of a provider and returns it.
+// Journal errors only cost the change times of providers without update times, so they
+// are logged and the listing goes on.
+func recordRevisions(vms []map[string]interface{}, namespace, providerName string, now time.Time) *RevisionJournal {
+	path, err := revisionJournalPath(namespace, providerName)
+	if err != nil {
+		klog.V(1).Infof("Not recording inventory revisions: %v", err)
+		return nil
+	}
+	journal, err := LoadRevisionJournal(path)
+	if err != nil {
+		klog.V(1).Infof("Not recording inventory revisions: %v", err)
+		return nil
+	}
+	if journal.Observe(vms, now) {
+		if err := journal.Save(path); err != nil {
+			klog.V(1).Infof("Failed to save inventory revisions: %v", err)
+		}
+	}
+	return journal
+}
+
+// filterChangedSince keeps the VMs changed after since, adding their change time as lastChanged
+func filterChangedSince(vms []map[string]interface{}, providerType string, journal *RevisionJournal, since time.Time) []map[string]interface{} {
+	filtered := make([]map[string]interface{}, 0, len(vms))
+	for _, vm := range vms {
+		t, ok := ChangeTime(vm, providerType, journal)
+		if !ok || !t.After(since) {
+			continue
+		}
+		vm["lastChanged"] = t.UTC().Format(time.RFC3339)
+		filtered = append(filtered, vm)
+	}
+	return filtered
+}
@@ -0,0 +1,109 @@
+package inventory
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParseChangedSince(t *testing.T) {
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value   string
+		want    time.Time
+		wantErr bool
+	}{
+		{value: "24h", want: now.Add(-24 * time.Hour)},
+		{value: "7d", want: now.AddDate(0, 0, -7)},
+		{value: "2026-03-01T08:00:00Z", want: time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)},
+		{value: "2026-03-01 08:00", want: time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)},
+		{value: "2026-04-01T08:00:00Z", wantErr: true},
+		{value: "yesterday", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseChangedSince(tt.value, now)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseChangedSince(%q) = %v, want an error", tt.value, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseChangedSince(%q) failed: %v", tt.value, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("ParseChangedSince(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func revisionVM(id string, revision float64) map[string]interface{} {
+	return map[string]interface{}{"id": id, "name": id, "revision": revision}
+}
+
+func TestRevisionJournal(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.json")
+	t0 := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+	t1 := t0.Add(24 * time.Hour)
+
+	journal, err := LoadRevisionJournal(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !journal.Observe([]map[string]interface{}{revisionVM("vm-1", 3), revisionVM("vm-2", 5)}, t0) {
+		t.Fatal("the baseline should change the journal")
+	}
+	if err := journal.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	journal, err = LoadRevisionJournal(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if journal.Observe([]map[string]interface{}{revisionVM("vm-1", 3), revisionVM("vm-2", 5)}, t1) {
+		t.Error("unchanged revisions should not change the journal")
+	}
+
+	vms := []map[string]interface{}{revisionVM("vm-1", 3), revisionVM("vm-2", 6), revisionVM("vm-3", 1)}
+	if !journal.Observe(vms, t1) {
+		t.Fatal("a new revision should change the journal")
+	}
+
+	changed := filterChangedSince(vms, "vsphere", journal, t0)
+	if len(changed) != 2 || changed[0]["id"] != "vm-2" || changed[1]["id"] != "vm-3" {
+		t.Fatalf("changed VMs = %v, want vm-2 and vm-3", changed)
+	}
+	if changed[0]["lastChanged"] != "2026-03-02T08:00:00Z" {
+		t.Errorf("lastChanged = %v, want the time the revision was first seen", changed[0]["lastChanged"])
+	}
+
+	// Removed VMs are forgotten
+	journal.Observe([]map[string]interface{}{revisionVM("vm-1", 3)}, t1)
+	if _, ok := journal.Objects["vm-2"]; ok {
+		t.Error("a removed VM should be forgotten")
+	}
+}
+
+func TestChangeTime(t *testing.T) {
+	openstack := map[string]interface{}{"id": "a", "updated": "2026-03-01T10:00:00Z", "created": "2025-01-01T00:00:00Z"}
+	if got, ok := ChangeTime(openstack, "openstack", nil); !ok || !got.Equal(time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("openstack change time = %v %v, want the update time", got, ok)
+	}
+
+	kubevirt := map[string]interface{}{"id": "b", "object": map[string]interface{}{"metadata": map[string]interface{}{
+		"creationTimestamp": "2025-01-01T00:00:00Z",
+		"managedFields": []interface{}{
+			map[string]interface{}{"manager": "virt-controller", "time": "2026-03-01T09:00:00Z"},
+			map[string]interface{}{"manager": "kubectl", "time": "2026-02-01T09:00:00Z"},
+		},
+	}}}
+	if got, ok := ChangeTime(kubevirt, "openshift", nil); !ok || !got.Equal(time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)) {
+		t.Errorf("openshift change time = %v %v, want the last managed fields time", got, ok)
+	}
+
+	if _, ok := ChangeTime(revisionVM("vm-1", 3), "vsphere", nil); ok {
+		t.Error("a vSphere VM without a journal should have no change time")
+	}
+}
@@ -90,6 +90,20 @@ func (pc *ProviderClient) GetResourceCollection(ctx context.Context, collection
 	return pc.GetResourceWithQuery(ctx, collection, fmt.Sprintf("detail=%d", detail))
 }
 
+// StreamResourceCollection fetches a collection of resources and calls fn with each resource
+// as it is decoded, without holding the whole collection in memory
+func (pc *ProviderClient) StreamResourceCollection(ctx context.Context, collection string, detail int, fn func(map[string]interface{}) error) error {
+	if err := pc.checkProviderReady(); err != nil {
+		return err
+	}
+
+	resourcePath := fmt.Sprintf("%s?detail=%d", collection, detail)
+	klog.V(2).Infof("Streaming inventory from provider %s/%s - path: %s, baseURL: %s, insecure=%v",
+		pc.GetProviderNamespace(), pc.GetProviderName(), resourcePath, pc.inventoryURL, pc.insecureSkipTLS)
+
+	return client.StreamProviderInventoryWithInsecure(ctx, pc.configFlags, pc.inventoryURL, pc.provider, resourcePath, pc.insecureSkipTLS, fn)
+}
+
 // GetResourceByID fetches a specific resource by ID
 func (pc *ProviderClient) GetResourceByID(ctx context.Context, collection, id string, detail int) (interface{}, error) {
 	return pc.GetResourceWithQuery(ctx, fmt.Sprintf("%s/%s", collection, id), fmt.Sprintf("detail=%d", detail))
@@ -124,6 +138,10 @@ func (pc *ProviderClient) GetVMs(ctx context.Context, detail int) (interface{},
 	return pc.GetResourceCollection(ctx, "vms", detail)
 }
 
+func (pc *ProviderClient) StreamVMs(ctx context.Context, detail int, fn func(map[string]interface{}) error) error {
+	return pc.StreamResourceCollection(ctx, "vms", detail, fn)
+}
+
 func (pc *ProviderClient) GetVM(ctx context.Context, id string, detail int) (interface{}, error) {
 	return pc.GetResourceByID(ctx, "vms", id, detail)
 }
@@ -93,6 +93,10 @@ func listClustersOnce(ctx context.Context, kubeConfigFlags *genericclioptions.Co
 
 	// Format and display the results
 	emptyMessage := fmt.Sprintf("No clusters found for provider %s", providerName)
+	if output.IsTemplateFormat(outputFormat) {
+		return output.PrintTemplate(data, outputFormat)
+	}
+
 	switch outputFormat {
 	case "json":
 		return output.PrintJSONWithEmpty(data, emptyMessage)
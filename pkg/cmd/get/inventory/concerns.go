@@ -0,0 +1,216 @@
+package inventory
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/yaacov/kubectl-mtv/pkg/util/output"
+)
+
+// concernSeverityRank orders concern categories from most to least severe
+var concernSeverityRank = map[string]int{
+	"Critical":    0,
+	"Warning":     1,
+	"Information": 2,
+}
+
+// concernRank returns the sort rank of a concern category, unknown categories sort last
+func concernRank(category string) int {
+	if rank, ok := concernSeverityRank[category]; ok {
+		return rank
+	}
+	return len(concernSeverityRank)
+}
+
+// vmConcerns returns the concern maps of a VM
+func vmConcerns(vm map[string]interface{}) []map[string]interface{} {
+	concernsArray, _ := vm["concerns"].([]interface{})
+	concerns := make([]map[string]interface{}, 0, len(concernsArray))
+	for _, c := range concernsArray {
+		if concern, ok := c.(map[string]interface{}); ok {
+			concerns = append(concerns, concern)
+		}
+	}
+	return concerns
+}
+
+// concernLabel returns the human readable label of a concern
+func concernLabel(concern map[string]interface{}) string {
+	if label, ok := concern["label"].(string); ok && label != "" {
+		return label
+	}
+	if id, ok := concern["id"].(string); ok && id != "" {
+		return id
+	}
+	if assessment, ok := concern["assessment"].(string); ok {
+		return assessment
+	}
+	return ""
+}
+
+// augmentConcernDetails adds the most severe concern and the list of concern labels to a VM
+func augmentConcernDetails(vm map[string]interface{}) {
+	concerns := vmConcerns(vm)
+	sort.SliceStable(concerns, func(i, j int) bool {
+		ci, _ := concerns[i]["category"].(string)
+		cj, _ := concerns[j]["category"].(string)
+		return concernRank(ci) < concernRank(cj)
+	})
+
+	seen := map[string]bool{}
+	labels := []string{}
+	for _, concern := range concerns {
+		label := concernLabel(concern)
+		if label == "" || seen[label] {
+			continue
+		}
+		seen[label] = true
+		labels = append(labels, label)
+	}
+
+	vm["topConcern"] = ""
+	if len(labels) > 0 {
+		vm["topConcern"] = labels[0]
+	}
+	vm["concernLabels"] = strings.Join(labels, ", ")
+}
+
+// filterVMsWithConcerns keeps only VMs that have concerns, ordered by severity breakdown
+func filterVMsWithConcerns(vms []map[string]interface{}) []map[string]interface{} {
+	filtered := make([]map[string]interface{}, 0, len(vms))
+	for _, vm := range vms {
+		if len(vmConcerns(vm)) == 0 {
+			continue
+		}
+		augmentConcernDetails(vm)
+		filtered = append(filtered, vm)
+	}
+
+	count := func(vm map[string]interface{}, key string) int {
+		n, _ := vm[key].(int)
+		return n
+	}
+	sort.SliceStable(filtered, func(i, j int) bool {
+		for _, key := range []string{"criticalConcerns", "warningConcerns", "infoConcerns"} {
+			if a, b := count(filtered[i], key), count(filtered[j], key); a != b {
+				return a > b
+			}
+		}
+		ni, _ := filtered[i]["name"].(string)
+		nj, _ := filtered[j]["name"].(string)
+		return ni < nj
+	})
+
+	return filtered
+}
+
+// summarizeConcerns groups concerns across VMs by severity and label
+func summarizeConcerns(vms []map[string]interface{}) []map[string]interface{} {
+	type key struct{ category, label string }
+	vmNames := map[key][]string{}
+
+	for _, vm := range vms {
+		name, _ := vm["name"].(string)
+		seen := map[key]bool{}
+		for _, concern := range vmConcerns(vm) {
+			category, _ := concern["category"].(string)
+			k := key{category: category, label: concernLabel(concern)}
+			if seen[k] {
+				continue
+			}
+			seen[k] = true
+			vmNames[k] = append(vmNames[k], name)
+		}
+	}
+
+	keys := make([]key, 0, len(vmNames))
+	for k := range vmNames {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if ri, rj := concernRank(keys[i].category), concernRank(keys[j].category); ri != rj {
+			return ri < rj
+		}
+		if ci, cj := len(vmNames[keys[i]]), len(vmNames[keys[j]]); ci != cj {
+			return ci > cj
+		}
+		return keys[i].label < keys[j].label
+	})
+
+	summary := make([]map[string]interface{}, 0, len(keys))
+	for _, k := range keys {
+		names := vmNames[k]
+		sort.Strings(names)
+		summary = append(summary, map[string]interface{}{
+			"severity": k.category,
+			"concern":  k.label,
+			"vmCount":  len(names),
+			"vmsHuman": strings.Join(names, ", "),
+		})
+	}
+	return summary
+}
+
+// colorizeConcernSeverity colors a concern category by severity
+func colorizeConcernSeverity(category string) string {
+	switch category {
+	case "Critical":
+		return output.Red(category)
+	case "Warning":
+		return output.Yellow(category)
+	default:
+		return output.Blue(category)
+	}
+}
+
+// colorizeCount colors a non-zero count with the given color function
+func colorizeCount(colorFunc func(string) string) func(string) string {
+	return func(val string) string {
+		if val == "" || val == "0" {
+			return val
+		}
+		return colorFunc(val)
+	}
+}
+
+// vmConcernColumns returns the table columns for the concerns-only VM listing
+func vmConcernColumns() []output.Column {
+	return []output.Column{
+		{Title: "NAME", Key: "name"},
+		{Title: "POWER", Key: "powerStateHuman", ColorFunc: output.ColorizePowerState},
+		{Title: "CRITICAL", Key: "criticalConcerns", ColorFunc: colorizeCount(output.Red)},
+		{Title: "WARNING", Key: "warningConcerns", ColorFunc: colorizeCount(output.Yellow)},
+		{Title: "INFO", Key: "infoConcerns"},
+		{Title: "TOP CONCERN", Key: "topConcern", MaxWidth: 50},
+		{Title: "CONCERNS", Key: "concernLabels", MaxWidth: 80},
+	}
+}
+
+// concernSummaryColumns returns the table columns for the grouped concern summary
+func concernSummaryColumns() []output.Column {
+	return []output.Column{
+		{Title: "SEVERITY", Key: "severity", ColorFunc: colorizeConcernSeverity},
+		{Title: "CONCERN", Key: "concern", MaxWidth: 60},
+		{Title: "VMS", Key: "vmCount"},
+		{Title: "AFFECTED", Key: "vmsHuman", MaxWidth: 80},
+	}
+}
+
+// printConcernSummary prints the concerns grouped by severity and label below the VM table
+func printConcernSummary(vms []map[string]interface{}, markdown bool) error {
+	summary := summarizeConcerns(vms)
+	if len(summary) == 0 {
+		return nil
+	}
+
+	fmt.Println()
+	printer := output.NewTablePrinter().WithColumns(concernSummaryColumns()...).AddItems(summary)
+	if markdown {
+		fmt.Println("## Concerns by severity")
+		fmt.Println()
+		return printer.PrintMarkdown()
+	}
+	fmt.Println("CONCERNS BY SEVERITY")
+	return printer.Print()
+}
@@ -0,0 +1,57 @@
+package inventory
+
+import "testing"
+
+func concernVM(name string, concerns ...map[string]interface{}) map[string]interface{} {
+	items := make([]interface{}, 0, len(concerns))
+	for _, c := range concerns {
+		items = append(items, c)
+	}
+	vm := map[string]interface{}{"name": name, "concerns": items}
+	augmentVMInfo(vm)
+	return vm
+}
+
+func TestFilterVMsWithConcerns(t *testing.T) {
+	vms := []map[string]interface{}{
+		concernVM("clean"),
+		concernVM("warn", map[string]interface{}{"category": "Warning", "label": "Changed Block Tracking (CBT) not enabled"}),
+		concernVM("crit",
+			map[string]interface{}{"category": "Information", "label": "VM has snapshots"},
+			map[string]interface{}{"category": "Critical", "label": "Shareable disk detected"},
+		),
+	}
+
+	filtered := filterVMsWithConcerns(vms)
+	if len(filtered) != 2 {
+		t.Fatalf("filtered = %d VMs, want 2", len(filtered))
+	}
+	if filtered[0]["name"] != "crit" || filtered[1]["name"] != "warn" {
+		t.Errorf("order = %v, %v; want crit, warn", filtered[0]["name"], filtered[1]["name"])
+	}
+	if filtered[0]["topConcern"] != "Shareable disk detected" {
+		t.Errorf("topConcern = %v", filtered[0]["topConcern"])
+	}
+	if filtered[0]["concernLabels"] != "Shareable disk detected, VM has snapshots" {
+		t.Errorf("concernLabels = %v", filtered[0]["concernLabels"])
+	}
+}
+
+func TestSummarizeConcerns(t *testing.T) {
+	snapshots := map[string]interface{}{"category": "Information", "label": "VM has snapshots"}
+	vms := []map[string]interface{}{
+		concernVM("a", snapshots, map[string]interface{}{"category": "Critical", "label": "Shareable disk detected"}),
+		concernVM("b", snapshots, snapshots),
+	}
+
+	summary := summarizeConcerns(vms)
+	if len(summary) != 2 {
+		t.Fatalf("summary = %d rows, want 2", len(summary))
+	}
+	if summary[0]["severity"] != "Critical" || summary[0]["vmCount"] != 1 {
+		t.Errorf("first row = %v, want the critical concern", summary[0])
+	}
+	if summary[1]["vmCount"] != 2 || summary[1]["vmsHuman"] != "a, b" {
+		t.Errorf("second row = %v, want snapshots on a, b", summary[1])
+	}
+}
@@ -74,6 +74,10 @@ func listDataCentersOnce(ctx context.Context, kubeConfigFlags *genericclioptions
 
 	// Format and display the results
 	emptyMessage := fmt.Sprintf("No datacenters found for provider %s", providerName)
+	if output.IsTemplateFormat(outputFormat) {
+		return output.PrintTemplate(data, outputFormat)
+	}
+
 	switch outputFormat {
 	case "json":
 		return output.PrintJSONWithEmpty(data, emptyMessage)
@@ -0,0 +1,148 @@
+package inventory
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/yaacov/kubectl-mtv/pkg/util/output"
+)
+
+// attachDatastoreVMs nests under each datastore the VMs with disks on it, by the size of
+// their disks on that datastore, largest first, and adds per-datastore totals: the VM
+// count and the disk size of all its VMs. When largest is positive only the largest VMs
+// are nested, with the share of the datastore they hold, to spot the VMs that would
+// saturate a storage backend when migrated together.
+func attachDatastoreVMs(datastores []map[string]interface{}, vms []map[string]interface{}, largest int) {
+	byID := make(map[string]map[string]interface{}, len(datastores))
+	for _, datastore := range datastores {
+		if id, ok := datastore["id"].(string); ok {
+			byID[id] = datastore
+		}
+	}
+
+	grouped := make(map[string][]map[string]interface{})
+	for _, vm := range vms {
+		onDatastore := map[string]map[string]interface{}{}
+		disks, _ := vm["disks"].([]interface{})
+		for _, d := range disks {
+			disk, ok := d.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			ref, _ := disk["datastore"].(map[string]interface{})
+			id, _ := ref["id"].(string)
+			if _, ok := byID[id]; !ok {
+				continue
+			}
+			entry, ok := onDatastore[id]
+			if !ok {
+				entry = map[string]interface{}{"name": vm["name"], "id": vm["id"], "diskCount": 0, "diskBytes": 0.0, "largestDiskBytes": 0.0}
+				onDatastore[id] = entry
+				grouped[id] = append(grouped[id], entry)
+			}
+			size := inventoryNumber(disk["capacity"])
+			entry["diskCount"] = entry["diskCount"].(int) + 1
+			entry["diskBytes"] = entry["diskBytes"].(float64) + size
+			entry["largestDiskBytes"] = math.Max(entry["largestDiskBytes"].(float64), size)
+		}
+	}
+
+	for id, datastore := range byID {
+		children := grouped[id]
+		sort.SliceStable(children, func(i, j int) bool {
+			bi, bj := children[i]["diskBytes"].(float64), children[j]["diskBytes"].(float64)
+			if bi != bj {
+				return bi > bj
+			}
+			return fmt.Sprint(children[i]["name"]) < fmt.Sprint(children[j]["name"])
+		})
+		addDatastoreVMTotals(datastore, children, largest)
+	}
+}
+
+// addDatastoreVMTotals sets the nested VMs of a datastore and their totals
+func addDatastoreVMTotals(datastore map[string]interface{}, vms []map[string]interface{}, largest int) {
+	var total float64
+	for _, vm := range vms {
+		total += vm["diskBytes"].(float64)
+	}
+
+	shown := vms
+	if largest > 0 && len(shown) > largest {
+		shown = shown[:largest]
+	}
+	nested := make([]interface{}, 0, len(shown))
+	var shownBytes float64
+	for _, vm := range shown {
+		bytes := vm["diskBytes"].(float64)
+		shownBytes += bytes
+		vm["diskHuman"] = humanizeBytes(bytes)
+		vm["largestDiskHuman"] = humanizeBytes(vm["largestDiskBytes"].(float64))
+		vm["sharePercent"], vm["shareHuman"] = vmDiskShare(bytes, total)
+		nested = append(nested, vm)
+	}
+
+	datastore["vms"] = nested
+	datastore["vmCount"] = len(vms)
+	datastore["vmCountHuman"] = fmt.Sprintf("%d", len(vms))
+	if len(shown) < len(vms) {
+		datastore["vmCountHuman"] = fmt.Sprintf("%d (top %d)", len(vms), len(shown))
+	}
+	datastore["vmDiskBytes"] = total
+	datastore["vmDiskHuman"] = humanizeBytes(total)
+	datastore["topVMsSharePercent"], datastore["shareHuman"] = vmDiskShare(shownBytes, total)
+}
+
+// vmDiskShare returns the share of the VM disks on a datastore held by some of its VMs,
+// as a percentage and as text
+func vmDiskShare(bytes, total float64) (float64, string) {
+	if total <= 0 {
+		return 0, ""
+	}
+	share := math.Round(bytes/total*1000) / 10
+	return share, fmt.Sprintf("%.1f%%", share)
+}
+
+// datastoreTreeRows flattens datastores and their nested VMs into table rows, each
+// datastore followed by its VMs drawn as tree branches
+func datastoreTreeRows(datastores []map[string]interface{}) []map[string]interface{} {
+	var rows []map[string]interface{}
+	for _, datastore := range datastores {
+		rows = append(rows, datastore)
+		vms, _ := datastore["vms"].([]interface{})
+		for i, item := range vms {
+			vm, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			prefix := treeBranch
+			if i == len(vms)-1 {
+				prefix = treeLast
+			}
+			rows = append(rows, map[string]interface{}{
+				"name":             prefix + fmt.Sprint(vm["name"]),
+				"id":               vm["id"],
+				"vmCountHuman":     fmt.Sprintf("%d disks", vm["diskCount"]),
+				"vmDiskHuman":      vm["diskHuman"],
+				"largestDiskHuman": vm["largestDiskHuman"],
+				"shareHuman":       vm["shareHuman"],
+			})
+		}
+	}
+	return rows
+}
+
+// datastoreTreeHeaders are the table columns of the datastores with their VMs; VM rows
+// show their disk count in VMS, their disks on the datastore in VM DISKS, and in SHARE
+// the part of the datastore VM disks they hold (the top VMs together on datastore rows)
+var datastoreTreeHeaders = []output.Column{
+	{Title: "NAME", Key: "name"},
+	{Title: "ID", Key: "id"},
+	{Title: "CAPACITY", Key: "capacityFormatted"},
+	{Title: "FREE", Key: "freeSpaceFormatted"},
+	{Title: "VMS", Key: "vmCountHuman"},
+	{Title: "VM DISKS", Key: "vmDiskHuman"},
+	{Title: "LARGEST DISK", Key: "largestDiskHuman"},
+	{Title: "SHARE", Key: "shareHuman"},
+}
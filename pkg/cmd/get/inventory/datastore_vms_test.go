@@ -0,0 +1,49 @@
+package inventory
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAttachDatastoreVMs(t *testing.T) {
+	const gib = 1024 * 1024 * 1024
+	disk := func(datastore string, gb float64) interface{} {
+		return map[string]interface{}{"datastore": map[string]interface{}{"kind": "Datastore", "id": datastore}, "capacity": gb * gib}
+	}
+	datastores := []map[string]interface{}{
+		{"id": "ds-1", "name": "fast"},
+		{"id": "ds-2", "name": "slow"},
+	}
+	vms := []map[string]interface{}{
+		{"id": "vm-1", "name": "web", "disks": []interface{}{disk("ds-1", 40)}},
+		{"id": "vm-2", "name": "db", "disks": []interface{}{disk("ds-1", 100), disk("ds-1", 200), disk("ds-2", 50)}},
+		{"id": "vm-3", "name": "cache", "disks": []interface{}{disk("ds-1", 60)}},
+		{"id": "vm-4", "name": "elsewhere", "disks": []interface{}{disk("ds-9", 10)}},
+	}
+
+	attachDatastoreVMs(datastores, vms, 2)
+
+	fast := datastores[0]
+	if fast["vmCount"] != 3 || fast["vmCountHuman"] != "3 (top 2)" || fast["vmDiskHuman"] != "400.0 GB" {
+		t.Errorf("fast totals = %v %v %v", fast["vmCount"], fast["vmCountHuman"], fast["vmDiskHuman"])
+	}
+	if fast["topVMsSharePercent"] != 90.0 {
+		t.Errorf("fast top share = %v, want 90", fast["topVMsSharePercent"])
+	}
+	db := fast["vms"].([]interface{})[0].(map[string]interface{})
+	if db["name"] != "db" || db["diskCount"] != 2 || db["largestDiskHuman"] != "200.0 GB" || db["shareHuman"] != "75.0%" {
+		t.Errorf("largest VM on fast = %v", db)
+	}
+	if datastores[1]["vmCount"] != 1 || datastores[1]["vmDiskHuman"] != "50.0 GB" {
+		t.Errorf("slow = %v, want db only", datastores[1])
+	}
+
+	var names []string
+	for _, row := range datastoreTreeRows(datastores) {
+		names = append(names, row["name"].(string))
+	}
+	want := "fast,├─ db,└─ cache,slow,└─ db"
+	if got := strings.Join(names, ","); got != want {
+		t.Errorf("tree rows = %s, want %s", got, want)
+	}
+}
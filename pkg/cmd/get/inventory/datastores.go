@@ -11,16 +11,20 @@ import (
 	"github.com/yaacov/kubectl-mtv/pkg/util/watch"
 )
 
-// ListDatastoresWithInsecure queries the provider's datastore inventory with optional insecure TLS skip verification
-func ListDatastoresWithInsecure(ctx context.Context, kubeConfigFlags *genericclioptions.ConfigFlags, providerName, namespace string, inventoryURL string, outputFormat string, query string, watchMode bool, insecureSkipTLS bool) error {
+// ListDatastoresWithInsecure queries the provider's datastore inventory with optional insecure TLS skip verification.
+// With withVMs each datastore lists the VMs with disks on it, only the largest ones when largest is positive.
+func ListDatastoresWithInsecure(ctx context.Context, kubeConfigFlags *genericclioptions.ConfigFlags, providerName, namespace string, inventoryURL string, outputFormat string, query string, withVMs bool, largest int, watchMode bool, insecureSkipTLS bool) error {
+	if largest < 0 {
+		return fmt.Errorf("invalid --largest %d: must be positive", largest)
+	}
 	sq := watch.NewSafeQuery(query)
 
 	return watch.WrapWithWatchAndQuery(watchMode, outputFormat, func() error {
-		return listDatastoresOnce(ctx, kubeConfigFlags, providerName, namespace, inventoryURL, outputFormat, sq.Get(), insecureSkipTLS)
+		return listDatastoresOnce(ctx, kubeConfigFlags, providerName, namespace, inventoryURL, outputFormat, sq.Get(), withVMs, largest, insecureSkipTLS)
 	}, watch.DefaultInterval, sq.Set, query)
 }
 
-func listDatastoresOnce(ctx context.Context, kubeConfigFlags *genericclioptions.ConfigFlags, providerName, namespace string, inventoryURL string, outputFormat string, query string, insecureSkipTLS bool) error {
+func listDatastoresOnce(ctx context.Context, kubeConfigFlags *genericclioptions.ConfigFlags, providerName, namespace string, inventoryURL string, outputFormat string, query string, withVMs bool, largest int, insecureSkipTLS bool) error {
 	// Get the provider object
 	provider, err := GetProviderByName(ctx, kubeConfigFlags, providerName, namespace)
 	if err != nil {
@@ -97,6 +101,15 @@ func listDatastoresOnce(ctx context.Context, kubeConfigFlags *genericclioptions.
 		datastores = append(datastores, datastore)
 	}
 
+	// Nest the VMs under their datastores before the query, so it can filter and sort on the per-datastore totals
+	if withVMs {
+		vms, err := fetchHostVMs(ctx, providerClient)
+		if err != nil {
+			return err
+		}
+		attachDatastoreVMs(datastores, vms, largest)
+	}
+
 	// Parse query options for advanced query features
 	var queryOpts *querypkg.QueryOptions
 	if query != "" {
@@ -123,11 +136,25 @@ func listDatastoresOnce(ctx context.Context, kubeConfigFlags *genericclioptions.
 
 	// Generate output
 	emptyMessage := fmt.Sprintf("No datastores found for provider %s", providerName)
+	if output.IsTemplateFormat(outputFormat) {
+		return output.PrintTemplate(datastores, outputFormat)
+	}
+
 	switch outputFormat {
 	case "json":
 		return output.PrintJSONWithEmpty(datastores, emptyMessage)
 	case "yaml":
 		return output.PrintYAMLWithEmpty(datastores, emptyMessage)
+	}
+
+	if withVMs {
+		if outputFormat == "markdown" {
+			return output.PrintMarkdownWithQuery(datastoreTreeRows(datastores), datastoreTreeHeaders, queryOpts, emptyMessage)
+		}
+		return output.PrintTableWithQuery(datastoreTreeRows(datastores), datastoreTreeHeaders, queryOpts, emptyMessage)
+	}
+
+	switch outputFormat {
 	case "markdown":
 		return output.PrintMarkdownWithQuery(datastores, defaultHeaders, queryOpts, emptyMessage)
 	default:
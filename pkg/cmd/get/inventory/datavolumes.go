@@ -48,7 +48,7 @@ func listDataVolumesOnce(ctx context.Context, kubeConfigFlags *genericclioptions
 			{Title: "PROGRESS", Key: "object.status.progress", ColorFunc: output.ColorizeProgress},
 			{Title: "STORAGE_CLASS", Key: "object.spec.pvc.storageClassName"},
 			{Title: "SIZE", Key: "sizeFormatted"},
-			{Title: "CREATED", Key: "object.metadata.creationTimestamp"},
+			{Title: "CREATED", Key: "object.metadata.creationTimestamp", ColorFunc: output.FormatTimeCell},
 		}
 	default:
 		return fmt.Errorf("provider type '%s' does not support data volume inventory", providerType)
@@ -117,6 +117,10 @@ func listDataVolumesOnce(ctx context.Context, kubeConfigFlags *genericclioptions
 
 	// Format and display the results
 	emptyMessage := fmt.Sprintf("No data volumes found for provider %s", providerName)
+	if output.IsTemplateFormat(outputFormat) {
+		return output.PrintTemplate(dataVolumes, outputFormat)
+	}
+
 	switch outputFormat {
 	case "json":
 		return output.PrintJSONWithEmpty(dataVolumes, emptyMessage)
@@ -0,0 +1,180 @@
+package inventory
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Device severities, aligned with the inventory concern categories.
+const (
+	DeviceSeverityCritical = "Critical"
+	DeviceSeverityWarning  = "Warning"
+)
+
+// PassthroughDevice describes a VM device that is bound to source host hardware
+// and will not be carried over to the target VM by the migration.
+type PassthroughDevice struct {
+	Kind     string `json:"kind"`
+	Detail   string `json:"detail,omitempty"`
+	Severity string `json:"severity"`
+}
+
+// vsphereDeviceKinds maps vSphere virtual device kinds to a display kind and severity.
+var vsphereDeviceKinds = map[string]PassthroughDevice{
+	"VirtualPCIPassthrough":    {Kind: "PCI passthrough", Severity: DeviceSeverityCritical},
+	"VirtualSCSIPassthrough":   {Kind: "SCSI passthrough", Severity: DeviceSeverityCritical},
+	"VirtualSriovEthernetCard": {Kind: "SR-IOV NIC", Severity: DeviceSeverityCritical},
+	"VirtualUSBController":     {Kind: "USB controller", Severity: DeviceSeverityWarning},
+}
+
+// DetectPassthroughDevices inspects inventory VM details and returns the
+// passthrough devices, SR-IOV NICs and USB controllers found on the VM.
+// PCI/SCSI passthrough and SR-IOV NICs are reported as Critical (blocking),
+// USB controllers as Warning (need attention).
+func DetectPassthroughDevices(vm map[string]interface{}) []PassthroughDevice {
+	var found []PassthroughDevice
+
+	// vSphere: devices[*].kind holds the virtual device type name
+	if devices, ok := vm["devices"].([]interface{}); ok {
+		for _, d := range devices {
+			device, ok := d.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			kind, _ := device["kind"].(string)
+			known, ok := vsphereDeviceKinds[kind]
+			if !ok {
+				continue
+			}
+			if key, ok := device["key"].(float64); ok {
+				known.Detail = fmt.Sprintf("key %d", int64(key))
+			}
+			found = append(found, known)
+		}
+	}
+
+	// oVirt: host devices attached to the VM (GPUs, PCI/USB devices)
+	if hostDevices, ok := vm["hostDevices"].([]interface{}); ok {
+		for _, d := range hostDevices {
+			device, ok := d.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			capability, _ := device["capability"].(string)
+			entry := PassthroughDevice{Kind: "Host device", Severity: DeviceSeverityCritical}
+			switch strings.ToLower(capability) {
+			case "pci":
+				entry.Kind = "PCI passthrough"
+			case "usb", "usb_device":
+				entry.Kind = "USB device"
+			case "scsi":
+				entry.Kind = "SCSI passthrough"
+			}
+			entry.Detail = strings.TrimSpace(strings.Join(nonEmpty(stringField(device, "vendor"), stringField(device, "product")), " "))
+			found = append(found, entry)
+		}
+	}
+
+	// oVirt: vNICs using the pci_passthrough interface are SR-IOV virtual functions
+	if nics, ok := vm["nics"].([]interface{}); ok {
+		for _, n := range nics {
+			nic, ok := n.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if strings.EqualFold(stringField(nic, "interface"), "pci_passthrough") {
+				found = append(found, PassthroughDevice{
+					Kind:     "SR-IOV NIC",
+					Detail:   stringField(nic, "name"),
+					Severity: DeviceSeverityCritical,
+				})
+			}
+		}
+	}
+
+	// oVirt: USB support enabled on the VM
+	if usbEnabled, ok := vm["usbEnabled"].(bool); ok && usbEnabled {
+		found = append(found, PassthroughDevice{Kind: "USB controller", Severity: DeviceSeverityWarning})
+	}
+
+	return found
+}
+
+// CountBlockingDevices returns the number of Critical devices in the list.
+func CountBlockingDevices(devices []PassthroughDevice) int {
+	count := 0
+	for _, d := range devices {
+		if d.Severity == DeviceSeverityCritical {
+			count++
+		}
+	}
+	return count
+}
+
+// FormatPassthroughDevices returns a compact summary such as "PCI passthrough x2, USB controller".
+func FormatPassthroughDevices(devices []PassthroughDevice) string {
+	if len(devices) == 0 {
+		return ""
+	}
+
+	counts := make(map[string]int)
+	for _, d := range devices {
+		counts[d.Kind]++
+	}
+
+	kinds := make([]string, 0, len(counts))
+	for kind := range counts {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+
+	parts := make([]string, 0, len(kinds))
+	for _, kind := range kinds {
+		if counts[kind] > 1 {
+			parts = append(parts, fmt.Sprintf("%s x%d", kind, counts[kind]))
+		} else {
+			parts = append(parts, kind)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// augmentPassthroughDevices adds the passthroughDevices, blockingDevices and
+// devicesHuman computed fields to the VM map.
+func augmentPassthroughDevices(vm map[string]interface{}) {
+	devices := DetectPassthroughDevices(vm)
+
+	list := make([]interface{}, 0, len(devices))
+	for _, d := range devices {
+		entry := map[string]interface{}{
+			"kind":     d.Kind,
+			"severity": d.Severity,
+		}
+		if d.Detail != "" {
+			entry["detail"] = d.Detail
+		}
+		list = append(list, entry)
+	}
+
+	vm["passthroughDevices"] = list
+	vm["blockingDevices"] = CountBlockingDevices(devices)
+	vm["devicesHuman"] = FormatPassthroughDevices(devices)
+}
+
+// stringField returns the string value of key in m, or "" when absent.
+func stringField(m map[string]interface{}, key string) string {
+	s, _ := m[key].(string)
+	return s
+}
+
+// nonEmpty returns the non-empty values from the given strings.
+func nonEmpty(values ...string) []string {
+	var out []string
+	for _, v := range values {
+		if v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
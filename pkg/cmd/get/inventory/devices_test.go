@@ -0,0 +1,65 @@
+package inventory
+
+import "testing"
+
+func TestDetectPassthroughDevices_VSphere(t *testing.T) {
+	vm := map[string]interface{}{
+		"devices": []interface{}{
+			map[string]interface{}{"kind": "VirtualVmxnet3", "key": float64(4000)},
+			map[string]interface{}{"kind": "VirtualPCIPassthrough", "key": float64(13000)},
+			map[string]interface{}{"kind": "VirtualSriovEthernetCard", "key": float64(4001)},
+			map[string]interface{}{"kind": "VirtualUSBController", "key": float64(7000)},
+		},
+	}
+
+	devices := DetectPassthroughDevices(vm)
+	if len(devices) != 3 {
+		t.Fatalf("DetectPassthroughDevices() returned %d devices, want 3: %+v", len(devices), devices)
+	}
+	if got := CountBlockingDevices(devices); got != 2 {
+		t.Errorf("CountBlockingDevices() = %d, want 2", got)
+	}
+	if got, want := FormatPassthroughDevices(devices), "PCI passthrough, SR-IOV NIC, USB controller"; got != want {
+		t.Errorf("FormatPassthroughDevices() = %q, want %q", got, want)
+	}
+}
+
+func TestDetectPassthroughDevices_OVirt(t *testing.T) {
+	vm := map[string]interface{}{
+		"usbEnabled": true,
+		"hostDevices": []interface{}{
+			map[string]interface{}{"capability": "pci", "vendor": "NVIDIA", "product": "A100"},
+			map[string]interface{}{"capability": "pci", "vendor": "NVIDIA", "product": "A100"},
+		},
+		"nics": []interface{}{
+			map[string]interface{}{"name": "nic1", "interface": "virtio"},
+			map[string]interface{}{"name": "nic2", "interface": "pci_passthrough"},
+		},
+	}
+
+	devices := DetectPassthroughDevices(vm)
+	if len(devices) != 4 {
+		t.Fatalf("DetectPassthroughDevices() returned %d devices, want 4: %+v", len(devices), devices)
+	}
+	if devices[0].Detail != "NVIDIA A100" {
+		t.Errorf("host device detail = %q, want %q", devices[0].Detail, "NVIDIA A100")
+	}
+	if got, want := FormatPassthroughDevices(devices), "PCI passthrough x2, SR-IOV NIC, USB controller"; got != want {
+		t.Errorf("FormatPassthroughDevices() = %q, want %q", got, want)
+	}
+}
+
+func TestAugmentPassthroughDevices_NoDevices(t *testing.T) {
+	vm := map[string]interface{}{"name": "plain"}
+	augmentPassthroughDevices(vm)
+
+	if got := vm["blockingDevices"]; got != 0 {
+		t.Errorf("blockingDevices = %v, want 0", got)
+	}
+	if got := vm["devicesHuman"]; got != "" {
+		t.Errorf("devicesHuman = %q, want empty", got)
+	}
+	if list, ok := vm["passthroughDevices"].([]interface{}); !ok || len(list) != 0 {
+		t.Errorf("passthroughDevices = %v, want empty list", vm["passthroughDevices"])
+	}
+}
@@ -112,6 +112,10 @@ func listDisksOnce(ctx context.Context, kubeConfigFlags *genericclioptions.Confi
 
 	// Format and display the results
 	emptyMessage := fmt.Sprintf("No disks found for provider %s", providerName)
+	if output.IsTemplateFormat(outputFormat) {
+		return output.PrintTemplate(data, outputFormat)
+	}
+
 	switch outputFormat {
 	case "json":
 		return output.PrintJSONWithEmpty(data, emptyMessage)
@@ -80,6 +80,10 @@ func listEC2InstancesOnce(ctx context.Context, kubeConfigFlags *genericclioption
 
 	// Format and display the results
 	emptyMessage := fmt.Sprintf("No EC2 instances found for provider %s", providerName)
+	if output.IsTemplateFormat(outputFormat) {
+		return output.PrintTemplate(data, outputFormat)
+	}
+
 	switch outputFormat {
 	case "json":
 		return output.PrintJSONWithEmpty(data, emptyMessage)
@@ -166,6 +170,10 @@ func listEC2VolumesOnce(ctx context.Context, kubeConfigFlags *genericclioptions.
 
 	// Format and display the results
 	emptyMessage := fmt.Sprintf("No EC2 volumes found for provider %s", providerName)
+	if output.IsTemplateFormat(outputFormat) {
+		return output.PrintTemplate(data, outputFormat)
+	}
+
 	switch outputFormat {
 	case "json":
 		return output.PrintJSONWithEmpty(data, emptyMessage)
@@ -244,6 +252,10 @@ func listEC2VolumeTypesOnce(ctx context.Context, kubeConfigFlags *genericcliopti
 
 	// Format and display the results
 	emptyMessage := fmt.Sprintf("No EC2 volume types found for provider %s", providerName)
+	if output.IsTemplateFormat(outputFormat) {
+		return output.PrintTemplate(data, outputFormat)
+	}
+
 	switch outputFormat {
 	case "json":
 		return output.PrintJSONWithEmpty(data, emptyMessage)
@@ -328,6 +340,10 @@ func listEC2NetworksOnce(ctx context.Context, kubeConfigFlags *genericclioptions
 
 	// Format and display the results
 	emptyMessage := fmt.Sprintf("No EC2 networks found for provider %s", providerName)
+	if output.IsTemplateFormat(outputFormat) {
+		return output.PrintTemplate(data, outputFormat)
+	}
+
 	switch outputFormat {
 	case "json":
 		return output.PrintJSONWithEmpty(data, emptyMessage)
@@ -413,6 +429,10 @@ func listEC2SnapshotsOnce(ctx context.Context, kubeConfigFlags *genericclioption
 
 	// Format and display the results
 	emptyMessage := fmt.Sprintf("No EC2 snapshots found for provider %s", providerName)
+	if output.IsTemplateFormat(outputFormat) {
+		return output.PrintTemplate(data, outputFormat)
+	}
+
 	switch outputFormat {
 	case "json":
 		return output.PrintJSONWithEmpty(data, emptyMessage)
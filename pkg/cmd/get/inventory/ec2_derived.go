@@ -0,0 +1,226 @@
+package inventory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/yaacov/kubectl-mtv/pkg/util/output"
+	querypkg "github.com/yaacov/kubectl-mtv/pkg/util/query"
+	"github.com/yaacov/kubectl-mtv/pkg/util/watch"
+)
+
+// The Forklift EC2 inventory does not collect AMIs or security groups as separate
+// resources, so these listings are derived from the instance inventory and only
+// include images and groups that are referenced by at least one instance.
+
+// ListEC2ImagesWithInsecure lists the AMIs used by the provider's EC2 instances with optional insecure TLS skip verification
+func ListEC2ImagesWithInsecure(ctx context.Context, kubeConfigFlags *genericclioptions.ConfigFlags, providerName, namespace string, inventoryURL string, outputFormat string, query string, watchMode bool, insecureSkipTLS bool) error {
+	sq := watch.NewSafeQuery(query)
+
+	return watch.WrapWithWatchAndQuery(watchMode, outputFormat, func() error {
+		return listEC2DerivedOnce(ctx, kubeConfigFlags, providerName, namespace, inventoryURL, outputFormat, sq.Get(), insecureSkipTLS,
+			"EC2 images", aggregateEC2Images, []output.Column{
+				{Title: "ID", Key: "id"},
+				{Title: "PLATFORM", Key: "platform"},
+				{Title: "ARCH", Key: "architecture"},
+				{Title: "INSTANCES", Key: "instanceCount"},
+				{Title: "USED-BY", Key: "instancesHuman", MaxWidth: 60},
+			})
+	}, watch.DefaultInterval, sq.Set, query)
+}
+
+// ListEC2SecurityGroupsWithInsecure lists the security groups attached to the provider's EC2 instances with optional insecure TLS skip verification
+func ListEC2SecurityGroupsWithInsecure(ctx context.Context, kubeConfigFlags *genericclioptions.ConfigFlags, providerName, namespace string, inventoryURL string, outputFormat string, query string, watchMode bool, insecureSkipTLS bool) error {
+	sq := watch.NewSafeQuery(query)
+
+	return watch.WrapWithWatchAndQuery(watchMode, outputFormat, func() error {
+		return listEC2DerivedOnce(ctx, kubeConfigFlags, providerName, namespace, inventoryURL, outputFormat, sq.Get(), insecureSkipTLS,
+			"EC2 security groups", aggregateEC2SecurityGroups, []output.Column{
+				{Title: "NAME", Key: "name"},
+				{Title: "ID", Key: "id"},
+				{Title: "VPC", Key: "vpcId"},
+				{Title: "INSTANCES", Key: "instanceCount"},
+				{Title: "USED-BY", Key: "instancesHuman", MaxWidth: 60},
+			})
+	}, watch.DefaultInterval, sq.Set, query)
+}
+
+// listEC2DerivedOnce fetches the EC2 instances and prints a resource list aggregated from them
+func listEC2DerivedOnce(ctx context.Context, kubeConfigFlags *genericclioptions.ConfigFlags, providerName, namespace string, inventoryURL string, outputFormat string, query string, insecureSkipTLS bool,
+	resourceLabel string, aggregate func([]interface{}) []interface{}, defaultHeaders []output.Column) error {
+	// Get the provider object
+	provider, err := GetProviderByName(ctx, kubeConfigFlags, providerName, namespace)
+	if err != nil {
+		return err
+	}
+
+	// Create a new provider client
+	providerClient := NewProviderClientWithInsecure(kubeConfigFlags, provider, inventoryURL, insecureSkipTLS)
+
+	// Get provider type to verify EC2 support
+	providerType, err := providerClient.GetProviderType()
+	if err != nil {
+		return fmt.Errorf("failed to get provider type: %v", err)
+	}
+
+	// Verify this is an EC2 provider
+	if providerType != "ec2" {
+		return fmt.Errorf("provider type '%s' is not an EC2 provider", providerType)
+	}
+
+	// Fetch EC2 instances from the provider
+	instances, err := providerClient.GetVMs(ctx, 4)
+	if err != nil {
+		return fmt.Errorf("failed to get EC2 instances from provider: %v", err)
+	}
+
+	// Extract objects from EC2 envelope and aggregate the derived resources
+	instanceList, _ := ExtractEC2Objects(instances).([]interface{})
+	var data interface{} = aggregate(instanceList)
+
+	// Parse query options for advanced query features
+	var queryOpts *querypkg.QueryOptions
+	if query != "" {
+		queryOpts, err = querypkg.ParseQueryString(query)
+		if err != nil {
+			return fmt.Errorf("failed to parse query: %v", err)
+		}
+
+		// Apply query filter
+		data, err = querypkg.ApplyQueryInterface(data, query)
+		if err != nil {
+			return fmt.Errorf("failed to apply query: %v", err)
+		}
+	}
+
+	// Format and display the results
+	emptyMessage := fmt.Sprintf("No %s found for provider %s", resourceLabel, providerName)
+	if output.IsTemplateFormat(outputFormat) {
+		return output.PrintTemplate(data, outputFormat)
+	}
+
+	switch outputFormat {
+	case "json":
+		return output.PrintJSONWithEmpty(data, emptyMessage)
+	case "yaml":
+		return output.PrintYAMLWithEmpty(data, emptyMessage)
+	case "markdown":
+		return output.PrintMarkdownWithQuery(data, defaultHeaders, queryOpts, emptyMessage)
+	case "table":
+		return output.PrintTableWithQuery(data, defaultHeaders, queryOpts, emptyMessage)
+	default:
+		return fmt.Errorf("unsupported output format: %s", outputFormat)
+	}
+}
+
+// aggregateEC2Images groups instances by AMI (ImageId)
+func aggregateEC2Images(instances []interface{}) []interface{} {
+	images := map[string]map[string]interface{}{}
+	users := map[string][]string{}
+
+	for _, item := range instances {
+		instance, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		imageID, _ := instance["ImageId"].(string)
+		if imageID == "" {
+			continue
+		}
+
+		if _, exists := images[imageID]; !exists {
+			platform, _ := instance["PlatformDetails"].(string)
+			architecture, _ := instance["Architecture"].(string)
+			images[imageID] = map[string]interface{}{
+				"id":           imageID,
+				"platform":     platform,
+				"architecture": architecture,
+			}
+		}
+		users[imageID] = append(users[imageID], ec2InstanceLabel(instance))
+	}
+
+	return finalizeEC2Aggregate(images, users)
+}
+
+// aggregateEC2SecurityGroups groups instances by attached security group
+func aggregateEC2SecurityGroups(instances []interface{}) []interface{} {
+	groups := map[string]map[string]interface{}{}
+	users := map[string][]string{}
+
+	for _, item := range instances {
+		instance, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		vpcID, _ := instance["VpcId"].(string)
+		label := ec2InstanceLabel(instance)
+
+		sgs, _ := instance["SecurityGroups"].([]interface{})
+		for _, sg := range sgs {
+			sgMap, ok := sg.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			groupID, _ := sgMap["GroupId"].(string)
+			if groupID == "" {
+				continue
+			}
+
+			if _, exists := groups[groupID]; !exists {
+				groupName, _ := sgMap["GroupName"].(string)
+				groups[groupID] = map[string]interface{}{
+					"id":    groupID,
+					"name":  groupName,
+					"vpcId": vpcID,
+				}
+			}
+			users[groupID] = append(users[groupID], label)
+		}
+	}
+
+	return finalizeEC2Aggregate(groups, users)
+}
+
+// finalizeEC2Aggregate adds instance usage fields and returns the items sorted by ID
+func finalizeEC2Aggregate(items map[string]map[string]interface{}, users map[string][]string) []interface{} {
+	ids := make([]string, 0, len(items))
+	for id := range items {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	result := make([]interface{}, 0, len(ids))
+	for _, id := range ids {
+		item := items[id]
+		instances := users[id]
+		sort.Strings(instances)
+
+		instanceList := make([]interface{}, 0, len(instances))
+		for _, name := range instances {
+			instanceList = append(instanceList, name)
+		}
+
+		item["instances"] = instanceList
+		item["instanceCount"] = len(instances)
+		item["instancesHuman"] = strings.Join(instances, ", ")
+		result = append(result, item)
+	}
+	return result
+}
+
+// ec2InstanceLabel returns the instance Name tag, falling back to the instance ID
+func ec2InstanceLabel(instance map[string]interface{}) string {
+	if name, ok := instance["name"].(string); ok && name != "" {
+		return name
+	}
+	if id, ok := instance["InstanceId"].(string); ok && id != "" {
+		return id
+	}
+	id, _ := instance["id"].(string)
+	return id
+}
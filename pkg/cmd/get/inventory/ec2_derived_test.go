@@ -0,0 +1,67 @@
+package inventory
+
+import "testing"
+
+func testEC2Instances() []interface{} {
+	return []interface{}{
+		map[string]interface{}{
+			"name":            "web-1",
+			"InstanceId":      "i-001",
+			"ImageId":         "ami-aaa",
+			"PlatformDetails": "Linux/UNIX",
+			"Architecture":    "x86_64",
+			"VpcId":           "vpc-1",
+			"SecurityGroups": []interface{}{
+				map[string]interface{}{"GroupId": "sg-web", "GroupName": "web"},
+				map[string]interface{}{"GroupId": "sg-ssh", "GroupName": "ssh"},
+			},
+		},
+		map[string]interface{}{
+			"InstanceId": "i-002",
+			"ImageId":    "ami-aaa",
+			"VpcId":      "vpc-1",
+			"SecurityGroups": []interface{}{
+				map[string]interface{}{"GroupId": "sg-ssh", "GroupName": "ssh"},
+			},
+		},
+		map[string]interface{}{
+			"name":    "db-1",
+			"ImageId": "ami-bbb",
+		},
+	}
+}
+
+func TestAggregateEC2Images(t *testing.T) {
+	images := aggregateEC2Images(testEC2Instances())
+	if len(images) != 2 {
+		t.Fatalf("images = %d, want 2", len(images))
+	}
+
+	first := images[0].(map[string]interface{})
+	if first["id"] != "ami-aaa" || first["instanceCount"] != 2 {
+		t.Errorf("unexpected first image: %v", first)
+	}
+	if first["instancesHuman"] != "i-002, web-1" {
+		t.Errorf("instancesHuman = %v", first["instancesHuman"])
+	}
+	if first["platform"] != "Linux/UNIX" {
+		t.Errorf("platform = %v", first["platform"])
+	}
+}
+
+func TestAggregateEC2SecurityGroups(t *testing.T) {
+	groups := aggregateEC2SecurityGroups(testEC2Instances())
+	if len(groups) != 2 {
+		t.Fatalf("groups = %d, want 2", len(groups))
+	}
+
+	ssh := groups[0].(map[string]interface{})
+	if ssh["id"] != "sg-ssh" || ssh["name"] != "ssh" || ssh["vpcId"] != "vpc-1" || ssh["instanceCount"] != 2 {
+		t.Errorf("unexpected ssh group: %v", ssh)
+	}
+
+	web := groups[1].(map[string]interface{})
+	if web["id"] != "sg-web" || web["instanceCount"] != 1 {
+		t.Errorf("unexpected web group: %v", web)
+	}
+}
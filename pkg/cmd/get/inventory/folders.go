@@ -109,6 +109,10 @@ func listFoldersOnce(ctx context.Context, kubeConfigFlags *genericclioptions.Con
 	// Generate output
 	// Format and display the results
 	emptyMessage := fmt.Sprintf("No folders found for provider %s", providerName)
+	if output.IsTemplateFormat(outputFormat) {
+		return output.PrintTemplate(folders, outputFormat)
+	}
+
 	switch outputFormat {
 	case "json":
 		return output.PrintJSONWithEmpty(folders, emptyMessage)
@@ -0,0 +1,48 @@
+package inventory
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// oldLinuxPatterns match guest OS identifiers of Linux releases that predate
+// virtio drivers in the default initramfs, with the first release that has them.
+var oldLinuxPatterns = []struct {
+	pattern    *regexp.Regexp
+	minVersion int
+}{
+	{regexp.MustCompile(`(rhel|centos|oracle|oel)[^0-9]{0,8}([0-9]+)`), 7},
+	{regexp.MustCompile(`(sles|suse)[^0-9]{0,8}([0-9]+)`), 12},
+}
+
+// oldKernelPattern matches the generic vSphere identifiers of 2.4 and 2.6 Linux kernels
+var oldKernelPattern = regexp.MustCompile(`other2[46]x`)
+
+// GuestConversionRisk inspects inventory VM details and returns why the guest
+// depends on guest conversion (virtio drivers, guest tools cleanup), or "" when
+// the guest is not known to need it. The guest OS is read from the vSphere
+// guestId/guestName and the oVirt/OVA osType fields.
+func GuestConversionRisk(vm map[string]interface{}) string {
+	for _, value := range nonEmpty(stringField(vm, "guestId"), stringField(vm, "osType"), stringField(vm, "guestName")) {
+		guest := strings.ToLower(value)
+
+		if strings.HasPrefix(guest, "win") || strings.Contains(guest, "windows") {
+			return fmt.Sprintf("Windows guest (%s)", value)
+		}
+		for _, p := range oldLinuxPatterns {
+			match := p.pattern.FindStringSubmatch(guest)
+			if match == nil {
+				continue
+			}
+			if version, err := strconv.Atoi(match[2]); err == nil && version < p.minVersion {
+				return fmt.Sprintf("older Linux guest (%s)", value)
+			}
+		}
+		if oldKernelPattern.MatchString(guest) {
+			return fmt.Sprintf("older Linux guest (%s)", value)
+		}
+	}
+	return ""
+}
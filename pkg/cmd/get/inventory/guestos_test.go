@@ -0,0 +1,38 @@
+package inventory
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGuestConversionRisk(t *testing.T) {
+	tests := []struct {
+		name string
+		vm   map[string]interface{}
+		want string
+	}{
+		{"vSphere Windows", map[string]interface{}{"guestId": "windows2019srv_64Guest"}, "Windows guest"},
+		{"vSphere legacy Windows", map[string]interface{}{"guestId": "winNetStandardGuest"}, "Windows guest"},
+		{"oVirt Windows", map[string]interface{}{"osType": "windows_2019x64"}, "Windows guest"},
+		{"guest name only", map[string]interface{}{"guestName": "Microsoft Windows Server 2016 (64-bit)"}, "Windows guest"},
+		{"RHEL 6", map[string]interface{}{"guestId": "rhel6_64Guest"}, "older Linux guest"},
+		{"oVirt RHEL 5", map[string]interface{}{"osType": "rhel_5x64"}, "older Linux guest"},
+		{"SLES 11", map[string]interface{}{"guestId": "sles11_64Guest"}, "older Linux guest"},
+		{"2.6 kernel", map[string]interface{}{"guestId": "other26xLinux64Guest"}, "older Linux guest"},
+		{"RHEL 9", map[string]interface{}{"guestId": "rhel9_64Guest"}, ""},
+		{"SLES 15", map[string]interface{}{"guestId": "sles15_64Guest"}, ""},
+		{"Ubuntu", map[string]interface{}{"guestId": "ubuntu64Guest"}, ""},
+		{"macOS", map[string]interface{}{"guestId": "darwin19_64Guest"}, ""},
+		{"unknown", map[string]interface{}{}, ""},
+	}
+
+	for _, tt := range tests {
+		got := GuestConversionRisk(tt.vm)
+		if tt.want == "" && got != "" {
+			t.Errorf("%s: GuestConversionRisk() = %q, want no risk", tt.name, got)
+		}
+		if tt.want != "" && !strings.HasPrefix(got, tt.want) {
+			t.Errorf("%s: GuestConversionRisk() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
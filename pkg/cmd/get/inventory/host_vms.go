@@ -0,0 +1,152 @@
+package inventory
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/yaacov/kubectl-mtv/pkg/util/output"
+	"github.com/yaacov/kubectl-mtv/pkg/util/vocab"
+)
+
+// Tree prefixes of the VM rows nested under their host
+const (
+	treeBranch = "├─ "
+	treeLast   = "└─ "
+)
+
+// attachHostVMs nests each VM under the host it runs on and adds per-host totals:
+// the VM counts, the allocated vCPUs and memory, and the vCPU to core ratio.
+// VMs are ordered by allocated memory, largest first, the order in which they
+// are usually evacuated. It returns the number of VMs not placed on a listed host.
+func attachHostVMs(hosts []map[string]interface{}, vms []map[string]interface{}) int {
+	byID := make(map[string]map[string]interface{}, len(hosts))
+	for _, host := range hosts {
+		host["vms"] = []interface{}{}
+		if id, ok := host["id"].(string); ok {
+			byID[id] = host
+		}
+	}
+
+	grouped := make(map[string][]map[string]interface{})
+	unplaced := 0
+	for _, vm := range vms {
+		hostID, _ := vm["host"].(string)
+		if _, ok := byID[hostID]; !ok {
+			unplaced++
+			continue
+		}
+		grouped[hostID] = append(grouped[hostID], hostVM(vm))
+	}
+
+	for id, host := range byID {
+		children := grouped[id]
+		sort.SliceStable(children, func(i, j int) bool {
+			mi, _ := children[i]["memoryGiB"].(float64)
+			mj, _ := children[j]["memoryGiB"].(float64)
+			if mi != mj {
+				return mi > mj
+			}
+			return fmt.Sprint(children[i]["name"]) < fmt.Sprint(children[j]["name"])
+		})
+		addHostVMTotals(host, children)
+	}
+	return unplaced
+}
+
+// hostVM returns the fields of a VM shown under its host
+func hostVM(vm map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"name":            vm["name"],
+		"id":              vm["id"],
+		"powerStateHuman": vm["powerStateHuman"],
+		"cpuCount":        inventoryNumber(vm["cpuCount"]),
+		"memoryGiB":       inventoryNumber(vm["memoryGiB"]),
+		"diskGiB":         inventoryNumber(vm["diskGiB"]),
+	}
+}
+
+// addHostVMTotals sets the nested VMs of a host and their totals
+func addHostVMTotals(host map[string]interface{}, vms []map[string]interface{}) {
+	var vcpus, memoryGiB float64
+	poweredOn := 0
+	nested := make([]interface{}, 0, len(vms))
+	for _, vm := range vms {
+		if vm["powerStateHuman"] == vocab.PowerOn {
+			poweredOn++
+		}
+		vcpus += inventoryNumber(vm["cpuCount"])
+		memoryGiB += inventoryNumber(vm["memoryGiB"])
+		nested = append(nested, vm)
+	}
+
+	host["vms"] = nested
+	host["vmCount"] = len(vms)
+	host["poweredOnCount"] = poweredOn
+	host["vmCountHuman"] = fmt.Sprintf("%d (%d on)", len(vms), poweredOn)
+	host["allocatedCpus"] = vcpus
+	host["allocatedMemoryGiB"] = math.Round(memoryGiB*10) / 10
+	host["allocatedMemoryHuman"] = fmt.Sprintf("%.1f GB", memoryGiB)
+
+	host["cpuRatio"], host["cpuRatioHuman"] = 0.0, ""
+	if cores := inventoryNumber(host["cpuCores"]); cores > 0 {
+		ratio := math.Round(vcpus/cores*100) / 100
+		host["cpuRatio"] = ratio
+		host["cpuRatioHuman"] = fmt.Sprintf("%.0f/%.0f (%.0f%%)", vcpus, cores, ratio*100)
+	}
+}
+
+// hostTreeRows flattens hosts and their nested VMs into table rows, each host
+// followed by its VMs drawn as tree branches
+func hostTreeRows(hosts []map[string]interface{}) []map[string]interface{} {
+	var rows []map[string]interface{}
+	for _, host := range hosts {
+		rows = append(rows, host)
+		vms, _ := host["vms"].([]interface{})
+		for i, item := range vms {
+			vm, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			prefix := treeBranch
+			if i == len(vms)-1 {
+				prefix = treeLast
+			}
+			rows = append(rows, map[string]interface{}{
+				"name":                 prefix + fmt.Sprint(vm["name"]),
+				"id":                   vm["id"],
+				"status":               vm["powerStateHuman"],
+				"allocatedCpus":        vm["cpuCount"],
+				"allocatedMemoryHuman": fmt.Sprintf("%.1f GB", inventoryNumber(vm["memoryGiB"])),
+			})
+		}
+	}
+	return rows
+}
+
+// hostTreeHeaders are the table columns of the hosts with their VMs; VM rows show
+// their power state in STATUS and their own allocation in VCPUS and MEMORY
+var hostTreeHeaders = []output.Column{
+	{Title: "NAME", Key: "name"},
+	{Title: "ID", Key: "id"},
+	{Title: "STATUS", Key: "status", ColorFunc: output.ColorizeStatus},
+	{Title: "VMS", Key: "vmCountHuman"},
+	{Title: "VCPUS", Key: "allocatedCpus"},
+	{Title: "VCPU/CORES", Key: "cpuRatioHuman"},
+	{Title: "MEMORY", Key: "allocatedMemoryHuman"},
+	{Title: "MAINTENANCE", Key: "inMaintenance", ColorFunc: output.ColorizeBooleanString},
+}
+
+// inventoryNumber returns a numeric inventory field, 0 when missing
+func inventoryNumber(value interface{}) float64 {
+	switch v := value.(type) {
+	case float64:
+		return v
+	case int64:
+		return float64(v)
+	case int:
+		return float64(v)
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,43 @@
+package inventory
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAttachHostVMs(t *testing.T) {
+	hosts := []map[string]interface{}{
+		{"id": "host-1", "name": "esx1", "cpuCores": float64(16)},
+		{"id": "host-2", "name": "esx2", "cpuCores": float64(8)},
+	}
+	vms := []map[string]interface{}{
+		{"id": "vm-1", "name": "web", "host": "host-1", "powerStateHuman": "On", "cpuCount": float64(4), "memoryGiB": 8.0},
+		{"id": "vm-2", "name": "db", "host": "host-1", "powerStateHuman": "On", "cpuCount": float64(16), "memoryGiB": 64.0},
+		{"id": "vm-3", "name": "old", "host": "host-1", "powerStateHuman": "Off", "cpuCount": float64(4), "memoryGiB": 8.0},
+		{"id": "vm-4", "name": "orphan", "host": "host-9"},
+	}
+
+	if unplaced := attachHostVMs(hosts, vms); unplaced != 1 {
+		t.Errorf("unplaced = %d, want 1", unplaced)
+	}
+
+	esx1 := hosts[0]
+	if esx1["vmCount"] != 3 || esx1["poweredOnCount"] != 2 || esx1["allocatedCpus"] != 24.0 || esx1["allocatedMemoryGiB"] != 80.0 {
+		t.Errorf("esx1 totals = %v", esx1)
+	}
+	if esx1["cpuRatio"] != 1.5 || esx1["cpuRatioHuman"] != "24/16 (150%)" {
+		t.Errorf("esx1 ratio = %v %v, want 1.5 and 24/16 (150%%)", esx1["cpuRatio"], esx1["cpuRatioHuman"])
+	}
+	if hosts[1]["vmCount"] != 0 || len(hosts[1]["vms"].([]interface{})) != 0 {
+		t.Errorf("esx2 = %v, want no VMs", hosts[1])
+	}
+
+	var names []string
+	for _, row := range hostTreeRows(hosts) {
+		names = append(names, row["name"].(string))
+	}
+	want := "esx1,├─ db,├─ old,└─ web,esx2"
+	if got := strings.Join(names, ","); got != want {
+		t.Errorf("tree rows = %s, want %s", got, want)
+	}
+}
@@ -3,25 +3,26 @@ package inventory
 import (
 	"context"
 	"fmt"
-	"strings"
 
 	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/klog/v2"
 
 	"github.com/yaacov/kubectl-mtv/pkg/util/output"
 	querypkg "github.com/yaacov/kubectl-mtv/pkg/util/query"
 	"github.com/yaacov/kubectl-mtv/pkg/util/watch"
 )
 
-// ListHostsWithInsecure queries the provider's host inventory with optional insecure TLS skip verification
-func ListHostsWithInsecure(ctx context.Context, kubeConfigFlags *genericclioptions.ConfigFlags, providerName, namespace string, inventoryURL string, outputFormat string, query string, watchMode bool, insecureSkipTLS bool) error {
+// ListHostsWithInsecure queries the provider's host inventory with optional insecure TLS skip verification.
+// With withVMs each host lists the VMs running on it with their allocated capacity.
+func ListHostsWithInsecure(ctx context.Context, kubeConfigFlags *genericclioptions.ConfigFlags, providerName, namespace string, inventoryURL string, outputFormat string, query string, withVMs bool, watchMode bool, insecureSkipTLS bool) error {
 	sq := watch.NewSafeQuery(query)
 
 	return watch.WrapWithWatchAndQuery(watchMode, outputFormat, func() error {
-		return listHostsOnce(ctx, kubeConfigFlags, providerName, namespace, inventoryURL, outputFormat, sq.Get(), insecureSkipTLS)
+		return listHostsOnce(ctx, kubeConfigFlags, providerName, namespace, inventoryURL, outputFormat, sq.Get(), withVMs, insecureSkipTLS)
 	}, watch.DefaultInterval, sq.Set, query)
 }
 
-func listHostsOnce(ctx context.Context, kubeConfigFlags *genericclioptions.ConfigFlags, providerName, namespace string, inventoryURL string, outputFormat string, query string, insecureSkipTLS bool) error {
+func listHostsOnce(ctx context.Context, kubeConfigFlags *genericclioptions.ConfigFlags, providerName, namespace string, inventoryURL string, outputFormat string, query string, withVMs bool, insecureSkipTLS bool) error {
 	// Get the provider object
 	provider, err := GetProviderByName(ctx, kubeConfigFlags, providerName, namespace)
 	if err != nil {
@@ -67,6 +68,17 @@ func listHostsOnce(ctx context.Context, kubeConfigFlags *genericclioptions.Confi
 		}
 	}
 
+	// Nest the VMs under their hosts before the query, so it can filter on the per-host totals
+	if withVMs {
+		vms, err := fetchHostVMs(ctx, providerClient)
+		if err != nil {
+			return err
+		}
+		if unplaced := attachHostVMs(hosts, vms); unplaced > 0 {
+			klog.V(2).Infof("%d VMs are not placed on a listed host", unplaced)
+		}
+	}
+
 	// Parse and apply query options
 	queryOpts, err := querypkg.ParseQueryString(query)
 	if err != nil {
@@ -80,8 +92,8 @@ func listHostsOnce(ctx context.Context, kubeConfigFlags *genericclioptions.Confi
 	}
 
 	// Format validation
-	outputFormat = strings.ToLower(outputFormat)
-	if outputFormat != "table" && outputFormat != "json" && outputFormat != "yaml" && outputFormat != "markdown" {
+	outputFormat = output.NormalizeFormat(outputFormat)
+	if outputFormat != "table" && outputFormat != "json" && outputFormat != "yaml" && outputFormat != "markdown" && !output.IsTemplateFormat(outputFormat) {
 		return fmt.Errorf("unsupported output format: %s. Supported formats: table, json, yaml, markdown", outputFormat)
 	}
 
@@ -98,14 +110,49 @@ func listHostsOnce(ctx context.Context, kubeConfigFlags *genericclioptions.Confi
 		{Title: "MAINTENANCE", Key: "inMaintenance", ColorFunc: output.ColorizeBooleanString},
 	}
 
+	if output.IsTemplateFormat(outputFormat) {
+		return output.PrintTemplate(hosts, outputFormat)
+	}
+
 	switch outputFormat {
 	case "json":
 		return output.PrintJSONWithEmpty(hosts, emptyMessage)
 	case "yaml":
 		return output.PrintYAMLWithEmpty(hosts, emptyMessage)
+	}
+
+	if withVMs {
+		if outputFormat == "markdown" {
+			return output.PrintMarkdownWithQuery(hostTreeRows(hosts), hostTreeHeaders, queryOpts, emptyMessage)
+		}
+		return output.PrintTableWithQuery(hostTreeRows(hosts), hostTreeHeaders, queryOpts, emptyMessage)
+	}
+
+	switch outputFormat {
 	case "markdown":
 		return output.PrintMarkdownWithQuery(hosts, defaultHeaders, queryOpts, emptyMessage)
 	default:
 		return output.PrintTableWithQuery(hosts, defaultHeaders, queryOpts, emptyMessage)
 	}
 }
+
+// fetchHostVMs returns the provider VMs with the computed fields used for the per-host totals
+func fetchHostVMs(ctx context.Context, providerClient *ProviderClient) ([]map[string]interface{}, error) {
+	data, err := providerClient.GetVMs(ctx, 4)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch VM inventory: %v", err)
+	}
+	dataArray, ok := data.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected data format: expected array for VM inventory")
+	}
+
+	vms := make([]map[string]interface{}, 0, len(dataArray))
+	for _, item := range dataArray {
+		if vm, ok := item.(map[string]interface{}); ok {
+			augmentVMInfo(vm)
+			vms = append(vms, vm)
+		}
+	}
+	return vms, nil
+}
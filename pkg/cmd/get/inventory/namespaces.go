@@ -3,7 +3,6 @@ package inventory
 import (
 	"context"
 	"fmt"
-	"strings"
 
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 
@@ -82,8 +81,8 @@ func listNamespacesOnce(ctx context.Context, kubeConfigFlags *genericclioptions.
 	}
 
 	// Format validation
-	outputFormat = strings.ToLower(outputFormat)
-	if outputFormat != "table" && outputFormat != "json" && outputFormat != "yaml" && outputFormat != "markdown" {
+	outputFormat = output.NormalizeFormat(outputFormat)
+	if outputFormat != "table" && outputFormat != "json" && outputFormat != "yaml" && outputFormat != "markdown" && !output.IsTemplateFormat(outputFormat) {
 		return fmt.Errorf("unsupported output format: %s. Supported formats: table, json, yaml, markdown", outputFormat)
 	}
 
@@ -95,6 +94,10 @@ func listNamespacesOnce(ctx context.Context, kubeConfigFlags *genericclioptions.
 		{Title: "PROVIDER", Key: "provider"},
 	}
 
+	if output.IsTemplateFormat(outputFormat) {
+		return output.PrintTemplate(namespaces, outputFormat)
+	}
+
 	switch outputFormat {
 	case "json":
 		return output.PrintJSONWithEmpty(namespaces, emptyMessage)
@@ -0,0 +1,170 @@
+package inventory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/yaacov/kubectl-mtv/pkg/util/output"
+)
+
+// Values of the --in-use-by flag of get inventory network
+const (
+	InUseByCount = "count"
+	InUseByNames = "names"
+)
+
+// networkUsageProviders are the provider types whose VMs reference their networks in the inventory
+var networkUsageProviders = map[string]bool{
+	"vsphere":   true,
+	"ovirt":     true,
+	"openstack": true,
+	"ova":       true,
+	"hyperv":    true,
+}
+
+// ValidateInUseBy checks the value of the --in-use-by flag; empty disables the cross reference
+func ValidateInUseBy(inUseBy string) error {
+	switch inUseBy {
+	case "", InUseByCount, InUseByNames:
+		return nil
+	default:
+		return fmt.Errorf("invalid --in-use-by value '%s': must be %s or %s", inUseBy, InUseByCount, InUseByNames)
+	}
+}
+
+// fetchNetworkUsageVMs returns the provider VMs and, for oVirt, the network of each NIC profile
+func fetchNetworkUsageVMs(ctx context.Context, providerClient *ProviderClient, providerType string) ([]map[string]interface{}, map[string]string, error) {
+	if !networkUsageProviders[providerType] {
+		return nil, nil, fmt.Errorf("--in-use-by is not supported for %s providers, VM network references are reported for vsphere, ovirt, openstack, ova and hyperv", providerType)
+	}
+
+	data, err := providerClient.GetVMs(ctx, 4)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch VM inventory: %v", err)
+	}
+	vms := toObjectSlice(data)
+
+	var profileNetworks map[string]string
+	if providerType == "ovirt" {
+		profiles, err := providerClient.GetNICProfiles(ctx, 4)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to fetch NIC profiles inventory: %v", err)
+		}
+		profileNetworks = make(map[string]string)
+		for _, profile := range toObjectSlice(profiles) {
+			id, _ := profile["id"].(string)
+			network, _ := profile["network"].(string)
+			if id != "" && network != "" {
+				profileNetworks[id] = network
+			}
+		}
+	}
+	return vms, profileNetworks, nil
+}
+
+// toObjectSlice returns the objects of an inventory array, skipping other items
+func toObjectSlice(data interface{}) []map[string]interface{} {
+	items, _ := data.([]interface{})
+	objects := make([]map[string]interface{}, 0, len(items))
+	for _, item := range items {
+		if obj, ok := item.(map[string]interface{}); ok {
+			objects = append(objects, obj)
+		}
+	}
+	return objects
+}
+
+// vmNetworkRefs returns the networks the NICs of a VM are connected to: network IDs, or
+// network names for OpenStack, whose VMs list their addresses by network name
+func vmNetworkRefs(providerType string, vm map[string]interface{}, profileNetworks map[string]string) []string {
+	var refs []string
+	switch providerType {
+	case "openstack":
+		addresses, _ := vm["addresses"].(map[string]interface{})
+		for name := range addresses {
+			refs = append(refs, name)
+		}
+	case "ovirt":
+		for _, nic := range toObjectSlice(vm["nics"]) {
+			profile, _ := nic["profile"].(string)
+			if network, ok := profileNetworks[profile]; ok {
+				refs = append(refs, network)
+			}
+		}
+	case "hyperv":
+		for _, nic := range toObjectSlice(vm["nics"]) {
+			if id, ok := nic["networkUUID"].(string); ok {
+				refs = append(refs, id)
+			}
+		}
+	default:
+		// vSphere networks have an "id" field, OVA networks an "ID" field
+		for _, network := range toObjectSlice(vm["networks"]) {
+			if id, ok := network["id"].(string); ok {
+				refs = append(refs, id)
+			} else if id, ok := network["ID"].(string); ok {
+				refs = append(refs, id)
+			}
+		}
+	}
+	return refs
+}
+
+// attachNetworkUsage adds to each network the number and sorted names of the VMs with a NIC
+// on it: vmCount, vmNames and vmNamesHuman. A VM with several NICs on a network counts once.
+func attachNetworkUsage(networks, vms []map[string]interface{}, providerType string, profileNetworks map[string]string) {
+	key := "id"
+	if providerType == "openstack" {
+		key = "name"
+	}
+
+	users := make(map[string]map[string]bool)
+	for _, vm := range vms {
+		name, _ := vm["name"].(string)
+		if name == "" {
+			name, _ = vm["id"].(string)
+		}
+		for _, ref := range vmNetworkRefs(providerType, vm, profileNetworks) {
+			if users[ref] == nil {
+				users[ref] = make(map[string]bool)
+			}
+			users[ref][name] = true
+		}
+	}
+
+	for _, network := range networks {
+		ref, _ := network[key].(string)
+		names := make([]string, 0, len(users[ref]))
+		for name := range users[ref] {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		vmNames := make([]interface{}, 0, len(names))
+		for _, name := range names {
+			vmNames = append(vmNames, name)
+		}
+		network["vmCount"] = len(names)
+		network["vmNames"] = vmNames
+		network["vmNamesHuman"] = strings.Join(names, ", ")
+	}
+}
+
+// networkUsageHeaders appends the cross reference columns to the network table columns
+func networkUsageHeaders(headers []output.Column, inUseBy string) []output.Column {
+	headers = append(headers, output.Column{Title: "VMS", Key: "vmCount", ColorFunc: colorizeVMCount})
+	if inUseBy == InUseByNames {
+		headers = append(headers, output.Column{Title: "IN USE BY", Key: "vmNamesHuman"})
+	}
+	return headers
+}
+
+// colorizeVMCount marks networks no VM uses, which need no mapping
+func colorizeVMCount(count string) string {
+	if count == "0" {
+		return output.Yellow(count)
+	}
+	return count
+}
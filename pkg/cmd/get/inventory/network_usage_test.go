@@ -0,0 +1,53 @@
+package inventory
+
+import (
+	"testing"
+)
+
+func TestAttachNetworkUsage(t *testing.T) {
+	networks := []map[string]interface{}{
+		{"id": "net-1", "name": "VM Network"},
+		{"id": "net-2", "name": "Storage"},
+	}
+	vms := []map[string]interface{}{
+		{"name": "web", "networks": []interface{}{
+			map[string]interface{}{"id": "net-1"},
+			map[string]interface{}{"id": "net-1"},
+		}},
+		{"name": "db", "networks": []interface{}{map[string]interface{}{"id": "net-1"}}},
+		{"name": "old"},
+	}
+
+	attachNetworkUsage(networks, vms, "vsphere", nil)
+
+	if networks[0]["vmCount"] != 2 || networks[0]["vmNamesHuman"] != "db, web" {
+		t.Errorf("VM Network usage = %v %v, want 2 and db, web", networks[0]["vmCount"], networks[0]["vmNamesHuman"])
+	}
+	if networks[1]["vmCount"] != 0 || len(networks[1]["vmNames"].([]interface{})) != 0 {
+		t.Errorf("Storage usage = %v, want no VMs", networks[1])
+	}
+}
+
+func TestVMNetworkRefs(t *testing.T) {
+	tests := []struct {
+		providerType string
+		vm           map[string]interface{}
+		want         string
+	}{
+		{"ova", map[string]interface{}{"networks": []interface{}{map[string]interface{}{"ID": "ova-net"}}}, "ova-net"},
+		{"hyperv", map[string]interface{}{"nics": []interface{}{map[string]interface{}{"networkUUID": "hv-net"}}}, "hv-net"},
+		{"ovirt", map[string]interface{}{"nics": []interface{}{map[string]interface{}{"profile": "prof-1"}}}, "ovirt-net"},
+		{"openstack", map[string]interface{}{"addresses": map[string]interface{}{"private": []interface{}{}}}, "private"},
+	}
+	profiles := map[string]string{"prof-1": "ovirt-net"}
+	for _, tt := range tests {
+		got := vmNetworkRefs(tt.providerType, tt.vm, profiles)
+		if len(got) != 1 || got[0] != tt.want {
+			t.Errorf("vmNetworkRefs(%s) = %v, want [%s]", tt.providerType, got, tt.want)
+		}
+	}
+
+	if err := ValidateInUseBy("all"); err == nil {
+		t.Error("ValidateInUseBy(all) succeeded, want an error")
+	}
+}
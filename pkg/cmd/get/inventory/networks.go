@@ -3,7 +3,6 @@ package inventory
 import (
 	"context"
 	"fmt"
-	"strings"
 
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 
@@ -42,16 +41,21 @@ func countNetworkSubnets(network map[string]interface{}) int {
 	return len(subnetsArray)
 }
 
-// ListNetworksWithInsecure queries the provider's network inventory and displays the results with optional insecure TLS skip verification
-func ListNetworksWithInsecure(ctx context.Context, kubeConfigFlags *genericclioptions.ConfigFlags, providerName, namespace string, inventoryURL string, outputFormat string, query string, watchMode bool, insecureSkipTLS bool) error {
+// ListNetworksWithInsecure queries the provider's network inventory and displays the results with optional insecure TLS skip verification.
+// With inUseBy (count or names) each network shows the VMs with a NIC on it.
+func ListNetworksWithInsecure(ctx context.Context, kubeConfigFlags *genericclioptions.ConfigFlags, providerName, namespace string, inventoryURL string, outputFormat string, query string, inUseBy string, watchMode bool, insecureSkipTLS bool) error {
+	if err := ValidateInUseBy(inUseBy); err != nil {
+		return err
+	}
+
 	sq := watch.NewSafeQuery(query)
 
 	return watch.WrapWithWatchAndQuery(watchMode, outputFormat, func() error {
-		return listNetworksOnce(ctx, kubeConfigFlags, providerName, namespace, inventoryURL, outputFormat, sq.Get(), insecureSkipTLS)
+		return listNetworksOnce(ctx, kubeConfigFlags, providerName, namespace, inventoryURL, outputFormat, sq.Get(), inUseBy, insecureSkipTLS)
 	}, watch.DefaultInterval, sq.Set, query)
 }
 
-func listNetworksOnce(ctx context.Context, kubeConfigFlags *genericclioptions.ConfigFlags, providerName, namespace string, inventoryURL string, outputFormat string, query string, insecureSkipTLS bool) error {
+func listNetworksOnce(ctx context.Context, kubeConfigFlags *genericclioptions.ConfigFlags, providerName, namespace string, inventoryURL string, outputFormat string, query string, inUseBy string, insecureSkipTLS bool) error {
 	// Get the provider object
 	provider, err := GetProviderByName(ctx, kubeConfigFlags, providerName, namespace)
 	if err != nil {
@@ -75,7 +79,7 @@ func listNetworksOnce(ctx context.Context, kubeConfigFlags *genericclioptions.Co
 			{Title: "NAME", Key: "name"},
 			{Title: "NAMESPACE", Key: "namespace"},
 			{Title: "ID", Key: "id"},
-			{Title: "CREATED", Key: "object.metadata.creationTimestamp"},
+			{Title: "CREATED", Key: "object.metadata.creationTimestamp", ColorFunc: output.FormatTimeCell},
 		}
 	case "openstack":
 		defaultHeaders = []output.Column{
@@ -155,6 +159,16 @@ func listNetworksOnce(ctx context.Context, kubeConfigFlags *genericclioptions.Co
 		}
 	}
 
+	// Join the networks with the VM NICs before the query, so it can filter on the VM count
+	if inUseBy != "" {
+		vms, profileNetworks, err := fetchNetworkUsageVMs(ctx, providerClient, providerType)
+		if err != nil {
+			return err
+		}
+		attachNetworkUsage(networks, vms, providerType, profileNetworks)
+		defaultHeaders = networkUsageHeaders(defaultHeaders, inUseBy)
+	}
+
 	// Parse and apply query options
 	queryOpts, err := querypkg.ParseQueryString(query)
 	if err != nil {
@@ -168,13 +182,17 @@ func listNetworksOnce(ctx context.Context, kubeConfigFlags *genericclioptions.Co
 	}
 
 	// Format validation
-	outputFormat = strings.ToLower(outputFormat)
-	if outputFormat != "table" && outputFormat != "json" && outputFormat != "yaml" && outputFormat != "markdown" {
+	outputFormat = output.NormalizeFormat(outputFormat)
+	if outputFormat != "table" && outputFormat != "json" && outputFormat != "yaml" && outputFormat != "markdown" && !output.IsTemplateFormat(outputFormat) {
 		return fmt.Errorf("unsupported output format: %s. Supported formats: table, json, yaml, markdown", outputFormat)
 	}
 
 	// Handle different output formats
 	emptyMessage := fmt.Sprintf("No networks found for provider %s", providerName)
+	if output.IsTemplateFormat(outputFormat) {
+		return output.PrintTemplate(networks, outputFormat)
+	}
+
 	switch outputFormat {
 	case "json":
 		return output.PrintJSONWithEmpty(networks, emptyMessage)
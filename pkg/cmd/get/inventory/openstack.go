@@ -76,6 +76,10 @@ func listInstancesOnce(ctx context.Context, kubeConfigFlags *genericclioptions.C
 
 	// Format and display the results
 	emptyMessage := fmt.Sprintf("No instances found for provider %s", providerName)
+	if output.IsTemplateFormat(outputFormat) {
+		return output.PrintTemplate(data, outputFormat)
+	}
+
 	switch outputFormat {
 	case "json":
 		return output.PrintJSONWithEmpty(data, emptyMessage)
@@ -157,6 +161,10 @@ func listImagesOnce(ctx context.Context, kubeConfigFlags *genericclioptions.Conf
 
 	// Format and display the results
 	emptyMessage := fmt.Sprintf("No images found for provider %s", providerName)
+	if output.IsTemplateFormat(outputFormat) {
+		return output.PrintTemplate(data, outputFormat)
+	}
+
 	switch outputFormat {
 	case "json":
 		return output.PrintJSONWithEmpty(data, emptyMessage)
@@ -239,6 +247,10 @@ func listFlavorsOnce(ctx context.Context, kubeConfigFlags *genericclioptions.Con
 
 	// Format and display the results
 	emptyMessage := fmt.Sprintf("No flavors found for provider %s", providerName)
+	if output.IsTemplateFormat(outputFormat) {
+		return output.PrintTemplate(data, outputFormat)
+	}
+
 	switch outputFormat {
 	case "json":
 		return output.PrintJSONWithEmpty(data, emptyMessage)
@@ -317,6 +329,10 @@ func listProjectsOnce(ctx context.Context, kubeConfigFlags *genericclioptions.Co
 
 	// Format and display the results
 	emptyMessage := fmt.Sprintf("No projects found for provider %s", providerName)
+	if output.IsTemplateFormat(outputFormat) {
+		return output.PrintTemplate(data, outputFormat)
+	}
+
 	switch outputFormat {
 	case "json":
 		return output.PrintJSONWithEmpty(data, emptyMessage)
@@ -399,6 +415,10 @@ func listVolumesOnce(ctx context.Context, kubeConfigFlags *genericclioptions.Con
 
 	// Format and display the results
 	emptyMessage := fmt.Sprintf("No volumes found for provider %s", providerName)
+	if output.IsTemplateFormat(outputFormat) {
+		return output.PrintTemplate(data, outputFormat)
+	}
+
 	switch outputFormat {
 	case "json":
 		return output.PrintJSONWithEmpty(data, emptyMessage)
@@ -476,6 +496,10 @@ func listVolumeTypesOnce(ctx context.Context, kubeConfigFlags *genericclioptions
 
 	// Format and display the results
 	emptyMessage := fmt.Sprintf("No volume types found for provider %s", providerName)
+	if output.IsTemplateFormat(outputFormat) {
+		return output.PrintTemplate(data, outputFormat)
+	}
+
 	switch outputFormat {
 	case "json":
 		return output.PrintJSONWithEmpty(data, emptyMessage)
@@ -557,6 +581,10 @@ func listSnapshotsOnce(ctx context.Context, kubeConfigFlags *genericclioptions.C
 
 	// Format and display the results
 	emptyMessage := fmt.Sprintf("No snapshots found for provider %s", providerName)
+	if output.IsTemplateFormat(outputFormat) {
+		return output.PrintTemplate(data, outputFormat)
+	}
+
 	switch outputFormat {
 	case "json":
 		return output.PrintJSONWithEmpty(data, emptyMessage)
@@ -637,6 +665,10 @@ func listSubnetsOnce(ctx context.Context, kubeConfigFlags *genericclioptions.Con
 
 	// Format and display the results
 	emptyMessage := fmt.Sprintf("No subnets found for provider %s", providerName)
+	if output.IsTemplateFormat(outputFormat) {
+		return output.PrintTemplate(data, outputFormat)
+	}
+
 	switch outputFormat {
 	case "json":
 		return output.PrintJSONWithEmpty(data, emptyMessage)
@@ -0,0 +1,126 @@
+package inventory
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"sigs.k8s.io/yaml"
+
+	planv1beta1 "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+)
+
+// PlanVM is an entry of the planvms output format: a plan VM and the source provider
+// (namespace/name) it was listed from, so create plan can check the VMs of a file
+// belong to its source provider
+type PlanVM struct {
+	planv1beta1.VM `yaml:",inline"`
+	Provider       string `json:"provider,omitempty" yaml:"provider,omitempty"`
+}
+
+// PlanVMsOf returns the plan VMs of planvms entries
+func PlanVMsOf(entries []PlanVM) []planv1beta1.VM {
+	if entries == nil {
+		return nil
+	}
+	vms := make([]planv1beta1.VM, 0, len(entries))
+	for _, entry := range entries {
+		vms = append(vms, entry.VM)
+	}
+	return vms
+}
+
+// PlanVMOverrides are per-VM plan settings keyed by VM name, e.g. target names, instance
+// types and LUKS secrets, kept apart from the inventory query that selects the VMs. Every
+// entry holds plan VM fields with their plan spec names (targetName, instanceType, luks, ...).
+type PlanVMOverrides map[string]map[string]interface{}
+
+// LoadPlanVMOverrides reads an overrides file (YAML or JSON) and checks that every entry
+// only sets known plan VM fields, and does not change the VM name or ID
+func LoadPlanVMOverrides(path string) (PlanVMOverrides, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read overrides file %s: %v", path, err)
+	}
+
+	overrides := PlanVMOverrides{}
+	if err := yaml.Unmarshal(content, &overrides); err != nil {
+		return nil, fmt.Errorf("failed to parse overrides file %s: expected a map of VM names to plan VM fields: %v", path, err)
+	}
+
+	for vmName, fields := range overrides {
+		for _, key := range []string{"id", "name"} {
+			if _, ok := fields[key]; ok {
+				return nil, fmt.Errorf("overrides of VM '%s' in %s: the VM %s cannot be overridden", vmName, path, key)
+			}
+		}
+		data, err := json.Marshal(fields)
+		if err != nil {
+			return nil, fmt.Errorf("overrides of VM '%s' in %s: %v", vmName, path, err)
+		}
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&planv1beta1.VM{}); err != nil {
+			return nil, fmt.Errorf("overrides of VM '%s' in %s: %v", vmName, path, err)
+		}
+	}
+	return overrides, nil
+}
+
+// MergePlanVMOverrides applies the overrides to the plan VMs with a matching name: nested
+// fields are merged, other fields replaced. It returns the names of the overridden VMs that
+// are not in the list, typically VMs the query no longer selects.
+func MergePlanVMOverrides(planVMs []PlanVM, overrides PlanVMOverrides) ([]string, error) {
+	matched := map[string]bool{}
+	for i := range planVMs {
+		fields, ok := overrides[planVMs[i].Name]
+		if !ok {
+			continue
+		}
+		matched[planVMs[i].Name] = true
+
+		data, err := json.Marshal(planVMs[i].VM)
+		if err != nil {
+			return nil, err
+		}
+		merged := map[string]interface{}{}
+		if err := json.Unmarshal(data, &merged); err != nil {
+			return nil, err
+		}
+		mergeFields(merged, fields)
+
+		data, err = json.Marshal(merged)
+		if err != nil {
+			return nil, err
+		}
+		vm := planv1beta1.VM{}
+		if err := json.Unmarshal(data, &vm); err != nil {
+			return nil, fmt.Errorf("overrides of VM '%s': %v", planVMs[i].Name, err)
+		}
+		planVMs[i].VM = vm
+	}
+
+	var unmatched []string
+	for vmName := range overrides {
+		if !matched[vmName] {
+			unmatched = append(unmatched, vmName)
+		}
+	}
+	sort.Strings(unmatched)
+	return unmatched, nil
+}
+
+// mergeFields merges src into dst, recursing into maps present in both
+func mergeFields(dst, src map[string]interface{}) {
+	for key, value := range src {
+		srcMap, srcIsMap := value.(map[string]interface{})
+		dstMap, dstIsMap := dst[key].(map[string]interface{})
+		if srcIsMap && dstIsMap {
+			mergeFields(dstMap, srcMap)
+			continue
+		}
+		dst[key] = value
+	}
+}
@@ -0,0 +1,94 @@
+package inventory
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeOverrides(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "overrides.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestMergePlanVMOverrides(t *testing.T) {
+	overrides, err := LoadPlanVMOverrides(writeOverrides(t, `
+web-01:
+  targetName: web-prod-01
+  instanceType: u1.large
+db-01:
+  luks:
+    name: db-01-luks
+retired-01:
+  targetName: gone
+`))
+	if err != nil {
+		t.Fatalf("LoadPlanVMOverrides() unexpected error: %v", err)
+	}
+
+	planVMs := []PlanVM{{Provider: "demo/vsphere"}, {Provider: "demo/vsphere"}, {Provider: "demo/vsphere"}}
+	planVMs[0].Name, planVMs[0].ID = "web-01", "vm-1"
+	planVMs[1].Name, planVMs[1].ID = "db-01", "vm-2"
+	planVMs[2].Name, planVMs[2].ID = "app-01", "vm-3"
+	planVMs[2].InstanceType = "u1.small"
+
+	unmatched, err := MergePlanVMOverrides(planVMs, overrides)
+	if err != nil {
+		t.Fatalf("MergePlanVMOverrides() unexpected error: %v", err)
+	}
+	if strings.Join(unmatched, ",") != "retired-01" {
+		t.Errorf("unmatched = %v, want [retired-01]", unmatched)
+	}
+
+	web := planVMs[0]
+	if web.TargetName != "web-prod-01" || web.InstanceType != "u1.large" || web.ID != "vm-1" || web.Provider != "demo/vsphere" {
+		t.Errorf("web-01 = %+v, want the overrides merged and the VM identity kept", web)
+	}
+	if planVMs[1].LUKS.Name != "db-01-luks" || planVMs[1].TargetName != "" {
+		t.Errorf("db-01 LUKS = %q, target name = %q, want db-01-luks and no target name", planVMs[1].LUKS.Name, planVMs[1].TargetName)
+	}
+	if planVMs[2].InstanceType != "u1.small" {
+		t.Errorf("app-01 instance type = %q, VMs without overrides must be unchanged", planVMs[2].InstanceType)
+	}
+}
+
+func TestLoadPlanVMOverridesErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"unknown field", "web-01:\n  targetNmae: web\n", "targetNmae"},
+		{"VM identity", "web-01:\n  id: vm-9\n", "cannot be overridden"},
+		{"not a map", "- web-01\n", "expected a map"},
+	}
+	for _, tt := range tests {
+		_, err := LoadPlanVMOverrides(writeOverrides(t, tt.content))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: LoadPlanVMOverrides() error = %v, want it to mention %q", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestBaseFieldsOnly(t *testing.T) {
+	tests := []struct {
+		providerType string
+		fields       []string
+		want         bool
+	}{
+		{"vsphere", []string{"name", "id"}, true},
+		{"vsphere", []string{"name", "powerStateHuman"}, false},
+		{"vsphere", nil, false},
+		{"ec2", []string{"name"}, false},
+	}
+	for _, tt := range tests {
+		if got := baseFieldsOnly(tt.providerType, tt.fields); got != tt.want {
+			t.Errorf("baseFieldsOnly(%s, %v) = %v, want %v", tt.providerType, tt.fields, got, tt.want)
+		}
+	}
+}
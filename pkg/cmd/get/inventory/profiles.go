@@ -74,6 +74,10 @@ func listDiskProfilesOnce(ctx context.Context, kubeConfigFlags *genericclioption
 
 	// Format and display the results
 	emptyMessage := fmt.Sprintf("No disk profiles found for provider %s", providerName)
+	if output.IsTemplateFormat(outputFormat) {
+		return output.PrintTemplate(data, outputFormat)
+	}
+
 	switch outputFormat {
 	case "json":
 		return output.PrintJSONWithEmpty(data, emptyMessage)
@@ -153,6 +157,10 @@ func listNICProfilesOnce(ctx context.Context, kubeConfigFlags *genericclioptions
 
 	// Format and display the results
 	emptyMessage := fmt.Sprintf("No NIC profiles found for provider %s", providerName)
+	if output.IsTemplateFormat(outputFormat) {
+		return output.PrintTemplate(data, outputFormat)
+	}
+
 	switch outputFormat {
 	case "json":
 		return output.PrintJSONWithEmpty(data, emptyMessage)
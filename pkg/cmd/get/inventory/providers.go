@@ -3,7 +3,6 @@ package inventory
 import (
 	"context"
 	"fmt"
-	"strings"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -92,7 +91,7 @@ func getProviders(ctx context.Context, dynamicClient dynamic.Interface, namespac
 	if namespace != "" {
 		return dynamicClient.Resource(client.ProvidersGVR).Namespace(namespace).List(ctx, metav1.ListOptions{})
 	} else {
-		return dynamicClient.Resource(client.ProvidersGVR).Namespace(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+		return client.ListAllNamespaces(ctx, dynamicClient, client.ProvidersGVR, metav1.ListOptions{})
 	}
 }
 
@@ -111,7 +110,7 @@ func getSpecificProvider(ctx context.Context, dynamicClient dynamic.Interface, n
 		}, nil
 	} else {
 		// If no namespace specified, list all and filter by name
-		providers, err := dynamicClient.Resource(client.ProvidersGVR).Namespace(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+		providers, err := client.ListAllNamespaces(ctx, dynamicClient, client.ProvidersGVR, metav1.ListOptions{})
 		if err != nil {
 			return nil, fmt.Errorf("failed to list providers: %v", err)
 		}
@@ -156,8 +155,8 @@ func ListProviders(ctx context.Context, configFlags *genericclioptions.ConfigFla
 	}
 
 	// Format validation
-	outputFormat = strings.ToLower(outputFormat)
-	if outputFormat != "table" && outputFormat != "json" && outputFormat != "yaml" && outputFormat != "markdown" {
+	outputFormat = output.NormalizeFormat(outputFormat)
+	if outputFormat != "table" && outputFormat != "json" && outputFormat != "yaml" && outputFormat != "markdown" && !output.IsTemplateFormat(outputFormat) {
 		return fmt.Errorf("unsupported output format: %s. Supported formats: table, json, yaml, markdown", outputFormat)
 	}
 
@@ -331,6 +330,10 @@ func ListProviders(ctx context.Context, configFlags *genericclioptions.ConfigFla
 	}
 
 	// Handle different output formats
+	if output.IsTemplateFormat(outputFormat) {
+		return output.PrintTemplate(items, outputFormat)
+	}
+
 	switch outputFormat {
 	case "json":
 		jsonPrinter := output.NewJSONPrinter().
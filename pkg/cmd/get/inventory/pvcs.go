@@ -48,7 +48,7 @@ func listPersistentVolumeClaimsOnce(ctx context.Context, kubeConfigFlags *generi
 			{Title: "CAPACITY", Key: "object.status.capacity.storage"},
 			{Title: "STORAGE_CLASS", Key: "object.spec.storageClassName"},
 			{Title: "ACCESS_MODES", Key: "object.spec.accessModes"},
-			{Title: "CREATED", Key: "object.metadata.creationTimestamp"},
+			{Title: "CREATED", Key: "object.metadata.creationTimestamp", ColorFunc: output.FormatTimeCell},
 		}
 	default:
 		return fmt.Errorf("provider type '%s' does not support persistent volume claim inventory", providerType)
@@ -130,6 +130,10 @@ func listPersistentVolumeClaimsOnce(ctx context.Context, kubeConfigFlags *generi
 
 	// Format and display the results
 	emptyMessage := fmt.Sprintf("No persistent volume claims found for provider %s", providerName)
+	if output.IsTemplateFormat(outputFormat) {
+		return output.PrintTemplate(pvcs, outputFormat)
+	}
+
 	switch outputFormat {
 	case "json":
 		return output.PrintJSONWithEmpty(pvcs, emptyMessage)
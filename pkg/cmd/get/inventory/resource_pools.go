@@ -116,6 +116,10 @@ func listResourcePoolsOnce(ctx context.Context, kubeConfigFlags *genericclioptio
 
 	// Format and display the results
 	emptyMessage := fmt.Sprintf("No resource pools found for provider %s", providerName)
+	if output.IsTemplateFormat(outputFormat) {
+		return output.PrintTemplate(resourcePools, outputFormat)
+	}
+
 	switch outputFormat {
 	case "json":
 		return output.PrintJSONWithEmpty(resourcePools, emptyMessage)
@@ -3,7 +3,6 @@ package inventory
 import (
 	"context"
 	"fmt"
-	"strings"
 
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 
@@ -22,21 +21,11 @@ func ListStorageWithInsecure(ctx context.Context, kubeConfigFlags *genericcliopt
 }
 
 func listStorageOnce(ctx context.Context, kubeConfigFlags *genericclioptions.ConfigFlags, providerName, namespace string, inventoryURL string, outputFormat string, query string, insecureSkipTLS bool) error {
-	// Get the provider object
-	provider, err := GetProviderByName(ctx, kubeConfigFlags, providerName, namespace)
+	storages, providerType, err := fetchStorage(ctx, kubeConfigFlags, providerName, namespace, inventoryURL, insecureSkipTLS)
 	if err != nil {
 		return err
 	}
 
-	// Create a new provider client
-	providerClient := NewProviderClientWithInsecure(kubeConfigFlags, provider, inventoryURL, insecureSkipTLS)
-
-	// Get provider type to determine which storage resource to fetch
-	providerType, err := providerClient.GetProviderType()
-	if err != nil {
-		return fmt.Errorf("failed to get provider type: %v", err)
-	}
-
 	// Define default headers based on provider type
 	var defaultHeaders []output.Column
 	switch providerType {
@@ -65,6 +54,59 @@ func listStorageOnce(ctx context.Context, kubeConfigFlags *genericclioptions.Con
 		}
 	}
 
+	// Parse and apply query options
+	queryOpts, err := querypkg.ParseQueryString(query)
+	if err != nil {
+		return fmt.Errorf("invalid query string: %v", err)
+	}
+
+	// Apply query options (sorting, filtering, limiting)
+	storages, err = querypkg.ApplyQuery(storages, queryOpts)
+	if err != nil {
+		return fmt.Errorf("error applying query: %v", err)
+	}
+
+	// Format validation
+	outputFormat = output.NormalizeFormat(outputFormat)
+	if outputFormat != "table" && outputFormat != "json" && outputFormat != "yaml" && outputFormat != "markdown" && !output.IsTemplateFormat(outputFormat) {
+		return fmt.Errorf("unsupported output format: %s. Supported formats: table, json, yaml, markdown", outputFormat)
+	}
+
+	// Handle different output formats
+	emptyMessage := fmt.Sprintf("No storage resources found for provider %s", providerName)
+	if output.IsTemplateFormat(outputFormat) {
+		return output.PrintTemplate(storages, outputFormat)
+	}
+
+	switch outputFormat {
+	case "json":
+		return output.PrintJSONWithEmpty(storages, emptyMessage)
+	case "yaml":
+		return output.PrintYAMLWithEmpty(storages, emptyMessage)
+	case "markdown":
+		return output.PrintMarkdownWithQuery(storages, defaultHeaders, queryOpts, emptyMessage)
+	default:
+		return output.PrintTableWithQuery(storages, defaultHeaders, queryOpts, emptyMessage)
+	}
+}
+
+// fetchStorage returns the storage inventory of a provider, with humanized capacity and free space, and the provider type
+func fetchStorage(ctx context.Context, kubeConfigFlags *genericclioptions.ConfigFlags, providerName, namespace string, inventoryURL string, insecureSkipTLS bool) ([]map[string]interface{}, string, error) {
+	// Get the provider object
+	provider, err := GetProviderByName(ctx, kubeConfigFlags, providerName, namespace)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Create a new provider client
+	providerClient := NewProviderClientWithInsecure(kubeConfigFlags, provider, inventoryURL, insecureSkipTLS)
+
+	// Get provider type to determine which storage resource to fetch
+	providerType, err := providerClient.GetProviderType()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get provider type: %v", err)
+	}
+
 	// Fetch storage inventory based on provider type
 	var data interface{}
 	switch providerType {
@@ -86,7 +128,7 @@ func listStorageOnce(ctx context.Context, kubeConfigFlags *genericclioptions.Con
 	}
 
 	if err != nil {
-		return fmt.Errorf("failed to fetch storage inventory: %v", err)
+		return nil, "", fmt.Errorf("failed to fetch storage inventory: %v", err)
 	}
 
 	// Extract objects from EC2 envelope
@@ -97,7 +139,7 @@ func listStorageOnce(ctx context.Context, kubeConfigFlags *genericclioptions.Con
 	// Verify data is an array
 	dataArray, ok := data.([]interface{})
 	if !ok {
-		return fmt.Errorf("unexpected data format: expected array for storage inventory")
+		return nil, "", fmt.Errorf("unexpected data format: expected array for storage inventory")
 	}
 
 	// Convert to expected format
@@ -128,34 +170,5 @@ func listStorageOnce(ctx context.Context, kubeConfigFlags *genericclioptions.Con
 		}
 	}
 
-	// Parse and apply query options
-	queryOpts, err := querypkg.ParseQueryString(query)
-	if err != nil {
-		return fmt.Errorf("invalid query string: %v", err)
-	}
-
-	// Apply query options (sorting, filtering, limiting)
-	storages, err = querypkg.ApplyQuery(storages, queryOpts)
-	if err != nil {
-		return fmt.Errorf("error applying query: %v", err)
-	}
-
-	// Format validation
-	outputFormat = strings.ToLower(outputFormat)
-	if outputFormat != "table" && outputFormat != "json" && outputFormat != "yaml" && outputFormat != "markdown" {
-		return fmt.Errorf("unsupported output format: %s. Supported formats: table, json, yaml, markdown", outputFormat)
-	}
-
-	// Handle different output formats
-	emptyMessage := fmt.Sprintf("No storage resources found for provider %s", providerName)
-	switch outputFormat {
-	case "json":
-		return output.PrintJSONWithEmpty(storages, emptyMessage)
-	case "yaml":
-		return output.PrintYAMLWithEmpty(storages, emptyMessage)
-	case "markdown":
-		return output.PrintMarkdownWithQuery(storages, defaultHeaders, queryOpts, emptyMessage)
-	default:
-		return output.PrintTableWithQuery(storages, defaultHeaders, queryOpts, emptyMessage)
-	}
+	return storages, providerType, nil
 }
@@ -0,0 +1,169 @@
+package inventory
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/yaacov/kubectl-mtv/pkg/util/output"
+	querypkg "github.com/yaacov/kubectl-mtv/pkg/util/query"
+	"github.com/yaacov/kubectl-mtv/pkg/util/watch"
+)
+
+// DefaultMinFreePercent is the free space below which a datastore or storage domain is marked low
+const DefaultMinFreePercent = 20.0
+
+// Headroom values of the capacity view
+const (
+	HeadroomOK  = "OK"
+	HeadroomLow = "LOW"
+)
+
+// storageCapacityProviders are the provider types whose storage inventory reports capacity and free space
+var storageCapacityProviders = map[string]bool{"vsphere": true, "ovirt": true}
+
+// ListStorageCapacityWithInsecure displays the capacity, used and free space of the provider's
+// datastores or storage domains, marking the ones with less than minFreePercent free space
+func ListStorageCapacityWithInsecure(ctx context.Context, kubeConfigFlags *genericclioptions.ConfigFlags, providerName, namespace string, inventoryURL string, outputFormat string, query string, minFreePercent float64, watchMode bool, insecureSkipTLS bool) error {
+	if minFreePercent < 0 || minFreePercent > 100 {
+		return fmt.Errorf("invalid --min-free-percent %g: must be between 0 and 100", minFreePercent)
+	}
+
+	sq := watch.NewSafeQuery(query)
+
+	return watch.WrapWithWatchAndQuery(watchMode, outputFormat, func() error {
+		return listStorageCapacityOnce(ctx, kubeConfigFlags, providerName, namespace, inventoryURL, outputFormat, sq.Get(), minFreePercent, insecureSkipTLS)
+	}, watch.DefaultInterval, sq.Set, query)
+}
+
+func listStorageCapacityOnce(ctx context.Context, kubeConfigFlags *genericclioptions.ConfigFlags, providerName, namespace string, inventoryURL string, outputFormat string, query string, minFreePercent float64, insecureSkipTLS bool) error {
+	storages, providerType, err := fetchStorage(ctx, kubeConfigFlags, providerName, namespace, inventoryURL, insecureSkipTLS)
+	if err != nil {
+		return err
+	}
+	if !storageCapacityProviders[providerType] {
+		return fmt.Errorf("capacity view is not supported for %s providers, storage capacity is reported for vsphere datastores and ovirt storage domains", providerType)
+	}
+
+	for _, storage := range storages {
+		addStorageCapacity(storage, minFreePercent)
+	}
+
+	// Parse and apply query options
+	queryOpts, err := querypkg.ParseQueryString(query)
+	if err != nil {
+		return fmt.Errorf("invalid query string: %v", err)
+	}
+
+	// Apply query options (sorting, filtering, limiting)
+	storages, err = querypkg.ApplyQuery(storages, queryOpts)
+	if err != nil {
+		return fmt.Errorf("error applying query: %v", err)
+	}
+
+	headers := []output.Column{
+		{Title: "NAME", Key: "name"},
+		{Title: "TYPE", Key: "type"},
+		{Title: "CAPACITY", Key: "capacityHuman"},
+		{Title: "USED", Key: "usedHuman"},
+		{Title: "FREE", Key: "freeHuman"},
+		{Title: "FREE%", Key: "freePercentHuman"},
+		{Title: "HEADROOM", Key: "headroom", ColorFunc: colorizeHeadroom},
+	}
+
+	emptyMessage := fmt.Sprintf("No storage resources found for provider %s", providerName)
+	if output.IsTemplateFormat(outputFormat) {
+		return output.PrintTemplate(storages, outputFormat)
+	}
+
+	switch output.NormalizeFormat(outputFormat) {
+	case "json":
+		return output.PrintJSONWithEmpty(storages, emptyMessage)
+	case "yaml":
+		return output.PrintYAMLWithEmpty(storages, emptyMessage)
+	case "markdown":
+		if len(storages) > 0 {
+			storages = append(storages, storageCapacityTotal(storages, minFreePercent))
+		}
+		return output.PrintMarkdownWithQuery(storages, headers, queryOpts, emptyMessage)
+	case "table":
+		if len(storages) > 0 {
+			storages = append(storages, storageCapacityTotal(storages, minFreePercent))
+		}
+		return output.PrintTableWithQuery(storages, headers, queryOpts, emptyMessage)
+	default:
+		return fmt.Errorf("unsupported output format: %s. Supported formats: table, json, yaml, markdown", outputFormat)
+	}
+}
+
+// addStorageCapacity adds the used space, free percentage and headroom of a datastore or storage domain
+func addStorageCapacity(storage map[string]interface{}, minFreePercent float64) {
+	capacity := storageBytes(storage["capacity"])
+	free := storageBytes(storage["free"])
+	used := math.Max(capacity-free, 0)
+
+	storage["used"] = used
+	storage["usedHuman"] = humanizeBytes(used)
+	storage["freePercent"], storage["freePercentHuman"], storage["headroom"] = storageHeadroom(capacity, free, minFreePercent)
+}
+
+// storageCapacityTotal returns the table row summing the capacity of the listed storages
+func storageCapacityTotal(storages []map[string]interface{}, minFreePercent float64) map[string]interface{} {
+	var capacity, free, used float64
+	for _, storage := range storages {
+		capacity += storageBytes(storage["capacity"])
+		free += storageBytes(storage["free"])
+		used += storageBytes(storage["used"])
+	}
+
+	total := map[string]interface{}{
+		"name":          "TOTAL",
+		"capacityHuman": humanizeBytes(capacity),
+		"usedHuman":     humanizeBytes(used),
+		"freeHuman":     humanizeBytes(free),
+	}
+	total["freePercent"], total["freePercentHuman"], total["headroom"] = storageHeadroom(capacity, free, minFreePercent)
+	return total
+}
+
+// storageHeadroom returns the free percentage, as a number and as text, and whether it is below the threshold.
+// Unknown capacity yields an empty headroom.
+func storageHeadroom(capacity, free, minFreePercent float64) (float64, string, string) {
+	if capacity <= 0 {
+		return 0, "", ""
+	}
+	freePercent := math.Round(free/capacity*1000) / 10
+	headroom := HeadroomOK
+	if freePercent < minFreePercent {
+		headroom = HeadroomLow
+	}
+	return freePercent, fmt.Sprintf("%.1f%%", freePercent), headroom
+}
+
+// storageBytes returns a byte count reported by the inventory, 0 when missing
+func storageBytes(value interface{}) float64 {
+	switch v := value.(type) {
+	case float64:
+		return v
+	case int64:
+		return float64(v)
+	case int:
+		return float64(v)
+	default:
+		return 0
+	}
+}
+
+// colorizeHeadroom colors storages with enough free space green and low ones red
+func colorizeHeadroom(headroom string) string {
+	switch headroom {
+	case HeadroomOK:
+		return output.Green(headroom)
+	case HeadroomLow:
+		return output.Red(headroom)
+	default:
+		return headroom
+	}
+}
@@ -0,0 +1,48 @@
+package inventory
+
+import "testing"
+
+const testGiB = 1024 * 1024 * 1024
+
+func TestAddStorageCapacity(t *testing.T) {
+	storage := map[string]interface{}{"name": "ds1", "capacity": float64(100 * testGiB), "free": float64(15 * testGiB)}
+	addStorageCapacity(storage, DefaultMinFreePercent)
+
+	if storage["used"] != float64(85*testGiB) || storage["usedHuman"] != "85.0 GB" {
+		t.Errorf("unexpected used space: %v %v", storage["used"], storage["usedHuman"])
+	}
+	if storage["freePercent"] != 15.0 || storage["freePercentHuman"] != "15.0%" || storage["headroom"] != HeadroomLow {
+		t.Errorf("unexpected headroom: %v %v %v", storage["freePercent"], storage["freePercentHuman"], storage["headroom"])
+	}
+
+	addStorageCapacity(storage, 10)
+	if storage["headroom"] != HeadroomOK {
+		t.Errorf("headroom = %v, want %s with a 10%% threshold", storage["headroom"], HeadroomOK)
+	}
+}
+
+func TestAddStorageCapacity_Unknown(t *testing.T) {
+	storage := map[string]interface{}{"name": "local"}
+	addStorageCapacity(storage, DefaultMinFreePercent)
+	if storage["headroom"] != "" || storage["freePercentHuman"] != "" {
+		t.Errorf("expected no headroom without capacity, got %v", storage)
+	}
+}
+
+func TestStorageCapacityTotal(t *testing.T) {
+	storages := []map[string]interface{}{
+		{"name": "ds1", "capacity": float64(100 * testGiB), "free": float64(10 * testGiB)},
+		{"name": "ds2", "capacity": float64(300 * testGiB), "free": float64(190 * testGiB)},
+	}
+	for _, storage := range storages {
+		addStorageCapacity(storage, DefaultMinFreePercent)
+	}
+
+	total := storageCapacityTotal(storages, DefaultMinFreePercent)
+	if total["capacityHuman"] != "400.0 GB" || total["usedHuman"] != "200.0 GB" || total["freeHuman"] != "200.0 GB" {
+		t.Errorf("unexpected total: %v", total)
+	}
+	if total["freePercent"] != 50.0 || total["headroom"] != HeadroomOK {
+		t.Errorf("unexpected total headroom: %v", total)
+	}
+}
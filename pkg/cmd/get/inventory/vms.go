@@ -3,7 +3,9 @@ package inventory
 import (
 	"context"
 	"fmt"
+	"os"
 	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -13,6 +15,7 @@ import (
 	planv1beta1 "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
 	"github.com/yaacov/kubectl-mtv/pkg/util/output"
 	querypkg "github.com/yaacov/kubectl-mtv/pkg/util/query"
+	"github.com/yaacov/kubectl-mtv/pkg/util/vocab"
 	"github.com/yaacov/kubectl-mtv/pkg/util/watch"
 )
 
@@ -98,11 +101,13 @@ func augmentVMInfo(vm map[string]interface{}) {
 	if memoryMB, exists := vm["memoryMB"]; exists {
 		if memVal, ok := memoryMB.(float64); ok {
 			vm["memoryGB"] = fmt.Sprintf("%.1f GB", memVal/1024)
+			vm["memoryGiB"] = vocab.ToGiB(memVal, vocab.MiB)
 		}
 	}
 
 	totalDiskCapacityGB := calculateTotalDiskCapacity(vm)
 	vm["diskCapacity"] = fmt.Sprintf("%.1f GB", totalDiskCapacityGB)
+	vm["diskGiB"] = vocab.ToGiB(totalDiskCapacityGB, vocab.GiB)
 
 	if storageUsed, exists := vm["storageUsed"]; exists {
 		if storageVal, ok := storageUsed.(float64); ok {
@@ -112,8 +117,22 @@ func augmentVMInfo(vm map[string]interface{}) {
 	}
 
 	augmentFromInstance(vm)
+	augmentPassthroughDevices(vm)
+	augmentPowerState(vm)
+}
+
+// AugmentVM adds the computed fields listed by 'get inventory vm', such as memoryGiB,
+// diskGiB and powerStateHuman, to an inventory VM
+func AugmentVM(vm map[string]interface{}) {
+	augmentVMInfo(vm)
+}
 
-	vm["powerStateHuman"] = humanizePowerState(vm)
+// augmentPowerState adds the power state reported by the provider and its canonical
+// form, so tables and queries use the same values for every provider type.
+func augmentPowerState(vm map[string]interface{}) {
+	raw := vocab.RawPowerState(vm)
+	vm["powerStateRaw"] = raw
+	vm["powerStateHuman"] = vocab.PowerState(raw)
 }
 
 // augmentAzureVMInfo adds computed fields to Azure VM data for display purposes.
@@ -131,46 +150,6 @@ func augmentAzureVMInfo(vm map[string]interface{}) {
 	}
 }
 
-// humanizePowerState derives a human-readable power state from a VM map.
-// It checks top-level powerState (vSphere, oVirt), then falls back to
-// object.status.printableStatus, object.status.phase, and
-// instance.status.phase (OpenShift/KubeVirt).
-func humanizePowerState(vm map[string]interface{}) string {
-	if ps, ok := vm["powerState"].(string); ok && ps != "" {
-		lower := strings.ToLower(ps)
-		if strings.Contains(lower, "on") || strings.Contains(lower, "up") || strings.Contains(lower, "running") {
-			return "On"
-		}
-		return "Off"
-	}
-
-	if ps, found, _ := unstructured.NestedString(vm, "object", "status", "printableStatus"); found && ps != "" {
-		lower := strings.ToLower(ps)
-		if strings.Contains(lower, "running") {
-			return "On"
-		}
-		if strings.Contains(lower, "stopped") || strings.Contains(lower, "off") || strings.Contains(lower, "halted") {
-			return "Off"
-		}
-		return ps
-	}
-
-	for _, prefix := range []string{"object", "instance"} {
-		if phase, found, _ := unstructured.NestedString(vm, prefix, "status", "phase"); found && phase != "" {
-			lower := strings.ToLower(phase)
-			if strings.Contains(lower, "running") {
-				return "On"
-			}
-			if strings.Contains(lower, "stopped") || strings.Contains(lower, "off") {
-				return "Off"
-			}
-			return phase
-		}
-	}
-
-	return ""
-}
-
 // augmentFromInstance extracts runtime info from the optional VirtualMachineInstance
 // data present on OpenShift/KubeVirt VMs. It only fills in fields that are not
 // already populated by the provider inventory.
@@ -209,6 +188,7 @@ func augmentFromInstance(vm map[string]interface{}) {
 			if q, err := resource.ParseQuantity(memStr); err == nil {
 				memGB := float64(q.Value()) / (1024 * 1024 * 1024)
 				vm["memoryGB"] = fmt.Sprintf("%.1f GB", memGB)
+				vm["memoryGiB"] = vocab.ToGiB(float64(q.Value()), vocab.Bytes)
 			}
 		}
 	}
@@ -317,24 +297,50 @@ func FetchVMsByQueryWithInsecure(ctx context.Context, kubeConfigFlags *genericcl
 	return planVMs, nil
 }
 
-// ListVMsWithInsecure queries the provider's VM inventory and displays the results with optional insecure TLS skip verification.
-func ListVMsWithInsecure(ctx context.Context, kubeConfigFlags *genericclioptions.ConfigFlags, providerName, namespace string, inventoryURL string, outputFormat string, query string, watchMode bool, insecureSkipTLS bool) error {
-	sq := watch.NewSafeQuery(query)
+// ListVMsOptions holds the options of listing the VM inventory of a provider
+type ListVMsOptions struct {
+	ProviderName    string
+	Namespace       string
+	InventoryURL    string
+	InsecureSkipTLS bool
+	OutputFormat    string
+	Query           string
+	Watch           bool
+	// ConcernsOnly lists only VMs with migration concerns, ordered by concern severity
+	ConcernsOnly bool
+	// Raw shows and queries power states as reported by the provider instead of their
+	// canonical form
+	Raw bool
+	// Overrides are the per-VM settings merged into the planvms output
+	Overrides PlanVMOverrides
+	// ChangedSince, when not zero, lists only the VMs changed after it
+	ChangedSince time.Time
+	// Fields, when not empty, limit the table columns and the json, yaml and template
+	// output to these fields
+	Fields []string
+}
 
-	return watch.WrapWithWatchAndQuery(watchMode, outputFormat, func() error {
-		return listVMsOnce(ctx, kubeConfigFlags, providerName, namespace, inventoryURL, outputFormat, sq.Get(), insecureSkipTLS)
-	}, watch.DefaultInterval, sq.Set, query)
+// ListVMsWithInsecure queries the provider's VM inventory and displays the results with optional insecure TLS skip verification.
+// The json-stream output prints one VM per line as it is decoded, unless the listing needs every VM first.
+func ListVMsWithInsecure(ctx context.Context, kubeConfigFlags *genericclioptions.ConfigFlags, opts ListVMsOptions) error {
+	sq := watch.NewSafeQuery(opts.Query)
+
+	return watch.WrapWithWatchAndQuery(opts.Watch, opts.OutputFormat, func() error {
+		once := opts
+		once.Query = sq.Get()
+		return listVMsOnce(ctx, kubeConfigFlags, once)
+	}, watch.DefaultInterval, sq.Set, opts.Query)
 }
 
-func listVMsOnce(ctx context.Context, kubeConfigFlags *genericclioptions.ConfigFlags, providerName, namespace string, inventoryURL string, outputFormat string, query string, insecureSkipTLS bool) error {
+func listVMsOnce(ctx context.Context, kubeConfigFlags *genericclioptions.ConfigFlags, opts ListVMsOptions) error {
 	// Get the provider object
-	provider, err := GetProviderByName(ctx, kubeConfigFlags, providerName, namespace)
+	provider, err := GetProviderByName(ctx, kubeConfigFlags, opts.ProviderName, opts.Namespace)
 	if err != nil {
 		return err
 	}
 
 	// Create a new provider client
-	providerClient := NewProviderClientWithInsecure(kubeConfigFlags, provider, inventoryURL, insecureSkipTLS)
+	providerClient := NewProviderClientWithInsecure(kubeConfigFlags, provider, opts.InventoryURL, opts.InsecureSkipTLS)
 
 	// Get provider type to verify VM support
 	providerType, err := providerClient.GetProviderType()
@@ -346,7 +352,21 @@ func listVMsOnce(ctx context.Context, kubeConfigFlags *genericclioptions.ConfigF
 	var data interface{}
 	switch providerType {
 	case "ovirt", "vsphere", "openstack", "ova", "openshift", "ec2", "hyperv", "azure":
-		data, err = providerClient.GetVMs(ctx, 4)
+		detail := 4
+		if opts.Query == "" && !opts.ConcernsOnly && opts.ChangedSince.IsZero() && baseFieldsOnly(providerType, opts.Fields) {
+			// The inventory lists the base fields of VMs without their details
+			detail = 0
+		}
+		if output.NormalizeFormat(opts.OutputFormat) == output.FormatJSONStream {
+			queryOpts, err := querypkg.ParseQueryString(opts.Query)
+			if err != nil {
+				return fmt.Errorf("invalid query string: %v", err)
+			}
+			if canStreamVMs(providerType, queryOpts, opts.ConcernsOnly, opts.ChangedSince) {
+				return streamVMs(ctx, providerClient, provider, providerType, detail, queryOpts, opts.Raw, opts.Fields)
+			}
+		}
+		data, err = providerClient.GetVMs(ctx, detail)
 	default:
 		return fmt.Errorf("provider type '%s' does not support VM inventory", providerType)
 	}
@@ -371,23 +391,47 @@ func listVMsOnce(ctx context.Context, kubeConfigFlags *genericclioptions.ConfigF
 	vms := make([]map[string]interface{}, 0, len(dataArray))
 	for _, item := range dataArray {
 		if vm, ok := item.(map[string]interface{}); ok {
-			vm["provider"] = providerName
+			vm["provider"] = opts.ProviderName
 
 			switch providerType {
 			case "ec2":
-				// EC2 uses raw fields, no augmentation needed
+				// EC2 uses raw fields, only the power state is normalized
+				augmentPowerState(vm)
 			case "azure":
 				augmentAzureVMInfo(vm)
 			default:
 				augmentVMInfo(vm)
 			}
 
+			if opts.Raw {
+				vm["powerStateHuman"] = vm["powerStateRaw"]
+			}
+
 			vms = append(vms, vm)
 		}
 	}
 
+	if !opts.ChangedSince.IsZero() {
+		// Record the revisions of providers without VM update times, so later listings with
+		// --changed-since can tell when each VM changed; plain listings write nothing
+		var journal *RevisionJournal
+		if !hasChangeTimes(providerType) {
+			journal = recordRevisions(vms, provider.GetNamespace(), opts.ProviderName, time.Now())
+		}
+		if journal != nil && journal.Baseline.After(opts.ChangedSince) {
+			fmt.Fprintf(os.Stderr, "Warning: VM revisions of provider %s are recorded since %s, earlier changes are not known\n",
+				opts.ProviderName, output.FormatTimestamp(journal.Baseline, false))
+		}
+		vms = filterChangedSince(vms, providerType, journal, opts.ChangedSince)
+	}
+
+	// Keep only VMs with concerns, most severe first
+	if opts.ConcernsOnly {
+		vms = filterVMsWithConcerns(vms)
+	}
+
 	// Parse and apply query options
-	queryOpts, err := querypkg.ParseQueryString(query)
+	queryOpts, err := querypkg.ParseQueryString(opts.Query)
 	if err != nil {
 		return fmt.Errorf("invalid query string: %v", err)
 	}
@@ -399,30 +443,55 @@ func listVMsOnce(ctx context.Context, kubeConfigFlags *genericclioptions.ConfigF
 	}
 
 	// Format validation
-	outputFormat = strings.ToLower(outputFormat)
-	if outputFormat != "table" && outputFormat != "json" && outputFormat != "yaml" && outputFormat != "markdown" && outputFormat != "planvms" {
-		return fmt.Errorf("unsupported output format: %s. Supported formats: table, json, yaml, markdown, planvms", outputFormat)
+	outputFormat := output.NormalizeFormat(opts.OutputFormat)
+	if outputFormat != "table" && outputFormat != "json" && outputFormat != "yaml" && outputFormat != "markdown" && outputFormat != "planvms" && outputFormat != output.FormatJSONStream && !output.IsTemplateFormat(outputFormat) {
+		return fmt.Errorf("unsupported output format: %s. Supported formats: table, json, yaml, markdown, planvms, json-stream", outputFormat)
 	}
 
 	// Handle different output formats
-	emptyMessage := fmt.Sprintf("No VMs found for provider %s", providerName)
+	emptyMessage := fmt.Sprintf("No VMs found for provider %s", opts.ProviderName)
+	if !opts.ChangedSince.IsZero() {
+		emptyMessage = fmt.Sprintf("No VMs of provider %s changed since %s", opts.ProviderName, output.FormatTimestamp(opts.ChangedSince, false))
+	}
+	if opts.ConcernsOnly {
+		emptyMessage = fmt.Sprintf("No VMs with concerns found for provider %s", opts.ProviderName)
+		if (outputFormat == "table" || outputFormat == "markdown") && len(opts.Fields) == 0 {
+			return printVMConcerns(vms, queryOpts, outputFormat == "markdown", emptyMessage)
+		}
+	}
+	if output.IsTemplateFormat(outputFormat) {
+		return output.PrintTemplate(output.SelectFields(vms, opts.Fields), outputFormat)
+	}
+
+	columns := vmColumns(providerType)
+	if !opts.ChangedSince.IsZero() {
+		columns = append(columns, output.Column{Title: "CHANGED", Key: "lastChanged", ColorFunc: output.FormatTimeCell})
+	}
+	if len(opts.Fields) > 0 {
+		columns = output.FieldColumns(opts.Fields, columns)
+	}
+
 	switch outputFormat {
 	case "json":
-		return output.PrintJSONWithEmpty(vms, emptyMessage)
+		return output.PrintJSONWithEmpty(output.SelectFields(vms, opts.Fields), emptyMessage)
 	case "yaml":
-		return output.PrintYAMLWithEmpty(vms, emptyMessage)
+		return output.PrintYAMLWithEmpty(output.SelectFields(vms, opts.Fields), emptyMessage)
+	case output.FormatJSONStream:
+		// The listing needed every VM first; print it as the same lines a stream would
+		return output.NewJSONStreamPrinter().PrintItems(output.SelectFields(vms, opts.Fields))
 	case "markdown":
-		return printVMsMarkdown(vms, queryOpts, providerType, emptyMessage)
+		return output.PrintMarkdownWithQuery(vms, columns, queryOpts, emptyMessage)
 	case "planvms":
-		// Convert inventory VMs to plan VM structs
-		planVMs := make([]planv1beta1.VM, 0, len(vms))
+		// Convert inventory VMs to plan VM structs, recording the provider they belong to
+		providerRef := provider.GetNamespace() + "/" + provider.GetName()
+		planVMs := make([]PlanVM, 0, len(vms))
 		for _, vm := range vms {
 			vmName, ok := vm["name"].(string)
 			if !ok {
 				continue
 			}
 
-			planVM := planv1beta1.VM{}
+			planVM := PlanVM{Provider: providerRef}
 			planVM.Name = vmName
 
 			// Add ID if available
@@ -433,6 +502,17 @@ func listVMsOnce(ctx context.Context, kubeConfigFlags *genericclioptions.ConfigF
 			planVMs = append(planVMs, planVM)
 		}
 
+		// Merge the per-VM overrides, kept apart from the query that selects the VMs
+		if len(opts.Overrides) > 0 {
+			unmatched, err := MergePlanVMOverrides(planVMs, opts.Overrides)
+			if err != nil {
+				return err
+			}
+			if len(unmatched) > 0 {
+				fmt.Fprintf(os.Stderr, "Warning: overrides for VMs not in the output: %s\n", strings.Join(unmatched, ", "))
+			}
+		}
+
 		// Marshal to YAML
 		yamlData, err := yaml.Marshal(planVMs)
 		if err != nil {
@@ -443,12 +523,39 @@ func listVMsOnce(ctx context.Context, kubeConfigFlags *genericclioptions.ConfigF
 		fmt.Println(string(yamlData))
 		return nil
 	default:
-		return output.PrintTableWithQuery(vms, vmColumns(providerType), queryOpts, emptyMessage)
+		return output.PrintTableWithQuery(vms, columns, queryOpts, emptyMessage)
+	}
+}
+
+// vmBaseFields are the VM fields the inventory lists without details
+var vmBaseFields = map[string]bool{"id": true, "name": true, "path": true, "revision": true, "selfLink": true, "provider": true}
+
+// baseFieldsOnly reports whether the fields can be read from the VM list without
+// details. EC2 and Azure VMs keep their fields in provider-specific objects.
+func baseFieldsOnly(providerType string, fields []string) bool {
+	if len(fields) == 0 || providerType == "ec2" || providerType == "azure" {
+		return false
+	}
+	for _, field := range fields {
+		if !vmBaseFields[field] {
+			return false
+		}
 	}
+	return true
 }
 
-func printVMsMarkdown(vms []map[string]interface{}, queryOpts *querypkg.QueryOptions, providerType, emptyMessage string) error {
-	return output.PrintMarkdownWithQuery(vms, vmColumns(providerType), queryOpts, emptyMessage)
+// printVMConcerns prints the concerns-only VM table followed by the concerns grouped by severity
+func printVMConcerns(vms []map[string]interface{}, queryOpts *querypkg.QueryOptions, markdown bool, emptyMessage string) error {
+	var err error
+	if markdown {
+		err = output.PrintMarkdownWithQuery(vms, vmConcernColumns(), queryOpts, emptyMessage)
+	} else {
+		err = output.PrintTableWithQuery(vms, vmConcernColumns(), queryOpts, emptyMessage)
+	}
+	if err != nil || len(vms) == 0 || (queryOpts != nil && queryOpts.HasSelect) {
+		return err
+	}
+	return printConcernSummary(vms, markdown)
 }
 
 // vmColumns returns the default table columns for VM listings based on provider type.
@@ -458,7 +565,7 @@ func vmColumns(providerType string) []output.Column {
 		return []output.Column{
 			{Title: "NAME", Key: "name"},
 			{Title: "TYPE", Key: "InstanceType"},
-			{Title: "STATE", Key: "State.Name", ColorFunc: output.ColorizeStatus},
+			{Title: "POWER", Key: "powerStateHuman", ColorFunc: output.ColorizePowerState},
 			{Title: "PLATFORM", Key: "PlatformDetails"},
 			{Title: "AZ", Key: "Placement.AvailabilityZone"},
 			{Title: "PUBLIC-IP", Key: "PublicIpAddress"},
@@ -486,6 +593,7 @@ func vmColumns(providerType string) []output.Column {
 			{Title: "DISK USAGE", Key: "storageUsedGB"},
 			{Title: "GUEST OS", Key: "guestId"},
 			{Title: "CONCERNS (C/W/I)", Key: "concernsHuman", ColorFunc: output.ColorizeConcerns},
+			{Title: "DEVICES", Key: "devicesHuman", ColorFunc: output.Yellow},
 		}
 	}
 }
@@ -0,0 +1,75 @@
+package inventory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/yaacov/tree-search-language/v6/pkg/tsl"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/yaacov/kubectl-mtv/pkg/util/client"
+	"github.com/yaacov/kubectl-mtv/pkg/util/output"
+	querypkg "github.com/yaacov/kubectl-mtv/pkg/util/query"
+)
+
+// canStreamVMs reports whether VMs can be printed as they are decoded. Sorting, concerns-only
+// and changed-since listings need every VM first, and EC2 wraps its VMs in an envelope.
+func canStreamVMs(providerType string, queryOpts *querypkg.QueryOptions, concernsOnly bool, changedSince time.Time) bool {
+	return providerType != "ec2" && !queryOpts.HasOrderBy && !concernsOnly && changedSince.IsZero()
+}
+
+// streamVMs prints the VMs of a provider as newline-delimited JSON while they are decoded from
+// the inventory, filtering and limiting them on the way, so memory stays constant however many
+// VMs the provider has.
+func streamVMs(ctx context.Context, providerClient *ProviderClient, provider *unstructured.Unstructured, providerType string, detail int, queryOpts *querypkg.QueryOptions, raw bool, fields []string) error {
+	if queryOpts.HasLimit && queryOpts.Limit == 0 {
+		return nil
+	}
+
+	var tree *tsl.TSLNode
+	if queryOpts.Where != "" {
+		var err error
+		tree, err = querypkg.ParseWhereClause(queryOpts.Where)
+		if err != nil {
+			return fmt.Errorf("error applying query: where clause error: %v", err)
+		}
+	}
+
+	providerName := provider.GetName()
+	printer := output.NewJSONStreamPrinter()
+
+	err := providerClient.StreamVMs(ctx, detail, func(vm map[string]interface{}) error {
+		vm["provider"] = providerName
+		if providerType == "azure" {
+			augmentAzureVMInfo(vm)
+		} else {
+			augmentVMInfo(vm)
+		}
+		if raw {
+			vm["powerStateHuman"] = vm["powerStateRaw"]
+		}
+
+		if tree != nil {
+			match, err := querypkg.MatchItem(vm, tree, queryOpts.Select)
+			if err != nil {
+				return fmt.Errorf("error applying query: where clause error: %v", err)
+			}
+			if !match {
+				return nil
+			}
+		}
+
+		if err := printer.Print(output.SelectFields([]map[string]interface{}{vm}, fields)[0]); err != nil {
+			return err
+		}
+		if queryOpts.HasLimit && queryOpts.Limit > 0 && printer.Count() >= queryOpts.Limit {
+			return client.ErrStopStream
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to fetch VM inventory: %v", err)
+	}
+	return nil
+}
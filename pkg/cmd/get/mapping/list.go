@@ -3,7 +3,6 @@ package mapping
 import (
 	"context"
 	"fmt"
-	"strings"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -79,19 +78,19 @@ func createMappingItem(mapping unstructured.Unstructured, mappingType string, us
 }
 
 // ListMappings lists network and storage mappings without watch functionality
-func ListMappings(ctx context.Context, configFlags *genericclioptions.ConfigFlags, mappingType, namespace, outputFormat string, mappingName string, useUTC bool, query string) error {
-	return listMappings(ctx, configFlags, mappingType, namespace, outputFormat, mappingName, useUTC, query)
+func ListMappings(ctx context.Context, configFlags *genericclioptions.ConfigFlags, mappingType, namespace, outputFormat string, mappingName string, useUTC bool, query string, selector string) error {
+	return listMappings(ctx, configFlags, mappingType, namespace, outputFormat, mappingName, useUTC, query, selector)
 }
 
-// getNetworkMappings retrieves all network mappings from the given namespace
-func getNetworkMappings(ctx context.Context, dynamicClient dynamic.Interface, namespace string, useUTC bool) ([]map[string]interface{}, error) {
+// getNetworkMappings retrieves all network mappings from the given namespace matching the label selector
+func getNetworkMappings(ctx context.Context, dynamicClient dynamic.Interface, namespace, selector string, useUTC bool) ([]map[string]interface{}, error) {
 	var networks *unstructured.UnstructuredList
 	var err error
 
 	if namespace != "" {
-		networks, err = dynamicClient.Resource(client.NetworkMapGVR).Namespace(namespace).List(ctx, metav1.ListOptions{})
+		networks, err = dynamicClient.Resource(client.NetworkMapGVR).Namespace(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
 	} else {
-		networks, err = dynamicClient.Resource(client.NetworkMapGVR).Namespace(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+		networks, err = client.ListAllNamespaces(ctx, dynamicClient, client.NetworkMapGVR, metav1.ListOptions{LabelSelector: selector})
 	}
 
 	if err != nil {
@@ -107,15 +106,15 @@ func getNetworkMappings(ctx context.Context, dynamicClient dynamic.Interface, na
 	return items, nil
 }
 
-// getStorageMappings retrieves all storage mappings from the given namespace
-func getStorageMappings(ctx context.Context, dynamicClient dynamic.Interface, namespace string, useUTC bool) ([]map[string]interface{}, error) {
+// getStorageMappings retrieves all storage mappings from the given namespace matching the label selector
+func getStorageMappings(ctx context.Context, dynamicClient dynamic.Interface, namespace, selector string, useUTC bool) ([]map[string]interface{}, error) {
 	var storage *unstructured.UnstructuredList
 	var err error
 
 	if namespace != "" {
-		storage, err = dynamicClient.Resource(client.StorageMapGVR).Namespace(namespace).List(ctx, metav1.ListOptions{})
+		storage, err = dynamicClient.Resource(client.StorageMapGVR).Namespace(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
 	} else {
-		storage, err = dynamicClient.Resource(client.StorageMapGVR).Namespace(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+		storage, err = client.ListAllNamespaces(ctx, dynamicClient, client.StorageMapGVR, metav1.ListOptions{LabelSelector: selector})
 	}
 
 	if err != nil {
@@ -144,7 +143,7 @@ func getSpecificNetworkMapping(ctx context.Context, dynamicClient dynamic.Interf
 		return []map[string]interface{}{item}, nil
 	} else {
 		// If no namespace specified, list all and filter by name
-		networks, err := dynamicClient.Resource(client.NetworkMapGVR).Namespace(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+		networks, err := client.ListAllNamespaces(ctx, dynamicClient, client.NetworkMapGVR, metav1.ListOptions{})
 		if err != nil {
 			return nil, fmt.Errorf("failed to list network mappings: %v", err)
 		}
@@ -178,7 +177,7 @@ func getSpecificStorageMapping(ctx context.Context, dynamicClient dynamic.Interf
 		return []map[string]interface{}{item}, nil
 	} else {
 		// If no namespace specified, list all and filter by name
-		storage, err := dynamicClient.Resource(client.StorageMapGVR).Namespace(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+		storage, err := client.ListAllNamespaces(ctx, dynamicClient, client.StorageMapGVR, metav1.ListOptions{})
 		if err != nil {
 			return nil, fmt.Errorf("failed to list storage mappings: %v", err)
 		}
@@ -224,17 +223,17 @@ func getSpecificAllMappings(ctx context.Context, dynamicClient dynamic.Interface
 	return allItems, nil
 }
 
-// getAllMappings retrieves all mappings (network and storage) from the given namespace
-func getAllMappings(ctx context.Context, dynamicClient dynamic.Interface, namespace string, useUTC bool) ([]map[string]interface{}, error) {
+// getAllMappings retrieves all mappings (network and storage) from the given namespace matching the label selector
+func getAllMappings(ctx context.Context, dynamicClient dynamic.Interface, namespace, selector string, useUTC bool) ([]map[string]interface{}, error) {
 	var allItems []map[string]interface{}
 
-	networkItems, err := getNetworkMappings(ctx, dynamicClient, namespace, useUTC)
+	networkItems, err := getNetworkMappings(ctx, dynamicClient, namespace, selector, useUTC)
 	if err != nil {
 		return nil, err
 	}
 	allItems = append(allItems, networkItems...)
 
-	storageItems, err := getStorageMappings(ctx, dynamicClient, namespace, useUTC)
+	storageItems, err := getStorageMappings(ctx, dynamicClient, namespace, selector, useUTC)
 	if err != nil {
 		return nil, err
 	}
@@ -244,15 +243,15 @@ func getAllMappings(ctx context.Context, dynamicClient dynamic.Interface, namesp
 }
 
 // listMappings lists network and storage mappings
-func listMappings(ctx context.Context, configFlags *genericclioptions.ConfigFlags, mappingType, namespace, outputFormat string, mappingName string, useUTC bool, query string) error {
+func listMappings(ctx context.Context, configFlags *genericclioptions.ConfigFlags, mappingType, namespace, outputFormat string, mappingName string, useUTC bool, query string, selector string) error {
 	dynamicClient, err := client.GetDynamicClient(configFlags)
 	if err != nil {
 		return fmt.Errorf("failed to get client: %v", err)
 	}
 
 	// Format validation
-	outputFormat = strings.ToLower(outputFormat)
-	if outputFormat != "table" && outputFormat != "json" && outputFormat != "yaml" && outputFormat != "markdown" {
+	outputFormat = output.NormalizeFormat(outputFormat)
+	if outputFormat != "table" && outputFormat != "json" && outputFormat != "yaml" && outputFormat != "markdown" && !output.IsTemplateFormat(outputFormat) {
 		return fmt.Errorf("unsupported output format: %s. Supported formats: table, json, yaml, markdown", outputFormat)
 	}
 
@@ -275,11 +274,11 @@ func listMappings(ctx context.Context, configFlags *genericclioptions.ConfigFlag
 		// Get mappings based on the requested type
 		switch mappingType {
 		case "network":
-			allItems, err = getNetworkMappings(ctx, dynamicClient, namespace, useUTC)
+			allItems, err = getNetworkMappings(ctx, dynamicClient, namespace, selector, useUTC)
 		case "storage":
-			allItems, err = getStorageMappings(ctx, dynamicClient, namespace, useUTC)
+			allItems, err = getStorageMappings(ctx, dynamicClient, namespace, selector, useUTC)
 		case "", "all":
-			allItems, err = getAllMappings(ctx, dynamicClient, namespace, useUTC)
+			allItems, err = getAllMappings(ctx, dynamicClient, namespace, selector, useUTC)
 		default:
 			return fmt.Errorf("unsupported mapping type: %s. Supported types: network, storage, all", mappingType)
 		}
@@ -303,6 +302,10 @@ func listMappings(ctx context.Context, configFlags *genericclioptions.ConfigFlag
 	}
 
 	// Handle output based on format
+	if output.IsTemplateFormat(outputFormat) {
+		return output.PrintTemplate(allItems, outputFormat)
+	}
+
 	switch outputFormat {
 	case "json":
 		jsonPrinter := output.NewJSONPrinter().
@@ -356,8 +359,8 @@ func listMappings(ctx context.Context, configFlags *genericclioptions.ConfigFlag
 }
 
 // List lists network and storage mappings with optional watch mode
-func List(ctx context.Context, configFlags *genericclioptions.ConfigFlags, mappingType, namespace string, watchMode bool, outputFormat string, mappingName string, useUTC bool, query string) error {
+func List(ctx context.Context, configFlags *genericclioptions.ConfigFlags, mappingType, namespace string, watchMode bool, outputFormat string, mappingName string, useUTC bool, query string, selector string) error {
 	return watch.WrapWithWatch(watchMode, outputFormat, func() error {
-		return ListMappings(ctx, configFlags, mappingType, namespace, outputFormat, mappingName, useUTC, query)
+		return ListMappings(ctx, configFlags, mappingType, namespace, outputFormat, mappingName, useUTC, query, selector)
 	}, watch.DefaultInterval)
 }
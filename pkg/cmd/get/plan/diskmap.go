@@ -0,0 +1,330 @@
+package plan
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	forkliftv1beta1 "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/klog/v2"
+
+	"github.com/yaacov/kubectl-mtv/pkg/util/client"
+	"github.com/yaacov/kubectl-mtv/pkg/util/output"
+	querypkg "github.com/yaacov/kubectl-mtv/pkg/util/query"
+	"github.com/yaacov/kubectl-mtv/pkg/util/watch"
+)
+
+// annImportBackingFile is the CDI annotation holding the VDDK backing file of an imported PVC
+const annImportBackingFile = "cdi.kubevirt.io/storage.import.backingFile"
+
+// persistentVolumeClaimsGVR and dataVolumesGVR are used to read the disks created for the migrated VMs
+var (
+	persistentVolumeClaimsGVR = schema.GroupVersionResource{
+		Group:    "",
+		Version:  "v1",
+		Resource: "persistentvolumeclaims",
+	}
+	dataVolumesGVR = schema.GroupVersionResource{
+		Group:    "cdi.kubevirt.io",
+		Version:  "v1beta1",
+		Resource: "datavolumes",
+	}
+)
+
+// snapshotSuffixPattern matches the delta disk suffix of a vSphere backing file (disk-000001.vmdk)
+var snapshotSuffixPattern = regexp.MustCompile(`-\d{6}\.vmdk$`)
+
+// diskMapColumns defines the default columns for the disk map table.
+var diskMapColumns = []output.Column{
+	{Title: "VM", Key: "vm"},
+	{Title: "DATASTORE", Key: "datastore"},
+	{Title: "SOURCE DISK", Key: "path"},
+	{Title: "SOURCE SIZE", Key: "sourceSize"},
+	{Title: "PVC", Key: "pvc"},
+	{Title: "DATAVOLUME", Key: "dataVolume"},
+	{Title: "STORAGE CLASS", Key: "storageClass"},
+	{Title: "SIZE", Key: "targetSize"},
+	{Title: "PHASE", Key: "phase", ColorFunc: output.ColorizeStatus},
+}
+
+// ListDiskMap lists, for each migrated VM of a plan, the source disks and the PVCs and
+// DataVolumes created for them in the target namespace.
+func ListDiskMap(ctx context.Context, configFlags *genericclioptions.ConfigFlags, name, namespace, outputFormat, queryStr string, watchMode bool) error {
+	sq := watch.NewSafeQuery(queryStr)
+
+	return watch.WrapWithWatchAndQuery(watchMode, outputFormat, func() error {
+		return listDiskMapOnce(ctx, configFlags, name, namespace, outputFormat, sq.Get())
+	}, watch.DefaultInterval, sq.Set, queryStr)
+}
+
+func listDiskMapOnce(ctx context.Context, configFlags *genericclioptions.ConfigFlags, name, namespace, outputFormat, queryStr string) error {
+	c, err := client.GetDynamicClient(configFlags)
+	if err != nil {
+		return fmt.Errorf("failed to get client: %v", err)
+	}
+
+	plan, migration, vms, err := getMigrationData(ctx, configFlags, name, namespace)
+	if err != nil {
+		return err
+	}
+
+	items := []map[string]interface{}{}
+	emptyMsg := fmt.Sprintf("No migrated disks found in plan %s", name)
+	if migration == nil {
+		emptyMsg = fmt.Sprintf("Plan %s has no migration yet", name)
+	} else {
+		targetNS, _, _ := unstructured.NestedString(plan.Object, "spec", "targetNamespace")
+		if targetNS == "" {
+			targetNS = plan.GetNamespace()
+		}
+		selector := fmt.Sprintf("plan=%s,migration=%s", plan.GetUID(), migration.GetUID())
+		pvcs := listTargetResources(ctx, c, persistentVolumeClaimsGVR, targetNS, selector)
+		dataVolumes := listTargetResources(ctx, c, dataVolumesGVR, targetNS, selector)
+		items = BuildDiskMap(vms, pvcs, dataVolumes)
+	}
+
+	queryOpts, err := querypkg.ParseQueryString(queryStr)
+	if err != nil {
+		return fmt.Errorf("invalid query string: %v", err)
+	}
+	items, err = querypkg.ApplyQuery(items, queryOpts)
+	if err != nil {
+		return fmt.Errorf("error applying query: %v", err)
+	}
+
+	outputFormat = output.NormalizeFormat(outputFormat)
+	if output.IsTemplateFormat(outputFormat) {
+		return output.PrintTemplate(items, outputFormat)
+	}
+
+	switch outputFormat {
+	case "json":
+		return output.PrintJSONWithEmpty(items, emptyMsg)
+	case "yaml":
+		return output.PrintYAMLWithEmpty(items, emptyMsg)
+	case "markdown":
+		return output.PrintMarkdownWithQuery(items, diskMapColumns, queryOpts, emptyMsg)
+	default:
+		return output.PrintTableWithQuery(items, diskMapColumns, queryOpts, emptyMsg)
+	}
+}
+
+// listTargetResources lists the migration resources of a kind in the target namespace. Missing
+// permissions or a missing CDI installation only leave the affected columns empty.
+func listTargetResources(ctx context.Context, c dynamic.Interface, gvr schema.GroupVersionResource, namespace, selector string) []unstructured.Unstructured {
+	list, err := c.Resource(gvr).Namespace(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		klog.V(1).Infof("Failed to list %s in '%s': %v", gvr.Resource, namespace, err)
+		return nil
+	}
+	return list.Items
+}
+
+// BuildDiskMap returns one row per migrated disk: the source disk of a migration status VM
+// (datastore, path and size from the disk transfer) and the PVC, DataVolume and storage class
+// created for it. Disk transfers without a PVC are listed with empty target columns.
+func BuildDiskMap(vms []interface{}, pvcs, dataVolumes []unstructured.Unstructured) []map[string]interface{} {
+	pvcsByVM := map[string][]*unstructured.Unstructured{}
+	for i := range pvcs {
+		vmID := pvcs[i].GetLabels()["vmID"]
+		pvcsByVM[vmID] = append(pvcsByVM[vmID], &pvcs[i])
+	}
+	dataVolumesByName := map[string]*unstructured.Unstructured{}
+	for i := range dataVolumes {
+		dataVolumesByName[dataVolumes[i].GetName()] = &dataVolumes[i]
+	}
+
+	items := []map[string]interface{}{}
+	for _, v := range vms {
+		vm, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		vmName, _, _ := unstructured.NestedString(vm, "name")
+		vmID, _, _ := unstructured.NestedString(vm, "id")
+		transfers := diskTransferSizes(vm)
+
+		vmPVCs := pvcsByVM[vmID]
+		sort.Slice(vmPVCs, func(i, j int) bool { return vmPVCs[i].GetName() < vmPVCs[j].GetName() })
+
+		matched := map[string]bool{}
+		for _, pvc := range vmPVCs {
+			dv := pvcDataVolume(pvc, dataVolumesByName)
+			source := pvcDiskSource(pvc, dv)
+			sourceBytes, transfer := matchDiskTransfer(transfers, source)
+			if transfer != "" {
+				matched[transfer] = true
+			}
+
+			item := diskMapItem(vmName, vmID, source, sourceBytes)
+			item["pvc"] = pvc.GetName()
+			item["namespace"] = pvc.GetNamespace()
+			if dv != nil {
+				item["dataVolume"] = dv.GetName()
+			}
+			item["storageClass"], _, _ = unstructured.NestedString(pvc.Object, "spec", "storageClassName")
+			item["phase"], _, _ = unstructured.NestedString(pvc.Object, "status", "phase")
+
+			size, _, _ := unstructured.NestedString(pvc.Object, "status", "capacity", "storage")
+			if size == "" {
+				size, _, _ = unstructured.NestedString(pvc.Object, "spec", "resources", "requests", "storage")
+			}
+			if q, err := resource.ParseQuantity(size); err == nil {
+				item["targetBytes"] = q.Value()
+				item["targetSize"] = formatDiskSize(q.Value(), "B")
+			}
+			items = append(items, item)
+		}
+
+		for _, transfer := range transfers {
+			if !matched[transfer.name] {
+				items = append(items, diskMapItem(vmName, vmID, transfer.name, transfer.bytes))
+			}
+		}
+	}
+	return items
+}
+
+// diskMapItem returns a disk map row with the VM and source disk columns set
+func diskMapItem(vmName, vmID, source string, sourceBytes int64) map[string]interface{} {
+	datastore, path := splitDiskSource(source)
+	item := map[string]interface{}{
+		"vm":         vmName,
+		"vmID":       vmID,
+		"sourceDisk": source,
+		"datastore":  datastore,
+		"path":       path,
+		"sourceSize": formatDiskSize(sourceBytes, "B"),
+	}
+	if sourceBytes > 0 {
+		item["sourceBytes"] = sourceBytes
+	}
+	return item
+}
+
+// diskTransfer is a disk transfer task of a migration status VM
+type diskTransfer struct {
+	name  string
+	bytes int64
+}
+
+// diskTransferSizes returns the disk transfer tasks of a VM with their total size in bytes
+func diskTransferSizes(vm map[string]interface{}) []diskTransfer {
+	var transfers []diskTransfer
+	pipeline, _, _ := unstructured.NestedSlice(vm, "pipeline")
+	for _, p := range pipeline {
+		phase, ok := p.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if phaseName, _, _ := unstructured.NestedString(phase, "name"); !strings.HasPrefix(phaseName, "DiskTransfer") {
+			continue
+		}
+		phaseUnit, _, _ := unstructured.NestedString(phase, "annotations", "unit")
+
+		tasks, _, _ := unstructured.NestedSlice(phase, "tasks")
+		for _, t := range tasks {
+			task, ok := t.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			name, _, _ := unstructured.NestedString(task, "name")
+			if name == "" {
+				continue
+			}
+			unit, _, _ := unstructured.NestedString(task, "annotations", "unit")
+			if unit == "" {
+				unit = phaseUnit
+			}
+			total, _, _ := unstructured.NestedInt64(task, "progress", "total")
+			transfers = append(transfers, diskTransfer{name: name, bytes: progressBytes(total, unit)})
+		}
+	}
+	return transfers
+}
+
+// progressBytes converts a Forklift progress value to bytes; DiskTransfer progress is reported in MB
+func progressBytes(value int64, unit string) int64 {
+	switch strings.ToUpper(unit) {
+	case "B", "BYTES":
+		return value
+	case "KB":
+		return value * 1024
+	case "GB":
+		return value * 1024 * 1024 * 1024
+	default:
+		return value * 1024 * 1024
+	}
+}
+
+// matchDiskTransfer returns the size and name of the disk transfer of a source disk.
+// vSphere transfers are named after the base disk, without the snapshot delta suffix.
+func matchDiskTransfer(transfers []diskTransfer, source string) (int64, string) {
+	if source == "" {
+		return 0, ""
+	}
+	base := snapshotSuffixPattern.ReplaceAllString(source, ".vmdk")
+	for _, transfer := range transfers {
+		if transfer.name == source || transfer.name == base {
+			return transfer.bytes, transfer.name
+		}
+	}
+	return 0, ""
+}
+
+// pvcDataVolume returns the DataVolume owning a PVC, or the DataVolume of the same name
+func pvcDataVolume(pvc *unstructured.Unstructured, dataVolumesByName map[string]*unstructured.Unstructured) *unstructured.Unstructured {
+	for _, owner := range pvc.GetOwnerReferences() {
+		if owner.Kind == "DataVolume" {
+			if dv, ok := dataVolumesByName[owner.Name]; ok {
+				return dv
+			}
+			return &unstructured.Unstructured{Object: map[string]interface{}{"metadata": map[string]interface{}{"name": owner.Name}}}
+		}
+	}
+	return dataVolumesByName[pvc.GetName()]
+}
+
+// pvcDiskSource returns the source disk of a PVC: the Forklift disk source annotation, the CDI
+// VDDK backing file, or the disk referenced by the DataVolume source.
+func pvcDiskSource(pvc, dv *unstructured.Unstructured) string {
+	annotations := pvc.GetAnnotations()
+	if source := annotations[forkliftv1beta1.AnnDiskSource]; source != "" {
+		return source
+	}
+	if source := annotations[annImportBackingFile]; source != "" {
+		return source
+	}
+	if dv == nil {
+		return ""
+	}
+	for _, fields := range [][]string{
+		{"spec", "source", "vddk", "backingFile"},
+		{"spec", "source", "imageio", "diskId"},
+		{"spec", "source", "http", "url"},
+	} {
+		if source, _, _ := unstructured.NestedString(dv.Object, fields...); source != "" {
+			return source
+		}
+	}
+	return dv.GetAnnotations()[forkliftv1beta1.AnnDiskSource]
+}
+
+// splitDiskSource splits a vSphere "[datastore] path/disk.vmdk" source into datastore and path;
+// other sources are returned as the path.
+func splitDiskSource(source string) (datastore, path string) {
+	if strings.HasPrefix(source, "[") {
+		if end := strings.Index(source, "]"); end > 0 {
+			return source[1:end], strings.TrimSpace(source[end+1:])
+		}
+	}
+	return "", source
+}
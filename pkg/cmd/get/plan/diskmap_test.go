@@ -0,0 +1,91 @@
+package plan
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestBuildDiskMap(t *testing.T) {
+	vms := []interface{}{
+		map[string]interface{}{
+			"name": "web-01",
+			"id":   "vm-1",
+			"pipeline": []interface{}{
+				map[string]interface{}{
+					"name":        "DiskTransfer",
+					"annotations": map[string]interface{}{"unit": "MB"},
+					"tasks": []interface{}{
+						map[string]interface{}{"name": "[ds1] web-01/web-01.vmdk", "progress": map[string]interface{}{"total": int64(10240)}},
+						map[string]interface{}{"name": "[ds2] web-01/web-01_1.vmdk", "progress": map[string]interface{}{"total": int64(2048)}},
+					},
+				},
+			},
+		},
+	}
+
+	pvcs := []unstructured.Unstructured{
+		{Object: map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"name":            "web-01-disk-a",
+				"namespace":       "target",
+				"labels":          map[string]interface{}{"vmID": "vm-1"},
+				"annotations":     map[string]interface{}{"forklift.konveyor.io/disk-source": "[ds1] web-01/web-01-000001.vmdk"},
+				"ownerReferences": []interface{}{map[string]interface{}{"kind": "DataVolume", "name": "web-01-dv-a", "apiVersion": "cdi.kubevirt.io/v1beta1", "uid": "1"}},
+			},
+			"spec":   map[string]interface{}{"storageClassName": "fast"},
+			"status": map[string]interface{}{"phase": "Bound", "capacity": map[string]interface{}{"storage": "10Gi"}},
+		}},
+		{Object: map[string]interface{}{
+			"metadata": map[string]interface{}{"name": "other-vm-disk", "labels": map[string]interface{}{"vmID": "vm-2"}},
+		}},
+	}
+	dataVolumes := []unstructured.Unstructured{
+		{Object: map[string]interface{}{"metadata": map[string]interface{}{"name": "web-01-dv-a"}}},
+	}
+
+	items := BuildDiskMap(vms, pvcs, dataVolumes)
+	if len(items) != 2 {
+		t.Fatalf("expected 2 rows, got %d: %v", len(items), items)
+	}
+
+	disk := items[0]
+	want := map[string]interface{}{
+		"vm":           "web-01",
+		"datastore":    "ds1",
+		"path":         "web-01/web-01-000001.vmdk",
+		"pvc":          "web-01-disk-a",
+		"dataVolume":   "web-01-dv-a",
+		"storageClass": "fast",
+		"phase":        "Bound",
+		"sourceSize":   "10.0 GB",
+		"targetSize":   "10.0 GB",
+	}
+	for key, value := range want {
+		if disk[key] != value {
+			t.Errorf("%s = %v, want %v", key, disk[key], value)
+		}
+	}
+
+	// The second transfer has no PVC yet
+	pending := items[1]
+	if pending["datastore"] != "ds2" || pending["sourceSize"] != "2.0 GB" || pending["pvc"] != nil {
+		t.Errorf("unexpected row for a transfer without PVC: %v", pending)
+	}
+}
+
+func TestSplitDiskSource(t *testing.T) {
+	tests := []struct {
+		source, datastore, path string
+	}{
+		{"[datastore 1] vm/vm.vmdk", "datastore 1", "vm/vm.vmdk"},
+		{"3b6a7c51-disk-id", "", "3b6a7c51-disk-id"},
+		{"", "", ""},
+	}
+	for _, tt := range tests {
+		datastore, path := splitDiskSource(tt.source)
+		if datastore != tt.datastore || path != tt.path {
+			t.Errorf("splitDiskSource(%q) = %q, %q", tt.source, datastore, path)
+		}
+	}
+}
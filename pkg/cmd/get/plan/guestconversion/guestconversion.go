@@ -0,0 +1,59 @@
+package guestconversion
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// SkipDecisionAnnotation records the guest check done by create plan when a plan skips guest
+// conversion (--skip-guest-conversion), so describe plan can show which VMs are at risk
+const SkipDecisionAnnotation = "kubectl-mtv/skip-guest-conversion"
+
+// Decision is the content of SkipDecisionAnnotation
+type Decision struct {
+	// AtRisk maps the names of VMs whose guests depend on guest conversion to the reason
+	AtRisk map[string]string `json:"atRisk,omitempty"`
+}
+
+// Format encodes the decision as an annotation value
+func Format(decision Decision) (string, error) {
+	data, err := json.Marshal(decision)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode guest conversion decision: %v", err)
+	}
+	return string(data), nil
+}
+
+// Get returns the decision recorded on the plan, or false when the plan has none
+func Get(plan *unstructured.Unstructured) (Decision, bool) {
+	var decision Decision
+	value, ok := plan.GetAnnotations()[SkipDecisionAnnotation]
+	if !ok {
+		return decision, false
+	}
+	if err := json.Unmarshal([]byte(value), &decision); err != nil {
+		return decision, false
+	}
+	return decision, true
+}
+
+// AtRiskVMs returns the names of the at-risk VMs in sorted order
+func (d Decision) AtRiskVMs() []string {
+	names := make([]string, 0, len(d.AtRisk))
+	for name := range d.AtRisk {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Consequence describes what skipping guest conversion means for an at-risk guest
+func Consequence(useCompatibilityMode bool) string {
+	if useCompatibilityMode {
+		return "it will run on emulated SATA/E1000E devices without virtio drivers, and source guest tools are not removed"
+	}
+	return "it may not boot without virtio drivers, use --use-compatibility-mode true or remove --skip-guest-conversion"
+}
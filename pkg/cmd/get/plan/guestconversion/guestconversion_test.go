@@ -0,0 +1,38 @@
+package guestconversion
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestDecisionRoundTrip(t *testing.T) {
+	plan := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	if _, ok := Get(plan); ok {
+		t.Fatal("Get() on a plan without annotation returned a decision")
+	}
+
+	value, err := Format(Decision{AtRisk: map[string]string{"web": "older Linux guest (rhel6_64Guest)", "ad": "Windows guest (windows2019srv_64Guest)"}})
+	if err != nil {
+		t.Fatalf("Format() unexpected error: %v", err)
+	}
+	plan.SetAnnotations(map[string]string{SkipDecisionAnnotation: value})
+
+	decision, ok := Get(plan)
+	if !ok {
+		t.Fatal("Get() did not return the recorded decision")
+	}
+	if names := decision.AtRiskVMs(); len(names) != 2 || names[0] != "ad" || names[1] != "web" {
+		t.Errorf("AtRiskVMs() = %v, want [ad web]", names)
+	}
+
+	plan.SetAnnotations(map[string]string{SkipDecisionAnnotation: "{}"})
+	if decision, ok := Get(plan); !ok || len(decision.AtRisk) != 0 {
+		t.Errorf("Get() with no at-risk VMs = %+v, %t", decision, ok)
+	}
+
+	plan.SetAnnotations(map[string]string{SkipDecisionAnnotation: "not json"})
+	if _, ok := Get(plan); ok {
+		t.Error("Get() accepted an invalid annotation")
+	}
+}
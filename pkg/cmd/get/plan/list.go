@@ -3,13 +3,13 @@ package plan
 import (
 	"context"
 	"fmt"
-	"strings"
 	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/client-go/dynamic"
+	"k8s.io/klog/v2"
 
 	"github.com/yaacov/kubectl-mtv/pkg/cmd/get/plan/status"
 	"github.com/yaacov/kubectl-mtv/pkg/util/client"
@@ -18,15 +18,30 @@ import (
 	"github.com/yaacov/kubectl-mtv/pkg/util/watch"
 )
 
-// getPlans retrieves all plans from the given namespace
-func getPlans(ctx context.Context, dynamicClient dynamic.Interface, namespace string) (*unstructured.UnstructuredList, error) {
+// getPlans retrieves all plans from the given namespace matching the label selector
+func getPlans(ctx context.Context, dynamicClient dynamic.Interface, namespace, selector string) (*unstructured.UnstructuredList, error) {
 	if namespace != "" {
-		return dynamicClient.Resource(client.PlansGVR).Namespace(namespace).List(ctx, metav1.ListOptions{})
+		return dynamicClient.Resource(client.PlansGVR).Namespace(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
 	} else {
-		return dynamicClient.Resource(client.PlansGVR).Namespace(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+		return client.ListAllNamespaces(ctx, dynamicClient, client.PlansGVR, metav1.ListOptions{LabelSelector: selector})
 	}
 }
 
+// getMigrations retrieves the migrations of the given namespace, or of all namespaces
+func getMigrations(ctx context.Context, dynamicClient dynamic.Interface, namespace string) ([]unstructured.Unstructured, error) {
+	var migrations *unstructured.UnstructuredList
+	var err error
+	if namespace != "" {
+		migrations, err = dynamicClient.Resource(client.MigrationsGVR).Namespace(namespace).List(ctx, metav1.ListOptions{})
+	} else {
+		migrations, err = client.ListAllNamespaces(ctx, dynamicClient, client.MigrationsGVR, metav1.ListOptions{})
+	}
+	if err != nil {
+		return nil, err
+	}
+	return migrations.Items, nil
+}
+
 // getSpecificPlan retrieves a specific plan by name
 func getSpecificPlan(ctx context.Context, dynamicClient dynamic.Interface, namespace, planName string) (*unstructured.UnstructuredList, error) {
 	if namespace != "" {
@@ -42,7 +57,7 @@ func getSpecificPlan(ctx context.Context, dynamicClient dynamic.Interface, names
 		}, nil
 	} else {
 		// If no namespace specified, list all and filter by name
-		plans, err := dynamicClient.Resource(client.PlansGVR).Namespace(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+		plans, err := client.ListAllNamespaces(ctx, dynamicClient, client.PlansGVR, metav1.ListOptions{})
 		if err != nil {
 			return nil, fmt.Errorf("failed to list plans: %v", err)
 		}
@@ -65,7 +80,7 @@ func getSpecificPlan(ctx context.Context, dynamicClient dynamic.Interface, names
 }
 
 // ListPlans lists migration plans without watch functionality
-func ListPlans(ctx context.Context, configFlags *genericclioptions.ConfigFlags, namespace string, outputFormat string, planName string, useUTC bool, query string) error {
+func ListPlans(ctx context.Context, configFlags *genericclioptions.ConfigFlags, namespace string, outputFormat string, planName string, useUTC bool, query string, selector string) error {
 	c, err := client.GetDynamicClient(configFlags)
 	if err != nil {
 		return fmt.Errorf("failed to get client: %v", err)
@@ -80,15 +95,21 @@ func ListPlans(ctx context.Context, configFlags *genericclioptions.ConfigFlags,
 		}
 	} else {
 		// Get all plans
-		plans, err = getPlans(ctx, c, namespace)
+		plans, err = getPlans(ctx, c, namespace, selector)
 		if err != nil {
 			return fmt.Errorf("failed to list plans: %v", err)
 		}
 	}
 
+	// List the migrations once for all plans, instead of once per plan
+	migrations, err := getMigrations(ctx, c, namespace)
+	if err != nil {
+		klog.V(2).Infof("Failed to list migrations: %v", err)
+	}
+
 	// Format validation
-	outputFormat = strings.ToLower(outputFormat)
-	if outputFormat != "table" && outputFormat != "json" && outputFormat != "yaml" && outputFormat != "markdown" {
+	outputFormat = output.NormalizeFormat(outputFormat)
+	if outputFormat != "table" && outputFormat != "json" && outputFormat != "yaml" && outputFormat != "markdown" && !output.IsTemplateFormat(outputFormat) {
 		return fmt.Errorf("unsupported output format: %s. Supported formats: table, json, yaml, markdown", outputFormat)
 	}
 
@@ -107,7 +128,7 @@ func ListPlans(ctx context.Context, configFlags *genericclioptions.ConfigFlags,
 		}
 
 		// Get plan details (ready, running migration, status)
-		planDetails, _ := status.GetPlanDetails(c, namespace, &p, client.MigrationsGVR)
+		planDetails, _ := status.PlanDetailsFromMigrations(&p, migrations)
 
 		// Format the VM migration status
 		var vmStatus string
@@ -184,6 +205,10 @@ func ListPlans(ctx context.Context, configFlags *genericclioptions.ConfigFlags,
 	}
 
 	// Handle different output formats
+	if output.IsTemplateFormat(outputFormat) {
+		return output.PrintTemplate(items, outputFormat)
+	}
+
 	switch outputFormat {
 	case "json":
 		// Use JSON printer
@@ -219,7 +244,7 @@ func ListPlans(ctx context.Context, configFlags *genericclioptions.ConfigFlags,
 		output.Column{Title: "VMS", Key: "vms"},
 		output.Column{Title: "READY", Key: "ready", ColorFunc: output.ColorizeConditionStatus},
 		output.Column{Title: "STATUS", Key: "status", ColorFunc: output.ColorizeStatus},
-		output.Column{Title: "PROGRESS", Key: "progress"},
+		output.Column{Title: "PROGRESS", Key: "progress", ColorFunc: output.ColorizeProgress},
 		output.Column{Title: "CUTOVER", Key: "cutover"},
 		output.Column{Title: "ARCHIVED", Key: "archived"},
 		output.Column{Title: "CREATED", Key: "created"},
@@ -250,8 +275,8 @@ func ListPlans(ctx context.Context, configFlags *genericclioptions.ConfigFlags,
 }
 
 // List lists migration plans with optional watch mode
-func List(ctx context.Context, configFlags *genericclioptions.ConfigFlags, namespace string, watchMode bool, outputFormat string, planName string, useUTC bool, query string) error {
+func List(ctx context.Context, configFlags *genericclioptions.ConfigFlags, namespace string, watchMode bool, outputFormat string, planName string, useUTC bool, query string, selector string) error {
 	return watch.WrapWithWatch(watchMode, outputFormat, func() error {
-		return ListPlans(ctx, configFlags, namespace, outputFormat, planName, useUTC, query)
+		return ListPlans(ctx, configFlags, namespace, outputFormat, planName, useUTC, query, selector)
 	}, watch.DefaultInterval)
 }
@@ -0,0 +1,178 @@
+package plan
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/get/plan/status"
+	"github.com/yaacov/kubectl-mtv/pkg/util/client"
+	"github.com/yaacov/kubectl-mtv/pkg/util/describe"
+	"github.com/yaacov/kubectl-mtv/pkg/util/output"
+	"github.com/yaacov/kubectl-mtv/pkg/util/watch"
+)
+
+// markdownVMColumns are the columns of the per-VM status table of the markdown document
+var markdownVMColumns = []describe.TableColumn{
+	{Display: "VM", Key: "name"},
+	{Display: "TARGET", Key: "target"},
+	{Display: "STATUS", Key: "status"},
+	{Display: "PROGRESS", Key: "progress"},
+	{Display: "STARTED", Key: "started"},
+	{Display: "COMPLETED", Key: "completed"},
+	{Display: "DURATION", Key: "duration"},
+	{Display: "ERROR", Key: "error"},
+}
+
+// ListMarkdown prints a plan as a markdown document to paste into change tickets and wikis:
+// the plan settings, its status and the status of each VM. With vmsOnly the settings are
+// left out.
+func ListMarkdown(ctx context.Context, configFlags *genericclioptions.ConfigFlags, name, namespace string, vmsOnly, useUTC, watchMode bool) error {
+	return watch.WrapWithWatch(watchMode, "markdown", func() error {
+		return listMarkdownOnce(ctx, configFlags, name, namespace, vmsOnly, useUTC)
+	}, watch.DefaultInterval)
+}
+
+func listMarkdownOnce(ctx context.Context, configFlags *genericclioptions.ConfigFlags, name, namespace string, vmsOnly, useUTC bool) error {
+	c, err := client.GetDynamicClient(configFlags)
+	if err != nil {
+		return fmt.Errorf("failed to get client: %v", err)
+	}
+
+	p, err := c.Resource(client.PlansGVR).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get plan: %v", err)
+	}
+	planDetails, _ := status.GetPlanDetails(c, namespace, p, client.MigrationsGVR)
+
+	return describe.Print(planMarkdown(p, planDetails, vmsOnly, useUTC), "markdown")
+}
+
+// planMarkdown builds the markdown document of a plan
+func planMarkdown(p *unstructured.Unstructured, planDetails status.PlanDetails, vmsOnly, useUTC bool) *describe.Description {
+	migration := planDetails.RunningMigration
+	if migration == nil {
+		migration = planDetails.LatestMigration
+	}
+
+	b := describe.NewBuilder(fmt.Sprintf("MIGRATION PLAN: %s", p.GetName()))
+	if !vmsOnly {
+		b.Section("SETTINGS")
+		for _, field := range planSettingsFields(p, useUTC) {
+			b.Field(field[0], field[1])
+		}
+
+		b.Section("STATUS")
+		b.Field("Ready", fmt.Sprintf("%t", planDetails.IsReady))
+		b.Field("Status", planDetails.Status)
+		if migration != nil {
+			b.Field("Migration", migration.GetName())
+		}
+		if planDetails.VMStats.Total > 0 {
+			b.Field("VMs", fmt.Sprintf("%d/%d completed (succeeded %d, failed %d, canceled %d)",
+				planDetails.VMStats.Completed, planDetails.VMStats.Total,
+				planDetails.VMStats.Succeeded, planDetails.VMStats.Failed, planDetails.VMStats.Canceled))
+		}
+		if planDetails.DiskProgress.Total > 0 {
+			b.Field("Disk Transfer", fmt.Sprintf("%.1f%% (%d/%d GB)",
+				float64(planDetails.DiskProgress.Completed)/float64(planDetails.DiskProgress.Total)*100,
+				planDetails.DiskProgress.Completed/1024, planDetails.DiskProgress.Total/1024))
+		}
+	}
+
+	if rows := planMarkdownVMRows(p, buildMigrationVMMap(planDetails)); len(rows) > 0 {
+		b.Section("VIRTUAL MACHINES")
+		b.Table(markdownVMColumns, rows)
+	}
+
+	return b.Build()
+}
+
+// planSettingsFields returns the label/value pairs of the plan spec
+func planSettingsFields(p *unstructured.Unstructured, useUTC bool) [][2]string {
+	spec := func(fields ...string) string {
+		value, _, _ := unstructured.NestedString(p.Object, append([]string{"spec"}, fields...)...)
+		return value
+	}
+	vms, _, _ := unstructured.NestedSlice(p.Object, "spec", "vms")
+	archived, _, _ := unstructured.NestedBool(p.Object, "spec", "archived")
+
+	fields := [][2]string{
+		{"Name", p.GetName()},
+		{"Namespace", p.GetNamespace()},
+	}
+	if description := spec("description"); description != "" {
+		fields = append(fields, [2]string{"Description", description})
+	}
+	fields = append(fields,
+		[2]string{"Source Provider", spec("provider", "source", "name")},
+		[2]string{"Target Provider", spec("provider", "destination", "name")},
+		[2]string{"Target Namespace", valueOrDash(spec("targetNamespace"))},
+		[2]string{"Migration Type", status.GetMigrationType(p)},
+		[2]string{"Network Map", valueOrDash(spec("map", "network", "name"))},
+		[2]string{"Storage Map", valueOrDash(spec("map", "storage", "name"))},
+		[2]string{"VMs", fmt.Sprintf("%d", len(vms))},
+		[2]string{"Archived", fmt.Sprintf("%t", archived)},
+		[2]string{"Created", output.FormatTimestamp(p.GetCreationTimestamp().Time, useUTC)},
+	)
+	return fields
+}
+
+// planMarkdownVMRows returns a row for each VM of the plan spec with its migration status
+func planMarkdownVMRows(p *unstructured.Unstructured, migrationVMs map[string]map[string]interface{}) []map[string]string {
+	specVMs, _, _ := unstructured.NestedSlice(p.Object, "spec", "vms")
+	rows := make([]map[string]string, 0, len(specVMs))
+	for _, v := range specVMs {
+		specVM, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		id, _, _ := unstructured.NestedString(specVM, "id")
+		name, _, _ := unstructured.NestedString(specVM, "name")
+		targetName, _, _ := unstructured.NestedString(specVM, "targetName")
+
+		migVM := migrationVMs[id]
+		if name == "" && migVM != nil {
+			name, _, _ = unstructured.NestedString(migVM, "name")
+		}
+		row := map[string]string{
+			"name":      valueOrDash(name),
+			"target":    resolveTargetName(targetName, migVM, name),
+			"status":    "Not started",
+			"progress":  "-",
+			"started":   "-",
+			"completed": "-",
+			"duration":  "-",
+			"error":     "",
+		}
+		if migVM != nil {
+			started, _, _ := unstructured.NestedString(migVM, "started")
+			completed, _, _ := unstructured.NestedString(migVM, "completed")
+			reasons, _, _ := unstructured.NestedStringSlice(migVM, "error", "reasons")
+
+			row["status"] = getVMCompletionStatus(migVM)
+			if row["status"] == status.StatusUnknown {
+				row["status"] = status.StatusRunning
+			}
+			row["progress"] = buildProgressString(migVM)
+			row["started"] = valueOrDash(started)
+			row["completed"] = valueOrDash(completed)
+			row["duration"] = formatDuration(started, completed)
+			row["error"] = strings.Join(reasons, "; ")
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// valueOrDash returns "-" for empty values
+func valueOrDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
@@ -0,0 +1,105 @@
+package plan
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/get/plan/status"
+	"github.com/yaacov/kubectl-mtv/pkg/util/describe"
+)
+
+func markdownPlan() *unstructured.Unstructured {
+	p := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"description":     "Wave 1",
+			"targetNamespace": "prod",
+			"warm":            true,
+			"provider": map[string]interface{}{
+				"source":      map[string]interface{}{"name": "vsphere"},
+				"destination": map[string]interface{}{"name": "host"},
+			},
+			"map": map[string]interface{}{
+				"network": map[string]interface{}{"name": "wave1-network"},
+			},
+			"vms": []interface{}{
+				map[string]interface{}{"id": "vm-1", "name": "web"},
+				map[string]interface{}{"id": "vm-2", "name": "db", "targetName": "db-new"},
+				map[string]interface{}{"id": "vm-3", "name": "cache"},
+			},
+		},
+	}}
+	p.SetName("wave1")
+	p.SetNamespace("mtv")
+	return p
+}
+
+func markdownMigration() *unstructured.Unstructured {
+	m := &unstructured.Unstructured{Object: map[string]interface{}{
+		"status": map[string]interface{}{
+			"vms": []interface{}{
+				map[string]interface{}{
+					"id": "vm-1", "name": "web", "phase": "Completed",
+					"started": "2026-01-01T10:00:00Z", "completed": "2026-01-01T10:05:30Z",
+					"conditions": []interface{}{map[string]interface{}{"type": "Succeeded", "status": "True"}},
+				},
+				map[string]interface{}{
+					"id": "vm-2", "name": "db", "phase": "DiskTransfer",
+					"started": "2026-01-01T10:00:00Z",
+					"error":   map[string]interface{}{"reasons": []interface{}{"disk busy", "retrying"}},
+				},
+			},
+		},
+	}}
+	m.SetName("wave1-abc")
+	return m
+}
+
+func TestPlanMarkdown(t *testing.T) {
+	details := status.PlanDetails{
+		IsReady:          true,
+		Status:           status.StatusRunning,
+		RunningMigration: markdownMigration(),
+		VMStats:          status.VMStats{Total: 2, Completed: 1, Succeeded: 1},
+	}
+
+	out, err := describe.Format(planMarkdown(markdownPlan(), details, false, true), "markdown")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"wave1", "Wave 1", "vsphere", "prod", "warm", "wave1-network", "wave1-abc",
+		"1/2 completed (succeeded 1, failed 0, canceled 0)",
+		"| web", "5m30s", "db-new", "disk busy; retrying", "Not started",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("markdown missing %q:\n%s", want, out)
+		}
+	}
+
+	vmsOnly, err := describe.Format(planMarkdown(markdownPlan(), details, true, true), "markdown")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(vmsOnly, "SETTINGS") || !strings.Contains(vmsOnly, "VIRTUAL MACHINES") {
+		t.Errorf("vms only markdown:\n%s", vmsOnly)
+	}
+}
+
+func TestPlanMarkdownVMRows(t *testing.T) {
+	migrationVMs := buildMigrationVMMap(status.PlanDetails{LatestMigration: markdownMigration()})
+	rows := planMarkdownVMRows(markdownPlan(), migrationVMs)
+	if len(rows) != 3 {
+		t.Fatalf("planMarkdownVMRows() returned %d rows", len(rows))
+	}
+	if rows[0]["status"] != status.StatusSucceeded || rows[0]["progress"] != "Completed" {
+		t.Errorf("web row = %v", rows[0])
+	}
+	if rows[1]["status"] != status.StatusRunning || rows[1]["target"] != "db-new" || rows[1]["duration"] != "-" {
+		t.Errorf("db row = %v", rows[1])
+	}
+	if rows[2]["status"] != "Not started" || rows[2]["target"] != "cache" {
+		t.Errorf("cache row = %v", rows[2])
+	}
+}
@@ -0,0 +1,69 @@
+package status
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// PrecopyStats summarizes the warm migration precopies of a VM, as recorded in
+// the status.vms[].warm field of the Migration
+type PrecopyStats struct {
+	// Iterations is the number of precopies started so far
+	Iterations int
+	// Successes and Failures count the finished precopies
+	Successes int
+	Failures  int
+	// LastCompleted is the end time of the most recent finished precopy
+	LastCompleted string
+	// LastDuration is how long the most recent finished precopy took, which
+	// approximates how long the final copy at cutover will take
+	LastDuration time.Duration
+	// LastDeltas is the number of disk deltas copied by the most recent finished
+	// precopy; the Migration status does not record delta sizes in bytes
+	LastDeltas int
+	// NextPrecopyAt is when the next precopy is scheduled
+	NextPrecopyAt string
+}
+
+// GetPrecopyStats returns the precopy statistics of a VM migration status, and
+// false when the VM is not migrated warm
+func GetPrecopyStats(vm map[string]interface{}) (PrecopyStats, bool) {
+	warm, exists, _ := unstructured.NestedMap(vm, "warm")
+	if !exists {
+		return PrecopyStats{}, false
+	}
+
+	var stats PrecopyStats
+	successes, _, _ := unstructured.NestedInt64(warm, "successes")
+	failures, _, _ := unstructured.NestedInt64(warm, "failures")
+	stats.Successes = int(successes)
+	stats.Failures = int(failures)
+	stats.NextPrecopyAt, _, _ = unstructured.NestedString(warm, "nextPrecopyAt")
+
+	precopies, _, _ := unstructured.NestedSlice(warm, "precopies")
+	stats.Iterations = len(precopies)
+	for i := len(precopies) - 1; i >= 0; i-- {
+		precopy, ok := precopies[i].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		end, _, _ := unstructured.NestedString(precopy, "end")
+		if end == "" {
+			continue
+		}
+		start, _, _ := unstructured.NestedString(precopy, "start")
+		deltas, _, _ := unstructured.NestedSlice(precopy, "deltas")
+
+		stats.LastCompleted = end
+		stats.LastDeltas = len(deltas)
+		startTime, errStart := time.Parse(time.RFC3339, start)
+		endTime, errEnd := time.Parse(time.RFC3339, end)
+		if errStart == nil && errEnd == nil && endTime.After(startTime) {
+			stats.LastDuration = endTime.Sub(startTime)
+		}
+		break
+	}
+
+	return stats, true
+}
@@ -0,0 +1,50 @@
+package status
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGetPrecopyStats(t *testing.T) {
+	vm := map[string]interface{}{
+		"name": "web",
+		"warm": map[string]interface{}{
+			"successes":     int64(2),
+			"failures":      int64(0),
+			"nextPrecopyAt": "2026-10-16T11:00:00Z",
+			"precopies": []interface{}{
+				map[string]interface{}{
+					"start":  "2026-10-16T09:00:00Z",
+					"end":    "2026-10-16T09:20:00Z",
+					"deltas": []interface{}{map[string]interface{}{"disk": "disk-1"}, map[string]interface{}{"disk": "disk-2"}},
+				},
+				map[string]interface{}{
+					"start":  "2026-10-16T10:00:00Z",
+					"end":    "2026-10-16T10:04:30Z",
+					"deltas": []interface{}{map[string]interface{}{"disk": "disk-1"}},
+				},
+				map[string]interface{}{
+					"start": "2026-10-16T10:30:00Z",
+				},
+			},
+		},
+	}
+
+	stats, ok := GetPrecopyStats(vm)
+	if !ok {
+		t.Fatal("GetPrecopyStats() = false, want true for a warm VM")
+	}
+	if stats.Iterations != 3 || stats.Successes != 2 || stats.Failures != 0 {
+		t.Errorf("iterations/successes/failures = %d/%d/%d, want 3/2/0", stats.Iterations, stats.Successes, stats.Failures)
+	}
+	if stats.LastCompleted != "2026-10-16T10:04:30Z" || stats.LastDuration != 4*time.Minute+30*time.Second || stats.LastDeltas != 1 {
+		t.Errorf("last precopy = %s, %s, %d deltas, want the second precopy", stats.LastCompleted, stats.LastDuration, stats.LastDeltas)
+	}
+	if stats.NextPrecopyAt != "2026-10-16T11:00:00Z" {
+		t.Errorf("NextPrecopyAt = %q", stats.NextPrecopyAt)
+	}
+
+	if _, ok := GetPrecopyStats(map[string]interface{}{"name": "cold"}); ok {
+		t.Error("GetPrecopyStats() = true for a VM without warm status")
+	}
+}
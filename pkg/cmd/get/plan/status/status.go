@@ -59,8 +59,7 @@ func GetRunningMigration(
 	migrationsGVR schema.GroupVersionResource,
 ) (*unstructured.Unstructured, *unstructured.Unstructured, error) {
 	// Get the plan UID
-	planUID, found, err := unstructured.NestedString(plan.Object, "metadata", "uid")
-	if !found || err != nil {
+	if _, found, err := unstructured.NestedString(plan.Object, "metadata", "uid"); !found || err != nil {
 		return nil, nil, fmt.Errorf("failed to get plan UID: %v", err)
 	}
 
@@ -72,12 +71,24 @@ func GetRunningMigration(
 		return nil, nil, fmt.Errorf("failed to list migrations: %v", err)
 	}
 
+	return FindRunningMigration(plan, migrationList.Items)
+}
+
+// FindRunningMigration is GetRunningMigration over migrations already listed, so listings
+// of many plans can share one list of migrations.
+func FindRunningMigration(plan *unstructured.Unstructured, migrations []unstructured.Unstructured) (*unstructured.Unstructured, *unstructured.Unstructured, error) {
+	// Get the plan UID
+	planUID, found, err := unstructured.NestedString(plan.Object, "metadata", "uid")
+	if !found || err != nil {
+		return nil, nil, fmt.Errorf("failed to get plan UID: %v", err)
+	}
+
 	var latestMigration *unstructured.Unstructured
 	var latestTimestamp metav1.Time
 
 	// Check each migration
-	for i := range migrationList.Items {
-		migration := &migrationList.Items[i]
+	for i := range migrations {
+		migration := &migrations[i]
 		// Check if this migration references our plan
 		planRef, found, _ := unstructured.NestedMap(migration.Object, "spec", "plan")
 		if !found {
@@ -239,6 +250,35 @@ type ProgressStats struct {
 	Total     int64
 }
 
+// VMHasCondition reports whether a migration VM status has a true condition of the given type
+func VMHasCondition(vm map[string]interface{}, condType string) bool {
+	conditions, _, _ := unstructured.NestedSlice(vm, "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if condition["type"] == condType && condition["status"] == "True" {
+			return true
+		}
+	}
+	return false
+}
+
+// ProgressBytes converts a Forklift progress value to bytes; DiskTransfer progress is reported in MB
+func ProgressBytes(value int64, unit string) int64 {
+	switch strings.ToUpper(unit) {
+	case "B", "BYTES":
+		return value
+	case "KB":
+		return value * 1024
+	case "GB":
+		return value * 1024 * 1024 * 1024
+	default:
+		return value * 1024 * 1024
+	}
+}
+
 // GetDiskTransferProgress extracts disk transfer progress from a migration object
 func GetDiskTransferProgress(migration *unstructured.Unstructured) (ProgressStats, error) {
 	stats := ProgressStats{}
@@ -329,6 +369,19 @@ func GetPlanDetails(
 	plan *unstructured.Unstructured,
 	migrationsGVR schema.GroupVersionResource,
 ) (PlanDetails, error) {
+	migrationList, err := client.Resource(migrationsGVR).
+		Namespace(namespace).
+		List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		ready, _ := IsPlanReady(plan)
+		return PlanDetails{IsReady: ready}, fmt.Errorf("failed to list migrations: %v", err)
+	}
+
+	return PlanDetailsFromMigrations(plan, migrationList.Items)
+}
+
+// PlanDetailsFromMigrations is GetPlanDetails over migrations already listed
+func PlanDetailsFromMigrations(plan *unstructured.Unstructured, migrations []unstructured.Unstructured) (PlanDetails, error) {
 	details := PlanDetails{}
 
 	// Get if plan is ready
@@ -339,7 +392,7 @@ func GetPlanDetails(
 	details.IsReady = ready
 
 	// Get if plan has running migration
-	runningMigration, latestMigration, err := GetRunningMigration(client, namespace, plan, migrationsGVR)
+	runningMigration, latestMigration, err := FindRunningMigration(plan, migrations)
 	if err != nil {
 		return details, err
 	}
@@ -0,0 +1,67 @@
+package status
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func testMigration(name, planUID string, created time.Time, running bool) unstructured.Unstructured {
+	m := unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{"name": name},
+		"spec":     map[string]interface{}{"plan": map[string]interface{}{"uid": planUID}},
+	}}
+	m.SetCreationTimestamp(metav1.NewTime(created))
+	if running {
+		_ = unstructured.SetNestedSlice(m.Object, []interface{}{
+			map[string]interface{}{"type": "Running", "status": "True"},
+		}, "status", "conditions")
+	}
+	return m
+}
+
+func TestFindRunningMigration(t *testing.T) {
+	plan := &unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "p1", "uid": "uid-1"},
+	}}
+	now := time.Now()
+
+	migrations := []unstructured.Unstructured{
+		testMigration("old", "uid-1", now.Add(-2*time.Hour), false),
+		testMigration("new", "uid-1", now.Add(-time.Hour), false),
+		testMigration("other-plan", "uid-2", now, true),
+	}
+	running, latest, err := FindRunningMigration(plan, migrations)
+	if err != nil {
+		t.Fatalf("FindRunningMigration() unexpected error: %v", err)
+	}
+	if running != nil || latest == nil || latest.GetName() != "new" {
+		t.Errorf("running = %v, latest = %v, want no running and the newest migration of the plan", running, latest)
+	}
+
+	migrations = append(migrations, testMigration("active", "uid-1", now, true))
+	running, _, _ = FindRunningMigration(plan, migrations)
+	if running == nil || running.GetName() != "active" {
+		t.Errorf("running = %v, want the running migration of the plan", running)
+	}
+}
+
+func TestProgressBytes(t *testing.T) {
+	tests := []struct {
+		unit string
+		want int64
+	}{
+		{unit: "bytes", want: 2},
+		{unit: "KB", want: 2 * 1024},
+		{unit: "MB", want: 2 * 1024 * 1024},
+		{unit: "", want: 2 * 1024 * 1024},
+		{unit: "GB", want: 2 * 1024 * 1024 * 1024},
+	}
+	for _, tt := range tests {
+		if got := ProgressBytes(2, tt.unit); got != tt.want {
+			t.Errorf("ProgressBytes(2, %q) = %d, want %d", tt.unit, got, tt.want)
+		}
+	}
+}
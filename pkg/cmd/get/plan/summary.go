@@ -0,0 +1,280 @@
+package plan
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/get/plan/status"
+	report "github.com/yaacov/kubectl-mtv/pkg/cmd/report/plan"
+	"github.com/yaacov/kubectl-mtv/pkg/util/client"
+	"github.com/yaacov/kubectl-mtv/pkg/util/describe"
+	"github.com/yaacov/kubectl-mtv/pkg/util/output"
+	"github.com/yaacov/kubectl-mtv/pkg/util/watch"
+)
+
+// Summary aggregates the status of many plans, for standups on large migration programs
+type Summary struct {
+	Namespace string `json:"namespace,omitempty"`
+	Selector  string `json:"selector,omitempty"`
+	Plans     int    `json:"plans"`
+	// ByStatus counts the plans by status, most frequent first
+	ByStatus []StatusCount `json:"byStatus"`
+	VMs      VMSummary     `json:"vms"`
+	// TransferredBytes and TotalBytes are the disk transfer progress of the latest migrations
+	TransferredBytes int64           `json:"transferredBytes"`
+	TotalBytes       int64           `json:"totalBytes"`
+	FailureReasons   []FailureReason `json:"failureReasons"`
+}
+
+// StatusCount is the number of plans with a status
+type StatusCount struct {
+	Status string `json:"status"`
+	Plans  int    `json:"plans"`
+}
+
+// VMSummary counts the VMs of the plans by the result of their latest migration
+type VMSummary struct {
+	Total    int `json:"total"`
+	Migrated int `json:"migrated"`
+	Failed   int `json:"failed"`
+	Canceled int `json:"canceled"`
+	Running  int `json:"running"`
+	Pending  int `json:"pending"`
+}
+
+// FailureReason is a failure reason of VMs and the VMs, as plan/vm, that failed with it
+type FailureReason struct {
+	Reason string   `json:"reason"`
+	Count  int      `json:"count"`
+	VMs    []string `json:"vms"`
+}
+
+// BuildSummary aggregates plans using the migrations listed for them
+func BuildSummary(plans, migrations []unstructured.Unstructured) *Summary {
+	summary := &Summary{Plans: len(plans)}
+	byStatus := map[string]int{}
+	byReason := map[string]*FailureReason{}
+
+	for i := range plans {
+		p := &plans[i]
+		details, _ := status.PlanDetailsFromMigrations(p, migrations)
+		planStatus := details.Status
+		if planStatus == "" {
+			planStatus = status.StatusUnknown
+		}
+		byStatus[planStatus]++
+
+		vms, _, _ := unstructured.NestedSlice(p.Object, "spec", "vms")
+		summary.VMs.Total += len(vms)
+
+		migration := details.RunningMigration
+		if migration == nil {
+			migration = details.LatestMigration
+		}
+		if migration == nil {
+			summary.VMs.Pending += len(vms)
+			continue
+		}
+
+		started := 0
+		migrationVMs, _, _ := unstructured.NestedSlice(migration.Object, "status", "vms")
+		for _, v := range migrationVMs {
+			vm, ok := v.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			started++
+			switch vmResult(vm) {
+			case status.StatusSucceeded:
+				summary.VMs.Migrated++
+			case status.StatusFailed:
+				summary.VMs.Failed++
+				reasons, _, _ := unstructured.NestedStringSlice(vm, "error", "reasons")
+				reason := strings.Join(reasons, "; ")
+				if reason == "" {
+					reason = "unknown"
+				}
+				if byReason[reason] == nil {
+					byReason[reason] = &FailureReason{Reason: reason}
+				}
+				name, _, _ := unstructured.NestedString(vm, "name")
+				byReason[reason].Count++
+				byReason[reason].VMs = append(byReason[reason].VMs, p.GetName()+"/"+name)
+			case status.StatusCanceled:
+				summary.VMs.Canceled++
+			default:
+				summary.VMs.Running++
+			}
+		}
+		if pending := len(vms) - started; pending > 0 {
+			summary.VMs.Pending += pending
+		}
+
+		progress, _ := status.GetDiskTransferProgress(migration)
+		// Disk transfer progress is reported in MB
+		summary.TransferredBytes += progress.Completed * 1024 * 1024
+		summary.TotalBytes += progress.Total * 1024 * 1024
+	}
+
+	for s, count := range byStatus {
+		summary.ByStatus = append(summary.ByStatus, StatusCount{Status: s, Plans: count})
+	}
+	sort.Slice(summary.ByStatus, func(i, j int) bool {
+		if summary.ByStatus[i].Plans != summary.ByStatus[j].Plans {
+			return summary.ByStatus[i].Plans > summary.ByStatus[j].Plans
+		}
+		return summary.ByStatus[i].Status < summary.ByStatus[j].Status
+	})
+
+	summary.FailureReasons = []FailureReason{}
+	for _, r := range byReason {
+		sort.Strings(r.VMs)
+		summary.FailureReasons = append(summary.FailureReasons, *r)
+	}
+	sort.Slice(summary.FailureReasons, func(i, j int) bool {
+		if summary.FailureReasons[i].Count != summary.FailureReasons[j].Count {
+			return summary.FailureReasons[i].Count > summary.FailureReasons[j].Count
+		}
+		return summary.FailureReasons[i].Reason < summary.FailureReasons[j].Reason
+	})
+
+	return summary
+}
+
+// vmResult returns Succeeded, Failed or Canceled for a completed VM of a migration,
+// or "" while it is still migrating
+func vmResult(vm map[string]interface{}) string {
+	phase, _, _ := unstructured.NestedString(vm, "phase")
+	if phase != "Completed" {
+		return ""
+	}
+	conditions, _, _ := unstructured.NestedSlice(vm, "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		condType, _, _ := unstructured.NestedString(condition, "type")
+		condStatus, _, _ := unstructured.NestedString(condition, "status")
+		if condStatus == "True" && (condType == status.StatusSucceeded || condType == status.StatusFailed || condType == status.StatusCanceled) {
+			return condType
+		}
+	}
+	return ""
+}
+
+// summaryDescription converts the summary to a description for table and markdown output
+func summaryDescription(summary *Summary) *describe.Description {
+	b := describe.NewBuilder("MIGRATION PLANS SUMMARY")
+	scope := summary.Namespace
+	if scope == "" {
+		scope = "all namespaces"
+	}
+	b.Field("Namespace", scope)
+	if summary.Selector != "" {
+		b.Field("Selector", summary.Selector)
+	}
+	b.Field("Plans", fmt.Sprintf("%d", summary.Plans))
+
+	if len(summary.ByStatus) > 0 {
+		b.Section("PLANS BY STATUS")
+		rows := []map[string]string{}
+		for _, s := range summary.ByStatus {
+			rows = append(rows, map[string]string{"status": s.Status, "plans": fmt.Sprintf("%d", s.Plans)})
+		}
+		b.Table([]describe.TableColumn{
+			{Display: "STATUS", Key: "status"},
+			{Display: "PLANS", Key: "plans"},
+		}, rows)
+	}
+
+	b.Section("VMS")
+	b.Field("Total", fmt.Sprintf("%d", summary.VMs.Total))
+	b.FieldC("Migrated", fmt.Sprintf("%d", summary.VMs.Migrated), output.Green)
+	if summary.VMs.Failed > 0 {
+		b.FieldC("Failed", fmt.Sprintf("%d", summary.VMs.Failed), output.Red)
+	} else {
+		b.Field("Failed", "0")
+	}
+	b.Field("Canceled", fmt.Sprintf("%d", summary.VMs.Canceled))
+	b.Field("Running", fmt.Sprintf("%d", summary.VMs.Running))
+	b.Field("Pending", fmt.Sprintf("%d", summary.VMs.Pending))
+
+	if summary.TotalBytes > 0 {
+		b.Section("DATA")
+		b.Field("Transferred", fmt.Sprintf("%s of %s (%.1f%%)",
+			report.FormatBytes(summary.TransferredBytes), report.FormatBytes(summary.TotalBytes),
+			float64(summary.TransferredBytes)/float64(summary.TotalBytes)*100))
+	}
+
+	if len(summary.FailureReasons) > 0 {
+		b.Section("FAILURE REASONS")
+		rows := []map[string]string{}
+		for _, r := range summary.FailureReasons {
+			vms := r.VMs
+			more := ""
+			if len(vms) > 3 {
+				vms, more = vms[:3], fmt.Sprintf(" (+%d more)", len(r.VMs)-3)
+			}
+			rows = append(rows, map[string]string{
+				"count":  fmt.Sprintf("%d", r.Count),
+				"reason": r.Reason,
+				"vms":    strings.Join(vms, ", ") + more,
+			})
+		}
+		b.Table([]describe.TableColumn{
+			{Display: "COUNT", Key: "count"},
+			{Display: "REASON", Key: "reason"},
+			{Display: "VMS", Key: "vms"},
+		}, rows)
+	}
+
+	return b.Build()
+}
+
+// printSummary lists the plans matching the selector and their migrations, and prints the summary
+func printSummary(ctx context.Context, configFlags *genericclioptions.ConfigFlags, namespace, selector, outputFormat string) error {
+	c, err := client.GetDynamicClient(configFlags)
+	if err != nil {
+		return fmt.Errorf("failed to get client: %v", err)
+	}
+
+	plans, err := getPlans(ctx, c, namespace, selector)
+	if err != nil {
+		return fmt.Errorf("failed to list plans: %v", err)
+	}
+	migrations, err := getMigrations(ctx, c, namespace)
+	if err != nil {
+		return fmt.Errorf("failed to list migrations: %v", err)
+	}
+
+	summary := BuildSummary(plans.Items, migrations)
+	summary.Namespace = namespace
+	summary.Selector = selector
+
+	switch output.NormalizeFormat(outputFormat) {
+	case "json":
+		return output.PrintJSONWithEmpty(summary, "")
+	case "yaml":
+		return output.PrintYAMLWithEmpty(summary, "")
+	case "table":
+		return describe.Print(summaryDescription(summary), "table")
+	case "markdown":
+		return describe.Print(summaryDescription(summary), "markdown")
+	default:
+		return fmt.Errorf("unsupported output format: %s. Supported formats: table, markdown, json, yaml", outputFormat)
+	}
+}
+
+// PrintSummary prints the aggregated status of the plans in namespace (all namespaces when
+// empty) matching the label selector
+func PrintSummary(ctx context.Context, configFlags *genericclioptions.ConfigFlags, namespace, selector string, watchMode bool, outputFormat string) error {
+	return watch.WrapWithWatch(watchMode, outputFormat, func() error {
+		return printSummary(ctx, configFlags, namespace, selector, outputFormat)
+	}, watch.DefaultInterval)
+}
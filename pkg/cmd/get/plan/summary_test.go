@@ -0,0 +1,104 @@
+package plan
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func summaryPlan(name, condition string, vms int) unstructured.Unstructured {
+	specVMs := []interface{}{}
+	for i := 0; i < vms; i++ {
+		specVMs = append(specVMs, map[string]interface{}{"name": "vm"})
+	}
+	return unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{"name": name, "uid": name + "-uid"},
+		"spec":     map[string]interface{}{"vms": specVMs},
+		"status": map[string]interface{}{"conditions": []interface{}{
+			map[string]interface{}{"type": condition, "status": "True"},
+		}},
+	}}
+}
+
+func summaryVM(name, result string, reasons ...string) interface{} {
+	vm := map[string]interface{}{
+		"name":  name,
+		"phase": "Completed",
+		"conditions": []interface{}{
+			map[string]interface{}{"type": result, "status": "True"},
+		},
+		"pipeline": []interface{}{
+			map[string]interface{}{"name": "DiskTransfer", "progress": map[string]interface{}{"completed": int64(512), "total": int64(1024)}},
+		},
+	}
+	if result == "" {
+		vm["phase"] = "CopyDisks"
+		vm["conditions"] = []interface{}{}
+	}
+	if len(reasons) > 0 {
+		r := []interface{}{}
+		for _, reason := range reasons {
+			r = append(r, reason)
+		}
+		vm["error"] = map[string]interface{}{"reasons": r}
+	}
+	return vm
+}
+
+func summaryMigration(planName string, vms ...interface{}) unstructured.Unstructured {
+	m := unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{"name": planName + "-migration"},
+		"spec":     map[string]interface{}{"plan": map[string]interface{}{"uid": planName + "-uid"}},
+		"status":   map[string]interface{}{"vms": vms},
+	}}
+	m.SetCreationTimestamp(metav1.NewTime(time.Now()))
+	return m
+}
+
+func TestBuildSummary(t *testing.T) {
+	plans := []unstructured.Unstructured{
+		summaryPlan("done", "Succeeded", 2),
+		summaryPlan("broken", "Failed", 3),
+		summaryPlan("other", "Failed", 1),
+		summaryPlan("new", "Ready", 4),
+	}
+	migrations := []unstructured.Unstructured{
+		summaryMigration("done", summaryVM("a", "Succeeded"), summaryVM("b", "Succeeded")),
+		summaryMigration("broken",
+			summaryVM("c", "Failed", "disk full"),
+			summaryVM("d", "Failed"),
+			summaryVM("e", "")),
+		summaryMigration("other", summaryVM("f", "Failed", "disk full")),
+	}
+
+	summary := BuildSummary(plans, migrations)
+
+	if summary.Plans != 4 {
+		t.Errorf("Plans = %d, want 4", summary.Plans)
+	}
+	if len(summary.ByStatus) == 0 || summary.ByStatus[0].Status != "Failed" || summary.ByStatus[0].Plans != 2 {
+		t.Errorf("ByStatus = %+v, want Failed first with 2 plans", summary.ByStatus)
+	}
+
+	want := VMSummary{Total: 10, Migrated: 2, Failed: 3, Running: 1, Pending: 4}
+	if summary.VMs != want {
+		t.Errorf("VMs = %+v, want %+v", summary.VMs, want)
+	}
+
+	if summary.TotalBytes != 6*1024*1024*1024 || summary.TransferredBytes != 3*1024*1024*1024 {
+		t.Errorf("bytes = %d of %d, want 3GiB of 6GiB", summary.TransferredBytes, summary.TotalBytes)
+	}
+
+	if len(summary.FailureReasons) != 2 {
+		t.Fatalf("FailureReasons = %+v, want 2 reasons", summary.FailureReasons)
+	}
+	first := summary.FailureReasons[0]
+	if first.Reason != "disk full" || first.Count != 2 || len(first.VMs) != 2 || first.VMs[0] != "broken/c" || first.VMs[1] != "other/f" {
+		t.Errorf("FailureReasons[0] = %+v, want disk full for broken/c and other/f", first)
+	}
+	if summary.FailureReasons[1].Reason != "unknown" {
+		t.Errorf("FailureReasons[1] = %+v, want the VM without reasons as unknown", summary.FailureReasons[1])
+	}
+}
@@ -0,0 +1,260 @@
+package targetlabels
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/get/plan/status"
+	"github.com/yaacov/kubectl-mtv/pkg/util/client"
+)
+
+// EnforcedLabelsAnnotation records the labels requested with --label-target-vms on a plan,
+// so they can be verified on the migrated VirtualMachines after migration
+const EnforcedLabelsAnnotation = "kubectl-mtv/label-target-vms"
+
+// Parse parses key=value label pairs (comma-separated, repeatable) and validates them as
+// Kubernetes labels
+func Parse(pairs []string) (map[string]string, error) {
+	labels := map[string]string{}
+	for _, group := range pairs {
+		for _, pair := range strings.Split(group, ",") {
+			pair = strings.TrimSpace(pair)
+			if pair == "" {
+				continue
+			}
+			key, value, ok := strings.Cut(pair, "=")
+			if !ok {
+				return nil, fmt.Errorf("invalid target VM label '%s': expected key=value", pair)
+			}
+			key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+			if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+				return nil, fmt.Errorf("invalid target VM label key '%s': %s", key, strings.Join(errs, "; "))
+			}
+			if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+				return nil, fmt.Errorf("invalid target VM label value '%s': %s", value, strings.Join(errs, "; "))
+			}
+			labels[key] = value
+		}
+	}
+	return labels, nil
+}
+
+// Format returns labels as sorted key=value pairs separated by commas
+func Format(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, key+"="+labels[key])
+	}
+	return strings.Join(pairs, ",")
+}
+
+// Merge adds extra labels to base, failing when a key is given two different values
+func Merge(base, extra map[string]string) (map[string]string, error) {
+	merged := map[string]string{}
+	for key, value := range base {
+		merged[key] = value
+	}
+	for key, value := range extra {
+		if existing, ok := merged[key]; ok && existing != value {
+			return nil, fmt.Errorf("target VM label '%s' is set to both '%s' and '%s'", key, existing, value)
+		}
+		merged[key] = value
+	}
+	return merged, nil
+}
+
+// Enforced returns the labels recorded on the plan with --label-target-vms
+func Enforced(plan *unstructured.Unstructured) map[string]string {
+	value := plan.GetAnnotations()[EnforcedLabelsAnnotation]
+	if value == "" {
+		return nil
+	}
+	labels, err := Parse([]string{value})
+	if err != nil {
+		return nil
+	}
+	return labels
+}
+
+// ApplyToPlan adds labels to the plan's spec.targetLabels and records them for post-migration
+// verification. Labels already set on the plan with a different value are overridden.
+func ApplyToPlan(ctx context.Context, c dynamic.Interface, plan *unstructured.Unstructured, labels map[string]string) error {
+	targetLabels := map[string]interface{}{}
+	for key, value := range labels {
+		targetLabels[key] = value
+	}
+
+	enforced := Enforced(plan)
+	if enforced == nil {
+		enforced = map[string]string{}
+	}
+	for key, value := range labels {
+		enforced[key] = value
+	}
+
+	patch := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{
+				EnforcedLabelsAnnotation: Format(enforced),
+			},
+		},
+		"spec": map[string]interface{}{
+			"targetLabels": targetLabels,
+		},
+	}
+	patchBytes, err := json.Marshal(patch)
+	if err != nil {
+		return fmt.Errorf("failed to create patch: %v", err)
+	}
+
+	_, err = c.Resource(client.PlansGVR).Namespace(plan.GetNamespace()).Patch(ctx, plan.GetName(), types.MergePatchType, patchBytes, metav1.PatchOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to set target VM labels on plan '%s': %v", plan.GetName(), err)
+	}
+	return nil
+}
+
+// VMCheck is the label verification result of a single migrated VM
+type VMCheck struct {
+	Name      string
+	Namespace string
+	// Found is false when the target VirtualMachine does not exist
+	Found bool
+	// Missing lists the expected labels that are absent or have another value
+	Missing []string
+	// Fixed is true when the missing labels were added
+	Fixed bool
+}
+
+// targetVMName returns the name of the VirtualMachine created for a migrated VM
+func targetVMName(vm map[string]interface{}) string {
+	for _, field := range []string{"newName", "targetName", "name"} {
+		if name, _ := vm[field].(string); name != "" {
+			return name
+		}
+	}
+	return ""
+}
+
+// vmSucceeded reports whether a migration VM status has a true Succeeded condition
+func vmSucceeded(vm map[string]interface{}) bool {
+	conditions, _, _ := unstructured.NestedSlice(vm, "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if condition["type"] == status.StatusSucceeded && condition["status"] == "True" {
+			return true
+		}
+	}
+	return false
+}
+
+// Verify checks that every successfully migrated VM of the migration carries the labels.
+// When fix is set, missing labels are added to the VirtualMachines.
+func Verify(ctx context.Context, c dynamic.Interface, plan, migration *unstructured.Unstructured, labels map[string]string, fix bool) ([]VMCheck, error) {
+	if migration == nil || len(labels) == 0 {
+		return nil, nil
+	}
+
+	targetNamespace, _, _ := unstructured.NestedString(plan.Object, "spec", "targetNamespace")
+	if targetNamespace == "" {
+		targetNamespace = plan.GetNamespace()
+	}
+
+	vms, _, _ := unstructured.NestedSlice(migration.Object, "status", "vms")
+	checks := []VMCheck{}
+	for _, v := range vms {
+		vm, ok := v.(map[string]interface{})
+		if !ok || !vmSucceeded(vm) {
+			continue
+		}
+
+		check := VMCheck{Name: targetVMName(vm), Namespace: targetNamespace}
+		target, err := c.Resource(client.VirtualMachinesGVR).Namespace(targetNamespace).Get(ctx, check.Name, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			checks = append(checks, check)
+			continue
+		}
+		if err != nil {
+			return checks, fmt.Errorf("failed to get VirtualMachine '%s/%s': %v", targetNamespace, check.Name, err)
+		}
+		check.Found = true
+
+		current := target.GetLabels()
+		missing := map[string]interface{}{}
+		for key, value := range labels {
+			if current[key] != value {
+				missing[key] = value
+				check.Missing = append(check.Missing, key+"="+value)
+			}
+		}
+		sort.Strings(check.Missing)
+
+		if fix && len(missing) > 0 {
+			patchBytes, err := json.Marshal(map[string]interface{}{
+				"metadata": map[string]interface{}{"labels": missing},
+			})
+			if err != nil {
+				return checks, fmt.Errorf("failed to create patch: %v", err)
+			}
+			_, err = c.Resource(client.VirtualMachinesGVR).Namespace(targetNamespace).Patch(ctx, check.Name, types.MergePatchType, patchBytes, metav1.PatchOptions{})
+			if err != nil {
+				return checks, fmt.Errorf("failed to label VirtualMachine '%s/%s': %v", targetNamespace, check.Name, err)
+			}
+			check.Fixed = true
+		}
+		checks = append(checks, check)
+	}
+	return checks, nil
+}
+
+// EnsureAfterMigration verifies the labels recorded on the plan against its latest migration,
+// adds any missing labels, and prints a short report. Plans without recorded labels are ignored.
+func EnsureAfterMigration(ctx context.Context, c dynamic.Interface, namespace, planName string) error {
+	plan, err := c.Resource(client.PlansGVR).Namespace(namespace).Get(ctx, planName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get plan '%s': %v", planName, err)
+	}
+	labels := Enforced(plan)
+	if len(labels) == 0 {
+		return nil
+	}
+
+	_, migration, err := status.GetRunningMigration(c, namespace, plan, client.MigrationsGVR)
+	if err != nil {
+		return err
+	}
+	checks, err := Verify(ctx, c, plan, migration, labels, true)
+	if err != nil {
+		return err
+	}
+
+	for _, check := range checks {
+		switch {
+		case !check.Found:
+			fmt.Printf("Warning: target VM '%s/%s' of plan '%s' was not found, labels not verified\n", check.Namespace, check.Name, planName)
+		case check.Fixed:
+			fmt.Printf("Added missing labels %s to target VM '%s/%s'\n", strings.Join(check.Missing, ","), check.Namespace, check.Name)
+		}
+	}
+	fmt.Printf("Verified target VM labels %s on %d VM(s) of plan '%s'\n", Format(labels), len(checks), planName)
+	return nil
+}
@@ -0,0 +1,97 @@
+package targetlabels
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		pairs   []string
+		want    map[string]string
+		wantErr bool
+	}{
+		{name: "single group", pairs: []string{"migrated-by=mtv,wave=7"}, want: map[string]string{"migrated-by": "mtv", "wave": "7"}},
+		{name: "repeated flag", pairs: []string{"migrated-by=mtv", "wave=7"}, want: map[string]string{"migrated-by": "mtv", "wave": "7"}},
+		{name: "spaces and empty pairs", pairs: []string{" a = b ,, c=d "}, want: map[string]string{"a": "b", "c": "d"}},
+		{name: "prefixed key", pairs: []string{"example.com/team=infra"}, want: map[string]string{"example.com/team": "infra"}},
+		{name: "empty value", pairs: []string{"flag="}, want: map[string]string{"flag": ""}},
+		{name: "missing value", pairs: []string{"wave"}, wantErr: true},
+		{name: "invalid key", pairs: []string{"bad key=x"}, wantErr: true},
+		{name: "invalid value", pairs: []string{"wave=not valid!"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.pairs)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Parse() = %v, want %v", got, tt.want)
+			}
+			for key, value := range tt.want {
+				if got[key] != value {
+					t.Errorf("Parse()[%q] = %q, want %q", key, got[key], value)
+				}
+			}
+		})
+	}
+}
+
+func TestFormat(t *testing.T) {
+	got := Format(map[string]string{"wave": "7", "migrated-by": "mtv"})
+	if want := "migrated-by=mtv,wave=7"; got != want {
+		t.Errorf("Format() = %q, want %q", got, want)
+	}
+	if got := Format(nil); got != "" {
+		t.Errorf("Format(nil) = %q, want empty", got)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	merged, err := Merge(map[string]string{"app": "web", "wave": "7"}, map[string]string{"wave": "7", "migrated-by": "mtv"})
+	if err != nil {
+		t.Fatalf("Merge() unexpected error: %v", err)
+	}
+	if got, want := Format(merged), "app=web,migrated-by=mtv,wave=7"; got != want {
+		t.Errorf("Merge() = %q, want %q", got, want)
+	}
+
+	if _, err := Merge(map[string]string{"wave": "6"}, map[string]string{"wave": "7"}); err == nil {
+		t.Error("Merge() expected error for conflicting values")
+	}
+}
+
+func TestEnforced(t *testing.T) {
+	plan := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	if got := Enforced(plan); got != nil {
+		t.Errorf("Enforced() without annotation = %v, want nil", got)
+	}
+
+	plan.SetAnnotations(map[string]string{EnforcedLabelsAnnotation: "migrated-by=mtv,wave=7"})
+	if got, want := Format(Enforced(plan)), "migrated-by=mtv,wave=7"; got != want {
+		t.Errorf("Enforced() = %q, want %q", got, want)
+	}
+}
+
+func TestTargetVMName(t *testing.T) {
+	tests := []struct {
+		vm   map[string]interface{}
+		want string
+	}{
+		{vm: map[string]interface{}{"name": "web", "newName": "web-new"}, want: "web-new"},
+		{vm: map[string]interface{}{"name": "web", "targetName": "web-target"}, want: "web-target"},
+		{vm: map[string]interface{}{"name": "web"}, want: "web"},
+	}
+	for _, tt := range tests {
+		if got := targetVMName(tt.vm); got != tt.want {
+			t.Errorf("targetVMName(%v) = %q, want %q", tt.vm, got, tt.want)
+		}
+	}
+}
@@ -177,9 +177,42 @@ func printVMInfo(vm map[string]interface{}, showOS bool) string {
 		fmt.Println()
 	}
 
+	if precopy, ok := status.GetPrecopyStats(vm); ok {
+		printPrecopyInfo(precopy)
+	}
+
 	return vmCompletionStatus
 }
 
+// printPrecopyInfo prints the warm migration precopy progress of a VM
+func printPrecopyInfo(precopy status.PrecopyStats) {
+	fmt.Printf("%s %d (succeeded %d, failed %d)", output.Bold("Precopies:"), precopy.Iterations, precopy.Successes, precopy.Failures)
+	if precopy.LastCompleted != "" {
+		fmt.Printf("  %s %s (%s, %s)", output.Bold("Last Precopy:"), output.Green(precopy.LastCompleted),
+			FormatPrecopyDuration(precopy.LastDuration), FormatPrecopyDeltas(precopy.LastDeltas))
+	}
+	if precopy.NextPrecopyAt != "" {
+		fmt.Printf("  %s %s", output.Bold("Next Precopy:"), output.Blue(precopy.NextPrecopyAt))
+	}
+	fmt.Println()
+}
+
+// FormatPrecopyDuration formats the duration of a precopy, or "-" when unknown
+func FormatPrecopyDuration(d time.Duration) string {
+	if d <= 0 {
+		return "-"
+	}
+	return d.Round(time.Second).String()
+}
+
+// FormatPrecopyDeltas formats the number of disk deltas copied by a precopy
+func FormatPrecopyDeltas(deltas int) string {
+	if deltas == 1 {
+		return "1 disk delta"
+	}
+	return fmt.Sprintf("%d disk deltas", deltas)
+}
+
 // printPipelineTable prints the pipeline table for a VM
 func printPipelineTable(vm map[string]interface{}, vmCompletionStatus string) {
 	pipeline, exists, _ := unstructured.NestedSlice(vm, "pipeline")
@@ -16,6 +16,7 @@ import (
 	"github.com/yaacov/kubectl-mtv/pkg/util/client"
 	"github.com/yaacov/kubectl-mtv/pkg/util/output"
 	querypkg "github.com/yaacov/kubectl-mtv/pkg/util/query"
+	"github.com/yaacov/kubectl-mtv/pkg/util/vocab"
 	"github.com/yaacov/kubectl-mtv/pkg/util/watch"
 )
 
@@ -29,7 +30,7 @@ var vmTableColumns = []output.Column{
 	{Title: "TARGET STATUS", Key: "targetStatus", ColorFunc: output.ColorizePowerState},
 	{Title: "PLAN", Key: "plan", ColorFunc: colorizePlanName},
 	{Title: "PLAN STATUS", Key: "planStatus", ColorFunc: output.ColorizeStatus},
-	{Title: "PROGRESS", Key: "progress"},
+	{Title: "PROGRESS", Key: "progress", ColorFunc: output.ColorizeProgress},
 }
 
 // colorizeTarget dims the target name when it has a * suffix (VM not found in inventory).
@@ -60,13 +61,13 @@ func ListVMsTable(
 	configFlags *genericclioptions.ConfigFlags,
 	planName, namespace, inventoryURL string,
 	insecureSkipTLS bool,
-	outputFormat, queryStr string,
+	outputFormat, queryStr, selector string,
 	watchMode bool,
 ) error {
 	sq := watch.NewSafeQuery(queryStr)
 
 	return watch.WrapWithWatchAndQuery(watchMode, outputFormat, func() error {
-		return listVMsTableOnce(ctx, configFlags, planName, namespace, inventoryURL, insecureSkipTLS, outputFormat, sq.Get())
+		return listVMsTableOnce(ctx, configFlags, planName, namespace, inventoryURL, insecureSkipTLS, outputFormat, sq.Get(), selector)
 	}, watch.DefaultInterval, sq.Set, queryStr)
 }
 
@@ -75,7 +76,7 @@ func listVMsTableOnce(
 	configFlags *genericclioptions.ConfigFlags,
 	planName, namespace, inventoryURL string,
 	insecureSkipTLS bool,
-	outputFormat, queryStr string,
+	outputFormat, queryStr, selector string,
 ) error {
 	c, err := client.GetDynamicClient(configFlags)
 	if err != nil {
@@ -90,7 +91,7 @@ func listVMsTableOnce(
 			return fmt.Errorf("failed to get plan: %v", err)
 		}
 	} else {
-		plans, err = getPlans(ctx, c, namespace)
+		plans, err = getPlans(ctx, c, namespace, selector)
 		if err != nil {
 			return fmt.Errorf("failed to list plans: %v", err)
 		}
@@ -130,12 +131,16 @@ func listVMsTableOnce(
 	}
 
 	// Output
-	outputFormat = strings.ToLower(outputFormat)
+	outputFormat = output.NormalizeFormat(outputFormat)
 	emptyMsg := "No VMs found"
 	if planName != "" {
 		emptyMsg = fmt.Sprintf("No VMs found in plan %s", planName)
 	}
 
+	if output.IsTemplateFormat(outputFormat) {
+		return output.PrintTemplate(items, outputFormat)
+	}
+
 	switch outputFormat {
 	case "json":
 		return output.PrintJSONWithEmpty(items, emptyMsg)
@@ -236,6 +241,7 @@ func buildPlanVMRows(
 
 		row := map[string]interface{}{
 			"vm":           vmName,
+			"id":           vmID,
 			"sourceStatus": srcStatus,
 			"sourceIP":     srcIP,
 			"target":       tgtDisplay,
@@ -528,64 +534,17 @@ func lookupTargetWorkload(targetWorkloads map[string]map[string]interface{}, tar
 	return extractPowerStatus(wl), extractIP(wl)
 }
 
-// extractPowerStatus extracts a human-readable power status from a VM/workload map.
+// extractPowerStatus extracts a human-readable power status from a VM/workload map,
+// "Running" when the provider reports it powered on and "Stopped" otherwise.
 func extractPowerStatus(vm map[string]interface{}) string {
-	// Check "powerState" (ovirt, vsphere)
-	if ps, ok := vm["powerState"].(string); ok && ps != "" {
-		lower := strings.ToLower(ps)
-		if strings.Contains(lower, "on") || strings.Contains(lower, "up") || strings.Contains(lower, "running") {
-			return "Running"
-		}
-		return "Stopped"
-	}
-
-	// Check "status" (OpenShift workloads)
-	if st, ok := vm["status"].(string); ok && st != "" {
-		lower := strings.ToLower(st)
-		if strings.Contains(lower, "running") {
-			return "Running"
-		}
-		if strings.Contains(lower, "stopped") || strings.Contains(lower, "off") {
-			return "Stopped"
-		}
-		return st
-	}
-
-	// Check nested object.status.printableStatus (KubeVirt VirtualMachine)
-	if ps, found, _ := unstructured.NestedString(vm, "object", "status", "printableStatus"); found && ps != "" {
-		lower := strings.ToLower(ps)
-		if strings.Contains(lower, "running") {
-			return "Running"
-		}
-		if strings.Contains(lower, "stopped") || strings.Contains(lower, "off") || strings.Contains(lower, "halted") {
-			return "Stopped"
-		}
-		return ps
-	}
-
-	// Check nested status.phase under object or instance (KubeVirt VM / VMI)
-	for _, prefix := range []string{"object", "instance"} {
-		if phase, found, _ := unstructured.NestedString(vm, prefix, "status", "phase"); found && phase != "" {
-			lower := strings.ToLower(phase)
-			if strings.Contains(lower, "running") {
-				return "Running"
-			}
-			if strings.Contains(lower, "stopped") || strings.Contains(lower, "off") {
-				return "Stopped"
-			}
-			return phase
-		}
-	}
-
-	// EC2: State.Name
-	if state, found, _ := unstructured.NestedString(vm, "State", "Name"); found && state != "" {
-		if strings.ToLower(state) == "running" {
-			return "Running"
-		}
+	switch vocab.PowerState(vocab.RawPowerState(vm)) {
+	case "":
+		return "-"
+	case vocab.PowerOn:
+		return "Running"
+	default:
 		return "Stopped"
 	}
-
-	return "-"
 }
 
 // extractIP extracts an IP address from a VM/workload map.
@@ -91,6 +91,11 @@ func TestExtractPowerStatus(t *testing.T) {
 			},
 			want: "Stopped",
 		},
+		{
+			name: "OpenStack SHUTOFF",
+			vm:   map[string]interface{}{"status": "SHUTOFF"},
+			want: "Stopped",
+		},
 		{
 			name: "empty vm",
 			vm:   map[string]interface{}{},
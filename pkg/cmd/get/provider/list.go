@@ -3,7 +3,6 @@ package provider
 import (
 	"context"
 	"fmt"
-	"strings"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -87,12 +86,12 @@ func countProviderResources(ctx context.Context, configFlags *genericclioptions.
 	return -1
 }
 
-// getProviders retrieves all providers from the given namespace
-func getProviders(ctx context.Context, dynamicClient dynamic.Interface, namespace string) (*unstructured.UnstructuredList, error) {
+// getProviders retrieves all providers from the given namespace matching the label selector
+func getProviders(ctx context.Context, dynamicClient dynamic.Interface, namespace, selector string) (*unstructured.UnstructuredList, error) {
 	if namespace != "" {
-		return dynamicClient.Resource(client.ProvidersGVR).Namespace(namespace).List(ctx, metav1.ListOptions{})
+		return dynamicClient.Resource(client.ProvidersGVR).Namespace(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
 	} else {
-		return dynamicClient.Resource(client.ProvidersGVR).Namespace(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+		return client.ListAllNamespaces(ctx, dynamicClient, client.ProvidersGVR, metav1.ListOptions{LabelSelector: selector})
 	}
 }
 
@@ -111,7 +110,7 @@ func getSpecificProvider(ctx context.Context, dynamicClient dynamic.Interface, n
 		}, nil
 	} else {
 		// If no namespace specified, list all and filter by name
-		providers, err := dynamicClient.Resource(client.ProvidersGVR).Namespace(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+		providers, err := client.ListAllNamespaces(ctx, dynamicClient, client.ProvidersGVR, metav1.ListOptions{})
 		if err != nil {
 			return nil, fmt.Errorf("failed to list providers: %v", err)
 		}
@@ -134,7 +133,7 @@ func getSpecificProvider(ctx context.Context, dynamicClient dynamic.Interface, n
 }
 
 // ListProviders lists providers without watch functionality
-func ListProviders(ctx context.Context, configFlags *genericclioptions.ConfigFlags, namespace string, baseURL string, outputFormat string, providerName string, insecureSkipTLS bool, query string) error {
+func ListProviders(ctx context.Context, configFlags *genericclioptions.ConfigFlags, namespace string, baseURL string, outputFormat string, providerName string, insecureSkipTLS bool, query string, selector string) error {
 	c, err := client.GetDynamicClient(configFlags)
 	if err != nil {
 		return fmt.Errorf("failed to get client: %v", err)
@@ -149,16 +148,27 @@ func ListProviders(ctx context.Context, configFlags *genericclioptions.ConfigFla
 		}
 	} else {
 		// Get all providers
-		providers, err = getProviders(ctx, c, namespace)
+		providers, err = getProviders(ctx, c, namespace, selector)
 		if err != nil {
 			return fmt.Errorf("failed to list providers: %v", err)
 		}
 	}
 
 	// Format validation
-	outputFormat = strings.ToLower(outputFormat)
-	if outputFormat != "table" && outputFormat != "json" && outputFormat != "yaml" && outputFormat != "markdown" {
-		return fmt.Errorf("unsupported output format: %s. Supported formats: table, json, yaml, markdown", outputFormat)
+	outputFormat = output.NormalizeFormat(outputFormat)
+	if outputFormat != "table" && outputFormat != "wide" && outputFormat != "json" && outputFormat != "yaml" && outputFormat != "markdown" && !output.IsTemplateFormat(outputFormat) {
+		return fmt.Errorf("unsupported output format: %s. Supported formats: table, wide, json, yaml, markdown", outputFormat)
+	}
+
+	// Wide output reads provider secrets to report the TLS verification setting
+	var secrets *secretLookup
+	if outputFormat == "wide" {
+		secrets = &secretLookup{ctx: ctx, cache: make(map[string]map[string][]byte)}
+		if clientset, err := client.GetKubernetesClientset(configFlags); err == nil {
+			secrets.clientset = clientset
+		} else {
+			klog.V(4).Infof("Failed to get kubernetes client, INSECURE column will be empty: %v", err)
+		}
 	}
 
 	// If baseURL is empty, try to discover it from an OpenShift Route
@@ -305,6 +315,9 @@ func ListProviders(ctx context.Context, configFlags *genericclioptions.ConfigFla
 		// and try to count missing fields from inventory if possible
 		normalizeProviderInventory(ctx, configFlags, baseURL, provider, item, insecureSkipTLS)
 
+		// Add URL host, secret, VDDK image and TLS fields (also queryable)
+		augmentWideFields(item, provider, secrets)
+
 		// Add the item to the list
 		items = append(items, item)
 	}
@@ -331,6 +344,10 @@ func ListProviders(ctx context.Context, configFlags *genericclioptions.ConfigFla
 	}
 
 	// Handle different output formats
+	if output.IsTemplateFormat(outputFormat) {
+		return output.PrintTemplate(items, outputFormat)
+	}
+
 	switch outputFormat {
 	case "json":
 		jsonPrinter := output.NewJSONPrinter().
@@ -358,9 +375,15 @@ func ListProviders(ctx context.Context, configFlags *genericclioptions.ConfigFla
 			headers = append(headers, output.Column{Title: "NAMESPACE", Key: "metadata.namespace"})
 		}
 
+		// Wide output shows only the URL host to leave room for the extra columns
+		urlKey := "spec.url"
+		if outputFormat == "wide" {
+			urlKey = "urlHost"
+		}
+
 		headers = append(headers,
 			output.Column{Title: "TYPE", Key: "spec.type"},
-			output.Column{Title: "URL", Key: "spec.url"},
+			output.Column{Title: "URL", Key: urlKey},
 			output.Column{Title: "STATUS", Key: "status.phase", ColorFunc: output.ColorizeStatus},
 			output.Column{Title: "CONNECTED", Key: "conditionStatuses.ConnectionStatus", ColorFunc: output.ColorizeConditionStatus},
 			output.Column{Title: "INVENTORY", Key: "conditionStatuses.InventoryStatus", ColorFunc: output.ColorizeConditionStatus},
@@ -368,6 +391,9 @@ func ListProviders(ctx context.Context, configFlags *genericclioptions.ConfigFla
 		)
 
 		headers = append(headers, getDynamicInventoryColumns()...)
+		if outputFormat == "wide" {
+			headers = append(headers, getWideColumns()...)
+		}
 		tablePrinter := output.NewTablePrinter().WithColumns(headers...).AddItems(items)
 
 		if len(items) == 0 {
@@ -389,8 +415,8 @@ func ListProviders(ctx context.Context, configFlags *genericclioptions.ConfigFla
 }
 
 // List lists providers with optional watch mode
-func List(ctx context.Context, configFlags *genericclioptions.ConfigFlags, namespace string, baseURL string, watchMode bool, outputFormat string, providerName string, insecureSkipTLS bool, query string) error {
+func List(ctx context.Context, configFlags *genericclioptions.ConfigFlags, namespace string, baseURL string, watchMode bool, outputFormat string, providerName string, insecureSkipTLS bool, query string, selector string) error {
 	return watch.WrapWithWatch(watchMode, outputFormat, func() error {
-		return ListProviders(ctx, configFlags, namespace, baseURL, outputFormat, providerName, insecureSkipTLS, query)
+		return ListProviders(ctx, configFlags, namespace, baseURL, outputFormat, providerName, insecureSkipTLS, query, selector)
 	}, watch.DefaultInterval)
 }
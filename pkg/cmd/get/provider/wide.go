@@ -0,0 +1,101 @@
+package provider
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	"github.com/yaacov/kubectl-mtv/pkg/util/output"
+)
+
+// getWideColumns returns the extra columns shown with -o wide
+func getWideColumns() []output.Column {
+	return []output.Column{
+		{Title: "SECRET", Key: "secretRef"},
+		{Title: "VDDK-IMAGE", Key: "vddkImage"},
+		{Title: "INSECURE", Key: "insecureSkipTLS", ColorFunc: colorizeInsecure},
+	}
+}
+
+// colorizeInsecure highlights providers that skip TLS verification
+func colorizeInsecure(value string) string {
+	if value == "true" {
+		return output.Yellow(value)
+	}
+	return value
+}
+
+// urlHost returns the host (and port) part of a provider URL, or the URL itself if it cannot be parsed
+func urlHost(rawURL string) string {
+	if rawURL == "" {
+		return ""
+	}
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Host == "" {
+		return rawURL
+	}
+	return parsed.Host
+}
+
+// secretLookup reads provider secrets once per namespace/name
+type secretLookup struct {
+	ctx       context.Context
+	clientset kubernetes.Interface
+	cache     map[string]map[string][]byte
+}
+
+// get returns the secret data, or nil if the secret cannot be read
+func (l *secretLookup) get(namespace, name string) map[string][]byte {
+	key := namespace + "/" + name
+	if data, ok := l.cache[key]; ok {
+		return data
+	}
+
+	var data map[string][]byte
+	if l.clientset != nil {
+		secret, err := l.clientset.CoreV1().Secrets(namespace).Get(l.ctx, name, metav1.GetOptions{})
+		if err != nil {
+			klog.V(4).Infof("Failed to get secret %s: %v", key, err)
+		} else {
+			data = secret.Data
+		}
+	}
+	l.cache[key] = data
+	return data
+}
+
+// augmentWideFields adds the computed fields used by the wide table columns
+func augmentWideFields(item map[string]interface{}, provider *unstructured.Unstructured, secrets *secretLookup) {
+	providerURL, _, _ := unstructured.NestedString(provider.Object, "spec", "url")
+	item["urlHost"] = urlHost(providerURL)
+
+	vddkImage, _, _ := unstructured.NestedString(provider.Object, "spec", "settings", "vddkInitImage")
+	item["vddkImage"] = vddkImage
+
+	secretName, _, _ := unstructured.NestedString(provider.Object, "spec", "secret", "name")
+	if secretName == "" {
+		return
+	}
+	secretNamespace, _, _ := unstructured.NestedString(provider.Object, "spec", "secret", "namespace")
+	if secretNamespace == "" {
+		secretNamespace = provider.GetNamespace()
+	}
+
+	if secretNamespace == provider.GetNamespace() {
+		item["secretRef"] = secretName
+	} else {
+		item["secretRef"] = secretNamespace + "/" + secretName
+	}
+
+	if secrets == nil {
+		return
+	}
+	if data := secrets.get(secretNamespace, secretName); data != nil {
+		item["insecureSkipTLS"] = strings.EqualFold(strings.TrimSpace(string(data["insecureSkipVerify"])), "true")
+	}
+}
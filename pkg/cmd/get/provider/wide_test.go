@@ -0,0 +1,78 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestURLHost(t *testing.T) {
+	tests := map[string]string{
+		"":                                   "",
+		"https://vcenter.example.com/sdk":    "vcenter.example.com",
+		"https://ovirt.example.com:8443/api": "ovirt.example.com:8443",
+		"nfs.example.com:/exports/ova":       "nfs.example.com:/exports/ova",
+	}
+	for in, want := range tests {
+		if got := urlHost(in); got != want {
+			t.Errorf("urlHost(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestAugmentWideFields(t *testing.T) {
+	provider := &unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "vsphere", "namespace": "mtv"},
+		"spec": map[string]interface{}{
+			"url":      "https://vcenter.example.com/sdk",
+			"secret":   map[string]interface{}{"name": "vsphere-creds", "namespace": "mtv"},
+			"settings": map[string]interface{}{"vddkInitImage": "quay.io/example/vddk:8"},
+		},
+	}}
+
+	secrets := &secretLookup{
+		ctx: context.Background(),
+		clientset: fake.NewSimpleClientset(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "vsphere-creds", Namespace: "mtv"},
+			Data:       map[string][]byte{"insecureSkipVerify": []byte("true")},
+		}),
+		cache: make(map[string]map[string][]byte),
+	}
+
+	item := map[string]interface{}{}
+	augmentWideFields(item, provider, secrets)
+
+	if item["urlHost"] != "vcenter.example.com" {
+		t.Errorf("urlHost = %v", item["urlHost"])
+	}
+	if item["secretRef"] != "vsphere-creds" {
+		t.Errorf("secretRef = %v", item["secretRef"])
+	}
+	if item["vddkImage"] != "quay.io/example/vddk:8" {
+		t.Errorf("vddkImage = %v", item["vddkImage"])
+	}
+	if item["insecureSkipTLS"] != true {
+		t.Errorf("insecureSkipTLS = %v, want true", item["insecureSkipTLS"])
+	}
+}
+
+func TestAugmentWideFields_NoSecret(t *testing.T) {
+	provider := &unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "host", "namespace": "mtv"},
+		"spec":     map[string]interface{}{"type": "openshift"},
+	}}
+
+	item := map[string]interface{}{}
+	augmentWideFields(item, provider, nil)
+
+	if _, ok := item["secretRef"]; ok {
+		t.Error("expected no secretRef for a provider without a secret")
+	}
+	if _, ok := item["insecureSkipTLS"]; ok {
+		t.Error("expected no insecureSkipTLS for a provider without a secret")
+	}
+}
@@ -0,0 +1,198 @@
+package wave
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/yaacov/kubectl-mtv/pkg/util/client"
+	"github.com/yaacov/kubectl-mtv/pkg/util/output"
+	querypkg "github.com/yaacov/kubectl-mtv/pkg/util/query"
+	"github.com/yaacov/kubectl-mtv/pkg/util/watch"
+	wavepkg "github.com/yaacov/kubectl-mtv/pkg/util/wave"
+)
+
+// waveColumns returns the table columns for the wave list
+func waveColumns(allNamespaces bool) []output.Column {
+	columns := []output.Column{}
+	if allNamespaces {
+		columns = append(columns, output.Column{Title: "NAMESPACE", Key: "namespace"})
+	}
+	return append(columns,
+		output.Column{Title: "NAME", Key: "name"},
+		output.Column{Title: "MODE", Key: "mode"},
+		output.Column{Title: "CONCURRENCY", Key: "concurrency"},
+		output.Column{Title: "PLANS", Key: "plansProgress"},
+		output.Column{Title: "RUNNING", Key: "running"},
+		output.Column{Title: "FAILED", Key: "failed"},
+		output.Column{Title: "VMS", Key: "vmsProgress"},
+		output.Column{Title: "START-AT", Key: "startAt"},
+		output.Column{Title: "STATUS", Key: "status", ColorFunc: output.ColorizeStatus},
+		output.Column{Title: "CREATED", Key: "created"},
+	)
+}
+
+// planColumns returns the table columns for the per-plan breakdown of a wave
+func planColumns() []output.Column {
+	return []output.Column{
+		{Title: "ORDER", Key: "order"},
+		{Title: "PLAN", Key: "name"},
+		{Title: "STATUS", Key: "status", ColorFunc: output.ColorizeStatus},
+		{Title: "VMS", Key: "vmsProgress"},
+		{Title: "FAILED", Key: "vmsFailed"},
+	}
+}
+
+// formatOptionalTime formats an optional time, or "-" when unset
+func formatOptionalTime(t *time.Time, useUTC bool) string {
+	if t == nil {
+		return "-"
+	}
+	return output.FormatTimestamp(*t, useUTC)
+}
+
+// createWaveItem creates a standardized wave item for output
+func createWaveItem(w *wavepkg.Wave, progress WaveProgress, useUTC bool, now time.Time) map[string]interface{} {
+	concurrency := "1"
+	if w.Mode == wavepkg.ModeParallel {
+		concurrency = fmt.Sprintf("%d", w.Limit())
+	}
+
+	plans := make([]interface{}, 0, len(progress.Plans))
+	for i, plan := range progress.Plans {
+		plans = append(plans, map[string]interface{}{
+			"order":        i + 1,
+			"name":         plan.Name,
+			"status":       plan.Status,
+			"vms":          plan.VMs,
+			"vmsSucceeded": plan.VMSucceeded,
+			"vmsFailed":    plan.VMFailed,
+			"vmsProgress":  fraction(plan.VMSucceeded, plan.VMs),
+		})
+	}
+
+	return map[string]interface{}{
+		"name":          w.Name,
+		"namespace":     w.Namespace,
+		"description":   w.Description,
+		"mode":          w.Mode,
+		"concurrency":   concurrency,
+		"planNames":     strings.Join(w.Plans, ","),
+		"plans":         plans,
+		"plansProgress": fraction(progress.Succeeded, len(progress.Plans)),
+		"succeeded":     progress.Succeeded,
+		"running":       progress.Running,
+		"failed":        progress.Failed,
+		"pending":       progress.Pending,
+		"vmsProgress":   fraction(progress.VMsDone, progress.VMs),
+		"vmsFailed":     progress.VMsFailed,
+		"startAt":       formatOptionalTime(w.StartAt, useUTC),
+		"startedAt":     formatOptionalTime(w.StartedAt, useUTC),
+		"completedAt":   formatOptionalTime(w.CompletedAt, useUTC),
+		"status":        WaveStatus(w, progress, now),
+		"created":       output.FormatTimestamp(w.Created, useUTC),
+	}
+}
+
+// getWaveItems fetches the waves and their aggregated progress
+func getWaveItems(ctx context.Context, c dynamic.Interface, namespace, waveName string, useUTC bool) ([]map[string]interface{}, error) {
+	var waves []*wavepkg.Wave
+	if waveName != "" {
+		w, err := wavepkg.Get(ctx, c, namespace, waveName)
+		if err != nil {
+			return nil, err
+		}
+		waves = []*wavepkg.Wave{w}
+	} else {
+		var err error
+		waves, err = wavepkg.List(ctx, c, namespace)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	items := make([]map[string]interface{}, 0, len(waves))
+	for _, w := range waves {
+		items = append(items, createWaveItem(w, GetWaveProgress(ctx, c, w), useUTC, now))
+	}
+	return items, nil
+}
+
+// ListWaves lists waves without watch functionality
+func ListWaves(ctx context.Context, configFlags *genericclioptions.ConfigFlags, namespace, outputFormat, waveName string, useUTC bool, query string) error {
+	c, err := client.GetDynamicClient(configFlags)
+	if err != nil {
+		return fmt.Errorf("failed to get client: %v", err)
+	}
+
+	outputFormat = output.NormalizeFormat(outputFormat)
+	if outputFormat != "table" && outputFormat != "json" && outputFormat != "yaml" && outputFormat != "markdown" && !output.IsTemplateFormat(outputFormat) {
+		return fmt.Errorf("unsupported output format: %s. Supported formats: table, json, yaml, markdown", outputFormat)
+	}
+
+	items, err := getWaveItems(ctx, c, namespace, waveName, useUTC)
+	if err != nil {
+		return err
+	}
+
+	var queryOpts *querypkg.QueryOptions
+	if query != "" {
+		queryOpts, err = querypkg.ParseQueryString(query)
+		if err != nil {
+			return fmt.Errorf("failed to parse query: %v", err)
+		}
+		items, err = querypkg.ApplyQuery(items, queryOpts)
+		if err != nil {
+			return fmt.Errorf("error applying query: %v", err)
+		}
+	}
+
+	emptyMessage := "No waves found."
+	if output.IsTemplateFormat(outputFormat) {
+		return output.PrintTemplate(items, outputFormat)
+	}
+
+	switch outputFormat {
+	case "json":
+		return output.PrintJSONWithEmpty(items, emptyMessage)
+	case "yaml":
+		return output.PrintYAMLWithEmpty(items, emptyMessage)
+	}
+
+	columns := waveColumns(namespace == "")
+	markdown := outputFormat == "markdown"
+	if markdown {
+		err = output.PrintMarkdownWithQuery(items, columns, queryOpts, emptyMessage)
+	} else {
+		err = output.PrintTableWithQuery(items, columns, queryOpts, emptyMessage)
+	}
+	if err != nil || waveName == "" || len(items) != 1 {
+		return err
+	}
+
+	// A single wave also shows the progress of each of its plans, in start order
+	plans, _ := items[0]["plans"].([]interface{})
+	fmt.Println()
+	printer := output.NewTablePrinter().WithColumns(planColumns()...)
+	for _, plan := range plans {
+		if planItem, ok := plan.(map[string]interface{}); ok {
+			printer.AddItem(planItem)
+		}
+	}
+	if markdown {
+		return printer.PrintMarkdown()
+	}
+	return printer.Print()
+}
+
+// List lists waves with optional watch mode
+func List(ctx context.Context, configFlags *genericclioptions.ConfigFlags, namespace string, watchMode bool, outputFormat, waveName string, useUTC bool, query string) error {
+	return watch.WrapWithWatch(watchMode, outputFormat, func() error {
+		return ListWaves(ctx, configFlags, namespace, outputFormat, waveName, useUTC, query)
+	}, watch.DefaultInterval)
+}
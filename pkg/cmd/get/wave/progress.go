@@ -0,0 +1,132 @@
+package wave
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/get/plan/status"
+	"github.com/yaacov/kubectl-mtv/pkg/util/client"
+	wavepkg "github.com/yaacov/kubectl-mtv/pkg/util/wave"
+)
+
+// Wave and plan progress states that are not plan condition types
+const (
+	StatusPending   = "Pending"
+	StatusScheduled = "Scheduled"
+	StatusNotFound  = "NotFound"
+)
+
+// PlanProgress is the progress of a single plan in a wave
+type PlanProgress struct {
+	Name        string
+	Status      string
+	VMs         int
+	VMSucceeded int
+	VMFailed    int
+}
+
+// WaveProgress is the aggregated progress of all plans in a wave
+type WaveProgress struct {
+	Plans     []PlanProgress
+	Succeeded int
+	Failed    int
+	Running   int
+	Pending   int
+	VMs       int
+	VMsDone   int
+	VMsFailed int
+}
+
+// GetPlanProgress returns the current progress of a plan from its latest migration
+func GetPlanProgress(ctx context.Context, c dynamic.Interface, namespace, planName string) PlanProgress {
+	progress := PlanProgress{Name: planName, Status: StatusNotFound}
+
+	plan, err := c.Resource(client.PlansGVR).Namespace(namespace).Get(ctx, planName, metav1.GetOptions{})
+	if err != nil {
+		return progress
+	}
+
+	planStatus, _ := status.GetPlanStatus(plan)
+	progress.Status = planStatus
+	if planStatus == status.StatusUnknown {
+		progress.Status = StatusPending
+	}
+
+	runningMigration, latestMigration, err := status.GetRunningMigration(c, namespace, plan, client.MigrationsGVR)
+	if err != nil {
+		return progress
+	}
+	migration := runningMigration
+	if migration == nil {
+		migration = latestMigration
+	}
+	if migration == nil {
+		return progress
+	}
+
+	if stats, err := status.GetVMStats(migration); err == nil {
+		progress.VMs = stats.Total
+		progress.VMSucceeded = stats.Succeeded
+		progress.VMFailed = stats.Failed
+	}
+	return progress
+}
+
+// GetWaveProgress aggregates the progress of every plan in the wave
+func GetWaveProgress(ctx context.Context, c dynamic.Interface, w *wavepkg.Wave) WaveProgress {
+	plans := make([]PlanProgress, 0, len(w.Plans))
+	for _, planName := range w.Plans {
+		plans = append(plans, GetPlanProgress(ctx, c, w.Namespace, planName))
+	}
+	return aggregateProgress(plans)
+}
+
+// aggregateProgress sums plan progress into wave progress
+func aggregateProgress(plans []PlanProgress) WaveProgress {
+	progress := WaveProgress{Plans: plans}
+	for _, plan := range plans {
+		switch plan.Status {
+		case status.StatusSucceeded:
+			progress.Succeeded++
+		case status.StatusFailed, status.StatusCanceled, StatusNotFound:
+			progress.Failed++
+		case status.StatusRunning, status.StatusExecuting:
+			progress.Running++
+		default:
+			progress.Pending++
+		}
+		progress.VMs += plan.VMs
+		progress.VMsDone += plan.VMSucceeded
+		progress.VMsFailed += plan.VMFailed
+	}
+	return progress
+}
+
+// WaveStatus returns the overall status of a wave from its plans and schedule
+func WaveStatus(w *wavepkg.Wave, progress WaveProgress, now time.Time) string {
+	switch {
+	case progress.Running > 0:
+		return status.StatusRunning
+	case len(progress.Plans) > 0 && progress.Succeeded == len(progress.Plans):
+		return status.StatusSucceeded
+	case progress.Failed > 0 && progress.Pending == 0:
+		return status.StatusFailed
+	case w.StartedAt != nil && w.CompletedAt == nil:
+		return status.StatusRunning
+	case w.StartedAt != nil && w.Result != "":
+		return w.Result
+	case w.StartAt != nil && w.StartAt.After(now):
+		return StatusScheduled
+	default:
+		return StatusPending
+	}
+}
+
+// fraction formats done/total counts
+func fraction(done, total int) string {
+	return fmt.Sprintf("%d/%d", done, total)
+}
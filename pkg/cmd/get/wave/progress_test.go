@@ -0,0 +1,77 @@
+package wave
+
+import (
+	"testing"
+	"time"
+
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/get/plan/status"
+	wavepkg "github.com/yaacov/kubectl-mtv/pkg/util/wave"
+)
+
+func TestWaveStatus(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	future := now.Add(time.Hour)
+	started := now.Add(-time.Hour)
+
+	tests := []struct {
+		name  string
+		wave  wavepkg.Wave
+		plans []PlanProgress
+		want  string
+	}{
+		{
+			name:  "scheduled",
+			wave:  wavepkg.Wave{StartAt: &future},
+			plans: []PlanProgress{{Status: StatusPending}},
+			want:  StatusScheduled,
+		},
+		{
+			name:  "pending",
+			wave:  wavepkg.Wave{},
+			plans: []PlanProgress{{Status: StatusPending}},
+			want:  StatusPending,
+		},
+		{
+			name:  "running",
+			wave:  wavepkg.Wave{StartedAt: &started},
+			plans: []PlanProgress{{Status: status.StatusSucceeded}, {Status: status.StatusRunning}},
+			want:  status.StatusRunning,
+		},
+		{
+			name:  "succeeded",
+			wave:  wavepkg.Wave{StartedAt: &started},
+			plans: []PlanProgress{{Status: status.StatusSucceeded}, {Status: status.StatusSucceeded}},
+			want:  status.StatusSucceeded,
+		},
+		{
+			name:  "failed",
+			wave:  wavepkg.Wave{StartedAt: &started},
+			plans: []PlanProgress{{Status: status.StatusSucceeded}, {Status: status.StatusFailed}},
+			want:  status.StatusFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := WaveStatus(&tt.wave, aggregateProgress(tt.plans), now); got != tt.want {
+				t.Errorf("WaveStatus() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAggregateProgress(t *testing.T) {
+	progress := aggregateProgress([]PlanProgress{
+		{Name: "a", Status: status.StatusSucceeded, VMs: 3, VMSucceeded: 3},
+		{Name: "b", Status: status.StatusRunning, VMs: 4, VMSucceeded: 1, VMFailed: 1},
+		{Name: "c", Status: StatusPending},
+		{Name: "d", Status: StatusNotFound},
+	})
+
+	if progress.Succeeded != 1 || progress.Running != 1 || progress.Pending != 1 || progress.Failed != 1 {
+		t.Errorf("unexpected plan counts: %+v", progress)
+	}
+	if progress.VMs != 7 || progress.VMsDone != 4 || progress.VMsFailed != 1 {
+		t.Errorf("unexpected VM counts: %+v", progress)
+	}
+}
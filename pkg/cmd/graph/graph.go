@@ -0,0 +1,242 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/klog/v2"
+
+	"github.com/yaacov/kubectl-mtv/pkg/util/client"
+)
+
+// Node kinds
+const (
+	KindPlan           = "Plan"
+	KindProvider       = "Provider"
+	KindNetworkMap     = "NetworkMap"
+	KindStorageMap     = "StorageMap"
+	KindHook           = "Hook"
+	KindMigration      = "Migration"
+	KindVirtualMachine = "VirtualMachine"
+)
+
+// Node is a resource in the graph
+type Node struct {
+	ID        string `json:"id"`
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+	// Missing marks referenced resources that do not exist
+	Missing bool `json:"missing,omitempty"`
+}
+
+// Edge is a reference from one resource to another
+type Edge struct {
+	From  string `json:"from"`
+	To    string `json:"to"`
+	Label string `json:"label"`
+}
+
+// Graph holds the resources related to a plan and the references between them
+type Graph struct {
+	Nodes []*Node `json:"nodes"`
+	Edges []Edge  `json:"edges"`
+
+	byKey map[string]*Node
+}
+
+// newGraph returns an empty graph
+func newGraph() *Graph {
+	return &Graph{Nodes: []*Node{}, Edges: []Edge{}, byKey: map[string]*Node{}}
+}
+
+// node returns the node of a resource, adding it on first use
+func (g *Graph) node(kind, namespace, name string) *Node {
+	key := kind + "/" + namespace + "/" + name
+	if n, ok := g.byKey[key]; ok {
+		return n
+	}
+	n := &Node{ID: fmt.Sprintf("n%d", len(g.Nodes)), Kind: kind, Name: name, Namespace: namespace}
+	g.byKey[key] = n
+	g.Nodes = append(g.Nodes, n)
+	return n
+}
+
+// edge adds a reference between two nodes, once
+func (g *Graph) edge(from, to *Node, label string) {
+	for _, e := range g.Edges {
+		if e.From == from.ID && e.To == to.ID && e.Label == label {
+			return
+		}
+	}
+	g.Edges = append(g.Edges, Edge{From: from.ID, To: to.ID, Label: label})
+}
+
+// ref is a namespaced reference to a resource
+type ref struct {
+	Name      string
+	Namespace string
+}
+
+// nestedRef reads a {name, namespace} reference, defaulting the namespace
+func nestedRef(obj map[string]interface{}, defaultNamespace string, fields ...string) ref {
+	name, _, _ := unstructured.NestedString(obj, append(fields, "name")...)
+	namespace, _, _ := unstructured.NestedString(obj, append(fields, "namespace")...)
+	if namespace == "" {
+		namespace = defaultNamespace
+	}
+	return ref{Name: name, Namespace: namespace}
+}
+
+// Build collects a plan and the resources it references or created: its providers, mappings
+// and hooks, its migrations, and the VirtualMachines created in the target namespace.
+// References to resources that do not exist are kept as missing nodes.
+func Build(ctx context.Context, c dynamic.Interface, name, namespace string) (*Graph, error) {
+	plan, err := c.Resource(client.PlansGVR).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get plan '%s': %v", name, err)
+	}
+
+	g := newGraph()
+	planNode := g.node(KindPlan, plan.GetNamespace(), plan.GetName())
+	fetch := func(gvr schema.GroupVersionResource, kind string, r ref) (*Node, *unstructured.Unstructured) {
+		n := g.node(kind, r.Namespace, r.Name)
+		obj, err := c.Resource(gvr).Namespace(r.Namespace).Get(ctx, r.Name, metav1.GetOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				n.Missing = true
+			} else {
+				klog.V(1).Infof("Failed to get %s '%s/%s': %v", kind, r.Namespace, r.Name, err)
+			}
+			return n, nil
+		}
+		return n, obj
+	}
+
+	// Providers
+	for _, role := range []string{"source", "destination"} {
+		if r := nestedRef(plan.Object, plan.GetNamespace(), "spec", "provider", role); r.Name != "" {
+			n, _ := fetch(client.ProvidersGVR, KindProvider, r)
+			g.edge(planNode, n, role)
+		}
+	}
+
+	// Mappings and the providers they were created for
+	mappings := []struct {
+		field string
+		gvr   schema.GroupVersionResource
+		kind  string
+	}{
+		{"network", client.NetworkMapGVR, KindNetworkMap},
+		{"storage", client.StorageMapGVR, KindStorageMap},
+	}
+	for _, m := range mappings {
+		r := nestedRef(plan.Object, plan.GetNamespace(), "spec", "map", m.field)
+		if r.Name == "" {
+			continue
+		}
+		n, obj := fetch(m.gvr, m.kind, r)
+		g.edge(planNode, n, m.field+" map")
+		if obj == nil {
+			continue
+		}
+		for _, role := range []string{"source", "destination"} {
+			if pr := nestedRef(obj.Object, obj.GetNamespace(), "spec", "provider", role); pr.Name != "" {
+				pn, _ := fetch(client.ProvidersGVR, KindProvider, pr)
+				g.edge(n, pn, role)
+			}
+		}
+	}
+
+	// Hooks of the plan VMs
+	vms, _, _ := unstructured.NestedSlice(plan.Object, "spec", "vms")
+	for _, v := range vms {
+		vm, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		hooks, _, _ := unstructured.NestedSlice(vm, "hooks")
+		for _, h := range hooks {
+			hook, ok := h.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			r := nestedRef(hook, plan.GetNamespace(), "hook")
+			if r.Name == "" {
+				continue
+			}
+			step, _, _ := unstructured.NestedString(hook, "step")
+			n, _ := fetch(client.HooksGVR, KindHook, r)
+			g.edge(planNode, n, step)
+		}
+	}
+
+	// Migrations, oldest first
+	migrationNodes := map[string]*Node{}
+	list, err := c.Resource(client.MigrationsGVR).Namespace(plan.GetNamespace()).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list migrations: %v", err)
+	}
+	migrations := list.Items
+	sort.SliceStable(migrations, func(i, j int) bool {
+		return migrations[i].GetCreationTimestamp().Time.Before(migrations[j].GetCreationTimestamp().Time)
+	})
+	for _, m := range migrations {
+		planUID, _, _ := unstructured.NestedString(m.Object, "spec", "plan", "uid")
+		planName, _, _ := unstructured.NestedString(m.Object, "spec", "plan", "name")
+		if planUID != string(plan.GetUID()) && (planUID != "" || planName != plan.GetName()) {
+			continue
+		}
+		n := g.node(KindMigration, m.GetNamespace(), m.GetName())
+		g.edge(n, planNode, "plan")
+		migrationNodes[string(m.GetUID())] = n
+	}
+
+	// VirtualMachines created by the plan, linked to the migration that created them
+	targetNamespace, _, _ := unstructured.NestedString(plan.Object, "spec", "targetNamespace")
+	if targetNamespace == "" {
+		targetNamespace = plan.GetNamespace()
+	}
+	targets, err := c.Resource(client.VirtualMachinesGVR).Namespace(targetNamespace).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("plan=%s", plan.GetUID()),
+	})
+	if err != nil {
+		klog.V(1).Infof("Failed to list VirtualMachines in '%s': %v", targetNamespace, err)
+		return g, nil
+	}
+	for _, vm := range targets.Items {
+		n := g.node(KindVirtualMachine, vm.GetNamespace(), vm.GetName())
+		if m, ok := migrationNodes[vm.GetLabels()["migration"]]; ok {
+			g.edge(m, n, "created")
+		} else {
+			g.edge(planNode, n, "created")
+		}
+	}
+
+	return g, nil
+}
+
+// Print builds the graph of a plan and prints it as dot or mermaid source
+func Print(ctx context.Context, configFlags *genericclioptions.ConfigFlags, name, namespace, format string) error {
+	c, err := client.GetDynamicClient(configFlags)
+	if err != nil {
+		return fmt.Errorf("failed to get client: %v", err)
+	}
+	g, err := Build(ctx, c, name, namespace)
+	if err != nil {
+		return err
+	}
+	out, err := Render(g, format)
+	if err != nil {
+		return err
+	}
+	fmt.Print(out)
+	return nil
+}
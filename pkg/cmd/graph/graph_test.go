@@ -0,0 +1,159 @@
+package graph
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/yaacov/kubectl-mtv/pkg/util/client"
+)
+
+func testObject(gvr schema.GroupVersionResource, kind, namespace, name string, spec map[string]interface{}) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": gvr.GroupVersion().String(),
+		"kind":       kind,
+		"spec":       spec,
+	}}
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+	obj.SetUID(types.UID(name + "-uid"))
+	return obj
+}
+
+func testGraphClient() *dynamicfake.FakeDynamicClient {
+	plan := testObject(client.PlansGVR, "Plan", "mtv", "wave1", map[string]interface{}{
+		"targetNamespace": "prod",
+		"provider": map[string]interface{}{
+			"source":      map[string]interface{}{"name": "vsphere"},
+			"destination": map[string]interface{}{"name": "host"},
+		},
+		"map": map[string]interface{}{
+			"network": map[string]interface{}{"name": "wave1-network"},
+			"storage": map[string]interface{}{"name": "wave1-storage"},
+		},
+		"vms": []interface{}{
+			map[string]interface{}{
+				"name":  "web",
+				"hooks": []interface{}{map[string]interface{}{"step": "PreHook", "hook": map[string]interface{}{"name": "quiesce"}}},
+			},
+		},
+	})
+	networkMap := testObject(client.NetworkMapGVR, "NetworkMap", "mtv", "wave1-network", map[string]interface{}{
+		"provider": map[string]interface{}{
+			"source":      map[string]interface{}{"name": "vsphere-old"},
+			"destination": map[string]interface{}{"name": "host"},
+		},
+	})
+	migration := testObject(client.MigrationsGVR, "Migration", "mtv", "wave1-x1", map[string]interface{}{
+		"plan": map[string]interface{}{"name": "wave1", "uid": "wave1-uid"},
+	})
+	other := testObject(client.MigrationsGVR, "Migration", "mtv", "wave2-x1", map[string]interface{}{
+		"plan": map[string]interface{}{"name": "wave2", "uid": "wave2-uid"},
+	})
+	vm := testObject(client.VirtualMachinesGVR, "VirtualMachine", "prod", "web", map[string]interface{}{})
+	vm.SetLabels(map[string]string{"plan": "wave1-uid", "migration": "wave1-x1-uid"})
+
+	objects := []runtime.Object{
+		plan, networkMap, migration, other, vm,
+		testObject(client.ProvidersGVR, "Provider", "mtv", "vsphere", map[string]interface{}{}),
+		testObject(client.ProvidersGVR, "Provider", "mtv", "host", map[string]interface{}{}),
+	}
+	return dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		client.PlansGVR:           "PlanList",
+		client.ProvidersGVR:       "ProviderList",
+		client.NetworkMapGVR:      "NetworkMapList",
+		client.StorageMapGVR:      "StorageMapList",
+		client.HooksGVR:           "HookList",
+		client.MigrationsGVR:      "MigrationList",
+		client.VirtualMachinesGVR: "VirtualMachineList",
+	}, objects...)
+}
+
+func TestBuild(t *testing.T) {
+	g, err := Build(context.Background(), testGraphClient(), "wave1", "mtv")
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	nodes := map[string]*Node{}
+	for _, n := range g.Nodes {
+		nodes[n.Kind+"/"+n.Name] = n
+	}
+	for _, key := range []string{
+		"Plan/wave1", "Provider/vsphere", "Provider/host", "Provider/vsphere-old", "NetworkMap/wave1-network",
+		"StorageMap/wave1-storage", "Hook/quiesce", "Migration/wave1-x1", "VirtualMachine/web",
+	} {
+		if nodes[key] == nil {
+			t.Errorf("missing node %s", key)
+		}
+	}
+	if nodes["Migration/wave2-x1"] != nil {
+		t.Errorf("migration of another plan in the graph")
+	}
+	for key, missing := range map[string]bool{
+		"Provider/vsphere": false, "NetworkMap/wave1-network": false,
+		"StorageMap/wave1-storage": true, "Hook/quiesce": true, "Provider/vsphere-old": true,
+	} {
+		if n := nodes[key]; n != nil && n.Missing != missing {
+			t.Errorf("%s missing = %t, want %t", key, n.Missing, missing)
+		}
+	}
+
+	edges := map[string]bool{}
+	for _, e := range g.Edges {
+		edges[e.From+" "+e.Label+" "+e.To] = true
+	}
+	for _, want := range []struct{ from, label, to string }{
+		{"Plan/wave1", "source", "Provider/vsphere"},
+		{"NetworkMap/wave1-network", "source", "Provider/vsphere-old"},
+		{"Plan/wave1", "PreHook", "Hook/quiesce"},
+		{"Migration/wave1-x1", "plan", "Plan/wave1"},
+		{"Migration/wave1-x1", "created", "VirtualMachine/web"},
+	} {
+		if !edges[nodes[want.from].ID+" "+want.label+" "+nodes[want.to].ID] {
+			t.Errorf("missing edge %s -%s-> %s", want.from, want.label, want.to)
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	g := newGraph()
+	plan := g.node(KindPlan, "mtv", "wave1")
+	provider := g.node(KindProvider, "mtv", `say "hi"`)
+	provider.Missing = true
+	g.edge(plan, provider, "source")
+	g.edge(plan, provider, "source")
+
+	dot, err := Render(g, "dot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"digraph plan {", `n1 [label="Provider\nmtv/say \"hi\"\n(missing)", shape=cylinder, style=dashed`, `n0 -> n1 [label="source"];`} {
+		if !strings.Contains(dot, want) {
+			t.Errorf("dot missing %q:\n%s", want, dot)
+		}
+	}
+	if strings.Count(dot, "->") != 1 {
+		t.Errorf("duplicate edges in dot:\n%s", dot)
+	}
+
+	mermaid, err := Render(g, "mermaid")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"flowchart LR", `n1[("Provider<br/>mtv/say #quot;hi#quot;<br/>(missing)")]`, `n0 -->|"source"| n1`, "class n1 missing"} {
+		if !strings.Contains(mermaid, want) {
+			t.Errorf("mermaid missing %q:\n%s", want, mermaid)
+		}
+	}
+
+	if _, err := Render(g, "png"); err == nil {
+		t.Error("expected an error for an unsupported format")
+	}
+}
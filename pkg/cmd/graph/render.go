@@ -0,0 +1,113 @@
+package graph
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Render formats the graph as Graphviz dot, mermaid flowchart or json
+func Render(g *Graph, format string) (string, error) {
+	switch strings.ToLower(format) {
+	case "", "dot":
+		return renderDot(g), nil
+	case "mermaid":
+		return renderMermaid(g), nil
+	case "json":
+		data, err := json.MarshalIndent(g, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("failed to encode graph: %v", err)
+		}
+		return string(data) + "\n", nil
+	default:
+		return "", fmt.Errorf("unsupported output format: %s. Supported formats: dot, mermaid, json", format)
+	}
+}
+
+// nodeShapes are the dot shapes of the node kinds
+var nodeShapes = map[string]string{
+	KindPlan:           "box",
+	KindProvider:       "cylinder",
+	KindNetworkMap:     "hexagon",
+	KindStorageMap:     "hexagon",
+	KindHook:           "cds",
+	KindMigration:      "ellipse",
+	KindVirtualMachine: "component",
+}
+
+// nodeLabel returns the lines of a node label: kind, namespace/name and a missing marker
+func nodeLabel(n *Node) []string {
+	lines := []string{n.Kind, n.Name}
+	if n.Namespace != "" {
+		lines[1] = n.Namespace + "/" + n.Name
+	}
+	if n.Missing {
+		lines = append(lines, "(missing)")
+	}
+	return lines
+}
+
+// renderDot formats the graph as Graphviz dot; missing resources are drawn dashed red
+func renderDot(g *Graph) string {
+	var sb strings.Builder
+	sb.WriteString("digraph plan {\n")
+	sb.WriteString("  rankdir=LR;\n")
+	sb.WriteString("  node [fontname=\"Helvetica\"];\n")
+	sb.WriteString("  edge [fontname=\"Helvetica\", fontsize=10];\n")
+	for _, n := range g.Nodes {
+		attrs := fmt.Sprintf("label=%s, shape=%s", dotQuote(strings.Join(nodeLabel(n), "\n")), nodeShapes[n.Kind])
+		if n.Missing {
+			attrs += ", style=dashed, color=red, fontcolor=red"
+		}
+		fmt.Fprintf(&sb, "  %s [%s];\n", n.ID, attrs)
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(&sb, "  %s -> %s [label=%s];\n", e.From, e.To, dotQuote(e.Label))
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// dotQuote quotes a dot string
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + strings.ReplaceAll(s, "\n", `\n`) + `"`
+}
+
+// renderMermaid formats the graph as a mermaid flowchart; missing resources use the
+// "missing" class
+func renderMermaid(g *Graph) string {
+	var sb strings.Builder
+	sb.WriteString("flowchart LR\n")
+	missing := []string{}
+	for _, n := range g.Nodes {
+		label := mermaidQuote(strings.Join(nodeLabel(n), "<br/>"))
+		switch n.Kind {
+		case KindProvider:
+			fmt.Fprintf(&sb, "  %s[(%s)]\n", n.ID, label)
+		case KindNetworkMap, KindStorageMap:
+			fmt.Fprintf(&sb, "  %s{{%s}}\n", n.ID, label)
+		case KindMigration:
+			fmt.Fprintf(&sb, "  %s([%s])\n", n.ID, label)
+		default:
+			fmt.Fprintf(&sb, "  %s[%s]\n", n.ID, label)
+		}
+		if n.Missing {
+			missing = append(missing, n.ID)
+		}
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(&sb, "  %s -->|%s| %s\n", e.From, mermaidQuote(e.Label), e.To)
+	}
+	if len(missing) > 0 {
+		sb.WriteString("  classDef missing stroke:#d00,stroke-dasharray:5 5,color:#d00\n")
+		fmt.Fprintf(&sb, "  class %s missing\n", strings.Join(missing, ","))
+	}
+	return sb.String()
+}
+
+// mermaidQuote quotes a mermaid label
+func mermaidQuote(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, "#quot;") + `"`
+}
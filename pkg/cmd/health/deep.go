@@ -0,0 +1,450 @@
+package health
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/yaacov/kubectl-mtv/pkg/util/client"
+)
+
+// Deep check status values
+const (
+	DeepCheckPass    = "Pass"
+	DeepCheckFail    = "Fail"
+	DeepCheckSkipped = "Skipped"
+)
+
+// ForkliftControllerDeployment is the name of the forklift-controller deployment
+const ForkliftControllerDeployment = "forklift-controller"
+
+// webhookCertExpiryWarning is how close to expiry a webhook CA is reported as a warning
+const webhookCertExpiryWarning = 30 * 24 * time.Hour
+
+var (
+	crdGVR = schema.GroupVersionResource{
+		Group:    "apiextensions.k8s.io",
+		Version:  "v1",
+		Resource: "customresourcedefinitions",
+	}
+	cdiGVR = schema.GroupVersionResource{
+		Group:    "cdi.kubevirt.io",
+		Version:  "v1beta1",
+		Resource: "cdis",
+	}
+	validatingWebhookGVR = schema.GroupVersionResource{
+		Group:    "admissionregistration.k8s.io",
+		Version:  "v1",
+		Resource: "validatingwebhookconfigurations",
+	}
+	mutatingWebhookGVR = schema.GroupVersionResource{
+		Group:    "admissionregistration.k8s.io",
+		Version:  "v1",
+		Resource: "mutatingwebhookconfigurations",
+	}
+)
+
+// forkliftCRDs lists the Forklift CRDs whose served versions are verified by the deep check
+var forkliftCRDs = []string{
+	"providers.forklift.konveyor.io",
+	"plans.forklift.konveyor.io",
+	"migrations.forklift.konveyor.io",
+	"networkmaps.forklift.konveyor.io",
+	"storagemaps.forklift.konveyor.io",
+	"hosts.forklift.konveyor.io",
+	"hooks.forklift.konveyor.io",
+	"forkliftcontrollers.forklift.konveyor.io",
+}
+
+// DeepCheck is the result of a single deep diagnostic check
+type DeepCheck struct {
+	Name      string        `json:"name" yaml:"name"`
+	Component string        `json:"component" yaml:"component"`
+	Status    string        `json:"status" yaml:"status"`
+	Severity  IssueSeverity `json:"severity" yaml:"severity"`
+	Message   string        `json:"message" yaml:"message"`
+	Duration  string        `json:"duration,omitempty" yaml:"duration,omitempty"`
+}
+
+// addDeepCheck records a deep check result and, for failures, a matching report issue.
+func (r *HealthReport) addDeepCheck(check DeepCheck, suggestion string) {
+	r.DeepChecks = append(r.DeepChecks, check)
+	if check.Status == DeepCheckFail {
+		r.AddIssue(check.Severity, check.Component, check.Name, check.Message, suggestion)
+	}
+}
+
+// RunDeepChecks runs the --deep diagnostics and appends their results to the report.
+// Operator components are checked in operatorNamespace; providers in providerNamespace
+// (or all namespaces when allNamespaces is set).
+func RunDeepChecks(ctx context.Context, configFlags *genericclioptions.ConfigFlags, report *HealthReport, operatorNamespace, providerNamespace string, allNamespaces bool, inventoryURL string, insecureSkipTLS bool) {
+	dynamicClient, err := client.GetDynamicClient(configFlags)
+	if err != nil {
+		report.addDeepCheck(DeepCheck{
+			Name:      "cluster-client",
+			Component: "Cluster",
+			Status:    DeepCheckFail,
+			Severity:  SeverityCritical,
+			Message:   fmt.Sprintf("Failed to create cluster client: %v", err),
+		}, "Check kubeconfig and cluster connectivity")
+		return
+	}
+
+	checkControllerDeployment(ctx, configFlags, report, operatorNamespace)
+	// The version negotiated with the cluster, v1beta1 unless an operator upgrade dropped it
+	apiVersion, err := client.ForkliftVersion(configFlags)
+	if err != nil {
+		apiVersion = client.Version
+	}
+	checkCRDVersions(ctx, dynamicClient, report, apiVersion)
+	checkCDI(ctx, dynamicClient, report)
+	checkWebhookCertificates(ctx, dynamicClient, report, operatorNamespace)
+
+	if inventoryURL == "" {
+		inventoryURL = client.DiscoverInventoryURL(ctx, configFlags, operatorNamespace)
+	}
+	if checkInventoryRoute(ctx, configFlags, report, inventoryURL, insecureSkipTLS) {
+		checkProviderConnectivity(ctx, configFlags, dynamicClient, report, providerNamespace, allNamespaces, inventoryURL, insecureSkipTLS)
+	} else {
+		report.addDeepCheck(DeepCheck{
+			Name:      "provider-connectivity",
+			Component: "Providers",
+			Status:    DeepCheckSkipped,
+			Severity:  SeverityInfo,
+			Message:   "Skipped because the inventory service is not reachable",
+		}, "")
+	}
+}
+
+// checkControllerDeployment verifies that the forklift-controller deployment is fully available.
+func checkControllerDeployment(ctx context.Context, configFlags *genericclioptions.ConfigFlags, report *HealthReport, namespace string) {
+	check := DeepCheck{Name: ForkliftControllerDeployment, Component: "Deployment"}
+
+	clientset, err := client.GetKubernetesClientset(configFlags)
+	if err != nil {
+		check.Status, check.Severity = DeepCheckFail, SeverityWarning
+		check.Message = fmt.Sprintf("Failed to get kubernetes clientset: %v", err)
+		report.addDeepCheck(check, "Check cluster connectivity and RBAC permissions")
+		return
+	}
+
+	deployment, err := clientset.AppsV1().Deployments(namespace).Get(ctx, ForkliftControllerDeployment, metav1.GetOptions{})
+	if err != nil {
+		check.Status, check.Severity = DeepCheckFail, SeverityCritical
+		check.Message = fmt.Sprintf("Failed to get deployment in namespace %s: %v", namespace, err)
+		report.addDeepCheck(check, "Verify the ForkliftController reconciled successfully")
+		return
+	}
+
+	desired := int32(1)
+	if deployment.Spec.Replicas != nil {
+		desired = *deployment.Spec.Replicas
+	}
+	ready := deployment.Status.ReadyReplicas
+	if ready < desired {
+		check.Status, check.Severity = DeepCheckFail, SeverityCritical
+		check.Message = fmt.Sprintf("%d/%d replicas ready", ready, desired)
+		report.addDeepCheck(check, "Check forklift-controller pod status and events")
+		return
+	}
+
+	check.Status, check.Severity = DeepCheckPass, SeverityInfo
+	check.Message = fmt.Sprintf("%d/%d replicas ready", ready, desired)
+	report.addDeepCheck(check, "")
+}
+
+// checkCRDVersions verifies that every Forklift CRD serves the API version used by kubectl-mtv.
+func checkCRDVersions(ctx context.Context, dynamicClient dynamic.Interface, report *HealthReport, apiVersion string) {
+	for _, name := range forkliftCRDs {
+		check := DeepCheck{Name: name, Component: "CRD"}
+
+		crd, err := dynamicClient.Resource(crdGVR).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			check.Status, check.Severity = DeepCheckFail, SeverityCritical
+			if errors.IsNotFound(err) {
+				check.Message = "CRD is not installed"
+			} else {
+				check.Message = fmt.Sprintf("Failed to get CRD: %v", err)
+			}
+			report.addDeepCheck(check, "Reinstall or upgrade the MTV operator")
+			continue
+		}
+
+		served, storage := crdVersions(crd)
+		if !slices.Contains(served, apiVersion) {
+			check.Status, check.Severity = DeepCheckFail, SeverityCritical
+			check.Message = fmt.Sprintf("%s is not served (served: %s)", apiVersion, strings.Join(served, ", "))
+			report.addDeepCheck(check, "Use a kubectl-mtv release that matches the installed MTV version")
+			continue
+		}
+
+		check.Status, check.Severity = DeepCheckPass, SeverityInfo
+		check.Message = fmt.Sprintf("served: %s, storage: %s", strings.Join(served, ", "), storage)
+		report.addDeepCheck(check, "")
+	}
+}
+
+// crdVersions returns the served versions and the storage version of a CRD.
+func crdVersions(crd *unstructured.Unstructured) ([]string, string) {
+	versions, _, _ := unstructured.NestedSlice(crd.Object, "spec", "versions")
+	var served []string
+	storage := ""
+	for _, v := range versions {
+		version, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := version["name"].(string)
+		if isServed, _ := version["served"].(bool); isServed {
+			served = append(served, name)
+		}
+		if isStorage, _ := version["storage"].(bool); isStorage {
+			storage = name
+		}
+	}
+	return served, storage
+}
+
+// checkCDI verifies that the Containerized Data Importer is installed and available.
+func checkCDI(ctx context.Context, dynamicClient dynamic.Interface, report *HealthReport) {
+	check := DeepCheck{Name: "cdi", Component: "CDI"}
+
+	if _, err := dynamicClient.Resource(crdGVR).Get(ctx, "datavolumes.cdi.kubevirt.io", metav1.GetOptions{}); err != nil {
+		check.Status, check.Severity = DeepCheckFail, SeverityCritical
+		if errors.IsNotFound(err) {
+			check.Message = "CDI is not installed (datavolumes.cdi.kubevirt.io CRD not found)"
+		} else {
+			check.Message = fmt.Sprintf("Failed to check CDI CRDs: %v", err)
+		}
+		report.addDeepCheck(check, "Install OpenShift Virtualization / KubeVirt with CDI")
+		return
+	}
+
+	cdis, err := dynamicClient.Resource(cdiGVR).List(ctx, metav1.ListOptions{})
+	if err != nil || len(cdis.Items) == 0 {
+		check.Status, check.Severity = DeepCheckFail, SeverityWarning
+		check.Message = "CDI CRDs exist but no CDI resource was found"
+		if err != nil {
+			check.Message = fmt.Sprintf("Failed to list CDI resources: %v", err)
+		}
+		report.addDeepCheck(check, "Verify the CDI operator is deployed")
+		return
+	}
+
+	cdi := cdis.Items[0]
+	if conditionStatus(&cdi, "Available") != "True" {
+		check.Status, check.Severity = DeepCheckFail, SeverityCritical
+		check.Message = fmt.Sprintf("CDI %s is not Available", cdi.GetName())
+		report.addDeepCheck(check, "Check CDI operator pods in the virtualization namespace")
+		return
+	}
+
+	check.Status, check.Severity = DeepCheckPass, SeverityInfo
+	check.Message = fmt.Sprintf("CDI %s is Available", cdi.GetName())
+	report.addDeepCheck(check, "")
+}
+
+// conditionStatus returns the status of a condition type in an object's status.conditions.
+func conditionStatus(obj *unstructured.Unstructured, conditionType string) string {
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if t, _ := condition["type"].(string); t == conditionType {
+			status, _ := condition["status"].(string)
+			return status
+		}
+	}
+	return ""
+}
+
+// checkWebhookCertificates verifies the CA bundles of the Forklift admission webhooks.
+func checkWebhookCertificates(ctx context.Context, dynamicClient dynamic.Interface, report *HealthReport, operatorNamespace string) {
+	found := 0
+	for _, gvr := range []schema.GroupVersionResource{validatingWebhookGVR, mutatingWebhookGVR} {
+		configs, err := dynamicClient.Resource(gvr).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			report.addDeepCheck(DeepCheck{
+				Name:      gvr.Resource,
+				Component: "Webhook",
+				Status:    DeepCheckSkipped,
+				Severity:  SeverityInfo,
+				Message:   fmt.Sprintf("Cannot list %s: %v", gvr.Resource, err),
+			}, "")
+			continue
+		}
+
+		for i := range configs.Items {
+			config := &configs.Items[i]
+			webhooks, _, _ := unstructured.NestedSlice(config.Object, "webhooks")
+			for _, w := range webhooks {
+				webhook, ok := w.(map[string]interface{})
+				if !ok || !isForkliftWebhook(config.GetName(), webhook, operatorNamespace) {
+					continue
+				}
+				found++
+				name, _ := webhook["name"].(string)
+				caBundle, _, _ := unstructured.NestedString(webhook, "clientConfig", "caBundle")
+				report.addDeepCheck(evaluateWebhookCABundle(name, caBundle, time.Now()), "Restart the forklift-operator to regenerate webhook certificates")
+			}
+		}
+	}
+
+	if found == 0 {
+		report.addDeepCheck(DeepCheck{
+			Name:      "forklift-webhooks",
+			Component: "Webhook",
+			Status:    DeepCheckFail,
+			Severity:  SeverityWarning,
+			Message:   "No Forklift admission webhooks found",
+		}, "Verify the forklift-api deployment and webhook configurations")
+	}
+}
+
+// isForkliftWebhook reports whether a webhook entry belongs to Forklift.
+func isForkliftWebhook(configName string, webhook map[string]interface{}, operatorNamespace string) bool {
+	if strings.Contains(configName, "forklift") {
+		return true
+	}
+	namespace, _, _ := unstructured.NestedString(webhook, "clientConfig", "service", "namespace")
+	return namespace != "" && namespace == operatorNamespace
+}
+
+// evaluateWebhookCABundle decodes a webhook caBundle and checks the certificate validity window.
+func evaluateWebhookCABundle(name, caBundle string, now time.Time) DeepCheck {
+	check := DeepCheck{Name: name, Component: "Webhook"}
+
+	if caBundle == "" {
+		check.Status, check.Severity = DeepCheckFail, SeverityCritical
+		check.Message = "Webhook has no caBundle"
+		return check
+	}
+
+	pemData, err := base64.StdEncoding.DecodeString(caBundle)
+	if err != nil {
+		check.Status, check.Severity = DeepCheckFail, SeverityCritical
+		check.Message = fmt.Sprintf("Failed to decode caBundle: %v", err)
+		return check
+	}
+
+	block, _ := pem.Decode(pemData)
+	if block == nil {
+		check.Status, check.Severity = DeepCheckFail, SeverityCritical
+		check.Message = "caBundle does not contain a PEM certificate"
+		return check
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		check.Status, check.Severity = DeepCheckFail, SeverityCritical
+		check.Message = fmt.Sprintf("Failed to parse caBundle certificate: %v", err)
+		return check
+	}
+
+	expires := cert.NotAfter.UTC().Format(time.RFC3339)
+	switch {
+	case now.After(cert.NotAfter):
+		check.Status, check.Severity = DeepCheckFail, SeverityCritical
+		check.Message = fmt.Sprintf("Certificate expired at %s", expires)
+	case now.Before(cert.NotBefore):
+		check.Status, check.Severity = DeepCheckFail, SeverityCritical
+		check.Message = fmt.Sprintf("Certificate is not valid before %s", cert.NotBefore.UTC().Format(time.RFC3339))
+	case cert.NotAfter.Sub(now) < webhookCertExpiryWarning:
+		check.Status, check.Severity = DeepCheckFail, SeverityWarning
+		check.Message = fmt.Sprintf("Certificate expires soon (%s)", expires)
+	default:
+		check.Status, check.Severity = DeepCheckPass, SeverityInfo
+		check.Message = fmt.Sprintf("Certificate valid until %s", expires)
+	}
+	return check
+}
+
+// checkInventoryRoute verifies that the inventory service answers at the given URL.
+// Returns true when the inventory is reachable.
+func checkInventoryRoute(ctx context.Context, configFlags *genericclioptions.ConfigFlags, report *HealthReport, inventoryURL string, insecureSkipTLS bool) bool {
+	check := DeepCheck{Name: "inventory-route", Component: "Inventory"}
+
+	if inventoryURL == "" {
+		check.Status, check.Severity = DeepCheckFail, SeverityWarning
+		check.Message = "No inventory URL provided and no forklift-inventory route found"
+		report.addDeepCheck(check, "Pass --inventory-url or set MTV_INVENTORY_URL")
+		return false
+	}
+
+	start := time.Now()
+	_, err := client.FetchProvidersWithDetailAndInsecure(ctx, configFlags, inventoryURL, 0, insecureSkipTLS)
+	check.Duration = time.Since(start).Round(time.Millisecond).String()
+	if err != nil {
+		check.Status, check.Severity = DeepCheckFail, SeverityCritical
+		check.Message = fmt.Sprintf("%s is not reachable: %v", inventoryURL, err)
+		report.addDeepCheck(check, "Check the forklift-inventory route, service and TLS settings")
+		return false
+	}
+
+	check.Status, check.Severity = DeepCheckPass, SeverityInfo
+	check.Message = fmt.Sprintf("%s is reachable", inventoryURL)
+	report.addDeepCheck(check, "")
+	return true
+}
+
+// checkProviderConnectivity queries the inventory of each provider in scope.
+func checkProviderConnectivity(ctx context.Context, configFlags *genericclioptions.ConfigFlags, dynamicClient dynamic.Interface, report *HealthReport, namespace string, allNamespaces bool, inventoryURL string, insecureSkipTLS bool) {
+	if allNamespaces {
+		namespace = metav1.NamespaceAll
+	}
+
+	providers, err := dynamicClient.Resource(client.ProvidersGVR).Namespace(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		report.addDeepCheck(DeepCheck{
+			Name:      "provider-connectivity",
+			Component: "Providers",
+			Status:    DeepCheckSkipped,
+			Severity:  SeverityInfo,
+			Message:   fmt.Sprintf("Cannot list providers: %v", err),
+		}, "")
+		return
+	}
+
+	for i := range providers.Items {
+		provider := &providers.Items[i]
+		check := DeepCheck{
+			Name:      provider.GetNamespace() + "/" + provider.GetName(),
+			Component: "Providers",
+		}
+
+		start := time.Now()
+		_, err := client.FetchProviderInventoryWithInsecure(ctx, configFlags, inventoryURL, provider, "", insecureSkipTLS)
+		check.Duration = time.Since(start).Round(time.Millisecond).String()
+		if err != nil {
+			check.Status, check.Severity = DeepCheckFail, SeverityWarning
+			check.Message = fmt.Sprintf("Inventory query failed: %v", err)
+			report.addDeepCheck(check, "Check provider credentials and network access from the forklift-controller")
+			continue
+		}
+
+		if conditionStatus(provider, "ConnectionTestSucceeded") == "False" {
+			check.Status, check.Severity = DeepCheckFail, SeverityCritical
+			check.Message = "Inventory reachable but provider connection test failed"
+			report.addDeepCheck(check, "Run 'kubectl mtv describe provider' for the connection error")
+			continue
+		}
+
+		check.Status, check.Severity = DeepCheckPass, SeverityInfo
+		check.Message = "Inventory reachable"
+		report.addDeepCheck(check, "")
+	}
+}
@@ -0,0 +1,127 @@
+package health
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func testCABundle(t *testing.T, notBefore, notAfter time.Time) string {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "forklift-api"},
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+	return base64.StdEncoding.EncodeToString(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
+func TestEvaluateWebhookCABundle(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name         string
+		caBundle     string
+		wantStatus   string
+		wantSeverity IssueSeverity
+	}{
+		{
+			name:         "valid certificate",
+			caBundle:     testCABundle(t, now.Add(-time.Hour), now.Add(365*24*time.Hour)),
+			wantStatus:   DeepCheckPass,
+			wantSeverity: SeverityInfo,
+		},
+		{
+			name:         "expiring soon",
+			caBundle:     testCABundle(t, now.Add(-time.Hour), now.Add(7*24*time.Hour)),
+			wantStatus:   DeepCheckFail,
+			wantSeverity: SeverityWarning,
+		},
+		{
+			name:         "expired",
+			caBundle:     testCABundle(t, now.Add(-48*time.Hour), now.Add(-time.Hour)),
+			wantStatus:   DeepCheckFail,
+			wantSeverity: SeverityCritical,
+		},
+		{
+			name:         "missing caBundle",
+			caBundle:     "",
+			wantStatus:   DeepCheckFail,
+			wantSeverity: SeverityCritical,
+		},
+		{
+			name:         "not PEM",
+			caBundle:     base64.StdEncoding.EncodeToString([]byte("garbage")),
+			wantStatus:   DeepCheckFail,
+			wantSeverity: SeverityCritical,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check := evaluateWebhookCABundle("forklift-api.webhook", tt.caBundle, now)
+			if check.Status != tt.wantStatus {
+				t.Errorf("status = %q, want %q (%s)", check.Status, tt.wantStatus, check.Message)
+			}
+			if check.Severity != tt.wantSeverity {
+				t.Errorf("severity = %q, want %q", check.Severity, tt.wantSeverity)
+			}
+		})
+	}
+}
+
+func TestCRDVersions(t *testing.T) {
+	crd := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"versions": []interface{}{
+				map[string]interface{}{"name": "v1alpha1", "served": false, "storage": false},
+				map[string]interface{}{"name": "v1beta1", "served": true, "storage": true},
+			},
+		},
+	}}
+
+	served, storage := crdVersions(crd)
+	if len(served) != 1 || served[0] != "v1beta1" {
+		t.Errorf("served = %v, want [v1beta1]", served)
+	}
+	if storage != "v1beta1" {
+		t.Errorf("storage = %q, want v1beta1", storage)
+	}
+}
+
+func TestAddDeepCheck_FailureAddsIssue(t *testing.T) {
+	report := NewHealthReport()
+	report.addDeepCheck(DeepCheck{Name: "cdi", Component: "CDI", Status: DeepCheckPass, Severity: SeverityInfo}, "")
+	report.addDeepCheck(DeepCheck{Name: "inventory-route", Component: "Inventory", Status: DeepCheckFail, Severity: SeverityCritical, Message: "unreachable"}, "check route")
+
+	if len(report.DeepChecks) != 2 {
+		t.Fatalf("DeepChecks = %d, want 2", len(report.DeepChecks))
+	}
+	if len(report.Issues) != 1 || report.Issues[0].Severity != SeverityCritical {
+		t.Fatalf("Issues = %+v, want one critical issue", report.Issues)
+	}
+
+	report.CalculateOverallStatus()
+	if report.OverallStatus != HealthStatusCritical {
+		t.Errorf("OverallStatus = %q, want %q", report.OverallStatus, HealthStatusCritical)
+	}
+}
@@ -19,6 +19,7 @@ func (r *HealthReport) ToDescription() *describe.Description {
 	r.buildLogAnalysisSection(b)
 	r.buildProvidersSection(b)
 	r.buildPlansSection(b)
+	r.buildDeepChecksSection(b)
 	r.buildSummarySection(b)
 
 	return b.Build()
@@ -261,6 +262,50 @@ func (r *HealthReport) buildPlansSection(b *describe.Builder) {
 	b.Table(headers, rows)
 }
 
+func (r *HealthReport) buildDeepChecksSection(b *describe.Builder) {
+	if len(r.DeepChecks) == 0 {
+		return
+	}
+
+	failed := 0
+	for _, c := range r.DeepChecks {
+		if c.Status == DeepCheckFail {
+			failed++
+		}
+	}
+
+	b.Section(fmt.Sprintf("DEEP CHECKS (%d total, %d failed)", len(r.DeepChecks), failed))
+
+	headers := []describe.TableColumn{
+		{Display: "COMPONENT", Key: "component"},
+		{Display: "CHECK", Key: "name"},
+		{Display: "STATUS", Key: "status", ColorFunc: colorizeDeepCheckStatus},
+		{Display: "SEVERITY", Key: "severity"},
+		{Display: "MESSAGE", Key: "message"},
+	}
+
+	rows := make([]map[string]string, 0, len(r.DeepChecks))
+	for _, c := range r.DeepChecks {
+		severity := ""
+		if c.Status == DeepCheckFail {
+			severity = string(c.Severity)
+		}
+		message := c.Message
+		if c.Duration != "" {
+			message += " (" + c.Duration + ")"
+		}
+		rows = append(rows, map[string]string{
+			"component": c.Component,
+			"name":      c.Name,
+			"status":    c.Status,
+			"severity":  severity,
+			"message":   message,
+		})
+	}
+
+	b.Table(headers, rows)
+}
+
 func (r *HealthReport) buildSummarySection(b *describe.Builder) {
 	b.Section("SUMMARY")
 
@@ -335,3 +380,15 @@ func colorBoolPtrWarning(val *bool, hasRemote bool) func(string) string {
 	}
 	return nil
 }
+
+// colorizeDeepCheckStatus colors a deep check status value.
+func colorizeDeepCheckStatus(status string) string {
+	switch strings.TrimSpace(status) {
+	case DeepCheckPass:
+		return output.Green(status)
+	case DeepCheckFail:
+		return output.Red(status)
+	default:
+		return output.Yellow(status)
+	}
+}
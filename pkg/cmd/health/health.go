@@ -214,6 +214,11 @@ func RunHealthCheck(ctx context.Context, configFlags *genericclioptions.ConfigFl
 		AnalyzePlansHealth(plans, report)
 	}
 
+	// 7. Deep diagnostics (opt-in, slower)
+	if opts.Deep {
+		RunDeepChecks(ctx, configFlags, report, operatorNamespace, planNS, opts.AllNamespaces, opts.InventoryURL, opts.InventoryInsecureSkipTLS)
+	}
+
 	// Calculate overall status and summary
 	report.CalculateOverallStatus()
 	report.CalculateSummary()
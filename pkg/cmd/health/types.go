@@ -35,6 +35,7 @@ type HealthReport struct {
 	Plans           []PlanHealth     `json:"plans" yaml:"plans"`
 	Issues          []HealthIssue    `json:"issues" yaml:"issues"`
 	Recommendations []string         `json:"recommendations" yaml:"recommendations"`
+	DeepChecks      []DeepCheck      `json:"deepChecks,omitempty" yaml:"deepChecks,omitempty"`
 	Summary         HealthSummary    `json:"summary" yaml:"summary"`
 	UserNamespace   string           `json:"userNamespace,omitempty" yaml:"userNamespace,omitempty"`
 	AllNamespaces   bool             `json:"allNamespaces,omitempty" yaml:"allNamespaces,omitempty"`
@@ -163,6 +164,12 @@ type HealthCheckOptions struct {
 	CheckLogs     bool
 	LogLines      int
 	Verbose       bool
+
+	// Deep enables the deep diagnostic checks (deployment readiness, inventory
+	// route, CRD versions, CDI, webhook certificates, provider connectivity)
+	Deep                     bool
+	InventoryURL             string
+	InventoryInsecureSkipTLS bool
 }
 
 // NewHealthReport creates a new health report with initial values
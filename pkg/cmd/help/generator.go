@@ -293,9 +293,9 @@ func getCategory(path []string) string {
 	}
 
 	switch path[0] {
-	case "get", "describe", "health":
+	case "get", "describe", "health", "doctor", "report", "top", "events", "cleanup", "estimate", "graph", "inventory":
 		return "read"
-	case "create", "delete", "patch", "start", "cancel", "archive", "unarchive", "cutover":
+	case "create", "delete", "patch", "start", "cancel", "archive", "unarchive", "seal", "cutover", "test":
 		return "write"
 	default:
 		return "admin"
@@ -525,6 +525,13 @@ func TestGetCategory(t *testing.T) {
 		{[]string{"describe"}, "read"},
 		{[]string{"describe", "plan"}, "read"},
 		{[]string{"health"}, "read"},
+		{[]string{"doctor"}, "read"},
+		{[]string{"report", "plan"}, "read"},
+		{[]string{"events"}, "read"},
+		{[]string{"cleanup", "snapshots"}, "read"},
+		{[]string{"estimate", "cost"}, "read"},
+		{[]string{"graph"}, "read"},
+		{[]string{"inventory", "diff"}, "read"},
 		{[]string{"create"}, "write"},
 		{[]string{"create", "plan"}, "write"},
 		{[]string{"delete"}, "write"},
@@ -536,7 +543,9 @@ func TestGetCategory(t *testing.T) {
 		{[]string{"cancel"}, "write"},
 		{[]string{"archive"}, "write"},
 		{[]string{"unarchive"}, "write"},
+		{[]string{"seal", "plan"}, "write"},
 		{[]string{"cutover"}, "write"},
+		{[]string{"test", "hook"}, "write"},
 		{[]string{"settings"}, "read"},
 		{[]string{"settings", "get"}, "read"},
 		{[]string{"settings", "set"}, "write"},
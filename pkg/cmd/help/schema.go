@@ -0,0 +1,340 @@
+package help
+
+import (
+	"regexp"
+	"strings"
+)
+
+// FieldGroup is a group of related inventory fields usable in TSL queries.
+type FieldGroup struct {
+	// Group is the group label (e.g., "Identity", "Compute")
+	Group string `json:"group" yaml:"group"`
+	// Fields are field paths or expressions (e.g., "parent.id", "len(disks)")
+	Fields []string `json:"fields" yaml:"fields"`
+}
+
+// ComputedField is a field added to inventory results by kubectl-mtv.
+type ComputedField struct {
+	Name        string `json:"name" yaml:"name"`
+	Description string `json:"description" yaml:"description"`
+}
+
+// Operator is a TSL operator, with an optional note on its semantics.
+type Operator struct {
+	Syntax      string `json:"syntax" yaml:"syntax"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+}
+
+// OperatorGroup is a group of related TSL operators.
+type OperatorGroup struct {
+	Group     string     `json:"group" yaml:"group"`
+	Operators []Operator `json:"operators" yaml:"operators"`
+}
+
+// InventorySchema describes the inventory fields of a provider type that can be
+// queried with TSL, and the operators available to query them.
+type InventorySchema struct {
+	// Provider is the provider type (e.g., "vsphere", "ovirt")
+	Provider string `json:"provider" yaml:"provider"`
+	// Title is the display name of the provider type (e.g., "vSphere")
+	Title string `json:"title" yaml:"title"`
+	// Note is an optional remark on the field naming of the provider
+	Note           string          `json:"note,omitempty" yaml:"note,omitempty"`
+	VMFields       []FieldGroup    `json:"vmFields" yaml:"vmFields"`
+	NetworkFields  []FieldGroup    `json:"networkFields" yaml:"networkFields"`
+	ComputedFields []ComputedField `json:"computedFields" yaml:"computedFields"`
+	Operators      []OperatorGroup `json:"operators" yaml:"operators"`
+}
+
+// tslOperators are the operators of the TSL query language.
+var tslOperators = []OperatorGroup{
+	{Group: "Comparison", Operators: []Operator{{Syntax: "="}, {Syntax: "!="}, {Syntax: "<>"}, {Syntax: "<"}, {Syntax: "<="}, {Syntax: ">"}, {Syntax: ">="}}},
+	{Group: "Arithmetic", Operators: []Operator{{Syntax: "+"}, {Syntax: "-"}, {Syntax: "*"}, {Syntax: "/"}, {Syntax: "%"}}},
+	{Group: "String match", Operators: []Operator{
+		{Syntax: "like", Description: "% wildcard"},
+		{Syntax: "ilike", Description: "case-insensitive like"},
+		{Syntax: "~=", Description: "regex match"},
+		{Syntax: "~!", Description: "regex not match"},
+	}},
+	{Group: "Logical", Operators: []Operator{{Syntax: "and"}, {Syntax: "or"}, {Syntax: "not"}}},
+	{Group: "Set/range", Operators: []Operator{
+		{Syntax: "in ['a','b']"},
+		{Syntax: "not in ['a','b']"},
+		{Syntax: "between X and Y"},
+	}},
+	{Group: "Null checks", Operators: []Operator{{Syntax: "is null"}, {Syntax: "is not null"}}},
+}
+
+// computedVMFields are added to the VMs of all providers by kubectl-mtv.
+var computedVMFields = []ComputedField{
+	{Name: "criticalConcerns", Description: "count of critical migration concerns"},
+	{Name: "warningConcerns", Description: "count of warning migration concerns"},
+	{Name: "infoConcerns", Description: "count of informational migration concerns"},
+	{Name: "concernsHuman", Description: "human-readable concern summary"},
+	{Name: "memoryGB", Description: "memory in GB (converted from MB or bytes)"},
+	{Name: "storageUsedGB", Description: "storage used in GB"},
+	{Name: "diskCapacity", Description: "total disk capacity"},
+	{Name: "memoryGiB", Description: "memory in GiB (number, all providers)"},
+	{Name: "diskGiB", Description: "total disk capacity in GiB (number)"},
+	{Name: "powerStateHuman", Description: "power state in a provider neutral vocabulary\n(On, Off, Suspended, Paused, Starting, Stopping, Error)"},
+	{Name: "powerStateRaw", Description: "power state as reported by the provider"},
+	{Name: "passthroughDevices", Description: "host-bound devices that will not be migrated\n(passthroughDevices[*].kind, passthroughDevices[*].severity)"},
+	{Name: "blockingDevices", Description: "count of Critical passthrough devices (PCI, SCSI, SR-IOV)"},
+	{Name: "devicesHuman", Description: "human-readable passthrough device summary"},
+	{Name: "provider", Description: "provider name"},
+}
+
+// inventorySchemas are the queryable inventory fields of each provider type. They are
+// the source of both the "help tsl" topic and the inventory schema MCP resources.
+var inventorySchemas = []InventorySchema{
+	{
+		Provider: "vsphere",
+		Title:    "vSphere",
+		VMFields: []FieldGroup{
+			{Group: "Identity", Fields: []string{"name", "id", "uuid", "path", "parent.id", "parent.kind"}},
+			{Group: "State", Fields: []string{"powerState", "connectionState"}},
+			{Group: "Compute", Fields: []string{"cpuCount", "coresPerSocket", "memoryMB"}},
+			{Group: "Guest", Fields: []string{"guestId", "guestName", "firmware", "isTemplate"}},
+			{Group: "Network", Fields: []string{"ipAddress", "hostName", "host"}},
+			{Group: "Storage", Fields: []string{"storageUsed"}},
+			{Group: "Security", Fields: []string{"secureBoot", "tpmEnabled", "changeTrackingEnabled"}},
+			{Group: "Disks", Fields: []string{"len(disks)", "disks[*].capacity", "disks[*].datastore.id", "disks[*].datastore.name", "disks[*].file", "disks[*].shared"}},
+			{Group: "NICs", Fields: []string{"len(nics)", "nics[*].mac", "nics[*].network.id"}},
+			{Group: "Networks", Fields: []string{"len(networks)", "networks[*].id", "networks[*].kind"}},
+			{Group: "Concerns", Fields: []string{"len(concerns)", "concerns[*].category", "concerns[*].assessment", "concerns[*].label"}},
+		},
+		NetworkFields: []FieldGroup{
+			{Group: "Identity", Fields: []string{"name", "id", "path", "key"}},
+			{Group: "Config", Fields: []string{"variant (Standard, DvPortGroup, DvSwitch)", "vlanId", "dvSwitch.id"}},
+			{Group: "Hosts", Fields: []string{"len(host)", "hostCount"}},
+		},
+	},
+	{
+		Provider: "ovirt",
+		Title:    "oVirt / RHV",
+		VMFields: []FieldGroup{
+			{Group: "Identity", Fields: []string{"name", "id", "path", "cluster", "host"}},
+			{Group: "State", Fields: []string{"status (up, down, ...)"}},
+			{Group: "Compute", Fields: []string{"cpuSockets", "cpuCores", "cpuThreads", "memory (bytes)"}},
+			{Group: "Guest", Fields: []string{"osType", "guestName", "guest.distribution", "guest.fullVersion"}},
+			{Group: "Config", Fields: []string{"haEnabled", "stateless", "placementPolicyAffinity", "display"}},
+			{Group: "Disks", Fields: []string{"len(diskAttachments)", "diskAttachments[*].disk", "diskAttachments[*].interface"}},
+			{Group: "NICs", Fields: []string{"len(nics)", "nics[*].name", "nics[*].mac", "nics[*].interface", "nics[*].ipAddress", "nics[*].profile"}},
+			{Group: "Concerns", Fields: []string{"len(concerns)", "concerns[*].category", "concerns[*].assessment", "concerns[*].label"}},
+		},
+		NetworkFields: []FieldGroup{
+			{Group: "Identity", Fields: []string{"name", "id", "path", "description"}},
+			{Group: "Config", Fields: []string{"dataCenter", "vlan", "usages"}},
+			{Group: "Profiles", Fields: []string{"len(profiles)"}},
+		},
+	},
+	{
+		Provider: "openstack",
+		Title:    "OpenStack",
+		VMFields: []FieldGroup{
+			{Group: "Identity", Fields: []string{"name", "id", "status"}},
+			{Group: "Resources", Fields: []string{"flavor.name", "image.name", "project.name"}},
+			{Group: "Volumes", Fields: []string{"len(attachedVolumes)", "attachedVolumes[*].ID"}},
+		},
+		NetworkFields: []FieldGroup{
+			{Group: "Identity", Fields: []string{"name", "id", "description"}},
+			{Group: "State", Fields: []string{"status", "adminStateUp", "shared"}},
+			{Group: "Subnets", Fields: []string{"len(subnets)", "subnetsCount"}},
+		},
+	},
+	{
+		Provider: "ec2",
+		Title:    "EC2",
+		Note:     "PascalCase",
+		VMFields: []FieldGroup{
+			{Group: "Identity", Fields: []string{"name", "InstanceType", "State.Name", "PlatformDetails"}},
+			{Group: "Placement", Fields: []string{"Placement.AvailabilityZone"}},
+			{Group: "Network", Fields: []string{"PublicIpAddress", "PrivateIpAddress", "VpcId", "SubnetId"}},
+			{Group: "Snapshots", Fields: []string{"State", "VolumeId", "VolumeSize", "sizeHuman", "Encrypted", "Progress"}},
+		},
+		NetworkFields: []FieldGroup{
+			{Group: "Identity", Fields: []string{"name", "id", "networkType (vpc, subnet)", "VpcId", "SubnetId"}},
+			{Group: "Config", Fields: []string{"CidrBlock", "State", "IsDefault", "AvailabilityZone"}},
+		},
+	},
+}
+
+// InventorySchemas returns the queryable inventory fields of each provider type,
+// with the computed fields and the TSL operators.
+func InventorySchemas() []InventorySchema {
+	schemas := make([]InventorySchema, 0, len(inventorySchemas))
+	for _, s := range inventorySchemas {
+		s.ComputedFields = computedVMFields
+		s.Operators = tslOperators
+		schemas = append(schemas, s)
+	}
+	return schemas
+}
+
+// GetInventorySchema returns the inventory schema of a provider type, or nil if not
+// found. The lookup is case-insensitive.
+func GetInventorySchema(provider string) *InventorySchema {
+	lower := strings.ToLower(provider)
+	for _, s := range InventorySchemas() {
+		if s.Provider == lower {
+			schema := s
+			return &schema
+		}
+	}
+	return nil
+}
+
+// Resources of an inventory schema
+const (
+	SchemaResourceVM      = "vm"
+	SchemaResourceNetwork = "network"
+)
+
+// indexPattern matches the list indexes of a field path, e.g. the [0] of disks[0].capacity
+var indexPattern = regexp.MustCompile(`\[\d+\]`)
+
+// QueryFields returns the fields of a resource ("vm" or "network") that can be used in
+// TSL queries, without the value notes of the schema (e.g. "status" for "status (up,
+// down, ...)"). The VM fields include the fields computed by kubectl-mtv.
+func (s InventorySchema) QueryFields(resource string) []string {
+	groups := s.VMFields
+	if resource == SchemaResourceNetwork {
+		groups = s.NetworkFields
+	}
+
+	var fields []string
+	for _, g := range groups {
+		for _, f := range g.Fields {
+			if i := strings.Index(f, " ("); i > 0 {
+				f = f[:i]
+			}
+			fields = append(fields, f)
+		}
+	}
+	if resource != SchemaResourceNetwork {
+		for _, f := range computedVMFields {
+			fields = append(fields, f.Name)
+		}
+	}
+	return fields
+}
+
+// HasQueryField reports whether field is a query field of the resource. List indexes
+// match the [*] of the schema, so disks[0].capacity is a field like disks[*].capacity.
+func (s InventorySchema) HasQueryField(resource, field string) bool {
+	field = indexPattern.ReplaceAllString(field, "[*]")
+	for _, f := range s.QueryFields(resource) {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// SuggestQueryFields returns up to five query fields of the resource that look like
+// field: the same field in another case, or the fields containing it.
+func (s InventorySchema) SuggestQueryFields(resource, field string) []string {
+	lower := strings.ToLower(field)
+	var suggestions []string
+	for _, f := range s.QueryFields(resource) {
+		if strings.ToLower(f) == lower {
+			return []string{f}
+		}
+		if lower != "" && strings.Contains(strings.ToLower(f), lower) && len(suggestions) < 5 {
+			suggestions = append(suggestions, f)
+		}
+	}
+	return suggestions
+}
+
+// renderOperators renders the operators as an aligned text block.
+func renderOperators(sb *strings.Builder, groups []OperatorGroup) {
+	for _, g := range groups {
+		label := g.Group + ":"
+		described := false
+		for _, op := range g.Operators {
+			if op.Description != "" {
+				described = true
+			}
+		}
+		if !described {
+			syntaxes := make([]string, 0, len(g.Operators))
+			for _, op := range g.Operators {
+				syntaxes = append(syntaxes, op.Syntax)
+			}
+			// Symbols are separated by spaces, keywords by commas
+			sep := "  "
+			if strings.ContainsAny(strings.Join(syntaxes, ""), "abcdefghijklmnopqrstuvwxyz") {
+				sep = ", "
+			}
+			sb.WriteString("  " + padRight(label, 16) + strings.Join(syntaxes, sep) + "\n")
+			continue
+		}
+		for i, op := range g.Operators {
+			if i == 0 {
+				sb.WriteString("  " + padRight(label, 16))
+			} else {
+				sb.WriteString(strings.Repeat(" ", 18))
+			}
+			sb.WriteString(padRight(op.Syntax, 7) + op.Description + "\n")
+		}
+	}
+}
+
+// renderFieldGroups renders field groups with wrapped, indented field lists.
+func renderFieldGroups(sb *strings.Builder, groups []FieldGroup) {
+	const width = 78
+	indent := strings.Repeat(" ", 15)
+	for _, g := range groups {
+		line := "  " + padRight(g.Group+":", 13)
+		for i, f := range g.Fields {
+			item := f
+			if i < len(g.Fields)-1 {
+				item += ","
+			}
+			if i > 0 && len(line)+1+len(item) > width {
+				sb.WriteString(line + "\n")
+				line = indent + item
+				continue
+			}
+			if i > 0 {
+				line += " "
+			}
+			line += item
+		}
+		sb.WriteString(line + "\n")
+	}
+}
+
+// renderComputedFields renders computed fields as a name / description list.
+func renderComputedFields(sb *strings.Builder, fields []ComputedField) {
+	for _, f := range fields {
+		lines := strings.Split(f.Description, "\n")
+		sb.WriteString("  " + padRight(f.Name, 19) + lines[0] + "\n")
+		for _, l := range lines[1:] {
+			sb.WriteString(strings.Repeat(" ", 21) + l + "\n")
+		}
+	}
+}
+
+// renderProviderFields renders one field list (VM or network) of every provider.
+func renderProviderFields(sb *strings.Builder, fields func(InventorySchema) []FieldGroup) {
+	for i, s := range inventorySchemas {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		title := s.Title
+		if s.Note != "" {
+			title += " (" + s.Note + ")"
+		}
+		sb.WriteString(title + ":\n")
+		renderFieldGroups(sb, fields(s))
+	}
+}
+
+func padRight(s string, width int) string {
+	if len(s) >= width {
+		return s + " "
+	}
+	return s + strings.Repeat(" ", width-len(s))
+}
@@ -0,0 +1,68 @@
+package help
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestInventorySchemas(t *testing.T) {
+	schemas := InventorySchemas()
+	if len(schemas) == 0 {
+		t.Fatal("expected inventory schemas")
+	}
+	for _, s := range schemas {
+		if len(s.VMFields) == 0 || len(s.NetworkFields) == 0 {
+			t.Errorf("%s: expected VM and network fields", s.Provider)
+		}
+		if len(s.ComputedFields) == 0 || len(s.Operators) == 0 {
+			t.Errorf("%s: expected computed fields and operators", s.Provider)
+		}
+	}
+
+	if GetInventorySchema("OVirt") == nil {
+		t.Error("GetInventorySchema should be case-insensitive")
+	}
+	if GetInventorySchema("hyperv") != nil {
+		t.Error("expected nil for unknown provider")
+	}
+}
+
+func TestTSLTopicRenderedFromSchemas(t *testing.T) {
+	content := GetTopic("tsl").Content
+	for _, want := range []string{
+		"Comparison:     =  !=  <>  <  <=  >  >=",
+		"~=     regex match",
+		"  Identity:    name, id, uuid, path, parent.id, parent.kind",
+		"               disks[*].datastore.name, disks[*].file, disks[*].shared",
+		"Network Fields by Provider",
+		"  Config:      dataCenter, vlan, usages",
+		"  powerStateHuman    power state in a provider neutral vocabulary",
+		"Examples\n--------",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("TSL topic missing %q", want)
+		}
+	}
+}
+
+func TestQueryFields(t *testing.T) {
+	vsphere := GetInventorySchema("vsphere")
+	for _, field := range []string{"cpuCount", "len(disks)", "disks[0].capacity", "powerStateHuman"} {
+		if !vsphere.HasQueryField(SchemaResourceVM, field) {
+			t.Errorf("expected %q to be a vSphere VM field", field)
+		}
+	}
+	if !vsphere.HasQueryField(SchemaResourceNetwork, "variant") {
+		t.Error("expected the value note to be stripped from the network field variant")
+	}
+	if vsphere.HasQueryField(SchemaResourceNetwork, "powerStateHuman") {
+		t.Error("computed VM fields should not be network fields")
+	}
+
+	if got := vsphere.SuggestQueryFields(SchemaResourceVM, "cpucount"); len(got) != 1 || got[0] != "cpuCount" {
+		t.Errorf("SuggestQueryFields(cpucount) = %v, want [cpuCount]", got)
+	}
+	if got := vsphere.SuggestQueryFields(SchemaResourceVM, "datastore"); len(got) != 2 {
+		t.Errorf("SuggestQueryFields(datastore) = %v, want the two datastore fields", got)
+	}
+}
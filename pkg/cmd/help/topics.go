@@ -16,176 +16,9 @@ type Topic struct {
 // topicRegistry holds all registered help topics.
 var topicRegistry = []Topic{
 	{
-		Name:  "tsl",
-		Short: "Tree Search Language (TSL) query syntax reference",
-		Content: `Query Language (TSL) Syntax
-==========================
-
-TSL is used to filter inventory results with --query "where ..." and to select
-VMs for migration plans with --vms "where ...".
-
-Query Structure:
-  [SELECT fields] WHERE condition [ORDER BY field [ASC|DESC]] [LIMIT n]
-  For --vms flag: where <condition>
-
-Operators:
-  Comparison:     =  !=  <>  <  <=  >  >=
-  Arithmetic:     +  -  *  /  %
-  String match:   like (% wildcard), ilike (case-insensitive)
-                  ~= (regex match), ~! (regex not match)
-  Logical:        and, or, not
-  Set/range:      in ['a','b'], not in ['a','b'], between X and Y
-  Null checks:    is null, is not null
-
-Array and Aggregate Functions:
-  len(field)                    length of an array field
-  sum(field[*].sub)             sum of numeric values in an array
-  any(field[*].sub = 'value')   true if any element matches
-  all(field[*].sub >= N)        true if all elements match
-
-Array Access and SI Units:
-  field[0]               index access (zero-based)
-  field[*].sub           wildcard access across all elements
-  field.sub              implicit traversal (same as field[*].sub)
-  4Gi, 512Mi, 1Ti        SI unit suffixes (Ki, Mi, Gi, Ti, Pi)
-
-Field Access:
-  Dot notation for nested fields: parent.id, guest.distribution
-  To discover all available fields for your provider, run:
-    kubectl-mtv get inventory vm --provider <provider> --output json
-
-VM Fields by Provider
----------------------
-
-vSphere:
-  Identity:    name, id, uuid, path, parent.id, parent.kind
-  State:       powerState, connectionState
-  Compute:     cpuCount, coresPerSocket, memoryMB
-  Guest:       guestId, guestName, firmware, isTemplate
-  Network:     ipAddress, hostName, host
-  Storage:     storageUsed
-  Security:    secureBoot, tpmEnabled, changeTrackingEnabled
-  Disks:       len(disks), disks[*].capacity, disks[*].datastore.id,
-               disks[*].datastore.name, disks[*].file, disks[*].shared
-  NICs:        len(nics), nics[*].mac, nics[*].network.id
-  Networks:    len(networks), networks[*].id, networks[*].kind
-  Concerns:    len(concerns), concerns[*].category, concerns[*].assessment,
-               concerns[*].label
-
-oVirt / RHV:
-  Identity:    name, id, path, cluster, host
-  State:       status (up, down, ...)
-  Compute:     cpuSockets, cpuCores, cpuThreads, memory (bytes)
-  Guest:       osType, guestName, guest.distribution, guest.fullVersion
-  Config:      haEnabled, stateless, placementPolicyAffinity, display
-  Disks:       len(diskAttachments), diskAttachments[*].disk,
-               diskAttachments[*].interface
-  NICs:        len(nics), nics[*].name, nics[*].mac, nics[*].interface,
-               nics[*].ipAddress, nics[*].profile
-  Concerns:    len(concerns), concerns[*].category, concerns[*].assessment,
-               concerns[*].label
-
-OpenStack:
-  Identity:    name, id, status
-  Resources:   flavor.name, image.name, project.name
-  Volumes:     len(attachedVolumes), attachedVolumes[*].ID
-
-EC2 (PascalCase):
-  Identity:    name, InstanceType, State.Name, PlatformDetails
-  Placement:   Placement.AvailabilityZone
-  Network:     PublicIpAddress, PrivateIpAddress, VpcId, SubnetId
-  Snapshots:   State, VolumeId, VolumeSize, sizeHuman, Encrypted, Progress
-
-Computed Fields (added by kubectl-mtv, available for all providers):
-  criticalConcerns   count of critical migration concerns
-  warningConcerns    count of warning migration concerns
-  infoConcerns       count of informational migration concerns
-  concernsHuman      human-readable concern summary
-  memoryGB           memory in GB (converted from MB or bytes)
-  storageUsedGB      storage used in GB
-  diskCapacity       total disk capacity
-  powerStateHuman    human-readable power state
-  provider           provider name
-
-Examples
---------
-
-  Basic filtering:
-    where name ~= 'prod-.*'
-    where name like '%web%'
-    where name in ['vm-01','vm-02','vm-03']
-
-  By compute resources (vSphere):
-    where powerState = 'poweredOn' and memoryMB > 4096
-    where cpuCount > 4 and memoryMB > 8192
-    where memoryMB between 2048 and 16384
-
-  By compute resources (oVirt, memory in bytes):
-    where status = 'up' and memory > 4Gi
-
-  By guest OS:
-    where guestId ~= 'rhel.*'                               (vSphere)
-    where guest.distribution ~= 'Red Hat.*'                  (oVirt)
-
-  By firmware and security:
-    where firmware = 'efi'
-    where isTemplate = false and secureBoot = true
-
-  By disk and network configuration:
-    where len(disks) > 1
-    where len(disks) > 1 and cpuCount <= 8
-    where len(nics) >= 2
-    where any(disks[*].shared = true)
-
-  Using the in operator (square brackets required):
-    where guestId in ['rhel8_64Guest','rhel9_64Guest']
-    where firmware in ['efi','bios']
-    where guestId not in ['rhel8_64Guest','']
-
-  Array element matching with any() (parentheses required for strings):
-    where any(concerns[*].category = 'Critical')
-    where any(concerns[*].category = 'Warning')
-    where any(disks[*].datastore.id = 'datastore-12')
-
-  Deep field access (dot notation, index, wildcard):
-    where parent.kind = 'Folder'
-    where disks[0].capacity > 50Gi
-    where any(disks[*].datastore.id = 'datastore-17')
-    where concerns[0].category = 'Critical'
-
-  Aggregate functions (sum, all):
-    where sum(disks[*].capacity) > 100Gi
-    where all(disks[*].shared = false)
-
-  Null checks:
-    where ipAddress is null
-    where ipAddress is not null
-
-  Arithmetic expressions:
-    where memoryMB / 1024 > 8
-
-  Select with deep fields and functions:
-    select name, disks[0].capacity, parent.kind where len(disks) > 1 limit 5
-    select name, sum(disks[*].capacity) as totalDisk where len(disks) > 1 order by totalDisk desc limit 10
-
-  By migration concerns:
-    where criticalConcerns > 0
-    where len(concerns) = 0
-
-  By folder path:
-    where path ~= '/Production/.*'
-    where path like '/Datacenter/vm/Linux/%'
-
-  Sorting and limiting:
-    where memoryMB > 1024 order by memoryMB desc limit 10
-    where powerState = 'poweredOn' order by name limit 50
-
-  OpenStack:
-    where status = 'ACTIVE' and flavor.name = 'm1.large'
-
-  EC2:
-    where State.Name = 'running' and InstanceType = 'm5.xlarge'
-    where Placement.AvailabilityZone = 'us-east-1a'`,
+		Name:    "tsl",
+		Short:   "Tree Search Language (TSL) query syntax reference",
+		Content: tslContent(),
 	},
 	{
 		Name:  "karl",
@@ -378,6 +211,142 @@ Important Constraints
 	},
 }
 
+// tslContent builds the TSL topic, with the operators and fields rendered from the
+// inventory schemas.
+func tslContent() string {
+	var sb strings.Builder
+	sb.WriteString(tslIntro)
+	sb.WriteString("\nOperators:\n")
+	renderOperators(&sb, tslOperators)
+	sb.WriteString("\n" + tslSyntax)
+
+	sb.WriteString("\nVM Fields by Provider\n---------------------\n\n")
+	renderProviderFields(&sb, func(s InventorySchema) []FieldGroup { return s.VMFields })
+
+	sb.WriteString("\nComputed Fields (added by kubectl-mtv, available for all providers):\n")
+	renderComputedFields(&sb, computedVMFields)
+
+	sb.WriteString("\nNetwork Fields by Provider (get inventory network --query)\n")
+	sb.WriteString("----------------------------------------------------------\n\n")
+	renderProviderFields(&sb, func(s InventorySchema) []FieldGroup { return s.NetworkFields })
+
+	sb.WriteString("\n" + tslExamples)
+	return sb.String()
+}
+
+const tslIntro = `Query Language (TSL) Syntax
+==========================
+
+TSL is used to filter inventory results with --query "where ..." and to select
+VMs for migration plans with --vms "where ...".
+
+Query Structure:
+  [SELECT fields] WHERE condition [ORDER BY field [ASC|DESC]] [LIMIT n]
+  For --vms flag: where <condition>
+`
+
+const tslSyntax = `Array and Aggregate Functions:
+  len(field)                    length of an array field
+  sum(field[*].sub)             sum of numeric values in an array
+  any(field[*].sub = 'value')   true if any element matches
+  all(field[*].sub >= N)        true if all elements match
+
+Array Access and SI Units:
+  field[0]               index access (zero-based)
+  field[*].sub           wildcard access across all elements
+  field.sub              implicit traversal (same as field[*].sub)
+  4Gi, 512Mi, 1Ti        SI unit suffixes (Ki, Mi, Gi, Ti, Pi)
+
+Field Access:
+  Dot notation for nested fields: parent.id, guest.distribution
+  To discover all available fields for your provider, run:
+    kubectl-mtv get inventory vm --provider <provider> --output json
+`
+
+const tslExamples = `Examples
+--------
+
+  Basic filtering:
+    where name ~= 'prod-.*'
+    where name like '%web%'
+    where name in ['vm-01','vm-02','vm-03']
+
+  By compute resources (vSphere):
+    where powerState = 'poweredOn' and memoryMB > 4096
+    where cpuCount > 4 and memoryMB > 8192
+    where memoryMB between 2048 and 16384
+
+  By compute resources (oVirt, memory in bytes):
+    where status = 'up' and memory > 4Gi
+
+  By guest OS:
+    where guestId ~= 'rhel.*'                               (vSphere)
+    where guest.distribution ~= 'Red Hat.*'                  (oVirt)
+
+  By firmware and security:
+    where firmware = 'efi'
+    where isTemplate = false and secureBoot = true
+
+  By disk and network configuration:
+    where len(disks) > 1
+    where len(disks) > 1 and cpuCount <= 8
+    where len(nics) >= 2
+    where any(disks[*].shared = true)
+
+  Using the in operator (square brackets required):
+    where guestId in ['rhel8_64Guest','rhel9_64Guest']
+    where firmware in ['efi','bios']
+    where guestId not in ['rhel8_64Guest','']
+
+  Array element matching with any() (parentheses required for strings):
+    where any(concerns[*].category = 'Critical')
+    where any(concerns[*].category = 'Warning')
+    where any(disks[*].datastore.id = 'datastore-12')
+
+  Deep field access (dot notation, index, wildcard):
+    where parent.kind = 'Folder'
+    where disks[0].capacity > 50Gi
+    where any(disks[*].datastore.id = 'datastore-17')
+    where concerns[0].category = 'Critical'
+
+  Aggregate functions (sum, all):
+    where sum(disks[*].capacity) > 100Gi
+    where all(disks[*].shared = false)
+
+  Null checks:
+    where ipAddress is null
+    where ipAddress is not null
+
+  Arithmetic expressions:
+    where memoryMB / 1024 > 8
+
+  Select with deep fields and functions:
+    select name, disks[0].capacity, parent.kind where len(disks) > 1 limit 5
+    select name, sum(disks[*].capacity) as totalDisk where len(disks) > 1 order by totalDisk desc limit 10
+
+  By migration concerns:
+    where criticalConcerns > 0
+    where len(concerns) = 0
+
+  By passthrough devices (GPU/PCI, SR-IOV NICs, USB controllers):
+    where blockingDevices > 0
+    where any(passthroughDevices[*].kind = 'SR-IOV NIC')
+
+  By folder path:
+    where path ~= '/Production/.*'
+    where path like '/Datacenter/vm/Linux/%'
+
+  Sorting and limiting:
+    where memoryMB > 1024 order by memoryMB desc limit 10
+    where powerState = 'poweredOn' order by name limit 50
+
+  OpenStack:
+    where status = 'ACTIVE' and flavor.name = 'm1.large'
+
+  EC2:
+    where State.Name = 'running' and InstanceType = 'm5.xlarge'
+    where Placement.AvailabilityZone = 'us-east-1a'`
+
 // GetTopic returns a copy of the topic with the given name, or nil if not found.
 // The lookup is case-insensitive.
 func GetTopic(name string) *Topic {
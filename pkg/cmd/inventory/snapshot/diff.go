@@ -0,0 +1,395 @@
+package snapshot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/yaml"
+
+	plan "github.com/yaacov/kubectl-mtv/pkg/cmd/report/plan"
+	"github.com/yaacov/kubectl-mtv/pkg/util/output"
+)
+
+// Change types reported by Diff
+const (
+	ChangeAdded       = "Added"
+	ChangeRemoved     = "Removed"
+	ChangeRenamed     = "Renamed"
+	ChangeModified    = "Changed"
+	ChangeDiskAdded   = "DiskAdded"
+	ChangeDiskRemoved = "DiskRemoved"
+	ChangeDiskResized = "DiskResized"
+)
+
+// Change is a difference of one inventory object between two snapshots
+type Change struct {
+	Collection string `json:"collection"`
+	Name       string `json:"name"`
+	ID         string `json:"id"`
+	Change     string `json:"change"`
+	Details    string `json:"details,omitempty"`
+}
+
+// Diff lists the changes of a provider's inventory between two snapshots
+type Diff struct {
+	Provider     string         `json:"provider"`
+	ProviderType string         `json:"providerType"`
+	From         time.Time      `json:"from"`
+	To           time.Time      `json:"to"`
+	Summary      map[string]int `json:"summary"`
+	Changes      []Change       `json:"changes"`
+}
+
+// compareFields lists the fields compared for each collection, on top of the name. Fields
+// missing from both objects are ignored, so one list serves every provider type.
+var compareFields = map[string][]string{
+	"vms":            {"powerState", "cpuCount", "coresPerSocket", "cpuSockets", "cpuCores", "memoryMB", "memory", "guestId", "osType", "firmware", "path", "flavor", "networks", "nics"},
+	"networks":       {"vlanId", "variant"},
+	"datastores":     {"capacity", "type"},
+	"storagedomains": {"capacity", "type"},
+	"storages":       {"capacity"},
+	"disks":          {"provisionedSize", "capacity", "storageDomain"},
+	"volumes":        {"size", "volumeType"},
+	"hosts":          {"cluster", "productVersion", "inMaintenance"},
+	"clusters":       {"dataCenter"},
+	"flavors":        {"vcpus", "ram", "disk"},
+}
+
+// sizeFields are byte sizes, shown in human readable units
+var sizeFields = map[string]bool{"capacity": true, "provisionedSize": true}
+
+var diffColumns = []output.Column{
+	{Title: "COLLECTION", Key: "collection"},
+	{Title: "NAME", Key: "name"},
+	{Title: "ID", Key: "id", MaxWidth: 40},
+	{Title: "CHANGE", Key: "change", ColorFunc: colorChange},
+	{Title: "DETAILS", Key: "details"},
+}
+
+// PrintDiff compares two snapshot files, or a snapshot file with the live inventory of a
+// provider when newFile is empty, and prints the changes as table, markdown, json or yaml
+func PrintDiff(ctx context.Context, configFlags *genericclioptions.ConfigFlags, oldFile, newFile, providerName, namespace, inventoryURL string, insecureSkipTLS bool, outputFormat string) error {
+	from, err := Load(oldFile)
+	if err != nil {
+		return err
+	}
+
+	var to *Snapshot
+	if newFile != "" {
+		to, err = Load(newFile)
+	} else {
+		if providerName == "" {
+			providerName = from.Provider
+			namespace = from.Namespace
+		}
+		to, err = Take(ctx, configFlags, providerName, namespace, inventoryURL, insecureSkipTLS)
+	}
+	if err != nil {
+		return err
+	}
+	if from.ProviderType != to.ProviderType {
+		return fmt.Errorf("cannot compare a %s inventory with a %s inventory", from.ProviderType, to.ProviderType)
+	}
+
+	diff := Compare(from, to)
+	items := make([]map[string]interface{}, 0, len(diff.Changes))
+	for _, c := range diff.Changes {
+		items = append(items, map[string]interface{}{
+			"collection": c.Collection,
+			"name":       c.Name,
+			"id":         c.ID,
+			"change":     c.Change,
+			"details":    c.Details,
+		})
+	}
+
+	emptyMsg := fmt.Sprintf("No inventory changes between %s and %s", from.Created.Format(time.RFC3339), to.Created.Format(time.RFC3339))
+	switch strings.ToLower(outputFormat) {
+	case "json":
+		data, err := json.MarshalIndent(diff, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode inventory diff: %v", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	case "yaml":
+		data, err := yaml.Marshal(diff)
+		if err != nil {
+			return fmt.Errorf("failed to encode inventory diff: %v", err)
+		}
+		fmt.Print(string(data))
+		return nil
+	case "markdown":
+		return output.PrintMarkdownWithQuery(items, diffColumns, nil, emptyMsg)
+	case "", "table":
+		if err := output.PrintTableWithQuery(items, diffColumns, nil, emptyMsg); err != nil {
+			return err
+		}
+		if len(diff.Changes) > 0 {
+			fmt.Printf("\n%s\n", summaryLine(diff.Summary))
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported output format: %s. Supported formats: table, markdown, json, yaml", outputFormat)
+	}
+}
+
+// Compare returns the changes from one snapshot to another: objects added, removed and
+// renamed, changed fields, and for VMs the disks added, removed and resized
+func Compare(from, to *Snapshot) *Diff {
+	diff := &Diff{
+		Provider:     to.Provider,
+		ProviderType: to.ProviderType,
+		From:         from.Created,
+		To:           to.Created,
+		Summary:      map[string]int{},
+		Changes:      []Change{},
+	}
+
+	collections := map[string]bool{}
+	for collection := range from.Resources {
+		collections[collection] = true
+	}
+	for collection := range to.Resources {
+		collections[collection] = true
+	}
+	names := make([]string, 0, len(collections))
+	for collection := range collections {
+		names = append(names, collection)
+	}
+	sort.Strings(names)
+
+	for _, collection := range names {
+		diff.Changes = append(diff.Changes, compareCollection(collection, from.Resources[collection], to.Resources[collection])...)
+	}
+	for _, c := range diff.Changes {
+		diff.Summary[c.Change]++
+	}
+	return diff
+}
+
+// compareCollection compares the objects of one collection, matched by ID
+func compareCollection(collection string, from, to []map[string]interface{}) []Change {
+	before := indexObjects(from)
+	after := indexObjects(to)
+
+	changes := []Change{}
+	for _, key := range sortedKeys(before, after) {
+		old, inOld := before[key]
+		cur, inNew := after[key]
+		switch {
+		case !inOld:
+			changes = append(changes, Change{Collection: collection, Name: stringField(cur, "name"), ID: key, Change: ChangeAdded})
+		case !inNew:
+			changes = append(changes, Change{Collection: collection, Name: stringField(old, "name"), ID: key, Change: ChangeRemoved})
+		default:
+			changes = append(changes, compareObject(collection, key, old, cur)...)
+		}
+	}
+	return changes
+}
+
+// compareObject compares two versions of the same object
+func compareObject(collection, id string, old, cur map[string]interface{}) []Change {
+	name := stringField(cur, "name")
+	changes := []Change{}
+
+	if oldName := stringField(old, "name"); oldName != name {
+		changes = append(changes, Change{Collection: collection, Name: name, ID: id, Change: ChangeRenamed, Details: fmt.Sprintf("%s -> %s", oldName, name)})
+	}
+
+	details := []string{}
+	for _, field := range compareFields[collection] {
+		oldValue, newValue := fieldString(old, field), fieldString(cur, field)
+		if oldValue == newValue {
+			continue
+		}
+		if sizeFields[field] {
+			oldValue, newValue = sizeString(old[field]), sizeString(cur[field])
+		}
+		details = append(details, fmt.Sprintf("%s: %s -> %s", field, orNone(oldValue), orNone(newValue)))
+	}
+	if len(details) > 0 {
+		changes = append(changes, Change{Collection: collection, Name: name, ID: id, Change: ChangeModified, Details: strings.Join(details, "; ")})
+	}
+
+	if collection == "vms" {
+		changes = append(changes, compareVMDisks(id, name, old, cur)...)
+	}
+	return changes
+}
+
+// compareVMDisks compares the disks embedded in a VM, matched by file, ID or key
+func compareVMDisks(id, name string, old, cur map[string]interface{}) []Change {
+	before, after := vmDisks(old), vmDisks(cur)
+	changes := []Change{}
+	for _, disk := range sortedKeys(before, after) {
+		oldSize, inOld := before[disk]
+		newSize, inNew := after[disk]
+		switch {
+		case !inOld:
+			changes = append(changes, Change{Collection: "vms", Name: name, ID: id, Change: ChangeDiskAdded, Details: fmt.Sprintf("%s (%s)", disk, plan.FormatBytes(newSize))})
+		case !inNew:
+			changes = append(changes, Change{Collection: "vms", Name: name, ID: id, Change: ChangeDiskRemoved, Details: disk})
+		case oldSize != newSize:
+			changes = append(changes, Change{Collection: "vms", Name: name, ID: id, Change: ChangeDiskResized, Details: fmt.Sprintf("%s: %s -> %s", disk, plan.FormatBytes(oldSize), plan.FormatBytes(newSize))})
+		}
+	}
+	return changes
+}
+
+// vmDisks returns the capacity of the disks embedded in a VM, by disk file, ID or key
+func vmDisks(vm map[string]interface{}) map[string]int64 {
+	disks := map[string]int64{}
+	list, _ := vm["disks"].([]interface{})
+	for i, d := range list {
+		disk, ok := d.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		key := firstString(disk, "file", "id", "name")
+		if key == "" {
+			key = fmt.Sprintf("disk-%d", i)
+		}
+		for _, field := range []string{"capacity", "provisionedSize", "size"} {
+			if size, ok := toInt64(disk[field]); ok {
+				disks[key] = size
+				break
+			}
+		}
+		if _, ok := disks[key]; !ok {
+			disks[key] = 0
+		}
+	}
+	return disks
+}
+
+// indexObjects indexes inventory objects by ID, falling back to the name
+func indexObjects(objects []map[string]interface{}) map[string]map[string]interface{} {
+	index := map[string]map[string]interface{}{}
+	for _, object := range objects {
+		if key := firstString(object, "id", "name"); key != "" {
+			index[key] = object
+		}
+	}
+	return index
+}
+
+// sortedKeys returns the union of the keys of two maps, sorted
+func sortedKeys[T any](a, b map[string]T) []string {
+	seen := map[string]bool{}
+	keys := []string{}
+	for _, m := range []map[string]T{a, b} {
+		for key := range m {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// fieldString returns a field as comparable text; objects and lists are compared as JSON
+func fieldString(object map[string]interface{}, field string) string {
+	value, ok := object[field]
+	if !ok || value == nil {
+		return ""
+	}
+	switch v := value.(type) {
+	case string:
+		return v
+	case map[string]interface{}:
+		// References are compared by ID, e.g. a flavor or a storage domain
+		if id, ok := v["id"].(string); ok {
+			return id
+		}
+	}
+	if n, ok := toInt64(value); ok {
+		return fmt.Sprintf("%d", n)
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(data)
+}
+
+// sizeString formats a byte size field
+func sizeString(value interface{}) string {
+	if n, ok := toInt64(value); ok {
+		return plan.FormatBytes(n)
+	}
+	return ""
+}
+
+// toInt64 converts a JSON number to int64; snapshots loaded from files hold float64
+func toInt64(value interface{}) (int64, bool) {
+	switch v := value.(type) {
+	case float64:
+		if v == float64(int64(v)) {
+			return int64(v), true
+		}
+	case int64:
+		return v, true
+	case int:
+		return int64(v), true
+	case json.Number:
+		n, err := v.Int64()
+		return n, err == nil
+	}
+	return 0, false
+}
+
+// stringField returns a string field or ""
+func stringField(object map[string]interface{}, field string) string {
+	s, _ := object[field].(string)
+	return s
+}
+
+// firstString returns the first non empty string field
+func firstString(object map[string]interface{}, fields ...string) string {
+	for _, field := range fields {
+		if s := stringField(object, field); s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+// orNone shows empty values as "none"
+func orNone(s string) string {
+	if s == "" {
+		return "none"
+	}
+	return s
+}
+
+// summaryLine formats the change counts, e.g. "3 Added, 1 Removed"
+func summaryLine(summary map[string]int) string {
+	parts := []string{}
+	for _, change := range []string{ChangeAdded, ChangeRemoved, ChangeRenamed, ChangeModified, ChangeDiskAdded, ChangeDiskRemoved, ChangeDiskResized} {
+		if summary[change] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", summary[change], change))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// colorChange colors additions green, removals red and other changes yellow
+func colorChange(change string) string {
+	switch change {
+	case ChangeAdded, ChangeDiskAdded:
+		return output.Green(change)
+	case ChangeRemoved, ChangeDiskRemoved:
+		return output.Red(change)
+	default:
+		return output.Yellow(change)
+	}
+}
@@ -0,0 +1,162 @@
+package snapshot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
+
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/get/inventory"
+)
+
+// Kind identifies inventory snapshot files
+const Kind = "InventorySnapshot"
+
+// APIVersion is the version of the inventory snapshot file format
+const APIVersion = "kubectl-mtv/v1"
+
+// Snapshot is the full inventory of a provider at a point in time
+type Snapshot struct {
+	APIVersion   string `json:"apiVersion"`
+	Kind         string `json:"kind"`
+	Provider     string `json:"provider"`
+	Namespace    string `json:"namespace"`
+	ProviderType string `json:"providerType"`
+	// ProviderUID is the UID of the provider, used by 'dev fake-inventory' to serve the snapshot
+	ProviderUID string    `json:"providerUID,omitempty"`
+	Created     time.Time `json:"created"`
+	// Resources holds the inventory objects by collection, e.g. vms, networks, datastores
+	Resources map[string][]map[string]interface{} `json:"resources"`
+}
+
+// snapshotCollections lists the inventory collections saved for each provider type
+var snapshotCollections = map[string][]string{
+	"vsphere":   {"vms", "networks", "datastores", "hosts", "clusters"},
+	"ovirt":     {"vms", "networks", "storagedomains", "disks", "hosts", "clusters"},
+	"openstack": {"vms", "networks", "volumes", "volumetypes", "flavors"},
+	"ova":       {"vms", "networks", "storages", "disks"},
+	"openshift": {"vms", "networkattachmentdefinitions", "storageclasses"},
+	"ec2":       {"vms", "networks", "storages"},
+	"hyperv":    {"vms", "networks", "storages"},
+	"azure":     {"vms", "networks", "storages"},
+}
+
+// Take reads every inventory collection of a provider relevant to migrations
+func Take(ctx context.Context, configFlags *genericclioptions.ConfigFlags, providerName, namespace, inventoryURL string, insecureSkipTLS bool) (*Snapshot, error) {
+	provider, err := inventory.GetProviderByName(ctx, configFlags, providerName, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	providerClient := inventory.NewProviderClientWithInsecure(configFlags, provider, inventoryURL, insecureSkipTLS)
+	providerType, err := providerClient.GetProviderType()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get provider type: %v", err)
+	}
+	collections, ok := snapshotCollections[providerType]
+	if !ok {
+		return nil, fmt.Errorf("provider type '%s' does not support inventory snapshots", providerType)
+	}
+
+	snapshot := &Snapshot{
+		APIVersion:   APIVersion,
+		Kind:         Kind,
+		Provider:     providerName,
+		Namespace:    provider.GetNamespace(),
+		ProviderType: providerType,
+		ProviderUID:  string(provider.GetUID()),
+		Created:      time.Now().UTC().Truncate(time.Second),
+		Resources:    map[string][]map[string]interface{}{},
+	}
+	for _, collection := range collections {
+		klog.V(2).Infof("Reading inventory collection '%s' of provider '%s'", collection, providerName)
+		data, err := providerClient.GetResourceCollection(ctx, collection, 4)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch %s of provider '%s': %v", collection, providerName, err)
+		}
+		if providerType == "ec2" {
+			data = inventory.ExtractEC2Objects(data)
+		}
+		items, ok := data.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("unexpected data format: expected array for %s inventory", collection)
+		}
+		objects := make([]map[string]interface{}, 0, len(items))
+		for _, item := range items {
+			if object, ok := item.(map[string]interface{}); ok {
+				objects = append(objects, object)
+			}
+		}
+		snapshot.Resources[collection] = objects
+	}
+	return snapshot, nil
+}
+
+// Write takes a snapshot of a provider's inventory and writes it to file, or to stdout when
+// file is empty. Files ending in .yaml or .yml are written as YAML, others as JSON.
+func Write(ctx context.Context, configFlags *genericclioptions.ConfigFlags, providerName, namespace, inventoryURL string, insecureSkipTLS bool, file string) error {
+	snapshot, err := Take(ctx, configFlags, providerName, namespace, inventoryURL, insecureSkipTLS)
+	if err != nil {
+		return err
+	}
+
+	data, err := Encode(snapshot, file)
+	if err != nil {
+		return err
+	}
+	if file == "" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	if err := os.WriteFile(file, data, 0644); err != nil {
+		return fmt.Errorf("failed to write inventory snapshot '%s': %v", file, err)
+	}
+
+	counts := []string{}
+	for _, collection := range snapshotCollections[snapshot.ProviderType] {
+		counts = append(counts, fmt.Sprintf("%d %s", len(snapshot.Resources[collection]), collection))
+	}
+	fmt.Fprintf(os.Stderr, "Inventory of provider '%s' written to %s (%s)\n", providerName, file, strings.Join(counts, ", "))
+	return nil
+}
+
+// Encode serializes a snapshot as YAML for .yaml and .yml files, and as JSON otherwise
+func Encode(snapshot *Snapshot, file string) ([]byte, error) {
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".yaml", ".yml":
+		data, err := yaml.Marshal(snapshot)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode inventory snapshot: %v", err)
+		}
+		return data, nil
+	default:
+		data, err := json.MarshalIndent(snapshot, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode inventory snapshot: %v", err)
+		}
+		return append(data, '\n'), nil
+	}
+}
+
+// Load reads a snapshot written by Write, in JSON or YAML
+func Load(file string) (*Snapshot, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read inventory snapshot '%s': %v", file, err)
+	}
+	snapshot := &Snapshot{}
+	if err := yaml.Unmarshal(data, snapshot); err != nil {
+		return nil, fmt.Errorf("failed to parse inventory snapshot '%s': %v", file, err)
+	}
+	if snapshot.Kind != Kind {
+		return nil, fmt.Errorf("'%s' is not an inventory snapshot, expected kind %s", file, Kind)
+	}
+	return snapshot, nil
+}
@@ -0,0 +1,113 @@
+package snapshot
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testSnapshot(created time.Time, vms, networks []map[string]interface{}) *Snapshot {
+	return &Snapshot{
+		APIVersion:   APIVersion,
+		Kind:         Kind,
+		Provider:     "vsphere-prod",
+		Namespace:    "mtv",
+		ProviderType: "vsphere",
+		Created:      created,
+		Resources:    map[string][]map[string]interface{}{"vms": vms, "networks": networks},
+	}
+}
+
+func vsphereVM(id, name string, memoryMB int64, disks ...map[string]interface{}) map[string]interface{} {
+	list := []interface{}{}
+	for _, d := range disks {
+		list = append(list, d)
+	}
+	return map[string]interface{}{"id": id, "name": name, "memoryMB": memoryMB, "powerState": "poweredOn", "disks": list}
+}
+
+func TestCompare(t *testing.T) {
+	disk := func(file string, capacity int64) map[string]interface{} {
+		return map[string]interface{}{"file": file, "capacity": capacity}
+	}
+	const gib = int64(1024 * 1024 * 1024)
+
+	from := testSnapshot(time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC),
+		[]map[string]interface{}{
+			vsphereVM("vm-1", "web-01", 4096, disk("[ds1] web-01/web-01.vmdk", 10*gib)),
+			vsphereVM("vm-2", "db-01", 8192, disk("[ds1] db-01/db-01.vmdk", 50*gib), disk("[ds1] db-01/db-01_1.vmdk", 5*gib)),
+			vsphereVM("vm-3", "old-app", 2048),
+		},
+		[]map[string]interface{}{{"id": "net-1", "name": "VM Network", "vlanId": "10"}},
+	)
+	to := testSnapshot(time.Date(2026, 2, 5, 0, 0, 0, 0, time.UTC),
+		[]map[string]interface{}{
+			vsphereVM("vm-1", "web-01", 4096, disk("[ds1] web-01/web-01.vmdk", 10*gib)),
+			vsphereVM("vm-2", "db-01-renamed", 16384, disk("[ds1] db-01/db-01.vmdk", 100*gib), disk("[ds2] db-01/db-01_2.vmdk", gib)),
+			vsphereVM("vm-4", "new-app", 2048),
+		},
+		[]map[string]interface{}{{"id": "net-1", "name": "Production", "vlanId": "10"}},
+	)
+
+	diff := Compare(from, to)
+	got := []string{}
+	for _, c := range diff.Changes {
+		got = append(got, c.Collection+"/"+c.ID+" "+c.Change+" "+c.Details)
+	}
+	want := []string{
+		"networks/net-1 Renamed VM Network -> Production",
+		"vms/vm-2 Renamed db-01 -> db-01-renamed",
+		"vms/vm-2 Changed memoryMB: 8192 -> 16384",
+		"vms/vm-2 DiskResized [ds1] db-01/db-01.vmdk: 50.0 GiB -> 100.0 GiB",
+		"vms/vm-2 DiskRemoved [ds1] db-01/db-01_1.vmdk",
+		"vms/vm-2 DiskAdded [ds2] db-01/db-01_2.vmdk (1.0 GiB)",
+		"vms/vm-3 Removed ",
+		"vms/vm-4 Added ",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Compare() changes:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if diff.Summary[ChangeRenamed] != 2 || diff.Summary[ChangeAdded] != 1 {
+		t.Errorf("unexpected summary %v", diff.Summary)
+	}
+}
+
+func TestEncodeLoad(t *testing.T) {
+	original := testSnapshot(time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC),
+		[]map[string]interface{}{vsphereVM("vm-1", "web-01", 4096, map[string]interface{}{"file": "a.vmdk", "capacity": int64(1024)})},
+		nil,
+	)
+
+	dir := t.TempDir()
+	for _, name := range []string{"inventory.json", "inventory.yaml"} {
+		file := filepath.Join(dir, name)
+		data, err := Encode(original, file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(file, data, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		loaded, err := Load(file)
+		if err != nil {
+			t.Fatalf("Load(%s): %v", name, err)
+		}
+		if !loaded.Created.Equal(original.Created) || loaded.Provider != "vsphere-prod" {
+			t.Errorf("Load(%s) = %+v", name, loaded)
+		}
+		// Numbers read back from files compare equal to the live inventory values
+		if diff := Compare(original, loaded); len(diff.Changes) != 0 {
+			t.Errorf("Load(%s) round trip changes: %v", name, diff.Changes)
+		}
+	}
+
+	other := filepath.Join(dir, "other.json")
+	if err := os.WriteFile(other, []byte(`{"kind":"Plan"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(other); err == nil {
+		t.Error("expected an error loading a file that is not a snapshot")
+	}
+}
@@ -156,7 +156,7 @@ func PatchHook(opts PatchHookOptions) error {
 		return fmt.Errorf("failed to patch hook '%s': %v", opts.Name, err)
 	}
 
-	fmt.Printf("hook/%s patched\n", opts.Name)
+	fmt.Printf("hook/%s patched%s\n", opts.Name, client.DryRunSuffix())
 	return nil
 }
 
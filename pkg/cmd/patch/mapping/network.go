@@ -3,6 +3,8 @@ package mapping
 import (
 	"context"
 	"fmt"
+	"os"
+	"strings"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -16,7 +18,7 @@ import (
 )
 
 // patchNetworkMapping patches an existing network mapping
-func patchNetworkMapping(configFlags *genericclioptions.ConfigFlags, name, namespace, addPairs, updatePairs, removePairs, inventoryURL string) error {
+func patchNetworkMapping(configFlags *genericclioptions.ConfigFlags, name, namespace, addPairs, updatePairs, removePairs, inventoryURL string, inventoryInsecureSkipTLS bool) error {
 	klog.V(2).Infof("Patching network mapping '%s' in namespace '%s'", name, namespace)
 
 	dynamicClient, err := client.GetDynamicClient(configFlags)
@@ -30,10 +32,50 @@ func patchNetworkMapping(configFlags *genericclioptions.ConfigFlags, name, names
 		return fmt.Errorf("failed to get network mapping '%s': %v", name, err)
 	}
 
+	workingPairs, err := patchedNetworkPairs(context.TODO(), configFlags, existingMapping, addPairs, updatePairs, removePairs, inventoryURL, inventoryInsecureSkipTLS)
+	if err != nil {
+		return err
+	}
+
+	// Patch the spec.map field (workingPairs is already unstructured)
+	patchData := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"map": workingPairs,
+		},
+	}
+
+	patchBytes, err := runtime.Encode(unstructured.UnstructuredJSONScheme, &unstructured.Unstructured{Object: patchData})
+	if err != nil {
+		return fmt.Errorf("failed to encode patch data: %v", err)
+	}
+
+	// Apply the patch
+	_, err = dynamicClient.Resource(client.NetworkMapGVR).Namespace(namespace).Patch(
+		context.TODO(),
+		name,
+		types.MergePatchType,
+		patchBytes,
+		metav1.PatchOptions{},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to patch network mapping: %v", err)
+	}
+
+	fmt.Printf("networkmap/%s patched%s\n", name, client.DryRunSuffix())
+	return nil
+}
+
+// parseNetworkPairs resolves network pairs against the source provider inventory; a
+// variable so tests can check the inventory settings without a cluster
+var parseNetworkPairs = mapping.ParseNetworkPairsWithInsecure
+
+// patchedNetworkPairs returns the pairs of a network mapping after the removals, additions
+// and updates, with the destinations of the changed pairs validated
+func patchedNetworkPairs(ctx context.Context, configFlags *genericclioptions.ConfigFlags, existingMapping *unstructured.Unstructured, addPairs, updatePairs, removePairs, inventoryURL string, inventoryInsecureSkipTLS bool) ([]interface{}, error) {
 	// Extract source provider for pair resolution
 	sourceProviderName, sourceProviderNamespace, err := getSourceProviderFromMapping(existingMapping)
 	if err != nil {
-		return fmt.Errorf("failed to get source provider from mapping: %v", err)
+		return nil, fmt.Errorf("failed to get source provider from mapping: %v", err)
 	}
 
 	if sourceProviderNamespace != "" {
@@ -46,7 +88,7 @@ func patchNetworkMapping(configFlags *genericclioptions.ConfigFlags, name, names
 	// Work with unstructured data throughout to avoid reflection issues
 	currentPairs, found, err := unstructured.NestedSlice(existingMapping.Object, "spec", "map")
 	if err != nil {
-		return fmt.Errorf("failed to extract existing mapping pairs: %v", err)
+		return nil, fmt.Errorf("failed to extract existing mapping pairs: %v", err)
 	}
 	if !found {
 		currentPairs = []interface{}{}
@@ -60,17 +102,24 @@ func patchNetworkMapping(configFlags *genericclioptions.ConfigFlags, name, names
 	// Process removals first
 	if removePairs != "" {
 		sourcesToRemove := parseSourcesToRemove(removePairs)
+		// Removing a source that is not mapped is a no-op, as it always was
+		if missing := missingSources(workingPairs, sourcesToRemove); len(missing) > 0 {
+			fmt.Fprintf(os.Stderr, "Warning: sources not in the mapping, nothing to remove: %s\n", strings.Join(missing, ", "))
+		}
 		klog.V(2).Infof("Removing %d network pairs from mapping", len(sourcesToRemove))
 		workingPairs = removeSourceFromUnstructuredPairs(workingPairs, sourcesToRemove)
-		klog.V(2).Infof("Successfully removed network pairs from mapping '%s'", name)
+		klog.V(2).Infof("Successfully removed network pairs from mapping '%s'", existingMapping.GetName())
 	}
 
+	// Added and updated pairs, their destinations are validated before patching
+	var changedPairs []interface{}
+
 	// Process additions
 	if addPairs != "" {
 		klog.V(2).Infof("Adding network pairs to mapping: %s", addPairs)
-		newPairs, err := mapping.ParseNetworkPairs(addPairs, sourceProviderNamespace, configFlags, sourceProviderName, inventoryURL)
+		newPairs, err := parseNetworkPairs(addPairs, sourceProviderNamespace, configFlags, sourceProviderName, inventoryURL, inventoryInsecureSkipTLS)
 		if err != nil {
-			return fmt.Errorf("failed to parse add-pairs: %v", err)
+			return nil, fmt.Errorf("failed to parse add-pairs: %v", err)
 		}
 
 		// Convert new pairs to unstructured format
@@ -85,8 +134,9 @@ func patchNetworkMapping(configFlags *genericclioptions.ConfigFlags, name, names
 		}
 
 		if len(newUnstructuredPairs) > 0 {
+			changedPairs = append(changedPairs, newUnstructuredPairs...)
 			workingPairs = append(workingPairs, newUnstructuredPairs...)
-			klog.V(2).Infof("Added %d network pairs to mapping '%s'", len(newUnstructuredPairs), name)
+			klog.V(2).Infof("Added %d network pairs to mapping '%s'", len(newUnstructuredPairs), existingMapping.GetName())
 		} else {
 			klog.V(2).Infof("No new network pairs to add after filtering duplicates")
 		}
@@ -95,9 +145,9 @@ func patchNetworkMapping(configFlags *genericclioptions.ConfigFlags, name, names
 	// Process updates
 	if updatePairs != "" {
 		klog.V(2).Infof("Updating network pairs in mapping: %s", updatePairs)
-		updatePairsList, err := mapping.ParseNetworkPairs(updatePairs, sourceProviderNamespace, configFlags, sourceProviderName, inventoryURL)
+		updatePairsList, err := parseNetworkPairs(updatePairs, sourceProviderNamespace, configFlags, sourceProviderName, inventoryURL, inventoryInsecureSkipTLS)
 		if err != nil {
-			return fmt.Errorf("failed to parse update-pairs: %v", err)
+			return nil, fmt.Errorf("failed to parse update-pairs: %v", err)
 		}
 
 		// Convert update pairs to unstructured format
@@ -111,38 +161,22 @@ func patchNetworkMapping(configFlags *genericclioptions.ConfigFlags, name, names
 			updateUnstructuredPairs = append(updateUnstructuredPairs, pairMap)
 		}
 
+		if missing := missingPairSources(workingPairs, updateUnstructuredPairs); len(missing) > 0 {
+			return nil, fmt.Errorf("cannot update pairs, sources not in mapping: %s (add them with --add-pair)", strings.Join(missing, ", "))
+		}
+		changedPairs = append(changedPairs, updateUnstructuredPairs...)
 		workingPairs = updateUnstructuredPairsBySource(workingPairs, updateUnstructuredPairs)
-		klog.V(2).Infof("Updated %d network pairs in mapping '%s'", len(updateUnstructuredPairs), name)
+		klog.V(2).Infof("Updated %d network pairs in mapping '%s'", len(updateUnstructuredPairs), existingMapping.GetName())
 	}
 
 	klog.V(3).Infof("Final working pairs count: %d", len(workingPairs))
 
-	// Patch the spec.map field (workingPairs is already unstructured)
-	patchData := map[string]interface{}{
-		"spec": map[string]interface{}{
-			"map": workingPairs,
-		},
-	}
-
-	patchBytes, err := runtime.Encode(unstructured.UnstructuredJSONScheme, &unstructured.Unstructured{Object: patchData})
-	if err != nil {
-		return fmt.Errorf("failed to encode patch data: %v", err)
-	}
-
-	// Apply the patch
-	_, err = dynamicClient.Resource(client.NetworkMapGVR).Namespace(namespace).Patch(
-		context.TODO(),
-		name,
-		types.MergePatchType,
-		patchBytes,
-		metav1.PatchOptions{},
-	)
-	if err != nil {
-		return fmt.Errorf("failed to patch network mapping: %v", err)
+	// The destinations must exist on the target provider
+	if err := validateDestinations(ctx, configFlags, existingMapping, "network", changedPairs, inventoryURL, inventoryInsecureSkipTLS); err != nil {
+		return nil, err
 	}
 
-	fmt.Printf("networkmap/%s patched\n", name)
-	return nil
+	return workingPairs, nil
 }
 
 // removeSourceFromUnstructuredPairs removes pairs with matching source names/IDs from unstructured pairs
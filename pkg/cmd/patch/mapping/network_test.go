@@ -0,0 +1,63 @@
+package mapping
+
+import (
+	"context"
+	"testing"
+
+	forkliftv1beta1 "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+func testNetworkMapping() *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "net-map", "namespace": "demo"},
+		"spec": map[string]interface{}{
+			"provider": map[string]interface{}{
+				"source":      map[string]interface{}{"name": "vsphere-prod", "namespace": "demo"},
+				"destination": map[string]interface{}{"name": "host", "namespace": "demo"},
+			},
+			"map": []interface{}{testPair("VM Network", "network-1", map[string]interface{}{"type": "pod"})},
+		},
+	}}
+}
+
+func TestPatchedNetworkPairs_InsecureSkipTLS(t *testing.T) {
+	var parseInsecure, fetchInsecure bool
+	origParse, origFetch := parseNetworkPairs, destinationInventory
+	defer func() { parseNetworkPairs, destinationInventory = origParse, origFetch }()
+
+	parseNetworkPairs = func(pairStr, defaultNamespace string, configFlags *genericclioptions.ConfigFlags, sourceProvider, inventoryURL string, insecureSkipTLS bool) ([]forkliftv1beta1.NetworkPair, error) {
+		parseInsecure = insecureSkipTLS
+		pair := forkliftv1beta1.NetworkPair{}
+		pair.Source.Name = "DMZ"
+		pair.Destination.Type = "multus"
+		pair.Destination.Namespace, pair.Destination.Name = "dmz-ns", "dmz-net"
+		return []forkliftv1beta1.NetworkPair{pair}, nil
+	}
+	destinationInventory = func(ctx context.Context, configFlags *genericclioptions.ConfigFlags, providerName, providerNamespace, resource, inventoryURL string, insecureSkipTLS bool) (interface{}, error) {
+		fetchInsecure = insecureSkipTLS
+		return []interface{}{map[string]interface{}{"namespace": "dmz-ns", "name": "dmz-net"}}, nil
+	}
+
+	pairs, err := patchedNetworkPairs(context.Background(), nil, testNetworkMapping(), "DMZ:dmz-ns/dmz-net", "", "", "https://inventory", true)
+	if err != nil {
+		t.Fatalf("patchedNetworkPairs() unexpected error: %v", err)
+	}
+	if len(pairs) != 2 {
+		t.Errorf("patchedNetworkPairs() = %d pairs, want 2", len(pairs))
+	}
+	if !parseInsecure || !fetchInsecure {
+		t.Errorf("insecure skip TLS not passed on: source resolution %v, destination validation %v", parseInsecure, fetchInsecure)
+	}
+}
+
+func TestPatchedNetworkPairs_RemoveUnknownSource(t *testing.T) {
+	pairs, err := patchedNetworkPairs(context.Background(), nil, testNetworkMapping(), "", "", "VM Network,not-mapped", "", false)
+	if err != nil {
+		t.Fatalf("patchedNetworkPairs() unexpected error for an unknown source: %v", err)
+	}
+	if len(pairs) != 0 {
+		t.Errorf("patchedNetworkPairs() = %v, want the mapped source removed", pairs)
+	}
+}
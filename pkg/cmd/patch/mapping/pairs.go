@@ -0,0 +1,217 @@
+package mapping
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/klog/v2"
+
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/get/inventory"
+	"github.com/yaacov/kubectl-mtv/pkg/util/client"
+)
+
+// MergePairFlags appends pairs given as 'source=destination' (the --add-pair and
+// --update-pair flags) to a comma-separated 'source:destination' pair list (the
+// --add-pairs and --update-pairs flags)
+func MergePairFlags(pairs string, values []string) (string, error) {
+	list := []string{}
+	if strings.TrimSpace(pairs) != "" {
+		list = append(list, pairs)
+	}
+	for _, v := range values {
+		parts := strings.SplitN(v, "=", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" || strings.TrimSpace(parts[1]) == "" {
+			return "", fmt.Errorf("invalid pair '%s': expected 'source=destination'", v)
+		}
+		if strings.Contains(v, ",") {
+			return "", fmt.Errorf("invalid pair '%s': repeat the flag for each pair instead of using commas", v)
+		}
+		list = append(list, strings.TrimSpace(parts[0])+":"+strings.TrimSpace(parts[1]))
+	}
+	return strings.Join(list, ","), nil
+}
+
+// MergeSourceFlags appends sources (the --remove-pair flag) to a comma-separated source
+// list (the --remove-pairs flag)
+func MergeSourceFlags(sources string, values []string) string {
+	list := []string{}
+	if strings.TrimSpace(sources) != "" {
+		list = append(list, sources)
+	}
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
+		}
+	}
+	return strings.Join(list, ",")
+}
+
+// pairSource returns the source name and ID of an unstructured mapping pair
+func pairSource(pair interface{}) (string, string) {
+	pairMap, ok := pair.(map[string]interface{})
+	if !ok {
+		return "", ""
+	}
+	sourceMap, ok := pairMap["source"].(map[string]interface{})
+	if !ok {
+		return "", ""
+	}
+	name, _ := sourceMap["name"].(string)
+	id, _ := sourceMap["id"].(string)
+	return name, id
+}
+
+// mappedSources returns the source names and IDs of the pairs
+func mappedSources(pairs []interface{}) map[string]bool {
+	mapped := make(map[string]bool)
+	for _, pair := range pairs {
+		name, id := pairSource(pair)
+		if name != "" {
+			mapped[name] = true
+		}
+		if id != "" {
+			mapped[id] = true
+		}
+	}
+	return mapped
+}
+
+// missingSources returns the sources (names or IDs) that no pair maps
+func missingSources(pairs []interface{}, sources []string) []string {
+	mapped := mappedSources(pairs)
+	var missing []string
+	for _, source := range sources {
+		if !mapped[source] {
+			missing = append(missing, source)
+		}
+	}
+	return missing
+}
+
+// missingPairSources returns the sources of newPairs that no pair of pairs maps
+func missingPairSources(pairs []interface{}, newPairs []interface{}) []string {
+	mapped := mappedSources(pairs)
+	var missing []string
+	for _, pair := range newPairs {
+		name, id := pairSource(pair)
+		if (name != "" && mapped[name]) || (id != "" && mapped[id]) {
+			continue
+		}
+		if name == "" {
+			name = id
+		}
+		missing = append(missing, name)
+	}
+	return missing
+}
+
+// destinationKeys returns the target cluster resources the pairs map to: the
+// namespace/name of the NADs of multus network pairs, or the storage classes of
+// storage pairs
+func destinationKeys(kind string, pairs []interface{}) []string {
+	var keys []string
+	for _, pair := range pairs {
+		pairMap, ok := pair.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		destination, ok := pairMap["destination"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		switch kind {
+		case "network":
+			if t, _ := destination["type"].(string); t != "multus" {
+				continue
+			}
+			namespace, _ := destination["namespace"].(string)
+			name, _ := destination["name"].(string)
+			keys = append(keys, namespace+"/"+name)
+		case "storage":
+			if storageClass, _ := destination["storageClass"].(string); storageClass != "" {
+				keys = append(keys, storageClass)
+			}
+		}
+	}
+	return keys
+}
+
+// inventoryKeys returns the keys of target inventory items, matching destinationKeys
+func inventoryKeys(kind string, items []interface{}) map[string]bool {
+	keys := make(map[string]bool)
+	for _, item := range items {
+		itemMap, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := itemMap["name"].(string)
+		if kind == "network" {
+			namespace, _ := itemMap["namespace"].(string)
+			name = namespace + "/" + name
+		}
+		keys[name] = true
+	}
+	return keys
+}
+
+// destinationInventory fetches an inventory collection of the target provider of a mapping;
+// a variable so tests can check the inventory settings without a cluster
+var destinationInventory = func(ctx context.Context, configFlags *genericclioptions.ConfigFlags, providerName, providerNamespace, resource, inventoryURL string, insecureSkipTLS bool) (interface{}, error) {
+	provider, err := inventory.GetProviderByName(ctx, configFlags, providerName, providerNamespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get target provider: %v", err)
+	}
+	data, err := client.FetchProviderInventoryWithInsecure(ctx, configFlags, inventoryURL, provider, resource+"?detail=4", insecureSkipTLS)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch target %s inventory: %v", resource, err)
+	}
+	return data, nil
+}
+
+// validateDestinations checks that the destinations of the pairs exist in the inventory
+// of the target provider of the mapping
+func validateDestinations(ctx context.Context, configFlags *genericclioptions.ConfigFlags, mappingObj *unstructured.Unstructured, kind string, pairs []interface{}, inventoryURL string, insecureSkipTLS bool) error {
+	keys := destinationKeys(kind, pairs)
+	if len(keys) == 0 {
+		return nil
+	}
+
+	providerName, _, _ := unstructured.NestedString(mappingObj.Object, "spec", "provider", "destination", "name")
+	providerNamespace, _, _ := unstructured.NestedString(mappingObj.Object, "spec", "provider", "destination", "namespace")
+	if providerName == "" {
+		return fmt.Errorf("target provider not found in mapping")
+	}
+	if providerNamespace == "" {
+		providerNamespace = mappingObj.GetNamespace()
+	}
+
+	resource, description := "networkattachmentdefinitions", "network attachment definition"
+	if kind == "storage" {
+		resource, description = "storageclasses", "storage class"
+	}
+	data, err := destinationInventory(ctx, configFlags, providerName, providerNamespace, resource, inventoryURL, insecureSkipTLS)
+	if err != nil {
+		return err
+	}
+	items, ok := data.([]interface{})
+	if !ok {
+		return fmt.Errorf("unexpected data format: expected array for target %s inventory", resource)
+	}
+
+	available := inventoryKeys(kind, items)
+	var missing []string
+	for _, key := range keys {
+		if !available[key] {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%s not found on target provider '%s': %s", description, providerName, strings.Join(missing, ", "))
+	}
+
+	klog.V(2).Infof("Validated %d destinations on target provider '%s'", len(keys), providerName)
+	return nil
+}
@@ -0,0 +1,77 @@
+package mapping
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMergePairFlags(t *testing.T) {
+	got, err := MergePairFlags("VM Network:default", []string{"DMZ = dmz-ns/dmz-net", "ds1=premium;volumeMode=Block"})
+	if err != nil {
+		t.Fatalf("MergePairFlags() unexpected error: %v", err)
+	}
+	if want := "VM Network:default,DMZ:dmz-ns/dmz-net,ds1:premium;volumeMode=Block"; got != want {
+		t.Errorf("MergePairFlags() = %q, want %q", got, want)
+	}
+
+	for _, bad := range []string{"no-destination", "=dst", "src=", "a=b,c=d"} {
+		if _, err := MergePairFlags("", []string{bad}); err == nil {
+			t.Errorf("MergePairFlags(%q) expected an error", bad)
+		}
+	}
+
+	if got := MergeSourceFlags("a,b", []string{" c ", ""}); got != "a,b,c" {
+		t.Errorf("MergeSourceFlags() = %q, want a,b,c", got)
+	}
+}
+
+func testPair(name, id string, destination map[string]interface{}) interface{} {
+	return map[string]interface{}{
+		"source":      map[string]interface{}{"name": name, "id": id},
+		"destination": destination,
+	}
+}
+
+func TestMissingSources(t *testing.T) {
+	pairs := []interface{}{
+		testPair("VM Network", "network-1", nil),
+		testPair("", "network-2", nil),
+	}
+
+	if got := missingSources(pairs, []string{"VM Network", "network-2", "DMZ"}); !reflect.DeepEqual(got, []string{"DMZ"}) {
+		t.Errorf("missingSources() = %v, want [DMZ]", got)
+	}
+
+	updates := []interface{}{
+		testPair("VM Network", "network-1", nil),
+		testPair("DMZ", "network-3", nil),
+	}
+	if got := missingPairSources(pairs, updates); !reflect.DeepEqual(got, []string{"DMZ"}) {
+		t.Errorf("missingPairSources() = %v, want [DMZ]", got)
+	}
+}
+
+func TestDestinationKeys(t *testing.T) {
+	network := []interface{}{
+		testPair("a", "1", map[string]interface{}{"type": "multus", "namespace": "prod", "name": "net"}),
+		testPair("b", "2", map[string]interface{}{"type": "pod"}),
+		testPair("c", "3", map[string]interface{}{"type": "ignored"}),
+	}
+	if got := destinationKeys("network", network); !reflect.DeepEqual(got, []string{"prod/net"}) {
+		t.Errorf("destinationKeys(network) = %v, want only the NAD", got)
+	}
+
+	storage := []interface{}{testPair("ds1", "1", map[string]interface{}{"storageClass": "premium"})}
+	if got := destinationKeys("storage", storage); !reflect.DeepEqual(got, []string{"premium"}) {
+		t.Errorf("destinationKeys(storage) = %v, want [premium]", got)
+	}
+
+	nads := inventoryKeys("network", []interface{}{map[string]interface{}{"namespace": "prod", "name": "net"}})
+	if !nads["prod/net"] {
+		t.Errorf("inventoryKeys(network) = %v, want prod/net", nads)
+	}
+	classes := inventoryKeys("storage", []interface{}{map[string]interface{}{"name": "premium"}})
+	if !classes["premium"] {
+		t.Errorf("inventoryKeys(storage) = %v, want premium", classes)
+	}
+}
@@ -8,9 +8,14 @@ import (
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 )
 
-// PatchNetwork patches a network mapping
+// PatchNetwork patches a network mapping (wrapper for backward compatibility)
 func PatchNetwork(configFlags *genericclioptions.ConfigFlags, name, namespace, addPairs, updatePairs, removePairs, inventoryURL string) error {
-	return patchNetworkMapping(configFlags, name, namespace, addPairs, updatePairs, removePairs, inventoryURL)
+	return PatchNetworkWithInsecure(configFlags, name, namespace, addPairs, updatePairs, removePairs, inventoryURL, false)
+}
+
+// PatchNetworkWithInsecure patches a network mapping with optional insecure TLS skip verification
+func PatchNetworkWithInsecure(configFlags *genericclioptions.ConfigFlags, name, namespace, addPairs, updatePairs, removePairs, inventoryURL string, inventoryInsecureSkipTLS bool) error {
+	return patchNetworkMapping(configFlags, name, namespace, addPairs, updatePairs, removePairs, inventoryURL, inventoryInsecureSkipTLS)
 }
 
 // PatchStorage patches a storage mapping (wrapper for backward compatibility)
@@ -3,6 +3,7 @@ package mapping
 import (
 	"context"
 	"fmt"
+	"os"
 	"strings"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -293,11 +294,18 @@ func patchStorageMappingWithOptions(configFlags *genericclioptions.ConfigFlags,
 	// Process removals first
 	if removePairs != "" {
 		sourcesToRemove := parseSourcesToRemove(removePairs)
+		// Removing a source that is not mapped is a no-op, as it always was
+		if missing := missingSources(workingPairs, sourcesToRemove); len(missing) > 0 {
+			fmt.Fprintf(os.Stderr, "Warning: sources not in the mapping, nothing to remove: %s\n", strings.Join(missing, ", "))
+		}
 		klog.V(2).Infof("Removing %d storage pairs from mapping", len(sourcesToRemove))
 		workingPairs = removeSourceFromUnstructuredStoragePairs(workingPairs, sourcesToRemove)
 		klog.V(2).Infof("Successfully removed storage pairs from mapping '%s'", name)
 	}
 
+	// Added and updated pairs, their destinations are validated before patching
+	var changedPairs []interface{}
+
 	// Process additions
 	if addPairs != "" {
 		klog.V(2).Infof("Adding storage pairs to mapping: %s", addPairs)
@@ -339,6 +347,7 @@ func patchStorageMappingWithOptions(configFlags *genericclioptions.ConfigFlags,
 		}
 
 		if len(newUnstructuredPairs) > 0 {
+			changedPairs = append(changedPairs, newUnstructuredPairs...)
 			workingPairs = append(workingPairs, newUnstructuredPairs...)
 			klog.V(2).Infof("Added %d storage pairs to mapping '%s'", len(newUnstructuredPairs), name)
 		} else {
@@ -378,12 +387,21 @@ func patchStorageMappingWithOptions(configFlags *genericclioptions.ConfigFlags,
 			updateUnstructuredPairs = append(updateUnstructuredPairs, pairMap)
 		}
 
+		if missing := missingPairSources(workingPairs, updateUnstructuredPairs); len(missing) > 0 {
+			return fmt.Errorf("cannot update pairs, sources not in mapping: %s (add them with --add-pair)", strings.Join(missing, ", "))
+		}
+		changedPairs = append(changedPairs, updateUnstructuredPairs...)
 		workingPairs = updateUnstructuredStoragePairsBySource(workingPairs, updateUnstructuredPairs)
 		klog.V(2).Infof("Updated %d storage pairs in mapping '%s'", len(updateUnstructuredPairs), name)
 	}
 
 	klog.V(3).Infof("Final working pairs count: %d", len(workingPairs))
 
+	// The destinations must exist on the target provider
+	if err := validateDestinations(context.TODO(), configFlags, existingMapping, "storage", changedPairs, inventoryURL, inventoryInsecureSkipTLS); err != nil {
+		return err
+	}
+
 	// Patch the spec.map field (workingPairs is already unstructured)
 	patchData := map[string]interface{}{
 		"spec": map[string]interface{}{
@@ -408,6 +426,6 @@ func patchStorageMappingWithOptions(configFlags *genericclioptions.ConfigFlags,
 		return fmt.Errorf("failed to patch storage mapping: %v", err)
 	}
 
-	fmt.Printf("storagemap/%s patched\n", name)
+	fmt.Printf("storagemap/%s patched%s\n", name, client.DryRunSuffix())
 	return nil
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"os"
 	"strings"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -14,6 +15,8 @@ import (
 	"k8s.io/klog/v2"
 
 	"github.com/yaacov/karl-interpreter/pkg/karl"
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/get/plan/guestconversion"
+	startplan "github.com/yaacov/kubectl-mtv/pkg/cmd/start/plan"
 	"github.com/yaacov/kubectl-mtv/pkg/util/client"
 	"github.com/yaacov/kubectl-mtv/pkg/util/flags"
 )
@@ -68,6 +71,7 @@ type PatchPlanOptions struct {
 	ServiceAccount                 string
 	TagMappingDisabled             bool
 	TagMappingLabelTags            []string
+	DependsOn                      []string
 
 	// Flag change tracking
 	UseCompatibilityModeChanged           bool
@@ -89,6 +93,7 @@ type PatchPlanOptions struct {
 	ServiceAccountChanged                 bool
 	TagMappingDisabledChanged             bool
 	TagMappingLabelTagsChanged            bool
+	DependsOnChanged                      bool
 }
 
 // PatchPlan patches an existing migration plan
@@ -481,11 +486,17 @@ func PatchPlan(opts PatchPlanOptions) error {
 		}
 	}
 
-	// Update skip guest conversion if flag was changed
+	// Update skip guest conversion if flag was changed. The guest check recorded by create plan
+	// no longer applies, so it is removed.
+	patchAnnotations := make(map[string]interface{})
 	if opts.SkipGuestConversionChanged {
 		patchSpec["skipGuestConversion"] = opts.SkipGuestConversion
+		patchAnnotations[guestconversion.SkipDecisionAnnotation] = nil
 		klog.V(2).Infof("Updated skip guest conversion to %t", opts.SkipGuestConversion)
 		planUpdated = true
+		if opts.SkipGuestConversion {
+			fmt.Fprintf(os.Stderr, "Warning: guest conversion is skipped for plan '%s'. Windows and older Linux guests need it to get virtio drivers and may not boot without --use-compatibility-mode (guests were not checked)\n", opts.Name)
+		}
 	}
 
 	// Update warm migration if flag was changed
@@ -539,17 +550,38 @@ func PatchPlan(opts PatchPlanOptions) error {
 		planUpdated = true
 	}
 
+	// Update the plans that must succeed before this plan is started
+	if opts.DependsOnChanged {
+		deps := startplan.ParseDependsOn(strings.Join(opts.DependsOn, ","))
+		for _, dep := range deps {
+			if dep == opts.Name {
+				return fmt.Errorf("plan '%s' cannot depend on itself", opts.Name)
+			}
+		}
+		if len(deps) > 0 {
+			patchAnnotations[startplan.DependsOnAnnotation] = strings.Join(deps, ",")
+		} else {
+			patchAnnotations[startplan.DependsOnAnnotation] = nil
+		}
+		klog.V(2).Infof("Updated plan dependencies to %v", deps)
+		planUpdated = true
+	}
+
 	// Early return if no changes were made
 	if !planUpdated {
 		fmt.Printf("plan/%s unchanged (no updates specified)\n", opts.Name)
 		return nil
 	}
 
-	// Apply merge patch if there are spec fields to patch
-	if len(patchSpec) > 0 {
+	// Apply merge patch if there are spec fields or annotations to patch
+	if len(patchSpec) > 0 || len(patchAnnotations) > 0 {
 		// Patch the changed spec fields
-		patchData := map[string]interface{}{
-			"spec": patchSpec,
+		patchData := map[string]interface{}{}
+		if len(patchSpec) > 0 {
+			patchData["spec"] = patchSpec
+		}
+		if len(patchAnnotations) > 0 {
+			patchData["metadata"] = map[string]interface{}{"annotations": patchAnnotations}
 		}
 
 		patchBytes, err := json.Marshal(patchData)
@@ -571,7 +603,7 @@ func PatchPlan(opts PatchPlanOptions) error {
 	}
 
 	// Print success message since we know planUpdated is true
-	fmt.Printf("plan/%s patched\n", opts.Name)
+	fmt.Printf("plan/%s patched%s\n", opts.Name, client.DryRunSuffix())
 
 	return nil
 }
@@ -874,7 +906,7 @@ func PatchPlanVM(configFlags *genericclioptions.ConfigFlags, planName, vmName, n
 		if err != nil {
 			return fmt.Errorf("failed to patch plan: %v", err)
 		}
-		fmt.Printf("plan/%s vm/%s patched\n", planName, vmName)
+		fmt.Printf("plan/%s vm/%s patched%s\n", planName, vmName, client.DryRunSuffix())
 	} else {
 		fmt.Printf("plan/%s vm/%s unchanged (no updates specified)\n", planName, vmName)
 	}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -70,6 +71,10 @@ type PatchProviderOptions struct {
 	AzureTargetRegion          string
 	AzureSnapshotSku           string
 	AzureSnapshotResourceGroup string
+
+	// Credential rotation
+	RotateCredentials bool
+	WaitTimeout       time.Duration
 }
 
 // PatchProvider patches an existing provider
@@ -111,6 +116,11 @@ func PatchProvider(opts PatchProviderOptions) error {
 		}
 	}
 
+	// Credential rotation must carry new secret values
+	if opts.RotateCredentials && !hasRotatedCredentials(opts) {
+		return fmt.Errorf("--rotate-credentials requires new credentials (e.g. --username/--password, --provider-token)")
+	}
+
 	// Track if we need to update credentials
 	// Note: AutoTargetCredentials for EC2 providers will populate EC2TargetAccessKeyID and EC2TargetSecretKey above
 	needsCredentialUpdate := opts.Username != "" || opts.Password != "" || opts.Token != "" || opts.CACert != "" ||
@@ -266,9 +276,21 @@ func PatchProvider(opts PatchProviderOptions) error {
 		}
 	}
 
+	// Reconcile with the new credentials and wait for the provider to validate them
+	if opts.RotateCredentials {
+		if secretUpdated || providerUpdated {
+			fmt.Printf("provider/%s patched%s\n", opts.Name, client.DryRunSuffix())
+		}
+		// A dry run changes nothing for the provider to reconcile
+		if client.DryRunEnabled() {
+			return nil
+		}
+		return rotateProviderCredentials(dynamicClient, opts)
+	}
+
 	// Provide user feedback
 	if providerUpdated || secretUpdated {
-		fmt.Printf("provider/%s patched\n", opts.Name)
+		fmt.Printf("provider/%s patched%s\n", opts.Name, client.DryRunSuffix())
 		if secretUpdated {
 			klog.V(2).Infof("Updated credentials for provider '%s'", opts.Name)
 		}
@@ -552,3 +574,23 @@ func updateVddkConfig(existingConfig string, bufSizeIn64K, bufCount int) string
 
 	return configBuilder.String()
 }
+
+// ProviderURL returns the URL of an existing provider, used to fetch its server certificate
+// when the URL is not being changed
+func ProviderURL(ctx context.Context, configFlags *genericclioptions.ConfigFlags, name, namespace string) (string, error) {
+	dynamicClient, err := client.GetDynamicClient(configFlags)
+	if err != nil {
+		return "", fmt.Errorf("failed to get client: %v", err)
+	}
+
+	existingProvider, err := dynamicClient.Resource(client.ProvidersGVR).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to get provider '%s': %v", name, err)
+	}
+
+	url, _, _ := unstructured.NestedString(existingProvider.Object, "spec", "url")
+	if url == "" {
+		return "", fmt.Errorf("provider '%s' has no URL to fetch a certificate from", name)
+	}
+	return url, nil
+}
@@ -0,0 +1,174 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/klog/v2"
+
+	"github.com/yaacov/kubectl-mtv/pkg/util/client"
+)
+
+// CredentialsRotatedAnnotation records when the provider credentials were last rotated.
+// Changing it forces the controller to reconcile the provider with the new secret.
+const CredentialsRotatedAnnotation = "kubectl-mtv/credentials-rotated-at"
+
+// DefaultRotateWaitTimeout is the default time to wait for a provider to become Ready after rotation
+const DefaultRotateWaitTimeout = 5 * time.Minute
+
+// rotatePollInterval is the interval between provider status checks
+var rotatePollInterval = 2 * time.Second
+
+// rotationBaseline is the provider state captured right after the reconcile was triggered.
+// The provider is usually already Ready before the secret changes, and neither the annotation
+// patch nor an unchanged condition moves the generation or lastTransitionTime, so the rotation
+// only counts once the controller writes a status that differs from this snapshot.
+type rotationBaseline struct {
+	resourceVersion string
+	status          map[string]interface{}
+}
+
+// providerFailureConditions are the conditions that report a credential problem when False
+var providerFailureConditions = []string{"ConnectionTestSucceeded", "Validated"}
+
+// hasRotatedCredentials reports whether the options carry any secret credential value
+func hasRotatedCredentials(opts PatchProviderOptions) bool {
+	return opts.Username != "" || opts.Password != "" || opts.Token != "" ||
+		opts.EC2TargetAccessKeyID != "" || opts.EC2TargetSecretKey != "" ||
+		opts.SMBUser != "" || opts.SMBPassword != "" ||
+		opts.AzureClientID != "" || opts.AzureClientSecret != ""
+}
+
+// providerReconciled reports whether the controller wrote a new provider status after the baseline
+func providerReconciled(obj *unstructured.Unstructured, baseline rotationBaseline) bool {
+	if obj.GetResourceVersion() == baseline.resourceVersion {
+		return false
+	}
+	status, _, _ := unstructured.NestedMap(obj.Object, "status")
+	return !reflect.DeepEqual(status, baseline.status)
+}
+
+// providerReadiness evaluates the provider conditions, returning whether it is Ready
+// and the messages of any failing credential related conditions.
+func providerReadiness(obj map[string]interface{}) (bool, []string) {
+	conditions, exists, _ := unstructured.NestedSlice(obj, "status", "conditions")
+	if !exists {
+		return false, nil
+	}
+
+	ready := false
+	var failures []string
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		condType, _, _ := unstructured.NestedString(condition, "type")
+		condStatus, _, _ := unstructured.NestedString(condition, "status")
+		message, _, _ := unstructured.NestedString(condition, "message")
+
+		if condType == "Ready" {
+			ready = condStatus == "True"
+			continue
+		}
+
+		// Forklift reports critical validation failures with category Critical
+		category, _, _ := unstructured.NestedString(condition, "category")
+		isFailureType := false
+		for _, t := range providerFailureConditions {
+			if condType == t {
+				isFailureType = true
+				break
+			}
+		}
+		if (isFailureType && condStatus == "False") || (category == "Critical" && condStatus == "True") {
+			if message == "" {
+				message = "condition is " + condStatus
+			}
+			failures = append(failures, fmt.Sprintf("%s: %s", condType, message))
+		}
+	}
+
+	return ready, failures
+}
+
+// triggerProviderReconcile annotates the provider so the controller reconciles it, returning
+// the baseline the provider status must move past for the new credentials to be observed.
+func triggerProviderReconcile(ctx context.Context, dynamicClient dynamic.Interface, namespace, name string, now time.Time) (rotationBaseline, error) {
+	patchBytes := []byte(fmt.Sprintf(`{"metadata":{"annotations":{%q:%q}}}`,
+		CredentialsRotatedAnnotation, now.UTC().Format(time.RFC3339)))
+
+	provider, err := dynamicClient.Resource(client.ProvidersGVR).Namespace(namespace).Patch(
+		ctx, name, types.MergePatchType, patchBytes, metav1.PatchOptions{})
+	if err != nil {
+		return rotationBaseline{}, fmt.Errorf("failed to trigger provider reconciliation: %v", err)
+	}
+
+	// The annotation patch leaves the status untouched, so it is the status before the reconcile
+	status, _, _ := unstructured.NestedMap(provider.Object, "status")
+	return rotationBaseline{resourceVersion: provider.GetResourceVersion(), status: status}, nil
+}
+
+// waitForProviderReady polls the provider until the controller wrote a new status after the
+// rotation baseline and the provider is Ready, reports failing conditions, or times out
+func waitForProviderReady(ctx context.Context, dynamicClient dynamic.Interface, namespace, name string, baseline rotationBaseline, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	for {
+		provider, err := dynamicClient.Resource(client.ProvidersGVR).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil && ctx.Err() == nil {
+			return fmt.Errorf("failed to get provider '%s': %v", name, err)
+		}
+
+		if err == nil && providerReconciled(provider, baseline) {
+			ready, failures := providerReadiness(provider.Object)
+			klog.V(3).Infof("Provider '%s' ready=%t failures=%v", name, ready, failures)
+
+			if len(failures) > 0 {
+				return fmt.Errorf("provider '%s' failed validation after credential rotation:\n  %s",
+					name, strings.Join(failures, "\n  "))
+			}
+			if ready {
+				return nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timed out after %s waiting for provider '%s' to reconcile the new credentials and become Ready", timeout, name)
+		case <-time.After(rotatePollInterval):
+		}
+	}
+}
+
+// rotateProviderCredentials triggers reconciliation after a secret update and waits for the provider
+func rotateProviderCredentials(dynamicClient dynamic.Interface, opts PatchProviderOptions) error {
+	ctx := context.TODO()
+
+	baseline, err := triggerProviderReconcile(ctx, dynamicClient, opts.Namespace, opts.Name, time.Now())
+	if err != nil {
+		return err
+	}
+
+	timeout := opts.WaitTimeout
+	if timeout <= 0 {
+		timeout = DefaultRotateWaitTimeout
+	}
+
+	fmt.Printf("Waiting for provider/%s to become Ready (timeout %s)...\n", opts.Name, timeout)
+	if err := waitForProviderReady(ctx, dynamicClient, opts.Namespace, opts.Name, baseline, timeout); err != nil {
+		return err
+	}
+
+	fmt.Printf("provider/%s credentials rotated\n", opts.Name)
+	return nil
+}
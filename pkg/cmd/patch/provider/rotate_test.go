@@ -0,0 +1,131 @@
+package provider
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func providerWithConditions(conditions ...map[string]interface{}) map[string]interface{} {
+	items := make([]interface{}, 0, len(conditions))
+	for _, c := range conditions {
+		items = append(items, c)
+	}
+	return map[string]interface{}{
+		"status": map[string]interface{}{
+			"conditions": items,
+		},
+	}
+}
+
+func TestProviderReconciled(t *testing.T) {
+	readyCondition := map[string]interface{}{"type": "Ready", "status": "True", "lastTransitionTime": "2026-01-01T09:59:00Z"}
+	before := providerWithConditions(readyCondition)
+	baseline := rotationBaseline{resourceVersion: "100", status: before["status"].(map[string]interface{})}
+
+	provider := func(resourceVersion string, obj map[string]interface{}) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{Object: obj}
+		u.SetResourceVersion(resourceVersion)
+		return u
+	}
+
+	tests := []struct {
+		name string
+		obj  *unstructured.Unstructured
+		want bool
+	}{
+		{
+			name: "ready before the rotation is not reconciled",
+			obj:  provider("100", providerWithConditions(readyCondition)),
+			want: false,
+		},
+		{
+			name: "metadata write without a status change is not reconciled",
+			obj:  provider("101", providerWithConditions(readyCondition)),
+			want: false,
+		},
+		{
+			name: "connection test re-evaluated",
+			obj: provider("102", providerWithConditions(
+				readyCondition,
+				map[string]interface{}{"type": "ConnectionTestSucceeded", "status": "True", "lastTransitionTime": "2026-01-01T10:00:05Z"},
+			)),
+			want: true,
+		},
+		{
+			name: "failure after the rotation",
+			obj: provider("103", providerWithConditions(
+				map[string]interface{}{"type": "ConnectionTestSucceeded", "status": "False", "message": "401 Unauthorized"},
+			)),
+			want: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := providerReconciled(tt.obj, baseline); got != tt.want {
+				t.Errorf("providerReconciled() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProviderReadiness(t *testing.T) {
+	tests := []struct {
+		name         string
+		obj          map[string]interface{}
+		wantReady    bool
+		wantFailures []string
+	}{
+		{
+			name:      "no status",
+			obj:       map[string]interface{}{},
+			wantReady: false,
+		},
+		{
+			name: "ready",
+			obj: providerWithConditions(
+				map[string]interface{}{"type": "Ready", "status": "True"},
+				map[string]interface{}{"type": "ConnectionTestSucceeded", "status": "True"},
+			),
+			wantReady: true,
+		},
+		{
+			name: "connection failed",
+			obj: providerWithConditions(
+				map[string]interface{}{"type": "Ready", "status": "False"},
+				map[string]interface{}{"type": "ConnectionTestSucceeded", "status": "False", "message": "401 Unauthorized"},
+			),
+			wantFailures: []string{"ConnectionTestSucceeded: 401 Unauthorized"},
+		},
+		{
+			name: "critical condition",
+			obj: providerWithConditions(
+				map[string]interface{}{"type": "SecretNotValid", "status": "True", "category": "Critical", "message": "missing password"},
+			),
+			wantFailures: []string{"SecretNotValid: missing password"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ready, failures := providerReadiness(tt.obj)
+			if ready != tt.wantReady {
+				t.Errorf("ready = %t, want %t", ready, tt.wantReady)
+			}
+			if strings.Join(failures, ";") != strings.Join(tt.wantFailures, ";") {
+				t.Errorf("failures = %v, want %v", failures, tt.wantFailures)
+			}
+		})
+	}
+}
+
+func TestHasRotatedCredentials(t *testing.T) {
+	if hasRotatedCredentials(PatchProviderOptions{URL: "https://example.com"}) {
+		t.Error("expected URL-only options to carry no credentials")
+	}
+	if !hasRotatedCredentials(PatchProviderOptions{Token: "new-token"}) {
+		t.Error("expected token to count as a rotated credential")
+	}
+}
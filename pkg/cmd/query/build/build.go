@@ -0,0 +1,251 @@
+// Package build composes TSL queries for the --query flags interactively, checking each
+// field against the inventory schema of the provider type.
+package build
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/get/inventory"
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/help"
+	querypkg "github.com/yaacov/kubectl-mtv/pkg/util/query"
+)
+
+// Operators are the TSL operators a condition can use, in the order they are offered
+var Operators = []string{"=", "!=", "<", "<=", ">", ">=", "like", "ilike", "~=", "~!", "in", "not in", "between", "is null", "is not null"}
+
+// Options configures an interactive query build
+type Options struct {
+	// Schema is the inventory schema of the provider type the query is for
+	Schema *help.InventorySchema
+	// Resource is the queried resource, help.SchemaResourceVM or help.SchemaResourceNetwork
+	Resource string
+	// In receives the answers and Out the questions
+	In  io.Reader
+	Out io.Writer
+}
+
+// ResolveSchema returns the inventory schema of a provider type, or of the type of a
+// provider when providerType is empty
+func ResolveSchema(ctx context.Context, configFlags *genericclioptions.ConfigFlags, providerType, providerName, namespace string) (*help.InventorySchema, error) {
+	if providerType == "" {
+		provider, err := inventory.GetProviderByName(ctx, configFlags, providerName, namespace)
+		if err != nil {
+			return nil, err
+		}
+		providerType, _, _ = unstructured.NestedString(provider.Object, "spec", "type")
+	}
+
+	schema := help.GetInventorySchema(providerType)
+	if schema == nil {
+		known := make([]string, 0, len(help.InventorySchemas()))
+		for _, s := range help.InventorySchemas() {
+			known = append(known, s.Provider)
+		}
+		return nil, fmt.Errorf("no query schema for provider type '%s' (known: %s)", providerType, strings.Join(known, ", "))
+	}
+	return schema, nil
+}
+
+// builder asks the questions of a query build
+type builder struct {
+	opts    Options
+	scanner *bufio.Scanner
+}
+
+// Build asks for the conditions of a query one field at a time, then for the ordering
+// and the limit, and returns the query. Unknown fields, operators and values are asked
+// again; an empty field, or the end of the input, finishes the conditions.
+func Build(opts Options) (string, error) {
+	b := &builder{opts: opts, scanner: bufio.NewScanner(opts.In)}
+	resource := "VM"
+	if opts.Resource == help.SchemaResourceNetwork {
+		resource = "network"
+	}
+	fmt.Fprintf(opts.Out, "Building a TSL query over %s %s fields.\n", opts.Schema.Title, resource)
+	fmt.Fprintf(opts.Out, "Enter ? to list the fields, or an empty field to finish the conditions.\n\n")
+
+	var conditions []string
+	for {
+		field, ok := b.askField("Field")
+		if !ok {
+			break
+		}
+		condition, ok := b.askCondition()
+		if !ok {
+			return "", fmt.Errorf("the input ended before the condition on '%s' was complete", field)
+		}
+		conditions = append(conditions, field+" "+condition)
+
+		join, _ := b.askChoice("Combine with another condition [and, or, empty to finish]", []string{"and", "or"}, true)
+		if join == "" {
+			break
+		}
+		conditions = append(conditions, join)
+	}
+
+	var parts []string
+	if len(conditions) > 0 {
+		parts = append(parts, "where "+strings.Join(conditions, " "))
+	}
+	if order := b.askOrder(); order != "" {
+		parts = append(parts, "order by "+order)
+	}
+	if limit := b.askLimit(); limit != "" {
+		parts = append(parts, "limit "+limit)
+	}
+
+	query := strings.Join(parts, " ")
+	options, err := querypkg.ParseQueryString(query)
+	if err == nil && options.Where != "" {
+		_, err = querypkg.ParseWhereClause(options.Where)
+	}
+	if err != nil {
+		return "", fmt.Errorf("the query '%s' is not valid: %v", query, err)
+	}
+	return query, nil
+}
+
+// ask prints the question and returns the trimmed answer; ok is false at the end of the input
+func (b *builder) ask(question string) (string, bool) {
+	fmt.Fprintf(b.opts.Out, "%s: ", question)
+	if !b.scanner.Scan() {
+		fmt.Fprintln(b.opts.Out)
+		return "", false
+	}
+	return strings.TrimSpace(b.scanner.Text()), true
+}
+
+// askField asks for a field of the schema until a known one is given; ok is false for an
+// empty answer
+func (b *builder) askField(question string) (string, bool) {
+	for {
+		field, ok := b.ask(question)
+		switch {
+		case !ok || field == "":
+			return "", false
+		case field == "?":
+			fmt.Fprintf(b.opts.Out, "  %s\n", strings.Join(b.opts.Schema.QueryFields(b.opts.Resource), ", "))
+		case b.opts.Schema.HasQueryField(b.opts.Resource, field):
+			return field, true
+		default:
+			msg := fmt.Sprintf("  unknown field '%s'", field)
+			if suggestions := b.opts.Schema.SuggestQueryFields(b.opts.Resource, field); len(suggestions) > 0 {
+				msg += fmt.Sprintf(", did you mean %s?", strings.Join(suggestions, ", "))
+			} else {
+				msg += ", enter ? to list the fields"
+			}
+			fmt.Fprintln(b.opts.Out, msg)
+		}
+	}
+}
+
+// askChoice asks until one of the choices is given; with optional, an empty answer is
+// accepted and returned as ""
+func (b *builder) askChoice(question string, choices []string, optional bool) (string, bool) {
+	for {
+		answer, ok := b.ask(question)
+		answer = strings.ToLower(strings.Join(strings.Fields(answer), " "))
+		if !ok || answer == "" && optional {
+			return "", ok
+		}
+		for _, c := range choices {
+			if answer == c {
+				return c, true
+			}
+		}
+		fmt.Fprintf(b.opts.Out, "  expected one of: %s\n", strings.Join(choices, ", "))
+	}
+}
+
+// askCondition asks for the operator and the values of a condition and returns them as
+// TSL, e.g. "in ['a', 'b']"; ok is false when the input ends first
+func (b *builder) askCondition() (string, bool) {
+	op, ok := b.askChoice(fmt.Sprintf("Operator [%s]", strings.Join(Operators, " ")), Operators, false)
+	if !ok {
+		return "", false
+	}
+
+	switch op {
+	case "is null", "is not null":
+		return op, true
+	case "in", "not in":
+		values, ok := b.askValue("Values (comma-separated)")
+		var literals []string
+		for _, v := range strings.Split(values, ",") {
+			literals = append(literals, Literal(strings.TrimSpace(v)))
+		}
+		return fmt.Sprintf("%s [%s]", op, strings.Join(literals, ", ")), ok
+	case "between":
+		from, ok := b.askValue("From")
+		if !ok {
+			return "", false
+		}
+		to, ok := b.askValue("To")
+		return fmt.Sprintf("between %s and %s", Literal(from), Literal(to)), ok
+	default:
+		value, ok := b.askValue("Value")
+		return op + " " + Literal(value), ok
+	}
+}
+
+// askValue asks until a value is given
+func (b *builder) askValue(question string) (string, bool) {
+	for {
+		value, ok := b.ask(question)
+		if value != "" || !ok {
+			return value, ok
+		}
+		fmt.Fprintln(b.opts.Out, "  a value is required")
+	}
+}
+
+// askOrder asks for the ordering field and direction, e.g. "memoryMB desc"
+func (b *builder) askOrder() string {
+	field, ok := b.askField("Order by field (empty for none)")
+	if !ok {
+		return ""
+	}
+	direction, _ := b.askChoice("Direction [asc, desc]", []string{"asc", "desc"}, true)
+	if direction == "" {
+		return field
+	}
+	return field + " " + direction
+}
+
+// askLimit asks for the maximum number of results
+func (b *builder) askLimit() string {
+	for {
+		limit, ok := b.ask("Limit (empty for none)")
+		if !ok || limit == "" {
+			return ""
+		}
+		if n, err := strconv.Atoi(limit); err == nil && n > 0 {
+			return limit
+		}
+		fmt.Fprintln(b.opts.Out, "  the limit must be a positive number")
+	}
+}
+
+// Literal returns a value as a TSL literal: numbers and booleans as they are, other
+// values single-quoted. Values the user already quoted are kept.
+func Literal(value string) string {
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		return value
+	}
+	switch strings.ToLower(value) {
+	case "true", "false":
+		return strings.ToLower(value)
+	}
+	if len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'' {
+		return value
+	}
+	return "'" + strings.ReplaceAll(value, "'", "\\'") + "'"
+}
@@ -0,0 +1,100 @@
+package build
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/help"
+)
+
+func TestBuild(t *testing.T) {
+	tests := []struct {
+		name     string
+		resource string
+		input    []string
+		want     string
+		wantOut  string
+	}{
+		{
+			name:  "conditions, order and limit",
+			input: []string{"cpucount", "cpuCount", ">=", "4", "and", "name", "like", "web%", "", "memoryMB", "desc", "10"},
+			want:  "where cpuCount >= 4 and name like 'web%' order by memoryMB desc limit 10",
+			// The miscased field is asked again with a suggestion
+			wantOut: "did you mean cpuCount?",
+		},
+		{
+			name:  "in, between and null checks",
+			input: []string{"powerStateHuman", "in", "On, Off", "or", "len(disks)", "between", "2", "4", "and", "ipAddress", "is not null"},
+			want:  "where powerStateHuman in ['On', 'Off'] or len(disks) between 2 and 4 and ipAddress is not null",
+		},
+		{
+			name:     "network fields",
+			resource: help.SchemaResourceNetwork,
+			input:    []string{"powerStateHuman", "vlanId", "=", "100", "", "", ""},
+			want:     "where vlanId = 100",
+			wantOut:  "unknown field 'powerStateHuman'",
+		},
+		{
+			name:    "invalid operator and limit asked again",
+			input:   []string{"name", "contains", "~=", "^db-", "", "", "-1", "5"},
+			want:    "where name ~= '^db-' limit 5",
+			wantOut: "expected one of",
+		},
+		{
+			name:  "only an order",
+			input: []string{"", "diskGiB", ""},
+			want:  "order by diskGiB",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resource := tt.resource
+			if resource == "" {
+				resource = help.SchemaResourceVM
+			}
+			var out bytes.Buffer
+			got, err := Build(Options{
+				Schema:   help.GetInventorySchema("vsphere"),
+				Resource: resource,
+				In:       strings.NewReader(strings.Join(tt.input, "\n") + "\n"),
+				Out:      &out,
+			})
+			if err != nil {
+				t.Fatalf("Build() error = %v\n%s", err, out.String())
+			}
+			if got != tt.want {
+				t.Errorf("Build() = %q, want %q", got, tt.want)
+			}
+			if !strings.Contains(out.String(), tt.wantOut) {
+				t.Errorf("output missing %q:\n%s", tt.wantOut, out.String())
+			}
+		})
+	}
+}
+
+func TestBuildIncompleteCondition(t *testing.T) {
+	_, err := Build(Options{
+		Schema:   help.GetInventorySchema("vsphere"),
+		Resource: help.SchemaResourceVM,
+		In:       strings.NewReader("name\n=\n"),
+		Out:      &bytes.Buffer{},
+	})
+	if err == nil || !strings.Contains(err.Error(), "condition on 'name'") {
+		t.Errorf("Build() error = %v, want an incomplete condition error", err)
+	}
+}
+
+func TestLiteral(t *testing.T) {
+	for value, want := range map[string]string{
+		"4":      "4",
+		"2.5":    "2.5",
+		"TRUE":   "true",
+		"web%":   "'web%'",
+		"'kept'": "'kept'",
+	} {
+		if got := Literal(value); got != want {
+			t.Errorf("Literal(%q) = %q, want %q", value, got, want)
+		}
+	}
+}
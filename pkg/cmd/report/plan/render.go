@@ -0,0 +1,285 @@
+package plan
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"strings"
+	"time"
+
+	"github.com/yaacov/kubectl-mtv/pkg/util/describe"
+	"github.com/yaacov/kubectl-mtv/pkg/util/output"
+)
+
+// Render formats the report as markdown, html or json
+func Render(report *Report, format string, useUTC bool) (string, error) {
+	switch strings.ToLower(format) {
+	case "", "markdown", "md":
+		return describe.Format(toDescription(report, useUTC), "markdown")
+	case "html":
+		return renderHTML(report, useUTC)
+	case "json":
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("failed to encode report: %v", err)
+		}
+		return string(data) + "\n", nil
+	default:
+		return "", fmt.Errorf("unsupported report format: %s. Supported formats: markdown, html, json", format)
+	}
+}
+
+// FileExtension returns the file extension of a report rendered in the given format
+func FileExtension(format string) string {
+	switch strings.ToLower(format) {
+	case "html":
+		return "html"
+	case "json":
+		return "json"
+	default:
+		return "md"
+	}
+}
+
+// toDescription converts the report to a description for markdown output
+func toDescription(report *Report, useUTC bool) *describe.Description {
+	b := describe.NewBuilder(fmt.Sprintf("MIGRATION REPORT: %s", report.Plan.Name))
+	b.Field("Generated", output.FormatTimestamp(report.GeneratedAt, useUTC))
+
+	b.Section("PLAN")
+	for _, field := range planFields(report, useUTC) {
+		b.Field(field[0], field[1])
+	}
+
+	b.Section("SUMMARY")
+	for _, field := range summaryFields(report, useUTC) {
+		b.Field(field[0], field[1])
+	}
+
+	if len(report.VMs) > 0 {
+		b.Section("VIRTUAL MACHINES")
+		b.Table(vmColumns(), vmRows(report, useUTC))
+	}
+
+	if notes := notesRows(report); len(notes) > 0 {
+		b.Section("WARNINGS AND FAILURES")
+		b.Table([]describe.TableColumn{
+			{Display: "SOURCE", Key: "source"},
+			{Display: "SEVERITY", Key: "severity"},
+			{Display: "MESSAGE", Key: "message"},
+		}, notes)
+	}
+
+	return b.Build()
+}
+
+// planFields returns the plan label/value pairs
+func planFields(report *Report, useUTC bool) [][2]string {
+	p := report.Plan
+	fields := [][2]string{
+		{"Name", p.Name},
+		{"Namespace", p.Namespace},
+		{"Created", output.FormatTimestamp(p.Created, useUTC)},
+		{"Status", p.Status},
+		{"Migration Type", p.MigrationType},
+		{"Source Provider", p.Source},
+		{"Target Provider", p.Target},
+		{"Target Namespace", p.TargetNamespace},
+	}
+	if p.NetworkMap != "" {
+		fields = append(fields, [2]string{"Network Map", p.NetworkMap})
+	}
+	if p.StorageMap != "" {
+		fields = append(fields, [2]string{"Storage Map", p.StorageMap})
+	}
+	if p.Description != "" {
+		fields = append(fields, [2]string{"Description", p.Description})
+	}
+	return fields
+}
+
+// summaryFields returns the migration summary label/value pairs
+func summaryFields(report *Report, useUTC bool) [][2]string {
+	s := report.Summary
+	if report.Migration == nil {
+		return [][2]string{
+			{"Migration", "not started"},
+			{"VMs", fmt.Sprintf("%d", s.VMs)},
+		}
+	}
+
+	m := report.Migration
+	return [][2]string{
+		{"Migration", m.Name},
+		{"Result", valueOr(m.Result, ResultRunning)},
+		{"Started", formatOptionalTime(m.Started, useUTC)},
+		{"Completed", formatOptionalTime(m.Completed, useUTC)},
+		{"Duration", formatSeconds(m.DurationSeconds)},
+		{"VMs", fmt.Sprintf("%d (succeeded %d, failed %d, canceled %d, running %d)", s.VMs, s.Succeeded, s.Failed, s.Canceled, s.Running)},
+		{"Data Transferred", FormatBytes(s.TransferredBytes)},
+		{"Disk Capacity", FormatBytes(s.DiskBytes)},
+	}
+}
+
+// vmColumns are the columns of the per-VM table
+func vmColumns() []describe.TableColumn {
+	return []describe.TableColumn{
+		{Display: "VM", Key: "name"},
+		{Display: "RESULT", Key: "result"},
+		{Display: "STARTED", Key: "started"},
+		{Display: "COMPLETED", Key: "completed"},
+		{Display: "DURATION", Key: "duration"},
+		{Display: "TRANSFERRED", Key: "transferred"},
+		{Display: "DISKS", Key: "disks"},
+		{Display: "ERROR", Key: "error"},
+	}
+}
+
+// vmRows returns the rows of the per-VM table
+func vmRows(report *Report, useUTC bool) []map[string]string {
+	rows := make([]map[string]string, 0, len(report.VMs))
+	for _, vm := range report.VMs {
+		var diskBytes int64
+		for _, disk := range vm.Disks {
+			diskBytes += disk.Bytes
+		}
+		disks := "-"
+		if len(vm.Disks) > 0 {
+			disks = fmt.Sprintf("%d (%s)", len(vm.Disks), FormatBytes(diskBytes))
+		}
+		rows = append(rows, map[string]string{
+			"name":        vm.Name,
+			"result":      vm.Result,
+			"started":     formatOptionalTime(vm.Started, useUTC),
+			"completed":   formatOptionalTime(vm.Completed, useUTC),
+			"duration":    formatSeconds(vm.DurationSeconds),
+			"transferred": FormatBytes(vm.TransferredBytes),
+			"disks":       disks,
+			"error":       vm.Error,
+		})
+	}
+	return rows
+}
+
+// notesRows returns plan warnings and failures followed by VM warnings
+func notesRows(report *Report) []map[string]string {
+	rows := []map[string]string{}
+	for _, note := range report.Failures {
+		rows = append(rows, map[string]string{"source": "plan/" + note.Type, "severity": note.Category, "message": note.Message})
+	}
+	for _, note := range report.Warnings {
+		rows = append(rows, map[string]string{"source": "plan/" + note.Type, "severity": note.Category, "message": note.Message})
+	}
+	for _, vm := range report.VMs {
+		for _, warning := range vm.Warnings {
+			rows = append(rows, map[string]string{"source": "vm/" + vm.Name, "severity": "Warning", "message": warning})
+		}
+	}
+	return rows
+}
+
+// htmlTemplate is a self-contained HTML page suitable for sharing
+var htmlTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Migration report: {{.Title}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+h1 { font-size: 1.5em; }
+h2 { font-size: 1.2em; margin-top: 1.5em; border-bottom: 1px solid #ccc; }
+table { border-collapse: collapse; margin-top: 0.5em; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: left; vertical-align: top; }
+th { background: #f3f3f3; }
+.fields th { background: none; font-weight: normal; color: #555; }
+.Succeeded { color: #1a7f37; }
+.Failed, .Critical, .Error { color: #cf222e; }
+.Canceled, .Warning, .Warn { color: #9a6700; }
+</style>
+</head>
+<body>
+<h1>Migration report: {{.Title}}</h1>
+<p>Generated {{.Generated}}</p>
+<h2>Plan</h2>
+<table class="fields">{{range .Plan}}
+<tr><th>{{index . 0}}</th><td>{{index . 1}}</td></tr>{{end}}
+</table>
+<h2>Summary</h2>
+<table class="fields">{{range .Summary}}
+<tr><th>{{index . 0}}</th><td>{{index . 1}}</td></tr>{{end}}
+</table>
+{{- if .VMs}}
+<h2>Virtual Machines</h2>
+<table>
+<tr>{{range .VMColumns}}<th>{{.Display}}</th>{{end}}</tr>{{range .VMs}}
+<tr><td>{{.name}}</td><td class="{{.result}}">{{.result}}</td><td>{{.started}}</td><td>{{.completed}}</td><td>{{.duration}}</td><td>{{.transferred}}</td><td>{{.disks}}</td><td>{{.error}}</td></tr>{{end}}
+</table>
+{{- end}}
+{{- if .Notes}}
+<h2>Warnings and Failures</h2>
+<table>
+<tr><th>SOURCE</th><th>SEVERITY</th><th>MESSAGE</th></tr>{{range .Notes}}
+<tr><td>{{.source}}</td><td class="{{.severity}}">{{.severity}}</td><td>{{.message}}</td></tr>{{end}}
+</table>
+{{- end}}
+</body>
+</html>
+`))
+
+// renderHTML formats the report as a standalone HTML page
+func renderHTML(report *Report, useUTC bool) (string, error) {
+	data := map[string]interface{}{
+		"Title":     report.Plan.Name,
+		"Generated": output.FormatTimestamp(report.GeneratedAt, useUTC),
+		"Plan":      planFields(report, useUTC),
+		"Summary":   summaryFields(report, useUTC),
+		"VMColumns": vmColumns(),
+		"VMs":       vmRows(report, useUTC),
+		"Notes":     notesRows(report),
+	}
+
+	var buf bytes.Buffer
+	if err := htmlTemplate.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render HTML report: %v", err)
+	}
+	return buf.String(), nil
+}
+
+// FormatBytes returns a human readable binary size, e.g. "1.5 GiB"
+func FormatBytes(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
+
+// formatSeconds returns a duration in seconds as text, or "-" when unknown
+func formatSeconds(seconds int64) string {
+	if seconds <= 0 {
+		return "-"
+	}
+	return (time.Duration(seconds) * time.Second).String()
+}
+
+// formatOptionalTime formats a time that may not be set
+func formatOptionalTime(t *time.Time, useUTC bool) string {
+	if t == nil {
+		return "-"
+	}
+	return output.FormatTimestamp(*t, useUTC)
+}
+
+// valueOr returns value, or fallback when value is empty
+func valueOr(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,372 @@
+package plan
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/klog/v2"
+
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/get/plan/status"
+	"github.com/yaacov/kubectl-mtv/pkg/util/client"
+	"github.com/yaacov/kubectl-mtv/pkg/util/fields"
+	"github.com/yaacov/kubectl-mtv/pkg/util/upload"
+)
+
+// dataVolumesGVR is used to read the DataVolumes created for the migrated disks
+var dataVolumesGVR = schema.GroupVersionResource{
+	Group:    "cdi.kubevirt.io",
+	Version:  "v1beta1",
+	Resource: "datavolumes",
+}
+
+// Report is a post-migration report of a plan
+type Report struct {
+	GeneratedAt time.Time       `json:"generatedAt"`
+	Plan        PlanInfo        `json:"plan"`
+	Migration   *MigrationInfo  `json:"migration,omitempty"`
+	Summary     Summary         `json:"summary"`
+	VMs         []VMReport      `json:"vms"`
+	Warnings    []ConditionNote `json:"warnings,omitempty"`
+	Failures    []ConditionNote `json:"failures,omitempty"`
+}
+
+// PlanInfo is the plan specification part of the report
+type PlanInfo struct {
+	Name            string    `json:"name"`
+	Namespace       string    `json:"namespace"`
+	Created         time.Time `json:"created"`
+	Status          string    `json:"status"`
+	MigrationType   string    `json:"migrationType"`
+	Source          string    `json:"source"`
+	Target          string    `json:"target"`
+	TargetNamespace string    `json:"targetNamespace"`
+	NetworkMap      string    `json:"networkMap,omitempty"`
+	StorageMap      string    `json:"storageMap,omitempty"`
+	Description     string    `json:"description,omitempty"`
+}
+
+// MigrationInfo describes the migration the report is based on
+type MigrationInfo struct {
+	Name      string     `json:"name"`
+	Result    string     `json:"result,omitempty"`
+	Started   *time.Time `json:"started,omitempty"`
+	Completed *time.Time `json:"completed,omitempty"`
+	// DurationSeconds is the run time, up to now while the migration is running
+	DurationSeconds int64 `json:"durationSeconds,omitempty"`
+}
+
+// Summary aggregates the per-VM results
+type Summary struct {
+	VMs              int   `json:"vms"`
+	Succeeded        int   `json:"succeeded"`
+	Failed           int   `json:"failed"`
+	Canceled         int   `json:"canceled"`
+	Running          int   `json:"running"`
+	TransferredBytes int64 `json:"transferredBytes"`
+	DiskBytes        int64 `json:"diskBytes"`
+}
+
+// VMReport is the result of a single VM
+type VMReport struct {
+	Name             string           `json:"name"`
+	ID               string           `json:"id,omitempty"`
+	Result           string           `json:"result"`
+	Phase            string           `json:"phase,omitempty"`
+	Started          *time.Time       `json:"started,omitempty"`
+	Completed        *time.Time       `json:"completed,omitempty"`
+	DurationSeconds  int64            `json:"durationSeconds,omitempty"`
+	TransferredBytes int64            `json:"transferredBytes"`
+	Disks            []DataVolumeInfo `json:"disks,omitempty"`
+	Warnings         []string         `json:"warnings,omitempty"`
+	Error            string           `json:"error,omitempty"`
+}
+
+// DataVolumeInfo is the status of a DataVolume created for a VM disk
+type DataVolumeInfo struct {
+	Name     string `json:"name"`
+	Phase    string `json:"phase,omitempty"`
+	Progress string `json:"progress,omitempty"`
+	Bytes    int64  `json:"bytes"`
+}
+
+// ConditionNote is a plan or migration condition worth reporting
+type ConditionNote struct {
+	Type     string `json:"type"`
+	Category string `json:"category,omitempty"`
+	Message  string `json:"message"`
+}
+
+// ResultRunning is the result of a VM that has not finished yet
+const ResultRunning = "Running"
+
+// Generate collects the plan, its latest migration and the migration DataVolumes and builds the report
+func Generate(ctx context.Context, c dynamic.Interface, name, namespace string) (*Report, error) {
+	plan, err := c.Resource(client.PlansGVR).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get plan '%s': %v", name, err)
+	}
+
+	running, latest, err := status.GetRunningMigration(c, namespace, plan, client.MigrationsGVR)
+	if err != nil {
+		return nil, err
+	}
+	migration := running
+	if migration == nil {
+		migration = latest
+	}
+
+	var dataVolumes []unstructured.Unstructured
+	if migration != nil {
+		dataVolumes = listDataVolumes(ctx, c, fields.TargetNamespace(plan), string(plan.GetUID()), string(migration.GetUID()))
+	}
+
+	return Build(plan, migration, dataVolumes, time.Now()), nil
+}
+
+// listDataVolumes returns the DataVolumes Forklift created for the migration. A missing
+// CDI installation or missing permissions only leave disk sizes out of the report.
+func listDataVolumes(ctx context.Context, c dynamic.Interface, namespace, planUID, migrationUID string) []unstructured.Unstructured {
+	list, err := c.Resource(dataVolumesGVR).Namespace(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("plan=%s,migration=%s", planUID, migrationUID),
+	})
+	if err != nil {
+		klog.V(1).Infof("Failed to list DataVolumes in '%s': %v", namespace, err)
+		return nil
+	}
+	return list.Items
+}
+
+// Build assembles a report from the plan, its migration (may be nil) and the migration DataVolumes
+func Build(plan, migration *unstructured.Unstructured, dataVolumes []unstructured.Unstructured, now time.Time) *Report {
+	planStatus, _ := status.GetPlanStatus(plan)
+	report := &Report{
+		GeneratedAt: now,
+		Plan: PlanInfo{
+			Name:            plan.GetName(),
+			Namespace:       plan.GetNamespace(),
+			Created:         plan.GetCreationTimestamp().Time,
+			Status:          planStatus,
+			MigrationType:   status.GetMigrationType(plan),
+			Source:          fields.String(plan.Object, "spec", "provider", "source", "name"),
+			Target:          fields.String(plan.Object, "spec", "provider", "destination", "name"),
+			TargetNamespace: fields.TargetNamespace(plan),
+			NetworkMap:      fields.String(plan.Object, "spec", "map", "network", "name"),
+			StorageMap:      fields.String(plan.Object, "spec", "map", "storage", "name"),
+			Description:     fields.String(plan.Object, "spec", "description"),
+		},
+		VMs: []VMReport{},
+	}
+
+	report.Warnings, report.Failures = conditionNotes(plan.Object)
+
+	if migration == nil {
+		report.Summary.VMs = len(fields.Slice(plan.Object, "spec", "vms"))
+		return report
+	}
+
+	report.Migration = &MigrationInfo{
+		Name:      migration.GetName(),
+		Result:    conditionResult(fields.Slice(migration.Object, "status", "conditions")),
+		Started:   fields.Time(migration.Object, "status", "started"),
+		Completed: fields.Time(migration.Object, "status", "completed"),
+	}
+	report.Migration.DurationSeconds = durationSeconds(report.Migration.Started, report.Migration.Completed, now)
+
+	disksByVM := groupDataVolumes(dataVolumes)
+	for _, v := range fields.Slice(migration.Object, "status", "vms") {
+		vm, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		vmReport := buildVMReport(vm, disksByVM[fields.String(vm, "id")], now)
+		report.VMs = append(report.VMs, vmReport)
+
+		switch vmReport.Result {
+		case status.StatusSucceeded:
+			report.Summary.Succeeded++
+		case status.StatusFailed:
+			report.Summary.Failed++
+		case status.StatusCanceled:
+			report.Summary.Canceled++
+		default:
+			report.Summary.Running++
+		}
+		report.Summary.TransferredBytes += vmReport.TransferredBytes
+		for _, disk := range vmReport.Disks {
+			report.Summary.DiskBytes += disk.Bytes
+		}
+	}
+	report.Summary.VMs = len(report.VMs)
+
+	return report
+}
+
+// buildVMReport builds the report of a single migration status.vms entry
+func buildVMReport(vm map[string]interface{}, disks []DataVolumeInfo, now time.Time) VMReport {
+	vmReport := VMReport{
+		Name:      fields.String(vm, "name"),
+		ID:        fields.String(vm, "id"),
+		Result:    conditionResult(fields.Slice(vm, "conditions")),
+		Phase:     fields.String(vm, "phase"),
+		Started:   fields.Time(vm, "started"),
+		Completed: fields.Time(vm, "completed"),
+		Disks:     disks,
+		Error:     fields.ErrorReasons(vm),
+	}
+	if vmReport.Result == "" {
+		vmReport.Result = ResultRunning
+	}
+	vmReport.DurationSeconds = durationSeconds(vmReport.Started, vmReport.Completed, now)
+
+	for _, p := range fields.Slice(vm, "pipeline") {
+		step, ok := p.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if strings.HasPrefix(fields.String(step, "name"), "DiskTransfer") {
+			completed, _, _ := unstructured.NestedInt64(step, "progress", "completed")
+			vmReport.TransferredBytes += status.ProgressBytes(completed, fields.String(step, "annotations", "unit"))
+		}
+		if reasons := fields.ErrorReasons(step); reasons != "" && vmReport.Error == "" {
+			vmReport.Error = fmt.Sprintf("%s: %s", fields.String(step, "name"), reasons)
+		}
+	}
+
+	for _, c := range fields.Slice(vm, "conditions") {
+		condition, ok := c.(map[string]interface{})
+		if !ok || fields.String(condition, "status") != "True" {
+			continue
+		}
+		if category := fields.String(condition, "category"); category == "Warn" || category == "Warning" {
+			vmReport.Warnings = append(vmReport.Warnings, fields.String(condition, "message"))
+		}
+	}
+	return vmReport
+}
+
+// groupDataVolumes returns the DataVolume details of each VM, keyed by the vmID label
+func groupDataVolumes(dataVolumes []unstructured.Unstructured) map[string][]DataVolumeInfo {
+	disks := map[string][]DataVolumeInfo{}
+	for i := range dataVolumes {
+		dv := &dataVolumes[i]
+		vmID := dv.GetLabels()["vmID"]
+		if vmID == "" {
+			continue
+		}
+		size := fields.String(dv.Object, "spec", "storage", "resources", "requests", "storage")
+		if size == "" {
+			size = fields.String(dv.Object, "spec", "pvc", "resources", "requests", "storage")
+		}
+		var bytes int64
+		if q, err := resource.ParseQuantity(size); err == nil {
+			bytes = q.Value()
+		}
+		disks[vmID] = append(disks[vmID], DataVolumeInfo{
+			Name:     dv.GetName(),
+			Phase:    fields.String(dv.Object, "status", "phase"),
+			Progress: fields.String(dv.Object, "status", "progress"),
+			Bytes:    bytes,
+		})
+	}
+	for vmID := range disks {
+		sort.Slice(disks[vmID], func(i, j int) bool { return disks[vmID][i].Name < disks[vmID][j].Name })
+	}
+	return disks
+}
+
+// conditionNotes returns the warning and critical/error conditions of an object
+func conditionNotes(obj map[string]interface{}) (warnings, failures []ConditionNote) {
+	for _, c := range fields.Slice(obj, "status", "conditions") {
+		condition, ok := c.(map[string]interface{})
+		if !ok || fields.String(condition, "status") != "True" {
+			continue
+		}
+		note := ConditionNote{
+			Type:     fields.String(condition, "type"),
+			Category: fields.String(condition, "category"),
+			Message:  fields.String(condition, "message"),
+		}
+		switch note.Category {
+		case "Warn", "Warning":
+			warnings = append(warnings, note)
+		case "Critical", "Error":
+			failures = append(failures, note)
+		}
+	}
+	return warnings, failures
+}
+
+// conditionResult returns Succeeded, Failed or Canceled from true conditions, if set
+func conditionResult(conditions []interface{}) string {
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok || fields.String(condition, "status") != "True" {
+			continue
+		}
+		switch condType := fields.String(condition, "type"); condType {
+		case status.StatusSucceeded, status.StatusFailed, status.StatusCanceled:
+			return condType
+		}
+	}
+	return ""
+}
+
+// durationSeconds returns the seconds between started and completed, or until now while still running
+func durationSeconds(started, completed *time.Time, now time.Time) int64 {
+	if started == nil {
+		return 0
+	}
+	end := now
+	if completed != nil {
+		end = *completed
+	}
+	return int64(end.Sub(*started).Round(time.Second) / time.Second)
+}
+
+// Write generates the report of a plan, uploads it when an uploader is given, and writes it
+// to file, or to stdout when neither a file nor an uploader is given
+func Write(ctx context.Context, configFlags *genericclioptions.ConfigFlags, name, namespace, format, file string, uploader *upload.S3Uploader, useUTC bool) error {
+	c, err := client.GetDynamicClient(configFlags)
+	if err != nil {
+		return fmt.Errorf("failed to get client: %v", err)
+	}
+
+	report, err := Generate(ctx, c, name, namespace)
+	if err != nil {
+		return err
+	}
+	content, err := Render(report, format, useUTC)
+	if err != nil {
+		return err
+	}
+
+	if uploader != nil {
+		objectURL, err := uploader.UploadNamed(ctx, []byte(content), fmt.Sprintf("%s-report.%s", name, FileExtension(format)))
+		if err != nil {
+			return fmt.Errorf("failed to upload report of plan '%s': %v", name, err)
+		}
+		fmt.Fprintf(os.Stderr, "Report of plan '%s' uploaded to %s\n", name, objectURL)
+	}
+
+	if file == "" {
+		if uploader == nil {
+			fmt.Print(content)
+		}
+		return nil
+	}
+	if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write report '%s': %v", file, err)
+	}
+	fmt.Fprintf(os.Stderr, "Report of plan '%s' written to %s\n", name, file)
+	return nil
+}
@@ -0,0 +1,181 @@
+package plan
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func reportPlan() *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "wave1", "namespace": "mtv"},
+		"spec": map[string]interface{}{
+			"targetNamespace": "apps",
+			"provider": map[string]interface{}{
+				"source":      map[string]interface{}{"name": "vsphere"},
+				"destination": map[string]interface{}{"name": "host"},
+			},
+			"vms": []interface{}{
+				map[string]interface{}{"id": "vm-1"},
+				map[string]interface{}{"id": "vm-2"},
+			},
+		},
+		"status": map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Succeeded", "status": "True", "category": "Advisory", "message": "done"},
+				map[string]interface{}{"type": "VMNetworksNotMapped", "status": "True", "category": "Warn", "message": "network not mapped"},
+				map[string]interface{}{"type": "Unused", "status": "False", "category": "Critical", "message": "ignored"},
+			},
+		},
+	}}
+}
+
+func reportMigration() *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "wave1-abc"},
+		"status": map[string]interface{}{
+			"started":   "2026-05-01T10:00:00Z",
+			"completed": "2026-05-01T11:30:00Z",
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Failed", "status": "True"},
+			},
+			"vms": []interface{}{
+				map[string]interface{}{
+					"id": "vm-1", "name": "web", "phase": "Completed",
+					"started": "2026-05-01T10:00:00Z", "completed": "2026-05-01T10:45:00Z",
+					"conditions": []interface{}{map[string]interface{}{"type": "Succeeded", "status": "True"}},
+					"pipeline": []interface{}{
+						map[string]interface{}{
+							"name":        "DiskTransfer",
+							"progress":    map[string]interface{}{"completed": int64(2048), "total": int64(2048)},
+							"annotations": map[string]interface{}{"unit": "MB"},
+						},
+					},
+				},
+				map[string]interface{}{
+					"id": "vm-2", "name": "db", "phase": "Completed",
+					"started": "2026-05-01T10:00:00Z", "completed": "2026-05-01T11:30:00Z",
+					"conditions": []interface{}{map[string]interface{}{"type": "Failed", "status": "True"}},
+					"error":      map[string]interface{}{"reasons": []interface{}{"disk copy failed"}},
+				},
+			},
+		},
+	}}
+}
+
+func reportDataVolume(name, vmID, size string) unstructured.Unstructured {
+	dv := unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{"name": name, "labels": map[string]interface{}{"vmID": vmID}},
+		"spec": map[string]interface{}{
+			"storage": map[string]interface{}{"resources": map[string]interface{}{"requests": map[string]interface{}{"storage": size}}},
+		},
+		"status": map[string]interface{}{"phase": "Succeeded", "progress": "100.0%"},
+	}}
+	return dv
+}
+
+func TestBuild(t *testing.T) {
+	now := time.Date(2026, 5, 2, 0, 0, 0, 0, time.UTC)
+	dvs := []unstructured.Unstructured{
+		reportDataVolume("web-disk-1", "vm-1", "10Gi"),
+		reportDataVolume("web-disk-0", "vm-1", "20Gi"),
+	}
+	report := Build(reportPlan(), reportMigration(), dvs, now)
+
+	if report.Plan.TargetNamespace != "apps" || report.Plan.Source != "vsphere" {
+		t.Errorf("unexpected plan info: %+v", report.Plan)
+	}
+	if report.Migration == nil || report.Migration.Result != "Failed" || report.Migration.DurationSeconds != 5400 {
+		t.Errorf("unexpected migration info: %+v", report.Migration)
+	}
+
+	s := report.Summary
+	if s.VMs != 2 || s.Succeeded != 1 || s.Failed != 1 {
+		t.Errorf("unexpected summary: %+v", s)
+	}
+	if s.TransferredBytes != 2048*1024*1024 {
+		t.Errorf("TransferredBytes = %d, want 2 GiB", s.TransferredBytes)
+	}
+	if s.DiskBytes != 30*1024*1024*1024 {
+		t.Errorf("DiskBytes = %d, want 30 GiB", s.DiskBytes)
+	}
+
+	web := report.VMs[0]
+	if web.DurationSeconds != 2700 || len(web.Disks) != 2 || web.Disks[0].Name != "web-disk-0" {
+		t.Errorf("unexpected web VM report: %+v", web)
+	}
+	if db := report.VMs[1]; db.Error != "disk copy failed" || db.Result != "Failed" {
+		t.Errorf("unexpected db VM report: %+v", db)
+	}
+
+	if len(report.Warnings) != 1 || report.Warnings[0].Type != "VMNetworksNotMapped" {
+		t.Errorf("Warnings = %+v, want the network warning", report.Warnings)
+	}
+	if len(report.Failures) != 0 {
+		t.Errorf("Failures = %+v, want none", report.Failures)
+	}
+}
+
+func TestBuildWithoutMigration(t *testing.T) {
+	report := Build(reportPlan(), nil, nil, time.Now())
+	if report.Migration != nil || report.Summary.VMs != 2 || len(report.VMs) != 0 {
+		t.Errorf("unexpected report without migration: %+v", report)
+	}
+}
+
+func TestRender(t *testing.T) {
+	report := Build(reportPlan(), reportMigration(), nil, time.Date(2026, 5, 2, 0, 0, 0, 0, time.UTC))
+
+	markdown, err := Render(report, "markdown", true)
+	if err != nil {
+		t.Fatalf("Render(markdown) unexpected error: %v", err)
+	}
+	for _, want := range []string{"wave1", "disk copy failed", "2.0 GiB", "network not mapped"} {
+		if !strings.Contains(markdown, want) {
+			t.Errorf("markdown report does not contain %q", want)
+		}
+	}
+
+	html, err := Render(report, "html", true)
+	if err != nil {
+		t.Fatalf("Render(html) unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(html, "<!DOCTYPE html>") || !strings.Contains(html, `<td class="Failed">Failed</td>`) {
+		t.Errorf("unexpected HTML report:\n%s", html)
+	}
+
+	data, err := Render(report, "json", true)
+	if err != nil {
+		t.Fatalf("Render(json) unexpected error: %v", err)
+	}
+	var decoded Report
+	if err := json.Unmarshal([]byte(data), &decoded); err != nil {
+		t.Fatalf("JSON report does not decode: %v", err)
+	}
+	if decoded.Summary.Failed != 1 || len(decoded.VMs) != 2 {
+		t.Errorf("unexpected decoded report: %+v", decoded.Summary)
+	}
+
+	if _, err := Render(report, "pdf", true); err == nil {
+		t.Error("Render(pdf) expected error")
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := map[int64]string{
+		0:                  "0 B",
+		1023:               "1023 B",
+		1536:               "1.5 KiB",
+		3 * 1024 * 1024:    "3.0 MiB",
+		5 << 40:            "5.0 TiB",
+		1024 * 1024 * 1024: "1.0 GiB",
+	}
+	for bytes, want := range tests {
+		if got := FormatBytes(bytes); got != want {
+			t.Errorf("FormatBytes(%d) = %q, want %q", bytes, got, want)
+		}
+	}
+}
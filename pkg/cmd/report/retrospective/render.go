@@ -0,0 +1,186 @@
+package retrospective
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	plan "github.com/yaacov/kubectl-mtv/pkg/cmd/report/plan"
+	"github.com/yaacov/kubectl-mtv/pkg/util/client"
+	"github.com/yaacov/kubectl-mtv/pkg/util/describe"
+	"github.com/yaacov/kubectl-mtv/pkg/util/output"
+	"github.com/yaacov/kubectl-mtv/pkg/util/upload"
+)
+
+// Render formats the retrospective as table, markdown or json
+func Render(retro *Retrospective, format string, useUTC bool) (string, error) {
+	switch strings.ToLower(format) {
+	case "", "table":
+		return describe.Format(toDescription(retro, useUTC), "table")
+	case "markdown", "md":
+		return describe.Format(toDescription(retro, useUTC), "markdown")
+	case "json":
+		data, err := json.MarshalIndent(retro, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("failed to encode retrospective: %v", err)
+		}
+		return string(data) + "\n", nil
+	default:
+		return "", fmt.Errorf("unsupported output format: %s. Supported formats: table, markdown, json", format)
+	}
+}
+
+// toDescription converts the retrospective to a description for table and markdown output
+func toDescription(retro *Retrospective, useUTC bool) *describe.Description {
+	b := describe.NewBuilder("MIGRATION RETROSPECTIVE")
+	scope := retro.Namespace
+	if scope == "" {
+		scope = "all namespaces"
+	}
+	b.Field("Namespace", scope)
+	b.Field("Since", output.FormatTimestamp(retro.Since, useUTC))
+	b.Field("Migrations", fmt.Sprintf("%d", retro.Migrations))
+	b.Field("VMs", fmt.Sprintf("%d", retro.VMs))
+	b.FieldC("Succeeded", fmt.Sprintf("%d", retro.Succeeded), output.Green)
+	if retro.Failed > 0 {
+		b.FieldC("Failed", fmt.Sprintf("%d", retro.Failed), output.Red)
+	} else {
+		b.Field("Failed", "0")
+	}
+	b.Field("Canceled", fmt.Sprintf("%d", retro.Canceled))
+
+	if retro.VMs == 0 {
+		return b.Build()
+	}
+
+	b.Section("DURATION BY VM SIZE")
+	if len(retro.DurationBySize) == 0 {
+		b.Field("Durations", "no succeeded VMs")
+	} else {
+		rows := []map[string]string{}
+		for _, s := range retro.DurationBySize {
+			rows = append(rows, map[string]string{
+				"bucket": s.Bucket,
+				"vms":    fmt.Sprintf("%d", s.VMs),
+				"median": formatSeconds(s.MedianDurationSeconds),
+				"max":    formatSeconds(s.MaxDurationSeconds),
+			})
+		}
+		b.Table([]describe.TableColumn{
+			{Display: "SIZE", Key: "bucket"},
+			{Display: "VMS", Key: "vms"},
+			{Display: "MEDIAN", Key: "median"},
+			{Display: "MAX", Key: "max"},
+		}, rows)
+	}
+
+	if len(retro.FailureCauses) > 0 {
+		b.Section("FAILURE CAUSES")
+		rows := []map[string]string{}
+		for _, c := range retro.FailureCauses {
+			rows = append(rows, map[string]string{
+				"count": fmt.Sprintf("%d", c.Count),
+				"cause": c.Cause,
+				"vms":   exampleVMs(c.VMs, 3),
+			})
+		}
+		b.Table([]describe.TableColumn{
+			{Display: "COUNT", Key: "count"},
+			{Display: "CAUSE", Key: "cause"},
+			{Display: "VMS", Key: "vms"},
+		}, rows)
+	}
+
+	if len(retro.Throughput) > 0 {
+		b.Section("THROUGHPUT BY STORAGE CLASS")
+		rows := []map[string]string{}
+		for _, t := range retro.Throughput {
+			rows = append(rows, map[string]string{
+				"class": t.StorageClass,
+				"vms":   fmt.Sprintf("%d", t.VMs),
+				"p50":   formatRate(t.P50),
+				"p90":   formatRate(t.P90),
+				"max":   formatRate(t.Max),
+			})
+		}
+		b.Table([]describe.TableColumn{
+			{Display: "STORAGE CLASS", Key: "class"},
+			{Display: "VMS", Key: "vms"},
+			{Display: "P50", Key: "p50"},
+			{Display: "P90", Key: "p90"},
+			{Display: "MAX", Key: "max"},
+		}, rows)
+	}
+
+	return b.Build()
+}
+
+// exampleVMs lists up to limit VM names, noting how many more there are
+func exampleVMs(vms []string, limit int) string {
+	if len(vms) <= limit {
+		return strings.Join(vms, ", ")
+	}
+	return fmt.Sprintf("%s (+%d more)", strings.Join(vms[:limit], ", "), len(vms)-limit)
+}
+
+// formatRate returns a transfer rate as text, e.g. "120.0 MiB/s"
+func formatRate(bytesPerSecond float64) string {
+	return plan.FormatBytes(int64(bytesPerSecond)) + "/s"
+}
+
+// formatSeconds returns a duration in seconds as text, or "-" when unknown
+func formatSeconds(seconds int64) string {
+	if seconds <= 0 {
+		return "-"
+	}
+	return (time.Duration(seconds) * time.Second).String()
+}
+
+// fileExtension returns the file extension of a retrospective rendered in the given format
+func fileExtension(format string) string {
+	switch strings.ToLower(format) {
+	case "markdown", "md":
+		return "md"
+	case "json":
+		return "json"
+	default:
+		return "txt"
+	}
+}
+
+// Print aggregates the migrations completed within the last since duration and prints the
+// retrospective, or uploads it instead when an uploader is given
+func Print(ctx context.Context, configFlags *genericclioptions.ConfigFlags, namespace string, since time.Duration, format string, uploader *upload.S3Uploader, useUTC bool) error {
+	c, err := client.GetDynamicClient(configFlags)
+	if err != nil {
+		return fmt.Errorf("failed to get client: %v", err)
+	}
+
+	now := time.Now()
+	retro, err := Generate(ctx, c, namespace, now.Add(-since))
+	if err != nil {
+		return err
+	}
+	content, err := Render(retro, format, useUTC)
+	if err != nil {
+		return err
+	}
+
+	if uploader != nil {
+		name := fmt.Sprintf("retrospective-%s.%s", now.UTC().Format("20060102T150405Z"), fileExtension(format))
+		objectURL, err := uploader.UploadNamed(ctx, []byte(content), name)
+		if err != nil {
+			return fmt.Errorf("failed to upload retrospective: %v", err)
+		}
+		fmt.Fprintf(os.Stderr, "Retrospective uploaded to %s\n", objectURL)
+		return nil
+	}
+
+	fmt.Print(content)
+	return nil
+}
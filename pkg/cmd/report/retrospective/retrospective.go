@@ -0,0 +1,399 @@
+package retrospective
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/get/plan/status"
+	"github.com/yaacov/kubectl-mtv/pkg/util/client"
+	"github.com/yaacov/kubectl-mtv/pkg/util/fields"
+)
+
+const gib = int64(1024 * 1024 * 1024)
+
+// SizeBucket is a range of VM disk sizes, the upper bound is exclusive (0 means unbounded)
+type SizeBucket struct {
+	Label    string
+	MinBytes int64
+	MaxBytes int64
+}
+
+// SizeBuckets are the VM size ranges used to compare migration durations
+var SizeBuckets = []SizeBucket{
+	{Label: "< 50 GiB", MinBytes: 0, MaxBytes: 50 * gib},
+	{Label: "50-200 GiB", MinBytes: 50 * gib, MaxBytes: 200 * gib},
+	{Label: "200 GiB-1 TiB", MinBytes: 200 * gib, MaxBytes: 1024 * gib},
+	{Label: ">= 1 TiB", MinBytes: 1024 * gib},
+}
+
+// UnknownStorageClass is used when the storage class of a VM cannot be resolved from its plan
+const UnknownStorageClass = "unknown"
+
+// Retrospective aggregates the VMs of the migrations completed in a time window
+type Retrospective struct {
+	GeneratedAt    time.Time         `json:"generatedAt"`
+	Since          time.Time         `json:"since"`
+	Namespace      string            `json:"namespace,omitempty"`
+	Migrations     int               `json:"migrations"`
+	VMs            int               `json:"vms"`
+	Succeeded      int               `json:"succeeded"`
+	Failed         int               `json:"failed"`
+	Canceled       int               `json:"canceled"`
+	DurationBySize []SizeStats       `json:"durationBySize"`
+	FailureCauses  []FailureCause    `json:"failureCauses"`
+	Throughput     []ThroughputStats `json:"throughput"`
+}
+
+// SizeStats is the duration of the succeeded VMs of a size bucket
+type SizeStats struct {
+	Bucket string `json:"bucket"`
+	VMs    int    `json:"vms"`
+	// MedianDurationSeconds is the median VM migration duration
+	MedianDurationSeconds int64 `json:"medianDurationSeconds"`
+	MaxDurationSeconds    int64 `json:"maxDurationSeconds"`
+}
+
+// FailureCause is a failure reason and the VMs that failed with it
+type FailureCause struct {
+	Cause string   `json:"cause"`
+	Count int      `json:"count"`
+	VMs   []string `json:"vms"`
+}
+
+// ThroughputStats are the disk transfer rates of the VMs migrated to a storage class, in bytes per second
+type ThroughputStats struct {
+	StorageClass string  `json:"storageClass"`
+	VMs          int     `json:"vms"`
+	P50          float64 `json:"p50BytesPerSecond"`
+	P90          float64 `json:"p90BytesPerSecond"`
+	Max          float64 `json:"maxBytesPerSecond"`
+}
+
+// vmSample is the retrospective data of a single finished VM
+type vmSample struct {
+	name            string
+	result          string
+	durationSeconds int64
+	sizeBytes       int64
+	// bytesPerSecond is the disk transfer rate, 0 when it cannot be computed
+	bytesPerSecond float64
+	storageClass   string
+	cause          string
+}
+
+// Generate lists the migrations, plans and storage maps of namespace (all namespaces when empty)
+// and aggregates the migrations completed since the given time
+func Generate(ctx context.Context, c dynamic.Interface, namespace string, since time.Time) (*Retrospective, error) {
+	migrations, err := c.Resource(client.MigrationsGVR).Namespace(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list migrations: %v", err)
+	}
+	plans, err := c.Resource(client.PlansGVR).Namespace(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list plans: %v", err)
+	}
+	storageMaps, err := c.Resource(client.StorageMapGVR).Namespace(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list storage maps: %v", err)
+	}
+
+	retro := Build(migrations.Items, StorageClassesByPlan(plans.Items, storageMaps.Items), since, time.Now())
+	retro.Namespace = namespace
+	return retro, nil
+}
+
+// StorageClassesByPlan returns the destination storage classes of each plan storage map,
+// keyed by "namespace/plan". Several classes are joined with "+".
+func StorageClassesByPlan(plans, storageMaps []unstructured.Unstructured) map[string]string {
+	classesByMap := map[string]string{}
+	for i := range storageMaps {
+		m := &storageMaps[i]
+		classes := []string{}
+		seen := map[string]bool{}
+		entries, _, _ := unstructured.NestedSlice(m.Object, "spec", "map")
+		for _, e := range entries {
+			entry, ok := e.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			class, _, _ := unstructured.NestedString(entry, "destination", "storageClass")
+			if class != "" && !seen[class] {
+				seen[class] = true
+				classes = append(classes, class)
+			}
+		}
+		sort.Strings(classes)
+		if len(classes) > 0 {
+			classesByMap[m.GetNamespace()+"/"+m.GetName()] = strings.Join(classes, "+")
+		}
+	}
+
+	classesByPlan := map[string]string{}
+	for i := range plans {
+		p := &plans[i]
+		name, _, _ := unstructured.NestedString(p.Object, "spec", "map", "storage", "name")
+		namespace, _, _ := unstructured.NestedString(p.Object, "spec", "map", "storage", "namespace")
+		if namespace == "" {
+			namespace = p.GetNamespace()
+		}
+		if class, ok := classesByMap[namespace+"/"+name]; ok {
+			classesByPlan[p.GetNamespace()+"/"+p.GetName()] = class
+		}
+	}
+	return classesByPlan
+}
+
+// Build aggregates the VMs of the migrations completed between since and now. storageClasses
+// maps "namespace/plan" to the storage class reported for the VMs of the plan.
+func Build(migrations []unstructured.Unstructured, storageClasses map[string]string, since, now time.Time) *Retrospective {
+	retro := &Retrospective{
+		GeneratedAt:    now,
+		Since:          since,
+		DurationBySize: []SizeStats{},
+		FailureCauses:  []FailureCause{},
+		Throughput:     []ThroughputStats{},
+	}
+
+	samples := []vmSample{}
+	for i := range migrations {
+		m := &migrations[i]
+		completed := fields.Time(m.Object, "status", "completed")
+		if completed == nil || completed.Before(since) || completed.After(now) {
+			continue
+		}
+		retro.Migrations++
+
+		planName, _, _ := unstructured.NestedString(m.Object, "spec", "plan", "name")
+		planNamespace, _, _ := unstructured.NestedString(m.Object, "spec", "plan", "namespace")
+		if planNamespace == "" {
+			planNamespace = m.GetNamespace()
+		}
+		storageClass := storageClasses[planNamespace+"/"+planName]
+		if storageClass == "" {
+			storageClass = UnknownStorageClass
+		}
+
+		vms, _, _ := unstructured.NestedSlice(m.Object, "status", "vms")
+		for _, v := range vms {
+			vm, ok := v.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			sample, ok := buildSample(vm, storageClass)
+			if !ok {
+				continue
+			}
+			sample.name = fmt.Sprintf("%s/%s", planName, sample.name)
+			samples = append(samples, sample)
+		}
+	}
+
+	retro.VMs = len(samples)
+	for _, s := range samples {
+		switch s.result {
+		case status.StatusSucceeded:
+			retro.Succeeded++
+		case status.StatusFailed:
+			retro.Failed++
+		case status.StatusCanceled:
+			retro.Canceled++
+		}
+	}
+	retro.DurationBySize = durationBySize(samples)
+	retro.FailureCauses = failureCauses(samples)
+	retro.Throughput = throughput(samples)
+	return retro
+}
+
+// buildSample reads a finished status.vms entry, VMs that did not finish are skipped
+func buildSample(vm map[string]interface{}, storageClass string) (vmSample, bool) {
+	sample := vmSample{
+		name:         fields.String(vm, "name"),
+		result:       conditionResult(vm),
+		storageClass: storageClass,
+	}
+	if sample.result == "" {
+		return sample, false
+	}
+	if sample.name == "" {
+		sample.name = fields.String(vm, "id")
+	}
+	started := fields.Time(vm, "started")
+	completed := fields.Time(vm, "completed")
+	if started != nil && completed != nil {
+		sample.durationSeconds = int64(completed.Sub(*started) / time.Second)
+	}
+
+	var transferred int64
+	var transferSeconds float64
+	pipeline, _, _ := unstructured.NestedSlice(vm, "pipeline")
+	for _, p := range pipeline {
+		step, ok := p.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if strings.HasPrefix(fields.String(step, "name"), "DiskTransfer") {
+			unit := fields.String(step, "annotations", "unit")
+			total, _, _ := unstructured.NestedInt64(step, "progress", "total")
+			done, _, _ := unstructured.NestedInt64(step, "progress", "completed")
+			sample.sizeBytes += status.ProgressBytes(total, unit)
+			transferred += status.ProgressBytes(done, unit)
+			stepStarted := fields.Time(step, "started")
+			stepCompleted := fields.Time(step, "completed")
+			if stepStarted != nil && stepCompleted != nil {
+				transferSeconds += stepCompleted.Sub(*stepStarted).Seconds()
+			}
+		}
+		if reasons := fields.ErrorReasons(step); reasons != "" && sample.cause == "" {
+			sample.cause = fmt.Sprintf("%s: %s", fields.String(step, "name"), reasons)
+		}
+	}
+	if reasons := fields.ErrorReasons(vm); reasons != "" {
+		sample.cause = reasons
+	}
+
+	// Without DiskTransfer timestamps, the whole VM duration is the transfer time
+	if transferSeconds == 0 {
+		transferSeconds = float64(sample.durationSeconds)
+	}
+	if sample.result == status.StatusSucceeded && transferred > 0 && transferSeconds > 0 {
+		sample.bytesPerSecond = float64(transferred) / transferSeconds
+	}
+	return sample, true
+}
+
+// durationBySize returns the median duration of the succeeded VMs of each size bucket
+func durationBySize(samples []vmSample) []SizeStats {
+	stats := []SizeStats{}
+	for _, bucket := range SizeBuckets {
+		durations := []float64{}
+		for _, s := range samples {
+			if s.result != status.StatusSucceeded || s.durationSeconds <= 0 {
+				continue
+			}
+			if s.sizeBytes < bucket.MinBytes || (bucket.MaxBytes > 0 && s.sizeBytes >= bucket.MaxBytes) {
+				continue
+			}
+			durations = append(durations, float64(s.durationSeconds))
+		}
+		if len(durations) == 0 {
+			continue
+		}
+		sort.Float64s(durations)
+		stats = append(stats, SizeStats{
+			Bucket:                bucket.Label,
+			VMs:                   len(durations),
+			MedianDurationSeconds: int64(Median(durations)),
+			MaxDurationSeconds:    int64(durations[len(durations)-1]),
+		})
+	}
+	return stats
+}
+
+// failureCauses ranks the failure reasons of the failed VMs, most frequent first
+func failureCauses(samples []vmSample) []FailureCause {
+	byCause := map[string]*FailureCause{}
+	for _, s := range samples {
+		if s.result != status.StatusFailed {
+			continue
+		}
+		cause := s.cause
+		if cause == "" {
+			cause = "unknown"
+		}
+		if byCause[cause] == nil {
+			byCause[cause] = &FailureCause{Cause: cause}
+		}
+		byCause[cause].Count++
+		byCause[cause].VMs = append(byCause[cause].VMs, s.name)
+	}
+
+	causes := []FailureCause{}
+	for _, c := range byCause {
+		sort.Strings(c.VMs)
+		causes = append(causes, *c)
+	}
+	sort.Slice(causes, func(i, j int) bool {
+		if causes[i].Count != causes[j].Count {
+			return causes[i].Count > causes[j].Count
+		}
+		return causes[i].Cause < causes[j].Cause
+	})
+	return causes
+}
+
+// throughput returns the disk transfer rate percentiles of the succeeded VMs per storage class
+func throughput(samples []vmSample) []ThroughputStats {
+	rates := map[string][]float64{}
+	for _, s := range samples {
+		if s.bytesPerSecond > 0 {
+			rates[s.storageClass] = append(rates[s.storageClass], s.bytesPerSecond)
+		}
+	}
+
+	stats := []ThroughputStats{}
+	for class, values := range rates {
+		sort.Float64s(values)
+		stats = append(stats, ThroughputStats{
+			StorageClass: class,
+			VMs:          len(values),
+			P50:          Percentile(values, 50),
+			P90:          Percentile(values, 90),
+			Max:          values[len(values)-1],
+		})
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].StorageClass < stats[j].StorageClass })
+	return stats
+}
+
+// Median returns the median of sorted values, 0 when empty
+func Median(sorted []float64) float64 {
+	n := len(sorted)
+	if n == 0 {
+		return 0
+	}
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
+
+// Percentile returns the nearest-rank percentile p (0-100) of sorted values, 0 when empty
+func Percentile(sorted []float64, p float64) float64 {
+	n := len(sorted)
+	if n == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(float64(n) * p / 100))
+	if rank < 1 {
+		rank = 1
+	}
+	if rank > n {
+		rank = n
+	}
+	return sorted[rank-1]
+}
+
+// conditionResult returns Succeeded, Failed or Canceled from the true conditions of a VM, if set
+func conditionResult(vm map[string]interface{}) string {
+	conditions, _, _ := unstructured.NestedSlice(vm, "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok || fields.String(condition, "status") != "True" {
+			continue
+		}
+		switch condType := fields.String(condition, "type"); condType {
+		case status.StatusSucceeded, status.StatusFailed, status.StatusCanceled:
+			return condType
+		}
+	}
+	return ""
+}
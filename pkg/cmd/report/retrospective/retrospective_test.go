@@ -0,0 +1,159 @@
+package retrospective
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func retroVM(name, result string, minutes int, sizeGB int64, reason string) interface{} {
+	vm := map[string]interface{}{
+		"name":       name,
+		"started":    "2026-05-01T10:00:00Z",
+		"completed":  time.Date(2026, 5, 1, 10, minutes, 0, 0, time.UTC).Format(time.RFC3339),
+		"conditions": []interface{}{map[string]interface{}{"type": result, "status": "True"}},
+		"pipeline": []interface{}{
+			map[string]interface{}{
+				"name":        "DiskTransfer",
+				"progress":    map[string]interface{}{"completed": sizeGB * 1024, "total": sizeGB * 1024},
+				"annotations": map[string]interface{}{"unit": "MB"},
+			},
+		},
+	}
+	if reason != "" {
+		vm["error"] = map[string]interface{}{"reasons": []interface{}{reason}}
+	}
+	return vm
+}
+
+func retroMigration(plan, completed string, vms ...interface{}) unstructured.Unstructured {
+	return unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{"name": plan + "-abc", "namespace": "mtv"},
+		"spec":     map[string]interface{}{"plan": map[string]interface{}{"name": plan, "namespace": "mtv"}},
+		"status":   map[string]interface{}{"completed": completed, "vms": vms},
+	}}
+}
+
+func TestBuild(t *testing.T) {
+	now := time.Date(2026, 5, 10, 0, 0, 0, 0, time.UTC)
+	migrations := []unstructured.Unstructured{
+		retroMigration("wave1", "2026-05-01T12:00:00Z",
+			retroVM("web", "Succeeded", 10, 10, ""),
+			retroVM("app", "Succeeded", 30, 20, ""),
+			retroVM("db", "Succeeded", 59, 100, ""),
+			retroVM("bad1", "Failed", 5, 10, "disk copy failed"),
+			retroVM("bad2", "Failed", 5, 10, "disk copy failed"),
+			retroVM("bad3", "Failed", 5, 10, "guest conversion failed"),
+		),
+		// Completed before the window
+		retroMigration("wave0", "2026-04-01T12:00:00Z", retroVM("old", "Succeeded", 10, 10, "")),
+		// Still running
+		retroMigration("wave2", "", retroVM("running", "Succeeded", 10, 10, "")),
+	}
+	classes := map[string]string{"mtv/wave1": "ceph-rbd"}
+	retro := Build(migrations, classes, now.Add(-30*24*time.Hour), now)
+
+	if retro.Migrations != 1 || retro.VMs != 6 || retro.Succeeded != 3 || retro.Failed != 3 {
+		t.Fatalf("unexpected totals: %+v", retro)
+	}
+
+	if len(retro.DurationBySize) != 2 {
+		t.Fatalf("DurationBySize = %+v, want two buckets", retro.DurationBySize)
+	}
+	if small := retro.DurationBySize[0]; small.VMs != 2 || small.MedianDurationSeconds != 20*60 || small.MaxDurationSeconds != 30*60 {
+		t.Errorf("unexpected small bucket: %+v", small)
+	}
+	if medium := retro.DurationBySize[1]; medium.Bucket != "50-200 GiB" || medium.VMs != 1 {
+		t.Errorf("unexpected medium bucket: %+v", medium)
+	}
+
+	if len(retro.FailureCauses) != 2 || retro.FailureCauses[0].Cause != "disk copy failed" || retro.FailureCauses[0].Count != 2 {
+		t.Errorf("unexpected failure causes: %+v", retro.FailureCauses)
+	}
+	if vms := retro.FailureCauses[0].VMs; len(vms) != 2 || vms[0] != "wave1/bad1" {
+		t.Errorf("unexpected failure cause VMs: %v", vms)
+	}
+
+	if len(retro.Throughput) != 1 || retro.Throughput[0].StorageClass != "ceph-rbd" || retro.Throughput[0].VMs != 3 {
+		t.Fatalf("unexpected throughput: %+v", retro.Throughput)
+	}
+	// web: 10 GiB in 10 minutes, app: 20 GiB in 30 minutes, db: 100 GiB in 59 minutes
+	if p50 := retro.Throughput[0].P50; int64(p50) != 10*gib/(10*60) {
+		t.Errorf("P50 = %v, want the web VM rate", p50)
+	}
+}
+
+func TestStorageClassesByPlan(t *testing.T) {
+	plans := []unstructured.Unstructured{{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "wave1", "namespace": "mtv"},
+		"spec":     map[string]interface{}{"map": map[string]interface{}{"storage": map[string]interface{}{"name": "storage"}}},
+	}}}
+	storageMaps := []unstructured.Unstructured{{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "storage", "namespace": "mtv"},
+		"spec": map[string]interface{}{"map": []interface{}{
+			map[string]interface{}{"destination": map[string]interface{}{"storageClass": "odf"}},
+			map[string]interface{}{"destination": map[string]interface{}{"storageClass": "ceph-rbd"}},
+			map[string]interface{}{"destination": map[string]interface{}{"storageClass": "odf"}},
+		}},
+	}}}
+	got := StorageClassesByPlan(plans, storageMaps)
+	if got["mtv/wave1"] != "ceph-rbd+odf" {
+		t.Errorf("StorageClassesByPlan() = %v", got)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	values := []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	if got := Percentile(values, 50); got != 5 {
+		t.Errorf("Percentile(50) = %v, want 5", got)
+	}
+	if got := Percentile(values, 90); got != 9 {
+		t.Errorf("Percentile(90) = %v, want 9", got)
+	}
+	if got := Median(values); got != 5.5 {
+		t.Errorf("Median() = %v, want 5.5", got)
+	}
+	if Percentile(nil, 50) != 0 || Median(nil) != 0 {
+		t.Error("expected 0 for empty values")
+	}
+}
+
+func TestRender(t *testing.T) {
+	now := time.Date(2026, 5, 10, 0, 0, 0, 0, time.UTC)
+	migrations := []unstructured.Unstructured{
+		retroMigration("wave1", "2026-05-01T12:00:00Z",
+			retroVM("web", "Succeeded", 10, 10, ""),
+			retroVM("db", "Failed", 5, 10, "disk copy failed"),
+		),
+	}
+	retro := Build(migrations, nil, now.Add(-30*24*time.Hour), now)
+
+	markdown, err := Render(retro, "markdown", true)
+	if err != nil {
+		t.Fatalf("Render(markdown) unexpected error: %v", err)
+	}
+	for _, want := range []string{"DURATION BY VM SIZE", "disk copy failed", UnknownStorageClass, "10m0s"} {
+		if !strings.Contains(markdown, want) {
+			t.Errorf("markdown retrospective does not contain %q:\n%s", want, markdown)
+		}
+	}
+
+	data, err := Render(retro, "json", true)
+	if err != nil {
+		t.Fatalf("Render(json) unexpected error: %v", err)
+	}
+	var decoded Retrospective
+	if err := json.Unmarshal([]byte(data), &decoded); err != nil {
+		t.Fatalf("JSON retrospective does not decode: %v", err)
+	}
+	if decoded.Failed != 1 || len(decoded.FailureCauses) != 1 {
+		t.Errorf("unexpected decoded retrospective: %+v", decoded)
+	}
+
+	if _, err := Render(retro, "html", true); err == nil {
+		t.Error("Render(html) expected error")
+	}
+}
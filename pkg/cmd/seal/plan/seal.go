@@ -0,0 +1,140 @@
+package plan
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/yaacov/kubectl-mtv/pkg/util/client"
+)
+
+const (
+	// SealAnnotation holds the checksum of the plan spec approved by seal plan
+	SealAnnotation = "kubectl-mtv/sealed-spec"
+	// SealedAtAnnotation records when the plan was sealed
+	SealedAtAnnotation = "kubectl-mtv/sealed-at"
+)
+
+// checksumPrefix names the hash of the stored checksum
+const checksumPrefix = "sha256:"
+
+// SpecChecksum returns the checksum of the plan spec. Map keys are encoded sorted,
+// so the checksum only changes when the spec content does.
+func SpecChecksum(plan *unstructured.Unstructured) (string, error) {
+	spec, _, _ := unstructured.NestedMap(plan.Object, "spec")
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode plan spec: %v", err)
+	}
+	sum := sha256.Sum256(data)
+	return checksumPrefix + hex.EncodeToString(sum[:]), nil
+}
+
+// Seal stores the checksum of the current spec of a plan, or removes it when unseal is set
+func Seal(ctx context.Context, configFlags *genericclioptions.ConfigFlags, name, namespace string, unseal bool, out io.Writer) error {
+	c, err := client.GetDynamicClient(configFlags)
+	if err != nil {
+		return fmt.Errorf("failed to get client: %v", err)
+	}
+	return seal(ctx, c, name, namespace, unseal, time.Now(), out)
+}
+
+func seal(ctx context.Context, c dynamic.Interface, name, namespace string, unseal bool, now time.Time, out io.Writer) error {
+	plan, err := c.Resource(client.PlansGVR).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get plan '%s': %v", name, err)
+	}
+
+	// A null annotation value removes it in a merge patch
+	annotations := map[string]interface{}{SealAnnotation: nil, SealedAtAnnotation: nil}
+	checksum := ""
+	if !unseal {
+		checksum, err = SpecChecksum(plan)
+		if err != nil {
+			return err
+		}
+		annotations[SealAnnotation] = checksum
+		annotations[SealedAtAnnotation] = now.UTC().Format(time.RFC3339)
+	}
+
+	patchBytes, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{"annotations": annotations},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create patch: %v", err)
+	}
+	if _, err := c.Resource(client.PlansGVR).Namespace(namespace).Patch(ctx, name, types.MergePatchType, patchBytes, metav1.PatchOptions{}); err != nil {
+		return fmt.Errorf("failed to update plan: %v", err)
+	}
+
+	if unseal {
+		fmt.Fprintf(out, "Plan '%s' unsealed\n", name)
+	} else {
+		fmt.Fprintf(out, "Plan '%s' sealed (%s)\n", name, checksum)
+	}
+	return nil
+}
+
+// SealStatus reports whether a plan is sealed and whether its spec changed since
+type SealStatus struct {
+	Sealed   bool
+	Drifted  bool
+	SealedAt string
+}
+
+// CheckSeal compares the spec of a plan with the checksum stored when it was sealed
+func CheckSeal(plan *unstructured.Unstructured) (SealStatus, error) {
+	annotations := plan.GetAnnotations()
+	stored, ok := annotations[SealAnnotation]
+	if !ok {
+		return SealStatus{}, nil
+	}
+
+	checksum, err := SpecChecksum(plan)
+	if err != nil {
+		return SealStatus{}, err
+	}
+	return SealStatus{Sealed: true, Drifted: checksum != stored, SealedAt: annotations[SealedAtAnnotation]}, nil
+}
+
+// Verify checks a plan for spec changes since it was sealed before it is started:
+// a changed spec prints a warning to out, or fails when strict is set
+func Verify(ctx context.Context, configFlags *genericclioptions.ConfigFlags, name, namespace string, strict bool, out io.Writer) error {
+	c, err := client.GetDynamicClient(configFlags)
+	if err != nil {
+		return fmt.Errorf("failed to get client: %v", err)
+	}
+	return verify(ctx, c, name, namespace, strict, out)
+}
+
+func verify(ctx context.Context, c dynamic.Interface, name, namespace string, strict bool, out io.Writer) error {
+	plan, err := c.Resource(client.PlansGVR).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get plan '%s': %v", name, err)
+	}
+
+	status, err := CheckSeal(plan)
+	if err != nil || !status.Drifted {
+		return err
+	}
+
+	msg := fmt.Sprintf("the spec of plan '%s' changed since it was sealed", name)
+	if status.SealedAt != "" {
+		msg = fmt.Sprintf("%s at %s", msg, status.SealedAt)
+	}
+	if strict {
+		return fmt.Errorf("%s; review the changes and run 'seal plan %s' again to approve them", msg, name)
+	}
+	fmt.Fprintf(out, "Warning: %s\n", msg)
+	return nil
+}
@@ -0,0 +1,92 @@
+package plan
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/yaacov/kubectl-mtv/pkg/util/client"
+)
+
+func testPlan() *unstructured.Unstructured {
+	p := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "forklift.konveyor.io/v1beta1",
+		"kind":       "Plan",
+		"spec": map[string]interface{}{
+			"targetNamespace": "apps",
+			"vms":             []interface{}{map[string]interface{}{"name": "web"}},
+		},
+	}}
+	p.SetName("wave7")
+	p.SetNamespace("demo")
+	return p
+}
+
+func TestSealAndVerify(t *testing.T) {
+	ctx := context.Background()
+	c := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		client.PlansGVR: "PlanList",
+	}, testPlan())
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	var out bytes.Buffer
+	if err := verify(ctx, c, "wave7", "demo", true, &out); err != nil || out.Len() != 0 {
+		t.Fatalf("verify() of an unsealed plan = %v, %q, want no error and no warning", err, out.String())
+	}
+
+	if err := seal(ctx, c, "wave7", "demo", false, now, &out); err != nil {
+		t.Fatalf("seal() unexpected error: %v", err)
+	}
+	if !strings.Contains(out.String(), "sealed (sha256:") {
+		t.Errorf("seal() output = %q, want the checksum", out.String())
+	}
+	out.Reset()
+	if err := verify(ctx, c, "wave7", "demo", true, &out); err != nil || out.Len() != 0 {
+		t.Fatalf("verify() of an unchanged sealed plan = %v, %q, want no error and no warning", err, out.String())
+	}
+
+	patch := []byte(`{"spec":{"targetNamespace":"other"}}`)
+	if _, err := c.Resource(client.PlansGVR).Namespace("demo").Patch(ctx, "wave7", types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := verify(ctx, c, "wave7", "demo", false, &out); err != nil {
+		t.Fatalf("verify() without strict unexpected error: %v", err)
+	}
+	if !strings.Contains(out.String(), "Warning: the spec of plan 'wave7' changed since it was sealed at 2026-10-01T12:00:00Z") {
+		t.Errorf("verify() warning = %q", out.String())
+	}
+	if err := verify(ctx, c, "wave7", "demo", true, &out); err == nil || !strings.Contains(err.Error(), "seal plan wave7") {
+		t.Errorf("verify() with strict error = %v, want a refusal suggesting to seal again", err)
+	}
+
+	if err := seal(ctx, c, "wave7", "demo", true, now, &out); err != nil {
+		t.Fatalf("seal() unseal unexpected error: %v", err)
+	}
+	p, err := c.Resource(client.PlansGVR).Namespace("demo").Get(ctx, "wave7", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status, _ := CheckSeal(p); status.Sealed {
+		t.Errorf("plan still sealed after unseal: %v", p.GetAnnotations())
+	}
+}
+
+func TestSpecChecksumIgnoresMetadata(t *testing.T) {
+	a, b := testPlan(), testPlan()
+	b.SetLabels(map[string]string{"wave": "7"})
+	sumA, _ := SpecChecksum(a)
+	sumB, _ := SpecChecksum(b)
+	if sumA != sumB {
+		t.Errorf("checksums differ for the same spec: %s != %s", sumA, sumB)
+	}
+}
@@ -0,0 +1,92 @@
+package settings
+
+import (
+	"fmt"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/yaacov/kubectl-mtv/pkg/util/config"
+)
+
+// LocalSetting is a command default saved for a kubeconfig context in the local settings file
+type LocalSetting struct {
+	Name        string `json:"name" yaml:"name"`
+	Value       string `json:"value,omitempty" yaml:"value,omitempty"`
+	IsSet       bool   `json:"isSet" yaml:"isSet"`
+	Description string `json:"description" yaml:"description"`
+}
+
+// LocalContext returns the kubeconfig context the local settings are saved for
+func LocalContext(configFlags *genericclioptions.ConfigFlags) (string, error) {
+	kubeContext := config.CurrentContext(configFlags)
+	if kubeContext == "" {
+		return "", fmt.Errorf("no kubeconfig context is selected, use --context to choose the context of the local settings")
+	}
+	return kubeContext, nil
+}
+
+// GetLocalSettings returns the local settings of the kubeconfig context, or only the named one
+func GetLocalSettings(configFlags *genericclioptions.ConfigFlags, name string) (string, []LocalSetting, error) {
+	kubeContext, err := LocalContext(configFlags)
+	if err != nil {
+		return "", nil, err
+	}
+	if name != "" && config.LookupDefault(name) == nil {
+		return "", nil, fmt.Errorf("unknown local setting '%s'", name)
+	}
+	file, err := config.LoadDefaults()
+	if err != nil {
+		return "", nil, err
+	}
+
+	values := file.Values(kubeContext)
+	var result []LocalSetting
+	for _, def := range config.Defaults {
+		if name != "" && def.Name != name {
+			continue
+		}
+		value, ok := values[def.Name]
+		result = append(result, LocalSetting{Name: def.Name, Value: value, IsSet: ok, Description: def.Description})
+	}
+	return kubeContext, result, nil
+}
+
+// SetLocalSettings saves local settings for the kubeconfig context; nothing is saved when a value is invalid
+func SetLocalSettings(configFlags *genericclioptions.ConfigFlags, names, values []string) (string, error) {
+	if len(names) != len(values) {
+		return "", fmt.Errorf("number of --setting flags (%d) must match number of --value flags (%d)", len(names), len(values))
+	}
+	kubeContext, err := LocalContext(configFlags)
+	if err != nil {
+		return "", err
+	}
+	file, err := config.LoadDefaults()
+	if err != nil {
+		return "", err
+	}
+	for i, name := range names {
+		if err := file.Set(kubeContext, name, values[i]); err != nil {
+			return "", err
+		}
+	}
+	return kubeContext, file.Save()
+}
+
+// UnsetLocalSetting removes a local setting of the kubeconfig context, reporting whether it was saved
+func UnsetLocalSetting(configFlags *genericclioptions.ConfigFlags, name string) (string, bool, error) {
+	kubeContext, err := LocalContext(configFlags)
+	if err != nil {
+		return "", false, err
+	}
+	if config.LookupDefault(name) == nil {
+		return "", false, fmt.Errorf("unknown local setting '%s'", name)
+	}
+	file, err := config.LoadDefaults()
+	if err != nil {
+		return "", false, err
+	}
+	if !file.Unset(kubeContext, name) {
+		return kubeContext, false, nil
+	}
+	return kubeContext, true, file.Save()
+}
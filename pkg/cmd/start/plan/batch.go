@@ -0,0 +1,246 @@
+package plan
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/klog/v2"
+
+	planstatus "github.com/yaacov/kubectl-mtv/pkg/cmd/get/plan/status"
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/settings"
+	"github.com/yaacov/kubectl-mtv/pkg/util/client"
+)
+
+// MaxConcurrentVMsAnnotation records the per-plan VM concurrency set with --max-concurrent-vms.
+// Forklift has no plan-level limit, so kubectl-mtv enforces it by migrating the plan in batches.
+const MaxConcurrentVMsAnnotation = "kubectl-mtv/max-concurrent-vms"
+
+// maxVMInflightSetting is the ForkliftController setting limiting concurrent VM migrations cluster-wide
+const maxVMInflightSetting = "controller_max_vm_inflight"
+
+// batchPollInterval is how often a running batch is checked for completion
+const batchPollInterval = 15 * time.Second
+
+// MaxConcurrentVMs returns the VM concurrency recorded on the plan, or 0 when none is set
+func MaxConcurrentVMs(plan *unstructured.Unstructured) int {
+	value := plan.GetAnnotations()[MaxConcurrentVMsAnnotation]
+	if value == "" {
+		return 0
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		return 0
+	}
+	return n
+}
+
+// ValidateMaxConcurrentVMs checks that n is positive and does not exceed the global
+// controller limit. When the controller settings cannot be read the limit is not checked.
+func ValidateMaxConcurrentVMs(ctx context.Context, configFlags *genericclioptions.ConfigFlags, n int) error {
+	if n < 1 {
+		return fmt.Errorf("--max-concurrent-vms must be at least 1, got %d", n)
+	}
+
+	values, err := settings.GetSettings(ctx, settings.GetSettingsOptions{ConfigFlags: configFlags, SettingName: maxVMInflightSetting})
+	if err != nil || len(values) == 0 {
+		klog.V(1).Infof("Could not read %s, skipping global limit check: %v", maxVMInflightSetting, err)
+		return nil
+	}
+	value := values[0].Default
+	if values[0].IsSet {
+		value = values[0].Value
+	}
+	limit, ok := value.(int)
+	if !ok {
+		return nil
+	}
+	if n > limit {
+		return fmt.Errorf("--max-concurrent-vms %d exceeds the global controller limit %s=%d", n, maxVMInflightSetting, limit)
+	}
+	return nil
+}
+
+// planVM is a VM of a plan as referenced by a Migration
+type planVM struct {
+	ID   string
+	Name string
+}
+
+// pendingVMs returns the plan VMs that have not succeeded, according to the plan's
+// migration history and the latest migration
+func pendingVMs(plan, latest *unstructured.Unstructured) ([]planVM, error) {
+	succeeded := map[string]bool{}
+	historyVMs, _, _ := unstructured.NestedSlice(plan.Object, "status", "migration", "vms")
+	if latest != nil {
+		latestVMs, _, _ := unstructured.NestedSlice(latest.Object, "status", "vms")
+		historyVMs = append(historyVMs, latestVMs...)
+	}
+	for _, v := range historyVMs {
+		vm, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if planstatus.VMHasCondition(vm, planstatus.StatusSucceeded) {
+			id, _ := vm["id"].(string)
+			succeeded[id] = true
+		}
+	}
+
+	specVMs, _, _ := unstructured.NestedSlice(plan.Object, "spec", "vms")
+	pending := []planVM{}
+	for _, v := range specVMs {
+		vm, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		id, _ := vm["id"].(string)
+		name, _ := vm["name"].(string)
+		if id == "" {
+			return nil, fmt.Errorf("VM '%s' of plan '%s' has no resolved ID, cannot migrate the plan in batches", name, plan.GetName())
+		}
+		if !succeeded[id] {
+			pending = append(pending, planVM{ID: id, Name: name})
+		}
+	}
+	return pending, nil
+}
+
+// splitBatch returns the first limit VMs to migrate and cancel references for the rest
+func splitBatch(pending []planVM, limit int) ([]planVM, []ref.Ref) {
+	if limit >= len(pending) {
+		return pending, nil
+	}
+	cancel := make([]ref.Ref, 0, len(pending)-limit)
+	for _, vm := range pending[limit:] {
+		cancel = append(cancel, ref.Ref{ID: vm.ID, Name: vm.Name})
+	}
+	return pending[:limit], cancel
+}
+
+// StartBatched migrates a plan with at most limit VMs in flight. Each batch is a Migration
+// that cancels the VMs outside the batch; the next batch starts when it finishes, and the
+// function returns once the last batch has started. With dry-run, the Migration of the
+// first batch is printed.
+func StartBatched(ctx context.Context, configFlags *genericclioptions.ConfigFlags, name, namespace string, limit int, cutoverTime *time.Time, useUTC bool, dryRun bool, outputFormat string) error {
+	c, err := client.GetDynamicClient(configFlags)
+	if err != nil {
+		return fmt.Errorf("failed to get client: %v", err)
+	}
+
+	for batch := 1; ; batch++ {
+		plan, err := c.Resource(client.PlansGVR).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to get plan: %v", err)
+		}
+		if batch > 1 {
+			// The previous batch may have completed the plan
+			if planStatus, _ := planstatus.GetPlanStatus(plan); planStatus == planstatus.StatusSucceeded {
+				return nil
+			}
+		}
+		if err := checkStartable(c, namespace, plan); err != nil {
+			return err
+		}
+
+		_, latest, err := planstatus.GetRunningMigration(c, namespace, plan, client.MigrationsGVR)
+		if err != nil {
+			return err
+		}
+		pending, err := pendingVMs(plan, latest)
+		if err != nil {
+			return err
+		}
+		if len(pending) == 0 {
+			return nil
+		}
+
+		vms, cancel := splitBatch(pending, limit)
+		if !dryRun && (batch > 1 || len(cancel) > 0) {
+			fmt.Fprintf(os.Stderr, "Starting batch %d of plan '%s': %d VM(s), %d remaining after this batch\n", batch, name, len(vms), len(cancel))
+		}
+		migration, err := createMigration(c, plan, cutoverTime, useUTC, dryRun, outputFormat, cancel)
+		if err != nil || dryRun {
+			return err
+		}
+
+		// The last batch runs like a regular migration, there is nothing left to wait for
+		if len(cancel) == 0 {
+			return nil
+		}
+		if err := waitForBatch(ctx, c, namespace, migration.GetName(), vms); err != nil {
+			return fmt.Errorf("batch %d of plan '%s': %v", batch, name, err)
+		}
+	}
+}
+
+// waitForBatch waits until the migration finishes and checks that every VM of the batch succeeded
+func waitForBatch(ctx context.Context, c dynamic.Interface, namespace, migrationName string, vms []planVM) error {
+	ticker := time.NewTicker(batchPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("stopped waiting for migration '%s': %v", migrationName, ctx.Err())
+		case <-ticker.C:
+		}
+
+		migration, err := c.Resource(client.MigrationsGVR).Namespace(namespace).Get(ctx, migrationName, metav1.GetOptions{})
+		if err != nil {
+			klog.V(1).Infof("Failed to get migration '%s': %v", migrationName, err)
+			continue
+		}
+		if !migrationFinished(migration) {
+			continue
+		}
+		return checkBatch(migration, vms)
+	}
+}
+
+// migrationFinished reports whether the migration has a terminal condition
+func migrationFinished(migration *unstructured.Unstructured) bool {
+	conditions, _, _ := unstructured.NestedSlice(migration.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok || condition["status"] != "True" {
+			continue
+		}
+		switch condition["type"] {
+		case planstatus.StatusSucceeded, planstatus.StatusFailed, planstatus.StatusCanceled:
+			return true
+		}
+	}
+	return false
+}
+
+// checkBatch returns an error naming the VMs of the batch that did not succeed
+func checkBatch(migration *unstructured.Unstructured, vms []planVM) error {
+	statuses := map[string]map[string]interface{}{}
+	migrationVMs, _, _ := unstructured.NestedSlice(migration.Object, "status", "vms")
+	for _, v := range migrationVMs {
+		if vm, ok := v.(map[string]interface{}); ok {
+			id, _ := vm["id"].(string)
+			statuses[id] = vm
+		}
+	}
+
+	failed := []string{}
+	for _, vm := range vms {
+		status, ok := statuses[vm.ID]
+		if !ok || !planstatus.VMHasCondition(status, planstatus.StatusSucceeded) {
+			failed = append(failed, vm.Name)
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("%d VM(s) did not succeed: %s", len(failed), strings.Join(failed, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package plan
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func succeededVM(id string) interface{} {
+	return map[string]interface{}{
+		"id":         id,
+		"conditions": []interface{}{map[string]interface{}{"type": "Succeeded", "status": "True"}},
+	}
+}
+
+func failedVM(id string) interface{} {
+	return map[string]interface{}{
+		"id":         id,
+		"conditions": []interface{}{map[string]interface{}{"type": "Failed", "status": "True"}},
+	}
+}
+
+func testPlan(vmIDs ...string) *unstructured.Unstructured {
+	vms := []interface{}{}
+	for _, id := range vmIDs {
+		vms = append(vms, map[string]interface{}{"id": id, "name": "vm-" + id})
+	}
+	plan := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{"vms": vms},
+	}}
+	plan.SetName("big-plan")
+	return plan
+}
+
+func TestMaxConcurrentVMs(t *testing.T) {
+	plan := testPlan()
+	if got := MaxConcurrentVMs(plan); got != 0 {
+		t.Errorf("MaxConcurrentVMs() without annotation = %d, want 0", got)
+	}
+
+	for value, want := range map[string]int{"5": 5, "0": 0, "-1": 0, "many": 0} {
+		plan.SetAnnotations(map[string]string{MaxConcurrentVMsAnnotation: value})
+		if got := MaxConcurrentVMs(plan); got != want {
+			t.Errorf("MaxConcurrentVMs() with %q = %d, want %d", value, got, want)
+		}
+	}
+}
+
+func TestPendingVMs(t *testing.T) {
+	plan := testPlan("1", "2", "3", "4")
+	_ = unstructured.SetNestedSlice(plan.Object, []interface{}{succeededVM("1")}, "status", "migration", "vms")
+	latest := &unstructured.Unstructured{Object: map[string]interface{}{
+		"status": map[string]interface{}{"vms": []interface{}{succeededVM("2"), failedVM("3")}},
+	}}
+
+	pending, err := pendingVMs(plan, latest)
+	if err != nil {
+		t.Fatalf("pendingVMs() unexpected error: %v", err)
+	}
+	if len(pending) != 2 || pending[0].ID != "3" || pending[1].ID != "4" {
+		t.Errorf("pendingVMs() = %v, want VMs 3 and 4", pending)
+	}
+
+	unresolved := testPlan("1")
+	_ = unstructured.SetNestedSlice(unresolved.Object, []interface{}{map[string]interface{}{"name": "no-id"}}, "spec", "vms")
+	if _, err := pendingVMs(unresolved, nil); err == nil {
+		t.Error("pendingVMs() expected error for a VM without ID")
+	}
+}
+
+func TestSplitBatch(t *testing.T) {
+	pending := []planVM{{ID: "1"}, {ID: "2"}, {ID: "3"}}
+
+	batch, cancel := splitBatch(pending, 2)
+	if len(batch) != 2 || len(cancel) != 1 || cancel[0].ID != "3" {
+		t.Errorf("splitBatch(limit=2) = %v, %v", batch, cancel)
+	}
+
+	batch, cancel = splitBatch(pending, 5)
+	if len(batch) != 3 || cancel != nil {
+		t.Errorf("splitBatch(limit=5) = %v, %v, want all VMs and no cancel", batch, cancel)
+	}
+}
+
+func TestCheckBatch(t *testing.T) {
+	migration := &unstructured.Unstructured{Object: map[string]interface{}{
+		"status": map[string]interface{}{"vms": []interface{}{succeededVM("1"), failedVM("2")}},
+	}}
+
+	if err := checkBatch(migration, []planVM{{ID: "1", Name: "vm-1"}}); err != nil {
+		t.Errorf("checkBatch() unexpected error: %v", err)
+	}
+	if err := checkBatch(migration, []planVM{{ID: "1", Name: "vm-1"}, {ID: "2", Name: "vm-2"}}); err == nil {
+		t.Error("checkBatch() expected error for a failed VM")
+	}
+	if err := checkBatch(migration, []planVM{{ID: "9", Name: "vm-9"}}); err == nil {
+		t.Error("checkBatch() expected error for a VM missing from the migration")
+	}
+}
+
+func TestMigrationFinished(t *testing.T) {
+	migration := &unstructured.Unstructured{Object: map[string]interface{}{
+		"status": map[string]interface{}{"conditions": []interface{}{
+			map[string]interface{}{"type": "Running", "status": "True"},
+		}},
+	}}
+	if migrationFinished(migration) {
+		t.Error("migrationFinished() = true for a running migration")
+	}
+
+	_ = unstructured.SetNestedSlice(migration.Object, []interface{}{
+		map[string]interface{}{"type": "Canceled", "status": "True"},
+	}, "status", "conditions")
+	if !migrationFinished(migration) {
+		t.Error("migrationFinished() = false for a canceled migration")
+	}
+}
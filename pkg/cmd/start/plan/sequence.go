@@ -0,0 +1,369 @@
+package plan
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	planstatus "github.com/yaacov/kubectl-mtv/pkg/cmd/get/plan/status"
+	"github.com/yaacov/kubectl-mtv/pkg/util/client"
+)
+
+// DependsOnAnnotation lists the plans, in the same namespace, that must have succeeded
+// before a plan is started (comma-separated names)
+const DependsOnAnnotation = "kubectl-mtv/depends-on"
+
+// Failure policies of a sequential start
+const (
+	// FailureAbort starts no further plans after a plan fails
+	FailureAbort = "abort"
+	// FailureContinue keeps starting the plans that do not depend on the failed one
+	FailureContinue = "continue"
+)
+
+// Outcomes of a plan in a sequential start
+const (
+	OutcomeSucceeded        = "Succeeded"
+	OutcomeAlreadySucceeded = "already succeeded"
+	OutcomeFailed           = "Failed"
+	OutcomeSkipped          = "skipped"
+)
+
+// ValidateOnFailure checks an --on-failure policy
+func ValidateOnFailure(policy string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(policy)) {
+	case FailureAbort, "":
+		return FailureAbort, nil
+	case FailureContinue:
+		return FailureContinue, nil
+	default:
+		return "", fmt.Errorf("invalid --on-failure '%s': use %s or %s", policy, FailureAbort, FailureContinue)
+	}
+}
+
+// DependsOn returns the plans a plan depends on, from its depends-on annotation
+func DependsOn(plan *unstructured.Unstructured) []string {
+	return ParseDependsOn(plan.GetAnnotations()[DependsOnAnnotation])
+}
+
+// ParseDependsOn splits a comma-separated list of plan names
+func ParseDependsOn(value string) []string {
+	var names []string
+	for _, name := range strings.Split(value, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// OrderPlans orders the plans so each one comes after the plans it depends on, keeping
+// the given order otherwise. Dependencies outside names are ignored here; they must
+// already have succeeded when the plan is started.
+func OrderPlans(names []string, dependsOn map[string][]string) ([]string, error) {
+	requested := make(map[string]bool, len(names))
+	for _, name := range names {
+		requested[name] = true
+	}
+
+	const (
+		visiting = 1
+		done     = 2
+	)
+	state := make(map[string]int, len(names))
+	ordered := make([]string, 0, len(names))
+	var visit func(name string, path []string) error
+	visit = func(name string, path []string) error {
+		switch state[name] {
+		case done:
+			return nil
+		case visiting:
+			return fmt.Errorf("plan dependencies form a cycle: %s", strings.Join(append(path, name), " -> "))
+		}
+		state[name] = visiting
+		for _, dep := range dependsOn[name] {
+			if requested[dep] {
+				if err := visit(dep, append(path, name)); err != nil {
+					return err
+				}
+			}
+		}
+		state[name] = done
+		ordered = append(ordered, name)
+		return nil
+	}
+
+	for _, name := range names {
+		if err := visit(name, nil); err != nil {
+			return nil, err
+		}
+	}
+	return ordered, nil
+}
+
+// SequenceResult is the outcome of one plan of a sequential start
+type SequenceResult struct {
+	Name    string
+	Outcome string
+	// Reason explains a failure or why the plan was skipped
+	Reason string
+	// Duration is how long the plan took to start and finish, zero when it was not run
+	Duration time.Duration
+}
+
+// SequenceOptions configures RunSequence
+type SequenceOptions struct {
+	// OnFailure is FailureAbort or FailureContinue
+	OnFailure string
+	// WaitTimeout bounds the wait for each plan, 0 waits until it finishes
+	WaitTimeout time.Duration
+	// Concurrency is the number of plans run at the same time, 1 when not positive;
+	// a plan still waits for the plans it depends on
+	Concurrency int
+	// Out receives the progress lines and the summary
+	Out io.Writer
+}
+
+// sequenceSteps are the cluster operations of a sequential start
+type sequenceSteps struct {
+	// status returns the status of a plan and the plans it depends on
+	status func(ctx context.Context, name string) (string, []string, error)
+	// start starts a plan
+	start func(ctx context.Context, name string) error
+	// wait blocks until the started migration of a plan succeeds
+	wait func(ctx context.Context, name string) error
+}
+
+// RunSequence starts the plans one after another, each only once the previous one and
+// the plans it depends on succeeded. Plans that already succeeded are skipped and plans
+// still running are waited for instead of started again, so an interrupted or failed
+// sequence can be resumed by running the same command.
+func RunSequence(ctx context.Context, configFlags *genericclioptions.ConfigFlags, namespace string, names []string, start func(ctx context.Context, name string) error, opts SequenceOptions) error {
+	if opts.Out == nil {
+		opts.Out = io.Discard
+	}
+	results, err := RunPlans(ctx, configFlags, namespace, names, start, opts)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(opts.Out, "Sequence finished:\n")
+	if failed := PrintResults(opts.Out, results); failed > 0 {
+		return fmt.Errorf("%d of %d plans did not succeed; fix the failures and run the command again to resume", failed, len(results))
+	}
+	return nil
+}
+
+// RunPlans runs the plans like RunSequence, with up to opts.Concurrency plans at a time,
+// and returns the outcome of each plan in the order they were run
+func RunPlans(ctx context.Context, configFlags *genericclioptions.ConfigFlags, namespace string, names []string, start func(ctx context.Context, name string) error, opts SequenceOptions) ([]SequenceResult, error) {
+	c, err := client.GetDynamicClient(configFlags)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get client: %v", err)
+	}
+	if opts.Out == nil {
+		opts.Out = io.Discard
+	}
+
+	steps := sequenceSteps{
+		status: func(ctx context.Context, name string) (string, []string, error) {
+			plan, err := c.Resource(client.PlansGVR).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+			if err != nil {
+				return "", nil, fmt.Errorf("failed to get plan '%s': %v", name, err)
+			}
+			planStatus, _ := planstatus.GetPlanStatus(plan)
+			return planStatus, DependsOn(plan), nil
+		},
+		start: start,
+		wait: func(ctx context.Context, name string) error {
+			return Wait(ctx, configFlags, name, namespace, WaitOptions{Timeout: opts.WaitTimeout, Out: opts.Out})
+		},
+	}
+
+	return runSequence(ctx, names, steps, opts)
+}
+
+// PrintResults prints one line per plan with its outcome and returns the number of plans
+// that did not succeed
+func PrintResults(out io.Writer, results []SequenceResult) int {
+	failed := 0
+	for _, result := range results {
+		line := result.Outcome
+		if result.Reason != "" {
+			line += ": " + result.Reason
+		}
+		fmt.Fprintf(out, "  %s: %s\n", result.Name, line)
+		if result.Outcome == OutcomeFailed || result.Outcome == OutcomeSkipped {
+			failed++
+		}
+	}
+	return failed
+}
+
+// runSequence runs the plans in dependency order and returns the outcome of each plan.
+// Plans are started in order with at most opts.Concurrency running; a plan is only
+// started once the plans it depends on in this run finished.
+func runSequence(ctx context.Context, names []string, steps sequenceSteps, opts SequenceOptions) ([]SequenceResult, error) {
+	statuses := make(map[string]string, len(names))
+	dependsOn := make(map[string][]string, len(names))
+	for _, name := range names {
+		planStatus, deps, err := steps.status(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		statuses[name], dependsOn[name] = planStatus, deps
+	}
+
+	ordered, err := OrderPlans(names, dependsOn)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := opts.Concurrency
+	if limit < 1 {
+		limit = 1
+	}
+	done := make(map[string]chan struct{}, len(ordered))
+	for _, name := range ordered {
+		done[name] = make(chan struct{})
+	}
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		aborted  string
+		outcomes = make(map[string]string, len(ordered))
+	)
+	semaphore := make(chan struct{}, limit)
+	results := make([]SequenceResult, len(ordered))
+	// finish records the outcome of a plan and lets the plans depending on it go on; a plan
+	// that was due to run and did not succeed stops the sequence unless failures continue
+	finish := func(i int, due bool) {
+		name := results[i].Name
+		outcomes[name] = results[i].Outcome
+		if due && results[i].Outcome != OutcomeSucceeded && opts.OnFailure != FailureContinue && aborted == "" {
+			aborted = name
+		}
+		close(done[name])
+	}
+
+	for i, name := range ordered {
+		results[i].Name = name
+		for _, dep := range dependsOn[name] {
+			if ch, ok := done[dep]; ok {
+				<-ch
+			}
+		}
+		semaphore <- struct{}{}
+
+		mu.Lock()
+		result := &results[i]
+		due := false
+		switch {
+		case aborted != "":
+			result.Outcome, result.Reason = OutcomeSkipped, fmt.Sprintf("not started after plan '%s' failed", aborted)
+		case statuses[name] == planstatus.StatusSucceeded:
+			result.Outcome = OutcomeAlreadySucceeded
+		case ctx.Err() != nil:
+			result.Outcome, result.Reason = OutcomeSkipped, ctx.Err().Error()
+		default:
+			due = true
+			if blocker := unmetDependency(ctx, dependsOn[name], outcomes, steps); blocker != "" {
+				result.Outcome, result.Reason = OutcomeSkipped, blocker
+				break
+			}
+			mu.Unlock()
+
+			wg.Add(1)
+			go func(i int, name string) {
+				defer wg.Done()
+				defer func() { <-semaphore }()
+
+				startedAt := time.Now()
+				err := runSequencePlan(ctx, name, statuses[name], steps, opts.Out)
+
+				mu.Lock()
+				defer mu.Unlock()
+				results[i].Duration = time.Since(startedAt)
+				if err != nil {
+					results[i].Outcome, results[i].Reason = OutcomeFailed, err.Error()
+				} else {
+					results[i].Outcome = OutcomeSucceeded
+				}
+				finish(i, true)
+			}(i, name)
+			continue
+		}
+		finish(i, due)
+		mu.Unlock()
+		<-semaphore
+	}
+
+	wg.Wait()
+	return results, nil
+}
+
+// unmetDependency returns why a plan cannot start yet: a dependency run in this sequence
+// that did not succeed, or another plan that has not succeeded
+func unmetDependency(ctx context.Context, deps []string, outcomes map[string]string, steps sequenceSteps) string {
+	for _, dep := range deps {
+		if outcome, ok := outcomes[dep]; ok {
+			if outcome != OutcomeSucceeded && outcome != OutcomeAlreadySucceeded {
+				return fmt.Sprintf("depends on plan '%s', which did not succeed", dep)
+			}
+			continue
+		}
+		depStatus, _, err := steps.status(ctx, dep)
+		if err != nil {
+			return fmt.Sprintf("depends on plan '%s': %v", dep, err)
+		}
+		if depStatus != planstatus.StatusSucceeded {
+			return fmt.Sprintf("depends on plan '%s', which has not succeeded (status %s)", dep, depStatus)
+		}
+	}
+	return ""
+}
+
+// runSequencePlan starts a plan, or resumes waiting for it when it is already running,
+// and waits until its migration succeeds
+func runSequencePlan(ctx context.Context, name, planStatus string, steps sequenceSteps, out io.Writer) error {
+	if planStatus == planstatus.StatusRunning || planStatus == planstatus.StatusExecuting {
+		fmt.Fprintf(out, "Plan '%s' is already running, waiting for it\n", name)
+	} else {
+		fmt.Fprintf(out, "Starting plan '%s'\n", name)
+		if err := steps.start(ctx, name); err != nil {
+			return err
+		}
+	}
+	return steps.wait(ctx, name)
+}
+
+// CheckDependencies returns an error when a plan depends on plans that have not succeeded
+func CheckDependencies(ctx context.Context, configFlags *genericclioptions.ConfigFlags, name, namespace string) error {
+	c, err := client.GetDynamicClient(configFlags)
+	if err != nil {
+		return fmt.Errorf("failed to get client: %v", err)
+	}
+	plan, err := c.Resource(client.PlansGVR).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get plan '%s': %v", name, err)
+	}
+
+	for _, dep := range DependsOn(plan) {
+		depPlan, err := c.Resource(client.PlansGVR).Namespace(namespace).Get(ctx, dep, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("plan '%s' depends on plan '%s': %v", name, dep, err)
+		}
+		if depStatus, _ := planstatus.GetPlanStatus(depPlan); depStatus != planstatus.StatusSucceeded {
+			return fmt.Errorf("plan '%s' depends on plan '%s', which has not succeeded (status %s); start them together with --sequential", name, dep, depStatus)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,198 @@
+package plan
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	planstatus "github.com/yaacov/kubectl-mtv/pkg/cmd/get/plan/status"
+)
+
+func TestOrderPlans(t *testing.T) {
+	deps := map[string][]string{
+		"web": {"app"},
+		"app": {"db", "external"},
+	}
+	ordered, err := OrderPlans([]string{"web", "reports", "app", "db"}, deps)
+	if err != nil {
+		t.Fatalf("OrderPlans() unexpected error: %v", err)
+	}
+	if got := strings.Join(ordered, ","); got != "db,app,web,reports" {
+		t.Errorf("OrderPlans() = %s, want db,app,web,reports", got)
+	}
+
+	deps["db"] = []string{"web"}
+	if _, err := OrderPlans([]string{"web", "app", "db"}, deps); err == nil || !strings.Contains(err.Error(), "cycle") {
+		t.Errorf("OrderPlans() with a cycle error = %v, want a cycle error", err)
+	}
+}
+
+// fakeSequence records the plans started and fails the ones listed in failing
+type fakeSequence struct {
+	statuses  map[string]string
+	dependsOn map[string][]string
+	failing   map[string]bool
+	started   []string
+	waited    []string
+}
+
+func (f *fakeSequence) steps() sequenceSteps {
+	return sequenceSteps{
+		status: func(ctx context.Context, name string) (string, []string, error) {
+			planStatus, ok := f.statuses[name]
+			if !ok {
+				return "", nil, fmt.Errorf("plan '%s' not found", name)
+			}
+			return planStatus, f.dependsOn[name], nil
+		},
+		start: func(ctx context.Context, name string) error {
+			f.started = append(f.started, name)
+			return nil
+		},
+		wait: func(ctx context.Context, name string) error {
+			f.waited = append(f.waited, name)
+			if f.failing[name] {
+				f.statuses[name] = planstatus.StatusFailed
+				return fmt.Errorf("migration Failed")
+			}
+			f.statuses[name] = planstatus.StatusSucceeded
+			return nil
+		},
+	}
+}
+
+func outcomes(results []SequenceResult) string {
+	var parts []string
+	for _, r := range results {
+		parts = append(parts, r.Name+"="+r.Outcome)
+	}
+	return strings.Join(parts, ",")
+}
+
+func TestRunSequenceAbort(t *testing.T) {
+	f := &fakeSequence{
+		statuses: map[string]string{"db": planstatus.StatusSucceeded, "app": "-", "web": "-", "reports": "-"},
+		failing:  map[string]bool{"app": true},
+	}
+	results, err := runSequence(context.Background(), []string{"db", "app", "web", "reports"}, f.steps(), SequenceOptions{OnFailure: FailureAbort, Out: io.Discard})
+	if err != nil {
+		t.Fatalf("runSequence() unexpected error: %v", err)
+	}
+	if got := outcomes(results); got != "db=already succeeded,app=Failed,web=skipped,reports=skipped" {
+		t.Errorf("outcomes = %s", got)
+	}
+	if strings.Join(f.started, ",") != "app" {
+		t.Errorf("started = %v, want only app", f.started)
+	}
+}
+
+func TestRunSequenceContinueAndResume(t *testing.T) {
+	f := &fakeSequence{
+		statuses:  map[string]string{"db": "-", "app": "-", "web": planstatus.StatusRunning, "reports": "-"},
+		dependsOn: map[string][]string{"app": {"db"}, "web": {"app"}},
+		failing:   map[string]bool{"db": true},
+	}
+	results, _ := runSequence(context.Background(), []string{"web", "reports", "app", "db"}, f.steps(), SequenceOptions{OnFailure: FailureContinue, Out: io.Discard})
+	if got := outcomes(results); got != "db=Failed,app=skipped,web=skipped,reports=Succeeded" {
+		t.Errorf("outcomes = %s", got)
+	}
+	if results[1].Reason != "depends on plan 'db', which did not succeed" {
+		t.Errorf("app skip reason = %q", results[1].Reason)
+	}
+
+	// Running again after db was fixed resumes: the running web plan is waited for, not started again
+	f.failing = nil
+	f.statuses["db"] = "-"
+	results, _ = runSequence(context.Background(), []string{"web", "reports", "app", "db"}, f.steps(), SequenceOptions{OnFailure: FailureAbort, Out: io.Discard})
+	if got := outcomes(results); got != "db=Succeeded,app=Succeeded,web=Succeeded,reports=already succeeded" {
+		t.Errorf("resumed outcomes = %s", got)
+	}
+	if got := strings.Join(f.started, ","); got != "db,reports,db,app" {
+		t.Errorf("started = %s, want web to be waited for and not started", got)
+	}
+}
+
+func TestRunSequenceExternalDependency(t *testing.T) {
+	f := &fakeSequence{
+		statuses:  map[string]string{"app": "-", "db": planstatus.StatusFailed},
+		dependsOn: map[string][]string{"app": {"db"}},
+	}
+	results, _ := runSequence(context.Background(), []string{"app"}, f.steps(), SequenceOptions{Out: io.Discard})
+	if results[0].Outcome != OutcomeSkipped || !strings.Contains(results[0].Reason, "has not succeeded (status Failed)") {
+		t.Errorf("app = %+v, want skipped until db succeeds", results[0])
+	}
+}
+
+func TestRunSequenceConcurrencyLimit(t *testing.T) {
+	var mu sync.Mutex
+	running, maxRunning := 0, 0
+	steps := sequenceSteps{
+		status: func(ctx context.Context, name string) (string, []string, error) {
+			return "-", nil, nil
+		},
+		start: func(ctx context.Context, name string) error { return nil },
+		wait: func(ctx context.Context, name string) error {
+			mu.Lock()
+			running++
+			if running > maxRunning {
+				maxRunning = running
+			}
+			mu.Unlock()
+
+			time.Sleep(10 * time.Millisecond)
+
+			mu.Lock()
+			running--
+			mu.Unlock()
+			return nil
+		},
+	}
+
+	results, err := runSequence(context.Background(), []string{"a", "b", "c", "d", "e"}, steps, SequenceOptions{Concurrency: 2, Out: io.Discard})
+	if err != nil {
+		t.Fatalf("runSequence() unexpected error: %v", err)
+	}
+	if maxRunning != 2 {
+		t.Errorf("max running plans = %d, want 2", maxRunning)
+	}
+	if got := outcomes(results); got != "a=Succeeded,b=Succeeded,c=Succeeded,d=Succeeded,e=Succeeded" {
+		t.Errorf("outcomes = %s", got)
+	}
+}
+
+func TestRunSequenceConcurrentDependency(t *testing.T) {
+	var mu sync.Mutex
+	finished := map[string]bool{}
+	steps := sequenceSteps{
+		status: func(ctx context.Context, name string) (string, []string, error) {
+			if name == "app" {
+				return "-", []string{"db"}, nil
+			}
+			return "-", nil, nil
+		},
+		start: func(ctx context.Context, name string) error {
+			mu.Lock()
+			defer mu.Unlock()
+			if name == "app" && !finished["db"] {
+				return fmt.Errorf("app started before db finished")
+			}
+			return nil
+		},
+		wait: func(ctx context.Context, name string) error {
+			time.Sleep(10 * time.Millisecond)
+			mu.Lock()
+			finished[name] = true
+			mu.Unlock()
+			return nil
+		},
+	}
+
+	results, _ := runSequence(context.Background(), []string{"db", "app", "web"}, steps, SequenceOptions{Concurrency: 3, Out: io.Discard})
+	if got := outcomes(results); got != "db=Succeeded,app=Succeeded,web=Succeeded" {
+		t.Errorf("outcomes = %s, want app to wait for db", got)
+	}
+}
@@ -12,8 +12,10 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/dynamic"
 
 	forkliftv1beta1 "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
 	planstatus "github.com/yaacov/kubectl-mtv/pkg/cmd/get/plan/status"
 	"github.com/yaacov/kubectl-mtv/pkg/util/client"
 	"github.com/yaacov/kubectl-mtv/pkg/util/output"
@@ -32,6 +34,18 @@ func Start(configFlags *genericclioptions.ConfigFlags, name, namespace string, c
 		return fmt.Errorf("failed to get plan: %v", err)
 	}
 
+	if err := checkStartable(c, namespace, plan); err != nil {
+		return err
+	}
+
+	_, err = createMigration(c, plan, cutoverTime, useUTC, dryRun, outputFormat, nil)
+	return err
+}
+
+// checkStartable verifies that the plan is ready, not running and not already succeeded
+func checkStartable(c dynamic.Interface, namespace string, plan *unstructured.Unstructured) error {
+	name := plan.GetName()
+
 	// Check if the plan is ready
 	planReady, err := planstatus.IsPlanReady(plan)
 	if err != nil {
@@ -58,6 +72,14 @@ func Start(configFlags *genericclioptions.ConfigFlags, name, namespace string, c
 	if planStatus == planstatus.StatusSucceeded {
 		return fmt.Errorf("migration plan '%s' has already succeeded", name)
 	}
+	return nil
+}
+
+// createMigration creates a Migration for the plan, or outputs it if dry-run is enabled.
+// VMs in cancel are canceled by the migration and left for a later one.
+func createMigration(c dynamic.Interface, plan *unstructured.Unstructured, cutoverTime *time.Time, useUTC bool, dryRun bool, outputFormat string, cancel []ref.Ref) (*unstructured.Unstructured, error) {
+	name := plan.GetName()
+	namespace := plan.GetNamespace()
 
 	// Check if the plan is a warm migration (handles both spec.type and legacy spec.warm)
 	warm := planstatus.IsWarmMigration(plan)
@@ -96,6 +118,7 @@ func Start(configFlags *genericclioptions.ConfigFlags, name, namespace string, c
 				Namespace: namespace,
 				UID:       types.UID(planUID),
 			},
+			Cancel: cancel,
 		},
 	}
 	migration.Kind = "Migration"
@@ -110,25 +133,25 @@ func Start(configFlags *genericclioptions.ConfigFlags, name, namespace string, c
 
 	// Handle dry-run mode
 	if dryRun {
-		return output.OutputResource(migration, outputFormat)
+		return nil, output.OutputResource(migration, outputFormat)
 	}
 
 	// Convert Migration object to Unstructured
 	unstructuredMigration, err := runtime.DefaultUnstructuredConverter.ToUnstructured(migration)
 	if err != nil {
-		return fmt.Errorf("failed to convert Migration to Unstructured: %v", err)
+		return nil, fmt.Errorf("failed to convert Migration to Unstructured: %v", err)
 	}
 	migrationUnstructured := &unstructured.Unstructured{Object: unstructuredMigration}
 
 	// Create the migration in the specified namespace
-	_, err = c.Resource(client.MigrationsGVR).Namespace(namespace).Create(context.TODO(), migrationUnstructured, metav1.CreateOptions{})
+	created, err := c.Resource(client.MigrationsGVR).Namespace(namespace).Create(context.TODO(), migrationUnstructured, metav1.CreateOptions{})
 	if err != nil {
-		return fmt.Errorf("failed to create migration: %v", err)
+		return nil, fmt.Errorf("failed to create migration: %v", err)
 	}
 
 	fmt.Fprintf(os.Stderr, "Migration started for plan '%s' in namespace '%s'\n", name, namespace)
 	if warm && cutoverTime != nil {
 		fmt.Fprintf(os.Stderr, "Cutover scheduled for: %s\n", output.FormatTimestamp(*cutoverTime, useUTC))
 	}
-	return nil
+	return created, nil
 }
@@ -0,0 +1,308 @@
+package plan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/klog/v2"
+
+	planstatus "github.com/yaacov/kubectl-mtv/pkg/cmd/get/plan/status"
+	"github.com/yaacov/kubectl-mtv/pkg/util/client"
+)
+
+// WaitForCompleted waits until the migration finishes and succeeds, the default of --wait
+const WaitForCompleted = "Completed"
+
+// waitPollInterval is how often a waited migration is checked
+var waitPollInterval = 10 * time.Second
+
+// waitProgressInterval is how often an unchanged progress line is repeated
+const waitProgressInterval = time.Minute
+
+// WaitOptions configures Wait
+type WaitOptions struct {
+	// For is the phase to wait for: Completed, Succeeded, Failed, Canceled, Running,
+	// or a VM pipeline step such as DiskTransfer or Cutover
+	For string
+	// Timeout bounds the wait, 0 waits until the phase is reached
+	Timeout time.Duration
+	// Out receives the progress lines
+	Out io.Writer
+}
+
+// ValidateWaitFor checks the --wait-for value and returns it with canonical casing
+func ValidateWaitFor(phase string) (string, error) {
+	phase = strings.TrimSpace(phase)
+	if phase == "" {
+		return "", fmt.Errorf("--wait-for requires a phase")
+	}
+	for _, known := range []string{WaitForCompleted, planstatus.StatusSucceeded, planstatus.StatusFailed, planstatus.StatusCanceled, planstatus.StatusRunning} {
+		if strings.EqualFold(phase, known) {
+			return known, nil
+		}
+	}
+	// Pipeline step names are defined by the provider adapters, e.g. DiskTransfer, Cutover, ImageConversion
+	for _, r := range phase {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+			return "", fmt.Errorf("invalid --wait-for phase '%s': use Completed, Succeeded, Failed, Canceled, Running or a pipeline step such as DiskTransfer", phase)
+		}
+	}
+	return phase, nil
+}
+
+// Wait blocks until the latest migration of a plan reaches the requested phase, printing
+// progress while waiting. It returns an error when the migration ends without reaching
+// the phase, or does not succeed when waiting for completion, and when the timeout expires.
+func Wait(ctx context.Context, configFlags *genericclioptions.ConfigFlags, name, namespace string, opts WaitOptions) error {
+	c, err := client.GetDynamicClient(configFlags)
+	if err != nil {
+		return fmt.Errorf("failed to get client: %v", err)
+	}
+	if opts.For == "" {
+		opts.For = WaitForCompleted
+	}
+	if opts.Out == nil {
+		opts.Out = io.Discard
+	}
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	var lastLine string
+	var lastPrinted time.Time
+	for {
+		plan, err := c.Resource(client.PlansGVR).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err == nil {
+			running, latest, err := planstatus.GetRunningMigration(c, namespace, plan, client.MigrationsGVR)
+			migration := running
+			if migration == nil {
+				migration = latest
+			}
+			switch {
+			case err != nil:
+				klog.V(1).Infof("Failed to get migrations of plan '%s': %v", name, err)
+			case migration != nil:
+				reached, err := WaitReached(migration, opts.For)
+				if reached || err != nil {
+					fmt.Fprintf(opts.Out, "%s\n", WaitProgress(migration))
+					if err != nil {
+						return fmt.Errorf("plan '%s': %v", name, err)
+					}
+					fmt.Fprintf(opts.Out, "Plan '%s' reached %s after %s\n", name, opts.For, time.Since(start).Round(time.Second))
+					return nil
+				}
+				if line := WaitProgress(migration); line != lastLine || time.Since(lastPrinted) >= waitProgressInterval {
+					fmt.Fprintf(opts.Out, "%s [%s]\n", line, time.Since(start).Round(time.Second))
+					lastLine, lastPrinted = line, time.Now()
+				}
+			}
+		} else if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			klog.V(1).Infof("Failed to get plan '%s': %v", name, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return fmt.Errorf("timed out after %s waiting for plan '%s' to reach %s", opts.Timeout, name, opts.For)
+			}
+			return fmt.Errorf("stopped waiting for plan '%s': %v", name, ctx.Err())
+		case <-time.After(waitPollInterval):
+		}
+	}
+}
+
+// WaitReached reports whether a migration reached the phase. The error is set when the
+// migration ended without reaching it: a failed or canceled migration when waiting for
+// completion, success or a step, or an unexpected result when waiting for a result.
+func WaitReached(migration *unstructured.Unstructured, phase string) (bool, error) {
+	result := migrationResult(migration)
+
+	switch phase {
+	case WaitForCompleted, planstatus.StatusSucceeded:
+		if result == "" {
+			return false, nil
+		}
+		if result != planstatus.StatusSucceeded {
+			return true, fmt.Errorf("migration '%s' %s%s", migration.GetName(), strings.ToLower(result), failedVMsSuffix(migration))
+		}
+		return true, nil
+	case planstatus.StatusFailed, planstatus.StatusCanceled:
+		if result == "" {
+			return false, nil
+		}
+		if result != phase {
+			return true, fmt.Errorf("migration '%s' finished as %s, not %s", migration.GetName(), result, phase)
+		}
+		return true, nil
+	case planstatus.StatusRunning:
+		if migrationHasCondition(migration, planstatus.StatusRunning) || vmsStarted(migration) {
+			return true, nil
+		}
+	default:
+		if stepReached(migration, phase) {
+			return true, nil
+		}
+	}
+
+	// A running phase or step can no longer be reached once the migration ended
+	switch result {
+	case "":
+		return false, nil
+	case planstatus.StatusSucceeded:
+		if phase == planstatus.StatusRunning {
+			return true, nil
+		}
+		return true, fmt.Errorf("migration '%s' succeeded without reaching %s", migration.GetName(), phase)
+	default:
+		return true, fmt.Errorf("migration '%s' %s before reaching %s%s", migration.GetName(), strings.ToLower(result), phase, failedVMsSuffix(migration))
+	}
+}
+
+// WaitProgress returns a one line summary of a migration: its state, VM counts and disk transfer progress
+func WaitProgress(migration *unstructured.Unstructured) string {
+	state := migrationResult(migration)
+	if state == "" {
+		state = "Pending"
+		if migrationHasCondition(migration, planstatus.StatusRunning) {
+			state = planstatus.StatusRunning
+		}
+	}
+
+	line := fmt.Sprintf("Migration %s: %s", migration.GetName(), state)
+	stats, _ := planstatus.GetVMStats(migration)
+	if stats.Total > 0 {
+		line += fmt.Sprintf(", VMs %d/%d completed (%d succeeded, %d failed, %d canceled)",
+			stats.Completed, stats.Total, stats.Succeeded, stats.Failed, stats.Canceled)
+	}
+	if progress, _ := planstatus.GetDiskTransferProgress(migration); progress.Total > 0 {
+		line += fmt.Sprintf(", disks %.1f%%", float64(progress.Completed)/float64(progress.Total)*100)
+	}
+	if steps := runningSteps(migration); steps != "" {
+		line += ", " + steps
+	}
+	return line
+}
+
+// migrationResult returns Succeeded, Failed or Canceled once the migration finished, or ""
+func migrationResult(migration *unstructured.Unstructured) string {
+	for _, result := range []string{planstatus.StatusFailed, planstatus.StatusCanceled, planstatus.StatusSucceeded} {
+		if migrationHasCondition(migration, result) {
+			return result
+		}
+	}
+	return ""
+}
+
+// migrationHasCondition reports whether the migration has a true condition of the given type
+func migrationHasCondition(migration *unstructured.Unstructured, condType string) bool {
+	conditions, _, _ := unstructured.NestedSlice(migration.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if ok && condition["type"] == condType && condition["status"] == "True" {
+			return true
+		}
+	}
+	return false
+}
+
+// migrationVMs returns the VM statuses of a migration
+func migrationVMs(migration *unstructured.Unstructured) []map[string]interface{} {
+	vms, _, _ := unstructured.NestedSlice(migration.Object, "status", "vms")
+	result := make([]map[string]interface{}, 0, len(vms))
+	for _, v := range vms {
+		if vm, ok := v.(map[string]interface{}); ok {
+			result = append(result, vm)
+		}
+	}
+	return result
+}
+
+// vmsStarted reports whether any VM of the migration started
+func vmsStarted(migration *unstructured.Unstructured) bool {
+	for _, vm := range migrationVMs(migration) {
+		if started, _ := vm["started"].(string); started != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// stepReached reports whether every VM still migrating started the pipeline step. Failed and
+// canceled VMs are not waited for, but at least one VM must have reached the step.
+func stepReached(migration *unstructured.Unstructured, step string) bool {
+	reached := 0
+	for _, vm := range migrationVMs(migration) {
+		if planstatus.VMHasCondition(vm, planstatus.StatusFailed) || planstatus.VMHasCondition(vm, planstatus.StatusCanceled) {
+			continue
+		}
+		if !vmStepStarted(vm, step) {
+			return false
+		}
+		reached++
+	}
+	return reached > 0
+}
+
+// vmStepStarted reports whether the VM pipeline step started or completed
+func vmStepStarted(vm map[string]interface{}, step string) bool {
+	pipeline, _, _ := unstructured.NestedSlice(vm, "pipeline")
+	for _, p := range pipeline {
+		s, ok := p.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if name, _ := s["name"].(string); !strings.EqualFold(name, step) {
+			continue
+		}
+		started, _ := s["started"].(string)
+		phase, _ := s["phase"].(string)
+		return started != "" || phase == planstatus.StatusRunning || phase == planstatus.StatusCompleted
+	}
+	return false
+}
+
+// runningSteps summarizes the current pipeline step of the VMs still migrating, e.g. "DiskTransfer: 2"
+func runningSteps(migration *unstructured.Unstructured) string {
+	counts := map[string]int{}
+	var order []string
+	for _, vm := range migrationVMs(migration) {
+		phase, _ := vm["phase"].(string)
+		if phase == "" || phase == planstatus.StatusCompleted {
+			continue
+		}
+		if counts[phase] == 0 {
+			order = append(order, phase)
+		}
+		counts[phase]++
+	}
+	parts := make([]string, 0, len(order))
+	for _, phase := range order {
+		parts = append(parts, fmt.Sprintf("%s: %d", phase, counts[phase]))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// failedVMsSuffix names the failed VMs of a migration, for error messages
+func failedVMsSuffix(migration *unstructured.Unstructured) string {
+	var failed []string
+	for _, vm := range migrationVMs(migration) {
+		if planstatus.VMHasCondition(vm, planstatus.StatusFailed) {
+			name, _ := vm["name"].(string)
+			failed = append(failed, name)
+		}
+	}
+	if len(failed) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (failed VMs: %s)", strings.Join(failed, ", "))
+}
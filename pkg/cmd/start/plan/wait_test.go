@@ -0,0 +1,105 @@
+package plan
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func testMigration(conditions []string, vms ...interface{}) *unstructured.Unstructured {
+	conds := []interface{}{}
+	for _, c := range conditions {
+		conds = append(conds, map[string]interface{}{"type": c, "status": "True"})
+	}
+	migration := &unstructured.Unstructured{Object: map[string]interface{}{
+		"status": map[string]interface{}{"conditions": conds, "vms": vms},
+	}}
+	migration.SetName("big-plan-x1")
+	return migration
+}
+
+func stepVM(name, phase string, steps map[string]string) interface{} {
+	pipeline := []interface{}{}
+	for step, stepPhase := range steps {
+		pipeline = append(pipeline, map[string]interface{}{"name": step, "phase": stepPhase})
+	}
+	return map[string]interface{}{"name": name, "phase": phase, "started": "2026-01-01T00:00:00Z", "pipeline": pipeline}
+}
+
+func TestValidateWaitFor(t *testing.T) {
+	for input, want := range map[string]string{"succeeded": "Succeeded", "COMPLETED": "Completed", "DiskTransfer": "DiskTransfer"} {
+		if got, err := ValidateWaitFor(input); err != nil || got != want {
+			t.Errorf("ValidateWaitFor(%q) = %q, %v, want %q", input, got, err, want)
+		}
+	}
+	for _, input := range []string{"", "Disk Transfer", "phase;rm"} {
+		if _, err := ValidateWaitFor(input); err == nil {
+			t.Errorf("ValidateWaitFor(%q) expected error", input)
+		}
+	}
+}
+
+func TestWaitReached(t *testing.T) {
+	running := testMigration([]string{"Running"},
+		stepVM("vm-1", "DiskTransfer", map[string]string{"DiskTransfer": "Running"}),
+		stepVM("vm-2", "DiskAllocation", map[string]string{"DiskTransfer": "Pending"}),
+	)
+	failedVMStatus := failedVM("3").(map[string]interface{})
+	failedVMStatus["name"] = "vm-3"
+	failed := testMigration([]string{"Failed"}, succeededVM("1"), failedVMStatus)
+	succeeded := testMigration([]string{"Succeeded"}, succeededVM("1"))
+
+	tests := []struct {
+		name      string
+		migration *unstructured.Unstructured
+		phase     string
+		reached   bool
+		err       string
+	}{
+		{"running not completed", running, WaitForCompleted, false, ""},
+		{"running reached", running, "Running", true, ""},
+		{"step not reached by every VM", running, "DiskTransfer", false, ""},
+		{"succeeded", succeeded, "Succeeded", true, ""},
+		{"failed while waiting for completion", failed, WaitForCompleted, true, "failed (failed VMs: vm-3)"},
+		{"waiting for failure", failed, "Failed", true, ""},
+		{"succeeded while waiting for failure", succeeded, "Failed", true, "finished as Succeeded, not Failed"},
+		{"failed before step", failed, "Cutover", true, "failed before reaching Cutover"},
+		{"succeeded without step", succeeded, "Cutover", true, "succeeded without reaching Cutover"},
+	}
+	for _, tt := range tests {
+		reached, err := WaitReached(tt.migration, tt.phase)
+		if reached != tt.reached {
+			t.Errorf("%s: reached = %v, want %v", tt.name, reached, tt.reached)
+		}
+		if tt.err == "" && err != nil {
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+		}
+		if tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)) {
+			t.Errorf("%s: error = %v, want %q", tt.name, err, tt.err)
+		}
+	}
+
+	// The step is reached once every VM still migrating started it
+	started := testMigration([]string{"Running"},
+		stepVM("vm-1", "DiskTransfer", map[string]string{"DiskTransfer": "Running"}),
+		stepVM("vm-2", "DiskTransfer", map[string]string{"DiskTransfer": "Completed"}),
+		failedVM("3"),
+	)
+	if reached, err := WaitReached(started, "disktransfer"); !reached || err != nil {
+		t.Errorf("step reached = %v, %v", reached, err)
+	}
+}
+
+func TestWaitProgress(t *testing.T) {
+	migration := testMigration([]string{"Running"},
+		stepVM("vm-1", "DiskTransfer", nil),
+		stepVM("vm-2", "DiskTransfer", nil),
+	)
+	got := WaitProgress(migration)
+	for _, want := range []string{"Migration big-plan-x1: Running", "VMs 0/2 completed", "DiskTransfer: 2"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("WaitProgress() = %q, missing %q", got, want)
+		}
+	}
+}
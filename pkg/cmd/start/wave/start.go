@@ -0,0 +1,150 @@
+package wave
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/klog/v2"
+
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/get/plan/status"
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/get/plan/targetlabels"
+	startplan "github.com/yaacov/kubectl-mtv/pkg/cmd/start/plan"
+	"github.com/yaacov/kubectl-mtv/pkg/util/client"
+	"github.com/yaacov/kubectl-mtv/pkg/util/output"
+	"github.com/yaacov/kubectl-mtv/pkg/util/telemetry"
+	wavepkg "github.com/yaacov/kubectl-mtv/pkg/util/wave"
+)
+
+// StartWaveOptions encapsulates the parameters for starting a migration wave.
+type StartWaveOptions struct {
+	Name        string
+	Namespace   string
+	ConfigFlags *genericclioptions.ConfigFlags
+	// Concurrency overrides the wave concurrency for parallel waves when positive
+	Concurrency int
+	// IgnoreSchedule starts the wave immediately even if its start time is in the future
+	IgnoreSchedule  bool
+	ContinueOnError bool
+	CutoverTime     *time.Time
+	UseUTC          bool
+}
+
+// Start runs the plans of a wave, sequentially or in parallel with a concurrency limit,
+// and waits until all of them finish. The plans are run like 'start plan --sequential',
+// so plan dependencies are respected and plans that already succeeded are skipped.
+func Start(ctx context.Context, opts StartWaveOptions) error {
+	c, err := client.GetDynamicClient(opts.ConfigFlags)
+	if err != nil {
+		return fmt.Errorf("failed to get client: %v", err)
+	}
+
+	w, err := wavepkg.Get(ctx, c, opts.Namespace, opts.Name)
+	if err != nil {
+		return err
+	}
+	if opts.Concurrency > 0 && w.Mode == wavepkg.ModeParallel {
+		w.Concurrency = opts.Concurrency
+	}
+	if err := w.Validate(); err != nil {
+		return err
+	}
+
+	// Wait for the scheduled start time
+	if w.StartAt != nil && !opts.IgnoreSchedule {
+		if delay := time.Until(*w.StartAt); delay > 0 {
+			fmt.Printf("Wave '%s' is scheduled to start at %s, waiting %s\n",
+				w.Name, output.FormatTimestamp(*w.StartAt, opts.UseUTC), delay.Round(time.Second))
+			select {
+			case <-ctx.Done():
+				return fmt.Errorf("wave '%s' was not started: %v", w.Name, ctx.Err())
+			case <-time.After(delay):
+			}
+		}
+	}
+
+	startedAt := time.Now()
+	w.StartedAt = &startedAt
+	w.CompletedAt = nil
+	w.Result = ""
+	if err := wavepkg.UpdateRunState(ctx, c, w); err != nil {
+		return err
+	}
+
+	fmt.Printf("Starting wave '%s' (%s, %d plan(s), up to %d at a time)\n", w.Name, w.Mode, len(w.Plans), w.Limit())
+
+	onFailure := startplan.FailureAbort
+	if opts.ContinueOnError {
+		onFailure = startplan.FailureContinue
+	}
+	start := func(ctx context.Context, planName string) error {
+		return startPlan(ctx, opts, planName)
+	}
+	results, runErr := startplan.RunPlans(ctx, opts.ConfigFlags, opts.Namespace, w.Plans, start, startplan.SequenceOptions{
+		OnFailure:   onFailure,
+		Concurrency: w.Limit(),
+		Out:         os.Stderr,
+	})
+
+	failed := startplan.PrintResults(os.Stdout, results)
+	for _, result := range results {
+		recordPlanResult(ctx, c, opts.Namespace, result)
+	}
+	if runErr != nil {
+		failed = len(w.Plans)
+	}
+
+	completedAt := time.Now()
+	w.CompletedAt = &completedAt
+	w.Result = status.StatusSucceeded
+	if failed > 0 {
+		w.Result = status.StatusFailed
+	}
+	// Record the result even if the run was interrupted
+	if err := wavepkg.UpdateRunState(context.Background(), c, w); err != nil {
+		klog.V(1).Infof("Failed to record result of wave '%s': %v", w.Name, err)
+	}
+
+	if runErr != nil {
+		return fmt.Errorf("wave '%s' was not started: %v", w.Name, runErr)
+	}
+	if failed > 0 {
+		return fmt.Errorf("wave '%s' finished with %d plan(s) that did not succeed", w.Name, failed)
+	}
+	fmt.Printf("Wave '%s' completed successfully in %s\n", w.Name, completedAt.Sub(startedAt).Round(time.Second))
+	return nil
+}
+
+// startPlan starts a plan of the wave, in batches when the plan limits its concurrent VMs
+func startPlan(ctx context.Context, opts StartWaveOptions, planName string) error {
+	c, err := client.GetDynamicClient(opts.ConfigFlags)
+	if err != nil {
+		return fmt.Errorf("failed to get client: %v", err)
+	}
+	plan, err := c.Resource(client.PlansGVR).Namespace(opts.Namespace).Get(ctx, planName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get plan: %v", err)
+	}
+	if limit := startplan.MaxConcurrentVMs(plan); limit > 0 {
+		return startplan.StartBatched(ctx, opts.ConfigFlags, planName, opts.Namespace, limit, opts.CutoverTime, opts.UseUTC, false, "")
+	}
+	return startplan.Start(opts.ConfigFlags, planName, opts.Namespace, opts.CutoverTime, opts.UseUTC, false, "")
+}
+
+// recordPlanResult reports the migration outcome of a plan run by the wave and verifies the
+// target VM labels of the plans that succeeded
+func recordPlanResult(ctx context.Context, c dynamic.Interface, namespace string, result startplan.SequenceResult) {
+	switch result.Outcome {
+	case startplan.OutcomeSucceeded:
+		telemetry.RecordMigration(status.StatusSucceeded, result.Duration)
+		if err := targetlabels.EnsureAfterMigration(ctx, c, namespace, result.Name); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to verify target VM labels of plan '%s': %v\n", result.Name, err)
+		}
+	case startplan.OutcomeFailed:
+		telemetry.RecordMigration(status.StatusFailed, result.Duration)
+	}
+}
@@ -0,0 +1,428 @@
+package hook
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/yaml"
+
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/get/inventory"
+	"github.com/yaacov/kubectl-mtv/pkg/util/client"
+)
+
+// DefaultImage is the image Forklift runs local hooks with
+const DefaultImage = "quay.io/kubev2v/hook-runner"
+
+// DefaultTimeout bounds a test run when neither --timeout nor the hook deadline is set
+const DefaultTimeout = 5 * time.Minute
+
+// TestLabel marks the pods and config maps created by hook test runs
+const TestLabel = "kubectl-mtv/hook-test"
+
+// mountPath is where Forklift mounts the playbook and the payloads in hook pods
+const mountPath = "/tmp/hook"
+
+// pollInterval is how often the pod phase is checked
+var pollInterval = time.Second
+
+// Options selects the hook and the payloads it is run with
+type Options struct {
+	Name      string
+	Namespace string
+	// WorkloadFile is a workload.yml payload; a sample VM is used when empty
+	WorkloadFile string
+	// Provider and VM read the workload payload of a real VM from the inventory
+	Provider                 string
+	VM                       string
+	InventoryURL             string
+	InventoryInsecureSkipTLS bool
+	// Plan is an existing plan passed as plan.yml; a sample plan is used when empty
+	Plan    string
+	Timeout time.Duration
+	// Keep leaves the pod and config map in place for inspection
+	Keep bool
+	Out  io.Writer
+}
+
+// Run is a hook resolved to what its pod runs
+type Run struct {
+	Hook           string
+	Namespace      string
+	Image          string
+	ServiceAccount string
+	Deadline       int64
+	Playbook       string
+	Workload       string
+	Plan           string
+}
+
+// Test runs a hook once in a throwaway pod and reports how it ended
+func Test(ctx context.Context, configFlags *genericclioptions.ConfigFlags, opts Options) error {
+	c, err := client.GetDynamicClient(configFlags)
+	if err != nil {
+		return fmt.Errorf("failed to get client: %v", err)
+	}
+
+	hook, err := c.Resource(client.HooksGVR).Namespace(opts.Namespace).Get(ctx, opts.Name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get hook '%s': %v", opts.Name, err)
+	}
+
+	run, err := resolveHook(hook)
+	if err != nil {
+		return err
+	}
+
+	if run.Workload, err = loadWorkload(ctx, configFlags, opts); err != nil {
+		return err
+	}
+
+	if opts.Plan != "" {
+		plan, err := c.Resource(client.PlansGVR).Namespace(opts.Namespace).Get(ctx, opts.Plan, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to get plan '%s': %v", opts.Plan, err)
+		}
+		unstructured.RemoveNestedField(plan.Object, "metadata", "managedFields")
+		data, err := yaml.Marshal(plan.Object)
+		if err != nil {
+			return fmt.Errorf("failed to encode plan '%s': %v", opts.Plan, err)
+		}
+		run.Plan = string(data)
+	} else {
+		run.Plan = samplePlan(run.Hook, run.Namespace)
+	}
+
+	clientset, err := client.GetKubernetesClientset(configFlags)
+	if err != nil {
+		return fmt.Errorf("failed to get kubernetes client: %v", err)
+	}
+
+	return Execute(ctx, clientset, run, opts)
+}
+
+// resolveHook reads the image, playbook and limits of a local hook
+func resolveHook(hook *unstructured.Unstructured) (*Run, error) {
+	if _, found, _ := unstructured.NestedMap(hook.Object, "spec", "aap"); found {
+		return nil, fmt.Errorf("hook '%s' runs an AAP job template; only local hooks can be tested", hook.GetName())
+	}
+
+	run := &Run{Hook: hook.GetName(), Namespace: hook.GetNamespace()}
+	run.Image, _, _ = unstructured.NestedString(hook.Object, "spec", "image")
+	if run.Image == "" {
+		run.Image = DefaultImage
+	}
+	run.ServiceAccount, _, _ = unstructured.NestedString(hook.Object, "spec", "serviceAccount")
+	run.Deadline, _, _ = unstructured.NestedInt64(hook.Object, "spec", "deadline")
+
+	encoded, _, _ := unstructured.NestedString(hook.Object, "spec", "playbook")
+	if encoded != "" {
+		playbook, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode the playbook of hook '%s': %v", hook.GetName(), err)
+		}
+		run.Playbook = string(playbook)
+	}
+
+	return run, nil
+}
+
+// loadWorkload returns the workload.yml payload from a file, the inventory or the sample
+func loadWorkload(ctx context.Context, configFlags *genericclioptions.ConfigFlags, opts Options) (string, error) {
+	switch {
+	case opts.WorkloadFile != "":
+		data, err := os.ReadFile(opts.WorkloadFile)
+		if err != nil {
+			return "", fmt.Errorf("failed to read workload file: %v", err)
+		}
+		var workload interface{}
+		if err := yaml.Unmarshal(data, &workload); err != nil {
+			return "", fmt.Errorf("failed to parse workload file %s: %v", opts.WorkloadFile, err)
+		}
+		return string(data), nil
+
+	case opts.Provider != "":
+		provider, err := inventory.GetProviderByName(ctx, configFlags, opts.Provider, opts.Namespace)
+		if err != nil {
+			return "", err
+		}
+		providerClient := inventory.NewProviderClientWithInsecure(configFlags, provider, opts.InventoryURL, opts.InventoryInsecureSkipTLS)
+		workload, err := providerClient.GetWorkload(ctx, opts.VM, 4)
+		if err != nil {
+			return "", fmt.Errorf("failed to get workload of VM '%s' from provider '%s': %v", opts.VM, opts.Provider, err)
+		}
+		data, err := yaml.Marshal(workload)
+		if err != nil {
+			return "", fmt.Errorf("failed to encode workload: %v", err)
+		}
+		return string(data), nil
+
+	default:
+		return sampleWorkload, nil
+	}
+}
+
+// Execute creates the payload config map and hook pod, streams the pod logs and
+// reports the exit status. The pod and config map are removed afterwards unless Keep is set.
+func Execute(ctx context.Context, clientset kubernetes.Interface, run *Run, opts Options) error {
+	out := opts.Out
+	if out == nil {
+		out = os.Stdout
+	}
+
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+		if run.Deadline > 0 {
+			// Leave time for the image pull on top of the hook deadline
+			timeout = time.Duration(run.Deadline)*time.Second + time.Minute
+		}
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	name := fmt.Sprintf("hook-test-%s-%s", run.Hook, utilrand.String(5))
+	if len(name) > 63 {
+		name = fmt.Sprintf("hook-test-%s-%s", strings.TrimRight(run.Hook[:47], "-."), utilrand.String(5))
+	}
+	labels := map[string]string{TestLabel: run.Hook}
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: run.Namespace, Labels: labels},
+		Data: map[string]string{
+			"workload.yml": run.Workload,
+			"plan.yml":     run.Plan,
+		},
+	}
+	if run.Playbook != "" {
+		configMap.Data["playbook.yml"] = run.Playbook
+	}
+	if _, err := clientset.CoreV1().ConfigMaps(run.Namespace).Create(ctx, configMap, metav1.CreateOptions{}); err != nil {
+		return fmt.Errorf("failed to create config map '%s': %v", name, err)
+	}
+
+	pod, err := clientset.CoreV1().Pods(run.Namespace).Create(ctx, hookPod(name, labels, run), metav1.CreateOptions{})
+	if err != nil {
+		deleteConfigMap(clientset, run.Namespace, name)
+		return fmt.Errorf("failed to create pod '%s': %v", name, err)
+	}
+
+	if opts.Keep {
+		defer fmt.Fprintf(out, "Kept pod '%s' and config map '%s' in namespace '%s'\n", name, name, run.Namespace)
+	} else {
+		defer func() {
+			// The run context may have expired, clean up with a fresh one
+			cleanupCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+			_ = clientset.CoreV1().Pods(run.Namespace).Delete(cleanupCtx, name, metav1.DeleteOptions{})
+			deleteConfigMap(clientset, run.Namespace, name)
+		}()
+	}
+
+	fmt.Fprintf(out, "Running hook '%s' in pod '%s' (image %s)\n", run.Hook, name, run.Image)
+	start := time.Now()
+
+	if pod, err = waitForPod(ctx, clientset, pod, started); err != nil {
+		return err
+	}
+
+	if pod.Status.Phase == corev1.PodRunning || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+		if err := streamLogs(ctx, clientset, pod, out); err != nil {
+			fmt.Fprintf(out, "Warning: failed to stream logs: %v\n", err)
+		}
+	}
+
+	if pod, err = waitForPod(ctx, clientset, pod, finished); err != nil {
+		return err
+	}
+
+	exitCode, reason := exitStatus(pod)
+	elapsed := time.Since(start).Round(time.Second)
+	if pod.Status.Phase == corev1.PodSucceeded {
+		fmt.Fprintf(out, "Hook '%s' succeeded in %s (exit code %d)\n", run.Hook, elapsed, exitCode)
+		return nil
+	}
+	if reason != "" {
+		return fmt.Errorf("hook '%s' failed after %s: exit code %d (%s)", run.Hook, elapsed, exitCode, reason)
+	}
+	return fmt.Errorf("hook '%s' failed after %s: exit code %d", run.Hook, elapsed, exitCode)
+}
+
+// hookPod builds a pod like the one Forklift runs for a hook step
+func hookPod(name string, labels map[string]string, run *Run) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: run.Namespace, Labels: labels},
+		Spec: corev1.PodSpec{
+			RestartPolicy:      corev1.RestartPolicyNever,
+			ServiceAccountName: run.ServiceAccount,
+			Containers: []corev1.Container{{
+				Name:  "hook",
+				Image: run.Image,
+				VolumeMounts: []corev1.VolumeMount{{
+					Name:      "hook",
+					MountPath: mountPath,
+				}},
+			}},
+			Volumes: []corev1.Volume{{
+				Name: "hook",
+				VolumeSource: corev1.VolumeSource{
+					ConfigMap: &corev1.ConfigMapVolumeSource{
+						LocalObjectReference: corev1.LocalObjectReference{Name: name},
+					},
+				},
+			}},
+		},
+	}
+	if run.Deadline > 0 {
+		pod.Spec.ActiveDeadlineSeconds = &run.Deadline
+	}
+	return pod
+}
+
+// started reports whether the hook container is running or done, and fails
+// on waiting reasons that never resolve on their own
+func started(pod *corev1.Pod) (bool, error) {
+	if pod.Status.Phase != corev1.PodPending {
+		return true, nil
+	}
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.State.Waiting == nil {
+			continue
+		}
+		switch status.State.Waiting.Reason {
+		case "ErrImagePull", "ImagePullBackOff", "InvalidImageName", "CreateContainerConfigError", "CreateContainerError":
+			return false, fmt.Errorf("hook container cannot start: %s: %s", status.State.Waiting.Reason, status.State.Waiting.Message)
+		}
+	}
+	return false, nil
+}
+
+// finished reports whether the pod has ended
+func finished(pod *corev1.Pod) (bool, error) {
+	return pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed, nil
+}
+
+// waitForPod polls the pod until done reports true or the context ends
+func waitForPod(ctx context.Context, clientset kubernetes.Interface, pod *corev1.Pod, done func(*corev1.Pod) (bool, error)) (*corev1.Pod, error) {
+	for {
+		ok, err := done(pod)
+		if err != nil || ok {
+			return pod, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return pod, fmt.Errorf("timed out waiting for pod '%s' (phase %s)", pod.Name, pod.Status.Phase)
+		case <-time.After(pollInterval):
+		}
+
+		pod, err = clientset.CoreV1().Pods(pod.Namespace).Get(ctx, pod.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get pod: %v", err)
+		}
+	}
+}
+
+// streamLogs follows the hook container logs until the container exits
+func streamLogs(ctx context.Context, clientset kubernetes.Interface, pod *corev1.Pod, out io.Writer) error {
+	stream, err := clientset.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{
+		Container: "hook",
+		Follow:    true,
+	}).Stream(ctx)
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+
+	_, err = io.Copy(out, stream)
+	return err
+}
+
+// exitStatus returns the exit code of the hook container and why the pod ended
+func exitStatus(pod *corev1.Pod) (int32, string) {
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name == "hook" && status.State.Terminated != nil {
+			terminated := status.State.Terminated
+			reason := terminated.Reason
+			if terminated.Message != "" {
+				reason = strings.TrimSpace(reason + ": " + terminated.Message)
+			}
+			return terminated.ExitCode, reason
+		}
+	}
+	// Pods stopped by activeDeadlineSeconds may have no terminated state
+	return -1, strings.TrimSpace(pod.Status.Reason + " " + pod.Status.Message)
+}
+
+func deleteConfigMap(clientset kubernetes.Interface, namespace, name string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	_ = clientset.CoreV1().ConfigMaps(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+}
+
+// sampleWorkload is a vSphere VM in the shape of the inventory workload payload
+const sampleWorkload = `vm:
+  id: vm-1
+  name: sample-vm
+  path: /Datacenter/vm/sample-vm
+  powerState: poweredOn
+  guestId: rhel9_64Guest
+  ipAddress: 192.0.2.10
+  cpuCount: 2
+  coresPerSocket: 1
+  memoryMB: 4096
+  firmware: efi
+  disks:
+    - key: 2000
+      file: '[datastore1] sample-vm/sample-vm.vmdk'
+      datastore:
+        kind: Datastore
+        id: datastore-1
+      capacity: 21474836480
+  nics:
+    - mac: 00:50:56:00:00:01
+      network:
+        kind: Network
+        id: network-1
+  guestNetworks:
+    - device: "4000"
+      mac: 00:50:56:00:00:01
+      ip: 192.0.2.10
+      origin: manual
+      prefix: 24
+      dns:
+        - 192.0.2.1
+  host:
+    id: host-1
+    name: esxi-1.example.com
+`
+
+// samplePlan is a minimal plan that runs the hook for the sample VM
+func samplePlan(hook, namespace string) string {
+	return fmt.Sprintf(`apiVersion: forklift.konveyor.io/v1beta1
+kind: Plan
+metadata:
+  name: hook-test
+  namespace: %[2]s
+spec:
+  targetNamespace: %[2]s
+  vms:
+    - id: vm-1
+      name: sample-vm
+      hooks:
+        - hook:
+            name: %[1]s
+            namespace: %[2]s
+          step: PreHook
+`, hook, namespace)
+}
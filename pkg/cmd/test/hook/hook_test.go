@@ -0,0 +1,144 @@
+package hook
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// fakeCluster returns a clientset whose pods end as soon as they are created
+func fakeCluster(exitCode int32) *fake.Clientset {
+	clientset := fake.NewSimpleClientset()
+	clientset.PrependReactor("create", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		pod := action.(k8stesting.CreateAction).GetObject().(*corev1.Pod)
+		pod.Status.Phase = corev1.PodSucceeded
+		reason := "Completed"
+		if exitCode != 0 {
+			pod.Status.Phase = corev1.PodFailed
+			reason = "Error"
+		}
+		pod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+			Name:  "hook",
+			State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: exitCode, Reason: reason}},
+		}}
+		return false, nil, nil
+	})
+	return clientset
+}
+
+func testRun() *Run {
+	return &Run{
+		Hook:      "pre-hook",
+		Namespace: "demo",
+		Image:     DefaultImage,
+		Deadline:  300,
+		Playbook:  "- hosts: localhost\n  tasks: []\n",
+		Workload:  sampleWorkload,
+		Plan:      samplePlan("pre-hook", "demo"),
+	}
+}
+
+func TestExecute_Succeeded(t *testing.T) {
+	clientset := fakeCluster(0)
+	var out bytes.Buffer
+
+	if err := Execute(context.Background(), clientset, testRun(), Options{Keep: true, Out: &out}); err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if !strings.Contains(out.String(), "Hook 'pre-hook' succeeded") || !strings.Contains(out.String(), "fake logs") {
+		t.Errorf("output = %q, want the logs and the success", out.String())
+	}
+
+	pods, _ := clientset.CoreV1().Pods("demo").List(context.Background(), metav1.ListOptions{})
+	if len(pods.Items) != 1 {
+		t.Fatalf("pods = %d, want the kept hook pod", len(pods.Items))
+	}
+	pod := pods.Items[0]
+	if pod.Labels[TestLabel] != "pre-hook" || pod.Spec.RestartPolicy != corev1.RestartPolicyNever {
+		t.Errorf("pod labels = %v, restart policy = %s", pod.Labels, pod.Spec.RestartPolicy)
+	}
+	if pod.Spec.ActiveDeadlineSeconds == nil || *pod.Spec.ActiveDeadlineSeconds != 300 {
+		t.Errorf("activeDeadlineSeconds = %v, want the hook deadline", pod.Spec.ActiveDeadlineSeconds)
+	}
+	if mounts := pod.Spec.Containers[0].VolumeMounts; len(mounts) != 1 || mounts[0].MountPath != mountPath {
+		t.Errorf("volume mounts = %v, want the payloads at %s", mounts, mountPath)
+	}
+
+	configMap, err := clientset.CoreV1().ConfigMaps("demo").Get(context.Background(), pod.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("config map not found: %v", err)
+	}
+	for _, key := range []string{"workload.yml", "plan.yml", "playbook.yml"} {
+		if configMap.Data[key] == "" {
+			t.Errorf("config map is missing %s", key)
+		}
+	}
+}
+
+func TestExecute_FailedIsCleanedUp(t *testing.T) {
+	clientset := fakeCluster(2)
+
+	err := Execute(context.Background(), clientset, testRun(), Options{Out: &bytes.Buffer{}})
+	if err == nil || !strings.Contains(err.Error(), "exit code 2") {
+		t.Fatalf("Execute() error = %v, want the exit code", err)
+	}
+
+	pods, _ := clientset.CoreV1().Pods("demo").List(context.Background(), metav1.ListOptions{})
+	configMaps, _ := clientset.CoreV1().ConfigMaps("demo").List(context.Background(), metav1.ListOptions{})
+	if len(pods.Items) != 0 || len(configMaps.Items) != 0 {
+		t.Errorf("pods = %d, config maps = %d, want both deleted", len(pods.Items), len(configMaps.Items))
+	}
+}
+
+func TestExecute_ImagePullFailsFast(t *testing.T) {
+	pollInterval = 10 * time.Millisecond
+	defer func() { pollInterval = time.Second }()
+
+	clientset := fake.NewSimpleClientset()
+	clientset.PrependReactor("create", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		pod := action.(k8stesting.CreateAction).GetObject().(*corev1.Pod)
+		pod.Status.Phase = corev1.PodPending
+		pod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+			Name:  "hook",
+			State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff", Message: "not found"}},
+		}}
+		return false, nil, nil
+	})
+
+	err := Execute(context.Background(), clientset, testRun(), Options{Out: &bytes.Buffer{}})
+	if err == nil || !strings.Contains(err.Error(), "ImagePullBackOff") {
+		t.Fatalf("Execute() error = %v, want the image pull failure", err)
+	}
+}
+
+func TestResolveHook(t *testing.T) {
+	hook := &unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "h1", "namespace": "demo"},
+		"spec": map[string]interface{}{
+			"serviceAccount": "hook-sa",
+			"playbook":       "LSBob3N0czogbG9jYWxob3N0Cg==",
+		},
+	}}
+
+	run, err := resolveHook(hook)
+	if err != nil {
+		t.Fatalf("resolveHook() unexpected error: %v", err)
+	}
+	if run.Image != DefaultImage || run.ServiceAccount != "hook-sa" || run.Playbook != "- hosts: localhost\n" {
+		t.Errorf("resolveHook() = %+v", run)
+	}
+
+	hook.Object["spec"] = map[string]interface{}{"aap": map[string]interface{}{"jobTemplateId": int64(42)}}
+	if _, err := resolveHook(hook); err == nil || !strings.Contains(err.Error(), "AAP") {
+		t.Errorf("resolveHook() error = %v, want AAP hooks refused", err)
+	}
+}
@@ -0,0 +1,115 @@
+package plan
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	report "github.com/yaacov/kubectl-mtv/pkg/cmd/report/plan"
+	"github.com/yaacov/kubectl-mtv/pkg/util/output"
+	"github.com/yaacov/kubectl-mtv/pkg/util/watch"
+)
+
+// Print shows the resource usage of the selected plans. Throughput needs two samples: the
+// first collection is repeated after sampleInterval, and in watch mode each refresh is
+// compared with the previous one. A zero sampleInterval shows throughput only in watch mode.
+func Print(ctx context.Context, opts Options, outputFormat string, watchMode bool, sampleInterval time.Duration) error {
+	outputFormat = strings.ToLower(outputFormat)
+	if outputFormat != "table" && outputFormat != "json" && outputFormat != "yaml" {
+		return fmt.Errorf("unsupported output format: %s. Supported formats: table, json, yaml", outputFormat)
+	}
+
+	sampler := NewSampler()
+	return watch.WrapWithWatch(watchMode, outputFormat, func() error {
+		if !sampler.HasSamples() && sampleInterval > 0 {
+			usages, err := Collect(ctx, opts)
+			if err != nil {
+				return err
+			}
+			sampler.Record(usages, time.Now())
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(sampleInterval):
+			}
+		}
+
+		usages, err := Collect(ctx, opts)
+		if err != nil {
+			return err
+		}
+		sampler.Record(usages, time.Now())
+		return printUsages(usages, outputFormat, opts)
+	}, watch.DefaultInterval)
+}
+
+func printUsages(usages []Usage, outputFormat string, opts Options) error {
+	emptyMessage := "No running migrations found"
+	if opts.Namespace != "" {
+		emptyMessage += " in namespace " + opts.Namespace
+	}
+
+	items := make([]map[string]interface{}, 0, len(usages))
+	for _, u := range usages {
+		items = append(items, usageItem(u))
+	}
+
+	switch outputFormat {
+	case "json":
+		return output.PrintJSONWithEmpty(items, emptyMessage)
+	case "yaml":
+		return output.PrintYAMLWithEmpty(items, emptyMessage)
+	}
+
+	columns := []output.Column{
+		{Title: "PLAN", Key: "name"},
+		{Title: "IMPORTERS", Key: "importerPods"},
+		{Title: "CONVERSIONS", Key: "conversionPods"},
+		{Title: "CPU-REQ", Key: "cpuRequest"},
+		{Title: "MEM-REQ", Key: "memoryRequest"},
+		{Title: "PVCS", Key: "pvcs"},
+		{Title: "PROVISIONED", Key: "provisioned"},
+		{Title: "COPIED", Key: "copied"},
+		{Title: "THROUGHPUT", Key: "throughput"},
+	}
+	if opts.Namespace == "" {
+		columns = append([]output.Column{{Title: "NAMESPACE", Key: "namespace"}}, columns...)
+	}
+	return output.PrintTableWithQuery(items, columns, nil, emptyMessage)
+}
+
+// usageItem returns the printable fields of a plan usage, raw values and human readable ones
+func usageItem(u Usage) map[string]interface{} {
+	item := map[string]interface{}{
+		"name":               u.Name,
+		"namespace":          u.Namespace,
+		"targetNamespace":    u.TargetNamespace,
+		"migration":          u.Migration,
+		"importerPods":       u.ImporterPods,
+		"conversionPods":     u.ConversionPods,
+		"cpuRequestMilli":    u.CPURequestMilli,
+		"memoryRequestBytes": u.MemoryRequest,
+		"pvcs":               u.PVCs,
+		"provisionedBytes":   u.ProvisionedBytes,
+		"copiedBytes":        u.CopiedBytes,
+		"cpuRequest":         formatMilliCPU(u.CPURequestMilli),
+		"memoryRequest":      report.FormatBytes(u.MemoryRequest),
+		"provisioned":        report.FormatBytes(u.ProvisionedBytes),
+		"copied":             report.FormatBytes(u.CopiedBytes),
+		"throughput":         "-",
+	}
+	if u.Throughput != nil {
+		item["throughputBytesPerSecond"] = *u.Throughput
+		item["throughput"] = report.FormatBytes(int64(*u.Throughput)) + "/s"
+	}
+	return item
+}
+
+// formatMilliCPU formats CPU millicores like Kubernetes quantities ("500m", "2")
+func formatMilliCPU(milli int64) string {
+	if milli%1000 == 0 {
+		return fmt.Sprintf("%d", milli/1000)
+	}
+	return fmt.Sprintf("%dm", milli)
+}
@@ -0,0 +1,324 @@
+package plan
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/get/plan/status"
+	"github.com/yaacov/kubectl-mtv/pkg/util/client"
+	"github.com/yaacov/kubectl-mtv/pkg/util/fields"
+)
+
+// dataVolumesGVR is used to read the progress of the DataVolumes created for the migrated disks
+var dataVolumesGVR = schema.GroupVersionResource{
+	Group:    "cdi.kubevirt.io",
+	Version:  "v1beta1",
+	Resource: "datavolumes",
+}
+
+// cdiImporterSelector selects the CDI importer pods that copy disk data into the target PVCs
+const cdiImporterSelector = "app=containerized-data-importer"
+
+// Usage is the resource consumption of the migration infrastructure of one plan
+type Usage struct {
+	Name            string `json:"name"`
+	Namespace       string `json:"namespace"`
+	TargetNamespace string `json:"targetNamespace"`
+	Migration       string `json:"migration,omitempty"`
+	// ImporterPods are the running disk transfer pods (CDI importers and populators)
+	ImporterPods int `json:"importerPods"`
+	// ConversionPods are the running virt-v2v guest conversion pods
+	ConversionPods   int   `json:"conversionPods"`
+	CPURequestMilli  int64 `json:"cpuRequestMilli"`
+	MemoryRequest    int64 `json:"memoryRequestBytes"`
+	PVCs             int   `json:"pvcs"`
+	ProvisionedBytes int64 `json:"provisionedBytes"`
+	// CopiedBytes is estimated from the DataVolume progress
+	CopiedBytes int64 `json:"copiedBytes"`
+	// Throughput is the copy rate since the previous sample, nil before the second sample
+	Throughput *float64 `json:"throughputBytesPerSecond,omitempty"`
+}
+
+// Options selects the plans to show
+type Options struct {
+	ConfigFlags *genericclioptions.ConfigFlags
+	// Name is the plan to show; empty shows all plans with a running migration
+	Name string
+	// Namespace of the plans; empty for all namespaces
+	Namespace string
+}
+
+// Collect returns the resource usage of the selected plans
+func Collect(ctx context.Context, opts Options) ([]Usage, error) {
+	c, err := client.GetDynamicClient(opts.ConfigFlags)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get client: %v", err)
+	}
+	clientset, err := client.GetKubernetesClientset(opts.ConfigFlags)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get kubernetes client: %v", err)
+	}
+
+	var plans []unstructured.Unstructured
+	if opts.Name != "" {
+		plan, err := c.Resource(client.PlansGVR).Namespace(opts.Namespace).Get(ctx, opts.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get plan '%s': %v", opts.Name, err)
+		}
+		plans = append(plans, *plan)
+	} else {
+		list, err := c.Resource(client.PlansGVR).Namespace(opts.Namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list plans: %v", err)
+		}
+		plans = list.Items
+	}
+
+	usages := []Usage{}
+	for i := range plans {
+		plan := &plans[i]
+		running, _, err := status.GetRunningMigration(c, plan.GetNamespace(), plan, client.MigrationsGVR)
+		if err != nil {
+			return nil, err
+		}
+		if running == nil {
+			// A plan asked for by name is shown idle
+			if opts.Name != "" {
+				usages = append(usages, Usage{Name: plan.GetName(), Namespace: plan.GetNamespace(), TargetNamespace: fields.TargetNamespace(plan)})
+			}
+			continue
+		}
+		usages = append(usages, collectPlan(ctx, c, clientset, plan, running))
+	}
+
+	sort.Slice(usages, func(i, j int) bool {
+		if usages[i].Namespace != usages[j].Namespace {
+			return usages[i].Namespace < usages[j].Namespace
+		}
+		return usages[i].Name < usages[j].Name
+	})
+	return usages, nil
+}
+
+// collectPlan lists the pods, PVCs and DataVolumes of a running migration. Missing
+// permissions or a missing CDI installation leave the affected columns empty.
+func collectPlan(ctx context.Context, c dynamic.Interface, clientset kubernetes.Interface, plan, migration *unstructured.Unstructured) Usage {
+	namespace := fields.TargetNamespace(plan)
+	selector := metav1.ListOptions{LabelSelector: fmt.Sprintf("plan=%s,migration=%s", plan.GetUID(), migration.GetUID())}
+
+	var planPods, importerPods []corev1.Pod
+	if list, err := clientset.CoreV1().Pods(namespace).List(ctx, selector); err == nil {
+		planPods = list.Items
+	} else {
+		klog.V(1).Infof("Failed to list migration pods in '%s': %v", namespace, err)
+	}
+	if list, err := clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: cdiImporterSelector}); err == nil {
+		importerPods = list.Items
+	} else {
+		klog.V(1).Infof("Failed to list importer pods in '%s': %v", namespace, err)
+	}
+
+	var pvcs []corev1.PersistentVolumeClaim
+	if list, err := clientset.CoreV1().PersistentVolumeClaims(namespace).List(ctx, selector); err == nil {
+		pvcs = list.Items
+	} else {
+		klog.V(1).Infof("Failed to list PVCs in '%s': %v", namespace, err)
+	}
+
+	var dataVolumes []unstructured.Unstructured
+	if list, err := c.Resource(dataVolumesGVR).Namespace(namespace).List(ctx, selector); err == nil {
+		dataVolumes = list.Items
+	} else {
+		klog.V(1).Infof("Failed to list DataVolumes in '%s': %v", namespace, err)
+	}
+
+	usage := Build(planPods, importerPods, pvcs, dataVolumes)
+	usage.Name = plan.GetName()
+	usage.Namespace = plan.GetNamespace()
+	usage.TargetNamespace = namespace
+	usage.Migration = migration.GetName()
+	return usage
+}
+
+// Build aggregates the running pods, PVCs and DataVolumes of a migration. planPods are the pods
+// labeled with the plan and migration; importerPods are the CDI importer pods of the target
+// namespace, counted when they import into one of the migration PVCs.
+func Build(planPods, importerPods []corev1.Pod, pvcs []corev1.PersistentVolumeClaim, dataVolumes []unstructured.Unstructured) Usage {
+	usage := Usage{PVCs: len(pvcs)}
+
+	pvcNames := map[string]bool{}
+	var pvcUIDs []string
+	for i := range pvcs {
+		pvc := &pvcs[i]
+		pvcNames[pvc.Name] = true
+		pvcUIDs = append(pvcUIDs, string(pvc.UID))
+		usage.ProvisionedBytes += pvcBytes(pvc)
+	}
+
+	addRequests := func(pod *corev1.Pod) {
+		for _, container := range pod.Spec.Containers {
+			usage.CPURequestMilli += container.Resources.Requests.Cpu().MilliValue()
+			usage.MemoryRequest += container.Resources.Requests.Memory().Value()
+		}
+	}
+
+	for i := range planPods {
+		pod := &planPods[i]
+		if pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		if isConversionPod(pod) {
+			usage.ConversionPods++
+		} else {
+			usage.ImporterPods++
+		}
+		addRequests(pod)
+	}
+	for i := range importerPods {
+		pod := &importerPods[i]
+		if pod.Status.Phase != corev1.PodRunning || !importsInto(pod, pvcNames, pvcUIDs) {
+			continue
+		}
+		usage.ImporterPods++
+		addRequests(pod)
+	}
+
+	for i := range dataVolumes {
+		usage.CopiedBytes += CopiedBytes(&dataVolumes[i])
+	}
+	return usage
+}
+
+// isConversionPod reports whether a migration pod runs virt-v2v
+func isConversionPod(pod *corev1.Pod) bool {
+	for _, container := range pod.Spec.Containers {
+		if strings.Contains(container.Name, "virt-v2v") {
+			return true
+		}
+	}
+	return false
+}
+
+// importsInto reports whether a CDI importer pod writes one of the PVCs. Importers are owned
+// by the PVC, or by its "prime-<PVC UID>" scratch PVC when populating through a prime PVC.
+func importsInto(pod *corev1.Pod, pvcNames map[string]bool, pvcUIDs []string) bool {
+	for _, owner := range pod.OwnerReferences {
+		if owner.Kind == "PersistentVolumeClaim" && pvcNames[owner.Name] {
+			return true
+		}
+	}
+	if pvcNames[strings.TrimPrefix(pod.Name, "importer-")] {
+		return true
+	}
+	for _, uid := range pvcUIDs {
+		if uid != "" && strings.Contains(pod.Name, uid) {
+			return true
+		}
+	}
+	return false
+}
+
+// pvcBytes returns the provisioned size of a PVC, or its requested size while it is not bound
+func pvcBytes(pvc *corev1.PersistentVolumeClaim) int64 {
+	if capacity, ok := pvc.Status.Capacity[corev1.ResourceStorage]; ok {
+		return capacity.Value()
+	}
+	if request, ok := pvc.Spec.Resources.Requests[corev1.ResourceStorage]; ok {
+		return request.Value()
+	}
+	return 0
+}
+
+// CopiedBytes estimates the bytes a DataVolume has copied from its size and progress ("45.50%")
+func CopiedBytes(dv *unstructured.Unstructured) int64 {
+	size, _, _ := unstructured.NestedString(dv.Object, "spec", "storage", "resources", "requests", "storage")
+	if size == "" {
+		size, _, _ = unstructured.NestedString(dv.Object, "spec", "pvc", "resources", "requests", "storage")
+	}
+	q, err := resource.ParseQuantity(size)
+	if err != nil {
+		return 0
+	}
+
+	phase, _, _ := unstructured.NestedString(dv.Object, "status", "phase")
+	if phase == "Succeeded" {
+		return q.Value()
+	}
+	progress, _, _ := unstructured.NestedString(dv.Object, "status", "progress")
+	percent, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(progress), "%"), 64)
+	if err != nil || percent <= 0 {
+		return 0
+	}
+	if percent > 100 {
+		percent = 100
+	}
+	return int64(float64(q.Value()) * percent / 100)
+}
+
+// sample is the copied bytes of a migration at a point in time
+type sample struct {
+	migration string
+	bytes     int64
+	at        time.Time
+}
+
+// Sampler derives throughput from the copied bytes of successive collections
+type Sampler struct {
+	mu       sync.Mutex
+	previous map[string]sample
+}
+
+// NewSampler returns a sampler without previous samples
+func NewSampler() *Sampler {
+	return &Sampler{previous: map[string]sample{}}
+}
+
+// HasSamples reports whether a previous collection was recorded
+func (s *Sampler) HasSamples() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.previous) > 0
+}
+
+// Record sets the throughput of the usages from the previous sample of the same migration
+// and remembers the current copied bytes
+func (s *Sampler) Record(usages []Usage, at time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current := map[string]sample{}
+	for i := range usages {
+		u := &usages[i]
+		if u.Migration == "" {
+			continue
+		}
+		key := u.Namespace + "/" + u.Name
+		if prev, ok := s.previous[key]; ok && prev.migration == u.Migration {
+			if elapsed := at.Sub(prev.at).Seconds(); elapsed > 0 {
+				// Progress can step back when a warm migration starts a new precopy
+				rate := float64(u.CopiedBytes-prev.bytes) / elapsed
+				if rate < 0 {
+					rate = 0
+				}
+				u.Throughput = &rate
+			}
+		}
+		current[key] = sample{migration: u.Migration, bytes: u.CopiedBytes, at: at}
+	}
+	s.previous = current
+}
@@ -0,0 +1,139 @@
+package plan
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const gib = 1024 * 1024 * 1024
+
+func testPod(name, container string, phase corev1.PodPhase, cpu, memory string, owner string) corev1.Pod {
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{
+			Name: container,
+			Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse(cpu),
+				corev1.ResourceMemory: resource.MustParse(memory),
+			}},
+		}}},
+		Status: corev1.PodStatus{Phase: phase},
+	}
+	if owner != "" {
+		pod.OwnerReferences = []metav1.OwnerReference{{Kind: "PersistentVolumeClaim", Name: owner}}
+	}
+	return pod
+}
+
+func testDataVolume(size, phase, progress string) unstructured.Unstructured {
+	return unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{"storage": map[string]interface{}{
+			"resources": map[string]interface{}{"requests": map[string]interface{}{"storage": size}},
+		}},
+		"status": map[string]interface{}{"phase": phase, "progress": progress},
+	}}
+}
+
+func TestBuild(t *testing.T) {
+	planPods := []corev1.Pod{
+		testPod("web-vm-1-abcde", "virt-v2v", corev1.PodRunning, "1", "1Gi", ""),
+		testPod("web-vm-2-fghij", "virt-v2v", corev1.PodSucceeded, "1", "1Gi", ""),
+		testPod("populate-xyz", "populate", corev1.PodRunning, "500m", "512Mi", ""),
+	}
+	importerPods := []corev1.Pod{
+		testPod("importer-web-disk-1", "importer", corev1.PodRunning, "100m", "256Mi", "web-disk-1"),
+		testPod("importer-prime-uid-2", "importer", corev1.PodRunning, "100m", "256Mi", "prime-uid-2"),
+		testPod("importer-other-disk", "importer", corev1.PodRunning, "100m", "256Mi", "other-disk"),
+	}
+	pvcs := []corev1.PersistentVolumeClaim{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "web-disk-1", UID: "uid-1"},
+			Status:     corev1.PersistentVolumeClaimStatus{Capacity: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("20Gi")}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "web-disk-2", UID: "uid-2"},
+			Spec: corev1.PersistentVolumeClaimSpec{Resources: corev1.VolumeResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("10Gi")},
+			}},
+		},
+	}
+	dataVolumes := []unstructured.Unstructured{
+		testDataVolume("20Gi", "ImportInProgress", "50.00%"),
+		testDataVolume("10Gi", "Succeeded", "100.0%"),
+	}
+
+	usage := Build(planPods, importerPods, pvcs, dataVolumes)
+	if usage.ConversionPods != 1 || usage.ImporterPods != 3 {
+		t.Errorf("pods = %d conversions, %d importers, want 1 and 3", usage.ConversionPods, usage.ImporterPods)
+	}
+	if usage.CPURequestMilli != 1700 || usage.MemoryRequest != 2*gib {
+		t.Errorf("requests = %dm CPU, %d bytes, want 1700m and 2Gi", usage.CPURequestMilli, usage.MemoryRequest)
+	}
+	if usage.PVCs != 2 || usage.ProvisionedBytes != 30*gib {
+		t.Errorf("PVCs = %d, %d bytes, want 2 and 30Gi", usage.PVCs, usage.ProvisionedBytes)
+	}
+	if usage.CopiedBytes != 20*gib {
+		t.Errorf("CopiedBytes = %d, want 20Gi", usage.CopiedBytes)
+	}
+}
+
+func TestCopiedBytes(t *testing.T) {
+	tests := []struct {
+		dv   unstructured.Unstructured
+		want int64
+	}{
+		{testDataVolume("10Gi", "ImportInProgress", "25.0%"), 10 * gib / 4},
+		{testDataVolume("10Gi", "ImportScheduled", "N/A"), 0},
+		{testDataVolume("10Gi", "Succeeded", ""), 10 * gib},
+		{testDataVolume("", "ImportInProgress", "50%"), 0},
+	}
+	for i, tt := range tests {
+		if got := CopiedBytes(&tt.dv); got != tt.want {
+			t.Errorf("%d: CopiedBytes() = %d, want %d", i, got, tt.want)
+		}
+	}
+}
+
+func TestSampler(t *testing.T) {
+	start := time.Date(2026, 5, 1, 10, 0, 0, 0, time.UTC)
+	sampler := NewSampler()
+
+	first := []Usage{{Name: "web", Namespace: "mtv", Migration: "web-1", CopiedBytes: gib}}
+	sampler.Record(first, start)
+	if first[0].Throughput != nil || !sampler.HasSamples() {
+		t.Fatal("the first sample has no throughput")
+	}
+
+	second := []Usage{
+		{Name: "web", Namespace: "mtv", Migration: "web-1", CopiedBytes: 2 * gib},
+		{Name: "db", Namespace: "mtv", Migration: "db-1", CopiedBytes: gib},
+	}
+	sampler.Record(second, start.Add(10*time.Second))
+	if second[0].Throughput == nil || *second[0].Throughput != float64(gib)/10 {
+		t.Errorf("web throughput = %v, want 1Gi in 10s", second[0].Throughput)
+	}
+	if second[1].Throughput != nil {
+		t.Error("a plan without a previous sample has no throughput")
+	}
+
+	// A new migration of the same plan starts over
+	third := []Usage{{Name: "web", Namespace: "mtv", Migration: "web-2", CopiedBytes: 0}}
+	sampler.Record(third, start.Add(20*time.Second))
+	if third[0].Throughput != nil {
+		t.Error("a new migration has no throughput")
+	}
+}
+
+func TestFormatMilliCPU(t *testing.T) {
+	if got := formatMilliCPU(2000); got != "2" {
+		t.Errorf("formatMilliCPU(2000) = %s", got)
+	}
+	if got := formatMilliCPU(1700); got != "1700m" {
+		t.Errorf("formatMilliCPU(1700) = %s", got)
+	}
+}
@@ -0,0 +1,48 @@
+package version
+
+import (
+	"context"
+
+	"github.com/yaacov/kubectl-mtv/pkg/util/client"
+	pkgversion "github.com/yaacov/kubectl-mtv/pkg/version"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+// Compatibility statuses
+const (
+	CompatibilityOK         = "compatible"
+	CompatibilityIssues     = "issues found"
+	CompatibilityNotChecked = "not checked"
+)
+
+// CheckCompatibility fetches the version of the inventory service and checks the versions
+// of info against the compatibility matrix embedded in kubectl-mtv
+func CheckCompatibility(ctx context.Context, info *Info, kubeConfigFlags *genericclioptions.ConfigFlags) error {
+	if info.OperatorStatus != "installed" {
+		info.CompatibilityStatus = CompatibilityNotChecked + ": MTV operator not available"
+		return nil
+	}
+
+	if info.InventoryVersion == "" {
+		info.InventoryVersion = "unknown"
+		if v, err := client.GetInventoryVersion(ctx, kubeConfigFlags, info.OperatorNamespace); err == nil {
+			info.InventoryVersion = v
+		}
+	}
+
+	issues, err := pkgversion.CheckCompatibility(pkgversion.Versions{
+		Client:    info.ClientVersion,
+		Operator:  info.OperatorVersion,
+		Inventory: info.InventoryVersion,
+		API:       info.APIVersion,
+	})
+	if err != nil {
+		return err
+	}
+	info.Compatibility = issues
+	info.CompatibilityStatus = CompatibilityOK
+	if len(issues) > 0 {
+		info.CompatibilityStatus = CompatibilityIssues
+	}
+	return nil
+}
@@ -56,6 +56,9 @@ func (info Info) formatMarkdown() string {
 		if info.OperatorStatus == "installed" {
 			out += fmt.Sprintf("| MTV Operator | %s |\n", escapeMarkdownCell(info.OperatorVersion))
 			out += fmt.Sprintf("| MTV Namespace | %s |\n", escapeMarkdownCell(info.OperatorNamespace))
+			if info.APIVersion != "" {
+				out += fmt.Sprintf("| MTV API | %s |\n", escapeMarkdownCell(info.APIVersion))
+			}
 		} else {
 			out += fmt.Sprintf("| MTV Operator | %s |\n", escapeMarkdownCell(info.OperatorStatus))
 		}
@@ -73,6 +76,16 @@ func (info Info) formatMarkdown() string {
 		}
 	}
 
+	if info.InventoryVersion != "" {
+		out += fmt.Sprintf("| MTV Inventory Version | %s |\n", escapeMarkdownCell(info.InventoryVersion))
+	}
+	if info.CompatibilityStatus != "" {
+		out += fmt.Sprintf("| Compatibility | %s |\n", escapeMarkdownCell(info.CompatibilityStatus))
+		for _, issue := range info.Compatibility {
+			out += fmt.Sprintf("| %s | %s |\n", strings.ToUpper(issue.Severity), escapeMarkdownCell(issue.Message))
+		}
+	}
+
 	return out
 }
 
@@ -85,6 +98,9 @@ func (info Info) formatTable() string {
 		if info.OperatorStatus == "installed" {
 			output += fmt.Sprintf("MTV Operator: %s\n", info.OperatorVersion)
 			output += fmt.Sprintf("MTV Namespace: %s\n", info.OperatorNamespace)
+			if info.APIVersion != "" {
+				output += fmt.Sprintf("MTV API: %s\n", info.APIVersion)
+			}
 		} else {
 			output += fmt.Sprintf("MTV Operator: %s\n", info.OperatorStatus)
 		}
@@ -103,5 +119,15 @@ func (info Info) formatTable() string {
 		}
 	}
 
+	if info.InventoryVersion != "" {
+		output += fmt.Sprintf("MTV Inventory Version: %s\n", info.InventoryVersion)
+	}
+	if info.CompatibilityStatus != "" {
+		output += fmt.Sprintf("Compatibility: %s\n", info.CompatibilityStatus)
+		for _, issue := range info.Compatibility {
+			output += fmt.Sprintf("  %s: %s\n", strings.ToUpper(issue.Severity), issue.Message)
+		}
+	}
+
 	return output
 }
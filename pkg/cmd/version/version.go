@@ -5,6 +5,7 @@ import (
 
 	"github.com/yaacov/kubectl-mtv/pkg/util/client"
 	"github.com/yaacov/kubectl-mtv/pkg/util/config"
+	pkgversion "github.com/yaacov/kubectl-mtv/pkg/version"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 )
 
@@ -14,9 +15,15 @@ type Info struct {
 	OperatorVersion   string `json:"operatorVersion,omitempty" yaml:"operatorVersion,omitempty"`
 	OperatorStatus    string `json:"operatorStatus,omitempty" yaml:"operatorStatus,omitempty"`
 	OperatorNamespace string `json:"operatorNamespace,omitempty" yaml:"operatorNamespace,omitempty"`
+	APIVersion        string `json:"apiVersion,omitempty" yaml:"apiVersion,omitempty"`
 	InventoryURL      string `json:"inventoryURL,omitempty" yaml:"inventoryURL,omitempty"`
 	InventoryStatus   string `json:"inventoryStatus,omitempty" yaml:"inventoryStatus,omitempty"`
 	InventoryInsecure bool   `json:"inventoryInsecure,omitempty" yaml:"inventoryInsecure,omitempty"`
+	InventoryVersion  string `json:"inventoryVersion,omitempty" yaml:"inventoryVersion,omitempty"`
+
+	// Set by CheckCompatibility
+	CompatibilityStatus string                          `json:"compatibilityStatus,omitempty" yaml:"compatibilityStatus,omitempty"`
+	Compatibility       []pkgversion.CompatibilityIssue `json:"compatibility,omitempty" yaml:"compatibility,omitempty"`
 }
 
 // GetInventoryInfo returns information about the MTV inventory service
@@ -64,6 +71,14 @@ func GetVersionInfo(ctx context.Context, clientVersion string, kubeConfigFlags *
 	// Get MTV Operator information
 	controllerVersion, controllerStatus, controllerNamespace := GetMTVControllerInfo(ctx, kubeConfigFlags)
 
+	// Get the Forklift API version negotiated with the cluster
+	apiVersion := ""
+	if controllerStatus == "installed" {
+		if version, err := client.ForkliftVersion(kubeConfigFlags); err == nil {
+			apiVersion = client.Group + "/" + version
+		}
+	}
+
 	// Get inventory information from global config
 	inventoryURL, inventoryStatus, inventoryInsecure := GetInventoryInfo(globalConfig)
 
@@ -72,6 +87,7 @@ func GetVersionInfo(ctx context.Context, clientVersion string, kubeConfigFlags *
 		OperatorVersion:   controllerVersion,
 		OperatorStatus:    controllerStatus,
 		OperatorNamespace: controllerNamespace,
+		APIVersion:        apiVersion,
 		InventoryURL:      inventoryURL,
 		InventoryStatus:   inventoryStatus,
 		InventoryInsecure: inventoryInsecure,
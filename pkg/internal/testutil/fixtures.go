@@ -0,0 +1,122 @@
+// Package testutil builds the unstructured Forklift and CDI resources used by the tests of
+// the command packages, so each test only spells out the fields it depends on. It is only
+// imported by tests.
+package testutil
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// Option sets fields of a fixture resource
+type Option func(obj *unstructured.Unstructured)
+
+// newObject returns a resource of the given kind with the options applied
+func newObject(apiVersion, kind, name, namespace string, opts []Option) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": apiVersion,
+		"kind":       kind,
+	}}
+	obj.SetName(name)
+	if namespace != "" {
+		obj.SetNamespace(namespace)
+	}
+	for _, opt := range opts {
+		opt(obj)
+	}
+	return obj
+}
+
+// Plan returns a Forklift Plan
+func Plan(name, namespace string, opts ...Option) *unstructured.Unstructured {
+	return newObject("forklift.konveyor.io/v1beta1", "Plan", name, namespace, opts)
+}
+
+// Migration returns a Forklift Migration
+func Migration(name, namespace string, opts ...Option) *unstructured.Unstructured {
+	return newObject("forklift.konveyor.io/v1beta1", "Migration", name, namespace, opts)
+}
+
+// NetworkMap returns a Forklift NetworkMap
+func NetworkMap(name, namespace string, opts ...Option) *unstructured.Unstructured {
+	return newObject("forklift.konveyor.io/v1beta1", "NetworkMap", name, namespace, opts)
+}
+
+// DataVolume returns a CDI DataVolume
+func DataVolume(name, namespace string, opts ...Option) *unstructured.Unstructured {
+	return newObject("cdi.kubevirt.io/v1beta1", "DataVolume", name, namespace, opts)
+}
+
+// WithUID sets the resource UID
+func WithUID(uid string) Option {
+	return func(obj *unstructured.Unstructured) {
+		obj.SetUID(types.UID(uid))
+	}
+}
+
+// WithLabels sets the resource labels
+func WithLabels(labels map[string]string) Option {
+	return func(obj *unstructured.Unstructured) {
+		obj.SetLabels(labels)
+	}
+}
+
+// WithField sets a nested field, e.g. WithField("apps", "spec", "targetNamespace")
+func WithField(value interface{}, fields ...string) Option {
+	return func(obj *unstructured.Unstructured) {
+		_ = unstructured.SetNestedField(obj.Object, value, fields...)
+	}
+}
+
+// WithTargetNamespace sets the spec.targetNamespace of a plan
+func WithTargetNamespace(namespace string) Option {
+	return WithField(namespace, "spec", "targetNamespace")
+}
+
+// WithProviders sets the source and destination providers of a plan
+func WithProviders(source, destination string) Option {
+	return WithField(map[string]interface{}{
+		"source":      map[string]interface{}{"name": source},
+		"destination": map[string]interface{}{"name": destination},
+	}, "spec", "provider")
+}
+
+// WithMaps sets the network and storage maps of a plan
+func WithMaps(network, storage string) Option {
+	return WithField(map[string]interface{}{
+		"network": map[string]interface{}{"name": network},
+		"storage": map[string]interface{}{"name": storage},
+	}, "spec", "map")
+}
+
+// WithVMs sets the spec.vms of a plan
+func WithVMs(vms ...interface{}) Option {
+	return WithField(vms, "spec", "vms")
+}
+
+// WithPlanRef sets the plan a migration runs
+func WithPlanRef(name, namespace string) Option {
+	return WithField(map[string]interface{}{"name": name, "namespace": namespace}, "spec", "plan")
+}
+
+// WithConditions sets the status.conditions of a resource
+func WithConditions(conditions ...interface{}) Option {
+	return WithField(conditions, "status", "conditions")
+}
+
+// WithStatusVMs sets the status.vms of a migration
+func WithStatusVMs(vms ...interface{}) Option {
+	return WithField(vms, "status", "vms")
+}
+
+// VM returns a plan VM reference; an empty id or name is left out
+func VM(id, name string) map[string]interface{} {
+	vm := map[string]interface{}{}
+	if id != "" {
+		vm["id"] = id
+	}
+	if name != "" {
+		vm["name"] = name
+	}
+	return vm
+}
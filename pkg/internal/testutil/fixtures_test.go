@@ -0,0 +1,44 @@
+package testutil
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestPlan(t *testing.T) {
+	plan := Plan("wave-1", "mtv",
+		WithUID("plan-uid"),
+		WithTargetNamespace("apps"),
+		WithProviders("vsphere", "host"),
+		WithVMs(VM("vm-1", "web"), VM("", "db")),
+		WithConditions(map[string]interface{}{"type": "Ready", "status": "True"}),
+	)
+
+	if plan.GetKind() != "Plan" || plan.GetName() != "wave-1" || plan.GetNamespace() != "mtv" || plan.GetUID() != "plan-uid" {
+		t.Errorf("metadata = %s %s/%s %s", plan.GetKind(), plan.GetNamespace(), plan.GetName(), plan.GetUID())
+	}
+	if source, _, _ := unstructured.NestedString(plan.Object, "spec", "provider", "source", "name"); source != "vsphere" {
+		t.Errorf("source provider = %q", source)
+	}
+	vms, _, _ := unstructured.NestedSlice(plan.Object, "spec", "vms")
+	if len(vms) != 2 {
+		t.Fatalf("vms = %v", vms)
+	}
+	if _, hasID := vms[1].(map[string]interface{})["id"]; hasID {
+		t.Errorf("VM without ID has an id field: %v", vms[1])
+	}
+	if conditions, _, _ := unstructured.NestedSlice(plan.Object, "status", "conditions"); len(conditions) != 1 {
+		t.Errorf("conditions = %v", conditions)
+	}
+}
+
+func TestMigration_WithoutNamespace(t *testing.T) {
+	migration := Migration("wave-1-abc", "", WithPlanRef("wave-1", "mtv"))
+	if _, found, _ := unstructured.NestedString(migration.Object, "metadata", "namespace"); found {
+		t.Error("expected no namespace")
+	}
+	if plan, _, _ := unstructured.NestedString(migration.Object, "spec", "plan", "name"); plan != "wave-1" {
+		t.Errorf("plan ref = %q", plan)
+	}
+}
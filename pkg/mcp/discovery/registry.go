@@ -168,6 +168,7 @@ func (r *Registry) GenerateServerInstructions() string {
 	sb.WriteString("  2. Call mtv_help(\"<command>\") to learn its flags and see examples\n")
 	sb.WriteString("  3. Execute the command with the correct flags\n")
 	sb.WriteString("\nThe tool descriptions list available commands but not their flags — always call mtv_help first for unfamiliar commands.\n")
+	sb.WriteString("\nResources: mtv://inventory-schema/{vsphere,ovirt,openstack,ec2} list the VM and network inventory fields and TSL operators for query flags.\n")
 
 	return sb.String()
 }
@@ -237,7 +238,7 @@ func (r *Registry) GenerateReadWriteDescription() string {
 	sb.WriteString("3. Browse VMs with mtv_read \"get inventory vm\" + TSL queries\n")
 	sb.WriteString("4. Create a migration plan (network/storage mappings are auto-generated; use --network-pairs/--storage-pairs to override)\n")
 	sb.WriteString("5. Start the plan\n")
-	sb.WriteString("6. Monitor with mtv_read \"get plan\"\n")
+	sb.WriteString("6. Monitor with mtv_read \"get plan\"; diagnose a failed or stuck plan with mtv_troubleshoot\n")
 	sb.WriteString("\nCommands:\n")
 
 	commands := r.ListReadWriteCommands()
@@ -0,0 +1,184 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/yaacov/kubectl-mtv/pkg/mcp/util"
+	"github.com/yaacov/kubectl-mtv/pkg/util/bugreport"
+	"k8s.io/klog/v2"
+)
+
+// auditNow returns the current time; tests replace it
+var auditNow = time.Now
+
+// exitCodePattern finds the exit code in the text of CLI error results
+var exitCodePattern = regexp.MustCompile(`\(exit (\d+)\)`)
+
+// AuditMiddleware records every tool call in the audit log and refuses mtv_write calls
+// of sessions over the write rate limit. Other MCP methods pass through unchanged.
+func AuditMiddleware(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		callReq, ok := req.(*mcp.CallToolRequest)
+		if method != "tools/call" || !ok || callReq.Params == nil {
+			return next(ctx, method, req)
+		}
+
+		start := auditNow()
+		record := util.AuditRecord{
+			Time: start.UTC(),
+			Tool: callReq.Params.Name,
+			Args: maskArguments(callReq.Params.Arguments),
+		}
+		setAuditCaller(&record, callReq)
+
+		var result mcp.Result
+		var err error
+		if allowed, retryAfter := checkWriteRateLimit(record.Tool, record.Session, start); allowed {
+			result, err = next(ctx, method, req)
+		} else {
+			record.RateLimited = true
+			result = rateLimitedResult(retryAfter)
+		}
+
+		record.DurationMS = float64(auditNow().Sub(start).Microseconds()) / 1000
+		record.ExitCode = resultExitCode(result, err)
+		if err != nil {
+			record.Error = err.Error()
+		}
+		if log := util.GetAuditLog(); log != nil {
+			if auditErr := log.Record(record); auditErr != nil {
+				klog.Warningf("Failed to audit tool call: %v", auditErr)
+			}
+		}
+		return result, err
+	}
+}
+
+// checkWriteRateLimit applies the per-session write rate limit to mtv_write calls
+func checkWriteRateLimit(tool, sessionID string, now time.Time) (bool, time.Duration) {
+	if tool != ToolWrite {
+		return true, 0
+	}
+	return util.AllowWrite(sessionID, now)
+}
+
+// rateLimitedResult tells the agent to slow down
+func rateLimitedResult(retryAfter time.Duration) *mcp.CallToolResult {
+	seconds := int(retryAfter.Round(time.Second) / time.Second)
+	if seconds < 1 {
+		seconds = 1
+	}
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf(
+			"Rate limit exceeded: this session may make %d mtv_write calls per minute. Retry after %d seconds.",
+			util.GetWriteRateLimit(), seconds)}},
+		IsError: true,
+	}
+}
+
+// setAuditCaller fills in the session, client and kubeconfig context of a tool call
+func setAuditCaller(record *util.AuditRecord, req *mcp.CallToolRequest) {
+	if req.Session == nil {
+		return
+	}
+	record.Session = req.Session.ID()
+	if params := req.Session.InitializeParams(); params != nil && params.ClientInfo != nil {
+		record.Client = params.ClientInfo.Name
+		if params.ClientInfo.Version != "" {
+			record.Client += "/" + params.ClientInfo.Version
+		}
+	}
+	if kubeContext, ok := util.GetSessionKubeContext(record.Session); ok {
+		record.Context = kubeContext
+	}
+}
+
+// maskArguments decodes the tool arguments and masks the values that may carry credentials
+func maskArguments(raw json.RawMessage) interface{} {
+	if len(raw) == 0 {
+		return nil
+	}
+	var args interface{}
+	if err := json.Unmarshal(raw, &args); err != nil {
+		return bugreport.RedactText(string(raw))
+	}
+	return maskValue(args)
+}
+
+// maskValue replaces the values of sensitive keys and redacts credentials in URLs
+func maskValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if bugreport.IsSensitiveFlag(key) {
+				v[key] = "<redacted>"
+			} else {
+				v[key] = maskValue(item)
+			}
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = maskValue(item)
+		}
+		return v
+	case string:
+		return bugreport.RedactText(v)
+	default:
+		return v
+	}
+}
+
+// resultExitCode returns the exit code of the command a tool call ran: the CLI return
+// value when known, otherwise 1 for failed calls and 0 for successful ones
+func resultExitCode(result mcp.Result, err error) int {
+	if err != nil {
+		return 1
+	}
+	toolResult, ok := result.(*mcp.CallToolResult)
+	if !ok || toolResult == nil {
+		return 0
+	}
+	if rv, ok := structuredReturnValue(toolResult.StructuredContent); ok {
+		return rv
+	}
+	if !toolResult.IsError {
+		return 0
+	}
+	for _, content := range toolResult.Content {
+		text, ok := content.(*mcp.TextContent)
+		if !ok {
+			continue
+		}
+		if m := exitCodePattern.FindStringSubmatch(text.Text); m != nil {
+			if code, err := strconv.Atoi(m[1]); err == nil {
+				return code
+			}
+		}
+	}
+	return 1
+}
+
+// structuredReturnValue returns the CLI return value in the structured content of a result
+func structuredReturnValue(content interface{}) (int, bool) {
+	switch c := content.(type) {
+	case map[string]interface{}:
+		rv, ok := c["return_value"].(float64)
+		return int(rv), ok
+	case json.RawMessage:
+		var out struct {
+			ReturnValue *int `json:"return_value"`
+		}
+		if err := json.Unmarshal(c, &out); err != nil || out.ReturnValue == nil {
+			return 0, false
+		}
+		return *out.ReturnValue, true
+	}
+	return 0, false
+}
@@ -0,0 +1,118 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/yaacov/kubectl-mtv/pkg/mcp/util"
+)
+
+func callToolRequest(name, args string) *mcp.CallToolRequest {
+	return &mcp.CallToolRequest{Params: &mcp.CallToolParamsRaw{Name: name, Arguments: json.RawMessage(args)}}
+}
+
+func TestAuditMiddleware(t *testing.T) {
+	var buf bytes.Buffer
+	util.SetAuditLog(util.NewAuditLog(&buf))
+	defer util.SetAuditLog(nil)
+
+	handler := AuditMiddleware(func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: "Command failed (exit 3): boom"}},
+			IsError: true,
+		}, nil
+	})
+
+	req := callToolRequest(ToolWrite, `{"command":"create provider","flags":{"name":"vc","password":"s3cret","url":"https://admin:pw@vc.example.com"}}`)
+	if _, err := handler(context.Background(), "tools/call", req); err != nil {
+		t.Fatal(err)
+	}
+
+	var record util.AuditRecord
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("invalid audit record %q: %v", buf.String(), err)
+	}
+	if record.Tool != ToolWrite || record.ExitCode != 3 {
+		t.Errorf("record = %+v, want tool %s and exit code 3", record, ToolWrite)
+	}
+	line := buf.String()
+	if strings.Contains(line, "s3cret") || strings.Contains(line, "admin:pw") {
+		t.Errorf("credentials leaked into the audit log: %s", line)
+	}
+	if !strings.Contains(line, `"name":"vc"`) {
+		t.Errorf("plain arguments should be kept: %s", line)
+	}
+
+	// Other methods are not audited
+	buf.Reset()
+	if _, err := handler(context.Background(), "tools/list", &mcp.ListToolsRequest{}); err != nil {
+		t.Fatal(err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("tools/list should not be audited: %s", buf.String())
+	}
+}
+
+func TestAuditMiddlewareRateLimit(t *testing.T) {
+	var buf bytes.Buffer
+	util.SetAuditLog(util.NewAuditLog(&buf))
+	util.SetWriteRateLimit(1)
+	defer util.SetAuditLog(nil)
+	defer util.SetWriteRateLimit(0)
+
+	calls := 0
+	handler := AuditMiddleware(func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		calls++
+		return &mcp.CallToolResult{}, nil
+	})
+
+	for i := 0; i < 2; i++ {
+		if _, err := handler(context.Background(), "tools/call", callToolRequest(ToolWrite, `{}`)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	result, err := handler(context.Background(), "tools/call", callToolRequest(ToolRead, `{}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls != 2 {
+		t.Errorf("handler ran %d times, want 2 (the second write is refused, reads are not limited)", calls)
+	}
+	if result.(*mcp.CallToolResult).IsError {
+		t.Error("reads should not be rate limited")
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d audit records, want 3", len(lines))
+	}
+	var refused util.AuditRecord
+	if err := json.Unmarshal([]byte(lines[1]), &refused); err != nil {
+		t.Fatal(err)
+	}
+	if !refused.RateLimited || refused.ExitCode != 1 {
+		t.Errorf("refused call record = %+v, want rate limited with exit code 1", refused)
+	}
+}
+
+func TestResultExitCode(t *testing.T) {
+	tests := []struct {
+		name   string
+		result mcp.Result
+		want   int
+	}{
+		{"success", &mcp.CallToolResult{}, 0},
+		{"structured return value", &mcp.CallToolResult{StructuredContent: json.RawMessage(`{"return_value":0,"data":[]}`)}, 0},
+		{"error text", &mcp.CallToolResult{IsError: true, Content: []mcp.Content{&mcp.TextContent{Text: "Command failed (exit 2): no"}}}, 2},
+		{"error without code", &mcp.CallToolResult{IsError: true, Content: []mcp.Content{&mcp.TextContent{Text: "invalid params"}}}, 1},
+	}
+	for _, tt := range tests {
+		if got := resultExitCode(tt.result, nil); got != tt.want {
+			t.Errorf("%s: exit code = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
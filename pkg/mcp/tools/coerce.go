@@ -10,6 +10,8 @@ import (
 	"github.com/google/jsonschema-go/jsonschema"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/yaacov/kubectl-mtv/pkg/mcp/util"
+	"github.com/yaacov/kubectl-mtv/pkg/util/progress"
+	"k8s.io/klog/v2"
 )
 
 // extractKubeCredsFromRequest extracts Kubernetes credentials from the request's
@@ -23,6 +25,116 @@ func extractKubeCredsFromRequest(ctx context.Context, req *mcp.CallToolRequest)
 	return ctx
 }
 
+// applySessionKubeContext resolves the kubeconfig context for a tool call and adds
+// it to the context. A context passed in the tool input is remembered for the
+// calling session, so later calls from the same agent keep targeting the same
+// cluster. Precedence: tool input > session selection > X-Kubernetes-Context
+// header > CLI default.
+func applySessionKubeContext(ctx context.Context, req *mcp.CallToolRequest, requested string) (context.Context, error) {
+	sessionID := ""
+	if req != nil && req.Session != nil {
+		sessionID = req.Session.ID()
+	}
+
+	if requested != "" {
+		if err := util.SetSessionKubeContext(sessionID, requested); err != nil {
+			return ctx, err
+		}
+	}
+
+	if kubeContext, ok := util.GetSessionKubeContext(sessionID); ok {
+		return util.WithKubeContext(ctx, kubeContext), nil
+	}
+
+	if req != nil && req.Extra != nil && req.Extra.Header != nil {
+		ctx = util.WithKubeContextFromHeaders(ctx, req.Extra.Header)
+		if kubeContext, ok := util.GetKubeContext(ctx); ok {
+			if err := util.ValidateKubeContext(kubeContext); err != nil {
+				return ctx, err
+			}
+		}
+	}
+	return ctx, nil
+}
+
+// withProgressNotifications forwards the progress events of the command run for a tool
+// call as MCP progress notifications, when the client asked for them with a progress token
+func withProgressNotifications(ctx context.Context, req *mcp.CallToolRequest) context.Context {
+	if req == nil || req.Params == nil || req.Session == nil {
+		return ctx
+	}
+	token := req.Params.GetProgressToken()
+	if token == nil {
+		return ctx
+	}
+	return util.WithProgressFunc(ctx, func(event progress.Event) {
+		message := event.Operation
+		if event.Message != "" {
+			message += ": " + event.Message
+		} else if event.Stage != "" {
+			message += ": " + event.Stage
+		}
+		params := &mcp.ProgressNotificationParams{
+			ProgressToken: token,
+			Message:       message,
+			Progress:      float64(event.Current),
+			Total:         float64(event.Total),
+		}
+		if err := req.Session.NotifyProgress(ctx, params); err != nil {
+			klog.V(2).Infof("[progress] failed to send progress notification: %v", err)
+		}
+	})
+}
+
+// takeKubeTargetFlags removes the kubeconfig/context flags from a tool's flags map so
+// sessions cannot point the subprocess at an arbitrary kubeconfig file. A "context"
+// flag is returned so it can be routed through the session selection instead.
+func takeKubeTargetFlags(flags map[string]any) string {
+	if flags == nil {
+		return ""
+	}
+	kubeContext := ""
+	if v, ok := flags["context"]; ok && v != nil {
+		kubeContext = fmt.Sprintf("%v", v)
+	}
+	delete(flags, "context")
+	delete(flags, "kubeconfig")
+	return kubeContext
+}
+
+// HandleSessionInitialized records the kubeconfig context a client selected in the
+// initialize request _meta (key "kubectl-mtv/context") for the new session, and
+// registers a hook that forgets the session's context when the session closes.
+func HandleSessionInitialized(ctx context.Context, req *mcp.InitializedRequest) {
+	if req == nil || req.Session == nil {
+		return
+	}
+	go clearSessionOnClose(req.Session)
+
+	params := req.Session.InitializeParams()
+	if params == nil || params.Meta == nil {
+		return
+	}
+	kubeContext, ok := params.Meta[util.SessionContextMetaKey].(string)
+	if !ok || kubeContext == "" {
+		return
+	}
+	if err := util.SetSessionKubeContext(req.Session.ID(), kubeContext); err != nil {
+		klog.Warningf("Ignoring session context selection: %v", err)
+		return
+	}
+	klog.V(1).Infof("Session %q selected kubeconfig context %q", req.Session.ID(), kubeContext)
+}
+
+// clearSessionOnClose waits for the session to close and forgets the kubeconfig context
+// it selected, either at initialize or later through a tool call.
+func clearSessionOnClose(session *mcp.ServerSession) {
+	sessionID := session.ID()
+	_ = session.Wait()
+	util.ClearSessionKubeContext(sessionID)
+	klog.V(2).Infof("Session %q closed, cleared its kubeconfig context", sessionID)
+}
+
 // AddToolWithCoercion registers a tool with the server using the low-level
 // s.AddTool API, adding a boolean coercion layer that converts string boolean
 // values ("true", "True", "TRUE", "false", "False", "FALSE") to actual JSON
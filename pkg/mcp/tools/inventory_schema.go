@@ -0,0 +1,54 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/help"
+)
+
+// InventorySchemaURIPrefix prefixes the URIs of the inventory schema resources,
+// e.g. mtv://inventory-schema/vsphere.
+const InventorySchemaURIPrefix = "mtv://inventory-schema/"
+
+// AddInventorySchemaResources publishes the queryable inventory fields of each provider
+// type (VM fields, network fields, computed fields and TSL operators) as MCP resources,
+// so clients can build queries without calling mtv_help("tsl"). The schemas are the
+// same metadata the "help tsl" topic is rendered from.
+func AddInventorySchemaResources(server *mcp.Server) {
+	for _, schema := range help.InventorySchemas() {
+		server.AddResource(&mcp.Resource{
+			URI:         InventorySchemaURIPrefix + schema.Provider,
+			Name:        "inventory-schema-" + schema.Provider,
+			Title:       schema.Title + " inventory schema",
+			Description: fmt.Sprintf("%s VM and network inventory fields and the TSL operators for the query flag of get inventory commands", schema.Title),
+			MIMEType:    "application/json",
+		}, HandleInventorySchemaResource)
+	}
+}
+
+// HandleInventorySchemaResource returns the inventory schema of the provider type
+// named by the resource URI as JSON.
+func HandleInventorySchemaResource(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+	uri := req.Params.URI
+	schema := help.GetInventorySchema(strings.TrimPrefix(uri, InventorySchemaURIPrefix))
+	if !strings.HasPrefix(uri, InventorySchemaURIPrefix) || schema == nil {
+		return nil, mcp.ResourceNotFoundError(uri)
+	}
+
+	data, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal inventory schema: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []*mcp.ResourceContents{{
+			URI:      uri,
+			MIMEType: "application/json",
+			Text:     string(data),
+		}},
+	}, nil
+}
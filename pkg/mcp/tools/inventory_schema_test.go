@@ -0,0 +1,38 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/help"
+)
+
+func TestHandleInventorySchemaResource(t *testing.T) {
+	req := &mcp.ReadResourceRequest{Params: &mcp.ReadResourceParams{URI: InventorySchemaURIPrefix + "vsphere"}}
+	result, err := HandleInventorySchemaResource(context.Background(), req)
+	if err != nil {
+		t.Fatalf("HandleInventorySchemaResource() unexpected error: %v", err)
+	}
+	if len(result.Contents) != 1 || result.Contents[0].MIMEType != "application/json" {
+		t.Fatalf("Contents = %+v, want one JSON document", result.Contents)
+	}
+
+	var schema help.InventorySchema
+	if err := json.Unmarshal([]byte(result.Contents[0].Text), &schema); err != nil {
+		t.Fatalf("resource is not a schema: %v", err)
+	}
+	if schema.Provider != "vsphere" || len(schema.VMFields) == 0 || len(schema.NetworkFields) == 0 || len(schema.Operators) == 0 {
+		t.Errorf("schema = %+v, want vSphere VM fields, network fields and operators", schema)
+	}
+}
+
+func TestHandleInventorySchemaResource_NotFound(t *testing.T) {
+	for _, uri := range []string{InventorySchemaURIPrefix + "hyperv", "mtv://other/vsphere"} {
+		req := &mcp.ReadResourceRequest{Params: &mcp.ReadResourceParams{URI: uri}}
+		if _, err := HandleInventorySchemaResource(context.Background(), req); err == nil {
+			t.Errorf("HandleInventorySchemaResource(%s) expected an error", uri)
+		}
+	}
+}
@@ -20,7 +20,7 @@ type MTVHelpInput struct {
 // GetMTVHelpTool returns the tool definition for on-demand help.
 func GetMTVHelpTool() *mcp.Tool {
 	return &mcp.Tool{
-		Name: "mtv_help",
+		Name: ToolHelp,
 		Description: `Get detailed flags, usage, and examples for any MTV command or topic.
 
 WHEN TO USE: Before calling any mtv_read or mtv_write command, call mtv_help("<command>") first to learn its required flags and syntax. The mtv_read/mtv_write descriptions list available commands but not their flags — mtv_help fills that gap.
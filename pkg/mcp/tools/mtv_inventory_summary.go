@@ -0,0 +1,276 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/yaacov/kubectl-mtv/pkg/mcp/util"
+)
+
+// inventorySummaryCommand is the command whose VM records are aggregated
+const inventorySummaryCommand = "get/inventory/vm"
+
+// inventorySummaryTopN limits the guest OS and concern label histograms
+const inventorySummaryTopN = 15
+
+// MTVInventorySummaryInput represents the input for the mtv_inventory_summary tool.
+type MTVInventorySummaryInput struct {
+	Provider string `json:"provider" jsonschema:"Source provider name"`
+
+	Query string `json:"query,omitempty" jsonschema:"Optional TSL query selecting the VMs to summarize (e.g. where powerState = 'poweredOn')"`
+
+	Namespace string `json:"namespace,omitempty" jsonschema:"Namespace of the provider"`
+
+	Context string `json:"context,omitempty" jsonschema:"Kubeconfig context (or in-cluster) to target; remembered for the rest of this session"`
+
+	NoCache bool `json:"no_cache,omitempty" jsonschema:"If true, bypass the read cache and fetch fresh inventory data"`
+}
+
+// InventorySummary holds pre-aggregated statistics of a set of inventory VMs
+type InventorySummary struct {
+	Provider    string         `json:"provider"`
+	Query       string         `json:"query,omitempty"`
+	VMs         int            `json:"vms"`
+	PowerStates map[string]int `json:"powerStates"`
+	TotalCPUs   int64          `json:"totalCpus"`
+	MemoryGB    float64        `json:"totalMemoryGB"`
+	DiskGB      float64        `json:"totalDiskCapacityGB"`
+	Disks       int            `json:"totalDisks"`
+	// LargestVMs lists the VMs with the most disk capacity
+	LargestVMs []SummaryVM `json:"largestVMs,omitempty"`
+	// GuestOS counts VMs per guest OS, the most frequent first
+	GuestOS []SummaryCount `json:"guestOS,omitempty"`
+	// ConcernCategories counts VMs having at least one concern of each category
+	ConcernCategories map[string]int `json:"vmsWithConcernsByCategory"`
+	// Concerns counts VMs per concern label, the most frequent first
+	Concerns []SummaryConcern `json:"concerns,omitempty"`
+}
+
+// SummaryVM is a VM listed in a summary
+type SummaryVM struct {
+	Name   string  `json:"name"`
+	DiskGB float64 `json:"diskCapacityGB"`
+}
+
+// SummaryCount is a histogram bucket
+type SummaryCount struct {
+	Value string `json:"value"`
+	VMs   int    `json:"vms"`
+}
+
+// SummaryConcern is the number of VMs reporting a concern
+type SummaryConcern struct {
+	Label    string `json:"label"`
+	Category string `json:"category"`
+	VMs      int    `json:"vms"`
+}
+
+// GetMTVInventorySummaryTool returns the tool definition for inventory statistics.
+func GetMTVInventorySummaryTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name: ToolInventorySummary,
+		Description: `Summarize the VMs of a source provider as pre-aggregated statistics, computed server side.
+
+WHEN TO USE: Assessment questions about the size and readiness of an estate ("how many VMs", "how much storage", "how many Windows VMs", "what blocks migration"). Prefer this over mtv_read "get inventory vm", which returns every VM record.
+
+Returns: VM count, power states, total CPUs, memory and disk capacity, the largest VMs by disk, a guest OS histogram, and a migration concern histogram (VMs per concern category and per concern label).
+
+Use query to narrow the VMs with TSL (call mtv_help("tsl") for fields), e.g. "where powerState = 'poweredOn'" or "where name ~= '^prod-'".`,
+		OutputSchema: mtvOutputSchema,
+		Annotations: &mcp.ToolAnnotations{
+			Title:           "MTV Inventory Summary",
+			ReadOnlyHint:    true,
+			DestructiveHint: ptrBool(false),
+			OpenWorldHint:   ptrBool(false),
+		},
+	}
+}
+
+// HandleMTVInventorySummary handles the mtv_inventory_summary tool invocation.
+func HandleMTVInventorySummary(ctx context.Context, req *mcp.CallToolRequest, input MTVInventorySummaryInput) (*mcp.CallToolResult, any, error) {
+	// Extract K8s credentials from HTTP headers (populated by SDK in HTTP mode)
+	ctx = extractKubeCredsFromRequest(ctx, req)
+
+	ctx, err := applySessionKubeContext(ctx, req, input.Context)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	provider := strings.TrimSpace(input.Provider)
+	if provider == "" {
+		return nil, nil, fmt.Errorf("provider is required (e.g. provider: \"my-vsphere\")")
+	}
+
+	flags := map[string]any{"provider": provider, "output": "json"}
+	if input.Query != "" {
+		flags["query"] = input.Query
+	}
+	if input.Namespace != "" {
+		flags["namespace"] = input.Namespace
+	}
+
+	// Share cached inventory reads with mtv_read
+	cacheKey := ""
+	if util.GetReadCacheTTL() > 0 {
+		if input.NoCache {
+			util.RecordReadCacheBypass()
+		} else {
+			cacheKey = util.ReadCacheKey(ctx, inventorySummaryCommand, flags)
+		}
+	}
+
+	result, cached := "", false
+	if cacheKey != "" {
+		result, cached = util.GetCachedRead(cacheKey)
+	}
+	if !cached {
+		result, err = util.RunKubectlMTVCommand(ctx, buildArgs(inventorySummaryCommand, flags))
+		if err != nil {
+			return nil, nil, fmt.Errorf("command failed: %w", err)
+		}
+	}
+
+	data, err := util.UnmarshalJSONResponse(result)
+	if err != nil {
+		return nil, nil, err
+	}
+	if errResult := buildCLIErrorResult(data); errResult != nil {
+		return errResult, nil, nil
+	}
+	if cacheKey != "" && !cached {
+		util.StoreCachedRead(cacheKey, result)
+	}
+
+	vms, _ := data["data"].([]interface{})
+	summary := SummarizeVMs(vms)
+	summary.Provider = provider
+	summary.Query = input.Query
+
+	return nil, map[string]interface{}{"return_value": 0, "data": summary}, nil
+}
+
+// SummarizeVMs aggregates inventory VM records as returned by "get inventory vm --output json"
+func SummarizeVMs(vms []interface{}) InventorySummary {
+	summary := InventorySummary{
+		PowerStates:       map[string]int{},
+		ConcernCategories: map[string]int{},
+	}
+	guestOS := map[string]int{}
+	concerns := map[[2]string]int{}
+
+	for _, item := range vms {
+		vm, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		summary.VMs++
+
+		powerState, _ := vm["powerStateHuman"].(string)
+		if powerState == "" {
+			powerState = "Unknown"
+		}
+		summary.PowerStates[powerState]++
+
+		if cpus, ok := vm["cpuCount"].(float64); ok {
+			summary.TotalCPUs += int64(cpus)
+		}
+		summary.MemoryGB += parseGB(vm["memoryGB"])
+		diskGB := parseGB(vm["diskCapacity"])
+		summary.DiskGB += diskGB
+		if disks, ok := vm["disks"].([]interface{}); ok {
+			summary.Disks += len(disks)
+		}
+		name, _ := vm["name"].(string)
+		summary.LargestVMs = append(summary.LargestVMs, SummaryVM{Name: name, DiskGB: diskGB})
+
+		if os := summaryGuestOS(vm); os != "" {
+			guestOS[os]++
+		}
+
+		// Count each concern category and label once per VM
+		seenCategories := map[string]bool{}
+		seenLabels := map[[2]string]bool{}
+		vmConcerns, _ := vm["concerns"].([]interface{})
+		for _, c := range vmConcerns {
+			concern, ok := c.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			category, _ := concern["category"].(string)
+			label, _ := concern["label"].(string)
+			if category != "" && !seenCategories[category] {
+				seenCategories[category] = true
+				summary.ConcernCategories[category]++
+			}
+			key := [2]string{label, category}
+			if label != "" && !seenLabels[key] {
+				seenLabels[key] = true
+				concerns[key]++
+			}
+		}
+	}
+
+	summary.MemoryGB = math.Round(summary.MemoryGB*10) / 10
+	summary.DiskGB = math.Round(summary.DiskGB*10) / 10
+
+	sort.SliceStable(summary.LargestVMs, func(i, j int) bool { return summary.LargestVMs[i].DiskGB > summary.LargestVMs[j].DiskGB })
+	if len(summary.LargestVMs) > 5 {
+		summary.LargestVMs = summary.LargestVMs[:5]
+	}
+
+	for os, count := range guestOS {
+		summary.GuestOS = append(summary.GuestOS, SummaryCount{Value: os, VMs: count})
+	}
+	sort.Slice(summary.GuestOS, func(i, j int) bool {
+		if summary.GuestOS[i].VMs != summary.GuestOS[j].VMs {
+			return summary.GuestOS[i].VMs > summary.GuestOS[j].VMs
+		}
+		return summary.GuestOS[i].Value < summary.GuestOS[j].Value
+	})
+	if len(summary.GuestOS) > inventorySummaryTopN {
+		summary.GuestOS = summary.GuestOS[:inventorySummaryTopN]
+	}
+
+	for key, count := range concerns {
+		summary.Concerns = append(summary.Concerns, SummaryConcern{Label: key[0], Category: key[1], VMs: count})
+	}
+	sort.Slice(summary.Concerns, func(i, j int) bool {
+		if summary.Concerns[i].VMs != summary.Concerns[j].VMs {
+			return summary.Concerns[i].VMs > summary.Concerns[j].VMs
+		}
+		return summary.Concerns[i].Label < summary.Concerns[j].Label
+	})
+	if len(summary.Concerns) > inventorySummaryTopN {
+		summary.Concerns = summary.Concerns[:inventorySummaryTopN]
+	}
+
+	return summary
+}
+
+// summaryGuestOS returns the guest OS of a VM from the fields the providers report
+func summaryGuestOS(vm map[string]interface{}) string {
+	for _, field := range []string{"guestName", "guestId", "osType", "guestOS"} {
+		if value, ok := vm[field].(string); ok && value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// parseGB parses the "12.5 GB" values added to inventory VMs, 0 when missing
+func parseGB(value interface{}) float64 {
+	s, ok := value.(string)
+	if !ok {
+		return 0
+	}
+	gb, err := strconv.ParseFloat(strings.TrimSpace(strings.TrimSuffix(s, "GB")), 64)
+	if err != nil {
+		return 0
+	}
+	return gb
+}
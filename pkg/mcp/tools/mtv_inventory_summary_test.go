@@ -0,0 +1,104 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/yaacov/kubectl-mtv/pkg/mcp/util"
+)
+
+func summaryVM(name, power, os string, cpus float64, memory, disk string, concerns ...[2]string) map[string]interface{} {
+	vm := map[string]interface{}{
+		"name":            name,
+		"powerStateHuman": power,
+		"guestName":       os,
+		"cpuCount":        cpus,
+		"memoryGB":        memory,
+		"diskCapacity":    disk,
+		"disks":           []interface{}{map[string]interface{}{}, map[string]interface{}{}},
+	}
+	list := []interface{}{}
+	for _, c := range concerns {
+		list = append(list, map[string]interface{}{"label": c[0], "category": c[1]})
+	}
+	vm["concerns"] = list
+	return vm
+}
+
+func TestSummarizeVMs(t *testing.T) {
+	cbt := [2]string{"Changed Block Tracking (CBT) not enabled", "Warning"}
+	rdm := [2]string{"Shareable disk detected", "Critical"}
+	vms := []interface{}{
+		summaryVM("web", "On", "Red Hat Enterprise Linux 9", 2, "4.0 GB", "50.0 GB", cbt, cbt),
+		summaryVM("db", "On", "Red Hat Enterprise Linux 9", 8, "32.0 GB", "500.0 GB", cbt, rdm),
+		summaryVM("win", "Off", "Microsoft Windows Server 2019", 4, "16.0 GB", "100.5 GB"),
+		"not a vm",
+	}
+
+	s := SummarizeVMs(vms)
+	if s.VMs != 3 || s.PowerStates["On"] != 2 || s.PowerStates["Off"] != 1 {
+		t.Errorf("unexpected counts: %+v", s)
+	}
+	if s.TotalCPUs != 14 || s.MemoryGB != 52 || s.DiskGB != 650.5 || s.Disks != 6 {
+		t.Errorf("unexpected capacity: cpus=%d memory=%v disk=%v disks=%d", s.TotalCPUs, s.MemoryGB, s.DiskGB, s.Disks)
+	}
+	if len(s.LargestVMs) != 3 || s.LargestVMs[0].Name != "db" {
+		t.Errorf("unexpected largest VMs: %+v", s.LargestVMs)
+	}
+	if len(s.GuestOS) != 2 || s.GuestOS[0].Value != "Red Hat Enterprise Linux 9" || s.GuestOS[0].VMs != 2 {
+		t.Errorf("unexpected guest OS histogram: %+v", s.GuestOS)
+	}
+	if s.ConcernCategories["Warning"] != 2 || s.ConcernCategories["Critical"] != 1 {
+		t.Errorf("unexpected concern categories: %+v", s.ConcernCategories)
+	}
+	// A concern repeated on one VM is counted once
+	if len(s.Concerns) != 2 || s.Concerns[0].Label != cbt[0] || s.Concerns[0].VMs != 2 {
+		t.Errorf("unexpected concern histogram: %+v", s.Concerns)
+	}
+}
+
+func TestParseGB(t *testing.T) {
+	tests := map[interface{}]float64{"12.5 GB": 12.5, "0.0 GB": 0, "n/a": 0, 42.0: 0}
+	for value, want := range tests {
+		if got := parseGB(value); got != want {
+			t.Errorf("parseGB(%v) = %v, want %v", value, got, want)
+		}
+	}
+}
+
+func TestHandleMTVInventorySummary(t *testing.T) {
+	ctx := context.Background()
+	req := &mcp.CallToolRequest{}
+
+	if _, _, err := HandleMTVInventorySummary(ctx, req, MTVInventorySummaryInput{}); err == nil {
+		t.Error("HandleMTVInventorySummary() without provider expected error")
+	}
+
+	// Serve the VM list from the read cache instead of running the command
+	util.SetReadCacheTTL(time.Minute)
+	defer util.SetReadCacheTTL(0)
+	key := util.ReadCacheKey(ctx, inventorySummaryCommand, map[string]any{"provider": "vsphere", "output": "json", "query": "where cpuCount > 1"})
+	util.StoreCachedRead(key, `{"command": "kubectl-mtv get inventory vm", "return_value": 0, "stdout": "[{\"name\": \"web\", \"powerStateHuman\": \"On\", \"diskCapacity\": \"20.0 GB\"}]", "stderr": ""}`)
+
+	_, data, err := HandleMTVInventorySummary(ctx, req, MTVInventorySummaryInput{Provider: "vsphere", Query: "where cpuCount > 1"})
+	if err != nil {
+		t.Fatalf("HandleMTVInventorySummary() unexpected error: %v", err)
+	}
+	summary, ok := data.(map[string]interface{})["data"].(InventorySummary)
+	if !ok || summary.VMs != 1 || summary.DiskGB != 20 || summary.Provider != "vsphere" {
+		t.Errorf("unexpected summary: %+v", data)
+	}
+}
+
+func TestGetMTVInventorySummaryTool(t *testing.T) {
+	tool := GetMTVInventorySummaryTool()
+	if tool.Name != "mtv_inventory_summary" || !strings.Contains(tool.Description, "concern") {
+		t.Errorf("unexpected tool: %+v", tool)
+	}
+	if tool.Annotations == nil || !tool.Annotations.ReadOnlyHint {
+		t.Error("mtv_inventory_summary should be read-only")
+	}
+}
@@ -18,7 +18,20 @@ type MTVReadInput struct {
 
 	ShowCLI bool `json:"show_cli,omitempty" jsonschema:"If true, does not execute. Returns the equivalent CLI command in the output field instead"`
 
+	Context string `json:"context,omitempty" jsonschema:"Kubeconfig context (or in-cluster) to target; remembered for the rest of this session"`
+
 	Fields []string `json:"fields,omitempty" jsonschema:"Limit JSON to these top-level keys only (e.g. [name, id, concerns])"`
+
+	NoCache bool `json:"no_cache,omitempty" jsonschema:"If true, bypass the read cache and fetch fresh inventory data"`
+}
+
+// cacheStatsCommand is the mtv_read command that reports read cache usage
+const cacheStatsCommand = "cache_stats"
+
+// isCacheableCommand reports whether a read command's response may be cached.
+// Only inventory reads are cached; plan and migration status must always be fresh.
+func isCacheableCommand(cmdPath string) bool {
+	return strings.HasPrefix(cmdPath, "get/inventory/")
 }
 
 func ptrBool(b bool) *bool { return &b }
@@ -48,9 +61,12 @@ var mtvOutputSchema = map[string]any{
 // The description lists available commands and a hint to use mtv_help.
 func GetMTVReadTool(registry *discovery.Registry) *mcp.Tool {
 	description := registry.GenerateReadOnlyDescription()
+	if ttl := util.GetReadCacheTTL(); ttl > 0 {
+		description += fmt.Sprintf("\n\nInventory results are cached for %s. Set no_cache: true to fetch fresh data. Command %q reports cache usage.", ttl, cacheStatsCommand)
+	}
 
 	return &mcp.Tool{
-		Name:         "mtv_read",
+		Name:         ToolRead,
 		Description:  description,
 		OutputSchema: mtvOutputSchema,
 		Annotations: &mcp.ToolAnnotations{
@@ -104,6 +120,15 @@ func HandleMTVRead(registry *discovery.Registry) func(context.Context, *mcp.Call
 		// Extract K8s credentials from HTTP headers (populated by SDK in HTTP mode)
 		ctx = extractKubeCredsFromRequest(ctx, req)
 
+		// Resolve the per-session cluster target
+		if flagContext := takeKubeTargetFlags(input.Flags); input.Context == "" {
+			input.Context = flagContext
+		}
+		ctx, err := applySessionKubeContext(ctx, req, input.Context)
+		if err != nil {
+			return nil, nil, err
+		}
+
 		// Validate input to catch common small-LLM mistakes early
 		if err := validateCommandInput(input.Command); err != nil {
 			return nil, nil, err
@@ -112,6 +137,11 @@ func HandleMTVRead(registry *discovery.Registry) func(context.Context, *mcp.Call
 		// Normalize command path
 		cmdPath := normalizeCommandPath(input.Command)
 
+		// Report read cache usage
+		if cmdPath == cacheStatsCommand {
+			return nil, map[string]interface{}{"return_value": 0, "data": util.GetReadCacheStats()}, nil
+		}
+
 		// Validate command exists and is read-only
 		if !registry.IsReadOnly(cmdPath) {
 			if registry.IsReadWrite(cmdPath) {
@@ -162,10 +192,26 @@ func HandleMTVRead(registry *discovery.Registry) func(context.Context, *mcp.Call
 		// Build command arguments (all params passed via flags)
 		args := buildArgs(cmdPath, input.Flags)
 
+		// Serve repeated inventory reads from the cache when enabled
+		cacheKey := ""
+		if util.GetReadCacheTTL() > 0 && !input.ShowCLI && isCacheableCommand(cmdPath) {
+			if input.NoCache {
+				util.RecordReadCacheBypass()
+			} else {
+				cacheKey = util.ReadCacheKey(ctx, cmdPath, input.Flags)
+			}
+		}
+
 		// Execute command
-		result, err := util.RunKubectlMTVCommand(ctx, args)
-		if err != nil {
-			return nil, nil, fmt.Errorf("command failed: %w", err)
+		result, cached := "", false
+		if cacheKey != "" {
+			result, cached = util.GetCachedRead(cacheKey)
+		}
+		if !cached {
+			result, err = util.RunKubectlMTVCommand(ctx, args)
+			if err != nil {
+				return nil, nil, fmt.Errorf("command failed: %w", err)
+			}
 		}
 
 		// Parse and return result
@@ -174,6 +220,11 @@ func HandleMTVRead(registry *discovery.Registry) func(context.Context, *mcp.Call
 			return nil, nil, err
 		}
 
+		// Only successful responses are cached
+		if cacheKey != "" && !cached && buildCLIErrorResult(data) == nil {
+			util.StoreCachedRead(cacheKey, result)
+		}
+
 		// Check for CLI errors and surface as MCP IsError response
 		if errResult := buildCLIErrorResult(data); errResult != nil {
 			if cmd := registry.ReadOnly[cmdPath]; cmd != nil {
@@ -321,6 +372,8 @@ func buildArgs(cmdPath string, flags map[string]any) []string {
 		"inventory_url": true, "inventory-url": true, "i": true,
 		// --watch starts an interactive TUI that hangs the MCP subprocess
 		"watch": true, "w": true,
+		// Clear text credentials must not reach the model; --show-credentials masks them
+		"reveal_credentials": true, "reveal-credentials": true,
 	}
 
 	// Add other flags using the normalizer
@@ -329,10 +382,17 @@ func buildArgs(cmdPath string, flags map[string]any) []string {
 	return args
 }
 
+// optionalValueFlags are flags whose value is optional, e.g. a bare --dry-run, so a value
+// must be passed in the --flag=value form
+var optionalValueFlags = map[string]bool{
+	"dry-run": true,
+}
+
 // appendNormalizedFlags appends flags from a map[string]any to the args slice.
 // It handles different value types:
 //   - bool true/false: passes --flag=true or --flag=false (equals form, safe for both BoolVar and ExplicitBool)
 //   - string "true"/"false": treated as boolean
+//   - string value of an optionalValueFlags flag: passes --flag=value
 //   - string/number: converted to string form
 //
 // Flag prefix is determined by key length: single char uses "-x", multi-char uses "--long"
@@ -366,6 +426,8 @@ func appendNormalizedFlags(args []string, flags map[string]any, skipFlags map[st
 				args = append(args, prefix+name+"=true")
 			} else if v == "false" {
 				args = append(args, prefix+name+"=false")
+			} else if v != "" && optionalValueFlags[name] {
+				args = append(args, prefix+name+"="+v)
 			} else if v != "" {
 				args = append(args, prefix+name, v)
 			}
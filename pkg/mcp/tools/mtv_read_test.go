@@ -3,6 +3,7 @@ package tools
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"os/exec"
 	"strings"
 	"testing"
@@ -179,6 +180,13 @@ func TestBuildArgs(t *testing.T) {
 			flags:        map[string]any{"provider": "my-provider", "query": "where name ~= 'prod-.*'", "extended": true},
 			wantContains: []string{"--query", "--extended"},
 		},
+		{
+			name:         "reveal credentials is dropped",
+			cmdPath:      "describe/provider",
+			flags:        map[string]any{"name": "vsphere", "show_credentials": true, "reveal_credentials": true},
+			wantContains: []string{"--show-credentials=true"},
+			wantMissing:  []string{"--reveal-credentials=true"},
+		},
 		{
 			name:         "namespace extracted from flags not duplicated",
 			cmdPath:      "get/plan",
@@ -1023,3 +1031,56 @@ func TestFilterMapFields(t *testing.T) {
 		t.Error("powerState should be filtered out")
 	}
 }
+
+// --- Read cache tests ---
+
+func TestHandleMTVRead_Cache(t *testing.T) {
+	registry := testRegistry()
+	handler := HandleMTVRead(registry)
+	ctx := context.Background()
+	req := &mcp.CallToolRequest{}
+
+	origFormat := util.GetOutputFormat()
+	defer util.SetOutputFormat(origFormat)
+	util.SetOutputFormat("json")
+	util.SetReadCacheTTL(time.Minute)
+	defer util.SetReadCacheTTL(0)
+
+	// Seed the cache with the response the handler would otherwise fetch
+	key := util.ReadCacheKey(ctx, "get/inventory/vm", map[string]any{"provider": "vsphere", "output": "json"})
+	util.StoreCachedRead(key, `{"command": "kubectl-mtv get inventory vm", "return_value": 0, "stdout": "[{\"name\": \"cached-vm\"}]", "stderr": ""}`)
+
+	_, data, err := handler(ctx, req, MTVReadInput{Command: "get inventory vm", Flags: map[string]any{"provider": "vsphere"}})
+	if err != nil {
+		t.Fatalf("handler() unexpected error: %v", err)
+	}
+	if !strings.Contains(fmt.Sprintf("%v", data), "cached-vm") {
+		t.Errorf("handler() did not return the cached response: %v", data)
+	}
+
+	// no_cache bypasses the cache (show_cli avoids running the command)
+	_, _, err = handler(ctx, req, MTVReadInput{Command: "get inventory vm", Flags: map[string]any{"provider": "vsphere"}, NoCache: true, ShowCLI: true})
+	if err != nil {
+		t.Fatalf("handler() unexpected error: %v", err)
+	}
+
+	_, data, err = handler(ctx, req, MTVReadInput{Command: "cache_stats"})
+	if err != nil {
+		t.Fatalf("handler(cache_stats) unexpected error: %v", err)
+	}
+	stats, ok := data.(map[string]interface{})["data"].(util.ReadCacheStats)
+	if !ok || !stats.Enabled || stats.Hits != 1 || stats.Entries != 1 {
+		t.Errorf("unexpected cache stats: %+v", data)
+	}
+}
+
+func TestIsCacheableCommand(t *testing.T) {
+	if !isCacheableCommand("get/inventory/vm") {
+		t.Error("inventory reads should be cacheable")
+	}
+	for _, cmdPath := range []string{"get/plan", "describe/plan", "health", "get/inventory"} {
+		if isCacheableCommand(cmdPath) {
+			t.Errorf("%s should not be cacheable", cmdPath)
+		}
+	}
+}
@@ -0,0 +1,320 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/yaacov/kubectl-mtv/pkg/mcp/util"
+	"github.com/yaacov/kubectl-mtv/pkg/util/describe"
+)
+
+// runTroubleshootCommand runs the commands gathering a troubleshooting bundle
+var runTroubleshootCommand = util.RunKubectlMTVCommand
+
+// logAnalysisPattern matches the log summary line of the plan diagnostics
+var logAnalysisPattern = regexp.MustCompile(`Log Analysis: (\d+) errors`)
+
+// MTVTroubleshootInput represents the input for the mtv_troubleshoot tool.
+type MTVTroubleshootInput struct {
+	Plan string `json:"plan" jsonschema:"Migration plan name"`
+
+	VM string `json:"vm,omitempty" jsonschema:"Optional VM name to focus the diagnosis on"`
+
+	Namespace string `json:"namespace,omitempty" jsonschema:"Namespace of the plan"`
+
+	LogLines int `json:"log_lines,omitempty" jsonschema:"Number of log lines to show per pod and for the controller (default 10, max 500)"`
+
+	Context string `json:"context,omitempty" jsonschema:"Kubeconfig context (or in-cluster) to target; remembered for the rest of this session"`
+}
+
+// TroubleshootBundle is the diagnosis bundle of a plan
+type TroubleshootBundle struct {
+	Plan      string `json:"plan"`
+	Namespace string `json:"namespace,omitempty"`
+	VM        string `json:"vm,omitempty"`
+	// Findings lists the problems found in the bundle
+	Findings []string `json:"findings"`
+	// Plan status, specification and diagnostics settings (VDDK image, cutover)
+	Summary    map[string]interface{} `json:"summary,omitempty"`
+	Conditions []map[string]string    `json:"conditions,omitempty"`
+	// Migration is the running migration, or the latest one
+	Migration map[string]interface{} `json:"migration,omitempty"`
+	// VMs holds the diagnostics per VM: conditions, pipeline errors, conversion, pod logs and events
+	VMs            map[string]interface{} `json:"vms,omitempty"`
+	ControllerLogs string                 `json:"controllerLogs,omitempty"`
+	// VMStatus is the detailed migration status of the requested VM
+	VMStatus map[string]interface{} `json:"vmStatus,omitempty"`
+	// Errors lists the parts of the bundle that could not be gathered
+	Errors []string `json:"errors,omitempty"`
+}
+
+// GetMTVTroubleshootTool returns the tool definition for plan troubleshooting.
+func GetMTVTroubleshootTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name: ToolTroubleshoot,
+		Description: `Gather everything needed to diagnose a migration plan in one call.
+
+WHEN TO USE: A plan or VM failed, is stuck, or behaves unexpectedly ("why did my migration fail?"). Prefer this over separate mtv_read calls for the plan, migration, events and logs.
+
+Returns a structured bundle: findings (the detected problems), plan status and settings, plan conditions, the running or latest migration, per VM diagnostics (VM conditions, pipeline errors, conversion, virt-v2v and importer pod logs, related events), forklift-controller log lines for the plan, and with vm set, the detailed migration status of that VM.
+
+Set vm to focus on one VM. Parts that cannot be gathered are listed in errors, the rest of the bundle is still returned.`,
+		OutputSchema: mtvOutputSchema,
+		Annotations: &mcp.ToolAnnotations{
+			Title:           "MTV Troubleshoot",
+			ReadOnlyHint:    true,
+			DestructiveHint: ptrBool(false),
+			OpenWorldHint:   ptrBool(false),
+		},
+	}
+}
+
+// HandleMTVTroubleshoot handles the mtv_troubleshoot tool invocation.
+func HandleMTVTroubleshoot(ctx context.Context, req *mcp.CallToolRequest, input MTVTroubleshootInput) (*mcp.CallToolResult, any, error) {
+	// Extract K8s credentials from HTTP headers (populated by SDK in HTTP mode)
+	ctx = extractKubeCredsFromRequest(ctx, req)
+
+	ctx, err := applySessionKubeContext(ctx, req, input.Context)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	planName := strings.TrimSpace(input.Plan)
+	if planName == "" {
+		return nil, nil, fmt.Errorf("plan is required (e.g. plan: \"my-migration\")")
+	}
+	vmName := strings.TrimSpace(input.VM)
+
+	flags := map[string]any{"name": planName, "output": "json", "diagnostics": true}
+	if input.Namespace != "" {
+		flags["namespace"] = input.Namespace
+	}
+	if input.LogLines > 0 {
+		flags["show-log-lines"] = input.LogLines
+	}
+
+	// The plan description with diagnostics is the core of the bundle
+	planDesc, errResult, err := runDescribe(ctx, flags)
+	if err != nil || errResult != nil {
+		return errResult, nil, err
+	}
+
+	bundle := BuildTroubleshootBundle(planDesc, vmName)
+	bundle.Plan = planName
+	bundle.Namespace = input.Namespace
+
+	if vmName != "" {
+		vmFlags := map[string]any{"name": planName, "vm": vmName, "output": "json"}
+		if input.Namespace != "" {
+			vmFlags["namespace"] = input.Namespace
+		}
+		vmDesc, vmErrResult, err := runDescribe(ctx, vmFlags)
+		switch {
+		case err != nil:
+			bundle.Errors = append(bundle.Errors, fmt.Sprintf("VM status: %v", err))
+		case vmErrResult != nil:
+			bundle.Errors = append(bundle.Errors, fmt.Sprintf("VM status: %s", resultText(vmErrResult)))
+		default:
+			bundle.VMStatus = compactDescription(vmDesc)
+		}
+	}
+
+	return nil, map[string]interface{}{"return_value": 0, "data": bundle}, nil
+}
+
+// runDescribe runs "describe plan" with the given flags and parses its JSON description
+func runDescribe(ctx context.Context, flags map[string]any) (*describe.Description, *mcp.CallToolResult, error) {
+	result, err := runTroubleshootCommand(ctx, buildArgs("describe/plan", flags))
+	if err != nil {
+		return nil, nil, fmt.Errorf("command failed: %w", err)
+	}
+	data, err := util.UnmarshalJSONResponse(result)
+	if err != nil {
+		return nil, nil, err
+	}
+	if errResult := buildCLIErrorResult(data); errResult != nil {
+		return nil, errResult, nil
+	}
+
+	raw, err := json.Marshal(data["data"])
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read description: %w", err)
+	}
+	var desc describe.Description
+	if err := json.Unmarshal(raw, &desc); err != nil || len(desc.Sections) == 0 {
+		return nil, nil, fmt.Errorf("unexpected describe output")
+	}
+	return &desc, nil, nil
+}
+
+// resultText returns the text of a tool result
+func resultText(result *mcp.CallToolResult) string {
+	var texts []string
+	for _, content := range result.Content {
+		if text, ok := content.(*mcp.TextContent); ok {
+			texts = append(texts, text.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+// BuildTroubleshootBundle arranges a plan description with diagnostics into a bundle and
+// collects its findings. With vmName set only the diagnostics of that VM are kept.
+func BuildTroubleshootBundle(desc *describe.Description, vmName string) TroubleshootBundle {
+	bundle := TroubleshootBundle{Summary: map[string]interface{}{}}
+
+	for _, section := range desc.Sections {
+		switch section.Title {
+		case "", "SPECIFICATION":
+			for key, value := range compactSection(section) {
+				bundle.Summary[key] = value
+			}
+		case "CONDITIONS":
+			for _, table := range section.Tables {
+				bundle.Conditions = append(bundle.Conditions, table.Rows...)
+			}
+		case "RUNNING MIGRATION", "LATEST MIGRATION":
+			bundle.Migration = compactSection(section)
+			bundle.Migration["Running"] = section.Title == "RUNNING MIGRATION"
+		case "DIAGNOSTICS":
+			for _, field := range section.Fields {
+				bundle.Summary[field.Label] = field.Value
+			}
+			for _, sub := range section.SubSections {
+				if sub.Title == "Controller Logs" {
+					for _, text := range sub.Texts {
+						bundle.ControllerLogs = strings.TrimSpace(bundle.ControllerLogs + "\n" + text.Content)
+					}
+					continue
+				}
+				name, ok := strings.CutPrefix(sub.Title, "VM: ")
+				if !ok || (vmName != "" && !strings.HasPrefix(name, vmName+" (")) {
+					continue
+				}
+				if bundle.VMs == nil {
+					bundle.VMs = map[string]interface{}{}
+				}
+				bundle.VMs[name] = compactSection(sub)
+			}
+		}
+	}
+
+	bundle.Findings = troubleshootFindings(bundle)
+	return bundle
+}
+
+// troubleshootFindings lists the problems of a bundle: plan status, failing conditions,
+// failed VMs, VM errors and log errors
+func troubleshootFindings(bundle TroubleshootBundle) []string {
+	findings := []string{}
+
+	if status, _ := bundle.Summary["Status"].(string); status == "Failed" || status == "Canceled" {
+		findings = append(findings, fmt.Sprintf("Plan status is %s", status))
+	}
+	if ready, _ := bundle.Summary["Ready"].(string); ready == "false" {
+		findings = append(findings, "Plan is not ready")
+	}
+	for _, condition := range bundle.Conditions {
+		if category := condition["category"]; (category == "Critical" || category == "Error") && condition["status"] == "True" {
+			findings = append(findings, fmt.Sprintf("Condition %s (%s): %s", condition["type"], category, condition["message"]))
+		}
+	}
+	if failed, _ := bundle.Migration["Failed"].(string); failed != "" && failed != "0" {
+		findings = append(findings, fmt.Sprintf("%s VM(s) failed in migration %v", failed, bundle.Migration["Name"]))
+	}
+	if vddk, _ := bundle.Summary["VDDK Image"].(string); vddk == "Not configured" {
+		findings = append(findings, "VDDK image is not configured")
+	}
+
+	for name, v := range bundle.VMs {
+		vm, _ := v.(map[string]interface{})
+		if vmError, _ := vm["Error"].(string); vmError != "" {
+			findings = append(findings, fmt.Sprintf("VM %s failed in phase %v: %s", name, vm["Phase"], vmError))
+		}
+		for _, pod := range textValues(vm["Pod"]) {
+			if errors := logErrorCount(pod); errors > 0 {
+				podName := strings.TrimSpace(strings.TrimPrefix(strings.SplitN(pod, "\n", 2)[0], "Name:"))
+				findings = append(findings, fmt.Sprintf("Pod %s of VM %s logged %d errors", podName, name, errors))
+			}
+		}
+	}
+
+	if errors := logErrorCount(bundle.ControllerLogs); errors > 0 {
+		findings = append(findings, fmt.Sprintf("forklift-controller logged %d errors for this plan", errors))
+	}
+	return findings
+}
+
+// logErrorCount returns the error count of a log analysis text
+func logErrorCount(text string) int {
+	m := logAnalysisPattern.FindStringSubmatch(text)
+	if m == nil {
+		return 0
+	}
+	count, _ := strconv.Atoi(m[1])
+	return count
+}
+
+// textValues returns the texts of a compacted text entry, which is a string or a list
+func textValues(value interface{}) []string {
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case []string:
+		return v
+	}
+	return nil
+}
+
+// compactDescription flattens a description into a map of compacted sections
+func compactDescription(desc *describe.Description) map[string]interface{} {
+	result := map[string]interface{}{}
+	for _, section := range desc.Sections {
+		compact := compactSection(section)
+		if section.Title == "" {
+			for key, value := range compact {
+				result[key] = value
+			}
+			continue
+		}
+		result[section.Title] = compact
+	}
+	return result
+}
+
+// compactSection flattens a describe section: fields become keys, tables become rows,
+// texts are keyed by their label (a list when a label repeats), sub-sections by title
+func compactSection(section describe.Section) map[string]interface{} {
+	result := map[string]interface{}{}
+	for _, field := range section.Fields {
+		result[field.Label] = field.Value
+	}
+	for _, table := range section.Tables {
+		rows, _ := result["rows"].([]map[string]string)
+		result["rows"] = append(rows, table.Rows...)
+	}
+	for _, text := range section.Texts {
+		label := text.Label
+		if label == "" {
+			label = "text"
+		}
+		switch existing := result[label].(type) {
+		case nil:
+			result[label] = text.Content
+		case string:
+			result[label] = []string{existing, text.Content}
+		case []string:
+			result[label] = append(existing, text.Content)
+		}
+	}
+	for _, sub := range section.SubSections {
+		result[sub.Title] = compactSection(sub)
+	}
+	return result
+}
@@ -0,0 +1,127 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/yaacov/kubectl-mtv/pkg/util/describe"
+)
+
+func testPlanDescription() *describe.Description {
+	b := describe.NewBuilder("MIGRATION PLAN")
+	b.Field("Name", "wave1")
+	b.Field("Ready", "true")
+	b.Field("Status", "Failed")
+	b.Section("SPECIFICATION")
+	b.SubSection("Providers")
+	b.Field("Source", "vsphere")
+	b.EndSubSection()
+	b.Section("LATEST MIGRATION")
+	b.Field("Name", "wave1-abc")
+	b.Field("Failed", "1")
+	b.Section("CONDITIONS")
+	b.Table([]describe.TableColumn{{Display: "TYPE", Key: "type"}}, []map[string]string{
+		{"type": "Failed", "status": "True", "category": "Critical", "message": "The plan execution has failed."},
+		{"type": "Ready", "status": "True", "category": "Required", "message": "The migration plan is ready."},
+	})
+	b.Section("DIAGNOSTICS")
+	b.Field("VDDK Image", "Not configured")
+	b.SubSection("VM: web (vm-1)")
+	b.Field("Phase", "CopyDisks")
+	b.Field("Error", "disk transfer failed")
+	b.Text("Pod", "Name:   wave1-vm-1-xyz\nStatus: Failed\nLog Analysis: 3 errors, 0 warnings", "")
+	b.EndSubSection()
+	b.SubSection("VM: db (vm-2)")
+	b.Field("Phase", "Completed")
+	b.EndSubSection()
+	b.SubSection("Controller Logs")
+	b.Text("", "Log Analysis: 2 errors, 1 warnings", "")
+	b.EndSubSection()
+	return b.Build()
+}
+
+func TestBuildTroubleshootBundle(t *testing.T) {
+	bundle := BuildTroubleshootBundle(testPlanDescription(), "")
+
+	if bundle.Summary["Status"] != "Failed" || bundle.Migration["Name"] != "wave1-abc" || bundle.Migration["Running"] != false {
+		t.Errorf("unexpected summary %v or migration %v", bundle.Summary, bundle.Migration)
+	}
+	if providers, ok := bundle.Summary["Providers"].(map[string]interface{}); !ok || providers["Source"] != "vsphere" {
+		t.Errorf("expected the specification in the summary, got %v", bundle.Summary)
+	}
+	if len(bundle.Conditions) != 2 || len(bundle.VMs) != 2 || !strings.Contains(bundle.ControllerLogs, "2 errors") {
+		t.Errorf("unexpected bundle %+v", bundle)
+	}
+
+	findings := strings.Join(bundle.Findings, "\n")
+	for _, want := range []string{
+		"Plan status is Failed",
+		"Condition Failed (Critical)",
+		"1 VM(s) failed in migration wave1-abc",
+		"VDDK image is not configured",
+		"VM web (vm-1) failed in phase CopyDisks: disk transfer failed",
+		"Pod wave1-vm-1-xyz of VM web (vm-1) logged 3 errors",
+		"forklift-controller logged 2 errors",
+	} {
+		if !strings.Contains(findings, want) {
+			t.Errorf("findings do not contain %q:\n%s", want, findings)
+		}
+	}
+	if strings.Contains(findings, "Ready") {
+		t.Errorf("findings should not report a healthy condition:\n%s", findings)
+	}
+
+	focused := BuildTroubleshootBundle(testPlanDescription(), "db")
+	if _, ok := focused.VMs["db (vm-2)"]; !ok || len(focused.VMs) != 1 {
+		t.Errorf("expected only the diagnostics of db, got %v", focused.VMs)
+	}
+}
+
+func TestHandleMTVTroubleshoot(t *testing.T) {
+	ctx := context.Background()
+	req := &mcp.CallToolRequest{}
+
+	if _, _, err := HandleMTVTroubleshoot(ctx, req, MTVTroubleshootInput{}); err == nil {
+		t.Error("HandleMTVTroubleshoot() without plan expected error")
+	}
+
+	planJSON, _ := json.Marshal(testPlanDescription())
+	var commands []string
+	original := runTroubleshootCommand
+	defer func() { runTroubleshootCommand = original }()
+	runTroubleshootCommand = func(_ context.Context, args []string) (string, error) {
+		commands = append(commands, strings.Join(args, " "))
+		if strings.Contains(strings.Join(args, " "), "--vm ") {
+			response, _ := json.Marshal(map[string]interface{}{"return_value": 1, "stdout": "", "stderr": "VM 'web' not found"})
+			return string(response), nil
+		}
+		response, _ := json.Marshal(map[string]interface{}{"return_value": 0, "stdout": string(planJSON)})
+		return string(response), nil
+	}
+
+	_, data, err := HandleMTVTroubleshoot(ctx, req, MTVTroubleshootInput{Plan: "wave1", VM: "web", Namespace: "demo"})
+	if err != nil {
+		t.Fatalf("HandleMTVTroubleshoot() unexpected error: %v", err)
+	}
+	bundle, ok := data.(map[string]interface{})["data"].(TroubleshootBundle)
+	if !ok || bundle.Plan != "wave1" || bundle.Namespace != "demo" || len(bundle.VMs) != 1 {
+		t.Fatalf("unexpected bundle: %+v", data)
+	}
+	if len(bundle.Errors) != 1 || !strings.Contains(bundle.Errors[0], "not found") {
+		t.Errorf("expected the VM status error to be reported, got %v", bundle.Errors)
+	}
+	if len(commands) != 2 || !strings.Contains(commands[0], "--diagnostics=true") {
+		t.Errorf("unexpected commands: %v", commands)
+	}
+}
+
+func TestGetMTVTroubleshootTool(t *testing.T) {
+	tool := GetMTVTroubleshootTool()
+	if tool.Name != "mtv_troubleshoot" || tool.Annotations == nil || !tool.Annotations.ReadOnlyHint {
+		t.Errorf("unexpected tool: %+v", tool)
+	}
+}
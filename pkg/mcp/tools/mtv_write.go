@@ -17,6 +17,12 @@ type MTVWriteInput struct {
 	Flags map[string]any `json:"flags,omitempty" jsonschema:"All parameters including positional args and options (e.g. name: \"my-provider\", type: \"vsphere\", url: \"https://vcenter/sdk\", namespace: \"ns\")"`
 
 	ShowCLI bool `json:"show_cli,omitempty" jsonschema:"If true, does not execute. Returns the equivalent CLI command in the output field instead"`
+
+	Context string `json:"context,omitempty" jsonschema:"Kubeconfig context (or in-cluster) to target; remembered for the rest of this session"`
+
+	Phase string `json:"phase,omitempty" jsonschema:"Optional two-step write: plan previews the changes as a diff without applying them and returns a transaction token, apply runs the planned write"`
+
+	Transaction string `json:"transaction,omitempty" jsonschema:"Transaction token returned by phase plan; required with phase apply, which takes no command or flags"`
 }
 
 // GetMTVWriteTool returns the tool definition for read-write MTV commands.
@@ -24,9 +30,14 @@ type MTVWriteInput struct {
 // The description lists available commands and hints to use mtv_help.
 func GetMTVWriteTool(registry *discovery.Registry) *mcp.Tool {
 	description := registry.GenerateReadWriteDescription()
+	if util.GetForceDryRun() {
+		description += "\n\nDRY-RUN ONLY: this server forces --dry-run on every write. Commands print the resources they would create or change instead of changing the cluster; set dry_run to \"server\" to also have the cluster validate them. Commands without --dry-run are refused."
+	}
+
+	description += "\n\nTo let the user review a write first, call with phase \"plan\": nothing is changed, the output shows the resource changes as a diff and a transaction token. After the user confirms, call with phase \"apply\" and the transaction token (no command or flags) to run it."
 
 	return &mcp.Tool{
-		Name:         "mtv_write",
+		Name:         ToolWrite,
 		Description:  description,
 		OutputSchema: mtvOutputSchema,
 		Annotations: &mcp.ToolAnnotations{
@@ -43,6 +54,31 @@ func HandleMTVWrite(registry *discovery.Registry) func(context.Context, *mcp.Cal
 		// Extract K8s credentials from HTTP headers (populated by SDK in HTTP mode)
 		ctx = extractKubeCredsFromRequest(ctx, req)
 
+		// An apply runs the command, flags and cluster target of its planned transaction
+		switch input.Phase {
+		case "", PhasePlan:
+		case PhaseApply:
+			tx, err := takeWriteTransaction(input.Transaction, requestSessionID(req))
+			if err != nil {
+				return nil, nil, err
+			}
+			input.Command, input.Flags, input.Context = tx.command, tx.flags, tx.context
+		default:
+			return nil, nil, fmt.Errorf("unknown phase '%s', use '%s' or '%s'", input.Phase, PhasePlan, PhaseApply)
+		}
+		if input.Phase != "" && input.ShowCLI {
+			return nil, nil, fmt.Errorf("phase cannot be combined with show_cli")
+		}
+
+		// Resolve the per-session cluster target
+		if flagContext := takeKubeTargetFlags(input.Flags); input.Context == "" {
+			input.Context = flagContext
+		}
+		ctx, err := applySessionKubeContext(ctx, req, input.Context)
+		if err != nil {
+			return nil, nil, err
+		}
+
 		// Validate input to catch common small-LLM mistakes early
 		if err := validateCommandInput(input.Command); err != nil {
 			return nil, nil, err
@@ -64,11 +100,30 @@ func HandleMTVWrite(registry *discovery.Registry) func(context.Context, *mcp.Cal
 			return nil, nil, fmt.Errorf("unknown command '%s'. Available write commands: %s", input.Command, strings.Join(available, ", "))
 		}
 
-		// Enable show-CLI mode if requested
+		// A planned write runs as a dry run showing a diff, and is kept to be applied later
+		if input.Phase == PhasePlan {
+			return planWrite(ctx, req, registry.ReadWrite[cmdPath], cmdPath, input)
+		}
+
+		// Run every write as a dry run when the server forces it
+		if util.GetForceDryRun() {
+			flags, blocked, out := forceWriteDryRun(registry.ReadWrite[cmdPath], input.Flags)
+			if blocked != nil {
+				return blocked, out, nil
+			}
+			input.Flags = flags
+		}
+
+		// Enable show-CLI mode if requested; otherwise refuse starts and cutovers outside the allowed windows
 		if input.ShowCLI {
 			ctx = util.WithShowCLI(ctx, true)
+		} else if blocked, out := checkWriteWindow(ctx, cmdPath, input.Flags); blocked != nil {
+			return blocked, out, nil
 		}
 
+		// Report the progress of long writes to clients that asked for it
+		ctx = withProgressNotifications(ctx, req)
+
 		// Build command arguments (all params passed via flags)
 		args := buildWriteArgs(cmdPath, input.Flags)
 
@@ -78,6 +133,11 @@ func HandleMTVWrite(registry *discovery.Registry) func(context.Context, *mcp.Cal
 			return nil, nil, fmt.Errorf("command failed: %w", err)
 		}
 
+		// A write may change what cached reads report
+		if !input.ShowCLI && !util.GetForceDryRun() {
+			util.InvalidateReadCache()
+		}
+
 		// Parse and return result
 		data, err := util.UnmarshalJSONResponse(result)
 		if err != nil {
@@ -96,6 +156,75 @@ func HandleMTVWrite(registry *discovery.Registry) func(context.Context, *mcp.Cal
 	}
 }
 
+// planWrite previews a write with a dry run and stores it as a transaction to apply
+func planWrite(ctx context.Context, req *mcp.CallToolRequest, cmd *discovery.Command, cmdPath string, input MTVWriteInput) (*mcp.CallToolResult, any, error) {
+	flags, err := planWriteFlags(cmd, input.Flags)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result, err := util.RunKubectlMTVCommand(withProgressNotifications(ctx, req), buildWriteArgs(cmdPath, flags))
+	if err != nil {
+		return nil, nil, fmt.Errorf("command failed: %w", err)
+	}
+	data, err := util.UnmarshalJSONResponse(result)
+	if err != nil {
+		return nil, nil, err
+	}
+	if errResult := buildCLIErrorResult(data); errResult != nil {
+		enrichErrorWithHelp(errResult, cmd)
+		return errResult, nil, nil
+	}
+
+	session := requestSessionID(req)
+	kubeContext, _ := util.GetSessionKubeContext(session)
+	tx := &writeTransaction{command: input.Command, flags: input.Flags, context: kubeContext, session: session}
+	token, err := newWriteTransaction(tx)
+	if err != nil {
+		return nil, nil, err
+	}
+	return nil, planResult(data, token, tx), nil
+}
+
+// forceWriteDryRun returns the flags of a write command with --dry-run set. Commands without a
+// --dry-run flag would change the cluster, so they are refused with an error result instead.
+func forceWriteDryRun(cmd *discovery.Command, cmdFlags map[string]any) (map[string]any, *mcp.CallToolResult, any) {
+	hasDryRun := false
+	if cmd != nil {
+		for _, f := range cmd.Flags {
+			if f.Name == "dry-run" {
+				hasDryRun = true
+				break
+			}
+		}
+	}
+	if !hasDryRun {
+		command := "this command"
+		if cmd != nil {
+			command = "'" + cmd.CommandPath() + "'"
+		}
+		text := fmt.Sprintf("Blocked: this server forces --dry-run on all write operations, and %s has no --dry-run. Do not retry; ask the user to run it.", command)
+		return nil, &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: text}},
+			IsError: true,
+		}, map[string]interface{}{"return_value": 1, "stderr": text}
+	}
+
+	forced := make(map[string]any, len(cmdFlags)+1)
+	forced["dry-run"] = true
+	for name, value := range cmdFlags {
+		if strings.ReplaceAll(name, "_", "-") == "dry-run" {
+			// Keep a requested server-side dry run, it does not persist anything either
+			if mode, ok := value.(string); ok && strings.EqualFold(mode, "server") {
+				forced["dry-run"] = "server"
+			}
+			continue
+		}
+		forced[name] = value
+	}
+	return forced, nil, nil
+}
+
 // buildWriteArgs builds the command-line arguments for kubectl-mtv write commands.
 // All parameters (namespace, name, etc.) are extracted from the flags map.
 func buildWriteArgs(cmdPath string, flags map[string]any) []string {
@@ -7,6 +7,7 @@ import (
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/yaacov/kubectl-mtv/pkg/mcp/discovery"
+	"github.com/yaacov/kubectl-mtv/pkg/mcp/util"
 )
 
 // --- Tool definition tests ---
@@ -100,6 +101,13 @@ func TestBuildWriteArgs(t *testing.T) {
 			flags:        map[string]any{"name": "my-plan", "namespace": "real-ns"},
 			wantContains: []string{"--namespace", "real-ns"},
 		},
+		{
+			name:         "dry-run mode uses the equals form",
+			cmdPath:      "patch/plan",
+			flags:        map[string]any{"plan-name": "my-plan", "dry_run": "server"},
+			wantContains: []string{"--dry-run=server"},
+			wantMissing:  []string{"--dry-run server"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -257,3 +265,56 @@ func TestHandleMTVWrite_ShowCLI(t *testing.T) {
 		})
 	}
 }
+
+// --- Forced dry-run tests ---
+
+func TestHandleMTVWrite_ForceDryRun(t *testing.T) {
+	util.SetForceDryRun(true)
+	defer util.SetForceDryRun(false)
+
+	registry := testRegistry()
+	registry.ReadWrite["create/provider"].Flags = []discovery.Flag{{Name: "dry-run", Type: "bool"}}
+	handler := HandleMTVWrite(registry)
+	ctx := context.Background()
+
+	if tool := GetMTVWriteTool(registry); !strings.Contains(tool.Description, "DRY-RUN ONLY") {
+		t.Error("Description should tell agents that writes are dry runs")
+	}
+
+	// A command with --dry-run runs with it, even when the agent asks otherwise
+	_, data, err := handler(ctx, &mcp.CallToolRequest{}, MTVWriteInput{
+		Command: "create provider",
+		Flags:   map[string]any{"name": "my-vsphere", "dry_run": false},
+		ShowCLI: true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	output, _ := data.(map[string]interface{})["output"].(string)
+	if !strings.Contains(output, "--dry-run=true") || strings.Contains(output, "--dry-run=false") {
+		t.Errorf("output = %q, want --dry-run=true only", output)
+	}
+
+	// A requested server-side dry run is kept
+	_, data, err = handler(ctx, &mcp.CallToolRequest{}, MTVWriteInput{
+		Command: "create provider",
+		Flags:   map[string]any{"name": "my-vsphere", "dry_run": "server"},
+		ShowCLI: true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	output, _ = data.(map[string]interface{})["output"].(string)
+	if !strings.Contains(output, "--dry-run=server") {
+		t.Errorf("output = %q, want --dry-run=server", output)
+	}
+
+	// A command without --dry-run is refused
+	result, _, err := handler(ctx, &mcp.CallToolRequest{}, MTVWriteInput{Command: "delete plan", Flags: map[string]any{"name": "old-plan"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result == nil || !result.IsError || !strings.Contains(result.Content[0].(*mcp.TextContent).Text, "forces --dry-run") {
+		t.Errorf("delete plan should be refused, got %+v", result)
+	}
+}
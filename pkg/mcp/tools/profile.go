@@ -0,0 +1,78 @@
+package tools
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Tool exposure profiles of the MCP server
+const (
+	// ProfileReadOnly exposes the tools that only read MTV resources and inventory
+	ProfileReadOnly = "read-only"
+	// ProfileDebug adds the troubleshooting tool, which also reads pod logs and events
+	ProfileDebug = "debug"
+	// ProfileFull exposes every tool, including mtv_write
+	ProfileFull = "full"
+)
+
+// Tool names
+const (
+	ToolRead             = "mtv_read"
+	ToolHelp             = "mtv_help"
+	ToolInventorySummary = "mtv_inventory_summary"
+	ToolTroubleshoot     = "mtv_troubleshoot"
+	ToolWrite            = "mtv_write"
+)
+
+// profileTools are the tools registered by each profile
+var profileTools = map[string][]string{
+	ProfileReadOnly: {ToolRead, ToolHelp, ToolInventorySummary},
+	ProfileDebug:    {ToolRead, ToolHelp, ToolInventorySummary, ToolTroubleshoot},
+	ProfileFull:     {ToolRead, ToolHelp, ToolInventorySummary, ToolTroubleshoot, ToolWrite},
+}
+
+// Profiles returns the names of the tool exposure profiles
+func Profiles() []string {
+	return []string{ProfileReadOnly, ProfileDebug, ProfileFull}
+}
+
+// EnabledTools returns the set of tools to register: the listed tools when any are given,
+// otherwise the tools of the profile. readOnly removes mtv_write from either.
+func EnabledTools(profile string, toolNames []string, readOnly bool) (map[string]bool, error) {
+	enabled := map[string]bool{}
+	if len(toolNames) > 0 {
+		known := profileTools[ProfileFull]
+		for _, name := range toolNames {
+			name = strings.TrimSpace(name)
+			if !slices.Contains(known, name) {
+				return nil, fmt.Errorf("unknown tool %q: must be one of: %s", name, strings.Join(known, ", "))
+			}
+			enabled[name] = true
+		}
+	} else {
+		names, ok := profileTools[profile]
+		if !ok {
+			return nil, fmt.Errorf("unknown profile %q: must be one of: %s", profile, strings.Join(Profiles(), ", "))
+		}
+		for _, name := range names {
+			enabled[name] = true
+		}
+	}
+
+	if readOnly {
+		delete(enabled, ToolWrite)
+	}
+	return enabled, nil
+}
+
+// ToolList returns the enabled tool names, sorted
+func ToolList(enabled map[string]bool) []string {
+	names := make([]string, 0, len(enabled))
+	for name := range enabled {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,42 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEnabledTools(t *testing.T) {
+	tests := []struct {
+		name     string
+		profile  string
+		tools    []string
+		readOnly bool
+		want     string
+		wantErr  string
+	}{
+		{name: "full", profile: ProfileFull, want: "mtv_help,mtv_inventory_summary,mtv_read,mtv_troubleshoot,mtv_write"},
+		{name: "full with --read-only", profile: ProfileFull, readOnly: true, want: "mtv_help,mtv_inventory_summary,mtv_read,mtv_troubleshoot"},
+		{name: "read-only", profile: ProfileReadOnly, want: "mtv_help,mtv_inventory_summary,mtv_read"},
+		{name: "debug", profile: ProfileDebug, want: "mtv_help,mtv_inventory_summary,mtv_read,mtv_troubleshoot"},
+		{name: "explicit tools override the profile", profile: ProfileReadOnly, tools: []string{"mtv_read", " mtv_write"}, want: "mtv_read,mtv_write"},
+		{name: "unknown profile", profile: "admin", wantErr: "unknown profile"},
+		{name: "unknown tool", profile: ProfileFull, tools: []string{"mtv_exec"}, wantErr: "unknown tool"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			enabled, err := EnabledTools(tt.profile, tt.tools, tt.readOnly)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("EnabledTools() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("EnabledTools() unexpected error: %v", err)
+			}
+			if got := strings.Join(ToolList(enabled), ","); got != tt.want {
+				t.Errorf("EnabledTools() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package tools
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/yaacov/kubectl-mtv/pkg/mcp/util"
+)
+
+func TestHandleSessionInitialized_ClearsContextOnClose(t *testing.T) {
+	ctx := context.Background()
+
+	initialized := make(chan string, 1)
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "v0.0.1"}, &mcp.ServerOptions{
+		InitializedHandler: func(ctx context.Context, req *mcp.InitializedRequest) {
+			HandleSessionInitialized(ctx, req)
+			initialized <- req.Session.ID()
+		},
+	})
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	serverSession, err := server.Connect(ctx, serverTransport, nil)
+	if err != nil {
+		t.Fatalf("server connect: %v", err)
+	}
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "v0.0.1"}, nil)
+	clientSession, err := client.Connect(ctx, clientTransport, nil)
+	if err != nil {
+		t.Fatalf("client connect: %v", err)
+	}
+
+	var sessionID string
+	select {
+	case sessionID = <-initialized:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the initialized notification")
+	}
+
+	// Select a context for the session the way a tool call does
+	if err := util.SetSessionKubeContext(sessionID, "prod"); err != nil {
+		t.Fatalf("SetSessionKubeContext: %v", err)
+	}
+	defer util.ClearSessionKubeContext(sessionID)
+
+	if err := clientSession.Close(); err != nil {
+		t.Fatalf("client close: %v", err)
+	}
+	_ = serverSession.Wait()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if _, ok := util.GetSessionKubeContext(sessionID); !ok {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("session kubeconfig context was not cleared after the session closed")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
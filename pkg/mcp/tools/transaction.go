@@ -0,0 +1,116 @@
+package tools
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/yaacov/kubectl-mtv/pkg/mcp/discovery"
+)
+
+// Write phases of the mtv_write tool
+const (
+	// PhasePlan previews the changes of a write and returns a transaction token
+	PhasePlan = "plan"
+	// PhaseApply runs the write planned under a transaction token
+	PhaseApply = "apply"
+)
+
+// TransactionTTL is how long a planned write can be applied
+const TransactionTTL = 15 * time.Minute
+
+// transactionNow returns the current time; tests replace it
+var transactionNow = time.Now
+
+// writeTransaction is a write planned with phase "plan", waiting to be applied
+type writeTransaction struct {
+	command string
+	flags   map[string]any
+	context string
+	session string
+	expires time.Time
+}
+
+// writeTransactions holds the planned writes by token
+var writeTransactions sync.Map
+
+// newWriteTransaction stores a planned write and returns its token. Expired transactions
+// are dropped on the way.
+func newWriteTransaction(tx *writeTransaction) (string, error) {
+	now := transactionNow()
+	writeTransactions.Range(func(key, value any) bool {
+		if now.After(value.(*writeTransaction).expires) {
+			writeTransactions.Delete(key)
+		}
+		return true
+	})
+
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to create transaction token: %v", err)
+	}
+	token := "tx-" + hex.EncodeToString(b)
+	tx.expires = now.Add(TransactionTTL)
+	writeTransactions.Store(token, tx)
+	return token, nil
+}
+
+// takeWriteTransaction removes and returns the planned write of a token. A token can be
+// applied once, only by the session that planned it, and only before it expires.
+func takeWriteTransaction(token, session string) (*writeTransaction, error) {
+	if token == "" {
+		return nil, fmt.Errorf("phase 'apply' needs the transaction token returned by phase 'plan'")
+	}
+	value, ok := writeTransactions.Load(token)
+	if !ok {
+		return nil, fmt.Errorf("unknown transaction '%s': it was already applied or never planned, run phase 'plan' again", token)
+	}
+	tx := value.(*writeTransaction)
+	if tx.session != session {
+		return nil, fmt.Errorf("transaction '%s' was planned by another session", token)
+	}
+	writeTransactions.Delete(token)
+	if transactionNow().After(tx.expires) {
+		return nil, fmt.Errorf("transaction '%s' expired at %s, run phase 'plan' again", token, tx.expires.UTC().Format(time.RFC3339))
+	}
+	return tx, nil
+}
+
+// planWriteFlags returns the flags that preview a write: a client dry run (or the server
+// dry run the agent asked for) printing the changes as a diff. Commands without --dry-run
+// cannot be previewed.
+func planWriteFlags(cmd *discovery.Command, cmdFlags map[string]any) (map[string]any, error) {
+	flags, blocked, _ := forceWriteDryRun(cmd, cmdFlags)
+	if blocked != nil {
+		return nil, fmt.Errorf("'%s' has no --dry-run, so it cannot be previewed; run it without phase 'plan'", cmd.CommandPath())
+	}
+	if flags["dry-run"] == true {
+		flags["dry-run"] = "client"
+	}
+	flags["diff"] = true
+	return flags, nil
+}
+
+// planResult adds the transaction token to the output of a previewed write
+func planResult(data map[string]interface{}, token string, tx *writeTransaction) map[string]interface{} {
+	data["transaction"] = token
+	data["expires"] = tx.expires.UTC().Format(time.RFC3339)
+	preview, _ := data["output"].(string)
+	if strings.TrimSpace(preview) == "" {
+		preview = "(no resource changes to preview)"
+	}
+	data["output"] = preview + fmt.Sprintf("\n\nNothing was changed. Show these changes to the user; once they confirm, call mtv_write with phase \"apply\" and transaction %q.", token)
+	return data
+}
+
+// requestSessionID returns the MCP session of a tool call, empty when there is none
+func requestSessionID(req *mcp.CallToolRequest) string {
+	if req == nil || req.Session == nil {
+		return ""
+	}
+	return req.Session.ID()
+}
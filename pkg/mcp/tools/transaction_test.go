@@ -0,0 +1,90 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/yaacov/kubectl-mtv/pkg/mcp/discovery"
+)
+
+func TestWriteTransaction(t *testing.T) {
+	now := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+	transactionNow = func() time.Time { return now }
+	defer func() { transactionNow = time.Now }()
+
+	tx := &writeTransaction{command: "patch plan", flags: map[string]any{"plan-name": "wave-1"}, session: "s1"}
+	token, err := newWriteTransaction(tx)
+	if err != nil {
+		t.Fatalf("newWriteTransaction: %v", err)
+	}
+
+	if _, err := takeWriteTransaction(token, "s2"); err == nil || !strings.Contains(err.Error(), "another session") {
+		t.Errorf("apply from another session: err = %v", err)
+	}
+	got, err := takeWriteTransaction(token, "s1")
+	if err != nil || got.command != "patch plan" {
+		t.Fatalf("takeWriteTransaction = %+v, %v", got, err)
+	}
+	if _, err := takeWriteTransaction(token, "s1"); err == nil || !strings.Contains(err.Error(), "already applied") {
+		t.Errorf("second apply: err = %v", err)
+	}
+
+	token, _ = newWriteTransaction(&writeTransaction{command: "patch plan", session: "s1"})
+	now = now.Add(TransactionTTL + time.Minute)
+	if _, err := takeWriteTransaction(token, "s1"); err == nil || !strings.Contains(err.Error(), "expired") {
+		t.Errorf("expired apply: err = %v", err)
+	}
+}
+
+func TestPlanWriteFlags(t *testing.T) {
+	cmd := &discovery.Command{Path: []string{"patch", "plan"}, Flags: []discovery.Flag{{Name: "dry-run", Type: "string"}}}
+
+	flags, err := planWriteFlags(cmd, map[string]any{"plan-name": "wave-1", "dry_run": false})
+	if err != nil {
+		t.Fatalf("planWriteFlags: %v", err)
+	}
+	if flags["dry-run"] != "client" || flags["diff"] != true || flags["plan-name"] != "wave-1" {
+		t.Errorf("flags = %v, want a client dry run with diff", flags)
+	}
+
+	flags, _ = planWriteFlags(cmd, map[string]any{"dry-run": "server"})
+	if flags["dry-run"] != "server" {
+		t.Errorf("dry-run = %v, want the requested server dry run", flags["dry-run"])
+	}
+
+	if _, err := planWriteFlags(&discovery.Command{Path: []string{"cutover", "plan"}}, nil); err == nil {
+		t.Error("planWriteFlags succeeded for a command without --dry-run")
+	}
+}
+
+func TestHandleMTVWrite_PhaseErrors(t *testing.T) {
+	handler := HandleMTVWrite(testRegistry())
+	ctx := context.Background()
+
+	tests := []struct {
+		name  string
+		input MTVWriteInput
+		want  string
+	}{
+		{"unknown phase", MTVWriteInput{Command: "create provider", Phase: "commit"}, "unknown phase"},
+		{"apply without token", MTVWriteInput{Phase: PhaseApply}, "needs the transaction token"},
+		{"apply unknown token", MTVWriteInput{Phase: PhaseApply, Transaction: "tx-missing"}, "unknown transaction"},
+		{"plan with show_cli", MTVWriteInput{Command: "create provider", Phase: PhasePlan, ShowCLI: true}, "show_cli"},
+		{"plan without dry-run", MTVWriteInput{Command: "delete plan", Phase: PhasePlan}, "cannot be previewed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := handler(ctx, &mcp.CallToolRequest{}, tt.input)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+
+	if tool := GetMTVWriteTool(testRegistry()); !strings.Contains(tool.Description, `phase "plan"`) {
+		t.Error("Description should explain the plan and apply phases")
+	}
+}
@@ -0,0 +1,244 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/yaacov/kubectl-mtv/pkg/mcp/util"
+	"github.com/yaacov/kubectl-mtv/pkg/util/flags"
+	"github.com/yaacov/kubectl-mtv/pkg/util/window"
+	"k8s.io/klog/v2"
+)
+
+// windowGuardedCommands are the write commands that may only run inside the allowed windows
+var windowGuardedCommands = map[string]bool{
+	"start/plan":   true,
+	"cutover/plan": true,
+	"start/wave":   true,
+}
+
+// windowNow returns the current time; tests replace it
+var windowNow = time.Now
+
+// fetchPlanAnnotations returns the annotations of the plans in a namespace, by plan name.
+// Tests replace it to avoid running the CLI.
+var fetchPlanAnnotations = func(ctx context.Context, namespace string) (map[string]map[string]interface{}, error) {
+	args := []string{"get", "plan", "--output", "json"}
+	if namespace != "" {
+		args = append(args, "--namespace", namespace)
+	}
+	result, err := util.RunKubectlMTVCommand(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	data, err := util.UnmarshalJSONResponse(result)
+	if err != nil {
+		return nil, err
+	}
+	if rv, ok := data["return_value"].(float64); ok && rv != 0 {
+		return nil, fmt.Errorf("failed to list plans: %v", data["stderr"])
+	}
+
+	annotations := map[string]map[string]interface{}{}
+	items, _ := data["data"].([]interface{})
+	for _, item := range items {
+		plan, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		object, _ := plan["object"].(map[string]interface{})
+		metadata, _ := object["metadata"].(map[string]interface{})
+		name, _ := metadata["name"].(string)
+		if name == "" {
+			continue
+		}
+		planAnnotations, _ := metadata["annotations"].(map[string]interface{})
+		annotations[name] = planAnnotations
+	}
+	return annotations, nil
+}
+
+// WindowViolation explains why a start or cutover was blocked
+type WindowViolation struct {
+	Blocked        bool             `json:"blocked"`
+	Command        string           `json:"command"`
+	Time           string           `json:"time"`
+	Plans          []PlanWindowInfo `json:"plans"`
+	NextWindowOpen string           `json:"next_window_open,omitempty"`
+	Hint           string           `json:"hint"`
+}
+
+// PlanWindowInfo describes the windows applying to one plan (or to a wave)
+type PlanWindowInfo struct {
+	Plan    string   `json:"plan,omitempty"`
+	Source  string   `json:"source"`
+	Windows []string `json:"allowed_windows,omitempty"`
+	Error   string   `json:"error,omitempty"`
+}
+
+// checkWriteWindow blocks start and cutover commands whose action time falls outside the
+// allowed windows. Plans annotated with window.AllowedWindowsAnnotation use their own
+// windows, other plans the server --allowed-windows. It returns nil when the command may run.
+func checkWriteWindow(ctx context.Context, cmdPath string, cmdFlags map[string]any) (*mcp.CallToolResult, any) {
+	if !windowGuardedCommands[cmdPath] {
+		return nil, nil
+	}
+
+	now := windowNow()
+	actionTime := now
+	if cmdPath == "cutover/plan" {
+		t, err := flagCutoverTime(cmdFlags, now)
+		if err != nil {
+			// Let the command report the invalid time
+			return nil, nil
+		}
+		actionTime = t
+	}
+
+	serverWindows := util.GetAllowedWindows()
+	var blocked []PlanWindowInfo
+	var blockedWindows []*window.Window
+
+	var names []string
+	annotations := map[string]map[string]interface{}{}
+	if cmdPath != "start/wave" {
+		var err error
+		if annotations, err = fetchPlanAnnotations(ctx, flagString(cmdFlags, "namespace", "n")); err != nil {
+			// Plans could not be read; the server windows still apply
+			klog.Warningf("Checking plan windows: %v", err)
+			annotations = map[string]map[string]interface{}{}
+		}
+
+		names = flagList(cmdFlags, "name", "names", "M")
+		if parseBoolValue(cmdFlags["all"]) {
+			names = names[:0]
+			for name := range annotations {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+		}
+	}
+
+	// Waves, and plans that could not be resolved, are checked against the server windows
+	if len(names) == 0 {
+		if len(serverWindows) > 0 && !window.AnyContains(serverWindows, actionTime) {
+			blocked = append(blocked, PlanWindowInfo{Source: "server --allowed-windows", Windows: window.Specs(serverWindows)})
+			blockedWindows = serverWindows
+		}
+	}
+
+	for _, name := range names {
+		info := PlanWindowInfo{Plan: name, Source: "server --allowed-windows"}
+		windows := serverWindows
+		if spec, ok := annotations[name][window.AllowedWindowsAnnotation].(string); ok && strings.TrimSpace(spec) != "" {
+			info.Source = "plan annotation " + window.AllowedWindowsAnnotation
+			planWindows, err := window.ParseList(spec)
+			if err != nil {
+				info.Error = err.Error()
+				blocked = append(blocked, info)
+				continue
+			}
+			windows = planWindows
+		}
+		if len(windows) == 0 || window.AnyContains(windows, actionTime) {
+			continue
+		}
+		info.Windows = window.Specs(windows)
+		blocked = append(blocked, info)
+		blockedWindows = append(blockedWindows, windows...)
+	}
+
+	if len(blocked) == 0 {
+		return nil, nil
+	}
+
+	violation := WindowViolation{
+		Blocked: true,
+		Command: strings.ReplaceAll(cmdPath, "/", " "),
+		Time:    actionTime.UTC().Format(time.RFC3339),
+		Plans:   blocked,
+		Hint:    "Do not retry now. Run the command inside an allowed window, or ask the user to change the windows.",
+	}
+	if next := window.NextOpening(blockedWindows, actionTime); !next.IsZero() {
+		violation.NextWindowOpen = next.UTC().Format(time.RFC3339)
+	}
+
+	text := fmt.Sprintf("Blocked: '%s' at %s is outside the allowed migration windows.", violation.Command, violation.Time)
+	if details, err := json.MarshalIndent(violation, "", "  "); err == nil {
+		text += "\n" + string(details)
+	}
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: text}},
+		IsError: true,
+	}, map[string]interface{}{"return_value": 1, "data": violation}
+}
+
+// flagCutoverTime returns the cutover time requested by the --in, --at or --cutover flags, now when unset
+func flagCutoverTime(cmdFlags map[string]any, now time.Time) (time.Time, error) {
+	if timezone := flagString(cmdFlags, "timezone"); timezone != "" {
+		loc, err := flags.LoadTimezone(timezone)
+		if err != nil {
+			return time.Time{}, err
+		}
+		now = now.In(loc)
+	}
+	if in := flagString(cmdFlags, "in"); in != "" {
+		d, err := flags.ParseRelativeDuration(in)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return now.Add(d), nil
+	}
+	if at := flagString(cmdFlags, "at", "cutover", "c"); at != "" {
+		return flags.ParseTimeValue(at, now)
+	}
+	return now, nil
+}
+
+// flagString returns the first set flag of the given names as a string
+func flagString(cmdFlags map[string]any, names ...string) string {
+	for _, name := range names {
+		if v, ok := cmdFlags[name]; ok && v != nil {
+			if s := strings.TrimSpace(fmt.Sprintf("%v", v)); s != "" {
+				return s
+			}
+		}
+	}
+	return ""
+}
+
+// flagList returns the values of list flags given as arrays or comma-separated strings
+func flagList(cmdFlags map[string]any, names ...string) []string {
+	var values []string
+	for _, name := range names {
+		switch v := cmdFlags[name].(type) {
+		case []any:
+			for _, item := range v {
+				values = append(values, splitList(fmt.Sprintf("%v", item))...)
+			}
+		case []string:
+			for _, item := range v {
+				values = append(values, splitList(item)...)
+			}
+		case nil:
+		default:
+			values = append(values, splitList(fmt.Sprintf("%v", v))...)
+		}
+	}
+	return values
+}
+
+func splitList(value string) []string {
+	var values []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			values = append(values, item)
+		}
+	}
+	return values
+}
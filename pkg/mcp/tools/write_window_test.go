@@ -0,0 +1,94 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/yaacov/kubectl-mtv/pkg/mcp/util"
+	"github.com/yaacov/kubectl-mtv/pkg/util/window"
+)
+
+// setWindowTestEnv fixes the clock on Friday 2026-05-01 12:00 UTC and serves plan annotations from plans
+func setWindowTestEnv(t *testing.T, serverWindows []string, plans map[string]map[string]interface{}) {
+	t.Helper()
+	origNow, origFetch := windowNow, fetchPlanAnnotations
+	t.Cleanup(func() {
+		windowNow, fetchPlanAnnotations = origNow, origFetch
+		_ = util.SetAllowedWindows(nil)
+	})
+
+	windowNow = func() time.Time { return time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC) }
+	fetchPlanAnnotations = func(ctx context.Context, namespace string) (map[string]map[string]interface{}, error) {
+		return plans, nil
+	}
+	if err := util.SetAllowedWindows(serverWindows); err != nil {
+		t.Fatalf("SetAllowedWindows() unexpected error: %v", err)
+	}
+}
+
+func TestCheckWriteWindow_ServerWindows(t *testing.T) {
+	setWindowTestEnv(t, []string{"Sat-Sun"}, map[string]map[string]interface{}{"db": nil})
+	ctx := context.Background()
+
+	result, out := checkWriteWindow(ctx, "start/plan", map[string]any{"name": "db"})
+	if result == nil || !result.IsError {
+		t.Fatal("start plan outside the server windows should be blocked")
+	}
+	violation := out.(map[string]interface{})["data"].(WindowViolation)
+	if violation.NextWindowOpen != "2026-05-02T00:00:00Z" || len(violation.Plans) != 1 || violation.Plans[0].Plan != "db" {
+		t.Errorf("unexpected violation: %+v", violation)
+	}
+
+	// The cutover time is checked, not the time of the call
+	if result, _ := checkWriteWindow(ctx, "cutover/plan", map[string]any{"name": "db", "in": "13h"}); result != nil {
+		t.Error("cutover scheduled inside the window should be allowed")
+	}
+	if result, _ := checkWriteWindow(ctx, "start/wave", map[string]any{"name": "wave-1"}); result == nil {
+		t.Error("start wave outside the server windows should be blocked")
+	}
+	if result, _ := checkWriteWindow(ctx, "create/plan", map[string]any{"name": "db"}); result != nil {
+		t.Error("other write commands should not be checked")
+	}
+}
+
+func TestCheckWriteWindow_PlanAnnotation(t *testing.T) {
+	setWindowTestEnv(t, nil, map[string]map[string]interface{}{
+		"web": {window.AllowedWindowsAnnotation: "Fri 11:00-13:00"},
+		"db":  {window.AllowedWindowsAnnotation: "Mon-Thu 22:00-04:00"},
+		"app": nil,
+		"bad": {window.AllowedWindowsAnnotation: "someday"},
+	})
+	ctx := context.Background()
+
+	if result, _ := checkWriteWindow(ctx, "start/plan", map[string]any{"name": []any{"web", "app"}}); result != nil {
+		t.Error("plans inside their windows, or without windows, should be allowed")
+	}
+
+	result, out := checkWriteWindow(ctx, "start/plan", map[string]any{"all": true})
+	if result == nil {
+		t.Fatal("start plan --all should be blocked by the db and bad plans")
+	}
+	violation := out.(map[string]interface{})["data"].(WindowViolation)
+	if len(violation.Plans) != 2 || violation.Plans[0].Plan != "bad" || violation.Plans[0].Error == "" || violation.Plans[1].Plan != "db" {
+		t.Errorf("unexpected violation: %+v", violation.Plans)
+	}
+	if violation.NextWindowOpen != "2026-05-04T22:00:00Z" {
+		t.Errorf("NextWindowOpen = %s, want Monday 22:00", violation.NextWindowOpen)
+	}
+}
+
+func TestHandleMTVWrite_WindowGuard(t *testing.T) {
+	setWindowTestEnv(t, []string{"Sat-Sun"}, nil)
+	handler := HandleMTVWrite(testRegistry())
+
+	result, _, err := handler(context.Background(), &mcp.CallToolRequest{}, MTVWriteInput{Command: "start plan", Flags: map[string]any{"name": "db"}})
+	if err != nil {
+		t.Fatalf("handler() unexpected error: %v", err)
+	}
+	if result == nil || !result.IsError || !strings.Contains(result.Content[0].(*mcp.TextContent).Text, "outside the allowed migration windows") {
+		t.Errorf("start plan outside the windows should be blocked, got %+v", result)
+	}
+}
@@ -0,0 +1,155 @@
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// AuditStdout is the --audit-log value that writes the audit records to stdout
+const AuditStdout = "-"
+
+// Defaults of the audit file rotation
+const (
+	DefaultAuditMaxSizeMB  = 10
+	DefaultAuditMaxBackups = 3
+)
+
+// AuditRecord describes one tool call
+type AuditRecord struct {
+	Time time.Time `json:"time"`
+	Tool string    `json:"tool"`
+	// Args are the tool arguments with credentials masked
+	Args interface{} `json:"args,omitempty"`
+	// Session, Client and Context identify the caller
+	Session    string  `json:"session,omitempty"`
+	Client     string  `json:"client,omitempty"`
+	Context    string  `json:"context,omitempty"`
+	DurationMS float64 `json:"duration_ms"`
+	ExitCode   int     `json:"exit_code"`
+	Error      string  `json:"error,omitempty"`
+	// RateLimited is set for write calls refused by the per-session rate limit
+	RateLimited bool `json:"rate_limited,omitempty"`
+}
+
+// AuditLog writes audit records as JSON lines
+type AuditLog struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewAuditLog returns an audit log writing JSON lines to w
+func NewAuditLog(w io.Writer) *AuditLog {
+	return &AuditLog{w: w}
+}
+
+// Record writes one audit record; failures are returned so the caller can log them
+func (a *AuditLog) Record(record AuditRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit record: %v", err)
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, err := a.w.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit record: %v", err)
+	}
+	return nil
+}
+
+// auditLog receives the records of every tool call; nil disables auditing
+var auditLog *AuditLog
+
+// SetAuditLog sets the audit log of the server, nil to disable auditing
+func SetAuditLog(log *AuditLog) {
+	auditLog = log
+}
+
+// GetAuditLog returns the audit log of the server, nil when auditing is disabled
+func GetAuditLog() *AuditLog {
+	return auditLog
+}
+
+// RotatingFile is an append-only file that is rotated when it grows over MaxSize:
+// the file is renamed to <path>.1, older files shift to <path>.2 and so on, and files
+// beyond MaxBackups are removed.
+type RotatingFile struct {
+	Path       string
+	MaxSize    int64
+	MaxBackups int
+
+	file *os.File
+	size int64
+}
+
+// OpenRotatingFile opens or creates the file at path for appending
+func OpenRotatingFile(path string, maxSize int64, maxBackups int) (*RotatingFile, error) {
+	r := &RotatingFile{Path: path, MaxSize: maxSize, MaxBackups: maxBackups}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *RotatingFile) open() error {
+	if dir := filepath.Dir(r.Path); dir != "" {
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return fmt.Errorf("failed to create audit log directory: %v", err)
+		}
+	}
+	file, err := os.OpenFile(r.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %v", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to open audit log: %v", err)
+	}
+	r.file, r.size = file, info.Size()
+	return nil
+}
+
+// Write appends p, rotating the file first when p would take it over MaxSize
+func (r *RotatingFile) Write(p []byte) (int, error) {
+	if r.MaxSize > 0 && r.size > 0 && r.size+int64(len(p)) > r.MaxSize {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// rotate shifts the backups and starts a new file
+func (r *RotatingFile) rotate() error {
+	if err := r.file.Close(); err != nil {
+		return fmt.Errorf("failed to close audit log: %v", err)
+	}
+	if r.MaxBackups < 1 {
+		if err := os.Remove(r.Path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to rotate audit log: %v", err)
+		}
+		return r.open()
+	}
+	for i := r.MaxBackups; i > 1; i-- {
+		older := fmt.Sprintf("%s.%d", r.Path, i-1)
+		if err := os.Rename(older, fmt.Sprintf("%s.%d", r.Path, i)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to rotate audit log: %v", err)
+		}
+	}
+	if err := os.Rename(r.Path, r.Path+".1"); err != nil {
+		return fmt.Errorf("failed to rotate audit log: %v", err)
+	}
+	return r.open()
+}
+
+// Close closes the file
+func (r *RotatingFile) Close() error {
+	return r.file.Close()
+}
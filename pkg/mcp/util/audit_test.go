@@ -0,0 +1,102 @@
+package util
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAuditLogRecord(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewAuditLog(&buf)
+	if err := log.Record(AuditRecord{Tool: "mtv_read", ExitCode: 2}); err != nil {
+		t.Fatal(err)
+	}
+	if err := log.Record(AuditRecord{Tool: "mtv_write", RateLimited: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2: %q", len(lines), buf.String())
+	}
+	var record map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &record); err != nil {
+		t.Fatal(err)
+	}
+	if record["tool"] != "mtv_read" || record["exit_code"] != float64(2) {
+		t.Errorf("record = %v", record)
+	}
+	if _, ok := record["rate_limited"]; ok {
+		t.Error("rate_limited should be omitted when false")
+	}
+}
+
+func TestRotatingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit", "calls.log")
+	file, err := OpenRotatingFile(path, 10, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		if _, err := file.Write([]byte(line)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := map[string]string{
+		path:        "fourth\n",
+		path + ".1": "third\n",
+		path + ".2": "second\n",
+	}
+	for name, content := range want {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != content {
+			t.Errorf("%s = %q, want %q", filepath.Base(name), data, content)
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Error("files beyond the backups should be removed")
+	}
+}
+
+func TestAllowWrite(t *testing.T) {
+	SetWriteRateLimit(2)
+	defer SetWriteRateLimit(0)
+
+	now := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+	for i := 0; i < 2; i++ {
+		if ok, _ := AllowWrite("a", now.Add(time.Duration(i)*time.Second)); !ok {
+			t.Fatalf("call %d should be allowed", i+1)
+		}
+	}
+	ok, retryAfter := AllowWrite("a", now.Add(10*time.Second))
+	if ok {
+		t.Fatal("the third call in a minute should be refused")
+	}
+	if retryAfter != 50*time.Second {
+		t.Errorf("retry after = %s, want 50s", retryAfter)
+	}
+	if ok, _ := AllowWrite("b", now.Add(10*time.Second)); !ok {
+		t.Error("other sessions have their own limit")
+	}
+	if ok, _ := AllowWrite("a", now.Add(61*time.Second)); !ok {
+		t.Error("calls should be allowed again after the window")
+	}
+
+	SetWriteRateLimit(0)
+	for i := 0; i < 10; i++ {
+		if ok, _ := AllowWrite("a", now); !ok {
+			t.Fatal("calls should be unlimited with a zero limit")
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package util
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// readCacheTTL is how long a cached read response is reused. 0 disables the cache (default).
+var readCacheTTL time.Duration
+
+// readCacheMaxEntries bounds the number of responses kept in the cache.
+const readCacheMaxEntries = 256
+
+// cacheNow returns the current time; replaced in tests.
+var cacheNow = time.Now
+
+// readCacheEntry is a cached command response and its expiry time.
+type readCacheEntry struct {
+	response string
+	expires  time.Time
+}
+
+// readCache holds cached read responses and usage counters.
+var readCache = struct {
+	sync.Mutex
+	entries  map[string]readCacheEntry
+	hits     int64
+	misses   int64
+	bypassed int64
+}{entries: map[string]readCacheEntry{}}
+
+// ReadCacheStats reports the state of the read cache (the cache_stats diagnostic).
+type ReadCacheStats struct {
+	Enabled    bool    `json:"enabled"`
+	TTLSeconds float64 `json:"ttl_seconds"`
+	Entries    int     `json:"entries"`
+	Hits       int64   `json:"hits"`
+	Misses     int64   `json:"misses"`
+	Bypassed   int64   `json:"bypassed"`
+}
+
+// SetReadCacheTTL sets how long read responses are cached, clears the cache and
+// resets its counters. 0 disables caching.
+func SetReadCacheTTL(ttl time.Duration) {
+	readCacheTTL = ttl
+	InvalidateReadCache()
+
+	readCache.Lock()
+	defer readCache.Unlock()
+	readCache.hits, readCache.misses, readCache.bypassed = 0, 0, 0
+}
+
+// GetReadCacheTTL returns how long read responses are cached (0 when disabled).
+func GetReadCacheTTL() time.Duration {
+	return readCacheTTL
+}
+
+// ReadCacheKey returns the cache key of a read command. The key covers the command path,
+// the flags (including namespace) and the cluster target of the request, so sessions
+// pointed at different clusters or using different credentials never share entries.
+// The key is a hash, so tokens are not kept in memory in clear text.
+func ReadCacheKey(ctx context.Context, cmdPath string, flags map[string]any) string {
+	kubeContext, _ := GetKubeContext(ctx)
+	server, _ := GetKubeServer(ctx)
+	token, _ := GetKubeToken(ctx)
+
+	// json.Marshal sorts map keys, so the same flags always give the same key
+	data, _ := json.Marshal([]any{cmdPath, flags, kubeContext, server, token})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// GetCachedRead returns the cached response for key if it has not expired.
+func GetCachedRead(key string) (string, bool) {
+	readCache.Lock()
+	defer readCache.Unlock()
+
+	entry, ok := readCache.entries[key]
+	if ok && cacheNow().Before(entry.expires) {
+		readCache.hits++
+		return entry.response, true
+	}
+	if ok {
+		delete(readCache.entries, key)
+	}
+	readCache.misses++
+	return "", false
+}
+
+// StoreCachedRead caches a response for the configured TTL. When the cache is full,
+// expired entries are dropped first, then the entry closest to expiry.
+func StoreCachedRead(key, response string) {
+	if readCacheTTL <= 0 {
+		return
+	}
+
+	readCache.Lock()
+	defer readCache.Unlock()
+
+	now := cacheNow()
+	if _, exists := readCache.entries[key]; !exists && len(readCache.entries) >= readCacheMaxEntries {
+		oldestKey := ""
+		var oldest time.Time
+		for k, entry := range readCache.entries {
+			if !now.Before(entry.expires) {
+				delete(readCache.entries, k)
+				continue
+			}
+			if oldestKey == "" || entry.expires.Before(oldest) {
+				oldestKey, oldest = k, entry.expires
+			}
+		}
+		if len(readCache.entries) >= readCacheMaxEntries {
+			delete(readCache.entries, oldestKey)
+		}
+	}
+	readCache.entries[key] = readCacheEntry{response: response, expires: now.Add(readCacheTTL)}
+}
+
+// RecordReadCacheBypass counts a read that skipped the cache on request (no_cache).
+func RecordReadCacheBypass() {
+	readCache.Lock()
+	defer readCache.Unlock()
+	readCache.bypassed++
+}
+
+// InvalidateReadCache drops all cached responses, e.g. after a write operation.
+func InvalidateReadCache() {
+	readCache.Lock()
+	defer readCache.Unlock()
+	readCache.entries = map[string]readCacheEntry{}
+}
+
+// GetReadCacheStats returns the current cache usage counters.
+func GetReadCacheStats() ReadCacheStats {
+	readCache.Lock()
+	defer readCache.Unlock()
+
+	entries := 0
+	now := cacheNow()
+	for _, entry := range readCache.entries {
+		if now.Before(entry.expires) {
+			entries++
+		}
+	}
+	return ReadCacheStats{
+		Enabled:    readCacheTTL > 0,
+		TTLSeconds: readCacheTTL.Seconds(),
+		Entries:    entries,
+		Hits:       readCache.hits,
+		Misses:     readCache.misses,
+		Bypassed:   readCache.bypassed,
+	}
+}
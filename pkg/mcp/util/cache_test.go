@@ -0,0 +1,99 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func setCacheClock(t *testing.T, now *time.Time) {
+	t.Helper()
+	orig := cacheNow
+	cacheNow = func() time.Time { return *now }
+	t.Cleanup(func() { cacheNow = orig })
+}
+
+func TestReadCache_TTL(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	setCacheClock(t, &now)
+	SetReadCacheTTL(30 * time.Second)
+	defer SetReadCacheTTL(0)
+
+	key := ReadCacheKey(context.Background(), "get/inventory/vm", map[string]any{"provider": "vsphere", "namespace": "demo"})
+	if _, ok := GetCachedRead(key); ok {
+		t.Fatal("GetCachedRead() hit on an empty cache")
+	}
+
+	StoreCachedRead(key, "response")
+	if got, ok := GetCachedRead(key); !ok || got != "response" {
+		t.Errorf("GetCachedRead() = %q, %t, want cached response", got, ok)
+	}
+
+	now = now.Add(31 * time.Second)
+	if _, ok := GetCachedRead(key); ok {
+		t.Error("GetCachedRead() returned an expired entry")
+	}
+
+	stats := GetReadCacheStats()
+	if !stats.Enabled || stats.TTLSeconds != 30 || stats.Hits != 1 || stats.Misses < 2 || stats.Entries != 0 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestReadCache_Disabled(t *testing.T) {
+	SetReadCacheTTL(0)
+	StoreCachedRead("key", "response")
+	if _, ok := GetCachedRead("key"); ok {
+		t.Error("StoreCachedRead() cached a response with the cache disabled")
+	}
+	if GetReadCacheStats().Enabled {
+		t.Error("stats report the cache as enabled")
+	}
+}
+
+func TestReadCacheKey(t *testing.T) {
+	ctx := context.Background()
+	flags := map[string]any{"provider": "vsphere", "namespace": "demo"}
+	key := ReadCacheKey(ctx, "get/inventory/vm", flags)
+
+	// Flag order does not matter, everything else does
+	if ReadCacheKey(ctx, "get/inventory/vm", map[string]any{"namespace": "demo", "provider": "vsphere"}) != key {
+		t.Error("ReadCacheKey() depends on flag order")
+	}
+	others := []string{
+		ReadCacheKey(ctx, "get/inventory/network", flags),
+		ReadCacheKey(ctx, "get/inventory/vm", map[string]any{"provider": "vsphere", "namespace": "prod"}),
+		ReadCacheKey(WithKubeContext(ctx, "other-cluster"), "get/inventory/vm", flags),
+		ReadCacheKey(WithKubeToken(ctx, "other-user"), "get/inventory/vm", flags),
+	}
+	for i, other := range others {
+		if other == key {
+			t.Errorf("key %d equals the base key", i)
+		}
+	}
+}
+
+func TestReadCache_EvictsWhenFull(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	setCacheClock(t, &now)
+	SetReadCacheTTL(time.Minute)
+	defer SetReadCacheTTL(0)
+
+	for i := 0; i <= readCacheMaxEntries; i++ {
+		StoreCachedRead(fmt.Sprintf("key-%d", i), "response")
+		now = now.Add(time.Millisecond)
+	}
+
+	if entries := GetReadCacheStats().Entries; entries != readCacheMaxEntries {
+		t.Errorf("Entries = %d, want %d", entries, readCacheMaxEntries)
+	}
+	if _, ok := GetCachedRead("key-0"); ok {
+		t.Error("the oldest entry was not evicted")
+	}
+
+	InvalidateReadCache()
+	if entries := GetReadCacheStats().Entries; entries != 0 {
+		t.Errorf("Entries after InvalidateReadCache() = %d, want 0", entries)
+	}
+}
@@ -0,0 +1,15 @@
+package util
+
+// forceDryRun makes every mtv_write command run with --dry-run; commands without
+// a --dry-run flag are refused.
+var forceDryRun bool
+
+// SetForceDryRun sets whether write operations are forced to run with --dry-run.
+func SetForceDryRun(force bool) {
+	forceDryRun = force
+}
+
+// GetForceDryRun returns whether write operations are forced to run with --dry-run.
+func GetForceDryRun() bool {
+	return forceDryRun
+}
@@ -0,0 +1,73 @@
+package util
+
+import (
+	"bytes"
+	"context"
+	"sync"
+
+	"github.com/yaacov/kubectl-mtv/pkg/util/progress"
+)
+
+// ProgressFunc receives the progress events of a running command
+type ProgressFunc func(progress.Event)
+
+// progressFuncKey is the context key for the progress callback
+const progressFuncKey contextKey = "progress_func"
+
+// WithProgressFunc asks RunKubectlMTVCommand to run the command with --progress json
+// and pass its progress events to fn while it runs
+func WithProgressFunc(ctx context.Context, fn ProgressFunc) context.Context {
+	return context.WithValue(ctx, progressFuncKey, fn)
+}
+
+// GetProgressFunc retrieves the progress callback from the context
+func GetProgressFunc(ctx context.Context) (ProgressFunc, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	fn, ok := ctx.Value(progressFuncKey).(ProgressFunc)
+	return fn, ok && fn != nil
+}
+
+// progressWriter is the stderr of a command run with --progress json: progress event
+// lines go to the callback, all other output is kept in the buffer
+type progressWriter struct {
+	mu      sync.Mutex
+	fn      ProgressFunc
+	partial []byte
+	buf     bytes.Buffer
+}
+
+func (w *progressWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.partial = append(w.partial, p...)
+	for {
+		i := bytes.IndexByte(w.partial, '\n')
+		if i < 0 {
+			break
+		}
+		w.line(w.partial[:i+1])
+		w.partial = w.partial[i+1:]
+	}
+	return len(p), nil
+}
+
+func (w *progressWriter) line(line []byte) {
+	if event, ok := progress.ParseEvent(string(line)); ok {
+		w.fn(event)
+		return
+	}
+	w.buf.Write(line)
+}
+
+// String returns the non-progress output, including an unterminated last line
+func (w *progressWriter) String() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.partial) > 0 {
+		w.line(w.partial)
+		w.partial = nil
+	}
+	return w.buf.String()
+}
@@ -0,0 +1,31 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/yaacov/kubectl-mtv/pkg/util/progress"
+)
+
+func TestProgressWriter(t *testing.T) {
+	var events []progress.Event
+	w := &progressWriter{fn: func(e progress.Event) { events = append(events, e) }}
+
+	// Events may arrive split across writes and mixed with other output
+	chunks := []string{
+		"Warning: VM web-01 not found\n{\"type\":\"progress\",\"operation\":\"create plan\",",
+		"\"stage\":\"resolve-vms\",\"current\":1,\"total\":2}\n",
+		"Error: boom",
+	}
+	for _, chunk := range chunks {
+		if _, err := w.Write([]byte(chunk)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if got := w.String(); got != "Warning: VM web-01 not found\nError: boom" {
+		t.Errorf("stderr = %q, want the output without progress events", got)
+	}
+	if len(events) != 1 || events[0].Stage != "resolve-vms" || events[0].Current != 1 || events[0].Total != 2 {
+		t.Errorf("events = %+v, want one resolve-vms event at 1/2", events)
+	}
+}
@@ -0,0 +1,58 @@
+package util
+
+import (
+	"sync"
+	"time"
+)
+
+// rateLimitWindow is the window of the write rate limit
+const rateLimitWindow = time.Minute
+
+// writeRateLimit is the number of write calls a session may make per minute, 0 for unlimited
+var writeRateLimit int
+
+// writeCalls are the times of the recent write calls, by session
+var (
+	writeCallsMu sync.Mutex
+	writeCalls   = map[string][]time.Time{}
+)
+
+// SetWriteRateLimit sets the number of write calls a session may make per minute (0 = unlimited)
+func SetWriteRateLimit(perMinute int) {
+	writeRateLimit = perMinute
+	writeCallsMu.Lock()
+	writeCalls = map[string][]time.Time{}
+	writeCallsMu.Unlock()
+}
+
+// GetWriteRateLimit returns the number of write calls a session may make per minute
+func GetWriteRateLimit() int {
+	return writeRateLimit
+}
+
+// AllowWrite records a write call of a session when the session is within its rate limit.
+// Otherwise it returns false and how long the session has to wait for the next call.
+func AllowWrite(sessionID string, now time.Time) (bool, time.Duration) {
+	if writeRateLimit <= 0 {
+		return true, 0
+	}
+
+	writeCallsMu.Lock()
+	defer writeCallsMu.Unlock()
+
+	// Keep only the calls in the last window
+	calls := writeCalls[sessionID]
+	start := now.Add(-rateLimitWindow)
+	i := 0
+	for i < len(calls) && !calls[i].After(start) {
+		i++
+	}
+	calls = calls[i:]
+
+	if len(calls) >= writeRateLimit {
+		writeCalls[sessionID] = calls
+		return false, calls[0].Add(rateLimitWindow).Sub(now)
+	}
+	writeCalls[sessionID] = append(calls, now)
+	return true, 0
+}
@@ -0,0 +1,184 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+)
+
+const (
+	// InClusterContext is the special context name that selects the MCP server's
+	// in-cluster service account instead of a kubeconfig context.
+	InClusterContext = "in-cluster"
+
+	// SessionContextMetaKey is the initialize request _meta key clients use to
+	// select a kubeconfig context for the whole session.
+	SessionContextMetaKey = "kubectl-mtv/context"
+
+	// inClusterServer is the API server URL reachable from inside a pod.
+	inClusterServer = "https://kubernetes.default.svc"
+)
+
+// In-cluster service account credential paths (variables so tests can override them).
+var (
+	inClusterTokenFile  = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+	inClusterCACertFile = "/var/run/secrets/kubernetes.io/serviceaccount/ca.crt"
+)
+
+// kubeContextKey is the context key for the per-session kubeconfig context
+const kubeContextKey contextKey = "kube_context"
+
+// WithKubeContext adds a kubeconfig context name to the context
+func WithKubeContext(ctx context.Context, kubeContext string) context.Context {
+	return context.WithValue(ctx, kubeContextKey, kubeContext)
+}
+
+// GetKubeContext retrieves the kubeconfig context name from the context
+func GetKubeContext(ctx context.Context) (string, bool) {
+	if ctx == nil {
+		return "", false
+	}
+	kubeContext, ok := ctx.Value(kubeContextKey).(string)
+	return kubeContext, ok
+}
+
+// defaultKubeconfig stores the kubeconfig file path set via CLI flags.
+// Sessions can only select contexts from this file; they never pass their own path.
+var defaultKubeconfig string
+
+// SetDefaultKubeconfig sets the kubeconfig file path from CLI flags.
+func SetDefaultKubeconfig(path string) {
+	defaultKubeconfig = path
+}
+
+// GetDefaultKubeconfig returns the kubeconfig file path set via CLI flags.
+func GetDefaultKubeconfig() string {
+	return defaultKubeconfig
+}
+
+// defaultKubeContext stores the kubeconfig context used when a session did not select one.
+var defaultKubeContext string
+
+// SetDefaultKubeContext sets the default kubeconfig context from CLI flags.
+func SetDefaultKubeContext(kubeContext string) {
+	defaultKubeContext = kubeContext
+}
+
+// GetDefaultKubeContext returns the default kubeconfig context set via CLI flags.
+func GetDefaultKubeContext() string {
+	return defaultKubeContext
+}
+
+// allowedKubeContexts restricts which contexts sessions may select.
+// An empty set allows any context present in the kubeconfig.
+var allowedKubeContexts map[string]bool
+
+// SetAllowedKubeContexts sets the contexts sessions are allowed to select.
+func SetAllowedKubeContexts(contexts []string) {
+	allowedKubeContexts = nil
+	for _, c := range contexts {
+		c = strings.TrimSpace(c)
+		if c == "" {
+			continue
+		}
+		if allowedKubeContexts == nil {
+			allowedKubeContexts = make(map[string]bool)
+		}
+		allowedKubeContexts[c] = true
+	}
+}
+
+// ValidateKubeContext checks that a session may select the given context.
+func ValidateKubeContext(kubeContext string) error {
+	if strings.ContainsAny(kubeContext, " \t\n") || strings.HasPrefix(kubeContext, "-") {
+		return fmt.Errorf("invalid context name %q", kubeContext)
+	}
+	if allowedKubeContexts != nil && !allowedKubeContexts[kubeContext] {
+		allowed := make([]string, 0, len(allowedKubeContexts))
+		for c := range allowedKubeContexts {
+			allowed = append(allowed, c)
+		}
+		return fmt.Errorf("context %q is not allowed by this MCP server (allowed: %s)", kubeContext, strings.Join(allowed, ", "))
+	}
+	return nil
+}
+
+// sessionContexts maps MCP session IDs to the kubeconfig context selected by that session.
+// Each session only ever reads its own entry, so credentials selected by one agent are
+// never used for another agent's tool calls.
+var sessionContexts sync.Map
+
+// SetSessionKubeContext records the kubeconfig context selected by an MCP session.
+// An empty context clears the selection.
+func SetSessionKubeContext(sessionID, kubeContext string) error {
+	if kubeContext == "" {
+		sessionContexts.Delete(sessionID)
+		return nil
+	}
+	if err := ValidateKubeContext(kubeContext); err != nil {
+		return err
+	}
+	sessionContexts.Store(sessionID, kubeContext)
+	return nil
+}
+
+// GetSessionKubeContext returns the kubeconfig context selected by an MCP session.
+func GetSessionKubeContext(sessionID string) (string, bool) {
+	v, ok := sessionContexts.Load(sessionID)
+	if !ok {
+		return "", false
+	}
+	return v.(string), true
+}
+
+// ClearSessionKubeContext forgets the context selected by an MCP session.
+func ClearSessionKubeContext(sessionID string) {
+	sessionContexts.Delete(sessionID)
+}
+
+// WithKubeContextFromHeaders extracts the X-Kubernetes-Context header and adds it to the context.
+func WithKubeContextFromHeaders(ctx context.Context, headers http.Header) context.Context {
+	if headers == nil {
+		return ctx
+	}
+	if kubeContext := headers.Get("X-Kubernetes-Context"); kubeContext != "" {
+		ctx = WithKubeContext(ctx, kubeContext)
+	}
+	return ctx
+}
+
+// kubeTargetArgs returns the --kubeconfig/--context (or in-cluster) flags for a command.
+// Precedence: context (session or header) > CLI default context > kubeconfig current-context.
+// The second return value reports whether the target was explicitly selected, in which
+// case the CLI default --server/--token must not be applied on top of it.
+func kubeTargetArgs(ctx context.Context) ([]string, bool, error) {
+	kubeContext, ok := GetKubeContext(ctx)
+	if !ok || kubeContext == "" {
+		kubeContext = defaultKubeContext
+	}
+	selected := ok && kubeContext != ""
+
+	if kubeContext == InClusterContext {
+		token, err := os.ReadFile(inClusterTokenFile)
+		if err != nil {
+			return nil, true, fmt.Errorf("in-cluster credentials are not available: %w", err)
+		}
+		return []string{
+			"--server", inClusterServer,
+			"--certificate-authority", inClusterCACertFile,
+			"--token", strings.TrimSpace(string(token)),
+		}, true, nil
+	}
+
+	var args []string
+	if defaultKubeconfig != "" {
+		args = append(args, "--kubeconfig", defaultKubeconfig)
+	}
+	if kubeContext != "" {
+		args = append(args, "--context", kubeContext)
+	}
+	return args, selected, nil
+}
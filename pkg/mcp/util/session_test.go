@@ -0,0 +1,135 @@
+package util
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSetSessionKubeContext_Isolation(t *testing.T) {
+	defer SetAllowedKubeContexts(nil)
+	defer ClearSessionKubeContext("session-a")
+	defer ClearSessionKubeContext("session-b")
+
+	if err := SetSessionKubeContext("session-a", "prod"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := SetSessionKubeContext("session-b", "staging"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got, _ := GetSessionKubeContext("session-a"); got != "prod" {
+		t.Errorf("session-a context = %q, want %q", got, "prod")
+	}
+	if got, _ := GetSessionKubeContext("session-b"); got != "staging" {
+		t.Errorf("session-b context = %q, want %q", got, "staging")
+	}
+
+	// Clearing one session must not affect the other
+	if err := SetSessionKubeContext("session-a", ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := GetSessionKubeContext("session-a"); ok {
+		t.Error("session-a context should be cleared")
+	}
+	if got, _ := GetSessionKubeContext("session-b"); got != "staging" {
+		t.Errorf("session-b context = %q, want %q", got, "staging")
+	}
+}
+
+func TestValidateKubeContext(t *testing.T) {
+	defer SetAllowedKubeContexts(nil)
+
+	SetAllowedKubeContexts(nil)
+	if err := ValidateKubeContext("anything"); err != nil {
+		t.Errorf("expected any context to be allowed, got: %v", err)
+	}
+	if err := ValidateKubeContext("--kubeconfig=/etc/passwd"); err == nil {
+		t.Error("expected flag-like context name to be rejected")
+	}
+
+	SetAllowedKubeContexts([]string{"prod", " in-cluster "})
+	if err := ValidateKubeContext("prod"); err != nil {
+		t.Errorf("expected prod to be allowed, got: %v", err)
+	}
+	if err := ValidateKubeContext(InClusterContext); err != nil {
+		t.Errorf("expected in-cluster to be allowed, got: %v", err)
+	}
+	if err := ValidateKubeContext("staging"); err == nil {
+		t.Error("expected staging to be rejected")
+	}
+}
+
+func TestWithKubeContextFromHeaders(t *testing.T) {
+	headers := http.Header{}
+	headers.Set("X-Kubernetes-Context", "prod")
+
+	ctx := WithKubeContextFromHeaders(context.Background(), headers)
+	if got, ok := GetKubeContext(ctx); !ok || got != "prod" {
+		t.Errorf("GetKubeContext() = %q, %v; want %q, true", got, ok, "prod")
+	}
+
+	ctx = WithKubeContextFromHeaders(context.Background(), nil)
+	if _, ok := GetKubeContext(ctx); ok {
+		t.Error("expected no context when headers are nil")
+	}
+}
+
+func TestRunKubectlMTVCommand_SessionContextReplacesDefaults(t *testing.T) {
+	origServer := GetDefaultKubeServer()
+	origToken := GetDefaultKubeToken()
+	origKubeconfig := GetDefaultKubeconfig()
+	defer func() {
+		SetDefaultKubeServer(origServer)
+		SetDefaultKubeToken(origToken)
+		SetDefaultKubeconfig(origKubeconfig)
+	}()
+
+	SetDefaultKubeServer("https://cli-default.example.com:6443")
+	SetDefaultKubeToken("cli-default-token")
+	SetDefaultKubeconfig("/etc/mtv/kubeconfig")
+
+	ctx := WithKubeContext(context.Background(), "prod")
+	ctx = WithShowCLI(ctx, true)
+
+	result, err := RunKubectlMTVCommand(ctx, []string{"get", "plan"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, want := range []string{"--kubeconfig", "/etc/mtv/kubeconfig", "--context", "prod"} {
+		if !strings.Contains(result, want) {
+			t.Errorf("expected %q in command, got: %s", want, result)
+		}
+	}
+	if strings.Contains(result, "cli-default.example.com") || strings.Contains(result, "--token") {
+		t.Errorf("CLI default credentials should NOT apply to a selected context, got: %s", result)
+	}
+}
+
+func TestRunKubectlMTVCommand_InClusterContext(t *testing.T) {
+	origTokenFile := inClusterTokenFile
+	defer func() { inClusterTokenFile = origTokenFile }()
+
+	inClusterTokenFile = filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(inClusterTokenFile, []byte("sa-token\n"), 0o600); err != nil {
+		t.Fatalf("failed to write token file: %v", err)
+	}
+
+	ctx := WithKubeContext(context.Background(), InClusterContext)
+	ctx = WithShowCLI(ctx, true)
+
+	result, err := RunKubectlMTVCommand(ctx, []string{"get", "plan"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(result, "https://kubernetes.default.svc") {
+		t.Errorf("expected in-cluster server in command, got: %s", result)
+	}
+	if strings.Contains(result, "sa-token") {
+		t.Errorf("service account token should be redacted, got: %s", result)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
 	"os/exec"
@@ -14,6 +15,9 @@ import (
 
 	shellquote "github.com/kballard/go-shellquote"
 	"k8s.io/klog/v2"
+
+	"github.com/yaacov/kubectl-mtv/pkg/util/progress"
+	"github.com/yaacov/kubectl-mtv/pkg/util/prompt"
 )
 
 // contextKey is a custom type for context keys to avoid collisions
@@ -260,12 +264,24 @@ func RunKubectlMTVCommand(ctx context.Context, args []string) (string, error) {
 		args = append([]string{"--certificate-authority", defaultKubeCACert}, args...)
 	}
 
+	// Select the kubeconfig context (or in-cluster service account) for this session.
+	// An explicitly selected target replaces the CLI default --server/--token so
+	// that sessions pointed at different clusters never share credentials.
+	targetArgs, targetSelected, err := kubeTargetArgs(ctx)
+	if err != nil {
+		return "", err
+	}
+	if len(targetArgs) > 0 {
+		klog.V(2).Infof("[auth] using session target: %s", formatShellCommand("", targetArgs))
+		args = append(targetArgs, args...)
+	}
+
 	// Check context first (HTTP headers), then fall back to CLI defaults for --server flag
 	// Server flag is prepended first so it appears before --token in the final command
 	if server, ok := GetKubeServer(ctx); ok && server != "" {
 		klog.V(2).Infof("[auth] using --server from HTTP header: %s", server)
 		args = append([]string{"--server", server}, args...)
-	} else if defaultKubeServer != "" {
+	} else if defaultKubeServer != "" && !targetSelected {
 		klog.V(2).Infof("[auth] using --server from CLI flag: %s", defaultKubeServer)
 		args = append([]string{"--server", defaultKubeServer}, args...)
 	} else {
@@ -276,7 +292,7 @@ func RunKubectlMTVCommand(ctx context.Context, args []string) (string, error) {
 	if token, ok := GetKubeToken(ctx); ok && token != "" {
 		klog.V(2).Info("[auth] using --token from HTTP header")
 		args = append([]string{"--token", token}, args...)
-	} else if defaultKubeToken != "" {
+	} else if defaultKubeToken != "" && !targetSelected {
 		klog.V(2).Info("[auth] using --token from CLI flag")
 		args = append([]string{"--token", defaultKubeToken}, args...)
 	} else {
@@ -309,11 +325,22 @@ func RunKubectlMTVCommand(ctx context.Context, args []string) (string, error) {
 		return "", fmt.Errorf("failed to resolve environment variables: %w", err)
 	}
 
-	cmd := exec.Command(selfExePath, resolvedArgs...)
+	// Report progress events while the command runs, keeping them out of its stderr
+	var stdout bytes.Buffer
+	var stderr interface {
+		io.Writer
+		String() string
+	} = &bytes.Buffer{}
+	if fn, ok := GetProgressFunc(ctx); ok {
+		resolvedArgs = append([]string{"--progress", progress.ModeJSON}, resolvedArgs...)
+		stderr = &progressWriter{fn: fn}
+	}
 
-	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(selfExePath, resolvedArgs...)
 	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
+	cmd.Stderr = stderr
+	// The tool call is the confirmation: the subprocess has no terminal to ask on
+	cmd.Env = append(os.Environ(), prompt.AssumeYesEnv+"=true")
 
 	// Set timeout of 120 seconds
 	timer := time.AfterFunc(120*time.Second, func() {
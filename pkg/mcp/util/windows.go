@@ -0,0 +1,24 @@
+package util
+
+import (
+	"github.com/yaacov/kubectl-mtv/pkg/util/window"
+)
+
+// allowedWindows restricts when start and cutover commands may run.
+// Empty allows them at any time, unless a plan sets its own windows.
+var allowedWindows []*window.Window
+
+// SetAllowedWindows sets the server-level windows in which plans may be started or cut over.
+func SetAllowedWindows(specs []string) error {
+	windows, err := window.ParseList(specs...)
+	if err != nil {
+		return err
+	}
+	allowedWindows = windows
+	return nil
+}
+
+// GetAllowedWindows returns the server-level windows (nil when not restricted).
+func GetAllowedWindows() []*window.Window {
+	return allowedWindows
+}
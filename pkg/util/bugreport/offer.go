@@ -0,0 +1,135 @@
+package bugreport
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// Environment variables that control bug report generation
+const (
+	// EnvBugReport selects when reports are written: ask (default), always or off
+	EnvBugReport = "KUBECTL_MTV_BUG_REPORT"
+	// EnvBugReportDir overrides the directory reports are written to
+	EnvBugReportDir = "KUBECTL_MTV_BUG_REPORT_DIR"
+)
+
+// Values of EnvBugReport
+const (
+	ModeAsk    = "ask"
+	ModeAlways = "always"
+	ModeOff    = "off"
+)
+
+// serverErrorMarkers are the messages of server side failures. Most commands wrap API errors
+// with %v, so the messages are matched when the typed error is no longer available.
+var serverErrorMarkers = []string{
+	"Internal error occurred",
+	"an error on the server",
+	"the server is currently unable to handle the request",
+	"the server was unable to return a response",
+	"Internal Server Error",
+}
+
+// inventoryServerError matches server side failures of the inventory service
+var inventoryServerError = regexp.MustCompile(`HTTP 5\d\d`)
+
+// IsUnexpected reports whether err is a server side failure worth reporting, rather than
+// a usage, validation or not found error the user can fix
+func IsUnexpected(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var status apierrors.APIStatus
+	if errors.As(err, &status) {
+		return apierrors.IsInternalError(err) || apierrors.IsUnexpectedServerError(err) ||
+			apierrors.IsServerTimeout(err) || status.Status().Code >= 500
+	}
+
+	message := err.Error()
+	for _, marker := range serverErrorMarkers {
+		if strings.Contains(message, marker) {
+			return true
+		}
+	}
+	return inventoryServerError.MatchString(message)
+}
+
+// Mode returns the bug report mode selected by the environment
+func Mode() string {
+	switch strings.ToLower(os.Getenv(EnvBugReport)) {
+	case ModeAlways, "on", "1", "true":
+		return ModeAlways
+	case ModeOff, "0", "false", "never":
+		return ModeOff
+	default:
+		return ModeAsk
+	}
+}
+
+// Dir returns the directory bug reports are written to
+func Dir() (string, error) {
+	if dir := os.Getenv(EnvBugReportDir); dir != "" {
+		return dir, nil
+	}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find user cache directory: %v", err)
+	}
+	return filepath.Join(dir, "kubectl-mtv", "bug-reports"), nil
+}
+
+// Interactive reports whether the user can answer a prompt on stdin
+func Interactive() bool {
+	for _, f := range []*os.File{os.Stdin, os.Stderr} {
+		info, err := f.Stat()
+		if err != nil || info.Mode()&os.ModeCharDevice == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Offer writes the report according to mode. In ask mode the user is prompted on in when
+// interactive, otherwise only told how to get a report. Messages are printed to out.
+func Offer(r *Report, mode string, interactive bool, in io.Reader, out io.Writer) {
+	if mode == ModeOff {
+		return
+	}
+
+	dir, err := Dir()
+	if err != nil {
+		fmt.Fprintf(out, "Failed to write bug report: %v\n", err)
+		return
+	}
+
+	if mode != ModeAlways {
+		if !interactive {
+			fmt.Fprintf(out, "\nTo write a pre-filled bug report, run the command again with %s=%s.\n", EnvBugReport, ModeAlways)
+			return
+		}
+		fmt.Fprintf(out, "\nThis looks like a bug in kubectl-mtv or MTV. Write a pre-filled bug report to %s? [y/N]: ", dir)
+		answer, _ := bufio.NewReader(in).ReadString('\n')
+		switch strings.ToLower(strings.TrimSpace(answer)) {
+		case "y", "yes":
+		default:
+			return
+		}
+	}
+
+	path, err := Write(dir, r)
+	if err != nil {
+		fmt.Fprintf(out, "Failed to write bug report: %v\n", err)
+		return
+	}
+	fmt.Fprintf(out, "Bug report written to %s\n", path)
+	fmt.Fprintf(out, "Please review it, then attach it to a new issue at %s\n", IssuesURL)
+}
@@ -0,0 +1,130 @@
+package bugreport
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	pkgversion "github.com/yaacov/kubectl-mtv/pkg/version"
+)
+
+// IssuesURL is where bug reports are filed
+const IssuesURL = "https://github.com/yaacov/kubectl-mtv/issues/new"
+
+// environmentVariables are reported as set or unset, their values are never collected
+var environmentVariables = []string{
+	"KUBECONFIG",
+	"MTV_INVENTORY_URL",
+	"MTV_INVENTORY_INSECURE_SKIP_TLS",
+	"MTV_NAMESPACE",
+	"MTV_TIME_FORMAT",
+	"NO_COLOR",
+}
+
+// Report is the content of a pre-filled bug report
+type Report struct {
+	Time      time.Time
+	Command   string
+	Args      []string
+	Error     string
+	Panic     bool
+	Stack     string
+	Version   string
+	GoVersion string
+	Platform  string
+	Binary    string
+	// Environment maps the names of relevant environment variables to "set" or "unset"
+	Environment map[string]string
+}
+
+// New returns a report of a failed command. args are the command line arguments without
+// the binary name; flag values that may hold credentials are redacted. stack is only given
+// for panics.
+func New(command string, args []string, failure string, stack []byte) *Report {
+	env := map[string]string{}
+	for _, name := range environmentVariables {
+		env[name] = "unset"
+		if os.Getenv(name) != "" {
+			env[name] = "set"
+		}
+	}
+
+	binary := "kubectl-mtv"
+	if len(os.Args) > 0 {
+		binary = filepath.Base(os.Args[0])
+	}
+
+	return &Report{
+		Time:        time.Now().UTC(),
+		Command:     command,
+		Args:        RedactArgs(args),
+		Error:       RedactText(failure),
+		Panic:       stack != nil,
+		Stack:       string(stack),
+		Version:     pkgversion.ClientVersion,
+		GoVersion:   runtime.Version(),
+		Platform:    runtime.GOOS + "/" + runtime.GOARCH,
+		Binary:      binary,
+		Environment: env,
+	}
+}
+
+// Markdown renders the report as a GitHub issue body
+func (r *Report) Markdown() string {
+	var sb strings.Builder
+
+	title := "Error in"
+	if r.Panic {
+		title = "Panic in"
+	}
+	fmt.Fprintf(&sb, "# %s `kubectl mtv %s`\n\n", title, r.Command)
+
+	sb.WriteString("## Description\n\n")
+	sb.WriteString("<!-- What were you trying to do, and what did you expect to happen? -->\n\n")
+
+	sb.WriteString("## Command\n\n")
+	sb.WriteString("Flag values that may hold credentials are redacted.\n\n")
+	fmt.Fprintf(&sb, "```\nkubectl mtv %s\n```\n\n", strings.Join(r.Args, " "))
+
+	sb.WriteString("## Error\n\n")
+	fmt.Fprintf(&sb, "```\n%s\n```\n\n", strings.TrimSpace(r.Error))
+
+	if r.Stack != "" {
+		sb.WriteString("## Stack Trace\n\n")
+		fmt.Fprintf(&sb, "```\n%s\n```\n\n", strings.TrimSpace(r.Stack))
+	}
+
+	sb.WriteString("## Environment\n\n")
+	fmt.Fprintf(&sb, "- kubectl-mtv version: %s\n", r.Version)
+	fmt.Fprintf(&sb, "- Go version: %s\n", r.GoVersion)
+	fmt.Fprintf(&sb, "- Platform: %s\n", r.Platform)
+	fmt.Fprintf(&sb, "- Binary: %s\n", r.Binary)
+	fmt.Fprintf(&sb, "- Time: %s\n", r.Time.Format(time.RFC3339))
+	for _, name := range environmentVariables {
+		fmt.Fprintf(&sb, "- %s: %s\n", name, r.Environment[name])
+	}
+	sb.WriteString("\n")
+
+	sb.WriteString("## Additional Context\n\n")
+	sb.WriteString("<!-- Please add the output of `kubectl mtv version` (MTV operator and cluster versions)\n")
+	sb.WriteString("     and anything else that helps reproduce the problem. Review this report for\n")
+	sb.WriteString("     resource names or URLs you do not want to share before filing it. -->\n")
+
+	return sb.String()
+}
+
+// Write saves the report as a markdown file in dir and returns its path
+func Write(dir string, r *Report) (string, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", fmt.Errorf("failed to create bug report directory: %v", err)
+	}
+	name := fmt.Sprintf("kubectl-mtv-bug-report-%s.md", r.Time.Format("20060102-150405"))
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(r.Markdown()), 0600); err != nil {
+		return "", fmt.Errorf("failed to write bug report: %v", err)
+	}
+	return path, nil
+}
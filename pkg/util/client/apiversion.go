@@ -0,0 +1,214 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"sync"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+)
+
+// NegotiateVersion picks the Forklift API version to use from the versions a cluster serves:
+// Version while it is served, otherwise the version the cluster prefers, so operator upgrades
+// that bump the API version keep working. Returns Version when the group is not served at all.
+func NegotiateVersion(served []string, preferred string) string {
+	for _, v := range served {
+		if v == Version {
+			return Version
+		}
+	}
+	if preferred != "" {
+		return preferred
+	}
+	if len(served) > 0 {
+		return served[0]
+	}
+	return Version
+}
+
+// ForkliftVersion returns the Forklift API version negotiated with the cluster
+func ForkliftVersion(configFlags *genericclioptions.ConfigFlags) (string, error) {
+	c, err := GetDynamicClient(configFlags)
+	if err != nil {
+		return "", err
+	}
+	if vc, ok := c.(*versionedClient); ok {
+		return vc.forkliftVersion(), nil
+	}
+	return Version, nil
+}
+
+// versionedClient is a dynamic client that sends the requests for Forklift resources to the
+// API version the cluster serves. Commands keep using the v1beta1 GVRs; the version is
+// discovered on the first Forklift request and objects sent to the cluster are converted.
+type versionedClient struct {
+	dynamic.Interface
+
+	// discover returns the served versions of the Forklift group and the preferred one
+	discover func() ([]string, string, error)
+
+	mu      sync.Mutex
+	version string
+}
+
+// newVersionedClient wraps a dynamic client, discovering the Forklift versions with the REST config
+func newVersionedClient(c dynamic.Interface, config *rest.Config) *versionedClient {
+	return &versionedClient{
+		Interface: c,
+		discover: func() ([]string, string, error) {
+			dc, err := discovery.NewDiscoveryClientForConfig(config)
+			if err != nil {
+				return nil, "", err
+			}
+			return forkliftGroupVersions(dc)
+		},
+	}
+}
+
+// forkliftGroupVersions returns the served versions of the Forklift group and the preferred one
+func forkliftGroupVersions(dc discovery.DiscoveryInterface) ([]string, string, error) {
+	groups, err := dc.ServerGroups()
+	if err != nil {
+		return nil, "", err
+	}
+	for _, g := range groups.Groups {
+		if g.Name != Group {
+			continue
+		}
+		served := make([]string, 0, len(g.Versions))
+		for _, v := range g.Versions {
+			served = append(served, v.Version)
+		}
+		return served, g.PreferredVersion.Version, nil
+	}
+	return nil, "", nil
+}
+
+// forkliftVersion returns the negotiated version, discovering it on first use. A failed
+// discovery falls back to Version and is retried on the next request.
+func (c *versionedClient) forkliftVersion() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.version != "" {
+		return c.version
+	}
+
+	served, preferred, err := c.discover()
+	if err != nil {
+		klog.V(2).Infof("Failed to discover Forklift API versions, using %s: %v", Version, err)
+		return Version
+	}
+	c.version = NegotiateVersion(served, preferred)
+	if c.version != Version {
+		klog.V(1).Infof("Cluster does not serve %s/%s, using %s/%s", Group, Version, Group, c.version)
+	}
+	return c.version
+}
+
+// Resource returns the resource client, for the negotiated version when gvr is a Forklift resource.
+// Forklift resource clients record the breadcrumb of the command when it is set.
+func (c *versionedClient) Resource(gvr schema.GroupVersionResource) dynamic.NamespaceableResourceInterface {
+	if gvr.Group != Group || gvr.Version != Version {
+		return c.Interface.Resource(gvr)
+	}
+	return &breadcrumbResource{NamespaceableResourceInterface: c.versionedResource(gvr)}
+}
+
+// versionedResource returns the client of a Forklift resource for the negotiated version
+func (c *versionedClient) versionedResource(gvr schema.GroupVersionResource) dynamic.NamespaceableResourceInterface {
+	version := c.forkliftVersion()
+	if version == Version {
+		return c.Interface.Resource(gvr)
+	}
+	gvr.Version = version
+	resource := c.Interface.Resource(gvr)
+	return &versionedResource{
+		versionedNamespaceResource: versionedNamespaceResource{ResourceInterface: resource, version: version},
+		namespaceable:              resource,
+	}
+}
+
+// versionedResource converts the objects sent to a cluster-scoped or namespaced Forklift resource
+type versionedResource struct {
+	versionedNamespaceResource
+	namespaceable dynamic.NamespaceableResourceInterface
+}
+
+// Namespace returns the namespaced resource client, converting objects like its parent
+func (r *versionedResource) Namespace(namespace string) dynamic.ResourceInterface {
+	return &versionedNamespaceResource{ResourceInterface: r.namespaceable.Namespace(namespace), version: r.version}
+}
+
+// versionedNamespaceResource converts the objects it creates, updates and applies to version
+type versionedNamespaceResource struct {
+	dynamic.ResourceInterface
+	version string
+}
+
+func (r *versionedNamespaceResource) Create(ctx context.Context, obj *unstructured.Unstructured, options metav1.CreateOptions, subresources ...string) (*unstructured.Unstructured, error) {
+	return r.ResourceInterface.Create(ctx, ConvertObject(obj, r.version), options, subresources...)
+}
+
+func (r *versionedNamespaceResource) Update(ctx context.Context, obj *unstructured.Unstructured, options metav1.UpdateOptions, subresources ...string) (*unstructured.Unstructured, error) {
+	return r.ResourceInterface.Update(ctx, ConvertObject(obj, r.version), options, subresources...)
+}
+
+func (r *versionedNamespaceResource) UpdateStatus(ctx context.Context, obj *unstructured.Unstructured, options metav1.UpdateOptions) (*unstructured.Unstructured, error) {
+	return r.ResourceInterface.UpdateStatus(ctx, ConvertObject(obj, r.version), options)
+}
+
+func (r *versionedNamespaceResource) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, options metav1.PatchOptions, subresources ...string) (*unstructured.Unstructured, error) {
+	data = bytes.ReplaceAll(data, []byte(`"`+Group+"/"+Version+`"`), []byte(`"`+Group+"/"+r.version+`"`))
+	return r.ResourceInterface.Patch(ctx, name, pt, data, options, subresources...)
+}
+
+func (r *versionedNamespaceResource) Apply(ctx context.Context, name string, obj *unstructured.Unstructured, options metav1.ApplyOptions, subresources ...string) (*unstructured.Unstructured, error) {
+	return r.ResourceInterface.Apply(ctx, name, ConvertObject(obj, r.version), options, subresources...)
+}
+
+func (r *versionedNamespaceResource) ApplyStatus(ctx context.Context, name string, obj *unstructured.Unstructured, options metav1.ApplyOptions) (*unstructured.Unstructured, error) {
+	return r.ResourceInterface.ApplyStatus(ctx, name, ConvertObject(obj, r.version), options)
+}
+
+// ConvertObject returns a copy of a Forklift object for another API version: its apiVersion,
+// and the apiVersion of the Forklift objects it references (e.g. plan hooks), are rewritten.
+// The object is returned unchanged when version is Version.
+func ConvertObject(obj *unstructured.Unstructured, version string) *unstructured.Unstructured {
+	from := Group + "/" + Version
+	if obj == nil || version == Version {
+		return obj
+	}
+	converted := obj.DeepCopy()
+	converted.Object = convertValue(converted.Object, from, Group+"/"+version).(map[string]interface{})
+	return converted
+}
+
+// convertValue rewrites the apiVersion fields equal to from in a decoded JSON value
+func convertValue(value interface{}, from, to string) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if s, ok := item.(string); ok && key == "apiVersion" && s == from {
+				v[key] = to
+				continue
+			}
+			v[key] = convertValue(item, from, to)
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = convertValue(item, from, to)
+		}
+		return v
+	default:
+		return value
+	}
+}
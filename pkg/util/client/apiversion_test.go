@@ -0,0 +1,125 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+func TestNegotiateVersion(t *testing.T) {
+	tests := []struct {
+		name      string
+		served    []string
+		preferred string
+		want      string
+	}{
+		{"v1beta1 served", []string{"v1beta1"}, "v1beta1", "v1beta1"},
+		{"v1beta1 still served after upgrade", []string{"v1", "v1beta1"}, "v1", "v1beta1"},
+		{"v1beta1 dropped", []string{"v1"}, "v1", "v1"},
+		{"no preferred version", []string{"v1"}, "", "v1"},
+		{"group not served", nil, "", "v1beta1"},
+	}
+	for _, tt := range tests {
+		if got := NegotiateVersion(tt.served, tt.preferred); got != tt.want {
+			t.Errorf("%s: NegotiateVersion(%v, %q) = %q, want %q", tt.name, tt.served, tt.preferred, got, tt.want)
+		}
+	}
+}
+
+func TestConvertObject(t *testing.T) {
+	plan := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "forklift.konveyor.io/v1beta1",
+		"kind":       "Plan",
+		"spec": map[string]interface{}{
+			"vms": []interface{}{
+				map[string]interface{}{
+					"hooks": []interface{}{
+						map[string]interface{}{"hook": map[string]interface{}{"apiVersion": "forklift.konveyor.io/v1beta1", "kind": "Hook"}},
+					},
+				},
+			},
+			"network": map[string]interface{}{"apiVersion": "k8s.cni.cncf.io/v1"},
+		},
+	}}
+
+	converted := ConvertObject(plan, "v1")
+	if converted.GetAPIVersion() != "forklift.konveyor.io/v1" {
+		t.Errorf("apiVersion = %q, want forklift.konveyor.io/v1", converted.GetAPIVersion())
+	}
+	vms, _, _ := unstructured.NestedSlice(converted.Object, "spec", "vms")
+	hooks, _, _ := unstructured.NestedSlice(vms[0].(map[string]interface{}), "hooks")
+	if v, _, _ := unstructured.NestedString(hooks[0].(map[string]interface{}), "hook", "apiVersion"); v != "forklift.konveyor.io/v1" {
+		t.Errorf("hook apiVersion = %q, want forklift.konveyor.io/v1", v)
+	}
+	if v, _, _ := unstructured.NestedString(converted.Object, "spec", "network", "apiVersion"); v != "k8s.cni.cncf.io/v1" {
+		t.Errorf("non-Forklift apiVersion changed to %q", v)
+	}
+	if plan.GetAPIVersion() != "forklift.konveyor.io/v1beta1" {
+		t.Errorf("ConvertObject modified its argument")
+	}
+	if ConvertObject(plan, Version) != plan {
+		t.Errorf("ConvertObject to %s should return the object unchanged", Version)
+	}
+}
+
+func TestVersionedClient(t *testing.T) {
+	v1Plans := schema.GroupVersionResource{Group: Group, Version: "v1", Resource: "plans"}
+	fake := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		v1Plans: "PlanList",
+	})
+
+	discoveries := 0
+	c := &versionedClient{
+		Interface: fake,
+		discover: func() ([]string, string, error) {
+			discoveries++
+			if discoveries == 1 {
+				return nil, "", fmt.Errorf("connection refused")
+			}
+			return []string{"v1"}, "v1", nil
+		},
+	}
+
+	// A failed discovery falls back to v1beta1 and is retried
+	if v := c.forkliftVersion(); v != Version {
+		t.Fatalf("version after failed discovery = %q, want %q", v, Version)
+	}
+
+	plan := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "forklift.konveyor.io/v1beta1",
+		"kind":       "Plan",
+		"metadata":   map[string]interface{}{"name": "wave-1", "namespace": "demo"},
+	}}
+	ctx := context.Background()
+	if _, err := c.Resource(PlansGVR).Namespace("demo").Create(ctx, plan, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("Create() unexpected error: %v", err)
+	}
+
+	// The v1beta1 GVR reaches the v1 resource, and the object was stored as v1
+	stored, err := fake.Resource(v1Plans).Namespace("demo").Get(ctx, "wave-1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("plan not stored as v1: %v", err)
+	}
+	if stored.GetAPIVersion() != "forklift.konveyor.io/v1" {
+		t.Errorf("stored apiVersion = %q, want forklift.konveyor.io/v1", stored.GetAPIVersion())
+	}
+
+	patch := []byte(`{"metadata":{"labels":{"wave":"1"}}}`)
+	if _, err := c.Resource(PlansGVR).Namespace("demo").Patch(ctx, "wave-1", types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		t.Fatalf("Patch() unexpected error: %v", err)
+	}
+	list, err := c.Resource(PlansGVR).Namespace("demo").List(ctx, metav1.ListOptions{})
+	if err != nil || len(list.Items) != 1 || list.Items[0].GetLabels()["wave"] != "1" {
+		t.Errorf("List() = %v, %v, want the patched plan", list, err)
+	}
+	if discoveries != 2 {
+		t.Errorf("discoveries = %d, want 2 (the version is cached after a successful discovery)", discoveries)
+	}
+}
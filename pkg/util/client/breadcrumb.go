@@ -0,0 +1,186 @@
+package client
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/klog/v2"
+)
+
+// BreadcrumbAnnotation records the last kubectl-mtv command that changed a Forklift resource
+const BreadcrumbAnnotation = "kubectl-mtv/last-action"
+
+// Breadcrumb describes the kubectl-mtv command changing resources, so changes made with the
+// CLI can be told apart from changes made in the UI or by GitOps. Flag values are only
+// hashed, they may hold credentials.
+type Breadcrumb struct {
+	Command   string `json:"command"`
+	User      string `json:"user,omitempty"`
+	Version   string `json:"version"`
+	Flags     string `json:"flags,omitempty"`
+	FlagsHash string `json:"flagsHash,omitempty"`
+	Time      string `json:"time"`
+}
+
+var (
+	breadcrumbMu sync.RWMutex
+	breadcrumb   *Breadcrumb
+)
+
+// SetBreadcrumb enables recording b on the Forklift resources created, updated and patched
+// from now on; nil disables recording
+func SetBreadcrumb(b *Breadcrumb) {
+	breadcrumbMu.Lock()
+	defer breadcrumbMu.Unlock()
+	breadcrumb = b
+}
+
+// currentBreadcrumb returns the annotation value to record, empty when recording is disabled
+func currentBreadcrumb() string {
+	breadcrumbMu.RLock()
+	defer breadcrumbMu.RUnlock()
+	if breadcrumb == nil {
+		return ""
+	}
+	data, err := json.Marshal(breadcrumb)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// NewBreadcrumb returns the breadcrumb of a command. flags maps the names of the flags set on
+// the command line to their values; the names are listed and the values hashed.
+func NewBreadcrumb(command, user, version string, flags map[string]string) *Breadcrumb {
+	b := &Breadcrumb{
+		Command: command,
+		User:    user,
+		Version: version,
+		Time:    time.Now().UTC().Format(time.RFC3339),
+	}
+	if len(flags) == 0 {
+		return b
+	}
+
+	names := make([]string, 0, len(flags))
+	for name := range flags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	h := sha256.New()
+	for _, name := range names {
+		fmt.Fprintf(h, "%s=%s\x00", name, flags[name])
+	}
+	b.Flags = strings.Join(names, ",")
+	b.FlagsHash = hex.EncodeToString(h.Sum(nil))[:12]
+	return b
+}
+
+// KubeconfigUser returns the user of the current kubeconfig context, empty when unknown
+func KubeconfigUser(configFlags *genericclioptions.ConfigFlags) string {
+	if configFlags.AuthInfoName != nil && *configFlags.AuthInfoName != "" {
+		return *configFlags.AuthInfoName
+	}
+	raw, err := configFlags.ToRawKubeConfigLoader().RawConfig()
+	if err != nil {
+		return ""
+	}
+	contextName := raw.CurrentContext
+	if configFlags.Context != nil && *configFlags.Context != "" {
+		contextName = *configFlags.Context
+	}
+	if kubeContext, ok := raw.Contexts[contextName]; ok {
+		return kubeContext.AuthInfo
+	}
+	return ""
+}
+
+// breadcrumbResource records the breadcrumb on the objects it creates, updates and patches
+type breadcrumbResource struct {
+	dynamic.NamespaceableResourceInterface
+}
+
+// Namespace returns the namespaced resource client, recording breadcrumbs like its parent
+func (r *breadcrumbResource) Namespace(namespace string) dynamic.ResourceInterface {
+	return &breadcrumbNamespaceResource{ResourceInterface: r.NamespaceableResourceInterface.Namespace(namespace)}
+}
+
+func (r *breadcrumbResource) Create(ctx context.Context, obj *unstructured.Unstructured, options metav1.CreateOptions, subresources ...string) (*unstructured.Unstructured, error) {
+	return (&breadcrumbNamespaceResource{r.NamespaceableResourceInterface}).Create(ctx, obj, options, subresources...)
+}
+
+func (r *breadcrumbResource) Update(ctx context.Context, obj *unstructured.Unstructured, options metav1.UpdateOptions, subresources ...string) (*unstructured.Unstructured, error) {
+	return (&breadcrumbNamespaceResource{r.NamespaceableResourceInterface}).Update(ctx, obj, options, subresources...)
+}
+
+func (r *breadcrumbResource) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, options metav1.PatchOptions, subresources ...string) (*unstructured.Unstructured, error) {
+	return (&breadcrumbNamespaceResource{r.NamespaceableResourceInterface}).Patch(ctx, name, pt, data, options, subresources...)
+}
+
+// breadcrumbNamespaceResource records the breadcrumb on the objects it creates, updates and patches
+type breadcrumbNamespaceResource struct {
+	dynamic.ResourceInterface
+}
+
+func (r *breadcrumbNamespaceResource) Create(ctx context.Context, obj *unstructured.Unstructured, options metav1.CreateOptions, subresources ...string) (*unstructured.Unstructured, error) {
+	if len(subresources) == 0 {
+		obj = withBreadcrumb(obj)
+	}
+	return r.ResourceInterface.Create(ctx, obj, options, subresources...)
+}
+
+func (r *breadcrumbNamespaceResource) Update(ctx context.Context, obj *unstructured.Unstructured, options metav1.UpdateOptions, subresources ...string) (*unstructured.Unstructured, error) {
+	if len(subresources) == 0 {
+		obj = withBreadcrumb(obj)
+	}
+	return r.ResourceInterface.Update(ctx, obj, options, subresources...)
+}
+
+// Patch applies the patch, then records the breadcrumb with a second merge patch. Patches of
+// every type are recorded the same way; a failure to record is logged and not returned.
+func (r *breadcrumbNamespaceResource) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, options metav1.PatchOptions, subresources ...string) (*unstructured.Unstructured, error) {
+	patched, err := r.ResourceInterface.Patch(ctx, name, pt, data, options, subresources...)
+	value := currentBreadcrumb()
+	if err != nil || value == "" || len(subresources) > 0 {
+		return patched, err
+	}
+
+	annotation, _ := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{"annotations": map[string]string{BreadcrumbAnnotation: value}},
+	})
+	recorded, recordErr := r.ResourceInterface.Patch(ctx, name, types.MergePatchType, annotation, metav1.PatchOptions{DryRun: options.DryRun})
+	if recordErr != nil {
+		klog.V(1).Infof("Failed to record %s on %s: %v", BreadcrumbAnnotation, name, recordErr)
+		return patched, nil
+	}
+	return recorded, nil
+}
+
+// withBreadcrumb returns a copy of obj with the breadcrumb annotation, or obj when recording is disabled
+func withBreadcrumb(obj *unstructured.Unstructured) *unstructured.Unstructured {
+	value := currentBreadcrumb()
+	if obj == nil || value == "" {
+		return obj
+	}
+	recorded := obj.DeepCopy()
+	annotations := recorded.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[BreadcrumbAnnotation] = value
+	recorded.SetAnnotations(annotations)
+	return recorded
+}
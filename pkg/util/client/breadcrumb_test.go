@@ -0,0 +1,87 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+func TestNewBreadcrumb(t *testing.T) {
+	b := NewBreadcrumb("patch plan", "admin", "v0.7.0", map[string]string{"warm": "true", "name": "wave-1"})
+	if b.Flags != "name,warm" {
+		t.Errorf("Flags = %q, want name,warm", b.Flags)
+	}
+	if len(b.FlagsHash) != 12 {
+		t.Errorf("FlagsHash = %q, want 12 hex digits", b.FlagsHash)
+	}
+
+	other := NewBreadcrumb("patch plan", "admin", "v0.7.0", map[string]string{"warm": "false", "name": "wave-1"})
+	if other.FlagsHash == b.FlagsHash {
+		t.Errorf("different flag values have the same hash %q", b.FlagsHash)
+	}
+	if NewBreadcrumb("get plan", "", "v0.7.0", nil).FlagsHash != "" {
+		t.Errorf("command without flags has a flags hash")
+	}
+}
+
+func TestBreadcrumbRecording(t *testing.T) {
+	fake := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		PlansGVR: "PlanList",
+	})
+	c := &versionedClient{
+		Interface: fake,
+		discover:  func() ([]string, string, error) { return []string{Version}, Version, nil },
+	}
+	ctx := context.Background()
+	plans := c.Resource(PlansGVR).Namespace("demo")
+
+	plan := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "forklift.konveyor.io/v1beta1",
+		"kind":       "Plan",
+		"metadata":   map[string]interface{}{"name": "wave-1", "namespace": "demo"},
+	}}
+
+	// Nothing is recorded unless a breadcrumb is set
+	SetBreadcrumb(nil)
+	created, err := plans.Create(ctx, plan, metav1.CreateOptions{})
+	if err != nil {
+		t.Fatalf("Create() unexpected error: %v", err)
+	}
+	if _, ok := created.GetAnnotations()[BreadcrumbAnnotation]; ok {
+		t.Errorf("breadcrumb recorded while recording is disabled")
+	}
+
+	SetBreadcrumb(NewBreadcrumb("patch plan", "admin", "v0.7.0", map[string]string{"warm": "true"}))
+	defer SetBreadcrumb(nil)
+
+	patch := []byte(`{"spec":{"warm":true}}`)
+	patched, err := plans.Patch(ctx, "wave-1", types.MergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		t.Fatalf("Patch() unexpected error: %v", err)
+	}
+	var recorded Breadcrumb
+	if err := json.Unmarshal([]byte(patched.GetAnnotations()[BreadcrumbAnnotation]), &recorded); err != nil {
+		t.Fatalf("breadcrumb annotation not recorded: %v", err)
+	}
+	if recorded.Command != "patch plan" || recorded.User != "admin" || recorded.Flags != "warm" {
+		t.Errorf("recorded breadcrumb = %+v", recorded)
+	}
+	if warm, _, _ := unstructured.NestedBool(patched.Object, "spec", "warm"); !warm {
+		t.Errorf("patch not applied")
+	}
+
+	// The caller's object is not modified
+	if _, err := plans.Update(ctx, created, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("Update() unexpected error: %v", err)
+	}
+	if _, ok := created.GetAnnotations()[BreadcrumbAnnotation]; ok {
+		t.Errorf("Update() modified its argument")
+	}
+}
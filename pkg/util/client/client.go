@@ -96,6 +96,13 @@ var (
 		Resource: "configmaps",
 	}
 
+	// VirtualMachinesGVR is used to access KubeVirt virtual machines
+	VirtualMachinesGVR = schema.GroupVersionResource{
+		Group:    "kubevirt.io",
+		Version:  "v1",
+		Resource: "virtualmachines",
+	}
+
 	// RouteGVR is used to access routes in an Openshift cluster
 	RouteGVR = schema.GroupVersionResource{
 		Group:    "route.openshift.io",
@@ -104,14 +111,15 @@ var (
 	}
 )
 
-// GetDynamicClient returns a dynamic client for interacting with MTV CRDs
+// GetDynamicClient returns a dynamic client for interacting with MTV CRDs.
+// Clients are cached per cluster and credentials and are safe for concurrent use.
 func GetDynamicClient(configFlags *genericclioptions.ConfigFlags) (dynamic.Interface, error) {
 	config, err := configFlags.ToRESTConfig()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get REST config: %v", err)
 	}
 
-	client, err := dynamic.NewForConfig(config)
+	client, err := cachedDynamicClient(configFlags, config)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create dynamic client: %v", err)
 	}
@@ -119,14 +127,15 @@ func GetDynamicClient(configFlags *genericclioptions.ConfigFlags) (dynamic.Inter
 	return client, nil
 }
 
-// GetKubernetesClientset returns a kubernetes clientset for interacting with the Kubernetes API
+// GetKubernetesClientset returns a kubernetes clientset for interacting with the Kubernetes API.
+// Clientsets are cached per cluster and credentials and are safe for concurrent use.
 func GetKubernetesClientset(configFlags *genericclioptions.ConfigFlags) (*kubernetes.Clientset, error) {
 	config, err := configFlags.ToRESTConfig()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get REST config: %v", err)
 	}
 
-	clientset, err := kubernetes.NewForConfig(config)
+	clientset, err := cachedClientset(configFlags, config)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create kubernetes clientset: %v", err)
 	}
@@ -385,6 +394,25 @@ func NewHTTPClient(baseURL string, transport http.RoundTripper) *HTTPClient {
 // This method respects context cancellation and deadlines, making it suitable for long-running
 // requests or requests that need to be cancelled (e.g., on SIGINT).
 func (c *HTTPClient) GetWithContext(ctx context.Context, path string) ([]byte, error) {
+	body, err := c.OpenWithContext(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	// Read the response body
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %v", err)
+	}
+
+	return data, nil
+}
+
+// OpenWithContext performs a context-aware HTTP GET request like GetWithContext, but returns
+// the response body unread so large responses can be decoded as they arrive. The caller
+// must close the body.
+func (c *HTTPClient) OpenWithContext(ctx context.Context, path string) (io.ReadCloser, error) {
 	// Split the path into path part and query part
 	parts := strings.SplitN(path, "?", 2)
 	pathPart := parts[0]
@@ -422,10 +450,10 @@ func (c *HTTPClient) GetWithContext(ctx context.Context, path string) ([]byte, e
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute request: %v", err)
 	}
-	defer resp.Body.Close()
 
 	// Check for non-success status codes
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		defer resp.Body.Close()
 		errBody, _ := io.ReadAll(io.LimitReader(resp.Body, 201))
 		if len(errBody) > 0 {
 			preview := string(errBody)
@@ -437,11 +465,5 @@ func (c *HTTPClient) GetWithContext(ctx context.Context, path string) ([]byte, e
 		return nil, fmt.Errorf("HTTP request failed with status: %s", resp.Status)
 	}
 
-	// Read the response body
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %v", err)
-	}
-
-	return body, nil
+	return resp.Body, nil
 }
@@ -0,0 +1,421 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/yaml"
+)
+
+// DryRunMode selects what write commands do with the changes they make
+type DryRunMode string
+
+const (
+	// DryRunNone applies the changes
+	DryRunNone DryRunMode = "none"
+	// DryRunClient answers the write requests locally, nothing is sent to the cluster
+	DryRunClient DryRunMode = "client"
+	// DryRunServer submits the write requests with server-side dry run, so validation and
+	// admission webhooks run, but nothing is persisted
+	DryRunServer DryRunMode = "server"
+)
+
+var (
+	dryRunMu      sync.Mutex
+	dryRunMode    = DryRunNone
+	dryRunKeys    []string
+	dryRunObjects map[string]*unstructured.Unstructured
+	// dryRunPaths holds the resources changed by client dry runs by API path, so later
+	// requests of the same command build on the earlier changes
+	dryRunPaths map[string][]byte
+	// dryRunBefore holds the state of each changed resource before the first dry-run write,
+	// nil for resources the dry run creates, in the order of dryRunChangeKeys
+	dryRunBefore     map[string]*unstructured.Unstructured
+	dryRunChangeKeys []string
+	dryRunDeleted    map[string]bool
+	// dryRunDiff makes dry runs also read the resources they replace, for PrintDryRunDiff
+	dryRunDiff bool
+	// showDiffWriter receives the diff of each update applied to the cluster, see SetShowDiff
+	showDiffWriter io.Writer
+)
+
+// SetDryRunDiff sets whether dry runs read the current state of the resources they update
+// or delete, so the changes can be printed as a diff
+func SetDryRunDiff(enabled bool) {
+	dryRunMu.Lock()
+	defer dryRunMu.Unlock()
+	dryRunDiff = enabled
+}
+
+// dryRunDiffEnabled reports whether dry runs record the current state of changed resources
+func dryRunDiffEnabled() bool {
+	dryRunMu.Lock()
+	defer dryRunMu.Unlock()
+	return dryRunDiff
+}
+
+// SetDryRun sets the dry-run mode of the write requests sent from now on, and drops the
+// resources recorded so far
+func SetDryRun(mode DryRunMode) {
+	dryRunMu.Lock()
+	defer dryRunMu.Unlock()
+	dryRunMode = mode
+	dryRunKeys = nil
+	dryRunObjects = map[string]*unstructured.Unstructured{}
+	dryRunPaths = map[string][]byte{}
+	dryRunBefore = map[string]*unstructured.Unstructured{}
+	dryRunChangeKeys = nil
+	dryRunDeleted = map[string]bool{}
+}
+
+// GetDryRun returns the dry-run mode of the write requests
+func GetDryRun() DryRunMode {
+	dryRunMu.Lock()
+	defer dryRunMu.Unlock()
+	return dryRunMode
+}
+
+// DryRunEnabled reports whether write requests are dry runs
+func DryRunEnabled() bool {
+	return GetDryRun() != DryRunNone
+}
+
+// DryRunSuffix returns the note added to the messages of changes that were not persisted
+func DryRunSuffix() string {
+	switch GetDryRun() {
+	case DryRunClient:
+		return " (dry run)"
+	case DryRunServer:
+		return " (server dry run)"
+	default:
+		return ""
+	}
+}
+
+// DryRunObjects returns the resources the dry-run write requests would have created or changed,
+// the last version of each resource, in the order they were first written
+func DryRunObjects() []*unstructured.Unstructured {
+	dryRunMu.Lock()
+	defer dryRunMu.Unlock()
+	objs := make([]*unstructured.Unstructured, 0, len(dryRunKeys))
+	for _, key := range dryRunKeys {
+		objs = append(objs, dryRunObjects[key])
+	}
+	return objs
+}
+
+// PrintDryRunObjects prints the resources of DryRunObjects as YAML documents, or as a JSON
+// list when format is json
+func PrintDryRunObjects(w io.Writer, format string) error {
+	objs := DryRunObjects()
+	if len(objs) == 0 {
+		return nil
+	}
+
+	if strings.ToLower(format) == "json" {
+		items := make([]interface{}, 0, len(objs))
+		for _, obj := range objs {
+			items = append(items, obj.Object)
+		}
+		data, err := json.MarshalIndent(map[string]interface{}{"apiVersion": "v1", "kind": "List", "items": items}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode dry-run resources: %v", err)
+		}
+		_, err = fmt.Fprintln(w, string(data))
+		return err
+	}
+
+	for _, obj := range objs {
+		data, err := yaml.Marshal(obj.Object)
+		if err != nil {
+			return fmt.Errorf("failed to encode dry-run resources: %v", err)
+		}
+		if _, err := fmt.Fprintf(w, "---\n%s", data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseDryRunObject decodes a resource of a dry-run request and returns it with its key;
+// other bodies, such as Status responses, return nil
+func parseDryRunObject(data []byte) (*unstructured.Unstructured, string) {
+	obj := &unstructured.Unstructured{}
+	if err := obj.UnmarshalJSON(data); err != nil || obj.GetKind() == "" || obj.GetKind() == "Status" {
+		return nil, ""
+	}
+	// Drop the fields only the server manages, they are noise in the printed resource
+	unstructured.RemoveNestedField(obj.Object, "metadata", "managedFields")
+	return obj, strings.Join([]string{obj.GetAPIVersion(), obj.GetKind(), obj.GetNamespace(), obj.GetName()}, "/")
+}
+
+// recordDryRunObject keeps a resource returned by a dry-run write request. A resource
+// without a recorded previous state is one the dry run creates.
+func recordDryRunObject(data []byte) {
+	obj, key := parseDryRunObject(data)
+	if obj == nil {
+		return
+	}
+	dryRunMu.Lock()
+	defer dryRunMu.Unlock()
+	if dryRunObjects == nil {
+		dryRunObjects = map[string]*unstructured.Unstructured{}
+	}
+	if _, ok := dryRunObjects[key]; !ok {
+		dryRunKeys = append(dryRunKeys, key)
+	}
+	dryRunObjects[key] = obj
+	recordDryRunBeforeLocked(key, nil)
+}
+
+// recordDryRunBefore keeps the state of a resource before its first dry-run write; deleted
+// marks resources the dry run deletes
+func recordDryRunBefore(data []byte, deleted bool) {
+	obj, key := parseDryRunObject(data)
+	if obj == nil {
+		return
+	}
+	dryRunMu.Lock()
+	defer dryRunMu.Unlock()
+	recordDryRunBeforeLocked(key, obj)
+	if deleted {
+		if dryRunDeleted == nil {
+			dryRunDeleted = map[string]bool{}
+		}
+		dryRunDeleted[key] = true
+	}
+}
+
+// recordDryRunBeforeLocked records the previous state of a resource the first time it is
+// changed; the caller holds dryRunMu
+func recordDryRunBeforeLocked(key string, obj *unstructured.Unstructured) {
+	if dryRunBefore == nil {
+		dryRunBefore = map[string]*unstructured.Unstructured{}
+	}
+	if _, ok := dryRunBefore[key]; ok {
+		return
+	}
+	dryRunBefore[key] = obj
+	dryRunChangeKeys = append(dryRunChangeKeys, key)
+}
+
+// withDryRun returns a copy of config whose write requests follow the dry-run mode
+func withDryRun(config *rest.Config) *rest.Config {
+	config = rest.CopyConfig(config)
+	config.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &dryRunTransport{next: rt}
+	})
+	return config
+}
+
+// dryRunTransport applies the dry-run mode to the write requests of the cluster clients. In
+// server mode the requests are sent with dryRun=All; in client mode they are answered from
+// the current resource and the request body, without changing the cluster.
+type dryRunTransport struct {
+	next http.RoundTripper
+}
+
+func (t *dryRunTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	mode := GetDryRun()
+	if mode == DryRunNone && (req.Method == http.MethodPatch || req.Method == http.MethodPut) {
+		if w := showDiffOutput(); w != nil {
+			if err := t.showDiff(req, w); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if mode == DryRunNone || !isWriteMethod(req.Method) {
+		return t.next.RoundTrip(req)
+	}
+	if mode == DryRunServer {
+		return t.serverDryRun(req)
+	}
+	return t.clientDryRun(req)
+}
+
+// isWriteMethod reports whether requests of method change the cluster
+func isWriteMethod(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// serverDryRun sends req with dryRun=All and records the resource the server returned
+func (t *dryRunTransport) serverDryRun(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodPost && dryRunDiffEnabled() {
+		t.recordCurrent(req)
+	}
+
+	req = req.Clone(req.Context())
+	query := req.URL.Query()
+	query.Set("dryRun", "All")
+	req.URL.RawQuery = query.Encode()
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil || req.Method == http.MethodDelete || resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp, err
+	}
+	data, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+	recordDryRunObject(data)
+	return resp, nil
+}
+
+// clientDryRun answers req without changing the cluster: creates and updates return the sent
+// resource, patches return the current resource with the patch applied, and deletes return
+// the current resource
+func (t *dryRunTransport) clientDryRun(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	path := req.URL.Path
+	switch req.Method {
+	case http.MethodPost:
+		obj := &unstructured.Unstructured{}
+		if err := obj.UnmarshalJSON(body); err == nil && obj.GetName() != "" {
+			path = strings.TrimSuffix(path, "/") + "/" + obj.GetName()
+		}
+		rememberDryRunPath(path, body)
+		return dryRunResponse(req, http.StatusCreated, body), nil
+	case http.MethodPut:
+		if _, ok := dryRunPath(path); !ok && dryRunDiffEnabled() {
+			t.recordCurrent(req)
+		}
+		rememberDryRunPath(path, body)
+		return dryRunResponse(req, http.StatusOK, body), nil
+	}
+
+	// Patches and deletes start from the current resource; errors such as NotFound are
+	// returned as they are
+	current, ok := dryRunPath(path)
+	if !ok {
+		resp, err := t.getCurrent(req)
+		if err != nil || resp.StatusCode < 200 || resp.StatusCode > 299 {
+			return resp, err
+		}
+		current, err = io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		recordDryRunBefore(current, false)
+	}
+	if req.Method == http.MethodDelete {
+		recordDryRunBefore(current, true)
+		return dryRunResponse(req, http.StatusOK, current), nil
+	}
+
+	contentType, _, _ := strings.Cut(req.Header.Get("Content-Type"), ";")
+	patched, err := applyDryRunPatch(types.PatchType(strings.TrimSpace(contentType)), current, body)
+	if err != nil {
+		return nil, err
+	}
+	rememberDryRunPath(path, patched)
+	return dryRunResponse(req, http.StatusOK, patched), nil
+}
+
+// rememberDryRunPath records a resource changed by a client dry run at its API path
+func rememberDryRunPath(path string, data []byte) {
+	recordDryRunObject(data)
+	dryRunMu.Lock()
+	defer dryRunMu.Unlock()
+	if dryRunPaths == nil {
+		dryRunPaths = map[string][]byte{}
+	}
+	dryRunPaths[path] = data
+}
+
+// dryRunPath returns the resource a client dry run changed at an API path
+func dryRunPath(path string) ([]byte, bool) {
+	dryRunMu.Lock()
+	defer dryRunMu.Unlock()
+	data, ok := dryRunPaths[path]
+	return data, ok
+}
+
+// recordCurrent records the current state of the resource a write request targets, for
+// the diff of the dry run; resources that cannot be read are left out of it
+func (t *dryRunTransport) recordCurrent(req *http.Request) {
+	resp, err := t.getCurrent(req)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return
+	}
+	current, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return
+	}
+	recordDryRunBefore(current, req.Method == http.MethodDelete)
+}
+
+// getCurrent reads the resource a write request targets
+func (t *dryRunTransport) getCurrent(req *http.Request) (*http.Response, error) {
+	get := req.Clone(req.Context())
+	get.Method = http.MethodGet
+	get.Body = nil
+	get.GetBody = nil
+	get.ContentLength = 0
+	get.Header.Del("Content-Type")
+	query := get.URL.Query()
+	query.Del("fieldManager")
+	query.Del("force")
+	get.URL.RawQuery = query.Encode()
+	return t.next.RoundTrip(get)
+}
+
+// applyDryRunPatch applies a JSON or merge patch to a resource. Strategic merge patches are
+// applied as merge patches, which gives the same result for the fields of custom resources.
+func applyDryRunPatch(patchType types.PatchType, current, patch []byte) ([]byte, error) {
+	switch patchType {
+	case types.JSONPatchType:
+		p, err := jsonpatch.DecodePatch(patch)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode patch: %v", err)
+		}
+		return p.Apply(current)
+	case types.MergePatchType, types.StrategicMergePatchType:
+		return jsonpatch.MergePatch(current, patch)
+	default:
+		return nil, fmt.Errorf("client dry run does not support %s patches, use --dry-run=server", patchType)
+	}
+}
+
+// dryRunResponse returns a JSON response answering req
+func dryRunResponse(req *http.Request, status int, body []byte) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,270 @@
+package client
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
+)
+
+// diffContext is the number of unchanged lines shown around each change of a dry-run diff
+const diffContext = 3
+
+// DryRunChange is a resource changed by the dry-run write requests: Before is nil for
+// created resources and After is nil for deleted ones
+type DryRunChange struct {
+	Kind      string
+	Namespace string
+	Name      string
+	Before    *unstructured.Unstructured
+	After     *unstructured.Unstructured
+}
+
+// Operation tells whether the change creates, updates or deletes the resource
+func (c DryRunChange) Operation() string {
+	switch {
+	case c.Before == nil:
+		return "create"
+	case c.After == nil:
+		return "delete"
+	default:
+		return "update"
+	}
+}
+
+// DryRunChanges returns the resources the dry-run write requests would have changed, with
+// their state before the first change and after the last one, in the order they were first
+// written
+func DryRunChanges() []DryRunChange {
+	dryRunMu.Lock()
+	defer dryRunMu.Unlock()
+	changes := make([]DryRunChange, 0, len(dryRunChangeKeys))
+	for _, key := range dryRunChangeKeys {
+		change := DryRunChange{Before: dryRunBefore[key]}
+		if !dryRunDeleted[key] {
+			change.After = dryRunObjects[key]
+		}
+		obj := change.After
+		if obj == nil {
+			obj = change.Before
+		}
+		if obj == nil {
+			continue
+		}
+		change.Kind, change.Namespace, change.Name = obj.GetKind(), obj.GetNamespace(), obj.GetName()
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// PrintDryRunDiff prints the changes of DryRunChanges as a unified diff of the resources as
+// YAML: created resources as added lines, deleted ones as removed lines. Secret values are
+// replaced by a short hash, so changed values still show as changed.
+func PrintDryRunDiff(w io.Writer) error {
+	for _, change := range DryRunChanges() {
+		if err := printChangeDiff(w, change); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// printChangeDiff prints one change as a unified diff of the resource as YAML
+func printChangeDiff(w io.Writer, change DryRunChange) error {
+	before, err := diffLines(change.Before)
+	if err != nil {
+		return err
+	}
+	after, err := diffLines(change.After)
+	if err != nil {
+		return err
+	}
+
+	name := change.Name
+	if change.Namespace != "" {
+		name = change.Namespace + "/" + name
+	}
+	body := unifiedDiff(before, after)
+	if body == "" {
+		_, err := fmt.Fprintf(w, "=== %s %s unchanged\n", change.Kind, name)
+		return err
+	}
+	_, err = fmt.Fprintf(w, "--- %s %s (current)\n+++ %s %s (after %s)\n%s", change.Kind, name, change.Kind, name, change.Operation(), body)
+	return err
+}
+
+// SetShowDiff sets the writer receiving the diff of each update before it is sent to the
+// cluster, for the --show-diff flag of patch commands; nil stops printing diffs. Dry runs
+// print their diff with PrintDryRunDiff instead.
+func SetShowDiff(w io.Writer) {
+	dryRunMu.Lock()
+	defer dryRunMu.Unlock()
+	showDiffWriter = w
+}
+
+// showDiffOutput returns the writer set by SetShowDiff
+func showDiffOutput() io.Writer {
+	dryRunMu.Lock()
+	defer dryRunMu.Unlock()
+	return showDiffWriter
+}
+
+// showDiff prints the diff between the resource an update or patch request targets and the
+// resource the request would leave, before the request is sent. Requests whose resource
+// cannot be read are sent without a diff, so the cluster reports their errors.
+func (t *dryRunTransport) showDiff(req *http.Request, w io.Writer) error {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		req.GetBody = func() (io.ReadCloser, error) { return io.NopCloser(bytes.NewReader(body)), nil }
+	}
+
+	resp, err := t.getCurrent(req)
+	if err != nil {
+		return nil
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil
+	}
+	current, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil
+	}
+
+	desired := body
+	if req.Method == http.MethodPatch {
+		contentType, _, _ := strings.Cut(req.Header.Get("Content-Type"), ";")
+		desired, err = applyDryRunPatch(types.PatchType(strings.TrimSpace(contentType)), current, body)
+		if err != nil {
+			klog.V(2).Infof("Not showing the diff of %s %s: %v", req.Method, req.URL.Path, err)
+			return nil
+		}
+	}
+
+	before, _ := parseDryRunObject(current)
+	after, _ := parseDryRunObject(desired)
+	if before == nil || after == nil {
+		return nil
+	}
+	return printChangeDiff(w, DryRunChange{Kind: after.GetKind(), Namespace: after.GetNamespace(), Name: after.GetName(), Before: before, After: after})
+}
+
+// diffLines returns the YAML lines of a resource compared by the diff, without the fields
+// that change on every write
+func diffLines(obj *unstructured.Unstructured) ([]string, error) {
+	if obj == nil {
+		return nil, nil
+	}
+	obj = obj.DeepCopy()
+	for _, field := range []string{"resourceVersion", "generation", "uid", "creationTimestamp", "managedFields"} {
+		unstructured.RemoveNestedField(obj.Object, "metadata", field)
+	}
+	if obj.GetKind() == "Secret" {
+		redactSecret(obj)
+	}
+	data, err := yaml.Marshal(obj.Object)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode dry-run resources: %v", err)
+	}
+	return strings.Split(strings.TrimSuffix(string(data), "\n"), "\n"), nil
+}
+
+// redactSecret replaces the values of a Secret by the first bytes of their SHA-256 hash
+func redactSecret(obj *unstructured.Unstructured) {
+	for _, field := range []string{"data", "stringData"} {
+		values, found, _ := unstructured.NestedMap(obj.Object, field)
+		if !found {
+			continue
+		}
+		for k, v := range values {
+			sum := sha256.Sum256([]byte(fmt.Sprint(v)))
+			values[k] = fmt.Sprintf("<redacted sha256:%x>", sum[:4])
+		}
+		_ = unstructured.SetNestedMap(obj.Object, values, field)
+	}
+}
+
+// unifiedDiff returns the changed lines between a and b with diffContext lines of context,
+// hunks separated by @@ lines; it is empty when the lines are the same
+func unifiedDiff(a, b []string) string {
+	// Longest common subsequence table, lcs[i][j] for a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	type line struct {
+		op   byte
+		text string
+	}
+	var lines []line
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			lines = append(lines, line{' ', a[i]})
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			lines = append(lines, line{'-', a[i]})
+			i++
+		default:
+			lines = append(lines, line{'+', b[j]})
+			j++
+		}
+	}
+
+	// Keep the changed lines and their context
+	show := make([]bool, len(lines))
+	changed := false
+	for k, l := range lines {
+		if l.op == ' ' {
+			continue
+		}
+		changed = true
+		for c := max(0, k-diffContext); c <= min(len(lines)-1, k+diffContext); c++ {
+			show[c] = true
+		}
+	}
+	if !changed {
+		return ""
+	}
+
+	var sb strings.Builder
+	for k, l := range lines {
+		if !show[k] {
+			continue
+		}
+		if k > 0 && !show[k-1] {
+			sb.WriteString("@@\n")
+		}
+		sb.WriteByte(l.op)
+		sb.WriteByte(' ')
+		sb.WriteString(l.text)
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
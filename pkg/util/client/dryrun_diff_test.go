@@ -0,0 +1,80 @@
+package client
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestPrintDryRunDiff(t *testing.T) {
+	SetDryRun(DryRunClient)
+	SetDryRunDiff(true)
+	defer SetDryRun(DryRunNone)
+	defer SetDryRunDiff(false)
+	rt := &dryRunTransport{next: &fakeAPI{}}
+
+	roundTripBody(t, rt, newTestRequest(t, http.MethodPatch, planURL, "application/merge-patch+json", `{"spec":{"warm":true}}`))
+	roundTripBody(t, rt, newTestRequest(t, http.MethodPost, "https://cluster/api/v1/namespaces/demo/secrets", "application/json",
+		`{"apiVersion":"v1","kind":"Secret","metadata":{"name":"creds","namespace":"demo"},"data":{"password":"czNjcmV0"}}`))
+
+	var out bytes.Buffer
+	if err := PrintDryRunDiff(&out); err != nil {
+		t.Fatal(err)
+	}
+	got := out.String()
+
+	for _, want := range []string{
+		"--- Plan demo/p1 (current)\n+++ Plan demo/p1 (after update)\n",
+		"  spec:\n-   warm: false\n+   warm: true\n",
+		"+++ Secret demo/creds (after create)\n",
+		"+   password: <redacted sha256:",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("PrintDryRunDiff() = \n%s\nwant it to contain %q", got, want)
+		}
+	}
+	if strings.Contains(got, "czNjcmV0") {
+		t.Errorf("PrintDryRunDiff() shows the secret value:\n%s", got)
+	}
+}
+
+func TestUnifiedDiffContext(t *testing.T) {
+	a := []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j"}
+	b := []string{"a", "B", "c", "d", "e", "f", "g", "h", "i", "J"}
+	want := "  a\n- b\n+ B\n  c\n  d\n  e\n@@\n  g\n  h\n  i\n- j\n+ J\n"
+	if got := unifiedDiff(a, b); got != want {
+		t.Errorf("unifiedDiff() = %q, want %q", got, want)
+	}
+	if got := unifiedDiff(a, a); got != "" {
+		t.Errorf("unifiedDiff() of equal lines = %q, want empty", got)
+	}
+}
+
+func TestShowDiff(t *testing.T) {
+	var out bytes.Buffer
+	SetDryRun(DryRunNone)
+	SetShowDiff(&out)
+	defer SetShowDiff(nil)
+	api := &fakeAPI{}
+	rt := &dryRunTransport{next: api}
+
+	roundTripBody(t, rt, newTestRequest(t, http.MethodPatch, planURL, "application/merge-patch+json", `{"spec":{"warm":true}}`))
+
+	want := "--- Plan demo/p1 (current)\n+++ Plan demo/p1 (after update)\n"
+	if got := out.String(); !strings.Contains(got, want) || !strings.Contains(got, "-   warm: false\n+   warm: true\n") {
+		t.Errorf("diff = \n%s\nwant the warm change of %q", got, want)
+	}
+	if len(api.requests) != 2 || !strings.HasPrefix(api.requests[0], "GET ") || !strings.HasPrefix(api.requests[1], "PATCH ") {
+		t.Errorf("requests = %v, want the plan read before the patch is sent", api.requests)
+	}
+	if len(DryRunChanges()) != 0 {
+		t.Errorf("DryRunChanges() = %v, want none for applied changes", DryRunChanges())
+	}
+
+	out.Reset()
+	roundTripBody(t, rt, newTestRequest(t, http.MethodPatch, planURL, "application/merge-patch+json", `{"spec":{"warm":false}}`))
+	if got := out.String(); got != "=== Plan demo/p1 unchanged\n" {
+		t.Errorf("diff of a no-op patch = %q", got)
+	}
+}
@@ -0,0 +1,141 @@
+package client
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// fakeAPI answers requests with a stored plan and remembers the requests it received
+type fakeAPI struct {
+	requests []string
+}
+
+const fakePlan = `{"apiVersion":"forklift.konveyor.io/v1beta1","kind":"Plan","metadata":{"name":"p1","namespace":"demo"},"spec":{"warm":false}}`
+
+func (f *fakeAPI) RoundTrip(req *http.Request) (*http.Response, error) {
+	f.requests = append(f.requests, req.Method+" "+req.URL.String())
+	body := fakePlan
+	if req.Body != nil {
+		data, _ := io.ReadAll(req.Body)
+		if req.Method == http.MethodPost || req.Method == http.MethodPut {
+			body = string(data)
+		}
+	}
+	return dryRunResponse(req, http.StatusOK, []byte(body)), nil
+}
+
+func newTestRequest(t *testing.T, method, url, contentType, body string) *http.Request {
+	t.Helper()
+	req, err := http.NewRequest(method, url, bytes.NewReader([]byte(body)))
+	if err != nil {
+		t.Fatalf("failed to build request: %v", err)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	return req
+}
+
+func roundTripBody(t *testing.T, rt http.RoundTripper, req *http.Request) string {
+	t.Helper()
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip() unexpected error: %v", err)
+	}
+	data, _ := io.ReadAll(resp.Body)
+	return string(data)
+}
+
+const planURL = "https://cluster/apis/forklift.konveyor.io/v1beta1/namespaces/demo/plans/p1"
+
+func TestDryRunTransport_None(t *testing.T) {
+	SetDryRun(DryRunNone)
+	api := &fakeAPI{}
+	rt := &dryRunTransport{next: api}
+
+	roundTripBody(t, rt, newTestRequest(t, http.MethodPatch, planURL, "application/merge-patch+json", `{"spec":{"warm":true}}`))
+	if len(api.requests) != 1 || strings.Contains(api.requests[0], "dryRun") {
+		t.Errorf("requests = %v, want the patch sent as is", api.requests)
+	}
+	if len(DryRunObjects()) != 0 {
+		t.Errorf("DryRunObjects() = %v, want none", DryRunObjects())
+	}
+}
+
+func TestDryRunTransport_Server(t *testing.T) {
+	SetDryRun(DryRunServer)
+	defer SetDryRun(DryRunNone)
+	api := &fakeAPI{}
+	rt := &dryRunTransport{next: api}
+
+	roundTripBody(t, rt, newTestRequest(t, http.MethodPatch, planURL, "application/merge-patch+json", `{"spec":{"warm":true}}`))
+	roundTripBody(t, rt, newTestRequest(t, http.MethodGet, planURL, "", ""))
+	roundTripBody(t, rt, newTestRequest(t, http.MethodDelete, planURL, "", ""))
+
+	want := []string{"PATCH " + planURL + "?dryRun=All", "GET " + planURL, "DELETE " + planURL + "?dryRun=All"}
+	if strings.Join(api.requests, "\n") != strings.Join(want, "\n") {
+		t.Errorf("requests = %v, want %v", api.requests, want)
+	}
+
+	objs := DryRunObjects()
+	if len(objs) != 1 || objs[0].GetKind() != "Plan" || objs[0].GetName() != "p1" {
+		t.Fatalf("DryRunObjects() = %v, want the plan returned by the server", objs)
+	}
+}
+
+func TestDryRunTransport_Client(t *testing.T) {
+	SetDryRun(DryRunClient)
+	defer SetDryRun(DryRunNone)
+	api := &fakeAPI{}
+	rt := &dryRunTransport{next: api}
+
+	// A merge patch is applied to the current plan, a JSON patch builds on it
+	roundTripBody(t, rt, newTestRequest(t, http.MethodPatch, planURL, "application/merge-patch+json", `{"spec":{"warm":true}}`))
+	body := roundTripBody(t, rt, newTestRequest(t, http.MethodPatch, planURL, "application/json-patch+json",
+		`[{"op":"add","path":"/spec/targetNamespace","value":"target"}]`))
+	if !strings.Contains(body, `"warm":true`) || !strings.Contains(body, `"targetNamespace":"target"`) {
+		t.Errorf("patched plan = %s, want both patches applied", body)
+	}
+
+	// Creates and deletes are answered without reaching the cluster
+	created := `{"apiVersion":"v1","kind":"Secret","metadata":{"name":"s1","namespace":"demo"}}`
+	if body := roundTripBody(t, rt, newTestRequest(t, http.MethodPost, "https://cluster/api/v1/namespaces/demo/secrets", "application/json", created)); body != created {
+		t.Errorf("created = %s, want the sent secret", body)
+	}
+	roundTripBody(t, rt, newTestRequest(t, http.MethodDelete, planURL, "", ""))
+
+	for _, r := range api.requests {
+		if !strings.HasPrefix(r, "GET ") {
+			t.Errorf("request %q reached the cluster, want only reads", r)
+		}
+	}
+	if len(api.requests) != 1 {
+		t.Errorf("requests = %v, want a single read of the plan", api.requests)
+	}
+
+	objs := DryRunObjects()
+	if len(objs) != 2 || objs[0].GetKind() != "Plan" || objs[1].GetKind() != "Secret" {
+		t.Fatalf("DryRunObjects() = %v, want the plan and the secret", objs)
+	}
+
+	var out bytes.Buffer
+	if err := PrintDryRunObjects(&out, "yaml"); err != nil {
+		t.Fatalf("PrintDryRunObjects() unexpected error: %v", err)
+	}
+	if strings.Count(out.String(), "---\n") != 2 || !strings.Contains(out.String(), "targetNamespace: target") {
+		t.Errorf("printed = %s, want two YAML documents with the patched plan", out.String())
+	}
+}
+
+func TestDryRunSuffix(t *testing.T) {
+	defer SetDryRun(DryRunNone)
+	for mode, want := range map[DryRunMode]string{DryRunNone: "", DryRunClient: " (dry run)", DryRunServer: " (server dry run)"} {
+		SetDryRun(mode)
+		if got := DryRunSuffix(); got != want {
+			t.Errorf("DryRunSuffix() in %s mode = %q, want %q", mode, got, want)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package client
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+)
+
+// clientFactory caches the clients built for a REST config, so commands that get a client
+// in several places, and concurrent callers, share one client per cluster and credentials.
+// The cache is dropped when a kubeconfig file changes.
+type clientFactory struct {
+	sync.Mutex
+	kubeconfigs string
+	dynamic     map[string]dynamic.Interface
+	clientsets  map[string]*kubernetes.Clientset
+}
+
+var clients = &clientFactory{
+	dynamic:    map[string]dynamic.Interface{},
+	clientsets: map[string]*kubernetes.Clientset{},
+}
+
+// InvalidateClientCache drops all cached clients, e.g. after credentials were rotated.
+func InvalidateClientCache() {
+	clients.Lock()
+	defer clients.Unlock()
+	clients.reset()
+}
+
+func (f *clientFactory) reset() {
+	f.dynamic = map[string]dynamic.Interface{}
+	f.clientsets = map[string]*kubernetes.Clientset{}
+}
+
+// checkKubeconfigs drops the cache when the kubeconfig files changed since the last call.
+// Must be called with the lock held.
+func (f *clientFactory) checkKubeconfigs(configFlags *genericclioptions.ConfigFlags) {
+	fingerprint := kubeconfigFingerprint(configFlags)
+	if fingerprint != f.kubeconfigs {
+		if f.kubeconfigs != "" {
+			klog.V(4).Infof("Kubeconfig changed, dropping cached clients")
+		}
+		f.reset()
+		f.kubeconfigs = fingerprint
+	}
+}
+
+// cachedDynamicClient returns the cached dynamic client of the REST config, creating it when missing
+func cachedDynamicClient(configFlags *genericclioptions.ConfigFlags, config *rest.Config) (dynamic.Interface, error) {
+	clients.Lock()
+	defer clients.Unlock()
+	clients.checkKubeconfigs(configFlags)
+
+	key := restConfigKey(config)
+	if c, ok := clients.dynamic[key]; ok {
+		return c, nil
+	}
+	config = withDryRun(config)
+	dc, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	c := newVersionedClient(dc, config)
+	clients.dynamic[key] = c
+	return c, nil
+}
+
+// cachedClientset returns the cached clientset of the REST config, creating it when missing
+func cachedClientset(configFlags *genericclioptions.ConfigFlags, config *rest.Config) (*kubernetes.Clientset, error) {
+	clients.Lock()
+	defer clients.Unlock()
+	clients.checkKubeconfigs(configFlags)
+
+	key := restConfigKey(config)
+	if c, ok := clients.clientsets[key]; ok {
+		return c, nil
+	}
+	c, err := kubernetes.NewForConfig(withDryRun(config))
+	if err != nil {
+		return nil, err
+	}
+	clients.clientsets[key] = c
+	return c, nil
+}
+
+// restConfigKey identifies the cluster and credentials of a REST config. Secrets are hashed,
+// never kept in the key as plain text.
+func restConfigKey(config *rest.Config) string {
+	h := sha256.New()
+	write := func(values ...interface{}) {
+		for _, v := range values {
+			fmt.Fprintf(h, "%v\x00", v)
+		}
+	}
+	write(config.Host, config.APIPath, config.BearerToken, config.BearerTokenFile,
+		config.Username, config.Password,
+		config.Impersonate.UserName, config.Impersonate.UID, strings.Join(config.Impersonate.Groups, ","),
+		config.Insecure, config.ServerName, config.CertFile, config.KeyFile, config.CAFile,
+		string(config.CertData), string(config.KeyData), string(config.CAData),
+		config.QPS, config.Burst, config.Timeout, config.UserAgent)
+	if config.ExecProvider != nil {
+		write(config.ExecProvider.Command, strings.Join(config.ExecProvider.Args, " "), config.ExecProvider.Env)
+	}
+	if config.AuthProvider != nil {
+		write(config.AuthProvider.Name, config.AuthProvider.Config)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// kubeconfigFingerprint returns the paths, sizes and modification times of the kubeconfig files
+func kubeconfigFingerprint(configFlags *genericclioptions.ConfigFlags) string {
+	if configFlags == nil {
+		return "-"
+	}
+	var b strings.Builder
+	for _, path := range configFlags.ToRawKubeConfigLoader().ConfigAccess().GetLoadingPrecedence() {
+		b.WriteString(path)
+		if info, err := os.Stat(path); err == nil {
+			fmt.Fprintf(&b, ":%d:%d", info.Size(), info.ModTime().UnixNano())
+		}
+		b.WriteString(";")
+	}
+	return b.String() + "-"
+}
@@ -0,0 +1,114 @@
+package client
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/dynamic"
+)
+
+const testKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: test
+  cluster:
+    server: https://%s:6443
+contexts:
+- name: test
+  context:
+    cluster: test
+    user: test
+current-context: test
+users:
+- name: test
+  user:
+    token: abc
+`
+
+func writeKubeconfig(t *testing.T, path, host string) {
+	t.Helper()
+	content := []byte(fmt.Sprintf(testKubeconfig, host))
+	if err := os.WriteFile(path, content, 0600); err != nil {
+		t.Fatalf("failed to write kubeconfig: %v", err)
+	}
+}
+
+func testConfigFlags(path string) *genericclioptions.ConfigFlags {
+	flags := genericclioptions.NewConfigFlags(false)
+	flags.KubeConfig = &path
+	return flags
+}
+
+func TestGetDynamicClient_Cached(t *testing.T) {
+	InvalidateClientCache()
+	path := filepath.Join(t.TempDir(), "kubeconfig")
+	writeKubeconfig(t, path, "cluster-a")
+	flags := testConfigFlags(path)
+
+	first, err := GetDynamicClient(flags)
+	if err != nil {
+		t.Fatalf("GetDynamicClient() unexpected error: %v", err)
+	}
+
+	// Concurrent callers share the cached client
+	var wg sync.WaitGroup
+	results := make([]dynamic.Interface, 8)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], _ = GetDynamicClient(testConfigFlags(path))
+		}(i)
+	}
+	wg.Wait()
+	for i, c := range results {
+		if c != first {
+			t.Errorf("caller %d got a new client, want the cached one", i)
+		}
+	}
+
+	// Other credentials get their own client
+	token := "other-token"
+	otherFlags := testConfigFlags(path)
+	otherFlags.BearerToken = &token
+	if other, _ := GetDynamicClient(otherFlags); other == first {
+		t.Error("a different token should not share the cached client")
+	}
+
+	clientset, err := GetKubernetesClientset(flags)
+	if err != nil {
+		t.Fatalf("GetKubernetesClientset() unexpected error: %v", err)
+	}
+	if again, _ := GetKubernetesClientset(flags); again != clientset {
+		t.Error("GetKubernetesClientset() should return the cached clientset")
+	}
+}
+
+func TestGetDynamicClient_KubeconfigChange(t *testing.T) {
+	InvalidateClientCache()
+	path := filepath.Join(t.TempDir(), "kubeconfig")
+	writeKubeconfig(t, path, "cluster-a")
+
+	first, err := GetDynamicClient(testConfigFlags(path))
+	if err != nil {
+		t.Fatalf("GetDynamicClient() unexpected error: %v", err)
+	}
+
+	writeKubeconfig(t, path, "cluster-b.example")
+	second, err := GetDynamicClient(testConfigFlags(path))
+	if err != nil {
+		t.Fatalf("GetDynamicClient() unexpected error: %v", err)
+	}
+	if second == first {
+		t.Error("a changed kubeconfig should drop the cached client")
+	}
+
+	InvalidateClientCache()
+	if third, _ := GetDynamicClient(testConfigFlags(path)); third == second {
+		t.Error("InvalidateClientCache() should drop the cached client")
+	}
+}
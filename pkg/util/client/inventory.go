@@ -3,7 +3,9 @@ package client
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/url"
 	"strings"
 
@@ -73,33 +75,110 @@ func FetchProviderInventoryWithInsecure(ctx context.Context, configFlags *generi
 		return nil, fmt.Errorf("failed to create authenticated HTTP client: %v", err)
 	}
 
+	path, err := providerInventoryPath(provider, subPath)
+	if err != nil {
+		return nil, err
+	}
+
+	klog.V(4).Infof("Fetching provider inventory from path: %s (insecure=%v)", path, insecureSkipTLS)
+
+	// Fetch the provider inventory
+	responseBytes, err := httpClient.GetWithContext(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseJSONResponse(responseBytes)
+}
+
+// providerInventoryPath returns the inventory path of a provider, /providers/<spec.type>/<metadata.uid>,
+// followed by subPath if any
+func providerInventoryPath(provider *unstructured.Unstructured, subPath string) (string, error) {
 	providerType, found, err := unstructured.NestedString(provider.Object, "spec", "type")
 	if err != nil || !found {
-		return nil, fmt.Errorf("provider type not found or error retrieving it: %v", err)
+		return "", fmt.Errorf("provider type not found or error retrieving it: %v", err)
 	}
 
 	providerUID, found, err := unstructured.NestedString(provider.Object, "metadata", "uid")
 	if err != nil || !found {
-		return nil, fmt.Errorf("provider UID not found or error retrieving it: %v", err)
+		return "", fmt.Errorf("provider UID not found or error retrieving it: %v", err)
 	}
 
-	// Construct the path for provider inventory: /providers/<spec.type>/<metadata.uid>
 	path := fmt.Sprintf("/providers/%s/%s", url.PathEscape(providerType), url.PathEscape(providerUID))
-
-	// Add subPath if provided
 	if subPath != "" {
 		path = fmt.Sprintf("%s/%s", path, strings.TrimPrefix(subPath, "/"))
 	}
+	return path, nil
+}
 
-	klog.V(4).Infof("Fetching provider inventory from path: %s (insecure=%v)", path, insecureSkipTLS)
+// ErrStopStream is returned by the function of StreamProviderInventoryWithInsecure to stop
+// reading the response early; the stream then ends without an error
+var ErrStopStream = errors.New("stop streaming")
 
-	// Fetch the provider inventory
-	responseBytes, err := httpClient.GetWithContext(ctx, path)
+// StreamProviderInventoryWithInsecure fetches a collection of the inventory of a provider and
+// calls fn with each object as it is decoded, so the response is never held in memory as a
+// whole. The response must be a JSON array; empty and null responses have no objects.
+func StreamProviderInventoryWithInsecure(ctx context.Context, configFlags *genericclioptions.ConfigFlags, baseURL string, provider *unstructured.Unstructured, subPath string, insecureSkipTLS bool, fn func(map[string]interface{}) error) error {
+	if provider == nil {
+		return fmt.Errorf("provider is nil")
+	}
+
+	httpClient, err := GetAuthenticatedHTTPClientWithInsecure(ctx, configFlags, baseURL, insecureSkipTLS)
 	if err != nil {
-		return nil, err
+		return fmt.Errorf("failed to create authenticated HTTP client: %v", err)
 	}
 
-	return parseJSONResponse(responseBytes)
+	path, err := providerInventoryPath(provider, subPath)
+	if err != nil {
+		return err
+	}
+
+	klog.V(4).Infof("Streaming provider inventory from path: %s (insecure=%v)", path, insecureSkipTLS)
+
+	body, err := httpClient.OpenWithContext(ctx, path)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	return decodeJSONArray(body, fn)
+}
+
+// decodeJSONArray calls fn with each object of the JSON array read from r, one at a time.
+// Elements that are not objects are skipped.
+func decodeJSONArray(r io.Reader, fn func(map[string]interface{}) error) error {
+	dec := json.NewDecoder(r)
+	tok, err := dec.Token()
+	if err == io.EOF || err == nil && tok == nil {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to parse inventory response as JSON: %v", err)
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '[' {
+		return fmt.Errorf("failed to stream inventory response: expected a JSON array, got %v", tok)
+	}
+
+	for dec.More() {
+		var item interface{}
+		if err := dec.Decode(&item); err != nil {
+			return fmt.Errorf("failed to parse inventory response as JSON: %v", err)
+		}
+		obj, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if err := fn(obj); err != nil {
+			if errors.Is(err, ErrStopStream) {
+				return nil
+			}
+			return err
+		}
+	}
+	if _, err := dec.Token(); err != nil {
+		return fmt.Errorf("failed to parse inventory response as JSON: %v", err)
+	}
+	return nil
 }
 
 // FetchSpecificProviderWithDetailAndInsecure fetches inventory for a specific provider by name with specified detail level
@@ -107,11 +186,8 @@ func FetchProviderInventoryWithInsecure(ctx context.Context, configFlags *generi
 // This function uses direct URL access: /providers/<type>/<uid>?detail=N
 func FetchSpecificProviderWithDetailAndInsecure(ctx context.Context, configFlags *genericclioptions.ConfigFlags, baseURL string, providerName string, detail int, insecureSkipTLS bool) (interface{}, error) {
 	// We need to determine the namespace to look for the provider CRD
-	// Try to get it from configFlags or use empty string for all namespaces
-	namespace := ""
-	if configFlags.Namespace != nil && *configFlags.Namespace != "" {
-		namespace = *configFlags.Namespace
-	}
+	// Use the namespace chosen for MTV resources or empty string for all namespaces
+	namespace, _ := MTVNamespace(configFlags)
 
 	// First get the provider CRD by name to extract type and UID
 	c, err := GetDynamicClient(configFlags)
@@ -0,0 +1,49 @@
+package client
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDecodeJSONArray(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		stopAt  int
+		want    []string
+		wantErr string
+	}{
+		{name: "array", body: `[{"name":"a"}, 7, {"name":"b"}]`, want: []string{"a", "b"}},
+		{name: "empty response", body: ``},
+		{name: "null", body: `null`},
+		{name: "empty array", body: `[]`},
+		{name: "stopped early", body: `[{"name":"a"},{"name":"b"},{"name":"c"}]`, stopAt: 2, want: []string{"a", "b"}},
+		{name: "object", body: `{"name":"a"}`, wantErr: "expected a JSON array"},
+		{name: "truncated", body: `[{"name":"a"},{"na`, want: []string{"a"}, wantErr: "failed to parse"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var names []string
+			err := decodeJSONArray(strings.NewReader(tt.body), func(item map[string]interface{}) error {
+				names = append(names, item["name"].(string))
+				if len(names) == tt.stopAt {
+					return ErrStopStream
+				}
+				return nil
+			})
+			if tt.wantErr == "" && err != nil || tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("decodeJSONArray() error = %v, want %q", err, tt.wantErr)
+			}
+			if strings.Join(names, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("decoded %v, want %v", names, tt.want)
+			}
+		})
+	}
+
+	failure := errors.New("write failed")
+	err := decodeJSONArray(strings.NewReader(`[{"name":"a"}]`), func(map[string]interface{}) error { return failure })
+	if !errors.Is(err, failure) {
+		t.Errorf("decodeJSONArray() error = %v, want the callback error", err)
+	}
+}
@@ -0,0 +1,128 @@
+package client
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/klog/v2"
+)
+
+// listWorkers bounds the concurrent per-namespace lists of ListAllNamespaces
+var listWorkers = 8
+
+var (
+	// namespacesGVR is used to find the namespaces to list one by one
+	namespacesGVR = schema.GroupVersionResource{Group: "", Version: "v1", Resource: "namespaces"}
+
+	// projectsGVR lists the namespaces a user can access on OpenShift, even without
+	// permission to list namespaces
+	projectsGVR = schema.GroupVersionResource{Group: "project.openshift.io", Version: "v1", Resource: "projects"}
+)
+
+// ListAllNamespaces lists a resource in all namespaces. A single cluster-scoped list is
+// used when RBAC allows it. Otherwise the namespaces (or OpenShift projects) the user can
+// see are listed concurrently with bounded workers, skipping namespaces where listing the
+// resource is forbidden. The cluster-scoped error is returned when no namespace can be listed.
+func ListAllNamespaces(ctx context.Context, c dynamic.Interface, gvr schema.GroupVersionResource, opts metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	list, err := c.Resource(gvr).Namespace(metav1.NamespaceAll).List(ctx, opts)
+	if err == nil || !apierrors.IsForbidden(err) {
+		return list, err
+	}
+
+	namespaces := listNamespaceNames(ctx, c)
+	if len(namespaces) == 0 {
+		return nil, err
+	}
+	klog.V(2).Infof("Cannot list %s cluster-wide, listing %d namespaces", gvr.Resource, len(namespaces))
+
+	merged, allowed, listErr := listNamespaced(ctx, c, gvr, namespaces, opts)
+	if listErr != nil {
+		return nil, listErr
+	}
+	if allowed == 0 {
+		return nil, err
+	}
+	return merged, nil
+}
+
+// listNamespaceNames returns the sorted names of the namespaces the user can see
+func listNamespaceNames(ctx context.Context, c dynamic.Interface) []string {
+	for _, gvr := range []schema.GroupVersionResource{namespacesGVR, projectsGVR} {
+		list, err := c.Resource(gvr).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			klog.V(3).Infof("Cannot list %s: %v", gvr.Resource, err)
+			continue
+		}
+		names := make([]string, 0, len(list.Items))
+		for _, item := range list.Items {
+			names = append(names, item.GetName())
+		}
+		sort.Strings(names)
+		return names
+	}
+	return nil
+}
+
+// listNamespaced lists the resource in each namespace with at most listWorkers lists in
+// flight, and merges the items in namespace order. It returns how many namespaces allowed
+// the list, and the first error other than forbidden.
+func listNamespaced(ctx context.Context, c dynamic.Interface, gvr schema.GroupVersionResource, namespaces []string, opts metav1.ListOptions) (*unstructured.UnstructuredList, int, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	lists := make([]*unstructured.UnstructuredList, len(namespaces))
+	errs := make([]error, len(namespaces))
+
+	var firstErr error
+	var mu sync.Mutex
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < listWorkers && w < len(namespaces); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				lists[i], errs[i] = c.Resource(gvr).Namespace(namespaces[i]).List(ctx, opts)
+				if errs[i] != nil && !apierrors.IsForbidden(errs[i]) {
+					// Stop the other lists, the listing fails anyway
+					mu.Lock()
+					if firstErr == nil {
+						firstErr = errs[i]
+					}
+					mu.Unlock()
+					cancel()
+				}
+			}
+		}()
+	}
+	for i := range namespaces {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, 0, firstErr
+	}
+
+	merged := &unstructured.UnstructuredList{Object: map[string]interface{}{}}
+	allowed := 0
+	for i, list := range lists {
+		if errs[i] != nil {
+			continue
+		}
+		allowed++
+		if len(merged.Object) == 0 {
+			merged.Object = list.Object
+		}
+		merged.Items = append(merged.Items, list.Items...)
+	}
+	return merged, allowed, nil
+}
@@ -0,0 +1,104 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func testObject(apiVersion, kind, namespace, name string) runtime.Object {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": apiVersion,
+		"kind":       kind,
+		"metadata":   map[string]interface{}{"name": name},
+	}}
+	if namespace != "" {
+		obj.SetNamespace(namespace)
+	}
+	return obj
+}
+
+// newListClient returns a fake cluster with plans in three namespaces where listing plans
+// cluster-wide and in the namespaces of forbidden is not allowed
+func newListClient(forbidden ...string) *dynamicfake.FakeDynamicClient {
+	c := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		PlansGVR:      "PlanList",
+		namespacesGVR: "NamespaceList",
+		projectsGVR:   "ProjectList",
+	},
+		testObject("v1", "Namespace", "", "team-b"),
+		testObject("v1", "Namespace", "", "team-a"),
+		testObject("v1", "Namespace", "", "team-c"),
+		testObject("forklift.konveyor.io/v1beta1", "Plan", "team-a", "plan-a"),
+		testObject("forklift.konveyor.io/v1beta1", "Plan", "team-b", "plan-b"),
+		testObject("forklift.konveyor.io/v1beta1", "Plan", "team-c", "plan-c"),
+	)
+	c.PrependReactor("list", "plans", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		ns := action.GetNamespace()
+		if ns == "" {
+			return true, nil, apierrors.NewForbidden(PlansGVR.GroupResource(), "", fmt.Errorf("cluster-wide list"))
+		}
+		for _, f := range forbidden {
+			if ns == f {
+				return true, nil, apierrors.NewForbidden(PlansGVR.GroupResource(), "", fmt.Errorf("namespace %s", ns))
+			}
+		}
+		return false, nil, nil
+	})
+	return c
+}
+
+func listedNames(list *unstructured.UnstructuredList) string {
+	var names []string
+	for _, item := range list.Items {
+		names = append(names, item.GetName())
+	}
+	return strings.Join(names, ",")
+}
+
+func TestListAllNamespaces_ClusterScoped(t *testing.T) {
+	c := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{PlansGVR: "PlanList"},
+		testObject("forklift.konveyor.io/v1beta1", "Plan", "team-a", "plan-a"),
+		testObject("forklift.konveyor.io/v1beta1", "Plan", "team-b", "plan-b"),
+	)
+
+	list, err := ListAllNamespaces(context.Background(), c, PlansGVR, metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("ListAllNamespaces() unexpected error: %v", err)
+	}
+	if len(list.Items) != 2 {
+		t.Errorf("items = %s, want both plans", listedNames(list))
+	}
+	if len(c.Actions()) != 1 {
+		t.Errorf("actions = %d, want a single cluster-scoped list", len(c.Actions()))
+	}
+}
+
+func TestListAllNamespaces_PerNamespace(t *testing.T) {
+	listWorkers = 2
+	defer func() { listWorkers = 8 }()
+
+	list, err := ListAllNamespaces(context.Background(), newListClient("team-b"), PlansGVR, metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("ListAllNamespaces() unexpected error: %v", err)
+	}
+	if got := listedNames(list); got != "plan-a,plan-c" {
+		t.Errorf("items = %s, want the plans of the allowed namespaces in namespace order", got)
+	}
+}
+
+func TestListAllNamespaces_AllForbidden(t *testing.T) {
+	_, err := ListAllNamespaces(context.Background(), newListClient("team-a", "team-b", "team-c"), PlansGVR, metav1.ListOptions{})
+	if !apierrors.IsForbidden(err) || !strings.Contains(err.Error(), "cluster-wide") {
+		t.Errorf("ListAllNamespaces() error = %v, want the cluster-scoped forbidden error", err)
+	}
+}
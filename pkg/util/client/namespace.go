@@ -1,33 +1,89 @@
 package client
 
 import (
+	"os"
+	"sync"
+
 	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/klog/v2"
+
+	"github.com/yaacov/kubectl-mtv/pkg/util/config"
 )
 
+// EnvNamespace overrides the saved default namespace for MTV resources
+const EnvNamespace = "MTV_NAMESPACE"
+
 // ForkliftMTVNamespace is the default namespace where Forklift MTV CRDs are deployed
 const OpenShiftMTVNamespace = "openshift-mtv"
 
+// Sources of the namespace returned by ResolveNamespaceWithSource
+const (
+	NamespaceSourceFlag    = "--namespace flag"
+	NamespaceSourceEnv     = EnvNamespace + " environment variable"
+	NamespaceSourceLocal   = "local settings of the kubeconfig context"
+	NamespaceSourceSetting = "default-namespace setting"
+	NamespaceSourceContext = "kubeconfig context"
+	NamespaceSourceDefault = "fallback"
+)
+
+// loggedNamespaces remembers the namespace choices already logged, commands resolve it many times
+var loggedNamespaces sync.Map
+
+// MTVNamespace returns the namespace explicitly chosen for MTV resources and its source:
+// 1. The namespace from command line flags
+// 2. The MTV_NAMESPACE environment variable
+// 3. The namespace saved for the kubeconfig context with 'settings set --local'
+// 4. The namespace saved with 'settings default-namespace'
+// It returns "" when none is set, leaving the kubeconfig context namespace to the caller.
+func MTVNamespace(configFlags *genericclioptions.ConfigFlags) (string, string) {
+	if configFlags.Namespace != nil && *configFlags.Namespace != "" {
+		return *configFlags.Namespace, NamespaceSourceFlag
+	}
+	if namespace := os.Getenv(EnvNamespace); namespace != "" {
+		return namespace, NamespaceSourceEnv
+	}
+	if namespace := config.ActiveDefault("namespace"); namespace != "" {
+		return namespace, NamespaceSourceLocal
+	}
+	file, err := config.LoadDefaults()
+	if err != nil {
+		klog.V(1).Infof("Ignoring the default-namespace setting: %v", err)
+		return "", ""
+	}
+	if file.DefaultNamespace != "" {
+		return file.DefaultNamespace, NamespaceSourceSetting
+	}
+	return "", ""
+}
+
 // ResolveNamespace determines the effective namespace with fallback logic:
-// 1. Use namespace from command line flags if specified
+// 1. Use the namespace chosen for MTV resources, see MTVNamespace
 // 2. Use namespace from current kubeconfig context if available
 // 3. Fall back to "default" namespace if neither is available
 func ResolveNamespace(configFlags *genericclioptions.ConfigFlags) string {
-	// Check if the namespace is set in the command line flags
-	if configFlags.Namespace != nil && *configFlags.Namespace != "" {
-		return *configFlags.Namespace
-	}
+	namespace, _ := ResolveNamespaceWithSource(configFlags)
+	return namespace
+}
 
-	// Try to get the namespace from kubeconfig
-	clientConfig := configFlags.ToRawKubeConfigLoader()
-	if clientConfig != nil {
-		namespace, _, err := clientConfig.Namespace()
-		if err == nil && namespace != "" {
-			return namespace
+// ResolveNamespaceWithSource is ResolveNamespace, also returning where the namespace came from
+func ResolveNamespaceWithSource(configFlags *genericclioptions.ConfigFlags) (string, string) {
+	namespace, source := MTVNamespace(configFlags)
+	if namespace == "" {
+		namespace, source = "default", NamespaceSourceDefault
+
+		// Try to get the namespace from kubeconfig
+		clientConfig := configFlags.ToRawKubeConfigLoader()
+		if clientConfig != nil {
+			if contextNamespace, _, err := clientConfig.Namespace(); err == nil && contextNamespace != "" {
+				namespace, source = contextNamespace, NamespaceSourceContext
+			}
 		}
 	}
 
-	// Fall back to default namespace if not found in kubeconfig
-	return "default"
+	if _, logged := loggedNamespaces.LoadOrStore(source+"/"+namespace, true); !logged {
+		klog.V(1).Infof("Using namespace '%s' from the %s", namespace, source)
+	}
+	return namespace, source
 }
 
 // ResolveNamespaceWithAllFlag determines the effective namespace considering the all-namespaces flag:
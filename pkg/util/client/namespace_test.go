@@ -0,0 +1,72 @@
+package client
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/yaacov/kubectl-mtv/pkg/util/config"
+)
+
+const namespaceKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: test
+  cluster:
+    server: https://127.0.0.1:6443
+contexts:
+- name: test
+  context:
+    cluster: test
+    namespace: context-ns
+current-context: test
+`
+
+func TestResolveNamespaceWithSource(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv("HOME", dir)
+	t.Setenv(EnvNamespace, "")
+
+	kubeconfig := filepath.Join(dir, "kubeconfig")
+	if err := os.WriteFile(kubeconfig, []byte(namespaceKubeconfig), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	configFlags := genericclioptions.NewConfigFlags(false)
+	configFlags.KubeConfig = &kubeconfig
+
+	check := func(wantNamespace, wantSource string) {
+		t.Helper()
+		namespace, source := ResolveNamespaceWithSource(configFlags)
+		if namespace != wantNamespace || source != wantSource {
+			t.Errorf("ResolveNamespaceWithSource() = %q, %q, want %q, %q", namespace, source, wantNamespace, wantSource)
+		}
+	}
+
+	check("context-ns", NamespaceSourceContext)
+
+	if err := (&config.DefaultsFile{DefaultNamespace: "openshift-mtv"}).Save(); err != nil {
+		t.Fatal(err)
+	}
+	check("openshift-mtv", NamespaceSourceSetting)
+
+	t.Setenv(EnvNamespace, "env-ns")
+	check("env-ns", NamespaceSourceEnv)
+
+	flagNamespace := "flag-ns"
+	configFlags.Namespace = &flagNamespace
+	check("flag-ns", NamespaceSourceFlag)
+
+	// Removing the setting falls back to the context namespace
+	configFlags.Namespace = nil
+	t.Setenv(EnvNamespace, "")
+	if err := (&config.DefaultsFile{}).Save(); err != nil {
+		t.Fatal(err)
+	}
+	check("context-ns", NamespaceSourceContext)
+	if namespace, _ := MTVNamespace(configFlags); namespace != "" {
+		t.Errorf("MTVNamespace() = %q, want none", namespace)
+	}
+}
@@ -2,6 +2,7 @@ package client
 
 import (
 	"context"
+	"fmt"
 	"strings"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -87,3 +88,32 @@ func GetMTVOperatorNamespace(ctx context.Context, configFlags *genericclioptions
 	// Fall back to hardcoded default
 	return OpenShiftMTVNamespace
 }
+
+// GetInventoryVersion returns the release of the inventory service: the image tag of the
+// inventory container of the forklift-controller deployment in the operator namespace.
+// Returns "unknown" when the image is referenced by digest only.
+func GetInventoryVersion(ctx context.Context, configFlags *genericclioptions.ConfigFlags, namespace string) (string, error) {
+	clientset, err := GetKubernetesClientset(configFlags)
+	if err != nil {
+		return "", err
+	}
+	deployment, err := clientset.AppsV1().Deployments(namespace).Get(ctx, "forklift-controller", metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+	for _, container := range deployment.Spec.Template.Spec.Containers {
+		if container.Name == "inventory" {
+			return ImageTag(container.Image), nil
+		}
+	}
+	return "", fmt.Errorf("deployment forklift-controller in namespace %s has no inventory container", namespace)
+}
+
+// ImageTag returns the tag of a container image reference, "unknown" when it has none
+func ImageTag(image string) string {
+	image, _, _ = strings.Cut(image, "@")
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		return image[i+1:]
+	}
+	return "unknown"
+}
@@ -13,6 +13,7 @@ import (
 
 	"github.com/yaacov/kubectl-mtv/pkg/cmd/get/inventory"
 	"github.com/yaacov/kubectl-mtv/pkg/util/client"
+	"github.com/yaacov/kubectl-mtv/pkg/util/wave"
 )
 
 // getResourceNames fetches resource names for completion
@@ -46,10 +47,30 @@ func getResourceNames(ctx context.Context, configFlags *genericclioptions.Config
 	return list, nil
 }
 
+// resolveNamespace returns the namespace to complete names from, or "" for all
+// namespaces when the command line sets --all-namespaces
+func resolveNamespace(cmd *cobra.Command, configFlags *genericclioptions.ConfigFlags) string {
+	if allNamespaces, err := cmd.Flags().GetBool("all-namespaces"); err == nil && allNamespaces {
+		return ""
+	}
+	return client.ResolveNamespace(configFlags)
+}
+
+// NameArgCompletion adapts a name completion to the positional NAME argument.
+// Commands accept a single name argument, so nothing is suggested once it is given.
+func NameArgCompletion(complete func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective)) func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return complete(cmd, args, toComplete)
+	}
+}
+
 // PlanNameCompletion provides completion for plan names
 func PlanNameCompletion(configFlags *genericclioptions.ConfigFlags) func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-		namespace := client.ResolveNamespace(configFlags)
+		namespace := resolveNamespace(cmd, configFlags)
 
 		names, err := getResourceNames(context.Background(), configFlags, client.PlansGVR, namespace)
 		if err != nil {
@@ -57,7 +78,7 @@ func PlanNameCompletion(configFlags *genericclioptions.ConfigFlags) func(cmd *co
 		}
 
 		if len(names) == 0 {
-			namespaceMsg := "current namespace"
+			namespaceMsg := "any namespace"
 			if namespace != "" {
 				namespaceMsg = fmt.Sprintf("namespace '%s'", namespace)
 			}
@@ -89,7 +110,7 @@ func ProviderNameCompletion(configFlags *genericclioptions.ConfigFlags) func(cmd
 // If providerType is empty, returns all providers
 func ProviderNameCompletionByType(configFlags *genericclioptions.ConfigFlags, providerType string) func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-		namespace := client.ResolveNamespace(configFlags)
+		namespace := resolveNamespace(cmd, configFlags)
 
 		// Get all providers
 		c, err := client.GetDynamicClient(configFlags)
@@ -103,7 +124,7 @@ func ProviderNameCompletionByType(configFlags *genericclioptions.ConfigFlags, pr
 		}
 
 		if len(resources.Items) == 0 {
-			namespaceMsg := "current namespace"
+			namespaceMsg := "any namespace"
 			if namespace != "" {
 				namespaceMsg = fmt.Sprintf("namespace '%s'", namespace)
 			}
@@ -153,7 +174,7 @@ func ProviderNameCompletionByType(configFlags *genericclioptions.ConfigFlags, pr
 // mappingType should be "network" or "storage"
 func MappingNameCompletion(configFlags *genericclioptions.ConfigFlags, mappingType string) func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-		namespace := client.ResolveNamespace(configFlags)
+		namespace := resolveNamespace(cmd, configFlags)
 
 		var gvr schema.GroupVersionResource
 		var resourceType string
@@ -171,7 +192,7 @@ func MappingNameCompletion(configFlags *genericclioptions.ConfigFlags, mappingTy
 		}
 
 		if len(names) == 0 {
-			namespaceMsg := "current namespace"
+			namespaceMsg := "any namespace"
 			if namespace != "" {
 				namespaceMsg = fmt.Sprintf("namespace '%s'", namespace)
 			}
@@ -557,3 +578,35 @@ func HostResourceNameCompletion(configFlags *genericclioptions.ConfigFlags) func
 		return filtered, cobra.ShellCompDirectiveNoFileComp
 	}
 }
+
+// WaveNameCompletion provides completion for migration wave names
+func WaveNameCompletion(configFlags *genericclioptions.ConfigFlags) func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		namespace := client.ResolveNamespace(configFlags)
+
+		c, err := client.GetDynamicClient(configFlags)
+		if err != nil {
+			return []string{fmt.Sprintf("Error getting client: %v", err)}, cobra.ShellCompDirectiveError
+		}
+
+		resources, err := c.Resource(client.ConfigMapsGVR).Namespace(namespace).List(context.Background(), metav1.ListOptions{
+			LabelSelector: wave.WaveLabel + "=true",
+		})
+		if err != nil {
+			return []string{fmt.Sprintf("Error fetching waves: %v", err)}, cobra.ShellCompDirectiveError
+		}
+
+		var filtered []string
+		for _, resource := range resources.Items {
+			if strings.HasPrefix(resource.GetName(), toComplete) {
+				filtered = append(filtered, resource.GetName())
+			}
+		}
+
+		if len(filtered) == 0 {
+			return []string{"No migration waves found"}, cobra.ShellCompDirectiveError
+		}
+
+		return filtered, cobra.ShellCompDirectiveNoFileComp
+	}
+}
@@ -0,0 +1,62 @@
+package completion
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+func TestNameArgCompletion(t *testing.T) {
+	names := func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"plan-a", "plan-b"}, cobra.ShellCompDirectiveNoFileComp
+	}
+	complete := NameArgCompletion(names)
+
+	got, _ := complete(&cobra.Command{}, nil, "")
+	if len(got) != 2 {
+		t.Errorf("NameArgCompletion() without args = %v, want both names", got)
+	}
+
+	got, directive := complete(&cobra.Command{}, []string{"plan-a"}, "")
+	if len(got) != 0 || directive != cobra.ShellCompDirectiveNoFileComp {
+		t.Errorf("NameArgCompletion() after the name argument = %v, %v, want no suggestions", got, directive)
+	}
+}
+
+func TestResolveNamespace(t *testing.T) {
+	namespace := "mtv"
+	configFlags := genericclioptions.NewConfigFlags(false)
+	configFlags.Namespace = &namespace
+
+	cmd := &cobra.Command{}
+	cmd.Flags().BoolP("all-namespaces", "A", false, "")
+	if got := resolveNamespace(cmd, configFlags); got != "mtv" {
+		t.Errorf("resolveNamespace() = %q, want mtv", got)
+	}
+
+	_ = cmd.Flags().Set("all-namespaces", "true")
+	if got := resolveNamespace(cmd, configFlags); got != "" {
+		t.Errorf("resolveNamespace() with --all-namespaces = %q, want all namespaces", got)
+	}
+}
+
+func TestQueryFieldCompletions(t *testing.T) {
+	fields := []string{"name", "cpuCount", "coresPerSocket", "len(disks)", "disks[*].capacity"}
+	tests := []struct {
+		toComplete string
+		want       []string
+	}{
+		{"", []string{"where "}},
+		{"where c", []string{"where cpuCount", "where coresPerSocket"}},
+		{"where CPU", []string{"where cpuCount"}},
+		{"where cpuCount > 2 and len(d", []string{"where cpuCount > 2 and len(disks)"}},
+		{"where x", nil},
+	}
+	for _, tt := range tests {
+		if got := queryFieldCompletions(fields, tt.toComplete); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("queryFieldCompletions(%q) = %v, want %v", tt.toComplete, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package completion
+
+import (
+	"context"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/get/inventory"
+	"github.com/yaacov/kubectl-mtv/pkg/cmd/help"
+	"github.com/yaacov/kubectl-mtv/pkg/util/client"
+)
+
+// QueryCompletion provides completion for the --query flag of inventory commands: the
+// word being typed is completed to the inventory fields of the --provider type, so
+// "where cpu" suggests "where cpuCount". resource is help.SchemaResourceVM or
+// help.SchemaResourceNetwork.
+func QueryCompletion(configFlags *genericclioptions.ConfigFlags, resource string) func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		providerName, _ := cmd.Flags().GetString("provider")
+		if providerName == "" {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		provider, err := inventory.GetProviderByName(context.Background(), configFlags, providerName, client.ResolveNamespace(configFlags))
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		providerType, _, _ := unstructured.NestedString(provider.Object, "spec", "type")
+		schema := help.GetInventorySchema(providerType)
+		if schema == nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		return queryFieldCompletions(schema.QueryFields(resource), toComplete), cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+	}
+}
+
+// queryFieldCompletions completes the last word of a partial query to the fields that
+// start with it, keeping the text before it. An empty query is completed to "where ".
+func queryFieldCompletions(fields []string, toComplete string) []string {
+	if strings.TrimSpace(toComplete) == "" {
+		return []string{"where "}
+	}
+
+	start := strings.LastIndex(toComplete, " ") + 1
+	prefix, word := toComplete[:start], strings.ToLower(toComplete[start:])
+	var completions []string
+	for _, f := range fields {
+		if strings.HasPrefix(strings.ToLower(f), word) {
+			completions = append(completions, prefix+f)
+		}
+	}
+	return completions
+}
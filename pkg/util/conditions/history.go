@@ -0,0 +1,141 @@
+package conditions
+
+import (
+	"sort"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// StatusRemoved is recorded when a condition disappears from the resource
+const StatusRemoved = "Removed"
+
+// maxEntries bounds the journal of one resource; older entries are dropped first
+const maxEntries = 100
+
+// FlappingTransitions is the number of transitions of one condition type that marks it as flapping
+const FlappingTransitions = 3
+
+// Entry is one observed state of a condition
+type Entry struct {
+	Time     time.Time `json:"time"`
+	Type     string    `json:"type"`
+	Status   string    `json:"status"`
+	Category string    `json:"category,omitempty"`
+	Reason   string    `json:"reason,omitempty"`
+	Message  string    `json:"message,omitempty"`
+}
+
+// sameState reports whether two entries describe the same condition state. Message changes
+// alone (e.g. progress counters) are not transitions.
+func (e Entry) sameState(other Entry) bool {
+	return e.Status == other.Status && e.Category == other.Category && e.Reason == other.Reason
+}
+
+// Journal is the client-side history of the conditions of one resource. Forklift updates
+// conditions in place, so earlier states are only known from previous observations.
+type Journal struct {
+	// UID of the observed resource; a journal of a deleted and recreated resource is discarded
+	UID     string  `json:"uid,omitempty"`
+	Entries []Entry `json:"entries"`
+}
+
+// Observe records the conditions of the resource that changed since the last observation and
+// reports whether the journal changed. The lastTransitionTime of a condition is used as the
+// transition time when it is newer than the previous entry, otherwise now.
+func (j *Journal) Observe(obj *unstructured.Unstructured, now time.Time) bool {
+	if uid := string(obj.GetUID()); uid != j.UID {
+		if j.UID != "" {
+			j.Entries = nil
+		}
+		j.UID = uid
+	}
+
+	last := j.latest()
+	changed := false
+	seen := map[string]bool{}
+
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, c := range conditions {
+		condMap, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		entry := Entry{}
+		entry.Type, _ = condMap["type"].(string)
+		entry.Status, _ = condMap["status"].(string)
+		entry.Category, _ = condMap["category"].(string)
+		entry.Reason, _ = condMap["reason"].(string)
+		entry.Message, _ = condMap["message"].(string)
+		if entry.Type == "" {
+			continue
+		}
+		seen[entry.Type] = true
+
+		previous, known := last[entry.Type]
+		if known && previous.sameState(entry) {
+			continue
+		}
+
+		entry.Time = now
+		if ts, _ := condMap["lastTransitionTime"].(string); ts != "" {
+			if t, err := time.Parse(time.RFC3339, ts); err == nil && (!known || t.After(previous.Time)) {
+				entry.Time = t
+			}
+		}
+		j.Entries = append(j.Entries, entry)
+		changed = true
+	}
+
+	for condType, previous := range last {
+		if !seen[condType] && previous.Status != StatusRemoved {
+			j.Entries = append(j.Entries, Entry{Time: now, Type: condType, Status: StatusRemoved})
+			changed = true
+		}
+	}
+
+	if changed {
+		sort.SliceStable(j.Entries, func(a, b int) bool { return j.Entries[a].Time.Before(j.Entries[b].Time) })
+		if len(j.Entries) > maxEntries {
+			j.Entries = j.Entries[len(j.Entries)-maxEntries:]
+		}
+	}
+	return changed
+}
+
+// latest returns the last recorded entry of each condition type
+func (j *Journal) latest() map[string]Entry {
+	last := map[string]Entry{}
+	for _, e := range j.Entries {
+		if prev, ok := last[e.Type]; !ok || !e.Time.Before(prev.Time) {
+			last[e.Type] = e
+		}
+	}
+	return last
+}
+
+// Transitions returns the number of state changes of each condition type, not counting
+// the first observation
+func (j *Journal) Transitions() map[string]int {
+	counts := map[string]int{}
+	seen := map[string]bool{}
+	for _, e := range j.Entries {
+		if seen[e.Type] {
+			counts[e.Type]++
+		}
+		seen[e.Type] = true
+	}
+	return counts
+}
+
+// Flapping returns the condition types with at least FlappingTransitions transitions, sorted
+func (j *Journal) Flapping() []string {
+	var types []string
+	for condType, count := range j.Transitions() {
+		if count >= FlappingTransitions {
+			types = append(types, condType)
+		}
+	}
+	sort.Strings(types)
+	return types
+}
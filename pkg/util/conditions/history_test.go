@@ -0,0 +1,161 @@
+package conditions
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/yaacov/kubectl-mtv/pkg/util/describe"
+)
+
+func plan(uid string, conditions ...map[string]interface{}) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "forklift.konveyor.io/v1beta1",
+		"kind":       "Plan",
+		"metadata":   map[string]interface{}{"name": "web", "namespace": "mtv"},
+	}}
+	obj.SetUID(types.UID(uid))
+	items := make([]interface{}, 0, len(conditions))
+	for _, c := range conditions {
+		items = append(items, c)
+	}
+	_ = unstructured.SetNestedSlice(obj.Object, items, "status", "conditions")
+	return obj
+}
+
+func condition(condType, status, transition string) map[string]interface{} {
+	c := map[string]interface{}{"type": condType, "status": status, "category": "Required", "message": condType + " is " + status}
+	if transition != "" {
+		c["lastTransitionTime"] = transition
+	}
+	return c
+}
+
+func TestJournalObserve(t *testing.T) {
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	j := &Journal{}
+
+	if !j.Observe(plan("uid-1", condition("Ready", "True", "2026-03-02T09:00:00Z")), now) {
+		t.Fatal("first observation should change the journal")
+	}
+	if len(j.Entries) != 1 || !j.Entries[0].Time.Equal(now.Add(-time.Hour)) {
+		t.Fatalf("entries = %+v, want the lastTransitionTime", j.Entries)
+	}
+
+	// Same state with a new message is not a transition
+	same := condition("Ready", "True", "2026-03-02T09:00:00Z")
+	same["message"] = "updated"
+	if j.Observe(plan("uid-1", same), now.Add(time.Minute)) {
+		t.Errorf("unchanged condition recorded: %+v", j.Entries)
+	}
+
+	// A transition without a newer lastTransitionTime is timed when observed
+	if !j.Observe(plan("uid-1", condition("Ready", "False", "2026-03-02T09:00:00Z")), now.Add(2*time.Minute)) {
+		t.Fatal("status change not recorded")
+	}
+	if got := j.Entries[len(j.Entries)-1]; got.Status != "False" || !got.Time.Equal(now.Add(2*time.Minute)) {
+		t.Errorf("transition entry = %+v", got)
+	}
+
+	// Disappearing conditions are recorded once
+	j.Observe(plan("uid-1"), now.Add(3*time.Minute))
+	if j.Observe(plan("uid-1"), now.Add(4*time.Minute)) {
+		t.Error("removed condition recorded twice")
+	}
+	if got := j.Entries[len(j.Entries)-1]; got.Status != StatusRemoved {
+		t.Errorf("last entry = %+v, want removed", got)
+	}
+
+	// A recreated resource starts a new journal
+	j.Observe(plan("uid-2", condition("Ready", "True", "")), now.Add(5*time.Minute))
+	if len(j.Entries) != 1 || j.UID != "uid-2" {
+		t.Errorf("entries after recreation = %+v", j.Entries)
+	}
+}
+
+func TestJournalFlapping(t *testing.T) {
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	j := &Journal{}
+	statuses := []string{"True", "False", "True", "False"}
+	for i, status := range statuses {
+		j.Observe(plan("uid", condition("ConnectionTestSucceeded", status, ""), condition("Ready", "True", "")), now.Add(time.Duration(i)*time.Minute))
+	}
+
+	if got := j.Transitions()["ConnectionTestSucceeded"]; got != 3 {
+		t.Errorf("transitions = %d, want 3", got)
+	}
+	if flapping := j.Flapping(); len(flapping) != 1 || flapping[0] != "ConnectionTestSucceeded" {
+		t.Errorf("flapping = %v", flapping)
+	}
+}
+
+func TestJournalObserve_BoundsEntries(t *testing.T) {
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	j := &Journal{}
+	for i := 0; i < maxEntries+20; i++ {
+		status := "True"
+		if i%2 == 1 {
+			status = "False"
+		}
+		j.Observe(plan("uid", condition("Ready", status, "")), now.Add(time.Duration(i)*time.Second))
+	}
+	if len(j.Entries) != maxEntries {
+		t.Errorf("entries = %d, want %d", len(j.Entries), maxEntries)
+	}
+}
+
+func TestLocalStoreRecord(t *testing.T) {
+	store := &LocalStore{Dir: t.TempDir()}
+	ctx := context.Background()
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+
+	if _, err := Record(ctx, store, plan("uid", condition("Ready", "True", "")), now); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	journal, err := Record(ctx, store, plan("uid", condition("Ready", "False", "")), now.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(journal.Entries) != 2 {
+		t.Fatalf("entries = %+v", journal.Entries)
+	}
+
+	loaded, err := store.Load(ctx, plan("uid"))
+	if err != nil || len(loaded.Entries) != 2 {
+		t.Errorf("loaded = %+v, %v", loaded, err)
+	}
+	if !strings.HasSuffix(store.Location(plan("uid")), "plan/mtv_web.json") {
+		t.Errorf("location = %s", store.Location(plan("uid")))
+	}
+}
+
+func TestAnnotationStoreLoad(t *testing.T) {
+	obj := plan("uid")
+	obj.SetAnnotations(map[string]string{HistoryAnnotation: `{"uid":"uid","entries":[{"time":"2026-03-02T10:00:00Z","type":"Ready","status":"True"}]}`})
+
+	journal, err := (&AnnotationStore{}).Load(context.Background(), obj)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(journal.Entries) != 1 || journal.Entries[0].Type != "Ready" {
+		t.Errorf("journal = %+v", journal)
+	}
+}
+
+func TestAddHistorySection(t *testing.T) {
+	store := &LocalStore{Dir: t.TempDir()}
+	b := describe.NewBuilder("MIGRATION PLAN")
+	AddHistorySection(context.Background(), b, store, plan("uid", condition("Ready", "True", "2026-03-02T09:00:00Z")), true)
+
+	text, err := describe.Format(b.Build(), "markdown")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(text, "CONDITION HISTORY") || !strings.Contains(text, "Ready is True") {
+		t.Errorf("section = %s", text)
+	}
+}
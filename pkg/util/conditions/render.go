@@ -0,0 +1,61 @@
+package conditions
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/yaacov/kubectl-mtv/pkg/util/describe"
+	"github.com/yaacov/kubectl-mtv/pkg/util/output"
+)
+
+// AddHistorySection records the current conditions of the resource in the store and adds a
+// CONDITION HISTORY timeline to the description. Store errors are shown in the section.
+func AddHistorySection(ctx context.Context, b *describe.Builder, store Store, obj *unstructured.Unstructured, useUTC bool) {
+	b.Section("CONDITION HISTORY")
+	b.Field("Store", store.Location(obj))
+
+	journal, err := Record(ctx, store, obj, time.Now())
+	if err != nil {
+		b.FieldC("Error", err.Error(), output.Red)
+		if journal == nil {
+			return
+		}
+	}
+
+	if flapping := journal.Flapping(); len(flapping) > 0 {
+		transitions := journal.Transitions()
+		parts := make([]string, 0, len(flapping))
+		for _, condType := range flapping {
+			parts = append(parts, fmt.Sprintf("%s (%d transitions)", condType, transitions[condType]))
+		}
+		b.FieldC("Flapping", strings.Join(parts, ", "), output.Yellow)
+	}
+
+	if len(journal.Entries) == 0 {
+		b.Field("Entries", "none recorded yet")
+		return
+	}
+
+	headers := []describe.TableColumn{
+		{Display: "TIME", Key: "time"},
+		{Display: "TYPE", Key: "type"},
+		{Display: "STATUS", Key: "status", ColorFunc: output.ColorizeConditionStatus},
+		{Display: "CATEGORY", Key: "category", ColorFunc: output.ColorizeCategory},
+		{Display: "MESSAGE", Key: "message"},
+	}
+	rows := make([]map[string]string, 0, len(journal.Entries))
+	for _, e := range journal.Entries {
+		rows = append(rows, map[string]string{
+			"time":     output.FormatTimestamp(e.Time, useUTC),
+			"type":     e.Type,
+			"status":   e.Status,
+			"category": e.Category,
+			"message":  e.Message,
+		})
+	}
+	b.Table(headers, rows)
+}
@@ -0,0 +1,171 @@
+package conditions
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+)
+
+// HistoryAnnotation holds the condition journal when it is persisted on the resource
+const HistoryAnnotation = "kubectl-mtv/condition-history"
+
+// Store names accepted by NewStore
+const (
+	StoreLocal      = "local"
+	StoreAnnotation = "annotation"
+)
+
+// Store loads and saves the condition journal of a resource
+type Store interface {
+	Load(ctx context.Context, obj *unstructured.Unstructured) (*Journal, error)
+	Save(ctx context.Context, obj *unstructured.Unstructured, journal *Journal) error
+	// Location describes where the journal of the resource is kept
+	Location(obj *unstructured.Unstructured) string
+}
+
+// NewStore returns the store of the given name. Annotation stores patch the resource of gvr.
+func NewStore(name string, c dynamic.Interface, gvr schema.GroupVersionResource) (Store, error) {
+	switch name {
+	case StoreLocal:
+		dir, err := DefaultDir()
+		if err != nil {
+			return nil, err
+		}
+		return &LocalStore{Dir: dir}, nil
+	case StoreAnnotation:
+		return &AnnotationStore{Client: c, GVR: gvr}, nil
+	default:
+		return nil, fmt.Errorf("invalid history store '%s', expected %s or %s", name, StoreLocal, StoreAnnotation)
+	}
+}
+
+// DefaultDir returns the local condition history directory in the user cache directory
+func DefaultDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find user cache directory: %v", err)
+	}
+	return filepath.Join(dir, "kubectl-mtv", "condition-history"), nil
+}
+
+// LocalStore keeps one JSON file per resource on the local machine
+type LocalStore struct {
+	Dir string
+}
+
+// path returns the journal file of a resource
+func (s *LocalStore) path(obj *unstructured.Unstructured) string {
+	kind := strings.ToLower(obj.GetKind())
+	if kind == "" {
+		kind = "resource"
+	}
+	return filepath.Join(s.Dir, kind, fmt.Sprintf("%s_%s.json", obj.GetNamespace(), obj.GetName()))
+}
+
+// Load reads the journal of a resource; a missing file is an empty journal
+func (s *LocalStore) Load(_ context.Context, obj *unstructured.Unstructured) (*Journal, error) {
+	data, err := os.ReadFile(s.path(obj))
+	if os.IsNotExist(err) {
+		return &Journal{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read condition history: %v", err)
+	}
+	journal := &Journal{}
+	if err := json.Unmarshal(data, journal); err != nil {
+		return nil, fmt.Errorf("failed to parse condition history %s: %v", s.path(obj), err)
+	}
+	return journal, nil
+}
+
+// Save writes the journal of a resource
+func (s *LocalStore) Save(_ context.Context, obj *unstructured.Unstructured, journal *Journal) error {
+	path := s.path(obj)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create condition history directory: %v", err)
+	}
+	data, err := json.MarshalIndent(journal, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode condition history: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write condition history: %v", err)
+	}
+	return nil
+}
+
+// Location returns the journal file of a resource
+func (s *LocalStore) Location(obj *unstructured.Unstructured) string {
+	return s.path(obj)
+}
+
+// AnnotationStore keeps the journal in the HistoryAnnotation of the resource, shared by
+// everyone describing it
+type AnnotationStore struct {
+	Client dynamic.Interface
+	GVR    schema.GroupVersionResource
+}
+
+// Load reads the journal from the resource annotation
+func (s *AnnotationStore) Load(_ context.Context, obj *unstructured.Unstructured) (*Journal, error) {
+	value := obj.GetAnnotations()[HistoryAnnotation]
+	if value == "" {
+		return &Journal{}, nil
+	}
+	journal := &Journal{}
+	if err := json.Unmarshal([]byte(value), journal); err != nil {
+		return nil, fmt.Errorf("failed to parse annotation %s: %v", HistoryAnnotation, err)
+	}
+	return journal, nil
+}
+
+// Save patches the journal into the resource annotation
+func (s *AnnotationStore) Save(ctx context.Context, obj *unstructured.Unstructured, journal *Journal) error {
+	data, err := json.Marshal(journal)
+	if err != nil {
+		return fmt.Errorf("failed to encode condition history: %v", err)
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{HistoryAnnotation: string(data)},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode condition history patch: %v", err)
+	}
+	_, err = s.Client.Resource(s.GVR).Namespace(obj.GetNamespace()).Patch(ctx, obj.GetName(), types.MergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to save condition history annotation: %v", err)
+	}
+	return nil
+}
+
+// Location names the annotation holding the journal
+func (s *AnnotationStore) Location(_ *unstructured.Unstructured) string {
+	return "annotation " + HistoryAnnotation
+}
+
+// Record adds the current conditions of the resource to its journal and saves the journal
+// when it changed
+func Record(ctx context.Context, store Store, obj *unstructured.Unstructured, now time.Time) (*Journal, error) {
+	journal, err := store.Load(ctx, obj)
+	if err != nil {
+		return nil, err
+	}
+	if journal.Observe(obj, now) {
+		if err := store.Save(ctx, obj, journal); err != nil {
+			return journal, err
+		}
+	}
+	return journal, nil
+}
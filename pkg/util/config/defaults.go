@@ -0,0 +1,264 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/klog/v2"
+)
+
+// Default is a command default that can be saved per kubeconfig context with
+// 'settings set --local'. It stands in for a flag the user did not set.
+type Default struct {
+	// Name is the setting name, also the name of the flag it defaults
+	Name        string
+	Description string
+	// Env is the environment variable that takes precedence over the saved value, if any
+	Env string
+	// resolved defaults are read where they are used instead of set on the flag
+	resolved bool
+	// applies limits the flags the default is set on, nil for every flag of that name
+	applies  func(flag *pflag.Flag) bool
+	validate func(value string) error
+}
+
+// Defaults lists the settings that can be saved per kubeconfig context
+var Defaults = []Default{
+	{Name: "namespace", Description: "namespace of MTV resources", Env: "MTV_NAMESPACE", resolved: true, validate: validateNotEmpty},
+	{Name: "inventory-url", Description: "base URL of the inventory service", Env: "MTV_INVENTORY_URL", validate: validateURL},
+	{Name: "output", Description: "output format (table, wide, json, yaml, markdown)", applies: printsTable, validate: validateOutput},
+	{Name: "request-timeout", Description: "timeout of single API server requests, e.g. 30s", validate: validateDuration},
+	{Name: "wait-timeout", Description: "maximum time to wait with --wait, e.g. 30m", validate: validateDuration},
+	{Name: "utc", Description: "format timestamps in UTC (true or false)", validate: validateBool},
+	{Name: "time-format", Description: "timestamp format, see --time-format", Env: "MTV_TIME_FORMAT", validate: validateNotEmpty},
+}
+
+// LookupDefault returns the definition of a saved default, nil when unknown
+func LookupDefault(name string) *Default {
+	for i := range Defaults {
+		if Defaults[i].Name == name {
+			return &Defaults[i]
+		}
+	}
+	return nil
+}
+
+// DefaultNames returns the names of the settings that can be saved per context
+func DefaultNames() []string {
+	names := make([]string, 0, len(Defaults))
+	for _, d := range Defaults {
+		names = append(names, d.Name)
+	}
+	return names
+}
+
+// DefaultsFile holds the default namespace saved with 'settings default-namespace' and the
+// saved defaults of each kubeconfig context
+type DefaultsFile struct {
+	DefaultNamespace string                       `yaml:"default-namespace,omitempty"`
+	Contexts         map[string]map[string]string `yaml:"contexts,omitempty"`
+}
+
+// DefaultsPath returns the path of the defaults file in the kubectl-mtv user config directory
+func DefaultsPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find user config directory: %v", err)
+	}
+	return filepath.Join(dir, "kubectl-mtv", "config.yaml"), nil
+}
+
+// LoadDefaults reads the defaults file; a missing file means no defaults are saved
+func LoadDefaults() (*DefaultsFile, error) {
+	file := &DefaultsFile{}
+	path, err := DefaultsPath()
+	if err != nil {
+		return file, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return file, nil
+	}
+	if err != nil {
+		return file, fmt.Errorf("failed to read '%s': %v", path, err)
+	}
+	if err := yaml.Unmarshal(data, file); err != nil {
+		return file, fmt.Errorf("failed to parse '%s': %v", path, err)
+	}
+	return file, nil
+}
+
+// Save writes the defaults file, removing it when no defaults are left
+func (f *DefaultsFile) Save() error {
+	path, err := DefaultsPath()
+	if err != nil {
+		return err
+	}
+	if len(f.Contexts) == 0 && f.DefaultNamespace == "" {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove '%s': %v", path, err)
+		}
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create '%s': %v", filepath.Dir(path), err)
+	}
+	data, err := yaml.Marshal(f)
+	if err != nil {
+		return fmt.Errorf("failed to encode settings: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write '%s': %v", path, err)
+	}
+	return nil
+}
+
+// Values returns the defaults saved for a context
+func (f *DefaultsFile) Values(kubeContext string) map[string]string {
+	return f.Contexts[kubeContext]
+}
+
+// Set saves a default for a context after validating it
+func (f *DefaultsFile) Set(kubeContext, name, value string) error {
+	def := LookupDefault(name)
+	if def == nil {
+		return fmt.Errorf("unknown local setting '%s', use one of: %s", name, strings.Join(DefaultNames(), ", "))
+	}
+	if err := def.validate(value); err != nil {
+		return fmt.Errorf("invalid value for '%s': %v", name, err)
+	}
+	if f.Contexts == nil {
+		f.Contexts = map[string]map[string]string{}
+	}
+	if f.Contexts[kubeContext] == nil {
+		f.Contexts[kubeContext] = map[string]string{}
+	}
+	f.Contexts[kubeContext][name] = value
+	return nil
+}
+
+// Unset removes a default of a context, it returns false when it was not saved
+func (f *DefaultsFile) Unset(kubeContext, name string) bool {
+	values, ok := f.Contexts[kubeContext]
+	if !ok {
+		return false
+	}
+	if _, ok := values[name]; !ok {
+		return false
+	}
+	delete(values, name)
+	if len(values) == 0 {
+		delete(f.Contexts, kubeContext)
+	}
+	return true
+}
+
+// CurrentContext returns the kubeconfig context commands use: the --context flag or the
+// current context of the kubeconfig, "" when there is no kubeconfig
+func CurrentContext(configFlags *genericclioptions.ConfigFlags) string {
+	if configFlags.Context != nil && *configFlags.Context != "" {
+		return *configFlags.Context
+	}
+	raw, err := configFlags.ToRawKubeConfigLoader().RawConfig()
+	if err != nil {
+		return ""
+	}
+	return raw.CurrentContext
+}
+
+// activeDefaults are the defaults of the current context, applied before a command runs
+var (
+	activeMu       sync.Mutex
+	activeDefaults map[string]string
+)
+
+// ApplyDefaults sets every flag of the command the user did not set, and whose
+// environment variable is not set either, to its saved default. Flags keep their
+// unchanged state, so commands still tell explicit flags from defaults. A value a
+// command does not accept, e.g. an output format it does not support, is skipped.
+func ApplyDefaults(flags *pflag.FlagSet, values map[string]string) {
+	activeMu.Lock()
+	activeDefaults = values
+	activeMu.Unlock()
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		def := LookupDefault(name)
+		if def == nil {
+			klog.V(1).Infof("Ignoring unknown local setting '%s'", name)
+			continue
+		}
+		if def.resolved || (def.Env != "" && os.Getenv(def.Env) != "") {
+			continue
+		}
+		flag := flags.Lookup(name)
+		if flag == nil || flag.Changed || (def.applies != nil && !def.applies(flag)) {
+			continue
+		}
+		if err := flag.Value.Set(values[name]); err != nil {
+			klog.V(1).Infof("Ignoring local setting %s=%s: %v", name, values[name], err)
+			continue
+		}
+		klog.V(2).Infof("Using local setting %s=%s", name, values[name])
+	}
+}
+
+// ActiveDefault returns a saved default of the current context, applied by ApplyDefaults
+func ActiveDefault(name string) string {
+	activeMu.Lock()
+	defer activeMu.Unlock()
+	return activeDefaults[name]
+}
+
+// printsTable tells display output flags from the dry-run output flags of the create
+// commands, which default to no output and must not be set without --dry-run
+func printsTable(flag *pflag.Flag) bool {
+	return flag.DefValue == "table"
+}
+
+func validateNotEmpty(value string) error {
+	if strings.TrimSpace(value) == "" {
+		return fmt.Errorf("value is empty")
+	}
+	return nil
+}
+
+func validateURL(value string) error {
+	u, err := url.Parse(value)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return fmt.Errorf("'%s' is not an absolute URL", value)
+	}
+	return nil
+}
+
+func validateOutput(value string) error {
+	switch value {
+	case "table", "wide", "json", "yaml", "markdown":
+		return nil
+	}
+	return fmt.Errorf("'%s' is not one of table, wide, json, yaml, markdown", value)
+}
+
+func validateDuration(value string) error {
+	_, err := time.ParseDuration(value)
+	return err
+}
+
+func validateBool(value string) error {
+	_, err := strconv.ParseBool(value)
+	return err
+}
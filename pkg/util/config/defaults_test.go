@@ -0,0 +1,122 @@
+package config
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+func TestDefaultsFileRoundTrip(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	file, err := LoadDefaults()
+	if err != nil {
+		t.Fatalf("LoadDefaults() on a missing file: %v", err)
+	}
+	if err := file.Set("prod", "namespace", "openshift-mtv"); err != nil {
+		t.Fatal(err)
+	}
+	if err := file.Set("prod", "output", "json"); err != nil {
+		t.Fatal(err)
+	}
+	if err := file.Set("prod", "wait-timeout", "soon"); err == nil {
+		t.Error("Set(wait-timeout=soon) expected an invalid duration error")
+	}
+	if err := file.Set("prod", "colour", "none"); err == nil || !strings.Contains(err.Error(), "unknown local setting") {
+		t.Errorf("Set(colour) error = %v, want an unknown setting error", err)
+	}
+	if err := file.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadDefaults()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := loaded.Values("prod"); got["namespace"] != "openshift-mtv" || got["output"] != "json" || len(got) != 2 {
+		t.Errorf("loaded prod defaults = %v", got)
+	}
+	if loaded.DefaultNamespace != "" {
+		t.Errorf("loaded default namespace = %q, want none", loaded.DefaultNamespace)
+	}
+	if len(loaded.Values("dev")) != 0 {
+		t.Errorf("defaults leaked to another context: %v", loaded.Values("dev"))
+	}
+
+	if !loaded.Unset("prod", "namespace") || loaded.Unset("prod", "namespace") {
+		t.Error("Unset(namespace) should remove the setting once")
+	}
+}
+
+func TestDefaultsFileDefaultNamespace(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	// The default namespace alone keeps the file
+	if err := (&DefaultsFile{DefaultNamespace: "openshift-mtv"}).Save(); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadDefaults()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.DefaultNamespace != "openshift-mtv" {
+		t.Errorf("loaded default namespace = %q, want openshift-mtv", loaded.DefaultNamespace)
+	}
+
+	loaded.DefaultNamespace = ""
+	if err := loaded.Save(); err != nil {
+		t.Fatal(err)
+	}
+	path, _ := DefaultsPath()
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected '%s' to be removed, stat error = %v", path, err)
+	}
+}
+
+func TestApplyDefaults(t *testing.T) {
+	t.Setenv("MTV_TIME_FORMAT", "")
+	t.Cleanup(func() { activeDefaults = nil })
+
+	var output, dryRunOutput, timeFormat string
+	var waitTimeout time.Duration
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	flags.StringVar(&output, "output", "table", "")
+	flags.DurationVar(&waitTimeout, "wait-timeout", 0, "")
+	flags.StringVar(&timeFormat, "time-format", "", "")
+	if err := flags.Parse([]string{"--wait-timeout", "5m"}); err != nil {
+		t.Fatal(err)
+	}
+
+	ApplyDefaults(flags, map[string]string{
+		"output":       "json",
+		"wait-timeout": "1h",
+		"time-format":  "rfc3339",
+		"namespace":    "openshift-mtv",
+	})
+
+	if output != "json" || timeFormat != "rfc3339" {
+		t.Errorf("output = %q, time-format = %q, want the saved defaults", output, timeFormat)
+	}
+	if waitTimeout != 5*time.Minute {
+		t.Errorf("wait-timeout = %v, the command line flag must win", waitTimeout)
+	}
+	if flags.Changed("output") {
+		t.Error("a saved default must not mark the flag as changed")
+	}
+	if ActiveDefault("namespace") != "openshift-mtv" {
+		t.Errorf("ActiveDefault(namespace) = %q", ActiveDefault("namespace"))
+	}
+
+	// Dry-run output flags default to no output and are left alone
+	dryRun := pflag.NewFlagSet("create", pflag.ContinueOnError)
+	dryRun.StringVar(&dryRunOutput, "output", "", "")
+	ApplyDefaults(dryRun, map[string]string{"output": "json"})
+	if dryRunOutput != "" {
+		t.Errorf("dry-run output = %q, want it unset", dryRunOutput)
+	}
+}
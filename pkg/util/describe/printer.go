@@ -23,6 +23,38 @@ func Print(desc *Description, format string) error {
 	return err
 }
 
+// PrintAll renders several descriptions and writes them to stdout: one after another in
+// table and markdown output, and as a single list in JSON and YAML output.
+func PrintAll(descs []*Description, format string) error {
+	var s string
+	switch strings.ToLower(format) {
+	case "json":
+		data, err := json.MarshalIndent(descs, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal descriptions as JSON: %w", err)
+		}
+		s = string(data) + "\n"
+	case "yaml":
+		data, err := yaml.Marshal(descs)
+		if err != nil {
+			return fmt.Errorf("failed to marshal descriptions as YAML: %w", err)
+		}
+		s = string(data)
+	default:
+		parts := make([]string, 0, len(descs))
+		for _, desc := range descs {
+			part, err := Format(desc, format)
+			if err != nil {
+				return err
+			}
+			parts = append(parts, part)
+		}
+		s = strings.Join(parts, "")
+	}
+	_, err := fmt.Fprint(os.Stdout, s)
+	return err
+}
+
 // Format renders the description and returns the result as a string.
 func Format(desc *Description, format string) (string, error) {
 	switch strings.ToLower(format) {
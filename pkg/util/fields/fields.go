@@ -0,0 +1,49 @@
+// Package fields reads the nested fields of unstructured Forklift resources, returning
+// zero values for missing or mistyped fields.
+package fields
+
+import (
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// String returns a nested string field or an empty string
+func String(obj map[string]interface{}, fields ...string) string {
+	value, _, _ := unstructured.NestedString(obj, fields...)
+	return value
+}
+
+// Slice returns a nested slice field or nil
+func Slice(obj map[string]interface{}, fields ...string) []interface{} {
+	value, _, _ := unstructured.NestedSlice(obj, fields...)
+	return value
+}
+
+// Time returns a nested RFC3339 time field or nil
+func Time(obj map[string]interface{}, fields ...string) *time.Time {
+	value := String(obj, fields...)
+	if value == "" {
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
+
+// ErrorReasons joins the reasons of a Forklift error block
+func ErrorReasons(obj map[string]interface{}) string {
+	reasons, _, _ := unstructured.NestedStringSlice(obj, "error", "reasons")
+	return strings.Join(reasons, "; ")
+}
+
+// TargetNamespace returns the plan target namespace, defaulting to the plan namespace
+func TargetNamespace(plan *unstructured.Unstructured) string {
+	if ns := String(plan.Object, "spec", "targetNamespace"); ns != "" {
+		return ns
+	}
+	return plan.GetNamespace()
+}
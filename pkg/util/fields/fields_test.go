@@ -0,0 +1,52 @@
+package fields
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestFields(t *testing.T) {
+	vm := map[string]interface{}{
+		"name":      "web",
+		"started":   "2026-01-01T10:00:00Z",
+		"completed": "not a time",
+		"pipeline":  []interface{}{"DiskTransfer"},
+		"error": map[string]interface{}{
+			"reasons": []interface{}{"disk full", "timeout"},
+		},
+	}
+
+	if got := String(vm, "name"); got != "web" {
+		t.Errorf("String = %q", got)
+	}
+	if got := String(vm, "pipeline"); got != "" {
+		t.Errorf("String of a slice = %q, want empty", got)
+	}
+	if got := Slice(vm, "pipeline"); len(got) != 1 {
+		t.Errorf("Slice = %v", got)
+	}
+	if got := Time(vm, "started"); got == nil || got.Hour() != 10 {
+		t.Errorf("Time = %v", got)
+	}
+	if got := Time(vm, "completed"); got != nil {
+		t.Errorf("Time of an invalid value = %v, want nil", got)
+	}
+	if got := ErrorReasons(vm); got != "disk full; timeout" {
+		t.Errorf("ErrorReasons = %q", got)
+	}
+}
+
+func TestTargetNamespace(t *testing.T) {
+	plan := &unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "web", "namespace": "mtv"},
+	}}
+	if got := TargetNamespace(plan); got != "mtv" {
+		t.Errorf("TargetNamespace without spec.targetNamespace = %q, want mtv", got)
+	}
+
+	_ = unstructured.SetNestedField(plan.Object, "apps", "spec", "targetNamespace")
+	if got := TargetNamespace(plan); got != "apps" {
+		t.Errorf("TargetNamespace = %q, want apps", got)
+	}
+}
@@ -0,0 +1,115 @@
+package flags
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/yaacov/kubectl-mtv/pkg/util/client"
+)
+
+// DryRunFlag implements pflag.Value for the --dry-run flag of write commands. A bare --dry-run
+// selects client, and the true/false values of the former boolean flag are still accepted.
+type DryRunFlag struct {
+	value client.DryRunMode
+}
+
+func (d *DryRunFlag) String() string {
+	if d.value == "" {
+		return string(client.DryRunNone)
+	}
+	return string(d.value)
+}
+
+func (d *DryRunFlag) Set(value string) error {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "none", "false":
+		d.value = client.DryRunNone
+	case "client", "true":
+		d.value = client.DryRunClient
+	case "server":
+		d.value = client.DryRunServer
+	default:
+		return fmt.Errorf("invalid dry-run mode: %s. Valid modes are: none, client, server", value)
+	}
+	return nil
+}
+
+func (d *DryRunFlag) Type() string {
+	return "string"
+}
+
+// GetValue returns the dry-run mode
+func (d *DryRunFlag) GetValue() client.DryRunMode {
+	if d.value == "" {
+		return client.DryRunNone
+	}
+	return d.value
+}
+
+// IsClient reports whether the resources are printed without contacting the cluster
+func (d *DryRunFlag) IsClient() bool {
+	return d.GetValue() == client.DryRunClient
+}
+
+// IsServer reports whether the changes are submitted with server-side dry run
+func (d *DryRunFlag) IsServer() bool {
+	return d.GetValue() == client.DryRunServer
+}
+
+// Enabled reports whether any dry-run mode is selected
+func (d *DryRunFlag) Enabled() bool {
+	return d.GetValue() != client.DryRunNone
+}
+
+// GetValidValues returns all valid dry-run modes for auto-completion
+func (d *DryRunFlag) GetValidValues() []string {
+	return []string{string(client.DryRunNone), string(client.DryRunClient), string(client.DryRunServer)}
+}
+
+// NewDryRunFlag creates a new dry-run flag
+func NewDryRunFlag() *DryRunFlag {
+	return &DryRunFlag{value: client.DryRunNone}
+}
+
+// AddDryRunFlag registers d as the --dry-run flag of cmd, with completion of its modes,
+// together with the --diff flag that prints a dry run as a diff
+func AddDryRunFlag(cmd *cobra.Command, d *DryRunFlag, usage string) {
+	cmd.Flags().Var(d, "dry-run", usage)
+	cmd.Flags().Lookup("dry-run").NoOptDefVal = string(client.DryRunClient)
+	_ = cmd.RegisterFlagCompletionFunc("dry-run", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return d.GetValidValues(), cobra.ShellCompDirectiveNoFileComp
+	})
+	cmd.Flags().Bool("diff", false, "With --dry-run, print the changes as a diff against the current resources instead of the changed resources")
+}
+
+// Diff reports whether the --diff flag of cmd is set, false when cmd has no such flag
+func Diff(cmd *cobra.Command) bool {
+	diff, err := cmd.Flags().GetBool("diff")
+	return err == nil && diff
+}
+
+// DryRunMode returns the mode selected by the --dry-run flag of cmd, none when cmd has no
+// such flag
+func DryRunMode(cmd *cobra.Command) client.DryRunMode {
+	f := cmd.Flags().Lookup("dry-run")
+	if f == nil {
+		return client.DryRunNone
+	}
+	if d, ok := f.Value.(*DryRunFlag); ok {
+		return d.GetValue()
+	}
+	return client.DryRunNone
+}
+
+// AddShowDiffFlag registers the --show-diff flag of the patch commands
+func AddShowDiffFlag(cmd *cobra.Command) {
+	cmd.Flags().Bool("show-diff", false, "Print a unified YAML diff of each resource between its current and patched state before applying the patch (with --dry-run, the diff replaces the printed resources)")
+}
+
+// ShowDiff reports whether the --show-diff flag of cmd is set, false when cmd has no such flag
+func ShowDiff(cmd *cobra.Command) bool {
+	showDiff, err := cmd.Flags().GetBool("show-diff")
+	return err == nil && showDiff
+}
@@ -0,0 +1,112 @@
+package flags
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/yaacov/kubectl-mtv/pkg/util/client"
+)
+
+func TestDryRunFlag_Parse(t *testing.T) {
+	tests := []struct {
+		args    []string
+		want    client.DryRunMode
+		wantErr bool
+	}{
+		{nil, client.DryRunNone, false},
+		{[]string{"--dry-run"}, client.DryRunClient, false},
+		{[]string{"--dry-run=client"}, client.DryRunClient, false},
+		{[]string{"--dry-run=server"}, client.DryRunServer, false},
+		{[]string{"--dry-run=Server"}, client.DryRunServer, false},
+		{[]string{"--dry-run=none"}, client.DryRunNone, false},
+		// Values of the former boolean flag
+		{[]string{"--dry-run=true"}, client.DryRunClient, false},
+		{[]string{"--dry-run=false"}, client.DryRunNone, false},
+		{[]string{"--dry-run=all"}, client.DryRunNone, true},
+	}
+
+	for _, tt := range tests {
+		cmd := &cobra.Command{Use: "test", RunE: func(cmd *cobra.Command, args []string) error { return nil }}
+		d := NewDryRunFlag()
+		AddDryRunFlag(cmd, d, "dry run")
+
+		err := cmd.ParseFlags(tt.args)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%v: error = %v, wantErr %v", tt.args, err, tt.wantErr)
+			continue
+		}
+		if tt.wantErr {
+			continue
+		}
+		if got := DryRunMode(cmd); got != tt.want {
+			t.Errorf("%v: mode = %s, want %s", tt.args, got, tt.want)
+		}
+		if d.Enabled() != (tt.want != client.DryRunNone) || d.IsServer() != (tt.want == client.DryRunServer) {
+			t.Errorf("%v: Enabled/IsServer do not match mode %s", tt.args, tt.want)
+		}
+	}
+}
+
+func TestDryRunFlag_BareFlagKeepsArgs(t *testing.T) {
+	cmd := &cobra.Command{Use: "test", RunE: func(cmd *cobra.Command, args []string) error { return nil }}
+	d := NewDryRunFlag()
+	AddDryRunFlag(cmd, d, "dry run")
+
+	if err := cmd.ParseFlags([]string{"--dry-run", "my-plan"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !d.IsClient() {
+		t.Errorf("mode = %s, want client", d.GetValue())
+	}
+	if args := cmd.Flags().Args(); len(args) != 1 || args[0] != "my-plan" {
+		t.Errorf("args = %v, want [my-plan]", args)
+	}
+}
+
+func TestDryRunMode_WithoutFlag(t *testing.T) {
+	cmd := &cobra.Command{Use: "test"}
+	cmd.Flags().Bool("dry-run", true, "boolean dry run")
+	if got := DryRunMode(cmd); got != client.DryRunNone {
+		t.Errorf("mode = %s, want none for a boolean --dry-run", got)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	cmd := &cobra.Command{Use: "test", RunE: func(cmd *cobra.Command, args []string) error { return nil }}
+	AddDryRunFlag(cmd, NewDryRunFlag(), "dry run")
+
+	if Diff(cmd) {
+		t.Error("expected --diff to be off by default")
+	}
+	if err := cmd.ParseFlags([]string{"--dry-run", "--diff"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !Diff(cmd) {
+		t.Error("expected --diff to be set")
+	}
+
+	// Commands that do not write have no --diff flag
+	if Diff(&cobra.Command{Use: "get"}) {
+		t.Error("expected false for a command without --diff")
+	}
+}
+
+func TestShowDiff(t *testing.T) {
+	cmd := &cobra.Command{Use: "test", RunE: func(cmd *cobra.Command, args []string) error { return nil }}
+	AddShowDiffFlag(cmd)
+
+	if ShowDiff(cmd) {
+		t.Error("expected --show-diff to be off by default")
+	}
+	if err := cmd.ParseFlags([]string{"--show-diff"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !ShowDiff(cmd) {
+		t.Error("expected --show-diff to be set")
+	}
+
+	if ShowDiff(&cobra.Command{Use: "get"}) {
+		t.Error("expected false for a command without --show-diff")
+	}
+}
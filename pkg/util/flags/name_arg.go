@@ -18,14 +18,14 @@ func ResolveNameArg(nameFlag *string, args []string) error {
 	return nil
 }
 
-// ResolveNamesArg sets *namesFlag from args[0] if provided.
-// It returns an error when both a positional argument and --name are given.
+// ResolveNamesArg sets *namesFlag from the positional arguments if provided.
+// It returns an error when both positional arguments and --name are given.
 func ResolveNamesArg(namesFlag *[]string, args []string) error {
 	if len(args) > 0 {
 		if len(*namesFlag) > 0 {
 			return fmt.Errorf("cannot specify name as both argument and --name flag")
 		}
-		*namesFlag = []string{args[0]}
+		*namesFlag = append([]string(nil), args...)
 	}
 	return nil
 }
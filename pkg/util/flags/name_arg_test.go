@@ -86,6 +86,12 @@ func TestResolveNamesArg(t *testing.T) {
 			args:      []string{"my-plan"},
 			wantNames: []string{"my-plan"},
 		},
+		{
+			name:      "several positional args",
+			flagVals:  nil,
+			args:      []string{"plan-a", "plan-b", "plan-c"},
+			wantNames: []string{"plan-a", "plan-b", "plan-c"},
+		},
 		{
 			name:     "both flag and positional arg",
 			flagVals: []string{"flag-name"},
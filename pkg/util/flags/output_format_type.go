@@ -5,11 +5,22 @@ import (
 	"strings"
 
 	v1beta1 "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+
+	"github.com/yaacov/kubectl-mtv/pkg/util/output"
 )
 
 // OutputFormatHelp is the help text for the --output / -o flag across all commands.
 const OutputFormatHelp = "Output format (table, json, yaml, markdown)"
 
+// OutputFormatWideHelp is the help text for the --output / -o flag on commands that support wide tables.
+const OutputFormatWideHelp = "Output format (table, wide, json, yaml, markdown)"
+
+// OutputFormatWideTemplateHelp is the help text for the --output / -o flag on get commands that support wide tables.
+const OutputFormatWideTemplateHelp = "Output format (table, wide, json, yaml, markdown, jsonpath=TEMPLATE, jsonpath-file=FILE, go-template=TEMPLATE, go-template-file=FILE)"
+
+// OutputFormatTemplateHelp is the help text for the --output / -o flag on get commands.
+const OutputFormatTemplateHelp = "Output format (table, json, yaml, markdown, jsonpath=TEMPLATE, jsonpath-file=FILE, go-template=TEMPLATE, go-template-file=FILE)"
+
 // QueryHelp is the help text for the --query / -q flag across all commands.
 // It highlights the IN operator using square brackets since that is the most common syntax mistake.
 const QueryHelp = `Query filter using TSL syntax (e.g. "where name ~= 'prod-.*'", "where name in ['vm1','vm2']")`
@@ -46,6 +57,8 @@ func ValidateOffloadVendor(vendor string) error {
 type OutputFormatTypeFlag struct {
 	value        string
 	validFormats []string
+	// templates accepts jsonpath and go-template expressions
+	templates bool
 }
 
 func (o *OutputFormatTypeFlag) String() string {
@@ -53,6 +66,11 @@ func (o *OutputFormatTypeFlag) String() string {
 }
 
 func (o *OutputFormatTypeFlag) Set(value string) error {
+	if o.templates && output.IsTemplateFormat(value) {
+		o.value = value
+		return nil
+	}
+
 	isValid := false
 	for _, validType := range o.validFormats {
 		if value == validType {
@@ -90,3 +108,17 @@ func NewOutputFormatTypeFlag() *OutputFormatTypeFlag {
 		value:        "table", // default value
 	}
 }
+
+// NewWideOutputFormatTypeFlag creates a new output format type flag that also accepts "wide"
+func NewWideOutputFormatTypeFlag() *OutputFormatTypeFlag {
+	return &OutputFormatTypeFlag{
+		validFormats: []string{"table", "wide", "json", "yaml", "markdown"},
+		value:        "table", // default value
+	}
+}
+
+// WithTemplates makes the flag also accept jsonpath= and go-template= expressions, used by the get commands
+func (o *OutputFormatTypeFlag) WithTemplates() *OutputFormatTypeFlag {
+	o.templates = true
+	return o
+}
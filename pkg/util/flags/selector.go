@@ -0,0 +1,24 @@
+package flags
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// SelectorHelp is the help text of the --selector flag of list commands
+const SelectorHelp = "Label selector to filter on (e.g. wave=3,team=payments); supports '=', '==', '!=', 'in', 'notin' and key existence"
+
+// ValidateSelector checks a label selector, which filters a list and cannot be combined with a resource name
+func ValidateSelector(selector, name string) error {
+	if selector == "" {
+		return nil
+	}
+	if name != "" {
+		return fmt.Errorf("a resource name cannot be combined with --selector")
+	}
+	if _, err := labels.Parse(selector); err != nil {
+		return fmt.Errorf("invalid --selector '%s': %v", selector, err)
+	}
+	return nil
+}
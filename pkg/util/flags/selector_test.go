@@ -0,0 +1,19 @@
+package flags
+
+import "testing"
+
+func TestValidateSelector(t *testing.T) {
+	for _, selector := range []string{"", "wave=3,team=payments", "wave in (1,2)", "!archived", "team!=ops"} {
+		if err := ValidateSelector(selector, ""); err != nil {
+			t.Errorf("ValidateSelector(%q) unexpected error: %v", selector, err)
+		}
+	}
+	for _, selector := range []string{"=3", "team=pay ments", "wave in (1,2"} {
+		if err := ValidateSelector(selector, ""); err == nil {
+			t.Errorf("ValidateSelector(%q) expected error", selector)
+		}
+	}
+	if err := ValidateSelector("wave=3", "my-plan"); err == nil {
+		t.Error("expected an error combining a name with a selector")
+	}
+}
@@ -0,0 +1,239 @@
+package flags
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TimeValueHelp describes the time formats accepted by ParseTimeValue
+const TimeValueHelp = `RFC3339 (2026-12-31T23:00:00Z), a local time ("22:30", "tomorrow 02:00", "2026-12-31 23:00", optionally followed by a timezone such as "Europe/Berlin" or "UTC"), "now", or a relative time ("in 2h", "+90m")`
+
+// TimeWithZoneLayout formats a time with its timezone abbreviation, e.g. "2026-03-08 03:30:00 EDT"
+const TimeWithZoneLayout = "2006-01-02 15:04:05 MST"
+
+// daysPrefix matches a leading day count in a duration, e.g. "1d" or "2d6h"
+var daysPrefix = regexp.MustCompile(`^(\d+)d`)
+
+// ParseRelativeDuration parses a positive duration such as "2h", "1h30m", "1d", "in 2h" or "+45m".
+// In addition to Go durations, a leading day count ("1d", "2d12h") is accepted.
+func ParseRelativeDuration(value string) (time.Duration, error) {
+	s := strings.TrimSpace(strings.ToLower(value))
+	s = strings.TrimPrefix(s, "in ")
+	s = strings.TrimPrefix(s, "+")
+	s = strings.ReplaceAll(s, " ", "")
+	if s == "" {
+		return 0, fmt.Errorf("empty duration")
+	}
+
+	var total time.Duration
+	if m := daysPrefix.FindStringSubmatch(s); m != nil {
+		days, err := strconv.Atoi(m[1])
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration '%s': %v", value, err)
+		}
+		total = time.Duration(days) * 24 * time.Hour
+		s = s[len(m[0]):]
+	}
+	if s != "" {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration '%s': use values like 2h, 90m, 1h30m or 1d", value)
+		}
+		total += d
+	}
+	if total <= 0 {
+		return 0, fmt.Errorf("invalid duration '%s': must be positive", value)
+	}
+	return total, nil
+}
+
+// localDateTimeLayouts are the absolute date and time layouts interpreted in the local timezone
+var localDateTimeLayouts = []string{
+	"2006-01-02 15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02T15:04:05",
+}
+
+// clockLayouts are the time-of-day layouts
+var clockLayouts = []string{"15:04", "15:04:05"}
+
+// wallClock is a local date and time as written, before it is resolved in a timezone
+type wallClock struct {
+	year           int
+	month          time.Month
+	day            int
+	hour, min, sec int
+	loc            *time.Location
+}
+
+func (w wallClock) String() string {
+	return fmt.Sprintf("%04d-%02d-%02d %02d:%02d:%02d", w.year, w.month, w.day, w.hour, w.min, w.sec)
+}
+
+// matches reports whether t shows this wall clock time in its location
+func (w wallClock) matches(t time.Time) bool {
+	return t.Year() == w.year && t.Month() == w.month && t.Day() == w.day &&
+		t.Hour() == w.hour && t.Minute() == w.min && t.Second() == w.sec
+}
+
+// resolve returns the absolute time of the wall clock time, and a warning when it falls in
+// a daylight saving time change: times skipped when clocks move forward, and times that
+// occur twice when clocks move back, where the earlier one is used
+func (w wallClock) resolve() (time.Time, string) {
+	t := time.Date(w.year, w.month, w.day, w.hour, w.min, w.sec, 0, w.loc)
+	if !w.matches(t) {
+		return t, fmt.Sprintf("%s does not exist in %s, clocks move forward for daylight saving time; using %s",
+			w, zoneName(w.loc), t.Format(TimeWithZoneLayout))
+	}
+
+	_, before := t.Add(-12 * time.Hour).Zone()
+	_, after := t.Add(12 * time.Hour).Zone()
+	if before == after {
+		return t, ""
+	}
+	shift := time.Duration(after-before) * time.Second
+	if shift < 0 {
+		shift = -shift
+	}
+	for _, other := range []time.Time{t.Add(-shift), t.Add(shift)} {
+		if w.matches(other) {
+			if other.Before(t) {
+				t = other
+			}
+			return t, fmt.Sprintf("%s occurs twice in %s, clocks move back for daylight saving time; using the earlier %s",
+				w, zoneName(w.loc), t.Format(TimeWithZoneLayout))
+		}
+	}
+	return t, ""
+}
+
+// zoneName returns a readable name of a timezone
+func zoneName(loc *time.Location) string {
+	if loc == time.Local {
+		return "the local timezone"
+	}
+	return loc.String()
+}
+
+// LoadTimezone loads a timezone by its IANA name (e.g. "America/New_York"), "UTC" or "Local"
+func LoadTimezone(name string) (*time.Location, error) {
+	if strings.EqualFold(name, "utc") {
+		return time.UTC, nil
+	}
+	if strings.EqualFold(name, "local") {
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown timezone '%s': use an IANA name such as America/New_York, UTC or Local", name)
+	}
+	return loc, nil
+}
+
+// splitTimezone splits a trailing timezone, an IANA name or UTC, from a local time value
+func splitTimezone(value string) (string, *time.Location, error) {
+	i := strings.LastIndex(value, " ")
+	if i < 0 {
+		return value, nil, nil
+	}
+	zone := value[i+1:]
+	if !strings.Contains(zone, "/") && !strings.EqualFold(zone, "utc") {
+		return value, nil, nil
+	}
+	loc, err := LoadTimezone(zone)
+	if err != nil {
+		return "", nil, err
+	}
+	return strings.TrimSpace(value[:i]), loc, nil
+}
+
+// parseClock parses a time of day on the date of day in the location of day
+func parseClock(value string, day time.Time) (wallClock, bool) {
+	for _, layout := range clockLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return wallClock{day.Year(), day.Month(), day.Day(), t.Hour(), t.Minute(), t.Second(), day.Location()}, true
+		}
+	}
+	return wallClock{}, false
+}
+
+// ParseTimeValue parses an absolute or relative point in time:
+//   - RFC3339, e.g. "2026-12-31T23:00:00Z"
+//   - "now"
+//   - a relative duration, e.g. "in 2h", "+90m", "1d"
+//   - a local time of day, e.g. "22:30" (today, or tomorrow if that time has passed)
+//   - "today HH:MM" or "tomorrow HH:MM"
+//   - a local date and time, e.g. "2026-12-31 23:00"
+//
+// Local times use the timezone of now, or a timezone following the time
+// ("2026-12-31 23:00 Europe/Berlin", "22:30 UTC").
+func ParseTimeValue(value string, now time.Time) (time.Time, error) {
+	t, _, err := ParseTimeValueWithWarning(value, now)
+	return t, err
+}
+
+// ParseTimeValueWithWarning is ParseTimeValue that also returns a warning when a local time
+// falls in a daylight saving time change, or "" when it does not
+func ParseTimeValueWithWarning(value string, now time.Time) (time.Time, string, error) {
+	s, loc, err := splitTimezone(strings.TrimSpace(value))
+	if err != nil {
+		return time.Time{}, "", err
+	}
+	if loc != nil {
+		now = now.In(loc)
+	}
+	lower := strings.ToLower(s)
+
+	if lower == "now" {
+		return now, "", nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, "", nil
+	}
+	if strings.HasPrefix(lower, "in ") || strings.HasPrefix(lower, "+") {
+		d, err := ParseRelativeDuration(lower)
+		if err != nil {
+			return time.Time{}, "", err
+		}
+		return now.Add(d), "", nil
+	}
+
+	for _, layout := range localDateTimeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			t, warning := wallClock{t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), now.Location()}.resolve()
+			return t, warning, nil
+		}
+	}
+
+	if rest, ok := strings.CutPrefix(lower, "today "); ok {
+		if w, ok := parseClock(strings.TrimSpace(rest), now); ok {
+			t, warning := w.resolve()
+			return t, warning, nil
+		}
+	}
+	if rest, ok := strings.CutPrefix(lower, "tomorrow "); ok {
+		if w, ok := parseClock(strings.TrimSpace(rest), now.AddDate(0, 0, 1)); ok {
+			t, warning := w.resolve()
+			return t, warning, nil
+		}
+	}
+	if w, ok := parseClock(lower, now); ok {
+		t, warning := w.resolve()
+		if t.Before(now) {
+			w, _ = parseClock(lower, now.AddDate(0, 0, 1))
+			t, warning = w.resolve()
+		}
+		return t, warning, nil
+	}
+
+	// A bare duration such as "2h" is also accepted
+	if d, err := ParseRelativeDuration(lower); err == nil {
+		return now.Add(d), "", nil
+	}
+
+	return time.Time{}, "", fmt.Errorf("invalid time '%s': use %s", value, TimeValueHelp)
+}
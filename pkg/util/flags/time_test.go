@@ -0,0 +1,111 @@
+package flags
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseRelativeDuration(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"2h", 2 * time.Hour, false},
+		{"1h30m", 90 * time.Minute, false},
+		{"in 2h", 2 * time.Hour, false},
+		{"+45m", 45 * time.Minute, false},
+		{"1d", 24 * time.Hour, false},
+		{"2d6h", 54 * time.Hour, false},
+		{"", 0, true},
+		{"0s", 0, true},
+		{"-1h", 0, true},
+		{"soon", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseRelativeDuration(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseRelativeDuration(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseRelativeDuration(%q) = %s, want %s", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestParseTimeValue(t *testing.T) {
+	loc := time.FixedZone("test", 2*60*60)
+	now := time.Date(2026, 10, 16, 20, 0, 0, 0, loc)
+
+	tests := []struct {
+		value   string
+		want    time.Time
+		wantErr bool
+	}{
+		{"now", now, false},
+		{"2026-12-31T23:00:00Z", time.Date(2026, 12, 31, 23, 0, 0, 0, time.UTC), false},
+		{"in 2h", now.Add(2 * time.Hour), false},
+		{"+90m", now.Add(90 * time.Minute), false},
+		{"3h", now.Add(3 * time.Hour), false},
+		{"22:30", time.Date(2026, 10, 16, 22, 30, 0, 0, loc), false},
+		{"08:00", time.Date(2026, 10, 17, 8, 0, 0, 0, loc), false},
+		{"today 21:15", time.Date(2026, 10, 16, 21, 15, 0, 0, loc), false},
+		{"tomorrow 02:00", time.Date(2026, 10, 17, 2, 0, 0, 0, loc), false},
+		{"Tomorrow 02:00:30", time.Date(2026, 10, 17, 2, 0, 30, 0, loc), false},
+		{"2026-12-31 23:00", time.Date(2026, 12, 31, 23, 0, 0, 0, loc), false},
+		{"next week", time.Time{}, true},
+		{"25:00", time.Time{}, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseTimeValue(tt.value, now)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseTimeValue(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("ParseTimeValue(%q) = %s, want %s", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestParseTimeValueTimezones(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("timezone database not available: %v", err)
+	}
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value       string
+		want        time.Time
+		wantWarning string
+	}{
+		{"2026-03-05 09:00 America/New_York", time.Date(2026, 3, 5, 14, 0, 0, 0, time.UTC), ""},
+		{"2026-03-05 09:00 UTC", time.Date(2026, 3, 5, 9, 0, 0, 0, time.UTC), ""},
+		// Clocks move forward from 02:00 to 03:00 on 2026-03-08
+		{"2026-03-08 02:30 America/New_York", time.Date(2026, 3, 8, 2, 30, 0, 0, newYork), "does not exist"},
+		{"2026-03-08 01:30 America/New_York", time.Date(2026, 3, 8, 6, 30, 0, 0, time.UTC), ""},
+		// Clocks move back from 02:00 to 01:00 on 2026-11-01, 01:30 EDT comes first
+		{"2026-11-01 01:30 America/New_York", time.Date(2026, 11, 1, 5, 30, 0, 0, time.UTC), "occurs twice"},
+		{"2026-11-01 02:30 America/New_York", time.Date(2026, 11, 1, 7, 30, 0, 0, time.UTC), ""},
+	}
+	for _, tt := range tests {
+		got, warning, err := ParseTimeValueWithWarning(tt.value, now)
+		if err != nil {
+			t.Errorf("ParseTimeValueWithWarning(%q) error = %v", tt.value, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("ParseTimeValueWithWarning(%q) = %s, want %s", tt.value, got, tt.want)
+		}
+		if (tt.wantWarning == "") != (warning == "") || !strings.Contains(warning, tt.wantWarning) {
+			t.Errorf("ParseTimeValueWithWarning(%q) warning = %q, want %q", tt.value, warning, tt.wantWarning)
+		}
+	}
+
+	if _, err := ParseTimeValue("22:30 Mars/Olympus", now); err == nil {
+		t.Error("expected an error for an unknown timezone")
+	}
+}
@@ -3,10 +3,12 @@ package flags
 import (
 	"fmt"
 	"strings"
+
+	"github.com/yaacov/kubectl-mtv/pkg/util/output"
 )
 
 // vmInventoryOutputFormats is the single source of truth for valid VM inventory output formats.
-var vmInventoryOutputFormats = []string{"table", "json", "yaml", "markdown", "planvms"}
+var vmInventoryOutputFormats = []string{"table", "json", "yaml", "markdown", "planvms", output.FormatJSONStream}
 
 // VMInventoryOutputTypeFlag implements pflag.Value interface for VM inventory output format validation
 type VMInventoryOutputTypeFlag struct {
@@ -18,6 +20,14 @@ func (v *VMInventoryOutputTypeFlag) String() string {
 }
 
 func (v *VMInventoryOutputTypeFlag) Set(value string) error {
+	if output.IsTemplateFormat(value) {
+		v.value = value
+		return nil
+	}
+	// ndjson is the common name of the json-stream format
+	if value == "ndjson" {
+		value = output.FormatJSONStream
+	}
 	for _, valid := range vmInventoryOutputFormats {
 		if value == valid {
 			v.value = value
@@ -0,0 +1,84 @@
+package output
+
+import (
+	"strings"
+
+	"github.com/yaacov/kubectl-mtv/pkg/util/query"
+)
+
+// ParseFields splits a comma-separated --fields value into field paths,
+// dropping blanks and duplicates.
+func ParseFields(value string) []string {
+	var fields []string
+	seen := map[string]bool{}
+	for _, field := range strings.Split(value, ",") {
+		field = strings.TrimPrefix(strings.TrimSpace(field), ".")
+		if field == "" || seen[field] {
+			continue
+		}
+		seen[field] = true
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// SelectFields returns copies of the items holding only the given fields. Dotted
+// paths such as "Placement.AvailabilityZone" keep their nesting, so table columns
+// and templates address the projected items like the full ones; paths with array
+// indexes are stored under the path itself. Fields an item lacks are left out.
+func SelectFields(items []map[string]interface{}, fields []string) []map[string]interface{} {
+	if len(fields) == 0 {
+		return items
+	}
+
+	projected := make([]map[string]interface{}, 0, len(items))
+	for _, item := range items {
+		out := make(map[string]interface{}, len(fields))
+		for _, field := range fields {
+			value, err := query.GetValueByPathString(item, field)
+			if err != nil || value == nil {
+				continue
+			}
+			setFieldValue(out, field, value)
+		}
+		projected = append(projected, out)
+	}
+	return projected
+}
+
+// setFieldValue stores value in item at the dotted path of field
+func setFieldValue(item map[string]interface{}, field string, value interface{}) {
+	if strings.Contains(field, "[") {
+		item[field] = value
+		return
+	}
+
+	parts := strings.Split(field, ".")
+	current := item
+	for _, part := range parts[:len(parts)-1] {
+		next, ok := current[part].(map[string]interface{})
+		if !ok {
+			next = map[string]interface{}{}
+			current[part] = next
+		}
+		current = next
+	}
+	current[parts[len(parts)-1]] = value
+}
+
+// FieldColumns returns one table column per field. Fields shown by a default
+// column keep its title and colors; other fields are titled by their path.
+func FieldColumns(fields []string, defaults []Column) []Column {
+	columns := make([]Column, 0, len(fields))
+	for _, field := range fields {
+		column := Column{Title: strings.ToUpper(field), Key: field}
+		for _, def := range defaults {
+			if def.Key == field {
+				column = def
+				break
+			}
+		}
+		columns = append(columns, column)
+	}
+	return columns
+}
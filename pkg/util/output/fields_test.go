@@ -0,0 +1,60 @@
+package output
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseFields(t *testing.T) {
+	got := ParseFields(" name, id,,.powerState,name ")
+	want := []string{"name", "id", "powerState"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseFields() = %v, want %v", got, want)
+	}
+	if got := ParseFields(""); got != nil {
+		t.Errorf("ParseFields(\"\") = %v, want nil", got)
+	}
+}
+
+func TestSelectFields(t *testing.T) {
+	items := []map[string]interface{}{
+		{
+			"name":      "web-01",
+			"id":        "vm-1",
+			"memoryMB":  4096.0,
+			"Placement": map[string]interface{}{"AvailabilityZone": "us-east-1a", "Tenancy": "default"},
+			"disks":     []interface{}{map[string]interface{}{"capacity": 10.0}},
+		},
+		{"name": "web-02"},
+	}
+
+	got := SelectFields(items, []string{"name", "id", "Placement.AvailabilityZone", "disks[0].capacity"})
+	want := []map[string]interface{}{
+		{
+			"name":              "web-01",
+			"id":                "vm-1",
+			"Placement":         map[string]interface{}{"AvailabilityZone": "us-east-1a"},
+			"disks[0].capacity": 10.0,
+		},
+		{"name": "web-02"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SelectFields() = %v, want %v", got, want)
+	}
+
+	if got := SelectFields(items, nil); !reflect.DeepEqual(got, items) {
+		t.Errorf("SelectFields() without fields = %v, want the items unchanged", got)
+	}
+}
+
+func TestFieldColumns(t *testing.T) {
+	defaults := []Column{{Title: "NAME", Key: "name"}, {Title: "POWER", Key: "powerStateHuman", ColorFunc: ColorizePowerState}}
+
+	got := FieldColumns([]string{"powerStateHuman", "guestId"}, defaults)
+	if len(got) != 2 || got[0].Title != "POWER" || got[0].ColorFunc == nil {
+		t.Errorf("FieldColumns()[0] = %+v, want the default POWER column", got)
+	}
+	if got[1].Title != "GUESTID" || got[1].Key != "guestId" {
+		t.Errorf("FieldColumns()[1] = %+v, want a column titled by the field", got[1])
+	}
+}
@@ -0,0 +1,85 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+)
+
+// showIDs and idsOnly are the global ID display options, set from the
+// --show-ids and --ids-only flags before any command runs.
+var (
+	showIDs = false
+	idsOnly = false
+)
+
+// idFields are the item fields holding the ID of a row, in order of preference:
+// inventory IDs, EC2 instance IDs and Kubernetes object UIDs.
+var idFields = []string{"id", "InstanceId", "metadata.uid"}
+
+// SetIDMode globally controls the IDs of printed tables: show adds an ID column to
+// tables that only show names, only prints the row IDs one per line for scripting.
+func SetIDMode(show, only bool) {
+	showIDs = show
+	idsOnly = only
+}
+
+// applyIDMode adds the ID column for --show-ids, or prints the row IDs for --ids-only,
+// and then reports the table as printed.
+func (t *TablePrinter) applyIDMode() (bool, error) {
+	if !showIDs && !idsOnly {
+		return false, nil
+	}
+
+	key := t.idKey()
+	if key == "" {
+		if idsOnly {
+			return false, fmt.Errorf("cannot print only IDs: the rows of this output have no ID field")
+		}
+		return false, nil
+	}
+
+	if idsOnly {
+		for _, item := range t.items {
+			if id := t.extractValue(item, key); id != "" {
+				if _, err := fmt.Fprintln(t.writer, id); err != nil {
+					return true, err
+				}
+			}
+		}
+		return true, nil
+	}
+
+	for _, c := range t.columns {
+		if c.Key == key {
+			return false, nil
+		}
+	}
+	idColumn := Column{Title: "ID", Key: key}
+	if len(t.columns) == 0 {
+		t.columns = []Column{idColumn}
+		return false, nil
+	}
+	// The ID follows the name, the first column of the tables
+	columns := make([]Column, 0, len(t.columns)+1)
+	columns = append(columns, t.columns[0], idColumn)
+	t.columns = append(columns, t.columns[1:]...)
+	return false, nil
+}
+
+// idKey returns the key of the row IDs: the key of a column titled ID, otherwise
+// the first ID field found in the rows, or "" when the rows have no ID.
+func (t *TablePrinter) idKey() string {
+	for _, c := range t.columns {
+		if c.Key != "" && strings.EqualFold(c.Title, "ID") {
+			return c.Key
+		}
+	}
+	for _, field := range idFields {
+		for _, item := range t.items {
+			if t.extractValue(item, field) != "" {
+				return field
+			}
+		}
+	}
+	return ""
+}
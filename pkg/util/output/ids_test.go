@@ -0,0 +1,57 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func printIDTable(t *testing.T, show, only bool) string {
+	t.Helper()
+	SetIDMode(show, only)
+	defer SetIDMode(false, false)
+
+	var buf bytes.Buffer
+	err := NewTablePrinter().
+		WithWriter(&buf).
+		WithColumns(
+			Column{Title: "NAME", Key: "name"},
+			Column{Title: "POWER", Key: "power"},
+		).
+		AddItems([]map[string]interface{}{
+			{"name": "web", "id": "vm-101", "power": "On"},
+			{"name": "db", "id": "vm-102", "power": "Off"},
+		}).
+		PrintMarkdown()
+	if err != nil {
+		t.Fatalf("PrintMarkdown returned error: %v", err)
+	}
+	return buf.String()
+}
+
+func TestShowIDs(t *testing.T) {
+	if out := printIDTable(t, false, false); strings.Contains(out, "vm-101") {
+		t.Errorf("default table shows IDs:\n%s", out)
+	}
+	out := printIDTable(t, true, false)
+	if !strings.HasPrefix(out, "| NAME | ID | POWER |") || !strings.Contains(out, "| web | vm-101 | On |") {
+		t.Errorf("--show-ids table = \n%s\nwant an ID column after NAME", out)
+	}
+}
+
+func TestIDsOnly(t *testing.T) {
+	if out := printIDTable(t, false, true); out != "vm-101\nvm-102\n" {
+		t.Errorf("--ids-only output = %q, want one ID per line", out)
+	}
+
+	SetIDMode(false, true)
+	defer SetIDMode(false, false)
+	err := NewTablePrinter().
+		WithWriter(&bytes.Buffer{}).
+		WithColumns(Column{Title: "NAME", Key: "name"}).
+		AddItem(map[string]interface{}{"name": "web"}).
+		Print()
+	if err == nil || !strings.Contains(err.Error(), "no ID") {
+		t.Errorf("Print() error = %v, want an error for rows without IDs", err)
+	}
+}
@@ -0,0 +1,54 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// FormatJSONStream is the output format writing newline-delimited JSON (NDJSON): one compact
+// object per line, printed as soon as it is available instead of as a single array
+const FormatJSONStream = "json-stream"
+
+// JSONStreamPrinter prints items as newline-delimited JSON as they are added, so a list of
+// any length is printed in constant memory
+type JSONStreamPrinter struct {
+	encoder *json.Encoder
+	count   int
+}
+
+// NewJSONStreamPrinter creates a new JSONStreamPrinter writing to stdout
+func NewJSONStreamPrinter() *JSONStreamPrinter {
+	return (&JSONStreamPrinter{}).WithWriter(os.Stdout)
+}
+
+// WithWriter sets the output writer
+func (j *JSONStreamPrinter) WithWriter(writer io.Writer) *JSONStreamPrinter {
+	j.encoder = json.NewEncoder(writer)
+	return j
+}
+
+// Print writes one item as a line of JSON
+func (j *JSONStreamPrinter) Print(item map[string]interface{}) error {
+	if err := j.encoder.Encode(item); err != nil {
+		return fmt.Errorf("failed to marshal JSON: %v", err)
+	}
+	j.count++
+	return nil
+}
+
+// PrintItems writes each item as a line of JSON
+func (j *JSONStreamPrinter) PrintItems(items []map[string]interface{}) error {
+	for _, item := range items {
+		if err := j.Print(item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Count returns the number of items printed
+func (j *JSONStreamPrinter) Count() int {
+	return j.count
+}
@@ -0,0 +1,27 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestJSONStreamPrinter(t *testing.T) {
+	var out bytes.Buffer
+	printer := NewJSONStreamPrinter().WithWriter(&out)
+
+	items := []map[string]interface{}{
+		{"name": "db-1", "cpuCount": 4},
+		{"name": "web-1", "disks": []interface{}{map[string]interface{}{"capacity": 10}}},
+	}
+	if err := printer.PrintItems(items); err != nil {
+		t.Fatalf("PrintItems() error = %v", err)
+	}
+
+	want := `{"cpuCount":4,"name":"db-1"}` + "\n" + `{"disks":[{"capacity":10}],"name":"web-1"}` + "\n"
+	if out.String() != want {
+		t.Errorf("output = %q, want %q", out.String(), want)
+	}
+	if printer.Count() != 2 {
+		t.Errorf("Count() = %d, want 2", printer.Count())
+	}
+}
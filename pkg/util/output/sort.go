@@ -0,0 +1,67 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/yaacov/kubectl-mtv/pkg/util/query"
+)
+
+// sortField and sortDescending are the global table ordering, set from the
+// --sort-by and --desc flags before any command runs.
+var (
+	sortField      = ""
+	sortDescending = false
+)
+
+// SetSortBy globally sorts printed tables by field: a column title (e.g. "MEMORY")
+// or a JSON field path of the items (e.g. "memoryMB" or ".spec.targetNamespace").
+// An empty field keeps the order of the command.
+func SetSortBy(field string, descending bool) {
+	sortField = strings.TrimSpace(field)
+	sortDescending = descending
+}
+
+// sortItems orders the table rows by the global sort field. Rows missing the
+// field keep their relative order.
+func (t *TablePrinter) sortItems() error {
+	if sortField == "" || len(t.items) == 0 {
+		return nil
+	}
+
+	key := t.sortKey(sortField)
+	found := false
+	for _, item := range t.items {
+		if val, err := query.GetValue(item, key, t.selectOptions); err == nil && val != nil {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return fmt.Errorf("cannot sort by '%s': no column or field with that name", sortField)
+	}
+
+	sorted, err := query.SortItems(t.items, &query.QueryOptions{
+		Select: t.selectOptions,
+		OrderBy: []query.OrderOption{{
+			Field:      query.SelectOption{Field: key, Alias: key},
+			Descending: sortDescending,
+		}},
+	})
+	if err != nil {
+		return err
+	}
+	t.items = sorted
+	return nil
+}
+
+// sortKey resolves a sort field to the key used to extract values: the key of the
+// column with a matching title, otherwise the field itself as a JSON path.
+func (t *TablePrinter) sortKey(field string) string {
+	for _, c := range t.columns {
+		if c.Key != "" && strings.EqualFold(c.Title, field) {
+			return c.Key
+		}
+	}
+	return strings.TrimPrefix(field, ".")
+}
@@ -0,0 +1,70 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func sortedMarkdownRows(t *testing.T, field string, descending bool) []string {
+	t.Helper()
+	SetSortBy(field, descending)
+	defer SetSortBy("", false)
+
+	var buf bytes.Buffer
+	err := NewTablePrinter().
+		WithWriter(&buf).
+		WithColumns(
+			Column{Title: "NAME", Key: "name"},
+			Column{Title: "MEMORY", Key: "memory"},
+		).
+		AddItems([]map[string]interface{}{
+			{"name": "web", "memory": "512 MB", "memoryMB": 512, "spec": map[string]interface{}{"cpus": 4}},
+			{"name": "db", "memory": "16384 MB", "memoryMB": 16384, "spec": map[string]interface{}{"cpus": 8}},
+			{"name": "cache", "memory": "2048 MB", "memoryMB": 2048, "spec": map[string]interface{}{"cpus": 2}},
+		}).
+		PrintMarkdown()
+	if err != nil {
+		t.Fatalf("PrintMarkdown returned error: %v", err)
+	}
+
+	var names []string
+	for _, line := range strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")[2:] {
+		names = append(names, strings.TrimSpace(strings.Split(line, "|")[1]))
+	}
+	return names
+}
+
+func TestSortBy(t *testing.T) {
+	tests := []struct {
+		field      string
+		descending bool
+		want       string
+	}{
+		{"", false, "web,db,cache"},
+		{"name", false, "cache,db,web"},
+		{"NAME", true, "web,db,cache"},
+		{"memoryMB", true, "db,cache,web"},
+		{".spec.cpus", false, "cache,web,db"},
+	}
+	for _, tt := range tests {
+		if got := strings.Join(sortedMarkdownRows(t, tt.field, tt.descending), ","); got != tt.want {
+			t.Errorf("sort by %q (desc %t) = %s, want %s", tt.field, tt.descending, got, tt.want)
+		}
+	}
+}
+
+func TestSortByUnknownField(t *testing.T) {
+	SetSortBy("diskGB", false)
+	defer SetSortBy("", false)
+
+	var buf bytes.Buffer
+	err := NewTablePrinter().
+		WithWriter(&buf).
+		WithColumns(Column{Title: "NAME", Key: "name"}).
+		AddItem(map[string]interface{}{"name": "web"}).
+		Print()
+	if err == nil || !strings.Contains(err.Error(), "diskGB") {
+		t.Errorf("Print() error = %v, want an error naming the unknown field", err)
+	}
+}
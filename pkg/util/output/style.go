@@ -20,8 +20,8 @@ var (
 )
 
 // colorEnabled controls whether ANSI color codes are emitted.
-// Defaults to true; set to false via SetColorEnabled for terminals that
-// don't support colors or when the --no-color flag / NO_COLOR env var is set.
+// Defaults to true; set to false via SetColorEnabled when stdout is not a
+// terminal or when the --no-color flag / NO_COLOR env var is set.
 var colorEnabled = true
 
 // SetColorEnabled globally enables or disables ANSI color output.
@@ -108,25 +108,6 @@ func Separator(length int, colorFn func(string) string) string {
 // Semantic colorizers
 // ---------------------------------------------------------------------------
 
-// ColorizeStatus returns a colored string based on status value.
-func ColorizeStatus(status string) string {
-	status = strings.TrimSpace(status)
-	switch strings.ToLower(status) {
-	case "running", "executing", "in-use":
-		return Blue(status)
-	case "completed", "succeeded", "ready", "available", "bound":
-		return Green(status)
-	case "pending", "stopped", "stopping", "creating", "unknown":
-		return Yellow(status)
-	case "failed", "not ready", "terminated", "shutting-down", "deleting", "error", "lost":
-		return Red(status)
-	case "canceled":
-		return Cyan(status)
-	default:
-		return status
-	}
-}
-
 // ColorizeCategory returns a colored string based on condition category.
 func ColorizeCategory(category string) string {
 	category = strings.TrimSpace(category)
@@ -148,9 +129,11 @@ func ColorizePowerState(state string) string {
 	switch strings.ToLower(state) {
 	case "running", "on":
 		return Green(state)
-	case "stopped", "off":
+	case "stopped", "off", "suspended", "paused":
 		return Yellow(state)
-	case "not found":
+	case "starting", "stopping":
+		return Blue(state)
+	case "not found", "error":
 		return Red(state)
 	default:
 		return state
@@ -223,21 +206,3 @@ func ColorizeImage(image string) string {
 		return Yellow(image)
 	}
 }
-
-// ColorizeProgress returns a colored string based on percentage thresholds.
-func ColorizeProgress(progress string) string {
-	trimmed := strings.TrimSpace(progress)
-	numStr := strings.TrimRight(trimmed, "%")
-	pct, err := strconv.ParseFloat(strings.TrimSpace(numStr), 64)
-	if err != nil {
-		return progress
-	}
-	if pct >= 100 {
-		return Green(progress)
-	} else if pct >= 75 {
-		return Blue(progress)
-	} else if pct >= 25 {
-		return Yellow(progress)
-	}
-	return Cyan(progress)
-}
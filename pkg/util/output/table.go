@@ -70,13 +70,24 @@ func (t *TablePrinter) AddItems(items []map[string]interface{}) *TablePrinter {
 }
 
 // PrintEmpty prints a message when there are no items to display.
+// Nothing is printed when only IDs are printed, so scripts read no IDs.
 func (t *TablePrinter) PrintEmpty(message string) error {
+	if idsOnly {
+		return nil
+	}
 	_, err := fmt.Fprintln(t.writer, message)
 	return err
 }
 
 // Print renders the table to the configured writer.
 func (t *TablePrinter) Print() error {
+	if err := t.sortItems(); err != nil {
+		return err
+	}
+	if printed, err := t.applyIDMode(); printed || err != nil {
+		return err
+	}
+
 	headers, rows := t.buildTable()
 	if len(headers) == 0 {
 		return nil
@@ -110,6 +121,12 @@ func (t *TablePrinter) PrintMarkdown() error {
 	if len(t.columns) == 0 {
 		return nil
 	}
+	if err := t.sortItems(); err != nil {
+		return err
+	}
+	if printed, err := t.applyIDMode(); printed || err != nil {
+		return err
+	}
 
 	headers := make([]string, len(t.columns))
 	for i, c := range t.columns {
@@ -0,0 +1,152 @@
+package output
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/template"
+
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// templateFormats are the output format prefixes taking a template, like kubectl -o
+var templateFormats = []string{"jsonpath=", "jsonpath-file=", "go-template=", "go-template-file="}
+
+// IsTemplateFormat reports whether an output format is a jsonpath or go-template expression
+func IsTemplateFormat(format string) bool {
+	for _, prefix := range templateFormats {
+		if strings.HasPrefix(format, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// TemplatePrinter prints data through a jsonpath or go-template expression. Lists are
+// wrapped in a kubectl style List object, so {.items[*].metadata.name} works as with kubectl.
+type TemplatePrinter struct {
+	kind     string
+	template string
+	writer   io.Writer
+}
+
+// NewTemplatePrinter creates a TemplatePrinter from an output format such as
+// "jsonpath={.items[*].metadata.name}" or "go-template-file=report.tmpl"
+func NewTemplatePrinter(format string) (*TemplatePrinter, error) {
+	kind, expr, found := strings.Cut(format, "=")
+	if !found || !IsTemplateFormat(format) {
+		return nil, fmt.Errorf("invalid template output format: %s", format)
+	}
+
+	if file, ok := strings.CutSuffix(kind, "-file"); ok {
+		data, err := os.ReadFile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read template file: %v", err)
+		}
+		kind, expr = file, string(data)
+	}
+	if strings.TrimSpace(expr) == "" {
+		return nil, fmt.Errorf("%s template is empty", kind)
+	}
+
+	return &TemplatePrinter{kind: kind, template: expr, writer: os.Stdout}, nil
+}
+
+// WithWriter sets the output writer
+func (t *TemplatePrinter) WithWriter(writer io.Writer) *TemplatePrinter {
+	t.writer = writer
+	return t
+}
+
+// Print renders the data through the template
+func (t *TemplatePrinter) Print(data interface{}) error {
+	obj, err := templateData(data)
+	if err != nil {
+		return err
+	}
+
+	if t.kind == "jsonpath" {
+		return t.printJSONPath(obj)
+	}
+	return t.printGoTemplate(obj)
+}
+
+// printJSONPath renders a jsonpath expression, missing keys print as empty like kubectl
+func (t *TemplatePrinter) printJSONPath(obj interface{}) error {
+	expr := t.template
+	// Accept relaxed expressions such as .items[*].metadata.name
+	if !strings.Contains(expr, "{") {
+		expr = "{" + expr + "}"
+	}
+
+	parser := jsonpath.New("output").AllowMissingKeys(true)
+	if err := parser.Parse(expr); err != nil {
+		return fmt.Errorf("failed to parse jsonpath template: %v", err)
+	}
+	if err := parser.Execute(t.writer, obj); err != nil {
+		return fmt.Errorf("failed to execute jsonpath template: %v", err)
+	}
+	return nil
+}
+
+// printGoTemplate renders a go-template, with the base64decode helper kubectl provides
+func (t *TemplatePrinter) printGoTemplate(obj interface{}) error {
+	funcs := template.FuncMap{
+		"base64decode": func(s string) (string, error) {
+			decoded, err := base64.StdEncoding.DecodeString(s)
+			return string(decoded), err
+		},
+	}
+	tmpl, err := template.New("output").Funcs(funcs).Parse(t.template)
+	if err != nil {
+		return fmt.Errorf("failed to parse go-template: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, obj); err != nil {
+		return fmt.Errorf("failed to execute go-template: %v", err)
+	}
+	_, err = t.writer.Write(buf.Bytes())
+	return err
+}
+
+// templateData converts data to plain JSON values, wrapping lists in a List object
+func templateData(data interface{}) (interface{}, error) {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal JSON: %v", err)
+	}
+	var obj interface{}
+	if err := json.Unmarshal(b, &obj); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal JSON: %v", err)
+	}
+
+	switch v := obj.(type) {
+	case []interface{}:
+		return map[string]interface{}{"apiVersion": "v1", "kind": "List", "items": v}, nil
+	case nil:
+		return map[string]interface{}{"apiVersion": "v1", "kind": "List", "items": []interface{}{}}, nil
+	}
+	return obj, nil
+}
+
+// PrintTemplate prints data through the jsonpath or go-template of an output format
+func PrintTemplate(data interface{}, format string) error {
+	printer, err := NewTemplatePrinter(format)
+	if err != nil {
+		return err
+	}
+	return printer.Print(data)
+}
+
+// NormalizeFormat lowercases an output format name, templates are kept as given
+func NormalizeFormat(format string) string {
+	if IsTemplateFormat(format) {
+		return format
+	}
+	return strings.ToLower(format)
+}
@@ -0,0 +1,73 @@
+package output
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTemplatePrinter(t *testing.T) {
+	items := []map[string]interface{}{
+		{"metadata": map[string]interface{}{"name": "plan-a"}, "status": map[string]interface{}{"phase": "Ready"}},
+		{"metadata": map[string]interface{}{"name": "plan-b"}},
+	}
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "names.tmpl")
+	if err := os.WriteFile(file, []byte(`{{range .items}}{{.metadata.name}};{{end}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{`jsonpath={.items[*].metadata.name}`, "plan-a plan-b"},
+		{`jsonpath=.items[0].metadata.name`, "plan-a"},
+		{`jsonpath={range .items[*]}{.metadata.name}={.status.phase}{"\n"}{end}`, "plan-a=Ready\nplan-b=\n"},
+		{`go-template={{range .items}}{{.metadata.name}} {{end}}`, "plan-a plan-b "},
+		{`go-template={{len .items}}`, "2"},
+		{"go-template-file=" + file, "plan-a;plan-b;"},
+	}
+	for _, tt := range tests {
+		printer, err := NewTemplatePrinter(tt.format)
+		if err != nil {
+			t.Errorf("NewTemplatePrinter(%q) error: %v", tt.format, err)
+			continue
+		}
+		var buf bytes.Buffer
+		if err := printer.WithWriter(&buf).Print(items); err != nil {
+			t.Errorf("Print(%q) error: %v", tt.format, err)
+			continue
+		}
+		if buf.String() != tt.want {
+			t.Errorf("Print(%q) = %q, want %q", tt.format, buf.String(), tt.want)
+		}
+	}
+}
+
+func TestTemplatePrinterErrors(t *testing.T) {
+	for _, format := range []string{"json", "jsonpath=", "jsonpath-file=/does/not/exist"} {
+		if _, err := NewTemplatePrinter(format); err == nil {
+			t.Errorf("NewTemplatePrinter(%q) expected error", format)
+		}
+	}
+
+	printer, err := NewTemplatePrinter("jsonpath={.items[")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := printer.WithWriter(&bytes.Buffer{}).Print([]map[string]interface{}{}); err == nil {
+		t.Error("expected a jsonpath parse error")
+	}
+}
+
+func TestNormalizeFormat(t *testing.T) {
+	if got := NormalizeFormat("JSON"); got != "json" {
+		t.Errorf("NormalizeFormat(JSON) = %q", got)
+	}
+	if got := NormalizeFormat("jsonpath={.items[*].metadata.Name}"); got != "jsonpath={.items[*].metadata.Name}" {
+		t.Errorf("NormalizeFormat kept template = %q", got)
+	}
+}
@@ -0,0 +1,146 @@
+package output
+
+import (
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ---------------------------------------------------------------------------
+// Table theme: status icons and progress bars
+// ---------------------------------------------------------------------------
+
+// StatusLevel classifies a status value; it picks the color and icon of the status.
+type StatusLevel int
+
+const (
+	StatusLevelNone StatusLevel = iota
+	StatusLevelOK
+	StatusLevelRunning
+	StatusLevelWarning
+	StatusLevelError
+	StatusLevelCanceled
+)
+
+// statusTheme is the color and icon of each status level. Icons are single-width
+// characters so that table columns stay aligned.
+var statusTheme = map[StatusLevel]struct {
+	color func(string) string
+	icon  string
+}{
+	StatusLevelOK:       {Green, "✔"},
+	StatusLevelRunning:  {Blue, "▶"},
+	StatusLevelWarning:  {Yellow, "▲"},
+	StatusLevelError:    {Red, "✖"},
+	StatusLevelCanceled: {Cyan, "⊘"},
+}
+
+// ProgressBarWidth is the number of cells of a progress bar.
+const ProgressBarWidth = 10
+
+// watchMode tells whether output is rendered for the watch screen, where percent
+// cells are shown as progress bars.
+var watchMode = false
+
+// SetWatchMode enables or disables progress bars in percent cells.
+func SetWatchMode(enabled bool) { watchMode = enabled }
+
+// IsWatchMode reports whether output is rendered for the watch screen.
+func IsWatchMode() bool { return watchMode }
+
+// IsTerminal reports whether f is a terminal. Color is only enabled when stdout is one,
+// so piped or redirected output carries no ANSI codes or icons.
+func IsTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// ClassifyStatus returns the level of a status value.
+func ClassifyStatus(status string) StatusLevel {
+	switch strings.ToLower(strings.TrimSpace(status)) {
+	case "running", "executing", "in-use":
+		return StatusLevelRunning
+	case "completed", "succeeded", "ready", "available", "bound":
+		return StatusLevelOK
+	case "pending", "stopped", "stopping", "creating", "unknown":
+		return StatusLevelWarning
+	case "failed", "not ready", "terminated", "shutting-down", "deleting", "error", "lost":
+		return StatusLevelError
+	case "canceled":
+		return StatusLevelCanceled
+	default:
+		return StatusLevelNone
+	}
+}
+
+// StatusIcon returns the icon of a status value, or "" for unknown values.
+func StatusIcon(status string) string {
+	return statusTheme[ClassifyStatus(status)].icon
+}
+
+// ColorizeStatus returns a status colored by its level and prefixed with its icon.
+// Without color the status is returned unchanged, so scripts see the plain value.
+func ColorizeStatus(status string) string {
+	status = strings.TrimSpace(status)
+	theme, ok := statusTheme[ClassifyStatus(status)]
+	if !ok || !colorEnabled {
+		return status
+	}
+	return theme.color(theme.icon + " " + status)
+}
+
+// percentPattern matches the first percentage of a progress value, e.g. "42.5%" in
+// "42.5% (10/24 GB)" or "CopyDisks (42%)".
+var percentPattern = regexp.MustCompile(`(\d+(?:\.\d+)?)\s*%`)
+
+// ParsePercent returns the first percentage of a progress value.
+func ParsePercent(progress string) (float64, bool) {
+	match := percentPattern.FindStringSubmatch(progress)
+	if match == nil {
+		return 0, false
+	}
+	pct, err := strconv.ParseFloat(match[1], 64)
+	return pct, err == nil
+}
+
+// ProgressBar renders a percentage as a bar of width cells, e.g. "████░░░░░░".
+func ProgressBar(pct float64, width int) string {
+	if pct < 0 {
+		pct = 0
+	}
+	if pct > 100 {
+		pct = 100
+	}
+	filled := int(pct/100*float64(width) + 0.5)
+	return strings.Repeat("█", filled) + strings.Repeat("░", width-filled)
+}
+
+// ColorizeProgress returns a colored string based on percentage thresholds. In watch
+// mode a progress bar is shown before the value. Values without a percentage, such as
+// "Completed", are colored as statuses.
+func ColorizeProgress(progress string) string {
+	pct, ok := ParsePercent(progress)
+	if !ok {
+		return ColorizeStatus(progress)
+	}
+	if !colorEnabled {
+		return progress
+	}
+
+	color := Cyan
+	switch {
+	case pct >= 100:
+		color = Green
+	case pct >= 75:
+		color = Blue
+	case pct >= 25:
+		color = Yellow
+	}
+
+	text := strings.TrimSpace(progress)
+	if watchMode {
+		text = ProgressBar(pct, ProgressBarWidth) + " " + text
+	}
+	return color(text)
+}
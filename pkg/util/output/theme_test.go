@@ -0,0 +1,97 @@
+package output
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestColorizeStatus(t *testing.T) {
+	defer SetColorEnabled(IsColorEnabled())
+
+	SetColorEnabled(true)
+	tests := []struct {
+		status string
+		icon   string
+	}{
+		{"Succeeded", "✔"},
+		{"Failed", "✖"},
+		{"Pending", "▲"},
+		{"Running", "▶"},
+		{"Canceled", "⊘"},
+	}
+	for _, tt := range tests {
+		got := StripANSI(ColorizeStatus(tt.status))
+		if got != tt.icon+" "+tt.status {
+			t.Errorf("ColorizeStatus(%q) = %q, want %q", tt.status, got, tt.icon+" "+tt.status)
+		}
+	}
+	if got := ColorizeStatus("Archived"); got != "Archived" {
+		t.Errorf("ColorizeStatus(Archived) = %q, want the unknown status unchanged", got)
+	}
+
+	SetColorEnabled(false)
+	if got := ColorizeStatus("Failed"); got != "Failed" {
+		t.Errorf("ColorizeStatus without color = %q, want no icon", got)
+	}
+}
+
+func TestParsePercent(t *testing.T) {
+	tests := []struct {
+		value string
+		want  float64
+		ok    bool
+	}{
+		{"42.5% (10/24 GB)", 42.5, true},
+		{"CopyDisks (42%)", 42, true},
+		{"100 %", 100, true},
+		{"Completed", 0, false},
+		{"-", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := ParsePercent(tt.value)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("ParsePercent(%q) = %v, %v, want %v, %v", tt.value, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestProgressBar(t *testing.T) {
+	tests := []struct {
+		pct  float64
+		want string
+	}{
+		{0, "░░░░░░░░░░"},
+		{42, "████░░░░░░"},
+		{100, "██████████"},
+		{150, "██████████"},
+	}
+	for _, tt := range tests {
+		if got := ProgressBar(tt.pct, 10); got != tt.want {
+			t.Errorf("ProgressBar(%v) = %q, want %q", tt.pct, got, tt.want)
+		}
+	}
+}
+
+func TestColorizeProgressWatchMode(t *testing.T) {
+	defer SetColorEnabled(IsColorEnabled())
+	defer SetWatchMode(IsWatchMode())
+
+	SetColorEnabled(true)
+	SetWatchMode(false)
+	if got := StripANSI(ColorizeProgress("50.0% (1/2 GB)")); got != "50.0% (1/2 GB)" {
+		t.Errorf("ColorizeProgress() = %q, want no bar outside watch mode", got)
+	}
+
+	SetWatchMode(true)
+	if got := StripANSI(ColorizeProgress("50.0% (1/2 GB)")); got != "█████░░░░░ 50.0% (1/2 GB)" {
+		t.Errorf("ColorizeProgress() in watch mode = %q, want a bar", got)
+	}
+	if got := StripANSI(ColorizeProgress("Completed")); !strings.HasPrefix(got, "✔") {
+		t.Errorf("ColorizeProgress(Completed) = %q, want a status icon", got)
+	}
+
+	SetColorEnabled(false)
+	if got := ColorizeProgress("50.0% (1/2 GB)"); got != "50.0% (1/2 GB)" {
+		t.Errorf("ColorizeProgress() without color = %q, want the plain value", got)
+	}
+}
@@ -1,9 +1,57 @@
 package output
 
 import (
+	"fmt"
+	"strings"
 	"time"
 )
 
+// DefaultTimeLayout is the layout used to print timestamps unless --time-format is set
+const DefaultTimeLayout = "2006-01-02 15:04:05"
+
+// timeFormatPresets are the named layouts accepted by SetTimeFormat
+var timeFormatPresets = map[string]string{
+	"default": DefaultTimeLayout,
+	"rfc3339": time.RFC3339,
+	"iso":     "2006-01-02T15:04:05Z07:00",
+	"short":   "01-02 15:04",
+	"date":    "2006-01-02",
+	"kitchen": time.Kitchen,
+}
+
+// TimeFormatHelp describes the values accepted by SetTimeFormat
+const TimeFormatHelp = "default, rfc3339, iso, short, date, kitchen, or a Go time layout (e.g. \"Jan 2 15:04 MST\")"
+
+// timeLayout and forceUTC are the global timestamp settings, set from the
+// --time-format and --utc flags before any command runs.
+var (
+	timeLayout = DefaultTimeLayout
+	forceUTC   = false
+)
+
+// SetTimeFormat sets the global layout used for printed timestamps. The value is
+// a preset name or a Go time layout; an empty value restores the default.
+func SetTimeFormat(format string) error {
+	if format == "" {
+		timeLayout = DefaultTimeLayout
+		return nil
+	}
+	if layout, ok := timeFormatPresets[strings.ToLower(format)]; ok {
+		timeLayout = layout
+		return nil
+	}
+	// A Go layout must reference the reference time, otherwise every timestamp prints the same text
+	probe := time.Date(2001, 2, 3, 4, 5, 6, 0, time.UTC)
+	if probe.Format(format) == format {
+		return fmt.Errorf("invalid time format '%s': use %s", format, TimeFormatHelp)
+	}
+	timeLayout = format
+	return nil
+}
+
+// SetUTC globally prints timestamps in UTC instead of the local timezone.
+func SetUTC(utc bool) { forceUTC = utc }
+
 // FormatTime formats a timestamp string with optional UTC conversion
 func FormatTime(timestamp string, useUTC bool) string {
 	if timestamp == "" {
@@ -16,26 +64,26 @@ func FormatTime(timestamp string, useUTC bool) string {
 		return timestamp
 	}
 
-	// Convert to UTC or local time as requested
-	if useUTC {
-		t = t.UTC()
-	} else {
-		t = t.Local()
-	}
-
-	// Format as "2006-01-02 15:04:05"
-	return t.Format("2006-01-02 15:04:05")
+	return FormatTimestamp(t, useUTC)
 }
 
 // FormatTimestamp formats a time.Time object with optional UTC conversion
 func FormatTimestamp(timestamp time.Time, useUTC bool) string {
 	// Convert to UTC or local time as requested
-	if useUTC {
+	if useUTC || forceUTC {
 		timestamp = timestamp.UTC()
 	} else {
 		timestamp = timestamp.Local()
 	}
 
-	// Format as "2006-01-02 15:04:05"
-	return timestamp.Format("2006-01-02 15:04:05")
+	return timestamp.Format(timeLayout)
+}
+
+// FormatTimeCell formats an RFC3339 table cell using the global timestamp settings.
+// Values that are not timestamps are returned unchanged. It can be used as a Column ColorFunc.
+func FormatTimeCell(value string) string {
+	if value == "" {
+		return value
+	}
+	return FormatTime(value, false)
 }
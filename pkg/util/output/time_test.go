@@ -0,0 +1,63 @@
+package output
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormatTimestampWithTimeFormat(t *testing.T) {
+	defer func() {
+		_ = SetTimeFormat("")
+		SetUTC(false)
+	}()
+
+	ts := time.Date(2026, 3, 4, 5, 6, 7, 0, time.FixedZone("EST", -5*3600))
+	SetUTC(true)
+
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{format: "", want: "2026-03-04 10:06:07"},
+		{format: "default", want: "2026-03-04 10:06:07"},
+		{format: "rfc3339", want: "2026-03-04T10:06:07Z"},
+		{format: "RFC3339", want: "2026-03-04T10:06:07Z"},
+		{format: "short", want: "03-04 10:06"},
+		{format: "date", want: "2026-03-04"},
+		{format: "Jan 2 15:04 MST", want: "Mar 4 10:06 UTC"},
+	}
+	for _, tt := range tests {
+		if err := SetTimeFormat(tt.format); err != nil {
+			t.Fatalf("SetTimeFormat(%q) unexpected error: %v", tt.format, err)
+		}
+		if got := FormatTimestamp(ts, false); got != tt.want {
+			t.Errorf("FormatTimestamp() with format %q = %q, want %q", tt.format, got, tt.want)
+		}
+	}
+}
+
+func TestSetTimeFormatInvalid(t *testing.T) {
+	defer func() { _ = SetTimeFormat("") }()
+
+	if err := SetTimeFormat("not a layout"); err == nil {
+		t.Error("SetTimeFormat() expected error for a layout without time fields")
+	}
+	if got := FormatTimestamp(time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC), true); got != "2026-01-02 03:04:05" {
+		t.Errorf("FormatTimestamp() after invalid format = %q, want default layout", got)
+	}
+}
+
+func TestFormatTimeCell(t *testing.T) {
+	defer SetUTC(false)
+	SetUTC(true)
+
+	if got := FormatTimeCell("2026-01-02T03:04:05Z"); got != "2026-01-02 03:04:05" {
+		t.Errorf("FormatTimeCell() = %q", got)
+	}
+	if got := FormatTimeCell("not-a-time"); got != "not-a-time" {
+		t.Errorf("FormatTimeCell() = %q, want value unchanged", got)
+	}
+	if got := FormatTimeCell(""); got != "" {
+		t.Errorf("FormatTimeCell(\"\") = %q, want empty", got)
+	}
+}
@@ -0,0 +1,138 @@
+// Package progress emits machine-readable progress events of long operations as
+// JSON lines on stderr, so wrapping tools can show progress instead of a silent wait.
+package progress
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Progress output modes, set with the global --progress flag
+const (
+	ModeNone = "none"
+	ModeJSON = "json"
+)
+
+// EventType marks the JSON lines written by this package, telling them apart from
+// other stderr output
+const EventType = "progress"
+
+// minStepInterval limits how often unfinished steps are reported, so loops over
+// thousands of items do not flood stderr
+const minStepInterval = 250 * time.Millisecond
+
+var (
+	mu      sync.Mutex
+	enabled           = false
+	out     io.Writer = os.Stderr
+	now               = time.Now
+)
+
+// SetMode enables JSON progress events ("json") or disables them ("none" or empty)
+func SetMode(mode string) error {
+	mu.Lock()
+	defer mu.Unlock()
+	switch strings.ToLower(strings.TrimSpace(mode)) {
+	case ModeJSON:
+		enabled = true
+	case ModeNone, "":
+		enabled = false
+	default:
+		return fmt.Errorf("invalid --progress '%s': use %s or %s", mode, ModeNone, ModeJSON)
+	}
+	return nil
+}
+
+// Enabled reports whether progress events are written
+func Enabled() bool {
+	mu.Lock()
+	defer mu.Unlock()
+	return enabled
+}
+
+// Event is one progress line
+type Event struct {
+	Type      string    `json:"type"`
+	Time      time.Time `json:"time"`
+	Operation string    `json:"operation"`
+	// Resource is the name of the resource the operation works on, if any
+	Resource string `json:"resource,omitempty"`
+	Stage    string `json:"stage,omitempty"`
+	Message  string `json:"message,omitempty"`
+	// Current counts the items of the stage done so far, out of Total when known
+	Current int  `json:"current"`
+	Total   int  `json:"total,omitempty"`
+	Done    bool `json:"done,omitempty"`
+	// Error is set on the final event of a failed operation
+	Error string `json:"error,omitempty"`
+}
+
+// ParseEvent returns the event of a progress line, and false for any other line
+func ParseEvent(line string) (Event, bool) {
+	var e Event
+	line = strings.TrimSpace(line)
+	if !strings.HasPrefix(line, "{") || json.Unmarshal([]byte(line), &e) != nil || e.Type != EventType {
+		return Event{}, false
+	}
+	return e, true
+}
+
+// Tracker reports the progress of one operation. Its methods do nothing unless
+// progress events are enabled.
+type Tracker struct {
+	event    Event
+	lastEmit time.Time
+}
+
+// Start begins reporting an operation, e.g. Start("create plan", "wave-1")
+func Start(operation, resource string) *Tracker {
+	t := &Tracker{event: Event{Type: EventType, Operation: operation, Resource: resource, Stage: "start"}}
+	t.emit()
+	return t
+}
+
+// Stage starts a new stage of total items (0 when not counted)
+func (t *Tracker) Stage(stage, message string, total int) {
+	t.event.Stage, t.event.Message = stage, message
+	t.event.Current, t.event.Total = 0, total
+	t.emit()
+}
+
+// Step counts one more item of the current stage. Steps are reported at most every
+// minStepInterval, and always for the first and the last item.
+func (t *Tracker) Step(message string) {
+	t.event.Current++
+	t.event.Message = message
+	if t.event.Current == 1 || t.event.Current == t.event.Total || now().Sub(t.lastEmit) >= minStepInterval {
+		t.emit()
+	}
+}
+
+// Done ends the operation, reporting err when it failed
+func (t *Tracker) Done(err error) {
+	t.event.Stage, t.event.Message, t.event.Done = "done", "", true
+	if err != nil {
+		t.event.Error = err.Error()
+	}
+	t.emit()
+}
+
+func (t *Tracker) emit() {
+	mu.Lock()
+	defer mu.Unlock()
+	if !enabled {
+		return
+	}
+	t.lastEmit = now()
+	t.event.Time = t.lastEmit.UTC()
+	data, err := json.Marshal(t.event)
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(out, "%s\n", data)
+}
@@ -0,0 +1,87 @@
+package progress
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func captureEvents(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	prevOut, prevNow := out, now
+	out = &buf
+	t.Cleanup(func() {
+		out, now = prevOut, prevNow
+		_ = SetMode(ModeNone)
+	})
+	return &buf
+}
+
+func parseAll(t *testing.T, buf *bytes.Buffer) []Event {
+	t.Helper()
+	var events []Event
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		e, ok := ParseEvent(line)
+		if !ok {
+			t.Fatalf("not a progress event: %q", line)
+		}
+		events = append(events, e)
+	}
+	return events
+}
+
+func TestTrackerEvents(t *testing.T) {
+	buf := captureEvents(t)
+	if err := SetMode(ModeJSON); err != nil {
+		t.Fatal(err)
+	}
+	clock := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	now = func() time.Time { return clock }
+
+	tracker := Start("create plan", "wave-1")
+	tracker.Stage("resolve-vms", "resolving VMs", 3)
+	tracker.Step("web-01")
+	tracker.Step("web-02") // throttled, same clock
+	tracker.Step("db-01")  // last item, always reported
+	tracker.Done(errors.New("boom"))
+
+	events := parseAll(t, buf)
+	if len(events) != 5 {
+		t.Fatalf("got %d events, want 5:\n%s", len(events), buf.String())
+	}
+	if events[0].Stage != "start" || events[0].Resource != "wave-1" || events[0].Operation != "create plan" {
+		t.Errorf("start event = %+v", events[0])
+	}
+	if step := events[3]; step.Current != 3 || step.Total != 3 || step.Message != "db-01" {
+		t.Errorf("last step event = %+v, want 3/3 db-01", step)
+	}
+	if last := events[4]; !last.Done || last.Error != "boom" {
+		t.Errorf("done event = %+v, want done with the error", last)
+	}
+}
+
+func TestDisabledWritesNothing(t *testing.T) {
+	buf := captureEvents(t)
+	tracker := Start("delete plan", "")
+	tracker.Step("a")
+	tracker.Done(nil)
+	if buf.Len() != 0 {
+		t.Errorf("disabled progress wrote %q", buf.String())
+	}
+}
+
+func TestSetModeAndParse(t *testing.T) {
+	captureEvents(t)
+	if err := SetMode("xml"); err == nil {
+		t.Error("SetMode(xml) expected an error")
+	}
+	if _, ok := ParseEvent("Error: plan not found"); ok {
+		t.Error("ParseEvent accepted a plain stderr line")
+	}
+	if _, ok := ParseEvent(`{"type":"other"}`); ok {
+		t.Error("ParseEvent accepted JSON of another type")
+	}
+}
@@ -0,0 +1,82 @@
+// Package prompt asks the user to confirm destructive commands before they change anything.
+// The resources affected are shown first; --yes or the KUBECTL_MTV_ASSUME_YES environment
+// variable skip the question, and without a terminal the command is refused so scripts must
+// opt in explicitly.
+package prompt
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/yaacov/kubectl-mtv/pkg/util/bugreport"
+	"github.com/yaacov/kubectl-mtv/pkg/util/output"
+)
+
+// AssumeYesEnv is the environment variable that confirms every question when set to true
+const AssumeYesEnv = "KUBECTL_MTV_ASSUME_YES"
+
+// ErrCanceled is returned when the user does not confirm
+var ErrCanceled = errors.New("canceled: nothing was changed")
+
+// Options configures a confirmation
+type Options struct {
+	// Yes confirms without asking
+	Yes bool
+	// Interactive tells whether the user can be asked on In
+	Interactive bool
+	In          io.Reader
+	// Out receives the affected resources and the question
+	Out io.Writer
+}
+
+// NewOptions returns options asking on the terminal; yes confirms without asking
+func NewOptions(yes bool) Options {
+	return Options{
+		Yes:         yes,
+		Interactive: bugreport.Interactive(),
+		In:          os.Stdin,
+		Out:         os.Stdout,
+	}
+}
+
+// AssumeYes tells whether KUBECTL_MTV_ASSUME_YES confirms every question
+func AssumeYes() bool {
+	yes, err := strconv.ParseBool(os.Getenv(AssumeYesEnv))
+	return err == nil && yes
+}
+
+// Table prints a title and the affected resources as a table, followed by an empty line
+func Table(opts Options, title string, columns []output.Column, items []map[string]interface{}) error {
+	fmt.Fprintf(opts.Out, "%s\n\n", title)
+	if err := output.NewTablePrinter().WithWriter(opts.Out).WithColumns(columns...).AddItems(items).Print(); err != nil {
+		return err
+	}
+	fmt.Fprintln(opts.Out)
+	return nil
+}
+
+// Confirm asks the question and returns nil when the user answers yes. Yes and
+// KUBECTL_MTV_ASSUME_YES confirm without asking; without a terminal the action is refused.
+// action describes what is refused, e.g. "delete 3 plans".
+func Confirm(opts Options, question, action string) error {
+	switch {
+	case opts.Yes || AssumeYes():
+		return nil
+	case !opts.Interactive:
+		return fmt.Errorf("refusing to %s without confirmation: run again with --yes or set %s=true", action, AssumeYesEnv)
+	}
+
+	fmt.Fprintf(opts.Out, "%s [y/N]: ", question)
+	answer, _ := bufio.NewReader(opts.In).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return nil
+	default:
+		return ErrCanceled
+	}
+}
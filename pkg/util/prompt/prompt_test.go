@@ -0,0 +1,57 @@
+package prompt
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/yaacov/kubectl-mtv/pkg/util/output"
+)
+
+func TestConfirm(t *testing.T) {
+	tests := []struct {
+		name        string
+		yes         bool
+		env         string
+		interactive bool
+		answer      string
+		wantErr     string
+		wantAsked   bool
+	}{
+		{name: "yes flag", yes: true},
+		{name: "assume yes env", env: "true"},
+		{name: "env not true", env: "no", wantErr: "run again with --yes"},
+		{name: "no terminal", wantErr: "refusing to delete 2 plans"},
+		{name: "confirmed", interactive: true, answer: "y\n", wantAsked: true},
+		{name: "confirmed with yes", interactive: true, answer: "YES\n", wantAsked: true},
+		{name: "declined", interactive: true, answer: "\n", wantErr: "canceled", wantAsked: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(AssumeYesEnv, tt.env)
+			var out bytes.Buffer
+			opts := Options{Yes: tt.yes, Interactive: tt.interactive, In: strings.NewReader(tt.answer), Out: &out}
+
+			err := Confirm(opts, "Delete these 2 plans?", "delete 2 plans")
+			if tt.wantErr == "" && err != nil || tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("Confirm() error = %v, want %q", err, tt.wantErr)
+			}
+			if asked := strings.Contains(out.String(), "[y/N]"); asked != tt.wantAsked {
+				t.Errorf("asked = %v, want %v: %q", asked, tt.wantAsked, out.String())
+			}
+		})
+	}
+}
+
+func TestTable(t *testing.T) {
+	var out bytes.Buffer
+	items := []map[string]interface{}{{"name": "wave-1", "vms": 12}}
+	if err := Table(Options{Out: &out}, "The following plan will be archived:", []output.Column{{Title: "NAME", Key: "name"}, {Title: "VMS", Key: "vms"}}, items); err != nil {
+		t.Fatalf("Table() error = %v", err)
+	}
+	for _, want := range []string{"will be archived", "wave-1", "12"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+}
@@ -23,15 +23,11 @@ func ApplyFilter(items []map[string]interface{}, tree *tsl.TSLNode, selectOpts [
 
 	// Filter the items collection using the TSL tree
 	for _, item := range items {
-		eval := evalFactory(item, selectOpts)
-
-		matchingFilter, err := semantics.Walk(tree, eval)
+		match, err := MatchItem(item, tree, selectOpts)
 		if err != nil {
-			return nil, fmt.Errorf("failed to evaluate where clause: %v", err)
+			return nil, err
 		}
-
-		// Convert interface{} to bool
-		if match, ok := matchingFilter.(bool); ok && match {
+		if match {
 			results = append(results, item)
 		}
 	}
@@ -39,6 +35,19 @@ func ApplyFilter(items []map[string]interface{}, tree *tsl.TSLNode, selectOpts [
 	return results, nil
 }
 
+// MatchItem reports whether a single item matches a TSL tree, for items filtered as they
+// arrive rather than as a list
+func MatchItem(item map[string]interface{}, tree *tsl.TSLNode, selectOpts []SelectOption) (bool, error) {
+	matchingFilter, err := semantics.Walk(tree, evalFactory(item, selectOpts))
+	if err != nil {
+		return false, fmt.Errorf("failed to evaluate where clause: %v", err)
+	}
+
+	// Convert interface{} to bool
+	match, ok := matchingFilter.(bool)
+	return ok && match, nil
+}
+
 // evalFactory gets an item and returns a method that will get the field and return its value
 func evalFactory(item map[string]interface{}, selectOpts []SelectOption) semantics.EvalFunc {
 	return func(k string) (interface{}, bool) {
@@ -0,0 +1,105 @@
+package secretref
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/yaacov/kubectl-mtv/pkg/util/upload"
+)
+
+// AWSSecretsManagerResolver reads secrets from AWS Secrets Manager. References are a
+// secret name or ARN, with #key to select a field of a JSON secret. Credentials and region
+// are resolved like the AWS CLI (environment, then the shared files); the region of an ARN
+// takes precedence.
+type AWSSecretsManagerResolver struct {
+	// Endpoint overrides the regional endpoint, e.g. for a VPC endpoint or local testing.
+	// AWS_ENDPOINT_URL_SECRETS_MANAGER is used when unset.
+	Endpoint   string
+	HTTPClient *http.Client
+}
+
+// Resolve returns the SecretString of a secret, or one field of it when it is JSON
+func (a *AWSSecretsManagerResolver) Resolve(ctx context.Context, ref string) (string, error) {
+	secretID, key := splitKey(ref)
+
+	creds, err := upload.LoadCredentials()
+	if err != nil {
+		return "", err
+	}
+	region := upload.LoadRegion()
+	// arn:aws:secretsmanager:REGION:ACCOUNT:secret:NAME
+	if parts := strings.Split(secretID, ":"); len(parts) >= 7 && parts[0] == "arn" {
+		region = parts[3]
+	}
+
+	endpoint := a.Endpoint
+	if endpoint == "" {
+		endpoint = os.Getenv("AWS_ENDPOINT_URL_SECRETS_MANAGER")
+	}
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://secretsmanager.%s.amazonaws.com", region)
+	}
+
+	body, err := json.Marshal(map[string]string{"SecretId": secretID})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode request: %v", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimRight(endpoint, "/")+"/", bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "secretsmanager.GetSecretValue")
+	upload.SignRequest(req, body, creds, region, "secretsmanager")
+
+	httpClient := a.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to read from Secrets Manager: %v", err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read Secrets Manager response: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		// Error responses carry a type and message, never the secret
+		var apiErr struct {
+			Type    string `json:"__type"`
+			Message string `json:"message"`
+		}
+		_ = json.Unmarshal(data, &apiErr)
+		if apiErr.Type != "" {
+			return "", fmt.Errorf("secrets manager returned %s: %s %s", resp.Status, apiErr.Type, apiErr.Message)
+		}
+		return "", fmt.Errorf("secrets manager returned %s", resp.Status)
+	}
+
+	var secret struct {
+		SecretString *string `json:"SecretString"`
+	}
+	if err := json.Unmarshal(data, &secret); err != nil {
+		return "", fmt.Errorf("failed to parse Secrets Manager response: %v", err)
+	}
+	if secret.SecretString == nil {
+		return "", fmt.Errorf("secret has no string value (binary secrets are not supported)")
+	}
+	if key == "" {
+		return *secret.SecretString, nil
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal([]byte(*secret.SecretString), &fields); err != nil {
+		return "", fmt.Errorf("secret is not a JSON object, remove #%s to use the whole value", key)
+	}
+	return selectField(fields, key)
+}
@@ -0,0 +1,141 @@
+// Package secretref resolves credential flag values that reference external secret
+// managers, e.g. --password vault:secret/vsphere#password, so secrets do not have to be
+// typed on the command line or kept in shell history.
+package secretref
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// PlainPrefix passes the rest of the value through unchanged, for literal values that
+// would otherwise look like a reference (plain:vault:not-a-reference)
+const PlainPrefix = "plain:"
+
+// Resolver fetches a secret value from one backend. ref is the value without the
+// "<scheme>:" prefix.
+type Resolver interface {
+	Resolve(ctx context.Context, ref string) (string, error)
+}
+
+var (
+	mu        sync.RWMutex
+	resolvers = map[string]Resolver{}
+)
+
+// Register makes a resolver available for values prefixed with "<scheme>:". Registering
+// a scheme again replaces its resolver.
+func Register(scheme string, r Resolver) {
+	mu.Lock()
+	defer mu.Unlock()
+	resolvers[scheme] = r
+}
+
+// Schemes returns the registered schemes, sorted
+func Schemes() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	schemes := make([]string, 0, len(resolvers))
+	for scheme := range resolvers {
+		schemes = append(schemes, scheme)
+	}
+	sort.Strings(schemes)
+	return schemes
+}
+
+// lookup returns the resolver of a value prefixed with a registered scheme
+func lookup(value string) (string, Resolver, string) {
+	scheme, ref, found := strings.Cut(value, ":")
+	if !found {
+		return "", nil, ""
+	}
+	mu.RLock()
+	defer mu.RUnlock()
+	r, ok := resolvers[scheme]
+	if !ok {
+		return "", nil, ""
+	}
+	return scheme, r, ref
+}
+
+// IsReference reports whether value references a registered secret backend
+func IsReference(value string) bool {
+	_, r, _ := lookup(value)
+	return r != nil
+}
+
+// Resolve returns the secret referenced by value, or value itself when it does not start
+// with a registered scheme. Errors name the reference but never a secret value.
+func Resolve(ctx context.Context, value string) (string, error) {
+	if strings.HasPrefix(value, PlainPrefix) {
+		return strings.TrimPrefix(value, PlainPrefix), nil
+	}
+	scheme, r, ref := lookup(value)
+	if r == nil {
+		return value, nil
+	}
+	if ref == "" {
+		return "", fmt.Errorf("empty %s secret reference", scheme)
+	}
+	secret, err := r.Resolve(ctx, ref)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s:%s: %v", scheme, ref, err)
+	}
+	return secret, nil
+}
+
+// ResolveAll resolves each value in place. Empty values are left unchanged.
+func ResolveAll(ctx context.Context, values ...*string) error {
+	for _, value := range values {
+		if value == nil || *value == "" {
+			continue
+		}
+		resolved, err := Resolve(ctx, *value)
+		if err != nil {
+			return err
+		}
+		*value = resolved
+	}
+	return nil
+}
+
+// splitKey splits "path#key" into the path and the key, which may be empty
+func splitKey(ref string) (string, string) {
+	path, key, _ := strings.Cut(ref, "#")
+	return path, key
+}
+
+// selectField returns the field key of a secret with several fields. Without a key the
+// secret must have exactly one field.
+func selectField(fields map[string]interface{}, key string) (string, error) {
+	if key == "" {
+		if len(fields) != 1 {
+			names := make([]string, 0, len(fields))
+			for name := range fields {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			return "", fmt.Errorf("secret has fields %s, select one with #key", strings.Join(names, ", "))
+		}
+		for name := range fields {
+			key = name
+		}
+	}
+	value, ok := fields[key]
+	if !ok {
+		return "", fmt.Errorf("secret has no field '%s'", key)
+	}
+	s, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("field '%s' is not a string", key)
+	}
+	return s, nil
+}
+
+func init() {
+	Register("vault", &VaultResolver{})
+	Register("aws-sm", &AWSSecretsManagerResolver{})
+}
@@ -0,0 +1,121 @@
+package secretref
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type staticResolver map[string]string
+
+func (s staticResolver) Resolve(_ context.Context, ref string) (string, error) {
+	return s[ref], nil
+}
+
+func TestResolve(t *testing.T) {
+	Register("test", staticResolver{"db#password": "s3cret"})
+	defer func() {
+		mu.Lock()
+		delete(resolvers, "test")
+		mu.Unlock()
+	}()
+
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"test:db#password", "s3cret"},
+		{"literal-password", "literal-password"},
+		{"unknown:value", "unknown:value"},
+		{"plain:test:db#password", "test:db#password"},
+	}
+	for _, tt := range tests {
+		got, err := Resolve(context.Background(), tt.value)
+		if err != nil || got != tt.want {
+			t.Errorf("Resolve(%q) = %q, %v, want %q", tt.value, got, err, tt.want)
+		}
+	}
+
+	user, password := "admin", "test:db#password"
+	if err := ResolveAll(context.Background(), &user, &password); err != nil || user != "admin" || password != "s3cret" {
+		t.Errorf("ResolveAll = %q, %q, %v", user, password, err)
+	}
+}
+
+func TestVaultResolver(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/secret/data/vsphere": // KV version 2
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": map[string]interface{}{"data": map[string]interface{}{"username": "admin", "password": "kv2"}},
+			})
+		case "/v1/kv1/vsphere": // KV version 1
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{"password": "kv1"}})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	v := &VaultResolver{Addr: server.URL, Token: "token", HTTPClient: server.Client()}
+	ctx := context.Background()
+
+	if got, err := v.Resolve(ctx, "secret/vsphere#password"); err != nil || got != "kv2" {
+		t.Errorf("KV v2 = %q, %v", got, err)
+	}
+	if got, err := v.Resolve(ctx, "kv1/vsphere"); err != nil || got != "kv1" {
+		t.Errorf("KV v1 with a single field = %q, %v", got, err)
+	}
+	if _, err := v.Resolve(ctx, "secret/vsphere"); err == nil || !strings.Contains(err.Error(), "#key") {
+		t.Errorf("expected a field selection error, got %v", err)
+	}
+	if _, err := v.Resolve(ctx, "secret/missing#password"); err == nil {
+		t.Error("expected error for a missing secret")
+	}
+}
+
+func TestAWSSecretsManagerResolver(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKID")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	t.Setenv("AWS_REGION", "us-east-1")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Amz-Target") != "secretsmanager.GetSecretValue" ||
+			!strings.Contains(r.Header.Get("Authorization"), "/us-east-1/secretsmanager/aws4_request") {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		var req struct{ SecretId string }
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		switch req.SecretId {
+		case "vsphere":
+			_ = json.NewEncoder(w).Encode(map[string]string{"SecretString": `{"username":"admin","password":"from-sm"}`})
+		case "token":
+			_ = json.NewEncoder(w).Encode(map[string]string{"SecretString": "plain-token"})
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"__type":"ResourceNotFoundException","message":"Secrets Manager can't find the specified secret."}`))
+		}
+	}))
+	defer server.Close()
+
+	a := &AWSSecretsManagerResolver{Endpoint: server.URL, HTTPClient: server.Client()}
+	ctx := context.Background()
+
+	if got, err := a.Resolve(ctx, "vsphere#password"); err != nil || got != "from-sm" {
+		t.Errorf("JSON field = %q, %v", got, err)
+	}
+	if got, err := a.Resolve(ctx, "token"); err != nil || got != "plain-token" {
+		t.Errorf("string secret = %q, %v", got, err)
+	}
+	if _, err := a.Resolve(ctx, "missing"); err == nil || !strings.Contains(err.Error(), "ResourceNotFoundException") {
+		t.Errorf("expected not found error, got %v", err)
+	}
+}
@@ -0,0 +1,132 @@
+package secretref
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// VaultResolver reads secrets from HashiCorp Vault KV engines. References have the form
+// mount/path#key, as used by the vault kv CLI. Connection settings follow the vault CLI:
+// VAULT_ADDR, VAULT_TOKEN (or ~/.vault-token), VAULT_NAMESPACE and VAULT_SKIP_VERIFY.
+type VaultResolver struct {
+	// Addr and Token override the environment when set
+	Addr       string
+	Token      string
+	HTTPClient *http.Client
+}
+
+// vaultResponse is the part of a Vault read response holding the secret fields
+type vaultResponse struct {
+	Data map[string]interface{} `json:"data"`
+}
+
+// Resolve reads a field of a KV secret. KV version 2 is tried first (mount/data/path),
+// then KV version 1 (mount/path).
+func (v *VaultResolver) Resolve(ctx context.Context, ref string) (string, error) {
+	path, key := splitKey(ref)
+	path = strings.Trim(path, "/")
+
+	addr := v.Addr
+	if addr == "" {
+		addr = os.Getenv("VAULT_ADDR")
+	}
+	if addr == "" {
+		return "", fmt.Errorf("VAULT_ADDR is not set")
+	}
+	token, err := v.token()
+	if err != nil {
+		return "", err
+	}
+
+	// KV version 2 nests the fields under data.data
+	if mount, rest, found := strings.Cut(path, "/"); found && !strings.HasPrefix(rest, "data/") {
+		fields, err := v.read(ctx, addr, token, mount+"/data/"+rest)
+		if err == nil {
+			if nested, ok := fields["data"].(map[string]interface{}); ok {
+				return selectField(nested, key)
+			}
+		}
+	}
+
+	fields, err := v.read(ctx, addr, token, path)
+	if err != nil {
+		return "", err
+	}
+	if nested, ok := fields["data"].(map[string]interface{}); ok && strings.Contains(path, "/data/") {
+		return selectField(nested, key)
+	}
+	return selectField(fields, key)
+}
+
+// token returns the Vault token from the resolver, VAULT_TOKEN or the vault CLI token file
+func (v *VaultResolver) token() (string, error) {
+	if v.Token != "" {
+		return v.Token, nil
+	}
+	if token := os.Getenv("VAULT_TOKEN"); token != "" {
+		return token, nil
+	}
+	home, err := os.UserHomeDir()
+	if err == nil {
+		if data, err := os.ReadFile(filepath.Join(home, ".vault-token")); err == nil {
+			return strings.TrimSpace(string(data)), nil
+		}
+	}
+	return "", fmt.Errorf("no Vault token: set VAULT_TOKEN or run 'vault login'")
+}
+
+// read returns the data of a Vault path
+func (v *VaultResolver) read(ctx context.Context, addr, token, path string) (map[string]interface{}, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(addr, "/")+"/v1/"+path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+	req.Header.Set("X-Vault-Token", token)
+	if namespace := os.Getenv("VAULT_NAMESPACE"); namespace != "" {
+		req.Header.Set("X-Vault-Namespace", namespace)
+	}
+
+	resp, err := v.httpClient().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read from Vault: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("vault returned %s", resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Vault response: %v", err)
+	}
+	var parsed vaultResponse
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse Vault response: %v", err)
+	}
+	if parsed.Data == nil {
+		return nil, fmt.Errorf("vault returned no data")
+	}
+	return parsed.Data, nil
+}
+
+// httpClient returns the configured client, honoring VAULT_SKIP_VERIFY
+func (v *VaultResolver) httpClient() *http.Client {
+	if v.HTTPClient != nil {
+		return v.HTTPClient
+	}
+	switch os.Getenv("VAULT_SKIP_VERIFY") {
+	case "", "0", "false":
+		return http.DefaultClient
+	}
+	return &http.Client{Transport: &http.Transport{
+		Proxy:           http.ProxyFromEnvironment,
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}}
+}
@@ -0,0 +1,155 @@
+package telemetry
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Environment variables that control telemetry regardless of the saved setting
+const (
+	// EnvTelemetry set to "off" (or "0", "false") disables telemetry for a single invocation
+	EnvTelemetry = "KUBECTL_MTV_TELEMETRY"
+	// EnvEndpoint overrides the configured report endpoint
+	EnvEndpoint = "KUBECTL_MTV_TELEMETRY_ENDPOINT"
+	// EnvDoNotTrack is the cross-tool opt-out convention (https://consoledonottrack.com)
+	EnvDoNotTrack = "DO_NOT_TRACK"
+)
+
+// Config is the user's telemetry choice, stored in the kubectl-mtv user config directory
+type Config struct {
+	Enabled bool `yaml:"enabled"`
+	// Endpoint receives aggregated reports; without it counters are only kept locally
+	Endpoint string `yaml:"endpoint,omitempty"`
+	// ClientID is a random identifier, not derived from the user, host or cluster
+	ClientID string `yaml:"clientId,omitempty"`
+}
+
+// configDir returns the kubectl-mtv user config directory
+func configDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find user config directory: %v", err)
+	}
+	return filepath.Join(dir, "kubectl-mtv"), nil
+}
+
+// ConfigPath returns the path of the telemetry settings file
+func ConfigPath() (string, error) {
+	dir, err := configDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "telemetry.yaml"), nil
+}
+
+// LoadConfig reads the telemetry settings; a missing file means telemetry is off
+func LoadConfig() (Config, error) {
+	path, err := ConfigPath()
+	if err != nil {
+		return Config{}, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return Config{}, nil
+	}
+	if err != nil {
+		return Config{}, fmt.Errorf("failed to read '%s': %v", path, err)
+	}
+
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return Config{}, fmt.Errorf("failed to parse '%s': %v", path, err)
+	}
+	return cfg, nil
+}
+
+// SaveConfig writes the telemetry settings
+func SaveConfig(cfg Config) error {
+	path, err := ConfigPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create '%s': %v", filepath.Dir(path), err)
+	}
+
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to encode telemetry settings: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write '%s': %v", path, err)
+	}
+	return nil
+}
+
+// Enable turns telemetry on, keeping an existing client ID and endpoint unless a new endpoint is given
+func Enable(endpoint string) (Config, error) {
+	cfg, err := LoadConfig()
+	if err != nil {
+		return Config{}, err
+	}
+	cfg.Enabled = true
+	if endpoint != "" {
+		cfg.Endpoint = endpoint
+	}
+	if cfg.ClientID == "" {
+		cfg.ClientID = newClientID()
+	}
+	return cfg, SaveConfig(cfg)
+}
+
+// Disable turns telemetry off and removes all locally collected counters
+func Disable() error {
+	cfg, err := LoadConfig()
+	if err != nil {
+		return err
+	}
+	cfg.Enabled = false
+	if err := SaveConfig(cfg); err != nil {
+		return err
+	}
+	return resetCounters()
+}
+
+// disabledByEnv reports whether the environment opts out of telemetry
+func disabledByEnv() bool {
+	switch os.Getenv(EnvTelemetry) {
+	case "off", "0", "false":
+		return true
+	}
+	switch os.Getenv(EnvDoNotTrack) {
+	case "", "0", "false":
+		return false
+	}
+	return true
+}
+
+// Active returns the effective configuration and whether telemetry should be collected
+func Active() (Config, bool) {
+	if disabledByEnv() {
+		return Config{}, false
+	}
+	cfg, err := LoadConfig()
+	if err != nil || !cfg.Enabled {
+		return cfg, false
+	}
+	if endpoint := os.Getenv(EnvEndpoint); endpoint != "" {
+		cfg.Endpoint = endpoint
+	}
+	return cfg, true
+}
+
+// newClientID returns a random anonymous identifier
+func newClientID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "anonymous"
+	}
+	return hex.EncodeToString(b)
+}
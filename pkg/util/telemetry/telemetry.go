@@ -0,0 +1,245 @@
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	pkgversion "github.com/yaacov/kubectl-mtv/pkg/version"
+)
+
+// Telemetry only ever records aggregated, anonymous counters: the command path
+// (never arguments or flag values), the outcome, and a coarse duration bucket.
+// Resource names, namespaces, URLs and cluster details are never collected.
+
+// reportInterval is the minimum time between two reports sent to the endpoint
+const reportInterval = 24 * time.Hour
+
+// reportTimeout bounds how long a command may be delayed by sending a report
+const reportTimeout = 2 * time.Second
+
+// Counters are the locally aggregated telemetry counters
+type Counters struct {
+	Since      time.Time        `json:"since"`
+	LastReport time.Time        `json:"lastReport,omitempty"`
+	Counts     map[string]int64 `json:"counts"`
+}
+
+// Report is the payload sent to the telemetry endpoint
+type Report struct {
+	ClientID    string           `json:"clientId"`
+	Version     string           `json:"version"`
+	OS          string           `json:"os"`
+	Arch        string           `json:"arch"`
+	PeriodStart time.Time        `json:"periodStart"`
+	PeriodEnd   time.Time        `json:"periodEnd"`
+	Counts      map[string]int64 `json:"counts"`
+}
+
+// durationBuckets are the upper bounds used to bucket durations
+var durationBuckets = []struct {
+	limit time.Duration
+	label string
+}{
+	{time.Second, "<1s"},
+	{10 * time.Second, "1s-10s"},
+	{time.Minute, "10s-1m"},
+	{10 * time.Minute, "1m-10m"},
+	{time.Hour, "10m-1h"},
+	{4 * time.Hour, "1h-4h"},
+}
+
+// DurationBucket returns the coarse bucket label of a duration
+func DurationBucket(d time.Duration) string {
+	for _, bucket := range durationBuckets {
+		if d < bucket.limit {
+			return bucket.label
+		}
+	}
+	return ">4h"
+}
+
+// outcome returns the counter label for an error
+func outcome(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "success"
+}
+
+// RecordCommand counts a finished command. commandPath is the cobra command path
+// without the binary name, e.g. "get plan".
+func RecordCommand(commandPath string, err error, duration time.Duration) {
+	record(
+		fmt.Sprintf("command/%s/%s", commandPath, outcome(err)),
+		fmt.Sprintf("command-duration/%s/%s", commandPath, DurationBucket(duration)),
+	)
+}
+
+// RecordMigration counts a migration outcome observed by the CLI (e.g. Succeeded, Failed, Canceled)
+func RecordMigration(result string, duration time.Duration) {
+	record(
+		fmt.Sprintf("migration/%s", strings.ToLower(result)),
+		fmt.Sprintf("migration-duration/%s", DurationBucket(duration)),
+	)
+}
+
+// record increments counters when telemetry is enabled and sends a report when one is due.
+// Telemetry must never affect the command, so all errors are only logged.
+func record(keys ...string) {
+	cfg, active := Active()
+	if !active {
+		return
+	}
+
+	counters, err := loadCounters()
+	if err != nil {
+		klog.V(2).Infof("Telemetry: %v", err)
+		return
+	}
+	for _, key := range keys {
+		counters.Counts[key]++
+	}
+
+	now := time.Now().UTC()
+	if cfg.Endpoint != "" && now.Sub(counters.LastReport) >= reportInterval {
+		ctx, cancel := context.WithTimeout(context.Background(), reportTimeout)
+		err := send(ctx, cfg, counters, now)
+		cancel()
+		if err != nil {
+			klog.V(2).Infof("Telemetry: %v", err)
+		} else {
+			counters = &Counters{Since: now, LastReport: now, Counts: map[string]int64{}}
+		}
+	}
+
+	if err := saveCounters(counters); err != nil {
+		klog.V(2).Infof("Telemetry: %v", err)
+	}
+}
+
+// send posts the aggregated counters to the telemetry endpoint
+func send(ctx context.Context, cfg Config, counters *Counters, now time.Time) error {
+	body, err := json.Marshal(Report{
+		ClientID:    cfg.ClientID,
+		Version:     pkgversion.ClientVersion,
+		OS:          runtime.GOOS,
+		Arch:        runtime.GOARCH,
+		PeriodStart: counters.Since,
+		PeriodEnd:   now,
+		Counts:      counters.Counts,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode report: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, cfg.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create report request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send report: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("failed to send report: %s", resp.Status)
+	}
+	return nil
+}
+
+// countersPath returns the path of the local counters file
+func countersPath() (string, error) {
+	dir, err := configDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "telemetry-counters.json"), nil
+}
+
+// loadCounters reads the local counters, starting a new period when none exist
+func loadCounters() (*Counters, error) {
+	path, err := countersPath()
+	if err != nil {
+		return nil, err
+	}
+
+	counters := &Counters{}
+	data, err := os.ReadFile(path)
+	switch {
+	case os.IsNotExist(err):
+	case err != nil:
+		return nil, fmt.Errorf("failed to read '%s': %v", path, err)
+	default:
+		if err := json.Unmarshal(data, counters); err != nil {
+			// A corrupt file only loses local counters, start over
+			klog.V(2).Infof("Telemetry: discarding unreadable '%s': %v", path, err)
+			counters = &Counters{}
+		}
+	}
+
+	if counters.Counts == nil {
+		counters.Counts = map[string]int64{}
+	}
+	if counters.Since.IsZero() {
+		counters.Since = time.Now().UTC()
+	}
+	return counters, nil
+}
+
+// saveCounters writes the local counters
+func saveCounters(counters *Counters) error {
+	path, err := countersPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create '%s': %v", filepath.Dir(path), err)
+	}
+	data, err := json.MarshalIndent(counters, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode counters: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write '%s': %v", path, err)
+	}
+	return nil
+}
+
+// resetCounters removes the local counters
+func resetCounters() error {
+	path, err := countersPath()
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove '%s': %v", path, err)
+	}
+	return nil
+}
+
+// PendingCounts returns the locally collected counters that have not been reported yet, sorted by key
+func PendingCounts() ([]string, map[string]int64, error) {
+	counters, err := loadCounters()
+	if err != nil {
+		return nil, nil, err
+	}
+	keys := make([]string, 0, len(counters.Counts))
+	for key := range counters.Counts {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys, counters.Counts, nil
+}
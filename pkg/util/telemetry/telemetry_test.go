@@ -0,0 +1,134 @@
+package telemetry
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+// useTempConfig points the user config directory at a temporary directory
+func useTempConfig(t *testing.T) {
+	t.Helper()
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(EnvTelemetry, "")
+	t.Setenv(EnvEndpoint, "")
+	t.Setenv(EnvDoNotTrack, "")
+}
+
+func TestDurationBucket(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{500 * time.Millisecond, "<1s"},
+		{5 * time.Second, "1s-10s"},
+		{30 * time.Second, "10s-1m"},
+		{5 * time.Minute, "1m-10m"},
+		{30 * time.Minute, "10m-1h"},
+		{2 * time.Hour, "1h-4h"},
+		{10 * time.Hour, ">4h"},
+	}
+	for _, tt := range tests {
+		if got := DurationBucket(tt.d); got != tt.want {
+			t.Errorf("DurationBucket(%s) = %s, want %s", tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestRecordIsOffByDefault(t *testing.T) {
+	useTempConfig(t)
+
+	RecordCommand("get plan", nil, time.Second)
+
+	path, err := countersPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("expected no counters file when telemetry is off, stat err = %v", err)
+	}
+}
+
+func TestRecordWhenEnabled(t *testing.T) {
+	useTempConfig(t)
+
+	if _, err := Enable(""); err != nil {
+		t.Fatalf("Enable: %v", err)
+	}
+	RecordCommand("get plan", nil, 2*time.Second)
+	RecordCommand("get plan", errors.New("boom"), 2*time.Second)
+	RecordMigration("Succeeded", 20*time.Minute)
+
+	_, counts, err := PendingCounts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]int64{
+		"command/get plan/success":         1,
+		"command/get plan/error":           1,
+		"command-duration/get plan/1s-10s": 2,
+		"migration/succeeded":              1,
+		"migration-duration/10m-1h":        1,
+	}
+	for key, value := range want {
+		if counts[key] != value {
+			t.Errorf("count[%s] = %d, want %d", key, counts[key], value)
+		}
+	}
+
+	// The environment opt-out wins over the saved setting
+	t.Setenv(EnvDoNotTrack, "1")
+	RecordCommand("get plan", nil, time.Second)
+	_, counts, _ = PendingCounts()
+	if counts["command/get plan/success"] != 1 {
+		t.Errorf("expected DO_NOT_TRACK to stop counting, got %d", counts["command/get plan/success"])
+	}
+
+	// Opting out removes local counters
+	if err := Disable(); err != nil {
+		t.Fatalf("Disable: %v", err)
+	}
+	t.Setenv(EnvDoNotTrack, "")
+	_, counts, _ = PendingCounts()
+	if len(counts) != 0 {
+		t.Errorf("expected no counters after Disable, got %v", counts)
+	}
+}
+
+func TestRecordSendsReport(t *testing.T) {
+	useTempConfig(t)
+
+	var report Report
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&report); err != nil {
+			t.Errorf("decode report: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	cfg, err := Enable(server.URL)
+	if err != nil {
+		t.Fatalf("Enable: %v", err)
+	}
+	RecordCommand("start wave", nil, time.Minute)
+
+	if report.ClientID != cfg.ClientID || report.Counts["command/start wave/success"] != 1 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+
+	// Reported counters are cleared, and the next report waits for the interval
+	report = Report{}
+	RecordCommand("start wave", nil, time.Minute)
+	if report.ClientID != "" {
+		t.Errorf("expected no second report within the interval, got %+v", report)
+	}
+	_, counts, _ := PendingCounts()
+	if counts["command/start wave/success"] != 1 {
+		t.Errorf("pending count = %d, want 1", counts["command/start wave/success"])
+	}
+}
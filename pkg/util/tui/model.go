@@ -1,6 +1,7 @@
 package tui
 
 import (
+	"errors"
 	"time"
 
 	"github.com/charmbracelet/bubbles/help"
@@ -27,6 +28,13 @@ type DataFetcher func() (string, error)
 // QueryUpdater is called when the user submits a new query string.
 type QueryUpdater func(query string)
 
+// StatusFunc reports whether the data source is live, and details shown while it is not,
+// e.g. a reconnect in progress. It is shown as the heartbeat of the status bar.
+type StatusFunc func() (live bool, detail string)
+
+// ErrTimedOut is returned by Run when the watch stopped after its timeout
+var ErrTimedOut = errors.New("watch timed out")
+
 // Option configures the TUI model.
 type Option func(*Model)
 
@@ -40,6 +48,16 @@ func WithInitialQuery(q string) Option {
 	return func(m *Model) { m.currentQuery = q }
 }
 
+// WithTimeout stops the TUI after d; 0 runs until the user quits.
+func WithTimeout(d time.Duration) Option {
+	return func(m *Model) { m.timeout = d }
+}
+
+// WithStatus shows the health of the data source as a heartbeat in the status bar.
+func WithStatus(status StatusFunc) Option {
+	return func(m *Model) { m.status = status }
+}
+
 // Model represents the TUI state
 type Model struct {
 	viewport        viewport.Model
@@ -58,6 +76,13 @@ type Model struct {
 	width           int
 	height          int
 
+	// Watch lifetime and health
+	startedAt time.Time
+	timeout   time.Duration
+	timedOut  bool
+	status    StatusFunc
+	beat      bool
+
 	// Interactive modes
 	mode tuiMode
 
@@ -196,6 +221,7 @@ func NewModel(dataFetcher DataFetcher, refreshInterval time.Duration, opts ...Op
 		dataFetcher:     dataFetcher,
 		refreshInterval: refreshInterval,
 		lastUpdate:      time.Now(),
+		startedAt:       time.Now(),
 		loading:         false,
 		showHelp:        false,
 		ready:           false,
@@ -214,16 +240,23 @@ func NewModel(dataFetcher DataFetcher, refreshInterval time.Duration, opts ...Op
 
 // Init initializes the TUI model
 func (m Model) Init() tea.Cmd {
-	return tea.Batch(
+	cmds := []tea.Cmd{
 		m.spinner.Tick,
 		fetchData(m.dataFetcher),
 		tickCmd(m.refreshInterval),
-	)
+	}
+	if m.timeout > 0 {
+		cmds = append(cmds, tea.Tick(m.timeout, func(time.Time) tea.Msg { return timeoutMsg{} }))
+	}
+	return tea.Batch(cmds...)
 }
 
 // TickMsg is sent on each refresh interval
 type tickMsg time.Time
 
+// timeoutMsg is sent when the watch timeout expires
+type timeoutMsg struct{}
+
 // fetchDataMsg is sent when data fetching completes
 type fetchDataMsg struct {
 	content string
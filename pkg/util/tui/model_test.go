@@ -0,0 +1,37 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTimeoutQuits(t *testing.T) {
+	m := NewModel(func() (string, error) { return "", nil }, time.Second, WithTimeout(time.Minute))
+
+	updated, cmd := m.Update(timeoutMsg{})
+	final := updated.(Model)
+	if !final.timedOut || !final.quitting || cmd == nil {
+		t.Errorf("Update(timeoutMsg) = timedOut %v, quitting %v, want the TUI to quit", final.timedOut, final.quitting)
+	}
+}
+
+func TestHeartbeat(t *testing.T) {
+	live, detail := true, ""
+	m := NewModel(func() (string, error) { return "", nil }, time.Second,
+		WithStatus(func() (bool, string) { return live, detail }))
+
+	updated, _ := m.Update(fetchDataMsg{content: "plans"})
+	m = updated.(Model)
+	first, ok := m.heartbeat()
+	updated, _ = m.Update(fetchDataMsg{content: "plans"})
+	second, _ := updated.(Model).heartbeat()
+	if !ok || first == second || !strings.HasSuffix(first, "Live") {
+		t.Errorf("heartbeat = %q then %q, want an alternating live beat", first, second)
+	}
+
+	live, detail = false, "Reconnecting (attempt 2)"
+	if hb, ok := m.heartbeat(); ok || hb != "○ Reconnecting (attempt 2)" {
+		t.Errorf("heartbeat while reconnecting = %q, %v", hb, ok)
+	}
+}
@@ -43,9 +43,15 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			tickCmd(m.refreshInterval),
 		)
 
+	case timeoutMsg:
+		m.quitting = true
+		m.timedOut = true
+		return m, tea.Quit
+
 	case fetchDataMsg:
 		m.loading = false
 		m.lastUpdate = time.Now()
+		m.beat = !m.beat
 
 		if msg.err != nil {
 			m.lastError = msg.err
@@ -348,9 +354,13 @@ func RunWithOptions(dataFetcher DataFetcher, refreshInterval time.Duration, opts
 		tea.WithMouseCellMotion(),
 	)
 
-	if _, err := p.Run(); err != nil {
+	final, err := p.Run()
+	if err != nil {
 		return err
 	}
+	if fm, ok := final.(Model); ok && fm.timedOut {
+		return ErrTimedOut
+	}
 
 	return nil
 }
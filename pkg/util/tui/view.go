@@ -113,6 +113,11 @@ func (m Model) renderQueryBar() string {
 func (m Model) renderStatusBar() string {
 	var parts []string
 
+	hb, live := m.heartbeat()
+	if hb != "" {
+		parts = append(parts, hb)
+	}
+
 	if m.loading {
 		parts = append(parts, m.spinner.View()+" Refreshing...")
 	}
@@ -126,6 +131,14 @@ func (m Model) renderStatusBar() string {
 
 	parts = append(parts, fmt.Sprintf("Refresh: %ds", int(m.refreshInterval.Seconds())))
 
+	if m.timeout > 0 {
+		left := time.Until(m.startedAt.Add(m.timeout))
+		if left < 0 {
+			left = 0
+		}
+		parts = append(parts, fmt.Sprintf("Stops in %s", left.Round(time.Second)))
+	}
+
 	if m.queryUpdater != nil && m.currentQuery != "" {
 		q := m.currentQuery
 		if len(q) > 30 {
@@ -148,6 +161,9 @@ func (m Model) renderStatusBar() string {
 	statusText := strings.Join(parts, " • ")
 
 	style := statusBarStyle
+	if !live {
+		style = statusBarErrorStyle
+	}
 	if m.lastError != nil {
 		style = statusBarErrorStyle
 		errorMsg := fmt.Sprintf("Error: %v", m.lastError)
@@ -164,6 +180,23 @@ func (m Model) renderStatusBar() string {
 	return style.Width(m.width).Render(statusText)
 }
 
+// heartbeat renders the health of the data source: a dot that alternates on each refresh
+// while the source is live, or the reconnect details while it is not
+func (m Model) heartbeat() (string, bool) {
+	if m.status == nil {
+		return "", true
+	}
+	live, detail := m.status()
+	switch {
+	case !live:
+		return "○ " + detail, false
+	case m.beat:
+		return "● Live", true
+	default:
+		return "◌ Live", true
+	}
+}
+
 // renderHelpOverlay renders the help panel overlay
 func (m Model) renderHelpOverlay() string {
 	helpContent := helpTitleStyle.Render("Keyboard Shortcuts") + "\n\n"
@@ -0,0 +1,136 @@
+package upload
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Credentials holds AWS credentials used to sign object storage requests
+type Credentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+}
+
+// profileName returns the AWS profile selected by AWS_PROFILE, or "default"
+func profileName() string {
+	if profile := os.Getenv("AWS_PROFILE"); profile != "" {
+		return profile
+	}
+	return "default"
+}
+
+// awsFilePath returns the path of an AWS shared file, honoring its environment override
+func awsFilePath(envVar, name string) string {
+	if path := os.Getenv(envVar); path != "" {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".aws", name)
+}
+
+// LoadCredentials resolves credentials the way the AWS CLI does for static keys:
+// AWS_ACCESS_KEY_ID/AWS_SECRET_ACCESS_KEY/AWS_SESSION_TOKEN first, then the
+// AWS_PROFILE (or default) section of the shared credentials file.
+func LoadCredentials() (Credentials, error) {
+	creds := Credentials{
+		AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+	}
+	if creds.AccessKeyID != "" && creds.SecretAccessKey != "" {
+		return creds, nil
+	}
+
+	profile := profileName()
+	path := awsFilePath("AWS_SHARED_CREDENTIALS_FILE", "credentials")
+	values, err := ReadINISection(path, profile)
+	if err != nil {
+		return Credentials{}, fmt.Errorf("no AWS credentials found: set AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY or configure profile '%s' in %s", profile, path)
+	}
+
+	creds = Credentials{
+		AccessKeyID:     values["aws_access_key_id"],
+		SecretAccessKey: values["aws_secret_access_key"],
+		SessionToken:    values["aws_session_token"],
+	}
+	if creds.AccessKeyID == "" || creds.SecretAccessKey == "" {
+		return Credentials{}, fmt.Errorf("profile '%s' in %s has no aws_access_key_id/aws_secret_access_key", profile, path)
+	}
+	return creds, nil
+}
+
+// LoadRegion resolves the AWS region from AWS_REGION, AWS_DEFAULT_REGION, or the shared
+// config file, defaulting to us-east-1
+func LoadRegion() string {
+	for _, envVar := range []string{"AWS_REGION", "AWS_DEFAULT_REGION"} {
+		if region := os.Getenv(envVar); region != "" {
+			return region
+		}
+	}
+
+	// The shared config file names non-default profiles "profile NAME"
+	profile := profileName()
+	section := profile
+	if profile != "default" {
+		section = "profile " + profile
+	}
+	if values, err := ReadINISection(awsFilePath("AWS_CONFIG_FILE", "config"), section); err == nil && values["region"] != "" {
+		return values["region"]
+	}
+
+	return "us-east-1"
+}
+
+// ReadINISection returns the key/value pairs of a section in an INI file, such as the AWS
+// shared config and credentials files. Keys are lower case and separated from their values
+// by '=' or ':'; a missing section is an error.
+func ReadINISection(path, section string) (map[string]string, error) {
+	if path == "" {
+		return nil, fmt.Errorf("no path")
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	values := map[string]string{}
+	found := false
+	inSection := false
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			inSection = strings.TrimSpace(line[1:len(line)-1]) == section
+			found = found || inSection
+			continue
+		}
+		if !inSection {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			key, value, ok = strings.Cut(line, ":")
+		}
+		if ok {
+			values[strings.ToLower(strings.TrimSpace(key))] = strings.TrimSpace(value)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("section '%s' not found in %s", section, path)
+	}
+	return values, nil
+}
@@ -0,0 +1,356 @@
+package upload
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// S3Location is a parsed s3://bucket/prefix URL
+type S3Location struct {
+	Bucket string
+	Prefix string
+}
+
+// String returns the location as an s3:// URL
+func (l S3Location) String() string {
+	if l.Prefix == "" {
+		return "s3://" + l.Bucket
+	}
+	return "s3://" + l.Bucket + "/" + l.Prefix
+}
+
+// ParseS3URL parses an s3://bucket/path URL
+func ParseS3URL(raw string) (S3Location, error) {
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return S3Location{}, fmt.Errorf("invalid upload URL '%s': %v", raw, err)
+	}
+	if parsed.Scheme != "s3" {
+		return S3Location{}, fmt.Errorf("unsupported upload URL '%s': only s3://bucket/path is supported", raw)
+	}
+	if parsed.Host == "" {
+		return S3Location{}, fmt.Errorf("invalid upload URL '%s': missing bucket name", raw)
+	}
+	return S3Location{
+		Bucket: parsed.Host,
+		Prefix: strings.Trim(parsed.Path, "/"),
+	}, nil
+}
+
+// S3Uploader uploads files to an S3 or S3-compatible bucket
+type S3Uploader struct {
+	Location    S3Location
+	Credentials Credentials
+	Region      string
+	// Endpoint overrides the AWS endpoint for S3-compatible storage (path-style addressing)
+	Endpoint   string
+	HTTPClient *http.Client
+}
+
+// NewS3Uploader creates an uploader for an s3:// URL using the standard AWS environment
+// variables and shared credentials/config files. AWS_ENDPOINT_URL_S3 or AWS_ENDPOINT_URL
+// select an S3-compatible endpoint (e.g. MinIO or OpenShift Data Foundation).
+func NewS3Uploader(rawURL string) (*S3Uploader, error) {
+	location, err := ParseS3URL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	creds, err := LoadCredentials()
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := os.Getenv("AWS_ENDPOINT_URL_S3")
+	if endpoint == "" {
+		endpoint = os.Getenv("AWS_ENDPOINT_URL")
+	}
+
+	return &S3Uploader{
+		Location:    location,
+		Credentials: creds,
+		Region:      LoadRegion(),
+		Endpoint:    strings.TrimRight(endpoint, "/"),
+		HTTPClient:  &http.Client{Timeout: 5 * time.Minute},
+	}, nil
+}
+
+// objectURL returns the request URL for an object key
+func (u *S3Uploader) objectURL(key string) string {
+	escapedKey := uriEncode(key, false)
+	if u.Endpoint != "" {
+		return fmt.Sprintf("%s/%s/%s", u.Endpoint, u.Location.Bucket, escapedKey)
+	}
+	return fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", u.Location.Bucket, u.Region, escapedKey)
+}
+
+// UploadDir uploads every regular file under dir, keeping the relative layout below
+// the location prefix joined with subPrefix. It returns the uploaded s3:// URLs.
+func (u *S3Uploader) UploadDir(ctx context.Context, dir, subPrefix string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Type().IsRegular() {
+			files = append(files, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read '%s': %v", dir, err)
+	}
+	sort.Strings(files)
+
+	uploaded := make([]string, 0, len(files))
+	for _, file := range files {
+		rel, err := filepath.Rel(dir, file)
+		if err != nil {
+			return uploaded, err
+		}
+		key := path.Join(u.Location.Prefix, subPrefix, filepath.ToSlash(rel))
+		if err := u.UploadFile(ctx, file, key); err != nil {
+			return uploaded, err
+		}
+		uploaded = append(uploaded, "s3://"+u.Location.Bucket+"/"+key)
+	}
+	return uploaded, nil
+}
+
+// UploadFile uploads a single local file to the given object key
+func (u *S3Uploader) UploadFile(ctx context.Context, localPath, key string) error {
+	data, err := os.ReadFile(localPath)
+	if err != nil {
+		return fmt.Errorf("failed to read '%s': %v", localPath, err)
+	}
+
+	contentType := mime.TypeByExtension(filepath.Ext(localPath))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	klog.V(2).Infof("Uploading %s to s3://%s/%s", localPath, u.Location.Bucket, key)
+	return u.UploadContent(ctx, data, key, contentType)
+}
+
+// UploadContent uploads in-memory content, e.g. a rendered report, to the given object key
+func (u *S3Uploader) UploadContent(ctx context.Context, data []byte, key, contentType string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, u.objectURL(key), bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create upload request: %v", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	signV4(req, hashHex(data), u.Credentials, u.Region, "s3", time.Now().UTC())
+
+	resp, err := u.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to upload '%s': %v", key, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("failed to upload '%s': %s: %s", key, resp.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// UploadNamed uploads in-memory content as name below the location prefix, with the
+// content type taken from the name's extension, and returns the object's s3:// URL
+func (u *S3Uploader) UploadNamed(ctx context.Context, data []byte, name string) (string, error) {
+	contentType := mime.TypeByExtension(path.Ext(name))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	key := path.Join(u.Location.Prefix, name)
+	klog.V(2).Infof("Uploading %s to s3://%s/%s", name, u.Location.Bucket, key)
+	if err := u.UploadContent(ctx, data, key, contentType); err != nil {
+		return "", err
+	}
+	return "s3://" + u.Location.Bucket + "/" + key, nil
+}
+
+// maxSinglePutBytes is the largest object S3 accepts in a single PUT request
+const maxSinglePutBytes = 5 << 30
+
+// unsignedPayload marks a request body that is streamed and not part of the signature
+const unsignedPayload = "UNSIGNED-PAYLOAD"
+
+// UploadLargeFile streams a local file to the given object key without reading it into
+// memory, e.g. OVA images. The payload is sent unsigned; TLS protects it in transit.
+func (u *S3Uploader) UploadLargeFile(ctx context.Context, localPath, key string) error {
+	file, err := os.Open(localPath)
+	if err != nil {
+		return fmt.Errorf("failed to open '%s': %v", localPath, err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to read '%s': %v", localPath, err)
+	}
+	if info.Size() > maxSinglePutBytes {
+		return fmt.Errorf("'%s' is %d bytes, larger than the 5 GiB S3 single upload limit", localPath, info.Size())
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, u.objectURL(key), file)
+	if err != nil {
+		return fmt.Errorf("failed to create upload request: %v", err)
+	}
+	req.ContentLength = info.Size()
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	signV4(req, unsignedPayload, u.Credentials, u.Region, "s3", time.Now().UTC())
+
+	klog.V(2).Infof("Uploading %s (%d bytes) to s3://%s/%s", localPath, info.Size(), u.Location.Bucket, key)
+	resp, err := u.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to upload '%s': %v", key, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("failed to upload '%s': %s: %s", key, resp.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// SignRequest signs a request to an AWS service with body as its payload, for callers
+// outside this package that talk to other AWS APIs
+func SignRequest(req *http.Request, body []byte, creds Credentials, region, service string) {
+	signV4(req, hashHex(body), creds, region, service, time.Now().UTC())
+}
+
+// signV4 adds AWS Signature Version 4 headers to the request. All headers already set
+// on the request are signed, together with host, x-amz-date and x-amz-content-sha256.
+func signV4(req *http.Request, payloadHash string, creds Credentials, region, service string, now time.Time) {
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	signRequest(req, payloadHash, creds, region, service, now)
+}
+
+// signRequest adds the x-amz-date and Authorization headers of AWS Signature Version 4,
+// signing host and all headers already set on the request
+func signRequest(req *http.Request, payloadHash string, creds Credentials, region, service string, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	if creds.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", creds.SessionToken)
+	}
+
+	// Canonical headers, including host which is not part of req.Header
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		trimmed := make([]string, 0, len(values))
+		for _, value := range values {
+			trimmed = append(trimmed, strings.Join(strings.Fields(value), " "))
+		}
+		headers[strings.ToLower(name)] = strings.Join(trimmed, ",")
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		uriEncode(req.URL.Path, false),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := strings.Join([]string{date, region, service, "aws4_request"}, "/")
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		hashHex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+creds.SecretAccessKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		creds.AccessKeyID, scope, signedHeaders, signature))
+}
+
+// canonicalQuery returns the sorted, encoded query string used for signing
+func canonicalQuery(values url.Values) string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	parts := []string{}
+	for _, key := range keys {
+		vals := append([]string(nil), values[key]...)
+		sort.Strings(vals)
+		for _, val := range vals {
+			parts = append(parts, uriEncode(key, true)+"="+uriEncode(val, true))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// uriEncode percent-encodes a string as required by SigV4; slashes are kept unless encodeSlash is set
+func uriEncode(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9'),
+			c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func hashHex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,112 @@
+// Package vocab maps the provider specific values of the inventory, such as power
+// states and size units, to the canonical vocabulary shown by kubectl-mtv.
+package vocab
+
+import (
+	"math"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Canonical power states
+const (
+	PowerOn        = "On"
+	PowerOff       = "Off"
+	PowerSuspended = "Suspended"
+	PowerPaused    = "Paused"
+	PowerStarting  = "Starting"
+	PowerStopping  = "Stopping"
+	PowerError     = "Error"
+)
+
+// powerStates maps provider power states, lowercased without spaces, dashes and
+// underscores, to the canonical power states
+var powerStates = map[string]string{
+	// vSphere
+	"poweredon":  PowerOn,
+	"poweredoff": PowerOff,
+	"suspended":  PowerSuspended,
+	// oVirt
+	"up":            PowerOn,
+	"down":          PowerOff,
+	"paused":        PowerPaused,
+	"poweringup":    PowerStarting,
+	"waitforlaunch": PowerStarting,
+	"poweringdown":  PowerStopping,
+	// OpenStack
+	"active":  PowerOn,
+	"shutoff": PowerOff,
+	"error":   PowerError,
+	// EC2
+	"running":      PowerOn,
+	"stopped":      PowerOff,
+	"pending":      PowerStarting,
+	"stopping":     PowerStopping,
+	"shuttingdown": PowerStopping,
+	// KubeVirt
+	"halted":   PowerOff,
+	"starting": PowerStarting,
+	// Hyper-V and Azure
+	"on":           PowerOn,
+	"off":          PowerOff,
+	"saved":        PowerSuspended,
+	"deallocated":  PowerOff,
+	"deallocating": PowerStopping,
+}
+
+// PowerState returns the canonical power state of a provider power state, e.g. "On" for
+// vSphere "poweredOn", oVirt "up", OpenStack "ACTIVE", EC2 "running" and Azure
+// "VM running". Unknown states are returned as reported with a capital first letter.
+func PowerState(raw string) string {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return ""
+	}
+	s := strings.ToLower(raw)
+	s = strings.TrimPrefix(s, "powerstate/")
+	s = strings.TrimPrefix(s, "vm ")
+	key := strings.NewReplacer(" ", "", "-", "", "_", "").Replace(s)
+	if state, ok := powerStates[key]; ok {
+		return state
+	}
+	return strings.ToUpper(raw[:1]) + raw[1:]
+}
+
+// RawPowerState returns the power state of an inventory VM as reported by its provider:
+// powerState (vSphere, oVirt, Hyper-V, Azure), status (oVirt, OpenStack), State.Name (EC2),
+// or the VirtualMachine and VirtualMachineInstance status (OpenShift)
+func RawPowerState(vm map[string]interface{}) string {
+	if ps, ok := vm["powerState"].(string); ok && ps != "" {
+		return ps
+	}
+	if st, ok := vm["status"].(string); ok && st != "" {
+		return st
+	}
+	if name, found, _ := unstructured.NestedString(vm, "State", "Name"); found && name != "" {
+		return name
+	}
+	if ps, found, _ := unstructured.NestedString(vm, "object", "status", "printableStatus"); found && ps != "" {
+		return ps
+	}
+	for _, prefix := range []string{"object", "instance"} {
+		if phase, found, _ := unstructured.NestedString(vm, prefix, "status", "phase"); found && phase != "" {
+			return phase
+		}
+	}
+	return ""
+}
+
+// Size units of the inventory
+const (
+	Bytes = 1.0
+	KiB   = 1024 * Bytes
+	MiB   = 1024 * KiB
+	GiB   = 1024 * MiB
+)
+
+// ToGiB converts a size in unit (Bytes, KiB, MiB or GiB) to GiB, rounded to one decimal,
+// the canonical size unit of computed inventory fields
+func ToGiB(size float64, unit float64) float64 {
+	return math.Round(size*unit/GiB*10) / 10
+}
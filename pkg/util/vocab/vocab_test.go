@@ -0,0 +1,57 @@
+package vocab
+
+import "testing"
+
+func TestPowerState(t *testing.T) {
+	tests := map[string]string{
+		"poweredOn":               PowerOn,
+		"up":                      PowerOn,
+		"ACTIVE":                  PowerOn,
+		"running":                 PowerOn,
+		"VM running":              PowerOn,
+		"PowerState/running":      PowerOn,
+		"poweredOff":              PowerOff,
+		"SHUTOFF":                 PowerOff,
+		"Halted":                  PowerOff,
+		"VM deallocated":          PowerOff,
+		"suspended":               PowerSuspended,
+		"shutting-down":           PowerStopping,
+		"powering_up":             PowerStarting,
+		"WaitingForVolumeBinding": "WaitingForVolumeBinding",
+		"":                        "",
+	}
+	for raw, want := range tests {
+		if got := PowerState(raw); got != want {
+			t.Errorf("PowerState(%q) = %q, want %q", raw, got, want)
+		}
+	}
+}
+
+func TestRawPowerState(t *testing.T) {
+	tests := []struct {
+		name string
+		vm   map[string]interface{}
+		want string
+	}{
+		{"vSphere", map[string]interface{}{"powerState": "poweredOn"}, "poweredOn"},
+		{"OpenStack", map[string]interface{}{"status": "SHUTOFF"}, "SHUTOFF"},
+		{"EC2", map[string]interface{}{"State": map[string]interface{}{"Name": "stopped"}}, "stopped"},
+		{"KubeVirt", map[string]interface{}{"object": map[string]interface{}{"status": map[string]interface{}{"printableStatus": "Running"}}}, "Running"},
+		{"VMI", map[string]interface{}{"instance": map[string]interface{}{"status": map[string]interface{}{"phase": "Scheduling"}}}, "Scheduling"},
+		{"none", map[string]interface{}{}, ""},
+	}
+	for _, tt := range tests {
+		if got := RawPowerState(tt.vm); got != tt.want {
+			t.Errorf("%s: RawPowerState() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestToGiB(t *testing.T) {
+	if got := ToGiB(4096, MiB); got != 4 {
+		t.Errorf("ToGiB(4096 MiB) = %v, want 4", got)
+	}
+	if got := ToGiB(21474836480, Bytes); got != 20 {
+		t.Errorf("ToGiB(20 GiB in bytes) = %v, want 20", got)
+	}
+}
@@ -0,0 +1,44 @@
+package wait
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Condition returns the status condition of the given type, or nil when it is not set
+func Condition(obj *unstructured.Unstructured, condType string) map[string]interface{} {
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if ok && condition["type"] == condType {
+			return condition
+		}
+	}
+	return nil
+}
+
+// ConditionTrue reports whether the condition of the given type has status True
+func ConditionTrue(obj *unstructured.Unstructured, condType string) bool {
+	condition := Condition(obj, condType)
+	return condition != nil && condition["status"] == "True"
+}
+
+// BlockingConditions returns the true Critical and Error conditions of a Forklift resource,
+// formatted as "Type: message"
+func BlockingConditions(obj *unstructured.Unstructured) []string {
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+
+	var problems []string
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok || condition["status"] != "True" {
+			continue
+		}
+		switch condition["category"] {
+		case "Critical", "Error":
+			problems = append(problems, fmt.Sprintf("%v: %v", condition["type"], condition["message"]))
+		}
+	}
+	return problems
+}
@@ -0,0 +1,227 @@
+package wait
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/klog/v2"
+
+	mtvwatch "github.com/yaacov/kubectl-mtv/pkg/util/watch"
+)
+
+// DefaultTimeout is the default of the --wait-timeout flag of the create commands
+const DefaultTimeout = 10 * time.Minute
+
+// DefaultSettle is how long problems must persist before a wait fails. Controllers report
+// transient problems while they reconcile a new resource, e.g. a connection test still running.
+const DefaultSettle = 30 * time.Second
+
+// rewatchDelay is the first delay before a failed watch is restarted, doubling on each failure
+var rewatchDelay = 2 * time.Second
+
+// Check evaluates an observed resource. done ends the wait; problems are the reasons the
+// resource is not done yet that will not resolve by themselves, e.g. failed validations.
+type Check func(obj *unstructured.Unstructured) (done bool, problems []string)
+
+// Options configures For
+type Options struct {
+	// Timeout bounds the wait, 0 waits until the check is done or fails
+	Timeout time.Duration
+	// Settle is how long the same problems must be reported before the wait fails
+	Settle time.Duration
+	// Out receives the problems as they are reported
+	Out io.Writer
+}
+
+// For watches the named resource until check reports it done, and returns its last state.
+// The resource is read once and then watched from its resource version; the watch is restarted
+// when it closes, with backoff while it cannot be established. It fails when the resource is deleted, when problems persist for
+// opts.Settle and when the timeout expires. kind and goal describe the wait in messages,
+// e.g. "provider" and "become Ready".
+func For(ctx context.Context, ri dynamic.ResourceInterface, kind, name, goal string, check Check, opts Options) (*unstructured.Unstructured, error) {
+	if opts.Out == nil {
+		opts.Out = io.Discard
+	}
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	w := &waiter{resource: fmt.Sprintf("%s '%s'", kind, name), goal: goal, check: check, opts: opts}
+	backoff := mtvwatch.NewBackoff(rewatchDelay, mtvwatch.MaxBackoff)
+	for {
+		obj, err := ri.Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, w.stopped(ctx)
+			}
+			return nil, fmt.Errorf("failed to get %s: %v", w.resource, err)
+		}
+		if done, err := w.evaluate(obj); done {
+			return obj, err
+		}
+
+		watcher, err := ri.Watch(ctx, metav1.ListOptions{
+			FieldSelector:   fields.OneTermEqualSelector("metadata.name", name).String(),
+			ResourceVersion: obj.GetResourceVersion(),
+		})
+		if err != nil {
+			delay := backoff.Next()
+			klog.V(2).Infof("Failed to watch %s, retrying in %s: %v", w.resource, delay, err)
+			select {
+			case <-ctx.Done():
+				return nil, w.stopped(ctx)
+			case <-time.After(delay):
+			}
+			continue
+		}
+		backoff.Reset()
+
+		obj, done, err := w.consume(ctx, watcher)
+		watcher.Stop()
+		if done {
+			return obj, err
+		}
+	}
+}
+
+// waiter holds the state of one wait
+type waiter struct {
+	resource string
+	goal     string
+	check    Check
+	opts     Options
+
+	last         *unstructured.Unstructured
+	problems     []string
+	failingSince time.Time
+}
+
+// evaluate checks an observed state of the resource, it is done when the check is done
+// or its problems persisted for the settle period
+func (w *waiter) evaluate(obj *unstructured.Unstructured) (bool, error) {
+	w.last = obj
+	done, problems := w.check(obj)
+	if done {
+		return true, nil
+	}
+	if len(problems) == 0 {
+		w.problems, w.failingSince = nil, time.Time{}
+		return false, nil
+	}
+
+	if w.failingSince.IsZero() || strings.Join(problems, "\n") != strings.Join(w.problems, "\n") {
+		w.failingSince = time.Now()
+		for _, p := range problems {
+			fmt.Fprintf(w.opts.Out, "  %s\n", p)
+		}
+	}
+	w.problems = problems
+	if time.Since(w.failingSince) >= w.opts.Settle {
+		return true, fmt.Errorf("%s did not %s:\n  %s", w.resource, w.goal, strings.Join(problems, "\n  "))
+	}
+	return false, nil
+}
+
+// consume evaluates the watch events until the wait is done, or the watch closes and must be restarted
+func (w *waiter) consume(ctx context.Context, watcher watch.Interface) (*unstructured.Unstructured, bool, error) {
+	for {
+		// Re-evaluate the last state when its problems have persisted for the settle period
+		var settled <-chan time.Time
+		if !w.failingSince.IsZero() {
+			settled = time.After(time.Until(w.failingSince.Add(w.opts.Settle)))
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, true, w.stopped(ctx)
+		case <-settled:
+			if done, err := w.evaluate(w.last); done {
+				return w.last, true, err
+			}
+		case event, ok := <-watcher.ResultChan():
+			if !ok {
+				return nil, false, nil
+			}
+			switch event.Type {
+			case watch.Added, watch.Modified:
+				obj, ok := event.Object.(*unstructured.Unstructured)
+				if !ok {
+					continue
+				}
+				if done, err := w.evaluate(obj); done {
+					return obj, true, err
+				}
+			case watch.Deleted:
+				return nil, true, fmt.Errorf("%s was deleted while waiting for it to %s", w.resource, w.goal)
+			case watch.Error:
+				klog.V(2).Infof("Watch error while waiting for %s, restarting: %v", w.resource, event.Object)
+				return nil, false, nil
+			}
+		}
+	}
+}
+
+// stopped returns the error of a wait ended by its context, listing the last problems
+func (w *waiter) stopped(ctx context.Context) error {
+	var err error
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		err = fmt.Errorf("timed out after %s waiting for %s to %s", w.opts.Timeout, w.resource, w.goal)
+	} else {
+		err = fmt.Errorf("stopped waiting for %s to %s: %v", w.resource, w.goal, ctx.Err())
+	}
+	if len(w.problems) > 0 {
+		err = fmt.Errorf("%v:\n  %s", err, strings.Join(w.problems, "\n  "))
+	}
+	return err
+}
+
+// ForEach calls wait for each name with the time left of a timeout shared by all of them,
+// 0 for no limit. Every name is waited for, and when several are waited for each failure is
+// printed to out as it happens. The error lists the failures, e.g. "2 of 3 plans are not
+// Ready" for kinds "plans" and problem "are not Ready".
+func ForEach(names []string, timeout time.Duration, out io.Writer, kinds, problem string, wait func(name string, timeout time.Duration) error) error {
+	if out == nil {
+		out = io.Discard
+	}
+	var deadline time.Time
+	if timeout > 0 {
+		deadline = time.Now().Add(timeout)
+	}
+
+	var failed []string
+	for _, name := range names {
+		left := timeout
+		if !deadline.IsZero() {
+			left = time.Until(deadline)
+			if left <= 0 {
+				failed = append(failed, fmt.Sprintf("timed out before waiting for %s '%s'", strings.TrimSuffix(kinds, "s"), name))
+				continue
+			}
+		}
+		if err := wait(name, left); err != nil {
+			if len(names) > 1 {
+				fmt.Fprintf(out, "Error: %v\n", err)
+			}
+			failed = append(failed, err.Error())
+		}
+	}
+
+	if len(failed) == 1 {
+		return errors.New(failed[0])
+	}
+	if len(failed) > 1 {
+		return fmt.Errorf("%d of %d %s %s:\n  %s", len(failed), len(names), kinds, problem, strings.Join(failed, "\n  "))
+	}
+	return nil
+}
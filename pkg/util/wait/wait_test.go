@@ -0,0 +1,146 @@
+package wait
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+var testGVR = schema.GroupVersionResource{Group: "forklift.konveyor.io", Version: "v1beta1", Resource: "providers"}
+
+func testProvider(conditions ...map[string]interface{}) *unstructured.Unstructured {
+	conds := []interface{}{}
+	for _, c := range conditions {
+		conds = append(conds, c)
+	}
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "forklift.konveyor.io/v1beta1",
+		"kind":       "Provider",
+		"status":     map[string]interface{}{"conditions": conds},
+	}}
+	obj.SetName("vsphere")
+	obj.SetNamespace("demo")
+	return obj
+}
+
+func testCondition(condType, category, message string) map[string]interface{} {
+	return map[string]interface{}{"type": condType, "status": "True", "category": category, "message": message}
+}
+
+func testClient(objects ...runtime.Object) dynamic.ResourceInterface {
+	c := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{testGVR: "ProviderList"}, objects...)
+	return c.Resource(testGVR).Namespace("demo")
+}
+
+func readyCheck(obj *unstructured.Unstructured) (bool, []string) {
+	return ConditionTrue(obj, "Ready"), BlockingConditions(obj)
+}
+
+func TestFor_AlreadyDone(t *testing.T) {
+	ri := testClient(testProvider(testCondition("Ready", "Required", "The provider is ready.")))
+
+	obj, err := For(context.Background(), ri, "provider", "vsphere", "become Ready", readyCheck, Options{Timeout: time.Second})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if obj.GetName() != "vsphere" {
+		t.Errorf("returned object %q, want vsphere", obj.GetName())
+	}
+}
+
+func TestFor_DoneOnUpdate(t *testing.T) {
+	ri := testClient(testProvider())
+
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		_, _ = ri.Update(context.Background(), testProvider(testCondition("Ready", "Required", "The provider is ready.")), metav1.UpdateOptions{})
+	}()
+
+	if _, err := For(context.Background(), ri, "provider", "vsphere", "become Ready", readyCheck, Options{Timeout: 5 * time.Second}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestFor_PersistentProblems(t *testing.T) {
+	ri := testClient(testProvider(testCondition("ConnectionTestFailed", "Critical", "Connection refused.")))
+
+	var out strings.Builder
+	_, err := For(context.Background(), ri, "provider", "vsphere", "become Ready", readyCheck,
+		Options{Timeout: 5 * time.Second, Settle: 100 * time.Millisecond, Out: &out})
+	if err == nil || !strings.Contains(err.Error(), "provider 'vsphere' did not become Ready") || !strings.Contains(err.Error(), "Connection refused.") {
+		t.Fatalf("expected settled problems error, got %v", err)
+	}
+	if !strings.Contains(out.String(), "ConnectionTestFailed: Connection refused.") {
+		t.Errorf("problems not reported while waiting, got %q", out.String())
+	}
+}
+
+func TestFor_Timeout(t *testing.T) {
+	ri := testClient(testProvider(testCondition("ConnectionTestFailed", "Critical", "Connection refused.")))
+
+	_, err := For(context.Background(), ri, "provider", "vsphere", "become Ready", readyCheck,
+		Options{Timeout: 100 * time.Millisecond, Settle: time.Minute})
+	if err == nil || !strings.Contains(err.Error(), "timed out after 100ms") || !strings.Contains(err.Error(), "Connection refused.") {
+		t.Fatalf("expected timeout error listing problems, got %v", err)
+	}
+}
+
+func TestBlockingConditions(t *testing.T) {
+	obj := testProvider(
+		testCondition("Ready", "Required", "ready"),
+		testCondition("VMNotFound", "Critical", "VM not found."),
+		testCondition("NamespaceNotValid", "Error", "Bad namespace."),
+		testCondition("VMPowerStateUnsupported", "Warn", "Powered off."),
+		map[string]interface{}{"type": "ConnectionTestFailed", "status": "False", "category": "Critical"},
+	)
+
+	got := BlockingConditions(obj)
+	want := []string{"VMNotFound: VM not found.", "NamespaceNotValid: Bad namespace."}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("BlockingConditions() = %v, want %v", got, want)
+	}
+}
+
+func TestForEach(t *testing.T) {
+	var waited []string
+	wait := func(name string, timeout time.Duration) error {
+		waited = append(waited, name)
+		if timeout <= 0 || timeout > time.Minute {
+			t.Errorf("wait(%s) timeout = %s, want the time left of one minute", name, timeout)
+		}
+		if name == "web" || name == "db" {
+			return fmt.Errorf("plan '%s' is not Ready", name)
+		}
+		return nil
+	}
+
+	var out strings.Builder
+	err := ForEach([]string{"web", "app", "db"}, time.Minute, &out, "plans", "are not Ready", wait)
+	if strings.Join(waited, ",") != "web,app,db" {
+		t.Errorf("waited = %v, want every plan", waited)
+	}
+	if err == nil || !strings.HasPrefix(err.Error(), "2 of 3 plans are not Ready:\n  plan 'web'") {
+		t.Errorf("ForEach() error = %v", err)
+	}
+	if strings.Count(out.String(), "Error: ") != 2 {
+		t.Errorf("printed failures = %q, want both", out.String())
+	}
+
+	out.Reset()
+	err = ForEach([]string{"web"}, 0, &out, "plans", "are not Ready", func(name string, timeout time.Duration) error {
+		return fmt.Errorf("plan '%s' is not Ready", name)
+	})
+	if err == nil || err.Error() != "plan 'web' is not Ready" || out.Len() != 0 {
+		t.Errorf("ForEach() of one plan error = %v, output %q", err, out.String())
+	}
+}
@@ -0,0 +1,37 @@
+package watch
+
+import "time"
+
+// MaxBackoff caps the delay between reconnect attempts of a watch
+const MaxBackoff = 2 * time.Minute
+
+// Backoff computes exponentially growing retry delays, doubling from Initial up to Max
+type Backoff struct {
+	Initial time.Duration
+	Max     time.Duration
+
+	current time.Duration
+}
+
+// NewBackoff returns a backoff starting at initial and capped at max
+func NewBackoff(initial, max time.Duration) *Backoff {
+	return &Backoff{Initial: initial, Max: max}
+}
+
+// Next returns the delay before the next attempt
+func (b *Backoff) Next() time.Duration {
+	if b.current == 0 {
+		b.current = b.Initial
+	} else {
+		b.current *= 2
+	}
+	if b.Max > 0 && b.current > b.Max {
+		b.current = b.Max
+	}
+	return b.current
+}
+
+// Reset starts the delays over from Initial, after a successful attempt
+func (b *Backoff) Reset() {
+	b.current = 0
+}
@@ -12,8 +12,8 @@ import (
 // Otherwise, it just calls the list function once
 func WrapWithWatch(watchMode bool, outputFormat string, listFunc RenderFunc, interval time.Duration) error {
 	if watchMode {
-		if outputFormat != "table" {
-			return fmt.Errorf("watch mode only supports table output format")
+		if outputFormat != "table" && outputFormat != "wide" {
+			return fmt.Errorf("watch mode only supports table and wide output formats")
 		}
 		return Watch(listFunc, interval)
 	}
@@ -25,8 +25,8 @@ func WrapWithWatch(watchMode bool, outputFormat string, listFunc RenderFunc, int
 // instead of WrapWithWatch so the user can press : to edit the query at runtime.
 func WrapWithWatchAndQuery(watchMode bool, outputFormat string, listFunc RenderFunc, interval time.Duration, queryUpdater tui.QueryUpdater, currentQuery string) error {
 	if watchMode {
-		if outputFormat != "table" {
-			return fmt.Errorf("watch mode only supports table output format")
+		if outputFormat != "table" && outputFormat != "wide" {
+			return fmt.Errorf("watch mode only supports table and wide output formats")
 		}
 		return WatchWithQuery(listFunc, interval, queryUpdater, currentQuery)
 	}
@@ -0,0 +1,95 @@
+package watch
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/yaacov/kubectl-mtv/pkg/util/output"
+	"github.com/yaacov/kubectl-mtv/pkg/util/tui"
+)
+
+// reconnector keeps a watch alive across API errors. A failed fetch keeps the
+// last good output on screen under a notice line, and the next attempts are
+// spaced with exponential backoff so a lost cluster connection is retried
+// until it comes back instead of failing every refresh.
+type reconnector struct {
+	fetch   tui.DataFetcher
+	backoff *Backoff
+	now     func() time.Time
+
+	// mu guards the state read by Status from the TUI; it is not held during a fetch
+	mu          sync.Mutex
+	lastContent string
+	lastSuccess time.Time
+	lastErr     error
+	failures    int
+	retryAt     time.Time
+}
+
+// newReconnector wraps fetch, retrying failures from interval up to MaxBackoff
+func newReconnector(fetch tui.DataFetcher, interval time.Duration) *reconnector {
+	return &reconnector{
+		fetch:   fetch,
+		backoff: NewBackoff(interval, MaxBackoff),
+		now:     time.Now,
+	}
+}
+
+// Fetch is the tui.DataFetcher of the watch. It never fails: errors are shown
+// as a notice above the last good output.
+func (r *reconnector) Fetch() (string, error) {
+	r.mu.Lock()
+	if r.failures > 0 && r.now().Before(r.retryAt) {
+		defer r.mu.Unlock()
+		return r.notice() + r.lastContent, nil
+	}
+	r.mu.Unlock()
+
+	content, err := r.fetch()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err != nil {
+		r.failures++
+		r.lastErr = err
+		r.retryAt = r.now().Add(r.backoff.Next())
+		return r.notice() + r.lastContent, nil
+	}
+
+	notice := ""
+	if r.failures > 0 {
+		notice = output.Green(fmt.Sprintf("Reconnected after %d failed attempt(s)", r.failures)) + "\n"
+	}
+	r.failures, r.lastErr = 0, nil
+	r.backoff.Reset()
+	r.lastContent = content
+	r.lastSuccess = r.now()
+	return notice + content, nil
+}
+
+// notice describes the failing connection and when it is retried
+func (r *reconnector) notice() string {
+	msg := fmt.Sprintf("Connection lost (attempt %d): %v. Retrying in %s",
+		r.failures, r.lastErr, r.retryAt.Sub(r.now()).Round(time.Second))
+	if !r.lastSuccess.IsZero() {
+		msg += fmt.Sprintf(", showing data from %s", r.lastSuccess.Format("15:04:05"))
+	}
+	return output.Yellow(msg) + "\n"
+}
+
+// Status is the tui.StatusFunc of the watch: live until a fetch fails, then the
+// reconnect attempt and the age of the data shown
+func (r *reconnector) Status() (bool, string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.failures == 0 {
+		return true, ""
+	}
+	detail := fmt.Sprintf("Reconnecting (attempt %d)", r.failures)
+	if !r.lastSuccess.IsZero() {
+		detail += fmt.Sprintf(", data from %s", r.lastSuccess.Format("15:04:05"))
+	}
+	return false, detail
+}
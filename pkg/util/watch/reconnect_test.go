@@ -0,0 +1,79 @@
+package watch
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBackoff(t *testing.T) {
+	b := NewBackoff(5*time.Second, 30*time.Second)
+	var got []time.Duration
+	for i := 0; i < 5; i++ {
+		got = append(got, b.Next())
+	}
+	want := []time.Duration{5 * time.Second, 10 * time.Second, 20 * time.Second, 30 * time.Second, 30 * time.Second}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("delays = %v, want %v", got, want)
+		}
+	}
+	b.Reset()
+	if d := b.Next(); d != 5*time.Second {
+		t.Errorf("Next() after Reset() = %s, want 5s", d)
+	}
+}
+
+func TestReconnector(t *testing.T) {
+	now := time.Date(2026, 10, 16, 10, 0, 0, 0, time.UTC)
+	calls := 0
+	var fail error
+	r := newReconnector(func() (string, error) {
+		calls++
+		if fail != nil {
+			return "", fail
+		}
+		return "plans\n", nil
+	}, 5*time.Second)
+	r.now = func() time.Time { return now }
+
+	if out, err := r.Fetch(); err != nil || out != "plans\n" {
+		t.Fatalf("Fetch() = %q, %v", out, err)
+	}
+
+	if live, _ := r.Status(); !live {
+		t.Error("Status() after a good fetch is not live")
+	}
+
+	fail = errors.New("connection refused")
+	out, err := r.Fetch()
+	if err != nil || !strings.Contains(out, "Connection lost (attempt 1): connection refused. Retrying in 5s") || !strings.HasSuffix(out, "plans\n") {
+		t.Fatalf("Fetch() after a failure = %q, %v, want a notice above the last output", out, err)
+	}
+
+	if live, detail := r.Status(); live || detail != "Reconnecting (attempt 1), data from 10:00:00" {
+		t.Errorf("Status() after a failure = %v, %q, want the reconnect attempt", live, detail)
+	}
+
+	// Refreshes before the retry time do not hit the API
+	now = now.Add(2 * time.Second)
+	r.Fetch()
+	if calls != 2 {
+		t.Errorf("fetch called %d times during backoff, want 2", calls)
+	}
+
+	now = now.Add(3 * time.Second)
+	if out, _ := r.Fetch(); !strings.Contains(out, "attempt 2") || !strings.Contains(out, "Retrying in 10s") {
+		t.Errorf("second failure notice = %q, want attempt 2 retried in 10s", out)
+	}
+
+	fail = nil
+	now = now.Add(10 * time.Second)
+	if out, _ := r.Fetch(); !strings.Contains(out, "Reconnected after 2 failed attempt(s)") {
+		t.Errorf("Fetch() after reconnecting = %q, want a reconnect notice", out)
+	}
+	if out, _ := r.Fetch(); out != "plans\n" {
+		t.Errorf("Fetch() once reconnected = %q, want the plain output", out)
+	}
+}
@@ -1,6 +1,7 @@
 package watch
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -8,12 +9,21 @@ import (
 	"sync"
 	"time"
 
+	"github.com/yaacov/kubectl-mtv/pkg/util/output"
 	"github.com/yaacov/kubectl-mtv/pkg/util/tui"
 )
 
 // DefaultInterval is the default watch interval for all watch operations
 const DefaultInterval = 5 * time.Second
 
+// timeout stops every watch after it, set from the global --watch-timeout flag; 0 watches until the user quits
+var timeout time.Duration
+
+// SetTimeout sets how long watches run before they stop by themselves
+func SetTimeout(d time.Duration) {
+	timeout = d
+}
+
 // RenderFunc is a function that renders output and returns an error if any
 type RenderFunc func() error
 
@@ -59,16 +69,27 @@ func captureOutput(renderFunc RenderFunc) tui.DataFetcher {
 }
 
 // Watch uses TUI mode for watching with smooth updates and interactive features.
+// Failed refreshes are retried with backoff while the last output stays on screen,
+// and the status bar heartbeat shows whether the data is live.
 func Watch(renderFunc RenderFunc, interval time.Duration) error {
-	return tui.Run(captureOutput(renderFunc), interval)
+	return run(renderFunc, interval)
 }
 
 // WatchWithQuery uses TUI mode with interactive query editing support.
 func WatchWithQuery(renderFunc RenderFunc, interval time.Duration, queryUpdater tui.QueryUpdater, currentQuery string) error {
-	return tui.RunWithOptions(
-		captureOutput(renderFunc),
-		interval,
-		tui.WithQueryUpdater(queryUpdater),
-		tui.WithInitialQuery(currentQuery),
-	)
+	return run(renderFunc, interval, tui.WithQueryUpdater(queryUpdater), tui.WithInitialQuery(currentQuery))
+}
+
+// run starts the TUI of a watch; reaching the watch timeout is a normal exit
+func run(renderFunc RenderFunc, interval time.Duration, opts ...tui.Option) error {
+	output.SetWatchMode(true)
+	r := newReconnector(captureOutput(renderFunc), interval)
+	opts = append(opts, tui.WithStatus(r.Status), tui.WithTimeout(timeout))
+
+	err := tui.RunWithOptions(r.Fetch, interval, opts...)
+	if errors.Is(err, tui.ErrTimedOut) {
+		fmt.Fprintf(os.Stderr, "Stopped watching after %s (--watch-timeout)\n", timeout)
+		return nil
+	}
+	return err
 }
@@ -0,0 +1,260 @@
+package wave
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/yaacov/kubectl-mtv/pkg/util/client"
+)
+
+// A migration wave is a client-side grouping of plans. Waves are stored as
+// ConfigMaps labeled with WaveLabel so no extra CRD has to be installed.
+
+// WaveLabel marks a ConfigMap as a kubectl-mtv migration wave
+const WaveLabel = "kubectl-mtv/wave"
+
+// Wave execution modes
+const (
+	ModeSequential = "sequential"
+	ModeParallel   = "parallel"
+)
+
+// ConfigMap data keys
+const (
+	keyPlans       = "plans"
+	keyMode        = "mode"
+	keyConcurrency = "concurrency"
+	keyStartAt     = "startAt"
+	keyDescription = "description"
+	keyStartedAt   = "startedAt"
+	keyCompletedAt = "completedAt"
+	keyResult      = "result"
+)
+
+// Wave is a named group of migration plans started together
+type Wave struct {
+	Name        string
+	Namespace   string
+	Plans       []string
+	Mode        string
+	Concurrency int
+	StartAt     *time.Time
+	Description string
+
+	// Run state, recorded by 'start wave'
+	StartedAt   *time.Time
+	CompletedAt *time.Time
+	Result      string
+
+	Created time.Time
+}
+
+// Validate checks the wave definition
+func (w *Wave) Validate() error {
+	if w.Name == "" {
+		return fmt.Errorf("wave name is required")
+	}
+	if len(w.Plans) == 0 {
+		return fmt.Errorf("wave '%s' must contain at least one plan", w.Name)
+	}
+	seen := map[string]bool{}
+	for _, plan := range w.Plans {
+		if seen[plan] {
+			return fmt.Errorf("plan '%s' is listed more than once in wave '%s'", plan, w.Name)
+		}
+		seen[plan] = true
+	}
+	switch w.Mode {
+	case ModeSequential, ModeParallel:
+	default:
+		return fmt.Errorf("invalid wave mode '%s': must be %s or %s", w.Mode, ModeSequential, ModeParallel)
+	}
+	if w.Concurrency < 0 {
+		return fmt.Errorf("concurrency must not be negative")
+	}
+	return nil
+}
+
+// Limit returns how many plans of the wave may run at the same time
+func (w *Wave) Limit() int {
+	if w.Mode == ModeSequential {
+		return 1
+	}
+	if w.Concurrency <= 0 || w.Concurrency > len(w.Plans) {
+		return len(w.Plans)
+	}
+	return w.Concurrency
+}
+
+// ToConfigMap returns the ConfigMap representation of the wave
+func (w *Wave) ToConfigMap() *unstructured.Unstructured {
+	data := map[string]interface{}{
+		keyPlans: strings.Join(w.Plans, "\n"),
+		keyMode:  w.Mode,
+	}
+	if w.Concurrency > 0 {
+		data[keyConcurrency] = strconv.Itoa(w.Concurrency)
+	}
+	if w.StartAt != nil {
+		data[keyStartAt] = w.StartAt.UTC().Format(time.RFC3339)
+	}
+	if w.Description != "" {
+		data[keyDescription] = w.Description
+	}
+	if w.StartedAt != nil {
+		data[keyStartedAt] = w.StartedAt.UTC().Format(time.RFC3339)
+	}
+	if w.CompletedAt != nil {
+		data[keyCompletedAt] = w.CompletedAt.UTC().Format(time.RFC3339)
+	}
+	if w.Result != "" {
+		data[keyResult] = w.Result
+	}
+
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata": map[string]interface{}{
+			"name":      w.Name,
+			"namespace": w.Namespace,
+			"labels": map[string]interface{}{
+				WaveLabel: "true",
+			},
+		},
+		"data": data,
+	}}
+}
+
+// FromConfigMap parses a wave from its ConfigMap representation
+func FromConfigMap(cm *unstructured.Unstructured) (*Wave, error) {
+	if cm.GetLabels()[WaveLabel] != "true" {
+		return nil, fmt.Errorf("configmap '%s' is not a migration wave", cm.GetName())
+	}
+	data, _, _ := unstructured.NestedStringMap(cm.Object, "data")
+
+	w := &Wave{
+		Name:        cm.GetName(),
+		Namespace:   cm.GetNamespace(),
+		Mode:        data[keyMode],
+		Description: data[keyDescription],
+		Result:      data[keyResult],
+		Created:     cm.GetCreationTimestamp().Time,
+	}
+	if w.Mode == "" {
+		w.Mode = ModeSequential
+	}
+	for _, plan := range strings.Split(data[keyPlans], "\n") {
+		if plan = strings.TrimSpace(plan); plan != "" {
+			w.Plans = append(w.Plans, plan)
+		}
+	}
+	if value := data[keyConcurrency]; value != "" {
+		concurrency, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("wave '%s' has invalid concurrency '%s': %v", w.Name, value, err)
+		}
+		w.Concurrency = concurrency
+	}
+
+	var err error
+	if w.StartAt, err = parseTime(data, keyStartAt); err != nil {
+		return nil, fmt.Errorf("wave '%s': %v", w.Name, err)
+	}
+	if w.StartedAt, err = parseTime(data, keyStartedAt); err != nil {
+		return nil, fmt.Errorf("wave '%s': %v", w.Name, err)
+	}
+	if w.CompletedAt, err = parseTime(data, keyCompletedAt); err != nil {
+		return nil, fmt.Errorf("wave '%s': %v", w.Name, err)
+	}
+	return w, nil
+}
+
+// parseTime parses an optional RFC3339 time from the wave data
+func parseTime(data map[string]string, key string) (*time.Time, error) {
+	value := data[key]
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s '%s': %v", key, value, err)
+	}
+	return &t, nil
+}
+
+// Get fetches a wave by name
+func Get(ctx context.Context, c dynamic.Interface, namespace, name string) (*Wave, error) {
+	cm, err := c.Resource(client.ConfigMapsGVR).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get wave '%s': %v", name, err)
+	}
+	return FromConfigMap(cm)
+}
+
+// List returns all waves in a namespace (all namespaces when namespace is empty)
+func List(ctx context.Context, c dynamic.Interface, namespace string) ([]*Wave, error) {
+	list, err := c.Resource(client.ConfigMapsGVR).Namespace(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: WaveLabel + "=true",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list waves: %v", err)
+	}
+
+	waves := make([]*Wave, 0, len(list.Items))
+	for i := range list.Items {
+		w, err := FromConfigMap(&list.Items[i])
+		if err != nil {
+			return nil, err
+		}
+		waves = append(waves, w)
+	}
+	return waves, nil
+}
+
+// Create stores a new wave
+func Create(ctx context.Context, c dynamic.Interface, w *Wave) error {
+	if err := w.Validate(); err != nil {
+		return err
+	}
+	_, err := c.Resource(client.ConfigMapsGVR).Namespace(w.Namespace).Create(ctx, w.ToConfigMap(), metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to create wave '%s': %v", w.Name, err)
+	}
+	return nil
+}
+
+// UpdateRunState records the run state of a wave
+func UpdateRunState(ctx context.Context, c dynamic.Interface, w *Wave) error {
+	cm, err := c.Resource(client.ConfigMapsGVR).Namespace(w.Namespace).Get(ctx, w.Name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get wave '%s': %v", w.Name, err)
+	}
+
+	desired, _, _ := unstructured.NestedStringMap(w.ToConfigMap().Object, "data")
+	data, _, _ := unstructured.NestedStringMap(cm.Object, "data")
+	if data == nil {
+		data = map[string]string{}
+	}
+	for _, key := range []string{keyStartedAt, keyCompletedAt, keyResult} {
+		if value, ok := desired[key]; ok {
+			data[key] = value
+		} else {
+			delete(data, key)
+		}
+	}
+	if err := unstructured.SetNestedStringMap(cm.Object, data, "data"); err != nil {
+		return fmt.Errorf("failed to update wave '%s': %v", w.Name, err)
+	}
+
+	if _, err := c.Resource(client.ConfigMapsGVR).Namespace(w.Namespace).Update(ctx, cm, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to update wave '%s': %v", w.Name, err)
+	}
+	return nil
+}
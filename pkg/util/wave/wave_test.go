@@ -0,0 +1,94 @@
+package wave
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWaveConfigMapRoundTrip(t *testing.T) {
+	startAt := time.Date(2026, 12, 31, 22, 0, 0, 0, time.UTC)
+	w := &Wave{
+		Name:        "wave-1",
+		Namespace:   "migrations",
+		Plans:       []string{"db", "app", "web"},
+		Mode:        ModeParallel,
+		Concurrency: 2,
+		StartAt:     &startAt,
+		Description: "first wave",
+	}
+
+	cm := w.ToConfigMap()
+	if cm.GetLabels()[WaveLabel] != "true" {
+		t.Fatalf("expected wave label, got %v", cm.GetLabels())
+	}
+
+	got, err := FromConfigMap(cm)
+	if err != nil {
+		t.Fatalf("FromConfigMap: %v", err)
+	}
+	if got.Name != w.Name || got.Namespace != w.Namespace || got.Mode != w.Mode ||
+		got.Concurrency != w.Concurrency || got.Description != w.Description {
+		t.Errorf("round trip mismatch: got %+v, want %+v", got, w)
+	}
+	if !reflect.DeepEqual(got.Plans, w.Plans) {
+		t.Errorf("plans = %v, want %v", got.Plans, w.Plans)
+	}
+	if got.StartAt == nil || !got.StartAt.Equal(startAt) {
+		t.Errorf("startAt = %v, want %v", got.StartAt, startAt)
+	}
+	if got.StartedAt != nil || got.CompletedAt != nil {
+		t.Errorf("expected no run state, got %v %v", got.StartedAt, got.CompletedAt)
+	}
+}
+
+func TestFromConfigMapRejectsUnlabeled(t *testing.T) {
+	cm := (&Wave{Name: "w", Plans: []string{"p"}, Mode: ModeSequential}).ToConfigMap()
+	cm.SetLabels(nil)
+	if _, err := FromConfigMap(cm); err == nil {
+		t.Fatal("expected an error for a configmap without the wave label")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		wave    Wave
+		wantErr bool
+	}{
+		{"valid sequential", Wave{Name: "w", Plans: []string{"a", "b"}, Mode: ModeSequential}, false},
+		{"valid parallel", Wave{Name: "w", Plans: []string{"a"}, Mode: ModeParallel, Concurrency: 3}, false},
+		{"no plans", Wave{Name: "w", Mode: ModeSequential}, true},
+		{"duplicate plan", Wave{Name: "w", Plans: []string{"a", "a"}, Mode: ModeSequential}, true},
+		{"bad mode", Wave{Name: "w", Plans: []string{"a"}, Mode: "random"}, true},
+		{"negative concurrency", Wave{Name: "w", Plans: []string{"a"}, Mode: ModeParallel, Concurrency: -1}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.wave.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLimit(t *testing.T) {
+	plans := []string{"a", "b", "c", "d"}
+	tests := []struct {
+		name string
+		wave Wave
+		want int
+	}{
+		{"sequential", Wave{Plans: plans, Mode: ModeSequential, Concurrency: 3}, 1},
+		{"parallel unlimited", Wave{Plans: plans, Mode: ModeParallel}, 4},
+		{"parallel limited", Wave{Plans: plans, Mode: ModeParallel, Concurrency: 2}, 2},
+		{"parallel limit above plans", Wave{Plans: plans, Mode: ModeParallel, Concurrency: 10}, 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.wave.Limit(); got != tt.want {
+				t.Errorf("Limit() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
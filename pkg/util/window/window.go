@@ -0,0 +1,226 @@
+package window
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// AllowedWindowsAnnotation lists the windows in which a plan may be started or cut over,
+// separated by ";" (e.g. "Sat-Sun 00:00-23:59; Mon-Fri 22:00-05:00 UTC")
+const AllowedWindowsAnnotation = "kubectl-mtv/allowed-windows"
+
+// searchDays bounds the search for the next opening of a weekly window
+const searchDays = 8
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// Window is a recurring weekly time range. A range whose end is not after its start
+// crosses midnight and ends on the next day.
+type Window struct {
+	// Spec is the text the window was parsed from
+	Spec     string
+	days     [7]bool
+	start    time.Duration
+	length   time.Duration
+	location *time.Location
+}
+
+// Parse parses a window of the form "[DAYS] [HH:MM-HH:MM] [TIMEZONE]":
+//   - DAYS is a comma-separated list of days or day ranges, e.g. "Mon-Fri", "Sat,Sun", "Fri-Mon"; every day when omitted
+//   - HH:MM-HH:MM is the time range, e.g. "22:00-06:00"; the whole day when omitted
+//   - TIMEZONE is an IANA name such as "Europe/Berlin"; UTC when omitted
+func Parse(spec string) (*Window, error) {
+	fields := strings.Fields(spec)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("empty window")
+	}
+
+	w := &Window{Spec: strings.Join(fields, " "), length: 24 * time.Hour, location: time.UTC}
+	allDays := true
+	for i, field := range fields {
+		switch {
+		case strings.Contains(field, ":"):
+			start, end, err := parseTimeRange(field)
+			if err != nil {
+				return nil, fmt.Errorf("invalid window %q: %v", spec, err)
+			}
+			w.start = start
+			w.length = end - start
+			if w.length <= 0 {
+				w.length += 24 * time.Hour
+			}
+		case i == 0 && isDayList(field):
+			if err := w.parseDays(field); err != nil {
+				return nil, fmt.Errorf("invalid window %q: %v", spec, err)
+			}
+			allDays = false
+		case i == len(fields)-1:
+			loc, err := time.LoadLocation(field)
+			if err != nil {
+				return nil, fmt.Errorf("invalid window %q: unknown timezone %q", spec, field)
+			}
+			w.location = loc
+		default:
+			return nil, fmt.Errorf("invalid window %q: unexpected %q, expected days, a time range like 22:00-06:00 or a timezone", spec, field)
+		}
+	}
+	if allDays {
+		w.days = [7]bool{true, true, true, true, true, true, true}
+	}
+	return w, nil
+}
+
+// ParseList parses windows separated by ";" or given as separate values
+func ParseList(specs ...string) ([]*Window, error) {
+	var windows []*Window
+	for _, value := range specs {
+		for _, spec := range strings.Split(value, ";") {
+			if strings.TrimSpace(spec) == "" {
+				continue
+			}
+			w, err := Parse(spec)
+			if err != nil {
+				return nil, err
+			}
+			windows = append(windows, w)
+		}
+	}
+	return windows, nil
+}
+
+// Contains reports whether t falls in the window
+func (w *Window) Contains(t time.Time) bool {
+	t = t.In(w.location)
+	// A window that crosses midnight may have opened the day before
+	for _, offset := range []int{0, -1} {
+		opens := w.opensOn(t.AddDate(0, 0, offset))
+		if w.days[opens.Weekday()] && !t.Before(opens) && t.Before(opens.Add(w.length)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Next returns the first opening of the window after t
+func (w *Window) Next(t time.Time) time.Time {
+	local := t.In(w.location)
+	for offset := 0; offset < searchDays; offset++ {
+		opens := w.opensOn(local.AddDate(0, 0, offset))
+		if w.days[opens.Weekday()] && opens.After(t) {
+			return opens
+		}
+	}
+	return time.Time{}
+}
+
+// opensOn returns the opening time of the window on the day of t
+func (w *Window) opensOn(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, w.location).Add(w.start)
+}
+
+// AnyContains reports whether t falls in any of the windows
+func AnyContains(windows []*Window, t time.Time) bool {
+	for _, w := range windows {
+		if w.Contains(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// NextOpening returns the earliest opening of the windows after t, zero when there is none
+func NextOpening(windows []*Window, t time.Time) time.Time {
+	var next time.Time
+	for _, w := range windows {
+		if opens := w.Next(t); !opens.IsZero() && (next.IsZero() || opens.Before(next)) {
+			next = opens
+		}
+	}
+	return next
+}
+
+// Specs returns the text of the windows
+func Specs(windows []*Window) []string {
+	specs := make([]string, 0, len(windows))
+	for _, w := range windows {
+		specs = append(specs, w.Spec)
+	}
+	return specs
+}
+
+// parseTimeRange parses "HH:MM-HH:MM" as offsets from midnight; "24:00" ends at midnight
+func parseTimeRange(value string) (time.Duration, time.Duration, error) {
+	from, to, ok := strings.Cut(value, "-")
+	if !ok {
+		return 0, 0, fmt.Errorf("time range %q must look like 22:00-06:00", value)
+	}
+	start, err := parseClock(from)
+	if err != nil {
+		return 0, 0, err
+	}
+	end, err := parseClock(to)
+	if err != nil {
+		return 0, 0, err
+	}
+	return start, end, nil
+}
+
+func parseClock(value string) (time.Duration, error) {
+	if value == "24:00" {
+		return 24 * time.Hour, nil
+	}
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", value)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// isDayList reports whether a field looks like a list of days rather than a timezone
+func isDayList(field string) bool {
+	for _, part := range strings.FieldsFunc(field, func(r rune) bool { return r == ',' || r == '-' }) {
+		if _, ok := weekdays[dayKey(part)]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func (w *Window) parseDays(field string) error {
+	for _, part := range strings.Split(field, ",") {
+		from, to, isRange := strings.Cut(part, "-")
+		first, ok := weekdays[dayKey(from)]
+		if !ok {
+			return fmt.Errorf("unknown day %q", from)
+		}
+		last := first
+		if isRange {
+			if last, ok = weekdays[dayKey(to)]; !ok {
+				return fmt.Errorf("unknown day %q", to)
+			}
+		}
+		// Ranges may wrap around the week, e.g. Fri-Mon
+		for d := first; ; d = (d + 1) % 7 {
+			w.days[d] = true
+			if d == last {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// dayKey normalizes "Monday", "MON" and "mon" to "mon"
+func dayKey(day string) string {
+	key := strings.ToLower(day)
+	if len(key) > 3 {
+		if d, ok := weekdays[key[:3]]; ok && strings.EqualFold(d.String(), day) {
+			return key[:3]
+		}
+	}
+	return key
+}
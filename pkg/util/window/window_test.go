@@ -0,0 +1,71 @@
+package window
+
+import (
+	"testing"
+	"time"
+)
+
+// 2026-05-01 is a Friday
+func at(day, hour, minute int) time.Time {
+	return time.Date(2026, 5, day, hour, minute, 0, 0, time.UTC)
+}
+
+func TestContains(t *testing.T) {
+	tests := []struct {
+		spec string
+		t    time.Time
+		want bool
+	}{
+		{"Mon-Fri 22:00-05:00", at(1, 23, 0), true},
+		{"Mon-Fri 22:00-05:00", at(2, 4, 59), true}, // Saturday morning, opened Friday
+		{"Mon-Fri 22:00-05:00", at(2, 5, 0), false},
+		{"Mon-Fri 22:00-05:00", at(2, 23, 0), false}, // Saturday night
+		{"Sat,Sun", at(3, 12, 0), true},
+		{"Sat,Sun", at(4, 0, 0), false},
+		{"Fri-Mon 08:00-18:00", at(4, 9, 0), true},
+		{"Fri-Mon 08:00-18:00", at(5, 9, 0), false},
+		{"20:00-24:00", at(5, 23, 59), true},
+		{"Fri 10:00-12:00 Europe/Berlin", at(1, 8, 30), true}, // 10:30 CEST
+		{"Fri 10:00-12:00 Europe/Berlin", at(1, 10, 30), false},
+		{"Saturday 00:00-06:00", at(2, 1, 0), true},
+	}
+	for _, tt := range tests {
+		w, err := Parse(tt.spec)
+		if err != nil {
+			t.Fatalf("Parse(%q) unexpected error: %v", tt.spec, err)
+		}
+		if got := w.Contains(tt.t); got != tt.want {
+			t.Errorf("Parse(%q).Contains(%s) = %v, want %v", tt.spec, tt.t, got, tt.want)
+		}
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	for _, spec := range []string{"", "Mon-Fri 22:00", "Mon-Fri 25:00-26:00", "Mon Fri", "Funday 10:00-11:00", "10:00-11:00 Mars/Base"} {
+		if _, err := Parse(spec); err == nil {
+			t.Errorf("Parse(%q) expected error", spec)
+		}
+	}
+}
+
+func TestNextOpening(t *testing.T) {
+	windows, err := ParseList("Sat 02:00-06:00; Mon-Fri 22:00-05:00")
+	if err != nil {
+		t.Fatalf("ParseList() unexpected error: %v", err)
+	}
+	if len(windows) != 2 || Specs(windows)[0] != "Sat 02:00-06:00" {
+		t.Fatalf("unexpected windows: %v", Specs(windows))
+	}
+
+	now := at(1, 12, 0)
+	if AnyContains(windows, now) {
+		t.Errorf("AnyContains(%s) = true, want false", now)
+	}
+	if next := NextOpening(windows, now); !next.Equal(at(1, 22, 0)) {
+		t.Errorf("NextOpening(%s) = %s, want Friday 22:00", now, next)
+	}
+	// Saturday noon: the next opening is Monday night
+	if next := NextOpening(windows, at(2, 12, 0)); !next.Equal(at(4, 22, 0)) {
+		t.Errorf("NextOpening(Saturday) = %s, want Monday 22:00", next)
+	}
+}
@@ -0,0 +1,76 @@
+package version
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// MinOperatorVersion is the oldest MTV operator release (major.minor) kubectl-mtv supports
+const MinOperatorVersion = "2.8"
+
+// TestedOperatorVersion is the newest MTV operator release (major.minor) kubectl-mtv was tested with
+const TestedOperatorVersion = "2.10"
+
+// releasePattern finds a major.minor[.patch] release in a version string
+var releasePattern = regexp.MustCompile(`(\d+)\.(\d+)(?:\.(\d+))?`)
+
+// Release is a parsed major.minor.patch version
+type Release struct {
+	Major, Minor, Patch int
+}
+
+// ParseRelease finds the release in a version string such as "v2.9.3" or the operator
+// CSV name "mtv-operator.v2.9.3"
+func ParseRelease(s string) (Release, error) {
+	m := releasePattern.FindStringSubmatch(s)
+	if m == nil {
+		return Release{}, fmt.Errorf("no release version in %q", s)
+	}
+	var r Release
+	r.Major, _ = strconv.Atoi(m[1])
+	r.Minor, _ = strconv.Atoi(m[2])
+	if m[3] != "" {
+		r.Patch, _ = strconv.Atoi(m[3])
+	}
+	return r, nil
+}
+
+// Compare orders releases by major and minor version, patch releases are compatible
+func (r Release) Compare(other Release) int {
+	if r.Major != other.Major {
+		return r.Major - other.Major
+	}
+	return r.Minor - other.Minor
+}
+
+// String returns the release as major.minor.patch
+func (r Release) String() string {
+	return fmt.Sprintf("%d.%d.%d", r.Major, r.Minor, r.Patch)
+}
+
+// mustRelease parses a release constant
+func mustRelease(s string) Release {
+	r, err := ParseRelease(s)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// OperatorSkew compares an MTV operator version with the supported range. It returns a
+// negative value when the operator is older than MinOperatorVersion, a positive value
+// when it is newer than TestedOperatorVersion, and 0 when it is supported.
+func OperatorSkew(operatorVersion string) (int, error) {
+	r, err := ParseRelease(operatorVersion)
+	if err != nil {
+		return 0, err
+	}
+	if r.Compare(mustRelease(MinOperatorVersion)) < 0 {
+		return -1, nil
+	}
+	if r.Compare(mustRelease(TestedOperatorVersion)) > 0 {
+		return 1, nil
+	}
+	return 0, nil
+}
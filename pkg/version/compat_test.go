@@ -0,0 +1,35 @@
+package version
+
+import "testing"
+
+func TestParseRelease(t *testing.T) {
+	for input, want := range map[string]Release{
+		"v2.9.3":                   {2, 9, 3},
+		"mtv-operator.v2.10.0":     {2, 10, 0},
+		"forklift-operator.v2.8":   {2, 8, 0},
+		"v0.7.1-3-gabcdef0":        {0, 7, 1},
+		"mtv-operator.v2.9.3-rc.1": {2, 9, 3},
+	} {
+		got, err := ParseRelease(input)
+		if err != nil || got != want {
+			t.Errorf("ParseRelease(%q) = %v, %v, want %v", input, got, err, want)
+		}
+	}
+	if _, err := ParseRelease("unknown"); err == nil {
+		t.Error("ParseRelease(unknown) expected error")
+	}
+}
+
+func TestOperatorSkew(t *testing.T) {
+	for input, want := range map[string]int{
+		"mtv-operator.v2.7.9":  -1,
+		"mtv-operator.v2.8.0":  0,
+		"mtv-operator.v2.10.4": 0,
+		"mtv-operator.v2.11.0": 1,
+		"mtv-operator.v3.0.0":  1,
+	} {
+		if got, err := OperatorSkew(input); err != nil || got != want {
+			t.Errorf("OperatorSkew(%q) = %d, %v, want %d", input, got, err, want)
+		}
+	}
+}
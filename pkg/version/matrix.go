@@ -0,0 +1,152 @@
+package version
+
+import (
+	_ "embed"
+	"fmt"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Severities of compatibility issues
+const (
+	SeverityWarning = "warning"
+	SeverityError   = "error"
+)
+
+// compatibilityMatrix is the embedded list of known-broken version combinations
+//
+//go:embed compatibility.yaml
+var compatibilityMatrix []byte
+
+// CompatibilityRule is a version combination of the compatibility matrix. Unset
+// constraints match any version.
+type CompatibilityRule struct {
+	Client    string   `json:"client,omitempty"`
+	Operator  string   `json:"operator,omitempty"`
+	Inventory string   `json:"inventory,omitempty"`
+	API       []string `json:"api,omitempty"`
+	Severity  string   `json:"severity"`
+	Message   string   `json:"message"`
+}
+
+// Versions are the versions of an installation checked against the compatibility matrix.
+// API is the served Forklift API, e.g. forklift.konveyor.io/v1beta1.
+type Versions struct {
+	Client    string
+	Operator  string
+	Inventory string
+	API       string
+}
+
+// CompatibilityIssue is a problem found by CheckCompatibility
+type CompatibilityIssue struct {
+	Severity string `json:"severity" yaml:"severity"`
+	Message  string `json:"message" yaml:"message"`
+}
+
+// CompatibilityRules returns the rules of the embedded compatibility matrix
+func CompatibilityRules() ([]CompatibilityRule, error) {
+	var matrix struct {
+		Rules []CompatibilityRule `json:"rules"`
+	}
+	if err := yaml.Unmarshal(compatibilityMatrix, &matrix); err != nil {
+		return nil, fmt.Errorf("invalid compatibility matrix: %v", err)
+	}
+	return matrix.Rules, nil
+}
+
+// CheckCompatibility returns the issues of the matrix rules matching the versions, and a
+// warning when the inventory service runs another release than the operator, as happens
+// while an upgrade rolls out. Versions that are unknown or cannot be parsed match no rule.
+func CheckCompatibility(v Versions) ([]CompatibilityIssue, error) {
+	rules, err := CompatibilityRules()
+	if err != nil {
+		return nil, err
+	}
+
+	issues := []CompatibilityIssue{}
+	replacer := strings.NewReplacer("{client}", v.Client, "{operator}", v.Operator, "{inventory}", v.Inventory, "{api}", v.API)
+	for _, rule := range rules {
+		matched, err := rule.matches(v)
+		if err != nil {
+			return nil, err
+		}
+		if matched {
+			issues = append(issues, CompatibilityIssue{Severity: rule.Severity, Message: replacer.Replace(strings.TrimSpace(rule.Message))})
+		}
+	}
+
+	operator, errOperator := ParseRelease(v.Operator)
+	inventory, errInventory := ParseRelease(v.Inventory)
+	if errOperator == nil && errInventory == nil && operator.Compare(inventory) != 0 {
+		issues = append(issues, CompatibilityIssue{
+			Severity: SeverityWarning,
+			Message: fmt.Sprintf("The inventory service runs %s while the MTV operator is %s; the operator upgrade has not finished rolling out, inventory queries may fail until it does",
+				v.Inventory, v.Operator),
+		})
+	}
+	return issues, nil
+}
+
+// matches tells whether every constraint of the rule matches the versions
+func (rule CompatibilityRule) matches(v Versions) (bool, error) {
+	for _, c := range []struct{ constraint, version string }{
+		{rule.Client, v.Client},
+		{rule.Operator, v.Operator},
+		{rule.Inventory, v.Inventory},
+	} {
+		if c.constraint == "" {
+			continue
+		}
+		ok, err := matchRange(c.constraint, c.version)
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	if len(rule.API) > 0 {
+		found := false
+		for _, api := range rule.API {
+			found = found || api == v.API
+		}
+		return found, nil
+	}
+	return true, nil
+}
+
+// matchRange tells whether a version is in a range such as ">=2.8 <2.10" or "2.9"
+func matchRange(constraint, version string) (bool, error) {
+	r, err := ParseRelease(version)
+	if err != nil {
+		return false, nil
+	}
+	for _, term := range strings.Fields(constraint) {
+		op := strings.TrimRight(term, "0123456789.v")
+		bound, err := ParseRelease(strings.TrimPrefix(term, op))
+		if err != nil {
+			return false, fmt.Errorf("invalid version range %q in the compatibility matrix", constraint)
+		}
+		cmp := r.Compare(bound)
+		var ok bool
+		switch op {
+		case "", "=", "==":
+			ok = cmp == 0
+		case "!=":
+			ok = cmp != 0
+		case "<":
+			ok = cmp < 0
+		case "<=":
+			ok = cmp <= 0
+		case ">":
+			ok = cmp > 0
+		case ">=":
+			ok = cmp >= 0
+		default:
+			return false, fmt.Errorf("invalid version range %q in the compatibility matrix", constraint)
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,65 @@
+package version
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckCompatibility(t *testing.T) {
+	tests := []struct {
+		name     string
+		versions Versions
+		want     []string
+	}{
+		{"supported", Versions{Client: "v0.9.0", Operator: "mtv-operator.v2.9.3", Inventory: "2.9.3", API: "forklift.konveyor.io/v1beta1"}, nil},
+		{"old operator", Versions{Client: "v0.9.0", Operator: "mtv-operator.v2.7.1", API: "forklift.konveyor.io/v1beta1"}, []string{"error:older than 2.8"}},
+		{"new operator", Versions{Operator: "mtv-operator.v2.11.0", API: "forklift.konveyor.io/v1beta1"}, []string{"warning:newer than 2.10"}},
+		{"new API", Versions{Operator: "mtv-operator.v2.10.0", API: "forklift.konveyor.io/v1"}, []string{"warning:converts its resources to forklift.konveyor.io/v1"}},
+		{"inventory upgrade", Versions{Operator: "mtv-operator.v2.10.0", Inventory: "v2.9.4"}, []string{"warning:upgrade has not finished"}},
+		{"unknown versions", Versions{Client: "unknown", Operator: "unknown", Inventory: "unknown"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues, err := CheckCompatibility(tt.versions)
+			if err != nil {
+				t.Fatalf("CheckCompatibility: %v", err)
+			}
+			if len(issues) != len(tt.want) {
+				t.Fatalf("issues = %+v, want %v", issues, tt.want)
+			}
+			for i, want := range tt.want {
+				severity, message, _ := strings.Cut(want, ":")
+				if issues[i].Severity != severity || !strings.Contains(issues[i].Message, message) {
+					t.Errorf("issue %d = %+v, want %s containing %q", i, issues[i], severity, message)
+				}
+			}
+		})
+	}
+}
+
+func TestMatchRange(t *testing.T) {
+	for _, tt := range []struct {
+		constraint, version string
+		want                bool
+	}{
+		{">=2.8 <2.10", "v2.9.5", true},
+		{">=2.8 <2.10", "v2.10.0", false},
+		{"2.9", "mtv-operator.v2.9.1", true},
+		{"!=2.9", "2.9.0", false},
+		{"<2.8", "unknown", false},
+	} {
+		if got, err := matchRange(tt.constraint, tt.version); err != nil || got != tt.want {
+			t.Errorf("matchRange(%q, %q) = %v, %v, want %v", tt.constraint, tt.version, got, err, tt.want)
+		}
+	}
+	if _, err := matchRange("~2.9", "2.9.0"); err == nil {
+		t.Error("matchRange(~2.9) expected error")
+	}
+
+	// The matrix agrees with the supported operator range
+	for _, v := range []string{MinOperatorVersion, TestedOperatorVersion} {
+		if issues, err := CheckCompatibility(Versions{Operator: v}); err != nil || len(issues) != 0 {
+			t.Errorf("CheckCompatibility(%s) = %+v, %v, want no issues", v, issues, err)
+		}
+	}
+}
@@ -673,7 +673,7 @@ def _lifecycle_delete_provider():
     """Delete the created provider and verify it's gone."""
     stdout, stderr, rc = run([
         "delete", "provider", "--name", SMOKE_PROVIDER,
-        "--namespace", TEST_NAMESPACE,
+        "--namespace", TEST_NAMESPACE, "--yes",
     ])
     assert_exit_ok("delete provider exits 0", rc, stderr)
 